@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE blockchainevents DROP COLUMN invalidated;
+ALTER TABLE tokentransfer DROP COLUMN invalidated;
+COMMIT;
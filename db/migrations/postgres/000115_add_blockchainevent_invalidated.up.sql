@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE blockchainevents ADD COLUMN invalidated BOOLEAN DEFAULT false;
+ALTER TABLE tokentransfer ADD COLUMN invalidated BOOLEAN DEFAULT false;
+COMMIT;
@@ -0,0 +1,15 @@
+BEGIN;
+CREATE TABLE tokenmetadata (
+  seq              SERIAL          PRIMARY KEY,
+  namespace        VARCHAR(64)     NOT NULL,
+  uri              VARCHAR(1024)   NOT NULL,
+  name             TEXT,
+  description      TEXT,
+  image            TEXT,
+  content          TEXT,
+  error            TEXT,
+  updated          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokenmetadata_uri ON tokenmetadata(namespace,uri);
+COMMIT;
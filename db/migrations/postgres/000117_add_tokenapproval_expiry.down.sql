@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX tokenapproval_expiry;
+ALTER TABLE tokenapproval DROP COLUMN expiry;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE tokenapproval ADD COLUMN expiry BIGINT;
+CREATE INDEX tokenapproval_expiry ON tokenapproval(namespace,active,expiry);
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE tokenpool DROP COLUMN identity_registry;
+ALTER TABLE operations DROP COLUMN failure_reason;
+COMMIT;
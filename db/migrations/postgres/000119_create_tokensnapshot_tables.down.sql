@@ -0,0 +1,4 @@
+BEGIN;
+DROP TABLE IF EXISTS tokensnapshotbalance;
+DROP TABLE IF EXISTS tokensnapshot;
+COMMIT;
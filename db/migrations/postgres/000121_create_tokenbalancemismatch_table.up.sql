@@ -0,0 +1,17 @@
+BEGIN;
+CREATE TABLE tokenbalancemismatch (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  token_index      VARCHAR(1024),
+  key              VARCHAR(1024)   NOT NULL,
+  balance          VARCHAR(65),
+  chain_balance    VARCHAR(65),
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX tokenbalancemismatch_id ON tokenbalancemismatch(namespace,id);
+CREATE UNIQUE INDEX tokenbalancemismatch_account ON tokenbalancemismatch(namespace,pool_id,token_index,key);
+COMMIT;
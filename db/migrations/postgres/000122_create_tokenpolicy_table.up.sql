@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE tokenpolicy (
+  seq              SERIAL          PRIMARY KEY,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  max_amount       VARCHAR(65),
+  daily_limit      VARCHAR(65),
+  senders          TEXT,
+  recipients       TEXT,
+  denied           TEXT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX tokenpolicy_pool ON tokenpolicy(namespace,pool_id);
+COMMIT;
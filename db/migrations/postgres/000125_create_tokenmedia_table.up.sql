@@ -0,0 +1,15 @@
+BEGIN;
+CREATE TABLE tokenmedia (
+  seq              SERIAL          PRIMARY KEY,
+  namespace        VARCHAR(64)     NOT NULL,
+  uri              VARCHAR(1024)   NOT NULL,
+  content_type     VARCHAR(255)    NOT NULL,
+  size             BIGINT          NOT NULL,
+  hash             CHAR(64)        NOT NULL,
+  payload_ref      VARCHAR(1024),
+  content          BYTEA,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokenmedia_uri ON tokenmedia(namespace,uri);
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+CREATE TABLE tokenpoolmigration (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  state            VARCHAR(64)     NOT NULL,
+  from_connector   VARCHAR(64)     NOT NULL,
+  from_locator     VARCHAR(1024)   NOT NULL,
+  to_connector     VARCHAR(64)     NOT NULL,
+  to_locator       VARCHAR(1024),
+  pool_state       VARCHAR(64)     NOT NULL,
+  config           TEXT,
+  tx_type          VARCHAR(64),
+  tx_id            UUID,
+  error            TEXT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX tokenpoolmigration_id ON tokenpoolmigration(namespace,id);
+CREATE INDEX tokenpoolmigration_pool ON tokenpoolmigration(namespace,pool_id,state);
+COMMIT;
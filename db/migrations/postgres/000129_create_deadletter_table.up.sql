@@ -0,0 +1,19 @@
+BEGIN;
+CREATE TABLE deadletter (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  sub_id           UUID            NOT NULL,
+  sub_name         VARCHAR(64)     NOT NULL,
+  event_id         UUID            NOT NULL,
+  event_type       VARCHAR(64)     NOT NULL,
+  reference        UUID,
+  attempts         INTEGER         NOT NULL,
+  status           INTEGER,
+  error            TEXT,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX deadletter_id ON deadletter(namespace,id);
+CREATE INDEX deadletter_sub ON deadletter(namespace,sub_id);
+COMMIT;
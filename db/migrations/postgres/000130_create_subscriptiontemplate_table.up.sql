@@ -0,0 +1,17 @@
+BEGIN;
+CREATE TABLE subscriptiontemplates (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  name             VARCHAR(64)     NOT NULL,
+  trigger_type     VARCHAR(64)     NOT NULL,
+  match_name       TEXT,
+  transport        VARCHAR(64)     NOT NULL,
+  filters          TEXT,
+  options          TEXT,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX subscriptiontemplates_id ON subscriptiontemplates(namespace,id);
+CREATE UNIQUE INDEX subscriptiontemplates_name ON subscriptiontemplates(namespace,name);
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+DROP INDEX IF EXISTS deadletter_state;
+ALTER TABLE deadletter DROP COLUMN updated;
+ALTER TABLE deadletter DROP COLUMN original_event;
+ALTER TABLE deadletter DROP COLUMN history;
+ALTER TABLE deadletter DROP COLUMN state;
+COMMIT;
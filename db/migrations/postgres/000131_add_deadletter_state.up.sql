@@ -0,0 +1,7 @@
+BEGIN;
+ALTER TABLE deadletter ADD COLUMN state VARCHAR(64) DEFAULT 'pending';
+ALTER TABLE deadletter ADD COLUMN history TEXT;
+ALTER TABLE deadletter ADD COLUMN original_event TEXT;
+ALTER TABLE deadletter ADD COLUMN updated BIGINT;
+CREATE INDEX deadletter_state ON deadletter(namespace,state);
+COMMIT;
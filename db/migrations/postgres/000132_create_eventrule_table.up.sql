@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE eventrules (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  name             VARCHAR(64)     NOT NULL,
+  action           VARCHAR(64)     NOT NULL,
+  match_conditions TEXT,
+  targets          TEXT,
+  labels           TEXT,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX eventrules_id ON eventrules(namespace,id);
+CREATE UNIQUE INDEX eventrules_name ON eventrules(namespace,name);
+COMMIT;
@@ -0,0 +1,13 @@
+BEGIN;
+CREATE TABLE rolebindings (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  principal        VARCHAR(1024)   NOT NULL,
+  role             VARCHAR(64)     NOT NULL,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX rolebindings_id ON rolebindings(namespace,id);
+CREATE UNIQUE INDEX rolebindings_principal ON rolebindings(namespace,principal,role);
+COMMIT;
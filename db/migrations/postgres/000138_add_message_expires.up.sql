@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN expires BIGINT;
+CREATE INDEX messages_expires ON messages(namespace_local,state,expires);
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+CREATE TABLE messagereceipts (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  message_id       UUID            NOT NULL,
+  message_hash     CHAR(64)        NOT NULL,
+  rtype            VARCHAR(64)     NOT NULL,
+  identity         VARCHAR(1024)   NOT NULL,
+  node_id          UUID            NOT NULL,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX messagereceipts_id ON messagereceipts(namespace,id);
+CREATE UNIQUE INDEX messagereceipts_member ON messagereceipts(namespace,message_id,rtype,identity,node_id);
+COMMIT;
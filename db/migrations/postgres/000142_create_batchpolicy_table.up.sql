@@ -0,0 +1,13 @@
+BEGIN;
+CREATE TABLE batchpolicy (
+  seq              SERIAL          PRIMARY KEY,
+  namespace        VARCHAR(64)     NOT NULL,
+  topic            VARCHAR(64)     NOT NULL,
+  max_latency      BIGINT,
+  max_size         BIGINT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX batchpolicy_topic ON batchpolicy(namespace,topic);
+COMMIT;
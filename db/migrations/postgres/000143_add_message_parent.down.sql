@@ -0,0 +1,4 @@
+BEGIN;
+DROP INDEX IF EXISTS messages_parent;
+ALTER TABLE messages DROP COLUMN parent_message;
+COMMIT;
@@ -0,0 +1,4 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN parent_message UUID;
+CREATE INDEX messages_parent ON messages(namespace_local,parent_message);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE batches ADD COLUMN disclosure_salt CHAR(64);
+COMMIT;
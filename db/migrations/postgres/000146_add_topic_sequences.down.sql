@@ -0,0 +1,5 @@
+BEGIN;
+DROP TABLE IF EXISTS topicsequences;
+DROP INDEX IF EXISTS messages_topic_sequence;
+ALTER TABLE messages DROP COLUMN topic_sequence;
+COMMIT;
@@ -0,0 +1,16 @@
+BEGIN;
+ALTER TABLE messages ADD COLUMN topic_sequence BIGINT DEFAULT 0;
+CREATE INDEX messages_topic_sequence ON messages(namespace_local,topic_sequence);
+
+CREATE TABLE topicsequences (
+  seq              SERIAL          PRIMARY KEY,
+  namespace        VARCHAR(64)     NOT NULL,
+  context          CHAR(64)        NOT NULL,
+  topic            VARCHAR(64)     NOT NULL,
+  group_hash       CHAR(64),
+  next_sequence    BIGINT          NOT NULL,
+  updated          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX topicsequences_context ON topicsequences(namespace,context);
+COMMIT;
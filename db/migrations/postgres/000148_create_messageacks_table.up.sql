@@ -0,0 +1,14 @@
+BEGIN;
+CREATE TABLE messageacks (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  message_id       UUID            NOT NULL,
+  outcome          VARCHAR(64)     NOT NULL,
+  reason           TEXT,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX messageacks_id ON messageacks(namespace,id);
+CREATE UNIQUE INDEX messageacks_message ON messageacks(namespace,message_id);
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+CREATE TABLE blobuploads (
+  seq              SERIAL          PRIMARY KEY,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  upload_offset    BIGINT          NOT NULL,
+  size             BIGINT          NOT NULL,
+  hash             CHAR(64),
+  filename         VARCHAR(1024),
+  mimetype         VARCHAR(255),
+  autometa         BOOLEAN         NOT NULL,
+  validator        VARCHAR(64),
+  datatype_name    VARCHAR(64),
+  datatype_version VARCHAR(64),
+  value            TEXT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX blobuploads_id ON blobuploads(namespace,id);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE datatypes DROP COLUMN compatibility;
+COMMIT;
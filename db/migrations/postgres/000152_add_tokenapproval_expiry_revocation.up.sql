@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE tokenapproval ADD COLUMN expiry_revocation UUID;
+COMMIT;
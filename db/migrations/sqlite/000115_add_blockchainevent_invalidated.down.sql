@@ -0,0 +1,2 @@
+ALTER TABLE blockchainevents DROP COLUMN invalidated;
+ALTER TABLE tokentransfer DROP COLUMN invalidated;
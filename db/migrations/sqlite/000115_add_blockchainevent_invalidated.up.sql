@@ -0,0 +1,2 @@
+ALTER TABLE blockchainevents ADD COLUMN invalidated BOOLEAN DEFAULT false;
+ALTER TABLE tokentransfer ADD COLUMN invalidated BOOLEAN DEFAULT false;
@@ -0,0 +1,13 @@
+CREATE TABLE tokenmetadata (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  namespace        VARCHAR(64)     NOT NULL,
+  uri              VARCHAR(1024)   NOT NULL,
+  name             TEXT,
+  description      TEXT,
+  image            TEXT,
+  content          TEXT,
+  error            TEXT,
+  updated          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokenmetadata_uri ON tokenmetadata(namespace,uri);
@@ -0,0 +1,2 @@
+DROP INDEX tokenapproval_expiry;
+ALTER TABLE tokenapproval DROP COLUMN expiry;
@@ -0,0 +1,2 @@
+ALTER TABLE tokenapproval ADD COLUMN expiry BIGINT;
+CREATE INDEX tokenapproval_expiry ON tokenapproval(namespace,active,expiry);
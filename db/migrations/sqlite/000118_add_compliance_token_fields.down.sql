@@ -0,0 +1,2 @@
+ALTER TABLE tokenpool DROP COLUMN identity_registry;
+ALTER TABLE operations DROP COLUMN failure_reason;
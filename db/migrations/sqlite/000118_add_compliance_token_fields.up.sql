@@ -0,0 +1,2 @@
+ALTER TABLE operations ADD COLUMN failure_reason VARCHAR(64) DEFAULT '';
+ALTER TABLE tokenpool ADD COLUMN identity_registry VARCHAR(1024) DEFAULT '';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tokensnapshotbalance;
+DROP TABLE IF EXISTS tokensnapshot;
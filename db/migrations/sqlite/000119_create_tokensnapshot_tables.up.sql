@@ -0,0 +1,25 @@
+CREATE TABLE tokensnapshot (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  block_number     BIGINT,
+  timestamp        BIGINT,
+  transfers        BIGINT          NOT NULL,
+  accounts         BIGINT          NOT NULL,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokensnapshot_id ON tokensnapshot(namespace,id);
+CREATE INDEX tokensnapshot_pool ON tokensnapshot(namespace,pool_id);
+
+CREATE TABLE tokensnapshotbalance (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  snapshot_id      UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  token_index      VARCHAR(1024),
+  key              VARCHAR(1024)   NOT NULL,
+  balance          VARCHAR(65)
+);
+
+CREATE INDEX tokensnapshotbalance_snapshot ON tokensnapshotbalance(namespace,snapshot_id);
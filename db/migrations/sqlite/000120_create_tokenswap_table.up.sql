@@ -0,0 +1,18 @@
+CREATE TABLE tokenswap (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  state            VARCHAR(64)     NOT NULL,
+  legs             TEXT,
+  hash_lock        CHAR(64)        NOT NULL,
+  preimage         CHAR(64)        NOT NULL,
+  timeout          BIGINT,
+  tx_type          VARCHAR(64),
+  tx_id            UUID,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX tokenswap_id ON tokenswap(namespace,id);
+CREATE INDEX tokenswap_state ON tokenswap(namespace,state);
+CREATE INDEX tokenswap_tx ON tokenswap(namespace,tx_id);
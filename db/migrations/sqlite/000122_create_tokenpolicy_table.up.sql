@@ -0,0 +1,14 @@
+CREATE TABLE tokenpolicy (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  max_amount       VARCHAR(65),
+  daily_limit      VARCHAR(65),
+  senders          TEXT,
+  recipients       TEXT,
+  denied           TEXT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX tokenpolicy_pool ON tokenpolicy(namespace,pool_id);
@@ -0,0 +1,21 @@
+CREATE TABLE tokentransferrequest (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  state            VARCHAR(64)     NOT NULL,
+  type             VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  key              VARCHAR(1024)   NOT NULL,
+  amount           VARCHAR(65),
+  transfer         TEXT,
+  required         INTEGER,
+  signoffs         TEXT,
+  tx_type          VARCHAR(64),
+  tx_id            UUID,
+  error            TEXT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX tokentransferrequest_id ON tokentransferrequest(namespace,id);
+CREATE INDEX tokentransferrequest_state ON tokentransferrequest(namespace,state);
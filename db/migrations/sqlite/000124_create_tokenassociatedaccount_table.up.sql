@@ -0,0 +1,15 @@
+CREATE TABLE tokenassociatedaccount (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  owner            VARCHAR(1024)   NOT NULL,
+  account          VARCHAR(1024)   NOT NULL,
+  payer            VARCHAR(1024),
+  tx_type          VARCHAR(64),
+  tx_id            UUID,
+  blockchain_event UUID,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokenassociatedaccount_owner ON tokenassociatedaccount(namespace,pool_id,owner);
+CREATE INDEX tokenassociatedaccount_account ON tokenassociatedaccount(namespace,account);
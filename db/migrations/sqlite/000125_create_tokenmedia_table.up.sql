@@ -0,0 +1,13 @@
+CREATE TABLE tokenmedia (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  namespace        VARCHAR(64)     NOT NULL,
+  uri              VARCHAR(1024)   NOT NULL,
+  content_type     VARCHAR(255)    NOT NULL,
+  size             BIGINT          NOT NULL,
+  hash             CHAR(64)        NOT NULL,
+  payload_ref      VARCHAR(1024),
+  content          BLOB,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokenmedia_uri ON tokenmedia(namespace,uri);
@@ -0,0 +1,26 @@
+CREATE TABLE tokenbulkmint (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  connector        VARCHAR(64)     NOT NULL,
+  state            VARCHAR(64)     NOT NULL,
+  key              VARCHAR(1024)   NOT NULL,
+  to_key           VARCHAR(1024)   NOT NULL,
+  items            TEXT,
+  batch_size       BIGINT          NOT NULL,
+  total            BIGINT          NOT NULL,
+  submitted        BIGINT          NOT NULL,
+  succeeded        BIGINT          NOT NULL,
+  failed           BIGINT          NOT NULL,
+  config           TEXT,
+  tx_type          VARCHAR(64),
+  tx_id            UUID,
+  operation_id     UUID,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX tokenbulkmint_id ON tokenbulkmint(namespace,id);
+CREATE INDEX tokenbulkmint_state ON tokenbulkmint(namespace,state);
+CREATE INDEX tokenbulkmint_pool ON tokenbulkmint(namespace,pool_id);
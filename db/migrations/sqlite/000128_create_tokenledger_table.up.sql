@@ -0,0 +1,24 @@
+CREATE TABLE tokenledger (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  pool_id          UUID            NOT NULL,
+  account          VARCHAR(1024)   NOT NULL,
+  counterparty     VARCHAR(1024),
+  token_index      VARCHAR(1024),
+  transfer_type    VARCHAR(64)     NOT NULL,
+  transfer_id      UUID            NOT NULL,
+  debit            VARCHAR(65),
+  credit           VARCHAR(65),
+  balance          VARCHAR(65),
+  reversal         BOOLEAN         NOT NULL,
+  tx_type          VARCHAR(64),
+  tx_id            UUID,
+  blockchain_event UUID,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX tokenledger_id ON tokenledger(namespace,id);
+CREATE INDEX tokenledger_account ON tokenledger(namespace,pool_id,account);
+CREATE INDEX tokenledger_transfer ON tokenledger(namespace,transfer_id);
+CREATE INDEX tokenledger_created ON tokenledger(namespace,created);
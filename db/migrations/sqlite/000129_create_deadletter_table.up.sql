@@ -0,0 +1,17 @@
+CREATE TABLE deadletter (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  sub_id           UUID            NOT NULL,
+  sub_name         VARCHAR(64)     NOT NULL,
+  event_id         UUID            NOT NULL,
+  event_type       VARCHAR(64)     NOT NULL,
+  reference        UUID,
+  attempts         INTEGER         NOT NULL,
+  status           INTEGER,
+  error            TEXT,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX deadletter_id ON deadletter(namespace,id);
+CREATE INDEX deadletter_sub ON deadletter(namespace,sub_id);
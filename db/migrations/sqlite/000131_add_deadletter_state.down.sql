@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS deadletter_state;
+ALTER TABLE deadletter DROP COLUMN updated;
+ALTER TABLE deadletter DROP COLUMN original_event;
+ALTER TABLE deadletter DROP COLUMN history;
+ALTER TABLE deadletter DROP COLUMN state;
@@ -0,0 +1,14 @@
+CREATE TABLE eventrules (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  name             VARCHAR(64)     NOT NULL,
+  action           VARCHAR(64)     NOT NULL,
+  match_conditions TEXT,
+  targets          TEXT,
+  labels           TEXT,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX eventrules_id ON eventrules(namespace,id);
+CREATE UNIQUE INDEX eventrules_name ON eventrules(namespace,name);
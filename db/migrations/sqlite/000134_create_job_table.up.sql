@@ -0,0 +1,17 @@
+CREATE TABLE jobs (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  jtype            VARCHAR(64)     NOT NULL,
+  status           VARCHAR(64)     NOT NULL,
+  input            TEXT,
+  output           TEXT,
+  progress_done    BIGINT          NOT NULL,
+  progress_total   BIGINT          NOT NULL,
+  error            TEXT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX jobs_id ON jobs(namespace,id);
+CREATE INDEX jobs_status ON jobs(namespace,status);
@@ -0,0 +1,19 @@
+CREATE TABLE auditlog (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  principal        VARCHAR(1024),
+  method           VARCHAR(64)     NOT NULL,
+  route            VARCHAR(1024)   NOT NULL,
+  path             VARCHAR(1024),
+  request_hash     CHAR(64),
+  idempotency_key  VARCHAR(256),
+  tx_id            UUID,
+  status           INTEGER         NOT NULL,
+  error            TEXT,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX auditlog_id ON auditlog(namespace,id);
+CREATE INDEX auditlog_principal ON auditlog(namespace,principal);
+CREATE INDEX auditlog_created ON auditlog(namespace,created);
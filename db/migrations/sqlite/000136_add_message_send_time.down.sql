@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS messages_send_time;
+ALTER TABLE messages DROP COLUMN send_time;
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN send_time BIGINT;
+CREATE INDEX messages_send_time ON messages(namespace_local,state,send_time);
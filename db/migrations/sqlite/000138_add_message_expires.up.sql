@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN expires BIGINT;
+CREATE INDEX messages_expires ON messages(namespace_local,state,expires);
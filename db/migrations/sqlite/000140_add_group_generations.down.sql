@@ -0,0 +1,4 @@
+ALTER TABLE groups DROP COLUMN previous;
+ALTER TABLE groups DROP COLUMN generation;
+ALTER TABLE groups DROP COLUMN successor;
+ALTER TABLE groups DROP COLUMN superseded;
@@ -0,0 +1,4 @@
+ALTER TABLE groups ADD COLUMN previous CHAR(64);
+ALTER TABLE groups ADD COLUMN generation BIGINT DEFAULT 0;
+ALTER TABLE groups ADD COLUMN successor CHAR(64);
+ALTER TABLE groups ADD COLUMN superseded BIGINT;
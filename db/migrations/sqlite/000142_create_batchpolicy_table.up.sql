@@ -0,0 +1,11 @@
+CREATE TABLE batchpolicy (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  namespace        VARCHAR(64)     NOT NULL,
+  topic            VARCHAR(64)     NOT NULL,
+  max_latency      BIGINT,
+  max_size         BIGINT,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX batchpolicy_topic ON batchpolicy(namespace,topic);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS messages_parent;
+ALTER TABLE messages DROP COLUMN parent_message;
@@ -0,0 +1,2 @@
+ALTER TABLE messages ADD COLUMN parent_message UUID;
+CREATE INDEX messages_parent ON messages(namespace_local,parent_message);
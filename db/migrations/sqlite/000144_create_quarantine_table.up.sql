@@ -0,0 +1,19 @@
+CREATE TABLE quarantine (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  org              VARCHAR(256)    NOT NULL,
+  node_id          UUID,
+  peer             VARCHAR(256)    NOT NULL,
+  batch_id         UUID,
+  reason           VARCHAR(64)     NOT NULL,
+  messages         INTEGER         NOT NULL,
+  size             BIGINT          NOT NULL,
+  transport        TEXT            NOT NULL,
+  state            VARCHAR(64)     NOT NULL,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX quarantine_id ON quarantine(namespace,id);
+CREATE INDEX quarantine_org ON quarantine(namespace,org);
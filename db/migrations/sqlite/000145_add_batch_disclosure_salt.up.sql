@@ -0,0 +1 @@
+ALTER TABLE batches ADD COLUMN disclosure_salt CHAR(64);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS topicsequences;
+DROP INDEX IF EXISTS messages_topic_sequence;
+ALTER TABLE messages DROP COLUMN topic_sequence;
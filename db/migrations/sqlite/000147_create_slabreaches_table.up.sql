@@ -0,0 +1,16 @@
+CREATE TABLE slabreaches (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  btype            VARCHAR(64)     NOT NULL,
+  message_id       UUID            NOT NULL,
+  topic            VARCHAR(64)     NOT NULL,
+  org              VARCHAR(1024),
+  node_id          UUID,
+  sla              BIGINT          NOT NULL,
+  latency          BIGINT          NOT NULL,
+  created          BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX slabreaches_id ON slabreaches(namespace,id);
+CREATE INDEX slabreaches_message ON slabreaches(namespace,message_id);
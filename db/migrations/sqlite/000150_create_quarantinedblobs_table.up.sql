@@ -0,0 +1,17 @@
+CREATE TABLE quarantinedblobs (
+  seq              INTEGER         PRIMARY KEY AUTOINCREMENT,
+  id               UUID            NOT NULL,
+  namespace        VARCHAR(64)     NOT NULL,
+  data_id          UUID,
+  hash             CHAR(64)        NOT NULL,
+  size             BIGINT          NOT NULL,
+  peer             VARCHAR(256),
+  payload_ref      VARCHAR(1024)   NOT NULL,
+  finding          TEXT            NOT NULL,
+  state            VARCHAR(64)     NOT NULL,
+  created          BIGINT          NOT NULL,
+  updated          BIGINT
+);
+
+CREATE UNIQUE INDEX quarantinedblobs_id ON quarantinedblobs(namespace,id);
+CREATE INDEX quarantinedblobs_data ON quarantinedblobs(namespace,data_id);
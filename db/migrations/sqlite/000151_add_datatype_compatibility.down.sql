@@ -0,0 +1 @@
+ALTER TABLE datatypes DROP COLUMN compatibility;
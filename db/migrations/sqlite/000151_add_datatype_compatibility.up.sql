@@ -0,0 +1 @@
+ALTER TABLE datatypes ADD COLUMN compatibility VARCHAR(64) NOT NULL DEFAULT '';
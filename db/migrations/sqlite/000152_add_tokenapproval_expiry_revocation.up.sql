@@ -0,0 +1 @@
+ALTER TABLE tokenapproval ADD COLUMN expiry_revocation UUID;
@@ -0,0 +1,109 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountabstraction
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	// ConfigSmartAccounts is a list of signing keys that are ERC-4337 smart accounts, and must be submitted as UserOperations
+	ConfigSmartAccounts = "smartAccounts"
+	// ConfigEntryPoint is the address of the ERC-4337 EntryPoint contract
+	ConfigEntryPoint = "entryPoint"
+	// ConfigPaymaster is the address of an optional paymaster contract to sponsor gas for UserOperations
+	ConfigPaymaster = "paymaster"
+
+	defaultEntryPoint = "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"
+)
+
+// InitConfig adds the account abstraction keys to the config section of a plugin
+func InitConfig(conf config.Section) {
+	conf.AddKnownKey(ConfigSmartAccounts)
+	conf.AddKnownKey(ConfigEntryPoint, defaultEntryPoint)
+	conf.AddKnownKey(ConfigPaymaster)
+}
+
+// UserOperation instructs a connector to submit a transaction as an ERC-4337 UserOperation,
+// through a bundler, rather than as a transaction signed directly by the key. The signing key
+// is the address of the smart account, and is used as the "sender" of the UserOperation.
+type UserOperation struct {
+	EntryPoint string `json:"entryPoint"`
+	Paymaster  string `json:"paymaster,omitempty"`
+}
+
+// AccountAbstraction is the set of smart accounts configured for a plugin, and the UserOperation
+// details used to submit their transactions
+type AccountAbstraction struct {
+	smartAccounts map[string]bool
+	userOperation *UserOperation
+}
+
+// New returns the account abstraction configured for a plugin, or nil if there are no smart accounts
+func New(ctx context.Context, conf config.Section) (aa *AccountAbstraction, err error) {
+	accounts := conf.GetStringSlice(ConfigSmartAccounts)
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	aa = &AccountAbstraction{
+		smartAccounts: make(map[string]bool),
+		userOperation: &UserOperation{},
+	}
+	for _, account := range accounts {
+		address, err := formatAddress(ctx, account)
+		if err != nil {
+			return nil, err
+		}
+		aa.smartAccounts[address] = true
+	}
+	if aa.userOperation.EntryPoint, err = formatAddress(ctx, conf.GetString(ConfigEntryPoint)); err != nil {
+		return nil, err
+	}
+	if paymaster := conf.GetString(ConfigPaymaster); paymaster != "" {
+		if aa.userOperation.Paymaster, err = formatAddress(ctx, paymaster); err != nil {
+			return nil, err
+		}
+	}
+	return aa, nil
+}
+
+// UserOperationFor returns the UserOperation details for a transaction, if the signing key is a smart account
+func (aa *AccountAbstraction) UserOperationFor(signingKey string) *UserOperation {
+	if aa != nil && aa.smartAccounts[strings.ToLower(signingKey)] {
+		return aa.userOperation
+	}
+	return nil
+}
+
+// formatAddress returns the lower case form of an address, which must have a valid EIP-55 checksum if it is mixed case
+func formatAddress(ctx context.Context, address string) (string, error) {
+	parsed, err := ethtypes.NewAddressWithChecksum(strings.ToLower(address))
+	if err != nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgInvalidEthAddress)
+	}
+	hexDigits := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if hexDigits != strings.ToLower(hexDigits) && hexDigits != strings.ToUpper(hexDigits) && "0x"+hexDigits != parsed.String() {
+		return "", i18n.NewError(ctx, coremsgs.MsgInvalidEthAddressChecksum, address)
+	}
+	return ethtypes.Address0xHex(*parsed).String(), nil
+}
@@ -0,0 +1,112 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accountabstraction
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSmartAccount = "0x2a7c9d5248681ce6c393117e641ad037f5c079f6"
+	testPaymaster    = "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+)
+
+func newTestConfig() config.Section {
+	config.RootConfigReset()
+	conf := config.RootSection("accountAbstraction")
+	InitConfig(conf)
+	conf.Set(ConfigSmartAccounts, []string{testSmartAccount})
+	conf.Set(ConfigPaymaster, testPaymaster)
+	return conf
+}
+
+func TestNew(t *testing.T) {
+	conf := newTestConfig()
+	conf.Set(ConfigSmartAccounts, []string{"0x2A7C9D5248681CE6C393117E641AD037F5C079F6"})
+
+	aa, err := New(context.Background(), conf)
+	assert.NoError(t, err)
+	assert.True(t, aa.smartAccounts[testSmartAccount])
+	assert.Equal(t, "0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789", aa.userOperation.EntryPoint)
+	assert.Equal(t, testPaymaster, aa.userOperation.Paymaster)
+}
+
+func TestNewNoSmartAccounts(t *testing.T) {
+	conf := newTestConfig()
+	conf.Set(ConfigSmartAccounts, []string{})
+
+	aa, err := New(context.Background(), conf)
+	assert.NoError(t, err)
+	assert.Nil(t, aa)
+	assert.Nil(t, aa.UserOperationFor(testSmartAccount))
+}
+
+func TestNewNoPaymaster(t *testing.T) {
+	conf := newTestConfig()
+	conf.Set(ConfigPaymaster, "")
+
+	aa, err := New(context.Background(), conf)
+	assert.NoError(t, err)
+	assert.Empty(t, aa.userOperation.Paymaster)
+}
+
+func TestNewBadSmartAccount(t *testing.T) {
+	conf := newTestConfig()
+	conf.Set(ConfigSmartAccounts, []string{"bad"})
+
+	_, err := New(context.Background(), conf)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestNewBadSmartAccountChecksum(t *testing.T) {
+	conf := newTestConfig()
+	conf.Set(ConfigSmartAccounts, []string{"0x2a7C9D5248681CE6C393117E641AD037F5C079F6"})
+
+	_, err := New(context.Background(), conf)
+	assert.Regexp(t, "FF10460", err)
+}
+
+func TestNewBadEntryPoint(t *testing.T) {
+	conf := newTestConfig()
+	conf.Set(ConfigEntryPoint, "bad")
+
+	_, err := New(context.Background(), conf)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestNewBadPaymaster(t *testing.T) {
+	conf := newTestConfig()
+	conf.Set(ConfigPaymaster, "bad")
+
+	_, err := New(context.Background(), conf)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestUserOperationFor(t *testing.T) {
+	aa, err := New(context.Background(), newTestConfig())
+	assert.NoError(t, err)
+
+	assert.Equal(t, &UserOperation{
+		EntryPoint: "0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789",
+		Paymaster:  testPaymaster,
+	}, aa.UserOperationFor("0x2A7C9D5248681CE6C393117E641AD037F5C079F6"))
+	assert.Nil(t, aa.UserOperationFor("0x01020304"))
+}
@@ -0,0 +1,134 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type auditContextKey struct{}
+
+// auditRecord collects the details of a call that are only known inside the route handler
+type auditRecord struct {
+	idempotencyKey core.IdempotencyKey
+	tx             *fftypes.UUID
+	err            error
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+type hashingBody struct {
+	io.Reader
+	io.Closer
+}
+
+// isAuditedRoute is true for the routes that change a namespace - everything a reader cannot call
+func isAuditedRoute(route *ffapi.Route) bool {
+	return requiredRole(route) != core.RoleReader
+}
+
+// auditHandler records each call to a route in the audit log, once the handler has completed
+func (as *apiServer) auditHandler(route *ffapi.Route, handler http.HandlerFunc) http.HandlerFunc {
+	routeName := strings.TrimPrefix(route.Path, "namespaces/{ns}/")
+	return func(res http.ResponseWriter, req *http.Request) {
+		hash := sha256.New()
+		if req.Body != nil {
+			req.Body = &hashingBody{Reader: io.TeeReader(req.Body, hash), Closer: req.Body}
+		}
+		record := &auditRecord{}
+		sw := &statusWriter{ResponseWriter: res, status: http.StatusOK}
+		handler(sw, req.WithContext(context.WithValue(req.Context(), auditContextKey{}, record)))
+		if req.Body != nil {
+			// Include any of the body the handler did not read in the hash
+			_, _ = io.Copy(io.Discard, req.Body)
+		}
+
+		entry := &core.AuditLogEntry{
+			Namespace:      mux.Vars(req)["ns"],
+			Principal:      orchestrator.Principal(req.Context(), req.Header),
+			Method:         req.Method,
+			Route:          routeName,
+			Path:           req.URL.Path,
+			RequestHash:    fftypes.HashResult(hash),
+			IdempotencyKey: record.idempotencyKey,
+			Transaction:    record.tx,
+			Status:         sw.status,
+		}
+		if record.err != nil {
+			entry.Error = record.err.Error()
+		}
+		as.auditLog.Record(req.Context(), entry)
+	}
+}
+
+// recordAuditDetails adds the idempotency key of the input, and the transaction of the output, to the
+// audit record of the request, if it is being audited
+func recordAuditDetails(r *ffapi.APIRequest, output interface{}, err error) {
+	record, ok := r.Req.Context().Value(auditContextKey{}).(*auditRecord)
+	if !ok {
+		return
+	}
+	if key := inputIdempotencyKey(r.Input); key != nil {
+		record.idempotencyKey = *key
+	}
+	record.tx = outputTransaction(output)
+	record.err = err
+}
+
+// outputTransaction is the FireFly transaction started by a route, from its output
+func outputTransaction(output interface{}) *fftypes.UUID {
+	switch out := output.(type) {
+	case *core.Message:
+		return out.TransactionID
+	case *core.Operation:
+		return out.Transaction
+	case *core.TokenPool:
+		return out.TX.ID
+	case *core.TokenTransfer:
+		return out.TX.ID
+	case *core.TokenTransferBatch:
+		return out.TX.ID
+	case *core.TokenBulkMint:
+		return out.TX.ID
+	case *core.TokenApproval:
+		return out.TX.ID
+	case *core.TokenSwap:
+		return out.TX.ID
+	case *core.TokenPoolMigration:
+		return out.TX.ID
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly/internal/auditlog"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/mocks/spieventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestAuditServer(t *testing.T) (*orchestratormocks.Orchestrator, *apiServer, *namespacemocks.Manager) {
+	mgr, o, as := newTestServer()
+	var err error
+	as.auditLog, err = auditlog.NewLogger(context.Background(), mgr)
+	assert.NoError(t, err)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	return o, as, mgr
+}
+
+func TestAuditLogBroadcast(t *testing.T) {
+	o, as, mgr := newTestAuditServer(t)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	txID := fftypes.NewUUID()
+	mbm := &broadcastmocks.Manager{}
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	o.On("Broadcast").Return(mbm)
+	mbm.On("BroadcastMessage", mock.Anything, mock.Anything, false).Return(&core.Message{TransactionID: txID}, nil)
+
+	body := `{"header":{"tag":"tag1"}}`
+	hash := fftypes.Bytes32(sha256.Sum256([]byte(body)))
+	o.On("RecordAuditLogEntry", mock.Anything, mock.MatchedBy(func(entry *core.AuditLogEntry) bool {
+		return entry.Namespace == "ns1" &&
+			entry.Principal == "alice" &&
+			entry.Method == "POST" &&
+			entry.Route == "messages/broadcast" &&
+			entry.Path == "/api/v1/namespaces/ns1/messages/broadcast" &&
+			entry.RequestHash.Equals(&hash) &&
+			entry.IdempotencyKey == "idem1" &&
+			entry.Transaction.Equals(txID) &&
+			entry.Status == 202 &&
+			entry.Error == ""
+	})).Return(nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(idempotencyKeyHeader, "idem1")
+	req.SetBasicAuth("alice", "secret")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	o.AssertExpectations(t)
+}
+
+func TestAuditLogFailedCall(t *testing.T) {
+	o, as, mgr := newTestAuditServer(t)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	mbm := &broadcastmocks.Manager{}
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	o.On("Broadcast").Return(mbm)
+	mbm.On("BroadcastMessage", mock.Anything, mock.Anything, false).Return(nil, fmt.Errorf("pop"))
+	o.On("RecordAuditLogEntry", mock.Anything, mock.MatchedBy(func(entry *core.AuditLogEntry) bool {
+		return entry.Principal == "" &&
+			entry.Transaction == nil &&
+			entry.Status == 500 &&
+			entry.Error == "pop"
+	})).Return(nil)
+
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+	o.AssertExpectations(t)
+}
+
+func TestAuditLogSkipsReads(t *testing.T) {
+	o, as, mgr := newTestAuditServer(t)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	o.On("GetMessages", mock.Anything, mock.Anything).Return([]*core.Message{}, nil, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	o.AssertNotCalled(t, "RecordAuditLogEntry", mock.Anything, mock.Anything)
+}
+
+func TestOutputTransaction(t *testing.T) {
+	txID := fftypes.NewUUID()
+	txRef := core.TransactionRef{ID: txID}
+	for _, output := range []interface{}{
+		&core.Message{TransactionID: txID},
+		&core.Operation{Transaction: txID},
+		&core.TokenPool{TX: txRef},
+		&core.TokenTransfer{TX: txRef},
+		&core.TokenTransferBatch{TX: txRef},
+		&core.TokenBulkMint{TX: txRef},
+		&core.TokenApproval{TX: txRef},
+		&core.TokenSwap{TX: txRef},
+		&core.TokenPoolMigration{TX: txRef},
+	} {
+		assert.Equal(t, txID, outputTransaction(output))
+	}
+	assert.Nil(t, outputTransaction(&core.Data{}))
+}
+
+func TestStartAuditLog(t *testing.T) {
+	coreconfig.Reset()
+	metrics.Clear()
+	InitConfig()
+	apiConfig.Set(httpserver.HTTPConfPort, 0)
+	config.Set(coreconfig.AuditEnabled, true)
+	config.Set(coreconfig.AuditExportFile, filepath.Join(t.TempDir(), "audit.log"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // server will immediately shut down
+	as := NewAPIServer()
+	mgr := &namespacemocks.Manager{}
+	mgr.On("SPIEvents").Return(&spieventsmocks.Manager{})
+	err := as.Serve(ctx, mgr)
+	assert.NoError(t, err)
+}
+
+func TestStartAuditLogExportFileFail(t *testing.T) {
+	coreconfig.Reset()
+	metrics.Clear()
+	InitConfig()
+	config.Set(coreconfig.AuditEnabled, true)
+	config.Set(coreconfig.AuditExportFile, filepath.Join(t.TempDir(), "missing", "audit.log"))
+	as := NewAPIServer()
+	err := as.Serve(context.Background(), &namespacemocks.Manager{})
+	assert.Regexp(t, "FF10604", err)
+}
@@ -0,0 +1,263 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/metrics"
+)
+
+const (
+	connLimitScopeListener  = "listener"
+	connLimitScopePrincipal = "principal"
+
+	connTypeHTTP      = "http"
+	connTypeWebSocket = "websocket"
+
+	// connLimitRetryAfter is the Retry-After for a shed request, long enough to spread out a reconnect storm
+	connLimitRetryAfter = "1"
+	// maxCloseReason is the longest reason that fits in a WebSocket close frame
+	maxCloseReason = 123
+)
+
+var connTypeDescriptions = map[string]string{
+	connTypeHTTP:      "requests",
+	connTypeWebSocket: "WebSocket connections",
+}
+
+// connLimits counts the concurrent HTTP requests and WebSocket connections of each key in a scope
+type connLimits struct {
+	scope          string
+	max            map[string]int
+	metricsEnabled bool
+
+	mux    sync.Mutex
+	active map[string]map[string]int
+}
+
+func newConnLimits(scope string, maxRequests, maxWebSockets int, metricsEnabled bool) *connLimits {
+	return &connLimits{
+		scope: scope,
+		max: map[string]int{
+			connTypeHTTP:      maxRequests,
+			connTypeWebSocket: maxWebSockets,
+		},
+		metricsEnabled: metricsEnabled,
+		active:         make(map[string]map[string]int),
+	}
+}
+
+// acquire counts a connection against the limit of a key, unless the key is already at the limit
+func (cl *connLimits) acquire(ctx context.Context, connType, key string) error {
+	max := cl.max[connType]
+	if max <= 0 {
+		return nil
+	}
+
+	cl.mux.Lock()
+	defer cl.mux.Unlock()
+	active := cl.active[connType]
+	if active == nil {
+		active = make(map[string]int)
+		cl.active[connType] = active
+	}
+	if active[key] >= max {
+		if cl.metricsEnabled {
+			metrics.ConnectionsShedCounter.WithLabelValues(connType, cl.scope, key).Inc()
+		}
+		return i18n.NewError(ctx, coremsgs.MsgConnectionLimitExceeded, max, connTypeDescriptions[connType], cl.scope, key)
+	}
+	active[key]++
+	cl.updated(connType, key, active[key])
+	return nil
+}
+
+func (cl *connLimits) release(connType, key string) {
+	if cl.max[connType] <= 0 {
+		return
+	}
+
+	cl.mux.Lock()
+	defer cl.mux.Unlock()
+	active := cl.active[connType]
+	active[key]--
+	count := active[key]
+	if count <= 0 {
+		// Keys are removed when idle, so a storm of clients with different addresses does not grow the map
+		delete(active, key)
+	}
+	cl.updated(connType, key, count)
+}
+
+func (cl *connLimits) updated(connType, key string, count int) {
+	if cl.metricsEnabled {
+		metrics.ConnectionsActiveGauge.WithLabelValues(connType, cl.scope, key).Set(float64(count))
+	}
+}
+
+// connLimiter sheds HTTP requests and WebSocket connections over the limits for each listener and principal,
+// so a storm of reconnecting clients cannot exhaust the file descriptors of the node
+type connLimiter struct {
+	listener  *connLimits
+	principal *connLimits
+}
+
+func newConnLimiter(metricsEnabled bool) *connLimiter {
+	return &connLimiter{
+		listener: newConnLimits(connLimitScopeListener,
+			config.GetInt(coreconfig.ConnLimitListenerMaxRequests),
+			config.GetInt(coreconfig.ConnLimitListenerMaxWebSockets),
+			metricsEnabled),
+		principal: newConnLimits(connLimitScopePrincipal,
+			config.GetInt(coreconfig.ConnLimitPrincipalMaxRequests),
+			config.GetInt(coreconfig.ConnLimitPrincipalMaxWebSockets),
+			metricsEnabled),
+	}
+}
+
+// listenerKey is the local address the request was accepted on, which is the path for a Unix domain socket
+func listenerKey(req *http.Request) string {
+	if addr, ok := req.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		return addr.String()
+	}
+	return req.Host
+}
+
+// acquire counts a connection against the listener and principal limits, returning a function to release it
+// that is safe to call more than once
+func (cl *connLimiter) acquire(req *http.Request, connType string) (func(), error) {
+	ctx := req.Context()
+	listener := listenerKey(req)
+	if err := cl.listener.acquire(ctx, connType, listener); err != nil {
+		return nil, err
+	}
+	principal := principalKey(req)
+	if err := cl.principal.acquire(ctx, connType, principal); err != nil {
+		cl.listener.release(connType, listener)
+		return nil, err
+	}
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cl.principal.release(connType, principal)
+			cl.listener.release(connType, listener)
+		})
+	}, nil
+}
+
+// middleware sheds HTTP requests over the limits with a 503, and WebSocket connections over the limits with a
+// try again later close frame. A WebSocket connection is counted until the hijacked connection is closed.
+func (cl *connLimiter) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		connType := connTypeHTTP
+		if websocket.IsWebSocketUpgrade(req) {
+			connType = connTypeWebSocket
+		}
+		release, err := cl.acquire(req, connType)
+		if err != nil {
+			log.L(req.Context()).Warnf("Shedding %s %s: %s", req.Method, req.URL.Path, err)
+			if connType == connTypeWebSocket {
+				shedWebSocket(res, req, err)
+			} else {
+				shedRequest(res, err)
+			}
+			return
+		}
+		if connType == connTypeWebSocket {
+			if hijacker, ok := res.(http.Hijacker); ok {
+				lw := &connLimitWriter{ResponseWriter: res, hijacker: hijacker, release: release}
+				next.ServeHTTP(lw, req)
+				if !lw.hijacked {
+					release()
+				}
+				return
+			}
+		}
+		defer release()
+		next.ServeHTTP(res, req)
+	})
+}
+
+func shedRequest(res http.ResponseWriter, err error) {
+	res.Header().Set("Content-Type", "application/json")
+	res.Header().Set("Retry-After", connLimitRetryAfter)
+	res.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(res).Encode(&fftypes.RESTError{Error: err.Error()})
+}
+
+// shedWebSocket completes the upgrade only to tell the client to try again later, as WebSocket clients
+// generally report a failed upgrade without its status
+func shedWebSocket(res http.ResponseWriter, req *http.Request, err error) {
+	upgrader := &websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			// Nothing is exchanged over the connection, other than the close frame
+			return true
+		},
+	}
+	conn, upgradeErr := upgrader.Upgrade(res, req, http.Header{"Retry-After": []string{connLimitRetryAfter}})
+	if upgradeErr != nil {
+		// The upgrader has written an error response
+		return
+	}
+	defer conn.Close()
+	reason := err.Error()
+	if len(reason) > maxCloseReason {
+		reason = reason[:maxCloseReason]
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, reason), time.Now().Add(time.Second))
+}
+
+// connLimitWriter releases a WebSocket connection from the limits when the connection hijacked by the upgrade is closed
+type connLimitWriter struct {
+	http.ResponseWriter
+	hijacker http.Hijacker
+	release  func()
+	hijacked bool
+}
+
+func (lw *connLimitWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := lw.hijacker.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	lw.hijacked = true
+	return &connLimitConn{Conn: conn, release: lw.release}, rw, nil
+}
+
+type connLimitConn struct {
+	net.Conn
+	release func()
+}
+
+func (lc *connLimitConn) Close() error {
+	defer lc.release()
+	return lc.Conn.Close()
+}
@@ -0,0 +1,253 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConnLimiter(listenerRequests, listenerWebSockets, principalRequests, principalWebSockets int, metricsEnabled bool) *connLimiter {
+	return &connLimiter{
+		listener:  newConnLimits(connLimitScopeListener, listenerRequests, listenerWebSockets, metricsEnabled),
+		principal: newConnLimits(connLimitScopePrincipal, principalRequests, principalWebSockets, metricsEnabled),
+	}
+}
+
+func newTestWebSocketServer(cl *connLimiter) *httptest.Server {
+	upgrader := &websocket.Upgrader{}
+	handler := cl.middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/reject" {
+			res.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		conn, err := upgrader.Upgrade(res, req, nil)
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+	}))
+	// The principal is set in the same way as an identity mapped from a client certificate
+	return httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if principal := req.Header.Get("X-Test-Principal"); principal != "" {
+			req = req.WithContext(orchestrator.WithPrincipal(req.Context(), principal))
+		}
+		handler.ServeHTTP(res, req)
+	}))
+}
+
+func TestNewAPIServerConnLimits(t *testing.T) {
+	coreconfig.Reset()
+	assert.Nil(t, NewAPIServer().(*apiServer).connLimiter)
+
+	config.Set(coreconfig.ConnLimitEnabled, true)
+	config.Set(coreconfig.ConnLimitListenerMaxRequests, 100)
+	config.Set(coreconfig.ConnLimitPrincipalMaxWebSockets, 5)
+	cl := NewAPIServer().(*apiServer).connLimiter
+	assert.NotNil(t, cl)
+	assert.Equal(t, 100, cl.listener.max[connTypeHTTP])
+	assert.Equal(t, 0, cl.listener.max[connTypeWebSocket])
+	assert.Equal(t, 0, cl.principal.max[connTypeHTTP])
+	assert.Equal(t, 5, cl.principal.max[connTypeWebSocket])
+}
+
+func TestConnLimitRequests(t *testing.T) {
+	mgr, _, as := newTestServer()
+	as.connLimiter = newTestConnLimiter(1, 0, 0, 0, false)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	// Hold the only request slot of the listener
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	release, err := as.connLimiter.acquire(req, connTypeHTTP)
+	assert.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 503, res.Result().StatusCode)
+	assert.Equal(t, "1", res.Result().Header.Get("Retry-After"))
+	assert.Regexp(t, "FF10606.*1 concurrent requests.*listener 'example.com'", res.Body.String())
+
+	release()
+	release()
+	assert.Empty(t, as.connLimiter.listener.active[connTypeHTTP])
+
+	req = httptest.NewRequest("GET", "/api/swagger.json", nil)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, as.connLimiter.listener.active[connTypeHTTP])
+}
+
+func TestConnLimitAdminRequests(t *testing.T) {
+	mgr, _, as := newTestServer()
+	as.connLimiter = newTestConnLimiter(0, 0, 1, 0, false)
+	r := as.createAdminMuxRouter(mgr)
+
+	req := httptest.NewRequest("GET", "/spi/swagger.json", nil)
+	req = req.WithContext(orchestrator.WithPrincipal(req.Context(), "team-a"))
+	_, err := as.connLimiter.acquire(req, connTypeHTTP)
+	assert.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 503, res.Result().StatusCode)
+	assert.Regexp(t, "FF10606.*principal 'team-a'", res.Body.String())
+
+	// Other principals have their own limits
+	req = httptest.NewRequest("GET", "/spi/swagger.json", nil)
+	req = req.WithContext(orchestrator.WithPrincipal(req.Context(), "team-b"))
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestConnLimitPrincipalReleasesListener(t *testing.T) {
+	cl := newTestConnLimiter(2, 0, 1, 0, false)
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	_, err := cl.acquire(req, connTypeHTTP)
+	assert.NoError(t, err)
+
+	_, err = cl.acquire(req, connTypeHTTP)
+	assert.Regexp(t, "FF10606.*principal '192.0.2.1'", err)
+	assert.Equal(t, 1, cl.listener.active[connTypeHTTP]["example.com"])
+}
+
+func TestConnLimitWebSockets(t *testing.T) {
+	metrics.Clear()
+	metrics.Registry()
+	cl := newTestConnLimiter(0, 1, 0, 0, true)
+	server := newTestWebSocketServer(cl)
+	defer server.Close()
+	url := fmt.Sprintf("ws://%s/ws", server.Listener.Addr())
+	listener := server.Listener.Addr().String()
+
+	conn1, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ConnectionsActiveGauge.WithLabelValues("websocket", "listener", listener)))
+
+	// The connection over the limit is told to try again later
+	conn2, res, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", res.Header.Get("Retry-After"))
+	_, _, err = conn2.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	assert.True(t, ok)
+	assert.Equal(t, websocket.CloseTryAgainLater, closeErr.Code)
+	assert.Regexp(t, "FF10606.*WebSocket connections.*listener", closeErr.Text)
+	conn2.Close()
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.ConnectionsShedCounter.WithLabelValues("websocket", "listener", listener)))
+
+	// Closing the first connection frees the slot
+	conn1.Close()
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(metrics.ConnectionsActiveGauge.WithLabelValues("websocket", "listener", listener)) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	conn3, _, err := websocket.DefaultDialer.Dial(url, nil)
+	assert.NoError(t, err)
+	conn3.Close()
+}
+
+func TestConnLimitWebSocketNotUpgraded(t *testing.T) {
+	cl := newTestConnLimiter(0, 1, 0, 0, false)
+	server := newTestWebSocketServer(cl)
+	defer server.Close()
+
+	_, res, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s/reject", server.Listener.Addr()), nil)
+	assert.Error(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+	assert.Empty(t, cl.listener.active[connTypeWebSocket])
+}
+
+func TestConnLimitWebSocketNoHijacker(t *testing.T) {
+	cl := newTestConnLimiter(0, 1, 0, 0, false)
+	handler := cl.middleware(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, 204, res.Result().StatusCode)
+	assert.Empty(t, cl.listener.active[connTypeWebSocket])
+}
+
+func TestConnLimitShedWebSocketBadUpgrade(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	res := httptest.NewRecorder()
+	shedWebSocket(res, req, fmt.Errorf("pop"))
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
+
+func TestConnLimitShedWebSocketLongReason(t *testing.T) {
+	cl := newTestConnLimiter(0, 0, 0, 1, false)
+	principal := strings.Repeat("a", 200)
+	cl.principal.active[connTypeWebSocket] = map[string]int{principal: 1}
+	server := newTestWebSocketServer(cl)
+	defer server.Close()
+
+	req := httptest.NewRequest("GET", "/ws", nil)
+	req.Header.Set("X-Test-Principal", principal)
+	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s/ws", server.Listener.Addr()), req.Header)
+	assert.NoError(t, err)
+	_, _, err = conn.ReadMessage()
+	closeErr, ok := err.(*websocket.CloseError)
+	assert.True(t, ok)
+	assert.Len(t, closeErr.Text, maxCloseReason)
+	conn.Close()
+}
+
+type testFailingHijacker struct {
+	http.ResponseWriter
+}
+
+func (h *testFailingHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, fmt.Errorf("pop")
+}
+
+func TestConnLimitWriterHijackFail(t *testing.T) {
+	lw := &connLimitWriter{ResponseWriter: httptest.NewRecorder(), hijacker: &testFailingHijacker{}, release: func() {}}
+	_, _, err := lw.Hijack()
+	assert.Regexp(t, "pop", err)
+	assert.False(t, lw.hijacked)
+}
@@ -0,0 +1,86 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+)
+
+const (
+	etagHeader        = "ETag"
+	ifNoneMatchHeader = "If-None-Match"
+)
+
+// isResourceRoute checks a route gets a single resource, such as a message or a token pool, rather
+// than a list or a file. The responses of these routes have an ETag, so clients polling them can
+// make conditional requests.
+func isResourceRoute(route *ffapi.Route) bool {
+	if route.Method != http.MethodGet || route.JSONOutputValue == nil || route.FilterFactory != nil {
+		return false
+	}
+	t := reflect.TypeOf(route.JSONOutputValue())
+	return t != nil && t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct
+}
+
+// resourceETag is a strong ETag calculated from the JSON of a resource, so it changes whenever any
+// field of the resource changes, including its updated timestamp
+func resourceETag(output interface{}) (string, bool) {
+	v := reflect.ValueOf(output)
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return "", false
+	}
+	b, err := json.Marshal(output)
+	if err != nil {
+		return "", false
+	}
+	hash := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(hash[:]) + `"`, true
+}
+
+// etagMatches checks an If-None-Match header against an ETag, using weak comparison as required for
+// If-None-Match by RFC 9110
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// applyETag sets the ETag of the resource returned by a route, replacing it with an empty
+// 304 Not Modified response when the client already has it
+func applyETag(r *ffapi.APIRequest, output interface{}) interface{} {
+	etag, ok := resourceETag(output)
+	if !ok {
+		return output
+	}
+	r.ResponseHeaders.Set(etagHeader, etag)
+	if ifNoneMatch := r.Req.Header.Get(ifNoneMatchHeader); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+		r.SuccessStatus = http.StatusNotModified
+		return http.NoBody
+	}
+	return output
+}
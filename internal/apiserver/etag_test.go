@@ -0,0 +1,136 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIsResourceRoute(t *testing.T) {
+	assert.True(t, isResourceRoute(getMsgByID))
+	assert.True(t, isResourceRoute(getTokenPoolByNameOrID))
+	assert.True(t, isResourceRoute(getContractInterface))
+	assert.False(t, isResourceRoute(getMsgs))
+	assert.False(t, isResourceRoute(getMsgData))
+	assert.False(t, isResourceRoute(getTokenSnapshotExport))
+	assert.False(t, isResourceRoute(postNewMessageBroadcast))
+}
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`"xyz", W/"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`*`, `"abc"`))
+	assert.False(t, etagMatches(`"xyz"`, `"abc"`))
+	assert.False(t, etagMatches(`abc`, `"abc"`))
+}
+
+func TestResourceETag(t *testing.T) {
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	etag1, ok := resourceETag(msg)
+	assert.True(t, ok)
+	etag2, _ := resourceETag(msg)
+	assert.Equal(t, etag1, etag2)
+
+	msg.State = core.MessageStateConfirmed
+	etag2, _ = resourceETag(msg)
+	assert.NotEqual(t, etag1, etag2)
+
+	_, ok = resourceETag((*core.Message)(nil))
+	assert.False(t, ok)
+	_, ok = resourceETag(map[bool]bool{true: true})
+	assert.False(t, ok)
+}
+
+func TestGetResourceETag(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	o.On("GetMessageByID", mock.Anything, "msg1").Return(msg, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages/msg1", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	etag := res.Result().Header.Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{64}"$`, etag)
+
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages/msg1", nil)
+	req.Header.Set("If-None-Match", etag)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 304, res.Result().StatusCode)
+	assert.Equal(t, etag, res.Result().Header.Get("ETag"))
+	b, _ := io.ReadAll(res.Body)
+	assert.Empty(t, b)
+
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages/msg1", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetResourceETagFields(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	o.On("GetMessageByID", mock.Anything, "msg1").Return(msg, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages/msg1", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	etag := res.Result().Header.Get("ETag")
+
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages/msg1?fields=header.id", nil)
+	req.Header.Set("If-None-Match", etag)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.NotEqual(t, etag, res.Result().Header.Get("ETag"))
+}
+
+func TestGetResourceETagNotFound(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetMessageByID", mock.Anything, "msg1").Return(nil, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages/msg1", nil)
+	req.Header.Set("If-None-Match", "*")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 404, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get("ETag"))
+}
+
+func TestGetListNoETag(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetMessages", mock.Anything, mock.Anything).Return([]*core.Message{}, nil, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/messages", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get("ETag"))
+}
@@ -0,0 +1,181 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const fieldsParam = "fields"
+
+var (
+	jsonAnyType       = reflect.TypeOf(fftypes.JSONAny(""))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// withFieldsParam adds the fields query parameter to all the GET routes that return JSON objects
+func withFieldsParam(routes []*ffapi.Route) []*ffapi.Route {
+	for _, route := range routes {
+		if route.Method == http.MethodGet && route.JSONOutputValue != nil && isObjectOutput(route) {
+			// The namespaced copies of a route share its query params, so a new slice is required
+			queryParams := make([]*ffapi.QueryParam, 0, len(route.QueryParams)+1)
+			queryParams = append(queryParams, route.QueryParams...)
+			route.QueryParams = append(queryParams, &ffapi.QueryParam{
+				Name: fieldsParam, Description: coremsgs.APIParamsFields,
+			})
+		}
+	}
+	return routes
+}
+
+// isObjectOutput checks the output of a route is an object, or a list of objects, rather than a file
+func isObjectOutput(route *ffapi.Route) bool {
+	t := reflect.TypeOf(route.JSONOutputValue())
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+		t = t.Elem()
+	}
+	return t != nil && (t.Kind() == reflect.Struct || t.Kind() == reflect.Map)
+}
+
+// parseFields parses a comma separated list of the JSON fields to return, such as "header.id,state",
+// checking each is a field of the output of the route
+func parseFields(ctx context.Context, route *ffapi.Route, value string) ([]string, error) {
+	if value == "" || route.JSONOutputValue == nil {
+		return nil, nil
+	}
+	outputType := reflect.TypeOf(route.JSONOutputValue())
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !isFieldPath(outputType, strings.Split(field, ".")) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidFieldsParam, field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func isFieldPath(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return true
+	}
+	switch {
+	case t == jsonAnyType || t.Kind() == reflect.Map || t.Kind() == reflect.Interface:
+		// Any field might be within these
+		return true
+	case reflect.PtrTo(t).Implements(jsonMarshalerType) || t.Kind() != reflect.Struct:
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		switch {
+		case name == "-" || !f.IsExported():
+		case name == "" && f.Anonymous:
+			if isFieldPath(f.Type, path) {
+				return true
+			}
+		case name == path[0] || (name == "" && f.Name == path[0]):
+			return isFieldPath(f.Type, path[1:])
+		}
+	}
+	return false
+}
+
+// projectOutput reduces the output of a route to the requested fields, applying the fields to each
+// item when the output is a list
+func projectOutput(output interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 || output == nil {
+		return output, nil
+	}
+	if v := reflect.ValueOf(output); v.Kind() == reflect.Ptr && v.IsNil() {
+		return output, nil
+	}
+	if withCount, ok := output.(*ffapi.FilterResultsWithCount); ok {
+		items, err := projectOutput(withCount.Items, fields)
+		if err != nil {
+			return nil, err
+		}
+		return &ffapi.FilterResultsWithCount{
+			Count: withCount.Count,
+			Total: withCount.Total,
+			Items: items,
+		}, nil
+	}
+	b, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&value); err != nil {
+		return nil, err
+	}
+	paths := make([][]string, len(fields))
+	for i, field := range fields {
+		paths[i] = strings.Split(field, ".")
+	}
+	return projectValue(value, paths), nil
+}
+
+func projectValue(value interface{}, paths [][]string) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			v[i] = projectValue(v[i], paths)
+		}
+		return v
+	case map[string]interface{}:
+		whole := make(map[string]bool)
+		within := make(map[string][][]string)
+		for _, path := range paths {
+			if len(path) == 1 {
+				whole[path[0]] = true
+			} else {
+				within[path[0]] = append(within[path[0]], path[1:])
+			}
+		}
+		projected := make(map[string]interface{})
+		for name, child := range v {
+			if whole[name] {
+				projected[name] = child
+			} else if childPaths, ok := within[name]; ok {
+				projected[name] = projectValue(child, childPaths)
+			}
+		}
+		return projected
+	default:
+		return value
+	}
+}
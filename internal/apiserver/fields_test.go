@@ -0,0 +1,141 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFieldsParamAdded(t *testing.T) {
+	assert.Equal(t, fieldsParam, getOps.QueryParams[len(getOps.QueryParams)-1].Name)
+	assert.Len(t, getMsgs.QueryParams, 4)
+	for _, qp := range append(postNewMessageBroadcast.QueryParams, getTokenTransfersExport.QueryParams...) {
+		assert.NotEqual(t, fieldsParam, qp.Name)
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	ctx := context.Background()
+	fields, err := parseFields(ctx, getMsgs, " header.id, state,,header.txparent.type,data")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"header.id", "state", "header.txparent.type", "data"}, fields)
+
+	fields, err = parseFields(ctx, getOps, "input.any.thing,updated")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"input.any.thing", "updated"}, fields)
+
+	fields, err = parseFields(ctx, getMsgs, "")
+	assert.NoError(t, err)
+	assert.Nil(t, fields)
+
+	_, err = parseFields(ctx, getMsgs, "header.unknown")
+	assert.Regexp(t, "FF10598.*header.unknown", err)
+
+	_, err = parseFields(ctx, getMsgs, "header.created.time")
+	assert.Regexp(t, "FF10598", err)
+
+	_, err = parseFields(ctx, getMsgs, "state.value")
+	assert.Regexp(t, "FF10598", err)
+}
+
+func TestProjectOutput(t *testing.T) {
+	msgID := fftypes.NewUUID()
+	msgs := []*core.Message{{
+		Header: core.MessageHeader{ID: msgID, Tag: "tag1"},
+		State:  core.MessageStateConfirmed,
+		Data:   core.DataRefs{{ID: fftypes.NewUUID()}},
+	}}
+	output, err := projectOutput(&ffapi.FilterResultsWithCount{Count: 1, Total: 10, Items: msgs}, []string{"header.id", "state"})
+	assert.NoError(t, err)
+	b, _ := json.Marshal(output)
+	assert.JSONEq(t, `{"count":1,"total":10,"items":[{"header":{"id":"`+msgID.String()+`"},"state":"confirmed"}]}`, string(b))
+
+	op := &core.Operation{Input: fftypes.JSONObject{"amount": json.Number("12345678901234567890"), "to": "0x1"}}
+	output, err = projectOutput(op, []string{"input.amount"})
+	assert.NoError(t, err)
+	b, _ = json.Marshal(output)
+	assert.JSONEq(t, `{"input":{"amount":12345678901234567890}}`, string(b))
+
+	var nilOp *core.Operation
+	output, err = projectOutput(nilOp, []string{"id"})
+	assert.NoError(t, err)
+	assert.Equal(t, nilOp, output)
+
+	output, err = projectOutput(msgs, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, msgs, output)
+}
+
+func TestProjectOutputBadJSON(t *testing.T) {
+	_, err := projectOutput(map[bool]bool{true: false}, []string{"id"})
+	assert.Error(t, err)
+
+	_, err = projectOutput(&ffapi.FilterResultsWithCount{Items: map[bool]bool{true: false}}, []string{"id"})
+	assert.Error(t, err)
+}
+
+func TestGetMessagesFields(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?fields=header.id,state", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	msgID := fftypes.NewUUID()
+	o.On("GetMessages", mock.Anything, mock.Anything, "header.id", "state").
+		Return([]*core.Message{{Header: core.MessageHeader{ID: msgID, SignerRef: core.SignerRef{Author: "org1"}}, State: core.MessageStateConfirmed}}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.JSONEq(t, `[{"header":{"id":"`+msgID.String()+`"},"state":"confirmed"}]`, res.Body.String())
+}
+
+func TestGetMessagesFieldsUnknown(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?fields=header.id,wrong", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10598", res.Body.String())
+}
+
+func TestGetOperationByIDFieldsNotFound(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/operations/"+fftypes.NewUUID().String()+"?fields=id", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	var op *core.Operation
+	o.On("GetOperationByID", mock.Anything, mock.Anything).Return(op, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 404, res.Result().StatusCode)
+}
@@ -0,0 +1,277 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/rs/cors"
+)
+
+const (
+	HardeningHeaders                 = "headers"
+	HardeningHeadersEnabled          = "enabled"
+	HardeningContentTypeOptions      = "contentTypeOptions"
+	HardeningFrameOptions            = "frameOptions"
+	HardeningReferrerPolicy          = "referrerPolicy"
+	HardeningContentSecurityPolicy   = "contentSecurityPolicy"
+	HardeningStrictTransportSecurity = "strictTransportSecurity"
+	HardeningCORS                    = "cors"
+	HardeningCORSRules               = "rules"
+	CORSRulePaths                    = "paths"
+	CORSRuleOrigins                  = "origins"
+	CORSRuleMethods                  = "methods"
+	CORSRuleHeaders                  = "headers"
+	CORSRuleExposedHeaders           = "exposedHeaders"
+	CORSRuleCredentials              = "credentials"
+	CORSRuleMaxAge                   = "maxAge"
+	RouteGroups                      = "routeGroups"
+	DisabledRouteGroups              = "disabledRouteGroups"
+)
+
+var securityHeaders = map[string]string{
+	"X-Content-Type-Options":    HardeningContentTypeOptions,
+	"X-Frame-Options":           HardeningFrameOptions,
+	"Referrer-Policy":           HardeningReferrerPolicy,
+	"Content-Security-Policy":   HardeningContentSecurityPolicy,
+	"Strict-Transport-Security": HardeningStrictTransportSecurity,
+}
+
+func initHardeningConfig(headers config.Section, corsRules config.ArraySection) {
+	headers.AddKnownKey(HardeningHeadersEnabled, false)
+	headers.AddKnownKey(HardeningContentTypeOptions, "nosniff")
+	headers.AddKnownKey(HardeningFrameOptions, "DENY")
+	headers.AddKnownKey(HardeningReferrerPolicy, "no-referrer")
+	headers.AddKnownKey(HardeningContentSecurityPolicy)
+	headers.AddKnownKey(HardeningStrictTransportSecurity)
+	corsRules.AddKnownKey(CORSRulePaths)
+	corsRules.AddKnownKey(CORSRuleOrigins)
+	corsRules.AddKnownKey(CORSRuleMethods, []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete})
+	corsRules.AddKnownKey(CORSRuleHeaders, []string{"*"})
+	corsRules.AddKnownKey(CORSRuleExposedHeaders)
+	corsRules.AddKnownKey(CORSRuleCredentials, false)
+	corsRules.AddKnownKey(CORSRuleMaxAge, 600)
+}
+
+func initRouteGroupsConfig(conf config.KeySet) {
+	conf.AddKnownKey(RouteGroups)
+	conf.AddKnownKey(DisabledRouteGroups)
+}
+
+// hardening adds security headers to every response of the API and SPI listeners, and applies the
+// CORS rules that match the path of each request in place of the cors section
+type hardening struct {
+	headers   map[string]string
+	corsRules []*corsRule
+}
+
+type corsRule struct {
+	paths [][]string
+	cors  *cors.Cors
+}
+
+func loadHardening(ctx context.Context, headers config.Section, corsRules config.ArraySection) (*hardening, error) {
+	hd := &hardening{
+		headers: make(map[string]string),
+	}
+	if headers.GetBool(HardeningHeadersEnabled) {
+		for header, key := range securityHeaders {
+			if value := headers.GetString(key); value != "" {
+				hd.headers[header] = value
+			}
+		}
+	}
+	// The size is read once, as reading the entries sets their defaults, which changes how the array is stored
+	size := corsRules.ArraySize()
+	for i := 0; i < size; i++ {
+		rule, err := loadCORSRule(ctx, i, corsRules.ArrayEntry(i))
+		if err != nil {
+			return nil, err
+		}
+		hd.corsRules = append(hd.corsRules, rule)
+	}
+	if len(hd.headers) == 0 && len(hd.corsRules) == 0 {
+		return nil, nil
+	}
+	return hd, nil
+}
+
+func loadCORSRule(ctx context.Context, i int, conf config.Section) (*corsRule, error) {
+	paths := conf.GetStringSlice(CORSRulePaths)
+	if len(paths) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidCORSRule, i, "no paths")
+	}
+	origins := conf.GetStringSlice(CORSRuleOrigins)
+	if len(origins) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidCORSRule, i, "no origins")
+	}
+	rule := &corsRule{
+		cors: cors.New(cors.Options{
+			AllowedOrigins:   origins,
+			AllowedMethods:   conf.GetStringSlice(CORSRuleMethods),
+			AllowedHeaders:   conf.GetStringSlice(CORSRuleHeaders),
+			ExposedHeaders:   conf.GetStringSlice(CORSRuleExposedHeaders),
+			AllowCredentials: conf.GetBool(CORSRuleCredentials),
+			MaxAge:           conf.GetInt(CORSRuleMaxAge),
+		}),
+	}
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/") {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidCORSRule, i, fmt.Sprintf("path '%s' does not start with /", path))
+		}
+		rule.paths = append(rule.paths, pathSegments(path))
+	}
+	return rule, nil
+}
+
+func pathSegments(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
+
+// matches is true when the path is within one of the paths of the rule, where a * matches any one segment
+func (r *corsRule) matches(segments []string) bool {
+	for _, pattern := range r.paths {
+		if len(pattern) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, p := range pattern {
+			if p != "*" && p != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap applies the security headers and CORS rules in front of a handler. The cors section applies to
+// the requests that do not match a rule.
+func (hd *hardening) wrap(ctx context.Context, handler http.Handler, corsConf config.Section) http.Handler {
+	defaultHandler := httpserver.WrapCorsIfEnabled(ctx, corsConf, handler)
+	if hd == nil {
+		return defaultHandler
+	}
+	ruleHandlers := make([]http.Handler, len(hd.corsRules))
+	for i, rule := range hd.corsRules {
+		ruleHandlers[i] = rule.cors.Handler(handler)
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		for header, value := range hd.headers {
+			res.Header().Set(header, value)
+		}
+		segments := pathSegments(req.URL.Path)
+		for i, rule := range hd.corsRules {
+			if rule.matches(segments) {
+				ruleHandlers[i].ServeHTTP(res, req)
+				return
+			}
+		}
+		defaultHandler.ServeHTTP(res, req)
+	})
+}
+
+// routeGroup is the group of routes a path belongs to. For the versioned API and SPI routes this is the
+// resource of the route, such as "messages" or "tokens", whether or not it is within a namespace or a tenant.
+// The other paths are in the "swagger", "ws", "sse" and "ui" groups, and the favicons are in no group.
+func routeGroup(path string) string {
+	segments := pathSegments(path)
+	switch {
+	case len(segments) == 0:
+		return ""
+	case len(segments) > 2 && (segments[0] == "api" || segments[0] == "spi") && segments[1] == "v1":
+		segments = segments[2:]
+		if segments[0] == "tenants" && len(segments) > 2 {
+			segments = segments[2:]
+		}
+		if segments[0] == "namespaces" && len(segments) > 2 {
+			segments = segments[2:]
+		}
+		return segments[0]
+	case segments[0] == "spi" && len(segments) == 2 && segments[1] == "ws":
+		return "ws"
+	case segments[0] == "api" || segments[0] == "spi":
+		return "swagger"
+	case segments[0] == "ws" || segments[0] == "sse" || segments[0] == "ui":
+		return segments[0]
+	}
+	return ""
+}
+
+func knownRouteGroups() map[string]bool {
+	groups := map[string]bool{"swagger": true, "ws": true, "sse": true, "ui": true}
+	for _, route := range routes {
+		groups[routeGroup("/api/v1/"+route.Path)] = true
+	}
+	for _, route := range spiRoutes {
+		groups[routeGroup("/spi/v1/"+route.Path)] = true
+	}
+	return groups
+}
+
+// exposeRoutes restricts the routes served on a listener to the route groups enabled in its config, with a 404
+// for the requests to the other groups
+func exposeRoutes(ctx context.Context, handler http.Handler, conf config.Section, listenerName string) (http.Handler, error) {
+	enabled := conf.GetStringSlice(RouteGroups)
+	disabled := conf.GetStringSlice(DisabledRouteGroups)
+	if len(enabled) == 0 && len(disabled) == 0 {
+		return handler, nil
+	}
+	known := knownRouteGroups()
+	exposed := make(map[string]bool)
+	for group := range known {
+		exposed[group] = len(enabled) == 0
+	}
+	for _, group := range enabled {
+		if !known[group] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgUnknownRouteGroup, group, listenerName)
+		}
+		exposed[group] = true
+	}
+	for _, group := range disabled {
+		if !known[group] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgUnknownRouteGroup, group, listenerName)
+		}
+		exposed[group] = false
+	}
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if group := routeGroup(req.URL.Path); group != "" && !exposed[group] {
+			err := i18n.NewError(req.Context(), i18n.Msg404NotFound)
+			res.Header().Set("Content-Type", "application/json")
+			res.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(res).Encode(&fftypes.RESTError{Error: err.Error()})
+			return
+		}
+		handler.ServeHTTP(res, req)
+	}), nil
+}
@@ -0,0 +1,276 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/hyperledger/firefly/mocks/spieventsmocks"
+	"github.com/stretchr/testify/assert"
+)
+
+var okHandler = http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+	_, _ = res.Write([]byte("ok"))
+})
+
+func TestRouteGroup(t *testing.T) {
+	for path, group := range map[string]string{
+		"/api/v1/status":                                 "status",
+		"/api/v1/namespaces":                             "namespaces",
+		"/api/v1/namespaces/ns1":                         "namespaces",
+		"/api/v1/namespaces/ns1/messages/broadcast":      "messages",
+		"/api/v1/namespaces/ns1/tokens/pools":            "tokens",
+		"/api/v1/tenants/t1/namespaces/ns1/tokens/pools": "tokens",
+		"/api/v1/tenants/t1/namespaces":                  "namespaces",
+		"/api/v1/tenants":                                "tenants",
+		"/spi/v1/namespaces/ns1/rolebindings":            "rolebindings",
+		"/spi/ws":                                        "ws",
+		"/api/swagger.json":                              "swagger",
+		"/api":                                           "swagger",
+		"/ws":                                            "ws",
+		"/sse/namespaces/ns1":                            "sse",
+		"/ui/index.html":                                 "ui",
+		"/favicon.ico":                                   "",
+		"/":                                              "",
+	} {
+		assert.Equal(t, group, routeGroup(path), path)
+	}
+	known := knownRouteGroups()
+	assert.True(t, known["messages"])
+	assert.True(t, known["tokens"])
+	assert.True(t, known["swagger"])
+	assert.False(t, known[""])
+}
+
+func TestExposeRoutes(t *testing.T) {
+	readListenersConfig(t, `
+http:
+  routeGroups: [tokens, status, swagger]
+spi:
+  disabledRouteGroups: [rolebindings]
+`)
+	handler, err := exposeRoutes(context.Background(), okHandler, apiConfig, "api")
+	assert.NoError(t, err)
+	for path, status := range map[string]int{
+		"/api/v1/namespaces/ns1/tokens/pools":    200,
+		"/api/v1/status":                         200,
+		"/api/swagger.json":                      200,
+		"/favicon.ico":                           200,
+		"/api/v1/namespaces/ns1/messages":        404,
+		"/api/v1/tenants/t1/namespaces/ns1/data": 404,
+		"/ws":                                    404,
+	} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, status, res.Code, path)
+		if status == 404 {
+			assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+			assert.Regexp(t, "FF00167", res.Body.String())
+		}
+	}
+
+	handler, err = exposeRoutes(context.Background(), okHandler, spiConfig, "spi")
+	assert.NoError(t, err)
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/spi/v1/namespaces/ns1/rolebindings", nil))
+	assert.Equal(t, 404, res.Code)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/spi/v1/namespaces/ns1/subscriptions", nil))
+	assert.Equal(t, 200, res.Code)
+}
+
+func TestExposeRoutesUnknownGroup(t *testing.T) {
+	for _, conf := range []string{
+		"http:\n  routeGroups: [messages, unknown]\n",
+		"http:\n  disabledRouteGroups: [unknown]\n",
+	} {
+		readListenersConfig(t, conf)
+		_, err := exposeRoutes(context.Background(), okHandler, apiConfig, "api")
+		assert.Regexp(t, "FF10616.*unknown.*api", err)
+	}
+}
+
+func TestLoadHardeningNone(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	hd, err := loadHardening(context.Background(), securityHeadersConfig, corsRulesConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, hd)
+
+	// The cors section applies when there is nothing to harden
+	corsConfig.Set(httpserver.CorsEnabled, true)
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	req.Header.Set("Origin", "https://example.com")
+	hd.wrap(context.Background(), okHandler, corsConfig).ServeHTTP(res, req)
+	assert.Equal(t, "*", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, res.Header().Get("X-Frame-Options"))
+}
+
+func TestLoadHardeningBadRules(t *testing.T) {
+	for conf, errRegexp := range map[string]string{
+		"hardening:\n  cors:\n    rules:\n    - origins: [a]\n":                     "FF10615.*0.*no paths",
+		"hardening:\n  cors:\n    rules:\n    - paths: [/api]\n":                    "FF10615.*0.*no origins",
+		"hardening:\n  cors:\n    rules:\n    - paths: [api]\n      origins: [a]\n": "FF10615.*path 'api'",
+	} {
+		readListenersConfig(t, conf)
+		_, err := loadHardening(context.Background(), securityHeadersConfig, corsRulesConfig)
+		assert.Regexp(t, errRegexp, err, conf)
+	}
+}
+
+func TestHardeningWrap(t *testing.T) {
+	readListenersConfig(t, `
+cors:
+  enabled: true
+  origins: [https://default.example.com]
+hardening:
+  headers:
+    enabled: true
+    contentSecurityPolicy: default-src 'none'
+    referrerPolicy: ""
+  cors:
+    rules:
+    - paths: [/api/v1/namespaces/*/tokens, /api/v1/status]
+      origins: [https://wallet.example.com]
+      methods: [GET, POST]
+      credentials: true
+`)
+	hd, err := loadHardening(context.Background(), securityHeadersConfig, corsRulesConfig)
+	assert.NoError(t, err)
+	handler := hd.wrap(context.Background(), okHandler, corsConfig)
+
+	send := func(method, path, origin string) *httptest.ResponseRecorder {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Origin", origin)
+		if method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		handler.ServeHTTP(res, req)
+		return res
+	}
+
+	res := send(http.MethodGet, "/api/v1/namespaces/ns1/tokens/pools", "https://wallet.example.com")
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "https://wallet.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", res.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "nosniff", res.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", res.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "default-src 'none'", res.Header().Get("Content-Security-Policy"))
+	assert.Empty(t, res.Header().Get("Referrer-Policy"))
+	assert.Empty(t, res.Header().Get("Strict-Transport-Security"))
+
+	// Preflight requests for a rule are answered by the rule
+	res = send(http.MethodOptions, "/api/v1/namespaces/ns1/tokens/transfers", "https://wallet.example.com")
+	assert.Equal(t, http.StatusNoContent, res.Code)
+	assert.Equal(t, "https://wallet.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "600", res.Header().Get("Access-Control-Max-Age"))
+
+	// The rule does not allow the origin of the cors section
+	res = send(http.MethodGet, "/api/v1/status", "https://default.example.com")
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+
+	// Paths outside the rules use the cors section
+	res = send(http.MethodGet, "/api/v1/namespaces/ns1/messages", "https://default.example.com")
+	assert.Equal(t, "https://default.example.com", res.Header().Get("Access-Control-Allow-Origin"))
+	res = send(http.MethodGet, "/api/v1/namespaces/ns1/messages", "https://wallet.example.com")
+	assert.Empty(t, res.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "DENY", res.Header().Get("X-Frame-Options"))
+}
+
+func TestMainListenerServerRouteGroupsAndPreflight(t *testing.T) {
+	readListenersConfig(t, `
+http:
+  address: 127.0.0.1
+  port: 0
+  auth:
+    type: basic
+    basic:
+      passwordfile: missing
+  disabledRouteGroups: [messages]
+hardening:
+  cors:
+    rules:
+    - paths: [/api/v1]
+      origins: [https://wallet.example.com]
+`)
+	hd, err := loadHardening(context.Background(), securityHeadersConfig, corsRulesConfig)
+	assert.NoError(t, err)
+	// The basic auth plugin fails to start with a missing password file
+	_, err = newMainListenerServer(context.Background(), "api", okHandler, nil, apiConfig, corsConfig, hd, 0)
+	assert.Regexp(t, "no such file", err)
+
+	apiConfig.SubSection("auth").Set("type", "")
+	ctx, cancel := context.WithCancel(context.Background())
+	onClose := make(chan error, 1)
+	ls, err := newMainListenerServer(ctx, "api", okHandler, onClose, apiConfig, corsConfig, hd, 0)
+	assert.NoError(t, err)
+	go ls.serve(ctx)
+
+	url := fmt.Sprintf("http://%s", ls.Addr())
+	res, err := http.Get(url + "/api/v1/namespaces/ns1/tokens/pools")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	b, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "ok", string(b))
+
+	res, err = http.Get(url + "/api/v1/namespaces/ns1/messages")
+	assert.NoError(t, err)
+	assert.Equal(t, 404, res.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodOptions, url+"/api/v1/namespaces/ns1/messages", nil)
+	req.Header.Set("Origin", "https://wallet.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "https://wallet.example.com", res.Header.Get("Access-Control-Allow-Origin"))
+
+	cancel()
+	assert.NoError(t, <-onClose)
+}
+
+func TestMainListenerServerBadConfig(t *testing.T) {
+	for conf, errRegexp := range map[string]string{
+		"http:\n  address: '...://'\n":                 "FF00151",
+		"http:\n  port: 0\n  routeGroups: [unknown]\n": "FF10616",
+	} {
+		readListenersConfig(t, conf)
+		_, err := newMainListenerServer(context.Background(), "api", okHandler, nil, apiConfig, corsConfig, nil, 0)
+		assert.Regexp(t, errRegexp, err, conf)
+	}
+}
+
+func TestServeBadHardening(t *testing.T) {
+	readListenersConfig(t, "hardening:\n  cors:\n    rules:\n    - origins: [a]\n")
+	metrics.Clear()
+	as := NewAPIServer()
+	mgr := &namespacemocks.Manager{}
+	mgr.On("SPIEvents").Return(&spieventsmocks.Manager{})
+	err := as.Serve(context.Background(), mgr)
+	assert.Regexp(t, "FF10615", err)
+}
@@ -0,0 +1,72 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// inputIdempotencyKey returns the idempotency key field of the input of a route that submits
+// transactions or messages, or nil if the input does not have one
+func inputIdempotencyKey(input interface{}) *core.IdempotencyKey {
+	switch in := input.(type) {
+	case *core.MessageInOut:
+		return &in.IdempotencyKey
+	case *core.TokenPoolInput:
+		return &in.IdempotencyKey
+	case *core.TokenTransferInput:
+		return &in.IdempotencyKey
+	case *core.TokenTransferBatchInput:
+		return &in.IdempotencyKey
+	case *core.TokenBulkMintInput:
+		return &in.IdempotencyKey
+	case *core.TokenApprovalInput:
+		return &in.IdempotencyKey
+	case *core.TokenSwapInput:
+		return &in.IdempotencyKey
+	case *core.ContractCallRequest:
+		return &in.IdempotencyKey
+	case *core.ContractDeployRequest:
+		return &in.IdempotencyKey
+	case *core.PublishInput:
+		return &in.IdempotencyKey
+	default:
+		return nil
+	}
+}
+
+// applyIdempotencyKeyHeader sets the idempotency key of the input from the Idempotency-Key header,
+// so it is persisted on the transaction or message in the same way as a key supplied in the body.
+// The header is ignored by routes that do not accept a key, as clients and gateways commonly set
+// it on every request.
+func applyIdempotencyKeyHeader(ctx context.Context, header string, input interface{}) error {
+	key := inputIdempotencyKey(input)
+	if header == "" || key == nil {
+		return nil
+	}
+	if *key != "" && *key != core.IdempotencyKey(header) {
+		return i18n.NewError(ctx, coremsgs.MsgIdempotencyKeyHeaderMismatch, header, *key)
+	}
+	*key = core.IdempotencyKey(header)
+	return nil
+}
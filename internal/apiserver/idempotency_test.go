@@ -0,0 +1,145 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/assetmocks"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/definitionsmocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestIdempotencyKeyHeader(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	mbm := &broadcastmocks.Manager{}
+	o.On("Broadcast").Return(mbm)
+	input := core.MessageInOut{}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Idempotency-Key", "key1")
+	res := httptest.NewRecorder()
+
+	mbm.On("BroadcastMessage", mock.Anything, mock.MatchedBy(func(in *core.MessageInOut) bool {
+		return in.IdempotencyKey == "key1"
+	}), false).Return(&core.Message{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	mbm.AssertExpectations(t)
+}
+
+func TestIdempotencyKeyHeaderMatchesBody(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mam := &assetmocks.Manager{}
+	o.On("Assets").Return(mam)
+	input := core.TokenTransferInput{IdempotencyKey: "key1"}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/tokens/transfers", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Idempotency-Key", "key1")
+	res := httptest.NewRecorder()
+
+	mam.On("TransferTokens", mock.Anything, mock.MatchedBy(func(in *core.TokenTransferInput) bool {
+		return in.IdempotencyKey == "key1"
+	}), false).Return(&core.TokenTransfer{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	mam.AssertExpectations(t)
+}
+
+func TestIdempotencyKeyHeaderMismatch(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	input := core.TokenTransferInput{IdempotencyKey: "key1"}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/tokens/transfers", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Idempotency-Key", "key2")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	var resBody map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resBody)
+	assert.Regexp(t, "FF10589", resBody["error"])
+}
+
+func TestIdempotencyKeyHeaderIgnored(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mds := &definitionsmocks.Sender{}
+	o.On("DefinitionSender").Return(mds)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	input := core.Datatype{}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/datatypes", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Idempotency-Key", "key1")
+	res := httptest.NewRecorder()
+
+	mds.On("DefineDatatype", mock.Anything, mock.AnythingOfType("*core.Datatype"), false).Return(nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	mds.AssertExpectations(t)
+}
+
+func TestApplyIdempotencyKeyHeaderInputs(t *testing.T) {
+	ctx := context.Background()
+	for _, input := range []interface{}{
+		&core.MessageInOut{},
+		&core.TokenPoolInput{},
+		&core.TokenTransferInput{},
+		&core.TokenTransferBatchInput{},
+		&core.TokenBulkMintInput{},
+		&core.TokenApprovalInput{},
+		&core.TokenSwapInput{},
+		&core.ContractCallRequest{},
+		&core.ContractDeployRequest{},
+		&core.PublishInput{},
+	} {
+		err := applyIdempotencyKeyHeader(ctx, "key1", input)
+		assert.NoError(t, err)
+		assert.Equal(t, core.IdempotencyKey("key1"), *inputIdempotencyKey(input))
+	}
+
+	err := applyIdempotencyKeyHeader(ctx, "key1", &core.EmptyInput{})
+	assert.NoError(t, err)
+	err = applyIdempotencyKeyHeader(ctx, "", &core.TokenTransferInput{})
+	assert.NoError(t, err)
+	err = applyIdempotencyKeyHeader(ctx, "key1", nil)
+	assert.NoError(t, err)
+}
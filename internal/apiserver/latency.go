@@ -0,0 +1,192 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	traceparentHeader = "traceparent"
+	// Exemplars are limited to 128 characters, including the label name
+	maxTraceIDLength = 64
+)
+
+// slowRequests keeps the slowest requests of a recent window, for diagnosing the clients that are loading the node
+type slowRequests struct {
+	size   int
+	window time.Duration
+
+	mux     sync.Mutex
+	entries []*core.SlowRequest
+}
+
+func newSlowRequests() *slowRequests {
+	size := config.GetInt(coreconfig.MetricsSlowRequestsSize)
+	if size <= 0 {
+		return nil
+	}
+	return &slowRequests{
+		size:   size,
+		window: config.GetDuration(coreconfig.MetricsSlowRequestsWindow),
+	}
+}
+
+// record keeps a request when there is room, or when it is slower than the fastest of the requests kept
+func (sr *slowRequests) record(entry *core.SlowRequest, now time.Time) {
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+
+	sr.expire(now)
+	if len(sr.entries) < sr.size {
+		sr.entries = append(sr.entries, entry)
+		return
+	}
+	fastest := 0
+	for i, e := range sr.entries {
+		if *e.Duration < *sr.entries[fastest].Duration {
+			fastest = i
+		}
+	}
+	if *entry.Duration > *sr.entries[fastest].Duration {
+		sr.entries[fastest] = entry
+	}
+}
+
+func (sr *slowRequests) expire(now time.Time) {
+	cutoff := now.Add(-sr.window)
+	kept := sr.entries[:0]
+	for _, e := range sr.entries {
+		if e.Started.Time().After(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	for i := len(kept); i < len(sr.entries); i++ {
+		sr.entries[i] = nil
+	}
+	sr.entries = kept
+}
+
+// snapshot returns the requests kept from the current window, slowest first
+func (sr *slowRequests) snapshot(now time.Time) []*core.SlowRequest {
+	if sr == nil {
+		return []*core.SlowRequest{}
+	}
+	sr.mux.Lock()
+	defer sr.mux.Unlock()
+
+	sr.expire(now)
+	entries := make([]*core.SlowRequest, len(sr.entries))
+	copy(entries, sr.entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return *entries[i].Duration > *entries[j].Duration
+	})
+	return entries
+}
+
+// latencyRoute is the route of a request to the versioned routes of a server, without the namespace or tenant
+func latencyRoute(req *http.Request) string {
+	route := mux.CurrentRoute(req)
+	if route == nil {
+		return ""
+	}
+	tmpl, _ := route.GetPathTemplate()
+	for _, prefix := range []string{"/api/v1/", "/spi/v1/"} {
+		if strings.HasPrefix(tmpl, prefix) {
+			tmpl = strings.TrimPrefix(tmpl, prefix)
+			tmpl = strings.TrimPrefix(tmpl, "tenants/{tenant}/")
+			return strings.TrimPrefix(tmpl, "namespaces/{ns}/")
+		}
+	}
+	return ""
+}
+
+// requestTraceID is the trace ID of the W3C traceparent header of a request, or otherwise its FireFly request ID.
+// A request ID is set on requests without one, so that it is also the ID in the logs of the request.
+func requestTraceID(req *http.Request) string {
+	if parts := strings.Split(req.Header.Get(traceparentHeader), "-"); len(parts) == 4 && len(parts[1]) == 32 {
+		return parts[1]
+	}
+	reqID := req.Header.Get(ffapi.FFRequestIDHeader)
+	if reqID == "" {
+		reqID = fftypes.ShortID()
+		req.Header.Set(ffapi.FFRequestIDHeader, reqID)
+	}
+	if len(reqID) > maxTraceIDLength {
+		reqID = reqID[:maxTraceIDLength]
+	}
+	return reqID
+}
+
+// latencyMiddleware records the latency of each request to the versioned routes of a server, in the route latency
+// histogram with the trace ID of the request as an exemplar, and in the slowest recent requests
+func (as *apiServer) latencyMiddleware(server string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			route := latencyRoute(req)
+			if route == "" {
+				next.ServeHTTP(res, req)
+				return
+			}
+			traceID := requestTraceID(req)
+			start := time.Now()
+			sw := &statusWriter{ResponseWriter: res, status: http.StatusOK}
+			next.ServeHTTP(sw, req)
+			duration := time.Since(start)
+
+			ns := mux.Vars(req)["ns"]
+			if as.metricsEnabled {
+				observer := metrics.RouteLatencyHistogram.WithLabelValues(server, req.Method, route, ns, strconv.Itoa(sw.status))
+				observer.(prometheus.ExemplarObserver).ObserveWithExemplar(duration.Seconds(), prometheus.Labels{
+					metrics.TraceIDExemplarLabelName: traceID,
+				})
+			}
+			if as.slowRequests != nil {
+				started := fftypes.FFTime(start)
+				ffDuration := fftypes.FFDuration(duration)
+				as.slowRequests.record(&core.SlowRequest{
+					Server:    server,
+					Namespace: ns,
+					Principal: orchestrator.Principal(req.Context(), req.Header),
+					Method:    req.Method,
+					Route:     route,
+					Path:      req.URL.Path,
+					Query:     req.URL.RawQuery,
+					Status:    sw.status,
+					TraceID:   traceID,
+					Started:   &started,
+					Duration:  &ffDuration,
+				}, time.Now())
+			}
+		})
+	}
+}
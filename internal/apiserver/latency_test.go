@@ -0,0 +1,206 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/mocks/spieventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func slowRequest(started time.Time, duration time.Duration) *core.SlowRequest {
+	ffStarted := fftypes.FFTime(started)
+	ffDuration := fftypes.FFDuration(duration)
+	return &core.SlowRequest{Started: &ffStarted, Duration: &ffDuration}
+}
+
+func TestNewSlowRequestsDisabled(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.MetricsSlowRequestsSize, 0)
+	assert.Nil(t, newSlowRequests())
+}
+
+func TestSlowRequestsKeepsSlowest(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.MetricsSlowRequestsSize, 2)
+	config.Set(coreconfig.MetricsSlowRequestsWindow, "1m")
+	sr := newSlowRequests()
+
+	now := time.Now()
+	sr.record(slowRequest(now, 2*time.Second), now)
+	sr.record(slowRequest(now, 1*time.Second), now)
+	sr.record(slowRequest(now, 3*time.Second), now)
+	sr.record(slowRequest(now, 500*time.Millisecond), now)
+
+	slow := sr.snapshot(now)
+	assert.Len(t, slow, 2)
+	assert.Equal(t, fftypes.FFDuration(3*time.Second), *slow[0].Duration)
+	assert.Equal(t, fftypes.FFDuration(2*time.Second), *slow[1].Duration)
+
+	// Requests older than the window are dropped, making room for faster ones
+	later := now.Add(2 * time.Minute)
+	sr.record(slowRequest(later, 100*time.Millisecond), later)
+	slow = sr.snapshot(later)
+	assert.Len(t, slow, 1)
+	assert.Equal(t, fftypes.FFDuration(100*time.Millisecond), *slow[0].Duration)
+
+	assert.Empty(t, sr.snapshot(later.Add(time.Hour)))
+}
+
+func TestRequestTraceID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	req.Header.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", requestTraceID(req))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	req.Header.Set(traceparentHeader, "bad")
+	req.Header.Set(ffapi.FFRequestIDHeader, "req1")
+	assert.Equal(t, "req1", requestTraceID(req))
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	req.Header.Set(ffapi.FFRequestIDHeader, strings.Repeat("a", 100))
+	assert.Len(t, requestTraceID(req), maxTraceIDLength)
+
+	// A request ID is set for the rest of the handling of the request
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/status", nil)
+	traceID := requestTraceID(req)
+	assert.NotEmpty(t, traceID)
+	assert.Equal(t, traceID, req.Header.Get(ffapi.FFRequestIDHeader))
+}
+
+func TestLatencyMiddleware(t *testing.T) {
+	mgr, o, as := newTestServer()
+	metrics.Clear()
+	config.Set(coreconfig.MetricsSlowRequestsSize, 10)
+	as.metricsEnabled = true
+	as.slowRequests = newSlowRequests()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetNamespace", mock.Anything).Return(&core.Namespace{Name: "ns1"})
+	o.On("GetDataByID", mock.Anything, "abcd").Return(&core.Data{}, nil)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/ns1/data/abcd?fields=id", nil)
+	req.Header.Set(traceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+
+	// Requests outside the versioned routes are not recorded
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/api/swagger.json", nil))
+	assert.Equal(t, 200, res.Code)
+
+	families, err := metrics.Registry().Gather()
+	assert.NoError(t, err)
+	found := false
+	for _, family := range families {
+		if family.GetName() != metrics.RouteLatencyHistogramName {
+			continue
+		}
+		found = true
+		assert.Len(t, family.Metric, 1)
+		labels := map[string]string{}
+		for _, l := range family.Metric[0].Label {
+			labels[l.GetName()] = l.GetValue()
+		}
+		assert.Equal(t, map[string]string{
+			"server":    "rest",
+			"method":    "GET",
+			"route":     "data/{dataid}",
+			"namespace": "ns1",
+			"code":      "200",
+		}, labels)
+		exemplars := 0
+		for _, bucket := range family.Metric[0].Histogram.Bucket {
+			if bucket.Exemplar != nil {
+				exemplars++
+				assert.Equal(t, "trace_id", bucket.Exemplar.Label[0].GetName())
+				assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", bucket.Exemplar.Label[0].GetValue())
+			}
+		}
+		assert.Equal(t, 1, exemplars)
+	}
+	assert.True(t, found)
+
+	slow := as.slowRequests.snapshot(time.Now())
+	assert.Len(t, slow, 1)
+	assert.Equal(t, "rest", slow[0].Server)
+	assert.Equal(t, "ns1", slow[0].Namespace)
+	assert.Equal(t, "data/{dataid}", slow[0].Route)
+	assert.Equal(t, "/api/v1/namespaces/ns1/data/abcd", slow[0].Path)
+	assert.Equal(t, "fields=id", slow[0].Query)
+	assert.Equal(t, 200, slow[0].Status)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", slow[0].TraceID)
+}
+
+func TestLatencyMiddlewareSPI(t *testing.T) {
+	mgr, _, as := newTestServer()
+	metrics.Clear()
+	as.slowRequests = newSlowRequests()
+	mgr.On("SPIEvents").Return(&spieventsmocks.Manager{})
+	r := as.createAdminMuxRouter(mgr)
+
+	req := httptest.NewRequest(http.MethodGet, "/spi/v1/requests/slow", nil)
+	req.Header.Set(ffapi.FFRequestIDHeader, "req1")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/spi/v1/requests/slow", nil))
+	var slow []*core.SlowRequest
+	err := json.NewDecoder(res.Body).Decode(&slow)
+	assert.NoError(t, err)
+	assert.Len(t, slow, 1)
+	assert.Equal(t, "admin", slow[0].Server)
+	assert.Equal(t, "requests/slow", slow[0].Route)
+	assert.Equal(t, "req1", slow[0].TraceID)
+}
+
+func TestMetricsOpenMetricsExemplars(t *testing.T) {
+	mgr, o, as := newTestServer()
+	metrics.Clear()
+	as.metricsEnabled = true
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetNamespace", mock.Anything).Return(&core.Namespace{Name: "ns1"})
+	o.On("GetDataByID", mock.Anything, "abcd").Return(&core.Data{}, nil)
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/ns1/data/abcd", nil)
+	req.Header.Set(ffapi.FFRequestIDHeader, "req1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	res := httptest.NewRecorder()
+	as.createMetricsMuxRouter().ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Code)
+	assert.Regexp(t, `ff_apiserver_route_latency_seconds_bucket\{.*route="data/\{dataid\}".*\} 1 # \{trace_id="req1"\}`, res.Body.String())
+}
@@ -0,0 +1,236 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/auth"
+	"github.com/hyperledger/firefly-common/pkg/auth/authfactory"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	Listeners          = "listeners"
+	ListenerAddress    = "address"
+	ListenerPort       = "port"
+	ListenerSocket     = "socket"
+	ListenerSocketMode = "socketMode"
+)
+
+func initListenersConfig(listeners config.ArraySection) {
+	listeners.AddKnownKey(ListenerAddress, "127.0.0.1")
+	listeners.AddKnownKey(ListenerPort)
+	listeners.AddKnownKey(ListenerSocket)
+	listeners.AddKnownKey(ListenerSocketMode, "0660")
+	initRouteGroupsConfig(listeners)
+	fftls.InitTLSConfig(listeners.SubSection("tls"))
+}
+
+// listenerServer serves a router on the main listener, or one of the additional listeners, of the API or
+// SPI server. The timeouts, auth and CORS settings are shared with the main listener of the server.
+type listenerServer struct {
+	name            string
+	listener        net.Listener
+	server          *http.Server
+	shutdownTimeout time.Duration
+	onClose         chan error
+}
+
+// newMainListenerServer serves a router on the address, port and TLS configured in the section of the server
+func newMainListenerServer(ctx context.Context, name string, handler http.Handler, onClose chan error, conf, corsConf config.Section, hd *hardening, maxRequestTimeout time.Duration) (*listenerServer, error) {
+	handler, err := wrapListenerHandler(ctx, handler, conf, conf, corsConf, hd, name)
+	if err != nil {
+		return nil, err
+	}
+	l, err := listenTCP(ctx, name, conf)
+	if err != nil {
+		return nil, err
+	}
+	return &listenerServer{
+		name:            name,
+		listener:        l,
+		server:          newListenerHTTPServer(ctx, handler, conf, maxRequestTimeout),
+		shutdownTimeout: conf.GetDuration(httpserver.HTTPConfShutdownTimeout),
+		onClose:         onClose,
+	}, nil
+}
+
+func newListenerServers(ctx context.Context, name string, handler http.Handler, onClose chan error, conf, corsConf config.Section, hd *hardening, listeners config.ArraySection, maxRequestTimeout time.Duration) ([]*listenerServer, error) {
+	// The size is read once, as reading the entries sets their defaults, which changes how the array is stored
+	size := listeners.ArraySize()
+	if size == 0 {
+		return nil, nil
+	}
+	servers := make([]*listenerServer, 0, size)
+	closeAll := func() {
+		for _, s := range servers {
+			_ = s.listener.Close()
+		}
+	}
+	for i := 0; i < size; i++ {
+		entry := listeners.ArrayEntry(i)
+		listenerHandler, err := wrapListenerHandler(ctx, handler, conf, entry, corsConf, hd, fmt.Sprintf("%s listener %d", name, i))
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		l, err := createListener(ctx, name, i, entry)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		servers = append(servers, &listenerServer{
+			name:            name,
+			listener:        l,
+			server:          newListenerHTTPServer(ctx, listenerHandler, conf, maxRequestTimeout),
+			shutdownTimeout: conf.GetDuration(httpserver.HTTPConfShutdownTimeout),
+			onClose:         onClose,
+		})
+	}
+	return servers, nil
+}
+
+// wrapListenerHandler applies the route groups of a listener, the auth of its server, and the security
+// headers and CORS in front of everything else, so that CORS preflight requests are not authenticated
+func wrapListenerHandler(ctx context.Context, handler http.Handler, conf, listenerConf, corsConf config.Section, hd *hardening, listenerName string) (http.Handler, error) {
+	if pluginName := conf.GetString(httpserver.HTTPAuthType); pluginName != "" {
+		authPlugin, err := authfactory.GetPlugin(ctx, pluginName)
+		if err != nil {
+			return nil, err
+		}
+		if err := authPlugin.Init(ctx, "", conf.SubSection("auth").SubSection(authPlugin.Name())); err != nil {
+			return nil, err
+		}
+		handler = auth.NewHandler(authPlugin).Handler(handler)
+	}
+	handler, err := exposeRoutes(ctx, handler, listenerConf, listenerName)
+	if err != nil {
+		return nil, err
+	}
+	return hd.wrap(ctx, handler, corsConf), nil
+}
+
+// createListener listens on a Unix domain socket when a socket path is configured, and otherwise
+// on TCP. Either can be wrapped in TLS.
+func createListener(ctx context.Context, name string, i int, conf config.Section) (l net.Listener, err error) {
+	socket := conf.GetString(ListenerSocket)
+	if socket == "" {
+		return listenTCP(ctx, name, conf)
+	}
+	mode, err := strconv.ParseUint(conf.GetString(ListenerSocketMode), 8, 32)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidListenerSocketMode, conf.GetString(ListenerSocketMode), name, i)
+	}
+	// A socket file left behind by a previous run that did not shut down cleanly is replaced
+	if fi, statErr := os.Stat(socket); statErr == nil && fi.Mode()&os.ModeSocket != 0 {
+		_ = os.Remove(socket)
+	}
+	if l, err = net.Listen("unix", socket); err == nil {
+		if err = os.Chmod(socket, os.FileMode(mode)); err != nil {
+			_ = l.Close()
+		}
+	}
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgAPIServerStartFailed, socket)
+	}
+	return listenTLS(ctx, name, l, conf)
+}
+
+// listenTCP listens on the address and port of a listener, or of the main listener of a server, which
+// have the same keys in their config
+func listenTCP(ctx context.Context, name string, conf config.Section) (net.Listener, error) {
+	listenAddr := fmt.Sprintf("%s:%d", conf.GetString(ListenerAddress), conf.GetUint(ListenerPort))
+	l, err := net.Listen("tcp", listenAddr)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgAPIServerStartFailed, listenAddr)
+	}
+	return listenTLS(ctx, name, l, conf)
+}
+
+func listenTLS(ctx context.Context, name string, l net.Listener, conf config.Section) (net.Listener, error) {
+	tlsConfig, err := fftls.ConstructTLSConfig(ctx, conf.SubSection("tls"), fftls.ServerType)
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+	if tlsConfig != nil {
+		l = tls.NewListener(l, tlsConfig)
+	}
+	log.L(ctx).Infof("%s listening on %s %s (tls=%t)", name, l.Addr().Network(), l.Addr(), tlsConfig != nil)
+	return l, nil
+}
+
+func newListenerHTTPServer(ctx context.Context, handler http.Handler, conf config.Section, maxRequestTimeout time.Duration) *http.Server {
+	// As for the main listener, the read and write timeouts are never less than the maximum request timeout
+	readTimeout := conf.GetDuration(httpserver.HTTPConfReadTimeout)
+	if readTimeout < maxRequestTimeout {
+		readTimeout = maxRequestTimeout + 1*time.Second
+	}
+	writeTimeout := conf.GetDuration(httpserver.HTTPConfWriteTimeout)
+	if writeTimeout < maxRequestTimeout {
+		writeTimeout = maxRequestTimeout + 1*time.Second
+	}
+	return &http.Server{
+		Handler:           handler,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		ReadHeaderTimeout: conf.GetDuration(httpserver.HTTPConfReadTimeout),
+		ConnContext: func(newCtx context.Context, c net.Conn) context.Context {
+			l := log.L(ctx).WithField("req", fftypes.ShortID())
+			return log.WithLogger(newCtx, l)
+		},
+	}
+}
+
+func (ls *listenerServer) Addr() net.Addr {
+	return ls.listener.Addr()
+}
+
+// serve serves requests until the context is closed, then reports the result on the close channel
+func (ls *listenerServer) serve(ctx context.Context) {
+	serverEnded := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), ls.shutdownTimeout)
+			defer cancel()
+			_ = ls.server.Shutdown(shutdownCtx)
+		case <-serverEnded:
+		}
+	}()
+	err := ls.server.Serve(ls.listener)
+	if err == http.ErrServerClosed {
+		err = nil
+	}
+	close(serverEnded)
+	log.L(ctx).Infof("%s listener %s complete", ls.name, ls.listener.Addr())
+	ls.onClose <- err
+}
@@ -0,0 +1,182 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/httpserver"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/hyperledger/firefly/mocks/spieventsmocks"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func readListenersConfig(t *testing.T, conf string) {
+	coreconfig.Reset()
+	InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(conf))
+	assert.NoError(t, err)
+}
+
+func unixSocketClient(socket string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+			},
+		},
+	}
+}
+
+func TestListenerServersUnixSocketAndTCP(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "firefly.sock")
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	err := os.WriteFile(filepath.Join(dir, "users"), []byte("firefly:"+string(hash)), 0600)
+	assert.NoError(t, err)
+	// A socket left behind by a previous run is replaced
+	stale, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	readListenersConfig(t, fmt.Sprintf(`
+http:
+  auth:
+    type: basic
+    basic:
+      passwordfile: %s
+  listeners:
+  - socket: %s
+    socketMode: "0600"
+  - address: 127.0.0.1
+    port: 0
+`, filepath.Join(dir, "users"), socket))
+
+	handler := http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, _ = res.Write([]byte("ok"))
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	onClose := make(chan error, 2)
+	servers, err := newListenerServers(ctx, "api", handler, onClose, apiConfig, corsConfig, nil, apiListenersConfig, 1*time.Minute)
+	assert.NoError(t, err)
+	assert.Len(t, servers, 2)
+	for _, ls := range servers {
+		go ls.serve(ctx)
+	}
+
+	fi, err := os.Stat(socket)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	assert.Equal(t, "unix", servers[0].Addr().Network())
+
+	// The auth of the server applies to every listener
+	req, _ := http.NewRequest(http.MethodGet, "http://firefly/api/v1/status", nil)
+	res, err := unixSocketClient(socket).Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 403, res.StatusCode)
+
+	req.SetBasicAuth("firefly", "secret")
+	res, err = unixSocketClient(socket).Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	b, _ := io.ReadAll(res.Body)
+	assert.Equal(t, "ok", string(b))
+
+	req, _ = http.NewRequest(http.MethodGet, fmt.Sprintf("http://%s/api/v1/status", servers[1].Addr()), nil)
+	req.SetBasicAuth("firefly", "secret")
+	res, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+
+	cancel()
+	assert.NoError(t, <-onClose)
+	assert.NoError(t, <-onClose)
+	_, err = os.Stat(socket)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestListenerServersNone(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	servers, err := newListenerServers(context.Background(), "api", http.NotFoundHandler(), nil, apiConfig, corsConfig, nil, apiListenersConfig, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, servers)
+}
+
+func TestListenerServersTLS(t *testing.T) {
+	readListenersConfig(t, `
+spi:
+  listeners:
+  - port: 0
+    tls:
+      enabled: true
+`)
+	servers, err := newListenerServers(context.Background(), "spi", http.NotFoundHandler(), nil, spiConfig, corsConfig, nil, spiListenersConfig, 0)
+	assert.NoError(t, err)
+	assert.Len(t, servers, 1)
+	_, isTCP := servers[0].listener.(*net.TCPListener)
+	assert.False(t, isTCP)
+	assert.Equal(t, "tcp", servers[0].Addr().Network())
+	servers[0].listener.Close()
+}
+
+func TestListenerServersBadConfig(t *testing.T) {
+	dir := t.TempDir()
+	for conf, errRegexp := range map[string]string{
+		"http:\n  listeners:\n  - socket: " + filepath.Join(dir, "a.sock") + "\n    socketMode: rw\n":               "FF10605",
+		"http:\n  listeners:\n  - socket: " + filepath.Join(dir, "missing", "a.sock") + "\n":                        "FF00151",
+		"http:\n  listeners:\n  - address: '...://'\n":                                                              "FF00151",
+		"http:\n  listeners:\n  - port: 0\n  - port: 0\n    tls:\n      enabled: true\n      caFile: missing.pem\n": "FF00153",
+		"http:\n  auth:\n    type: unknown\n  listeners:\n  - port: 0\n":                                            "FF00168",
+		"http:\n  auth:\n    type: basic\n  listeners:\n  - port: 0\n":                                              "no such file",
+	} {
+		readListenersConfig(t, conf)
+		_, err := newListenerServers(context.Background(), "api", http.NotFoundHandler(), nil, apiConfig, corsConfig, nil, apiListenersConfig, 0)
+		assert.Regexp(t, errRegexp, err, conf)
+	}
+}
+
+func TestStartListenersFail(t *testing.T) {
+	for _, conf := range []string{
+		"http:\n  port: 0\n  listeners:\n  - socketMode: rw\n    socket: a.sock\n",
+		"http:\n  port: 0\nspi:\n  enabled: true\n  port: 0\n  listeners:\n  - socketMode: rw\n    socket: a.sock\n",
+	} {
+		readListenersConfig(t, conf)
+		metrics.Clear()
+		apiConfig.Set(httpserver.HTTPConfPort, 0)
+		as := NewAPIServer()
+		mgr := &namespacemocks.Manager{}
+		mgr.On("SPIEvents").Return(&spieventsmocks.Manager{})
+		err := as.Serve(context.Background(), mgr)
+		assert.Regexp(t, "FF10605", err)
+	}
+}
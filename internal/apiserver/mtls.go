@@ -0,0 +1,242 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"regexp"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	MTLSRequired        = "required"
+	MTLSIdentities      = "identities"
+	MTLSIdentitySubject = "subject"
+	MTLSIdentitySAN     = "san"
+	MTLSIdentityName    = "identity"
+	MTLSIdentityKeys    = "keys"
+)
+
+func initMTLSConfig(config config.Section, identities config.ArraySection) {
+	config.AddKnownKey(MTLSRequired, false)
+	identities.AddKnownKey(MTLSIdentitySubject)
+	identities.AddKnownKey(MTLSIdentitySAN)
+	identities.AddKnownKey(MTLSIdentityName)
+	identities.AddKnownKey(MTLSIdentityKeys)
+}
+
+// certIdentity maps the verified client certificates that match a subject DN pattern and/or a SAN,
+// to a FireFly identity and the signing keys it is allowed to use
+type certIdentity struct {
+	subject  *regexp.Regexp
+	san      string
+	identity string
+	keys     []string
+}
+
+type certIdentityKey struct{}
+
+// certIdentities is the ordered list of mappings, where the first that matches a certificate applies
+type certIdentities struct {
+	required bool
+	mappings []*certIdentity
+}
+
+func loadCertIdentities(ctx context.Context, conf config.Section, identities config.ArraySection) (*certIdentities, error) {
+	ci := &certIdentities{
+		required: conf.GetBool(MTLSRequired),
+	}
+	for i := 0; i < identities.ArraySize(); i++ {
+		entry := identities.ArrayEntry(i)
+		m := &certIdentity{
+			san:      entry.GetString(MTLSIdentitySAN),
+			identity: entry.GetString(MTLSIdentityName),
+			keys:     entry.GetStringSlice(MTLSIdentityKeys),
+		}
+		if subject := entry.GetString(MTLSIdentitySubject); subject != "" {
+			var err error
+			if m.subject, err = regexp.Compile(subject); err != nil {
+				return nil, i18n.NewError(ctx, coremsgs.MsgInvalidCertIdentityMapping, i, err)
+			}
+		}
+		if m.identity == "" || (m.subject == nil && m.san == "") {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidCertIdentityMapping, i, "an identity, and a subject or san, are required")
+		}
+		ci.mappings = append(ci.mappings, m)
+	}
+	if !ci.required && len(ci.mappings) == 0 {
+		return nil, nil
+	}
+	return ci, nil
+}
+
+func (m *certIdentity) matches(cert *x509.Certificate) bool {
+	if m.subject != nil && !m.subject.MatchString(cert.Subject.String()) {
+		return false
+	}
+	if m.san != "" {
+		for _, name := range cert.DNSNames {
+			if name == m.san {
+				return true
+			}
+		}
+		for _, email := range cert.EmailAddresses {
+			if email == m.san {
+				return true
+			}
+		}
+		for _, uri := range cert.URIs {
+			if uri.String() == m.san {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// lookup finds the mapping for the client certificate of a request, which must have been verified
+// against the CA of the API listener
+func (ci *certIdentities) lookup(req *http.Request) *certIdentity {
+	if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 || len(req.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	cert := req.TLS.VerifiedChains[0][0]
+	for _, m := range ci.mappings {
+		if m.matches(cert) {
+			return m
+		}
+	}
+	log.L(req.Context()).Debugf("No identity mapping for client certificate '%s'", cert.Subject)
+	return nil
+}
+
+// middleware records the identity mapped from the client certificate on the context of the request, as
+// its principal. When certificates are required, requests without a mapped certificate are rejected.
+func (ci *certIdentities) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		m := ci.lookup(req)
+		if m == nil {
+			if ci.required {
+				err := i18n.NewError(req.Context(), coremsgs.MsgCertIdentityRequired)
+				log.L(req.Context()).Warnf("Rejecting %s %s: %s", req.Method, req.URL.Path, err)
+				res.Header().Set("Content-Type", "application/json")
+				res.WriteHeader(http.StatusUnauthorized)
+				_ = json.NewEncoder(res).Encode(&fftypes.RESTError{Error: err.Error()})
+				return
+			}
+			next.ServeHTTP(res, req)
+			return
+		}
+		ctx := orchestrator.WithPrincipal(req.Context(), m.identity)
+		ctx = context.WithValue(ctx, certIdentityKey{}, m)
+		next.ServeHTTP(res, req.WithContext(ctx))
+	})
+}
+
+// inputSigners returns the author and signing key fields of the input of a route that submits
+// transactions or messages, including those of any message sent with it
+func inputSigners(input interface{}) (authors []*string, keys []*string) {
+	withMessage := func(msg *core.MessageInOut) {
+		if msg != nil {
+			authors = append(authors, &msg.Header.Author)
+			keys = append(keys, &msg.Header.Key)
+		}
+	}
+	switch in := input.(type) {
+	case *core.MessageInOut:
+		withMessage(in)
+	case *core.SignerRef:
+		authors = append(authors, &in.Author)
+		keys = append(keys, &in.Key)
+	case *core.GroupMembersInput:
+		authors = append(authors, &in.Author)
+		keys = append(keys, &in.Key)
+	case *core.IdentityCreateDTO:
+		keys = append(keys, &in.Key)
+	case *core.TokenPoolInput:
+		keys = append(keys, &in.Key)
+	case *core.TokenTransferInput:
+		keys = append(keys, &in.Key)
+		withMessage(in.Message)
+	case *core.TokenTransferBatchInput:
+		keys = append(keys, &in.Key)
+	case *core.TokenBulkMintInput:
+		keys = append(keys, &in.Key)
+	case *core.TokenApprovalInput:
+		keys = append(keys, &in.Key)
+		withMessage(in.Message)
+	case *core.TokenSwapInput:
+		for _, leg := range in.Legs {
+			keys = append(keys, &leg.Key)
+		}
+	case *core.ContractCallRequest:
+		keys = append(keys, &in.Key)
+		withMessage(in.Message)
+	case *core.ContractDeployRequest:
+		keys = append(keys, &in.Key)
+	}
+	return authors, keys
+}
+
+// applyCertIdentity defaults the authors and signing keys of the input to the identity mapped from the
+// client certificate of the request, and rejects any the identity is not allowed to use. Any key can be
+// used with an identity that has no keys configured.
+func applyCertIdentity(ctx context.Context, input interface{}) error {
+	m, ok := ctx.Value(certIdentityKey{}).(*certIdentity)
+	if !ok {
+		return nil
+	}
+	authors, keys := inputSigners(input)
+	for _, author := range authors {
+		if *author == "" {
+			*author = m.identity
+		} else if *author != m.identity {
+			return i18n.NewError(ctx, coremsgs.MsgCertIdentityAuthorNotAllowed, m.identity, *author)
+		}
+	}
+	if len(m.keys) == 0 {
+		return nil
+	}
+	for _, key := range keys {
+		if *key == "" {
+			*key = m.keys[0]
+		} else if !containsString(m.keys, *key) {
+			return i18n.NewError(ctx, coremsgs.MsgCertIdentityKeyNotAllowed, m.identity, *key)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
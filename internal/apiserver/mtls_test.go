@@ -0,0 +1,321 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testClientCert(cn string, sans ...string) *tls.ConnectionState {
+	cert := &x509.Certificate{
+		Subject:  pkix.Name{CommonName: cn, Organization: []string{"Org1"}},
+		DNSNames: sans,
+	}
+	return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+}
+
+func newTestCertIdentities(required bool) *certIdentities {
+	return &certIdentities{
+		required: required,
+		mappings: []*certIdentity{
+			{subject: regexp.MustCompile(`^CN=app1,O=Org1$`), identity: "did:firefly:org/app1", keys: []string{"0x1111", "0x2222"}},
+			{san: "app2.example.com", identity: "did:firefly:org/app2"},
+		},
+	}
+}
+
+func TestLoadCertIdentities(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	ci, err := loadCertIdentities(context.Background(), mtlsConfig, mtlsIdentitiesConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, ci)
+
+	viper.SetConfigType("yaml")
+	err = viper.ReadConfig(strings.NewReader(`
+mtls:
+  required: true
+  identities:
+  - subject: ^CN=app1,
+    identity: did:firefly:org/app1
+    keys:
+    - "0x1111"
+  - san: app2.example.com
+    identity: did:firefly:org/app2
+`))
+	assert.NoError(t, err)
+	ci, err = loadCertIdentities(context.Background(), mtlsConfig, mtlsIdentitiesConfig)
+	assert.NoError(t, err)
+	assert.True(t, ci.required)
+	assert.Len(t, ci.mappings, 2)
+	assert.Equal(t, []string{"0x1111"}, ci.mappings[0].keys)
+	assert.Equal(t, "app2.example.com", ci.mappings[1].san)
+}
+
+func TestLoadCertIdentitiesBadConfig(t *testing.T) {
+	for _, conf := range []string{
+		"mtls:\n  identities:\n  - subject: '['\n    identity: app1\n",
+		"mtls:\n  identities:\n  - san: app1.example.com\n",
+		"mtls:\n  identities:\n  - identity: app1\n",
+	} {
+		coreconfig.Reset()
+		InitConfig()
+		viper.SetConfigType("yaml")
+		err := viper.ReadConfig(strings.NewReader(conf))
+		assert.NoError(t, err)
+		_, err = loadCertIdentities(context.Background(), mtlsConfig, mtlsIdentitiesConfig)
+		assert.Regexp(t, "FF10594", err)
+	}
+}
+
+func TestServeBadCertIdentities(t *testing.T) {
+	mgr, _, as := newTestServer()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader("mtls:\n  identities:\n  - identity: app1\n"))
+	assert.NoError(t, err)
+	err = as.Serve(context.Background(), mgr)
+	assert.Regexp(t, "FF10594", err)
+}
+
+func TestCertIdentityLookup(t *testing.T) {
+	ci := newTestCertIdentities(false)
+
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	assert.Nil(t, ci.lookup(req))
+
+	req.TLS = testClientCert("app1")
+	assert.Equal(t, "did:firefly:org/app1", ci.lookup(req).identity)
+
+	req.TLS = testClientCert("app2", "other.example.com", "app2.example.com")
+	assert.Equal(t, "did:firefly:org/app2", ci.lookup(req).identity)
+
+	req.TLS = testClientCert("app3", "app3.example.com")
+	assert.Nil(t, ci.lookup(req))
+
+	req.TLS = &tls.ConnectionState{}
+	assert.Nil(t, ci.lookup(req))
+}
+
+func TestCertIdentityMatchesSAN(t *testing.T) {
+	u, _ := url.Parse("spiffe://example.com/app")
+	cert := &x509.Certificate{
+		EmailAddresses: []string{"app@example.com"},
+		URIs:           []*url.URL{u},
+	}
+	assert.True(t, (&certIdentity{san: "app@example.com"}).matches(cert))
+	assert.True(t, (&certIdentity{san: "spiffe://example.com/app"}).matches(cert))
+	assert.False(t, (&certIdentity{san: "spiffe://example.com/other"}).matches(cert))
+	assert.False(t, (&certIdentity{subject: regexp.MustCompile("CN=app"), san: "app@example.com"}).matches(cert))
+}
+
+func TestCertIdentityRequired(t *testing.T) {
+	mgr, _, as := newTestServer()
+	as.certIdentities = newTestCertIdentities(true)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	req := httptest.NewRequest("GET", "/api/v1/websockets", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 401, res.Result().StatusCode)
+	assert.Regexp(t, "FF10595", res.Body.String())
+
+	req.TLS = testClientCert("app1")
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestCertIdentityNotRequired(t *testing.T) {
+	mgr, _, as := newTestServer()
+	as.certIdentities = newTestCertIdentities(false)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	req := httptest.NewRequest("GET", "/api/v1/websockets", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestCertIdentityBroadcast(t *testing.T) {
+	mgr, o, as := newTestServer()
+	as.certIdentities = newTestCertIdentities(false)
+	r := as.createMuxRouter(context.Background(), mgr)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	mbm := &broadcastmocks.Manager{}
+	o.On("Broadcast").Return(mbm)
+	mbm.On("BroadcastMessage", mock.MatchedBy(func(ctx context.Context) bool {
+		return orchestrator.Principal(ctx, nil) == "did:firefly:org/app1"
+	}), mock.MatchedBy(func(msg *core.MessageInOut) bool {
+		return msg.Header.Author == "did:firefly:org/app1" && msg.Header.Key == "0x1111"
+	}), false).Return(&core.Message{}, nil)
+
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&core.MessageInOut{})
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.TLS = testClientCert("app1")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	mbm.AssertExpectations(t)
+}
+
+func TestCertIdentityBroadcastWrongAuthor(t *testing.T) {
+	mgr, o, as := newTestServer()
+	as.certIdentities = newTestCertIdentities(false)
+	r := as.createMuxRouter(context.Background(), mgr)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+
+	msg := &core.MessageInOut{}
+	msg.Header.Author = "did:firefly:org/app2"
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(msg)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.TLS = testClientCert("app1")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 403, res.Result().StatusCode)
+	assert.Regexp(t, "FF10596", res.Body.String())
+}
+
+func TestApplyCertIdentity(t *testing.T) {
+	app1 := newTestCertIdentities(false).mappings[0]
+	ctx := context.WithValue(context.Background(), certIdentityKey{}, app1)
+
+	// No certificate identity on the context
+	transfer := &core.TokenTransferInput{}
+	err := applyCertIdentity(context.Background(), transfer)
+	assert.NoError(t, err)
+	assert.Empty(t, transfer.Key)
+
+	transfer = &core.TokenTransferInput{Message: &core.MessageInOut{}}
+	err = applyCertIdentity(ctx, transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1111", transfer.Key)
+	assert.Equal(t, "did:firefly:org/app1", transfer.Message.Header.Author)
+	assert.Equal(t, "0x1111", transfer.Message.Header.Key)
+
+	signer := &core.SignerRef{}
+	err = applyCertIdentity(ctx, signer)
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/app1", signer.Author)
+	assert.Equal(t, "0x1111", signer.Key)
+
+	swap := &core.TokenSwapInput{Legs: []*core.TokenSwapLegInput{{}, {}}}
+	swap.Legs[1].Key = "0x2222"
+	err = applyCertIdentity(ctx, swap)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x1111", swap.Legs[0].Key)
+	assert.Equal(t, "0x2222", swap.Legs[1].Key)
+
+	members := &core.GroupMembersInput{}
+	err = applyCertIdentity(ctx, members)
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/app1", members.Author)
+	assert.Equal(t, "0x1111", members.Key)
+
+	members = &core.GroupMembersInput{SignerRef: core.SignerRef{Author: "did:firefly:org/other"}}
+	err = applyCertIdentity(ctx, members)
+	assert.Regexp(t, "FF10596", err)
+
+	for _, input := range []interface{}{
+		&core.SignerRef{Key: "0x3333"},
+		&core.GroupMembersInput{SignerRef: core.SignerRef{Key: "0x3333"}},
+		&core.IdentityCreateDTO{Key: "0x3333"},
+		&core.TokenPoolInput{TokenPool: core.TokenPool{Key: "0x3333"}},
+		&core.TokenTransferBatchInput{Key: "0x3333"},
+		&core.TokenBulkMintInput{Key: "0x3333"},
+		&core.TokenApprovalInput{TokenApproval: core.TokenApproval{Key: "0x3333"}},
+		&core.ContractCallRequest{Key: "0x3333"},
+		&core.ContractDeployRequest{Key: "0x3333"},
+	} {
+		err = applyCertIdentity(ctx, input)
+		assert.Regexp(t, "FF10597.*0x3333", err)
+	}
+
+	// Any key can be used by an identity without keys
+	app2 := newTestCertIdentities(false).mappings[1]
+	ctx = context.WithValue(context.Background(), certIdentityKey{}, app2)
+	call := &core.ContractCallRequest{Key: "0x3333"}
+	err = applyCertIdentity(ctx, call)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x3333", call.Key)
+}
+
+// signerFields returns the author and key fields of a route input, including those of embedded structs
+func signerFields(v reflect.Value) (fields []reflect.Value) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			fields = append(fields, signerFields(v.Field(i))...)
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if (name == "author" || name == "key") && field.Type.Kind() == reflect.String {
+			fields = append(fields, v.Field(i))
+		}
+	}
+	return fields
+}
+
+func TestInputSignersCoversRoutes(t *testing.T) {
+	for _, route := range routes {
+		if route.Method == http.MethodGet || route.JSONInputValue == nil {
+			continue
+		}
+		input := route.JSONInputValue()
+		v := reflect.ValueOf(input)
+		if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		for _, field := range signerFields(v.Elem()) {
+			field.SetString("sentinel")
+			authors, keys := inputSigners(input)
+			found := false
+			for _, s := range append(authors, keys...) {
+				found = found || s == field.Addr().Interface().(*string)
+			}
+			assert.True(t, found, "%s %s: author/key field of %T is not checked by inputSigners", route.Method, route.Path, input)
+		}
+	}
+}
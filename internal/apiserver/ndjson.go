@@ -0,0 +1,158 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// isStreamableRoute returns true for the collection routes that stream their results as NDJSON.
+// Other routes ignore the Accept header, and return their results as JSON.
+func isStreamableRoute(route *ffapi.Route) bool {
+	ce, ok := route.Extensions.(*coreExtensions)
+	return ok && ce.Streaming && route.Method == http.MethodGet && route.FilterFactory != nil
+}
+
+// acceptsNDJSON returns true when the Accept header of a request asks for newline delimited JSON
+func acceptsNDJSON(req *http.Request) bool {
+	for _, accept := range req.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			if mt, _, err := mime.ParseMediaType(mediaType); err == nil && mt == ndjsonContentType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ndjsonHandler passes NDJSON requests to the stream handler, which has its own limit, and sets the content
+// type of a successful response. The stream is returned to the handler factory as a reader, which it would
+// otherwise send as a binary response.
+func ndjsonHandler(handler, streamHandler http.HandlerFunc) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		if acceptsNDJSON(req) {
+			streamHandler(&ndjsonResponseWriter{ResponseWriter: res}, req)
+			return
+		}
+		handler(res, req)
+	}
+}
+
+type ndjsonResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *ndjsonResponseWriter) WriteHeader(status int) {
+	if status == http.StatusOK {
+		w.Header().Set("Content-Type", ndjsonContentType)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+type ndjsonStream struct {
+	pw      *io.PipeWriter
+	fields  []string
+	once    sync.Once
+	started chan error
+}
+
+// streamNDJSON runs the handler of a collection route in the background, writing each item of the results
+// to the returned stream as a line of JSON. Routes pass the iterator of the request to the database, so
+// that the items are written as they are read, and any items returned in the list are written after them.
+// Errors before the first item are returned with the usual status, and later errors are written as the last
+// line of the stream.
+func streamNDJSON(r *ffapi.APIRequest, cr *coreRequest, handler func(r *ffapi.APIRequest, cr *coreRequest) (interface{}, error), fields []string) (io.ReadCloser, error) {
+	pr, pw := io.Pipe()
+	s := &ndjsonStream{
+		pw:      pw,
+		fields:  fields,
+		started: make(chan error, 1),
+	}
+	cr.iterator = s.row
+	go func() {
+		output, err := handler(r, cr)
+		if err == nil {
+			s.start(nil)
+			err = s.items(output)
+		}
+		if err != nil && !s.start(err) {
+			log.L(cr.ctx).Errorf("NDJSON response failed after streaming started: %s", err)
+			_ = s.write(&fftypes.RESTError{Error: err.Error()})
+		}
+		_ = pw.Close()
+	}()
+	if err := <-s.started; err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
+// start releases the response on the first item, or the completion of the handler. It returns true
+// for the call that released it.
+func (s *ndjsonStream) start(err error) (first bool) {
+	s.once.Do(func() {
+		first = true
+		s.started <- err
+	})
+	return first
+}
+
+func (s *ndjsonStream) row(row interface{}) error {
+	s.start(nil)
+	projected, err := projectOutput(row, s.fields)
+	if err != nil {
+		return err
+	}
+	return s.write(projected)
+}
+
+func (s *ndjsonStream) items(output interface{}) error {
+	if withCount, ok := output.(*ffapi.FilterResultsWithCount); ok {
+		output = withCount.Items
+	}
+	v := reflect.ValueOf(output)
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := s.row(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ndjsonStream) write(value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	_, err = s.pw.Write(append(b, '\n'))
+	return err
+}
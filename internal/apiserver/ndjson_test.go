@@ -0,0 +1,202 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func iterateRows(rows ...interface{}) func(args mock.Arguments) {
+	return func(args mock.Arguments) {
+		it := database.Iterator(args[0].(context.Context))
+		for _, row := range rows {
+			if err := it(row); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestAcceptsNDJSON(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events", nil)
+	assert.False(t, acceptsNDJSON(req))
+	req.Header.Set("Accept", "application/json")
+	assert.False(t, acceptsNDJSON(req))
+	req.Header.Set("Accept", "application/json;q=0.5, application/x-ndjson")
+	assert.True(t, acceptsNDJSON(req))
+	req.Header.Set("Accept", "application/x-ndjson; charset=utf-8")
+	assert.True(t, acceptsNDJSON(req))
+}
+
+func TestGetEventsNDJSON(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	event1 := &core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed, Topic: "topic1"}
+	event2 := &core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed, Topic: "topic2"}
+	o.On("GetEvents", mock.Anything, mock.Anything).
+		Run(iterateRows(event1, event2)).
+		Return([]*core.Event{}, nil, nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events?fields=id,topic", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "application/x-ndjson", res.Result().Header.Get("Content-Type"))
+	assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\",\"topic\":\"topic1\"}\n{\"id\":\"%s\",\"topic\":\"topic2\"}\n", event1.ID, event2.ID), res.Body.String())
+}
+
+func TestGetEventsNDJSONStreamLimit(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetEvents", mock.Anything, mock.Anything).Return([]*core.Event{}, nil, nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events?limit=500000", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events?limit=2000000", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 400, res.Result().StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events?limit=500000", nil)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
+
+func TestGetBatchesNDJSONNotStreamed(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetBatches", mock.Anything, mock.Anything).Return([]*core.BatchPersisted{}, nil, nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/batches", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "application/json", res.Result().Header.Get("Content-Type"))
+	assert.JSONEq(t, "[]", res.Body.String())
+}
+
+func TestGetOperationsNDJSONList(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	ops := []*core.Operation{{ID: fftypes.NewUUID()}, {ID: fftypes.NewUUID()}}
+	o.On("GetOperations", mock.Anything, mock.Anything, "id").
+		Return(ops, &ffapi.FilterResult{TotalCount: &[]int64{2}[0]}, nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/operations?fields=id&count", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\"}\n{\"id\":\"%s\"}\n", ops[0].ID, ops[1].ID), res.Body.String())
+}
+
+func TestGetEventsNDJSONEmpty(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetEvents", mock.Anything, mock.Anything).Return([]*core.Event{}, nil, nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "application/x-ndjson", res.Result().Header.Get("Content-Type"))
+	assert.Empty(t, res.Body.String())
+}
+
+func TestGetEventsNDJSONFail(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("GetEvents", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+	assert.Equal(t, "application/json", res.Result().Header.Get("Content-Type"))
+	assert.Regexp(t, "pop", res.Body.String())
+}
+
+func TestGetEventsNDJSONFailAfterStart(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	event := &core.Event{ID: fftypes.NewUUID()}
+	o.On("GetEvents", mock.Anything, mock.Anything).
+		Run(iterateRows(event)).
+		Return(nil, nil, fmt.Errorf("pop"))
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/events?fields=id", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, fmt.Sprintf("{\"id\":\"%s\"}\n{\"error\":\"pop\"}\n", event.ID), res.Body.String())
+}
+
+func TestStreamNDJSONClosed(t *testing.T) {
+	iterated := make(chan error)
+	cr := &coreRequest{ctx: context.Background()}
+	stream, err := streamNDJSON(&ffapi.APIRequest{}, cr, func(r *ffapi.APIRequest, cr *coreRequest) (interface{}, error) {
+		err := cr.iterator(&core.Event{})
+		if err == nil {
+			err = cr.iterator(&core.Event{})
+		}
+		iterated <- err
+		return nil, err
+	}, nil)
+	assert.NoError(t, err)
+	line := make([]byte, 1)
+	_, err = stream.Read(line)
+	assert.NoError(t, err)
+	stream.Close()
+	assert.Equal(t, io.ErrClosedPipe, <-iterated)
+}
+
+func TestStreamNDJSONProjectFail(t *testing.T) {
+	s := &ndjsonStream{fields: []string{"id"}, started: make(chan error, 1)}
+	err := s.row(map[bool]bool{true: false})
+	assert.Error(t, err)
+}
+
+func TestNDJSONResponseWriterError(t *testing.T) {
+	res := httptest.NewRecorder()
+	w := &ndjsonResponseWriter{ResponseWriter: res}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	assert.Equal(t, "application/json", res.Result().Header.Get("Content-Type"))
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+}
@@ -0,0 +1,403 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	queryParam = "q"
+
+	// The limits keep a query cheap to parse, and the SQL it generates a reasonable size
+	maxQueryLength     = 4096
+	maxQueryConditions = 100
+	maxQueryDepth      = 10
+)
+
+// withQueryParam adds the q query parameter to all the GET routes that take filters
+func withQueryParam(routes []*ffapi.Route) []*ffapi.Route {
+	for _, route := range routes {
+		if route.Method == http.MethodGet && route.FilterFactory != nil {
+			// The namespaced copies of a route share its query params, so a new slice is required
+			queryParams := make([]*ffapi.QueryParam, 0, len(route.QueryParams)+1)
+			queryParams = append(queryParams, route.QueryParams...)
+			route.QueryParams = append(queryParams, &ffapi.QueryParam{
+				Name: queryParam, Description: coremsgs.APIParamsQuery,
+			})
+		}
+	}
+	return routes
+}
+
+type queryTokenType int
+
+const (
+	queryTokenEOF queryTokenType = iota
+	queryTokenWord
+	queryTokenString
+	queryTokenSymbol
+)
+
+type queryToken struct {
+	tokenType queryTokenType
+	value     string
+	pos       int
+}
+
+// is checks if the token is a symbol, or an unquoted keyword in any case
+func (t *queryToken) is(value string) bool {
+	return (t.tokenType == queryTokenSymbol || t.tokenType == queryTokenWord) && strings.EqualFold(t.value, value)
+}
+
+// queryParser compiles a query such as "type=broadcast AND (topic=t1 OR topic=t2) ORDER BY sequence DESC"
+// into conditions of the filter builder of the route
+type queryParser struct {
+	ctx        context.Context
+	fb         ffapi.FilterBuilder
+	fields     map[string]bool
+	tokens     []*queryToken
+	pos        int
+	conditions int
+}
+
+func isQueryWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.-:+/", r)
+}
+
+func tokenizeQuery(ctx context.Context, query string) ([]*queryToken, error) {
+	var tokens []*queryToken
+	runes := []rune(query)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			// Quotes are escaped by doubling them, as in SQL
+			start := i
+			var value strings.Builder
+			for i++; ; i++ {
+				if i >= len(runes) {
+					return nil, i18n.NewError(ctx, coremsgs.MsgInvalidQuery, start+1, "closing quote")
+				}
+				if runes[i] == r {
+					if i+1 < len(runes) && runes[i+1] == r {
+						i++
+					} else {
+						break
+					}
+				}
+				value.WriteRune(runes[i])
+			}
+			i++
+			tokens = append(tokens, &queryToken{tokenType: queryTokenString, value: value.String(), pos: start + 1})
+		case strings.ContainsRune("(),=", r):
+			tokens = append(tokens, &queryToken{tokenType: queryTokenSymbol, value: string(r), pos: i + 1})
+			i++
+		case strings.ContainsRune("!<>", r):
+			symbol := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				symbol += "="
+			}
+			if symbol == "!" {
+				return nil, i18n.NewError(ctx, coremsgs.MsgInvalidQuery, i+1, "!=")
+			}
+			tokens = append(tokens, &queryToken{tokenType: queryTokenSymbol, value: symbol, pos: i + 1})
+			i += len(symbol)
+		case isQueryWordChar(r):
+			start := i
+			for i < len(runes) && isQueryWordChar(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, &queryToken{tokenType: queryTokenWord, value: string(runes[start:i]), pos: start + 1})
+		default:
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidQuery, i+1, "field, value or operator")
+		}
+	}
+	return append(tokens, &queryToken{tokenType: queryTokenEOF, pos: len(runes) + 1}), nil
+}
+
+// applyQuery adds the conditions and sort of a query to the filter of a request
+func applyQuery(ctx context.Context, filter ffapi.AndFilter, query string) error {
+	if strings.TrimSpace(query) == "" || filter == nil {
+		return nil
+	}
+	if len(query) > maxQueryLength {
+		return i18n.NewError(ctx, coremsgs.MsgQueryTooComplex, maxQueryLength, maxQueryConditions, maxQueryDepth)
+	}
+	tokens, err := tokenizeQuery(ctx, query)
+	if err != nil {
+		return err
+	}
+	qp := &queryParser{
+		ctx:    ctx,
+		fb:     filter.Builder(),
+		fields: make(map[string]bool),
+		tokens: tokens,
+	}
+	for _, field := range qp.fb.Fields() {
+		qp.fields[strings.ToLower(field)] = true
+	}
+
+	if !qp.peek().is("ORDER") {
+		condition, err := qp.parseOr(0)
+		if err != nil {
+			return err
+		}
+		filter.Condition(condition)
+	}
+	if qp.peek().is("ORDER") {
+		if err := qp.parseOrderBy(filter); err != nil {
+			return err
+		}
+	}
+	if qp.peek().tokenType != queryTokenEOF {
+		return qp.expected("AND, OR or ORDER BY")
+	}
+	return nil
+}
+
+func (qp *queryParser) peek() *queryToken {
+	return qp.tokens[qp.pos]
+}
+
+func (qp *queryParser) next() *queryToken {
+	t := qp.tokens[qp.pos]
+	if t.tokenType != queryTokenEOF {
+		qp.pos++
+	}
+	return t
+}
+
+func (qp *queryParser) accept(value string) bool {
+	if qp.peek().is(value) {
+		qp.pos++
+		return true
+	}
+	return false
+}
+
+func (qp *queryParser) expected(what string) error {
+	return i18n.NewError(qp.ctx, coremsgs.MsgInvalidQuery, qp.peek().pos, what)
+}
+
+func (qp *queryParser) parseOr(depth int) (ffapi.Filter, error) {
+	filters, err := qp.parseList("OR", func() (ffapi.Filter, error) { return qp.parseAnd(depth) })
+	if err != nil {
+		return nil, err
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return qp.fb.Or(filters...), nil
+}
+
+func (qp *queryParser) parseAnd(depth int) (ffapi.Filter, error) {
+	filters, err := qp.parseList("AND", func() (ffapi.Filter, error) { return qp.parseTerm(depth) })
+	if err != nil {
+		return nil, err
+	}
+	if len(filters) == 1 {
+		return filters[0], nil
+	}
+	return qp.fb.And(filters...), nil
+}
+
+func (qp *queryParser) parseList(separator string, parse func() (ffapi.Filter, error)) ([]ffapi.Filter, error) {
+	var filters []ffapi.Filter
+	for {
+		filter, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+		if !qp.accept(separator) {
+			return filters, nil
+		}
+	}
+}
+
+func (qp *queryParser) parseTerm(depth int) (ffapi.Filter, error) {
+	if qp.accept("(") {
+		if depth >= maxQueryDepth {
+			return nil, i18n.NewError(qp.ctx, coremsgs.MsgQueryTooComplex, maxQueryLength, maxQueryConditions, maxQueryDepth)
+		}
+		filter, err := qp.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if !qp.accept(")") {
+			return nil, qp.expected(")")
+		}
+		return filter, nil
+	}
+	return qp.parseCondition()
+}
+
+func (qp *queryParser) parseField() (string, error) {
+	t := qp.peek()
+	if t.tokenType != queryTokenWord {
+		return "", qp.expected("field")
+	}
+	field := strings.ToLower(t.value)
+	if !qp.fields[field] {
+		return "", i18n.NewError(qp.ctx, coremsgs.MsgQueryUnknownField, t.value)
+	}
+	qp.pos++
+	return field, nil
+}
+
+func (qp *queryParser) parseValue() (driver.Value, error) {
+	t := qp.peek()
+	switch {
+	case t.tokenType == queryTokenString:
+	case t.tokenType == queryTokenWord && t.is("NULL"):
+		qp.pos++
+		return nil, nil
+	case t.tokenType == queryTokenWord:
+	default:
+		return nil, qp.expected("value")
+	}
+	qp.pos++
+	return t.value, nil
+}
+
+func (qp *queryParser) parseValues() ([]driver.Value, error) {
+	if !qp.accept("(") {
+		return nil, qp.expected("(")
+	}
+	var values []driver.Value
+	for {
+		value, err := qp.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if qp.accept(")") {
+			return values, nil
+		}
+		if !qp.accept(",") {
+			return nil, qp.expected(", or )")
+		}
+	}
+}
+
+type queryOp struct {
+	op    func(name string, value driver.Value) ffapi.Filter
+	notOp func(name string, value driver.Value) ffapi.Filter
+}
+
+func (qp *queryParser) queryOps() map[string]queryOp {
+	fb := qp.fb
+	return map[string]queryOp{
+		"=":           {op: fb.Eq},
+		"!=":          {op: fb.Neq},
+		"<":           {op: fb.Lt},
+		"<=":          {op: fb.Lte},
+		">":           {op: fb.Gt},
+		">=":          {op: fb.Gte},
+		"CONTAINS":    {op: fb.Contains, notOp: fb.NotContains},
+		"ICONTAINS":   {op: fb.IContains, notOp: fb.NotIContains},
+		"STARTSWITH":  {op: fb.StartsWith, notOp: fb.NotStartsWith},
+		"ISTARTSWITH": {op: fb.IStartsWith, notOp: fb.NotIStartsWith},
+		"ENDSWITH":    {op: fb.EndsWith, notOp: fb.NotEndsWith},
+		"IENDSWITH":   {op: fb.IEndsWith, notOp: fb.NotIEndsWith},
+	}
+}
+
+// parseCondition parses a single comparison of a field, such as "tag = order_created", "topic NOT IN (t1, t2)",
+// "author IS NOT NULL" or "tag ICONTAINS order"
+func (qp *queryParser) parseCondition() (ffapi.Filter, error) {
+	qp.conditions++
+	if qp.conditions > maxQueryConditions {
+		return nil, i18n.NewError(qp.ctx, coremsgs.MsgQueryTooComplex, maxQueryLength, maxQueryConditions, maxQueryDepth)
+	}
+	field, err := qp.parseField()
+	if err != nil {
+		return nil, err
+	}
+
+	if qp.accept("IS") {
+		negate := qp.accept("NOT")
+		if !qp.accept("NULL") {
+			return nil, qp.expected("NULL")
+		}
+		if negate {
+			return qp.fb.Neq(field, nil), nil
+		}
+		return qp.fb.Eq(field, nil), nil
+	}
+
+	negate := qp.accept("NOT")
+	if qp.accept("IN") {
+		values, err := qp.parseValues()
+		if err != nil {
+			return nil, err
+		}
+		if negate {
+			return qp.fb.NotIn(field, values), nil
+		}
+		return qp.fb.In(field, values), nil
+	}
+
+	opToken := qp.peek()
+	op, ok := qp.queryOps()[strings.ToUpper(opToken.value)]
+	if !ok || opToken.tokenType == queryTokenString || (negate && op.notOp == nil) {
+		return nil, qp.expected("operator")
+	}
+	qp.pos++
+	value, err := qp.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if negate {
+		return op.notOp(field, value), nil
+	}
+	return op.op(field, value), nil
+}
+
+// parseOrderBy parses the sort, such as "ORDER BY created DESC, sequence"
+func (qp *queryParser) parseOrderBy(filter ffapi.AndFilter) error {
+	qp.next()
+	if !qp.accept("BY") {
+		return qp.expected("BY")
+	}
+	for {
+		field, err := qp.parseField()
+		if err != nil {
+			return err
+		}
+		if qp.accept("DESC") {
+			field = "-" + field
+		} else {
+			qp.accept("ASC")
+		}
+		filter.Sort(field)
+		if !qp.accept(",") {
+			return nil
+		}
+	}
+}
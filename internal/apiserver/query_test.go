@@ -0,0 +1,145 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testQuery(t *testing.T, query string) (string, error) {
+	filter := database.MessageQueryFactory.NewFilter(context.Background()).And()
+	if err := applyQuery(context.Background(), filter, query); err != nil {
+		return "", err
+	}
+	fi, err := filter.Finalize()
+	assert.NoError(t, err)
+	return fi.String(), nil
+}
+
+func TestQueryParamAdded(t *testing.T) {
+	assert.Equal(t, queryParam, getMsgs.QueryParams[len(getMsgs.QueryParams)-2].Name)
+	for _, qp := range append(getMsgByID.QueryParams, postNewMessageBroadcast.QueryParams...) {
+		assert.NotEqual(t, queryParam, qp.Name)
+	}
+}
+
+func TestApplyQuery(t *testing.T) {
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{"", ""},
+		{"tag=t1", "( tag == 't1' )"},
+		{"type = broadcast AND (topics = t1 OR topics = 't 2')", "( ( type == 'broadcast' ) && ( ( topics == 't1' ) || ( topics == 't 2' ) ) )"},
+		{"type=broadcast and topics=t1 or tag=x", "( ( ( type == 'broadcast' ) && ( topics == 't1' ) ) || ( tag == 'x' ) )"},
+		{"sequence>=10 AND sequence<20 AND sequence>5 AND sequence<=15 AND tag!=x", "( ( sequence >= 10 ) && ( sequence << 20 ) && ( sequence >> 5 ) && ( sequence <= 15 ) && ( tag != 'x' ) )"},
+		{"tag CONTAINS a AND tag NOT ICONTAINS b", "( ( tag %= 'a' ) && ( tag ;% 'b' ) )"},
+		{"tag STARTSWITH a AND tag NOT STARTSWITH b AND tag ISTARTSWITH c AND tag NOT ISTARTSWITH d", "( ( tag ^= 'a' ) && ( tag !^ 'b' ) && ( tag :^ 'c' ) && ( tag ;^ 'd' ) )"},
+		{"tag ENDSWITH a AND tag NOT ENDSWITH b AND tag IENDSWITH c AND tag NOT IENDSWITH d", "( ( tag $= 'a' ) && ( tag !$ 'b' ) && ( tag :$ 'c' ) && ( tag ;$ 'd' ) )"},
+		{"tag NOT CONTAINS a OR tag ICONTAINS b", "( ( tag !% 'a' ) || ( tag :% 'b' ) )"},
+		{"topics IN (t1, 't''2') AND tag NOT IN (\"x\")", "( ( topics IN ['t1','t'2'] ) && ( tag NI ['x'] ) )"},
+		{"tag IS NULL OR tag is not null", "( ( tag == null ) || ( tag != null ) )"},
+		{"tag = NULL", "( tag == null )"},
+		{"ORDER BY sequence DESC, tag asc, created", " sort=-sequence,tag,created"},
+		{"Tag=t1 ORDER BY Sequence", "( tag == 't1' ) sort=sequence"},
+	} {
+		result, err := testQuery(t, tc.query)
+		assert.NoError(t, err, tc.query)
+		assert.Equal(t, tc.expected, result, tc.query)
+	}
+}
+
+func TestApplyQueryErrors(t *testing.T) {
+	for _, tc := range []struct {
+		query string
+		err   string
+	}{
+		{"tag='t1", "FF10607.*position 5.*closing quote"},
+		{"tag ! t1", "FF10607.*position 5.*!="},
+		{"tag=t1;", "FF10607.*position 7"},
+		{"wrong=t1", "FF10609.*wrong"},
+		{"'tag'=t1", "FF10607.*position 1.*field"},
+		{"tag", "FF10607.*position 4.*operator"},
+		{"tag 'CONTAINS' x", "FF10607.*operator"},
+		{"tag NOT = x", "FF10607.*operator"},
+		{"tag =", "FF10607.*value"},
+		{"tag = (", "FF10607.*value"},
+		{"(tag = x", "FF10607.*\\)"},
+		{"(tag = ", "FF10607.*value"},
+		{"tag = x AND", "FF10607.*field"},
+		{"tag = x y", "FF10607.*AND, OR or ORDER BY"},
+		{"tag IS x", "FF10607.*NULL"},
+		{"tag IN x", "FF10607.*\\("},
+		{"tag IN (x y)", "FF10607.*, or \\)"},
+		{"tag IN (,)", "FF10607.*value"},
+		{"ORDER sequence", "FF10607.*BY"},
+		{"ORDER BY wrong", "FF10609"},
+		{"tag=x ORDER BY", "FF10607.*field"},
+		{strings.Repeat("(", 11) + "tag=x" + strings.Repeat(")", 11), "FF10608"},
+		{strings.Repeat("tag=x OR ", 100) + "tag=x", "FF10608"},
+		{strings.Repeat(" ", maxQueryLength) + "tag=x", "FF10608"},
+	} {
+		_, err := testQuery(t, tc.query)
+		assert.Regexp(t, tc.err, err, tc.query)
+	}
+}
+
+func TestApplyQueryNoFilter(t *testing.T) {
+	var filter ffapi.AndFilter
+	assert.NoError(t, applyQuery(context.Background(), filter, "tag=x"))
+}
+
+func TestGetMessagesQuery(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	q := url.QueryEscape("tag=t1 OR (topics=t2 AND author=org1) ORDER BY sequence DESC")
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?q="+q+"&type=broadcast&limit=10", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		return assert.NoError(t, err) &&
+			assert.Equal(t, "( type == 'broadcast' ) && ( ( tag == 't1' ) || ( ( topics == 't2' ) && ( author == 'org1' ) ) ) sort=-sequence limit=10", fi.String())
+	})).Return([]*core.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesQueryInvalid(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?q="+url.QueryEscape("tag=t1 OR"), nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10607", res.Body.String())
+}
@@ -0,0 +1,270 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+)
+
+const (
+	rateLimitScopePrincipal = "principal"
+	rateLimitScopeNamespace = "namespace"
+
+	// rateLimitSweepInterval is how often the usage of keys that no longer affect their limits is discarded
+	rateLimitSweepInterval = time.Minute
+)
+
+// rateLimits is a token bucket rate limit and a daily quota, applied separately to each key in a scope
+type rateLimits struct {
+	scope             string
+	requestsPerSecond float64
+	burst             float64
+	dailyQuota        int64
+	metricsEnabled    bool
+
+	mux       sync.Mutex
+	keys      map[string]*rateLimitUsage
+	swept     time.Time
+	day       string
+	usedToday int64
+}
+
+type rateLimitUsage struct {
+	tokens  float64
+	updated time.Time
+	day     string
+	used    int64
+}
+
+func newRateLimits(scope string, requestsPerSecond float64, burst, dailyQuota int64, metricsEnabled bool) *rateLimits {
+	if burst <= 0 {
+		burst = int64(math.Ceil(requestsPerSecond))
+	}
+	return &rateLimits{
+		scope:             scope,
+		requestsPerSecond: requestsPerSecond,
+		burst:             float64(burst),
+		dailyQuota:        dailyQuota,
+		metricsEnabled:    metricsEnabled,
+		keys:              make(map[string]*rateLimitUsage),
+	}
+}
+
+func (rl *rateLimits) enabled() bool {
+	return rl.requestsPerSecond > 0 || rl.dailyQuota > 0
+}
+
+// take counts a request against the limits of a key, returning how long to wait before retrying if
+// the request is over either of them
+func (rl *rateLimits) take(ctx context.Context, key string, now time.Time) (time.Duration, error) {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	now = now.UTC()
+	day := now.Format("2006-01-02")
+	rl.sweep(now, day)
+	usage, ok := rl.keys[key]
+	if !ok {
+		usage = &rateLimitUsage{tokens: rl.burst, updated: now}
+		rl.keys[key] = usage
+	}
+
+	if usage.day != day {
+		usage.day = day
+		usage.used = 0
+	}
+	if rl.dailyQuota > 0 && usage.used >= rl.dailyQuota {
+		rl.rejected("quota")
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		return tomorrow.Sub(now), i18n.NewError(ctx, coremsgs.MsgDailyQuotaExceeded, rl.dailyQuota, rl.scope, key)
+	}
+
+	if rl.requestsPerSecond > 0 {
+		usage.tokens = math.Min(rl.burst, usage.tokens+now.Sub(usage.updated).Seconds()*rl.requestsPerSecond)
+		usage.updated = now
+		if usage.tokens < 1 {
+			rl.rejected("rate")
+			return time.Duration((1 - usage.tokens) / rl.requestsPerSecond * float64(time.Second)), i18n.NewError(ctx, coremsgs.MsgRateLimitExceeded, rl.requestsPerSecond, rl.scope, key)
+		}
+		usage.tokens--
+	}
+
+	usage.used++
+	rl.usedToday++
+	rl.updateQuotaUsage()
+	return 0, nil
+}
+
+// refund returns a request taken for a key, when it is rejected by another limit and is not served
+func (rl *rateLimits) refund(key string) {
+	rl.mux.Lock()
+	defer rl.mux.Unlock()
+
+	usage, ok := rl.keys[key]
+	if !ok {
+		return
+	}
+	if rl.requestsPerSecond > 0 {
+		usage.tokens = math.Min(rl.burst, usage.tokens+1)
+	}
+	if usage.used > 0 {
+		usage.used--
+	}
+	if rl.usedToday > 0 {
+		rl.usedToday--
+	}
+	rl.updateQuotaUsage()
+}
+
+func (rl *rateLimits) updateQuotaUsage() {
+	if rl.metricsEnabled && rl.dailyQuota > 0 {
+		metrics.QuotaUsageGauge.WithLabelValues(rl.scope).Set(float64(rl.usedToday))
+	}
+}
+
+// sweep discards the usage of keys that would start afresh anyway - those whose bucket has refilled, and
+// that have no requests counted against a daily quota today - so the keys do not grow without bound.
+// Must be called with the lock held.
+func (rl *rateLimits) sweep(now time.Time, day string) {
+	if rl.day != day {
+		rl.day = day
+		rl.usedToday = 0
+	}
+	if now.Sub(rl.swept) < rateLimitSweepInterval {
+		return
+	}
+	rl.swept = now
+	for key, usage := range rl.keys {
+		refilled := rl.requestsPerSecond <= 0 || usage.tokens+now.Sub(usage.updated).Seconds()*rl.requestsPerSecond >= rl.burst
+		if refilled && (rl.dailyQuota <= 0 || usage.day != day) {
+			delete(rl.keys, key)
+		}
+	}
+}
+
+func (rl *rateLimits) rejected(reason string) {
+	if rl.metricsEnabled {
+		metrics.RateLimitRejectedCounter.WithLabelValues(rl.scope, reason).Inc()
+	}
+}
+
+// rateLimiter applies the rate limits and quotas for principals and namespaces to the API routes
+type rateLimiter struct {
+	principal        *rateLimits
+	namespace        *rateLimits
+	defaultNamespace string
+}
+
+func newRateLimiter(metricsEnabled bool) *rateLimiter {
+	return &rateLimiter{
+		principal: newRateLimits(rateLimitScopePrincipal,
+			config.GetFloat64(coreconfig.RateLimitPrincipalRequestsPerSecond),
+			config.GetInt64(coreconfig.RateLimitPrincipalBurst),
+			config.GetInt64(coreconfig.RateLimitPrincipalDailyQuota),
+			metricsEnabled),
+		namespace: newRateLimits(rateLimitScopeNamespace,
+			config.GetFloat64(coreconfig.RateLimitNamespaceRequestsPerSecond),
+			config.GetInt64(coreconfig.RateLimitNamespaceBurst),
+			config.GetInt64(coreconfig.RateLimitNamespaceDailyQuota),
+			metricsEnabled),
+		defaultNamespace: config.GetString(coreconfig.NamespacesDefault),
+	}
+}
+
+// principalKey is the principal authenticated by the transport of the request, or the client address for
+// requests without one. The limits are checked before the auth plugin has verified the credentials of the
+// request, so the principal header and basic auth username cannot be used - as a client could choose a new
+// one for each request to avoid the limits.
+func principalKey(req *http.Request) string {
+	if principal := orchestrator.TransportPrincipal(req.Context()); principal != "" {
+		return principal
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+func (rl *rateLimiter) check(req *http.Request, mgr namespace.Manager) (time.Duration, error) {
+	now := time.Now()
+	principal := principalKey(req)
+	if rl.principal.enabled() {
+		if retryAfter, err := rl.principal.take(req.Context(), principal, now); err != nil {
+			return retryAfter, err
+		}
+	}
+	if rl.namespace.enabled() {
+		ns := mux.Vars(req)["ns"]
+		if ns == "" {
+			ns = rl.defaultNamespace
+		}
+		// Requests to namespaces that do not exist are rejected by the route, so are not counted - and
+		// cannot grow the keys without bound
+		if _, err := mgr.Orchestrator(req.Context(), ns, true); err == nil {
+			if retryAfter, err := rl.namespace.take(req.Context(), ns, now); err != nil {
+				// The request is not served, so does not count against the limits of the principal
+				if rl.principal.enabled() {
+					rl.principal.refund(principal)
+				}
+				return retryAfter, err
+			}
+		}
+	}
+	return 0, nil
+}
+
+// middleware rejects API requests over the limits with a 429, and a Retry-After header in whole seconds
+func (rl *rateLimiter) middleware(mgr namespace.Manager) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if !strings.HasPrefix(req.URL.Path, "/api/v1/") {
+				next.ServeHTTP(res, req)
+				return
+			}
+			retryAfter, err := rl.check(req, mgr)
+			if err != nil {
+				log.L(req.Context()).Warnf("Rejecting %s %s: %s", req.Method, req.URL.Path, err)
+				res.Header().Set("Content-Type", "application/json")
+				res.Header().Set("Retry-After", fmt.Sprintf("%d", int64(math.Ceil(math.Max(retryAfter.Seconds(), 1)))))
+				res.WriteHeader(http.StatusTooManyRequests)
+				_ = json.NewEncoder(res).Encode(&fftypes.RESTError{Error: err.Error()})
+				return
+			}
+			next.ServeHTTP(res, req)
+		})
+	}
+}
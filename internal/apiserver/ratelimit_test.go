@@ -0,0 +1,267 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewAPIServerRateLimits(t *testing.T) {
+	coreconfig.Reset()
+	assert.Nil(t, NewAPIServer().(*apiServer).rateLimiter)
+
+	config.Set(coreconfig.RateLimitEnabled, true)
+	config.Set(coreconfig.RateLimitPrincipalRequestsPerSecond, 2.5)
+	config.Set(coreconfig.RateLimitNamespaceDailyQuota, 1000)
+	rl := NewAPIServer().(*apiServer).rateLimiter
+	assert.NotNil(t, rl)
+	assert.Equal(t, float64(3), rl.principal.burst)
+	assert.True(t, rl.principal.enabled())
+	assert.True(t, rl.namespace.enabled())
+	assert.Equal(t, "default", rl.defaultNamespace)
+}
+
+func TestRateLimitPrincipal(t *testing.T) {
+	mgr, _, as := newTestServer()
+	as.rateLimiter = &rateLimiter{
+		principal: newRateLimits(rateLimitScopePrincipal, 0.1, 2, 0, false),
+		namespace: newRateLimits(rateLimitScopeNamespace, 0, 0, 0, false),
+	}
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	// Unverified usernames do not get their own limits, so cannot be rotated to avoid them
+	for i, status := range []int{200, 200, 429} {
+		req := httptest.NewRequest("GET", "/api/v1/websockets", nil)
+		req.SetBasicAuth(fmt.Sprintf("user-%d", i), "secret")
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, status, res.Result().StatusCode, i)
+		if status == 429 {
+			assert.Equal(t, "10", res.Result().Header.Get("Retry-After"))
+			assert.Regexp(t, "FF10592.*192.0.2.1", res.Body.String())
+		}
+	}
+
+	// Principals authenticated by the transport have their own limits
+	for i, status := range []int{200, 200, 429} {
+		req := httptest.NewRequest("GET", "/api/v1/websockets", nil)
+		req = req.WithContext(orchestrator.WithPrincipal(req.Context(), "team-a"))
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, status, res.Result().StatusCode, i)
+		if status == 429 {
+			assert.Regexp(t, "FF10592.*team-a", res.Body.String())
+		}
+	}
+
+	// Only the API routes are limited
+	req := httptest.NewRequest("GET", "/api/swagger.json", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestRateLimitNamespace(t *testing.T) {
+	mgr, o, as := newTestServer()
+	as.rateLimiter = &rateLimiter{
+		principal:        newRateLimits(rateLimitScopePrincipal, 0, 0, 0, false),
+		namespace:        newRateLimits(rateLimitScopeNamespace, 0, 0, 1, false),
+		defaultNamespace: "default",
+	}
+	mgr.On("Orchestrator", mock.Anything, "ns1", true).Return(o, nil)
+	mgr.On("Orchestrator", mock.Anything, "default", true).Return(o, nil)
+	mgr.On("Orchestrator", mock.Anything, "unknown", true).Return(nil, fmt.Errorf("pop"))
+	mgr.On("Orchestrator", mock.Anything, "unknown", false).Return(nil, fmt.Errorf("pop"))
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	as.rateLimiter.namespace.keys["ns1"] = &rateLimitUsage{day: time.Now().UTC().Format("2006-01-02"), used: 1}
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/status", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 429, res.Result().StatusCode)
+	assert.Regexp(t, "FF10593.*namespace 'ns1'", res.Body.String())
+
+	as.rateLimiter.namespace.keys["default"] = &rateLimitUsage{day: time.Now().UTC().Format("2006-01-02"), used: 1}
+	req = httptest.NewRequest("GET", "/api/v1/status", nil)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 429, res.Result().StatusCode)
+	assert.Regexp(t, "FF10593.*namespace 'default'", res.Body.String())
+
+	// Namespaces that do not exist are not counted
+	req = httptest.NewRequest("GET", "/api/v1/namespaces/unknown/status", nil)
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.NotEqual(t, 429, res.Result().StatusCode)
+	assert.NotContains(t, as.rateLimiter.namespace.keys, "unknown")
+}
+
+func TestRateLimitNamespaceRefundsPrincipal(t *testing.T) {
+	mgr, o, as := newTestServer()
+	as.rateLimiter = &rateLimiter{
+		principal: newRateLimits(rateLimitScopePrincipal, 0.1, 1, 5, false),
+		namespace: newRateLimits(rateLimitScopeNamespace, 0, 0, 1, false),
+	}
+	mgr.On("Orchestrator", mock.Anything, "ns1", true).Return(o, nil)
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	// A request rejected for the namespace does not use up the limits of the principal
+	as.rateLimiter.namespace.keys["ns1"] = &rateLimitUsage{day: time.Now().UTC().Format("2006-01-02"), used: 1}
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/status", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 429, res.Result().StatusCode)
+	assert.Regexp(t, "FF10593.*namespace 'ns1'", res.Body.String())
+	usage := as.rateLimiter.principal.keys["192.0.2.1"]
+	assert.Equal(t, float64(1), usage.tokens)
+	assert.Equal(t, int64(0), usage.used)
+	assert.Equal(t, int64(0), as.rateLimiter.principal.usedToday)
+}
+
+func TestRateLimitRefund(t *testing.T) {
+	metrics.Clear()
+	metrics.Registry()
+	rl := newRateLimits(rateLimitScopeNamespace, 1, 2, 5, true)
+	now := time.Now()
+	ctx := context.Background()
+
+	_, err := rl.take(ctx, "ns1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.QuotaUsageGauge.WithLabelValues("namespace")))
+
+	rl.refund("ns1")
+	assert.Equal(t, float64(2), rl.keys["ns1"].tokens)
+	assert.Equal(t, int64(0), rl.keys["ns1"].used)
+	assert.Equal(t, float64(0), testutil.ToFloat64(metrics.QuotaUsageGauge.WithLabelValues("namespace")))
+
+	// Refunds never take the usage below zero
+	rl.refund("ns1")
+	rl.refund("unknown")
+	assert.Equal(t, float64(2), rl.keys["ns1"].tokens)
+	assert.Equal(t, int64(0), rl.keys["ns1"].used)
+	assert.Equal(t, int64(0), rl.usedToday)
+}
+
+func TestRateLimitRefill(t *testing.T) {
+	rl := newRateLimits(rateLimitScopePrincipal, 2, 1, 0, false)
+	now := time.Now()
+	ctx := context.Background()
+
+	_, err := rl.take(ctx, "team-a", now)
+	assert.NoError(t, err)
+	retryAfter, err := rl.take(ctx, "team-a", now.Add(250*time.Millisecond))
+	assert.Regexp(t, "FF10592", err)
+	assert.Equal(t, 250*time.Millisecond, retryAfter)
+	_, err = rl.take(ctx, "team-a", now.Add(500*time.Millisecond))
+	assert.NoError(t, err)
+}
+
+func TestRateLimitDailyQuota(t *testing.T) {
+	metrics.Clear()
+	metrics.Registry()
+	rl := newRateLimits(rateLimitScopeNamespace, 0, 0, 2, true)
+	now := time.Date(2023, 6, 1, 23, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	_, err := rl.take(ctx, "ns1", now)
+	assert.NoError(t, err)
+	_, err = rl.take(ctx, "ns1", now)
+	assert.NoError(t, err)
+	_, err = rl.take(ctx, "ns2", now)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), testutil.ToFloat64(metrics.QuotaUsageGauge.WithLabelValues("namespace")))
+
+	retryAfter, err := rl.take(ctx, "ns1", now)
+	assert.Regexp(t, "FF10593", err)
+	assert.Equal(t, time.Hour, retryAfter)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.RateLimitRejectedCounter.WithLabelValues("namespace", "quota")))
+
+	// The quota resets at midnight UTC
+	_, err = rl.take(ctx, "ns1", now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.QuotaUsageGauge.WithLabelValues("namespace")))
+}
+
+func TestRateLimitSweep(t *testing.T) {
+	rl := newRateLimits(rateLimitScopePrincipal, 0.01, 1, 0, false)
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	_, err := rl.take(ctx, "idle", now)
+	assert.NoError(t, err)
+	_, err = rl.take(ctx, "busy", now.Add(50*time.Second))
+	assert.NoError(t, err)
+
+	// Buckets that have refilled are discarded, and those still refilling are kept
+	_, err = rl.take(ctx, "other", now.Add(101*time.Second))
+	assert.NoError(t, err)
+	assert.NotContains(t, rl.keys, "idle")
+	assert.Contains(t, rl.keys, "busy")
+	assert.Contains(t, rl.keys, "other")
+}
+
+func TestRateLimitSweepDailyQuota(t *testing.T) {
+	rl := newRateLimits(rateLimitScopeNamespace, 0, 0, 5, false)
+	now := time.Date(2023, 6, 1, 23, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	_, err := rl.take(ctx, "ns1", now)
+	assert.NoError(t, err)
+
+	// The usage is kept for the rest of the day, as it counts against the quota
+	_, err = rl.take(ctx, "ns2", now.Add(30*time.Minute))
+	assert.NoError(t, err)
+	assert.Contains(t, rl.keys, "ns1")
+
+	_, err = rl.take(ctx, "ns2", now.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.NotContains(t, rl.keys, "ns1")
+	assert.Equal(t, int64(1), rl.usedToday)
+}
+
+func TestPrincipalKey(t *testing.T) {
+	coreconfig.Reset()
+	req := httptest.NewRequest("GET", "/api/v1/status", nil)
+	req.RemoteAddr = "10.0.0.1:12345"
+	assert.Equal(t, "10.0.0.1", principalKey(req))
+
+	req.RemoteAddr = "pipe"
+	assert.Equal(t, "pipe", principalKey(req))
+
+	// Unverified principals are not used
+	req.SetBasicAuth("team-a", "secret")
+	assert.Equal(t, "pipe", principalKey(req))
+	config.Set(coreconfig.RBACPrincipalHeader, "X-Auth-User")
+	req.Header = http.Header{"X-Auth-User": []string{"team-b"}}
+	assert.Equal(t, "pipe", principalKey(req))
+
+	req = req.WithContext(orchestrator.WithPrincipal(req.Context(), "team-c"))
+	assert.Equal(t, "team-c", principalKey(req))
+}
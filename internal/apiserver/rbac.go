@@ -0,0 +1,85 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// readRoutes are the routes other than GETs that only query the namespace, or consume events from it
+var readRoutes = map[string]bool{
+	"apis/{apiName}/query/{methodPath}": true,
+	"contracts/query":                   true,
+	"graphql":                           true,
+	"subscriptions/{subid}/ack":         true,
+	"subscriptions/{subid}/pull":        true,
+	"tokens/transfers/check":            true,
+	"verifiers/resolve":                 true,
+}
+
+// sendRoutes are the routes that send messages, upload and publish data, and invoke contracts
+var sendRoutes = map[string]bool{
+	"apis/{apiName}/invoke/{methodPath}": true,
+	"contracts/invoke":                   true,
+	"data":                               true,
+	"data/{dataid}":                      true,
+	"data/{dataid}/blob/publish":         true,
+	"data/{dataid}/value/publish":        true,
+	"datauploads":                        true,
+	"datauploads/{uploadid}":             true,
+	"datauploads/{uploadid}/complete":    true,
+	"messages/broadcast":                 true,
+	"messages/private":                   true,
+	"messages/requestreply":              true,
+	"scheduledmessages/{msgid}/cancel":   true,
+	"draftmessages/{msgid}/commit":       true,
+	"draftmessages/{msgid}/discard":      true,
+	"messages/{msgid}/accept":            true,
+	"messages/{msgid}/reject":            true,
+	"messages/{msgid}/read":              true,
+	"messages/{msgid}/recall":            true,
+}
+
+// requiredRole is the role a principal needs in the namespace to use a route. Token routes need the
+// token admin role, and everything else that changes the namespace needs the namespace admin role.
+func requiredRole(route *ffapi.Route) core.Role {
+	path := strings.TrimPrefix(route.Path, "namespaces/{ns}/")
+	switch {
+	case route.Method == http.MethodGet || readRoutes[path]:
+		return core.RoleReader
+	case sendRoutes[path]:
+		return core.RoleSender
+	case strings.HasPrefix(path, "tokens/"):
+		return core.RoleTokenAdmin
+	default:
+		return core.RoleNamespaceAdmin
+	}
+}
+
+func authorizeRole(r *ffapi.APIRequest, or orchestrator.Orchestrator, route *ffapi.Route) error {
+	return or.AuthorizeRole(r.Req.Context(), &fftypes.AuthReq{
+		Method: r.Req.Method,
+		URL:    r.Req.URL,
+		Header: r.Req.Header,
+	}, requiredRole(route))
+}
@@ -0,0 +1,161 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRBACServer() (*orchestratormocks.Orchestrator, *mux.Router) {
+	mgr, o, as := newTestServer()
+	as.rbacEnabled = true
+	r := as.createMuxRouter(context.Background(), mgr)
+	return o, r
+}
+
+func TestRequiredRole(t *testing.T) {
+	for route, role := range map[string]core.Role{
+		"getMsgs":                        core.RoleReader,
+		"getMsgsNamespace":               core.RoleReader,
+		"postContractQuery":              core.RoleReader,
+		"postContractAPIQuery":           core.RoleReader,
+		"postGraphQL":                    core.RoleReader,
+		"postSubscriptionPull":           core.RoleReader,
+		"postNewMessageBroadcast":        core.RoleSender,
+		"postNewMessagePrivateNamespace": core.RoleSender,
+		"postData":                       core.RoleSender,
+		"postContractInvoke":             core.RoleSender,
+		"postContractAPIInvoke":          core.RoleSender,
+		"postScheduledMsgCancel":         core.RoleSender,
+		"postDraftMsgCommit":             core.RoleSender,
+		"postDraftMsgDiscard":            core.RoleSender,
+		"postMsgAccept":                  core.RoleSender,
+		"postMsgReject":                  core.RoleSender,
+		"postMsgRead":                    core.RoleSender,
+		"postMsgRecall":                  core.RoleSender,
+		"postDataUpload":                 core.RoleSender,
+		"patchDataUpload":                core.RoleSender,
+		"postDataUploadComplete":         core.RoleSender,
+		"deleteDataUpload":               core.RoleSender,
+		"getDataUploads":                 core.RoleReader,
+		"postTokenTransfer":              core.RoleTokenAdmin,
+		"postTokenPoolNamespace":         core.RoleTokenAdmin,
+		"deleteTokenPool":                core.RoleTokenAdmin,
+		"postNewSubscription":            core.RoleNamespaceAdmin,
+		"postNewDatatype":                core.RoleNamespaceAdmin,
+		"deleteContractAPI":              core.RoleNamespaceAdmin,
+		"postNetworkAction":              core.RoleNamespaceAdmin,
+	} {
+		found := false
+		for _, r := range routes {
+			if r.Name == route {
+				assert.Equal(t, role, requiredRole(r), route)
+				found = true
+			}
+		}
+		assert.True(t, found, route)
+	}
+}
+
+func TestRBACEnforced(t *testing.T) {
+	o, r := newTestRBACServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("AuthorizeRole", mock.Anything, mock.MatchedBy(func(authReq *fftypes.AuthReq) bool {
+		username, _, _ := (&http.Request{Header: authReq.Header}).BasicAuth()
+		return username == "team-a" && authReq.Method == http.MethodPost
+	}), core.RoleSender).Return(nil)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	mbm := &broadcastmocks.Manager{}
+	o.On("Broadcast").Return(mbm)
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&core.MessageInOut{})
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/messages/broadcast", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.SetBasicAuth("team-a", "secret")
+	res := httptest.NewRecorder()
+
+	mbm.On("BroadcastMessage", mock.Anything, mock.AnythingOfType("*core.MessageInOut"), false).
+		Return(&core.Message{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 202, res.Result().StatusCode)
+	o.AssertExpectations(t)
+}
+
+func TestRBACForbidden(t *testing.T) {
+	o, r := newTestRBACServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	o.On("AuthorizeRole", mock.Anything, mock.Anything, core.RoleNamespaceAdmin).
+		Return(i18n.NewError(context.Background(), coremsgs.MsgRBACForbidden, "team-a", "ns1", core.RoleNamespaceAdmin))
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&core.Subscription{})
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/subscriptions", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 403, res.Result().StatusCode)
+}
+
+func TestRBACGlobalRoutesNotEnforced(t *testing.T) {
+	o, r := newTestRBACServer()
+	req := httptest.NewRequest("GET", "/api/v1/websockets", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	o.AssertNotCalled(t, "AuthorizeRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRBACFormUploadForbidden(t *testing.T) {
+	o, r := newTestRBACServer()
+	o.On("AuthorizeRole", mock.Anything, mock.Anything, core.RoleSender).Return(fmt.Errorf("pop"))
+
+	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+	writer, err := w.CreateFormFile("file", "filename.ext")
+	assert.NoError(t, err)
+	writer.Write([]byte(`some data`))
+	w.Close()
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/data", &b)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+}
@@ -37,8 +37,9 @@ var getBlockchainEvents = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.BlockchainEvent{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Streaming: true,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return r.FilterResult(cr.or.GetBlockchainEvents(cr.ctx, r.Filter))
+			return r.FilterResult(cr.or.GetBlockchainEvents(database.WithIterator(cr.ctx, cr.iterator), r.Filter))
 		},
 	},
 }
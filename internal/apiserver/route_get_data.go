@@ -37,8 +37,9 @@ var getData = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return core.DataArray{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Streaming: true,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return r.FilterResult(cr.or.GetData(cr.ctx, r.Filter))
+			return r.FilterResult(cr.or.GetData(database.WithIterator(cr.ctx, cr.iterator), r.Filter))
 		},
 	},
 }
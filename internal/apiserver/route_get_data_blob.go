@@ -17,6 +17,7 @@
 package apiserver
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -39,20 +40,34 @@ var getDataBlob = &ffapi.Route{
 	Description:     coremsgs.APIEndpointsGetDataBlob,
 	JSONInputValue:  nil,
 	JSONOutputValue: func() interface{} { return []byte{} },
-	JSONOutputCodes: []int{http.StatusOK},
+	JSONOutputCodes: []int{http.StatusOK, http.StatusPartialContent},
 	Extensions: &coreExtensions{
 		EnabledIf: func(or orchestrator.Orchestrator) bool {
 			return or.Data().BlobsEnabled()
 		},
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			blob, reader, err := cr.or.Data().DownloadBlob(cr.ctx, r.PP["dataid"])
-			if err == nil {
-				r.ResponseHeaders.Set(core.HTTPHeadersBlobHashSHA256, blob.Hash.String())
-				if blob.Size > 0 {
-					r.ResponseHeaders.Set(core.HTTPHeadersBlobSize, strconv.FormatInt(blob.Size, 10))
+			br := core.ParseBlobRange(r.Req.Header.Get("Range"), r.Req.Header.Get("If-Range"))
+			dl, err := cr.or.Data().DownloadBlobRange(cr.ctx, r.PP["dataid"], br)
+			if err != nil {
+				if dl != nil && dl.Blob != nil {
+					// The range could not be satisfied, so tell the client the size of the blob
+					r.ResponseHeaders.Set("Content-Range", fmt.Sprintf("bytes */%d", dl.Blob.Size))
 				}
+				return nil, err
 			}
-			return reader, nil
+			blob := dl.Blob
+			r.ResponseHeaders.Set(core.HTTPHeadersBlobHashSHA256, blob.Hash.String())
+			r.ResponseHeaders.Set("ETag", core.BlobETag(blob.Hash))
+			if blob.Size > 0 {
+				r.ResponseHeaders.Set(core.HTTPHeadersBlobSize, strconv.FormatInt(blob.Size, 10))
+				r.ResponseHeaders.Set("Accept-Ranges", "bytes")
+			}
+			if dl.Partial {
+				r.ResponseHeaders.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", dl.First, dl.Last, blob.Size))
+				r.ResponseHeaders.Set("Content-Length", strconv.FormatInt(dl.Last-dl.First+1, 10))
+				r.SuccessStatus = http.StatusPartialContent
+			}
+			return dl.Reader, nil
 		},
 	},
 }
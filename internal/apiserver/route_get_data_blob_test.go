@@ -18,11 +18,15 @@ package apiserver
 
 import (
 	"bytes"
+	"context"
 	"io/ioutil"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/multipartymocks"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -42,17 +46,76 @@ func TestGetDataBlob(t *testing.T) {
 	res := httptest.NewRecorder()
 
 	blobHash := fftypes.NewRandB32()
-	mdm.On("DownloadBlob", mock.Anything, "abcd1234").
-		Return(&core.Blob{
-			Hash: blobHash,
-			Size: 12345,
-		}, ioutil.NopCloser(bytes.NewReader([]byte("hello"))), nil)
+	mdm.On("DownloadBlobRange", mock.Anything, "abcd1234", (*core.BlobRange)(nil)).
+		Return(&data.BlobDownload{
+			Blob: &core.Blob{
+				Hash: blobHash,
+				Size: 12345,
+			},
+			Reader: ioutil.NopCloser(bytes.NewReader([]byte("hello"))),
+		}, nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "bytes", res.Result().Header.Get("Accept-Ranges"))
+	assert.Equal(t, core.BlobETag(blobHash), res.Result().Header.Get("ETag"))
 	b, err := ioutil.ReadAll(res.Body)
 	assert.NoError(t, err)
 	assert.Equal(t, "hello", string(b))
 	assert.Equal(t, "12345", res.Result().Header.Get(core.HTTPHeadersBlobSize))
 	assert.Equal(t, blobHash.String(), res.Result().Header.Get(core.HTTPHeadersBlobHashSHA256))
 }
+
+func TestGetDataBlobRange(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Range", "bytes=2-6")
+	res := httptest.NewRecorder()
+
+	blobHash := fftypes.NewRandB32()
+	mdm.On("DownloadBlobRange", mock.Anything, "abcd1234", &core.BlobRange{First: 2, Last: 6}).
+		Return(&data.BlobDownload{
+			Blob: &core.Blob{
+				Hash: blobHash,
+				Size: 12345,
+			},
+			Reader:  ioutil.NopCloser(bytes.NewReader([]byte("hello"))),
+			Partial: true,
+			First:   2,
+			Last:    6,
+		}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 206, res.Result().StatusCode)
+	b, err := ioutil.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+	assert.Equal(t, "bytes 2-6/12345", res.Result().Header.Get("Content-Range"))
+	assert.Equal(t, "5", res.Result().Header.Get("Content-Length"))
+}
+
+func TestGetDataBlobRangeNotSatisfiable(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mdm := &datamocks.Manager{}
+	mdm.On("BlobsEnabled").Return(true)
+	o.On("Data").Return(mdm)
+	o.On("MultiParty").Return(&multipartymocks.Manager{})
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/data/abcd1234/blob", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Range", "bytes=20000-")
+	res := httptest.NewRecorder()
+
+	mdm.On("DownloadBlobRange", mock.Anything, "abcd1234", &core.BlobRange{First: 20000, Last: -1}).
+		Return(&data.BlobDownload{Blob: &core.Blob{Size: 12345}}, i18n.NewError(context.Background(), coremsgs.MsgBlobRangeNotSatisfiable, 12345))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 416, res.Result().StatusCode)
+	assert.Equal(t, "bytes */12345", res.Result().Header.Get("Content-Range"))
+}
@@ -0,0 +1,48 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var getDataUploadByID = &ffapi.Route{
+	Name:   "getDataUploadByID",
+	Path:   "datauploads/{uploadid}",
+	Method: http.MethodGet,
+	PathParams: []*ffapi.PathParam{
+		{Name: "uploadid", Description: coremsgs.APIParamsUploadID},
+	},
+	QueryParams:     nil,
+	Description:     coremsgs.APIEndpointsGetDataUploadByID,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &core.BlobUpload{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		EnabledIf: func(or orchestrator.Orchestrator) bool {
+			return or.Data().BlobsEnabled()
+		},
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			return cr.or.Data().GetBlobUpload(cr.ctx, r.PP["uploadid"])
+		},
+	},
+}
@@ -41,11 +41,12 @@ var getEvents = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Event{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Streaming: true,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
 			if strings.EqualFold(r.QP["fetchreferences"], "true") || strings.EqualFold(r.QP["fetchreference"], "true") {
 				return r.FilterResult(cr.or.GetEventsWithReferences(cr.ctx, r.Filter))
 			}
-			return r.FilterResult(cr.or.GetEvents(cr.ctx, r.Filter))
+			return r.FilterResult(cr.or.GetEvents(database.WithIterator(cr.ctx, cr.iterator), r.Filter))
 		},
 	},
 }
@@ -0,0 +1,48 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+var getJobOutput = &ffapi.Route{
+	Name:   "getJobOutput",
+	Path:   "jobs/{jobId}/output",
+	Method: http.MethodGet,
+	PathParams: []*ffapi.PathParam{
+		{Name: "jobId", Description: coremsgs.APIParamsJobID},
+	},
+	QueryParams:     nil,
+	Description:     coremsgs.APIEndpointsGetJobOutput,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return []byte{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			reader, name, err := cr.or.Jobs().GetJobOutputFile(cr.ctx, r.PP["jobId"])
+			if err == nil {
+				r.ResponseHeaders.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", name))
+			}
+			return reader, err
+		},
+	},
+}
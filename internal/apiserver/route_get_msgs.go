@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -33,6 +34,7 @@ var getMsgs = &ffapi.Route{
 	PathParams: nil,
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "fetchdata", IsBool: true, Description: coremsgs.APIFetchDataDesc},
+		{Name: "includeexpired", IsBool: true, Description: coremsgs.APIIncludeExpiredDesc},
 	},
 	FilterFactory:   database.MessageQueryFactory,
 	Description:     coremsgs.APIEndpointsGetMsgs,
@@ -40,11 +42,26 @@ var getMsgs = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Message{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Streaming: true,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			if cr.maskExpired && !strings.EqualFold(r.QP["includeexpired"], "true") {
+				maskExpiredMessages(r.Filter)
+			}
 			if strings.EqualFold(r.QP["fetchdata"], "true") {
 				return r.FilterResult(cr.or.GetMessagesWithData(cr.ctx, r.Filter))
 			}
-			return r.FilterResult(cr.or.GetMessages(cr.ctx, r.Filter))
+			return r.FilterResult(cr.or.GetMessages(database.WithIterator(cr.ctx, cr.iterator), r.Filter, cr.fields...))
 		},
 	},
 }
+
+// maskExpiredMessages hides confirmed messages that are past their expiry time, which are only of
+// interest for a short time, such as ephemeral coordination traffic
+func maskExpiredMessages(filter ffapi.AndFilter) {
+	fb := filter.Builder()
+	filter.Condition(fb.Or(
+		fb.Neq("state", core.MessageStateConfirmed),
+		fb.Eq("expires", nil),
+		fb.Gt("expires", fftypes.Now()),
+	))
+}
@@ -17,8 +17,10 @@
 package apiserver
 
 import (
+	"context"
 	"encoding/json"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -86,3 +88,41 @@ func TestGetMessagesWithCountAndData(t *testing.T) {
 	assert.Equal(t, int64(0), resWithCount.Count)
 	assert.Equal(t, int64(10), resWithCount.Total)
 }
+
+func TestGetMessagesMaskExpired(t *testing.T) {
+	mgr, o, as := newTestServer()
+	as.maskExpired = true
+	r := as.createMuxRouter(context.Background(), mgr)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return strings.Contains(fi.String(), "( ( state != 'confirmed' ) || ( expires == null ) || ( expires >> ")
+	})).Return([]*core.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetMessagesIncludeExpired(t *testing.T) {
+	mgr, o, as := newTestServer()
+	as.maskExpired = true
+	r := as.createMuxRouter(context.Background(), mgr)
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/messages?includeexpired", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetMessages", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		return !strings.Contains(fi.String(), "expires")
+	})).Return([]*core.Message{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
@@ -40,7 +40,11 @@ var getNamespaces = &ffapi.Route{
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return cr.mgr.GetNamespaces(cr.ctx, strings.EqualFold(r.QP["includeinitializing"], "true"))
+			namespaces, err := cr.mgr.GetNamespaces(cr.ctx, strings.EqualFold(r.QP["includeinitializing"], "true"))
+			if err != nil {
+				return nil, err
+			}
+			return visibleNamespaces(cr.ctx, namespaces), nil
 		},
 	},
 }
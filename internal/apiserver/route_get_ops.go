@@ -37,8 +37,9 @@ var getOps = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Operation{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Streaming: true,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return r.FilterResult(cr.or.GetOperations(cr.ctx, r.Filter))
+			return r.FilterResult(cr.or.GetOperations(database.WithIterator(cr.ctx, cr.iterator), r.Filter, cr.fields...))
 		},
 	},
 }
@@ -0,0 +1,66 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTenantNamespaces(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/tenants/acme/namespaces?includeinitializing", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mgr.On("GetNamespaces", mock.Anything, true).
+		Return([]*core.NamespaceWithInitStatus{
+			{Namespace: &core.Namespace{Name: "default"}},
+			{Namespace: &core.Namespace{Name: "acme-orders", Tenant: "acme"}},
+			{Namespace: &core.Namespace{Name: "other-orders", Tenant: "other"}},
+		}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var namespaces []*core.Namespace
+	err := json.NewDecoder(res.Body).Decode(&namespaces)
+	assert.NoError(t, err)
+	assert.Len(t, namespaces, 1)
+	assert.Equal(t, "acme-orders", namespaces[0].Name)
+}
+
+func TestGetTenantNamespacesFail(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/tenants/acme/namespaces", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mgr.On("GetNamespaces", mock.Anything, false).
+		Return(nil, fmt.Errorf("pop"))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+}
@@ -0,0 +1,75 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var getTokenLedgerExport = &ffapi.Route{
+	Name:       "getTokenLedgerExport",
+	Path:       "tokens/export/ledger",
+	Method:     http.MethodGet,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "format", Description: coremsgs.APIParamsTokenExportFormat},
+		{Name: "pool", Description: coremsgs.APIParamsTokenExportPool},
+		{Name: "account", Description: coremsgs.APIParamsTokenExportAccount},
+		{Name: "columns", Description: coremsgs.APIParamsTokenExportColumns},
+		{Name: "startTime", Description: coremsgs.APIParamsTokenExportStartTime},
+		{Name: "endTime", Description: coremsgs.APIParamsTokenExportEndTime},
+	},
+	Description:     coremsgs.APIEndpointsGetTokenLedgerExport,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return []byte{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			export := &core.TokenLedgerExport{
+				Format:  fftypes.FFEnum(r.QP["format"]),
+				Pool:    r.QP["pool"],
+				Account: r.QP["account"],
+			}
+			if r.QP["columns"] != "" {
+				export.Columns = strings.Split(r.QP["columns"], ",")
+			}
+			if r.QP["startTime"] != "" {
+				if export.StartTime, err = fftypes.ParseTimeString(r.QP["startTime"]); err != nil {
+					return nil, i18n.NewError(cr.ctx, coremsgs.MsgTokenExportInvalidTime, "startTime", err)
+				}
+			}
+			if r.QP["endTime"] != "" {
+				if export.EndTime, err = fftypes.ParseTimeString(r.QP["endTime"]); err != nil {
+					return nil, i18n.NewError(cr.ctx, coremsgs.MsgTokenExportInvalidTime, "endTime", err)
+				}
+			}
+			reader, err := cr.or.Assets().ExportTokenLedger(cr.ctx, export)
+			if err == nil {
+				r.ResponseHeaders.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"ledger.%s\"", export.Format))
+			}
+			return reader, err
+		},
+	},
+}
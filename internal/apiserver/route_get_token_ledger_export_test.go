@@ -0,0 +1,83 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/assetmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTokenLedgerExport(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mam := &assetmocks.Manager{}
+	o.On("Assets").Return(mam)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/tokens/export/ledger?format=parquet&pool=pool1&account=0x01&columns=id,balance&startTime=2023-01-01T00:00:00Z&endTime=2023-02-01T00:00:00Z", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mam.On("ExportTokenLedger", mock.Anything, mock.MatchedBy(func(export *core.TokenLedgerExport) bool {
+		return export.Format == core.TokenExportFormatParquet &&
+			export.Pool == "pool1" &&
+			export.Account == "0x01" &&
+			len(export.Columns) == 2 && export.Columns[1] == "balance" &&
+			export.StartTime.String() == "2023-01-01T00:00:00Z" &&
+			export.EndTime.String() == "2023-02-01T00:00:00Z"
+	})).Return(io.NopCloser(bytes.NewReader([]byte("PAR1"))), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	b, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "PAR1", string(b))
+	assert.Equal(t, "attachment; filename=\"ledger.parquet\"", res.Result().Header.Get("Content-Disposition"))
+}
+
+func TestGetTokenLedgerExportBadStartTime(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/tokens/export/ledger?startTime=bad", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	b, _ := io.ReadAll(res.Body)
+	assert.Regexp(t, "FF10499.*startTime", string(b))
+}
+
+func TestGetTokenLedgerExportBadEndTime(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/tokens/export/ledger?endTime=bad", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	b, _ := io.ReadAll(res.Body)
+	assert.Regexp(t, "FF10499.*endTime", string(b))
+}
@@ -0,0 +1,54 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+)
+
+var getTokenMedia = &ffapi.Route{
+	Name:       "getTokenMedia",
+	Path:       "tokens/media",
+	Method:     http.MethodGet,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "uri", Description: coremsgs.APIParamsTokenMediaURI},
+	},
+	Description:     coremsgs.APIEndpointsGetTokenMedia,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return []byte{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		EnabledIf: func(or orchestrator.Orchestrator) bool {
+			return or.Assets().MediaProxyEnabled()
+		},
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			media, reader, err := cr.or.Assets().GetTokenMedia(cr.ctx, r.QP["uri"])
+			if err == nil {
+				r.ResponseHeaders.Set("Content-Type", media.ContentType)
+				r.ResponseHeaders.Set("Content-Length", strconv.FormatInt(media.Size, 10))
+				r.ResponseHeaders.Set("ETag", strconv.Quote(media.Hash.String()))
+			}
+			return reader, err
+		},
+	},
+}
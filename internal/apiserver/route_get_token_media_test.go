@@ -0,0 +1,71 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/assetmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTokenMedia(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mam := &assetmocks.Manager{}
+	mam.On("MediaProxyEnabled").Return(true)
+	o.On("Assets").Return(mam)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/tokens/media?uri=https%3A%2F%2Fexample.com%2Ftoken%2F1", nil)
+	res := httptest.NewRecorder()
+
+	hash := fftypes.NewRandB32()
+	mam.On("GetTokenMedia", mock.Anything, "https://example.com/token/1").
+		Return(&core.TokenMedia{
+			ContentType: "image/png",
+			Size:        5,
+			Hash:        hash,
+		}, io.NopCloser(bytes.NewReader([]byte("hello"))), nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	b, err := io.ReadAll(res.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(b))
+	assert.Equal(t, "image/png", res.Result().Header.Get("Content-Type"))
+	assert.Equal(t, "5", res.Result().Header.Get("Content-Length"))
+	assert.Equal(t, `"`+hash.String()+`"`, res.Result().Header.Get("ETag"))
+}
+
+func TestGetTokenMediaDisabled(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mam := &assetmocks.Manager{}
+	mam.On("MediaProxyEnabled").Return(false)
+	o.On("Assets").Return(mam)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/tokens/media?uri=https%3A%2F%2Fexample.com%2Ftoken%2F1", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+}
@@ -35,7 +35,7 @@ func TestGetTokenPoolByNameOrID(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	res := httptest.NewRecorder()
 
-	mam.On("GetTokenPoolByNameOrID", mock.Anything, "abc").
+	mam.On("GetTokenPoolDetails", mock.Anything, "abc").
 		Return(&core.TokenPool{}, nil)
 	r.ServeHTTP(res, req)
 
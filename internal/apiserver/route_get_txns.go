@@ -37,8 +37,9 @@ var getTxns = &ffapi.Route{
 	JSONOutputValue: func() interface{} { return []*core.Transaction{} },
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
+		Streaming: true,
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return r.FilterResult(cr.or.GetTransactions(cr.ctx, r.Filter))
+			return r.FilterResult(cr.or.GetTransactions(database.WithIterator(cr.ctx, cr.iterator), r.Filter))
 		},
 	},
 }
@@ -0,0 +1,68 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var patchDataUpload = &ffapi.Route{
+	Name:   "patchDataUpload",
+	Path:   "datauploads/{uploadid}",
+	Method: http.MethodPatch,
+	PathParams: []*ffapi.PathParam{
+		{Name: "uploadid", Description: coremsgs.APIParamsUploadID},
+	},
+	QueryParams: nil,
+	FormParams: []*ffapi.FormParam{
+		{Name: "offset", Description: coremsgs.APIParamsUploadOffset},
+		{Name: "hash", Description: coremsgs.APIParamsUploadChunkHash},
+	},
+	Description:     coremsgs.APIEndpointsPatchDataUpload,
+	JSONInputValue:  nil,
+	JSONOutputValue: func() interface{} { return &core.BlobUpload{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		EnabledIf: func(or orchestrator.Orchestrator) bool {
+			return or.Data().BlobsEnabled()
+		},
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			return nil, i18n.NewError(cr.ctx, coremsgs.MsgBlobUploadChunkRequired)
+		},
+		CoreFormUploadHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			offset, err := strconv.ParseInt(r.FP["offset"], 10, 64)
+			if err != nil {
+				return nil, i18n.NewError(cr.ctx, coremsgs.MsgBlobUploadInvalidOffset, r.FP["offset"])
+			}
+			var chunkHash *fftypes.Bytes32
+			if r.FP["hash"] != "" {
+				if chunkHash, err = fftypes.ParseBytes32(cr.ctx, r.FP["hash"]); err != nil {
+					return nil, err
+				}
+			}
+			return cr.or.Data().AppendBlobUpload(cr.ctx, r.PP["uploadid"], offset, chunkHash, r.Part)
+		},
+	},
+}
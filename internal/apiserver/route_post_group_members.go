@@ -0,0 +1,48 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var postGroupMembers = &ffapi.Route{
+	Name:   "postGroupMembers",
+	Path:   "groups/{hash}/members",
+	Method: http.MethodPost,
+	PathParams: []*ffapi.PathParam{
+		{Name: "hash", Description: coremsgs.APIParamsGroupHash},
+	},
+	QueryParams:     nil,
+	Description:     coremsgs.APIEndpointsPostGroupMembers,
+	JSONInputValue:  func() interface{} { return &core.GroupMembersInput{} },
+	JSONOutputValue: func() interface{} { return &core.Group{} },
+	JSONOutputCodes: []int{http.StatusAccepted},
+	Extensions: &coreExtensions{
+		EnabledIf: func(or orchestrator.Orchestrator) bool {
+			return or.PrivateMessaging() != nil
+		},
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			return cr.or.PrivateMessaging().ChangeGroupMembers(cr.ctx, r.PP["hash"], r.Input.(*core.GroupMembersInput))
+		},
+	},
+}
@@ -24,19 +24,25 @@ import (
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/namespace"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
 type coreRequest struct {
-	mgr        namespace.Manager
-	or         orchestrator.Orchestrator
-	ctx        context.Context
-	apiBaseURL string
+	mgr          namespace.Manager
+	or           orchestrator.Orchestrator
+	ctx          context.Context
+	apiBaseURL   string
+	fields       []string
+	iterator     database.RowIterator
+	slowRequests *slowRequests
+	maskExpired  bool
 }
 
 type coreExtensions struct {
 	EnabledIf             func(or orchestrator.Orchestrator) bool
 	CoreJSONHandler       func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error)
 	CoreFormUploadHandler func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error)
+	Streaming             bool // the collection passes the iterator of the request to the database, so can be streamed as NDJSON
 }
 
 const (
@@ -45,20 +51,28 @@ const (
 	routeTagNonDefaultNamespace = "Non-Default Namespace"
 )
 
-var routes = append(
+var routes = withFieldsParam(withQueryParam(append(
 	globalRoutes([]*ffapi.Route{
 		getNamespace,
 		getNamespaces,
+		getTenantNamespaces,
 		getWebSockets,
 	}),
 	namespacedRoutes([]*ffapi.Route{
+		deleteBatchPolicy,
 		deleteContractAPI,
 		deleteContractInterface,
 		deleteContractListener,
 		deleteData,
+		deleteDataUpload,
+		deleteEventRule,
 		deleteSubscription,
+		deleteSubscriptionTemplate,
 		deleteTokenPool,
+		deleteTokenPoolPolicy,
 		getBatchByID,
+		getBatchPolicies,
+		getBatchPolicy,
 		getBatches,
 		getBlockchainEventByID,
 		getBlockchainEvents,
@@ -76,12 +90,17 @@ var routes = append(
 		getDataBlob,
 		getDataSubPaths,
 		getDataValue,
+		getDataValueJSON,
 		getDataByID,
 		getDataMsgs,
+		getDataUploadByID,
+		getDataUploads,
 		getDatatypeByName,
 		getDatatypes,
 		getEventByID,
 		getEvents,
+		getEventRuleByNameOrID,
+		getEventRules,
 		getGroupByHash,
 		getGroups,
 		getIdentities,
@@ -89,10 +108,16 @@ var routes = append(
 		getIdentityByID,
 		getIdentityDID,
 		getIdentityVerifiers,
+		getJobByID,
+		getJobOutput,
+		getJobs,
+		getMsgAcks,
 		getMsgByID,
 		getMsgData,
 		getMsgEvents,
+		getMsgReceipts,
 		getMsgs,
+		getMsgThread,
 		getMsgTxn,
 		getNetworkDIDDocByDID,
 		getNetworkIdentities,
@@ -101,23 +126,53 @@ var routes = append(
 		getNetworkNodes,
 		getNetworkOrg,
 		getNetworkOrgs,
+		getNetworkStatus,
 		getNextPins,
 		getOpByID,
 		getOps,
 		getPins,
+		getScheduledMsgs,
+		getDraftMsgs,
+		getSLABreachByID,
+		getSLABreaches,
 		getStatus,
 		getStatusBatchManager,
+		getStatusBatchQueues,
 		getSubscriptionByID,
 		getSubscriptions,
+		getSubscriptionTemplateByNameOrID,
+		getSubscriptionTemplates,
+		getTokenAccountActivity,
 		getTokenAccountPools,
 		getTokenAccounts,
 		getTokenApprovals,
+		getTokenAssociatedAccounts,
+		getTokenBalanceMismatches,
 		getTokenBalances,
+		getTokenBulkMintByID,
+		getTokenBulkMints,
 		getTokenConnectors,
+		getTokenLedger,
+		getTokenLedgerExport,
+		getTokenMedia,
 		getTokenPoolByNameOrID,
+		getTokenPoolHolders,
+		getTokenPoolIndexes,
+		getTokenPoolMigrations,
+		getTokenPoolPolicy,
 		getTokenPools,
+		getTokenSnapshotBalances,
+		getTokenSnapshotByID,
+		getTokenSnapshotExport,
+		getTokenSnapshots,
+		getTokenSwapByID,
+		getTokenSwaps,
 		getTokenTransferByID,
+		getTokenTransferRequestByID,
+		getTokenTransferRequests,
 		getTokenTransfers,
+		getTokenTransfersExport,
+		getTopicSequences,
 		getTxnBlockchainEvents,
 		getTxnByID,
 		getTxnOps,
@@ -125,7 +180,9 @@ var routes = append(
 		getTxnStatus,
 		getVerifierByID,
 		getVerifiers,
+		patchDataUpload,
 		patchUpdateIdentity,
+		postBatchesFlush,
 		postContractAPIInvoke,
 		postContractAPIPublish,
 		postContractAPIQuery,
@@ -138,32 +195,64 @@ var routes = append(
 		postData,
 		postDataBlobPublish,
 		postDataValuePublish,
+		postDataUpload,
+		postDataUploadComplete,
+		postGraphQL,
+		postGroupMembers,
+		postDisclosureVerify,
+		postJobCancel,
+		postMsgAccept,
+		postMsgDisclosure,
+		postMsgRead,
+		postMsgRecall,
+		postMsgReject,
 		postNetworkAction,
 		postNewContractAPI,
 		postNewContractInterface,
 		postNewContractListener,
 		postNewDatatype,
+		postNewEventRule,
 		postNewIdentity,
+		postNewJob,
 		postNewMessageBroadcast,
 		postNewMessagePrivate,
 		postNewMessageRequestReply,
 		postNewSubscription,
+		postNewSubscriptionTemplate,
+		postSubscriptionAck,
+		postSubscriptionPull,
 		postNewOrganization,
 		postNewOrganizationSelf,
 		postNodesSelf,
 		postOpRetry,
 		postPinsRewind,
+		postScheduledMsgCancel,
+		postDraftMsgCommit,
+		postDraftMsgDiscard,
 		postTokenApproval,
+		postTokenBulkMint,
 		postTokenBurn,
 		postTokenMint,
 		postTokenPool,
+		postTokenPoolMigrate,
+		postTokenPoolPause,
 		postTokenPoolPublish,
+		postTokenPoolReconcile,
+		postTokenPoolResume,
+		postTokenPoolRetire,
+		postTokenPoolSnapshot,
+		postTokenSwap,
 		postTokenTransfer,
+		postTokenTransferBatch,
+		postTokenTransferCheck,
+		postTokenTransferRequestSignoff,
+		putBatchPolicy,
 		putContractAPI,
 		putSubscription,
+		putTokenPoolPolicy,
 		postVerifiersResolve,
 	})...,
-)
+)))
 
 func globalRoutes(routes []*ffapi.Route) []*ffapi.Route {
 	for _, route := range routes {
@@ -35,20 +35,36 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/httpserver"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/auditlog"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/events/eifactory"
+	"github.com/hyperledger/firefly/internal/events/grpcstream"
+	"github.com/hyperledger/firefly/internal/events/sse"
 	"github.com/hyperledger/firefly/internal/events/websockets"
+	"github.com/hyperledger/firefly/internal/grpcserver"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/namespace"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
 )
 
 var (
-	spiConfig     = config.RootSection("spi")
-	apiConfig     = config.RootSection("http")
-	metricsConfig = config.RootSection("metrics")
-	corsConfig    = config.RootSection("cors")
+	spiConfig       = config.RootSection("spi")
+	apiConfig       = config.RootSection("http")
+	metricsConfig   = config.RootSection("metrics")
+	grpcConfig      = config.RootSection("grpc")
+	corsConfig      = config.RootSection("cors")
+	mtlsConfig      = config.RootSection("mtls")
+	tenancyConfig   = config.RootSection("tenancy")
+	hardeningConfig = config.RootSection("hardening")
+
+	mtlsIdentitiesConfig  = mtlsConfig.SubArray(MTLSIdentities)
+	tenantsConfig         = tenancyConfig.SubArray(TenancyTenants)
+	apiListenersConfig    = apiConfig.SubArray(Listeners)
+	spiListenersConfig    = spiConfig.SubArray(Listeners)
+	securityHeadersConfig = hardeningConfig.SubSection(HardeningHeaders)
+	corsRulesConfig       = hardeningConfig.SubSection(HardeningCORS).SubArray(HardeningCORSRules)
 )
 
 // Server is the external interface for the API Server
@@ -61,6 +77,16 @@ type apiServer struct {
 	apiTimeout     time.Duration
 	apiMaxTimeout  time.Duration
 	metricsEnabled bool
+	sseEnabled     bool
+	rbacEnabled    bool
+	maskExpired    bool
+	rateLimiter    *rateLimiter
+	connLimiter    *connLimiter
+	certIdentities *certIdentities
+	tenancy        *tenancy
+	hardening      *hardening
+	slowRequests   *slowRequests
+	auditLog       *auditlog.Logger
 	ffiSwaggerGen  FFISwaggerGen
 }
 
@@ -69,16 +95,59 @@ func InitConfig() {
 	httpserver.InitHTTPConfig(spiConfig, 5001)
 	httpserver.InitHTTPConfig(metricsConfig, 6000)
 	httpserver.InitCORSConfig(corsConfig)
+	initRouteGroupsConfig(apiConfig)
+	initRouteGroupsConfig(spiConfig)
+	initListenersConfig(apiListenersConfig)
+	initListenersConfig(spiListenersConfig)
+	initHardeningConfig(securityHeadersConfig, corsRulesConfig)
 	initMetricsConfig(metricsConfig)
+	initMTLSConfig(mtlsConfig, mtlsIdentitiesConfig)
+	initTenancyConfig(tenancyConfig, tenantsConfig)
+	grpcserver.InitConfig(grpcConfig)
 }
 
 func NewAPIServer() Server {
-	return &apiServer{
+	as := &apiServer{
 		apiTimeout:     config.GetDuration(coreconfig.APIRequestTimeout),
 		apiMaxTimeout:  config.GetDuration(coreconfig.APIRequestMaxTimeout),
 		metricsEnabled: config.GetBool(coreconfig.MetricsEnabled),
+		sseEnabled:     transportEnabled("sse"),
+		rbacEnabled:    config.GetBool(coreconfig.RBACEnabled),
+		maskExpired:    config.GetBool(coreconfig.MessageExpiryMaskConfirmed),
+		slowRequests:   newSlowRequests(),
 		ffiSwaggerGen:  NewFFISwaggerGen(),
 	}
+	if config.GetBool(coreconfig.RateLimitEnabled) {
+		as.rateLimiter = newRateLimiter(as.metricsEnabled)
+	}
+	if config.GetBool(coreconfig.ConnLimitEnabled) {
+		as.connLimiter = newConnLimiter(as.metricsEnabled)
+	}
+	return as
+}
+
+// skipPath bypasses a middleware for one path. The metrics middleware wraps the response writer in
+// a way that hides http.Flusher, which a Server-Sent Events stream cannot work without.
+func skipPath(path string, middleware mux.MiddlewareFunc) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		wrapped := middleware(next)
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			if req.URL.Path == path {
+				next.ServeHTTP(res, req)
+				return
+			}
+			wrapped.ServeHTTP(res, req)
+		})
+	}
+}
+
+func transportEnabled(name string) bool {
+	for _, transport := range config.GetStringSlice(coreconfig.EventTransportsEnabled) {
+		if transport == name {
+			return true
+		}
+	}
+	return false
 }
 
 // Serve is the main entry point for the API Server
@@ -86,23 +155,54 @@ func (as *apiServer) Serve(ctx context.Context, mgr namespace.Manager) (err erro
 	httpErrChan := make(chan error)
 	spiErrChan := make(chan error)
 	metricsErrChan := make(chan error)
+	grpcErrChan := make(chan error)
 
-	apiHTTPServer, err := httpserver.NewHTTPServer(ctx, "api", as.createMuxRouter(ctx, mgr), httpErrChan, apiConfig, corsConfig, &httpserver.ServerOptions{
-		MaximumRequestTimeout: as.apiMaxTimeout,
-	})
+	if as.certIdentities, err = loadCertIdentities(ctx, mtlsConfig, mtlsIdentitiesConfig); err != nil {
+		return err
+	}
+	if as.tenancy, err = loadTenancy(ctx, tenancyConfig, tenantsConfig); err != nil {
+		return err
+	}
+	if as.hardening, err = loadHardening(ctx, securityHeadersConfig, corsRulesConfig); err != nil {
+		return err
+	}
+	if config.GetBool(coreconfig.AuditEnabled) {
+		if as.auditLog, err = auditlog.NewLogger(ctx, mgr); err != nil {
+			return err
+		}
+		defer as.auditLog.Close()
+	}
+
+	apiRouter := as.createMuxRouter(ctx, mgr)
+	apiMainServer, err := newMainListenerServer(ctx, "api", apiRouter, httpErrChan, apiConfig, corsConfig, as.hardening, as.apiMaxTimeout)
 	if err != nil {
 		return err
 	}
-	go apiHTTPServer.ServeHTTP(ctx)
+	apiListeners, err := newListenerServers(ctx, "api", apiRouter, httpErrChan, apiConfig, corsConfig, as.hardening, apiListenersConfig, as.apiMaxTimeout)
+	if err != nil {
+		_ = apiMainServer.listener.Close()
+		return err
+	}
+	go apiMainServer.serve(ctx)
+	for _, ls := range apiListeners {
+		go ls.serve(ctx)
+	}
 
 	if config.GetBool(coreconfig.SPIEnabled) {
-		spiHTTPServer, err := httpserver.NewHTTPServer(ctx, "spi", as.createAdminMuxRouter(mgr), spiErrChan, spiConfig, corsConfig, &httpserver.ServerOptions{
-			MaximumRequestTimeout: as.apiMaxTimeout,
-		})
+		spiRouter := as.createAdminMuxRouter(mgr)
+		spiMainServer, err := newMainListenerServer(ctx, "spi", spiRouter, spiErrChan, spiConfig, corsConfig, as.hardening, as.apiMaxTimeout)
 		if err != nil {
 			return err
 		}
-		go spiHTTPServer.ServeHTTP(ctx)
+		spiListeners, err := newListenerServers(ctx, "spi", spiRouter, spiErrChan, spiConfig, corsConfig, as.hardening, spiListenersConfig, as.apiMaxTimeout)
+		if err != nil {
+			_ = spiMainServer.listener.Close()
+			return err
+		}
+		go spiMainServer.serve(ctx)
+		for _, ls := range spiListeners {
+			go ls.serve(ctx)
+		}
 	} else if config.GetBool(coreconfig.LegacyAdminEnabled) {
 		log.L(ctx).Warnf("Your config includes an 'admin' section, which should be renamed to 'spi' - SPI server will not be enabled until this is corrected")
 	}
@@ -117,10 +217,22 @@ func (as *apiServer) Serve(ctx context.Context, mgr namespace.Manager) (err erro
 		go metricsHTTPServer.ServeHTTP(ctx)
 	}
 
-	return as.waitForServerStop(httpErrChan, spiErrChan, metricsErrChan)
+	if grpcConfig.GetBool(grpcserver.GRPCConfigEnabled) {
+		var tenantAuth core.Authorizer
+		if as.tenancy != nil {
+			tenantAuth = as.tenancy.authorizer(mgr, nil)
+		}
+		grpcServer, err := grpcserver.NewServer(ctx, grpcConfig, mgr, as.auditLog, tenantAuth)
+		if err != nil {
+			return err
+		}
+		go grpcServer.Serve(ctx, grpcErrChan)
+	}
+
+	return as.waitForServerStop(httpErrChan, spiErrChan, metricsErrChan, grpcErrChan)
 }
 
-func (as *apiServer) waitForServerStop(httpErrChan, spiErrChan, metricsErrChan chan error) error {
+func (as *apiServer) waitForServerStop(httpErrChan, spiErrChan, metricsErrChan, grpcErrChan chan error) error {
 	select {
 	case err := <-httpErrChan:
 		return err
@@ -128,6 +240,8 @@ func (as *apiServer) waitForServerStop(httpErrChan, spiErrChan, metricsErrChan c
 		return err
 	case err := <-metricsErrChan:
 		return err
+	case err := <-grpcErrChan:
+		return err
 	}
 }
 
@@ -227,11 +341,15 @@ func getOrchestrator(ctx context.Context, mgr namespace.Manager, tag string, r *
 	return nil, i18n.NewError(ctx, coremsgs.MsgMissingNamespace)
 }
 
-func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manager, apiBaseURL string, route *ffapi.Route) http.HandlerFunc {
+func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manager, apiBaseURL string, route *ffapi.Route, enforceRoles bool) http.HandlerFunc {
 	// We extend the base ffapi functionality, with standardized DB filter support for all core resources.
 	// We also pass the Orchestrator context through
 	ce := route.Extensions.(*coreExtensions)
+	enforceRoles = enforceRoles && route.Tag != routeTagGlobal
+	withETag := isResourceRoute(route)
+	streamable := isStreamableRoute(route)
 	route.JSONHandler = func(r *ffapi.APIRequest) (output interface{}, err error) {
+		defer func() { recordAuditDetails(r, output, err) }()
 		or, err := getOrchestrator(r.Req.Context(), mgr, route.Tag, r)
 		if err != nil {
 			return nil, err
@@ -248,25 +366,68 @@ func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manage
 				return nil, err
 			}
 		}
+		if enforceRoles {
+			if err := authorizeRole(r, or, route); err != nil {
+				return nil, err
+			}
+		}
 
 		if ce.EnabledIf != nil && !ce.EnabledIf(or) {
 			return nil, i18n.NewError(r.Req.Context(), coremsgs.MsgActionNotSupported)
 		}
 
+		if err := applyIdempotencyKeyHeader(r.Req.Context(), r.Req.Header.Get(idempotencyKeyHeader), r.Input); err != nil {
+			return nil, err
+		}
+		if err := applyCertIdentity(r.Req.Context(), r.Input); err != nil {
+			return nil, err
+		}
+		if err := applyQuery(r.Req.Context(), r.Filter, r.QP[queryParam]); err != nil {
+			return nil, err
+		}
+		fields, err := parseFields(r.Req.Context(), route, r.QP[fieldsParam])
+		if err != nil {
+			return nil, err
+		}
+
 		cr := &coreRequest{
-			mgr:        mgr,
-			or:         or,
-			ctx:        r.Req.Context(),
-			apiBaseURL: apiBaseURL,
+			mgr:          mgr,
+			or:           or,
+			ctx:          r.Req.Context(),
+			apiBaseURL:   apiBaseURL,
+			fields:       fields,
+			slowRequests: as.slowRequests,
+			maskExpired:  as.maskExpired,
+		}
+		if streamable && acceptsNDJSON(r.Req) {
+			stream, err := streamNDJSON(r, cr, ce.CoreJSONHandler, fields)
+			if err != nil {
+				return nil, err
+			}
+			return stream, nil
+		}
+		output, err = ce.CoreJSONHandler(r, cr)
+		if err != nil {
+			return nil, err
+		}
+		output, err = projectOutput(output, fields)
+		if err == nil && withETag {
+			output = applyETag(r, output)
 		}
-		return ce.CoreJSONHandler(r, cr)
+		return output, err
 	}
 	if ce.CoreFormUploadHandler != nil {
 		route.FormUploadHandler = func(r *ffapi.APIRequest) (output interface{}, err error) {
+			defer func() { recordAuditDetails(r, output, err) }()
 			or, err := getOrchestrator(r.Req.Context(), mgr, route.Tag, r)
 			if err != nil {
 				return nil, err
 			}
+			if enforceRoles {
+				if err := authorizeRole(r, or, route); err != nil {
+					return nil, err
+				}
+			}
 			if ce.EnabledIf != nil && !ce.EnabledIf(or) {
 				return nil, i18n.NewError(r.Req.Context(), coremsgs.MsgActionNotSupported)
 			}
@@ -280,6 +441,14 @@ func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manage
 			return ce.CoreFormUploadHandler(r, cr)
 		}
 	}
+	if as.auditLog != nil && isAuditedRoute(route) {
+		return as.auditHandler(route, hf.RouteHandler(route))
+	}
+	if streamable {
+		streamHF := *hf
+		streamHF.MaxFilterLimit = uint64(config.GetUint(coreconfig.APIMaxStreamLimit))
+		return ndjsonHandler(hf.RouteHandler(route), streamHF.RouteHandler(route))
+	}
 	return hf.RouteHandler(route)
 }
 
@@ -299,7 +468,22 @@ func (as *apiServer) createMuxRouter(ctx context.Context, mgr namespace.Manager)
 	hf := as.handlerFactory()
 
 	if as.metricsEnabled {
-		r.Use(metrics.GetRestServerInstrumentation().Middleware)
+		r.Use(skipPath(`/sse`, metrics.GetRestServerInstrumentation().Middleware))
+	}
+	if as.metricsEnabled || as.slowRequests != nil {
+		r.Use(as.latencyMiddleware("rest"))
+	}
+	if as.certIdentities != nil {
+		r.Use(as.certIdentities.middleware)
+	}
+	if as.tenancy != nil {
+		r.Use(as.tenancy.middleware(mgr))
+	}
+	if as.connLimiter != nil {
+		r.Use(as.connLimiter.middleware)
+	}
+	if as.rateLimiter != nil {
+		r.Use(as.rateLimiter.middleware(mgr))
 	}
 
 	publicURL := as.getPublicURL(apiConfig, "")
@@ -307,8 +491,12 @@ func (as *apiServer) createMuxRouter(ctx context.Context, mgr namespace.Manager)
 	for _, route := range routes {
 		if ce, ok := route.Extensions.(*coreExtensions); ok {
 			if ce.CoreJSONHandler != nil {
-				r.HandleFunc(fmt.Sprintf("/api/v1/%s", route.Path), as.routeHandler(hf, mgr, apiBaseURL, route)).
-					Methods(route.Method)
+				handler := as.routeHandler(hf, mgr, apiBaseURL, route, as.rbacEnabled)
+				r.HandleFunc(fmt.Sprintf("/api/v1/%s", route.Path), handler).Methods(route.Method)
+				if as.tenancy != nil && route.Tag == routeTagNonDefaultNamespace {
+					// The namespaced routes are also served under the tenant of the namespace
+					r.HandleFunc(fmt.Sprintf("/api/v1/tenants/{tenant}/%s", route.Path), handler).Methods(route.Method)
+				}
 			}
 		}
 	}
@@ -324,9 +512,21 @@ func (as *apiServer) createMuxRouter(ctx context.Context, mgr namespace.Manager)
 	r.HandleFunc(`/api`, hf.APIWrapper(hf.SwaggerUIHandler(publicURL+"/api/swagger.yaml")))
 	r.HandleFunc(`/favicon{any:.*}.png`, favIcons)
 
+	var streamAuth core.Authorizer = mgr
+	if as.tenancy != nil {
+		streamAuth = as.tenancy.authorizer(mgr, mgr)
+	}
 	ws, _ := eifactory.GetPlugin(ctx, "websockets")
-	ws.(*websockets.WebSockets).SetAuthorizer(mgr)
+	ws.(*websockets.WebSockets).SetAuthorizer(streamAuth)
 	r.HandleFunc(`/ws`, ws.(*websockets.WebSockets).ServeHTTP)
+	grpcEvents, _ := eifactory.GetPlugin(ctx, "grpc")
+	grpcEvents.(*grpcstream.GRPC).SetAuthorizer(streamAuth)
+	if as.sseEnabled {
+		// The SSE plugin is only initialized when it is enabled
+		sseEvents, _ := eifactory.GetPlugin(ctx, "sse")
+		sseEvents.(*sse.SSE).SetAuthorizer(streamAuth)
+		r.HandleFunc(`/sse`, sseEvents.(*sse.SSE).ServeHTTP)
+	}
 
 	uiPath := config.GetString(coreconfig.UIPath)
 	if uiPath != "" && config.GetBool(coreconfig.UIEnabled) {
@@ -354,6 +554,12 @@ func (as *apiServer) createAdminMuxRouter(mgr namespace.Manager) *mux.Router {
 	if as.metricsEnabled {
 		r.Use(metrics.GetAdminServerInstrumentation().Middleware)
 	}
+	if as.metricsEnabled || as.slowRequests != nil {
+		r.Use(as.latencyMiddleware("admin"))
+	}
+	if as.connLimiter != nil {
+		r.Use(as.connLimiter.middleware)
+	}
 	hf := as.handlerFactory()
 
 	publicURL := as.getPublicURL(spiConfig, "spi")
@@ -361,7 +567,8 @@ func (as *apiServer) createAdminMuxRouter(mgr namespace.Manager) *mux.Router {
 	for _, route := range spiRoutes {
 		if ce, ok := route.Extensions.(*coreExtensions); ok {
 			if ce.CoreJSONHandler != nil {
-				r.HandleFunc(fmt.Sprintf("/spi/v1/%s", route.Path), as.routeHandler(hf, mgr, apiBaseURL, route)).
+				// The admin API is not subject to role-based access control, as it is used to manage the role bindings
+				r.HandleFunc(fmt.Sprintf("/spi/v1/%s", route.Path), as.routeHandler(hf, mgr, apiBaseURL, route, false)).
 					Methods(route.Method)
 			}
 		}
@@ -379,7 +586,10 @@ func (as *apiServer) createMetricsMuxRouter() *mux.Router {
 	r := mux.NewRouter()
 
 	r.Path(config.GetString(coreconfig.MetricsPath)).Handler(promhttp.InstrumentMetricHandler(metrics.Registry(),
-		promhttp.HandlerFor(metrics.Registry(), promhttp.HandlerOpts{})))
+		promhttp.HandlerFor(metrics.Registry(), promhttp.HandlerOpts{
+			// OpenMetrics is required for the exemplars of the route latency histogram
+			EnableOpenMetrics: true,
+		})))
 
 	return r
 }
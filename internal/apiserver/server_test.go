@@ -36,6 +36,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/grpcserver"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/mocks/apiservermocks"
 	"github.com/hyperledger/firefly/mocks/contractmocks"
@@ -87,6 +88,8 @@ func TestStartStopServer(t *testing.T) {
 	apiConfig.Set(httpserver.HTTPConfPort, 0)
 	spiConfig.Set(httpserver.HTTPConfPort, 0)
 	metricsConfig.Set(httpserver.HTTPConfPort, 0)
+	grpcConfig.Set(grpcserver.GRPCConfigPort, 0)
+	grpcConfig.Set(grpcserver.GRPCConfigEnabled, true)
 	config.Set(coreconfig.UIPath, "test")
 	config.Set(coreconfig.SPIEnabled, true)
 	ctx, cancel := context.WithCancel(context.Background())
@@ -166,6 +169,23 @@ func TestStartAdminWSHandler(t *testing.T) {
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
 
+func TestSSEHandlerEnabled(t *testing.T) {
+	coreconfig.Reset()
+	metrics.Clear()
+	InitConfig()
+	config.Set(coreconfig.EventTransportsEnabled, []string{"websockets", "sse"})
+	as := NewAPIServer().(*apiServer)
+	assert.True(t, as.sseEnabled)
+	mgr := &namespacemocks.Manager{}
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	// A request without a namespace is rejected by the SSE plugin
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest("GET", "/sse", nil))
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10178", res.Body.String())
+}
+
 func TestStartMetricsFail(t *testing.T) {
 	coreconfig.Reset()
 	metrics.Clear()
@@ -182,6 +202,23 @@ func TestStartMetricsFail(t *testing.T) {
 	assert.Regexp(t, "FF00151", err)
 }
 
+func TestStartGRPCFail(t *testing.T) {
+	coreconfig.Reset()
+	metrics.Clear()
+	InitConfig()
+	apiConfig.Set(httpserver.HTTPConfPort, 0)
+	grpcConfig.Set(grpcserver.GRPCConfigAddress, "...://")
+	grpcConfig.Set(grpcserver.GRPCConfigEnabled, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel() // server will immediately shut down
+	as := NewAPIServer()
+	mgr := &namespacemocks.Manager{}
+	mae := &spieventsmocks.Manager{}
+	mgr.On("SPIEvents").Return(mae)
+	err := as.Serve(ctx, mgr)
+	assert.Regexp(t, "FF10586", err)
+}
+
 func TestNotFound(t *testing.T) {
 	_, _, as := newTestServer()
 	handler := as.handlerFactory().APIWrapper(as.notFoundHandler)
@@ -199,7 +236,7 @@ func TestNotFound(t *testing.T) {
 func TestFilterTooMany(t *testing.T) {
 	mgr, o, as := newTestServer()
 	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
-	handler := as.routeHandler(as.handlerFactory(), mgr, "", getBatches)
+	handler := as.routeHandler(as.handlerFactory(), mgr, "", getBatches, false)
 
 	req := httptest.NewRequest("GET", "http://localhost:12345/test?limit=99999999999", nil)
 	res := httptest.NewRecorder()
@@ -213,7 +250,7 @@ func TestFilterTooMany(t *testing.T) {
 func TestUnauthorized(t *testing.T) {
 	mgr, o, as := newTestServer()
 	o.On("Authorize", mock.Anything, mock.Anything).Return(i18n.NewError(context.Background(), i18n.MsgUnauthorized))
-	handler := as.routeHandler(as.handlerFactory(), mgr, "", getBatches)
+	handler := as.routeHandler(as.handlerFactory(), mgr, "", getBatches, false)
 
 	req := httptest.NewRequest("GET", "http://localhost:12345/test", nil)
 	res := httptest.NewRecorder()
@@ -272,19 +309,24 @@ func TestWaitForServerStop(t *testing.T) {
 	chl1 := make(chan error, 1)
 	chl2 := make(chan error, 1)
 	chl3 := make(chan error, 1)
+	chl4 := make(chan error, 1)
 	chl1 <- fmt.Errorf("pop1")
 
 	as := &apiServer{}
-	err := as.waitForServerStop(chl1, chl2, chl3)
+	err := as.waitForServerStop(chl1, chl2, chl3, chl4)
 	assert.EqualError(t, err, "pop1")
 
 	chl2 <- fmt.Errorf("pop2")
-	err = as.waitForServerStop(chl1, chl2, chl3)
+	err = as.waitForServerStop(chl1, chl2, chl3, chl4)
 	assert.EqualError(t, err, "pop2")
 
 	chl3 <- fmt.Errorf("pop3")
-	err = as.waitForServerStop(chl1, chl2, chl3)
+	err = as.waitForServerStop(chl1, chl2, chl3, chl4)
 	assert.EqualError(t, err, "pop3")
+
+	chl4 <- fmt.Errorf("pop4")
+	err = as.waitForServerStop(chl1, chl2, chl3, chl4)
+	assert.EqualError(t, err, "pop4")
 }
 
 func TestContractAPISwaggerJSON(t *testing.T) {
@@ -24,10 +24,29 @@ var spiRoutes = append(globalRoutes([]*ffapi.Route{
 	spiGetNamespaceByName,
 	spiGetNamespaces,
 	spiGetOpByID,
+	spiGetSlowRequests,
 	spiPatchOpByID,
 	spiPostReset,
 }),
 	namespacedRoutes([]*ffapi.Route{
+		spiDeleteRoleBinding,
+		spiGetAuditLog,
+		spiGetDeadLetterByID,
+		spiGetDeadLetters,
 		spiGetOps,
+		spiGetQuarantine,
+		spiGetQuarantineByID,
+		spiGetQuarantinedBlobByID,
+		spiGetQuarantinedBlobs,
+		spiGetRoleBindingByID,
+		spiGetRoleBindings,
+		spiPostDeadLetterDiscard,
+		spiPostDeadLetterRequeue,
+		spiPostQuarantineDiscard,
+		spiPostQuarantineRelease,
+		spiPostQuarantinedBlobDiscard,
+		spiPostQuarantinedBlobRelease,
+		spiPostRoleBinding,
+		spiPostSubscriptionRewind,
 	})...,
 )
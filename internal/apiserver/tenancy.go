@@ -0,0 +1,183 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	TenancyEnabled   = "enabled"
+	TenancyTenants   = "tenants"
+	TenantName       = "name"
+	TenantPrincipals = "principals"
+)
+
+func initTenancyConfig(config config.Section, tenants config.ArraySection) {
+	config.AddKnownKey(TenancyEnabled, false)
+	tenants.AddKnownKey(TenantName)
+	tenants.AddKnownKey(TenantPrincipals)
+}
+
+type tenantsContextKey struct{}
+
+// tenancy groups namespaces under tenants, and restricts the namespaces of each tenant to the principals bound to it
+type tenancy struct {
+	tenants     map[string]bool
+	byPrincipal map[string]map[string]bool
+}
+
+func loadTenancy(ctx context.Context, conf config.Section, tenants config.ArraySection) (*tenancy, error) {
+	if !conf.GetBool(TenancyEnabled) {
+		return nil, nil
+	}
+	tn := &tenancy{
+		tenants:     make(map[string]bool),
+		byPrincipal: make(map[string]map[string]bool),
+	}
+	for i := 0; i < tenants.ArraySize(); i++ {
+		entry := tenants.ArrayEntry(i)
+		name := entry.GetString(TenantName)
+		if err := fftypes.ValidateFFNameField(ctx, name, fmt.Sprintf("tenancy.tenants[%d].name", i)); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidTenant, i, err)
+		}
+		if tn.tenants[name] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidTenant, i, "duplicate name")
+		}
+		tn.tenants[name] = true
+		for _, principal := range entry.GetStringSlice(TenantPrincipals) {
+			if tn.byPrincipal[principal] == nil {
+				tn.byPrincipal[principal] = make(map[string]bool)
+			}
+			tn.byPrincipal[principal][name] = true
+		}
+	}
+	return tn, nil
+}
+
+// check returns the status and error to reject a request with, if the principal is not bound to the tenant
+// in the path of the request, or to the tenant of the namespace in the path
+func (tn *tenancy) check(ctx context.Context, mgr namespace.Manager, principal string, bound map[string]bool, tenant, ns string) (int, error) {
+	forbidden := func(tenant string) (int, error) {
+		if principal == "" {
+			return http.StatusUnauthorized, i18n.NewError(ctx, coremsgs.MsgRBACNoPrincipal)
+		}
+		return http.StatusForbidden, i18n.NewError(ctx, coremsgs.MsgTenantForbidden, principal, tenant)
+	}
+
+	if tenant != "" {
+		if !tn.tenants[tenant] {
+			return http.StatusNotFound, i18n.NewError(ctx, coremsgs.MsgTenantNotFound, tenant)
+		}
+		if !bound[tenant] {
+			return forbidden(tenant)
+		}
+	}
+	if ns == "" {
+		return 0, nil
+	}
+
+	nsTenant := ""
+	if or, err := mgr.Orchestrator(ctx, ns, true); err == nil {
+		nsTenant = or.GetNamespace(ctx).Tenant
+	}
+	if tenant != "" && nsTenant != tenant {
+		// Namespaces of other tenants are reported as not found, so their names are not revealed
+		return http.StatusNotFound, i18n.NewError(ctx, coremsgs.MsgTenantNamespaceNotFound, ns, tenant)
+	}
+	if nsTenant != "" && !bound[nsTenant] {
+		return forbidden(nsTenant)
+	}
+	return 0, nil
+}
+
+// middleware rejects requests to the namespaces of tenants the principal is not bound to, and records the tenants
+// the principal is bound to on the context of the request
+func (tn *tenancy) middleware(mgr namespace.Manager) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			ctx := req.Context()
+			principal := orchestrator.Principal(ctx, req.Header)
+			bound := tn.byPrincipal[principal]
+			if bound == nil {
+				bound = map[string]bool{}
+			}
+			vars := mux.Vars(req)
+			if status, err := tn.check(ctx, mgr, principal, bound, vars["tenant"], vars["ns"]); err != nil {
+				log.L(ctx).Warnf("Rejecting %s %s: %s", req.Method, req.URL.Path, err)
+				res.Header().Set("Content-Type", "application/json")
+				res.WriteHeader(status)
+				_ = json.NewEncoder(res).Encode(&fftypes.RESTError{Error: err.Error()})
+				return
+			}
+			next.ServeHTTP(res, req.WithContext(context.WithValue(ctx, tenantsContextKey{}, bound)))
+		})
+	}
+}
+
+// tenantAuthorizer applies the tenant check to the requests of the gRPC API and the event streams, which
+// are not served through the REST mux, before passing them to the next authorizer (if any)
+type tenantAuthorizer struct {
+	tn   *tenancy
+	mgr  namespace.Manager
+	next core.Authorizer
+}
+
+func (tn *tenancy) authorizer(mgr namespace.Manager, next core.Authorizer) core.Authorizer {
+	return &tenantAuthorizer{tn: tn, mgr: mgr, next: next}
+}
+
+func (ta *tenantAuthorizer) Authorize(ctx context.Context, authReq *fftypes.AuthReq) error {
+	principal := orchestrator.Principal(ctx, authReq.Header)
+	if _, err := ta.tn.check(ctx, ta.mgr, principal, ta.tn.byPrincipal[principal], "", authReq.Namespace); err != nil {
+		log.L(ctx).Warnf("Rejecting access to namespace '%s': %s", authReq.Namespace, err)
+		return err
+	}
+	if ta.next != nil {
+		return ta.next.Authorize(ctx, authReq)
+	}
+	return nil
+}
+
+// visibleNamespaces removes the namespaces of tenants the principal of the request is not bound to, when
+// tenancy is enabled
+func visibleNamespaces(ctx context.Context, namespaces []*core.NamespaceWithInitStatus) []*core.NamespaceWithInitStatus {
+	bound, ok := ctx.Value(tenantsContextKey{}).(map[string]bool)
+	if !ok {
+		return namespaces
+	}
+	visible := make([]*core.NamespaceWithInitStatus, 0, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Tenant == "" || bound[ns.Tenant] {
+			visible = append(visible, ns)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,190 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestTenancyServer(t *testing.T) (*namespacemocks.Manager, *orchestratormocks.Orchestrator, *mux.Router) {
+	mgr, o, as := newTestServer()
+	tenancyConfig.Set(TenancyEnabled, true)
+	tenantsConfig.ArrayEntry(0).Set(TenantName, "acme")
+	tenantsConfig.ArrayEntry(0).Set(TenantPrincipals, []string{"alice", "bob"})
+	tenantsConfig.ArrayEntry(1).Set(TenantName, "other")
+	tenantsConfig.ArrayEntry(1).Set(TenantPrincipals, []string{"bob"})
+	var err error
+	as.tenancy, err = loadTenancy(context.Background(), tenancyConfig, tenantsConfig)
+	assert.NoError(t, err)
+
+	for ns, tenant := range map[string]string{"ns1": "acme", "mynamespace": "", "ns2": "other"} {
+		nsOr := &orchestratormocks.Orchestrator{}
+		nsOr.On("GetNamespace", mock.Anything).Return(&core.Namespace{Name: ns, Tenant: tenant}).Maybe()
+		mgr.On("Orchestrator", mock.Anything, ns, true).Return(nsOr, nil).Maybe()
+	}
+	mgr.On("Orchestrator", mock.Anything, "ns2", false).Return(o, nil).Maybe()
+	mgr.On("Orchestrator", mock.Anything, "unknown", true).Return(nil, fmt.Errorf("pop")).Maybe()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil).Maybe()
+	o.On("GetMessages", mock.Anything, mock.Anything).Return([]*core.Message{}, nil, nil).Maybe()
+	return mgr, o, as.createMuxRouter(context.Background(), mgr)
+}
+
+func TestLoadTenancy(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	tn, err := loadTenancy(context.Background(), tenancyConfig, tenantsConfig)
+	assert.NoError(t, err)
+	assert.Nil(t, tn)
+
+	tenancyConfig.Set(TenancyEnabled, true)
+	tenantsConfig.ArrayEntry(0).Set(TenantName, "acme")
+	tenantsConfig.ArrayEntry(0).Set(TenantPrincipals, []string{"alice"})
+	tn, err = loadTenancy(context.Background(), tenancyConfig, tenantsConfig)
+	assert.NoError(t, err)
+	assert.True(t, tn.tenants["acme"])
+	assert.True(t, tn.byPrincipal["alice"]["acme"])
+}
+
+func TestLoadTenancyBadName(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	tenancyConfig.Set(TenancyEnabled, true)
+	tenantsConfig.ArrayEntry(0).Set(TenantName, "!acme")
+	_, err := loadTenancy(context.Background(), tenancyConfig, tenantsConfig)
+	assert.Regexp(t, "FF10610.*0", err)
+}
+
+func TestLoadTenancyDuplicate(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	tenancyConfig.Set(TenancyEnabled, true)
+	tenantsConfig.ArrayEntry(0).Set(TenantName, "acme")
+	tenantsConfig.ArrayEntry(1).Set(TenantName, "acme")
+	_, err := loadTenancy(context.Background(), tenancyConfig, tenantsConfig)
+	assert.Regexp(t, "FF10610.*1.*duplicate", err)
+}
+
+func TestTenantRoutes(t *testing.T) {
+	_, _, r := newTestTenancyServer(t)
+
+	for _, tc := range []struct {
+		path      string
+		principal string
+		status    int
+		err       string
+	}{
+		{"/api/v1/tenants/acme/namespaces/ns1/messages", "alice", 200, ""},
+		{"/api/v1/namespaces/ns1/messages", "alice", 200, ""},
+		{"/api/v1/namespaces/mynamespace/messages", "carol", 200, ""},
+		{"/api/v1/tenants/other/namespaces/ns2/messages", "bob", 200, ""},
+		{"/api/v1/tenants/acme/namespaces/ns1/messages", "carol", 403, "FF10614.*carol.*acme"},
+		{"/api/v1/tenants/acme/namespaces/ns1/messages", "", 401, "FF10590"},
+		{"/api/v1/namespaces/ns1/messages", "carol", 403, "FF10614.*carol.*acme"},
+		{"/api/v1/namespaces/ns2/messages", "alice", 403, "FF10614.*alice.*other"},
+		{"/api/v1/tenants/acme/namespaces/ns2/messages", "alice", 404, "FF10613.*ns2.*acme"},
+		{"/api/v1/tenants/acme/namespaces/mynamespace/messages", "alice", 404, "FF10613"},
+		{"/api/v1/tenants/acme/namespaces/unknown/messages", "alice", 404, "FF10613"},
+		{"/api/v1/tenants/unknown/namespaces/ns1/messages", "alice", 404, "FF10612"},
+	} {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		if tc.principal != "" {
+			req.SetBasicAuth(tc.principal, "secret")
+		}
+		res := httptest.NewRecorder()
+		r.ServeHTTP(res, req)
+		assert.Equal(t, tc.status, res.Result().StatusCode, tc.path)
+		if tc.err != "" {
+			assert.Regexp(t, tc.err, res.Body.String(), tc.path)
+		}
+	}
+}
+
+func TestTenantVisibleNamespaces(t *testing.T) {
+	mgr, _, r := newTestTenancyServer(t)
+	mgr.On("GetNamespaces", mock.Anything, false).Return([]*core.NamespaceWithInitStatus{
+		{Namespace: &core.Namespace{Name: "default"}},
+		{Namespace: &core.Namespace{Name: "ns1", Tenant: "acme"}},
+		{Namespace: &core.Namespace{Name: "ns2", Tenant: "other"}},
+	}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/namespaces", nil)
+	req.SetBasicAuth("alice", "secret")
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.JSONEq(t, `[{"name":"default","networkName":"","description":"","created":null},{"name":"ns1","networkName":"","description":"","tenant":"acme","created":null}]`, res.Body.String())
+}
+
+func TestTenantRoutesDisabled(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+
+	req := httptest.NewRequest("GET", "/api/v1/tenants/acme/namespaces/ns1/messages", nil)
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, req)
+	assert.Equal(t, 404, res.Result().StatusCode)
+}
+
+func TestVisibleNamespacesNoTenancy(t *testing.T) {
+	config.Set(coreconfig.NamespacesDefault, "default")
+	namespaces := []*core.NamespaceWithInitStatus{
+		{Namespace: &core.Namespace{Name: "ns1", Tenant: "acme"}},
+	}
+	assert.Equal(t, namespaces, visibleNamespaces(context.Background(), namespaces))
+}
+
+func TestTenantAuthorizer(t *testing.T) {
+	mgr, _, _ := newTestTenancyServer(t)
+	as := &apiServer{}
+	var err error
+	as.tenancy, err = loadTenancy(context.Background(), tenancyConfig, tenantsConfig)
+	assert.NoError(t, err)
+
+	header := func(principal string) http.Header {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.SetBasicAuth(principal, "secret")
+		return req.Header
+	}
+	auth := as.tenancy.authorizer(mgr, nil)
+	assert.NoError(t, auth.Authorize(context.Background(), &fftypes.AuthReq{Namespace: "ns1", Header: header("alice")}))
+	assert.NoError(t, auth.Authorize(context.Background(), &fftypes.AuthReq{Namespace: "mynamespace", Header: header("carol")}))
+	err = auth.Authorize(context.Background(), &fftypes.AuthReq{Namespace: "ns2", Header: header("alice")})
+	assert.Regexp(t, "FF10614.*alice.*other", err)
+	err = auth.Authorize(context.Background(), &fftypes.AuthReq{Namespace: "ns1", Header: http.Header{}})
+	assert.Regexp(t, "FF10590", err)
+
+	next := &namespacemocks.Manager{}
+	next.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	auth = as.tenancy.authorizer(mgr, next)
+	err = auth.Authorize(context.Background(), &fftypes.AuthReq{Namespace: "ns1", Header: header("alice")})
+	assert.Regexp(t, "pop", err)
+	next.AssertExpectations(t)
+}
@@ -18,7 +18,9 @@ package assets
 
 import (
 	"context"
+	"io"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -35,39 +37,86 @@ import (
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/sharedstorage"
 	"github.com/hyperledger/firefly/pkg/tokens"
 )
 
 type Manager interface {
 	core.Named
 
+	Start() error
+	WaitStop()
+
 	CreateTokenPool(ctx context.Context, pool *core.TokenPoolInput, waitConfirm bool) (*core.TokenPool, error)
 	ActivateTokenPool(ctx context.Context, pool *core.TokenPool) error
 	GetTokenPools(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenPool, *ffapi.FilterResult, error)
 	GetTokenPoolByLocator(ctx context.Context, connector, poolLocator string) (*core.TokenPool, error)
 	GetTokenPoolByNameOrID(ctx context.Context, poolNameOrID string) (*core.TokenPool, error)
+	GetTokenPoolDetails(ctx context.Context, poolNameOrID string) (*core.TokenPool, error)
 	GetTokenPoolByID(ctx context.Context, id *fftypes.UUID) (*core.TokenPool, error)
 	ResolvePoolMethods(ctx context.Context, pool *core.TokenPool) error
 	DeleteTokenPool(ctx context.Context, poolNameOrID string) error
+	PauseTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error)
+	ResumeTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error)
+	RetireTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error)
+	MigrateTokenPool(ctx context.Context, poolNameOrID string, input *core.TokenPoolMigrationInput) (*core.TokenPoolMigration, error)
+	GetTokenPoolMigrations(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenPoolMigration, *ffapi.FilterResult, error)
+	CompleteTokenPoolMigration(ctx context.Context, resolved *tokens.TokenPool) (*core.TokenPool, error)
+	SetTokenTransferPolicy(ctx context.Context, poolNameOrID string, policy *core.TokenTransferPolicy) (*core.TokenTransferPolicy, error)
+	GetTokenTransferPolicy(ctx context.Context, poolNameOrID string) (*core.TokenTransferPolicy, error)
+	DeleteTokenTransferPolicy(ctx context.Context, poolNameOrID string) error
 
 	GetTokenBalances(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error)
 	GetTokenAccounts(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenAccount, *ffapi.FilterResult, error)
+	GetTokenAssociatedAccounts(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenAssociatedAccount, *ffapi.FilterResult, error)
 	GetTokenAccountPools(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error)
+	GetTokenAccountActivity(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenActivity, *ffapi.FilterResult, error)
+	GetTokenPoolHolders(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error)
+	GetTokenIndexOwnership(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error)
+	ReconcileTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenReconciliation, error)
+	GetTokenBalanceMismatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)
 
 	GetTokenTransfers(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransfer, *ffapi.FilterResult, error)
 	GetTokenTransferByID(ctx context.Context, id string) (*core.TokenTransfer, error)
+	ExportTokenTransfers(ctx context.Context, export *core.TokenTransferExport) (io.ReadCloser, error)
+	GetTokenLedger(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenLedgerEntry, *ffapi.FilterResult, error)
+	ExportTokenLedger(ctx context.Context, export *core.TokenLedgerExport) (io.ReadCloser, error)
+
+	CreateTokenPoolSnapshot(ctx context.Context, poolNameOrID string, input *core.TokenSnapshotInput) (*core.TokenSnapshot, error)
+	GetTokenSnapshots(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenSnapshot, *ffapi.FilterResult, error)
+	GetTokenSnapshotByID(ctx context.Context, id string) (*core.TokenSnapshot, error)
+	GetTokenSnapshotBalances(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.TokenSnapshotBalance, *ffapi.FilterResult, error)
+	ExportTokenSnapshot(ctx context.Context, id string) (io.ReadCloser, error)
 
 	NewTransfer(transfer *core.TokenTransferInput) syncasync.Sender
 	MintTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
 	BurnTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
 	TransferTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error)
+	CheckTransferEligibility(ctx context.Context, transfer *core.TokenTransferInput) (*core.TokenTransferEligibility, error)
+	TransferTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput, waitConfirm bool) (*core.TokenTransferBatch, error)
 
 	GetTokenConnectors(ctx context.Context) []*core.TokenConnector
+	GetTokenConnectorBreaker(name string) *core.TokenConnectorBreaker
 
 	NewApproval(approve *core.TokenApprovalInput) syncasync.Sender
 	TokenApproval(ctx context.Context, approval *core.TokenApprovalInput, waitConfirm bool) (*core.TokenApproval, error)
 	GetTokenApprovals(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenApproval, *ffapi.FilterResult, error)
 
+	CreateTokenSwap(ctx context.Context, input *core.TokenSwapInput) (*core.TokenSwap, error)
+	GetTokenSwaps(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenSwap, *ffapi.FilterResult, error)
+	GetTokenSwapByID(ctx context.Context, id string) (*core.TokenSwap, error)
+
+	MintTokensBulk(ctx context.Context, input *core.TokenBulkMintInput) (*core.TokenBulkMint, error)
+	GetTokenBulkMints(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBulkMint, *ffapi.FilterResult, error)
+	GetTokenBulkMintByID(ctx context.Context, id string) (*core.TokenBulkMint, error)
+
+	GetTokenTransferRequests(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransferRequest, *ffapi.FilterResult, error)
+	GetTokenTransferRequestByID(ctx context.Context, id string) (*core.TokenTransferRequest, error)
+	SignoffTokenTransferRequest(ctx context.Context, id, principal string, signer *core.SignerRef) (*core.TokenTransferRequest, error)
+
+	MediaProxyEnabled() bool
+	GetTokenMedia(ctx context.Context, metadataURI string) (*core.TokenMedia, io.ReadCloser, error)
+
 	// From operations.OperationHandler
 	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
 	RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, complete bool, err error)
@@ -83,14 +132,29 @@ type assetManager struct {
 	broadcast        broadcast.Manager        // optional
 	messaging        privatemessaging.Manager // optional
 	tokens           map[string]tokens.Plugin
+	breakers         map[string]*connectorBreaker
 	metrics          metrics.Manager
 	operations       operations.Manager
 	contracts        contracts.Manager
 	cache            cache.CInterface
+	feesCache        cache.CInterface
+	metadata         *metadataIndexer // optional
+	media            *mediaProxy      // optional
 	keyNormalization int
+	confirmations    int
+	approvalExpiry   approvalExpiryConfig
+	expiryLoopDone   chan struct{}
+	swap             swapConfig
+	swapLoopDone     chan struct{}
+	reconciliation   reconciliationConfig
+	reconcileDone    chan struct{}
+	signoff          signoffConfig
+	bulkMint         bulkMintConfig
+	bulkMintLoopDone chan struct{}
+	circuitBreaker   circuitBreakerConfig
 }
 
-func NewAssetManager(ctx context.Context, ns, keyNormalization string, di database.Plugin, ti map[string]tokens.Plugin, im identity.Manager, sa syncasync.Bridge, bm broadcast.Manager, pm privatemessaging.Manager, mm metrics.Manager, om operations.Manager, cm contracts.Manager, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
+func NewAssetManager(ctx context.Context, ns, keyNormalization string, confirmations int, di database.Plugin, ti map[string]tokens.Plugin, ss sharedstorage.Plugin, im identity.Manager, sa syncasync.Bridge, bm broadcast.Manager, pm privatemessaging.Manager, mm metrics.Manager, om operations.Manager, cm contracts.Manager, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
 	if di == nil || im == nil || sa == nil || ti == nil || mm == nil || om == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "AssetManager")
 	}
@@ -106,9 +170,41 @@ func NewAssetManager(ctx context.Context, ns, keyNormalization string, di databa
 		messaging:        pm,
 		tokens:           ti,
 		keyNormalization: identity.ParseKeyNormalizationConfig(keyNormalization),
+		confirmations:    confirmations,
 		metrics:          mm,
 		operations:       om,
 		contracts:        cm,
+		approvalExpiry: approvalExpiryConfig{
+			checkInterval: config.GetDuration(coreconfig.AssetApprovalExpiryCheckInterval),
+			batchSize:     config.GetInt(coreconfig.AssetApprovalExpiryBatchSize),
+		},
+		swap: swapConfig{
+			defaultTimeout: config.GetDuration(coreconfig.AssetSwapDefaultTimeout),
+			checkInterval:  config.GetDuration(coreconfig.AssetSwapCheckInterval),
+			batchSize:      config.GetInt(coreconfig.AssetSwapBatchSize),
+		},
+		reconciliation: reconciliationConfig{
+			interval:  config.GetDuration(coreconfig.AssetReconciliationInterval),
+			batchSize: config.GetInt(coreconfig.AssetReconciliationBatchSize),
+		},
+		bulkMint: bulkMintConfig{
+			batchSize:     config.GetInt(coreconfig.AssetBulkMintBatchSize),
+			maxBatchSize:  config.GetInt(coreconfig.AssetBulkMintMaxBatchSize),
+			maxItems:      config.GetInt(coreconfig.AssetBulkMintMaxItems),
+			batchInterval: config.GetDuration(coreconfig.AssetBulkMintBatchInterval),
+			maxInFlight:   config.GetInt(coreconfig.AssetBulkMintMaxInFlight),
+		},
+		circuitBreaker: circuitBreakerConfig{
+			failureThreshold: config.GetInt(coreconfig.AssetCircuitBreakerFailureThreshold),
+			probeInterval:    config.GetDuration(coreconfig.AssetCircuitBreakerProbeInterval),
+		},
+	}
+	am.breakers = make(map[string]*connectorBreaker, len(ti))
+	for name, plugin := range ti {
+		am.breakers[name] = newConnectorBreaker(name, plugin, &am.circuitBreaker)
+	}
+	if am.signoff, err = loadSignoffConfig(ctx); err != nil {
+		return nil, err
 	}
 	if cacheManager != nil {
 		am.cache, err = cacheManager.GetCache(
@@ -122,12 +218,37 @@ func NewAssetManager(ctx context.Context, ns, keyNormalization string, di databa
 		if err != nil {
 			return nil, err
 		}
+		am.feesCache, err = cacheManager.GetCache(
+			cache.NewCacheConfig(
+				ctx,
+				coreconfig.CacheTokenFeesLimit,
+				coreconfig.CacheTokenFeesTTL,
+				ns,
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config.GetBool(coreconfig.AssetMetadataEnabled) {
+		am.metadata = newMetadataIndexer(ctx, ns, di)
+		if config.GetBool(coreconfig.AssetMetadataMediaEnabled) {
+			if am.media, err = newMediaProxy(ctx, ns, di, ss, am.metadata); err != nil {
+				return nil, err
+			}
+		}
 	}
 	om.RegisterHandler(ctx, am, []core.OpType{
 		core.OpTypeTokenCreatePool,
 		core.OpTypeTokenActivatePool,
+		core.OpTypeTokenMigratePool,
 		core.OpTypeTokenTransfer,
+		core.OpTypeTokenTransferBatch,
 		core.OpTypeTokenApproval,
+		core.OpTypeTokenSwapLock,
+		core.OpTypeTokenSwapInvoke,
+		core.OpTypeTokenSwapClaim,
+		core.OpTypeTokenSwapRefund,
 	})
 	return am, nil
 }
@@ -136,34 +257,109 @@ func (am *assetManager) Name() string {
 	return "AssetManager"
 }
 
+func (am *assetManager) Start() error {
+	if am.metadata != nil {
+		am.metadata.start()
+	}
+	am.expiryLoopDone = make(chan struct{})
+	go am.approvalExpiryLoop()
+	am.swapLoopDone = make(chan struct{})
+	go am.swapLoop()
+	am.bulkMintLoopDone = make(chan struct{})
+	go am.bulkMintLoop()
+	if am.reconciliation.interval > 0 {
+		am.reconcileDone = make(chan struct{})
+		go am.reconciliationLoop()
+	}
+	return nil
+}
+
+func (am *assetManager) WaitStop() {
+	if am.expiryLoopDone != nil {
+		<-am.expiryLoopDone
+	}
+	if am.swapLoopDone != nil {
+		<-am.swapLoopDone
+	}
+	if am.bulkMintLoopDone != nil {
+		<-am.bulkMintLoopDone
+	}
+	if am.reconcileDone != nil {
+		<-am.reconcileDone
+	}
+}
+
+// selectTokenPlugin returns the named token connector, with its REST calls wrapped by the circuit breaker
 func (am *assetManager) selectTokenPlugin(ctx context.Context, name string) (tokens.Plugin, error) {
-	for pluginName, plugin := range am.tokens {
-		if pluginName == name {
-			return plugin, nil
-		}
+	if cb, ok := am.breakers[name]; ok {
+		return cb, nil
 	}
 	return nil, i18n.NewError(ctx, coremsgs.MsgUnknownTokensPlugin, name)
 }
 
 func (am *assetManager) GetTokenBalances(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
-	return am.database.GetTokenBalances(ctx, am.namespace, filter)
+	balances, fr, err := am.database.GetTokenBalances(ctx, am.namespace, filter)
+	if err == nil {
+		err = am.enrichBalancesWithMetadata(ctx, balances)
+	}
+	return balances, fr, err
 }
 
 func (am *assetManager) GetTokenAccounts(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenAccount, *ffapi.FilterResult, error) {
 	return am.database.GetTokenAccounts(ctx, am.namespace, filter)
 }
 
+func (am *assetManager) GetTokenAssociatedAccounts(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenAssociatedAccount, *ffapi.FilterResult, error) {
+	return am.database.GetTokenAssociatedAccounts(ctx, am.namespace, filter)
+}
+
 func (am *assetManager) GetTokenAccountPools(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error) {
 	return am.database.GetTokenAccountPools(ctx, am.namespace, key, filter)
 }
 
+// GetTokenPoolHolders rolls up the token indexes held by each key in a non-fungible pool, such as to find how many
+// tokens in the pool a key holds, without paging through the balance of every token index
+func (am *assetManager) GetTokenPoolHolders(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error) {
+	pool, err := am.getNonFungibleTokenPool(ctx, poolNameOrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return am.database.GetTokenPoolHolders(ctx, am.namespace, pool.ID, filter)
+}
+
+// GetTokenIndexOwnership rolls up the keys holding each token index in a non-fungible pool, such as to find the
+// current owner of a token
+func (am *assetManager) GetTokenIndexOwnership(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error) {
+	pool, err := am.getNonFungibleTokenPool(ctx, poolNameOrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return am.database.GetTokenIndexOwnership(ctx, am.namespace, pool.ID, filter)
+}
+
+func (am *assetManager) getNonFungibleTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if pool.Type != core.TokenTypeNonFungible {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolNotNonFungible, pool.Name)
+	}
+	return pool, nil
+}
+
+func (am *assetManager) GetTokenAccountActivity(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenActivity, *ffapi.FilterResult, error) {
+	return am.database.GetTokenAccountActivity(ctx, am.namespace, key, filter)
+}
+
 func (am *assetManager) GetTokenConnectors(ctx context.Context) []*core.TokenConnector {
 	connectors := []*core.TokenConnector{}
 	for token := range am.tokens {
 		connectors = append(
 			connectors,
 			&core.TokenConnector{
-				Name: token,
+				Name:    token,
+				Breaker: am.GetTokenConnectorBreaker(token),
 			},
 		)
 	}
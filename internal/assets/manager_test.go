@@ -21,6 +21,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/txcommon"
@@ -72,7 +74,7 @@ func newTestAssetsCommon(t *testing.T, metrics bool) (*assetManager, func()) {
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
 	mti.On("Name").Return("ut").Maybe()
 	ctx, cancel := context.WithCancel(ctx)
-	a, err := NewAssetManager(ctx, "ns1", "blockchain_plugin", mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+	a, err := NewAssetManager(ctx, "ns1", "blockchain_plugin", 0, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, nil, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
 	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything).Maybe()
 	rag.RunFn = func(a mock.Arguments) {
 		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
@@ -83,8 +85,25 @@ func newTestAssetsCommon(t *testing.T, metrics bool) (*assetManager, func()) {
 	return am, cancel
 }
 
+// newTestPool returns a confirmed fungible pool on the test connector, with any options applied
+func newTestPool(opts ...func(pool *core.TokenPool)) *core.TokenPool {
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Name:      "pool1",
+		Type:      core.TokenTypeFungible,
+		Connector: "magic-tokens",
+		Locator:   "F1",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	for _, opt := range opts {
+		opt(pool)
+	}
+	return pool
+}
+
 func TestInitFail(t *testing.T) {
-	_, err := NewAssetManager(context.Background(), "", "", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewAssetManager(context.Background(), "", "", 0, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -105,11 +124,51 @@ func TestCacheInitFail(t *testing.T) {
 	cmi.On("GetCache", mock.Anything).Return(nil, cacheInitError)
 	txHelper, _ := txcommon.NewTransactionHelper(context.Background(), "ns1", mdi, mdm, cmi)
 
-	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", mdi, map[string]tokens.Plugin{"magic-tokens": mti}, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", 0, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, nil, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
+
+	assert.Equal(t, cacheInitError, err)
+}
+
+func TestFeesCacheInitFail(t *testing.T) {
+	cacheInitError := errors.New("Initialization error.")
+	coreconfig.Reset()
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	mim := &identitymanagermocks.Manager{}
+	msa := &syncasyncmocks.Bridge{}
+	mbm := &broadcastmocks.Manager{}
+	mpm := &privatemessagingmocks.Manager{}
+	mti := &tokenmocks.Plugin{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+	mcm := &contractmocks.Manager{}
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.MatchedBy(func(cc *cache.CConfig) bool {
+		name, _ := cc.UniqueName()
+		return name == "ns1::cache.tokenfees"
+	})).Return(nil, cacheInitError)
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute), nil)
+	txHelper, _ := txcommon.NewTransactionHelper(context.Background(), "ns1", mdi, mdm, cmi)
+
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", 0, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, nil, mim, msa, mbm, mpm, mm, mom, mcm, txHelper, cmi)
 
 	assert.Equal(t, cacheInitError, err)
 }
 
+func TestSignoffConfigInitFail(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.AssetSignoffThreshold, "not a number")
+	mdi := &databasemocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	msa := &syncasyncmocks.Bridge{}
+	mti := &tokenmocks.Plugin{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+
+	_, err := NewAssetManager(context.Background(), "ns1", "blockchain_plugin", 0, mdi, map[string]tokens.Plugin{"magic-tokens": mti}, nil, mim, msa, nil, nil, mm, mom, nil, nil, nil)
+	assert.Regexp(t, "FF10500", err)
+}
+
 func TestName(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -140,6 +199,18 @@ func TestGetTokenAccounts(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetTokenAssociatedAccounts(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenAssociatedAccountQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi.On("GetTokenAssociatedAccounts", context.Background(), "ns1", f).Return([]*core.TokenAssociatedAccount{}, nil, nil)
+	_, _, err := am.GetTokenAssociatedAccounts(context.Background(), f)
+	assert.NoError(t, err)
+}
+
 func TestGetTokenAccountPools(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -152,6 +223,79 @@ func TestGetTokenAccountPools(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetTokenPoolHolders(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:   fftypes.NewUUID(),
+		Type: core.TokenTypeNonFungible,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenPoolHolderQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolHolders", context.Background(), "ns1", pool.ID, f).Return([]*core.TokenPoolHolder{}, nil, nil)
+	_, _, err := am.GetTokenPoolHolders(context.Background(), "pool1", f)
+	assert.NoError(t, err)
+}
+
+func TestGetTokenPoolHoldersFungible(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:   fftypes.NewUUID(),
+		Name: "pool1",
+		Type: core.TokenTypeFungible,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenPoolHolderQueryFactory.NewFilter(context.Background())
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	_, _, err := am.GetTokenPoolHolders(context.Background(), "pool1", fb.And())
+	assert.Regexp(t, "FF10515", err)
+}
+
+func TestGetTokenIndexOwnership(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:   fftypes.NewUUID(),
+		Type: core.TokenTypeNonFungible,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenIndexOwnershipQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenIndexOwnership", context.Background(), "ns1", pool.ID, f).Return([]*core.TokenIndexOwnership{}, nil, nil)
+	_, _, err := am.GetTokenIndexOwnership(context.Background(), "pool1", f)
+	assert.NoError(t, err)
+}
+
+func TestGetTokenIndexOwnershipNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenIndexOwnershipQueryFactory.NewFilter(context.Background())
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+	_, _, err := am.GetTokenIndexOwnership(context.Background(), "pool1", fb.And())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetTokenAccountActivity(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenActivityQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi.On("GetTokenAccountActivity", context.Background(), "ns1", "0x1", f).Return([]*core.TokenActivity{}, nil, nil)
+	_, _, err := am.GetTokenAccountActivity(context.Background(), "0x1", f)
+	assert.NoError(t, err)
+}
+
 func TestGetTokenConnectors(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
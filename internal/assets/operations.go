@@ -36,16 +36,32 @@ type activatePoolData struct {
 	Pool *core.TokenPool `json:"pool"`
 }
 
+type migratePoolData struct {
+	Pool      *core.TokenPool          `json:"pool"`
+	Migration *core.TokenPoolMigration `json:"migration"`
+}
+
 type transferData struct {
 	Pool     *core.TokenPool     `json:"pool"`
 	Transfer *core.TokenTransfer `json:"transfer"`
 }
 
+type transferBatchData struct {
+	Pool      *core.TokenPool       `json:"pool"`
+	Transfers []*core.TokenTransfer `json:"transfers"`
+}
+
 type approvalData struct {
 	Pool     *core.TokenPool     `json:"pool"`
 	Approval *core.TokenApproval `json:"approval"`
 }
 
+type tokenSwapData struct {
+	Pool *core.TokenPool `json:"pool"`
+	Swap *core.TokenSwap `json:"swap"`
+	Leg  int             `json:"leg"`
+}
+
 func (am *assetManager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	switch op.Type {
 	case core.OpTypeTokenCreatePool:
@@ -68,6 +84,27 @@ func (am *assetManager) PrepareOperation(ctx context.Context, op *core.Operation
 		}
 		return opActivatePool(op, pool), nil
 
+	case core.OpTypeTokenMigratePool:
+		migrationID, err := txcommon.RetrieveTokenPoolMigrateInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		migration, err := am.database.GetTokenPoolMigrationByID(ctx, am.namespace, migrationID)
+		if err != nil {
+			return nil, err
+		} else if migration == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		} else if migration.State != core.TokenPoolMigrationStatePending {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolMigrationNotPending, migration.ID)
+		}
+		pool, err := am.GetTokenPoolByID(ctx, migration.Pool)
+		if err != nil {
+			return nil, err
+		} else if pool == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return opMigratePool(op, pool, migration), nil
+
 	case core.OpTypeTokenTransfer:
 		transfer, err := txcommon.RetrieveTokenTransferInputs(ctx, op)
 		if err != nil {
@@ -81,6 +118,19 @@ func (am *assetManager) PrepareOperation(ctx context.Context, op *core.Operation
 		}
 		return opTransfer(op, pool, transfer), nil
 
+	case core.OpTypeTokenTransferBatch:
+		transfers, err := txcommon.RetrieveTokenTransferBatchInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := am.GetTokenPoolByID(ctx, transfers[0].Pool)
+		if err != nil {
+			return nil, err
+		} else if pool == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return opTransferBatch(op, pool, transfers), nil
+
 	case core.OpTypeTokenApproval:
 		approval, err := txcommon.RetrieveTokenApprovalInputs(ctx, op)
 		if err != nil {
@@ -94,6 +144,34 @@ func (am *assetManager) PrepareOperation(ctx context.Context, op *core.Operation
 		}
 		return opApproval(op, pool, approval), nil
 
+	case core.OpTypeTokenSwapLock, core.OpTypeTokenSwapClaim, core.OpTypeTokenSwapRefund:
+		swapID, leg, err := txcommon.RetrieveTokenSwapInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		swap, err := am.database.GetTokenSwapByID(ctx, am.namespace, swapID)
+		if err != nil {
+			return nil, err
+		} else if swap == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenSwapNotFound, swapID)
+		} else if leg < 0 || leg >= len(swap.Legs) {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		pool, err := am.GetTokenPoolByID(ctx, swap.Legs[leg].Pool)
+		if err != nil {
+			return nil, err
+		} else if pool == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return opTokenSwap(op, pool, swap, leg), nil
+
+	case core.OpTypeTokenSwapInvoke:
+		req, err := txcommon.RetrieveBlockchainInvokeInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		return txcommon.OpBlockchainInvoke(op, req, nil), nil
+
 	default:
 		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotSupported, op.Type)
 	}
@@ -117,6 +195,16 @@ func (am *assetManager) RunOperation(ctx context.Context, op *core.PreparedOpera
 		complete, err = plugin.ActivateTokenPool(ctx, data.Pool)
 		return nil, complete, err
 
+	case migratePoolData:
+		// The new connector resolves the existing pool from the migration config, in the same way as
+		// when a pool is created for a pre-existing contract
+		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
+		if err != nil {
+			return nil, false, err
+		}
+		complete, err = plugin.CreateTokenPool(ctx, op.NamespacedIDString(), data.Pool)
+		return nil, complete, err
+
 	case transferData:
 		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
 		if err != nil {
@@ -133,6 +221,13 @@ func (am *assetManager) RunOperation(ctx context.Context, op *core.PreparedOpera
 			panic(fmt.Sprintf("unknown transfer type: %v", data.Transfer.Type))
 		}
 
+	case transferBatchData:
+		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
+		if err != nil {
+			return nil, false, err
+		}
+		return nil, false, plugin.TransferTokensBatch(ctx, op.NamespacedIDString(), data.Pool.Locator, data.Transfers, data.Pool.Methods)
+
 	case approvalData:
 		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
 		if err != nil {
@@ -140,6 +235,27 @@ func (am *assetManager) RunOperation(ctx context.Context, op *core.PreparedOpera
 		}
 		return nil, false, plugin.TokensApproval(ctx, op.NamespacedIDString(), data.Pool.Locator, data.Approval, data.Pool.Methods)
 
+	case tokenSwapData:
+		plugin, err := am.selectTokenPlugin(ctx, data.Pool.Connector)
+		if err != nil {
+			return nil, false, err
+		}
+		leg := data.Swap.Legs[data.Leg]
+		switch op.Type {
+		case core.OpTypeTokenSwapLock:
+			return nil, false, plugin.LockTokens(ctx, op.NamespacedIDString(), data.Pool.Locator, data.Swap, leg)
+		case core.OpTypeTokenSwapClaim:
+			return nil, false, plugin.ClaimTokens(ctx, op.NamespacedIDString(), data.Pool.Locator, data.Swap, leg)
+		case core.OpTypeTokenSwapRefund:
+			return nil, false, plugin.RefundTokens(ctx, op.NamespacedIDString(), data.Pool.Locator, data.Swap, leg)
+		default:
+			panic(fmt.Sprintf("unknown token swap operation type: %v", op.Type))
+		}
+
+	case txcommon.BlockchainInvokeData:
+		// The invoke leg of a swap is submitted through the contract manager
+		return am.contracts.RunOperation(ctx, op)
+
 	default:
 		return nil, false, i18n.NewError(ctx, coremsgs.MsgOperationDataIncorrect, op.Data)
 	}
@@ -164,6 +280,13 @@ func (am *assetManager) OnOperationUpdate(ctx context.Context, op *core.Operatio
 		}
 	}
 
+	// Leave the pool on its previous connector if the new connector could not resolve it
+	if op.Type == core.OpTypeTokenMigratePool && update.Status == core.OpStatusFailed {
+		if err := am.onTokenPoolMigrationFailed(ctx, op, update); err != nil {
+			return err
+		}
+	}
+
 	// Write an event for failed transfer operations
 	if op.Type == core.OpTypeTokenTransfer && update.Status == core.OpStatusFailed {
 		tokenTransfer, err := txcommon.RetrieveTokenTransferInputs(ctx, op)
@@ -182,6 +305,21 @@ func (am *assetManager) OnOperationUpdate(ctx context.Context, op *core.Operatio
 		}
 	}
 
+	// Write an event for each leg of failed batch transfer operations
+	if op.Type == core.OpTypeTokenTransferBatch && update.Status == core.OpStatusFailed {
+		transfers, err := txcommon.RetrieveTokenTransferBatchInputs(ctx, op)
+		if err != nil {
+			log.L(ctx).Warnf("Could not parse token transfer batch: %s (%+v)", err, op.Input)
+		}
+		for _, tokenTransfer := range transfers {
+			event := core.NewEvent(core.EventTypeTransferOpFailed, op.Namespace, op.ID, op.Transaction, tokenTransfer.Pool.String())
+			event.Correlator = tokenTransfer.LocalID
+			if err := am.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+	}
+
 	// Write an event for failed approval operations
 	if op.Type == core.OpTypeTokenApproval && update.Status == core.OpStatusFailed {
 		tokenApproval, err := txcommon.RetrieveTokenApprovalInputs(ctx, op)
@@ -200,6 +338,13 @@ func (am *assetManager) OnOperationUpdate(ctx context.Context, op *core.Operatio
 		}
 	}
 
+	// Resume a failed swap, when a retry of the claim or refund that failed it succeeds
+	if (op.Type == core.OpTypeTokenSwapClaim || op.Type == core.OpTypeTokenSwapRefund) && update.Status == core.OpStatusSucceeded {
+		if err := am.resumeFailedSwap(ctx, op); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -223,6 +368,22 @@ func opActivatePool(op *core.Operation, pool *core.TokenPool) *core.PreparedOper
 	}
 }
 
+// opMigratePool passes the pool to the new connector with the connector name and config of the migration, and
+// with the transaction of the migration so that the pool reported by the connector is correlated to the migration
+func opMigratePool(op *core.Operation, pool *core.TokenPool, migration *core.TokenPoolMigration) *core.PreparedOperation {
+	target := *pool
+	target.Connector = migration.ToConnector
+	target.Config = migration.Config
+	target.TX = migration.TX
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      migratePoolData{Pool: &target, Migration: migration},
+	}
+}
+
 func opTransfer(op *core.Operation, pool *core.TokenPool, transfer *core.TokenTransfer) *core.PreparedOperation {
 	return &core.PreparedOperation{
 		ID:        op.ID,
@@ -233,6 +394,16 @@ func opTransfer(op *core.Operation, pool *core.TokenPool, transfer *core.TokenTr
 	}
 }
 
+func opTransferBatch(op *core.Operation, pool *core.TokenPool, transfers []*core.TokenTransfer) *core.PreparedOperation {
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      transferBatchData{Pool: pool, Transfers: transfers},
+	}
+}
+
 func opApproval(op *core.Operation, pool *core.TokenPool, approval *core.TokenApproval) *core.PreparedOperation {
 	return &core.PreparedOperation{
 		ID:        op.ID,
@@ -242,3 +413,13 @@ func opApproval(op *core.Operation, pool *core.TokenPool, approval *core.TokenAp
 		Data:      approvalData{Pool: pool, Approval: approval},
 	}
 }
+
+func opTokenSwap(op *core.Operation, pool *core.TokenPool, swap *core.TokenSwap, leg int) *core.PreparedOperation {
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      tokenSwapData{Pool: pool, Swap: swap, Leg: leg},
+	}
+}
@@ -20,11 +20,14 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/mocks/contractmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/tokenmocks"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -94,6 +97,44 @@ func TestPrepareAndRunActivatePool(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestPrepareAndRunMigratePool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mti := addTestMigrationConnector(am)
+
+	pool, migration := newTestMigratingPool()
+	migration.Config = fftypes.JSONObject{"address": "0x12345"}
+	op := &core.Operation{
+		Type:      core.OpTypeTokenMigratePool,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	txcommon.AddTokenPoolMigrateInputs(op, migration.ID)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migration.ID).Return(migration, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mti.On("CreateTokenPool", context.Background(), "ns1:"+op.ID.String(), mock.MatchedBy(func(p *core.TokenPool) bool {
+		return p.ID.Equals(pool.ID) &&
+			p.Connector == "new-tokens" &&
+			p.Config.GetString("address") == "0x12345" &&
+			p.TX.ID.Equals(migration.TX.ID)
+	})).Return(false, nil)
+
+	po, err := am.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, migration, po.Data.(migratePoolData).Migration)
+	assert.Equal(t, "magic-tokens", pool.Connector)
+
+	_, complete, err := am.RunOperation(context.Background(), po)
+
+	assert.False(t, complete)
+	assert.NoError(t, err)
+
+	mti.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
 func TestPrepareAndRunTransfer(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -133,6 +174,45 @@ func TestPrepareAndRunTransfer(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestPrepareAndRunTransferBatch(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:      core.OpTypeTokenTransferBatch,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		Locator:   "F1",
+	}
+	transfers := []*core.TokenTransfer{
+		{LocalID: fftypes.NewUUID(), Pool: pool.ID, Type: core.TokenTransferTypeTransfer, TokenIndex: "1"},
+		{LocalID: fftypes.NewUUID(), Pool: pool.ID, Type: core.TokenTransferTypeTransfer, TokenIndex: "2"},
+	}
+	txcommon.AddTokenTransferBatchInputs(op, transfers)
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti.On("TransferTokensBatch", context.Background(), "ns1:"+op.ID.String(), "F1", transfers, (*fftypes.JSONAny)(nil)).Return(nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+
+	po, err := am.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, pool, po.Data.(transferBatchData).Pool)
+	assert.Equal(t, transfers, po.Data.(transferBatchData).Transfers)
+
+	_, complete, err := am.RunOperation(context.Background(), po)
+
+	assert.False(t, complete)
+	assert.NoError(t, err)
+
+	mti.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
 func TestPrepareAndRunApproval(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -246,6 +326,117 @@ func TestPrepareOperationActivatePoolNotFound(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestPrepareOperationMigratePoolBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeTokenMigratePool,
+		Input: fftypes.JSONObject{"migration": "bad"},
+	}
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestPrepareOperationMigratePoolError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	migrationID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type: core.OpTypeTokenMigratePool,
+	}
+	txcommon.AddTokenPoolMigrateInputs(op, migrationID)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migrationID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationMigratePoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	migrationID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type: core.OpTypeTokenMigratePool,
+	}
+	txcommon.AddTokenPoolMigrateInputs(op, migrationID)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migrationID).Return(nil, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationMigratePoolNotPending(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	migration.State = core.TokenPoolMigrationStateFailed
+	op := &core.Operation{
+		Type: core.OpTypeTokenMigratePool,
+	}
+	txcommon.AddTokenPoolMigrateInputs(op, migration.ID)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migration.ID).Return(migration, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10513", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationMigratePoolPoolError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	op := &core.Operation{
+		Type: core.OpTypeTokenMigratePool,
+	}
+	txcommon.AddTokenPoolMigrateInputs(op, migration.ID)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migration.ID).Return(migration, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", migration.Pool).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationMigratePoolPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	op := &core.Operation{
+		Type: core.OpTypeTokenMigratePool,
+	}
+	txcommon.AddTokenPoolMigrateInputs(op, migration.ID)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migration.ID).Return(migration, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", migration.Pool).Return(nil, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestPrepareOperationTransferBadInput(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -297,6 +488,61 @@ func TestPrepareOperationTransferNotFound(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestPrepareOperationTransferBatchBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeTokenTransferBatch,
+		Input: fftypes.JSONObject{},
+	}
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10483", err)
+}
+
+func TestPrepareOperationTransferBatchError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	poolID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type: core.OpTypeTokenTransferBatch,
+		Input: fftypes.JSONObject{"transfers": []interface{}{
+			map[string]interface{}{"pool": poolID.String()},
+		}},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", poolID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationTransferBatchNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	poolID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type: core.OpTypeTokenTransferBatch,
+		Input: fftypes.JSONObject{"transfers": []interface{}{
+			map[string]interface{}{"pool": poolID.String()},
+		}},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", poolID).Return(nil, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
 func TestPrepareOperationApprovalBadInput(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -407,6 +653,22 @@ func TestRunOperationActivatePoolBadPlugin(t *testing.T) {
 	assert.Regexp(t, "FF10272", err)
 }
 
+func TestRunOperationMigratePoolBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.PreparedOperation{
+		Data: migratePoolData{
+			Pool: &core.TokenPool{},
+		},
+	}
+
+	_, complete, err := am.RunOperation(context.Background(), op)
+
+	assert.False(t, complete)
+	assert.Regexp(t, "FF10272", err)
+}
+
 func TestRunOperationTransferBadPlugin(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -421,6 +683,20 @@ func TestRunOperationTransferBadPlugin(t *testing.T) {
 	assert.Regexp(t, "FF10272", err)
 }
 
+func TestRunOperationTransferBatchBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{}
+	pool := &core.TokenPool{}
+	transfers := []*core.TokenTransfer{{}}
+
+	_, complete, err := am.RunOperation(context.Background(), opTransferBatch(op, pool, transfers))
+
+	assert.False(t, complete)
+	assert.Regexp(t, "FF10272", err)
+}
+
 func TestRunOperationApprovalBadPlugin(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -640,97 +916,120 @@ func TestOperationUpdatePoolEventFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
-func TestOperationUpdateTransfer(t *testing.T) {
+func TestOperationUpdateMigratePool(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
 
-	transfer := &core.TokenTransfer{
-		LocalID: fftypes.NewUUID(),
-		Pool:    fftypes.NewUUID(),
-		Type:    core.TokenTransferTypeTransfer,
-	}
+	pool, migration := newTestMigratingPool()
 	op := &core.Operation{
-		ID:   fftypes.NewUUID(),
-		Type: core.OpTypeTokenTransfer,
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenMigratePool,
+		Namespace: "ns1",
 	}
-	err := txcommon.AddTokenTransferInputs(op, transfer)
-	assert.NoError(t, err)
-
+	txcommon.AddTokenPoolMigrateInputs(op, migration.ID)
 	update := &core.OperationUpdate{
-		Status: core.OpStatusFailed,
+		Status:       core.OpStatusFailed,
+		ErrorMessage: "pop",
 	}
 
 	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migration.ID).Return(migration, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *core.TokenPool) bool {
+		return p.State == core.TokenPoolStatePaused
+	}), database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("UpdateTokenPoolMigration", context.Background(), "ns1", migration.ID, mock.Anything).Return(nil)
 	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
-		return event.Type == core.EventTypeTransferOpFailed && *event.Reference == *op.ID && *event.Correlator == *transfer.LocalID
+		return event.Type == core.EventTypePoolMigrationFailed && event.Correlator.Equals(migration.ID)
 	})).Return(nil)
 
-	err = am.OnOperationUpdate(context.Background(), op, update)
-
+	err := am.OnOperationUpdate(context.Background(), op, update)
 	assert.NoError(t, err)
 
 	mdi.AssertExpectations(t)
 }
 
-func TestOperationUpdateTransferBadInput(t *testing.T) {
+func TestOperationUpdateMigratePoolBadInput(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
 
 	op := &core.Operation{
-		ID:   fftypes.NewUUID(),
-		Type: core.OpTypeTokenTransfer,
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenMigratePool,
+		Namespace: "ns1",
+		Input:     fftypes.JSONObject{"migration": "bad"},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+	}
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+}
+
+func TestOperationUpdateMigratePoolNotPending(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	migration.State = core.TokenPoolMigrationStateSucceeded
+	op := &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenMigratePool,
+		Namespace: "ns1",
 	}
+	txcommon.AddTokenPoolMigrateInputs(op, migration.ID)
 	update := &core.OperationUpdate{
 		Status: core.OpStatusFailed,
 	}
 
 	mdi := am.database.(*databasemocks.Plugin)
-	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
-		return event.Type == core.EventTypeTransferOpFailed && *event.Reference == *op.ID && event.Correlator == nil
-	})).Return(nil)
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migration.ID).Return(migration, nil)
 
 	err := am.OnOperationUpdate(context.Background(), op, update)
-
 	assert.NoError(t, err)
 
 	mdi.AssertExpectations(t)
 }
 
-func TestOperationUpdateTransferEventFail(t *testing.T) {
+func TestOperationUpdateMigratePoolFail(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
 
+	migrationID := fftypes.NewUUID()
 	op := &core.Operation{
-		ID:   fftypes.NewUUID(),
-		Type: core.OpTypeTokenTransfer,
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenMigratePool,
+		Namespace: "ns1",
 	}
+	txcommon.AddTokenPoolMigrateInputs(op, migrationID)
 	update := &core.OperationUpdate{
 		Status: core.OpStatusFailed,
 	}
 
 	mdi := am.database.(*databasemocks.Plugin)
-	mdi.On("InsertEvent", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+	mdi.On("GetTokenPoolMigrationByID", context.Background(), "ns1", migrationID).Return(nil, fmt.Errorf("pop"))
 
 	err := am.OnOperationUpdate(context.Background(), op, update)
-
 	assert.EqualError(t, err, "pop")
 
 	mdi.AssertExpectations(t)
 }
 
-func TestOperationUpdateApproval(t *testing.T) {
+func TestOperationUpdateTransfer(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
 
-	approval := &core.TokenApproval{
+	transfer := &core.TokenTransfer{
 		LocalID: fftypes.NewUUID(),
 		Pool:    fftypes.NewUUID(),
+		Type:    core.TokenTransferTypeTransfer,
 	}
 	op := &core.Operation{
 		ID:   fftypes.NewUUID(),
-		Type: core.OpTypeTokenApproval,
+		Type: core.OpTypeTokenTransfer,
 	}
-	err := txcommon.AddTokenApprovalInputs(op, approval)
+	err := txcommon.AddTokenTransferInputs(op, transfer)
 	assert.NoError(t, err)
 
 	update := &core.OperationUpdate{
@@ -739,7 +1038,7 @@ func TestOperationUpdateApproval(t *testing.T) {
 
 	mdi := am.database.(*databasemocks.Plugin)
 	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
-		return event.Type == core.EventTypeApprovalOpFailed && *event.Reference == *op.ID && *event.Correlator == *approval.LocalID
+		return event.Type == core.EventTypeTransferOpFailed && *event.Reference == *op.ID && *event.Correlator == *transfer.LocalID
 	})).Return(nil)
 
 	err = am.OnOperationUpdate(context.Background(), op, update)
@@ -749,7 +1048,159 @@ func TestOperationUpdateApproval(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
-func TestOperationUpdateApprovalBadInput(t *testing.T) {
+func TestOperationUpdateTransferBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeTokenTransfer,
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeTransferOpFailed && *event.Reference == *op.ID && event.Correlator == nil
+	})).Return(nil)
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateTransferEventFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeTokenTransfer,
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateTransferBatch(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfers := []*core.TokenTransfer{
+		{LocalID: fftypes.NewUUID(), Pool: fftypes.NewUUID(), Type: core.TokenTransferTypeTransfer},
+		{LocalID: fftypes.NewUUID(), Pool: fftypes.NewUUID(), Type: core.TokenTransferTypeTransfer},
+	}
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeTokenTransferBatch,
+	}
+	err := txcommon.AddTokenTransferBatchInputs(op, transfers)
+	assert.NoError(t, err)
+
+	update := &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	for _, transfer := range transfers {
+		localID := transfer.LocalID
+		mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+			return event.Type == core.EventTypeTransferOpFailed && *event.Reference == *op.ID && *event.Correlator == *localID
+		})).Return(nil).Once()
+	}
+
+	err = am.OnOperationUpdate(context.Background(), op, update)
+
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateTransferBatchBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeTokenTransferBatch,
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+	}
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+
+	assert.NoError(t, err)
+}
+
+func TestOperationUpdateTransferBatchEventFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeTokenTransferBatch,
+	}
+	err := txcommon.AddTokenTransferBatchInputs(op, []*core.TokenTransfer{{LocalID: fftypes.NewUUID()}})
+	assert.NoError(t, err)
+	update := &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	err = am.OnOperationUpdate(context.Background(), op, update)
+
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateApproval(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	approval := &core.TokenApproval{
+		LocalID: fftypes.NewUUID(),
+		Pool:    fftypes.NewUUID(),
+	}
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeTokenApproval,
+	}
+	err := txcommon.AddTokenApprovalInputs(op, approval)
+	assert.NoError(t, err)
+
+	update := &core.OperationUpdate{
+		Status: core.OpStatusFailed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeApprovalOpFailed && *event.Reference == *op.ID && *event.Correlator == *approval.LocalID
+	})).Return(nil)
+
+	err = am.OnOperationUpdate(context.Background(), op, update)
+
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateApprovalBadInput(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
 
@@ -794,3 +1245,330 @@ func TestOperationUpdateApprovalEventFail(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func TestPrepareAndRunTokenSwap(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		Locator:   "F1",
+	}
+	swap := &core.TokenSwap{
+		ID: fftypes.NewUUID(),
+		Legs: core.TokenSwapLegs{
+			{Type: core.TokenSwapLegTypeTransfer, Pool: fftypes.NewUUID()},
+			{Type: core.TokenSwapLegTypeTransfer, Pool: pool.ID},
+		},
+	}
+
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swap.ID).Return(swap, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+
+	for _, opType := range []core.OpType{core.OpTypeTokenSwapLock, core.OpTypeTokenSwapClaim, core.OpTypeTokenSwapRefund} {
+		op := &core.Operation{
+			Type:      opType,
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		}
+		txcommon.AddTokenSwapInputs(op, swap.ID, 1)
+		mti.On("LockTokens", context.Background(), "ns1:"+op.ID.String(), "F1", swap, swap.Legs[1]).Return(nil).Maybe()
+		mti.On("ClaimTokens", context.Background(), "ns1:"+op.ID.String(), "F1", swap, swap.Legs[1]).Return(nil).Maybe()
+		mti.On("RefundTokens", context.Background(), "ns1:"+op.ID.String(), "F1", swap, swap.Legs[1]).Return(nil).Maybe()
+
+		po, err := am.PrepareOperation(context.Background(), op)
+		assert.NoError(t, err)
+		assert.Equal(t, pool, po.Data.(tokenSwapData).Pool)
+		assert.Equal(t, swap, po.Data.(tokenSwapData).Swap)
+		assert.Equal(t, 1, po.Data.(tokenSwapData).Leg)
+
+		_, complete, err := am.RunOperation(context.Background(), po)
+		assert.False(t, complete)
+		assert.NoError(t, err)
+	}
+
+	mti.AssertNumberOfCalls(t, "LockTokens", 1)
+	mti.AssertNumberOfCalls(t, "ClaimTokens", 1)
+	mti.AssertNumberOfCalls(t, "RefundTokens", 1)
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareAndRunTokenSwapInvoke(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:      core.OpTypeTokenSwapInvoke,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Input:     fftypes.JSONObject{"type": "invoke", "key": "0x12345"},
+	}
+
+	mcm := am.contracts.(*contractmocks.Manager)
+	mcm.On("RunOperation", context.Background(), mock.MatchedBy(func(po *core.PreparedOperation) bool {
+		return po.Data.(txcommon.BlockchainInvokeData).Request.Key == "0x12345"
+	})).Return(nil, false, nil)
+
+	po, err := am.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpTypeTokenSwapInvoke, po.Type)
+
+	_, complete, err := am.RunOperation(context.Background(), po)
+	assert.False(t, complete)
+	assert.NoError(t, err)
+
+	mcm.AssertExpectations(t)
+}
+
+func TestPrepareOperationTokenSwapInvokeBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeTokenSwapInvoke,
+		Input: fftypes.JSONObject{"key": map[bool]bool{true: false}},
+	}
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF00127", err)
+}
+
+func TestPrepareOperationTokenSwapBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeTokenSwapLock,
+		Input: fftypes.JSONObject{"swap": "bad"},
+	}
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestPrepareOperationTokenSwapError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swapID := fftypes.NewUUID()
+	op := &core.Operation{Type: core.OpTypeTokenSwapLock}
+	txcommon.AddTokenSwapInputs(op, swapID, 0)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swapID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationTokenSwapNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swapID := fftypes.NewUUID()
+	op := &core.Operation{Type: core.OpTypeTokenSwapClaim}
+	txcommon.AddTokenSwapInputs(op, swapID, 0)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swapID).Return(nil, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10490", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationTokenSwapBadLeg(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := &core.TokenSwap{
+		ID:   fftypes.NewUUID(),
+		Legs: core.TokenSwapLegs{{Pool: fftypes.NewUUID()}},
+	}
+	op := &core.Operation{Type: core.OpTypeTokenSwapRefund}
+	txcommon.AddTokenSwapInputs(op, swap.ID, 1)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swap.ID).Return(swap, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationTokenSwapPoolError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := &core.TokenSwap{
+		ID:   fftypes.NewUUID(),
+		Legs: core.TokenSwapLegs{{Pool: fftypes.NewUUID()}},
+	}
+	op := &core.Operation{Type: core.OpTypeTokenSwapLock}
+	txcommon.AddTokenSwapInputs(op, swap.ID, 0)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swap.ID).Return(swap, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", swap.Legs[0].Pool).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPrepareOperationTokenSwapPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := &core.TokenSwap{
+		ID:   fftypes.NewUUID(),
+		Legs: core.TokenSwapLegs{{Pool: fftypes.NewUUID()}},
+	}
+	op := &core.Operation{Type: core.OpTypeTokenSwapLock}
+	txcommon.AddTokenSwapInputs(op, swap.ID, 0)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swap.ID).Return(swap, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", swap.Legs[0].Pool).Return(nil, nil)
+
+	_, err := am.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRunOperationTokenSwapBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{}
+	pool := &core.TokenPool{}
+	swap := &core.TokenSwap{Legs: core.TokenSwapLegs{{}}}
+
+	_, complete, err := am.RunOperation(context.Background(), opTokenSwap(op, pool, swap, 0))
+
+	assert.False(t, complete)
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestRunOperationTokenSwapUnknownType(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{Type: core.OpTypeTokenTransfer}
+	pool := &core.TokenPool{Connector: "magic-tokens"}
+	swap := &core.TokenSwap{Legs: core.TokenSwapLegs{{}}}
+
+	assert.PanicsWithValue(t, "unknown token swap operation type: token_transfer", func() {
+		am.RunOperation(context.Background(), opTokenSwap(op, pool, swap, 0))
+	})
+}
+
+func TestOperationUpdateTokenSwapResume(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := &core.TokenSwap{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenSwapStateFailed,
+	}
+	op := &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenSwapRefund,
+		Namespace: "ns1",
+	}
+	txcommon.AddTokenSwapInputs(op, swap.ID, 0)
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swap.ID).Return(swap, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		val, _ := info.SetOperations[0].Value.Value()
+		return val == "refunding"
+	})).Return(nil)
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateTokenSwapNotFailed(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := &core.TokenSwap{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenSwapStateClaiming,
+	}
+	op := &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenSwapClaim,
+		Namespace: "ns1",
+	}
+	txcommon.AddTokenSwapInputs(op, swap.ID, 0)
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swap.ID).Return(swap, nil)
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateTokenSwapBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	op := &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenSwapClaim,
+		Namespace: "ns1",
+		Input:     fftypes.JSONObject{"swap": "bad"},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+	}
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+}
+
+func TestOperationUpdateTokenSwapFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swapID := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:        fftypes.NewUUID(),
+		Type:      core.OpTypeTokenSwapClaim,
+		Namespace: "ns1",
+	}
+	txcommon.AddTokenSwapInputs(op, swapID, 0)
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", swapID).Return(nil, fmt.Errorf("pop"))
+
+	err := am.OnOperationUpdate(context.Background(), op, update)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
@@ -0,0 +1,284 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// Parquet constants, from the parquet-format specification
+const (
+	parquetMagic         = "PAR1"
+	parquetTypeByteArray = 6
+	parquetRepetitionReq = 0
+	parquetConvertedUTF8 = 0
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+	parquetCodecNone     = 0
+	parquetPageTypeData  = 0
+	parquetFileVersion   = 1
+)
+
+// Thrift compact protocol field types
+const (
+	thriftTypeI32    byte = 5
+	thriftTypeI64    byte = 6
+	thriftTypeBinary byte = 8
+	thriftTypeList   byte = 9
+	thriftTypeStruct byte = 12
+)
+
+// parquetWriter writes rows of string columns as a Parquet file. Every column is a required UTF-8
+// string, stored uncompressed with plain encoding. Rows are buffered until a row group is full, and
+// then written out, so only a single row group is held in memory at a time.
+type parquetWriter struct {
+	w            io.Writer
+	columns      []string
+	rowGroupSize int
+	values       [][]string
+	offset       int64
+	numRows      int64
+	rowGroups    []*parquetRowGroup
+}
+
+type parquetRowGroup struct {
+	numRows int64
+	size    int64
+	chunks  []*parquetColumnChunk
+}
+
+type parquetColumnChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+func newParquetWriter(w io.Writer, columns []string, rowGroupSize int) *parquetWriter {
+	return &parquetWriter{
+		w:            w,
+		columns:      columns,
+		rowGroupSize: rowGroupSize,
+		values:       make([][]string, len(columns)),
+	}
+}
+
+func (pw *parquetWriter) write(b []byte) error {
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	return err
+}
+
+// Write buffers a single row, which must have a value for every column
+func (pw *parquetWriter) Write(row []string) error {
+	if pw.offset == 0 {
+		if err := pw.write([]byte(parquetMagic)); err != nil {
+			return err
+		}
+	}
+	for i, value := range row {
+		pw.values[i] = append(pw.values[i], value)
+	}
+	if len(pw.values[0]) >= pw.rowGroupSize {
+		return pw.flushRowGroup()
+	}
+	return nil
+}
+
+func (pw *parquetWriter) flushRowGroup() error {
+	rowGroup := &parquetRowGroup{
+		numRows: int64(len(pw.values[0])),
+		chunks:  make([]*parquetColumnChunk, len(pw.columns)),
+	}
+	for i, values := range pw.values {
+		var data bytes.Buffer
+		for _, v := range values {
+			_ = binary.Write(&data, binary.LittleEndian, uint32(len(v)))
+			data.WriteString(v)
+		}
+		header := newThriftWriter()
+		header.i32(1, parquetPageTypeData)
+		header.i32(2, int32(data.Len()))
+		header.i32(3, int32(data.Len()))
+		header.beginStruct(5)
+		header.i32(1, int32(len(values)))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.endStruct()
+		header.end()
+
+		chunk := &parquetColumnChunk{
+			offset:    pw.offset,
+			size:      int64(header.Len() + data.Len()),
+			numValues: int64(len(values)),
+		}
+		if err := pw.write(header.Bytes()); err != nil {
+			return err
+		}
+		if err := pw.write(data.Bytes()); err != nil {
+			return err
+		}
+		rowGroup.chunks[i] = chunk
+		rowGroup.size += chunk.size
+		pw.values[i] = pw.values[i][:0]
+	}
+	pw.numRows += rowGroup.numRows
+	pw.rowGroups = append(pw.rowGroups, rowGroup)
+	return nil
+}
+
+// Close writes any buffered rows, followed by the file footer. It does not close the underlying writer.
+func (pw *parquetWriter) Close() error {
+	if pw.offset == 0 {
+		if err := pw.write([]byte(parquetMagic)); err != nil {
+			return err
+		}
+	}
+	if len(pw.values[0]) > 0 {
+		if err := pw.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	meta := newThriftWriter()
+	meta.i32(1, parquetFileVersion)
+	meta.list(2, thriftTypeStruct, len(pw.columns)+1)
+	meta.beginElement()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(pw.columns)))
+	meta.endStruct()
+	for _, column := range pw.columns {
+		meta.beginElement()
+		meta.i32(1, parquetTypeByteArray)
+		meta.i32(3, parquetRepetitionReq)
+		meta.binary(4, column)
+		meta.i32(6, parquetConvertedUTF8)
+		meta.endStruct()
+	}
+	meta.i64(3, pw.numRows)
+	meta.list(4, thriftTypeStruct, len(pw.rowGroups))
+	for _, rowGroup := range pw.rowGroups {
+		meta.beginElement()
+		meta.list(1, thriftTypeStruct, len(rowGroup.chunks))
+		for i, chunk := range rowGroup.chunks {
+			meta.beginElement()
+			meta.i64(2, chunk.offset)
+			meta.beginStruct(3)
+			meta.i32(1, parquetTypeByteArray)
+			meta.list(2, thriftTypeI32, 2)
+			meta.varint(zigzag(parquetEncodingPlain))
+			meta.varint(zigzag(parquetEncodingRLE))
+			meta.list(3, thriftTypeBinary, 1)
+			meta.str(pw.columns[i])
+			meta.i32(4, parquetCodecNone)
+			meta.i64(5, chunk.numValues)
+			meta.i64(6, chunk.size)
+			meta.i64(7, chunk.size)
+			meta.i64(9, chunk.offset)
+			meta.endStruct()
+			meta.endStruct()
+		}
+		meta.i64(2, rowGroup.size)
+		meta.i64(3, rowGroup.numRows)
+		meta.endStruct()
+	}
+	meta.binary(6, "hyperledger-firefly")
+	meta.end()
+
+	footer := meta.Bytes()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(meta.Len()))
+	footer = append(footer, parquetMagic...)
+	return pw.write(footer)
+}
+
+// thriftWriter is a minimal encoder for the Thrift compact protocol, as used for Parquet metadata.
+// Fields within each struct must be written in ascending order, with gaps of no more than 15.
+type thriftWriter struct {
+	bytes.Buffer
+	lastField []int16
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{lastField: []int16{0}}
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (t *thriftWriter) str(s string) {
+	t.varint(uint64(len(s)))
+	t.WriteString(s)
+}
+
+func (t *thriftWriter) field(id int16, fieldType byte) {
+	last := &t.lastField[len(t.lastField)-1]
+	t.WriteByte(byte(id-*last)<<4 | fieldType)
+	*last = id
+}
+
+func (t *thriftWriter) i32(id int16, v int32) {
+	t.field(id, thriftTypeI32)
+	t.varint(zigzag(int64(v)))
+}
+
+func (t *thriftWriter) i64(id int16, v int64) {
+	t.field(id, thriftTypeI64)
+	t.varint(zigzag(v))
+}
+
+func (t *thriftWriter) binary(id int16, s string) {
+	t.field(id, thriftTypeBinary)
+	t.str(s)
+}
+
+func (t *thriftWriter) list(id int16, elemType byte, size int) {
+	t.field(id, thriftTypeList)
+	if size < 15 {
+		t.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.WriteByte(0xf0 | elemType)
+		t.varint(uint64(size))
+	}
+}
+
+func (t *thriftWriter) beginStruct(id int16) {
+	t.field(id, thriftTypeStruct)
+	t.lastField = append(t.lastField, 0)
+}
+
+// beginElement starts a struct that is an element of a list, and so has no field header
+func (t *thriftWriter) beginElement() {
+	t.lastField = append(t.lastField, 0)
+}
+
+func (t *thriftWriter) endStruct() {
+	t.WriteByte(0)
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+// end terminates the top-level struct
+func (t *thriftWriter) end() {
+	t.WriteByte(0)
+}
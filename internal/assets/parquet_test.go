@@ -0,0 +1,152 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// Checked with the parquet-go reader to contain two row groups, with rows [1 x] [2 y] [3 z]
+const testParquetFile = "504152311500151415142c15041500150615060000010000003101000000321500151415142c150415001506150600000100" +
+	"00007801000000791500150a150a2c1502150015061506000001000000331500150a150a2c15021500150615060000010000007a15" +
+	"02193c4806736368656d61150400150c2500180161250000150c25001801622500001606192c192c26081c150c1925000619180161" +
+	"150016041636163626080000263e1c150c19250006191801621500160416361636263e0000166c160400192c26741c150c19250006" +
+	"1918016115001602162c162c2674000026a0011c150c192500061918016215001602162c162c26a00100001658160200281368797065" +
+	"726c65646765722d66697265666c7900b100000050415231"
+
+type failingWriter struct {
+	allowed int
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	if w.allowed == 0 {
+		return 0, fmt.Errorf("pop")
+	}
+	w.allowed--
+	return len(b), nil
+}
+
+// bytesParquetFile is an in-memory source for the independent parquet-go reader
+type bytesParquetFile struct {
+	*bytes.Reader
+}
+
+func (f *bytesParquetFile) Open(name string) (source.ParquetFile, error) {
+	return &bytesParquetFile{bytes.NewReader(f.bytes())}, nil
+}
+
+func (f *bytesParquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, fmt.Errorf("read only")
+}
+
+func (f *bytesParquetFile) Write(b []byte) (int, error) {
+	return 0, fmt.Errorf("read only")
+}
+
+func (f *bytesParquetFile) Close() error {
+	return nil
+}
+
+func (f *bytesParquetFile) bytes() []byte {
+	b := make([]byte, f.Size())
+	_, _ = f.ReadAt(b, 0)
+	return b
+}
+
+func TestParquetWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := newParquetWriter(&buf, []string{"localId", "amount"}, 2)
+	rows := [][]string{{"id1", "10"}, {"id2", ""}, {"id3", "30"}, {"id4", "40"}, {"id5", "50"}}
+	for _, row := range rows {
+		assert.NoError(t, w.Write(row))
+	}
+	assert.NoError(t, w.Close())
+
+	pr, err := reader.NewParquetColumnReader(&bytesParquetFile{bytes.NewReader(buf.Bytes())}, 1)
+	assert.NoError(t, err)
+	defer pr.ReadStop()
+	assert.Equal(t, int64(len(rows)), pr.GetNumRows())
+	assert.Len(t, pr.Footer.RowGroups, 3)
+	for i, name := range []string{"localId", "amount"} {
+		assert.Equal(t, name, pr.SchemaHandler.GetExName(i+1))
+		values, _, _, err := pr.ReadColumnByIndex(int64(i), pr.GetNumRows())
+		assert.NoError(t, err)
+		assert.Len(t, values, len(rows))
+		for r, v := range values {
+			assert.Equal(t, rows[r][i], v)
+		}
+	}
+}
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := newParquetWriter(&buf, []string{"a", "b"}, 2)
+	assert.NoError(t, w.Write([]string{"1", "x"}))
+	assert.NoError(t, w.Write([]string{"2", "y"}))
+	assert.NoError(t, w.Write([]string{"3", "z"}))
+	assert.NoError(t, w.Close())
+	assert.Equal(t, testParquetFile, hex.EncodeToString(buf.Bytes()))
+}
+
+func TestParquetWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	w := newParquetWriter(&buf, []string{"a"}, 2)
+	assert.NoError(t, w.Close())
+	b := buf.Bytes()
+	assert.Equal(t, "PAR1", string(b[0:4]))
+	assert.Equal(t, "PAR1", string(b[len(b)-4:]))
+}
+
+func TestParquetWriterManyColumns(t *testing.T) {
+	// Lists of 15 or more elements have a separate size in the Thrift encoding
+	columns := make([]string, 20)
+	for i := range columns {
+		columns[i] = fmt.Sprintf("c%d", i)
+	}
+	var buf bytes.Buffer
+	w := newParquetWriter(&buf, columns, 2)
+	assert.NoError(t, w.Close())
+	assert.Contains(t, buf.String(), "c19")
+}
+
+func TestParquetWriterFail(t *testing.T) {
+	// Each of the writes to the underlying writer fails in turn: the magic number,
+	// the page header and data for each of the two columns, and the footer
+	for allowed := 0; allowed < 6; allowed++ {
+		w := newParquetWriter(&failingWriter{allowed: allowed}, []string{"a", "b"}, 1)
+		err := w.Write([]string{"1", "x"})
+		if err == nil {
+			err = w.Close()
+		}
+		assert.EqualError(t, err, "pop")
+	}
+
+	// The final row group is written on close
+	w := newParquetWriter(&failingWriter{allowed: 1}, []string{"a"}, 2)
+	assert.NoError(t, w.Write([]string{"1"}))
+	assert.EqualError(t, w.Close(), "pop")
+
+	w = newParquetWriter(&failingWriter{}, []string{"a"}, 2)
+	assert.EqualError(t, w.Close(), "pop")
+}
@@ -18,6 +18,7 @@ package assets
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -210,8 +211,15 @@ func (am *assetManager) validateApproval(ctx context.Context, approval *core.Tok
 	approval.TokenApproval.Pool = pool.ID
 	approval.TokenApproval.Connector = pool.Connector
 
-	if pool.State != core.TokenPoolStateConfirmed {
-		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolNotConfirmed)
+	if approval.Expiry != nil && (!approval.Approved || !approval.Expiry.Time().After(time.Now())) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidApprovalExpiry)
+	}
+
+	// Revoking an existing approval is still permitted while a pool is paused
+	if pool.State != core.TokenPoolStatePaused || approval.Approved {
+		if err = checkTokenPoolActive(ctx, pool); err != nil {
+			return nil, err
+		}
 	}
 	approval.Key, err = am.identity.ResolveInputSigningKey(ctx, approval.Key, am.keyNormalization)
 	return pool, err
@@ -0,0 +1,130 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type approvalExpiryConfig struct {
+	checkInterval time.Duration
+	batchSize     int
+}
+
+func (am *assetManager) approvalExpiryLoop() {
+	defer close(am.expiryLoopDone)
+
+	ticker := time.NewTicker(am.approvalExpiry.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-am.ctx.Done():
+			log.L(am.ctx).Debugf("Approval expiry loop exiting")
+			return
+		case <-ticker.C:
+			if err := am.revokeExpiredApprovals(am.ctx); err != nil {
+				log.L(am.ctx).Errorf("Failed to query expired token approvals: %s", err)
+			}
+		}
+	}
+}
+
+// revokeExpiredApprovals submits revocations for the expired approvals that do not have one yet. Approvals are
+// excluded from the query once their revocation is recorded, and those that could not be revoked are paged past,
+// so every expired approval is reached on each check however many of them fail.
+func (am *assetManager) revokeExpiredApprovals(ctx context.Context) error {
+	now := fftypes.Now()
+	skip := 0
+	for {
+		fb := database.TokenApprovalQueryFactory.NewFilter(ctx)
+		filter := fb.And(
+			fb.Eq("active", true),
+			fb.Eq("approved", true),
+			fb.Lte("expiry", now),
+			fb.Eq("expiryrevocation", nil),
+		).Sort("expiry").Skip(uint64(skip)).Limit(uint64(am.approvalExpiry.batchSize))
+		approvals, _, err := am.database.GetTokenApprovals(ctx, am.namespace, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, approval := range approvals {
+			if err := am.revokeExpiredApproval(ctx, approval); err != nil {
+				// Retried on the next check
+				log.L(ctx).Errorf("Failed to revoke expired token approval '%s': %s", approval.LocalID, err)
+				skip++
+			}
+		}
+		if len(approvals) < am.approvalExpiry.batchSize {
+			return nil
+		}
+	}
+}
+
+// revokeExpiredApproval submits a revocation for an expired approval. The approval remains active with
+// the connector until the revocation is confirmed, so the transaction of the revocation is recorded on the
+// approval to exclude it from later checks, and the idempotency key ensures that only one revocation is
+// submitted for each approval even if recording it fails.
+// If the revocation operation fails, it can be retried via the operations API.
+func (am *assetManager) revokeExpiredApproval(ctx context.Context, expired *core.TokenApproval) error {
+	revocation := &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{
+			Key:      expired.Key,
+			Operator: expired.Operator,
+			Approved: false,
+		},
+		Pool:           expired.Pool.String(),
+		IdempotencyKey: core.IdempotencyKey("approval-expiry:" + expired.LocalID.String()),
+	}
+	err := am.NewApproval(revocation).Send(ctx)
+	if idemErr, alreadySubmitted := err.(*sqlcommon.IdempotencyError); alreadySubmitted {
+		log.L(ctx).Debugf("Revocation already submitted for expired token approval '%s'", expired.LocalID)
+		return am.recordExpiryRevocation(ctx, expired, idemErr.ExistingTXID)
+	}
+	if err != nil {
+		if revocation.TX.ID != nil {
+			// The transaction exists, so the failed operation is retried via the operations API rather than by the next check
+			if recordErr := am.recordExpiryRevocation(ctx, expired, revocation.TX.ID); recordErr != nil {
+				return recordErr
+			}
+		}
+		return err
+	}
+
+	log.L(ctx).Infof("Submitted revocation for expired token approval '%s' tx=%s", expired.LocalID, revocation.TX.ID)
+	return am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := am.recordExpiryRevocation(ctx, expired, revocation.TX.ID); err != nil {
+			return err
+		}
+		event := core.NewEvent(core.EventTypeApprovalExpired, am.namespace, expired.LocalID, revocation.TX.ID, expired.Pool.String())
+		return am.database.InsertEvent(ctx, event)
+	})
+}
+
+func (am *assetManager) recordExpiryRevocation(ctx context.Context, expired *core.TokenApproval, txID *fftypes.UUID) error {
+	fb := database.TokenApprovalQueryFactory.NewFilter(ctx)
+	return am.database.UpdateTokenApprovals(ctx,
+		fb.And(fb.Eq("localid", expired.LocalID)),
+		database.TokenApprovalQueryFactory.NewUpdate(ctx).Set("expiryrevocation", txID))
+}
@@ -0,0 +1,286 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestExpiredApproval() *core.TokenApproval {
+	return &core.TokenApproval{
+		LocalID:  fftypes.NewUUID(),
+		Pool:     fftypes.NewUUID(),
+		Key:      "0x12345",
+		Operator: "0x67890",
+		Approved: true,
+		Active:   true,
+		Expiry:   fftypes.Now(),
+	}
+}
+
+func matchExpiryRevocation(txID *fftypes.UUID) interface{} {
+	return mock.MatchedBy(func(update ffapi.Update) bool {
+		info, err := update.Finalize()
+		if err != nil || len(info.SetOperations) != 1 || info.SetOperations[0].Field != "expiryrevocation" {
+			return false
+		}
+		value, _ := info.SetOperations[0].Value.Value()
+		return value == txID.String()
+	})
+}
+
+func TestStartStopApprovalExpiryLoop(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	am.approvalExpiry.checkInterval = 1 * time.Millisecond
+	am.metadata = newMetadataIndexer(am.ctx, "ns1", am.database)
+
+	checked := make(chan struct{})
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenApprovals", am.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(checked)
+	}).Once()
+	mdi.On("GetTokenApprovals", am.ctx, "ns1", mock.Anything).Return([]*core.TokenApproval{}, nil, nil).Maybe()
+
+	err := am.Start()
+	assert.NoError(t, err)
+	<-checked
+	cancel()
+	am.WaitStop()
+}
+
+func TestRevokeExpiredApprovals(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	expired := newTestExpiredApproval()
+	pool := &core.TokenPool{
+		ID:        expired.Pool,
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return([]*core.TokenApproval{expired}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", expired.Pool).Return(pool, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "0x12345", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, core.IdempotencyKey("approval-expiry:"+expired.LocalID.String())).Return(txID, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(approvalData)
+		return data.Approval.Operator == "0x67890" && !data.Approval.Approved && data.Approval.Expiry == nil
+	})).Return(nil, nil)
+	mdi.On("UpdateTokenApprovals", context.Background(), mock.Anything, matchExpiryRevocation(txID)).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeApprovalExpired &&
+			event.Reference.Equals(expired.LocalID) &&
+			event.Transaction.Equals(txID) &&
+			event.Topic == expired.Pool.String()
+	})).Return(nil)
+
+	err := am.revokeExpiredApprovals(context.Background())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestRevokeExpiredApprovalsQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := am.revokeExpiredApprovals(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRevokeExpiredApprovalsSubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.approvalExpiry.batchSize = 2
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", matchSkip(0)).Return([]*core.TokenApproval{newTestExpiredApproval(), newTestExpiredApproval()}, nil, nil).Once()
+	mdi.On("GetTokenApprovals", context.Background(), "ns1", matchSkip(2)).Return([]*core.TokenApproval{newTestExpiredApproval()}, nil, nil).Once()
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	// Failures are logged and retried on the next check, and are paged past so they cannot hold up later expirations
+	err := am.revokeExpiredApprovals(context.Background())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func matchSkip(skip uint64) interface{} {
+	return mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, err := filter.Finalize()
+		return err == nil && info.Skip == skip
+	})
+}
+
+func TestRevokeExpiredApprovalOperationFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	expired := newTestExpiredApproval()
+	pool := &core.TokenPool{
+		ID:        expired.Pool,
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", expired.Pool).Return(pool, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "0x12345", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, mock.Anything).Return(txID, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything).Return(nil, fmt.Errorf("pop"))
+	mdi.On("UpdateTokenApprovals", context.Background(), mock.Anything, matchExpiryRevocation(txID)).Return(nil)
+
+	// The revocation is recorded, as the failed operation is retried via the operations API
+	err := am.revokeExpiredApproval(context.Background(), expired)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestRevokeExpiredApprovalOperationFailRecordFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	expired := newTestExpiredApproval()
+	pool := &core.TokenPool{
+		ID:        expired.Pool,
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", expired.Pool).Return(pool, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "0x12345", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, mock.Anything).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything).Return(nil, fmt.Errorf("pop"))
+	mdi.On("UpdateTokenApprovals", context.Background(), mock.Anything, mock.Anything).Return(fmt.Errorf("pop2"))
+
+	err := am.revokeExpiredApproval(context.Background(), expired)
+	assert.EqualError(t, err, "pop2")
+}
+
+func TestRevokeExpiredApprovalAlreadySubmitted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	expired := newTestExpiredApproval()
+	existingTX := fftypes.NewUUID()
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenApproval, mock.Anything).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  existingTX,
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(nil, nil)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("UpdateTokenApprovals", context.Background(), mock.Anything, matchExpiryRevocation(existingTX)).Return(nil)
+
+	// The existing revocation is recorded, so the approval is not checked again
+	err := am.revokeExpiredApproval(context.Background(), expired)
+	assert.NoError(t, err)
+	mdi.AssertExpectations(t)
+
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestApprovalExpiryInvalid(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+
+	past := fftypes.FFTime(time.Now().Add(-1 * time.Minute))
+	_, err := am.validateApproval(context.Background(), &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{Approved: true, Expiry: &past},
+		Pool:          "pool1",
+	})
+	assert.Regexp(t, "FF10481", err)
+
+	future := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	_, err = am.validateApproval(context.Background(), &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{Approved: false, Expiry: &future},
+		Pool:          "pool1",
+	})
+	assert.Regexp(t, "FF10481", err)
+}
+
+func TestApprovalExpiryValid(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "key", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	future := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	_, err := am.validateApproval(context.Background(), &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{Approved: true, Key: "key", Expiry: &future},
+		Pool:          "pool1",
+	})
+	assert.NoError(t, err)
+}
@@ -1039,3 +1039,35 @@ func TestApprovalPrepare(t *testing.T) {
 	mim.AssertExpectations(t)
 	mth.AssertExpectations(t)
 }
+
+func TestApprovalPausedPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		Name:      "pool1",
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStatePaused,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "key", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	// New approvals are rejected
+	_, err := am.validateApproval(context.Background(), &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{Approved: true, Key: "key"},
+		Pool:          "pool1",
+	})
+	assert.Regexp(t, "FF10484", err)
+
+	// Revocations are still permitted
+	_, err = am.validateApproval(context.Background(), &core.TokenApprovalInput{
+		TokenApproval: core.TokenApproval{Approved: false, Key: "key"},
+		Pool:          "pool1",
+	})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
@@ -0,0 +1,368 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type bulkMintConfig struct {
+	batchSize     int
+	maxBatchSize  int
+	maxItems      int
+	batchInterval time.Duration
+	maxInFlight   int
+}
+
+// MintTokensBulk validates a bulk mint and records it, along with the parent operation that tracks its progress.
+// Nothing is submitted to the connector by this call. The bulk mint loop submits one batch of each bulk mint on
+// every interval, and because the progress is stored, it carries on where it left off after a restart.
+func (am *assetManager) MintTokensBulk(ctx context.Context, input *core.TokenBulkMintInput) (*core.TokenBulkMint, error) {
+	if len(input.Items) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenBulkMintEmpty)
+	}
+	if len(input.Items) > am.bulkMint.maxItems {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenBulkMintTooLarge, len(input.Items), am.bulkMint.maxItems)
+	}
+	if input.BatchSize == 0 {
+		input.BatchSize = am.bulkMint.batchSize
+	} else if input.BatchSize > am.bulkMint.maxBatchSize {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenBulkMintBatchSizeTooLarge, input.BatchSize, am.bulkMint.maxBatchSize)
+	}
+
+	// Validate the accounts and pool policy once, as they are shared by every mint
+	template := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Type:   core.TokenTransferTypeMint,
+			Key:    input.Key,
+			To:     input.To,
+			Amount: *fftypes.NewFFBigInt(1),
+		},
+		Pool: input.Pool,
+	}
+	pool, err := am.validateTransfer(ctx, template)
+	if err != nil {
+		return nil, err
+	}
+	if am.requiresSignoff(pool, fftypes.NewFFBigInt(1)) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenBulkMintRequiresSignoff, am.signoff.thresholdStr)
+	}
+	if pool.Type != core.TokenTypeNonFungible {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolNotNonFungible, pool.Name)
+	}
+	plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
+	if err != nil {
+		return nil, err
+	}
+
+	txid, err := am.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTokenTransfer, input.IdempotencyKey)
+	if err != nil {
+		if idemErr, ok := err.(*sqlcommon.IdempotencyError); ok {
+			if existing, lookupErr := am.getTokenBulkMintByTX(ctx, idemErr.ExistingTXID); lookupErr != nil || existing != nil {
+				return existing, lookupErr
+			}
+		}
+		return nil, err
+	}
+
+	bulkMint := &core.TokenBulkMint{
+		ID:        fftypes.NewUUID(),
+		Namespace: am.namespace,
+		Pool:      pool.ID,
+		Connector: pool.Connector,
+		State:     core.TokenBulkMintStatePending,
+		Key:       template.Key,
+		To:        template.To,
+		Items:     input.Items,
+		BatchSize: input.BatchSize,
+		Total:     len(input.Items),
+		Config:    input.Config,
+		TX: core.TransactionRef{
+			ID:   txid,
+			Type: core.TransactionTypeTokenTransfer,
+		},
+	}
+
+	// The parent operation is never run - it moves straight to pending, and is resolved by the bulk mint loop
+	op := core.NewOperation(
+		plugin,
+		am.namespace,
+		txid,
+		core.OpTypeTokenBulkMint)
+	op.Status = core.OpStatusPending
+	op.Output = bulkMintProgress(bulkMint)
+	txcommon.AddTokenBulkMintInputs(op, bulkMint.ID)
+	bulkMint.Operation = op.ID
+
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := am.database.InsertTokenBulkMint(ctx, bulkMint); err != nil {
+			return err
+		}
+		return am.operations.AddOrReuseOperation(ctx, op)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bulkMint, nil
+}
+
+func (am *assetManager) GetTokenBulkMints(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBulkMint, *ffapi.FilterResult, error) {
+	return am.database.GetTokenBulkMints(ctx, am.namespace, filter)
+}
+
+func (am *assetManager) GetTokenBulkMintByID(ctx context.Context, id string) (*core.TokenBulkMint, error) {
+	bulkMintID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return am.database.GetTokenBulkMintByID(ctx, am.namespace, bulkMintID)
+}
+
+func (am *assetManager) getTokenBulkMintByTX(ctx context.Context, txid *fftypes.UUID) (*core.TokenBulkMint, error) {
+	fb := database.TokenBulkMintQueryFactory.NewFilter(ctx)
+	bulkMints, _, err := am.database.GetTokenBulkMints(ctx, am.namespace, fb.And(fb.Eq("tx.id", txid)).Limit(1))
+	if err != nil || len(bulkMints) == 0 {
+		return nil, err
+	}
+	return bulkMints[0], nil
+}
+
+func bulkMintProgress(bulkMint *core.TokenBulkMint) fftypes.JSONObject {
+	return fftypes.JSONObject{
+		"total":     bulkMint.Total,
+		"submitted": bulkMint.Submitted,
+		"succeeded": bulkMint.Succeeded,
+		"failed":    bulkMint.Failed,
+	}
+}
+
+func (am *assetManager) bulkMintLoop() {
+	defer close(am.bulkMintLoopDone)
+
+	ticker := time.NewTicker(am.bulkMint.batchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-am.ctx.Done():
+			log.L(am.ctx).Debugf("Bulk mint loop exiting")
+			return
+		case <-ticker.C:
+			if err := am.advanceBulkMints(am.ctx); err != nil {
+				log.L(am.ctx).Errorf("Failed to query in-flight bulk mints: %s", err)
+			}
+		}
+	}
+}
+
+func (am *assetManager) advanceBulkMints(ctx context.Context) error {
+	fb := database.TokenBulkMintQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.In("state", []driver.Value{
+			core.TokenBulkMintStatePending,
+			core.TokenBulkMintStateSubmitted,
+		}),
+	).Sort("updated").Limit(uint64(am.bulkMint.maxInFlight))
+	bulkMints, _, err := am.database.GetTokenBulkMints(ctx, am.namespace, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, bulkMint := range bulkMints {
+		if err := am.advanceBulkMint(ctx, bulkMint); err != nil {
+			// Retried on the next interval
+			log.L(ctx).Errorf("Failed to advance bulk mint '%s': %s", bulkMint.ID, err)
+		}
+	}
+	return nil
+}
+
+func (am *assetManager) advanceBulkMint(ctx context.Context, bulkMint *core.TokenBulkMint) error {
+	// Any mint that was recorded, but not submitted before a restart, is submitted first
+	if _, err := am.operations.ResubmitOperations(ctx, bulkMint.TX.ID); err != nil {
+		return err
+	}
+	submitted := bulkMint.State == core.TokenBulkMintStatePending
+	if submitted {
+		if err := am.submitBulkMintBatch(ctx, bulkMint); err != nil {
+			return err
+		}
+	}
+	return am.updateBulkMintProgress(ctx, bulkMint, submitted)
+}
+
+// submitBulkMintBatch records the mint operations for the next batch, along with the new progress, in a single
+// database transaction - and then submits each of them to the connector. A mint that fails to submit is marked
+// failed by the operations manager, and can be retried individually via the operations API.
+func (am *assetManager) submitBulkMintBatch(ctx context.Context, bulkMint *core.TokenBulkMint) error {
+	pool, err := am.GetTokenPoolByID(ctx, bulkMint.Pool)
+	if err != nil {
+		return err
+	} else if pool == nil {
+		return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	// A paused pool holds the remaining batches, until it is resumed
+	if err = checkTokenPoolActive(ctx, pool); err != nil {
+		return err
+	}
+	plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
+	if err != nil {
+		return err
+	}
+
+	end := bulkMint.Submitted + bulkMint.BatchSize
+	if end > bulkMint.Total {
+		end = bulkMint.Total
+	}
+	state := bulkMint.State
+	if end == bulkMint.Total {
+		state = core.TokenBulkMintStateSubmitted
+	}
+
+	batch := bulkMint.Items[bulkMint.Submitted:end]
+	transfers := make([]*core.TokenTransfer, len(batch))
+	ops := make([]*core.Operation, len(batch))
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		for i, item := range batch {
+			transfers[i] = &core.TokenTransfer{
+				Type:       core.TokenTransferTypeMint,
+				LocalID:    fftypes.NewUUID(),
+				Pool:       pool.ID,
+				Connector:  pool.Connector,
+				TokenIndex: item.TokenIndex,
+				URI:        item.URI,
+				Amount:     *fftypes.NewFFBigInt(1),
+				Key:        bulkMint.Key,
+				From:       bulkMint.Key,
+				To:         bulkMint.To,
+				Config:     bulkMint.Config,
+				TX:         bulkMint.TX,
+			}
+			ops[i] = core.NewOperation(
+				plugin,
+				am.namespace,
+				bulkMint.TX.ID,
+				core.OpTypeTokenTransfer)
+			err := txcommon.AddTokenTransferInputs(ops[i], transfers[i])
+			if err == nil {
+				err = am.operations.AddOrReuseOperation(ctx, ops[i])
+			}
+			if err != nil {
+				return err
+			}
+		}
+		update := database.TokenBulkMintQueryFactory.NewUpdate(ctx).
+			Set("submitted", end).
+			Set("state", state)
+		return am.database.UpdateTokenBulkMint(ctx, am.namespace, bulkMint.ID, update)
+	})
+	if err != nil {
+		return err
+	}
+	log.L(ctx).Infof("Submitting mints %d-%d of %d for bulk mint '%s'", bulkMint.Submitted+1, end, bulkMint.Total, bulkMint.ID)
+	bulkMint.Submitted = end
+	bulkMint.State = state
+
+	for i, op := range ops {
+		if am.metrics.IsMetricsEnabled() {
+			am.metrics.TransferSubmitted(transfers[i])
+		}
+		if _, err := am.operations.RunOperation(ctx, opTransfer(op, pool, transfers[i])); err != nil {
+			log.L(ctx).Errorf("Failed to submit mint %s for bulk mint '%s': %s", op.ID, bulkMint.ID, err)
+		}
+	}
+	return nil
+}
+
+func (am *assetManager) countBulkMintOperations(ctx context.Context, bulkMint *core.TokenBulkMint, status core.OpStatus) (int, error) {
+	// Operations that have been retried are not counted, as the outcome is that of the retry
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("tx", bulkMint.TX.ID),
+		fb.Eq("type", core.OpTypeTokenTransfer),
+		fb.Eq("status", status),
+		fb.Eq("retry", nil),
+	).Count(true).Limit(1)
+	_, fr, err := am.database.GetOperations(ctx, am.namespace, filter)
+	if err != nil {
+		return 0, err
+	}
+	return int(*fr.TotalCount), nil
+}
+
+// updateBulkMintProgress counts the mints that have succeeded and failed so far, and records the progress on both the
+// bulk mint and its parent operation. Once the outcome of every mint is known, the parent operation is resolved.
+func (am *assetManager) updateBulkMintProgress(ctx context.Context, bulkMint *core.TokenBulkMint, submitted bool) (err error) {
+	succeeded, err := am.countBulkMintOperations(ctx, bulkMint, core.OpStatusSucceeded)
+	if err != nil {
+		return err
+	}
+	failed, err := am.countBulkMintOperations(ctx, bulkMint, core.OpStatusFailed)
+	if err != nil {
+		return err
+	}
+
+	state := bulkMint.State
+	opUpdate := &core.OperationUpdateDTO{Status: core.OpStatusPending}
+	if state == core.TokenBulkMintStateSubmitted && succeeded+failed >= bulkMint.Total {
+		if failed > 0 {
+			state = core.TokenBulkMintStateFailed
+			errMsg := i18n.NewError(ctx, coremsgs.MsgTokenBulkMintFailed, failed, bulkMint.Total).Error()
+			opUpdate.Status = core.OpStatusFailed
+			opUpdate.Error = &errMsg
+		} else {
+			state = core.TokenBulkMintStateCompleted
+			opUpdate.Status = core.OpStatusSucceeded
+		}
+	}
+	if !submitted && succeeded == bulkMint.Succeeded && failed == bulkMint.Failed && state == bulkMint.State {
+		return nil
+	}
+
+	bulkMint.Succeeded = succeeded
+	bulkMint.Failed = failed
+	opUpdate.Output = bulkMintProgress(bulkMint)
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		update := database.TokenBulkMintQueryFactory.NewUpdate(ctx).
+			Set("succeeded", succeeded).
+			Set("failed", failed).
+			Set("state", state)
+		if err := am.database.UpdateTokenBulkMint(ctx, am.namespace, bulkMint.ID, update); err != nil {
+			return err
+		}
+		return am.operations.ResolveOperationByID(ctx, bulkMint.Operation, opUpdate)
+	})
+	if err != nil {
+		return err
+	}
+	if state != bulkMint.State {
+		log.L(ctx).Infof("Bulk mint '%s' moved from %s to %s (succeeded=%d failed=%d)", bulkMint.ID, bulkMint.State, state, succeeded, failed)
+		bulkMint.State = state
+	}
+	return nil
+}
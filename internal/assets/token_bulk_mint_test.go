@@ -0,0 +1,664 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBulkMintInput() *core.TokenBulkMintInput {
+	return &core.TokenBulkMintInput{
+		Pool: "pool1",
+		To:   "0x23456",
+		Items: []*core.TokenBulkMintItem{
+			{TokenIndex: "1", URI: "ipfs://drop/1"},
+			{TokenIndex: "2", URI: "ipfs://drop/2"},
+			{TokenIndex: "3", URI: "ipfs://drop/3"},
+		},
+		IdempotencyKey: "idem1",
+	}
+}
+
+func newTestBulkMint(state core.TokenBulkMintState, submitted int) *core.TokenBulkMint {
+	return &core.TokenBulkMint{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Pool:      fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     state,
+		Key:       "0x12345",
+		To:        "0x23456",
+		Items: core.TokenBulkMintItems{
+			{TokenIndex: "1", URI: "ipfs://drop/1"},
+			{TokenIndex: "2", URI: "ipfs://drop/2"},
+			{TokenIndex: "3", URI: "ipfs://drop/3"},
+		},
+		BatchSize: 2,
+		Total:     3,
+		Submitted: submitted,
+		TX: core.TransactionRef{
+			ID:   fftypes.NewUUID(),
+			Type: core.TransactionTypeTokenTransfer,
+		},
+		Operation: fftypes.NewUUID(),
+	}
+}
+
+func mockBulkMintCounts(mdi *databasemocks.Plugin, succeeded, failed int64) {
+	mdi.On("GetOperations", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		info, _ := f.Finalize()
+		return strings.Contains(info.String(), "Succeeded")
+	})).Return([]*core.Operation{}, &ffapi.FilterResult{TotalCount: &succeeded}, nil)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		info, _ := f.Finalize()
+		return strings.Contains(info.String(), "Failed")
+	})).Return([]*core.Operation{}, &ffapi.FilterResult{TotalCount: &failed}, nil)
+}
+
+func TestMintTokensBulkSuccess(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestBulkMintInput()
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txID, nil)
+	mdi.On("InsertTokenBulkMint", context.Background(), mock.MatchedBy(func(bulkMint *core.TokenBulkMint) bool {
+		return bulkMint.State == core.TokenBulkMintStatePending &&
+			bulkMint.Total == 3 &&
+			bulkMint.BatchSize == 50 &&
+			bulkMint.Key == "0x12345" &&
+			bulkMint.To == "0x23456" &&
+			bulkMint.TX.ID.Equals(txID)
+	})).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTokenBulkMint &&
+			op.Status == core.OpStatusPending &&
+			op.Transaction.Equals(txID) &&
+			op.Output.GetInt64("total") == 3
+	})).Return(nil)
+
+	bulkMint, err := am.MintTokensBulk(context.Background(), input)
+	assert.NoError(t, err)
+	assert.Equal(t, pool.ID, bulkMint.Pool)
+	assert.NotNil(t, bulkMint.Operation)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMintTokensBulkEmpty(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.MintTokensBulk(context.Background(), &core.TokenBulkMintInput{})
+	assert.Regexp(t, "FF10516", err)
+}
+
+func TestMintTokensBulkTooLarge(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	am.bulkMint.maxItems = 2
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.Regexp(t, "FF10517", err)
+}
+
+func TestMintTokensBulkBatchSizeTooLarge(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestBulkMintInput()
+	input.BatchSize = 1000
+	_, err := am.MintTokensBulk(context.Background(), input)
+	assert.Regexp(t, "FF10518", err)
+}
+
+func TestMintTokensBulkRequiresSignoff(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	am.signoff.threshold = new(big.Rat)
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.Regexp(t, "FF10519", err)
+}
+
+func TestMintTokensBulkPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestMintTokensBulkFungible(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.Type = core.TokenTypeFungible
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.Regexp(t, "FF10515", err)
+}
+
+func TestMintTokensBulkBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.Connector = "bad"
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestMintTokensBulkSubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestMintTokensBulkAlreadySubmitted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	existing := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  existing.TX.ID,
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mdi.On("GetTokenBulkMints", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBulkMint{existing}, nil, nil)
+
+	bulkMint, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.NoError(t, err)
+	assert.Equal(t, existing, bulkMint)
+}
+
+func TestMintTokensBulkAlreadySubmittedNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  fftypes.NewUUID(),
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mdi.On("GetTokenBulkMints", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBulkMint{}, nil, nil)
+
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestMintTokensBulkInsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mdi.On("InsertTokenBulkMint", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.MintTokensBulk(context.Background(), newTestBulkMintInput())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenBulkMints(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	fb := database.TokenBulkMintQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenBulkMints", context.Background(), "ns1", f).Return([]*core.TokenBulkMint{}, nil, nil)
+
+	_, _, err := am.GetTokenBulkMints(context.Background(), f)
+	assert.NoError(t, err)
+}
+
+func TestGetTokenBulkMintByID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	id := fftypes.NewUUID()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenBulkMintByID", context.Background(), "ns1", id).Return(&core.TokenBulkMint{}, nil)
+
+	_, err := am.GetTokenBulkMintByID(context.Background(), id.String())
+	assert.NoError(t, err)
+}
+
+func TestGetTokenBulkMintByIDBadID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.GetTokenBulkMintByID(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetTokenBulkMintByTXFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenBulkMints", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.getTokenBulkMintByTX(context.Background(), fftypes.NewUUID())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestStartStopBulkMintLoop(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	am.bulkMint.batchInterval = 1 * time.Millisecond
+
+	checked := make(chan struct{})
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenApprovals", am.ctx, "ns1", mock.Anything).Return([]*core.TokenApproval{}, nil, nil).Maybe()
+	mdi.On("GetTokenSwaps", am.ctx, "ns1", mock.Anything).Return([]*core.TokenSwap{}, nil, nil).Maybe()
+	mdi.On("GetTokenBulkMints", am.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(checked)
+	}).Once()
+	mdi.On("GetTokenBulkMints", am.ctx, "ns1", mock.Anything).Return([]*core.TokenBulkMint{}, nil, nil).Maybe()
+
+	err := am.Start()
+	assert.NoError(t, err)
+	<-checked
+	cancel()
+	am.WaitStop()
+}
+
+func TestAdvanceBulkMintsError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenBulkMints", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBulkMint{bulkMint}, nil, nil)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, fmt.Errorf("pop"))
+
+	// Failures are logged, and retried on the next interval
+	err := am.advanceBulkMints(context.Background())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceBulkMintSubmitBatch(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.ID = bulkMint.Pool
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTokenTransfer && op.Transaction.Equals(bulkMint.TX.ID)
+	})).Return(nil).Twice()
+	mdi.On("UpdateTokenBulkMint", context.Background(), "ns1", bulkMint.ID, mock.MatchedBy(func(u ffapi.Update) bool {
+		info, _ := u.Finalize()
+		return strings.Contains(info.String(), "submitted=2")
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return data.Transfer.TokenIndex == "1" && data.Transfer.URI == "ipfs://drop/1" &&
+			data.Transfer.Type == core.TokenTransferTypeMint && data.Transfer.To == "0x23456" && data.Pool == pool
+	})).Return(nil, nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		return op.Data.(transferData).Transfer.TokenIndex == "2"
+	})).Return(nil, fmt.Errorf("pop"))
+	mockBulkMintCounts(mdi, 1, 1)
+	mdi.On("UpdateTokenBulkMint", context.Background(), "ns1", bulkMint.ID, mock.MatchedBy(func(u ffapi.Update) bool {
+		info, _ := u.Finalize()
+		return strings.Contains(info.String(), "succeeded=1")
+	})).Return(nil)
+	mom.On("ResolveOperationByID", context.Background(), bulkMint.Operation, mock.MatchedBy(func(update *core.OperationUpdateDTO) bool {
+		return update.Status == core.OpStatusPending && update.Output.GetInt64("submitted") == 2 && update.Output.GetInt64("failed") == 1
+	})).Return(nil)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenBulkMintStatePending, bulkMint.State)
+	assert.Equal(t, 2, bulkMint.Submitted)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceBulkMintSubmitLastBatch(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 2)
+	bulkMint.Succeeded = 2
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.ID = bulkMint.Pool
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil).Once()
+	mdi.On("UpdateTokenBulkMint", context.Background(), "ns1", bulkMint.ID, mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		return op.Data.(transferData).Transfer.TokenIndex == "3"
+	})).Return(nil, nil)
+	mockBulkMintCounts(mdi, 2, 0)
+	mom.On("ResolveOperationByID", context.Background(), bulkMint.Operation, mock.MatchedBy(func(update *core.OperationUpdateDTO) bool {
+		return update.Status == core.OpStatusPending && update.Output.GetInt64("submitted") == 3
+	})).Return(nil)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenBulkMintStateSubmitted, bulkMint.State)
+	assert.Equal(t, 3, bulkMint.Submitted)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceBulkMintCompleted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStateSubmitted, 3)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mockBulkMintCounts(mdi, 3, 0)
+	mdi.On("UpdateTokenBulkMint", context.Background(), "ns1", bulkMint.ID, mock.Anything).Return(nil)
+	mom.On("ResolveOperationByID", context.Background(), bulkMint.Operation, mock.MatchedBy(func(update *core.OperationUpdateDTO) bool {
+		return update.Status == core.OpStatusSucceeded && update.Error == nil && update.Output.GetInt64("succeeded") == 3
+	})).Return(nil)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenBulkMintStateCompleted, bulkMint.State)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceBulkMintFailed(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStateSubmitted, 3)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mockBulkMintCounts(mdi, 2, 1)
+	mdi.On("UpdateTokenBulkMint", context.Background(), "ns1", bulkMint.ID, mock.Anything).Return(nil)
+	mom.On("ResolveOperationByID", context.Background(), bulkMint.Operation, mock.MatchedBy(func(update *core.OperationUpdateDTO) bool {
+		return update.Status == core.OpStatusFailed && strings.Contains(*update.Error, "FF10520")
+	})).Return(nil)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenBulkMintStateFailed, bulkMint.State)
+	assert.Equal(t, 1, bulkMint.Failed)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceBulkMintNoProgress(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStateSubmitted, 3)
+	bulkMint.Succeeded = 1
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mockBulkMintCounts(mdi, 1, 0)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenBulkMintStateSubmitted, bulkMint.State)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceBulkMintPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", bulkMint.Pool).Return(nil, fmt.Errorf("pop"))
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestAdvanceBulkMintPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", bulkMint.Pool).Return(nil, nil)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestAdvanceBulkMintPoolPaused(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.ID = bulkMint.Pool
+	pool.State = core.TokenPoolStatePaused
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.Regexp(t, "FF10484", err)
+	assert.Equal(t, 0, bulkMint.Submitted)
+}
+
+func TestAdvanceBulkMintBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.ID = bulkMint.Pool
+	pool.Connector = "bad"
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestAdvanceBulkMintAddOperationFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.ID = bulkMint.Pool
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, 0, bulkMint.Submitted)
+}
+
+func TestAdvanceBulkMintUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStatePending, 0)
+	pool := newTestPool(func(p *core.TokenPool) { p.Type = core.TokenTypeNonFungible })
+	pool.ID = bulkMint.Pool
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mdi.On("UpdateTokenBulkMint", context.Background(), "ns1", bulkMint.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, 0, bulkMint.Submitted)
+}
+
+func TestAdvanceBulkMintCountSucceededFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStateSubmitted, 3)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestAdvanceBulkMintCountFailedFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStateSubmitted, 3)
+	succeeded := int64(3)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{}, &ffapi.FilterResult{TotalCount: &succeeded}, nil).Once()
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestAdvanceBulkMintProgressUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	bulkMint := newTestBulkMint(core.TokenBulkMintStateSubmitted, 3)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), bulkMint.TX.ID).Return(nil, nil)
+	mockBulkMintCounts(mdi, 3, 0)
+	mdi.On("UpdateTokenBulkMint", context.Background(), "ns1", bulkMint.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := am.advanceBulkMint(context.Background(), bulkMint)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenBulkMintStateSubmitted, bulkMint.State)
+}
@@ -0,0 +1,248 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+type circuitBreakerConfig struct {
+	failureThreshold int
+	probeInterval    time.Duration
+}
+
+// connectorBreaker wraps the REST calls to a single token connector. After a run of consecutive failures,
+// new requests are failed fast rather than queueing up against a connector that is down. Once the probe
+// interval has passed a single request is let through, and its outcome decides whether the breaker closes
+// again or stays open for another interval.
+type connectorBreaker struct {
+	tokens.Plugin
+	name        string
+	conf        *circuitBreakerConfig
+	mux         sync.Mutex
+	state       core.TokenConnectorBreakerState
+	failures    int
+	lastError   string
+	lastFailure *fftypes.FFTime
+	nextProbe   time.Time
+}
+
+func newConnectorBreaker(name string, plugin tokens.Plugin, conf *circuitBreakerConfig) *connectorBreaker {
+	return &connectorBreaker{
+		Plugin: plugin,
+		name:   name,
+		conf:   conf,
+		state:  core.TokenConnectorBreakerStateClosed,
+	}
+}
+
+func (cb *connectorBreaker) status() *core.TokenConnectorBreaker {
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	status := &core.TokenConnectorBreaker{
+		State:               cb.state,
+		ConsecutiveFailures: cb.failures,
+		LastError:           cb.lastError,
+		LastFailure:         cb.lastFailure,
+	}
+	if cb.state == core.TokenConnectorBreakerStateOpen {
+		nextProbe := fftypes.FFTime(cb.nextProbe)
+		status.NextProbe = &nextProbe
+	}
+	return status
+}
+
+func (cb *connectorBreaker) allow(ctx context.Context) error {
+	if cb.conf.failureThreshold <= 0 {
+		return nil
+	}
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	switch cb.state {
+	case core.TokenConnectorBreakerStateOpen:
+		if time.Now().Before(cb.nextProbe) {
+			return i18n.NewError(ctx, coremsgs.MsgTokenConnectorUnavailable, cb.name, cb.failures)
+		}
+		log.L(ctx).Infof("Probing token connector '%s' for recovery", cb.name)
+		cb.state = core.TokenConnectorBreakerStateHalfOpen
+		return nil
+	case core.TokenConnectorBreakerStateHalfOpen:
+		// Only the probe request is let through, until it completes
+		return i18n.NewError(ctx, coremsgs.MsgTokenConnectorUnavailable, cb.name, cb.failures)
+	default:
+		return nil
+	}
+}
+
+// record notes the outcome of a call to the connector. Only failing to reach the connector, or a 5xx response
+// from it, counts towards tripping the breaker. A request rejected by the connector itself, such as a transfer
+// failing compliance checks or any other 4xx response, shows that the connector is healthy.
+func (cb *connectorBreaker) record(ctx context.Context, err error) {
+	var opFailure *core.OperationFailure
+	var errRes *tokens.ErrorResponse
+	if err != nil && (errors.As(err, &opFailure) || (errors.As(err, &errRes) && errRes.StatusCode < 500)) {
+		err = nil
+	}
+	cb.mux.Lock()
+	defer cb.mux.Unlock()
+	if err == nil {
+		if cb.state != core.TokenConnectorBreakerStateClosed {
+			log.L(ctx).Infof("Token connector '%s' has recovered", cb.name)
+		}
+		cb.state = core.TokenConnectorBreakerStateClosed
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	cb.lastError = err.Error()
+	cb.lastFailure = fftypes.Now()
+	if cb.conf.failureThreshold > 0 && (cb.state == core.TokenConnectorBreakerStateHalfOpen || cb.failures >= cb.conf.failureThreshold) {
+		if cb.state == core.TokenConnectorBreakerStateClosed {
+			log.L(ctx).Errorf("Token connector '%s' is unavailable after %d consecutive failures: %s", cb.name, cb.failures, err)
+		}
+		cb.state = core.TokenConnectorBreakerStateOpen
+		cb.nextProbe = time.Now().Add(cb.conf.probeInterval)
+	}
+}
+
+func (cb *connectorBreaker) call(ctx context.Context, fn func() error) error {
+	if err := cb.allow(ctx); err != nil {
+		return err
+	}
+	err := fn()
+	cb.record(ctx, err)
+	return err
+}
+
+func (cb *connectorBreaker) CreateTokenPool(ctx context.Context, nsOpID string, pool *core.TokenPool) (complete bool, err error) {
+	err = cb.call(ctx, func() (err error) {
+		complete, err = cb.Plugin.CreateTokenPool(ctx, nsOpID, pool)
+		return err
+	})
+	return complete, err
+}
+
+func (cb *connectorBreaker) ActivateTokenPool(ctx context.Context, pool *core.TokenPool) (complete bool, err error) {
+	err = cb.call(ctx, func() (err error) {
+		complete, err = cb.Plugin.ActivateTokenPool(ctx, pool)
+		return err
+	})
+	return complete, err
+}
+
+func (cb *connectorBreaker) DeactivateTokenPool(ctx context.Context, pool *core.TokenPool) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.DeactivateTokenPool(ctx, pool)
+	})
+}
+
+func (cb *connectorBreaker) CheckInterface(ctx context.Context, pool *core.TokenPool, methods []*fftypes.FFIMethod) (result *fftypes.JSONAny, err error) {
+	err = cb.call(ctx, func() (err error) {
+		result, err = cb.Plugin.CheckInterface(ctx, pool, methods)
+		return err
+	})
+	return result, err
+}
+
+func (cb *connectorBreaker) MintTokens(ctx context.Context, nsOpID string, poolLocator string, mint *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.MintTokens(ctx, nsOpID, poolLocator, mint, methods)
+	})
+}
+
+func (cb *connectorBreaker) BurnTokens(ctx context.Context, nsOpID string, poolLocator string, burn *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.BurnTokens(ctx, nsOpID, poolLocator, burn, methods)
+	})
+}
+
+func (cb *connectorBreaker) TransferTokens(ctx context.Context, nsOpID string, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.TransferTokens(ctx, nsOpID, poolLocator, transfer, methods)
+	})
+}
+
+func (cb *connectorBreaker) TransferTokensBatch(ctx context.Context, nsOpID string, poolLocator string, transfers []*core.TokenTransfer, methods *fftypes.JSONAny) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.TransferTokensBatch(ctx, nsOpID, poolLocator, transfers, methods)
+	})
+}
+
+func (cb *connectorBreaker) CheckTransfer(ctx context.Context, poolLocator string, transfer *core.TokenTransfer) (result *core.TokenTransferEligibility, err error) {
+	err = cb.call(ctx, func() (err error) {
+		result, err = cb.Plugin.CheckTransfer(ctx, poolLocator, transfer)
+		return err
+	})
+	return result, err
+}
+
+func (cb *connectorBreaker) GetBalance(ctx context.Context, poolLocator, tokenIndex, account string) (balance *fftypes.FFBigInt, err error) {
+	err = cb.call(ctx, func() (err error) {
+		balance, err = cb.Plugin.GetBalance(ctx, poolLocator, tokenIndex, account)
+		return err
+	})
+	return balance, err
+}
+
+func (cb *connectorBreaker) GetTokenFees(ctx context.Context, poolLocator, tokenIndex string) (fees *core.TokenFees, err error) {
+	err = cb.call(ctx, func() (err error) {
+		fees, err = cb.Plugin.GetTokenFees(ctx, poolLocator, tokenIndex)
+		return err
+	})
+	return fees, err
+}
+
+func (cb *connectorBreaker) LockTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.LockTokens(ctx, nsOpID, poolLocator, swap, leg)
+	})
+}
+
+func (cb *connectorBreaker) ClaimTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.ClaimTokens(ctx, nsOpID, poolLocator, swap, leg)
+	})
+}
+
+func (cb *connectorBreaker) RefundTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.RefundTokens(ctx, nsOpID, poolLocator, swap, leg)
+	})
+}
+
+func (cb *connectorBreaker) TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error {
+	return cb.call(ctx, func() error {
+		return cb.Plugin.TokensApproval(ctx, nsOpID, poolLocator, approval, methods)
+	})
+}
+
+func (am *assetManager) GetTokenConnectorBreaker(name string) *core.TokenConnectorBreaker {
+	if cb, ok := am.breakers[name]; ok {
+		return cb.status()
+	}
+	return nil
+}
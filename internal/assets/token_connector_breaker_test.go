@@ -0,0 +1,288 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBreaker(failureThreshold int) (*connectorBreaker, *tokenmocks.Plugin) {
+	mti := &tokenmocks.Plugin{}
+	return newConnectorBreaker("magic-tokens", mti, &circuitBreakerConfig{
+		failureThreshold: failureThreshold,
+		probeInterval:    1 * time.Hour,
+	}), mti
+}
+
+func TestBreakerTripsAfterConsecutiveFailures(t *testing.T) {
+	cb, mti := newTestBreaker(2)
+	ctx := context.Background()
+
+	mti.On("MintTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Twice()
+
+	err := cb.MintTokens(ctx, "ns1:op1", "pool1", &core.TokenTransfer{}, nil)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenConnectorBreakerStateClosed, cb.status().State)
+
+	err = cb.MintTokens(ctx, "ns1:op1", "pool1", &core.TokenTransfer{}, nil)
+	assert.EqualError(t, err, "pop")
+
+	status := cb.status()
+	assert.Equal(t, core.TokenConnectorBreakerStateOpen, status.State)
+	assert.Equal(t, 2, status.ConsecutiveFailures)
+	assert.Equal(t, "pop", status.LastError)
+	assert.NotNil(t, status.LastFailure)
+	assert.NotNil(t, status.NextProbe)
+
+	// Fails fast without calling the connector
+	err = cb.MintTokens(ctx, "ns1:op1", "pool1", &core.TokenTransfer{}, nil)
+	assert.Regexp(t, "FF10521.*magic-tokens.*2", err)
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	cb, mti := newTestBreaker(2)
+	ctx := context.Background()
+
+	mti.On("BurnTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	mti.On("BurnTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(nil).Once()
+	mti.On("BurnTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Once()
+
+	for i := 0; i < 3; i++ {
+		_ = cb.BurnTokens(ctx, "ns1:op1", "pool1", &core.TokenTransfer{}, nil)
+	}
+
+	status := cb.status()
+	assert.Equal(t, core.TokenConnectorBreakerStateClosed, status.State)
+	assert.Equal(t, 1, status.ConsecutiveFailures)
+	assert.Nil(t, status.NextProbe)
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerOperationFailureNotCounted(t *testing.T) {
+	cb, mti := newTestBreaker(1)
+	ctx := context.Background()
+
+	mti.On("TransferTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(&core.OperationFailure{
+		Reason: core.OpFailureReasonComplianceRejected,
+		Err:    fmt.Errorf("pop"),
+	})
+
+	err := cb.TransferTokens(ctx, "ns1:op1", "pool1", &core.TokenTransfer{}, nil)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenConnectorBreakerStateClosed, cb.status().State)
+	assert.Zero(t, cb.status().ConsecutiveFailures)
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerClientErrorNotCounted(t *testing.T) {
+	cb, mti := newTestBreaker(1)
+	ctx := context.Background()
+
+	mti.On("TransferTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(&tokens.ErrorResponse{
+		StatusCode: 400,
+		Err:        fmt.Errorf("pop"),
+	})
+
+	err := cb.TransferTokens(ctx, "ns1:op1", "pool1", &core.TokenTransfer{}, nil)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenConnectorBreakerStateClosed, cb.status().State)
+	assert.Zero(t, cb.status().ConsecutiveFailures)
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerServerErrorCounted(t *testing.T) {
+	cb, mti := newTestBreaker(1)
+	ctx := context.Background()
+
+	mti.On("TransferTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(&tokens.ErrorResponse{
+		StatusCode: 503,
+		Err:        fmt.Errorf("pop"),
+	})
+
+	err := cb.TransferTokens(ctx, "ns1:op1", "pool1", &core.TokenTransfer{}, nil)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenConnectorBreakerStateOpen, cb.status().State)
+	assert.Equal(t, 1, cb.status().ConsecutiveFailures)
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerProbeRecovers(t *testing.T) {
+	cb, mti := newTestBreaker(1)
+	ctx := context.Background()
+
+	mti.On("TokensApproval", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	mti.On("TokensApproval", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(nil).Once()
+
+	err := cb.TokensApproval(ctx, "ns1:op1", "pool1", &core.TokenApproval{}, nil)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenConnectorBreakerStateOpen, cb.status().State)
+
+	cb.nextProbe = time.Now()
+	err = cb.TokensApproval(ctx, "ns1:op1", "pool1", &core.TokenApproval{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenConnectorBreakerStateClosed, cb.status().State)
+	assert.Zero(t, cb.status().ConsecutiveFailures)
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerProbeFails(t *testing.T) {
+	cb, mti := newTestBreaker(1)
+	ctx := context.Background()
+
+	mti.On("LockTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := cb.LockTokens(ctx, "ns1:op1", "pool1", &core.TokenSwap{}, &core.TokenSwapLeg{})
+	assert.EqualError(t, err, "pop")
+
+	cb.nextProbe = time.Now()
+	err = cb.LockTokens(ctx, "ns1:op1", "pool1", &core.TokenSwap{}, &core.TokenSwapLeg{})
+	assert.EqualError(t, err, "pop")
+
+	status := cb.status()
+	assert.Equal(t, core.TokenConnectorBreakerStateOpen, status.State)
+	assert.Equal(t, 2, status.ConsecutiveFailures)
+	assert.True(t, time.Time(*status.NextProbe).After(time.Now()))
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerHalfOpenAllowsSingleProbe(t *testing.T) {
+	cb, _ := newTestBreaker(1)
+	ctx := context.Background()
+
+	cb.state = core.TokenConnectorBreakerStateOpen
+	cb.failures = 1
+
+	err := cb.allow(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenConnectorBreakerStateHalfOpen, cb.status().State)
+
+	err = cb.allow(ctx)
+	assert.Regexp(t, "FF10521", err)
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	cb, mti := newTestBreaker(0)
+	ctx := context.Background()
+
+	mti.On("ClaimTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Times(3)
+
+	for i := 0; i < 3; i++ {
+		err := cb.ClaimTokens(ctx, "ns1:op1", "pool1", &core.TokenSwap{}, &core.TokenSwapLeg{})
+		assert.EqualError(t, err, "pop")
+	}
+	assert.Equal(t, core.TokenConnectorBreakerStateClosed, cb.status().State)
+	assert.Equal(t, 3, cb.status().ConsecutiveFailures)
+
+	mti.AssertExpectations(t)
+}
+
+func TestBreakerPassesThroughResults(t *testing.T) {
+	cb, mti := newTestBreaker(5)
+	ctx := context.Background()
+
+	pool := &core.TokenPool{}
+	methods := fftypes.JSONAnyPtr(`{}`)
+	eligibility := &core.TokenTransferEligibility{Eligible: true}
+	mti.On("CreateTokenPool", ctx, "ns1:op1", pool).Return(true, nil)
+	mti.On("ActivateTokenPool", ctx, pool).Return(true, nil)
+	mti.On("DeactivateTokenPool", ctx, pool).Return(nil)
+	mti.On("CheckInterface", ctx, pool, mock.Anything).Return(methods, nil)
+	mti.On("TransferTokensBatch", ctx, "ns1:op1", "pool1", mock.Anything, methods).Return(nil)
+	mti.On("CheckTransfer", ctx, "pool1", mock.Anything).Return(eligibility, nil)
+	mti.On("GetBalance", ctx, "pool1", "1", "0x12345").Return(fftypes.NewFFBigInt(10), nil)
+	mti.On("RefundTokens", ctx, "ns1:op1", "pool1", mock.Anything, mock.Anything).Return(nil)
+
+	complete, err := cb.CreateTokenPool(ctx, "ns1:op1", pool)
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	complete, err = cb.ActivateTokenPool(ctx, pool)
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	err = cb.DeactivateTokenPool(ctx, pool)
+	assert.NoError(t, err)
+	result, err := cb.CheckInterface(ctx, pool, []*fftypes.FFIMethod{})
+	assert.NoError(t, err)
+	assert.Equal(t, methods, result)
+	err = cb.TransferTokensBatch(ctx, "ns1:op1", "pool1", []*core.TokenTransfer{}, methods)
+	assert.NoError(t, err)
+	checked, err := cb.CheckTransfer(ctx, "pool1", &core.TokenTransfer{})
+	assert.NoError(t, err)
+	assert.Equal(t, eligibility, checked)
+	balance, err := cb.GetBalance(ctx, "pool1", "1", "0x12345")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), balance.Int64())
+	err = cb.RefundTokens(ctx, "ns1:op1", "pool1", &core.TokenSwap{}, &core.TokenSwapLeg{})
+	assert.NoError(t, err)
+
+	mti.AssertExpectations(t)
+}
+
+func TestGetTokenConnectorBreaker(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	breaker := am.GetTokenConnectorBreaker("magic-tokens")
+	assert.Equal(t, core.TokenConnectorBreakerStateClosed, breaker.State)
+	assert.Nil(t, am.GetTokenConnectorBreaker("bad"))
+
+	connectors := am.GetTokenConnectors(context.Background())
+	assert.Equal(t, breaker, connectors[0].Breaker)
+}
+
+func TestRunOperationBreakerOpen(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	cb := am.breakers["magic-tokens"]
+	cb.state = core.TokenConnectorBreakerStateOpen
+	cb.failures = 5
+	cb.nextProbe = time.Now().Add(1 * time.Hour)
+
+	pool := &core.TokenPool{
+		Locator:   "F1",
+		Connector: "magic-tokens",
+	}
+	mint := &core.TokenTransfer{
+		LocalID: fftypes.NewUUID(),
+		Type:    core.TokenTransferTypeMint,
+	}
+	op := &core.Operation{
+		Type: core.OpTypeTokenTransfer,
+		ID:   fftypes.NewUUID(),
+	}
+
+	_, _, err := am.RunOperation(context.Background(), opTransfer(op, pool, mint))
+	assert.Regexp(t, "FF10521", err)
+}
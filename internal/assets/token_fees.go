@@ -0,0 +1,91 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// GetTokenPoolDetails returns a token pool, along with the royalty and transfer fee reported by its connector
+func (am *assetManager) GetTokenPoolDetails(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	// The pool is shared with the cache, so the fees are set on a copy
+	details := *pool
+	details.Fees = am.getTokenFeesOrWarn(ctx, pool, "")
+	return &details, nil
+}
+
+func (am *assetManager) enrichTransferWithFees(ctx context.Context, transfer *core.TokenTransfer) error {
+	pool, err := am.GetTokenPoolByID(ctx, transfer.Pool)
+	if err != nil || pool == nil {
+		return err
+	}
+	fees := am.getTokenFeesOrWarn(ctx, pool, transfer.TokenIndex)
+	if fees == nil {
+		return nil
+	}
+	transfer.Fees = &core.TokenFees{Royalty: fees.Royalty}
+	if fees.TransferFee != nil {
+		transferFee := *fees.TransferFee
+		if transfer.Type == core.TokenTransferTypeTransfer {
+			transferFee.Amount = transferFee.FeeOn(&transfer.Amount)
+		}
+		transfer.Fees.TransferFee = &transferFee
+	}
+	return nil
+}
+
+// getTokenFeesOrWarn looks up the fees for a pool (or a token index within a pool) from the connector, without
+// failing the request if the connector is unable to report them
+func (am *assetManager) getTokenFeesOrWarn(ctx context.Context, pool *core.TokenPool, tokenIndex string) *core.TokenFees {
+	cacheKey := fmt.Sprintf("ns=%s,pool=%s,tokenindex=%s", am.namespace, pool.ID, tokenIndex)
+	if cachedValue := am.feesCache.Get(cacheKey); cachedValue != nil {
+		log.L(ctx).Debugf("Token fees cache hit: %s", cacheKey)
+		return feesOrNil(cachedValue.(*core.TokenFees))
+	}
+
+	plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
+	if err != nil {
+		log.L(ctx).Warnf("Unable to query fees for token pool '%s': %s", pool.ID, err)
+		return nil
+	}
+	fees, err := plugin.GetTokenFees(ctx, pool.Locator, tokenIndex)
+	if err != nil {
+		log.L(ctx).Warnf("Unable to query fees for token pool '%s': %s", pool.ID, err)
+		return nil
+	}
+	if fees == nil {
+		// Cache that there are no fees, so the connector is not queried again until the entry expires
+		fees = &core.TokenFees{}
+	}
+	am.feesCache.Set(cacheKey, fees)
+	return feesOrNil(fees)
+}
+
+func feesOrNil(fees *core.TokenFees) *core.TokenFees {
+	if fees.Royalty == nil && fees.TransferFee == nil {
+		return nil
+	}
+	return fees
+}
@@ -0,0 +1,231 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTokenPoolDetails(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	fees := &core.TokenFees{
+		Royalty: &core.TokenRoyalty{Receiver: "0x12345", BasisPoints: 500},
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti.On("GetTokenFees", context.Background(), "F1", "").Return(fees, nil).Once()
+
+	result, err := am.GetTokenPoolDetails(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, fees, result.Fees)
+	assert.Nil(t, pool.Fees)
+
+	// Served from the cache
+	result, err = am.GetTokenPoolDetails(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, fees, result.Fees)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestGetTokenPoolDetailsNoFees(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti.On("GetTokenFees", context.Background(), "F1", "").Return(nil, nil).Once()
+
+	result, err := am.GetTokenPoolDetails(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Nil(t, result.Fees)
+
+	// Lack of fees is also cached
+	result, err = am.GetTokenPoolDetails(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Nil(t, result.Fees)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestGetTokenPoolDetailsFeesFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mti.On("GetTokenFees", context.Background(), "F1", "").Return(nil, fmt.Errorf("pop"))
+
+	result, err := am.GetTokenPoolDetails(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, "pool1", result.Name)
+	assert.Nil(t, result.Fees)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestGetTokenPoolDetailsBadConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	pool.Connector = "bad"
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+
+	result, err := am.GetTokenPoolDetails(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Nil(t, result.Fees)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenPoolDetailsNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.GetTokenPoolDetails(context.Background(), "pool1")
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenTransferByIDWithFees(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	transfer := &core.TokenTransfer{
+		LocalID:    fftypes.NewUUID(),
+		Type:       core.TokenTransferTypeTransfer,
+		Pool:       pool.ID,
+		TokenIndex: "1",
+		Amount:     *fftypes.NewFFBigInt(1000),
+	}
+	fees := &core.TokenFees{
+		Royalty:     &core.TokenRoyalty{Receiver: "0x12345", BasisPoints: 500},
+		TransferFee: &core.TokenTransferFee{BasisPoints: 100},
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", transfer.LocalID).Return(transfer, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mti.On("GetTokenFees", context.Background(), "F1", "1").Return(fees, nil)
+
+	result, err := am.GetTokenTransferByID(context.Background(), transfer.LocalID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, fees.Royalty, result.Fees.Royalty)
+	assert.Equal(t, int64(100), result.Fees.TransferFee.BasisPoints)
+	assert.Equal(t, int64(10), result.Fees.TransferFee.Amount.Int64())
+	assert.Nil(t, fees.TransferFee.Amount)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestGetTokenTransferByIDMintWithFees(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	transfer := &core.TokenTransfer{
+		LocalID: fftypes.NewUUID(),
+		Type:    core.TokenTransferTypeMint,
+		Pool:    pool.ID,
+		Amount:  *fftypes.NewFFBigInt(1000),
+	}
+	fees := &core.TokenFees{
+		TransferFee: &core.TokenTransferFee{BasisPoints: 100},
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", transfer.LocalID).Return(transfer, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mti.On("GetTokenFees", context.Background(), "F1", "").Return(fees, nil)
+
+	result, err := am.GetTokenTransferByID(context.Background(), transfer.LocalID.String())
+	assert.NoError(t, err)
+	assert.Nil(t, result.Fees.Royalty)
+	assert.Nil(t, result.Fees.TransferFee.Amount)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestGetTokenTransferByIDNoFees(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	transfer := &core.TokenTransfer{
+		LocalID: fftypes.NewUUID(),
+		Type:    core.TokenTransferTypeTransfer,
+		Pool:    pool.ID,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", transfer.LocalID).Return(transfer, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mti.On("GetTokenFees", context.Background(), "F1", "").Return(nil, nil)
+
+	result, err := am.GetTokenTransferByID(context.Background(), transfer.LocalID.String())
+	assert.NoError(t, err)
+	assert.Nil(t, result.Fees)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestGetTokenTransferByIDPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransfer{
+		LocalID: fftypes.NewUUID(),
+		Pool:    fftypes.NewUUID(),
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", transfer.LocalID).Return(transfer, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", transfer.Pool).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.GetTokenTransferByID(context.Background(), transfer.LocalID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
@@ -0,0 +1,151 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// ledgerExportColumns lists every column that can be exported from the token ledger, in the default order
+var ledgerExportColumns = []struct {
+	name  string
+	value func(e *core.TokenLedgerEntry) string
+}{
+	{"id", func(e *core.TokenLedgerEntry) string { return e.ID.String() }},
+	{"sequence", func(e *core.TokenLedgerEntry) string { return strconv.FormatInt(e.Sequence, 10) }},
+	{"pool", func(e *core.TokenLedgerEntry) string { return e.Pool.String() }},
+	{"account", func(e *core.TokenLedgerEntry) string { return e.Account }},
+	{"counterparty", func(e *core.TokenLedgerEntry) string { return e.Counterparty }},
+	{"tokenIndex", func(e *core.TokenLedgerEntry) string { return e.TokenIndex }},
+	{"transferType", func(e *core.TokenLedgerEntry) string { return string(e.TransferType) }},
+	{"transfer", func(e *core.TokenLedgerEntry) string { return e.Transfer.String() }},
+	{"debit", func(e *core.TokenLedgerEntry) string { return e.Debit.String() }},
+	{"credit", func(e *core.TokenLedgerEntry) string { return e.Credit.String() }},
+	{"balance", func(e *core.TokenLedgerEntry) string { return e.Balance.String() }},
+	{"reversal", func(e *core.TokenLedgerEntry) string { return strconv.FormatBool(e.Reversal) }},
+	{"txType", func(e *core.TokenLedgerEntry) string { return string(e.TX.Type) }},
+	{"txId", func(e *core.TokenLedgerEntry) string { return e.TX.ID.String() }},
+	{"blockchainEvent", func(e *core.TokenLedgerEntry) string { return e.BlockchainEvent.String() }},
+	{"created", func(e *core.TokenLedgerEntry) string {
+		if e.Created == nil {
+			return ""
+		}
+		return e.Created.String()
+	}},
+}
+
+func (am *assetManager) GetTokenLedger(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenLedgerEntry, *ffapi.FilterResult, error) {
+	return am.database.GetTokenLedgerEntries(ctx, am.namespace, filter)
+}
+
+// ExportTokenLedger streams the matching ledger entries in the order they were recorded, in the same
+// formats as a token transfer export.
+func (am *assetManager) ExportTokenLedger(ctx context.Context, export *core.TokenLedgerExport) (io.ReadCloser, error) {
+	if export.Format == "" {
+		export.Format = core.TokenExportFormatCSV
+	}
+	if export.Format != core.TokenExportFormatCSV && export.Format != core.TokenExportFormatParquet {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenExportInvalidFormat, export.Format,
+			strings.Join([]string{string(core.TokenExportFormatCSV), string(core.TokenExportFormatParquet)}, ","))
+	}
+
+	columns, values, err := am.resolveLedgerExportColumns(ctx, export.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := database.TokenLedgerQueryFactory.NewFilter(ctx)
+	conditions := []ffapi.Filter{}
+	if export.Pool != "" {
+		pool, err := am.GetTokenPoolByNameOrID(ctx, export.Pool)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fb.Eq("pool", pool.ID))
+	}
+	if export.Account != "" {
+		conditions = append(conditions, fb.Eq("account", export.Account))
+	}
+	if export.StartTime != nil {
+		conditions = append(conditions, fb.Gte("created", export.StartTime))
+	}
+	if export.EndTime != nil {
+		conditions = append(conditions, fb.Lt("created", export.EndTime))
+	}
+	filter := fb.And(conditions...).Sort("sequence").Ascending().Limit(transferExportPageSize)
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := am.writeLedgerExport(ctx, export.Format, filter, pw, columns, values)
+		if err != nil {
+			log.L(ctx).Errorf("Token ledger export failed: %s", err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+func (am *assetManager) resolveLedgerExportColumns(ctx context.Context, requested []string) ([]string, []func(e *core.TokenLedgerEntry) string, error) {
+	all := make([]string, len(ledgerExportColumns))
+	byName := make(map[string]func(e *core.TokenLedgerEntry) string, len(ledgerExportColumns))
+	for i, c := range ledgerExportColumns {
+		all[i] = c.name
+		byName[c.name] = c.value
+	}
+	if len(requested) == 0 {
+		requested = all
+	}
+	values := make([]func(e *core.TokenLedgerEntry) string, len(requested))
+	for i, name := range requested {
+		if values[i] = byName[name]; values[i] == nil {
+			return nil, nil, i18n.NewError(ctx, coremsgs.MsgTokenExportInvalidColumn, name, strings.Join(all, ","))
+		}
+	}
+	return requested, values, nil
+}
+
+func (am *assetManager) writeLedgerExport(ctx context.Context, format core.TokenExportFormat, filter ffapi.Filter, pw io.Writer, columns []string, values []func(e *core.TokenLedgerEntry) string) error {
+	w := newTransferExportWriter(format, pw, columns)
+	row := make([]string, len(values))
+	for skip := uint64(0); ; skip += transferExportPageSize {
+		entries, _, err := am.database.GetTokenLedgerEntries(ctx, am.namespace, filter.Skip(skip))
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			for i, value := range values {
+				row[i] = value(entry)
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		if len(entries) < transferExportPageSize {
+			return w.Close()
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetTokenLedger(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	fb := database.TokenLedgerQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenLedgerEntries", context.Background(), "ns1", f).Return([]*core.TokenLedgerEntry{}, nil, nil)
+
+	_, _, err := am.GetTokenLedger(context.Background(), f)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExportTokenLedgerCSV(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID()}
+	entry := &core.TokenLedgerEntry{
+		ID:           fftypes.NewUUID(),
+		Sequence:     12,
+		Account:      "0x01",
+		Counterparty: "0x02",
+		Reversal:     true,
+	}
+	entry.Debit.Int().SetInt64(10)
+	entry.Balance.Int().SetInt64(-10)
+	start := fftypes.Now()
+	end := fftypes.Now()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenLedgerEntries", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == 0 && f.String() == fmt.Sprintf("( pool == '%s' ) && ( account == '0x01' ) && ( created >= %d ) && ( created << %d ) sort=sequence limit=1000",
+			pool.ID, start.UnixNano(), end.UnixNano())
+	})).Return([]*core.TokenLedgerEntry{entry}, nil, nil)
+
+	reader, err := am.ExportTokenLedger(context.Background(), &core.TokenLedgerExport{
+		Pool:      "pool1",
+		Account:   "0x01",
+		Columns:   []string{"id", "sequence", "counterparty", "debit", "credit", "balance", "reversal", "created"},
+		StartTime: start,
+		EndTime:   end,
+	})
+	assert.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("id,sequence,counterparty,debit,credit,balance,reversal,created\n%s,12,0x02,10,0,-10,true,\n", entry.ID), string(b))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExportTokenLedgerParquetPaged(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	page := make([]*core.TokenLedgerEntry, transferExportPageSize)
+	for i := range page {
+		page[i] = &core.TokenLedgerEntry{Created: fftypes.Now()}
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenLedgerEntries", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == 0
+	})).Return(page, nil, nil)
+	mdi.On("GetTokenLedgerEntries", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == transferExportPageSize
+	})).Return([]*core.TokenLedgerEntry{}, nil, nil)
+
+	reader, err := am.ExportTokenLedger(context.Background(), &core.TokenLedgerExport{
+		Format: core.TokenExportFormatParquet,
+	})
+	assert.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "PAR1", string(b[0:4]))
+	assert.Equal(t, "PAR1", string(b[len(b)-4:]))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExportTokenLedgerQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenLedgerEntries", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	reader, err := am.ExportTokenLedger(context.Background(), &core.TokenLedgerExport{})
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestExportTokenLedgerWriteFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenLedgerEntries", context.Background(), "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+
+	filter := database.TokenLedgerQueryFactory.NewFilter(context.Background()).And()
+	values := []func(e *core.TokenLedgerEntry) string{ledgerExportColumns[0].value}
+	err := am.writeLedgerExport(context.Background(), core.TokenExportFormatParquet, filter, &failingWriter{}, []string{"id"}, values)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestExportTokenLedgerBadFormat(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.ExportTokenLedger(context.Background(), &core.TokenLedgerExport{Format: "xlsx"})
+	assert.Regexp(t, "FF10497.*xlsx", err)
+}
+
+func TestExportTokenLedgerBadColumn(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.ExportTokenLedger(context.Background(), &core.TokenLedgerExport{Columns: []string{"id", "wrong"}})
+	assert.Regexp(t, "FF10498.*wrong", err)
+}
+
+func TestExportTokenLedgerBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.ExportTokenLedger(context.Background(), &core.TokenLedgerExport{Pool: "pool1"})
+	assert.EqualError(t, err, "pop")
+}
@@ -0,0 +1,184 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/sharedstorage"
+)
+
+const (
+	mediaStoreDatabase      = "database"
+	mediaStoreSharedStorage = "sharedstorage"
+)
+
+// mediaProxy serves cached copies of the images referenced by token metadata, so that applications
+// do not need to hotlink third-party gateways. Only images referenced by metadata that has already been
+// indexed are served, and each image is fetched once on first request, then served from the cache.
+type mediaProxy struct {
+	namespace     string
+	database      database.Plugin
+	sharedstorage sharedstorage.Plugin
+	metadata      *metadataIndexer
+	store         string
+	maxSize       int64
+	contentTypes  map[string]bool
+}
+
+func newMediaProxy(ctx context.Context, ns string, di database.Plugin, ss sharedstorage.Plugin, mi *metadataIndexer) (*mediaProxy, error) {
+	mp := &mediaProxy{
+		namespace:     ns,
+		database:      di,
+		sharedstorage: ss,
+		metadata:      mi,
+		store:         config.GetString(coreconfig.AssetMetadataMediaStore),
+		maxSize:       config.GetByteSize(coreconfig.AssetMetadataMediaMaxSize),
+		contentTypes:  make(map[string]bool),
+	}
+	switch mp.store {
+	case mediaStoreDatabase:
+	case mediaStoreSharedStorage:
+		if ss == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenMediaNoSharedStorage, ns)
+		}
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenMediaInvalidStore, mp.store)
+	}
+	for _, contentType := range config.GetStringSlice(coreconfig.AssetMetadataMediaContentTypes) {
+		contentType = strings.ToLower(contentType)
+		if !isMediaContentType(contentType) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenMediaInvalidContentType, contentType)
+		}
+		mp.contentTypes[contentType] = true
+	}
+	return mp, nil
+}
+
+// isMediaContentType checks a content type is an image or video, as anything else (such as HTML) could be
+// used to serve active content from the FireFly origin
+func isMediaContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/") || strings.HasPrefix(contentType, "video/")
+}
+
+// get returns the image referenced by the cached metadata at a token URI
+func (mp *mediaProxy) get(ctx context.Context, metadataURI string) (*core.TokenMedia, io.ReadCloser, error) {
+	metadata, err := mp.database.GetTokenMetadataByURI(ctx, mp.namespace, metadataURI)
+	if err != nil {
+		return nil, nil, err
+	}
+	if metadata == nil || metadata.Image == "" {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgTokenMediaNotFound, uriForErrors(metadataURI))
+	}
+
+	media, err := mp.database.GetTokenMediaByURI(ctx, mp.namespace, metadata.Image)
+	if err != nil {
+		return nil, nil, err
+	}
+	if media != nil && !mp.contentTypes[media.ContentType] {
+		// Cached before the allowed content types were changed
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgTokenMediaContentType, uriForErrors(metadata.Image), media.ContentType)
+	}
+	switch {
+	case media == nil:
+		return mp.fetch(ctx, metadata.Image)
+	case media.PayloadRef == "":
+		return media, io.NopCloser(bytes.NewReader(media.Content)), nil
+	case mp.sharedstorage != nil:
+		reader, err := mp.sharedstorage.DownloadData(ctx, media.PayloadRef)
+		if err != nil {
+			return nil, nil, err
+		}
+		return media, reader, nil
+	default:
+		// Cached in shared storage, but shared storage is no longer configured for this namespace
+		content, _, err := mp.download(ctx, metadata.Image)
+		if err != nil {
+			return nil, nil, err
+		}
+		return media, io.NopCloser(bytes.NewReader(content)), nil
+	}
+}
+
+func (mp *mediaProxy) download(ctx context.Context, uri string) ([]byte, string, error) {
+	content, contentType, err := mp.metadata.download(ctx, uri, mp.maxSize)
+	if err != nil {
+		return nil, "", err
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "application/octet-stream" {
+		// Detect the type from the content, if the server did not report a usable one
+		mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(content))
+	}
+	if !mp.contentTypes[mediaType] {
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgTokenMediaContentType, uriForErrors(uri), mediaType)
+	}
+	return content, mediaType, nil
+}
+
+func (mp *mediaProxy) fetch(ctx context.Context, uri string) (*core.TokenMedia, io.ReadCloser, error) {
+	content, contentType, err := mp.download(ctx, uri)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hash := fftypes.Bytes32(sha256.Sum256(content))
+	media := &core.TokenMedia{
+		Namespace:   mp.namespace,
+		URI:         uri,
+		ContentType: contentType,
+		Size:        int64(len(content)),
+		Hash:        &hash,
+	}
+	if mp.store == mediaStoreSharedStorage {
+		if media.PayloadRef, err = mp.sharedstorage.UploadData(ctx, bytes.NewReader(content)); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		media.Content = content
+	}
+	if err := mp.database.InsertTokenMedia(ctx, media); err != nil {
+		// The image is still served - it is most likely already cached by a parallel request
+		log.L(ctx).Warnf("Failed to cache token media from '%s': %s", uriForErrors(uri), err)
+	}
+	return media, io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (am *assetManager) MediaProxyEnabled() bool {
+	return am.media != nil
+}
+
+func (am *assetManager) GetTokenMedia(ctx context.Context, metadataURI string) (*core.TokenMedia, io.ReadCloser, error) {
+	if am.media == nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	return am.media.get(ctx, metadataURI)
+}
@@ -0,0 +1,442 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testMediaURI = "https://example.com/image/1.png"
+
+var testPNG = []byte("\x89PNG\x0D\x0A\x1A\x0A-some-image")
+
+func newTestMediaProxy(t *testing.T, store string) (*assetManager, *sharedstoragemocks.Plugin, func()) {
+	am, cancel := newTestMetadataIndexer(t)
+	config.Set(coreconfig.AssetMetadataMediaStore, store)
+	mss := &sharedstoragemocks.Plugin{}
+	var err error
+	am.media, err = newMediaProxy(am.ctx, "ns1", am.database, mss, am.metadata)
+	assert.NoError(t, err)
+	return am, mss, func() {
+		cancel()
+		mss.AssertExpectations(t)
+	}
+}
+
+func newTestMediaMetadata(mdi *databasemocks.Plugin) {
+	mdi.On("GetTokenMetadataByURI", context.Background(), "ns1", testMetadataURI).Return(&core.TokenMetadata{
+		URI:   testMetadataURI,
+		Image: testMediaURI,
+	}, nil)
+}
+
+func readMedia(t *testing.T, reader io.ReadCloser) string {
+	defer reader.Close()
+	b, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(b)
+}
+
+func TestNewAssetManagerMediaEnabled(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.AssetMetadataEnabled, true)
+	config.Set(coreconfig.AssetMetadataMediaEnabled, true)
+	mom := &operationmocks.Manager{}
+	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, err := NewAssetManager(ctx, "ns1", "blockchain_plugin", 0, &databasemocks.Plugin{}, map[string]tokens.Plugin{}, nil, &identitymanagermocks.Manager{}, &syncasyncmocks.Bridge{}, nil, nil, &metricsmocks.Manager{}, mom, nil, nil, nil)
+	assert.NoError(t, err)
+	am := a.(*assetManager)
+	assert.True(t, am.MediaProxyEnabled())
+	assert.Equal(t, mediaStoreDatabase, am.media.store)
+	assert.Equal(t, int64(10*1024*1024), am.media.maxSize)
+	assert.True(t, am.media.contentTypes["image/png"])
+	assert.False(t, am.media.contentTypes["image/svg+xml"])
+
+	config.Set(coreconfig.AssetMetadataMediaStore, mediaStoreSharedStorage)
+	_, err = NewAssetManager(ctx, "ns1", "blockchain_plugin", 0, &databasemocks.Plugin{}, map[string]tokens.Plugin{}, nil, &identitymanagermocks.Manager{}, &syncasyncmocks.Bridge{}, nil, nil, &metricsmocks.Manager{}, mom, nil, nil, nil)
+	assert.Regexp(t, "FF10510", err)
+
+	config.Set(coreconfig.AssetMetadataMediaStore, "wrong")
+	_, err = NewAssetManager(ctx, "ns1", "blockchain_plugin", 0, &databasemocks.Plugin{}, map[string]tokens.Plugin{}, nil, &identitymanagermocks.Manager{}, &syncasyncmocks.Bridge{}, nil, nil, &metricsmocks.Manager{}, mom, nil, nil, nil)
+	assert.Regexp(t, "FF10509", err)
+
+	config.Set(coreconfig.AssetMetadataMediaStore, mediaStoreDatabase)
+	config.Set(coreconfig.AssetMetadataMediaContentTypes, []string{"image/png", "text/html"})
+	_, err = NewAssetManager(ctx, "ns1", "blockchain_plugin", 0, &databasemocks.Plugin{}, map[string]tokens.Plugin{}, nil, &identitymanagermocks.Manager{}, &syncasyncmocks.Bridge{}, nil, nil, &metricsmocks.Manager{}, mom, nil, nil, nil)
+	assert.Regexp(t, "FF10545.*text/html", err)
+}
+
+func TestGetTokenMediaDisabled(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	assert.False(t, am.MediaProxyEnabled())
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestGetTokenMediaFetchDatabase(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", testMediaURI, func(req *http.Request) (*http.Response, error) {
+		res := httpmock.NewBytesResponse(200, testPNG)
+		res.Header.Set("Content-Type", "image/png; charset=binary")
+		return res, nil
+	})
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+	mdi.On("InsertTokenMedia", context.Background(), mock.MatchedBy(func(media *core.TokenMedia) bool {
+		return media.URI == testMediaURI &&
+			media.Namespace == "ns1" &&
+			media.ContentType == "image/png" &&
+			media.Size == int64(len(testPNG)) &&
+			media.Hash != nil &&
+			bytes.Equal(media.Content, testPNG) &&
+			media.PayloadRef == ""
+	})).Return(nil)
+
+	media, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/png", media.ContentType)
+	assert.Equal(t, string(testPNG), readMedia(t, reader))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenMediaFetchSharedStorage(t *testing.T) {
+	am, mss, cancel := newTestMediaProxy(t, mediaStoreSharedStorage)
+	defer cancel()
+
+	// No content type is reported, so it is detected from the content
+	httpmock.RegisterResponder("GET", testMediaURI, httpmock.NewBytesResponder(200, testPNG))
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+	mss.On("UploadData", context.Background(), mock.Anything).Return("QmPayload", nil)
+	mdi.On("InsertTokenMedia", context.Background(), mock.MatchedBy(func(media *core.TokenMedia) bool {
+		return media.ContentType == "image/png" &&
+			media.Content == nil &&
+			media.PayloadRef == "QmPayload"
+	})).Return(nil)
+
+	_, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, string(testPNG), readMedia(t, reader))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenMediaFetchUploadFail(t *testing.T) {
+	am, mss, cancel := newTestMediaProxy(t, mediaStoreSharedStorage)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", testMediaURI, httpmock.NewBytesResponder(200, testPNG))
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+	mss.On("UploadData", context.Background(), mock.Anything).Return("", fmt.Errorf("pop"))
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenMediaFetchInsertFail(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", testMediaURI, httpmock.NewBytesResponder(200, testPNG))
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+	mdi.On("InsertTokenMedia", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	// The image is still served
+	_, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, string(testPNG), readMedia(t, reader))
+}
+
+func TestGetTokenMediaFetchBadContentType(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", testMediaURI, func(req *http.Request) (*http.Response, error) {
+		res := httpmock.NewStringResponse(200, "<html><script>alert(1)</script></html>")
+		res.Header.Set("Content-Type", "text/html")
+		return res, nil
+	})
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10508.*text/html", err)
+}
+
+func TestGetTokenMediaFetchDataURI(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenMetadataByURI", context.Background(), "ns1", testMetadataURI).Return(&core.TokenMetadata{
+		URI:   testMetadataURI,
+		Image: "data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=",
+	}, nil)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+
+	// SVG is not allowed by default
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10508.*data:image/svg\\+xml;base64,\\.\\.\\..*image/svg\\+xml", err)
+
+	am.media.contentTypes["image/svg+xml"] = true
+	mdi.On("InsertTokenMedia", context.Background(), mock.Anything).Return(nil)
+	media, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", media.ContentType)
+	assert.Equal(t, "<svg></svg>", readMedia(t, reader))
+}
+
+func TestGetTokenMediaFetchTooLarge(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+	am.media.maxSize = 5
+
+	httpmock.RegisterResponder("GET", testMediaURI, httpmock.NewBytesResponder(200, testPNG))
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10480.*5 bytes", err)
+}
+
+func TestGetTokenMediaCachedDatabase(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(&core.TokenMedia{
+		URI:         testMediaURI,
+		ContentType: "image/png",
+		Content:     testPNG,
+	}, nil)
+
+	_, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, string(testPNG), readMedia(t, reader))
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestGetTokenMediaCachedBadContentType(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(&core.TokenMedia{
+		URI:         testMediaURI,
+		ContentType: "text/html",
+		Content:     []byte("<html><script>alert(1)</script></html>"),
+	}, nil)
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10508.*text/html", err)
+}
+
+func TestGetTokenMediaFetchVideo(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+	am.media.contentTypes["video/mp4"] = true
+
+	httpmock.RegisterResponder("GET", testMediaURI, func(req *http.Request) (*http.Response, error) {
+		res := httpmock.NewStringResponse(200, "some-video")
+		res.Header.Set("Content-Type", "video/mp4")
+		return res, nil
+	})
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+	mdi.On("InsertTokenMedia", context.Background(), mock.Anything).Return(nil)
+
+	media, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, "video/mp4", media.ContentType)
+	assert.Equal(t, "some-video", readMedia(t, reader))
+}
+
+func TestGetTokenMediaFetchLoopbackBlocked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(testPNG)
+	}))
+	defer server.Close()
+
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.metadata = newTestGuardedIndexer(t)
+	var err error
+	am.media, err = newMediaProxy(am.ctx, "ns1", am.database, nil, am.metadata)
+	assert.NoError(t, err)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenMetadataByURI", context.Background(), "ns1", testMetadataURI).Return(&core.TokenMetadata{
+		URI:   testMetadataURI,
+		Image: server.URL + "/image.png",
+	}, nil)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", server.URL+"/image.png").Return(nil, nil)
+
+	_, _, err = am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10540.*127.0.0.1", err)
+}
+
+func TestGetTokenMediaCachedSharedStorage(t *testing.T) {
+	am, mss, cancel := newTestMediaProxy(t, mediaStoreSharedStorage)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(&core.TokenMedia{
+		URI:         testMediaURI,
+		ContentType: "image/png",
+		PayloadRef:  "QmPayload",
+	}, nil)
+	mss.On("DownloadData", context.Background(), "QmPayload").Return(io.NopCloser(bytes.NewReader(testPNG)), nil)
+
+	_, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, string(testPNG), readMedia(t, reader))
+}
+
+func TestGetTokenMediaCachedSharedStorageFail(t *testing.T) {
+	am, mss, cancel := newTestMediaProxy(t, mediaStoreSharedStorage)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(&core.TokenMedia{
+		URI:         testMediaURI,
+		ContentType: "image/png",
+		PayloadRef:  "QmPayload",
+	}, nil)
+	mss.On("DownloadData", context.Background(), "QmPayload").Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenMediaCachedSharedStorageUnavailable(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+	am.media.sharedstorage = nil
+
+	httpmock.RegisterResponder("GET", testMediaURI, httpmock.NewBytesResponder(200, testPNG))
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(&core.TokenMedia{
+		URI:         testMediaURI,
+		ContentType: "image/png",
+		PayloadRef:  "QmPayload",
+	}, nil)
+
+	_, reader, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.NoError(t, err)
+	assert.Equal(t, string(testPNG), readMedia(t, reader))
+
+	httpmock.RegisterResponder("GET", testMediaURI, httpmock.NewStringResponder(404, "not found"))
+	_, _, err = am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10477", err)
+}
+
+func TestGetTokenMediaNotIndexed(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenMetadataByURI", context.Background(), "ns1", testMetadataURI).Return(nil, nil).Once()
+	mdi.On("GetTokenMetadataByURI", context.Background(), "ns1", testMetadataURI).Return(&core.TokenMetadata{
+		URI: testMetadataURI,
+	}, nil).Once()
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10507", err)
+
+	// Metadata without an image
+	_, _, err = am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10507", err)
+}
+
+func TestGetTokenMediaMetadataFail(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenMetadataByURI", context.Background(), "ns1", testMetadataURI).Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenMediaLookupFail(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenMediaFetchFail(t *testing.T) {
+	am, _, cancel := newTestMediaProxy(t, mediaStoreDatabase)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", testMediaURI, httpmock.NewStringResponder(500, "pop"))
+	mdi := am.database.(*databasemocks.Plugin)
+	newTestMediaMetadata(mdi)
+	mdi.On("GetTokenMediaByURI", context.Background(), "ns1", testMediaURI).Return(nil, nil)
+
+	_, _, err := am.GetTokenMedia(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10477", err)
+}
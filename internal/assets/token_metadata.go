@@ -0,0 +1,342 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// metadataIndexer dereferences the URIs of tokens in the background, and caches the ERC-721 / ERC-1155
+// metadata documents in the database. Queries on transfers and balances are enriched with whatever
+// metadata is already cached, and any URIs that have not been fetched yet are queued for fetching.
+type metadataIndexer struct {
+	ctx           context.Context
+	namespace     string
+	database      database.Plugin
+	client        *resty.Client
+	ipfsGateway   string
+	maxSize       int64
+	retryInterval time.Duration
+	workers       int
+	queue         chan string
+	queuedLock    sync.Mutex
+	queued        map[string]bool
+}
+
+func newMetadataIndexer(ctx context.Context, ns string, di database.Plugin) *metadataIndexer {
+	guard := newURIGuard(config.GetStringSlice(coreconfig.AssetMetadataAllowedHosts), config.GetInt(coreconfig.AssetMetadataMaxRedirects))
+	client := ffresty.NewWithConfig(ctx, ffresty.Config{
+		HTTPRequestTimeout: config.GetDuration(coreconfig.AssetMetadataRequestTimeout),
+	})
+	client.SetTransport(guard.transport())
+	client.SetRedirectPolicy(resty.RedirectPolicyFunc(guard.checkRedirect))
+	return &metadataIndexer{
+		ctx:           ctx,
+		namespace:     ns,
+		database:      di,
+		client:        client,
+		ipfsGateway:   strings.TrimSuffix(config.GetString(coreconfig.AssetMetadataIPFSGateway), "/"),
+		maxSize:       config.GetByteSize(coreconfig.AssetMetadataMaxSize),
+		retryInterval: config.GetDuration(coreconfig.AssetMetadataRetryInterval),
+		workers:       config.GetInt(coreconfig.AssetMetadataWorkers),
+		queue:         make(chan string, config.GetInt(coreconfig.AssetMetadataQueueLength)),
+		queued:        make(map[string]bool),
+	}
+}
+
+func (mi *metadataIndexer) start() {
+	for i := 0; i < mi.workers; i++ {
+		go mi.fetchLoop()
+	}
+}
+
+func (mi *metadataIndexer) fetchLoop() {
+	for {
+		select {
+		case <-mi.ctx.Done():
+			log.L(mi.ctx).Debugf("Token metadata fetcher exiting")
+			return
+		case uri := <-mi.queue:
+			metadata := mi.fetch(mi.ctx, uri)
+			if err := mi.database.UpsertTokenMetadata(mi.ctx, metadata); err != nil {
+				// Will be queued again on the next query that needs it
+				log.L(mi.ctx).Errorf("Failed to store token metadata for '%s': %s", uri, err)
+			}
+			mi.queuedLock.Lock()
+			delete(mi.queued, uri)
+			mi.queuedLock.Unlock()
+		}
+	}
+}
+
+// queueFetch never blocks the caller - if the queue is full, the URI is picked up on a later query
+func (mi *metadataIndexer) queueFetch(uri string) {
+	mi.queuedLock.Lock()
+	defer mi.queuedLock.Unlock()
+	if mi.queued[uri] {
+		return
+	}
+	select {
+	case mi.queue <- uri:
+		mi.queued[uri] = true
+	default:
+		log.L(mi.ctx).Debugf("Token metadata queue full - skipping '%s'", uri)
+	}
+}
+
+// substituteTokenID replaces the ERC-1155 {id} placeholder in a URI with the token index,
+// as lowercase hex padded to 64 characters
+func substituteTokenID(uri, tokenIndex string) string {
+	if !strings.Contains(uri, "{id}") {
+		return uri
+	}
+	id, ok := new(big.Int).SetString(tokenIndex, 10)
+	if !ok {
+		return uri
+	}
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
+
+// uriForErrors omits the (potentially large) content of data URIs from errors
+func uriForErrors(uri string) string {
+	if strings.HasPrefix(uri, "data:") {
+		if header, _, ok := strings.Cut(uri, ","); ok {
+			return header + ",..."
+		}
+	}
+	return uri
+}
+
+func (mi *metadataIndexer) fetch(ctx context.Context, uri string) *core.TokenMetadata {
+	metadata := &core.TokenMetadata{
+		Namespace: mi.namespace,
+		URI:       uri,
+	}
+	content, _, err := mi.download(ctx, uri, mi.maxSize)
+	if err == nil {
+		err = validateTokenMetadata(ctx, uriForErrors(uri), content, metadata)
+	}
+	if err != nil {
+		log.L(ctx).Warnf("Unable to index token metadata for '%s': %s", uriForErrors(uri), err)
+		metadata.Error = err.Error()
+	}
+	return metadata
+}
+
+// download fetches the content at a token URI, along with the content type reported by the server (or
+// declared in the data URI). It is used both for metadata documents, and for the media they reference.
+func (mi *metadataIndexer) download(ctx context.Context, uri string, maxSize int64) ([]byte, string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgTokenMetadataUnsupportedURI, uri)
+	}
+	var fetchURL string
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		fetchURL = uri
+	case "ipfs":
+		// Both ipfs://<cid>/<path> and ipfs://ipfs/<cid>/<path> are in use
+		path := strings.TrimPrefix(strings.TrimPrefix(uri[len("ipfs://"):], "/"), "ipfs/")
+		fetchURL = mi.ipfsGateway + "/ipfs/" + path
+	case "data":
+		return mi.decodeDataURI(ctx, uri, maxSize)
+	default:
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgTokenMetadataUnsupportedURI, uri)
+	}
+
+	res, err := mi.client.R().
+		SetContext(ctx).
+		SetDoNotParseResponse(true).
+		Get(fetchURL)
+	ffresty.OnAfterResponse(mi.client, res) // required using SetDoNotParseResponse
+	if err != nil || !res.IsSuccess() {
+		return nil, "", ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgTokenMetadataFetchFailed)
+	}
+	defer res.RawBody().Close()
+	content, err := io.ReadAll(io.LimitReader(res.RawBody(), maxSize+1))
+	if err != nil {
+		return nil, "", i18n.WrapError(ctx, err, coremsgs.MsgTokenMetadataFetchFailed, err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgTokenMetadataTooLarge, uri, maxSize)
+	}
+	return content, res.Header().Get("Content-Type"), nil
+}
+
+// decodeDataURI handles metadata and media stored on-chain, as either data:<content type>;base64,<data>
+// or data:<content type>,<url encoded data>
+func (mi *metadataIndexer) decodeDataURI(ctx context.Context, uri string, maxSize int64) (content []byte, contentType string, err error) {
+	header, data, ok := strings.Cut(uri[len("data:"):], ",")
+	if !ok {
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgTokenMetadataUnsupportedURI, uri)
+	}
+	uri = uriForErrors(uri)
+	if strings.HasSuffix(header, ";base64") {
+		contentType = strings.TrimSuffix(header, ";base64")
+		content, err = base64.StdEncoding.DecodeString(data)
+	} else {
+		contentType = header
+		var decoded string
+		decoded, err = url.PathUnescape(data)
+		content = []byte(decoded)
+	}
+	if err != nil {
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgTokenMetadataInvalid, uri, err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgTokenMetadataTooLarge, uri, maxSize)
+	}
+	return content, contentType, nil
+}
+
+// validateTokenMetadata checks a document against the ERC-721 metadata JSON schema, and the
+// additional fields defined for ERC-1155. Unknown fields are permitted, and returned in the content.
+func validateTokenMetadata(ctx context.Context, uri string, content []byte, metadata *core.TokenMetadata) error {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgTokenMetadataInvalid, uri, err)
+	}
+	for _, field := range []string{"name", "description", "image"} {
+		if v, ok := doc[field]; ok {
+			if _, isString := v.(string); !isString {
+				return i18n.NewError(ctx, coremsgs.MsgTokenMetadataInvalid, uri, fmt.Sprintf("'%s' must be a string", field))
+			}
+		}
+	}
+	if v, ok := doc["decimals"]; ok {
+		if d, isNumber := v.(float64); !isNumber || d != float64(int64(d)) || d < 0 {
+			return i18n.NewError(ctx, coremsgs.MsgTokenMetadataInvalid, uri, "'decimals' must be a non-negative integer")
+		}
+	}
+	for _, field := range []string{"properties", "localization"} {
+		if v, ok := doc[field]; ok {
+			if _, isObject := v.(map[string]interface{}); !isObject {
+				return i18n.NewError(ctx, coremsgs.MsgTokenMetadataInvalid, uri, fmt.Sprintf("'%s' must be an object", field))
+			}
+		}
+	}
+
+	metadata.Name, _ = doc["name"].(string)
+	metadata.Description, _ = doc["description"].(string)
+	metadata.Image, _ = doc["image"].(string)
+	metadata.Content = fftypes.JSONAnyPtrBytes(content)
+	return nil
+}
+
+// lookup returns the cached metadata for a set of URIs, queuing a fetch for any that are not cached
+// yet, or where the last fetch failed more than the retry interval ago
+func (mi *metadataIndexer) lookup(ctx context.Context, uris []string) (map[string]*core.TokenMetadata, error) {
+	results := make(map[string]*core.TokenMetadata)
+	if len(uris) == 0 {
+		return results, nil
+	}
+
+	values := make([]driver.Value, 0, len(uris))
+	unique := make(map[string]bool)
+	for _, uri := range uris {
+		if !unique[uri] {
+			unique[uri] = true
+			values = append(values, uri)
+		}
+	}
+	fb := database.TokenMetadataQueryFactory.NewFilter(ctx)
+	filter := fb.And(fb.In("uri", values)).Limit(uint64(len(values)))
+	cached, _, err := mi.database.GetTokenMetadata(ctx, mi.namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, metadata := range cached {
+		delete(unique, metadata.URI)
+		if metadata.Error != "" {
+			if time.Since(*metadata.Updated.Time()) > mi.retryInterval {
+				mi.queueFetch(metadata.URI)
+			}
+			continue
+		}
+		results[metadata.URI] = metadata
+	}
+	for uri := range unique {
+		mi.queueFetch(uri)
+	}
+	return results, nil
+}
+
+func (am *assetManager) enrichTransfersWithMetadata(ctx context.Context, transfers []*core.TokenTransfer) error {
+	if am.metadata == nil {
+		return nil
+	}
+	uris := make([]string, 0, len(transfers))
+	for _, transfer := range transfers {
+		if transfer.URI != "" {
+			uris = append(uris, substituteTokenID(transfer.URI, transfer.TokenIndex))
+		}
+	}
+	metadata, err := am.metadata.lookup(ctx, uris)
+	if err != nil {
+		return err
+	}
+	for _, transfer := range transfers {
+		if transfer.URI != "" {
+			transfer.Metadata = metadata[substituteTokenID(transfer.URI, transfer.TokenIndex)]
+		}
+	}
+	return nil
+}
+
+func (am *assetManager) enrichBalancesWithMetadata(ctx context.Context, balances []*core.TokenBalance) error {
+	if am.metadata == nil {
+		return nil
+	}
+	uris := make([]string, 0, len(balances))
+	for _, balance := range balances {
+		if balance.URI != "" {
+			uris = append(uris, substituteTokenID(balance.URI, balance.TokenIndex))
+		}
+	}
+	metadata, err := am.metadata.lookup(ctx, uris)
+	if err != nil {
+		return err
+	}
+	for _, balance := range balances {
+		if balance.URI != "" {
+			balance.Metadata = metadata[substituteTokenID(balance.URI, balance.TokenIndex)]
+		}
+	}
+	return nil
+}
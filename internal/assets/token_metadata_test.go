@@ -0,0 +1,337 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const testMetadataURI = "https://example.com/token/1"
+
+func newTestMetadataIndexer(t *testing.T) (*assetManager, func()) {
+	am, cancel := newTestAssets(t)
+	am.metadata = newMetadataIndexer(am.ctx, "ns1", am.database)
+	httpmock.ActivateNonDefault(am.metadata.client.GetClient())
+	return am, func() {
+		cancel()
+		httpmock.DeactivateAndReset()
+	}
+}
+
+func TestNewAssetManagerMetadataEnabled(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.AssetMetadataEnabled, true)
+	mom := &operationmocks.Manager{}
+	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a, err := NewAssetManager(ctx, "ns1", "blockchain_plugin", 0, &databasemocks.Plugin{}, map[string]tokens.Plugin{}, nil, &identitymanagermocks.Manager{}, &syncasyncmocks.Bridge{}, nil, nil, &metricsmocks.Manager{}, mom, nil, nil, nil)
+	assert.NoError(t, err)
+	am := a.(*assetManager)
+	assert.NotNil(t, am.metadata)
+	assert.Equal(t, 2, am.metadata.workers)
+	assert.Equal(t, int64(1024*1024), am.metadata.maxSize)
+}
+
+func TestSubstituteTokenID(t *testing.T) {
+	assert.Equal(t, "https://example.com/token/1", substituteTokenID("https://example.com/token/1", "1"))
+	assert.Equal(t, "https://example.com/000000000000000000000000000000000000000000000000000000000000004d.json", substituteTokenID("https://example.com/{id}.json", "77"))
+	assert.Equal(t, "https://example.com/{id}.json", substituteTokenID("https://example.com/{id}.json", "not a number"))
+}
+
+func TestFetchMetadataHTTP(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", testMetadataURI,
+		httpmock.NewStringResponder(200, `{"name":"Token 1","description":"The first token","image":"ipfs://QmImage","attributes":[{"trait_type":"color","value":"red"}]}`))
+
+	metadata := am.metadata.fetch(context.Background(), testMetadataURI)
+	assert.Empty(t, metadata.Error)
+	assert.Equal(t, "ns1", metadata.Namespace)
+	assert.Equal(t, testMetadataURI, metadata.URI)
+	assert.Equal(t, "Token 1", metadata.Name)
+	assert.Equal(t, "The first token", metadata.Description)
+	assert.Equal(t, "ipfs://QmImage", metadata.Image)
+	assert.Equal(t, "red", metadata.Content.JSONObject().GetObjectArray("attributes")[0].GetString("value"))
+}
+
+func TestFetchMetadataIPFS(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", "https://ipfs.io/ipfs/QmCID/1.json",
+		httpmock.NewStringResponder(200, `{"name":"Token 1"}`))
+
+	metadata := am.metadata.fetch(context.Background(), "ipfs://QmCID/1.json")
+	assert.Empty(t, metadata.Error)
+	assert.Equal(t, "Token 1", metadata.Name)
+
+	metadata = am.metadata.fetch(context.Background(), "ipfs://ipfs/QmCID/1.json")
+	assert.Empty(t, metadata.Error)
+	assert.Equal(t, "Token 1", metadata.Name)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestFetchMetadataDataURI(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+
+	metadata := am.metadata.fetch(context.Background(), "data:application/json;base64,eyJuYW1lIjoiVG9rZW4gMSJ9")
+	assert.Empty(t, metadata.Error)
+	assert.Equal(t, "Token 1", metadata.Name)
+
+	metadata = am.metadata.fetch(context.Background(), "data:application/json,%7B%22name%22%3A%22Token%201%22%7D")
+	assert.Empty(t, metadata.Error)
+	assert.Equal(t, "Token 1", metadata.Name)
+
+	metadata = am.metadata.fetch(context.Background(), "data:application/json;base64,!!!")
+	assert.Regexp(t, "FF10478", metadata.Error)
+
+	metadata = am.metadata.fetch(context.Background(), "data:application/json")
+	assert.Regexp(t, "FF10479", metadata.Error)
+
+	am.metadata.maxSize = 5
+	metadata = am.metadata.fetch(context.Background(), "data:application/json,%7B%22name%22%3A%22Token%201%22%7D")
+	assert.Regexp(t, "FF10480", metadata.Error)
+}
+
+func TestFetchMetadataUnsupportedURI(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+
+	metadata := am.metadata.fetch(context.Background(), "ftp://example.com/token/1")
+	assert.Regexp(t, "FF10479", metadata.Error)
+
+	metadata = am.metadata.fetch(context.Background(), "::bad")
+	assert.Regexp(t, "FF10479", metadata.Error)
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestFetchMetadataHTTPError(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+
+	httpmock.RegisterResponder("GET", testMetadataURI,
+		httpmock.NewStringResponder(404, `not found`))
+
+	metadata := am.metadata.fetch(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10477.*not found", metadata.Error)
+	assert.Nil(t, metadata.Content)
+}
+
+func TestFetchMetadataTooLarge(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+	am.metadata.maxSize = 10
+
+	httpmock.RegisterResponder("GET", testMetadataURI,
+		httpmock.NewStringResponder(200, `{"name":"`+strings.Repeat("a", 100)+`"}`))
+
+	metadata := am.metadata.fetch(context.Background(), testMetadataURI)
+	assert.Regexp(t, "FF10480.*10 bytes", metadata.Error)
+}
+
+func TestValidateTokenMetadataInvalid(t *testing.T) {
+	for _, content := range []string{
+		`!json`,
+		`["not an object"]`,
+		`{"name":12345}`,
+		`{"image":{"url":"ipfs://QmImage"}}`,
+		`{"decimals":"18"}`,
+		`{"decimals":1.5}`,
+		`{"decimals":-1}`,
+		`{"properties":[]}`,
+		`{"localization":"en"}`,
+	} {
+		err := validateTokenMetadata(context.Background(), testMetadataURI, []byte(content), &core.TokenMetadata{})
+		assert.Regexp(t, "FF10478", err, content)
+	}
+
+	metadata := &core.TokenMetadata{}
+	err := validateTokenMetadata(context.Background(), testMetadataURI, []byte(`{"name":"Token 1","decimals":18,"properties":{"rarity":"rare"}}`), metadata)
+	assert.NoError(t, err)
+	assert.Equal(t, "Token 1", metadata.Name)
+}
+
+func TestMetadataFetchLoop(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+
+	httpmock.RegisterResponder("GET", testMetadataURI,
+		httpmock.NewStringResponder(200, `{"name":"Token 1"}`))
+
+	stored := make(chan *core.TokenMetadata, 2)
+	mdi.On("UpsertTokenMetadata", mock.Anything, mock.MatchedBy(func(metadata *core.TokenMetadata) bool {
+		return metadata.URI == testMetadataURI
+	})).Return(nil).Run(func(args mock.Arguments) {
+		stored <- args[1].(*core.TokenMetadata)
+	}).Once()
+	mdi.On("UpsertTokenMetadata", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		stored <- args[1].(*core.TokenMetadata)
+	}).Once()
+
+	am.metadata.start()
+	am.metadata.queueFetch(testMetadataURI)
+	metadata := <-stored
+	assert.Equal(t, "Token 1", metadata.Name)
+
+	// Failures to store are logged, and the URI can be queued again
+	am.metadata.queueFetch("ftp://example.com/token/1")
+	metadata = <-stored
+	assert.Regexp(t, "FF10479", metadata.Error)
+	for {
+		am.metadata.queuedLock.Lock()
+		remaining := len(am.metadata.queued)
+		am.metadata.queuedLock.Unlock()
+		if remaining == 0 {
+			break
+		}
+		time.Sleep(1 * time.Millisecond)
+	}
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMetadataQueueFetch(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+	am.metadata.queue = make(chan string, 1)
+
+	am.metadata.queueFetch("https://example.com/token/1")
+	am.metadata.queueFetch("https://example.com/token/1")
+	assert.Len(t, am.metadata.queue, 1)
+
+	// Queue is full, so the URI is skipped until a later query
+	am.metadata.queueFetch("https://example.com/token/2")
+	assert.Len(t, am.metadata.queue, 1)
+	assert.False(t, am.metadata.queued["https://example.com/token/2"])
+}
+
+func TestMetadataLookup(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+
+	cached := &core.TokenMetadata{URI: "https://example.com/token/1", Name: "Token 1", Updated: fftypes.Now()}
+	recentFailure := &core.TokenMetadata{URI: "https://example.com/token/2", Error: "pop", Updated: fftypes.Now()}
+	staleTime := time.Now().Add(-2 * time.Hour)
+	staleFailure := &core.TokenMetadata{URI: "https://example.com/token/3", Error: "pop", Updated: (*fftypes.FFTime)(&staleTime)}
+	mdi.On("GetTokenMetadata", mock.Anything, "ns1", mock.Anything).Return([]*core.TokenMetadata{cached, recentFailure, staleFailure}, nil, nil)
+
+	results, err := am.metadata.lookup(context.Background(), []string{
+		"https://example.com/token/1",
+		"https://example.com/token/1",
+		"https://example.com/token/2",
+		"https://example.com/token/3",
+		"https://example.com/token/4",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, cached, results["https://example.com/token/1"])
+
+	// Only the stale failure and the uncached URI are fetched
+	assert.Len(t, am.metadata.queue, 2)
+	assert.True(t, am.metadata.queued["https://example.com/token/3"])
+	assert.True(t, am.metadata.queued["https://example.com/token/4"])
+
+	results, err = am.metadata.lookup(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestGetTokenTransfersWithMetadata(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+
+	transfers := []*core.TokenTransfer{
+		{URI: "https://example.com/{id}.json", TokenIndex: "1"},
+		{TokenIndex: "2"},
+	}
+	resolvedURI := "https://example.com/0000000000000000000000000000000000000000000000000000000000000001.json"
+	cached := &core.TokenMetadata{URI: resolvedURI, Name: "Token 1", Updated: fftypes.Now()}
+	f := database.TokenTransferQueryFactory.NewFilter(context.Background()).And()
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", f).Return(transfers, nil, nil)
+	mdi.On("GetTokenMetadata", context.Background(), "ns1", mock.Anything).Return([]*core.TokenMetadata{cached}, nil, nil)
+
+	results, _, err := am.GetTokenTransfers(context.Background(), f)
+	assert.NoError(t, err)
+	assert.Equal(t, cached, results[0].Metadata)
+	assert.Nil(t, results[1].Metadata)
+}
+
+func TestGetTokenTransferByIDWithMetadata(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+
+	transfer := &core.TokenTransfer{LocalID: fftypes.NewUUID(), URI: testMetadataURI}
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", transfer.LocalID).Return(transfer, nil)
+	mdi.On("GetTokenMetadata", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.GetTokenTransferByID(context.Background(), transfer.LocalID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenBalancesWithMetadata(t *testing.T) {
+	am, cancel := newTestMetadataIndexer(t)
+	defer cancel()
+	mdi := am.database.(*databasemocks.Plugin)
+
+	balances := []*core.TokenBalance{{URI: testMetadataURI}, {}}
+	cached := &core.TokenMetadata{URI: testMetadataURI, Name: "Token 1", Updated: fftypes.Now()}
+	f := database.TokenBalanceQueryFactory.NewFilter(context.Background()).And()
+	mdi.On("GetTokenBalances", context.Background(), "ns1", f).Return(balances, nil, nil)
+	mdi.On("GetTokenMetadata", context.Background(), "ns1", mock.Anything).Return([]*core.TokenMetadata{cached}, nil, nil).Once()
+
+	results, _, err := am.GetTokenBalances(context.Background(), f)
+	assert.NoError(t, err)
+	assert.Equal(t, cached, results[0].Metadata)
+	assert.Nil(t, results[1].Metadata)
+
+	mdi.On("GetTokenMetadata", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, _, err = am.GetTokenBalances(context.Background(), f)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestURIForErrors(t *testing.T) {
+	assert.Equal(t, testMetadataURI, uriForErrors(testMetadataURI))
+	assert.Equal(t, "data:application/json;base64,...", uriForErrors("data:application/json;base64,eyJuYW1lIjoiVG9rZW4gMSJ9"))
+	assert.Equal(t, "data:application/json", uriForErrors("data:application/json"))
+}
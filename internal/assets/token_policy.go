@@ -0,0 +1,145 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const policyTransferPageSize = 100
+
+func (am *assetManager) SetTokenTransferPolicy(ctx context.Context, poolNameOrID string, policy *core.TokenTransferPolicy) (*core.TokenTransferPolicy, error) {
+	if policy.MaxAmount != nil && policy.MaxAmount.Int().Sign() < 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPolicyNegativeAmount, "maxAmount")
+	}
+	if policy.DailyLimit != nil && policy.DailyLimit.Int().Sign() < 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPolicyNegativeAmount, "dailyLimit")
+	}
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	policy.Namespace = am.namespace
+	policy.Pool = pool.ID
+	if err = am.database.UpsertTokenTransferPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (am *assetManager) GetTokenTransferPolicy(ctx context.Context, poolNameOrID string) (*core.TokenTransferPolicy, error) {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return am.database.GetTokenTransferPolicy(ctx, am.namespace, pool.ID)
+}
+
+func (am *assetManager) DeleteTokenTransferPolicy(ctx context.Context, poolNameOrID string) error {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return err
+	}
+	return am.database.DeleteTokenTransferPolicy(ctx, am.namespace, pool.ID)
+}
+
+// checkTransferPolicy evaluates the policy for the pool (if any) against a transfer that is about to be submitted.
+// Mints have no sender and burns have no recipient, so only the relevant allowlist is checked for each.
+func (am *assetManager) checkTransferPolicy(ctx context.Context, pool *core.TokenPool, transfer *core.TokenTransfer) error {
+	policy, err := am.database.GetTokenTransferPolicy(ctx, am.namespace, pool.ID)
+	if err != nil || policy == nil {
+		return err
+	}
+	if err = am.evaluateTransferPolicy(ctx, pool, policy, transfer); err != nil {
+		log.L(ctx).Warnf("Token %s in pool '%s' denied by policy: key=%s from=%s to=%s amount=%s: %s",
+			transfer.Type, pool.ID, transfer.Key, transfer.From, transfer.To, transfer.Amount.String(), err)
+	}
+	return err
+}
+
+func (am *assetManager) evaluateTransferPolicy(ctx context.Context, pool *core.TokenPool, policy *core.TokenTransferPolicy, transfer *core.TokenTransfer) error {
+	sender := transfer.Type != core.TokenTransferTypeMint
+	recipient := transfer.Type != core.TokenTransferTypeBurn
+
+	for _, denied := range policy.Denied {
+		if denied == transfer.Key || (sender && denied == transfer.From) || (recipient && denied == transfer.To) {
+			return i18n.NewError(ctx, coremsgs.MsgTokenPolicyAccountDenied, denied, pool.Name)
+		}
+	}
+	if sender && len(policy.Senders) > 0 && !policyListContains(policy.Senders, transfer.From) {
+		return i18n.NewError(ctx, coremsgs.MsgTokenPolicySenderNotAllowed, transfer.From, pool.Name)
+	}
+	if recipient && len(policy.Recipients) > 0 && !policyListContains(policy.Recipients, transfer.To) {
+		return i18n.NewError(ctx, coremsgs.MsgTokenPolicyRecipientNotAllowed, transfer.To, pool.Name)
+	}
+	if policy.MaxAmount != nil && transfer.Amount.Int().Cmp(policy.MaxAmount.Int()) > 0 {
+		return i18n.NewError(ctx, coremsgs.MsgTokenPolicyMaxAmount, transfer.Amount.String(), policy.MaxAmount.String(), pool.Name)
+	}
+	if policy.DailyLimit != nil {
+		total, err := am.getDailyTransferTotal(ctx, pool.ID, transfer.Key)
+		if err != nil {
+			return err
+		}
+		if total.Int().Add(total.Int(), transfer.Amount.Int()).Cmp(policy.DailyLimit.Int()) > 0 {
+			return i18n.NewError(ctx, coremsgs.MsgTokenPolicyDailyLimit, transfer.Amount.String(), transfer.Key, policy.DailyLimit.String(), pool.Name)
+		}
+	}
+	return nil
+}
+
+// getDailyTransferTotal sums everything the key has moved in the pool over the last 24 hours.
+// Only confirmed transfers are recorded, so any that are still in flight are not included.
+func (am *assetManager) getDailyTransferTotal(ctx context.Context, poolID *fftypes.UUID, key string) (*fftypes.FFBigInt, error) {
+	since := fftypes.FFTime(time.Now().Add(-24 * time.Hour))
+	total := fftypes.NewFFBigInt(0)
+	fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	for skip := uint64(0); ; skip += policyTransferPageSize {
+		filter := fb.And(
+			fb.Eq("pool", poolID),
+			fb.Eq("key", key),
+			fb.Eq("invalidated", false),
+			fb.Gte("created", &since),
+		).Sort("created").Skip(skip).Limit(policyTransferPageSize)
+		transfers, _, err := am.database.GetTokenTransfers(ctx, am.namespace, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, transfer := range transfers {
+			total.Int().Add(total.Int(), transfer.Amount.Int())
+		}
+		if len(transfers) < policyTransferPageSize {
+			return total, nil
+		}
+	}
+}
+
+func policyListContains(list fftypes.FFStringArray, account string) bool {
+	for _, entry := range list {
+		if entry == account {
+			return true
+		}
+	}
+	return false
+}
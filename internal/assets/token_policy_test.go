@@ -0,0 +1,269 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetTokenTransferPolicy(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("UpsertTokenTransferPolicy", context.Background(), mock.MatchedBy(func(policy *core.TokenTransferPolicy) bool {
+		return policy.Pool.Equals(pool.ID) && policy.Namespace == "ns1"
+	})).Return(nil)
+
+	policy, err := am.SetTokenTransferPolicy(context.Background(), "pool1", &core.TokenTransferPolicy{
+		MaxAmount: fftypes.NewFFBigInt(10),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, pool.ID, policy.Pool)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSetTokenTransferPolicyNegative(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.SetTokenTransferPolicy(context.Background(), "pool1", &core.TokenTransferPolicy{
+		MaxAmount: fftypes.NewFFBigInt(-1),
+	})
+	assert.Regexp(t, "FF10496.*maxAmount", err)
+
+	_, err = am.SetTokenTransferPolicy(context.Background(), "pool1", &core.TokenTransferPolicy{
+		DailyLimit: fftypes.NewFFBigInt(-1),
+	})
+	assert.Regexp(t, "FF10496.*dailyLimit", err)
+}
+
+func TestSetTokenTransferPolicyBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.SetTokenTransferPolicy(context.Background(), "pool1", &core.TokenTransferPolicy{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSetTokenTransferPolicyUpsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(newTestPool(), nil)
+	mdi.On("UpsertTokenTransferPolicy", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.SetTokenTransferPolicy(context.Background(), "pool1", &core.TokenTransferPolicy{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenTransferPolicy(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(&core.TokenTransferPolicy{}, nil)
+
+	_, err := am.GetTokenTransferPolicy(context.Background(), "pool1")
+	assert.NoError(t, err)
+}
+
+func TestGetTokenTransferPolicyBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.GetTokenTransferPolicy(context.Background(), "pool1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteTokenTransferPolicy(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("DeleteTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil)
+
+	err := am.DeleteTokenTransferPolicy(context.Background(), "pool1")
+	assert.NoError(t, err)
+}
+
+func TestDeleteTokenTransferPolicyBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	err := am.DeleteTokenTransferPolicy(context.Background(), "pool1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckTransferPolicyRules(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	policy := &core.TokenTransferPolicy{
+		MaxAmount:  fftypes.NewFFBigInt(10),
+		Senders:    fftypes.FFStringArray{"0x01", "0x02"},
+		Recipients: fftypes.FFStringArray{"0x03"},
+		Denied:     fftypes.FFStringArray{"0x02"},
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(policy, nil)
+
+	check := func(transferType core.TokenTransferType, key, from, to string, amount int64) error {
+		transfer := &core.TokenTransfer{Type: transferType, Key: key, From: from, To: to}
+		transfer.Amount.Int().SetInt64(amount)
+		return am.checkTransferPolicy(context.Background(), pool, transfer)
+	}
+
+	assert.NoError(t, check(core.TokenTransferTypeTransfer, "0x01", "0x01", "0x03", 10))
+	assert.Regexp(t, "FF10494", check(core.TokenTransferTypeTransfer, "0x01", "0x01", "0x03", 11))
+	assert.Regexp(t, "FF10491.*0x02", check(core.TokenTransferTypeTransfer, "0x02", "0x01", "0x03", 1))
+	assert.Regexp(t, "FF10491.*0x02", check(core.TokenTransferTypeTransfer, "0x01", "0x02", "0x03", 1))
+	assert.Regexp(t, "FF10492.*0x04", check(core.TokenTransferTypeTransfer, "0x01", "0x04", "0x03", 1))
+	assert.Regexp(t, "FF10493.*0x04", check(core.TokenTransferTypeTransfer, "0x01", "0x01", "0x04", 1))
+
+	// Mints are not checked against the senders, and burns are not checked against the recipients
+	assert.NoError(t, check(core.TokenTransferTypeMint, "0x01", "0x01", "0x03", 1))
+	assert.Regexp(t, "FF10493", check(core.TokenTransferTypeMint, "0x01", "0x01", "0x01", 1))
+	assert.NoError(t, check(core.TokenTransferTypeBurn, "0x01", "0x01", "0x01", 1))
+	assert.Regexp(t, "FF10492", check(core.TokenTransferTypeBurn, "0x01", "0x05", "0x05", 1))
+}
+
+func TestCheckTransferPolicyNone(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+
+	err := am.checkTransferPolicy(context.Background(), pool, &core.TokenTransfer{})
+	assert.NoError(t, err)
+}
+
+func TestCheckTransferPolicyFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, fmt.Errorf("pop"))
+
+	err := am.checkTransferPolicy(context.Background(), pool, &core.TokenTransfer{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckTransferPolicyDailyLimit(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(&core.TokenTransferPolicy{
+		DailyLimit: fftypes.NewFFBigInt(150),
+	}, nil)
+
+	// A full page of transfers, followed by a partial page
+	page := make([]*core.TokenTransfer, policyTransferPageSize)
+	for i := range page {
+		page[i] = &core.TokenTransfer{}
+		page[i].Amount.Int().SetInt64(1)
+	}
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == 0
+	})).Return(page, nil, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == policyTransferPageSize
+	})).Return(page[0:40], nil, nil)
+
+	transfer := &core.TokenTransfer{Type: core.TokenTransferTypeTransfer, Key: "0x01"}
+	transfer.Amount.Int().SetInt64(10)
+	err := am.checkTransferPolicy(context.Background(), pool, transfer)
+	assert.NoError(t, err)
+
+	transfer.Amount.Int().SetInt64(11)
+	err = am.checkTransferPolicy(context.Background(), pool, transfer)
+	assert.Regexp(t, "FF10495.*0x01", err)
+}
+
+func TestCheckTransferPolicyDailyLimitQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(&core.TokenTransferPolicy{
+		DailyLimit: fftypes.NewFFBigInt(150),
+	}, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := am.checkTransferPolicy(context.Background(), pool, &core.TokenTransfer{Key: "0x01"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckTransferEligibilityPolicyDenied(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(&core.TokenTransferPolicy{
+		Denied: fftypes.FFStringArray{"0x12345"},
+	}, nil)
+
+	_, err := am.CheckTransferEligibility(context.Background(), &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{To: "0x67890"},
+		Pool:          "pool1",
+	})
+	assert.Regexp(t, "FF10491", err)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
@@ -51,6 +51,18 @@ func (am *assetManager) CreateTokenPool(ctx context.Context, pool *core.TokenPoo
 		pool.Connector = connector
 	}
 
+	// Pass the namespace default confirmations to the connector, unless overridden on the pool
+	if _, ok := pool.Config["confirmations"]; ok {
+		if confirmations := pool.Config.GetInt64("confirmations"); confirmations < 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidConfirmations, confirmations)
+		}
+	} else if am.confirmations > 0 {
+		if pool.Config == nil {
+			pool.Config = fftypes.JSONObject{}
+		}
+		pool.Config["confirmations"] = am.confirmations
+	}
+
 	if pool.Interface != nil {
 		if err := am.contracts.ResolveFFIReference(ctx, pool.Interface); err != nil {
 			return nil, err
@@ -252,6 +264,93 @@ func (am *assetManager) DeleteTokenPool(ctx context.Context, poolNameOrID string
 		if err = am.database.DeleteTokenBalances(ctx, am.namespace, pool.ID); err != nil {
 			return err
 		}
+		if err = am.database.DeleteTokenBalanceMismatches(ctx, am.namespace, pool.ID); err != nil {
+			return err
+		}
+		if err = am.database.DeleteTokenTransferPolicy(ctx, am.namespace, pool.ID); err != nil {
+			return err
+		}
 		return plugin.DeactivateTokenPool(ctx, pool)
 	})
 }
+
+func (am *assetManager) PauseTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	return am.updateTokenPoolState(ctx, poolNameOrID, core.TokenPoolStatePaused, core.EventTypePoolPaused,
+		core.TokenPoolStateConfirmed)
+}
+
+func (am *assetManager) ResumeTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	return am.updateTokenPoolState(ctx, poolNameOrID, core.TokenPoolStateConfirmed, core.EventTypePoolResumed,
+		core.TokenPoolStatePaused)
+}
+
+func (am *assetManager) RetireTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	return am.updateTokenPoolState(ctx, poolNameOrID, core.TokenPoolStateRetired, core.EventTypePoolRetired,
+		core.TokenPoolStateConfirmed, core.TokenPoolStatePaused)
+}
+
+// updateTokenPoolState moves a pool to a new lifecycle state, if it is currently in one of the allowed states.
+// These states are local to this node, and only affect what this node will submit to the connector - events
+// from the blockchain for the pool continue to be indexed regardless of state.
+func (am *assetManager) updateTokenPoolState(ctx context.Context, poolNameOrID string, newState core.TokenPoolState, eventType core.EventType, allowed ...core.TokenPoolState) (pool *core.TokenPool, err error) {
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if pool, err = am.GetTokenPoolByNameOrID(ctx, poolNameOrID); err != nil {
+			return err
+		}
+		// Re-read the pool, as the cached copy may have a stale state
+		if pool, err = am.database.GetTokenPoolByID(ctx, am.namespace, pool.ID); err != nil {
+			return err
+		} else if pool == nil {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+
+		valid := false
+		for _, state := range allowed {
+			if pool.State == state {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return i18n.NewError(ctx, coremsgs.MsgTokenPoolInvalidStateChange, pool.State, newState)
+		}
+
+		pool.State = newState
+		if err = am.database.UpsertTokenPool(ctx, pool, database.UpsertOptimizationExisting); err != nil {
+			return err
+		}
+		event := core.NewEvent(eventType, am.namespace, pool.ID, nil, pool.ID.String())
+		return am.database.InsertEvent(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	am.cacheTokenPool(pool, poolNameOrID)
+	return pool, nil
+}
+
+// cacheTokenPool replaces any cached copies of the pool (including under any other name or ID it was looked up by),
+// so that a change in its state is enforced immediately
+func (am *assetManager) cacheTokenPool(pool *core.TokenPool, aliases ...string) {
+	for _, key := range append(aliases, pool.Name, pool.ID.String()) {
+		am.cache.Set(fmt.Sprintf("ns=%s,poolnameorid=%s", am.namespace, key), pool)
+	}
+	am.cache.Set(fmt.Sprintf("ns=%s,connector=%s,poollocator=%s", am.namespace, pool.Connector, pool.Locator), pool)
+}
+
+// checkTokenPoolActive returns an error if new transfers or approvals cannot be submitted against the pool
+func checkTokenPoolActive(ctx context.Context, pool *core.TokenPool) error {
+	switch pool.State {
+	case core.TokenPoolStateConfirmed:
+		return nil
+	case core.TokenPoolStatePaused:
+		return i18n.NewError(ctx, coremsgs.MsgTokenPoolPaused, pool.Name)
+	case core.TokenPoolStateRetired:
+		return i18n.NewError(ctx, coremsgs.MsgTokenPoolRetired, pool.Name)
+	case core.TokenPoolStateMigrating:
+		return i18n.NewError(ctx, coremsgs.MsgTokenPoolMigrating, pool.Name)
+	default:
+		return i18n.NewError(ctx, coremsgs.MsgTokenPoolNotConfirmed)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+// MigrateTokenPool re-points a confirmed (or paused) pool to a different token connector. The new connector is
+// asked to resolve the pool from the supplied config (such as the address of the existing contract), and the
+// pool is re-pointed once the details it reports have been verified against the existing pool.
+// Transfers and approvals cannot be submitted while the migration is in progress.
+func (am *assetManager) MigrateTokenPool(ctx context.Context, poolNameOrID string, input *core.TokenPoolMigrationInput) (migration *core.TokenPoolMigration, err error) {
+	plugin, err := am.selectTokenPlugin(ctx, input.Connector)
+	if err != nil {
+		return nil, err
+	}
+
+	var pool *core.TokenPool
+	var op *core.Operation
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if pool, err = am.GetTokenPoolByNameOrID(ctx, poolNameOrID); err != nil {
+			return err
+		}
+		// Re-read the pool, as the cached copy may have a stale state
+		if pool, err = am.database.GetTokenPoolByID(ctx, am.namespace, pool.ID); err != nil {
+			return err
+		} else if pool == nil {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		if pool.State != core.TokenPoolStateConfirmed && pool.State != core.TokenPoolStatePaused {
+			return i18n.NewError(ctx, coremsgs.MsgTokenPoolInvalidStateChange, pool.State, core.TokenPoolStateMigrating)
+		}
+		if pool.Connector == input.Connector {
+			return i18n.NewError(ctx, coremsgs.MsgTokenPoolMigrationSameConnector, pool.Name, pool.Connector)
+		}
+
+		// Pass the namespace default confirmations to the connector, unless overridden on the migration
+		config := input.Config
+		if am.confirmations > 0 {
+			if config == nil {
+				config = fftypes.JSONObject{}
+			}
+			if _, ok := config["confirmations"]; !ok {
+				config["confirmations"] = am.confirmations
+			}
+		}
+
+		txid, err := am.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTokenPool, "")
+		if err != nil {
+			return err
+		}
+
+		migration = &core.TokenPoolMigration{
+			ID:            fftypes.NewUUID(),
+			Namespace:     am.namespace,
+			Pool:          pool.ID,
+			State:         core.TokenPoolMigrationStatePending,
+			FromConnector: pool.Connector,
+			FromLocator:   pool.Locator,
+			ToConnector:   input.Connector,
+			PoolState:     pool.State,
+			Config:        config,
+			TX: core.TransactionRef{
+				ID:   txid,
+				Type: core.TransactionTypeTokenPool,
+			},
+		}
+		if err = am.database.InsertTokenPoolMigration(ctx, migration); err != nil {
+			return err
+		}
+
+		pool.State = core.TokenPoolStateMigrating
+		if err = am.database.UpsertTokenPool(ctx, pool, database.UpsertOptimizationExisting); err != nil {
+			return err
+		}
+
+		op = core.NewOperation(
+			plugin,
+			am.namespace,
+			txid,
+			core.OpTypeTokenMigratePool)
+		txcommon.AddTokenPoolMigrateInputs(op, migration.ID)
+		return am.operations.AddOrReuseOperation(ctx, op)
+	})
+	if err != nil {
+		return nil, err
+	}
+	am.cacheTokenPool(pool, poolNameOrID)
+
+	// If the operation fails, the migration is marked as failed and the pool is returned to its previous state
+	_, err = am.operations.RunOperation(ctx, opMigratePool(op, pool, migration))
+	return migration, err
+}
+
+func (am *assetManager) GetTokenPoolMigrations(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenPoolMigration, *ffapi.FilterResult, error) {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return am.database.GetTokenPoolMigrations(ctx, am.namespace, filter.Condition(filter.Builder().Eq("pool", pool.ID)))
+}
+
+// CompleteTokenPoolMigration is called when a token connector reports a pool that the event manager does not
+// otherwise expect, to check if it is the new connector resolving a pool that is being migrated.
+// If the details from the new connector match the existing pool, the pool is re-pointed to the new connector
+// and returned, ready to be activated so that the new connector starts delivering its events. The returned
+// pool carries the transaction of the migration, so that the activation is recorded against the migration.
+// Returns nil if the pool is not the subject of a pending migration to this connector.
+func (am *assetManager) CompleteTokenPoolMigration(ctx context.Context, resolved *tokens.TokenPool) (*core.TokenPool, error) {
+	if resolved.TX.ID == nil {
+		return nil, nil
+	}
+	fb := database.TokenPoolMigrationQueryFactory.NewFilter(ctx)
+	migrations, _, err := am.database.GetTokenPoolMigrations(ctx, am.namespace, fb.And(
+		fb.Eq("tx.id", resolved.TX.ID),
+		fb.Eq("state", core.TokenPoolMigrationStatePending),
+	))
+	if err != nil || len(migrations) == 0 {
+		return nil, err
+	}
+	migration := migrations[0]
+	if migration.ToConnector != resolved.Connector {
+		log.L(ctx).Debugf("Ignoring token pool from connector '%s' for migration '%s' to connector '%s'", resolved.Connector, migration.ID, migration.ToConnector)
+		return nil, nil
+	}
+
+	pool, err := am.database.GetTokenPoolByID(ctx, am.namespace, migration.Pool)
+	if err != nil || pool == nil {
+		return nil, err
+	}
+	if mismatch := compareMigratedPool(pool, resolved); mismatch != "" {
+		reason := i18n.NewError(ctx, coremsgs.MsgTokenPoolMigrationMismatch, resolved.Connector, mismatch)
+		return nil, am.failTokenPoolMigration(ctx, migration, reason.Error())
+	}
+
+	previous := *pool
+	pool.Connector = resolved.Connector
+	pool.Locator = resolved.PoolLocator
+	pool.PluginData = resolved.PluginData
+	pool.Standard = resolved.Standard
+	pool.InterfaceFormat = core.TokenInterfaceFormat(resolved.InterfaceFormat)
+	pool.IdentityRegistry = resolved.IdentityRegistry
+	if resolved.Info != nil {
+		pool.Info = resolved.Info
+	}
+	pool.State = migration.PoolState
+
+	// The methods for a pool tied to a contract interface are specific to the connector
+	if pool.Interface != nil && pool.Interface.ID != nil && pool.InterfaceFormat != "" {
+		if err := am.ResolvePoolMethods(ctx, pool); err != nil {
+			return nil, err
+		}
+	}
+	if err := am.database.UpsertTokenPool(ctx, pool, database.UpsertOptimizationExisting); err != nil {
+		return nil, err
+	}
+
+	update := database.TokenPoolMigrationQueryFactory.NewUpdate(ctx).
+		Set("state", core.TokenPoolMigrationStateSucceeded).
+		Set("tolocator", pool.Locator)
+	if err := am.database.UpdateTokenPoolMigration(ctx, am.namespace, migration.ID, update); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Token pool migrated, id=%s connector=%s locator='%s'", pool.ID, pool.Connector, pool.Locator)
+	event := core.NewEvent(core.EventTypePoolMigrated, am.namespace, pool.ID, migration.TX.ID, pool.ID.String())
+	event.Correlator = migration.ID
+	if err := am.database.InsertEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	am.cacheTokenPool(pool)
+
+	// Stop the previous connector from delivering events for the pool
+	plugin, err := am.selectTokenPlugin(ctx, previous.Connector)
+	if err == nil {
+		err = plugin.DeactivateTokenPool(ctx, &previous)
+	}
+	if err != nil {
+		log.L(ctx).Warnf("Failed to deactivate token pool '%s' on previous connector '%s': %s", pool.ID, previous.Connector, err)
+	}
+
+	activate := *pool
+	activate.TX = migration.TX
+	return &activate, nil
+}
+
+// compareMigratedPool returns the name of the first field reported by the new connector that does not match
+// the existing pool, or an empty string if the pool matches
+func compareMigratedPool(pool *core.TokenPool, resolved *tokens.TokenPool) string {
+	switch {
+	case pool.Type != resolved.Type:
+		return "type"
+	case pool.Decimals != resolved.Decimals:
+		return "decimals"
+	case pool.Symbol != "" && resolved.Symbol != "" && pool.Symbol != resolved.Symbol:
+		return "symbol"
+	}
+	address, newAddress := pool.Info.GetString("address"), resolved.Info.GetString("address")
+	if address != "" && newAddress != "" && !strings.EqualFold(address, newAddress) {
+		return "address"
+	}
+	return ""
+}
+
+// failTokenPoolMigration leaves the pool on its previous connector, and returns it to its previous state
+func (am *assetManager) failTokenPoolMigration(ctx context.Context, migration *core.TokenPoolMigration, reason string) error {
+	pool, err := am.database.GetTokenPoolByID(ctx, am.namespace, migration.Pool)
+	if err != nil {
+		return err
+	}
+	if pool != nil && pool.State == core.TokenPoolStateMigrating {
+		pool.State = migration.PoolState
+		if err := am.database.UpsertTokenPool(ctx, pool, database.UpsertOptimizationExisting); err != nil {
+			return err
+		}
+		am.cacheTokenPool(pool)
+	}
+
+	update := database.TokenPoolMigrationQueryFactory.NewUpdate(ctx).
+		Set("state", core.TokenPoolMigrationStateFailed).
+		Set("error", reason)
+	if err := am.database.UpdateTokenPoolMigration(ctx, am.namespace, migration.ID, update); err != nil {
+		return err
+	}
+	log.L(ctx).Errorf("Migration of token pool '%s' to connector '%s' failed: %s", migration.Pool, migration.ToConnector, reason)
+	event := core.NewEvent(core.EventTypePoolMigrationFailed, am.namespace, migration.Pool, migration.TX.ID, migration.Pool.String())
+	event.Correlator = migration.ID
+	return am.database.InsertEvent(ctx, event)
+}
+
+func (am *assetManager) onTokenPoolMigrationFailed(ctx context.Context, op *core.Operation, update *core.OperationUpdate) error {
+	migrationID, err := txcommon.RetrieveTokenPoolMigrateInputs(ctx, op)
+	if err != nil {
+		log.L(ctx).Warnf("Could not parse token pool migration: %s (%+v)", err, op.Input)
+		return nil
+	}
+	migration, err := am.database.GetTokenPoolMigrationByID(ctx, am.namespace, migrationID)
+	if err != nil {
+		return err
+	}
+	if migration == nil || migration.State != core.TokenPoolMigrationStatePending {
+		return nil
+	}
+	return am.failTokenPoolMigration(ctx, migration, update.ErrorMessage)
+}
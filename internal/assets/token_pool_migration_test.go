@@ -0,0 +1,650 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/contractmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func addTestMigrationConnector(am *assetManager) *tokenmocks.Plugin {
+	mti := &tokenmocks.Plugin{}
+	mti.On("Name").Return("ut").Maybe()
+	am.tokens["new-tokens"] = mti
+	am.breakers["new-tokens"] = newConnectorBreaker("new-tokens", mti, &am.circuitBreaker)
+	return mti
+}
+
+func newTestMigratingPool() (*core.TokenPool, *core.TokenPoolMigration) {
+	pool := newTestPool(func(p *core.TokenPool) {
+		p.State = core.TokenPoolStateMigrating
+		p.Info = fftypes.JSONObject{"address": "0xABCDEF"}
+	})
+	migration := &core.TokenPoolMigration{
+		ID:            fftypes.NewUUID(),
+		Namespace:     "ns1",
+		Pool:          pool.ID,
+		State:         core.TokenPoolMigrationStatePending,
+		FromConnector: "magic-tokens",
+		FromLocator:   "F1",
+		ToConnector:   "new-tokens",
+		PoolState:     core.TokenPoolStatePaused,
+		TX: core.TransactionRef{
+			ID:   fftypes.NewUUID(),
+			Type: core.TransactionTypeTokenPool,
+		},
+	}
+	return pool, migration
+}
+
+func newTestResolvedPool(migration *core.TokenPoolMigration) *tokens.TokenPool {
+	return &tokens.TokenPool{
+		Type:        core.TokenTypeFungible,
+		PoolLocator: "address=0xabcdef",
+		Connector:   "new-tokens",
+		Standard:    "ERC20",
+		Info:        fftypes.JSONObject{"address": "0xabcdef"},
+		TX:          migration.TX,
+	}
+}
+
+func TestMigrateTokenPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.confirmations = 5
+	addTestMigrationConnector(am)
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Name:      "pool1",
+		Connector: "magic-tokens",
+		Locator:   "F1",
+		State:     core.TokenPoolStatePaused,
+	}
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("")).Return(txID, nil)
+	mdi.On("InsertTokenPoolMigration", context.Background(), mock.MatchedBy(func(migration *core.TokenPoolMigration) bool {
+		return migration.Pool.Equals(pool.ID) &&
+			migration.State == core.TokenPoolMigrationStatePending &&
+			migration.FromConnector == "magic-tokens" &&
+			migration.FromLocator == "F1" &&
+			migration.ToConnector == "new-tokens" &&
+			migration.PoolState == core.TokenPoolStatePaused &&
+			migration.Config.GetString("address") == "0x12345" &&
+			migration.Config.GetInt64("confirmations") == 5 &&
+			migration.TX.ID.Equals(txID)
+	})).Return(nil)
+	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *core.TokenPool) bool {
+		return p.State == core.TokenPoolStateMigrating
+	}), database.UpsertOptimizationExisting).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTokenMigratePool && op.Transaction.Equals(txID)
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(migratePoolData)
+		return data.Pool.ID.Equals(pool.ID) &&
+			data.Pool.Connector == "new-tokens" &&
+			data.Pool.Config.GetString("address") == "0x12345" &&
+			data.Pool.TX.ID.Equals(txID)
+	})).Return(nil, nil)
+
+	migration, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+		Config:    fftypes.JSONObject{"address": "0x12345"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "new-tokens", migration.ToConnector)
+
+	// The cached pool reflects the migrating state
+	cached, err := am.GetTokenPoolByNameOrID(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF10511", checkTokenPoolActive(context.Background(), cached))
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolBadConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "bad",
+	})
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestMigrateTokenPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	addTestMigrationConnector(am)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+	})
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolRereadFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	addTestMigrationConnector(am)
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolDeleted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	addTestMigrationConnector(am)
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(nil, nil)
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+	})
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolBadState(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	addTestMigrationConnector(am)
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStatePending,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+	})
+	assert.Regexp(t, "FF10486", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolSameConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "magic-tokens",
+	})
+	assert.Regexp(t, "FF10512", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolSubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	addTestMigrationConnector(am)
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolInsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	addTestMigrationConnector(am)
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mdi.On("InsertTokenPoolMigration", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestMigrateTokenPoolUpsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.confirmations = 5
+	addTestMigrationConnector(am)
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mdi.On("InsertTokenPoolMigration", context.Background(), mock.Anything).Return(nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	_, err := am.MigrateTokenPool(context.Background(), "pool1", &core.TokenPoolMigrationInput{
+		Connector: "new-tokens",
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestGetTokenPoolMigrations(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID: fftypes.NewUUID(),
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{}, nil, nil)
+
+	fb := database.TokenPoolMigrationQueryFactory.NewFilter(context.Background())
+	_, _, err := am.GetTokenPoolMigrations(context.Background(), "pool1", fb.And())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenPoolMigrationsNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	fb := database.TokenPoolMigrationQueryFactory.NewFilter(context.Background())
+	_, _, err := am.GetTokenPoolMigrations(context.Background(), "pool1", fb.And())
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigration(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	mtiNew := addTestMigrationConnector(am)
+
+	pool, migration := newTestMigratingPool()
+	pool.Interface = &fftypes.FFIReference{ID: fftypes.NewUUID()}
+	pool.InterfaceFormat = core.TokenInterfaceFormatABI
+	resolved := newTestResolvedPool(migration)
+	resolved.InterfaceFormat = "abi"
+	methods := []*fftypes.FFIMethod{{Name: "transfer"}}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mcm := am.contracts.(*contractmocks.Manager)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mcm.On("GetFFIMethods", context.Background(), pool.Interface.ID).Return(methods, nil)
+	mtiNew.On("CheckInterface", context.Background(), mock.Anything, methods).Return(fftypes.JSONAnyPtr(`{"transfer":{}}`), nil)
+	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *core.TokenPool) bool {
+		return p.Connector == "new-tokens" &&
+			p.Locator == "address=0xabcdef" &&
+			p.Standard == "ERC20" &&
+			p.State == core.TokenPoolStatePaused &&
+			p.Methods.String() == `{"transfer":{}}`
+	}), database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("UpdateTokenPoolMigration", context.Background(), "ns1", migration.ID, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypePoolMigrated &&
+			event.Reference.Equals(pool.ID) &&
+			event.Correlator.Equals(migration.ID) &&
+			event.Transaction.Equals(migration.TX.ID)
+	})).Return(nil)
+	mti.On("DeactivateTokenPool", context.Background(), mock.MatchedBy(func(p *core.TokenPool) bool {
+		return p.Connector == "magic-tokens" && p.Locator == "F1"
+	})).Return(fmt.Errorf("pop"))
+
+	migrated, err := am.CompleteTokenPoolMigration(context.Background(), resolved)
+	assert.NoError(t, err)
+	assert.Equal(t, "new-tokens", migrated.Connector)
+	assert.Equal(t, migration.TX, migrated.TX)
+
+	// Transfers are accepted again once the pool is back in its previous state
+	cached, err := am.GetTokenPoolByNameOrID(context.Background(), pool.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, "new-tokens", cached.Connector)
+
+	mdi.AssertExpectations(t)
+	mcm.AssertExpectations(t)
+	mti.AssertExpectations(t)
+	mtiNew.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationNoTX(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	migrated, err := am.CompleteTokenPoolMigration(context.Background(), &tokens.TokenPool{})
+	assert.NoError(t, err)
+	assert.Nil(t, migrated)
+}
+
+func TestCompleteTokenPoolMigrationQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.CompleteTokenPoolMigration(context.Background(), newTestResolvedPool(migration))
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationOtherConnector(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	resolved := newTestResolvedPool(migration)
+	resolved.Connector = "magic-tokens"
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+
+	migrated, err := am.CompleteTokenPoolMigration(context.Background(), resolved)
+	assert.NoError(t, err)
+	assert.Nil(t, migrated)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", migration.Pool).Return(nil, nil)
+
+	migrated, err := am.CompleteTokenPoolMigration(context.Background(), newTestResolvedPool(migration))
+	assert.NoError(t, err)
+	assert.Nil(t, migrated)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationMismatch(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, migration := newTestMigratingPool()
+	resolved := newTestResolvedPool(migration)
+	resolved.Decimals = 18
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *core.TokenPool) bool {
+		return p.Connector == "magic-tokens" && p.State == core.TokenPoolStatePaused
+	}), database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("UpdateTokenPoolMigration", context.Background(), "ns1", migration.ID, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypePoolMigrationFailed &&
+			event.Reference.Equals(pool.ID) &&
+			event.Correlator.Equals(migration.ID)
+	})).Return(nil)
+
+	migrated, err := am.CompleteTokenPoolMigration(context.Background(), resolved)
+	assert.NoError(t, err)
+	assert.Nil(t, migrated)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationResolveMethodsFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	addTestMigrationConnector(am)
+
+	pool, migration := newTestMigratingPool()
+	pool.Interface = &fftypes.FFIReference{ID: fftypes.NewUUID()}
+	pool.InterfaceFormat = core.TokenInterfaceFormatABI
+	mdi := am.database.(*databasemocks.Plugin)
+	mcm := am.contracts.(*contractmocks.Manager)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mcm.On("GetFFIMethods", context.Background(), pool.Interface.ID).Return(nil, fmt.Errorf("pop"))
+
+	resolved := newTestResolvedPool(migration)
+	resolved.InterfaceFormat = "abi"
+	_, err := am.CompleteTokenPoolMigration(context.Background(), resolved)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mcm.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationUpsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, migration := newTestMigratingPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	_, err := am.CompleteTokenPoolMigration(context.Background(), newTestResolvedPool(migration))
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, migration := newTestMigratingPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("UpdateTokenPoolMigration", context.Background(), "ns1", migration.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.CompleteTokenPoolMigration(context.Background(), newTestResolvedPool(migration))
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompleteTokenPoolMigrationEventFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, migration := newTestMigratingPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolMigrations", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPoolMigration{migration}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("UpdateTokenPoolMigration", context.Background(), "ns1", migration.ID, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.CompleteTokenPoolMigration(context.Background(), newTestResolvedPool(migration))
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCompareMigratedPool(t *testing.T) {
+	pool, migration := newTestMigratingPool()
+	pool.Symbol = "FFT"
+
+	resolved := newTestResolvedPool(migration)
+	assert.Equal(t, "", compareMigratedPool(pool, resolved))
+
+	resolved.Type = core.TokenTypeNonFungible
+	assert.Equal(t, "type", compareMigratedPool(pool, resolved))
+
+	resolved = newTestResolvedPool(migration)
+	resolved.Decimals = 18
+	assert.Equal(t, "decimals", compareMigratedPool(pool, resolved))
+
+	resolved = newTestResolvedPool(migration)
+	resolved.Symbol = "ETH"
+	assert.Equal(t, "symbol", compareMigratedPool(pool, resolved))
+
+	resolved = newTestResolvedPool(migration)
+	resolved.Info = fftypes.JSONObject{"address": "0x12345"}
+	assert.Equal(t, "address", compareMigratedPool(pool, resolved))
+
+	// Connectors are not required to report the address
+	resolved.Info = nil
+	assert.Equal(t, "", compareMigratedPool(pool, resolved))
+}
+
+func TestFailTokenPoolMigrationGetPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, migration := newTestMigratingPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", migration.Pool).Return(nil, fmt.Errorf("pop"))
+
+	err := am.failTokenPoolMigration(context.Background(), migration, "failed")
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestFailTokenPoolMigrationUpsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, migration := newTestMigratingPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", migration.Pool).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	err := am.failTokenPoolMigration(context.Background(), migration, "failed")
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestFailTokenPoolMigrationUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool, migration := newTestMigratingPool()
+	pool.State = core.TokenPoolStateConfirmed
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", migration.Pool).Return(pool, nil)
+	mdi.On("UpdateTokenPoolMigration", context.Background(), "ns1", migration.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := am.failTokenPoolMigration(context.Background(), migration, "failed")
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTokenPoolMigratingRejectsTransfers(t *testing.T) {
+	err := checkTokenPoolActive(context.Background(), &core.TokenPool{
+		Name:  "pool1",
+		State: core.TokenPoolStateMigrating,
+	})
+	assert.Regexp(t, "FF10511", err)
+}
@@ -121,6 +121,89 @@ func TestCreateTokenPoolDefaultConnectorSuccess(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestCreateTokenPoolDefaultConfirmations(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.confirmations = 5
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name: "testpool",
+		},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("resolved-key", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(createPoolData)
+		return op.Type == core.OpTypeTokenCreatePool && data.Pool.Config["confirmations"] == 5
+	})).Return(nil, nil)
+
+	_, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolBadConfirmations(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name:   "testpool",
+			Config: fftypes.JSONObject{"confirmations": -1},
+		},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
+
+	_, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.Regexp(t, "FF10457", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolOverrideConfirmations(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.confirmations = 5
+
+	pool := &core.TokenPoolInput{
+		TokenPool: core.TokenPool{
+			Name:   "testpool",
+			Config: fftypes.JSONObject{"confirmations": 1},
+		},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("resolved-key", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenPool, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(createPoolData)
+		return op.Type == core.OpTypeTokenCreatePool && data.Pool.Config["confirmations"] == 1
+	})).Return(nil, nil)
+
+	_, err := am.CreateTokenPool(context.Background(), pool, false)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestCreateTokenPoolIdempotentResubmit(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -249,6 +332,7 @@ func TestCreateTokenPoolDefaultConnectorNoConnectors(t *testing.T) {
 	}
 
 	am.tokens = make(map[string]tokens.Plugin)
+	am.breakers = make(map[string]*connectorBreaker)
 
 	mdi := am.database.(*databasemocks.Plugin)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "testpool").Return(nil, nil)
@@ -775,6 +859,7 @@ func TestGetTokenPoolByLocatorBadPlugin(t *testing.T) {
 	defer cancel()
 
 	am.tokens = make(map[string]tokens.Plugin)
+	am.breakers = make(map[string]*connectorBreaker)
 	mdi := am.database.(*databasemocks.Plugin)
 	_, err := am.GetTokenPoolByLocator(context.Background(), "magic-tokens", "abc")
 	assert.Regexp(t, "FF10272", err)
@@ -1083,6 +1168,56 @@ func TestDeletePoolBalancesFail(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestDeletePoolBalanceMismatchesFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		Interface: &fftypes.FFIReference{
+			ID: fftypes.NewUUID(),
+		},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("DeleteTokenPool", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenTransfers", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenApprovals", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenBalances", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenBalanceMismatches", context.Background(), "ns1", pool.ID).Return(fmt.Errorf("pop"))
+
+	err := am.DeleteTokenPool(context.Background(), "pool1")
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeletePoolPolicyFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("DeleteTokenPool", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenTransfers", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenApprovals", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenBalances", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenBalanceMismatches", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(fmt.Errorf("pop"))
+
+	err := am.DeleteTokenPool(context.Background(), "pool1")
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
 func TestDeletePoolSuccess(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -1101,6 +1236,8 @@ func TestDeletePoolSuccess(t *testing.T) {
 	mdi.On("DeleteTokenTransfers", context.Background(), "ns1", pool.ID).Return(nil)
 	mdi.On("DeleteTokenApprovals", context.Background(), "ns1", pool.ID).Return(nil)
 	mdi.On("DeleteTokenBalances", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenBalanceMismatches", context.Background(), "ns1", pool.ID).Return(nil)
+	mdi.On("DeleteTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil)
 
 	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
 	mti.On("DeactivateTokenPool", context.Background(), pool).Return(nil)
@@ -1129,3 +1266,187 @@ func TestDeletePoolBadPlugin(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func TestPauseTokenPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Name:      "pool1",
+		Connector: "magic-tokens",
+		Locator:   "F1",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	stored := *pool
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(&stored, nil)
+	mdi.On("UpsertTokenPool", context.Background(), mock.MatchedBy(func(p *core.TokenPool) bool {
+		return p.State == core.TokenPoolStatePaused
+	}), database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypePoolPaused && event.Reference.Equals(pool.ID) && event.Topic == pool.ID.String()
+	})).Return(nil)
+
+	// Warm the cache with the confirmed pool
+	_, err := am.GetTokenPoolByNameOrID(context.Background(), "pool1")
+	assert.NoError(t, err)
+
+	result, err := am.PauseTokenPool(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenPoolStatePaused, result.State)
+
+	// The cached pool reflects the new state
+	cached, err := am.GetTokenPoolByNameOrID(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenPoolStatePaused, cached.State)
+	cached, err = am.GetTokenPoolByLocator(context.Background(), "magic-tokens", "F1")
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenPoolStatePaused, cached.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResumeTokenPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStatePaused,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypePoolResumed
+	})).Return(nil)
+
+	result, err := am.ResumeTokenPool(context.Background(), pool.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenPoolStateConfirmed, result.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRetireTokenPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStatePaused,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypePoolRetired
+	})).Return(nil)
+
+	result, err := am.RetireTokenPool(context.Background(), pool.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenPoolStateRetired, result.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResumeTokenPoolNotPaused(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStateRetired,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+
+	_, err := am.ResumeTokenPool(context.Background(), pool.ID.String())
+	assert.Regexp(t, "FF10486", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPauseTokenPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.PauseTokenPool(context.Background(), "pool1")
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPauseTokenPoolDeleted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(nil, nil)
+
+	_, err := am.PauseTokenPool(context.Background(), "pool1")
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPauseTokenPoolGetFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.PauseTokenPool(context.Background(), "pool1")
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestPauseTokenPoolUpsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		ID:    fftypes.NewUUID(),
+		State: core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool.ID).Return(pool, nil)
+	mdi.On("UpsertTokenPool", context.Background(), pool, database.UpsertOptimizationExisting).Return(fmt.Errorf("pop"))
+
+	_, err := am.PauseTokenPool(context.Background(), pool.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckTokenPoolActive(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, checkTokenPoolActive(ctx, &core.TokenPool{State: core.TokenPoolStateConfirmed}))
+	assert.Regexp(t, "FF10293", checkTokenPoolActive(ctx, &core.TokenPool{State: core.TokenPoolStatePending}))
+	assert.Regexp(t, "FF10484.*pool1", checkTokenPoolActive(ctx, &core.TokenPool{Name: "pool1", State: core.TokenPoolStatePaused}))
+	assert.Regexp(t, "FF10485.*pool1", checkTokenPoolActive(ctx, &core.TokenPool{Name: "pool1", State: core.TokenPoolStateRetired}))
+}
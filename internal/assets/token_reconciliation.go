@@ -0,0 +1,180 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+type reconciliationConfig struct {
+	interval  time.Duration
+	batchSize int
+}
+
+func (am *assetManager) reconciliationLoop() {
+	defer close(am.reconcileDone)
+
+	ticker := time.NewTicker(am.reconciliation.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-am.ctx.Done():
+			log.L(am.ctx).Debugf("Token reconciliation loop exiting")
+			return
+		case <-ticker.C:
+			if err := am.reconcileAllTokenPools(am.ctx); err != nil {
+				log.L(am.ctx).Errorf("Failed to query token pools for reconciliation: %s", err)
+			}
+		}
+	}
+}
+
+func (am *assetManager) reconcileAllTokenPools(ctx context.Context) error {
+	fb := database.TokenPoolQueryFactory.NewFilter(ctx)
+	filter := fb.And(fb.Neq("state", core.TokenPoolStatePending)).Limit(uint64(am.reconciliation.batchSize))
+	for skip := uint64(0); ; skip += uint64(am.reconciliation.batchSize) {
+		pools, _, err := am.database.GetTokenPools(ctx, am.namespace, filter.Skip(skip))
+		if err != nil {
+			return err
+		}
+		for _, pool := range pools {
+			if _, err := am.reconcileTokenPool(ctx, pool); err != nil {
+				// Retried on the next interval
+				log.L(ctx).Errorf("Failed to reconcile token pool '%s': %s", pool.ID, err)
+			}
+		}
+		if len(pools) < am.reconciliation.batchSize {
+			return nil
+		}
+	}
+}
+
+// ReconcileTokenPool compares the balance FireFly has computed for every known account in a pool with the
+// on-chain balance reported by the connector, and returns the accounts that do not match.
+func (am *assetManager) ReconcileTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenReconciliation, error) {
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if pool.State == core.TokenPoolStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolNotConfirmed)
+	}
+	return am.reconcileTokenPool(ctx, pool)
+}
+
+func (am *assetManager) reconcileTokenPool(ctx context.Context, pool *core.TokenPool) (*core.TokenReconciliation, error) {
+	plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &core.TokenReconciliation{
+		Pool:       pool.ID,
+		Mismatches: []*core.TokenBalanceMismatch{},
+	}
+	fb := database.TokenBalanceQueryFactory.NewFilter(ctx)
+	filter := fb.And(fb.Eq("pool", pool.ID)).Sort("tokenindex", "key").Ascending().Limit(uint64(am.reconciliation.batchSize))
+	for skip := uint64(0); ; skip += uint64(am.reconciliation.batchSize) {
+		balances, _, err := am.database.GetTokenBalances(ctx, am.namespace, filter.Skip(skip))
+		if err != nil {
+			return nil, err
+		}
+		for _, balance := range balances {
+			mismatch, err := am.reconcileTokenBalance(ctx, plugin, pool, balance)
+			if err != nil {
+				return nil, err
+			}
+			if mismatch != nil {
+				result.Mismatches = append(result.Mismatches, mismatch)
+			}
+		}
+		result.Accounts += int64(len(balances))
+		if len(balances) < am.reconciliation.batchSize {
+			break
+		}
+	}
+
+	result.Created = fftypes.Now()
+	log.L(ctx).Infof("Reconciled %d accounts in token pool '%s' with %d mismatches", result.Accounts, pool.ID, len(result.Mismatches))
+	return result, nil
+}
+
+// reconcileTokenBalance compares a single balance with the chain. A mismatch is persisted, and an event emitted,
+// only when it is first detected or when either balance has changed since it was last detected - so a persistent
+// discrepancy is not reported on every reconciliation. A mismatch that has since been resolved is deleted.
+//
+// Note that a mismatch can be transient, such as when a transfer has been confirmed on-chain but not yet indexed.
+func (am *assetManager) reconcileTokenBalance(ctx context.Context, plugin tokens.Plugin, pool *core.TokenPool, balance *core.TokenBalance) (*core.TokenBalanceMismatch, error) {
+	chainBalance, err := plugin.GetBalance(ctx, pool.Locator, balance.TokenIndex, balance.Key)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := am.database.GetTokenBalanceMismatch(ctx, am.namespace, pool.ID, balance.TokenIndex, balance.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	if chainBalance.Int().Cmp(balance.Balance.Int()) == 0 {
+		if existing != nil {
+			log.L(ctx).Infof("Token balance mismatch '%s' has been resolved", existing.ID)
+			return nil, am.database.DeleteTokenBalanceMismatch(ctx, am.namespace, existing.ID)
+		}
+		return nil, nil
+	}
+	if existing != nil &&
+		existing.Balance.Int().Cmp(balance.Balance.Int()) == 0 &&
+		existing.ChainBalance.Int().Cmp(chainBalance.Int()) == 0 {
+		return existing, nil
+	}
+
+	mismatch := &core.TokenBalanceMismatch{
+		ID:           fftypes.NewUUID(),
+		Namespace:    am.namespace,
+		Pool:         pool.ID,
+		TokenIndex:   balance.TokenIndex,
+		Key:          balance.Key,
+		Balance:      balance.Balance,
+		ChainBalance: *chainBalance,
+	}
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := am.database.UpsertTokenBalanceMismatch(ctx, mismatch); err != nil {
+			return err
+		}
+		event := core.NewEvent(core.EventTypeReconciliationMismatch, am.namespace, mismatch.ID, nil, pool.ID.String())
+		return am.database.InsertEvent(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.L(ctx).Warnf("Token balance mismatch in pool '%s' for account '%s' index '%s': balance=%s chainBalance=%s",
+		pool.ID, balance.Key, balance.TokenIndex, balance.Balance.String(), chainBalance.String())
+	return mismatch, nil
+}
+
+func (am *assetManager) GetTokenBalanceMismatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error) {
+	return am.database.GetTokenBalanceMismatches(ctx, am.namespace, filter)
+}
@@ -0,0 +1,284 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestReconcileBalance(pool *core.TokenPool, key string, balance int64) *core.TokenBalance {
+	b := &core.TokenBalance{
+		Pool:       pool.ID,
+		TokenIndex: "1",
+		Key:        key,
+	}
+	b.Balance.Int().SetInt64(balance)
+	return b
+}
+
+func newTestMismatch(pool *core.TokenPool, key string, balance, chainBalance int64) *core.TokenBalanceMismatch {
+	m := &core.TokenBalanceMismatch{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Pool:       pool.ID,
+		TokenIndex: "1",
+		Key:        key,
+	}
+	m.Balance.Int().SetInt64(balance)
+	m.ChainBalance.Int().SetInt64(chainBalance)
+	return m
+}
+
+func TestStartStopReconciliationLoop(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	am.reconciliation.interval = 1 * time.Millisecond
+
+	checked := make(chan struct{})
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPools", am.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(checked)
+	}).Once()
+	mdi.On("GetTokenPools", am.ctx, "ns1", mock.Anything).Return([]*core.TokenPool{}, nil, nil).Maybe()
+
+	err := am.Start()
+	assert.NoError(t, err)
+	<-checked
+	cancel()
+	am.WaitStop()
+}
+
+func TestReconcileAllTokenPools(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.reconciliation.batchSize = 1
+
+	pool := newTestPool()
+	pool.Connector = "bad"
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == 0
+	})).Return([]*core.TokenPool{pool}, nil, nil).Once()
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPool{}, nil, nil).Once()
+
+	// Failures for individual pools are logged, and retried on the next interval
+	err := am.reconcileAllTokenPools(context.Background())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReconcileAllTokenPoolsQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := am.reconcileAllTokenPools(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcileTokenPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	matched := newTestReconcileBalance(pool, "0x01", 10)
+	resolved := newTestReconcileBalance(pool, "0x02", 10)
+	unchanged := newTestReconcileBalance(pool, "0x03", 10)
+	detected := newTestReconcileBalance(pool, "0x04", 10)
+	resolvedMismatch := newTestMismatch(pool, "0x02", 10, 5)
+	unchangedMismatch := newTestMismatch(pool, "0x03", 10, 5)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{matched, resolved, unchanged, detected}, nil, nil)
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x01").Return(fftypes.NewFFBigInt(10), nil)
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x02").Return(fftypes.NewFFBigInt(10), nil)
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x03").Return(fftypes.NewFFBigInt(5), nil)
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x04").Return(fftypes.NewFFBigInt(3), nil)
+	mdi.On("GetTokenBalanceMismatch", context.Background(), "ns1", pool.ID, "1", "0x01").Return(nil, nil)
+	mdi.On("GetTokenBalanceMismatch", context.Background(), "ns1", pool.ID, "1", "0x02").Return(resolvedMismatch, nil)
+	mdi.On("GetTokenBalanceMismatch", context.Background(), "ns1", pool.ID, "1", "0x03").Return(unchangedMismatch, nil)
+	mdi.On("GetTokenBalanceMismatch", context.Background(), "ns1", pool.ID, "1", "0x04").Return(nil, nil)
+	mdi.On("DeleteTokenBalanceMismatch", context.Background(), "ns1", resolvedMismatch.ID).Return(nil)
+	mdi.On("UpsertTokenBalanceMismatch", context.Background(), mock.MatchedBy(func(m *core.TokenBalanceMismatch) bool {
+		return m.Key == "0x04" && m.Balance.Int().Int64() == 10 && m.ChainBalance.Int().Int64() == 3
+	})).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeReconciliationMismatch && event.Topic == pool.ID.String()
+	})).Return(nil)
+
+	result, err := am.ReconcileTokenPool(context.Background(), "pool1")
+	assert.NoError(t, err)
+	assert.Equal(t, pool.ID, result.Pool)
+	assert.Equal(t, int64(4), result.Accounts)
+	assert.Equal(t, 2, len(result.Mismatches))
+	assert.Equal(t, unchangedMismatch, result.Mismatches[0])
+	assert.Equal(t, "0x04", result.Mismatches[1].Key)
+	assert.NotNil(t, result.Created)
+
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestReconcileTokenPoolPaging(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.reconciliation.batchSize = 1
+
+	pool := newTestPool()
+	balance := newTestReconcileBalance(pool, "0x01", 10)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == 0
+	})).Return([]*core.TokenBalance{balance}, nil, nil).Once()
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{}, nil, nil).Once()
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x01").Return(fftypes.NewFFBigInt(10), nil)
+	mdi.On("GetTokenBalanceMismatch", context.Background(), "ns1", pool.ID, "1", "0x01").Return(nil, nil)
+
+	result, err := am.reconcileTokenPool(context.Background(), pool)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), result.Accounts)
+	assert.Empty(t, result.Mismatches)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestReconcileTokenPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.ReconcileTokenPool(context.Background(), "pool1")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcileTokenPoolNotConfirmed(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	pool.State = core.TokenPoolStatePending
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+
+	_, err := am.ReconcileTokenPool(context.Background(), "pool1")
+	assert.Regexp(t, "FF10293", err)
+}
+
+func TestReconcileTokenPoolBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	pool.Connector = "bad"
+
+	_, err := am.reconcileTokenPool(context.Background(), pool)
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestReconcileTokenPoolBalancesFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcileTokenPool(context.Background(), pool)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcileTokenPoolGetBalanceFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	balance := newTestReconcileBalance(pool, "0x01", 10)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mdi.On("GetTokenBalances", context.Background(), "ns1", mock.Anything).Return([]*core.TokenBalance{balance}, nil, nil)
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x01").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcileTokenPool(context.Background(), pool)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcileTokenBalanceGetMismatchFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	balance := newTestReconcileBalance(pool, "0x01", 10)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x01").Return(fftypes.NewFFBigInt(10), nil)
+	mdi.On("GetTokenBalanceMismatch", context.Background(), "ns1", pool.ID, "1", "0x01").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.reconcileTokenBalance(context.Background(), mti, pool, balance)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReconcileTokenBalanceChanged(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := newTestPool()
+	balance := newTestReconcileBalance(pool, "0x01", 10)
+	existing := newTestMismatch(pool, "0x01", 10, 5)
+	mdi := am.database.(*databasemocks.Plugin)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mti.On("GetBalance", context.Background(), "F1", "1", "0x01").Return(fftypes.NewFFBigInt(7), nil)
+	mdi.On("GetTokenBalanceMismatch", context.Background(), "ns1", pool.ID, "1", "0x01").Return(existing, nil)
+	mdi.On("UpsertTokenBalanceMismatch", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.reconcileTokenBalance(context.Background(), mti, pool, balance)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenBalanceMismatches(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	fb := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi.On("GetTokenBalanceMismatches", context.Background(), "ns1", f).Return([]*core.TokenBalanceMismatch{}, nil, nil)
+	_, _, err := am.GetTokenBalanceMismatches(context.Background(), f)
+	assert.NoError(t, err)
+}
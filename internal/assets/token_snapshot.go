@@ -0,0 +1,199 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const snapshotPageSize = 1000
+
+type snapshotAccount struct {
+	tokenIndex string
+	key        string
+}
+
+// CreateTokenPoolSnapshot replays the confirmed transfers of a pool up to the requested point, and
+// persists the resulting balance of every account.
+//
+// A block number cutoff relies on the protocol ID of each transfer being prefixed with a zero-padded
+// block number, as it is for the standard blockchain connectors. A timestamp cutoff is compared with
+// the time each transfer was indexed by FireFly.
+func (am *assetManager) CreateTokenPoolSnapshot(ctx context.Context, poolNameOrID string, input *core.TokenSnapshotInput) (*core.TokenSnapshot, error) {
+	if (input.BlockNumber == nil) == (input.Timestamp == nil) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenSnapshotPointRequired)
+	}
+	pool, err := am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &core.TokenSnapshot{
+		ID:          fftypes.NewUUID(),
+		Namespace:   am.namespace,
+		Pool:        pool.ID,
+		BlockNumber: input.BlockNumber,
+		Timestamp:   input.Timestamp,
+	}
+
+	totals := make(map[snapshotAccount]*big.Int)
+	adjust := func(tokenIndex, key string, amount *big.Int, negate bool) {
+		account := snapshotAccount{tokenIndex: tokenIndex, key: key}
+		total, ok := totals[account]
+		if !ok {
+			total = new(big.Int)
+			totals[account] = total
+		}
+		if negate {
+			total.Sub(total, amount)
+		} else {
+			total.Add(total, amount)
+		}
+	}
+
+	fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	conditions := []ffapi.Filter{
+		fb.Eq("pool", pool.ID),
+		fb.Eq("invalidated", false),
+	}
+	if input.BlockNumber != nil {
+		conditions = append(conditions, fb.Lt("protocolid", fmt.Sprintf("%.12d", *input.BlockNumber+1)))
+	} else {
+		conditions = append(conditions, fb.Lte("created", input.Timestamp))
+	}
+	filter := fb.And(conditions...).Sort("created", "localid").Ascending().Limit(snapshotPageSize)
+	for skip := uint64(0); ; skip += snapshotPageSize {
+		transfers, _, err := am.database.GetTokenTransfers(ctx, am.namespace, filter.Skip(skip))
+		if err != nil {
+			return nil, err
+		}
+		for _, transfer := range transfers {
+			if transfer.From != "" {
+				adjust(transfer.TokenIndex, transfer.From, transfer.Amount.Int(), true)
+			}
+			if transfer.To != "" {
+				adjust(transfer.TokenIndex, transfer.To, transfer.Amount.Int(), false)
+			}
+		}
+		snapshot.Transfers += int64(len(transfers))
+		if len(transfers) < snapshotPageSize {
+			break
+		}
+	}
+
+	balances := make([]*core.TokenSnapshotBalance, 0, len(totals))
+	for account, total := range totals {
+		if total.Sign() == 0 {
+			continue
+		}
+		balance := &core.TokenSnapshotBalance{
+			Snapshot:   snapshot.ID,
+			Namespace:  am.namespace,
+			TokenIndex: account.tokenIndex,
+			Key:        account.key,
+		}
+		balance.Balance.Int().Set(total)
+		balances = append(balances, balance)
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].TokenIndex != balances[j].TokenIndex {
+			return balances[i].TokenIndex < balances[j].TokenIndex
+		}
+		return balances[i].Key < balances[j].Key
+	})
+	snapshot.Accounts = int64(len(balances))
+	snapshot.Created = fftypes.Now()
+
+	if err := am.database.InsertTokenSnapshot(ctx, snapshot, balances); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Created snapshot '%s' of token pool '%s' with %d accounts from %d transfers", snapshot.ID, pool.ID, snapshot.Accounts, snapshot.Transfers)
+	return snapshot, nil
+}
+
+func (am *assetManager) GetTokenSnapshots(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenSnapshot, *ffapi.FilterResult, error) {
+	return am.database.GetTokenSnapshots(ctx, am.namespace, filter)
+}
+
+func (am *assetManager) GetTokenSnapshotByID(ctx context.Context, id string) (*core.TokenSnapshot, error) {
+	snapshotID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return am.database.GetTokenSnapshotByID(ctx, am.namespace, snapshotID)
+}
+
+func (am *assetManager) GetTokenSnapshotBalances(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.TokenSnapshotBalance, *ffapi.FilterResult, error) {
+	snapshot, err := am.getTokenSnapshotByIDNotNil(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return am.database.GetTokenSnapshotBalances(ctx, am.namespace, snapshot.ID, filter)
+}
+
+// ExportTokenSnapshot renders every balance in a snapshot as CSV
+func (am *assetManager) ExportTokenSnapshot(ctx context.Context, id string) (io.ReadCloser, error) {
+	snapshot, err := am.getTokenSnapshotByIDNotNil(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"tokenIndex", "key", "balance"})
+	fb := database.TokenSnapshotBalanceQueryFactory.NewFilter(ctx)
+	filter := fb.And().Sort("tokenindex", "key").Ascending().Limit(snapshotPageSize)
+	for skip := uint64(0); ; skip += snapshotPageSize {
+		balances, _, err := am.database.GetTokenSnapshotBalances(ctx, am.namespace, snapshot.ID, filter.Skip(skip))
+		if err != nil {
+			return nil, err
+		}
+		for _, balance := range balances {
+			_ = w.Write([]string{balance.TokenIndex, balance.Key, balance.Balance.String()})
+		}
+		if len(balances) < snapshotPageSize {
+			break
+		}
+	}
+	w.Flush()
+	return io.NopCloser(&buf), nil
+}
+
+func (am *assetManager) getTokenSnapshotByIDNotNil(ctx context.Context, id string) (*core.TokenSnapshot, error) {
+	snapshot, err := am.GetTokenSnapshotByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return snapshot, nil
+}
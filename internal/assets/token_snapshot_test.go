@@ -0,0 +1,266 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSnapshotTransfer(tokenIndex, from, to string, amount int64) *core.TokenTransfer {
+	transfer := &core.TokenTransfer{
+		TokenIndex: tokenIndex,
+		From:       from,
+		To:         to,
+	}
+	transfer.Amount.Int().SetInt64(amount)
+	return transfer
+}
+
+func TestCreateTokenPoolSnapshotByBlock(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID()}
+	blockNumber := int64(10)
+	transfers := []*core.TokenTransfer{
+		newTestSnapshotTransfer("1", "", "0x01", 10),
+		newTestSnapshotTransfer("1", "0x01", "0x02", 4),
+		newTestSnapshotTransfer("1", "0x02", "", 4),
+		newTestSnapshotTransfer("1", "0x01", "0x03", 1),
+		newTestSnapshotTransfer("2", "", "0x01", 1),
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == fmt.Sprintf("( pool == '%s' ) && ( invalidated == false ) && ( protocolid << '000000000011' ) sort=created,localid limit=1000", pool.ID)
+	})).Return(transfers, nil, nil)
+	mdi.On("InsertTokenSnapshot", context.Background(), mock.MatchedBy(func(snapshot *core.TokenSnapshot) bool {
+		return snapshot.Pool.Equals(pool.ID) && *snapshot.BlockNumber == 10 && snapshot.Transfers == 5 && snapshot.Accounts == 3
+	}), mock.MatchedBy(func(balances []*core.TokenSnapshotBalance) bool {
+		return len(balances) == 3 &&
+			balances[0].TokenIndex == "1" && balances[0].Key == "0x01" && balances[0].Balance.Int().Int64() == 5 &&
+			balances[1].TokenIndex == "1" && balances[1].Key == "0x03" && balances[1].Balance.Int().Int64() == 1 &&
+			balances[2].TokenIndex == "2" && balances[2].Key == "0x01" && balances[2].Balance.Int().Int64() == 1
+	})).Return(nil)
+
+	snapshot, err := am.CreateTokenPoolSnapshot(context.Background(), "pool1", &core.TokenSnapshotInput{
+		BlockNumber: &blockNumber,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), snapshot.Accounts)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolSnapshotByTimestampPaged(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID()}
+	page := make([]*core.TokenTransfer, snapshotPageSize)
+	for i := range page {
+		page[i] = newTestSnapshotTransfer("", "", "0x01", 1)
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(page, nil, nil).Once()
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{}, nil, nil).Once()
+	mdi.On("InsertTokenSnapshot", context.Background(), mock.MatchedBy(func(snapshot *core.TokenSnapshot) bool {
+		return snapshot.Timestamp != nil && snapshot.Transfers == snapshotPageSize && snapshot.Accounts == 1
+	}), mock.MatchedBy(func(balances []*core.TokenSnapshotBalance) bool {
+		return len(balances) == 1 && balances[0].Balance.Int().Int64() == snapshotPageSize
+	})).Return(nil)
+
+	_, err := am.CreateTokenPoolSnapshot(context.Background(), "pool1", &core.TokenSnapshotInput{
+		Timestamp: fftypes.Now(),
+	})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolSnapshotBadInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.CreateTokenPoolSnapshot(context.Background(), "pool1", &core.TokenSnapshotInput{})
+	assert.Regexp(t, "FF10482", err)
+
+	blockNumber := int64(10)
+	_, err = am.CreateTokenPoolSnapshot(context.Background(), "pool1", &core.TokenSnapshotInput{
+		BlockNumber: &blockNumber,
+		Timestamp:   fftypes.Now(),
+	})
+	assert.Regexp(t, "FF10482", err)
+}
+
+func TestCreateTokenPoolSnapshotPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, nil)
+
+	_, err := am.CreateTokenPoolSnapshot(context.Background(), "pool1", &core.TokenSnapshotInput{
+		Timestamp: fftypes.Now(),
+	})
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolSnapshotQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&core.TokenPool{ID: fftypes.NewUUID()}, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenPoolSnapshot(context.Background(), "pool1", &core.TokenSnapshotInput{
+		Timestamp: fftypes.Now(),
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateTokenPoolSnapshotInsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&core.TokenPool{ID: fftypes.NewUUID()}, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{
+		newTestSnapshotTransfer("", "", "0x01", 1),
+	}, nil, nil)
+	mdi.On("InsertTokenSnapshot", context.Background(), mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenPoolSnapshot(context.Background(), "pool1", &core.TokenSnapshotInput{
+		Timestamp: fftypes.Now(),
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenSnapshots(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	fb := database.TokenSnapshotQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSnapshots", context.Background(), "ns1", f).Return([]*core.TokenSnapshot{}, nil, nil)
+	_, _, err := am.GetTokenSnapshots(context.Background(), f)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenSnapshotByIDBadID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.GetTokenSnapshotByID(context.Background(), "badUUID")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetTokenSnapshotBalances(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	snapshot := &core.TokenSnapshot{ID: fftypes.NewUUID()}
+	fb := database.TokenSnapshotBalanceQueryFactory.NewFilter(context.Background())
+	f := fb.And()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSnapshotByID", context.Background(), "ns1", snapshot.ID).Return(snapshot, nil)
+	mdi.On("GetTokenSnapshotBalances", context.Background(), "ns1", snapshot.ID, f).Return([]*core.TokenSnapshotBalance{}, nil, nil)
+	_, _, err := am.GetTokenSnapshotBalances(context.Background(), snapshot.ID.String(), f)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenSnapshotBalancesNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	id := fftypes.NewUUID()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSnapshotByID", context.Background(), "ns1", id).Return(nil, nil)
+	_, _, err := am.GetTokenSnapshotBalances(context.Background(), id.String(), nil)
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExportTokenSnapshot(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	snapshot := &core.TokenSnapshot{ID: fftypes.NewUUID()}
+	balance := &core.TokenSnapshotBalance{TokenIndex: "1", Key: "0x01"}
+	balance.Balance.Int().SetInt64(10)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSnapshotByID", context.Background(), "ns1", snapshot.ID).Return(snapshot, nil)
+	mdi.On("GetTokenSnapshotBalances", context.Background(), "ns1", snapshot.ID, mock.Anything).Return([]*core.TokenSnapshotBalance{balance}, nil, nil)
+
+	r, err := am.ExportTokenSnapshot(context.Background(), snapshot.ID.String())
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(r)
+	assert.Equal(t, "tokenIndex,key,balance\n1,0x01,10\n", string(b))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExportTokenSnapshotBadID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.ExportTokenSnapshot(context.Background(), "badUUID")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestExportTokenSnapshotQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	snapshot := &core.TokenSnapshot{ID: fftypes.NewUUID()}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSnapshotByID", context.Background(), "ns1", snapshot.ID).Return(snapshot, nil)
+	mdi.On("GetTokenSnapshotBalances", context.Background(), "ns1", snapshot.ID, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.ExportTokenSnapshot(context.Background(), snapshot.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
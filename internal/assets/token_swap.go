@@ -0,0 +1,485 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type swapConfig struct {
+	defaultTimeout time.Duration
+	checkInterval  time.Duration
+	batchSize      int
+}
+
+// swapStep is a single operation in the lifecycle of a swap, for one of its legs
+type swapStep struct {
+	opType core.OpType
+	leg    int
+}
+
+// CreateTokenSwap validates both legs of a swap, and submits the lock of the first leg.
+// The remaining steps are driven by the swap loop, as each operation completes:
+//   - pending: lock (or invoke) each leg in turn, moving to refunding if any of them fails
+//   - claiming: claim each locked leg in turn, using the preimage of the hash lock
+//   - refunding: refund each leg that was successfully locked
+//
+// A swap that passes its timeout while pending, or before any leg is claimed, moves to refunding.
+// A failed claim or refund moves the swap to failed, and retrying that operation resumes the swap.
+func (am *assetManager) CreateTokenSwap(ctx context.Context, input *core.TokenSwapInput) (*core.TokenSwap, error) {
+	swap, err := am.validateSwap(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	txid, err := am.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTokenSwap, input.IdempotencyKey)
+	if err != nil {
+		// As for transfers, an idempotency key clash resubmits any operation that never got submitted
+		if idemErr, ok := err.(*sqlcommon.IdempotencyError); ok {
+			operation, resubmitErr := am.operations.ResubmitOperations(ctx, idemErr.ExistingTXID)
+			if resubmitErr != nil {
+				return nil, resubmitErr
+			} else if operation != nil {
+				return am.getTokenSwapByTX(ctx, idemErr.ExistingTXID)
+			}
+		}
+		return nil, err
+	}
+	swap.TX = core.TransactionRef{
+		ID:   txid,
+		Type: core.TransactionTypeTokenSwap,
+	}
+
+	first := swapStep{opType: core.OpTypeTokenSwapLock, leg: 0}
+	var op *core.Operation
+	err = am.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
+		if err = am.database.InsertTokenSwap(ctx, swap); err != nil {
+			return err
+		}
+		op, err = am.newSwapOperation(ctx, swap, first)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return swap, am.runSwapOperation(ctx, swap, first, op)
+}
+
+func (am *assetManager) validateSwap(ctx context.Context, input *core.TokenSwapInput) (*core.TokenSwap, error) {
+	if len(input.Legs) != 2 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenSwapInvalidLegs)
+	}
+
+	swap := &core.TokenSwap{
+		ID:        fftypes.NewUUID(),
+		Namespace: am.namespace,
+		State:     core.TokenSwapStatePending,
+		Legs:      make(core.TokenSwapLegs, len(input.Legs)),
+		Preimage:  fftypes.NewRandB32(),
+	}
+	hashLock := fftypes.Bytes32(sha256.Sum256(swap.Preimage[:]))
+	swap.HashLock = &hashLock
+
+	timeout := am.swap.defaultTimeout
+	if input.Timeout != nil {
+		timeout = time.Duration(*input.Timeout)
+	}
+	expiry := fftypes.FFTime(time.Now().Add(timeout))
+	swap.Timeout = &expiry
+
+	for i, legInput := range input.Legs {
+		leg := &legInput.TokenSwapLeg
+		if leg.Type == "" {
+			leg.Type = core.TokenSwapLegTypeTransfer
+		}
+		switch {
+		case leg.Type == core.TokenSwapLegTypeTransfer:
+			if err := am.validateSwapTransferLeg(ctx, legInput); err != nil {
+				return nil, err
+			}
+		case leg.Type == core.TokenSwapLegTypeInvoke && i > 0:
+			if leg.Invoke == nil {
+				return nil, i18n.NewError(ctx, coremsgs.MsgTokenSwapInvokeRequired, i)
+			}
+			if leg.Invoke.Type == "" {
+				leg.Invoke.Type = core.CallTypeInvoke
+			}
+			if err := am.contracts.ResolveInvokeContractRequest(ctx, leg.Invoke); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenSwapInvalidLegs)
+		}
+		leg.LockID = fmt.Sprintf("%s-%d", swap.ID, i)
+		swap.Legs[i] = leg
+	}
+	return swap, nil
+}
+
+func (am *assetManager) validateSwapTransferLeg(ctx context.Context, legInput *core.TokenSwapLegInput) error {
+	leg := &legInput.TokenSwapLeg
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Type:       core.TokenTransferTypeTransfer,
+			Key:        leg.Key,
+			From:       leg.From,
+			To:         leg.To,
+			TokenIndex: leg.TokenIndex,
+			Amount:     leg.Amount,
+		},
+		Pool: legInput.Pool,
+	}
+	pool, err := am.validateTransfer(ctx, transfer)
+	if err != nil {
+		return err
+	}
+	if am.requiresSignoff(pool, &leg.Amount) {
+		return i18n.NewError(ctx, coremsgs.MsgTokenSwapLegRequiresSignoff, leg.Amount.String(), am.signoff.thresholdStr)
+	}
+	if transfer.From == transfer.To {
+		return i18n.NewError(ctx, coremsgs.MsgCannotTransferToSelf)
+	}
+	if _, err := am.selectTokenPlugin(ctx, pool.Connector); err != nil {
+		return err
+	}
+	leg.Pool = pool.ID
+	leg.Connector = pool.Connector
+	leg.Key = transfer.Key
+	leg.From = transfer.From
+	leg.To = transfer.To
+	return nil
+}
+
+func (am *assetManager) GetTokenSwaps(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenSwap, *ffapi.FilterResult, error) {
+	return am.database.GetTokenSwaps(ctx, am.namespace, filter)
+}
+
+func (am *assetManager) GetTokenSwapByID(ctx context.Context, id string) (*core.TokenSwap, error) {
+	swapID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return am.database.GetTokenSwapByID(ctx, am.namespace, swapID)
+}
+
+func (am *assetManager) getTokenSwapByTX(ctx context.Context, txid *fftypes.UUID) (*core.TokenSwap, error) {
+	fb := database.TokenSwapQueryFactory.NewFilter(ctx)
+	swaps, _, err := am.database.GetTokenSwaps(ctx, am.namespace, fb.And(fb.Eq("tx.id", txid)).Limit(1))
+	if err != nil || len(swaps) == 0 {
+		return nil, err
+	}
+	return swaps[0], nil
+}
+
+func (am *assetManager) swapLoop() {
+	defer close(am.swapLoopDone)
+
+	ticker := time.NewTicker(am.swap.checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-am.ctx.Done():
+			log.L(am.ctx).Debugf("Token swap loop exiting")
+			return
+		case <-ticker.C:
+			if err := am.advanceSwaps(am.ctx); err != nil {
+				log.L(am.ctx).Errorf("Failed to query in-flight token swaps: %s", err)
+			}
+		}
+	}
+}
+
+// advanceSwaps checks every in-flight swap on each pass. The swaps are paged through with a cursor on their
+// creation time and ID, which do not change as swaps advance, so swaps that are waiting (for example for their
+// timeout) cannot hold up the swaps behind them however many there are.
+func (am *assetManager) advanceSwaps(ctx context.Context) error {
+	var after *core.TokenSwap
+	for {
+		fb := database.TokenSwapQueryFactory.NewFilter(ctx)
+		conditions := []ffapi.Filter{
+			fb.In("state", []driver.Value{
+				core.TokenSwapStatePending,
+				core.TokenSwapStateClaiming,
+				core.TokenSwapStateRefunding,
+			}),
+		}
+		if after != nil {
+			conditions = append(conditions, fb.Or(
+				fb.Gt("created", after.Created),
+				fb.And(fb.Eq("created", after.Created), fb.Gt("id", after.ID)),
+			))
+		}
+		filter := fb.And(conditions...).Sort("created", "id").Ascending().Limit(uint64(am.swap.batchSize))
+		swaps, _, err := am.database.GetTokenSwaps(ctx, am.namespace, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, swap := range swaps {
+			if err := am.advanceSwap(ctx, swap); err != nil {
+				// Retried on the next check
+				log.L(ctx).Errorf("Failed to advance token swap '%s': %s", swap.ID, err)
+			}
+		}
+		if len(swaps) < am.swap.batchSize {
+			return nil
+		}
+		after = swaps[len(swaps)-1]
+	}
+}
+
+// swapOperations returns the latest operation for each step of a swap (ignoring any that have been retried)
+func (am *assetManager) swapOperations(ctx context.Context, swap *core.TokenSwap) (map[swapStep]*core.Operation, error) {
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	ops, _, err := am.database.GetOperations(ctx, am.namespace, fb.And(fb.Eq("tx", swap.TX.ID)))
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make(map[swapStep]*core.Operation, len(ops))
+	for _, op := range ops {
+		if op.Retry != nil {
+			continue
+		}
+		step := swapStep{opType: op.Type, leg: 1}
+		if op.Type != core.OpTypeTokenSwapInvoke {
+			if _, step.leg, err = txcommon.RetrieveTokenSwapInputs(ctx, op); err != nil {
+				return nil, err
+			}
+		}
+		steps[step] = op
+	}
+	return steps, nil
+}
+
+func (am *assetManager) advanceSwap(ctx context.Context, swap *core.TokenSwap) error {
+	ops, err := am.swapOperations(ctx, swap)
+	if err != nil {
+		return err
+	}
+	return am.advanceSwapState(ctx, swap, ops)
+}
+
+// swapExpired returns true for a swap that has passed its timeout before any of its legs were claimed,
+// after which the escrowed tokens can only be refunded
+func swapExpired(swap *core.TokenSwap, ops map[swapStep]*core.Operation) bool {
+	if swap.Timeout == nil || time.Now().Before(*swap.Timeout.Time()) {
+		return false
+	}
+	switch swap.State {
+	case core.TokenSwapStatePending:
+		return true
+	case core.TokenSwapStateClaiming:
+		for i := range swap.Legs {
+			if ops[swapStep{opType: core.OpTypeTokenSwapClaim, leg: i}] != nil {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (am *assetManager) advanceSwapState(ctx context.Context, swap *core.TokenSwap, ops map[swapStep]*core.Operation) error {
+	if swapExpired(swap, ops) {
+		log.L(ctx).Infof("Token swap '%s' expired at %s", swap.ID, swap.Timeout)
+		if err := am.updateSwapState(ctx, swap, core.TokenSwapStateRefunding, ""); err != nil {
+			return err
+		}
+	}
+
+	var steps []swapStep
+	switch swap.State {
+	case core.TokenSwapStatePending:
+		steps = append(steps, swapStep{opType: core.OpTypeTokenSwapLock, leg: 0})
+		if swap.Legs[1].Type == core.TokenSwapLegTypeInvoke {
+			steps = append(steps, swapStep{opType: core.OpTypeTokenSwapInvoke, leg: 1})
+		} else {
+			steps = append(steps, swapStep{opType: core.OpTypeTokenSwapLock, leg: 1})
+		}
+	case core.TokenSwapStateClaiming:
+		for i, leg := range swap.Legs {
+			if leg.Type == core.TokenSwapLegTypeTransfer {
+				steps = append(steps, swapStep{opType: core.OpTypeTokenSwapClaim, leg: i})
+			}
+		}
+	case core.TokenSwapStateRefunding:
+		for i, leg := range swap.Legs {
+			lock := ops[swapStep{opType: core.OpTypeTokenSwapLock, leg: i}]
+			if leg.Type != core.TokenSwapLegTypeTransfer || lock == nil || lock.Status == core.OpStatusFailed {
+				continue
+			}
+			if lock.Status != core.OpStatusSucceeded {
+				// An expired swap waits for any lock that is still in-flight, so it can be refunded
+				return nil
+			}
+			steps = append(steps, swapStep{opType: core.OpTypeTokenSwapRefund, leg: i})
+		}
+	default:
+		return nil
+	}
+
+	done, failed, err := am.runSwapSteps(ctx, swap, ops, steps)
+	if err != nil || !(done || failed) {
+		return err
+	}
+
+	switch {
+	case swap.State == core.TokenSwapStatePending && failed:
+		if err = am.updateSwapState(ctx, swap, core.TokenSwapStateRefunding, ""); err == nil {
+			err = am.advanceSwapState(ctx, swap, ops)
+		}
+	case swap.State == core.TokenSwapStatePending:
+		if err = am.updateSwapState(ctx, swap, core.TokenSwapStateClaiming, ""); err == nil {
+			err = am.advanceSwapState(ctx, swap, ops)
+		}
+	case failed:
+		err = am.updateSwapState(ctx, swap, core.TokenSwapStateFailed, core.EventTypeSwapFailed)
+	case swap.State == core.TokenSwapStateClaiming:
+		err = am.updateSwapState(ctx, swap, core.TokenSwapStateCompleted, core.EventTypeSwapCompleted)
+	default:
+		err = am.updateSwapState(ctx, swap, core.TokenSwapStateRefunded, core.EventTypeSwapRefunded)
+	}
+	return err
+}
+
+// runSwapSteps executes the steps strictly in order, so that at most one operation is in-flight for a swap.
+// The next missing operation is submitted, and the result reports whether every step succeeded, or one failed.
+func (am *assetManager) runSwapSteps(ctx context.Context, swap *core.TokenSwap, ops map[swapStep]*core.Operation, steps []swapStep) (done, failed bool, err error) {
+	for _, step := range steps {
+		op := ops[step]
+		if op == nil {
+			return false, false, am.submitSwapStep(ctx, swap, step)
+		}
+		switch op.Status {
+		case core.OpStatusSucceeded:
+			continue
+		case core.OpStatusFailed:
+			return false, true, nil
+		default:
+			return false, false, nil
+		}
+	}
+	return true, false, nil
+}
+
+func (am *assetManager) submitSwapStep(ctx context.Context, swap *core.TokenSwap, step swapStep) error {
+	op, err := am.newSwapOperation(ctx, swap, step)
+	if err != nil {
+		return err
+	}
+	return am.runSwapOperation(ctx, swap, step, op)
+}
+
+func (am *assetManager) newSwapOperation(ctx context.Context, swap *core.TokenSwap, step swapStep) (op *core.Operation, err error) {
+	leg := swap.Legs[step.leg]
+	if step.opType == core.OpTypeTokenSwapInvoke {
+		if op, err = am.contracts.NewInvokeOperation(swap.TX.ID, step.opType, leg.Invoke); err != nil {
+			return nil, err
+		}
+	} else {
+		plugin, err := am.selectTokenPlugin(ctx, leg.Connector)
+		if err != nil {
+			return nil, err
+		}
+		op = core.NewOperation(
+			plugin,
+			am.namespace,
+			swap.TX.ID,
+			step.opType)
+		txcommon.AddTokenSwapInputs(op, swap.ID, step.leg)
+	}
+	if err = am.operations.AddOrReuseOperation(ctx, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (am *assetManager) runSwapOperation(ctx context.Context, swap *core.TokenSwap, step swapStep, op *core.Operation) error {
+	leg := swap.Legs[step.leg]
+	var prepared *core.PreparedOperation
+	if step.opType == core.OpTypeTokenSwapInvoke {
+		prepared = txcommon.OpBlockchainInvoke(op, leg.Invoke, nil)
+	} else {
+		pool, err := am.GetTokenPoolByID(ctx, leg.Pool)
+		if err != nil {
+			return err
+		} else if pool == nil {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		prepared = opTokenSwap(op, pool, swap, step.leg)
+	}
+	_, err := am.operations.RunOperation(ctx, prepared)
+	return err
+}
+
+func (am *assetManager) updateSwapState(ctx context.Context, swap *core.TokenSwap, state core.TokenSwapState, eventType core.EventType) error {
+	err := am.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		update := database.TokenSwapQueryFactory.NewUpdate(ctx).Set("state", state)
+		if err := am.database.UpdateTokenSwap(ctx, am.namespace, swap.ID, update); err != nil {
+			return err
+		}
+		if eventType == "" {
+			return nil
+		}
+		event := core.NewEvent(eventType, am.namespace, swap.ID, swap.TX.ID, swap.Legs[0].Pool.String())
+		return am.database.InsertEvent(ctx, event)
+	})
+	if err != nil {
+		return err
+	}
+	log.L(ctx).Infof("Token swap '%s' moved from %s to %s", swap.ID, swap.State, state)
+	swap.State = state
+	return nil
+}
+
+// resumeFailedSwap moves a failed swap back into claiming or refunding, when a retry of the claim
+// or refund operation that failed it succeeds
+func (am *assetManager) resumeFailedSwap(ctx context.Context, op *core.Operation) error {
+	swapID, _, err := txcommon.RetrieveTokenSwapInputs(ctx, op)
+	if err != nil {
+		log.L(ctx).Warnf("Could not parse token swap: %s (%+v)", err, op.Input)
+		return nil
+	}
+	swap, err := am.database.GetTokenSwapByID(ctx, op.Namespace, swapID)
+	if err != nil || swap == nil || swap.State != core.TokenSwapStateFailed {
+		return err
+	}
+	state := core.TokenSwapStateClaiming
+	if op.Type == core.OpTypeTokenSwapRefund {
+		state = core.TokenSwapStateRefunding
+	}
+	log.L(ctx).Infof("Resuming token swap '%s' in state %s", swap.ID, state)
+	update := database.TokenSwapQueryFactory.NewUpdate(ctx).Set("state", state)
+	return am.database.UpdateTokenSwap(ctx, op.Namespace, swap.ID, update)
+}
@@ -0,0 +1,972 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/mocks/contractmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSwapInput() *core.TokenSwapInput {
+	return &core.TokenSwapInput{
+		Legs: []*core.TokenSwapLegInput{
+			{
+				TokenSwapLeg: core.TokenSwapLeg{From: "A", To: "B", Amount: *fftypes.NewFFBigInt(5)},
+				Pool:         "pool1",
+			},
+			{
+				TokenSwapLeg: core.TokenSwapLeg{From: "B", To: "A", Amount: *fftypes.NewFFBigInt(10)},
+				Pool:         "pool2",
+			},
+		},
+		IdempotencyKey: "idem1",
+	}
+}
+
+func newTestSwap(state core.TokenSwapState, second core.TokenSwapLegType) *core.TokenSwap {
+	swap := &core.TokenSwap{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		State:     state,
+		Legs: core.TokenSwapLegs{
+			{Type: core.TokenSwapLegTypeTransfer, Pool: fftypes.NewUUID(), Connector: "magic-tokens"},
+			{Type: second},
+		},
+		TX: core.TransactionRef{ID: fftypes.NewUUID(), Type: core.TransactionTypeTokenSwap},
+	}
+	if second == core.TokenSwapLegTypeTransfer {
+		swap.Legs[1].Pool = fftypes.NewUUID()
+		swap.Legs[1].Connector = "magic-tokens"
+	} else {
+		swap.Legs[1].Invoke = &core.ContractCallRequest{Type: core.CallTypeInvoke, Key: "0x12345"}
+	}
+	return swap
+}
+
+func newTestSwapOp(swap *core.TokenSwap, opType core.OpType, leg int, status core.OpStatus) *core.Operation {
+	op := &core.Operation{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Transaction: swap.TX.ID,
+		Type:        opType,
+		Status:      status,
+	}
+	if opType != core.OpTypeTokenSwapInvoke {
+		txcommon.AddTokenSwapInputs(op, swap.ID, leg)
+	}
+	return op
+}
+
+func mockSwapPools(mdi *databasemocks.Plugin, swap *core.TokenSwap) {
+	for _, leg := range swap.Legs {
+		if leg.Pool != nil {
+			mdi.On("GetTokenPoolByID", context.Background(), "ns1", leg.Pool).Return(&core.TokenPool{
+				ID:        leg.Pool,
+				Connector: "magic-tokens",
+				Locator:   "F1",
+			}, nil).Maybe()
+		}
+	}
+}
+
+func expireSwap(swap *core.TokenSwap) {
+	expiry := fftypes.FFTime(time.Now().Add(-1 * time.Minute))
+	swap.Timeout = &expiry
+}
+
+func matchSwapState(state core.TokenSwapState) interface{} {
+	return mock.MatchedBy(func(update ffapi.Update) bool {
+		info, _ := update.Finalize()
+		val, _ := info.SetOperations[0].Value.Value()
+		return val == state.String()
+	})
+}
+
+func TestCreateTokenSwapSuccess(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestSwapInput()
+	pool1 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "magic-tokens", Locator: "F1", State: core.TokenPoolStateConfirmed}
+	pool2 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "magic-tokens", Locator: "F2", State: core.TokenPoolStateConfirmed}
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool1, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool2").Return(pool2, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool1.ID).Return(pool1, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(txID, nil)
+	mdi.On("InsertTokenSwap", context.Background(), mock.MatchedBy(func(swap *core.TokenSwap) bool {
+		return swap.State == core.TokenSwapStatePending && swap.TX.ID.Equals(txID)
+	})).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTokenSwapLock && op.Transaction.Equals(txID)
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(tokenSwapData)
+		return data.Pool == pool1 && data.Leg == 0
+	})).Return(nil, nil)
+
+	swap, err := am.CreateTokenSwap(context.Background(), input)
+	assert.NoError(t, err)
+	assert.Equal(t, pool1.ID, swap.Legs[0].Pool)
+	assert.Equal(t, pool2.ID, swap.Legs[1].Pool)
+	assert.Equal(t, core.TokenSwapLegTypeTransfer, swap.Legs[1].Type)
+	assert.Equal(t, swap.ID.String()+"-1", swap.Legs[1].LockID)
+	hash := fftypes.Bytes32(sha256.Sum256(swap.Preimage[:]))
+	assert.Equal(t, &hash, swap.HashLock)
+	assert.True(t, time.Time(*swap.Timeout).After(time.Now().Add(23*time.Hour)))
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestCreateTokenSwapWithInvoke(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestSwapInput()
+	input.Legs[1] = &core.TokenSwapLegInput{
+		TokenSwapLeg: core.TokenSwapLeg{
+			Type:   core.TokenSwapLegTypeInvoke,
+			Invoke: &core.ContractCallRequest{},
+		},
+	}
+	timeout := fftypes.FFDuration(1 * time.Minute)
+	input.Timeout = &timeout
+	pool1 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "magic-tokens", Locator: "F1", State: core.TokenPoolStateConfirmed}
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mcm := am.contracts.(*contractmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool1, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", pool1.ID).Return(pool1, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mcm.On("ResolveInvokeContractRequest", context.Background(), mock.MatchedBy(func(req *core.ContractCallRequest) bool {
+		return req.Type == core.CallTypeInvoke
+	})).Return(nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(txID, nil)
+	mdi.On("InsertTokenSwap", context.Background(), mock.Anything).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything).Return(nil, nil)
+
+	swap, err := am.CreateTokenSwap(context.Background(), input)
+	assert.NoError(t, err)
+	assert.True(t, time.Time(*swap.Timeout).Before(time.Now().Add(2*time.Minute)))
+
+	mdi.AssertExpectations(t)
+	mcm.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestCreateTokenSwapBadLegs(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestSwapInput()
+	input.Legs = input.Legs[0:1]
+	_, err := am.CreateTokenSwap(context.Background(), input)
+	assert.Regexp(t, "FF10488", err)
+
+	input = newTestSwapInput()
+	input.Legs[0].Type = core.TokenSwapLegTypeInvoke
+	_, err = am.CreateTokenSwap(context.Background(), input)
+	assert.Regexp(t, "FF10488", err)
+}
+
+func TestCreateTokenSwapInvokeRequired(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestSwapInput()
+	input.Legs[0].Pool = ""
+	input.Legs[1] = &core.TokenSwapLegInput{
+		TokenSwapLeg: core.TokenSwapLeg{Type: core.TokenSwapLegTypeInvoke},
+	}
+	pool1 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "magic-tokens", State: core.TokenPoolStateConfirmed}
+	total := int64(1)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPools", context.Background(), "ns1", mock.Anything).Return([]*core.TokenPool{pool1}, &ffapi.FilterResult{
+		TotalCount: &total,
+	}, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	_, err := am.CreateTokenSwap(context.Background(), input)
+	assert.Regexp(t, "FF10489.*1", err)
+}
+
+func TestCreateTokenSwapInvokeFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestSwapInput()
+	input.Legs[1] = &core.TokenSwapLegInput{
+		TokenSwapLeg: core.TokenSwapLeg{
+			Type:   core.TokenSwapLegTypeInvoke,
+			Invoke: &core.ContractCallRequest{},
+		},
+	}
+	pool1 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "magic-tokens", State: core.TokenPoolStateConfirmed}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mcm := am.contracts.(*contractmocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool1, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mcm.On("ResolveInvokeContractRequest", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenSwap(context.Background(), input)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateTokenSwapBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateTokenSwapToSelf(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestSwapInput()
+	input.Legs[0].To = "A"
+	pool1 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "magic-tokens", State: core.TokenPoolStateConfirmed}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool1, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	_, err := am.CreateTokenSwap(context.Background(), input)
+	assert.Regexp(t, "FF10280", err)
+}
+
+func TestCreateTokenSwapLegOverMaxAmount(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	input := newTestSwapInput()
+	pool1 := newTestPool()
+	policy := &core.TokenTransferPolicy{MaxAmount: fftypes.NewFFBigInt(4)}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool1, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool1.ID).Return(policy, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	_, err := am.CreateTokenSwap(context.Background(), input)
+	assert.Regexp(t, "FF10494.*5.*4", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCreateTokenSwapLegRequiresSignoff(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.signoff.threshold = big.NewRat(7, 1)
+	am.signoff.thresholdStr = "7"
+
+	// The first leg is within the threshold, but the second is not
+	mockValidSwapPools(am)
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.Regexp(t, "FF10544.*10.*7", err)
+}
+
+func TestCreateTokenSwapBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool1 := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "bad", State: core.TokenPoolStateConfirmed}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool1, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.Regexp(t, "FF10272", err)
+}
+
+func mockValidSwapPools(am *assetManager) {
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Connector: "magic-tokens", Locator: "F1", State: core.TokenPoolStateConfirmed}
+	mdi.On("GetTokenPool", context.Background(), "ns1", mock.Anything).Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+}
+
+func TestCreateTokenSwapSubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockValidSwapPools(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateTokenSwapIdempotentResubmit(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockValidSwapPools(am)
+	existingTX := fftypes.NewUUID()
+	existing := &core.TokenSwap{ID: fftypes.NewUUID()}
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi := am.database.(*databasemocks.Plugin)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  existingTX,
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(&core.Operation{}, nil)
+	mdi.On("GetTokenSwaps", context.Background(), "ns1", mock.Anything).Return([]*core.TokenSwap{existing}, nil, nil)
+
+	swap, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.NoError(t, err)
+	assert.Equal(t, existing, swap)
+
+	mom.AssertExpectations(t)
+}
+
+func TestCreateTokenSwapIdempotentNoResubmit(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockValidSwapPools(am)
+	existingTX := fftypes.NewUUID()
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  existingTX,
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(nil, nil)
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.Regexp(t, "pop", err)
+
+	mom.AssertExpectations(t)
+}
+
+func TestCreateTokenSwapIdempotentResubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockValidSwapPools(am)
+	existingTX := fftypes.NewUUID()
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  existingTX,
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(nil, fmt.Errorf("resubmit"))
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.EqualError(t, err, "resubmit")
+
+	mom.AssertExpectations(t)
+}
+
+func TestCreateTokenSwapInsertFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockValidSwapPools(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi := am.database.(*databasemocks.Plugin)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mdi.On("InsertTokenSwap", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateTokenSwapAddOpFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mockValidSwapPools(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenSwap, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mdi.On("InsertTokenSwap", context.Background(), mock.Anything).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.CreateTokenSwap(context.Background(), newTestSwapInput())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenSwaps(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwaps", context.Background(), "ns1", mock.Anything).Return([]*core.TokenSwap{}, nil, nil)
+
+	_, _, err := am.GetTokenSwaps(context.Background(), nil)
+	assert.NoError(t, err)
+}
+
+func TestGetTokenSwapByID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	id := fftypes.NewUUID()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", context.Background(), "ns1", id).Return(&core.TokenSwap{ID: id}, nil)
+
+	swap, err := am.GetTokenSwapByID(context.Background(), id.String())
+	assert.NoError(t, err)
+	assert.Equal(t, id, swap.ID)
+
+	_, err = am.GetTokenSwapByID(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetTokenSwapByTXFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwaps", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.getTokenSwapByTX(context.Background(), fftypes.NewUUID())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestStartStopSwapLoop(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	am.swap.checkInterval = 1 * time.Millisecond
+
+	checked := make(chan struct{})
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenApprovals", am.ctx, "ns1", mock.Anything).Return([]*core.TokenApproval{}, nil, nil).Maybe()
+	mdi.On("GetTokenSwaps", am.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(checked)
+	}).Once()
+	mdi.On("GetTokenSwaps", am.ctx, "ns1", mock.Anything).Return([]*core.TokenSwap{}, nil, nil).Maybe()
+
+	err := am.Start()
+	assert.NoError(t, err)
+	<-checked
+	cancel()
+	am.WaitStop()
+}
+
+func TestAdvanceSwapsError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwaps", context.Background(), "ns1", mock.Anything).Return([]*core.TokenSwap{swap}, nil, nil)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	// Failures are logged, and retried on the next check
+	err := am.advanceSwaps(context.Background())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestAdvanceSwapsPages(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	am.swap.batchSize = 2
+
+	swaps := make([]*core.TokenSwap, 3)
+	for i := range swaps {
+		swaps[i] = newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+		swaps[i].Created = fftypes.Now()
+	}
+	matchAfter := func(after *core.TokenSwap) interface{} {
+		return mock.MatchedBy(func(filter ffapi.Filter) bool {
+			info, err := filter.Finalize()
+			if err != nil {
+				return false
+			}
+			if after == nil {
+				return len(info.Children) == 1
+			}
+			return len(info.Children) == 2 && strings.Contains(info.String(), after.ID.String())
+		})
+	}
+
+	// Every swap is reached on each check, although none of them advance
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwaps", context.Background(), "ns1", matchAfter(nil)).Return(swaps[0:2], nil, nil).Once()
+	mdi.On("GetTokenSwaps", context.Background(), "ns1", matchAfter(swaps[1])).Return(swaps[2:], nil, nil).Once()
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Times(3)
+
+	err := am.advanceSwaps(context.Background())
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestAdvanceSwapPendingSubmitLock(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mockSwapPools(mdi, swap)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusSucceeded),
+	}, nil, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		_, leg, _ := txcommon.RetrieveTokenSwapInputs(context.Background(), op)
+		return op.Type == core.OpTypeTokenSwapLock && leg == 1
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		return op.Data.(tokenSwapData).Leg == 1
+	})).Return(nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStatePending, swap.State)
+
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceSwapPendingSubmitInvoke(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeInvoke)
+	invokeOp := &core.Operation{ID: fftypes.NewUUID(), Type: core.OpTypeTokenSwapInvoke}
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mcm := am.contracts.(*contractmocks.Manager)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusSucceeded),
+	}, nil, nil)
+	mcm.On("NewInvokeOperation", swap.TX.ID, core.OpTypeTokenSwapInvoke, swap.Legs[1].Invoke).Return(invokeOp, nil)
+	mom.On("AddOrReuseOperation", context.Background(), invokeOp).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		return op.ID == invokeOp.ID && op.Data.(txcommon.BlockchainInvokeData).Request == swap.Legs[1].Invoke
+	})).Return(nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+
+	mcm.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceSwapPendingInvokeFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeInvoke)
+	mdi := am.database.(*databasemocks.Plugin)
+	mcm := am.contracts.(*contractmocks.Manager)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusSucceeded),
+	}, nil, nil)
+	mcm.On("NewInvokeOperation", swap.TX.ID, core.OpTypeTokenSwapInvoke, swap.Legs[1].Invoke).Return(nil, fmt.Errorf("pop"))
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestAdvanceSwapPendingInFlight(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusPending),
+	}, nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStatePending, swap.State)
+}
+
+func TestAdvanceSwapPendingToClaiming(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeInvoke)
+	retried := newTestSwapOp(swap, core.OpTypeTokenSwapInvoke, 1, core.OpStatusFailed)
+	retried.Retry = fftypes.NewUUID()
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mockSwapPools(mdi, swap)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusSucceeded),
+		retried,
+		newTestSwapOp(swap, core.OpTypeTokenSwapInvoke, 1, core.OpStatusSucceeded),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateClaiming)).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTokenSwapClaim
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		return op.Type == core.OpTypeTokenSwapClaim && op.Data.(tokenSwapData).Leg == 0
+	})).Return(nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateClaiming, swap.State)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceSwapPendingToRefunding(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mockSwapPools(mdi, swap)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusSucceeded),
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 1, core.OpStatusFailed),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateRefunding)).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		_, leg, _ := txcommon.RetrieveTokenSwapInputs(context.Background(), op)
+		return op.Type == core.OpTypeTokenSwapRefund && leg == 0
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything).Return(nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateRefunding, swap.State)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceSwapPendingExpired(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	expireSwap(swap)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mockSwapPools(mdi, swap)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusSucceeded),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateRefunding)).Return(nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		_, leg, _ := txcommon.RetrieveTokenSwapInputs(context.Background(), op)
+		return op.Type == core.OpTypeTokenSwapRefund && leg == 0
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything).Return(nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateRefunding, swap.State)
+
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestAdvanceSwapPendingExpiredLockInFlight(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	expireSwap(swap)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusPending),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateRefunding)).Return(nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateRefunding, swap.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestAdvanceSwapPendingExpiredUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	expireSwap(swap)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenSwapStatePending, swap.State)
+}
+
+func TestAdvanceSwapClaimingExpiredToRefunded(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStateClaiming, core.TokenSwapLegTypeInvoke)
+	expireSwap(swap)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusFailed),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateRefunding)).Return(nil).Once()
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateRefunded)).Return(nil).Once()
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeSwapRefunded
+	})).Return(nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateRefunded, swap.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestAdvanceSwapClaimingExpiredAfterClaim(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStateClaiming, core.TokenSwapLegTypeTransfer)
+	expireSwap(swap)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapClaim, 0, core.OpStatusPending),
+	}, nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateClaiming, swap.State)
+}
+
+func TestSwapExpired(t *testing.T) {
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	assert.False(t, swapExpired(swap, nil))
+	expireSwap(swap)
+	assert.True(t, swapExpired(swap, nil))
+	swap.State = core.TokenSwapStateRefunding
+	assert.False(t, swapExpired(swap, nil))
+}
+
+func TestAdvanceSwapPendingUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusFailed),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenSwapStatePending, swap.State)
+}
+
+func TestAdvanceSwapClaimingToCompleted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStateClaiming, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapClaim, 0, core.OpStatusSucceeded),
+		newTestSwapOp(swap, core.OpTypeTokenSwapClaim, 1, core.OpStatusSucceeded),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateCompleted)).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeSwapCompleted &&
+			event.Reference.Equals(swap.ID) &&
+			event.Transaction.Equals(swap.TX.ID) &&
+			event.Topic == swap.Legs[0].Pool.String()
+	})).Return(nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateCompleted, swap.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestAdvanceSwapClaimingToFailed(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStateClaiming, core.TokenSwapLegTypeInvoke)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapClaim, 0, core.OpStatusFailed),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateFailed)).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeSwapFailed
+	})).Return(fmt.Errorf("pop"))
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenSwapStateClaiming, swap.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestAdvanceSwapRefundingToRefunded(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStateRefunding, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 0, core.OpStatusSucceeded),
+		newTestSwapOp(swap, core.OpTypeTokenSwapRefund, 0, core.OpStatusSucceeded),
+		newTestSwapOp(swap, core.OpTypeTokenSwapLock, 1, core.OpStatusFailed),
+	}, nil, nil)
+	mdi.On("UpdateTokenSwap", context.Background(), "ns1", swap.ID, matchSwapState(core.TokenSwapStateRefunded)).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeSwapRefunded
+	})).Return(nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateRefunded, swap.State)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestAdvanceSwapCompletedNoop(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStateCompleted, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.NoError(t, err)
+}
+
+func TestAdvanceSwapBadOpInput(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{
+		{Type: core.OpTypeTokenSwapLock, Input: fftypes.JSONObject{"swap": "bad"}},
+	}, nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestAdvanceSwapBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	swap.Legs[0].Connector = "bad"
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.Regexp(t, "FF10272", err)
+}
+
+func TestAdvanceSwapRunPoolError(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", swap.Legs[0].Pool).Return(nil, fmt.Errorf("pop"))
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestAdvanceSwapRunPoolNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	swap := newTestSwap(core.TokenSwapStatePending, core.TokenSwapLegTypeTransfer)
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return([]*core.Operation{}, nil, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", swap.Legs[0].Pool).Return(nil, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+
+	err := am.advanceSwap(context.Background(), swap)
+	assert.Regexp(t, "FF10109", err)
+}
@@ -26,11 +26,16 @@ import (
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
 func (am *assetManager) GetTokenTransfers(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransfer, *ffapi.FilterResult, error) {
-	return am.database.GetTokenTransfers(ctx, am.namespace, filter)
+	transfers, fr, err := am.database.GetTokenTransfers(ctx, am.namespace, filter)
+	if err == nil {
+		err = am.enrichTransfersWithMetadata(ctx, transfers)
+	}
+	return transfers, fr, err
 }
 
 func (am *assetManager) GetTokenTransferByID(ctx context.Context, id string) (*core.TokenTransfer, error) {
@@ -38,7 +43,14 @@ func (am *assetManager) GetTokenTransferByID(ctx context.Context, id string) (*c
 	if err != nil {
 		return nil, err
 	}
-	return am.database.GetTokenTransferByID(ctx, am.namespace, transferID)
+	transfer, err := am.database.GetTokenTransferByID(ctx, am.namespace, transferID)
+	if err == nil && transfer != nil {
+		err = am.enrichTransfersWithMetadata(ctx, []*core.TokenTransfer{transfer})
+	}
+	if err == nil && transfer != nil {
+		err = am.enrichTransferWithFees(ctx, transfer)
+	}
+	return transfer, err
 }
 
 func (am *assetManager) NewTransfer(transfer *core.TokenTransferInput) syncasync.Sender {
@@ -86,23 +98,22 @@ func (s *transferSender) setDefaults() {
 	s.transfer.LocalID = fftypes.NewUUID()
 }
 
+func (am *assetManager) resolveTransferPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	if poolNameOrID == "" {
+		return am.getDefaultTokenPool(ctx)
+	}
+	return am.GetTokenPoolByNameOrID(ctx, poolNameOrID)
+}
+
 func (am *assetManager) validateTransfer(ctx context.Context, transfer *core.TokenTransferInput) (pool *core.TokenPool, err error) {
-	if transfer.Pool == "" {
-		pool, err = am.getDefaultTokenPool(ctx)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		pool, err = am.GetTokenPoolByNameOrID(ctx, transfer.Pool)
-		if err != nil {
-			return nil, err
-		}
+	if pool, err = am.resolveTransferPool(ctx, transfer.Pool); err != nil {
+		return nil, err
 	}
 	transfer.TokenTransfer.Pool = pool.ID
 	transfer.TokenTransfer.Connector = pool.Connector
 
-	if pool.State != core.TokenPoolStateConfirmed {
-		return nil, i18n.NewError(ctx, coremsgs.MsgTokenPoolNotConfirmed)
+	if err = checkTokenPoolActive(ctx, pool); err != nil {
+		return nil, err
 	}
 	if transfer.Key, err = am.identity.ResolveInputSigningKey(ctx, transfer.Key, am.keyNormalization); err != nil {
 		return nil, err
@@ -112,12 +123,27 @@ func (am *assetManager) validateTransfer(ctx context.Context, transfer *core.Tok
 	}
 	if transfer.To == "" {
 		transfer.To = transfer.Key
+	} else if blockchain.IsENSName(transfer.To) {
+		// ENS names are resolved to an address by the blockchain plugin
+		to, err := am.identity.ResolveInputVerifierRef(ctx, &core.VerifierRef{Value: transfer.To}, blockchain.ResolveKeyIntentLookup)
+		if err != nil {
+			return nil, err
+		}
+		transfer.To = to.Value
+	}
+	if err = am.checkTransferPolicy(ctx, pool, &transfer.TokenTransfer); err != nil {
+		return nil, err
 	}
 	return pool, nil
 }
 
 func (am *assetManager) MintTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (out *core.TokenTransfer, err error) {
 	transfer.Type = core.TokenTransferTypeMint
+	if signoff, err := am.transferRequiresSignoff(ctx, transfer); err != nil {
+		return nil, err
+	} else if signoff {
+		return am.createTransferRequest(ctx, transfer)
+	}
 
 	sender := am.NewTransfer(transfer)
 	if am.metrics.IsMetricsEnabled() {
@@ -148,6 +174,11 @@ func (am *assetManager) BurnTokens(ctx context.Context, transfer *core.TokenTran
 
 func (am *assetManager) TransferTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (out *core.TokenTransfer, err error) {
 	transfer.Type = core.TokenTransferTypeTransfer
+	if signoff, err := am.transferRequiresSignoff(ctx, transfer); err != nil {
+		return nil, err
+	} else if signoff {
+		return am.createTransferRequest(ctx, transfer)
+	}
 
 	sender := am.NewTransfer(transfer)
 	if am.metrics.IsMetricsEnabled() {
@@ -161,6 +192,21 @@ func (am *assetManager) TransferTokens(ctx context.Context, transfer *core.Token
 	return &transfer.TokenTransfer, err
 }
 
+// CheckTransferEligibility asks the connector if a transfer would be permitted, without submitting it.
+// For compliance-gated tokens, this surfaces rejections (such as an unverified recipient) before any transaction is created.
+func (am *assetManager) CheckTransferEligibility(ctx context.Context, transfer *core.TokenTransferInput) (*core.TokenTransferEligibility, error) {
+	transfer.Type = core.TokenTransferTypeTransfer
+	pool, err := am.validateTransfer(ctx, transfer)
+	if err != nil {
+		return nil, err
+	}
+	plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.CheckTransfer(ctx, pool.Locator, &transfer.TokenTransfer)
+}
+
 func (s *transferSender) resolveAndSend(ctx context.Context, method sendMethod) (err error) {
 	if !s.resolved {
 		var opResubmit bool
@@ -0,0 +1,199 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/hyperledger/firefly/pkg/tokens"
+)
+
+// TransferTokensBatch submits every leg of the batch as a single operation, so the connector can move them all in one
+// blockchain transaction (such as an ERC-1155 safeBatchTransferFrom). Each leg is assigned its own LocalID up-front,
+// which is used for the transfer record created when the connector confirms that leg.
+func (am *assetManager) TransferTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput, waitConfirm bool) (*core.TokenTransferBatch, error) {
+	if len(batch.Legs) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferBatchEmpty)
+	}
+
+	// Every leg is validated before the transaction is created, so a batch that is rejected leaves nothing behind
+	template, pool, plugin, err := am.validateTransferBatch(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &core.TokenTransferBatch{
+		Transfers: make([]*core.TokenTransfer, len(batch.Legs)),
+	}
+	for i, leg := range batch.Legs {
+		out.Transfers[i] = &core.TokenTransfer{
+			Type:       core.TokenTransferTypeTransfer,
+			LocalID:    fftypes.NewUUID(),
+			Pool:       pool.ID,
+			Connector:  pool.Connector,
+			TokenIndex: leg.TokenIndex,
+			Amount:     leg.Amount,
+			Key:        template.Key,
+			From:       template.From,
+			To:         template.To,
+			Config:     batch.Config,
+		}
+	}
+
+	txid, err := am.txHelper.SubmitNewTransaction(ctx, core.TransactionTypeTokenTransfer, batch.IdempotencyKey)
+	if err != nil {
+		// As for single transfers, an idempotency key clash resubmits any operation that never got submitted
+		if idemErr, ok := err.(*sqlcommon.IdempotencyError); ok {
+			return am.resubmitTransferBatch(ctx, idemErr, out, waitConfirm)
+		}
+		return nil, err
+	}
+	out.TX = core.TransactionRef{
+		ID:   txid,
+		Type: core.TransactionTypeTokenTransfer,
+	}
+	for _, transfer := range out.Transfers {
+		transfer.TX = out.TX
+		if am.metrics.IsMetricsEnabled() {
+			am.metrics.TransferSubmitted(transfer)
+		}
+	}
+
+	send := func(ctx context.Context) error {
+		return am.sendTransferBatch(ctx, plugin, pool, out)
+	}
+	if !waitConfirm {
+		return out, send(ctx)
+	}
+	return am.waitTransferBatch(ctx, out, send)
+}
+
+// validateTransferBatch validates the accounts once, as they are shared by every leg. The pool policy, and the sign-off
+// threshold, are checked against the total amount of the batch.
+func (am *assetManager) validateTransferBatch(ctx context.Context, batch *core.TokenTransferBatchInput) (*core.TokenTransferInput, *core.TokenPool, tokens.Plugin, error) {
+	template := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Type: core.TokenTransferTypeTransfer,
+			Key:  batch.Key,
+			From: batch.From,
+			To:   batch.To,
+		},
+		Pool: batch.Pool,
+	}
+	for _, leg := range batch.Legs {
+		template.Amount.Int().Add(template.Amount.Int(), leg.Amount.Int())
+	}
+	pool, err := am.validateTransfer(ctx, template)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if am.requiresSignoff(pool, &template.Amount) {
+		return nil, nil, nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferBatchRequiresSignoff, template.Amount.String(), am.signoff.thresholdStr)
+	}
+	if template.From == template.To {
+		return nil, nil, nil, i18n.NewError(ctx, coremsgs.MsgCannotTransferToSelf)
+	}
+	plugin, err := am.selectTokenPlugin(ctx, pool.Connector)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return template, pool, plugin, nil
+}
+
+// resubmitTransferBatch handles a batch whose idempotency key matches an existing transaction. The transfers are
+// replaced with those persisted in the operation of the existing transaction, so the result (and any wait for
+// confirmation) refers to the LocalIDs that were actually submitted.
+func (am *assetManager) resubmitTransferBatch(ctx context.Context, idemErr *sqlcommon.IdempotencyError, out *core.TokenTransferBatch, waitConfirm bool) (*core.TokenTransferBatch, error) {
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	ops, _, err := am.database.GetOperations(ctx, am.namespace, fb.And(
+		fb.Eq("tx", idemErr.ExistingTXID),
+		fb.Eq("type", core.OpTypeTokenTransferBatch),
+	))
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, idemErr
+	}
+	if out.Transfers, err = txcommon.RetrieveTokenTransferBatchInputs(ctx, ops[0]); err != nil {
+		return nil, err
+	}
+	out.TX = core.TransactionRef{
+		ID:   idemErr.ExistingTXID,
+		Type: core.TransactionTypeTokenTransfer,
+	}
+
+	resubmit := func(ctx context.Context) error {
+		operation, err := am.operations.ResubmitOperations(ctx, idemErr.ExistingTXID)
+		if err != nil {
+			return err
+		} else if operation == nil {
+			// The operation was already submitted, so the clash is reported as for single transfers
+			return idemErr
+		}
+		return nil
+	}
+	if !waitConfirm {
+		return out, resubmit(ctx)
+	}
+	return am.waitTransferBatch(ctx, out, resubmit)
+}
+
+// waitTransferBatch sends the batch and waits for it to be confirmed. All legs are confirmed in the same blockchain
+// transaction, so it waits for the last one and then re-reads the others.
+func (am *assetManager) waitTransferBatch(ctx context.Context, out *core.TokenTransferBatch, send syncasync.SendFunction) (*core.TokenTransferBatch, error) {
+	last := len(out.Transfers) - 1
+	confirmed, err := am.syncasync.WaitForTokenTransfer(ctx, out.Transfers[last].LocalID, send)
+	if err != nil {
+		return out, err
+	}
+	out.Transfers[last] = confirmed
+	for i := 0; i < last; i++ {
+		transfer, err := am.database.GetTokenTransferByID(ctx, am.namespace, out.Transfers[i].LocalID)
+		if err != nil {
+			return out, err
+		} else if transfer != nil {
+			out.Transfers[i] = transfer
+		}
+	}
+	return out, nil
+}
+
+func (am *assetManager) sendTransferBatch(ctx context.Context, plugin tokens.Plugin, pool *core.TokenPool, out *core.TokenTransferBatch) error {
+	op := core.NewOperation(
+		plugin,
+		am.namespace,
+		out.TX.ID,
+		core.OpTypeTokenTransferBatch)
+	if err := txcommon.AddTokenTransferBatchInputs(op, out.Transfers); err != nil {
+		return err
+	}
+	if err := am.operations.AddOrReuseOperation(ctx, op); err != nil {
+		return err
+	}
+
+	_, err := am.operations.RunOperation(ctx, opTransferBatch(op, pool, out.Transfers))
+	return err
+}
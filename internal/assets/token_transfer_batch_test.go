@@ -0,0 +1,455 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestTransferBatch() *core.TokenTransferBatchInput {
+	return &core.TokenTransferBatchInput{
+		Pool: "pool1",
+		From: "A",
+		To:   "B",
+		Legs: []*core.TokenTransferBatchLeg{
+			{TokenIndex: "1", Amount: *fftypes.NewFFBigInt(5)},
+			{TokenIndex: "2", Amount: *fftypes.NewFFBigInt(10)},
+		},
+		IdempotencyKey: "idem1",
+	}
+}
+
+func mockValidTransferBatch(am *assetManager) *core.TokenPool {
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	return pool
+}
+
+func TestTransferTokensBatchSuccess(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(txID, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeTokenTransferBatch && op.Transaction.Equals(txID)
+	})).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferBatchData)
+		return op.Type == core.OpTypeTokenTransferBatch && data.Pool == pool && len(data.Transfers) == 2
+	})).Return(nil, nil)
+
+	out, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.NoError(t, err)
+	assert.Equal(t, *txID, *out.TX.ID)
+	assert.Len(t, out.Transfers, 2)
+	for i, transfer := range out.Transfers {
+		assert.NotNil(t, transfer.LocalID)
+		assert.Equal(t, batch.Legs[i].TokenIndex, transfer.TokenIndex)
+		assert.Equal(t, core.TokenTransferTypeTransfer, transfer.Type)
+		assert.Equal(t, pool.ID, transfer.Pool)
+		assert.Equal(t, "0x12345", transfer.Key)
+		assert.Equal(t, "A", transfer.From)
+		assert.Equal(t, "B", transfer.To)
+		assert.Equal(t, txID, transfer.TX.ID)
+	}
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchNoLegs(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.TransferTokensBatch(context.Background(), &core.TokenTransferBatchInput{}, false)
+	assert.Regexp(t, "FF10483", err)
+}
+
+func TestTransferTokensBatchToSelf(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	batch.To = "A"
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.Regexp(t, "FF10280", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertNotCalled(t, "SubmitNewTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferTokensBatchBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mth.AssertNotCalled(t, "SubmitNewTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferTokensBatchBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	pool := &core.TokenPool{
+		Connector: "bad",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.Regexp(t, "FF10272", err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertNotCalled(t, "SubmitNewTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTransferTokensBatchTXFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockValidTransferBatch(am)
+
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+}
+
+func newTestTransferBatchOp(t *testing.T, txID *fftypes.UUID) (*core.Operation, []*core.TokenTransfer) {
+	transfers := []*core.TokenTransfer{
+		{LocalID: fftypes.NewUUID(), TokenIndex: "1", Amount: *fftypes.NewFFBigInt(5)},
+		{LocalID: fftypes.NewUUID(), TokenIndex: "2", Amount: *fftypes.NewFFBigInt(10)},
+	}
+	op := &core.Operation{ID: fftypes.NewUUID(), Transaction: txID, Type: core.OpTypeTokenTransferBatch}
+	err := txcommon.AddTokenTransferBatchInputs(op, transfers)
+	assert.NoError(t, err)
+	return op, transfers
+}
+
+func mockIdempotentTransferBatch(am *assetManager, existingTX *fftypes.UUID, ops []*core.Operation) {
+	mockValidTransferBatch(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi := am.database.(*databasemocks.Plugin)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  existingTX,
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(ops, nil, nil)
+}
+
+func TestTransferTokensBatchIdempotentResubmit(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	existingTX := fftypes.NewUUID()
+	op, transfers := newTestTransferBatchOp(t, existingTX)
+	mockIdempotentTransferBatch(am, existingTX, []*core.Operation{op})
+
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(op, nil)
+
+	// The result is the batch that was originally persisted, rather than new transfers
+	out, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.NoError(t, err)
+	assert.Equal(t, existingTX, out.TX.ID)
+	assert.Len(t, out.Transfers, 2)
+	assert.Equal(t, transfers[0].LocalID, out.Transfers[0].LocalID)
+	assert.Equal(t, transfers[1].LocalID, out.Transfers[1].LocalID)
+
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchIdempotentResubmitConfirm(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	existingTX := fftypes.NewUUID()
+	op, transfers := newTestTransferBatchOp(t, existingTX)
+	mockIdempotentTransferBatch(am, existingTX, []*core.Operation{op})
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mom := am.operations.(*operationmocks.Manager)
+	msa := am.syncasync.(*syncasyncmocks.Bridge)
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(op, nil)
+	msa.On("WaitForTokenTransfer", context.Background(), transfers[1].LocalID, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.SendFunction)
+			send(context.Background())
+		}).
+		Return(transfers[1], nil)
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", transfers[0].LocalID).Return(transfers[0], nil)
+
+	// The wait is on the persisted LocalIDs, which are the ones the connector confirms
+	out, err := am.TransferTokensBatch(context.Background(), batch, true)
+	assert.NoError(t, err)
+	assert.Equal(t, transfers[0].LocalID, out.Transfers[0].LocalID)
+
+	mom.AssertExpectations(t)
+	msa.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchIdempotentAlreadySubmitted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	existingTX := fftypes.NewUUID()
+	op, _ := newTestTransferBatchOp(t, existingTX)
+	mockIdempotentTransferBatch(am, existingTX, []*core.Operation{op})
+
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(nil, nil)
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.IsType(t, &sqlcommon.IdempotencyError{}, err)
+
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchIdempotentResubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	existingTX := fftypes.NewUUID()
+	op, _ := newTestTransferBatchOp(t, existingTX)
+	mockIdempotentTransferBatch(am, existingTX, []*core.Operation{op})
+
+	mom := am.operations.(*operationmocks.Manager)
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(nil, fmt.Errorf("resubmit fail"))
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.EqualError(t, err, "resubmit fail")
+
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchIdempotentNoOperation(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockIdempotentTransferBatch(am, fftypes.NewUUID(), []*core.Operation{})
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.IsType(t, &sqlcommon.IdempotencyError{}, err)
+}
+
+func TestTransferTokensBatchIdempotentBadOperation(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	existingTX := fftypes.NewUUID()
+	mockIdempotentTransferBatch(am, existingTX, []*core.Operation{{Input: fftypes.JSONObject{}}})
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.Regexp(t, "FF10483", err)
+}
+
+func TestTransferTokensBatchIdempotentLookupFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockValidTransferBatch(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi := am.database.(*databasemocks.Plugin)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  fftypes.NewUUID(),
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mdi.On("GetOperations", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestTransferTokensBatchAddOperationFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockValidTransferBatch(am)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestTransferTokensBatchConfirm(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	confirmed := []*core.TokenTransfer{
+		{ProtocolID: "000000000001/000000/000001"},
+		{ProtocolID: "000000000001/000000/000002"},
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	msa := am.syncasync.(*syncasyncmocks.Bridge)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.Anything).Return(nil, nil)
+	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.SendFunction)
+			send(context.Background())
+		}).
+		Return(confirmed[1], nil)
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", mock.Anything).Return(confirmed[0], nil)
+
+	out, err := am.TransferTokensBatch(context.Background(), batch, true)
+	assert.NoError(t, err)
+	assert.Equal(t, confirmed, out.Transfers)
+
+	mdi.AssertExpectations(t)
+	msa.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchConfirmFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockValidTransferBatch(am)
+
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	msa := am.syncasync.(*syncasyncmocks.Bridge)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, true)
+	assert.EqualError(t, err, "pop")
+
+	msa.AssertExpectations(t)
+}
+
+func TestTransferTokensBatchConfirmLookupFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	batch := newTestTransferBatch()
+	mockValidTransferBatch(am)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	msa := am.syncasync.(*syncasyncmocks.Bridge)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
+	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).Return(&core.TokenTransfer{}, nil)
+	mdi.On("GetTokenTransferByID", context.Background(), "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokensBatch(context.Background(), batch, true)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	msa.AssertExpectations(t)
+}
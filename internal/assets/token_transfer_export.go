@@ -0,0 +1,185 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const (
+	transferExportPageSize     = 1000
+	transferExportRowGroupSize = 10000
+)
+
+// transferExportColumns lists every column that can be exported, in the default order
+var transferExportColumns = []struct {
+	name  string
+	value func(t *core.TokenTransfer) string
+}{
+	{"localId", func(t *core.TokenTransfer) string { return t.LocalID.String() }},
+	{"type", func(t *core.TokenTransfer) string { return string(t.Type) }},
+	{"pool", func(t *core.TokenTransfer) string { return t.Pool.String() }},
+	{"tokenIndex", func(t *core.TokenTransfer) string { return t.TokenIndex }},
+	{"uri", func(t *core.TokenTransfer) string { return t.URI }},
+	{"connector", func(t *core.TokenTransfer) string { return t.Connector }},
+	{"key", func(t *core.TokenTransfer) string { return t.Key }},
+	{"from", func(t *core.TokenTransfer) string { return t.From }},
+	{"to", func(t *core.TokenTransfer) string { return t.To }},
+	{"amount", func(t *core.TokenTransfer) string { return t.Amount.String() }},
+	{"protocolId", func(t *core.TokenTransfer) string { return t.ProtocolID }},
+	{"message", func(t *core.TokenTransfer) string { return t.Message.String() }},
+	{"messageHash", func(t *core.TokenTransfer) string { return t.MessageHash.String() }},
+	{"txType", func(t *core.TokenTransfer) string { return string(t.TX.Type) }},
+	{"txId", func(t *core.TokenTransfer) string { return t.TX.ID.String() }},
+	{"blockchainEvent", func(t *core.TokenTransfer) string { return t.BlockchainEvent.String() }},
+	{"created", func(t *core.TokenTransfer) string {
+		if t.Created == nil {
+			return ""
+		}
+		return t.Created.String()
+	}},
+}
+
+// transferExportWriter is implemented by each export format
+type transferExportWriter interface {
+	Write(row []string) error
+	Close() error
+}
+
+type csvExportWriter struct {
+	*csv.Writer
+}
+
+func (w *csvExportWriter) Close() error {
+	w.Flush()
+	return w.Error()
+}
+
+// ExportTokenTransfers validates the export, and then returns a reader that streams the matching transfers
+// as they are read from the database, a page at a time. Any error after the first page is returned
+// from the reader, as the response will already have started.
+func (am *assetManager) ExportTokenTransfers(ctx context.Context, export *core.TokenTransferExport) (io.ReadCloser, error) {
+	if export.Format == "" {
+		export.Format = core.TokenExportFormatCSV
+	}
+	if export.Format != core.TokenExportFormatCSV && export.Format != core.TokenExportFormatParquet {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenExportInvalidFormat, export.Format,
+			strings.Join([]string{string(core.TokenExportFormatCSV), string(core.TokenExportFormatParquet)}, ","))
+	}
+
+	columns, values, err := am.resolveTransferExportColumns(ctx, export.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	conditions := []ffapi.Filter{fb.Eq("invalidated", false)}
+	if export.Pool != "" {
+		pool, err := am.GetTokenPoolByNameOrID(ctx, export.Pool)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, fb.Eq("pool", pool.ID))
+	}
+	if export.Account != "" {
+		conditions = append(conditions, fb.Or(fb.Eq("from", export.Account), fb.Eq("to", export.Account)))
+	}
+	if export.StartTime != nil {
+		conditions = append(conditions, fb.Gte("created", export.StartTime))
+	}
+	if export.EndTime != nil {
+		conditions = append(conditions, fb.Lt("created", export.EndTime))
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		err := am.writeTransferExport(ctx, export.Format, conditions, pw, columns, values)
+		if err != nil {
+			log.L(ctx).Errorf("Token transfer export failed: %s", err)
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
+
+func (am *assetManager) resolveTransferExportColumns(ctx context.Context, requested []string) ([]string, []func(t *core.TokenTransfer) string, error) {
+	all := make([]string, len(transferExportColumns))
+	byName := make(map[string]func(t *core.TokenTransfer) string, len(transferExportColumns))
+	for i, c := range transferExportColumns {
+		all[i] = c.name
+		byName[c.name] = c.value
+	}
+	if len(requested) == 0 {
+		requested = all
+	}
+	values := make([]func(t *core.TokenTransfer) string, len(requested))
+	for i, name := range requested {
+		if values[i] = byName[name]; values[i] == nil {
+			return nil, nil, i18n.NewError(ctx, coremsgs.MsgTokenExportInvalidColumn, name, strings.Join(all, ","))
+		}
+	}
+	return requested, values, nil
+}
+
+// newTransferExportWriter starts the export. Parquet stores the column names in the file schema, whereas CSV has a header row.
+func newTransferExportWriter(format core.TokenExportFormat, w io.Writer, columns []string) transferExportWriter {
+	if format == core.TokenExportFormatParquet {
+		return newParquetWriter(w, columns, transferExportRowGroupSize)
+	}
+	cw := csv.NewWriter(w)
+	_ = cw.Write(columns) // any error is returned on close
+	return &csvExportWriter{cw}
+}
+
+// writeTransferExport pages through the transfers in database sequence order, using the last sequence read
+// as a cursor for the next page, so transfers inserted while the export is running cannot shift the pages.
+func (am *assetManager) writeTransferExport(ctx context.Context, format core.TokenExportFormat, conditions []ffapi.Filter, pw io.Writer, columns []string, values []func(t *core.TokenTransfer) string) error {
+	w := newTransferExportWriter(format, pw, columns)
+	row := make([]string, len(values))
+	last := int64(-1)
+	for {
+		fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+		pageConditions := append(append([]ffapi.Filter{}, conditions...), fb.Gt("sequence", last))
+		filter := fb.And(pageConditions...).Sort("sequence").Ascending().Limit(transferExportPageSize)
+		transfers, _, err := am.database.GetTokenTransfers(ctx, am.namespace, filter)
+		if err != nil {
+			return err
+		}
+		for _, transfer := range transfers {
+			last = transfer.Sequence
+			for i, value := range values {
+				row[i] = value(transfer)
+			}
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		if len(transfers) < transferExportPageSize {
+			return w.Close()
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExportTokenTransfersCSV(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{ID: fftypes.NewUUID()}
+	transfer := &core.TokenTransfer{
+		LocalID: fftypes.NewUUID(),
+		Type:    core.TokenTransferTypeTransfer,
+		From:    "0x01",
+		To:      "0x02",
+	}
+	transfer.Amount.Int().SetInt64(10)
+	start := fftypes.Now()
+	end := fftypes.Now()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.String() == fmt.Sprintf("( invalidated == false ) && ( pool == '%s' ) && ( ( from == '0x01' ) || ( to == '0x01' ) ) && ( created >= %d ) && ( created << %d ) && ( sequence >> -1 ) sort=sequence limit=1000",
+			pool.ID, start.UnixNano(), end.UnixNano())
+	})).Return([]*core.TokenTransfer{transfer}, nil, nil)
+
+	reader, err := am.ExportTokenTransfers(context.Background(), &core.TokenTransferExport{
+		Pool:      "pool1",
+		Account:   "0x01",
+		Columns:   []string{"localId", "from", "to", "amount", "created"},
+		StartTime: start,
+		EndTime:   end,
+	})
+	assert.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("localId,from,to,amount,created\n%s,0x01,0x02,10,\n", transfer.LocalID), string(b))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExportTokenTransfersParquetPaged(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	page := make([]*core.TokenTransfer, transferExportPageSize)
+	for i := range page {
+		page[i] = &core.TokenTransfer{Created: fftypes.Now(), Sequence: int64(i + 100)}
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == 0 && strings.HasSuffix(f.String(), "( sequence >> -1 ) sort=sequence limit=1000")
+	})).Return(page, nil, nil)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, _ := filter.Finalize()
+		return f.Skip == 0 && strings.HasSuffix(f.String(), fmt.Sprintf("( sequence >> %d ) sort=sequence limit=1000", transferExportPageSize+99))
+	})).Return([]*core.TokenTransfer{}, nil, nil)
+
+	reader, err := am.ExportTokenTransfers(context.Background(), &core.TokenTransferExport{
+		Format: core.TokenExportFormatParquet,
+	})
+	assert.NoError(t, err)
+	b, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "PAR1", string(b[0:4]))
+	assert.Equal(t, "PAR1", string(b[len(b)-4:]))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExportTokenTransfersQueryFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	reader, err := am.ExportTokenTransfers(context.Background(), &core.TokenTransferExport{})
+	assert.NoError(t, err)
+	_, err = io.ReadAll(reader)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestExportTokenTransfersWriteFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransfers", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransfer{{}}, nil, nil)
+
+	values := []func(t *core.TokenTransfer) string{transferExportColumns[0].value}
+	err := am.writeTransferExport(context.Background(), core.TokenExportFormatParquet, nil, &failingWriter{}, []string{"localId"}, values)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestExportTokenTransfersBadFormat(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.ExportTokenTransfers(context.Background(), &core.TokenTransferExport{Format: "xlsx"})
+	assert.Regexp(t, "FF10497.*xlsx", err)
+}
+
+func TestExportTokenTransfersBadColumn(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.ExportTokenTransfers(context.Background(), &core.TokenTransferExport{Columns: []string{"localId", "wrong"}})
+	assert.Regexp(t, "FF10498.*wrong", err)
+}
+
+func TestExportTokenTransfersBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.ExportTokenTransfers(context.Background(), &core.TokenTransferExport{Pool: "pool1"})
+	assert.EqualError(t, err, "pop")
+}
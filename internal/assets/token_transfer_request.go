@@ -0,0 +1,246 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// signoffUpdateAttempts bounds how many times a sign-off is retried against concurrent updates to the request
+const signoffUpdateAttempts = 5
+
+type signoffConfig struct {
+	threshold    *big.Rat // in whole tokens, or nil if sign-off is disabled
+	thresholdStr string
+	approvers    []string
+	required     int
+}
+
+func loadSignoffConfig(ctx context.Context) (conf signoffConfig, err error) {
+	thresholdStr := config.GetString(coreconfig.AssetSignoffThreshold)
+	if thresholdStr == "" {
+		return conf, nil
+	}
+	threshold, ok := new(big.Rat).SetString(thresholdStr)
+	if !ok || threshold.Sign() < 0 {
+		return conf, i18n.NewError(ctx, coremsgs.MsgInvalidSignoffThreshold, thresholdStr)
+	}
+	conf.threshold = threshold
+	conf.thresholdStr = thresholdStr
+	conf.approvers = config.GetStringSlice(coreconfig.AssetSignoffApprovers)
+	conf.required = config.GetInt(coreconfig.AssetSignoffRequired)
+	if conf.required < 1 || conf.required > len(conf.approvers) {
+		return conf, i18n.NewError(ctx, coremsgs.MsgInvalidSignoffApprovers, conf.required, len(conf.approvers))
+	}
+	return conf, nil
+}
+
+// requiresSignoff compares the amount against the threshold in whole tokens, by scaling it down by the decimals of the pool
+func (am *assetManager) requiresSignoff(pool *core.TokenPool, amount *fftypes.FFBigInt) bool {
+	if am.signoff.threshold == nil {
+		return false
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(pool.Decimals)), nil)
+	return new(big.Rat).SetFrac(amount.Int(), scale).Cmp(am.signoff.threshold) > 0
+}
+
+// transferRequiresSignoff looks up the pool of a transfer or mint, only when sign-off is enabled, to check the amount against the threshold
+func (am *assetManager) transferRequiresSignoff(ctx context.Context, transfer *core.TokenTransferInput) (bool, error) {
+	if am.signoff.threshold == nil {
+		return false, nil
+	}
+	pool, err := am.resolveTransferPool(ctx, transfer.Pool)
+	if err != nil {
+		return false, err
+	}
+	return am.requiresSignoff(pool, &transfer.Amount), nil
+}
+
+func (am *assetManager) isSignoffApprover(principal string) bool {
+	for _, approver := range am.signoff.approvers {
+		if approver == principal {
+			return true
+		}
+	}
+	return false
+}
+
+// createTransferRequest validates a transfer or mint that is above the sign-off threshold, and stores it
+// as a pending request instead of submitting it. Nothing is sent to the token connector until the
+// required number of approvers have signed off the request.
+func (am *assetManager) createTransferRequest(ctx context.Context, transfer *core.TokenTransferInput) (*core.TokenTransfer, error) {
+	if _, err := am.validateTransfer(ctx, transfer); err != nil {
+		return nil, err
+	}
+
+	request := &core.TokenTransferRequest{
+		ID:        fftypes.NewUUID(),
+		Namespace: am.namespace,
+		State:     core.TokenTransferRequestStatePending,
+		Type:      transfer.Type,
+		Pool:      transfer.TokenTransfer.Pool,
+		Key:       transfer.Key,
+		Amount:    transfer.Amount,
+		Transfer:  transfer,
+		Required:  am.signoff.required,
+		Signoffs:  core.TokenTransferSignoffs{},
+	}
+	if err := am.database.InsertTokenTransferRequest(ctx, request); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Token %s of %s held for sign-off in request '%s'", transfer.Type, transfer.Amount.String(), request.ID)
+
+	transfer.Request = request.ID
+	return &transfer.TokenTransfer, nil
+}
+
+func (am *assetManager) GetTokenTransferRequests(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransferRequest, *ffapi.FilterResult, error) {
+	return am.database.GetTokenTransferRequests(ctx, am.namespace, filter)
+}
+
+func (am *assetManager) GetTokenTransferRequestByID(ctx context.Context, id string) (*core.TokenTransferRequest, error) {
+	requestID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return am.database.GetTokenTransferRequestByID(ctx, am.namespace, requestID)
+}
+
+// SignoffTokenTransferRequest records the sign-off of one of the configured approvers against a pending
+// request. The approver is the authenticated principal of the caller, and never the signer in the input,
+// so one approver cannot sign off as another. Once the required number of approvers have signed off, the
+// transfer is submitted to the token connector. The idempotency key of the transfer ensures it is only
+// submitted once, even if the final sign-offs arrive concurrently.
+func (am *assetManager) SignoffTokenTransferRequest(ctx context.Context, id, principal string, signer *core.SignerRef) (*core.TokenTransferRequest, error) {
+	if principal == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferSignoffNoPrincipal)
+	}
+	if !am.isSignoffApprover(principal) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferSignoffNotApprover, principal)
+	}
+	request, err := am.recordSignoff(ctx, id, principal, signer)
+	if err != nil {
+		return nil, err
+	}
+	if len(request.Signoffs) < request.Required {
+		return request, nil
+	}
+
+	submitErr := am.submitTransferRequest(ctx, request)
+	if _, alreadySubmitted := submitErr.(*sqlcommon.IdempotencyError); alreadySubmitted {
+		log.L(ctx).Debugf("Token transfer request '%s' has already been submitted", request.ID)
+		return request, nil
+	}
+	var update ffapi.Update
+	if submitErr != nil {
+		request.State = core.TokenTransferRequestStateFailed
+		request.Error = submitErr.Error()
+		update = database.TokenTransferRequestQueryFactory.NewUpdate(ctx).Set("error", request.Error)
+	} else {
+		request.State = core.TokenTransferRequestStateSubmitted
+		request.TX = request.Transfer.TX
+		update = database.TokenTransferRequestQueryFactory.NewUpdate(ctx).Set("tx.type", request.TX.Type).Set("tx.id", request.TX.ID)
+	}
+	update = update.Set("state", request.State)
+
+	if _, err := am.database.UpdateTokenTransferRequest(ctx, am.namespace, request.ID, nil, update); err != nil {
+		return nil, err
+	}
+	return request, submitErr
+}
+
+// recordSignoff adds the sign-off to the request, with an update that only applies if the request is unchanged
+// since it was read. If a concurrent sign-off updated the request first, it is read again and the sign-off is
+// checked against the latest state, so no sign-off can overwrite another.
+func (am *assetManager) recordSignoff(ctx context.Context, id, principal string, signer *core.SignerRef) (*core.TokenTransferRequest, error) {
+	resolved := false
+	for attempt := 0; attempt < signoffUpdateAttempts; attempt++ {
+		request, err := am.GetTokenTransferRequestByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if request == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferRequestNotFound, id)
+		}
+		if request.State != core.TokenTransferRequestStatePending {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferRequestNotPending, request.ID, request.State)
+		}
+
+		if !resolved {
+			if err := am.identity.ResolveInputSigningIdentity(ctx, signer); err != nil {
+				return nil, err
+			}
+			resolved = true
+		}
+		for _, signoff := range request.Signoffs {
+			if signoff.Principal == principal {
+				return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferAlreadySignedOff, principal, request.ID)
+			}
+		}
+
+		request.Signoffs = append(request.Signoffs, &core.TokenTransferSignoff{
+			Principal: principal,
+			Author:    signer.Author,
+			Key:       signer.Key,
+			Created:   fftypes.Now(),
+		})
+		signoffs, _ := json.Marshal(request.Signoffs)
+		fb := database.TokenTransferRequestQueryFactory.NewFilter(ctx)
+		filter := fb.And(
+			fb.Eq("state", core.TokenTransferRequestStatePending),
+			fb.Eq("updated", request.Updated),
+		)
+		update := database.TokenTransferRequestQueryFactory.NewUpdate(ctx).Set("signoffs", signoffs)
+		updated, err := am.database.UpdateTokenTransferRequest(ctx, am.namespace, request.ID, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if updated {
+			return request, nil
+		}
+		log.L(ctx).Debugf("Token transfer request '%s' was updated concurrently - retrying sign-off", request.ID)
+	}
+	return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferSignoffConflict, id)
+}
+
+func (am *assetManager) submitTransferRequest(ctx context.Context, request *core.TokenTransferRequest) error {
+	transfer := request.Transfer
+	if transfer.IdempotencyKey == "" {
+		transfer.IdempotencyKey = core.IdempotencyKey("signoff:" + request.ID.String())
+	}
+	if am.metrics.IsMetricsEnabled() {
+		am.metrics.TransferSubmitted(&transfer.TokenTransfer)
+	}
+	if err := am.NewTransfer(transfer).Send(ctx); err != nil {
+		return err
+	}
+	log.L(ctx).Infof("Submitted signed off token transfer request '%s' tx=%s", request.ID, transfer.TX.ID)
+	return nil
+}
@@ -0,0 +1,663 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func enableTestSignoff(am *assetManager, required int) {
+	am.signoff = signoffConfig{
+		threshold:    big.NewRat(100, 1),
+		thresholdStr: "100",
+		approvers:    []string{"did:firefly:org/org1", "did:firefly:org/org2"},
+		required:     required,
+	}
+}
+
+func newTestTransferRequest(required int, signoffs ...string) *core.TokenTransferRequest {
+	request := &core.TokenTransferRequest{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		State:     core.TokenTransferRequestStatePending,
+		Type:      core.TokenTransferTypeTransfer,
+		Pool:      fftypes.NewUUID(),
+		Key:       "0x12345",
+		Amount:    *fftypes.NewFFBigInt(1000),
+		Transfer: &core.TokenTransferInput{
+			TokenTransfer: core.TokenTransfer{
+				Type:   core.TokenTransferTypeTransfer,
+				Key:    "0x12345",
+				From:   "0x12345",
+				To:     "0x67890",
+				Amount: *fftypes.NewFFBigInt(1000),
+			},
+			Pool: "pool1",
+		},
+		Required: required,
+		Signoffs: core.TokenTransferSignoffs{},
+		Updated:  fftypes.Now(),
+	}
+	for _, author := range signoffs {
+		request.Signoffs = append(request.Signoffs, &core.TokenTransferSignoff{Principal: author, Author: author})
+	}
+	return request
+}
+
+func matchRequestUpdate(fields ...string) interface{} {
+	return mock.MatchedBy(func(update ffapi.Update) bool {
+		info, err := update.Finalize()
+		if err != nil || len(info.SetOperations) != len(fields) {
+			return false
+		}
+		for i, op := range info.SetOperations {
+			if op.Field != fields[i] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// matchSignoffFilter checks the sign-off update is conditional on the request being unchanged since it was read
+func matchSignoffFilter(request *core.TokenTransferRequest) interface{} {
+	return mock.MatchedBy(func(filter ffapi.Filter) bool {
+		f, err := filter.Finalize()
+		return err == nil && f.String() == fmt.Sprintf("( state == 'pending' ) && ( updated == %d )", request.Updated.UnixNano())
+	})
+}
+
+func TestLoadSignoffConfigDisabled(t *testing.T) {
+	coreconfig.Reset()
+	conf, err := loadSignoffConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, conf.threshold)
+}
+
+func TestLoadSignoffConfigOK(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.AssetSignoffThreshold, "1000000000000000000000")
+	config.Set(coreconfig.AssetSignoffApprovers, []string{"did:firefly:org/org1", "did:firefly:org/org2"})
+	config.Set(coreconfig.AssetSignoffRequired, 2)
+	conf, err := loadSignoffConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1000000000000000000000/1", conf.threshold.String())
+	assert.Equal(t, 2, conf.required)
+	assert.Len(t, conf.approvers, 2)
+}
+
+func TestLoadSignoffConfigBadThreshold(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.AssetSignoffThreshold, "-1")
+	_, err := loadSignoffConfig(context.Background())
+	assert.Regexp(t, "FF10500", err)
+}
+
+func TestLoadSignoffConfigNotEnoughApprovers(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.AssetSignoffThreshold, "100")
+	config.Set(coreconfig.AssetSignoffApprovers, []string{"did:firefly:org/org1"})
+	config.Set(coreconfig.AssetSignoffRequired, 2)
+	_, err := loadSignoffConfig(context.Background())
+	assert.Regexp(t, "FF10501", err)
+}
+
+func TestTransferTokensRequiresSignoff(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			To:     "0x67890",
+			Amount: *fftypes.NewFFBigInt(1000),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mdi.On("InsertTokenTransferRequest", context.Background(), mock.MatchedBy(func(request *core.TokenTransferRequest) bool {
+		return request.State == core.TokenTransferRequestStatePending &&
+			request.Type == core.TokenTransferTypeTransfer &&
+			request.Pool.Equals(pool.ID) &&
+			request.Key == "0x12345" &&
+			request.Required == 2 &&
+			request.Transfer == transfer
+	})).Return(nil)
+
+	out, err := am.TransferTokens(context.Background(), transfer, true)
+	assert.NoError(t, err)
+	assert.NotNil(t, out.Request)
+	assert.Nil(t, out.TX.ID)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestMintTokensRequiresSignoff(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	mint := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(1000),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("InsertTokenTransferRequest", context.Background(), mock.MatchedBy(func(request *core.TokenTransferRequest) bool {
+		return request.Type == core.TokenTransferTypeMint
+	})).Return(fmt.Errorf("pop"))
+
+	_, err := am.MintTokens(context.Background(), mint, false)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestTransferTokensBelowSignoffThreshold(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			To:     "0x67890",
+			Amount: *fftypes.NewFFBigInt(100),
+		},
+		Pool: "pool1",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&core.TokenPool{}, nil)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+}
+
+func TestTransferTokensSignoffThresholdDecimals(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	// 99 tokens of a pool with 18 decimals is below the threshold of 100 tokens, despite the large raw amount
+	amount, _ := new(big.Int).SetString("99000000000000000000", 10)
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			To:     "0x67890",
+			Amount: fftypes.FFBigInt(*amount),
+		},
+		Pool: "pool1",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(&core.TokenPool{Decimals: 18}, nil)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.EqualError(t, err, "pop")
+
+	mth.AssertExpectations(t)
+}
+
+func TestRequiresSignoffDecimals(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+	am.signoff.threshold = big.NewRat(1, 2)
+
+	pool := &core.TokenPool{Decimals: 2}
+	assert.False(t, am.requiresSignoff(pool, fftypes.NewFFBigInt(50)))
+	assert.True(t, am.requiresSignoff(pool, fftypes.NewFFBigInt(51)))
+}
+
+func TestTransferTokensRequiresSignoffValidateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			Amount: *fftypes.NewFFBigInt(1000),
+		},
+		Pool: "pool1",
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestTransferTokensBatchRequiresSignoff(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	mockValidTransferBatch(am)
+
+	_, err := am.TransferTokensBatch(context.Background(), &core.TokenTransferBatchInput{
+		Pool: "pool1",
+		Legs: []*core.TokenTransferBatchLeg{
+			{TokenIndex: "1", Amount: *fftypes.NewFFBigInt(60)},
+			{TokenIndex: "2", Amount: *fftypes.NewFFBigInt(60)},
+		},
+	}, false)
+	assert.Regexp(t, "FF10506", err)
+}
+
+func TestTransferTokensBatchRequiresSignoffPoolFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.TransferTokensBatch(context.Background(), &core.TokenTransferBatchInput{
+		Pool: "pool1",
+		Legs: []*core.TokenTransferBatchLeg{
+			{TokenIndex: "1", Amount: *fftypes.NewFFBigInt(60)},
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetTokenTransferRequests(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferRequests", context.Background(), "ns1", mock.Anything).Return([]*core.TokenTransferRequest{}, nil, nil)
+
+	_, _, err := am.GetTokenTransferRequests(context.Background(), nil)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetTokenTransferRequestByIDBadID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	_, err := am.GetTokenTransferRequestByID(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestSignoffTokenTransferRequestPartial(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	request := newTestTransferRequest(2)
+	signer := &core.SignerRef{Author: "org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Run(func(args mock.Arguments) {
+		signer.Author = "did:firefly:org/org1"
+		signer.Key = "0xaaaaa"
+	}).Return(nil)
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, matchSignoffFilter(request), matchRequestUpdate("signoffs")).Return(true, nil)
+
+	result, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenTransferRequestStatePending, result.State)
+	assert.Len(t, result.Signoffs, 1)
+	assert.Equal(t, "did:firefly:org/org1", result.Signoffs[0].Principal)
+	assert.Equal(t, "did:firefly:org/org1", result.Signoffs[0].Author)
+	assert.Equal(t, "0xaaaaa", result.Signoffs[0].Key)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestSignoffTokenTransferRequestSubmit(t *testing.T) {
+	am, cancel := newTestAssetsWithMetrics(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	request := newTestTransferRequest(2, "did:firefly:org/org1")
+	signer := &core.SignerRef{Author: "did:firefly:org/org2", Key: "0xbbbbb"}
+	pool := &core.TokenPool{
+		ID:        request.Pool,
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	txID := fftypes.NewUUID()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("signoff:"+request.ID.String())).Return(txID, nil)
+	mim.On("ResolveInputSigningKey", context.Background(), "0x12345", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", pool.ID).Return(nil, nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return data.Transfer.To == "0x67890" && data.Transfer.Amount.Int().Int64() == 1000
+	})).Return(nil, nil)
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, matchSignoffFilter(request), matchRequestUpdate("signoffs")).Return(true, nil)
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, nil, matchRequestUpdate("tx.type", "tx.id", "state")).Return(true, nil)
+
+	result, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org2", signer)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenTransferRequestStateSubmitted, result.State)
+	assert.Equal(t, txID, result.TX.ID)
+	assert.Len(t, result.Signoffs, 2)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestSignoffTokenTransferRequestSubmitFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	request := newTestTransferRequest(1)
+	request.Transfer.IdempotencyKey = "idem1"
+	signer := &core.SignerRef{Author: "did:firefly:org/org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(nil, fmt.Errorf("pop"))
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, matchSignoffFilter(request), matchRequestUpdate("signoffs")).Return(true, nil)
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, nil, matchRequestUpdate("error", "state")).Return(true, nil)
+
+	result, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.TokenTransferRequestStateFailed, result.State)
+	assert.Equal(t, "pop", result.Error)
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestSignoffTokenTransferRequestAlreadySubmitted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	request := newTestTransferRequest(1)
+	signer := &core.SignerRef{Author: "did:firefly:org/org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	existingTX := fftypes.NewUUID()
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, mock.Anything).Return(nil, &sqlcommon.IdempotencyError{
+		ExistingTXID:  existingTX,
+		OriginalError: fmt.Errorf("pop"),
+	})
+	mom.On("ResubmitOperations", context.Background(), existingTX).Return(nil, nil)
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, matchSignoffFilter(request), matchRequestUpdate("signoffs")).Return(true, nil)
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestSignoffTokenTransferRequestUpdateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	request := newTestTransferRequest(2)
+	signer := &core.SignerRef{Author: "did:firefly:org/org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil)
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSignoffTokenTransferRequestUpdateStateFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	request := newTestTransferRequest(1)
+	signer := &core.SignerRef{Author: "did:firefly:org/org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, matchSignoffFilter(request), matchRequestUpdate("signoffs")).Return(true, nil)
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, nil, matchRequestUpdate("error", "state")).Return(false, fmt.Errorf("pop2"))
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.EqualError(t, err, "pop2")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSignoffTokenTransferRequestConcurrentSignoff(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	// org1 and org2 both read the request with no sign-offs, but org2 records its sign-off first.
+	// The conditional update for org1 then fails, so it reads the request again and adds its
+	// sign-off alongside the one from org2, rather than overwriting it.
+	stale := newTestTransferRequest(2)
+	latest := newTestTransferRequest(2, "did:firefly:org/org2")
+	latest.ID = stale.ID
+	latestUpdated := fftypes.FFTime(stale.Updated.Time().Add(time.Second))
+	latest.Updated = &latestUpdated
+	signer := &core.SignerRef{Author: "did:firefly:org/org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", stale.ID).Return(stale, nil).Once()
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", stale.ID).Return(latest, nil).Once()
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil).Once()
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", stale.ID, matchSignoffFilter(stale), matchRequestUpdate("signoffs")).Return(false, nil).Once()
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", stale.ID, matchSignoffFilter(latest), matchRequestUpdate("signoffs")).Return(true, nil).Once()
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", stale.ID, nil, matchRequestUpdate("error", "state")).Return(true, nil)
+
+	result, err := am.SignoffTokenTransferRequest(context.Background(), stale.ID.String(), "did:firefly:org/org1", signer)
+	assert.EqualError(t, err, "pop")
+	assert.Len(t, result.Signoffs, 2)
+	assert.Equal(t, "did:firefly:org/org2", result.Signoffs[0].Author)
+	assert.Equal(t, "did:firefly:org/org1", result.Signoffs[1].Author)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestSignoffTokenTransferRequestConflictRetriesExhausted(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	request := newTestTransferRequest(2)
+	signer := &core.SignerRef{Author: "did:firefly:org/org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(func(ctx context.Context, ns string, id *fftypes.UUID) *core.TokenTransferRequest {
+		latest := newTestTransferRequest(2)
+		latest.ID = request.ID
+		return latest
+	}, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil).Once()
+	mdi.On("UpdateTokenTransferRequest", context.Background(), "ns1", request.ID, mock.Anything, matchRequestUpdate("signoffs")).Return(false, nil).Times(signoffUpdateAttempts)
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.Regexp(t, "FF10543", err)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestSignoffTokenTransferRequestNotApprover(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	// The signer in the input cannot be used to sign off as an approver - only the authenticated principal counts
+	_, err := am.SignoffTokenTransferRequest(context.Background(), fftypes.NewUUID().String(), "did:firefly:org/org3", &core.SignerRef{Author: "did:firefly:org/org1"})
+	assert.Regexp(t, "FF10504.*org3", err)
+}
+
+func TestSignoffTokenTransferRequestNoPrincipal(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), fftypes.NewUUID().String(), "", &core.SignerRef{Author: "did:firefly:org/org1"})
+	assert.Regexp(t, "FF10690", err)
+}
+
+func TestSignoffTokenTransferRequestDuplicate(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	request := newTestTransferRequest(2, "did:firefly:org/org1")
+	signer := &core.SignerRef{Author: "did:firefly:org/org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(nil)
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.Regexp(t, "FF10505", err)
+}
+
+func TestSignoffTokenTransferRequestResolveFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 2)
+
+	request := newTestTransferRequest(2)
+	signer := &core.SignerRef{Author: "org1"}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+	mim.On("ResolveInputSigningIdentity", context.Background(), signer).Return(fmt.Errorf("pop"))
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", signer)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSignoffTokenTransferRequestNotPending(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	request := newTestTransferRequest(1)
+	request.State = core.TokenTransferRequestStateSubmitted
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", request.ID).Return(request, nil)
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), request.ID.String(), "did:firefly:org/org1", &core.SignerRef{})
+	assert.Regexp(t, "FF10503", err)
+}
+
+func TestSignoffTokenTransferRequestNotFound(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	id := fftypes.NewUUID()
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenTransferRequestByID", context.Background(), "ns1", id).Return(nil, nil)
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), id.String(), "did:firefly:org/org1", &core.SignerRef{})
+	assert.Regexp(t, "FF10502", err)
+}
+
+func TestSignoffTokenTransferRequestBadID(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+	enableTestSignoff(am, 1)
+
+	_, err := am.SignoffTokenTransferRequest(context.Background(), "bad", "did:firefly:org/org1", &core.SignerRef{})
+	assert.Regexp(t, "FF00138", err)
+}
@@ -32,7 +32,9 @@ import (
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/mocks/tokenmocks"
 	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
@@ -60,6 +62,7 @@ func TestGetTokenTransferByID(t *testing.T) {
 	u := fftypes.NewUUID()
 	mdi := am.database.(*databasemocks.Plugin)
 	mdi.On("GetTokenTransferByID", context.Background(), "ns1", u).Return(&core.TokenTransfer{}, nil)
+	mdi.On("GetTokenPoolByID", context.Background(), "ns1", (*fftypes.UUID)(nil)).Return(nil, nil)
 	_, err := am.GetTokenTransferByID(context.Background(), u.String())
 	assert.NoError(t, err)
 
@@ -96,6 +99,7 @@ func TestMintTokensSuccess(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
@@ -221,6 +225,7 @@ func TestMintTokensBadConnector(t *testing.T) {
 	mth := am.txHelper.(*txcommonmocks.Helper)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 
 	_, err := am.MintTokens(context.Background(), mint, false)
@@ -265,6 +270,7 @@ func TestMintTokenDefaultPoolSuccess(t *testing.T) {
 		info, _ := f.Finalize()
 		return info.Count && info.Limit == 1
 	}))).Return(tokenPools, filterResult, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
@@ -456,6 +462,7 @@ func TestMintTokensFail(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
@@ -495,6 +502,7 @@ func TestMintTokensOperationFail(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
 
@@ -530,6 +538,7 @@ func TestMintTokensConfirm(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
@@ -573,6 +582,7 @@ func TestBurnTokensSuccess(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
@@ -643,6 +653,7 @@ func TestBurnTokensConfirm(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
@@ -689,6 +700,7 @@ func TestTransferTokensSuccess(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
@@ -705,6 +717,123 @@ func TestTransferTokensSuccess(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestTransferTokensResolveDestination(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "alice.eth",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mim.On("ResolveInputVerifierRef", context.Background(), &core.VerifierRef{Value: "alice.eth"}, blockchain.ResolveKeyIntentLookup).Return(&core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: "0x67890",
+	}, nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return op.Type == core.OpTypeTokenTransfer && data.Transfer.To == "0x67890"
+	})).Return(nil, nil)
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensDottedDestination(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "0.0.1002",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mom := am.operations.(*operationmocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0.0.1001", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
+	mom.On("RunOperation", context.Background(), mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(transferData)
+		return op.Type == core.OpTypeTokenTransfer && data.Transfer.To == "0.0.1002"
+	})).Return(nil, nil)
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestTransferTokensResolveDestinationFail(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "alice.eth",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mim.On("ResolveInputVerifierRef", context.Background(), &core.VerifierRef{Value: "alice.eth"}, blockchain.ResolveKeyIntentLookup).Return(nil, fmt.Errorf("pop"))
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
 func TestTransferTokensUnconfirmedPool(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -736,6 +865,36 @@ func TestTransferTokensUnconfirmedPool(t *testing.T) {
 	mth.AssertExpectations(t)
 }
 
+func TestTransferTokensPausedPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			From:   "A",
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Name:      "pool1",
+		Locator:   "F1",
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStatePaused,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mth := am.txHelper.(*txcommonmocks.Helper)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+
+	_, err := am.TransferTokens(context.Background(), transfer, false)
+	assert.Regexp(t, "FF10484", err)
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
 func TestTransferTokensIdentityFail(t *testing.T) {
 	am, cancel := newTestAssets(t)
 	defer cancel()
@@ -787,6 +946,7 @@ func TestTransferTokensNoFromOrTo(t *testing.T) {
 	mth := am.txHelper.(*txcommonmocks.Helper)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 
 	_, err := am.TransferTokens(context.Background(), transfer, false)
@@ -861,6 +1021,7 @@ func TestTransferTokensWithBroadcastMessage(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mbm.On("NewBroadcast", transfer.Message).Return(mms)
@@ -964,6 +1125,7 @@ func TestTransferTokensWithBroadcastMessageSendFail(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mbm.On("NewBroadcast", transfer.Message).Return(mms)
@@ -1061,6 +1223,7 @@ func TestTransferTokensWithPrivateMessage(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mpm.On("NewMessage", transfer.Message).Return(mms)
@@ -1184,6 +1347,7 @@ func TestTransferTokensConfirm(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	msa.On("WaitForTokenTransfer", context.Background(), mock.Anything, mock.Anything).
@@ -1249,6 +1413,7 @@ func TestTransferTokensWithBroadcastConfirm(t *testing.T) {
 	mom := am.operations.(*operationmocks.Manager)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 	mbm.On("NewBroadcast", transfer.Message).Return(mms)
@@ -1336,6 +1501,7 @@ func TestTransferPrepare(t *testing.T) {
 	mth := am.txHelper.(*txcommonmocks.Helper)
 	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
 	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
 	mth.On("SubmitNewTransaction", context.Background(), core.TransactionTypeTokenTransfer, core.IdempotencyKey("idem1")).Return(fftypes.NewUUID(), nil)
 
 	err := sender.Prepare(context.Background())
@@ -1345,3 +1511,69 @@ func TestTransferPrepare(t *testing.T) {
 	mim.AssertExpectations(t)
 	mth.AssertExpectations(t)
 }
+
+func TestCheckTransferEligibility(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	transfer := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			To:     "B",
+			Amount: *fftypes.NewFFBigInt(5),
+		},
+		Pool: "pool1",
+	}
+	pool := &core.TokenPool{
+		Locator:   "F1",
+		Connector: "magic-tokens",
+		State:     core.TokenPoolStateConfirmed,
+	}
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mti := am.tokens["magic-tokens"].(*tokenmocks.Plugin)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+	mti.On("CheckTransfer", context.Background(), "F1", mock.MatchedBy(func(t *core.TokenTransfer) bool {
+		return t.From == "0x12345" && t.To == "B" && t.Type == core.TokenTransferTypeTransfer
+	})).Return(&core.TokenTransferEligibility{Eligible: false, Reason: "recipient not verified"}, nil)
+
+	eligibility, err := am.CheckTransferEligibility(context.Background(), transfer)
+	assert.NoError(t, err)
+	assert.False(t, eligibility.Eligible)
+	assert.Equal(t, "recipient not verified", eligibility.Reason)
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mti.AssertExpectations(t)
+}
+
+func TestCheckTransferEligibilityBadPool(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	mdi := am.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := am.CheckTransferEligibility(context.Background(), &core.TokenTransferInput{Pool: "pool1"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckTransferEligibilityBadPlugin(t *testing.T) {
+	am, cancel := newTestAssets(t)
+	defer cancel()
+
+	pool := &core.TokenPool{
+		Connector: "bad",
+		State:     core.TokenPoolStateConfirmed,
+	}
+	mdi := am.database.(*databasemocks.Plugin)
+	mim := am.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x12345", nil)
+	mdi.On("GetTokenPool", context.Background(), "ns1", "pool1").Return(pool, nil)
+	mdi.On("GetTokenTransferPolicy", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := am.CheckTransferEligibility(context.Background(), &core.TokenTransferInput{Pool: "pool1"})
+	assert.Regexp(t, "FF10272", err)
+}
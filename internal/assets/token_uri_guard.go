@@ -0,0 +1,133 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// cgnatRange is the shared address space used by carrier-grade NAT (RFC 6598), which net.IP does not class as private
+var cgnatRange = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// uriGuard stops token URIs from being used to make requests into the network FireFly runs in.
+// Token URIs are set by whoever minted the token, so without this a URI such as
+// http://169.254.169.254/ could be used to read a cloud metadata service, or call an internal API.
+//
+// Every connection is checked when it is dialed, after DNS resolution, so neither a redirect nor a
+// DNS record that changes between checks can reach a non-public address. If an allowlist of hosts is
+// configured, only those hosts are fetched from, and they may resolve to any address - for example
+// an IPFS gateway on the local network.
+type uriGuard struct {
+	allowedHosts map[string]bool
+	maxRedirects int
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+	isPublicIP   func(ip net.IP) bool
+	dialer       *net.Dialer
+}
+
+func newURIGuard(allowedHosts []string, maxRedirects int) *uriGuard {
+	g := &uriGuard{
+		maxRedirects: maxRedirects,
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+		isPublicIP:   isPublicIP,
+		dialer:       &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+	}
+	if len(allowedHosts) > 0 {
+		g.allowedHosts = make(map[string]bool, len(allowedHosts))
+		for _, host := range allowedHosts {
+			g.allowedHosts[strings.ToLower(host)] = true
+		}
+	}
+	return g
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified() ||
+		cgnatRange.Contains(ip))
+}
+
+// checkHost rejects hosts outside of the allowlist, when one is configured
+func (g *uriGuard) checkHost(ctx context.Context, host string) error {
+	if g.allowedHosts != nil && !g.allowedHosts[strings.ToLower(host)] {
+		return i18n.NewError(ctx, coremsgs.MsgTokenURIHostNotAllowed, host)
+	}
+	return nil
+}
+
+func (g *uriGuard) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.checkHost(ctx, host); err != nil {
+		return nil, err
+	}
+	ips, err := g.lookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if g.allowedHosts == nil {
+		for _, ip := range ips {
+			if !g.isPublicIP(ip.IP) {
+				return nil, i18n.NewError(ctx, coremsgs.MsgTokenURIAddressBlocked, host, ip.IP)
+			}
+		}
+	}
+	// Dial the address we checked, rather than resolving the host again
+	var conn net.Conn
+	for _, ip := range ips {
+		if conn, err = g.dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port)); err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// checkRedirect limits the number of redirects, and applies the allowlist to each redirect target.
+// The address of each target is checked when it is dialed.
+func (g *uriGuard) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > g.maxRedirects {
+		return i18n.NewError(req.Context(), coremsgs.MsgTokenURITooManyRedirects, via[0].URL, g.maxRedirects)
+	}
+	return g.checkHost(req.Context(), req.URL.Hostname())
+}
+
+// transport is used for all requests to token URIs, so every connection goes through the guard.
+// No proxy is used, as the guard needs to see the address of the target.
+func (g *uriGuard) transport() *http.Transport {
+	return &http.Transport{
+		DialContext:           g.dialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
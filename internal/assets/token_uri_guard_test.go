@@ -0,0 +1,177 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assets
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGuardedIndexer(t *testing.T, allowedHosts ...string) *metadataIndexer {
+	coreconfig.Reset()
+	if len(allowedHosts) > 0 {
+		config.Set(coreconfig.AssetMetadataAllowedHosts, allowedHosts)
+	}
+	return newMetadataIndexer(context.Background(), "ns1", nil)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for ip, public := range map[string]bool{
+		"8.8.8.8":         true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"::1":             false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"fe80::1":         false,
+		"fd00::1":         false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"224.0.0.1":       false,
+		"::ffff:10.0.0.1": false,
+	} {
+		assert.Equal(t, public, isPublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestDownloadLoopbackBlocked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name":"internal"}`))
+	}))
+	defer server.Close()
+
+	mi := newTestGuardedIndexer(t)
+	_, _, err := mi.download(context.Background(), server.URL, 1024)
+	assert.Regexp(t, "FF10540.*127.0.0.1", err)
+}
+
+func TestDialCloudMetadataBlocked(t *testing.T) {
+	g := newURIGuard(nil, 3)
+	_, err := g.dialContext(context.Background(), "tcp", "169.254.169.254:80")
+	assert.Regexp(t, "FF10540.*169.254.169.254", err)
+}
+
+func TestDialBadAddress(t *testing.T) {
+	g := newURIGuard(nil, 3)
+	_, err := g.dialContext(context.Background(), "tcp", "no-port")
+	assert.Error(t, err)
+}
+
+func TestDialLookupFail(t *testing.T) {
+	g := newURIGuard(nil, 3)
+	g.lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return nil, fmt.Errorf("pop")
+	}
+	_, err := g.dialContext(context.Background(), "tcp", "example.com:80")
+	assert.Regexp(t, "pop", err)
+}
+
+func TestDialConnectFail(t *testing.T) {
+	g := newURIGuard([]string{"localhost"}, 3)
+	g.lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+
+	_, err = g.dialContext(context.Background(), "tcp", "localhost:"+port)
+	assert.Error(t, err)
+}
+
+func TestDownloadAllowedHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"name":"gateway"}`))
+	}))
+	defer server.Close()
+
+	mi := newTestGuardedIndexer(t, "127.0.0.1")
+	content, _, err := mi.download(context.Background(), server.URL, 1024)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"name":"gateway"}`, string(content))
+}
+
+func TestDialHostNotAllowed(t *testing.T) {
+	g := newURIGuard([]string{"ipfs.example.com"}, 3)
+	_, err := g.dialContext(context.Background(), "tcp", "example.com:443")
+	assert.Regexp(t, "FF10541.*example.com", err)
+}
+
+func TestDownloadRedirectToPrivateAddressBlocked(t *testing.T) {
+	var serverURL *url.URL
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == serverURL.Host {
+			http.Redirect(w, r, fmt.Sprintf("http://internal.test:%s/secret", serverURL.Port()), http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"secret"}`))
+	}))
+	defer server.Close()
+	serverURL, _ = url.Parse(server.URL)
+
+	// Treat the test server as a public address, and point the redirect target at a private one
+	mi := newTestGuardedIndexer(t)
+	g := newURIGuard(nil, 3)
+	g.isPublicIP = func(ip net.IP) bool { return ip.Equal(net.ParseIP("127.0.0.1")) }
+	g.lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		if host == "internal.test" {
+			return []net.IPAddr{{IP: net.ParseIP("127.0.0.2")}}, nil
+		}
+		return net.DefaultResolver.LookupIPAddr(ctx, host)
+	}
+	mi.client.SetTransport(g.transport())
+
+	_, _, err := mi.download(context.Background(), server.URL, 1024)
+	assert.Regexp(t, "FF10540.*internal.test", err)
+}
+
+func TestDownloadRedirectToHostNotAllowed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer server.Close()
+
+	mi := newTestGuardedIndexer(t, "127.0.0.1")
+	_, _, err := mi.download(context.Background(), server.URL, 1024)
+	assert.Regexp(t, "FF10541.*169.254.169.254", err)
+}
+
+func TestDownloadTooManyRedirects(t *testing.T) {
+	redirects := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirects++
+		http.Redirect(w, r, fmt.Sprintf("/loop/%d", redirects), http.StatusFound)
+	}))
+	defer server.Close()
+
+	mi := newTestGuardedIndexer(t, "127.0.0.1")
+	_, _, err := mi.download(context.Background(), server.URL, 1024)
+	assert.Regexp(t, "FF10542", err)
+	assert.Equal(t, 4, redirects)
+}
@@ -0,0 +1,88 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Logger records the mutating calls made to the API servers in the audit log of their namespace,
+// and appends them to the configured export file
+type Logger struct {
+	namespaceManager namespace.Manager
+	exportMux        sync.Mutex
+	exportFile       *os.File
+}
+
+func NewLogger(ctx context.Context, nm namespace.Manager) (*Logger, error) {
+	l := &Logger{
+		namespaceManager: nm,
+	}
+	if filename := config.GetString(coreconfig.AuditExportFile); filename != "" {
+		f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgAuditExportFileFailed, filename, err)
+		}
+		l.exportFile = f
+	}
+	return l, nil
+}
+
+// Record stores an entry in the audit log of its namespace. Failures are logged rather than returned,
+// as the call being audited has already completed.
+func (l *Logger) Record(ctx context.Context, entry *core.AuditLogEntry) {
+	if entry.Namespace == "" {
+		entry.Namespace = config.GetString(coreconfig.NamespacesDefault)
+	}
+	entry.ID = fftypes.NewUUID()
+	entry.Created = fftypes.Now()
+
+	or, err := l.namespaceManager.Orchestrator(ctx, entry.Namespace, false)
+	if err == nil {
+		err = or.RecordAuditLogEntry(ctx, entry)
+	}
+	if err != nil {
+		log.L(ctx).Errorf("Failed to record audit log entry %s for %s %s in namespace '%s': %s", entry.ID, entry.Method, entry.Route, entry.Namespace, err)
+	}
+
+	if l.exportFile != nil {
+		b, _ := json.Marshal(entry)
+		l.exportMux.Lock()
+		defer l.exportMux.Unlock()
+		if _, err := l.exportFile.Write(append(b, '\n')); err != nil {
+			log.L(ctx).Errorf("Failed to export audit log entry %s: %s", entry.ID, err)
+		}
+	}
+}
+
+func (l *Logger) Close() {
+	if l.exportFile != nil {
+		_ = l.exportFile.Close()
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auditlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestLogger(t *testing.T) (*Logger, *namespacemocks.Manager, *orchestratormocks.Orchestrator) {
+	coreconfig.Reset()
+	mnm := &namespacemocks.Manager{}
+	mor := &orchestratormocks.Orchestrator{}
+	l, err := NewLogger(context.Background(), mnm)
+	assert.NoError(t, err)
+	return l, mnm, mor
+}
+
+func TestRecord(t *testing.T) {
+	l, mnm, mor := newTestLogger(t)
+	defer l.Close()
+
+	mnm.On("Orchestrator", mock.Anything, "ns1", false).Return(mor, nil)
+	mor.On("RecordAuditLogEntry", mock.Anything, mock.MatchedBy(func(entry *core.AuditLogEntry) bool {
+		return entry.ID != nil && entry.Created != nil && entry.Principal == "alice"
+	})).Return(nil)
+
+	l.Record(context.Background(), &core.AuditLogEntry{Namespace: "ns1", Principal: "alice"})
+
+	mnm.AssertExpectations(t)
+	mor.AssertExpectations(t)
+}
+
+func TestRecordDefaultNamespace(t *testing.T) {
+	l, mnm, _ := newTestLogger(t)
+	defer l.Close()
+
+	mnm.On("Orchestrator", mock.Anything, "default", false).Return(nil, fmt.Errorf("pop"))
+
+	l.Record(context.Background(), &core.AuditLogEntry{})
+
+	mnm.AssertExpectations(t)
+}
+
+func TestRecordExportFile(t *testing.T) {
+	coreconfig.Reset()
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	config.Set(coreconfig.AuditExportFile, filename)
+	mnm := &namespacemocks.Manager{}
+	mor := &orchestratormocks.Orchestrator{}
+	l, err := NewLogger(context.Background(), mnm)
+	assert.NoError(t, err)
+
+	mnm.On("Orchestrator", mock.Anything, "ns1", false).Return(mor, nil)
+	mor.On("RecordAuditLogEntry", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	l.Record(context.Background(), &core.AuditLogEntry{Namespace: "ns1", Route: "messages/broadcast"})
+	l.Record(context.Background(), &core.AuditLogEntry{Namespace: "ns1", Route: "contracts/invoke"})
+	l.Close()
+
+	b, err := os.ReadFile(filename)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Len(t, lines, 2)
+	var entry core.AuditLogEntry
+	err = json.Unmarshal([]byte(lines[1]), &entry)
+	assert.NoError(t, err)
+	assert.Equal(t, "contracts/invoke", entry.Route)
+	assert.NotNil(t, entry.ID)
+
+	// Writes to a closed file are logged
+	l.Record(context.Background(), &core.AuditLogEntry{Namespace: "ns1"})
+}
+
+func TestNewLoggerBadExportFile(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.AuditExportFile, filepath.Join(t.TempDir(), "missing", "audit.log"))
+	_, err := NewLogger(context.Background(), &namespacemocks.Manager{})
+	assert.Regexp(t, "FF10604", err)
+}
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
@@ -62,8 +63,12 @@ func NewBatchManager(ctx context.Context, ns string, di database.Plugin, dm data
 		newMessages:                make(chan int64, readPageSize),
 		inflightSequences:          make(map[int64]*batchProcessor),
 		shoulderTap:                make(chan bool, 1),
+		schedulerTap:               make(chan bool, 1),
 		rewindOffset:               -1,
 		done:                       make(chan struct{}),
+		schedulerDone:              make(chan struct{}),
+		policies:                   make(map[string]*core.BatchPolicy),
+		queue:                      newBatchQueue(ns),
 		retry: &retry.Retry{
 			InitialDelay: config.GetDuration(coreconfig.BatchRetryInitDelay),
 			MaximumDelay: config.GetDuration(coreconfig.BatchRetryMaxDelay),
@@ -81,6 +86,18 @@ type Manager interface {
 	Close()
 	WaitStop()
 	Status() *ManagerStatus
+	GetScheduledMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
+	CancelScheduledMessage(ctx context.Context, id string) (*core.Message, error)
+	GetDraftMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
+	CommitDraftMessage(ctx context.Context, id string) (*core.Message, error)
+	DiscardDraftMessage(ctx context.Context, id string) (*core.Message, error)
+	SetBatchPolicy(ctx context.Context, topic string, policy *core.BatchPolicy) (*core.BatchPolicy, error)
+	GetBatchPolicies(ctx context.Context) ([]*core.BatchPolicy, error)
+	GetBatchPolicy(ctx context.Context, topic string) (*core.BatchPolicy, error)
+	DeleteBatchPolicy(ctx context.Context, topic string) error
+	FlushBatches(ctx context.Context, req *FlushRequest) (*FlushResult, error)
+	GetQueueStatus() *QueueStatus
+	CheckBackpressure(ctx context.Context, topics []string) error
 }
 
 type ManagerStatus struct {
@@ -114,6 +131,12 @@ type batchManager struct {
 	inflightSequences          map[int64]*batchProcessor
 	inflightFlushed            []int64
 	shoulderTap                chan bool
+	schedulerMux               sync.Mutex
+	schedulerTap               chan bool
+	schedulerDone              chan struct{}
+	policyMux                  sync.RWMutex
+	policies                   map[string]*core.BatchPolicy
+	queue                      *batchQueue
 	readPageSize               uint64
 	minimumPollDelay           time.Duration
 	messagePollTimeout         time.Duration
@@ -123,11 +146,12 @@ type batchManager struct {
 type DispatchHandler func(context.Context, *DispatchPayload) error
 
 type DispatcherOptions struct {
-	BatchType      core.BatchType
-	BatchMaxSize   uint
-	BatchMaxBytes  int64
-	BatchTimeout   time.Duration
-	DisposeTimeout time.Duration
+	BatchType        core.BatchType
+	BatchMaxSize     uint
+	BatchMaxBytes    int64
+	BatchTimeout     time.Duration
+	DisposeTimeout   time.Duration
+	DisclosureProofs bool // generate a disclosure salt for each pinned batch, so the fields of its data can be selectively disclosed
 }
 
 type dispatcher struct {
@@ -162,9 +186,13 @@ func (bm *batchManager) RegisterDispatcher(name string, txType core.TransactionT
 }
 
 func (bm *batchManager) Start() error {
+	if err := bm.loadBatchPolicies(); err != nil {
+		return err
+	}
 	go bm.messageSequencer()
 	// We must be always ready to process DB events, or we block commits. So we have a dedicated worker for that
 	go bm.newMessageNotifier()
+	go bm.messageScheduler()
 	return nil
 }
 
@@ -217,6 +245,10 @@ func (bm *batchManager) assembleMessageData(id *fftypes.UUID) (msg *core.Message
 	if !foundAll {
 		return nil, nil, i18n.NewError(bm.ctx, coremsgs.MsgDataNotFound, id)
 	}
+	if len(msg.Chunks) > 0 {
+		// The data of a chunked message is sent in its chunk messages, rather than in the batch
+		return msg, nil, nil
+	}
 	return msg, retData, nil
 }
 
@@ -260,6 +292,7 @@ func (bm *batchManager) notifyFlushed(sequences []int64) {
 	bm.inflightMux.Lock()
 	bm.inflightFlushed = append(bm.inflightFlushed, sequences...)
 	bm.inflightMux.Unlock()
+	bm.queue.remove(sequences)
 }
 
 func (bm *batchManager) readPage(lastPageFull bool) ([]*core.IDAndSequence, bool, error) {
@@ -298,8 +331,9 @@ func (bm *batchManager) messageSequencer() {
 
 	lastPageFull := false
 	for {
-		// Each time round the loop we check for quiescing processors
+		// Each time round the loop we check for quiescing processors, and update the age of the queued messages
 		bm.reapQuiescing()
+		bm.queue.updateAgeMetrics(time.Now())
 
 		// Read messages from the DB - in an error condition we retry until success, or a closed context
 		entries, fullPage, err := bm.readPage(lastPageFull)
@@ -309,6 +343,7 @@ func (bm *batchManager) messageSequencer() {
 		}
 
 		if len(entries) > 0 {
+			work := make([]*batchWork, 0, len(entries))
 			for _, entry := range entries {
 				msg, data, err := bm.assembleMessageData(&entry.ID)
 				if err != nil {
@@ -319,14 +354,18 @@ func (bm *batchManager) messageSequencer() {
 				// We likely retrieved this message from the cache, which is written by the message-writer before
 				// the database store. Meaning we cannot rely on the sequence having been set.
 				msg.Sequence = entry.Sequence
+				work = append(work, &batchWork{msg: msg, data: data})
+			}
 
-				processor, err := bm.getProcessor(msg.Header.TxType, msg.Header.Type, msg.Header.Group, msg.Header.SignerRef.Author)
+			// High priority messages in the page are dispatched ahead of the others on different topics
+			for _, w := range prioritizeWork(work) {
+				processor, err := bm.getProcessor(w.msg.Header.TxType, w.msg.Header.Type, w.msg.Header.Group, w.msg.Header.SignerRef.Author)
 				if err != nil {
-					l.Errorf("Failed to dispatch message %s: %s", msg.Header.ID, err)
+					l.Errorf("Failed to dispatch message %s: %s", w.msg.Header.ID, err)
 					continue
 				}
 
-				bm.dispatchMessage(processor, msg, data)
+				bm.dispatchMessage(processor, w.msg, w.data)
 			}
 
 			// Next time round only read after the messages we just processed (unless we get a tap to rewind)
@@ -347,6 +386,12 @@ func (bm *batchManager) messageSequencer() {
 func (bm *batchManager) newMessageNotification(seq int64) {
 	rewindToQueue := int64(-1)
 
+	// The new message might be scheduled earlier than the scheduler is waiting for
+	select {
+	case bm.schedulerTap <- true:
+	default:
+	}
+
 	// Determine if we need to queue a rewind
 	bm.rewindOffsetMux.Lock()
 	lastSequenceBeforeMsg := seq - 1
@@ -405,11 +450,13 @@ func (bm *batchManager) dispatchMessage(processor *batchProcessor, msg *core.Mes
 	bm.inflightMux.Lock()
 	bm.inflightSequences[msg.Sequence] = processor
 	bm.inflightMux.Unlock()
+	bm.queue.add(msg, time.Now())
 
 	work := &batchWork{
 		msg:  msg,
 		data: data,
 	}
+	work.maxSize, work.maxLatency = bm.batchPolicy(msg)
 	processor.newWork <- work
 }
 
@@ -469,6 +516,7 @@ func (bm *batchManager) Close() {
 
 func (bm *batchManager) WaitStop() {
 	<-bm.done
+	<-bm.schedulerDone
 	processors := bm.getProcessors()
 	for _, p := range processors {
 		<-p.done
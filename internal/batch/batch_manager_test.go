@@ -150,6 +150,9 @@ func TestE2EDispatchBroadcast(t *testing.T) {
 	mdi.On("InsertTransaction", mock.Anything, mock.Anything).Return(nil)
 	mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil) // transaction submit
 
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdi.On("GetBatchPolicies", mock.Anything, "ns1").Return([]*core.BatchPolicy{}, nil)
+
 	err := bm.Start()
 	assert.NoError(t, err)
 
@@ -276,6 +279,9 @@ func TestE2EDispatchPrivateUnpinned(t *testing.T) {
 	mdi.On("InsertTransaction", mock.Anything, mock.Anything).Return(nil)
 	mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil) // transaction submit
 
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdi.On("GetBatchPolicies", mock.Anything, "ns1").Return([]*core.BatchPolicy{}, nil)
+
 	err := bm.Start()
 	assert.NoError(t, err)
 
@@ -315,6 +321,9 @@ func TestDispatchUnknownType(t *testing.T) {
 	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{{ID: *msg.Header.ID}}, nil).Once()
 	mdm.On("GetMessageWithDataCached", mock.Anything, mock.Anything).Return(msg, core.DataArray{}, true, nil)
 
+	mdi.On("GetMessages", mock.Anything, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+	mdi.On("GetBatchPolicies", mock.Anything, "ns1").Return([]*core.BatchPolicy{}, nil)
+
 	err := bm.Start()
 	assert.NoError(t, err)
 
@@ -399,6 +408,7 @@ func TestMessageSequencerMissingMessageData(t *testing.T) {
 	mdm.On("GetMessageWithDataCached", mock.Anything, mock.Anything).Return(msg, core.DataArray{}, false, nil)
 
 	bm.(*batchManager).messageSequencer()
+	close(bm.(*batchManager).schedulerDone) // scheduler not started
 
 	bm.WaitStop()
 
@@ -406,6 +416,114 @@ func TestMessageSequencerMissingMessageData(t *testing.T) {
 	mdm.AssertExpectations(t)
 }
 
+func TestMessageSequencerHighPriorityFirst(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	processor := &batchProcessor{
+		conf:    &batchProcessorConf{name: "utprocessor"},
+		newWork: make(chan *batchWork, 3),
+	}
+	bm.dispatcherMap[bm.getDispatcherKey(core.TransactionTypeBatchPin, core.MessageTypeBroadcast)] = &dispatcher{
+		processors: map[string]*batchProcessor{
+			bm.getProcessorKey("org1", nil): processor,
+		},
+	}
+	newMsg := func(priority core.MessagePriority) *core.Message {
+		return &core.Message{
+			Header: core.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				TxType:    core.TransactionTypeBatchPin,
+				Type:      core.MessageTypeBroadcast,
+				SignerRef: core.SignerRef{Author: "org1"},
+			},
+			Priority: priority,
+		}
+	}
+	msg1 := newMsg("")
+	msg2 := newMsg(core.MessagePriorityHigh)
+	msg3 := newMsg(core.MessagePriorityNormal)
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{
+		{ID: *msg1.Header.ID, Sequence: 1},
+		{ID: *msg2.Header.ID, Sequence: 2},
+		{ID: *msg3.Header.ID, Sequence: 3},
+	}, nil).Run(func(args mock.Arguments) {
+		cancel()
+	}).Once()
+	mdm := bm.data.(*datamocks.Manager)
+	for _, msg := range []*core.Message{msg1, msg2, msg3} {
+		mdm.On("GetMessageWithDataCached", mock.Anything, msg.Header.ID).Return(msg, core.DataArray{}, true, nil)
+	}
+
+	bm.messageSequencer()
+
+	assert.Equal(t, msg2, (<-processor.newWork).msg)
+	assert.Equal(t, msg1, (<-processor.newWork).msg)
+	assert.Equal(t, msg3, (<-processor.newWork).msg)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestMessageSequencerHighPriorityKeepsTopicOrder(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	processor := &batchProcessor{
+		conf:    &batchProcessorConf{name: "utprocessor"},
+		newWork: make(chan *batchWork, 4),
+	}
+	bm.dispatcherMap[bm.getDispatcherKey(core.TransactionTypeBatchPin, core.MessageTypeBroadcast)] = &dispatcher{
+		processors: map[string]*batchProcessor{
+			bm.getProcessorKey("org1", nil): processor,
+		},
+	}
+	newMsg := func(topic string, priority core.MessagePriority) *core.Message {
+		return &core.Message{
+			Header: core.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				TxType:    core.TransactionTypeBatchPin,
+				Type:      core.MessageTypeBroadcast,
+				SignerRef: core.SignerRef{Author: "org1"},
+				Topics:    fftypes.FFStringArray{topic},
+			},
+			Priority: priority,
+		}
+	}
+	msg1 := newMsg("topic1", core.MessagePriorityNormal)
+	msg2 := newMsg("topic2", core.MessagePriorityNormal)
+	msg3 := newMsg("topic1", core.MessagePriorityHigh)
+	msg4 := newMsg("topic3", core.MessagePriorityHigh)
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageIDs", mock.Anything, "ns1", mock.Anything).Return([]*core.IDAndSequence{
+		{ID: *msg1.Header.ID, Sequence: 1},
+		{ID: *msg2.Header.ID, Sequence: 2},
+		{ID: *msg3.Header.ID, Sequence: 3},
+		{ID: *msg4.Header.ID, Sequence: 4},
+	}, nil).Run(func(args mock.Arguments) {
+		cancel()
+	}).Once()
+	mdm := bm.data.(*datamocks.Manager)
+	for _, msg := range []*core.Message{msg1, msg2, msg3, msg4} {
+		mdm.On("GetMessageWithDataCached", mock.Anything, msg.Header.ID).Return(msg, core.DataArray{}, true, nil)
+	}
+
+	bm.messageSequencer()
+
+	// The high priority message on topic3 overtakes the others, but the high priority message on
+	// topic1 stays behind the earlier message on topic1
+	assert.Equal(t, msg4, (<-processor.newWork).msg)
+	assert.Equal(t, msg1, (<-processor.newWork).msg)
+	assert.Equal(t, msg2, (<-processor.newWork).msg)
+	assert.Equal(t, msg3, (<-processor.newWork).msg)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
 func TestMessageSequencerUpdateMessagesFail(t *testing.T) {
 	mdi := &databasemocks.Plugin{}
 	mdm := &datamocks.Manager{}
@@ -456,6 +574,7 @@ func TestMessageSequencerUpdateMessagesFail(t *testing.T) {
 	}
 
 	bm.(*batchManager).messageSequencer()
+	close(bm.(*batchManager).schedulerDone) // scheduler not started
 
 	bm.Close()
 	bm.WaitStop()
@@ -499,6 +618,7 @@ func TestMessageSequencerDispatchFail(t *testing.T) {
 	mdi.On("RunAsGroup", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	bm.(*batchManager).messageSequencer()
+	close(bm.(*batchManager).schedulerDone) // scheduler not started
 
 	bm.Close()
 	bm.WaitStop()
@@ -554,6 +674,7 @@ func TestMessageSequencerUpdateBatchFail(t *testing.T) {
 	mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil).Maybe()
 
 	bm.(*batchManager).messageSequencer()
+	close(bm.(*batchManager).schedulerDone) // scheduler not started
 
 	bm.Close()
 	bm.WaitStop()
@@ -640,6 +761,28 @@ func TestGetMessageNotFound(t *testing.T) {
 	assert.Regexp(t, "FF10133", err)
 }
 
+func TestAssembleMessageDataChunked(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mdm := &datamocks.Manager{}
+	mim := &identitymanagermocks.Manager{}
+	ctx := context.Background()
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
+	bm, _ := NewBatchManager(context.Background(), "ns1", mdi, mdm, mim, txHelper)
+	bm.Close()
+	msg := &core.Message{
+		Header: core.MessageHeader{ID: fftypes.NewUUID()},
+		Chunks: fftypes.FFStringArray{fftypes.NewUUID().String()},
+	}
+	mdm.On("GetMessageWithDataCached", mock.Anything, mock.Anything).Return(msg, core.DataArray{{ID: fftypes.NewUUID()}}, true, nil)
+	msgOut, data, err := bm.(*batchManager).assembleMessageData(msg.Header.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, msg, msgOut)
+	assert.Nil(t, data)
+	mdm.AssertExpectations(t)
+}
+
 func TestDoubleTap(t *testing.T) {
 	bm, cancel := newTestBatchManager(t)
 	defer cancel()
@@ -20,6 +20,7 @@ import (
 	"context"
 	"database/sql/driver"
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -34,8 +35,10 @@ import (
 )
 
 type batchWork struct {
-	msg  *core.Message
-	data core.DataArray
+	msg        *core.Message
+	data       core.DataArray
+	maxSize    uint
+	maxLatency time.Duration
 }
 
 type batchProcessorConf struct {
@@ -82,6 +85,10 @@ type batchProcessor struct {
 	assemblyID         *fftypes.UUID
 	assemblyQueue      []*batchWork
 	assemblyQueueBytes int64
+	assemblyPolicySize uint
+	flushMux           sync.Mutex
+	flushTopics        map[string]bool
+	flushTap           chan bool
 	statusMux          sync.Mutex
 	flushStatus        FlushStatus
 	retry              *retry.Retry
@@ -120,6 +127,7 @@ func newBatchProcessor(bm *batchManager, conf *batchProcessorConf, baseRetryConf
 		newWork:   make(chan *batchWork, conf.BatchMaxSize),
 		quiescing: make(chan bool, 1),
 		done:      make(chan struct{}),
+		flushTap:  make(chan bool, 1),
 		retry: &retry.Retry{
 			InitialDelay: baseRetryConf.InitialDelay,
 			MaximumDelay: baseRetryConf.MaximumDelay,
@@ -138,6 +146,49 @@ func newBatchProcessor(bm *batchManager, conf *batchProcessorConf, baseRetryConf
 	return bp
 }
 
+func (bw *batchWork) highPriority() bool {
+	return bw.msg.Priority == core.MessagePriorityHigh
+}
+
+// sharesTopic returns true if the work is on any of the topics
+func (bw *batchWork) sharesTopic(topics map[string]bool) bool {
+	for _, topic := range bw.msg.Header.Topics {
+		if topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+// prioritizeWork moves high priority work ahead of the normal priority work, in a list of work that is
+// in sequence order. High priority work only overtakes work that shares none of its topics, so the
+// messages on each topic are still assembled in the order they were sent.
+func prioritizeWork(work []*batchWork) []*batchWork {
+	ahead := make([]*batchWork, 0, len(work))
+	behind := make([]*batchWork, 0, len(work))
+	behindTopics := make(map[string]bool)
+	for _, w := range work {
+		if w.highPriority() && !w.sharesTopic(behindTopics) {
+			ahead = append(ahead, w)
+			continue
+		}
+		behind = append(behind, w)
+		for _, topic := range w.msg.Header.Topics {
+			behindTopics[topic] = true
+		}
+	}
+	return append(ahead, behind...)
+}
+
+// timeout returns the time to wait for a batch containing this work to fill, which is the
+// batch timeout of the dispatcher unless a batch policy for its topics sets a lower latency
+func (bw *batchWork) timeout(batchTimeout time.Duration) time.Duration {
+	if bw.maxLatency > 0 && bw.maxLatency < batchTimeout {
+		return bw.maxLatency
+	}
+	return batchTimeout
+}
+
 func (bw *batchWork) estimateSize() int64 {
 	sizeEstimate := bw.msg.EstimateSize(false /* we calculate data size separately, as we have the full data objects */)
 	for _, d := range bw.data {
@@ -160,17 +211,71 @@ func (bp *batchProcessor) newAssembly(initialWork ...*batchWork) {
 	bp.assemblyID = fftypes.NewUUID()
 	bp.assemblyQueue = append([]*batchWork{}, initialWork...)
 	bp.assemblyQueueBytes = batchSizeEstimateBase
+	bp.assemblyPolicySize = 0
+	for _, work := range initialWork {
+		bp.applyPolicy(work)
+	}
+}
+
+// applyPolicy lowers the maximum size of the batch being assembled to that of the batch policy of
+// the work, if it has one
+func (bp *batchProcessor) applyPolicy(work *batchWork) {
+	if work.maxSize > 0 && (bp.assemblyPolicySize == 0 || work.maxSize < bp.assemblyPolicySize) {
+		bp.assemblyPolicySize = work.maxSize
+	}
+}
+
+func (bp *batchProcessor) assemblyMaxSize() uint {
+	if bp.assemblyPolicySize > 0 && bp.assemblyPolicySize < bp.conf.BatchMaxSize {
+		return bp.assemblyPolicySize
+	}
+	return bp.conf.BatchMaxSize
+}
+
+// requestFlush asks the assembly loop to flush the current batch, if it contains a message on the
+// topic, or regardless of its contents for an empty topic
+func (bp *batchProcessor) requestFlush(topic string) {
+	bp.flushMux.Lock()
+	if bp.flushTopics == nil {
+		bp.flushTopics = make(map[string]bool)
+	}
+	bp.flushTopics[topic] = true
+	bp.flushMux.Unlock()
+	select {
+	case bp.flushTap <- true:
+	default:
+	}
+}
+
+// flushRequested pops the pending flush requests, and returns true if any of them match the current batch
+func (bp *batchProcessor) flushRequested() bool {
+	bp.flushMux.Lock()
+	topics := bp.flushTopics
+	bp.flushTopics = nil
+	bp.flushMux.Unlock()
+
+	if len(bp.assemblyQueue) == 0 {
+		return false
+	}
+	if topics[""] {
+		return true
+	}
+	for _, work := range bp.assemblyQueue {
+		if work.sharesTopic(topics) {
+			return true
+		}
+	}
+	return false
 }
 
 // addWork adds the work to the assemblyQueue, and calculates if we have overflowed with this work.
-// We check for duplicates, and add the work in sequence order.
+// We check for duplicates, and add the work in sequence order, then move high priority work ahead
+// of any normal priority work that is not on the same topics.
+// High priority work flushes the batch, rather than waiting for it to fill or time out.
 // This helps in the case for parallel REST APIs all committing to the DB at a similar time.
 // With a sufficient batch size and batch timeout, the batch will still dispatch the messages
 // in DB sequence order (although this is not guaranteed).
 func (bp *batchProcessor) addWork(newWork *batchWork) (full, overflow bool) {
-	newQueue := make([]*batchWork, 0, len(bp.assemblyQueue)+1)
-	added := false
-
 	if newWork.msg.BatchID != nil {
 		log.L(bp.ctx).Warnf("Adding message to a new batch when one was already assigned. Old batch %s is likely abandoned.", newWork.msg.BatchID)
 	}
@@ -190,21 +295,17 @@ func (bp *batchProcessor) addWork(newWork *batchWork) (full, overflow bool) {
 	if full {
 		bp.assemblyQueue = append(bp.assemblyQueue, newWork)
 	} else {
-		for _, work := range bp.assemblyQueue {
-			if !added && newWork.msg.Sequence < work.msg.Sequence {
-				newQueue = append(newQueue, newWork)
-				added = true
-			}
-			newQueue = append(newQueue, work)
-		}
-		if !added {
-			newQueue = append(newQueue, newWork)
-		}
+		newQueue := append(make([]*batchWork, 0, len(bp.assemblyQueue)+1), bp.assemblyQueue...)
+		newQueue = append(newQueue, newWork)
+		sort.SliceStable(newQueue, func(i, j int) bool {
+			return newQueue[i].msg.Sequence < newQueue[j].msg.Sequence
+		})
 
 		bp.assemblyQueueBytes += newWork.estimateSize()
-		bp.assemblyQueue = newQueue
+		bp.assemblyQueue = prioritizeWork(newQueue)
+		bp.applyPolicy(newWork)
 
-		full = len(bp.assemblyQueue) >= int(bp.conf.BatchMaxSize) || bp.assemblyQueueBytes >= bp.conf.BatchMaxBytes
+		full = len(bp.assemblyQueue) >= int(bp.assemblyMaxSize()) || bp.assemblyQueueBytes >= bp.conf.BatchMaxBytes || newWork.highPriority()
 		overflow = len(bp.assemblyQueue) > 1 && (batchOfOne || bp.assemblyQueueBytes > bp.conf.BatchMaxBytes)
 	}
 
@@ -296,6 +397,7 @@ func (bp *batchProcessor) assemblyLoop() {
 	l := log.L(bp.ctx)
 
 	var batchTimeout = time.NewTimer(bp.conf.DisposeTimeout)
+	var deadline time.Time
 	idle := true
 	quiescing := false
 	for !quiescing {
@@ -314,15 +416,23 @@ func (bp *batchProcessor) assemblyLoop() {
 				// We need to flush
 				timedout = true
 			}
+		case <-bp.flushTap:
+			if bp.flushRequested() {
+				l.Debugf("Batch flush requested")
+				timedout = true
+			}
 		case work, ok := <-bp.newWork:
 			if !ok {
 				quiescing = true
 			} else {
 				full, overflow = bp.addWork(work)
-				if idle {
-					// We've hit a message while we were idle - we now need to wait for the batch to time out.
+				timeout := work.timeout(bp.conf.BatchTimeout)
+				if idle || time.Now().Add(timeout).Before(deadline) {
+					// We've hit a message while we were idle, or one with a lower latency than the batch allows
+					// for so far - we now need to wait for the batch to time out.
 					_ = batchTimeout.Stop()
-					batchTimeout = time.NewTimer(bp.conf.BatchTimeout)
+					batchTimeout = time.NewTimer(timeout)
+					deadline = time.Now().Add(timeout)
 					idle = false
 				}
 			}
@@ -334,7 +444,9 @@ func (bp *batchProcessor) assemblyLoop() {
 			// If we are in overflow, start the clock for the next batch to start before we do the flush
 			// (even though we won't check it until after).
 			if overflow {
-				batchTimeout = time.NewTimer(bp.conf.BatchTimeout)
+				timeout := bp.assemblyQueue[len(bp.assemblyQueue)-1].timeout(bp.conf.BatchTimeout)
+				batchTimeout = time.NewTimer(timeout)
+				deadline = time.Now().Add(timeout)
 			}
 
 			err := bp.flush(overflow)
@@ -408,6 +520,9 @@ func (bp *batchProcessor) initPayload(id *fftypes.UUID, flushWork []*batchWork)
 			},
 		},
 	}
+	if bp.conf.DisclosureProofs && core.IsPinned(bp.conf.txType) {
+		payload.Batch.DisclosureSalt = fftypes.NewRandB32()
+	}
 	localNode, err := bp.bm.identity.GetLocalNode(bp.ctx)
 	if err == nil && localNode != nil {
 		payload.Batch.BatchHeader.Node = localNode.ID
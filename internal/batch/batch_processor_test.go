@@ -396,6 +396,105 @@ func TestAddWorkInSort(t *testing.T) {
 	}, bp.assemblyQueue)
 }
 
+func TestAddWorkHighPriority(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+	bp.assemblyQueue = []*batchWork{
+		{msg: &core.Message{Sequence: 200, Priority: core.MessagePriorityHigh}},
+		{msg: &core.Message{Sequence: 201}},
+		{msg: &core.Message{Sequence: 202}},
+	}
+	full, overflow := bp.addWork(&batchWork{
+		msg: &core.Message{Sequence: 203, Priority: core.MessagePriorityHigh},
+	})
+	assert.True(t, full)
+	assert.False(t, overflow)
+	assert.Equal(t, []*batchWork{
+		{msg: &core.Message{Sequence: 200, Priority: core.MessagePriorityHigh}},
+		{msg: &core.Message{Sequence: 203, Priority: core.MessagePriorityHigh}},
+		{msg: &core.Message{Sequence: 201}},
+		{msg: &core.Message{Sequence: 202}},
+	}, bp.assemblyQueue)
+
+	full, _ = bp.addWork(&batchWork{
+		msg: &core.Message{Sequence: 199},
+	})
+	assert.False(t, full)
+	assert.Equal(t, int64(199), bp.assemblyQueue[2].msg.Sequence)
+}
+
+func TestAddWorkHighPriorityKeepsTopicOrder(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+	newMsg := func(sequence int64, topic string, priority core.MessagePriority) *core.Message {
+		return &core.Message{
+			Header:   core.MessageHeader{Topics: fftypes.FFStringArray{topic}},
+			Sequence: sequence,
+			Priority: priority,
+		}
+	}
+	bp.assemblyQueue = []*batchWork{
+		{msg: newMsg(200, "topic1", core.MessagePriorityNormal)},
+		{msg: newMsg(201, "topic2", core.MessagePriorityNormal)},
+	}
+	full, _ := bp.addWork(&batchWork{
+		msg: newMsg(202, "topic2", core.MessagePriorityHigh),
+	})
+	assert.True(t, full)
+	assert.Equal(t, []*batchWork{
+		{msg: newMsg(200, "topic1", core.MessagePriorityNormal)},
+		{msg: newMsg(201, "topic2", core.MessagePriorityNormal)},
+		{msg: newMsg(202, "topic2", core.MessagePriorityHigh)},
+	}, bp.assemblyQueue)
+
+	// A late arriving message on a topic with high priority work ahead of it keeps its sequence order
+	bp.addWork(&batchWork{
+		msg: newMsg(203, "topic3", core.MessagePriorityHigh),
+	})
+	bp.addWork(&batchWork{
+		msg: newMsg(199, "topic3", core.MessagePriorityNormal),
+	})
+	assert.Equal(t, []*batchWork{
+		{msg: newMsg(199, "topic3", core.MessagePriorityNormal)},
+		{msg: newMsg(200, "topic1", core.MessagePriorityNormal)},
+		{msg: newMsg(201, "topic2", core.MessagePriorityNormal)},
+		{msg: newMsg(202, "topic2", core.MessagePriorityHigh)},
+		{msg: newMsg(203, "topic3", core.MessagePriorityHigh)},
+	}, bp.assemblyQueue)
+}
+
+func TestPrioritizeWork(t *testing.T) {
+	newWork := func(sequence int64, priority core.MessagePriority, topics ...string) *batchWork {
+		return &batchWork{msg: &core.Message{
+			Header:   core.MessageHeader{Topics: topics},
+			Sequence: sequence,
+			Priority: priority,
+		}}
+	}
+	sequences := func(work []*batchWork) []int64 {
+		s := make([]int64, len(work))
+		for i, w := range work {
+			s[i] = w.msg.Sequence
+		}
+		return s
+	}
+
+	work := prioritizeWork([]*batchWork{
+		newWork(1, core.MessagePriorityNormal, "a"),
+		newWork(2, core.MessagePriorityHigh, "b"),
+		newWork(3, core.MessagePriorityHigh, "a"),
+		newWork(4, core.MessagePriorityNormal, "c"),
+		newWork(5, core.MessagePriorityHigh, "c", "d"),
+		newWork(6, core.MessagePriorityHigh, "b", "e"),
+		newWork(7, core.MessagePriorityHigh, "a", "e"),
+	})
+	assert.Equal(t, []int64{2, 6, 1, 3, 4, 5, 7}, sequences(work))
+}
+
 func TestAddWorkBatchOfOne(t *testing.T) {
 	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
 		return nil
@@ -657,6 +756,41 @@ func TestMaskContextsUpdateMessageFail(t *testing.T) {
 	mim.AssertExpectations(t)
 }
 
+func TestInitPayloadDisclosureSalt(t *testing.T) {
+	coreconfig.Reset()
+
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	cancel()
+
+	mim := bp.bm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", mock.Anything).Return(&core.Identity{}, nil)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:   fftypes.NewUUID(),
+			Type: core.MessageTypePrivate,
+		},
+	}
+
+	state := bp.initPayload(fftypes.NewUUID(), []*batchWork{{msg: msg}})
+	assert.Nil(t, state.Batch.DisclosureSalt)
+
+	bp.conf.DisclosureProofs = true
+	state = bp.initPayload(fftypes.NewUUID(), []*batchWork{{msg: msg}})
+	assert.NotNil(t, state.Batch.DisclosureSalt)
+
+	bp.conf.txType = core.TransactionTypeUnpinned
+	state = bp.initPayload(fftypes.NewUUID(), []*batchWork{{msg: msg}})
+	assert.Nil(t, state.Batch.DisclosureSalt)
+
+	bp.cancelCtx()
+	<-bp.done
+
+	mim.AssertExpectations(t)
+}
+
 func TestSealBatchTXAlreadyAssigned(t *testing.T) {
 	coreconfig.Reset()
 
@@ -794,3 +928,137 @@ func TestBigBatchEstimate(t *testing.T) {
 
 	assert.Greater(t, sizeEstimate, int64(len(bd)))
 }
+
+func TestBatchPolicyMaxLatency(t *testing.T) {
+	log.SetLevel("debug")
+	coreconfig.Reset()
+
+	dispatched := make(chan *DispatchPayload)
+	cancel, mdi, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		dispatched <- state
+		return nil
+	})
+	defer cancel()
+	bp.conf.BatchTimeout = 100 * time.Second
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("UpdateMessages", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertOrGetBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	mth := bp.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeBatchPin, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+
+	mdm := bp.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", mock.Anything, mock.Anything).Return()
+
+	mim := bp.bm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", mock.Anything).Return(&core.Identity{}, nil)
+
+	// The first message waits for the batch timeout, but the second has a policy with a low latency
+	go func() {
+		bp.newWork <- &batchWork{
+			msg: &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topic1"}}, Sequence: 1000},
+		}
+		bp.newWork <- &batchWork{
+			msg:        &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topic2"}}, Sequence: 1001},
+			maxLatency: 10 * time.Millisecond,
+		}
+	}()
+
+	batch := <-dispatched
+	assert.Equal(t, 2, len(batch.Messages))
+
+	bp.cancelCtx()
+	<-bp.done
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestBatchPolicyMaxSize(t *testing.T) {
+	log.SetLevel("debug")
+	coreconfig.Reset()
+
+	dispatched := make(chan *DispatchPayload)
+	cancel, mdi, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		dispatched <- state
+		return nil
+	})
+	defer cancel()
+	bp.conf.BatchTimeout = 100 * time.Second
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("UpdateMessages", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertOrGetBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	mth := bp.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeBatchPin, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+
+	mdm := bp.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", mock.Anything, mock.Anything).Return()
+
+	mim := bp.bm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", mock.Anything).Return(&core.Identity{}, nil)
+
+	go func() {
+		for i := 0; i < 3; i++ {
+			bp.newWork <- &batchWork{
+				msg:     &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}, Sequence: int64(1000 + i)},
+				maxSize: 2,
+			}
+		}
+	}()
+
+	batch := <-dispatched
+	assert.Equal(t, 2, len(batch.Messages))
+
+	bp.cancelCtx()
+	<-bp.done
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
+
+func TestBatchPolicyAssemblyMaxSize(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+
+	bp.newAssembly(&batchWork{msg: &core.Message{}, maxSize: 3})
+	assert.Equal(t, uint(3), bp.assemblyMaxSize())
+
+	bp.applyPolicy(&batchWork{msg: &core.Message{}, maxSize: 20})
+	assert.Equal(t, uint(3), bp.assemblyMaxSize())
+
+	bp.newAssembly(&batchWork{msg: &core.Message{}, maxSize: 20})
+	assert.Equal(t, uint(10), bp.assemblyMaxSize())
+
+	bp.newAssembly()
+	assert.Equal(t, uint(10), bp.assemblyMaxSize())
+}
+
+func TestFlushRequested(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	cancel()
+	<-bp.done
+
+	// Nothing to flush
+	bp.requestFlush("")
+	assert.False(t, bp.flushRequested())
+
+	bp.assemblyQueue = []*batchWork{
+		{msg: &core.Message{Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic1", "topic2"}}}},
+	}
+
+	bp.requestFlush("topic3")
+	assert.False(t, bp.flushRequested())
+
+	bp.requestFlush("topic3")
+	bp.requestFlush("topic2")
+	assert.True(t, bp.flushRequested())
+	assert.False(t, bp.flushRequested())
+
+	bp.requestFlush("")
+	assert.True(t, bp.flushRequested())
+}
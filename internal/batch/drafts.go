@@ -0,0 +1,142 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// GetDraftMessages returns the messages that are waiting to be committed
+func (bm *batchManager) GetDraftMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	filter = filter.Condition(filter.Builder().Eq("state", core.MessageStateDraft))
+	return bm.database.GetMessages(ctx, bm.namespace, filter)
+}
+
+func (bm *batchManager) getDraftMessage(ctx context.Context, id string) (*core.Message, error) {
+	msgID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := bm.database.GetMessageByID(ctx, bm.namespace, msgID)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	if msg.State != core.MessageStateDraft {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotDraft, msg.Header.ID, msg.State)
+	}
+	return msg, nil
+}
+
+func (bm *batchManager) getDraftChunks(ctx context.Context, msg *core.Message) ([]*core.Message, error) {
+	if len(msg.Chunks) == 0 {
+		return nil, nil
+	}
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	chunks, _, err := bm.database.GetMessages(ctx, bm.namespace, fb.And(
+		fb.Eq("cid", msg.Header.ID),
+		fb.Eq("type", core.MessageTypeChunk),
+		fb.Eq("state", core.MessageStateDraft),
+	).Sort("sequence"))
+	return chunks, err
+}
+
+// CommitDraftMessage moves a draft message to the ready state, or to the scheduled state if it has a send time
+// in the future. The message is replaced, so that it is assigned a new sequence and picked up by the message
+// sequencer for batch assembly. The chunks of a large broadcast are committed with it, ahead of the message.
+func (bm *batchManager) CommitDraftMessage(ctx context.Context, id string) (*core.Message, error) {
+	// Hold the scheduler lock, so a draft cannot be committed and discarded at the same time
+	bm.schedulerMux.Lock()
+	defer bm.schedulerMux.Unlock()
+
+	msg, err := bm.getDraftMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	chunks, err := bm.getDraftChunks(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	state := core.MessageStateReady
+	if msg.SendTime != nil && time.Time(*msg.SendTime).After(time.Now()) {
+		state = core.MessageStateScheduled
+	}
+	msgs := append(chunks, msg)
+	err = bm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		for _, m := range msgs {
+			m.State = state
+			if err := bm.database.ReplaceMessage(ctx, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range msgs {
+		bm.data.UpdateMessageIfCached(ctx, m)
+	}
+	log.L(ctx).Infof("Committed draft message %s to state %s", msg.Header.ID, state)
+	return msg, nil
+}
+
+// DiscardDraftMessage moves a draft message, and any chunks of it, to the cancelled state, so that it is never sent
+func (bm *batchManager) DiscardDraftMessage(ctx context.Context, id string) (*core.Message, error) {
+	bm.schedulerMux.Lock()
+	defer bm.schedulerMux.Unlock()
+
+	msg, err := bm.getDraftMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		update := database.MessageQueryFactory.NewUpdate(ctx).Set("state", core.MessageStateCancelled)
+		if err := bm.database.UpdateMessage(ctx, bm.namespace, msg.Header.ID, update); err != nil {
+			return err
+		}
+		if len(msg.Chunks) > 0 {
+			fb := database.MessageQueryFactory.NewFilter(ctx)
+			return bm.database.UpdateMessages(ctx, bm.namespace, fb.And(
+				fb.Eq("cid", msg.Header.ID),
+				fb.Eq("type", core.MessageTypeChunk),
+				fb.Eq("state", core.MessageStateDraft),
+			), update)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	bm.data.UpdateMessageStateIfCached(ctx, msg.Header.ID, core.MessageStateCancelled, nil, "")
+	msg.State = core.MessageStateCancelled
+	log.L(ctx).Infof("Discarded draft message %s", msg.Header.ID)
+	return msg, nil
+}
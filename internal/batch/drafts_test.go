@@ -0,0 +1,296 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newDraftMessage() *core.Message {
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+		LocalNamespace: "ns1",
+		State:          core.MessageStateDraft,
+	}
+}
+
+func newDraftChunk(msg *core.Message) *core.Message {
+	chunk := newDraftMessage()
+	chunk.Header.CID = msg.Header.ID
+	chunk.Header.Type = core.MessageTypeChunk
+	msg.Chunks = append(msg.Chunks, chunk.Header.ID.String())
+	return chunk
+}
+
+func TestGetDraftMessages(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( tag == 'tag1' ) && ( state == 'draft' )", fi.String())
+		return true
+	})).Return([]*core.Message{}, nil, nil)
+
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	_, _, err := bm.GetDraftMessages(context.Background(), fb.And(fb.Eq("tag", "tag1")))
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCommitDraftMessage(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("ReplaceMessage", context.Background(), mock.MatchedBy(func(m *core.Message) bool {
+		return m.Header.ID.Equals(msg.Header.ID) && m.State == core.MessageStateReady
+	})).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", context.Background(), msg).Return()
+
+	res, err := bm.CommitDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateReady, res.State)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestCommitDraftMessageScheduledWithChunks(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	sendTime := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	msg.SendTime = &sendTime
+	chunk1 := newDraftChunk(msg)
+	chunk2 := newDraftChunk(msg)
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mdi.On("GetMessages", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("( cid == '%s' ) && ( type == 'chunk' ) && ( state == 'draft' ) sort=sequence", msg.Header.ID), fi.String())
+		return true
+	})).Return([]*core.Message{chunk1, chunk2}, nil, nil)
+	mockRunAsGroup(mdi)
+	var replaced []*fftypes.UUID
+	mdi.On("ReplaceMessage", context.Background(), mock.MatchedBy(func(m *core.Message) bool {
+		return m.State == core.MessageStateScheduled
+	})).Run(func(args mock.Arguments) {
+		replaced = append(replaced, args[1].(*core.Message).Header.ID)
+	}).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", context.Background(), mock.Anything).Return()
+
+	res, err := bm.CommitDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateScheduled, res.State)
+	assert.Equal(t, []*fftypes.UUID{chunk1.Header.ID, chunk2.Header.ID, msg.Header.ID}, replaced)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestCommitDraftMessageChunksFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	newDraftChunk(msg)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mdi.On("GetMessages", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := bm.CommitDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCommitDraftMessageReplaceFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("ReplaceMessage", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.CommitDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCommitDraftMessageBadID(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	_, err := bm.CommitDraftMessage(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestCommitDraftMessageGetFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.CommitDraftMessage(context.Background(), fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCommitDraftMessageNotFound(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := bm.CommitDraftMessage(context.Background(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCommitDraftMessageNotDraft(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	msg.State = core.MessageStateReady
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+
+	_, err := bm.CommitDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "FF10663", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDiscardDraftMessage(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	newDraftChunk(msg)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("UpdateMessage", context.Background(), "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	mdi.On("UpdateMessages", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("( cid == '%s' ) && ( type == 'chunk' ) && ( state == 'draft' )", msg.Header.ID), fi.String())
+		return true
+	}), mock.Anything).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageStateIfCached", context.Background(), msg.Header.ID, core.MessageStateCancelled, (*fftypes.FFTime)(nil), "").Return()
+
+	res, err := bm.DiscardDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateCancelled, res.State)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestDiscardDraftMessageNoChunks(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("UpdateMessage", context.Background(), "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageStateIfCached", context.Background(), msg.Header.ID, core.MessageStateCancelled, (*fftypes.FFTime)(nil), "").Return()
+
+	res, err := bm.DiscardDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateCancelled, res.State)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestDiscardDraftMessageBadID(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	_, err := bm.DiscardDraftMessage(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDiscardDraftMessageUpdateFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("UpdateMessage", context.Background(), "ns1", msg.Header.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.DiscardDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDiscardDraftMessageNotDraft(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newDraftMessage()
+	msg.State = core.MessageStateCancelled
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+
+	_, err := bm.DiscardDraftMessage(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "FF10663", err)
+
+	mdi.AssertExpectations(t)
+}
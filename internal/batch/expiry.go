@@ -0,0 +1,114 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// expirableStates are the states of a locally sent message that has not yet been confirmed
+var expirableStates = []driver.Value{
+	core.MessageStateScheduled,
+	core.MessageStateReady,
+	core.MessageStateSent,
+}
+
+func isExpirable(state core.MessageState) bool {
+	for _, s := range expirableStates {
+		if state == s {
+			return true
+		}
+	}
+	return false
+}
+
+// expireMessage moves an unconfirmed message to the expired state, and emits a message_expired event for each
+// of its topics. A message that is already in a batch might still be confirmed after it expires.
+func (bm *batchManager) expireMessage(id *fftypes.UUID) error {
+	bm.schedulerMux.Lock()
+	defer bm.schedulerMux.Unlock()
+
+	// Re-read the message under the lock, in case it was cancelled or confirmed
+	msg, err := bm.database.GetMessageByID(bm.ctx, bm.namespace, id)
+	if err != nil || msg == nil || !isExpirable(msg.State) {
+		return err
+	}
+
+	log.L(bm.ctx).Infof("Expiring message %s in state %s with expiry %s", msg.Header.ID, msg.State, msg.Expires)
+	err = bm.database.RunAsGroup(bm.ctx, func(ctx context.Context) error {
+		// Only update the message if its state has not changed since we read it
+		fb := database.MessageQueryFactory.NewFilter(ctx)
+		filter := fb.And(
+			fb.Eq("id", msg.Header.ID),
+			fb.Eq("state", msg.State),
+		)
+		update := database.MessageQueryFactory.NewUpdate(ctx).Set("state", core.MessageStateExpired)
+		if err := bm.database.UpdateMessages(ctx, bm.namespace, filter, update); err != nil {
+			return err
+		}
+		for _, topic := range msg.Header.Topics {
+			// One event per topic
+			event := core.NewEvent(core.EventTypeMessageExpired, bm.namespace, msg.Header.ID, msg.TransactionID, topic)
+			event.Correlator = msg.Header.CID
+			if err := bm.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	bm.data.UpdateMessageStateIfCached(bm.ctx, msg.Header.ID, core.MessageStateExpired, nil, "")
+	return nil
+}
+
+// expireMessages expires the unconfirmed messages that have reached their expiry time, and returns
+// the expiry time of the next message to wake up for (if there is one)
+func (bm *batchManager) expireMessages() (*time.Time, error) {
+	fb := database.MessageQueryFactory.NewFilterLimit(bm.ctx, bm.readPageSize)
+	msgs, _, err := bm.database.GetMessages(bm.ctx, bm.namespace, fb.And(
+		fb.In("state", expirableStates),
+		fb.Gt("expires", 0),
+	).Sort("expires").Limit(bm.readPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, msg := range msgs {
+		if expires := time.Time(*msg.Expires); expires.After(now) {
+			return &expires, nil
+		}
+		if err := bm.expireMessage(msg.Header.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// If we read a full page, there might be more messages that are due
+	if len(msgs) == int(bm.readPageSize) {
+		return &now, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,207 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newExpiringMessage(state core.MessageState, expires time.Time) *core.Message {
+	e := fftypes.FFTime(expires)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			CID:    fftypes.NewUUID(),
+			Topics: fftypes.FFStringArray{"topic1", "topic2"},
+		},
+		LocalNamespace: "ns1",
+		State:          state,
+		Expires:        &e,
+	}
+}
+
+func mockRunAsGroup(mdi *databasemocks.Plugin) {
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		ctx := a.Get(0).(context.Context)
+		fn := a.Get(1).(func(context.Context) error)
+		rag.ReturnArguments = mock.Arguments{fn(ctx)}
+	}
+}
+
+func TestExpireMessages(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+	bm.readPageSize = 100
+
+	due := newExpiringMessage(core.MessageStateSent, time.Now().Add(-1*time.Second))
+	confirmed := newExpiringMessage(core.MessageStateSent, time.Now().Add(-1*time.Second))
+	future := newExpiringMessage(core.MessageStateReady, time.Now().Add(1*time.Hour))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( state IN ['scheduled','ready','sent'] ) && ( expires >> 0 ) sort=expires limit=100", fi.String())
+		return true
+	})).Return([]*core.Message{due, confirmed, future}, nil, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", due.Header.ID).Return(due, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", confirmed.Header.ID).Return(&core.Message{
+		Header: confirmed.Header,
+		State:  core.MessageStateConfirmed,
+	}, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("UpdateMessages", bm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("( id == '%s' ) && ( state == 'sent' )", due.Header.ID), fi.String())
+		return true
+	}), mock.Anything).Return(nil)
+	mdi.On("InsertEvent", bm.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeMessageExpired && e.Reference.Equals(due.Header.ID) &&
+			e.Correlator.Equals(due.Header.CID) && e.Namespace == "ns1"
+	})).Return(nil).Twice()
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageStateIfCached", bm.ctx, due.Header.ID, core.MessageStateExpired, (*fftypes.FFTime)(nil), "").Return()
+
+	next, err := bm.expireMessages()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Time(*future.Expires), *next)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestExpireMessagesFullPage(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+	bm.readPageSize = 1
+
+	due := newExpiringMessage(core.MessageStateScheduled, time.Now().Add(-1*time.Second))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return([]*core.Message{due}, nil, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", due.Header.ID).Return(due, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("UpdateMessages", bm.ctx, "ns1", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", bm.ctx, mock.Anything).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageStateIfCached", bm.ctx, due.Header.ID, core.MessageStateExpired, (*fftypes.FFTime)(nil), "").Return()
+
+	next, err := bm.expireMessages()
+	assert.NoError(t, err)
+	assert.NotNil(t, next)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestExpireMessagesQueryFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := bm.expireMessages()
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExpireMessagesUpdateFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	due := newExpiringMessage(core.MessageStateReady, time.Now().Add(-1*time.Second))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return([]*core.Message{due}, nil, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", due.Header.ID).Return(due, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("UpdateMessages", bm.ctx, "ns1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.expireMessages()
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExpireMessageInsertEventFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	due := newExpiringMessage(core.MessageStateReady, time.Now().Add(-1*time.Second))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", due.Header.ID).Return(due, nil)
+	mockRunAsGroup(mdi)
+	mdi.On("UpdateMessages", bm.ctx, "ns1", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertEvent", bm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := bm.expireMessage(due.Header.ID)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestExpireMessageGetFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := bm.expireMessage(fftypes.NewUUID())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMessageSchedulerNextExpiry(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	bm.minimumPollDelay = 0
+	bm.messagePollTimeout = 1 * time.Minute
+
+	scheduled := newScheduledMessage(time.Now().Add(1 * time.Hour))
+	expiring := newExpiringMessage(core.MessageStateSent, time.Now().Add(1*time.Millisecond))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Sort[0].Field == "sendtime"
+	})).Return([]*core.Message{scheduled}, nil, nil)
+	// Wakes up at the expiry time, rather than the poll timeout or the send time
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Sort[0].Field == "expires"
+	})).Return([]*core.Message{expiring}, nil, nil).Once()
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	bm.messageScheduler()
+	<-bm.schedulerDone
+
+	mdi.AssertExpectations(t)
+}
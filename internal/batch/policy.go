@@ -0,0 +1,132 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// FlushRequest selects the batches to flush immediately - all pending batches in the namespace, or just
+// those containing a message on the topic
+type FlushRequest struct {
+	Topic string `ffstruct:"BatchFlushRequest" json:"topic,omitempty"`
+}
+
+type FlushResult struct {
+	Processors int `ffstruct:"BatchFlushResult" json:"processors"`
+}
+
+// loadBatchPolicies populates the policy cache on startup. Policies are only changed through this
+// manager, so the cache is kept up to date from then on.
+func (bm *batchManager) loadBatchPolicies() error {
+	policies, err := bm.database.GetBatchPolicies(bm.ctx, bm.namespace)
+	if err != nil {
+		return err
+	}
+	bm.policyMux.Lock()
+	defer bm.policyMux.Unlock()
+	for _, policy := range policies {
+		bm.policies[policy.Topic] = policy
+	}
+	return nil
+}
+
+func (bm *batchManager) SetBatchPolicy(ctx context.Context, topic string, policy *core.BatchPolicy) (*core.BatchPolicy, error) {
+	if err := (fftypes.FFStringArray{topic}).Validate(ctx, "topic", true, 1); err != nil {
+		return nil, err
+	}
+	if (policy.MaxLatency == nil && policy.MaxSize == 0) || (policy.MaxLatency != nil && *policy.MaxLatency <= 0) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBatchPolicyInvalid, topic)
+	}
+	policy.Namespace = bm.namespace
+	policy.Topic = topic
+	if err := bm.database.UpsertBatchPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	bm.policyMux.Lock()
+	bm.policies[topic] = policy
+	bm.policyMux.Unlock()
+	log.L(ctx).Infof("Set batch policy for topic '%s': maxLatency=%v maxSize=%d", topic, policy.MaxLatency, policy.MaxSize)
+	return policy, nil
+}
+
+func (bm *batchManager) GetBatchPolicies(ctx context.Context) ([]*core.BatchPolicy, error) {
+	return bm.database.GetBatchPolicies(ctx, bm.namespace)
+}
+
+func (bm *batchManager) GetBatchPolicy(ctx context.Context, topic string) (*core.BatchPolicy, error) {
+	return bm.database.GetBatchPolicy(ctx, bm.namespace, topic)
+}
+
+func (bm *batchManager) DeleteBatchPolicy(ctx context.Context, topic string) error {
+	if err := bm.database.DeleteBatchPolicy(ctx, bm.namespace, topic); err != nil {
+		return err
+	}
+
+	bm.policyMux.Lock()
+	delete(bm.policies, topic)
+	bm.policyMux.Unlock()
+	log.L(ctx).Infof("Deleted batch policy for topic '%s'", topic)
+	return nil
+}
+
+// batchPolicy returns the strictest limits of the policies for the topics of the message,
+// with zero values where no policy sets a limit
+func (bm *batchManager) batchPolicy(msg *core.Message) (maxSize uint, maxLatency time.Duration) {
+	bm.policyMux.RLock()
+	defer bm.policyMux.RUnlock()
+	for _, topic := range msg.Header.Topics {
+		policy, ok := bm.policies[topic]
+		if !ok {
+			continue
+		}
+		if policy.MaxSize > 0 && (maxSize == 0 || policy.MaxSize < maxSize) {
+			maxSize = policy.MaxSize
+		}
+		if policy.MaxLatency != nil && (maxLatency == 0 || time.Duration(*policy.MaxLatency) < maxLatency) {
+			maxLatency = time.Duration(*policy.MaxLatency)
+		}
+	}
+	return maxSize, maxLatency
+}
+
+// FlushBatches asks each batch processor to flush the batch it is assembling immediately, rather than
+// waiting for it to fill or time out. With a topic, only batches containing a message on that topic are
+// flushed. Messages that have not yet been assembled into a batch are not affected.
+func (bm *batchManager) FlushBatches(ctx context.Context, req *FlushRequest) (*FlushResult, error) {
+	if req.Topic != "" {
+		if err := (fftypes.FFStringArray{req.Topic}).Validate(ctx, "topic", true, 1); err != nil {
+			return nil, err
+		}
+	}
+	processors := bm.getProcessors()
+	for _, p := range processors {
+		p.requestFlush(req.Topic)
+	}
+	log.L(ctx).Infof("Requested flush of %d batch processors (topic='%s')", len(processors), req.Topic)
+	return &FlushResult{
+		Processors: len(processors),
+	}, nil
+}
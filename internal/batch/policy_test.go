@@ -0,0 +1,249 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBatchPolicy(maxLatency time.Duration, maxSize uint) *core.BatchPolicy {
+	policy := &core.BatchPolicy{MaxSize: maxSize}
+	if maxLatency > 0 {
+		policy.MaxLatency = (*fftypes.FFDuration)(&maxLatency)
+	}
+	return policy
+}
+
+func TestStartLoadBatchPoliciesFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetBatchPolicies", mock.Anything, "ns1").Return(nil, fmt.Errorf("pop"))
+
+	err := bm.Start()
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestLoadBatchPolicies(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	policy := newTestBatchPolicy(0, 5)
+	policy.Topic = "topic1"
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetBatchPolicies", mock.Anything, "ns1").Return([]*core.BatchPolicy{policy}, nil)
+
+	err := bm.loadBatchPolicies()
+	assert.NoError(t, err)
+	assert.Equal(t, policy, bm.policies["topic1"])
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSetBatchPolicy(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertBatchPolicy", context.Background(), mock.MatchedBy(func(p *core.BatchPolicy) bool {
+		return p.Namespace == "ns1" && p.Topic == "topic1"
+	})).Return(nil)
+
+	policy, err := bm.SetBatchPolicy(context.Background(), "topic1", newTestBatchPolicy(50*time.Millisecond, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, policy, bm.policies["topic1"])
+
+	mdi.AssertExpectations(t)
+}
+
+func TestSetBatchPolicyBadTopic(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	_, err := bm.SetBatchPolicy(context.Background(), "!wrong", newTestBatchPolicy(0, 5))
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestSetBatchPolicyNoLimits(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	_, err := bm.SetBatchPolicy(context.Background(), "topic1", &core.BatchPolicy{})
+	assert.Regexp(t, "FF10634", err)
+}
+
+func TestSetBatchPolicyNegativeLatency(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	maxLatency := fftypes.FFDuration(-1)
+	_, err := bm.SetBatchPolicy(context.Background(), "topic1", &core.BatchPolicy{MaxLatency: &maxLatency})
+	assert.Regexp(t, "FF10634", err)
+}
+
+func TestSetBatchPolicyFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertBatchPolicy", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.SetBatchPolicy(context.Background(), "topic1", newTestBatchPolicy(0, 5))
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, bm.policies)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetBatchPolicies(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetBatchPolicies", context.Background(), "ns1").Return([]*core.BatchPolicy{}, nil)
+	mdi.On("GetBatchPolicy", context.Background(), "ns1", "topic1").Return(&core.BatchPolicy{}, nil)
+
+	_, err := bm.GetBatchPolicies(context.Background())
+	assert.NoError(t, err)
+	_, err = bm.GetBatchPolicy(context.Background(), "topic1")
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeleteBatchPolicy(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	bm.policies["topic1"] = newTestBatchPolicy(0, 5)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("DeleteBatchPolicy", context.Background(), "ns1", "topic1").Return(nil)
+
+	err := bm.DeleteBatchPolicy(context.Background(), "topic1")
+	assert.NoError(t, err)
+	assert.Empty(t, bm.policies)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeleteBatchPolicyFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	bm.policies["topic1"] = newTestBatchPolicy(0, 5)
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("DeleteBatchPolicy", context.Background(), "ns1", "topic1").Return(fmt.Errorf("pop"))
+
+	err := bm.DeleteBatchPolicy(context.Background(), "topic1")
+	assert.EqualError(t, err, "pop")
+	assert.Len(t, bm.policies, 1)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestBatchPolicyStrictestOfTopics(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	bm.policies["topic1"] = newTestBatchPolicy(50*time.Millisecond, 0)
+	bm.policies["topic2"] = newTestBatchPolicy(20*time.Millisecond, 10)
+	bm.policies["topic3"] = newTestBatchPolicy(0, 5)
+
+	maxSize, maxLatency := bm.batchPolicy(&core.Message{
+		Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic1", "topic2", "topic3", "topic4"}},
+	})
+	assert.Equal(t, uint(5), maxSize)
+	assert.Equal(t, 20*time.Millisecond, maxLatency)
+
+	maxSize, maxLatency = bm.batchPolicy(&core.Message{
+		Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic4"}},
+	})
+	assert.Zero(t, maxSize)
+	assert.Zero(t, maxLatency)
+}
+
+func TestFlushBatchesBadTopic(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	_, err := bm.FlushBatches(context.Background(), &FlushRequest{Topic: "!wrong"})
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestFlushBatches(t *testing.T) {
+	dispatched := make(chan *DispatchPayload)
+	cancel, mdi, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		dispatched <- state
+		return nil
+	})
+	defer cancel()
+	bp.conf.BatchTimeout = 100 * time.Second
+	bm := bp.bm
+	bm.allDispatchers = append(bm.allDispatchers, &dispatcher{
+		processors: map[string]*batchProcessor{"utprocessor": bp},
+	})
+	mockRunAsGroupPassthrough(mdi)
+	mdi.On("UpdateMessages", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("InsertOrGetBatch", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	mth := bp.txHelper.(*txcommonmocks.Helper)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeBatchPin, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+
+	mdm := bp.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", mock.Anything, mock.Anything).Return()
+
+	mim := bp.bm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", mock.Anything).Return(&core.Identity{}, nil)
+
+	bp.newWork <- &batchWork{
+		msg: &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Topics: fftypes.FFStringArray{"topic1"}}, Sequence: 1000},
+	}
+
+	// Keep asking until the work has been assembled, and the batch is flushed
+	var batch *DispatchPayload
+	for batch == nil {
+		res, err := bm.FlushBatches(context.Background(), &FlushRequest{Topic: "topic1"})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, res.Processors)
+		select {
+		case batch = <-dispatched:
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	assert.Equal(t, 1, len(batch.Messages))
+
+	bp.cancelCtx()
+	<-bp.done
+
+	mdi.AssertExpectations(t)
+	mth.AssertExpectations(t)
+}
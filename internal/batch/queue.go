@@ -0,0 +1,250 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// QueueStatus reports the messages that have been read for batching, and are waiting to be sent in a batch
+type QueueStatus struct {
+	Depth         int                 `ffstruct:"BatchQueueStatus" json:"depth"`
+	DispatcherLag *fftypes.FFDuration `ffstruct:"BatchQueueStatus" json:"dispatcherLag,omitempty"`
+	Topics        []*TopicQueueStatus `ffstruct:"BatchQueueStatus" json:"topics"`
+}
+
+type TopicQueueStatus struct {
+	Topic        string             `ffstruct:"BatchTopicQueueStatus" json:"topic"`
+	Depth        int                `ffstruct:"BatchTopicQueueStatus" json:"depth"`
+	Oldest       *fftypes.FFTime    `ffstruct:"BatchTopicQueueStatus" json:"oldest"`
+	OldestAge    fftypes.FFDuration `ffstruct:"BatchTopicQueueStatus" json:"oldestAge"`
+	Backpressure bool               `ffstruct:"BatchTopicQueueStatus" json:"backpressure"`
+}
+
+type queuedMessage struct {
+	topics []string
+	ready  time.Time
+}
+
+type topicQueue struct {
+	depth  int
+	oldest time.Time
+}
+
+// batchQueue tracks the messages that have been dispatched to a processor, until the processor has
+// recorded them as sent. These are the messages held in memory by the batch manager.
+type batchQueue struct {
+	mux            sync.Mutex
+	namespace      string
+	metricsEnabled bool
+	maxDepth       int
+	maxAge         time.Duration
+	messages       map[int64]*queuedMessage
+	topics         map[string]*topicQueue
+	lag            *time.Duration
+}
+
+func newBatchQueue(ns string) *batchQueue {
+	return &batchQueue{
+		namespace:      ns,
+		metricsEnabled: config.GetBool(coreconfig.MetricsEnabled),
+		maxDepth:       config.GetInt(coreconfig.BatchBackpressureMaxQueueDepth),
+		maxAge:         config.GetDuration(coreconfig.BatchBackpressureMaxMessageAge),
+		messages:       make(map[int64]*queuedMessage),
+		topics:         make(map[string]*topicQueue),
+	}
+}
+
+// readyTime is when the message became ready to send - when it was created, or its send time if it was scheduled
+func readyTime(msg *core.Message) time.Time {
+	ready := time.Time(*msg.Header.Created)
+	if msg.SendTime != nil && time.Time(*msg.SendTime).After(ready) {
+		ready = time.Time(*msg.SendTime)
+	}
+	return ready
+}
+
+func (bq *batchQueue) add(msg *core.Message, now time.Time) {
+	bq.mux.Lock()
+	defer bq.mux.Unlock()
+
+	if _, queued := bq.messages[msg.Sequence]; queued {
+		return
+	}
+	qm := &queuedMessage{
+		topics: msg.Header.Topics,
+		ready:  now,
+	}
+	if msg.Header.Created != nil {
+		qm.ready = readyTime(msg)
+	}
+	bq.messages[msg.Sequence] = qm
+
+	lag := now.Sub(qm.ready)
+	if lag < 0 {
+		lag = 0
+	}
+	bq.lag = &lag
+	if bq.metricsEnabled {
+		metrics.BatchDispatcherLagGauge.WithLabelValues(bq.namespace).Set(lag.Seconds())
+	}
+
+	for _, topic := range qm.topics {
+		tq, ok := bq.topics[topic]
+		if !ok {
+			tq = &topicQueue{}
+			bq.topics[topic] = tq
+		}
+		tq.depth++
+		if tq.depth == 1 || qm.ready.Before(tq.oldest) {
+			tq.oldest = qm.ready
+		}
+		if bq.metricsEnabled {
+			metrics.BatchQueueDepthGauge.WithLabelValues(bq.namespace, topic).Set(float64(tq.depth))
+		}
+	}
+}
+
+func (bq *batchQueue) remove(sequences []int64) {
+	bq.mux.Lock()
+	defer bq.mux.Unlock()
+
+	stale := make(map[string]*topicQueue)
+	for _, seq := range sequences {
+		qm, queued := bq.messages[seq]
+		if !queued {
+			continue
+		}
+		delete(bq.messages, seq)
+		for _, topic := range qm.topics {
+			tq := bq.topics[topic]
+			tq.depth--
+			if tq.depth == 0 {
+				delete(bq.topics, topic)
+				delete(stale, topic)
+				if bq.metricsEnabled {
+					metrics.BatchQueueDepthGauge.DeleteLabelValues(bq.namespace, topic)
+					metrics.BatchQueueOldestAgeGauge.DeleteLabelValues(bq.namespace, topic)
+				}
+				continue
+			}
+			if qm.ready.Equal(tq.oldest) {
+				stale[topic] = tq
+			}
+			if bq.metricsEnabled {
+				metrics.BatchQueueDepthGauge.WithLabelValues(bq.namespace, topic).Set(float64(tq.depth))
+			}
+		}
+	}
+
+	// Find the new oldest message for topics that lost theirs, in a single pass over the queue
+	if len(stale) > 0 {
+		found := make(map[string]bool, len(stale))
+		for _, qm := range bq.messages {
+			for _, topic := range qm.topics {
+				if tq, ok := stale[topic]; ok && (!found[topic] || qm.ready.Before(tq.oldest)) {
+					tq.oldest = qm.ready
+					found[topic] = true
+				}
+			}
+		}
+	}
+}
+
+func (bq *batchQueue) overLimit(tq *topicQueue, now time.Time) bool {
+	return (bq.maxDepth > 0 && tq.depth >= bq.maxDepth) ||
+		(bq.maxAge > 0 && now.Sub(tq.oldest) >= bq.maxAge)
+}
+
+func (bq *batchQueue) check(ctx context.Context, topics []string, now time.Time) error {
+	if bq.maxDepth <= 0 && bq.maxAge <= 0 {
+		return nil
+	}
+	bq.mux.Lock()
+	defer bq.mux.Unlock()
+
+	for _, topic := range topics {
+		if tq, ok := bq.topics[topic]; ok && bq.overLimit(tq, now) {
+			if bq.metricsEnabled {
+				metrics.BatchBackpressureRejectedCounter.WithLabelValues(bq.namespace, topic).Inc()
+			}
+			return i18n.NewError(ctx, coremsgs.MsgBatchQueueBackpressure, topic, tq.depth, now.Sub(tq.oldest).Round(time.Millisecond))
+		}
+	}
+	return nil
+}
+
+func (bq *batchQueue) status(now time.Time) *QueueStatus {
+	bq.mux.Lock()
+	defer bq.mux.Unlock()
+
+	status := &QueueStatus{
+		Depth:  len(bq.messages),
+		Topics: make([]*TopicQueueStatus, 0, len(bq.topics)),
+	}
+	if bq.lag != nil {
+		lag := fftypes.FFDuration(*bq.lag)
+		status.DispatcherLag = &lag
+	}
+	for topic, tq := range bq.topics {
+		oldest := fftypes.FFTime(tq.oldest)
+		status.Topics = append(status.Topics, &TopicQueueStatus{
+			Topic:        topic,
+			Depth:        tq.depth,
+			Oldest:       &oldest,
+			OldestAge:    fftypes.FFDuration(now.Sub(tq.oldest)),
+			Backpressure: bq.overLimit(tq, now),
+		})
+	}
+	sort.Slice(status.Topics, func(i, j int) bool {
+		return status.Topics[i].Topic < status.Topics[j].Topic
+	})
+	return status
+}
+
+// updateAgeMetrics is called on each pass of the message sequencer, as the age of the oldest message on
+// each topic grows while it waits to be sent
+func (bq *batchQueue) updateAgeMetrics(now time.Time) {
+	if !bq.metricsEnabled {
+		return
+	}
+	bq.mux.Lock()
+	defer bq.mux.Unlock()
+
+	for topic, tq := range bq.topics {
+		metrics.BatchQueueOldestAgeGauge.WithLabelValues(bq.namespace, topic).Set(now.Sub(tq.oldest).Seconds())
+	}
+}
+
+func (bm *batchManager) GetQueueStatus() *QueueStatus {
+	return bm.queue.status(time.Now())
+}
+
+func (bm *batchManager) CheckBackpressure(ctx context.Context, topics []string) error {
+	return bm.queue.check(ctx, topics, time.Now())
+}
@@ -0,0 +1,175 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestQueuedMessage(seq int64, created time.Time, topics ...string) *core.Message {
+	createdTime := fftypes.FFTime(created)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:      fftypes.NewUUID(),
+			Created: &createdTime,
+			Topics:  topics,
+		},
+		Sequence: seq,
+	}
+}
+
+func TestBatchQueueAddRemove(t *testing.T) {
+	testConfigReset()
+	bq := newBatchQueue("ns1")
+
+	now := time.Now()
+	sendTime := fftypes.FFTime(now.Add(-5 * time.Second))
+	scheduled := newTestQueuedMessage(3, now.Add(-1*time.Hour), "topic2")
+	scheduled.SendTime = &sendTime
+
+	bq.add(newTestQueuedMessage(1, now.Add(-10*time.Second), "topic1", "topic2"), now)
+	bq.add(newTestQueuedMessage(2, now.Add(-20*time.Second), "topic1"), now)
+	bq.add(scheduled, now)
+	bq.add(newTestQueuedMessage(2, now, "topic1"), now) // already queued
+
+	status := bq.status(now)
+	assert.Equal(t, 3, status.Depth)
+	assert.Equal(t, fftypes.FFDuration(5*time.Second), *status.DispatcherLag)
+	assert.Len(t, status.Topics, 2)
+	assert.Equal(t, "topic1", status.Topics[0].Topic)
+	assert.Equal(t, 2, status.Topics[0].Depth)
+	assert.Equal(t, fftypes.FFDuration(20*time.Second), status.Topics[0].OldestAge)
+	assert.Equal(t, "topic2", status.Topics[1].Topic)
+	assert.Equal(t, 2, status.Topics[1].Depth)
+	assert.Equal(t, fftypes.FFDuration(10*time.Second), status.Topics[1].OldestAge)
+	assert.False(t, status.Topics[0].Backpressure)
+
+	bq.remove([]int64{2, 4})
+	status = bq.status(now)
+	assert.Equal(t, 2, status.Depth)
+	assert.Equal(t, 1, status.Topics[0].Depth)
+	assert.Equal(t, fftypes.FFDuration(10*time.Second), status.Topics[0].OldestAge)
+
+	bq.remove([]int64{1})
+	status = bq.status(now)
+	assert.Len(t, status.Topics, 1)
+	assert.Equal(t, "topic2", status.Topics[0].Topic)
+	assert.Equal(t, fftypes.FFDuration(5*time.Second), status.Topics[0].OldestAge)
+
+	bq.remove([]int64{3})
+	status = bq.status(now)
+	assert.Zero(t, status.Depth)
+	assert.Empty(t, status.Topics)
+}
+
+func TestBatchQueueNoCreated(t *testing.T) {
+	testConfigReset()
+	bq := newBatchQueue("ns1")
+
+	now := time.Now()
+	bq.add(&core.Message{Header: core.MessageHeader{Topics: fftypes.FFStringArray{"topic1"}}, Sequence: 1}, now)
+
+	status := bq.status(now)
+	assert.Equal(t, fftypes.FFDuration(0), *status.DispatcherLag)
+	assert.Equal(t, fftypes.FFDuration(0), status.Topics[0].OldestAge)
+}
+
+func TestBatchQueueBackpressureDepth(t *testing.T) {
+	testConfigReset()
+	config.Set(coreconfig.MetricsEnabled, true)
+	config.Set(coreconfig.BatchBackpressureMaxQueueDepth, 2)
+	metrics.Clear()
+	metrics.Registry()
+	bq := newBatchQueue("ns1")
+
+	ctx := context.Background()
+	now := time.Now()
+	bq.add(newTestQueuedMessage(1, now, "topic1"), now)
+	assert.NoError(t, bq.check(ctx, []string{"topic1", "topic2"}, now))
+
+	bq.add(newTestQueuedMessage(2, now, "topic1"), now)
+	err := bq.check(ctx, []string{"topic2", "topic1"}, now)
+	assert.Regexp(t, "FF10664.*topic1", err)
+	assert.True(t, bq.status(now).Topics[0].Backpressure)
+	assert.Equal(t, float64(2), testutil.ToFloat64(metrics.BatchQueueDepthGauge.WithLabelValues("ns1", "topic1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.BatchBackpressureRejectedCounter.WithLabelValues("ns1", "topic1")))
+
+	bq.remove([]int64{1})
+	assert.NoError(t, bq.check(ctx, []string{"topic1"}, now))
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.BatchQueueDepthGauge.WithLabelValues("ns1", "topic1")))
+}
+
+func TestBatchQueueBackpressureAge(t *testing.T) {
+	testConfigReset()
+	config.Set(coreconfig.MetricsEnabled, true)
+	config.Set(coreconfig.BatchBackpressureMaxMessageAge, "1m")
+	metrics.Clear()
+	metrics.Registry()
+	bq := newBatchQueue("ns1")
+
+	ctx := context.Background()
+	now := time.Now()
+	bq.add(newTestQueuedMessage(1, now.Add(-30*time.Second), "topic1"), now)
+	assert.NoError(t, bq.check(ctx, []string{"topic1"}, now))
+	assert.Equal(t, float64(30), testutil.ToFloat64(metrics.BatchDispatcherLagGauge.WithLabelValues("ns1")))
+
+	later := now.Add(30 * time.Second)
+	bq.updateAgeMetrics(later)
+	assert.Equal(t, float64(60), testutil.ToFloat64(metrics.BatchQueueOldestAgeGauge.WithLabelValues("ns1", "topic1")))
+	assert.Regexp(t, "FF10664.*topic1", bq.check(ctx, []string{"topic1"}, later))
+
+	bq.remove([]int64{1})
+	assert.Zero(t, testutil.CollectAndCount(metrics.BatchQueueOldestAgeGauge))
+	assert.Zero(t, testutil.CollectAndCount(metrics.BatchQueueDepthGauge))
+}
+
+func TestBatchQueueMetricsDisabled(t *testing.T) {
+	testConfigReset()
+	config.Set(coreconfig.MetricsEnabled, false)
+	bq := newBatchQueue("ns1")
+
+	now := time.Now()
+	bq.add(newTestQueuedMessage(1, now, "topic1"), now)
+	bq.updateAgeMetrics(now)
+	bq.remove([]int64{1})
+}
+
+func TestGetQueueStatusAndCheckBackpressure(t *testing.T) {
+	testConfigReset()
+	config.Set(coreconfig.BatchBackpressureMaxQueueDepth, 1)
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	bm.queue.add(newTestQueuedMessage(1, time.Now(), "topic1"), time.Now())
+	status := bm.GetQueueStatus()
+	assert.Equal(t, 1, status.Depth)
+	assert.True(t, status.Topics[0].Backpressure)
+	assert.Regexp(t, "FF10664", bm.CheckBackpressure(context.Background(), []string{"topic1"}))
+
+	bm.notifyFlushed([]int64{1})
+	assert.NoError(t, bm.CheckBackpressure(context.Background(), []string{"topic1"}))
+}
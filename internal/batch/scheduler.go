@@ -0,0 +1,178 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// GetScheduledMessages returns the messages that are waiting for their send time
+func (bm *batchManager) GetScheduledMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	filter = filter.Condition(filter.Builder().Eq("state", core.MessageStateScheduled))
+	return bm.database.GetMessages(ctx, bm.namespace, filter)
+}
+
+// CancelScheduledMessage moves a message that is waiting for its send time to the cancelled state,
+// so that it is never sent
+func (bm *batchManager) CancelScheduledMessage(ctx context.Context, id string) (*core.Message, error) {
+	msgID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hold the scheduler lock, so we cannot race with the dispatch of the message
+	bm.schedulerMux.Lock()
+	defer bm.schedulerMux.Unlock()
+
+	msg, err := bm.database.GetMessageByID(ctx, bm.namespace, msgID)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	if msg.State != core.MessageStateScheduled {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotScheduled, msg.Header.ID, msg.State)
+	}
+
+	update := database.MessageQueryFactory.NewUpdate(ctx).Set("state", core.MessageStateCancelled)
+	if err := bm.database.UpdateMessage(ctx, bm.namespace, msgID, update); err != nil {
+		return nil, err
+	}
+	bm.data.UpdateMessageStateIfCached(ctx, msgID, core.MessageStateCancelled, nil, "")
+	msg.State = core.MessageStateCancelled
+	log.L(ctx).Infof("Cancelled scheduled message %s", msgID)
+	return msg, nil
+}
+
+// dispatchScheduledMessage moves a scheduled message to the ready state. The message is replaced, so that
+// it is assigned a new sequence and picked up by the message sequencer for batch assembly.
+func (bm *batchManager) dispatchScheduledMessage(id *fftypes.UUID) error {
+	bm.schedulerMux.Lock()
+	defer bm.schedulerMux.Unlock()
+
+	// Re-read the message under the lock, in case it was cancelled
+	msg, err := bm.database.GetMessageByID(bm.ctx, bm.namespace, id)
+	if err != nil || msg == nil || msg.State != core.MessageStateScheduled {
+		return err
+	}
+
+	log.L(bm.ctx).Infof("Dispatching scheduled message %s with send time %s", msg.Header.ID, msg.SendTime)
+	msg.State = core.MessageStateReady
+	if err := bm.database.ReplaceMessage(bm.ctx, msg); err != nil {
+		return err
+	}
+	bm.data.UpdateMessageIfCached(bm.ctx, msg)
+	return nil
+}
+
+// dispatchScheduledMessages dispatches the scheduled messages that have reached their send time, and returns
+// the send time of the next message to wake up for (if there is one)
+func (bm *batchManager) dispatchScheduledMessages() (*time.Time, error) {
+	fb := database.MessageQueryFactory.NewFilterLimit(bm.ctx, bm.readPageSize)
+	msgs, _, err := bm.database.GetMessages(bm.ctx, bm.namespace, fb.And(
+		fb.Eq("state", core.MessageStateScheduled),
+	).Sort("sendtime").Limit(bm.readPageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, msg := range msgs {
+		if msg.SendTime != nil {
+			if sendTime := time.Time(*msg.SendTime); sendTime.After(now) {
+				return &sendTime, nil
+			}
+		}
+		if err := bm.dispatchScheduledMessage(msg.Header.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// If we read a full page, there might be more messages that are due
+	if len(msgs) == int(bm.readPageSize) {
+		return &now, nil
+	}
+	return nil, nil
+}
+
+func (bm *batchManager) messageScheduler() {
+	l := log.L(bm.ctx)
+	l.Debugf("Started scheduled message dispatcher")
+	defer close(bm.schedulerDone)
+
+	for {
+		// In an error condition we retry until success, or a closed context
+		var next *time.Time
+		err := bm.retry.Do(bm.ctx, "dispatch scheduled messages", func(attempt int) (retry bool, err error) {
+			if next, err = bm.dispatchScheduledMessages(); err != nil {
+				return true, err
+			}
+			// Wake up at the earlier of the next send time and the next expiry time
+			nextExpiry, err := bm.expireMessages()
+			if nextExpiry != nil && (next == nil || nextExpiry.Before(*next)) {
+				next = nextExpiry
+			}
+			return true, err
+		})
+		if err != nil {
+			l.Debugf("Exiting: %s", err)
+			return
+		}
+
+		if done := bm.waitForScheduledMessages(next); done {
+			return
+		}
+	}
+}
+
+func (bm *batchManager) waitForScheduledMessages(next *time.Time) (done bool) {
+	l := log.L(bm.ctx)
+
+	// We have a short minimum timeout, to stop us thrashing the DB
+	time.Sleep(bm.minimumPollDelay)
+
+	// Wake up at the next send or expiry time, or after the poll timeout to pick up messages scheduled by other means.
+	// New messages also tap us, in case they are scheduled before the next send time we know about.
+	timeout := bm.messagePollTimeout - bm.minimumPollDelay
+	if next != nil {
+		if untilNext := time.Until(*next); untilNext < timeout {
+			timeout = untilNext
+		}
+	}
+	timer := time.NewTimer(timeout)
+	select {
+	case <-bm.schedulerTap:
+		timer.Stop()
+		return false
+	case <-timer.C:
+		return false
+	case <-bm.ctx.Done():
+		timer.Stop()
+		l.Debugf("Exiting due to cancelled context")
+		return true
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package batch
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newScheduledMessage(sendTime time.Time) *core.Message {
+	st := fftypes.FFTime(sendTime)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+		LocalNamespace: "ns1",
+		State:          core.MessageStateScheduled,
+		SendTime:       &st,
+	}
+}
+
+func TestGetScheduledMessages(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( tag == 'tag1' ) && ( state == 'scheduled' )", fi.String())
+		return true
+	})).Return([]*core.Message{}, nil, nil)
+
+	fb := database.MessageQueryFactory.NewFilter(context.Background())
+	_, _, err := bm.GetScheduledMessages(context.Background(), fb.And(fb.Eq("tag", "tag1")))
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCancelScheduledMessage(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newScheduledMessage(time.Now().Add(1 * time.Hour))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mdi.On("UpdateMessage", context.Background(), "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageStateIfCached", context.Background(), msg.Header.ID, core.MessageStateCancelled, (*fftypes.FFTime)(nil), "").Return()
+
+	res, err := bm.CancelScheduledMessage(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateCancelled, res.State)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestCancelScheduledMessageBadID(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	_, err := bm.CancelScheduledMessage(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestCancelScheduledMessageGetFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := bm.CancelScheduledMessage(context.Background(), fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCancelScheduledMessageNotFound(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := bm.CancelScheduledMessage(context.Background(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCancelScheduledMessageNotScheduled(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newScheduledMessage(time.Now())
+	msg.State = core.MessageStateSent
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+
+	_, err := bm.CancelScheduledMessage(context.Background(), msg.Header.ID.String())
+	assert.Regexp(t, "FF10618", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCancelScheduledMessageUpdateFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	msg := newScheduledMessage(time.Now().Add(1 * time.Hour))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", context.Background(), "ns1", msg.Header.ID).Return(msg, nil)
+	mdi.On("UpdateMessage", context.Background(), "ns1", msg.Header.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := bm.CancelScheduledMessage(context.Background(), msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDispatchScheduledMessages(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	due := newScheduledMessage(time.Now().Add(-1 * time.Second))
+	cancelled := newScheduledMessage(time.Now().Add(-1 * time.Second))
+	future := newScheduledMessage(time.Now().Add(1 * time.Hour))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, err := f.Finalize()
+		assert.NoError(t, err)
+		assert.Equal(t, "( state == 'scheduled' ) sort=sendtime limit=100", fi.String())
+		return true
+	})).Return([]*core.Message{due, cancelled, future}, nil, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", due.Header.ID).Return(due, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", cancelled.Header.ID).Return(&core.Message{
+		Header: cancelled.Header,
+		State:  core.MessageStateCancelled,
+	}, nil)
+	mdi.On("ReplaceMessage", bm.ctx, mock.MatchedBy(func(msg *core.Message) bool {
+		return msg.Header.ID.Equals(due.Header.ID) && msg.State == core.MessageStateReady
+	})).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", bm.ctx, due).Return()
+
+	next, err := bm.dispatchScheduledMessages()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Time(*future.SendTime), *next)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestDispatchScheduledMessagesFullPage(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+	bm.readPageSize = 1
+
+	due := newScheduledMessage(time.Now().Add(-1 * time.Second))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return([]*core.Message{due}, nil, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", due.Header.ID).Return(due, nil)
+	mdi.On("ReplaceMessage", bm.ctx, due).Return(nil)
+	mdm := bm.data.(*datamocks.Manager)
+	mdm.On("UpdateMessageIfCached", bm.ctx, due).Return()
+
+	next, err := bm.dispatchScheduledMessages()
+	assert.NoError(t, err)
+	assert.NotNil(t, next)
+
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestDispatchScheduledMessagesNone(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+
+	next, err := bm.dispatchScheduledMessages()
+	assert.NoError(t, err)
+	assert.Nil(t, next)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDispatchScheduledMessagesQueryFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := bm.dispatchScheduledMessages()
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDispatchScheduledMessagesReplaceFail(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+
+	due := newScheduledMessage(time.Now().Add(-1 * time.Second))
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return([]*core.Message{due}, nil, nil)
+	mdi.On("GetMessageByID", bm.ctx, "ns1", due.Header.ID).Return(due, nil)
+	mdi.On("ReplaceMessage", bm.ctx, due).Return(fmt.Errorf("pop"))
+
+	_, err := bm.dispatchScheduledMessages()
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMessageSchedulerCancelledContext(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	cancel()
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	bm.messageScheduler()
+	<-bm.schedulerDone
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMessageSchedulerWaitCancelled(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessages", bm.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil).Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	bm.messageScheduler()
+	<-bm.schedulerDone
+
+	mdi.AssertExpectations(t)
+}
+
+func TestWaitForScheduledMessagesNextSendTime(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+	bm.minimumPollDelay = 0
+	bm.messagePollTimeout = 1 * time.Minute
+
+	next := time.Now().Add(1 * time.Millisecond)
+	done := bm.waitForScheduledMessages(&next)
+	assert.False(t, done)
+}
+
+func TestWaitForScheduledMessagesTapped(t *testing.T) {
+	bm, cancel := newTestBatchManager(t)
+	defer cancel()
+	bm.minimumPollDelay = 0
+	bm.messagePollTimeout = 1 * time.Minute
+
+	bm.newMessageNotification(12345)
+	done := bm.waitForScheduledMessages(nil)
+	assert.False(t, done)
+}
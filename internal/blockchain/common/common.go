@@ -45,6 +45,8 @@ type BlockchainCallbacks interface {
 	PrepareBatchPinOrNetworkAction(ctx context.Context, events EventsToDispatch, subInfo *SubscriptionInfo, location *fftypes.JSONAny, event *blockchain.Event, signingKey *core.VerifierRef, params *BatchPinParams)
 	// Common logic for parsing a BatchPinOrNetworkAction event, and if not discarded to add it to the by-namespace map
 	PrepareBlockchainEvent(ctx context.Context, events EventsToDispatch, namespace string, event *blockchain.EventForListener)
+	// Common logic for routing the removal of a previously delivered event (such as by a chain reorganization) to the by-namespace map
+	PrepareBlockchainEventRemoved(ctx context.Context, events EventsToDispatch, namespace string, event *blockchain.Event)
 	// Dispatch logic, that ensures all the right namespace callbacks get called for the event batch
 	DispatchBlockchainEvents(ctx context.Context, events EventsToDispatch) error
 }
@@ -94,6 +96,9 @@ type BlockchainReceiptNotification struct {
 	Message          string                   `json:"errorMessage,omitempty"`
 	ProtocolID       string                   `json:"protocolId,omitempty"`
 	ContractLocation *fftypes.JSONAny         `json:"contractLocation,omitempty"`
+	PrivacyGroupID   string                   `json:"privacyGroupId,omitempty"`
+	UserOpHash       string                   `json:"userOpHash,omitempty"`
+	Sponsorship      string                   `json:"sponsorship,omitempty"`
 }
 
 func NewBlockchainCallbacks() BlockchainCallbacks {
@@ -231,22 +236,30 @@ func (cb *callbacks) addNetworkAction(ctx context.Context, events EventsToDispat
 }
 
 func (cb *callbacks) PrepareBlockchainEvent(ctx context.Context, events EventsToDispatch, namespace string, event *blockchain.EventForListener) {
+	cb.addToNamespace(ctx, events, namespace, &blockchain.EventToDispatch{
+		Type:        blockchain.EventTypeForListener,
+		ForListener: event,
+	})
+}
+
+func (cb *callbacks) PrepareBlockchainEventRemoved(ctx context.Context, events EventsToDispatch, namespace string, event *blockchain.Event) {
+	cb.addToNamespace(ctx, events, namespace, &blockchain.EventToDispatch{
+		Type:    blockchain.EventTypeRemoved,
+		Removed: event,
+	})
+}
+
+func (cb *callbacks) addToNamespace(ctx context.Context, events EventsToDispatch, namespace string, event *blockchain.EventToDispatch) {
 	cb.lock.RLock()
 	defer cb.lock.RUnlock()
 	if namespace == "" {
 		// Older subscriptions don't populate namespace, so deliver the event to every handler
 		for namespace := range cb.handlers {
-			events[namespace] = append(events[namespace], &blockchain.EventToDispatch{
-				Type:        blockchain.EventTypeForListener,
-				ForListener: event,
-			})
+			events[namespace] = append(events[namespace], event)
 		}
 	} else {
 		if _, ok := cb.handlers[namespace]; ok {
-			events[namespace] = append(events[namespace], &blockchain.EventToDispatch{
-				Type:        blockchain.EventTypeForListener,
-				ForListener: event,
-			})
+			events[namespace] = append(events[namespace], event)
 		} else {
 			log.L(ctx).Errorf("No handler found for blockchain event on namespace '%s'", namespace)
 		}
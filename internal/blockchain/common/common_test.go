@@ -99,6 +99,33 @@ func TestCallbackBlockchainEvent(t *testing.T) {
 	mcb.AssertExpectations(t)
 }
 
+func TestCallbackBlockchainEventRemoved(t *testing.T) {
+	event := &blockchain.Event{
+		ProtocolID: "012345",
+	}
+
+	mcb := &blockchainmocks.Callbacks{}
+	cb := NewBlockchainCallbacks()
+	cb.SetHandler("ns1", mcb)
+
+	mcb.On("BlockchainEventBatch", mock.MatchedBy(func(batch []*blockchain.EventToDispatch) bool {
+		return len(batch) == 1 &&
+			batch[0].Type == blockchain.EventTypeRemoved &&
+			batch[0].Removed.ProtocolID == "012345"
+	})).Return(nil).Twice()
+	events := make(EventsToDispatch)
+	cb.PrepareBlockchainEventRemoved(context.Background(), events, "ns1", event)
+	err := cb.DispatchBlockchainEvents(context.Background(), events)
+	assert.NoError(t, err)
+
+	events = make(EventsToDispatch)
+	cb.PrepareBlockchainEventRemoved(context.Background(), events, "", event)
+	err = cb.DispatchBlockchainEvents(context.Background(), events)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
 func TestCallbackBatchPinBadBatch(t *testing.T) {
 	event := &blockchain.Event{}
 	verifier := &core.VerifierRef{}
@@ -155,3 +155,40 @@ var networkVersionMethodABI = &abi.Entry{
 		},
 	},
 }
+
+var batchPinPackedMethodABI = &abi.Entry{
+	Name: "pinBatchPacked",
+	Type: "function",
+	Inputs: abi.ParameterArray{
+		{
+			InternalType: "bytes",
+			Name:         "data",
+			Type:         "bytes",
+		},
+	},
+}
+
+var batchPinPackedEventABI = &abi.Entry{
+	Name: "BatchPinPacked",
+	Type: "event",
+	Inputs: abi.ParameterArray{
+		{
+			Indexed:      false,
+			InternalType: "address",
+			Name:         "author",
+			Type:         "address",
+		},
+		{
+			Indexed:      false,
+			InternalType: "uint256",
+			Name:         "timestamp",
+			Type:         "uint256",
+		},
+		{
+			Indexed:      false,
+			InternalType: "bytes",
+			Name:         "data",
+			Type:         "bytes",
+		},
+	},
+}
@@ -0,0 +1,185 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/accountabstraction"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/coremocks"
+	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testSmartAccount = "0x2a7c9d5248681ce6c393117e641ad037f5c079f6"
+	testPaymaster    = "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+)
+
+func newTestAccountAbstractionConf(e *Ethereum) {
+	resetConf(e)
+	aaConf := utConfig.SubSection(AccountAbstractionConfigKey)
+	aaConf.Set(accountabstraction.ConfigSmartAccounts, []string{testSmartAccount})
+	aaConf.Set(accountabstraction.ConfigPaymaster, testPaymaster)
+}
+
+func TestInitAccountAbstractionFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	newTestAccountAbstractionConf(e)
+	utConfig.SubSection(AccountAbstractionConfigKey).Set(accountabstraction.ConfigSmartAccounts, []string{"bad"})
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestInvokeContractSmartAccount(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	newTestAccountAbstractionConf(e)
+	var err error
+	e.accountAbstraction, err = accountabstraction.New(context.Background(), utConfig.SubSection(AccountAbstractionConfigKey))
+	assert.NoError(t, err)
+
+	locationBytes, err := json.Marshal(&Location{Address: "0x12345"})
+	assert.NoError(t, err)
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			switch body["from"] {
+			case testSmartAccount:
+				assert.Equal(t, map[string]interface{}{
+					"entryPoint": "0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789",
+					"paymaster":  testPaymaster,
+				}, body["userOperation"])
+			default:
+				assert.NotContains(t, body, "userOperation")
+			}
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	err = e.InvokeContract(context.Background(), "", testSmartAccount, fftypes.JSONAnyPtrBytes(locationBytes), testFFIMethod(), params, testFFIErrors(), nil, nil)
+	assert.NoError(t, err)
+
+	err = e.InvokeContract(context.Background(), "", "0x01020304", fftypes.JSONAnyPtrBytes(locationBytes), testFFIMethod(), params, testFFIErrors(), nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestDeployContractSmartAccount(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	newTestAccountAbstractionConf(e)
+	var err error
+	e.accountAbstraction, err = accountabstraction.New(context.Background(), utConfig.SubSection(AccountAbstractionConfigKey))
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, map[string]interface{}{
+				"entryPoint": "0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789",
+				"paymaster":  testPaymaster,
+			}, body["userOperation"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	err = e.DeployContract(context.Background(), "", testSmartAccount, fftypes.JSONAnyPtr("[]"), fftypes.JSONAnyPtr(`"0x123456"`), []interface{}{}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestInvokeContractSmartAccountOverride(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	newTestAccountAbstractionConf(e)
+	var err error
+	e.accountAbstraction, err = accountabstraction.New(context.Background(), utConfig.SubSection(AccountAbstractionConfigKey))
+	assert.NoError(t, err)
+
+	locationBytes, err := json.Marshal(&Location{Address: "0x12345"})
+	assert.NoError(t, err)
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	options := map[string]interface{}{
+		"userOperation": map[string]interface{}{},
+	}
+	err = e.InvokeContract(context.Background(), "", testSmartAccount, fftypes.JSONAnyPtrBytes(locationBytes), testFFIMethod(), params, testFFIErrors(), options, nil)
+	assert.Regexp(t, "FF10398", err)
+}
+
+func TestHandleReceiptUserOperation(t *testing.T) {
+	em := &coremocks.OperationCallbacks{}
+	e := &Ethereum{
+		ctx:       context.Background(),
+		topic:     "topic1",
+		callbacks: common.NewBlockchainCallbacks(),
+		wsconn:    &wsmocks.WSClient{},
+	}
+	e.SetOperationHandler("ns1", em)
+
+	var reply common.BlockchainReceiptNotification
+	operationID := fftypes.NewUUID()
+	data := fftypes.JSONAnyPtr(`{
+		"headers": {
+			"requestId": "ns1:` + operationID.String() + `",
+			"type": "TransactionSuccess"
+		},
+		"transactionHash": "0x71a38acb7a5d4a970854f6d638ceb1fa10a4b59cbf4ed7674273a1a8dc8b36b8",
+		"userOpHash": "0x9a3d5b4e3e1c1ff4c3aa8d542c4eb7b2ee7a0fce91de2d6c530bf78b3a9cd8e1",
+		"sponsorship": "sponsored"
+	}`)
+
+	em.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == "ns1:"+operationID.String() &&
+			update.Status == core.OpStatusSucceeded &&
+			update.Output.GetString("userOpHash") == "0x9a3d5b4e3e1c1ff4c3aa8d542c4eb7b2ee7a0fce91de2d6c530bf78b3a9cd8e1" &&
+			update.Output.GetString("sponsorship") == "sponsored"
+	})).Return(nil)
+
+	err := json.Unmarshal(data.Bytes(), &reply)
+	assert.NoError(t, err)
+
+	common.HandleReceipt(context.Background(), e, &reply, e.callbacks)
+
+	em.AssertExpectations(t)
+}
@@ -0,0 +1,145 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+)
+
+const (
+	batchPinModeStandard = "standard"
+	batchPinModePacked   = "packed"
+)
+
+// packedBatchPinVersion is the first byte of every packed payload, so the format can evolve
+const packedBatchPinVersion byte = 1
+
+// packedBatchPinMinVersion is the first network version of the FireFly contract that implements pinBatchPacked
+const packedBatchPinMinVersion = 3
+
+// encodePackedBatchPin packs a batch pin into a single byte string, instead of the four separately
+// ABI encoded parameters of pinBatch. This avoids the word padding, offsets and lengths of the ABI
+// encoding, which dominate the calldata cost of small batches on L2 rollups. The layout is:
+//
+//	version (1) | uuids (32) | batchHash (32) | len(payloadRef) (2) | payloadRef | len(contexts) (2) | contexts (32 each)
+func encodePackedBatchPin(ctx context.Context, batch *blockchain.BatchPin) (string, error) {
+	if len(batch.BatchPayloadRef) > math.MaxUint16 || len(batch.Contexts) > math.MaxUint16 {
+		return "", i18n.NewError(ctx, coremsgs.MsgInvalidPackedBatchPin, "too large")
+	}
+
+	data := make([]byte, 0, 1+32+32+2+len(batch.BatchPayloadRef)+2+32*len(batch.Contexts))
+	data = append(data, packedBatchPinVersion)
+	data = append(data, (*batch.TransactionID)[:]...)
+	data = append(data, (*batch.BatchID)[:]...)
+	data = append(data, (*batch.BatchHash)[:]...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(batch.BatchPayloadRef)))
+	data = append(data, batch.BatchPayloadRef...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(batch.Contexts)))
+	for _, c := range batch.Contexts {
+		data = append(data, c[:]...)
+	}
+	return "0x" + hex.EncodeToString(data), nil
+}
+
+// decodePackedBatchPin unpacks the data of a BatchPinPacked event into the same parameters that are
+// parsed from a standard BatchPin event, so the rest of the event path is shared
+func decodePackedBatchPin(ctx context.Context, packed string) (*common.BatchPinParams, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(packed, "0x"))
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidPackedBatchPin, err)
+	}
+
+	var pos int
+	read := func(n int) ([]byte, bool) {
+		if pos+n > len(data) {
+			return nil, false
+		}
+		b := data[pos : pos+n]
+		pos += n
+		return b, true
+	}
+	readHex := func(n int) (string, bool) {
+		b, ok := read(n)
+		return "0x" + hex.EncodeToString(b), ok
+	}
+	readLen := func() (int, bool) {
+		b, ok := read(2)
+		if !ok {
+			return 0, false
+		}
+		return int(binary.BigEndian.Uint16(b)), true
+	}
+	truncated := i18n.NewError(ctx, coremsgs.MsgInvalidPackedBatchPin, "truncated")
+
+	version, ok := read(1)
+	if !ok {
+		return nil, truncated
+	}
+	if version[0] != packedBatchPinVersion {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidPackedBatchPin, "unknown version")
+	}
+
+	params := &common.BatchPinParams{}
+	if params.UUIDs, ok = readHex(32); !ok {
+		return nil, truncated
+	}
+	if params.BatchHash, ok = readHex(32); !ok {
+		return nil, truncated
+	}
+	payloadRefLen, ok := readLen()
+	if !ok {
+		return nil, truncated
+	}
+	payloadRef, ok := read(payloadRefLen)
+	if !ok {
+		return nil, truncated
+	}
+	params.PayloadRef = string(payloadRef)
+	contextCount, ok := readLen()
+	if !ok {
+		return nil, truncated
+	}
+	params.Contexts = make([]string, contextCount)
+	for i := range params.Contexts {
+		if params.Contexts[i], ok = readHex(32); !ok {
+			return nil, truncated
+		}
+	}
+	if pos != len(data) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidPackedBatchPin, "trailing data")
+	}
+	return params, nil
+}
+
+// checkPackedBatchPinVersion rejects packed mode for versions of the FireFly contract that do not implement
+// pinBatchPacked. Otherwise every batch pin would fail, and the BatchPinPacked events that are subscribed to
+// would never be emitted.
+func checkPackedBatchPinVersion(ctx context.Context, version int) error {
+	if version < packedBatchPinMinVersion {
+		return i18n.NewError(ctx, coremsgs.MsgPackedBatchPinRequiresV3, version)
+	}
+	return nil
+}
@@ -0,0 +1,427 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testPackedBatchPin() *blockchain.BatchPin {
+	return &blockchain.BatchPin{
+		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
+		BatchHash:       fftypes.MustParseBytes32("d71eb138d74c229a388eb0e1abc03f4c7cbb21d4fc4b839fbf0ec73e4263f6be"),
+		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts: []*fftypes.Bytes32{
+			fftypes.MustParseBytes32("68e4da79f805bca5b912bcda9c63d03e6e867108dabb9b944109aea541ef522a"),
+			fftypes.MustParseBytes32("19b82093de5ce92a01e333048e877e2374354bf846dd034864ef6ffbd6438771"),
+		},
+	}
+}
+
+func TestPackedBatchPinRoundTrip(t *testing.T) {
+	batch := testPackedBatchPin()
+	packed, err := encodePackedBatchPin(context.Background(), batch)
+	assert.NoError(t, err)
+	// 1 version + 32 uuids + 32 hash + 2 + 46 payloadRef + 2 + 2*32 contexts
+	assert.Len(t, packed, 2+2*179)
+
+	params, err := decodePackedBatchPin(context.Background(), packed)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", params.UUIDs)
+	assert.Equal(t, "0xd71eb138d74c229a388eb0e1abc03f4c7cbb21d4fc4b839fbf0ec73e4263f6be", params.BatchHash)
+	assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", params.PayloadRef)
+	assert.Equal(t, []string{
+		"0x68e4da79f805bca5b912bcda9c63d03e6e867108dabb9b944109aea541ef522a",
+		"0x19b82093de5ce92a01e333048e877e2374354bf846dd034864ef6ffbd6438771",
+	}, params.Contexts)
+	assert.Empty(t, params.NsOrAction)
+}
+
+func TestPackedBatchPinNoPayloadRef(t *testing.T) {
+	batch := testPackedBatchPin()
+	batch.BatchPayloadRef = ""
+	packed, err := encodePackedBatchPin(context.Background(), batch)
+	assert.NoError(t, err)
+
+	params, err := decodePackedBatchPin(context.Background(), packed)
+	assert.NoError(t, err)
+	assert.Empty(t, params.PayloadRef)
+	assert.Len(t, params.Contexts, 2)
+}
+
+func TestEncodePackedBatchPinTooLarge(t *testing.T) {
+	batch := testPackedBatchPin()
+	batch.BatchPayloadRef = strings.Repeat("a", 65536)
+	_, err := encodePackedBatchPin(context.Background(), batch)
+	assert.Regexp(t, "FF10465.*too large", err)
+}
+
+func TestDecodePackedBatchPinErrors(t *testing.T) {
+	packed, err := encodePackedBatchPin(context.Background(), testPackedBatchPin())
+	assert.NoError(t, err)
+
+	_, err = decodePackedBatchPin(context.Background(), "0xZZ")
+	assert.Regexp(t, "FF10465", err)
+
+	_, err = decodePackedBatchPin(context.Background(), "0x")
+	assert.Regexp(t, "FF10465.*truncated", err)
+
+	_, err = decodePackedBatchPin(context.Background(), "0x02"+packed[4:])
+	assert.Regexp(t, "FF10465.*unknown version", err)
+
+	_, err = decodePackedBatchPin(context.Background(), packed+"00")
+	assert.Regexp(t, "FF10465.*trailing data", err)
+
+	// Every truncation point must be detected
+	for _, l := range []int{4, 66, 130, 132, 150, 224, 226, 290} {
+		_, err = decodePackedBatchPin(context.Background(), packed[0:l])
+		assert.Regexp(t, "FF10465.*truncated", err, l)
+	}
+}
+
+func TestInitBadBatchPinMode(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+	utConfig.SubSection(BatchPinConfigKey).Set(BatchPinMode, "wrong")
+
+	cmi := &cachemocks.Manager{}
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.Regexp(t, "FF10463.*wrong", err)
+}
+
+func TestSubmitBatchPinPacked(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.batchPinPacked = true
+
+	batch := testPackedBatchPin()
+	packed, err := encodePackedBatchPin(context.Background(), batch)
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			res, err := mockNetworkVersion(t, 3)(req)
+			if res != nil || err != nil {
+				return res, err
+			}
+
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			method := body["method"].(map[string]interface{})
+			params := body["params"].([]interface{})
+			assert.Equal(t, "pinBatchPacked", method["name"])
+			assert.Equal(t, []interface{}{packed}, params)
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+	err = e.SubmitBatchPin(context.Background(), "", "ns1", "0x91d2b4381a4cd5c7c0f27565a7d4b829844c8635", batch, location)
+	assert.NoError(t, err)
+}
+
+func TestSubmitBatchPinPackedV2(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.batchPinPacked = true
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`, mockNetworkVersion(t, 2))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+	err := e.SubmitBatchPin(context.Background(), "", "ns1", "0x91d2b4381a4cd5c7c0f27565a7d4b829844c8635", testPackedBatchPin(), location)
+	assert.Regexp(t, "FF10464", err)
+}
+
+func TestSubmitBatchPinPackedTooLarge(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.batchPinPacked = true
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`, mockNetworkVersion(t, 3))
+
+	batch := testPackedBatchPin()
+	batch.BatchPayloadRef = strings.Repeat("a", 65536)
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+	err := e.SubmitBatchPin(context.Background(), "", "ns1", "0x91d2b4381a4cd5c7c0f27565a7d4b829844c8635", batch, location)
+	assert.Regexp(t, "FF10465", err)
+}
+
+func initPackedSubscriptionTest(t *testing.T, e *Ethereum, version int) {
+	resetConf(e)
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			event := body["event"].(map[string]interface{})
+			return httpmock.NewJsonResponderOrPanic(200, subscription{
+				ID: event["name"].(string),
+			})(req)
+		})
+	httpmock.RegisterResponder("POST", "http://localhost:12345/", mockNetworkVersion(t, version))
+
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utConfig.SubSection(BatchPinConfigKey).Set(BatchPinMode, batchPinModePacked)
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+	assert.True(t, e.batchPinPacked)
+}
+
+func TestAddFireflySubscriptionPacked(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	initPackedSubscriptionTest(t, e, 3)
+	defer httpmock.DeactivateAndReset()
+
+	contract := &blockchain.MultipartyContract{
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": "0x123",
+		}.String()),
+		FirstEvent: "newest",
+	}
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	subID, err := e.AddFireflySubscription(e.ctx, ns, contract)
+	assert.NoError(t, err)
+	assert.Equal(t, "BatchPin", subID)
+
+	subInfo := e.subs.GetSubscription("BatchPin")
+	assert.Equal(t, "BatchPinPacked", subInfo.Extra)
+	packedSubInfo := e.subs.GetSubscription("BatchPinPacked")
+	assert.Equal(t, "ns1", packedSubInfo.V2Namespace)
+
+	e.RemoveFireflySubscription(e.ctx, subID)
+	assert.Nil(t, e.subs.GetSubscription("BatchPin"))
+	assert.Nil(t, e.subs.GetSubscription("BatchPinPacked"))
+}
+
+func TestAddFireflySubscriptionPackedV1(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	initPackedSubscriptionTest(t, e, 1)
+	defer httpmock.DeactivateAndReset()
+
+	contract := &blockchain.MultipartyContract{
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": "0x123",
+		}.String()),
+		FirstEvent: "newest",
+	}
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	_, err := e.AddFireflySubscription(e.ctx, ns, contract)
+	assert.Regexp(t, "FF10464", err)
+}
+
+func TestAddFireflySubscriptionPackedV2(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	initPackedSubscriptionTest(t, e, 2)
+	defer httpmock.DeactivateAndReset()
+
+	contract := &blockchain.MultipartyContract{
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": "0x123",
+		}.String()),
+		FirstEvent: "newest",
+	}
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	_, err := e.AddFireflySubscription(e.ctx, ns, contract)
+	assert.Regexp(t, "FF10464.*version 2", err)
+	assert.Nil(t, e.subs.GetSubscription("BatchPin"))
+}
+
+func TestAddFireflySubscriptionPackedCreateError(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	initPackedSubscriptionTest(t, e, 3)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			event := body["event"].(map[string]interface{})
+			if event["name"] == "BatchPinPacked" {
+				return httpmock.NewJsonResponderOrPanic(500, "pop")(req)
+			}
+			return httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1"})(req)
+		})
+
+	contract := &blockchain.MultipartyContract{
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": "0x123",
+		}.String()),
+		FirstEvent: "newest",
+	}
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	_, err := e.AddFireflySubscription(e.ctx, ns, contract)
+	assert.Regexp(t, "FF10111", err)
+}
+
+func TestHandleMessageBatchPinPacked(t *testing.T) {
+	packed, err := encodePackedBatchPin(context.Background(), testPackedBatchPin())
+	assert.NoError(t, err)
+
+	data := fftypes.JSONAnyPtr(`
+[
+  {
+		"address": "0x1C197604587F046FD40684A8f21f4609FB811A7b",
+		"blockNumber": "38011",
+		"transactionIndex": "0x0",
+		"transactionHash": "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628",
+		"data": {
+			"author": "0X91D2B4381A4CD5C7C0F27565A7D4B829844C8635",
+			"timestamp": "1620576488",
+			"data": "` + packed + `"
+		},
+		"subId": "sb-packed",
+		"signature": "0x1C197604587F046FD40684A8f21f4609FB811A7b:BatchPinPacked(address,uint256,bytes)",
+		"logIndex": "50",
+		"timestamp": "1620576488"
+  },
+  {
+		"address": "0x1C197604587F046FD40684A8f21f4609FB811A7b",
+		"blockNumber": "38011",
+		"transactionIndex": "0x1",
+		"transactionHash": "0x0c50dff0893e795293189d9cc5ba0d63c4020d8758ace4a69d02c9d6d43cb695",
+		"data": {
+			"author": "0x91d2b4381a4cd5c7c0f27565a7d4b829844c8635",
+			"timestamp": "1620576488",
+			"data": "0x02"
+		},
+		"subId": "sb-packed",
+		"signature": "BatchPinPacked(address,uint256,bytes)",
+		"logIndex": "51",
+		"timestamp": "1620576488"
+  }
+]`)
+
+	em := &blockchainmocks.Callbacks{}
+	e := &Ethereum{
+		callbacks: common.NewBlockchainCallbacks(),
+		subs:      common.NewFireflySubscriptions(),
+	}
+	e.SetHandler("ns1", em)
+	e.subs.AddSubscription(
+		context.Background(),
+		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
+		2, "sb-packed", nil,
+	)
+
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(events []*blockchain.EventToDispatch) bool {
+		return len(events) == 1 &&
+			events[0].Type == blockchain.EventTypeBatchPinComplete
+	})).Return(nil)
+
+	var events []interface{}
+	err = json.Unmarshal(data.Bytes(), &events)
+	assert.NoError(t, err)
+	err = e.handleMessageBatch(context.Background(), 0, events)
+	assert.NoError(t, err)
+
+	b := em.Calls[0].Arguments[0].([]*blockchain.EventToDispatch)[0].BatchPinComplete
+	assert.Equal(t, "ns1", b.Namespace)
+	assert.Equal(t, core.TransactionTypeBatchPin, b.Batch.TransactionType)
+	assert.Equal(t, "9ffc50ff-6bfe-4502-adc7-93aea54cc059", b.Batch.TransactionID.String())
+	assert.Equal(t, "c5df767c-fe44-4e03-8eb5-1c5523097db5", b.Batch.BatchID.String())
+	assert.Equal(t, "d71eb138d74c229a388eb0e1abc03f4c7cbb21d4fc4b839fbf0ec73e4263f6be", b.Batch.BatchHash.String())
+	assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", b.Batch.BatchPayloadRef)
+	assert.Equal(t, "0x91d2b4381a4cd5c7c0f27565a7d4b829844c8635", b.SigningKey.Value)
+	assert.Len(t, b.Batch.Contexts, 2)
+	assert.Equal(t, "68e4da79f805bca5b912bcda9c63d03e6e867108dabb9b944109aea541ef522a", b.Batch.Contexts[0].String())
+
+	em.AssertExpectations(t)
+}
+
+func TestHandleMessageBatchPinPackedBadEvent(t *testing.T) {
+	data := fftypes.JSONAnyPtr(`
+[
+  {
+		"address": "0x1C197604587F046FD40684A8f21f4609FB811A7b",
+		"blockNumber": "38011",
+		"transactionIndex": "0x0",
+		"transactionHash": "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628",
+		"subId": "sb-packed",
+		"signature": "BatchPinPacked(address,uint256,bytes)",
+		"logIndex": "50",
+		"timestamp": "1620576488"
+  }
+]`)
+
+	em := &blockchainmocks.Callbacks{}
+	e := &Ethereum{
+		callbacks: common.NewBlockchainCallbacks(),
+		subs:      common.NewFireflySubscriptions(),
+	}
+	e.SetHandler("ns1", em)
+	e.subs.AddSubscription(
+		context.Background(),
+		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
+		2, "sb-packed", nil,
+	)
+
+	var events []interface{}
+	err := json.Unmarshal(data.Bytes(), &events)
+	assert.NoError(t, err)
+	err = e.handleMessageBatch(context.Background(), 0, events)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+}
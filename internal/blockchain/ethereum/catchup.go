@@ -0,0 +1,145 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// catchupTracker manages the catch-up phase after each connect to the connector, while the connector
+// replays the events missed during downtime. The connector delivers full batches while it has a backlog,
+// so the first partial batch marks the end of catch-up. During catch-up, event batches are spaced
+// out by a minimum interval so aggregation does not overwhelm the database.
+//
+// Each listener on the stream is replayed by the connector from its own checkpoint, so progress
+// is also tracked per listener, starting from the checkpoint block when catch-up began.
+type catchupTracker struct {
+	lock      sync.Mutex
+	batchSize int
+	interval  time.Duration
+	status    core.BlockchainCatchupStatus
+	listeners map[string]*core.BlockchainCatchupListenerStatus
+	lastBatch time.Time
+}
+
+func newCatchupTracker(batchSize uint, interval time.Duration) *catchupTracker {
+	return &catchupTracker{
+		batchSize: int(batchSize),
+		interval:  interval,
+	}
+}
+
+func (c *catchupTracker) start(ctx context.Context, subs []*subscription) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	log.L(ctx).Infof("Starting catch-up of events from connector for %d listeners", len(subs))
+	c.status = core.BlockchainCatchupStatus{
+		Active:    true,
+		Started:   fftypes.Now(),
+		Listeners: make([]*core.BlockchainCatchupListenerStatus, 0, len(subs)),
+	}
+	c.listeners = make(map[string]*core.BlockchainCatchupListenerStatus, len(subs))
+	for _, sub := range subs {
+		log.L(ctx).Debugf("Listener '%s' (%s) replaying from checkpoint block %d", sub.Name, sub.ID, sub.Checkpoint.Block)
+		listener := &core.BlockchainCatchupListenerStatus{
+			BackendID: sub.ID,
+			Name:      sub.Name,
+			FromBlock: sub.Checkpoint.Block,
+			LastBlock: sub.Checkpoint.Block,
+		}
+		c.listeners[sub.ID] = listener
+		c.status.Listeners = append(c.status.Listeners, listener)
+	}
+}
+
+// throttle blocks until the minimum interval since the last batch has passed, if catch-up is active
+func (c *catchupTracker) throttle(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	delay := time.Until(c.lastBatch.Add(c.interval))
+	active := c.status.Active
+	c.lock.Unlock()
+	if !active || delay <= 0 {
+		return
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(delay):
+	}
+}
+
+func (c *catchupTracker) batchProcessed(ctx context.Context, events []interface{}) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.lastBatch = time.Now()
+	if !c.status.Active {
+		return
+	}
+	c.status.Events += int64(len(events))
+	for _, event := range events {
+		if msgMap, ok := event.(map[string]interface{}); ok {
+			msgJSON := fftypes.JSONObject(msgMap)
+			blockNumber, err := strconv.ParseInt(msgJSON.GetString("blockNumber"), 10, 64)
+			if err == nil && blockNumber > c.status.LastBlock {
+				c.status.LastBlock = blockNumber
+			}
+			if listener, ok := c.listeners[msgJSON.GetString("subId")]; ok {
+				listener.Events++
+				if err == nil && blockNumber > listener.LastBlock {
+					listener.LastBlock = blockNumber
+				}
+			}
+		}
+	}
+	if len(events) < c.batchSize {
+		c.status.Active = false
+		c.status.Completed = fftypes.Now()
+		log.L(ctx).Infof("Catch-up of events from connector complete: events=%d lastBlock=%d", c.status.Events, c.status.LastBlock)
+	}
+}
+
+func (c *catchupTracker) getStatus() *core.BlockchainCatchupStatus {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	status := c.status
+	if c.status.Listeners != nil {
+		status.Listeners = make([]*core.BlockchainCatchupListenerStatus, len(c.status.Listeners))
+		for i, listener := range c.status.Listeners {
+			listenerCopy := *listener
+			status.Listeners[i] = &listenerCopy
+		}
+	}
+	return &status
+}
@@ -0,0 +1,197 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func testCatchupEvents(blockNumbers ...string) []interface{} {
+	events := make([]interface{}, len(blockNumbers))
+	for i, b := range blockNumbers {
+		events[i] = map[string]interface{}{"blockNumber": b}
+	}
+	return events
+}
+
+func TestCatchupTrackerLifecycle(t *testing.T) {
+	c := newCatchupTracker(2, 0)
+	assert.False(t, c.getStatus().Active)
+
+	c.start(context.Background(), nil)
+	status := c.getStatus()
+	assert.True(t, status.Active)
+	assert.NotNil(t, status.Started)
+	assert.Nil(t, status.Completed)
+
+	// A full batch means the connector still has a backlog
+	c.batchProcessed(context.Background(), testCatchupEvents("100", "101"))
+	status = c.getStatus()
+	assert.True(t, status.Active)
+	assert.Equal(t, int64(2), status.Events)
+	assert.Equal(t, int64(101), status.LastBlock)
+
+	// A partial batch ends catch-up
+	c.batchProcessed(context.Background(), []interface{}{"bad"})
+	status = c.getStatus()
+	assert.False(t, status.Active)
+	assert.NotNil(t, status.Completed)
+	assert.Equal(t, int64(3), status.Events)
+	assert.Equal(t, int64(101), status.LastBlock)
+
+	// Batches after catch-up are not counted
+	c.batchProcessed(context.Background(), testCatchupEvents("102"))
+	assert.Equal(t, int64(3), c.getStatus().Events)
+
+	// Reconnecting starts a new catch-up
+	c.start(context.Background(), nil)
+	status = c.getStatus()
+	assert.True(t, status.Active)
+	assert.Zero(t, status.Events)
+}
+
+func TestCatchupTrackerThrottle(t *testing.T) {
+	c := newCatchupTracker(1, 50*time.Millisecond)
+	c.start(context.Background(), nil)
+	c.batchProcessed(context.Background(), testCatchupEvents("1"))
+	c.batchProcessed(context.Background(), testCatchupEvents())
+	assert.False(t, c.getStatus().Active)
+
+	// No throttling once catch-up is complete
+	startTime := time.Now()
+	c.throttle(context.Background())
+	assert.Less(t, time.Since(startTime), 50*time.Millisecond)
+
+	c.start(context.Background(), nil)
+	c.batchProcessed(context.Background(), testCatchupEvents("1"))
+	startTime = time.Now()
+	c.throttle(context.Background())
+	assert.GreaterOrEqual(t, time.Since(startTime), 40*time.Millisecond)
+}
+
+func TestCatchupTrackerThrottleContextCancelled(t *testing.T) {
+	c := newCatchupTracker(1, 1*time.Hour)
+	c.start(context.Background(), nil)
+	c.batchProcessed(context.Background(), testCatchupEvents("1"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.throttle(ctx)
+	assert.True(t, c.getStatus().Active)
+}
+
+func TestCatchupTrackerNil(t *testing.T) {
+	var c *catchupTracker
+	c.start(context.Background(), nil)
+	c.throttle(context.Background())
+	c.batchProcessed(context.Background(), testCatchupEvents("1"))
+	assert.Nil(t, c.getStatus())
+}
+
+func TestCatchupTrackerListeners(t *testing.T) {
+	c := newCatchupTracker(3, 0)
+	c.start(context.Background(), []*subscription{
+		{ID: "sub1", Name: "listener1", subscriptionCheckpoint: subscriptionCheckpoint{Checkpoint: ListenerCheckpoint{Block: 90}}},
+		{ID: "sub2", Name: "listener2", subscriptionCheckpoint: subscriptionCheckpoint{Checkpoint: ListenerCheckpoint{Block: 120}}},
+	})
+	status := c.getStatus()
+	assert.Len(t, status.Listeners, 2)
+	assert.Equal(t, int64(90), status.Listeners[0].FromBlock)
+	assert.Equal(t, int64(120), status.Listeners[1].LastBlock)
+
+	c.batchProcessed(context.Background(), []interface{}{
+		map[string]interface{}{"subId": "sub1", "blockNumber": "95"},
+		map[string]interface{}{"subId": "sub1", "blockNumber": "bad"},
+		map[string]interface{}{"subId": "unknown", "blockNumber": "200"},
+	})
+	status = c.getStatus()
+	assert.True(t, status.Active)
+	assert.Equal(t, int64(200), status.LastBlock)
+	assert.Equal(t, &core.BlockchainCatchupListenerStatus{
+		BackendID: "sub1",
+		Name:      "listener1",
+		FromBlock: 90,
+		LastBlock: 95,
+		Events:    2,
+	}, status.Listeners[0])
+	assert.Equal(t, &core.BlockchainCatchupListenerStatus{
+		BackendID: "sub2",
+		Name:      "listener2",
+		FromBlock: 120,
+		LastBlock: 120,
+	}, status.Listeners[1])
+
+	// The returned status is a copy
+	status.Listeners[0].Events = 100
+	assert.Equal(t, int64(2), c.getStatus().Listeners[0].Events)
+}
+
+func TestAfterConnectStartsCatchup(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	e.catchup = newCatchupTracker(50, 0)
+	e.streamID = "es12345"
+	e.streams = newTestStreamManager(e.client)
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []*subscription{
+			{ID: "sub1", Stream: "es12345", Name: "listener1", subscriptionCheckpoint: subscriptionCheckpoint{Checkpoint: ListenerCheckpoint{Block: 42}}},
+			{ID: "sub2", Stream: "es-other", Name: "listener2"},
+		}))
+
+	wsm := &wsmocks.WSClient{}
+	wsm.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	err := e.afterConnect(e.ctx, wsm)
+	assert.NoError(t, err)
+	status := e.catchup.getStatus()
+	assert.True(t, status.Active)
+	assert.Len(t, status.Listeners, 1)
+	assert.Equal(t, "sub1", status.Listeners[0].BackendID)
+	assert.Equal(t, int64(42), status.Listeners[0].FromBlock)
+}
+
+func TestAfterConnectCatchupSubscriptionsFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	e.catchup = newCatchupTracker(50, 0)
+	e.streams = newTestStreamManager(e.client)
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewStringResponder(500, "pop"))
+
+	wsm := &wsmocks.WSClient{}
+	wsm.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	err := e.afterConnect(e.ctx, wsm)
+	assert.NoError(t, err)
+	status := e.catchup.getStatus()
+	assert.True(t, status.Active)
+	assert.Empty(t, status.Listeners)
+}
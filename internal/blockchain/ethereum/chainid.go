@@ -0,0 +1,140 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// chainIDVerifier tracks the chain IDs pinned on the multiparty contracts of each namespace, and
+// verifies them against the chain the connector is connected to. This prevents events from a
+// different environment being processed, after a misconfiguration of the connector endpoint.
+type chainIDVerifier struct {
+	client *resty.Client
+	path   string
+	lock   sync.Mutex
+	// expected chain ID, and namespace, for each FireFly subscription that has a pinned chain ID
+	expected map[string]pinnedChainID
+}
+
+type pinnedChainID struct {
+	namespace string
+	chainID   string
+}
+
+func newChainIDVerifier(client *resty.Client, path string) *chainIDVerifier {
+	return &chainIDVerifier{
+		client:   client,
+		path:     path,
+		expected: make(map[string]pinnedChainID),
+	}
+}
+
+func (cv *chainIDVerifier) getChainID(ctx context.Context) (string, error) {
+	var resErr ethError
+	var status map[string]interface{}
+	res, err := cv.client.R().
+		SetContext(ctx).
+		SetError(&resErr).
+		SetResult(&status).
+		Get(cv.path)
+	if err != nil || !res.IsSuccess() {
+		return "", wrapError(ctx, &resErr, res, err)
+	}
+	switch chainID := status["chainId"].(type) {
+	case string:
+		if chainID != "" {
+			return chainID, nil
+		}
+	case float64:
+		return strconv.FormatFloat(chainID, 'f', 0, 64), nil
+	}
+	return "", i18n.NewError(ctx, coremsgs.MsgChainIDUnavailable, cv.path)
+}
+
+func chainIDMatches(expected, actual string) bool {
+	// Chain IDs may be reported in decimal or hex form
+	normalize := func(s string) string {
+		if strings.HasPrefix(s, "0x") {
+			if n, err := strconv.ParseUint(s[2:], 16, 64); err == nil {
+				return strconv.FormatUint(n, 10)
+			}
+		}
+		return s
+	}
+	return normalize(expected) == normalize(actual)
+}
+
+// pin verifies the chain ID for a namespace contract, and records it for verification on reconnect
+func (cv *chainIDVerifier) pin(ctx context.Context, subID, namespace, chainID string) error {
+	actual, err := cv.getChainID(ctx)
+	if err != nil {
+		return err
+	}
+	if !chainIDMatches(chainID, actual) {
+		return i18n.NewError(ctx, coremsgs.MsgChainIDMismatch, actual, namespace, chainID)
+	}
+	cv.lock.Lock()
+	defer cv.lock.Unlock()
+	cv.expected[subID] = pinnedChainID{namespace: namespace, chainID: chainID}
+	return nil
+}
+
+func (cv *chainIDVerifier) unpin(subID string) {
+	if cv == nil {
+		return
+	}
+	cv.lock.Lock()
+	defer cv.lock.Unlock()
+	delete(cv.expected, subID)
+}
+
+// verifyAll checks all pinned chain IDs, and is called on every reconnect before listening for events
+func (cv *chainIDVerifier) verifyAll(ctx context.Context) error {
+	if cv == nil {
+		return nil
+	}
+	cv.lock.Lock()
+	pinned := make([]pinnedChainID, 0, len(cv.expected))
+	for _, p := range cv.expected {
+		pinned = append(pinned, p)
+	}
+	cv.lock.Unlock()
+	if len(pinned) == 0 {
+		return nil
+	}
+
+	actual, err := cv.getChainID(ctx)
+	if err != nil {
+		return err
+	}
+	for _, p := range pinned {
+		if !chainIDMatches(p.chainID, actual) {
+			log.L(ctx).Errorf("Refusing to process events: connector chain ID '%s' does not match '%s' for namespace '%s'", actual, p.chainID, p.namespace)
+			return i18n.NewError(ctx, coremsgs.MsgChainIDMismatch, actual, p.namespace, p.chainID)
+		}
+	}
+	return nil
+}
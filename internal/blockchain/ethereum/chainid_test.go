@@ -0,0 +1,186 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetChainID(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	cv := newChainIDVerifier(e.client, "/status")
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"chainId": 1337}))
+	chainID, err := cv.getChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "1337", chainID)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"chainId": "0x539"}))
+	chainID, err = cv.getChainID(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "0x539", chainID)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"ok": true}))
+	_, err = cv.getChainID(context.Background())
+	assert.Regexp(t, "FF10476", err)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(500, ethError{Error: "pop"}))
+	_, err = cv.getChainID(context.Background())
+	assert.Regexp(t, "FF10111.*pop", err)
+}
+
+func TestChainIDMatches(t *testing.T) {
+	assert.True(t, chainIDMatches("1337", "1337"))
+	assert.True(t, chainIDMatches("1337", "0x539"))
+	assert.True(t, chainIDMatches("0x539", "1337"))
+	assert.False(t, chainIDMatches("1", "1337"))
+	assert.False(t, chainIDMatches("0xzz", "1337"))
+}
+
+func TestChainIDVerifyAll(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	cv := newChainIDVerifier(e.client, "/status")
+
+	// Nothing pinned, so no need to call the connector
+	assert.NoError(t, cv.verifyAll(context.Background()))
+	assert.Zero(t, httpmock.GetTotalCallCount())
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"chainId": "1337"}))
+	err := cv.pin(context.Background(), "sub1", "ns1", "1337")
+	assert.NoError(t, err)
+	assert.NoError(t, cv.verifyAll(context.Background()))
+
+	// The connector is now connected to a different chain
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"chainId": "1"}))
+	err = cv.verifyAll(context.Background())
+	assert.Regexp(t, "FF10475.*'1'.*ns1.*'1337'", err)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewStringResponder(500, "pop"))
+	err = cv.verifyAll(context.Background())
+	assert.Regexp(t, "FF10111", err)
+
+	cv.unpin("sub1")
+	assert.NoError(t, cv.verifyAll(context.Background()))
+}
+
+func TestChainIDVerifierNil(t *testing.T) {
+	var cv *chainIDVerifier
+	cv.unpin("sub1")
+	assert.NoError(t, cv.verifyAll(context.Background()))
+}
+
+func TestAfterConnectChainIDMismatch(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.chainIDs = newChainIDVerifier(e.client, "/status")
+	e.chainIDs.expected["sub1"] = pinnedChainID{namespace: "ns1", chainID: "1337"}
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"chainId": "1"}))
+
+	wsm := &wsmocks.WSClient{}
+	err := e.afterConnect(e.ctx, wsm)
+	assert.Regexp(t, "FF10475", err)
+	wsm.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestAddFireflySubscriptionChainID(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, subscription{
+			ID: "sub1",
+		}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/", mockNetworkVersion(t, 2))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"chainId": "1337"}))
+
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	_, err = e.AddFireflySubscription(e.ctx, ns, &blockchain.MultipartyContract{
+		Location:   location,
+		FirstEvent: "newest",
+		ChainID:    "1",
+	})
+	assert.Regexp(t, "FF10475", err)
+	assert.Nil(t, e.subs.GetSubscription("sub1"))
+
+	_, err = e.AddFireflySubscription(e.ctx, ns, &blockchain.MultipartyContract{
+		Location:   location,
+		FirstEvent: "newest",
+		ChainID:    "1337",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, e.subs.GetSubscription("sub1"))
+	assert.Equal(t, "1337", e.chainIDs.expected["sub1"].chainID)
+
+	e.RemoveFireflySubscription(e.ctx, "sub1")
+	assert.Empty(t, e.chainIDs.expected)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/accountabstraction"
 )
 
 const (
@@ -35,6 +36,17 @@ const (
 	defaultBackgroundInitialDelay = "5s"
 	defaultBackgroundRetryFactor  = 2.0
 	defaultBackgroundMaxDelay     = "1m"
+
+	defaultFailoverHealthCheckPath     = "/status"
+	defaultFailoverHealthCheckInterval = "30s"
+
+	defaultCatchupBatchInterval = "100ms"
+
+	defaultChainIDPath = "/status"
+
+	defaultENSRegistry = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+	defaultBatchPinMode = batchPinModeStandard
 )
 
 const (
@@ -63,6 +75,20 @@ const (
 	EthconnectBackgroundStartMaxDelay = "backgroundStart.maxDelay"
 	// EthconnectBackgroundStartFactor is to set the factor by which the delay increases when retrying
 	EthconnectBackgroundStartFactor = "backgroundStart.factor"
+	// EthconnectFailoverURLs is a list of additional connector URLs, to fail over to when the primary URL is unhealthy
+	EthconnectFailoverURLs = "failover.urls"
+	// EthconnectFailoverHealthCheckPath is the HTTP path used to check the health of each connector URL
+	EthconnectFailoverHealthCheckPath = "failover.healthCheckPath"
+	// EthconnectFailoverHealthCheckInterval is how often the active connector URL is checked for health, when failover URLs are configured
+	EthconnectFailoverHealthCheckInterval = "failover.healthCheckInterval"
+	// EthconnectCatchupBatchInterval is the minimum time between event batches while catching up on events after connecting
+	EthconnectCatchupBatchInterval = "catchup.batchInterval"
+	// EthconnectQueryQuorumURLs is a list of additional independent connector URLs, that contract queries are fanned out to
+	EthconnectQueryQuorumURLs = "queryQuorum.urls"
+	// EthconnectChainIDPath is the HTTP path on the connector that reports the chain ID, used to verify the chain ID pinned on namespace contracts
+	EthconnectChainIDPath = "chainIdPath"
+	// EthconnectQueryQuorumMinMatching is the number of connector endpoints that must return matching results for a contract query
+	EthconnectQueryQuorumMinMatching = "queryQuorum.minMatching"
 
 	// AddressResolverConfigKey is a sub-key in the config to contain an address resolver config.
 	AddressResolverConfigKey = "addressResolver"
@@ -79,6 +105,21 @@ const (
 	// AddressResolverResponseField the name of a JSON field that is provided in the response, that contains the ethereum address (default "address")
 	AddressResolverResponseField = "responseField"
 
+	// ENSConfigKey is a sub-key in the config to contain the ENS name resolution config
+	ENSConfigKey = "ens"
+	// ENSEnabled enables resolution of ENS names (such as "alice.eth") wherever an address is accepted
+	ENSEnabled = "enabled"
+	// ENSRegistry is the address of the ENS registry contract
+	ENSRegistry = "registry"
+
+	// AccountAbstractionConfigKey is a sub-key in the config to contain the ERC-4337 account abstraction config
+	AccountAbstractionConfigKey = "accountAbstraction"
+
+	// BatchPinConfigKey is a sub-key in the config to contain the batch pinning config
+	BatchPinConfigKey = "batchPin"
+	// BatchPinMode selects how batch pins are encoded on chain - "standard" or "packed"
+	BatchPinMode = "mode"
+
 	// FFTMConfigKey is a sub-key in the config that optionally contains FireFly transaction connection information
 	FFTMConfigKey = "fftm"
 )
@@ -92,6 +133,13 @@ func (e *Ethereum) InitConfig(config config.Section) {
 	e.ethconnectConf.AddKnownKey(EthconnectBackgroundStartInitialDelay, defaultBackgroundInitialDelay)
 	e.ethconnectConf.AddKnownKey(EthconnectBackgroundStartFactor, defaultBackgroundRetryFactor)
 	e.ethconnectConf.AddKnownKey(EthconnectBackgroundStartMaxDelay, defaultBackgroundMaxDelay)
+	e.ethconnectConf.AddKnownKey(EthconnectFailoverURLs)
+	e.ethconnectConf.AddKnownKey(EthconnectFailoverHealthCheckPath, defaultFailoverHealthCheckPath)
+	e.ethconnectConf.AddKnownKey(EthconnectFailoverHealthCheckInterval, defaultFailoverHealthCheckInterval)
+	e.ethconnectConf.AddKnownKey(EthconnectCatchupBatchInterval, defaultCatchupBatchInterval)
+	e.ethconnectConf.AddKnownKey(EthconnectQueryQuorumURLs)
+	e.ethconnectConf.AddKnownKey(EthconnectQueryQuorumMinMatching)
+	e.ethconnectConf.AddKnownKey(EthconnectChainIDPath, defaultChainIDPath)
 	e.ethconnectConf.AddKnownKey(EthconnectConfigBatchSize, defaultBatchSize)
 	e.ethconnectConf.AddKnownKey(EthconnectConfigBatchTimeout, defaultBatchTimeout)
 	e.ethconnectConf.AddKnownKey(EthconnectPrefixShort, defaultPrefixShort)
@@ -110,4 +158,13 @@ func (e *Ethereum) InitConfig(config config.Section) {
 	addressResolverConf.AddKnownKey(AddressResolverURLTemplate)
 	addressResolverConf.AddKnownKey(AddressResolverBodyTemplate)
 	addressResolverConf.AddKnownKey(AddressResolverResponseField, defaultAddressResolverResponseField)
+
+	ensConf := config.SubSection(ENSConfigKey)
+	ensConf.AddKnownKey(ENSEnabled)
+	ensConf.AddKnownKey(ENSRegistry, defaultENSRegistry)
+
+	accountabstraction.InitConfig(config.SubSection(AccountAbstractionConfigKey))
+
+	batchPinConf := config.SubSection(BatchPinConfigKey)
+	batchPinConf.AddKnownKey(BatchPinMode, defaultBatchPinMode)
 }
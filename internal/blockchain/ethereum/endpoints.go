@@ -0,0 +1,225 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// endpointManager tracks the set of connector URLs configured for the plugin, and which one is
+// currently active. All endpoints are expected to be replicas that share the same event stream
+// and subscription state (for example an HA pair of evmconnect instances).
+type endpointManager struct {
+	client     *resty.Client
+	urls       []string
+	healthPath string
+	wsKeyPath  string
+	mux        sync.Mutex
+	active     int
+	onSwitch   func(ctx context.Context)
+}
+
+func newEndpointManager(client *resty.Client, urls []string, healthPath, wsKeyPath string) *endpointManager {
+	em := &endpointManager{
+		client:     client,
+		urls:       urls,
+		healthPath: healthPath,
+		wsKeyPath:  wsKeyPath,
+	}
+	// The base URL of the shared client cannot be changed safely while requests are in flight,
+	// so each relative request is resolved against the active endpoint as it is sent
+	client.OnBeforeRequest(em.resolveRequestURL)
+	return em
+}
+
+func (em *endpointManager) resolveRequestURL(_ *resty.Client, req *resty.Request) error {
+	if u, err := url.Parse(req.URL); err == nil && u.IsAbs() {
+		return nil
+	}
+	req.URL = strings.TrimSuffix(em.activeURL(), "/") + "/" + strings.TrimPrefix(req.URL, "/")
+	return nil
+}
+
+func (em *endpointManager) activeURL() string {
+	em.mux.Lock()
+	defer em.mux.Unlock()
+	return em.urls[em.active]
+}
+
+func (em *endpointManager) activeWSURL(ctx context.Context) (string, error) {
+	u, err := url.Parse(em.activeURL())
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, i18n.MsgInvalidURL, em.activeURL())
+	}
+	u.Path = em.wsKeyPath
+	switch u.Scheme {
+	case "http":
+		u.Scheme = "ws"
+	case "https":
+		u.Scheme = "wss"
+	}
+	return u.String(), nil
+}
+
+func (em *endpointManager) isHealthy(ctx context.Context, baseURL string) bool {
+	res, err := em.client.R().
+		SetContext(ctx).
+		Get(strings.TrimSuffix(baseURL, "/") + em.healthPath)
+	if err != nil || !res.IsSuccess() {
+		log.L(ctx).Warnf("Connector endpoint %s failed health check: %s", baseURL, restErrorSummary(res, err))
+		return false
+	}
+	return true
+}
+
+// selectHealthy checks the health of the active endpoint and, if it is unhealthy, each of the
+// other endpoints in turn - switching REST requests over to the first healthy one found.
+// Returns true if the active endpoint changed.
+func (em *endpointManager) selectHealthy(ctx context.Context) (changed bool, err error) {
+	em.mux.Lock()
+	start := em.active
+	em.mux.Unlock()
+
+	for i := 0; i < len(em.urls); i++ {
+		candidate := (start + i) % len(em.urls)
+		if em.isHealthy(ctx, em.urls[candidate]) {
+			if candidate != start {
+				log.L(ctx).Warnf("Failing over from connector endpoint %s to %s", em.urls[start], em.urls[candidate])
+				em.mux.Lock()
+				em.active = candidate
+				em.mux.Unlock()
+			}
+			return candidate != start, nil
+		}
+	}
+	return false, i18n.NewError(ctx, coremsgs.MsgNoHealthyConnectorEndpoint, len(em.urls))
+}
+
+func (em *endpointManager) healthCheckLoop(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			log.L(ctx).Debugf("Connector endpoint health check loop exiting")
+			return
+		case <-ticker.C:
+			// Errors are already logged, and the websocket reconnect will retry selection
+			if changed, _ := em.selectHealthy(ctx); changed && em.onSwitch != nil {
+				em.onSwitch(ctx)
+			}
+		}
+	}
+}
+
+// fireflySubscriptionParams are the inputs used to ensure a FireFly subscription, so that it can be
+// ensured again on another endpoint after a failover
+type fireflySubscriptionParams struct {
+	namespace  string
+	version    int
+	address    string
+	firstEvent string
+	abi        *abi.Entry
+}
+
+func (e *Ethereum) recordFireflySubscription(subID, namespace string, version int, address, firstEvent string, abi *abi.Entry) {
+	e.fireflySubsMux.Lock()
+	defer e.fireflySubsMux.Unlock()
+	if e.fireflySubs == nil {
+		e.fireflySubs = make(map[string]*fireflySubscriptionParams)
+	}
+	e.fireflySubs[subID] = &fireflySubscriptionParams{
+		namespace:  namespace,
+		version:    version,
+		address:    address,
+		firstEvent: firstEvent,
+		abi:        abi,
+	}
+}
+
+func (e *Ethereum) forgetFireflySubscription(subID string) {
+	e.fireflySubsMux.Lock()
+	defer e.fireflySubsMux.Unlock()
+	delete(e.fireflySubs, subID)
+}
+
+func (e *Ethereum) ensureStreamAndSubscriptions(ctx context.Context) error {
+	stream, err := e.streams.ensureEventStream(ctx, e.topic)
+	if err != nil {
+		return err
+	}
+	e.streamID = stream.ID
+	log.L(ctx).Infof("Event stream: %s (topic=%s,url=%s)", e.streamID, e.topic, e.endpoints.activeURL())
+
+	e.fireflySubsMux.Lock()
+	defer e.fireflySubsMux.Unlock()
+	for subID, p := range e.fireflySubs {
+		sub, err := e.streams.ensureFireFlySubscription(ctx, p.namespace, p.version, p.address, p.firstEvent, e.streamID, p.abi)
+		if err != nil {
+			return err
+		}
+		if sub.ID != subID {
+			// Events for the new subscription would not be routed to the namespace
+			log.L(ctx).Errorf("Connector endpoint %s has subscription %s for namespace '%s' rather than %s - endpoints must share subscription state", e.endpoints.activeURL(), sub.ID, p.namespace, subID)
+		}
+	}
+	return nil
+}
+
+// endpointSwitched moves the websocket over to the endpoint the health check switched the REST
+// client to. The new websocket ensures the event stream and subscriptions before it connects.
+func (e *Ethereum) endpointSwitched(ctx context.Context) {
+	e.wsMux.Lock()
+	if !e.wsStarted {
+		// The first connect will select the endpoint
+		e.wsMux.Unlock()
+		return
+	}
+	wsconn, err := wsclient.New(e.ctx, e.wsConfig, e.beforeConnect, e.afterConnect)
+	if err != nil {
+		e.wsMux.Unlock()
+		log.L(ctx).Errorf("Failed to create websocket for connector endpoint %s: %s", e.endpoints.activeURL(), err)
+		return
+	}
+	old := e.wsconn
+	e.wsconn = wsconn
+	e.wsMux.Unlock()
+
+	old.Close()
+	if err := wsconn.Connect(); err != nil {
+		log.L(ctx).Errorf("Failed to connect websocket to connector endpoint %s. Terminating server!", e.endpoints.activeURL())
+		e.cancelCtx()
+	}
+}
+
+func restErrorSummary(res *resty.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return res.Status()
+}
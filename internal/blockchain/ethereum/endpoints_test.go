@@ -0,0 +1,408 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestEndpointManager(urls ...string) (*endpointManager, func()) {
+	client := resty.New().SetBaseURL(urls[0])
+	httpmock.ActivateNonDefault(client.GetClient())
+	return newEndpointManager(client, urls, "/status", "/ws"), httpmock.DeactivateAndReset
+}
+
+func TestSelectHealthyActiveOK(t *testing.T) {
+	em, done := newTestEndpointManager("http://ec1", "http://ec2")
+	defer done()
+
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(200, "{}"))
+
+	changed, err := em.selectHealthy(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, "http://ec1", em.activeURL())
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestSelectHealthyFailover(t *testing.T) {
+	em, done := newTestEndpointManager("http://ec1", "http://ec2", "https://ec3/")
+	defer done()
+
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(500, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/status", httpmock.NewErrorResponder(fmt.Errorf("pop")))
+	httpmock.RegisterResponder("GET", "https://ec3/status", httpmock.NewStringResponder(200, "{}"))
+	httpmock.RegisterResponder("GET", "https://ec3/eventstreams", httpmock.NewStringResponder(200, "[]"))
+
+	changed, err := em.selectHealthy(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "https://ec3/", em.activeURL())
+
+	// Relative requests go to the new endpoint, without changing the base URL of the shared client
+	res, err := em.client.R().Get("/eventstreams")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode())
+	assert.Equal(t, "http://ec1", em.client.BaseURL)
+
+	wsURL, err := em.activeWSURL(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "wss://ec3/ws", wsURL)
+}
+
+func TestSelectHealthyNoneHealthy(t *testing.T) {
+	em, done := newTestEndpointManager("http://ec1", "http://ec2")
+	defer done()
+
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(500, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/status", httpmock.NewStringResponder(503, "{}"))
+
+	_, err := em.selectHealthy(context.Background())
+	assert.Regexp(t, "FF10456.*2", err)
+	assert.Equal(t, "http://ec1", em.activeURL())
+}
+
+func TestActiveWSURLBadURL(t *testing.T) {
+	em, done := newTestEndpointManager("::::badurl")
+	defer done()
+
+	_, err := em.activeWSURL(context.Background())
+	assert.Regexp(t, "FF00149", err)
+}
+
+func TestHealthCheckLoop(t *testing.T) {
+	em, done := newTestEndpointManager("http://ec1", "http://ec2")
+	defer done()
+
+	checked := make(chan struct{})
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(500, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/status", func(req *http.Request) (*http.Response, error) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+		return httpmock.NewStringResponse(200, "{}"), nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loopDone := make(chan struct{})
+	go func() {
+		em.healthCheckLoop(ctx, 1*time.Millisecond)
+		close(loopDone)
+	}()
+	<-checked
+	cancel()
+	<-loopDone
+	assert.Equal(t, "http://ec2", em.activeURL())
+}
+
+func TestBeforeConnectFailover(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	e.streams = newTestStreamManager(e.client)
+	em, done := newTestEndpointManager("http://ec1", "http://ec2")
+	defer done()
+	e.client = em.client
+	e.streams.client = em.client
+	e.endpoints = em
+
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(500, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/status", httpmock.NewStringResponder(200, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1"}}))
+	httpmock.RegisterResponder("PATCH", "http://ec2/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Name: "topic1"}))
+
+	wsm := e.wsconn.(*wsmocks.WSClient)
+	wsm.On("URL").Return("ws://ec1/ws").Once()
+	wsm.On("SetURL", "ws://ec2/ws").Return()
+
+	err := e.beforeConnect(e.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", e.streamID)
+
+	wsm.On("URL").Return("ws://ec2/ws")
+	err = e.beforeConnect(e.ctx)
+	assert.NoError(t, err)
+
+	wsm.AssertExpectations(t)
+}
+
+func TestBeforeConnectNoneHealthy(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	em, done := newTestEndpointManager("http://ec1", "http://ec2")
+	defer done()
+	e.endpoints = em
+
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(500, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/status", httpmock.NewStringResponder(500, "{}"))
+
+	err := e.beforeConnect(e.ctx)
+	assert.Regexp(t, "FF10456", err)
+}
+
+func TestBeforeConnectStreamFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	em, done := newTestEndpointManager("http://ec1", "http://ec2")
+	defer done()
+	e.client = em.client
+	e.streams = newTestStreamManager(em.client)
+	e.endpoints = em
+
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(500, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/status", httpmock.NewStringResponder(200, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2/eventstreams", httpmock.NewStringResponder(500, "{}"))
+
+	wsm := e.wsconn.(*wsmocks.WSClient)
+	wsm.On("URL").Return("ws://ec1/ws")
+
+	err := e.beforeConnect(e.ctx)
+	assert.Regexp(t, "FF10111", err)
+}
+
+func TestInitAndStartWithFailover(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	toServer, _, wsURL, done := wsclient.NewTestWSServer(nil)
+	defer done()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	httpmock.RegisterResponder("GET", "http://primary.example.com/status", httpmock.NewStringResponder(503, "{}"))
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/status", httpURL), httpmock.NewStringResponder(200, "{}"))
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1"}}))
+	httpmock.RegisterResponder("PATCH", fmt.Sprintf("%s/eventstreams/es12345", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Name: "topic1"}))
+
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://primary.example.com")
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utEthconnectConf.Set(EthconnectFailoverURLs, []string{httpURL})
+	utEthconnectConf.Set(EthconnectFailoverHealthCheckInterval, "1h")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, httpURL, e.endpoints.activeURL())
+	assert.Equal(t, "es12345", e.streamID)
+
+	err = e.Start()
+	assert.NoError(t, err)
+
+	startupMessage := <-toServer
+	assert.Equal(t, `{"type":"listen","topic":"topic1"}`, startupMessage)
+}
+
+func TestInitFailoverNoneHealthy(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://ec1.example.com/status", httpmock.NewStringResponder(503, "{}"))
+	httpmock.RegisterResponder("GET", "http://ec2.example.com/status", httpmock.NewStringResponder(503, "{}"))
+
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://ec1.example.com")
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utEthconnectConf.Set(EthconnectFailoverURLs, []string{"http://ec2.example.com"})
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.Regexp(t, "FF10456", err)
+}
+
+func TestHealthCheckFailoverMovesWebSocket(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	toServer1, _, wsURL1, done1 := wsclient.NewTestWSServer(nil)
+	defer done1()
+	toServer2, fromServer2, wsURL2, done2 := wsclient.NewTestWSServer(nil)
+	defer done2()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpURLs := make([]string, 2)
+	for i, wsURL := range []string{wsURL1, wsURL2} {
+		u, _ := url.Parse(wsURL)
+		u.Scheme = "http"
+		httpURLs[i] = u.String()
+		httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURLs[i]),
+			httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1"}}))
+		httpmock.RegisterResponder("PATCH", fmt.Sprintf("%s/eventstreams/es12345", httpURLs[i]),
+			httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Name: "topic1"}))
+	}
+	var primaryDown int32
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/status", httpURLs[0]), func(req *http.Request) (*http.Response, error) {
+		if atomic.LoadInt32(&primaryDown) == 1 {
+			return httpmock.NewStringResponse(503, "{}"), nil
+		}
+		return httpmock.NewStringResponse(200, "{}"), nil
+	})
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/status", httpURLs[1]), httpmock.NewStringResponder(200, "{}"))
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subscriptions", httpURLs[1]), httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/subscriptions", httpURLs[1]), httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1"}))
+
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, httpURLs[0])
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utEthconnectConf.Set(EthconnectFailoverURLs, []string{httpURLs[1]})
+	utEthconnectConf.Set(EthconnectFailoverHealthCheckInterval, "1ms")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+	e.recordFireflySubscription("sub1", "ns1", 2, "0x123", "newest", batchPinEventABI)
+
+	err = e.Start()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"listen","topic":"topic1"}`, <-toServer1)
+	assert.Equal(t, `{"type":"listenreplies"}`, <-toServer1)
+
+	// The REST client, event stream, subscriptions and websocket all move to the failover endpoint
+	atomic.StoreInt32(&primaryDown, 1)
+	assert.Equal(t, `{"type":"listen","topic":"topic1"}`, <-toServer2)
+	assert.Equal(t, `{"type":"listenreplies"}`, <-toServer2)
+	assert.Equal(t, httpURLs[1], e.ensuredURL)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()[fmt.Sprintf("POST %s/subscriptions", httpURLs[1])])
+
+	// The event loop follows the new websocket
+	fromServer2 <- `[]`
+	assert.Equal(t, `{"type":"ack","topic":"topic1"}`, <-toServer2)
+}
+
+func TestEnsureStreamAndSubscriptionsMismatch(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	em, done := newTestEndpointManager("http://ec1")
+	defer done()
+	e.client = em.client
+	e.streams = newTestStreamManager(em.client)
+	e.endpoints = em
+	e.recordFireflySubscription("sub1", "ns1", 2, "0x123", "newest", batchPinEventABI)
+
+	httpmock.RegisterResponder("GET", "http://ec1/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1"}}))
+	httpmock.RegisterResponder("PATCH", "http://ec1/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Name: "topic1"}))
+	httpmock.RegisterResponder("GET", "http://ec1/subscriptions", httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", "http://ec1/subscriptions", httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub2"}))
+
+	// A different subscription is logged, as the endpoints do not share state
+	err := e.ensureStreamAndSubscriptions(e.ctx)
+	assert.NoError(t, err)
+
+	e.forgetFireflySubscription("sub1")
+	assert.Empty(t, e.fireflySubs)
+}
+
+func TestEnsureStreamAndSubscriptionsFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	em, done := newTestEndpointManager("http://ec1")
+	defer done()
+	e.client = em.client
+	e.streams = newTestStreamManager(em.client)
+	e.endpoints = em
+	e.recordFireflySubscription("sub1", "ns1", 2, "0x123", "newest", batchPinEventABI)
+
+	httpmock.RegisterResponder("GET", "http://ec1/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1"}}))
+	httpmock.RegisterResponder("PATCH", "http://ec1/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Name: "topic1"}))
+	httpmock.RegisterResponder("GET", "http://ec1/subscriptions", httpmock.NewStringResponder(500, "{}"))
+
+	err := e.ensureStreamAndSubscriptions(e.ctx)
+	assert.Regexp(t, "FF10111", err)
+}
+
+func TestEndpointSwitchedBeforeStart(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	// The websocket picks the endpoint when it first connects
+	e.endpointSwitched(e.ctx)
+	e.wsconn.(*wsmocks.WSClient).AssertExpectations(t)
+}
+
+func TestEndpointSwitchedBadConfig(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	em, done := newTestEndpointManager("http://ec1")
+	defer done()
+	e.endpoints = em
+	e.wsStarted = true
+	e.wsConfig = &wsclient.WSConfig{HTTPURL: "::::badurl"}
+
+	e.endpointSwitched(e.ctx)
+	assert.Equal(t, e.wsconn, e.getWSConn())
+}
+
+func TestEndpointSwitchedConnectFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	em, done := newTestEndpointManager("http://ec1")
+	defer done()
+	e.endpoints = em
+	e.wsStarted = true
+	e.wsConfig = &wsclient.WSConfig{HTTPURL: "http://ec1", WSKeyPath: "/ws"}
+	wsm := e.wsconn.(*wsmocks.WSClient)
+	wsm.On("Close").Return()
+
+	httpmock.RegisterResponder("GET", "http://ec1/status", httpmock.NewStringResponder(500, "{}"))
+
+	e.endpointSwitched(e.ctx)
+	<-e.ctx.Done()
+	wsm.AssertExpectations(t)
+}
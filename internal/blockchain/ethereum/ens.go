@@ -0,0 +1,158 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"golang.org/x/crypto/sha3"
+	"golang.org/x/net/idna"
+)
+
+const ensZeroAddress = "0x0000000000000000000000000000000000000000"
+
+var ensResolverMethodABI = &abi.Entry{
+	Name: "resolver",
+	Type: "function",
+	Inputs: abi.ParameterArray{
+		{
+			InternalType: "bytes32",
+			Name:         "node",
+			Type:         "bytes32",
+		},
+	},
+	Outputs: abi.ParameterArray{
+		{
+			InternalType: "address",
+			Name:         "",
+			Type:         "address",
+		},
+	},
+	StateMutability: "view",
+}
+
+var ensAddrMethodABI = &abi.Entry{
+	Name: "addr",
+	Type: "function",
+	Inputs: abi.ParameterArray{
+		{
+			InternalType: "bytes32",
+			Name:         "node",
+			Type:         "bytes32",
+		},
+	},
+	Outputs: abi.ParameterArray{
+		{
+			InternalType: "address payable",
+			Name:         "",
+			Type:         "address",
+		},
+	},
+	StateMutability: "view",
+}
+
+// ensNameProfile applies the UTS-46 normalization that EIP-137 requires of names before they are hashed
+var ensNameProfile = idna.New(idna.MapForLookup(), idna.Transitional(false), idna.StrictDomainName(false))
+
+func normalizeENSName(ctx context.Context, name string) (string, error) {
+	normalized, err := ensNameProfile.ToUnicode(name)
+	if err == nil {
+		for _, label := range strings.Split(normalized, ".") {
+			if label == "" {
+				err = i18n.NewError(ctx, coremsgs.MsgENSNameInvalid, name)
+			}
+		}
+	}
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgENSNameInvalid, name)
+	}
+	return normalized, nil
+}
+
+// ensNamehash implements the EIP-137 namehash algorithm, for a name that has already been normalized
+func ensNamehash(name string) string {
+	node := make([]byte, 32)
+	if name != "" {
+		labels := strings.Split(name, ".")
+		for i := len(labels) - 1; i >= 0; i-- {
+			labelHash := sha3.NewLegacyKeccak256()
+			labelHash.Write([]byte(labels[i]))
+			hash := sha3.NewLegacyKeccak256()
+			hash.Write(node)
+			hash.Write(labelHash.Sum(nil))
+			node = hash.Sum(nil)
+		}
+	}
+	return "0x" + hex.EncodeToString(node)
+}
+
+func (e *Ethereum) queryENSAddress(ctx context.Context, name, contract string, method *abi.Entry, node string) (string, error) {
+	res, err := e.queryContractMethod(ctx, contract, "", method, []interface{}{node}, nil, nil)
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgENSResolveFailed, name)
+	}
+	output := &queryOutput{}
+	if err = json.Unmarshal(res.Body(), output); err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgENSResolveFailed, name)
+	}
+	result, _ := output.Output.(string)
+	address, err := formatEthAddress(ctx, result)
+	if err != nil {
+		return "", i18n.WrapError(ctx, err, coremsgs.MsgENSResolveFailed, name)
+	}
+	return address, nil
+}
+
+// resolveENSName resolves an ENS name to an address, by querying the ENS registry for the resolver of
+// the name and then querying that resolver for the address. Results are cached.
+func (e *Ethereum) resolveENSName(ctx context.Context, name string) (string, error) {
+	normalized, err := normalizeENSName(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	cacheKey := "ens:" + normalized
+	if cached := e.cache.GetString(cacheKey); cached != "" {
+		return cached, nil
+	}
+
+	node := ensNamehash(normalized)
+	resolver, err := e.queryENSAddress(ctx, name, e.ensRegistry, ensResolverMethodABI, node)
+	if err != nil {
+		return "", err
+	}
+	if resolver == ensZeroAddress {
+		return "", i18n.NewError(ctx, coremsgs.MsgENSNameNotFound, name)
+	}
+	address, err := e.queryENSAddress(ctx, name, resolver, ensAddrMethodABI, node)
+	if err != nil {
+		return "", err
+	}
+	if address == ensZeroAddress {
+		return "", i18n.NewError(ctx, coremsgs.MsgENSNameNotFound, name)
+	}
+
+	log.L(ctx).Infof("ENS name '%s' resolved to '%s'", name, address)
+	e.cache.SetString(cacheKey, address)
+	return address, nil
+}
@@ -0,0 +1,228 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testENSRegistry = "0x00000000000c2e074ec69a0dfb2997ba6c7d2e1e"
+	testENSResolver = "0x4976fb03c32e5b8cfe2b6ccb31c09ba78ebaba41"
+	testENSAddress  = "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+)
+
+func newTestENSEthereum() (*Ethereum, func()) {
+	e, cancel := newTestEthereum()
+	e.ensRegistry = testENSRegistry
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	return e, func() {
+		httpmock.DeactivateAndReset()
+		cancel()
+	}
+}
+
+func mockENSQueries(t *testing.T, resolver, address string) {
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			params := body["params"].([]interface{})
+			assert.Equal(t, ensNamehash("vitalik.eth"), params[0])
+			switch body["to"] {
+			case testENSRegistry:
+				return httpmock.NewJsonResponderOrPanic(200, queryOutput{Output: resolver})(req)
+			default:
+				assert.Equal(t, testENSResolver, body["to"])
+				return httpmock.NewJsonResponderOrPanic(200, queryOutput{Output: address})(req)
+			}
+		})
+}
+
+func TestENSNamehash(t *testing.T) {
+	assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", ensNamehash(""))
+	assert.Equal(t, "0x93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae", ensNamehash("eth"))
+	assert.Equal(t, "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f", ensNamehash("foo.eth"))
+}
+
+func TestNormalizeENSName(t *testing.T) {
+	normalized, err := normalizeENSName(context.Background(), "Foo.ETH")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo.eth", normalized)
+
+	normalized, err = normalizeENSName(context.Background(), "ÅLICE.eth")
+	assert.NoError(t, err)
+	assert.Equal(t, "ålice.eth", normalized)
+
+	_, err = normalizeENSName(context.Background(), "bad\u200d.eth")
+	assert.Regexp(t, "FF10695", err)
+
+	_, err = normalizeENSName(context.Background(), "alice..eth")
+	assert.Regexp(t, "FF10695", err)
+}
+
+func TestResolveSigningKeyENSCached(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	mockENSQueries(t, "0x4976fb03C32e5B8cfe2b6cCB31c09Ba78EBaBa41", "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+
+	resolved, err := e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.NoError(t, err)
+	assert.Equal(t, testENSAddress, resolved)
+
+	resolved, err = e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.NoError(t, err)
+	assert.Equal(t, testENSAddress, resolved)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestResolveSigningKeyENSNormalized(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	mockENSQueries(t, "0x4976fb03C32e5B8cfe2b6cCB31c09Ba78EBaBa41", "0xd8dA6BF26964aF9D7eEd9e03E53415D37aA96045")
+
+	resolved, err := e.ResolveSigningKey(context.Background(), "Vitalik.ETH", blockchain.ResolveKeyIntentLookup)
+	assert.NoError(t, err)
+	assert.Equal(t, testENSAddress, resolved)
+
+	// The cache is keyed on the normalized name
+	resolved, err = e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.NoError(t, err)
+	assert.Equal(t, testENSAddress, resolved)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestResolveSigningKeyENSInvalidName(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	_, err := e.ResolveSigningKey(context.Background(), "alice..eth", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10695", err)
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
+
+func TestResolveSigningKeyENSNoResolver(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	mockENSQueries(t, ensZeroAddress, "")
+
+	_, err := e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10462", err)
+}
+
+func TestResolveSigningKeyENSNoAddress(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	mockENSQueries(t, testENSResolver, ensZeroAddress)
+
+	_, err := e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10462", err)
+}
+
+func TestResolveSigningKeyENSBadAddress(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	mockENSQueries(t, testENSResolver, "not an address")
+
+	_, err := e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10461.*FF10141", err)
+}
+
+func TestResolveSigningKeyENSBadResponse(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/", httpmock.NewStringResponder(200, "[]"))
+
+	_, err := e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10461", err)
+}
+
+func TestResolveSigningKeyENSQueryFail(t *testing.T) {
+	e, done := newTestENSEthereum()
+	defer done()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/", httpmock.NewStringResponder(500, `{"error":"pop"}`))
+
+	_, err := e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10461.*pop", err)
+}
+
+func TestResolveSigningKeyENSDisabled(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	_, err := e.ResolveSigningKey(context.Background(), "vitalik.eth", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestInitENSBadRegistry(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utConfig.SubSection(ENSConfigKey).Set(ENSEnabled, true)
+	utConfig.SubSection(ENSConfigKey).Set(ENSRegistry, "bad")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestInitENSEnabled(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1"}}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Name: "topic1"}))
+
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utConfig.SubSection(ENSConfigKey).Set(ENSEnabled, true)
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, testENSRegistry, e.ensRegistry)
+}
@@ -24,6 +24,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly-common/pkg/config"
@@ -34,7 +36,9 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
 	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
 	"github.com/hyperledger/firefly-signer/pkg/ffi2abi"
+	"github.com/hyperledger/firefly/internal/accountabstraction"
 	"github.com/hyperledger/firefly/internal/blockchain/common"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
@@ -46,6 +50,7 @@ import (
 
 const (
 	broadcastBatchEventSignature = "BatchPin(address,uint256,string,bytes32,bytes32,string,bytes32[])"
+	packedBatchEventSignature    = "BatchPinPacked(address,uint256,bytes)"
 )
 
 const (
@@ -69,15 +74,29 @@ type Ethereum struct {
 	streams              *streamManager
 	streamID             string
 	wsconn               wsclient.WSClient
+	wsConfig             *wsclient.WSConfig
+	wsMux                sync.Mutex
+	wsStarted            bool
 	closed               chan struct{}
 	addressResolveAlways bool
 	addressResolver      *addressResolver
+	ensRegistry          string
+	accountAbstraction   *accountabstraction.AccountAbstraction
+	batchPinPacked       bool
 	metrics              metrics.Manager
 	ethconnectConf       config.Section
 	subs                 common.FireflySubscriptions
 	cache                cache.CInterface
 	backgroundRetry      *retry.Retry
 	backgroundStart      bool
+	endpoints            *endpointManager
+	healthCheckInterval  time.Duration
+	ensuredURL           string
+	fireflySubs          map[string]*fireflySubscriptionParams
+	fireflySubsMux       sync.Mutex
+	catchup              *catchupTracker
+	queryQuorum          *queryQuorum
+	chainIDs             *chainIDVerifier
 }
 
 type eventStreamWebsocket struct {
@@ -161,6 +180,24 @@ func (e *Ethereum) Init(ctx context.Context, cancelCtx context.CancelFunc, conf
 		}
 	}
 
+	if ensConf := conf.SubSection(ENSConfigKey); ensConf.GetBool(ENSEnabled) {
+		if e.ensRegistry, err = formatEthAddress(ctx, ensConf.GetString(ENSRegistry)); err != nil {
+			return err
+		}
+	}
+
+	if e.accountAbstraction, err = accountabstraction.New(ctx, conf.SubSection(AccountAbstractionConfigKey)); err != nil {
+		return err
+	}
+
+	switch mode := conf.SubSection(BatchPinConfigKey).GetString(BatchPinMode); mode {
+	case batchPinModeStandard:
+	case batchPinModePacked:
+		e.batchPinPacked = true
+	default:
+		return i18n.NewError(ctx, coremsgs.MsgInvalidBatchPinMode, mode)
+	}
+
 	if ethconnectConf.GetString(ffresty.HTTPConfigURL) == "" {
 		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "url", ethconnectConf)
 	}
@@ -184,7 +221,20 @@ func (e *Ethereum) Init(ctx context.Context, cancelCtx context.CancelFunc, conf
 	if wsConfig.WSKeyPath == "" {
 		wsConfig.WSKeyPath = "/ws"
 	}
-	e.wsconn, err = wsclient.New(ctx, wsConfig, nil, e.afterConnect)
+	var beforeConnect wsclient.WSPreConnectHandler
+	if failoverURLs := ethconnectConf.GetStringSlice(EthconnectFailoverURLs); len(failoverURLs) > 0 {
+		urls := append([]string{ethconnectConf.GetString(ffresty.HTTPConfigURL)}, failoverURLs...)
+		e.endpoints = newEndpointManager(e.client, urls, ethconnectConf.GetString(EthconnectFailoverHealthCheckPath), wsConfig.WSKeyPath)
+		e.endpoints.onSwitch = e.endpointSwitched
+		e.healthCheckInterval = ethconnectConf.GetDuration(EthconnectFailoverHealthCheckInterval)
+		beforeConnect = e.beforeConnect
+	}
+	e.wsConfig = wsConfig
+	if e.queryQuorum, err = newQueryQuorum(e.ctx, ethconnectConf, e.client); err != nil {
+		return err
+	}
+	e.chainIDs = newChainIDVerifier(e.client, ethconnectConf.GetString(EthconnectChainIDPath))
+	e.wsconn, err = wsclient.New(ctx, wsConfig, beforeConnect, e.afterConnect)
 	if err != nil {
 		return err
 	}
@@ -203,6 +253,7 @@ func (e *Ethereum) Init(ctx context.Context, cancelCtx context.CancelFunc, conf
 	e.cache = cache
 
 	e.streams = newStreamManager(e.client, e.cache, e.ethconnectConf.GetUint(EthconnectConfigBatchSize), uint(e.ethconnectConf.GetDuration(EthconnectConfigBatchTimeout).Milliseconds()))
+	e.catchup = newCatchupTracker(e.ethconnectConf.GetUint(EthconnectConfigBatchSize), e.ethconnectConf.GetDuration(EthconnectCatchupBatchInterval))
 
 	e.backgroundStart = e.ethconnectConf.GetBool(EthconnectBackgroundStart)
 	if e.backgroundStart {
@@ -215,6 +266,11 @@ func (e *Ethereum) Init(ctx context.Context, cancelCtx context.CancelFunc, conf
 		return nil
 	}
 
+	if e.endpoints != nil {
+		if _, err := e.endpoints.selectHealthy(e.ctx); err != nil {
+			return err
+		}
+	}
 	stream, err := e.streams.ensureEventStream(e.ctx, e.topic)
 	if err != nil {
 		return err
@@ -222,6 +278,9 @@ func (e *Ethereum) Init(ctx context.Context, cancelCtx context.CancelFunc, conf
 
 	e.streamID = stream.ID
 	log.L(e.ctx).Infof("Event stream: %s (topic=%s)", e.streamID, e.topic)
+	if e.endpoints != nil {
+		e.ensuredURL = e.endpoints.activeURL()
+	}
 
 	e.closed = make(chan struct{})
 	go e.eventLoop()
@@ -246,7 +305,7 @@ func (e *Ethereum) startBackgroundLoop() {
 
 		e.streamID = stream.ID
 		log.L(e.ctx).Infof("Event stream: %s (topic=%s)", e.streamID, e.topic)
-		err = e.wsconn.Connect()
+		err = e.connectWebSocket()
 		if err != nil {
 			return true, err
 		}
@@ -259,12 +318,33 @@ func (e *Ethereum) startBackgroundLoop() {
 }
 
 func (e *Ethereum) Start() (err error) {
+	if e.endpoints != nil && e.healthCheckInterval > 0 {
+		go e.endpoints.healthCheckLoop(e.ctx, e.healthCheckInterval)
+	}
+
 	if e.backgroundStart {
 		go e.startBackgroundLoop()
 		return nil
 	}
 
-	return e.wsconn.Connect()
+	return e.connectWebSocket()
+}
+
+func (e *Ethereum) connectWebSocket() error {
+	// Not locked during the connect, as beforeConnect needs the websocket
+	if err := e.getWSConn().Connect(); err != nil {
+		return err
+	}
+	e.wsMux.Lock()
+	e.wsStarted = true
+	e.wsMux.Unlock()
+	return nil
+}
+
+func (e *Ethereum) getWSConn() wsclient.WSClient {
+	e.wsMux.Lock()
+	defer e.wsMux.Unlock()
+	return e.wsconn
 }
 
 func (e *Ethereum) Capabilities() *blockchain.Capabilities {
@@ -281,13 +361,37 @@ func (e *Ethereum) AddFireflySubscription(ctx context.Context, namespace *core.N
 	if err != nil {
 		return "", err
 	}
+	if e.batchPinPacked {
+		if err := checkPackedBatchPinVersion(ctx, version); err != nil {
+			return "", err
+		}
+	}
 
 	sub, err := e.streams.ensureFireFlySubscription(ctx, namespace.Name, version, ethLocation.Address, contract.FirstEvent, e.streamID, batchPinEventABI)
 	if err != nil {
 		return "", err
 	}
 
-	e.subs.AddSubscription(ctx, namespace, version, sub.ID, nil)
+	// Network actions are always emitted as standard BatchPin events, so in packed mode a second
+	// subscription is needed for the packed batch pins. It is tracked via the extra info of the first.
+	var packedSubID interface{}
+	if e.batchPinPacked {
+		packedSub, err := e.streams.ensureFireFlySubscription(ctx, namespace.Name, version, ethLocation.Address, contract.FirstEvent, e.streamID, batchPinPackedEventABI)
+		if err != nil {
+			return "", err
+		}
+		e.subs.AddSubscription(ctx, namespace, version, packedSub.ID, nil)
+		e.recordFireflySubscription(packedSub.ID, namespace.Name, version, ethLocation.Address, contract.FirstEvent, batchPinPackedEventABI)
+		packedSubID = packedSub.ID
+	}
+
+	if contract.ChainID != "" {
+		if err := e.chainIDs.pin(ctx, sub.ID, namespace.Name, contract.ChainID); err != nil {
+			return "", err
+		}
+	}
+	e.subs.AddSubscription(ctx, namespace, version, sub.ID, packedSubID)
+	e.recordFireflySubscription(sub.ID, namespace.Name, version, ethLocation.Address, contract.FirstEvent, batchPinEventABI)
 	return sub.ID, nil
 }
 
@@ -295,10 +399,45 @@ func (e *Ethereum) RemoveFireflySubscription(ctx context.Context, subID string)
 	// Don't actually delete the subscription from ethconnect, as this may be called while processing
 	// events from the subscription (and handling that scenario cleanly could be difficult for ethconnect).
 	// TODO: can old subscriptions be somehow cleaned up later?
+	if subInfo := e.subs.GetSubscription(subID); subInfo != nil {
+		if packedSubID, ok := subInfo.Extra.(string); ok {
+			e.subs.RemoveSubscription(ctx, packedSubID)
+			e.forgetFireflySubscription(packedSubID)
+		}
+	}
+	e.forgetFireflySubscription(subID)
+	e.chainIDs.unpin(subID)
 	e.subs.RemoveSubscription(ctx, subID)
 }
 
+func (e *Ethereum) beforeConnect(ctx context.Context) error {
+	// Select a healthy connector endpoint before each connect/reconnect
+	if _, err := e.endpoints.selectHealthy(ctx); err != nil {
+		return err
+	}
+	wsURL, err := e.endpoints.activeWSURL(ctx)
+	if err != nil {
+		return err
+	}
+	if activeURL := e.endpoints.activeURL(); activeURL != e.ensuredURL {
+		// Ensure the event stream and subscriptions on the newly selected endpoint, before we connect to it
+		if err := e.ensureStreamAndSubscriptions(ctx); err != nil {
+			return err
+		}
+		e.ensuredURL = activeURL
+	}
+	if wsconn := e.getWSConn(); wsconn.URL() != wsURL {
+		wsconn.SetURL(wsURL)
+	}
+	return nil
+}
+
 func (e *Ethereum) afterConnect(ctx context.Context, w wsclient.WSClient) error {
+	// Do not listen for events if the connector is now on a different chain to any namespace
+	if err := e.chainIDs.verifyAll(ctx); err != nil {
+		return err
+	}
+
 	// Send a subscribe to our topic after each connect/reconnect
 	b, _ := json.Marshal(&ethWSCommandPayload{
 		Type:  "listen",
@@ -311,9 +450,29 @@ func (e *Ethereum) afterConnect(ctx context.Context, w wsclient.WSClient) error
 		})
 		err = w.Send(ctx, b)
 	}
+	if err == nil && e.catchup != nil {
+		// The connector replays any events we missed while disconnected, for each listener from its checkpoint
+		e.catchup.start(ctx, e.streamSubscriptions(ctx))
+	}
 	return err
 }
 
+// streamSubscriptions returns the listeners on our event stream, along with their checkpoints
+func (e *Ethereum) streamSubscriptions(ctx context.Context) []*subscription {
+	subs, err := e.streams.getSubscriptions(ctx)
+	if err != nil {
+		log.L(ctx).Warnf("Unable to query listener checkpoints for catch-up: %s", err)
+		return nil
+	}
+	streamSubs := make([]*subscription, 0, len(subs))
+	for _, sub := range subs {
+		if sub.Stream == e.streamID {
+			streamSubs = append(streamSubs, sub)
+		}
+	}
+	return streamSubs
+}
+
 func ethHexFormatB32(b *fftypes.Bytes32) string {
 	if b == nil {
 		return "0x0000000000000000000000000000000000000000000000000000000000000000"
@@ -376,6 +535,37 @@ func (e *Ethereum) processBatchPinEvent(ctx context.Context, events common.Event
 		NsOrAction: nsOrAction,
 	}
 
+	e.prepareBatchPin(ctx, events, location, subInfo, event, authorAddress, params, msgJSON)
+}
+
+// processBatchPinRemoved invalidates the blockchain event recorded for a batch pin that has been removed from
+// the chain. Messages already confirmed from the batch cannot be rewound, so they remain confirmed.
+func (e *Ethereum) processBatchPinRemoved(ctx context.Context, events common.EventsToDispatch, subInfo *common.SubscriptionInfo, msgJSON fftypes.JSONObject) {
+	event := e.parseBlockchainEvent(ctx, msgJSON)
+	if event == nil {
+		return // move on
+	}
+	log.L(ctx).Warnf("Batch pin event %s in transaction %s has been removed from the chain", event.ProtocolID, event.BlockchainTXID)
+	// V1 subscriptions are shared by namespaces, so the removal is routed to all of them
+	e.callbacks.PrepareBlockchainEventRemoved(ctx, events, subInfo.V2Namespace, event)
+}
+
+func (e *Ethereum) processPackedBatchPinEvent(ctx context.Context, events common.EventsToDispatch, location *fftypes.JSONAny, subInfo *common.SubscriptionInfo, msgJSON fftypes.JSONObject) {
+	event := e.parseBlockchainEvent(ctx, msgJSON)
+	if event == nil {
+		return // move on
+	}
+
+	params, err := decodePackedBatchPin(ctx, event.Output.GetString("data"))
+	if err != nil {
+		log.L(ctx).Errorf("BatchPinPacked event is not valid (%s): %+v", err, msgJSON)
+		return // move on
+	}
+
+	e.prepareBatchPin(ctx, events, location, subInfo, event, event.Output.GetString("author"), params, msgJSON)
+}
+
+func (e *Ethereum) prepareBatchPin(ctx context.Context, events common.EventsToDispatch, location *fftypes.JSONAny, subInfo *common.SubscriptionInfo, event *blockchain.Event, authorAddress string, params *common.BatchPinParams, msgJSON fftypes.JSONObject) {
 	// Validate the ethereum address - it must already be a valid address, we do not
 	// engage the address resolve on this blockchain-driven path.
 	authorAddress, err := formatEthAddress(ctx, authorAddress)
@@ -400,7 +590,10 @@ func (e *Ethereum) processContractEvent(ctx context.Context, events common.Event
 
 	namespace := common.GetNamespaceFromSubName(subName)
 	event := e.parseBlockchainEvent(ctx, msgJSON)
-	if event != nil {
+	if event != nil && msgJSON.GetBool("removed") {
+		log.L(ctx).Warnf("Blockchain event %s in transaction %s has been removed from the chain", event.ProtocolID, event.BlockchainTXID)
+		e.callbacks.PrepareBlockchainEventRemoved(ctx, events, namespace, event)
+	} else if event != nil {
 		e.callbacks.PrepareBlockchainEvent(ctx, events, namespace, &blockchain.EventForListener{
 			Event:      event,
 			ListenerID: subID,
@@ -444,9 +637,13 @@ func (e *Ethereum) handleMessageBatch(ctx context.Context, batchID int64, messag
 			if firstColon >= 0 {
 				signature = signature[firstColon+1:]
 			}
-			switch signature {
-			case broadcastBatchEventSignature:
+			switch {
+			case msgJSON.GetBool("removed"):
+				e.processBatchPinRemoved(ctx, events, subInfo, msgJSON)
+			case signature == broadcastBatchEventSignature:
 				e.processBatchPinEvent(ctx, events, location, subInfo, msgJSON)
+			case signature == packedBatchEventSignature:
+				e.processPackedBatchPinEvent(ctx, events, location, subInfo, msgJSON)
 			default:
 				log.L(ctx).Infof("Ignoring event with unknown signature: %s", signature)
 			}
@@ -464,16 +661,22 @@ func (e *Ethereum) handleMessageBatch(ctx context.Context, batchID int64, messag
 }
 
 func (e *Ethereum) eventLoop() {
-	defer e.wsconn.Close()
+	defer func() { e.getWSConn().Close() }()
 	defer close(e.closed)
 	l := log.L(e.ctx).WithField("role", "event-loop")
 	ctx := log.WithLogger(e.ctx, l)
 	for {
+		// The websocket is replaced when the health check fails over to another endpoint
+		wsconn := e.getWSConn()
 		select {
 		case <-ctx.Done():
 			l.Debugf("Event loop exiting (context cancelled)")
 			return
-		case msgBytes, ok := <-e.wsconn.Receive():
+		case msgBytes, ok := <-wsconn.Receive():
+			if !ok && wsconn != e.getWSConn() {
+				l.Debugf("Switching to websocket %s", e.getWSConn().URL())
+				continue
+			}
 			if !ok {
 				l.Debugf("Event loop exiting (receive channel closed). Terminating server!")
 				e.cancelCtx()
@@ -488,13 +691,15 @@ func (e *Ethereum) eventLoop() {
 			}
 			switch msgTyped := msgParsed.(type) {
 			case []interface{}:
+				e.catchup.throttle(ctx)
 				err = e.handleMessageBatch(ctx, 0, msgTyped)
 				if err == nil {
+					e.catchup.batchProcessed(ctx, msgTyped)
 					ack, _ := json.Marshal(&ethWSCommandPayload{
 						Type:  "ack",
 						Topic: e.topic,
 					})
-					err = e.wsconn.Send(ctx, ack)
+					err = wsconn.Send(ctx, ack)
 				}
 			case map[string]interface{}:
 				isBatch := false
@@ -502,6 +707,7 @@ func (e *Ethereum) eventLoop() {
 					if events, ok := msgTyped["events"].([]interface{}); ok {
 						// FFTM delivery with a batch number to use in the ack
 						isBatch = true
+						e.catchup.throttle(ctx)
 						err = e.handleMessageBatch(ctx, (int64)(batchNumber), events)
 						// Errors processing messages are converted into nacks
 						ackOrNack := &ethWSCommandPayload{
@@ -509,6 +715,7 @@ func (e *Ethereum) eventLoop() {
 							BatchNumber: int64(batchNumber),
 						}
 						if err == nil {
+							e.catchup.batchProcessed(ctx, events)
 							ackOrNack.Type = "ack"
 						} else {
 							log.L(ctx).Errorf("Rejecting batch due error: %s", err)
@@ -516,7 +723,7 @@ func (e *Ethereum) eventLoop() {
 							ackOrNack.Message = err.Error()
 						}
 						b, _ := json.Marshal(&ackOrNack)
-						err = e.wsconn.Send(ctx, b)
+						err = wsconn.Send(ctx, b)
 					}
 				}
 				if !isBatch {
@@ -545,12 +752,23 @@ func formatEthAddress(ctx context.Context, key string) (string, error) {
 	keyLower := strings.ToLower(key)
 	keyNoHexPrefix := strings.TrimPrefix(keyLower, "0x")
 	if addressVerify.MatchString(keyNoHexPrefix) {
+		// Mixed-case input must carry a valid EIP-55 checksum - all lower or all upper case skips the check
+		keyNoPrefix := strings.TrimPrefix(strings.TrimPrefix(key, "0x"), "0X")
+		if keyNoPrefix != keyNoHexPrefix && keyNoPrefix != strings.ToUpper(keyNoHexPrefix) {
+			checksummed, _ := ethtypes.NewAddressWithChecksum(keyNoHexPrefix)
+			if checksummed.String() != "0x"+keyNoPrefix {
+				return "", i18n.NewError(ctx, coremsgs.MsgInvalidEthAddressChecksum, key)
+			}
+		}
 		return "0x" + keyNoHexPrefix, nil
 	}
 	return "", i18n.NewError(ctx, coremsgs.MsgInvalidEthAddress)
 }
 
 func (e *Ethereum) ResolveSigningKey(ctx context.Context, key string, intent blockchain.ResolveKeyIntent) (resolved string, err error) {
+	if e.ensRegistry != "" && blockchain.IsENSName(key) {
+		return e.resolveENSName(ctx, key)
+	}
 	if !e.addressResolveAlways {
 		// If there's no address resolver plugin, or addressResolveAlways is false,
 		// we check if it's already an ethereum address - in which case we can just return it.
@@ -590,6 +808,9 @@ func (e *Ethereum) buildEthconnectRequestBody(ctx context.Context, messageType,
 	}
 	if signingKey != "" {
 		body["from"] = signingKey
+		if userOp := e.accountAbstraction.UserOperationFor(signingKey); userOp != nil {
+			body["userOperation"] = userOp
+		}
 	}
 	if len(errors) > 0 {
 		body["errors"] = errors
@@ -598,6 +819,9 @@ func (e *Ethereum) buildEthconnectRequestBody(ctx context.Context, messageType,
 }
 
 func (e *Ethereum) applyOptions(ctx context.Context, body, options map[string]interface{}) (map[string]interface{}, error) {
+	if err := validatePrivacyOptions(ctx, options); err != nil {
+		return nil, err
+	}
 	for k, v := range options {
 		// Set the new field if it's not already set. Do not allow overriding of existing fields
 		if _, ok := body[k]; !ok {
@@ -651,6 +875,17 @@ func (e *Ethereum) queryContractMethod(ctx context.Context, address, signingKey
 	return res, nil
 }
 
+func (e *Ethereum) queryContractQuorum(ctx context.Context, address, signingKey string, abi *abi.Entry, input []interface{}, errors []*abi.Entry, options map[string]interface{}) (interface{}, error) {
+	if e.metrics.IsMetricsEnabled() {
+		e.metrics.BlockchainQuery(address, abi.Name)
+	}
+	body, err := e.buildEthconnectRequestBody(ctx, "Query", address, signingKey, abi, "", input, errors, options)
+	if err != nil {
+		return nil, err
+	}
+	return e.queryQuorum.query(ctx, body)
+}
+
 func (e *Ethereum) buildBatchPinInput(ctx context.Context, version int, namespace string, batch *blockchain.BatchPin) (*abi.Entry, []interface{}) {
 	ethHashes := make([]string, len(batch.Contexts))
 	for i, v := range batch.Contexts {
@@ -696,7 +931,20 @@ func (e *Ethereum) SubmitBatchPin(ctx context.Context, nsOpID, networkNamespace,
 		return err
 	}
 
-	method, input := e.buildBatchPinInput(ctx, version, networkNamespace, batch)
+	var method *abi.Entry
+	var input []interface{}
+	if e.batchPinPacked {
+		if err := checkPackedBatchPinVersion(ctx, version); err != nil {
+			return err
+		}
+		packed, err := encodePackedBatchPin(ctx, batch)
+		if err != nil {
+			return err
+		}
+		method, input = batchPinPackedMethodABI, []interface{}{packed}
+	} else {
+		method, input = e.buildBatchPinInput(ctx, version, networkNamespace, batch)
+	}
 
 	var emptyErrors []*abi.Entry
 	return e.invokeContractMethod(ctx, ethLocation.Address, signingKey, method, nsOpID, input, emptyErrors, nil)
@@ -753,6 +1001,9 @@ func (e *Ethereum) DeployContract(ctx context.Context, nsOpID, signingKey string
 	}
 	if signingKey != "" {
 		body["from"] = signingKey
+		if userOp := e.accountAbstraction.UserOperationFor(signingKey); userOp != nil {
+			body["userOperation"] = userOp
+		}
 	}
 	body, err := e.applyOptions(ctx, body, options)
 	if err != nil {
@@ -832,6 +1083,9 @@ func (e *Ethereum) QueryContract(ctx context.Context, signingKey string, locatio
 	if err != nil {
 		return nil, err
 	}
+	if e.queryQuorum != nil {
+		return e.queryContractQuorum(ctx, ethereumLocation.Address, signingKey, abi, orderedInput, errorsAbi, options)
+	}
 	res, err := e.queryContractMethod(ctx, ethereumLocation.Address, signingKey, abi, orderedInput, errorsAbi, options)
 	if err != nil || !res.IsSuccess() {
 		return nil, err
@@ -890,10 +1144,27 @@ func (e *Ethereum) AddContractListener(ctx context.Context, listener *core.Contr
 
 	subName := fmt.Sprintf("ff-sub-%s-%s", listener.Namespace, listener.ID)
 	firstEvent := string(core.SubOptsFirstEventNewest)
+	confirmations := 0
+	var topics []*string
 	if listener.Options != nil {
 		firstEvent = listener.Options.FirstEvent
+		if listener.Options.Confirmations != nil {
+			confirmations = *listener.Options.Confirmations
+		}
+		if listener.Options.DecodingHint != nil {
+			if abi, err = resolveEventDecodingHint(ctx, abi, listener.Options.DecodingHint); err != nil {
+				return err
+			}
+		}
+		if topics, err = parseEventTopicFilters(ctx, abi, listener.Options.Topics); err != nil {
+			return err
+		}
 	}
-	result, err := e.streams.createSubscription(ctx, location, e.streamID, subName, firstEvent, abi)
+	if abi.Anonymous && len(topics) == 0 {
+		// The connector cannot identify anonymous events by signature, so they would never be delivered
+		return i18n.NewError(ctx, coremsgs.MsgAnonymousEventNoTopics, abi.Name)
+	}
+	result, err := e.streams.createSubscription(ctx, location, e.streamID, subName, firstEvent, confirmations, abi, topics)
 	if err != nil {
 		return err
 	}
@@ -1064,6 +1335,57 @@ func (e *Ethereum) GetAndConvertDeprecatedContractConfig(ctx context.Context) (l
 	return location, fromBlock, err
 }
 
+func (e *Ethereum) GetNetworkStatus(ctx context.Context) (*core.BlockchainNetworkStatus, error) {
+	stream, err := e.streams.getEventStream(ctx, e.streamID)
+	if err != nil {
+		return nil, err
+	}
+	status := &core.BlockchainNetworkStatus{
+		EventStream: &core.EventStreamNetworkStatus{
+			ID:        stream.ID,
+			Connected: !stream.Suspended && (stream.Status == "" || stream.Status == "started"),
+		},
+		Catchup: e.catchup.getStatus(),
+	}
+
+	if status.ChainHead, err = e.getChainHead(ctx); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+type blockNumberResponse struct {
+	Result *ethtypes.HexInteger `json:"result"`
+	Error  *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// getChainHead asks the connector for the current block number of the chain, with an eth_blockNumber JSON-RPC request
+func (e *Ethereum) getChainHead(ctx context.Context) (int64, error) {
+	var blockNumber blockNumberResponse
+	res, err := e.client.R().
+		SetContext(ctx).
+		SetBody(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      1,
+			"method":  "eth_blockNumber",
+			"params":  []interface{}{},
+		}).
+		SetResult(&blockNumber).
+		Post("/")
+	if err != nil || !res.IsSuccess() {
+		return 0, wrapError(ctx, nil, res, err)
+	}
+	if blockNumber.Error != nil {
+		return 0, i18n.NewError(ctx, coremsgs.MsgEthConnectorRESTErr, blockNumber.Error.Message)
+	}
+	if blockNumber.Result == nil {
+		return 0, i18n.NewError(ctx, coremsgs.MsgChainHeadMissing, res.String())
+	}
+	return blockNumber.Result.BigInt().Int64(), nil
+}
+
 func (e *Ethereum) GetTransactionStatus(ctx context.Context, operation *core.Operation) (interface{}, error) {
 	txnID := (&core.PreparedOperation{ID: operation.ID, Namespace: operation.Namespace}).NamespacedIDString()
 
@@ -1100,9 +1422,10 @@ func (e *Ethereum) GetTransactionStatus(ctx context.Context, operation *core.Ope
 				Headers: common.BlockchainReceiptHeaders{
 					ReceiptID: statusResponse.GetString("id"),
 					ReplyType: replyType},
-				TxHash:     statusResponse.GetString("transactionHash"),
-				Message:    statusResponse.GetString("errorMessage"),
-				ProtocolID: receiptInfo.GetString("protocolId")}
+				TxHash:         statusResponse.GetString("transactionHash"),
+				Message:        statusResponse.GetString("errorMessage"),
+				ProtocolID:     receiptInfo.GetString("protocolId"),
+				PrivacyGroupID: receiptInfo.GetString("privacyGroupId")}
 			err := common.HandleReceipt(ctx, e, receipt, e.callbacks)
 			if err != nil {
 				log.L(ctx).Warnf("Failed to handle receipt")
@@ -1346,10 +1346,16 @@ func TestVerifyEthAddress(t *testing.T) {
 	_, err := e.ResolveSigningKey(context.Background(), "0x12345", blockchain.ResolveKeyIntentSign)
 	assert.Regexp(t, "FF10141", err)
 
-	key, err := e.ResolveSigningKey(context.Background(), "0x2a7c9D5248681CE6c393117E641aD037F5C079F6", blockchain.ResolveKeyIntentSign)
+	key, err := e.ResolveSigningKey(context.Background(), "0x2a7C9D5248681cE6c393117e641Ad037f5c079F6", blockchain.ResolveKeyIntentSign)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x2a7c9d5248681ce6c393117e641ad037f5c079f6", key)
 
+	key, err = e.ResolveSigningKey(context.Background(), "0x2A7C9D5248681CE6C393117E641AD037F5C079F6", blockchain.ResolveKeyIntentSign)
+	assert.NoError(t, err)
+	assert.Equal(t, "0x2a7c9d5248681ce6c393117e641ad037f5c079f6", key)
+
+	_, err = e.ResolveSigningKey(context.Background(), "0x2a7c9D5248681CE6c393117E641aD037F5C079F6", blockchain.ResolveKeyIntentSign)
+	assert.Regexp(t, "FF10460", err)
 }
 
 func TestHandleMessageBatchPinOK(t *testing.T) {
@@ -1557,6 +1563,57 @@ func TestHandleMessageBatchPinEmpty(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestHandleMessageBatchPinRemoved(t *testing.T) {
+	em := &blockchainmocks.Callbacks{}
+	e := &Ethereum{
+		subs:      common.NewFireflySubscriptions(),
+		callbacks: common.NewBlockchainCallbacks(),
+	}
+	e.SetHandler("ns1", em)
+	e.subs.AddSubscription(
+		context.Background(),
+		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
+		1, "sb-b5b97a4e-a317-4053-6400-1474650efcb5", nil,
+	)
+
+	var events []interface{}
+	err := json.Unmarshal([]byte(`
+	[
+		{
+			"address": "0x1C197604587F046FD40684A8f21f4609FB811A7b",
+			"subId": "sb-b5b97a4e-a317-4053-6400-1474650efcb5",
+			"signature": "BatchPin(address,uint256,string,bytes32,bytes32,string,bytes32[])",
+			"blockNumber": "38011",
+			"transactionIndex": "0x0",
+			"logIndex": "50",
+			"timestamp": "1620576488",
+			"data": {},
+			"transactionHash": "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628",
+			"removed": true
+		},
+		{
+			"address": "0x1C197604587F046FD40684A8f21f4609FB811A7b",
+			"subId": "sb-b5b97a4e-a317-4053-6400-1474650efcb5",
+			"signature": "BatchPin(address,uint256,string,bytes32,bytes32,string,bytes32[])",
+			"transactionHash": "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628",
+			"removed": true
+		}
+	]`), &events)
+	assert.NoError(t, err)
+
+	// The blockchain event for the pin is invalidated, and the incomplete removal is skipped
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(batch []*blockchain.EventToDispatch) bool {
+		return len(batch) == 1 &&
+			batch[0].Type == blockchain.EventTypeRemoved &&
+			batch[0].Removed.ProtocolID == "000000038011/000000/000050" &&
+			batch[0].Removed.BlockchainTXID == "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628"
+	})).Return(nil)
+	err = e.handleMessageBatch(context.Background(), 0, events)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+}
+
 func TestHandleMessageBatchMissingData(t *testing.T) {
 	e := &Ethereum{
 		subs:      common.NewFireflySubscriptions(),
@@ -1758,6 +1815,98 @@ func TestHandleReceiptTXSuccess(t *testing.T) {
 	em.AssertExpectations(t)
 }
 
+func TestHandleReceiptTXSuccessPrivate(t *testing.T) {
+	em := &coremocks.OperationCallbacks{}
+	wsm := &wsmocks.WSClient{}
+	e := &Ethereum{
+		ctx:       context.Background(),
+		topic:     "topic1",
+		callbacks: common.NewBlockchainCallbacks(),
+		wsconn:    wsm,
+	}
+	e.SetOperationHandler("ns1", em)
+
+	var reply common.BlockchainReceiptNotification
+	operationID := fftypes.NewUUID()
+	data := fftypes.JSONAnyPtr(`{
+		"headers": {
+			"requestId": "ns1:` + operationID.String() + `",
+			"type": "TransactionSuccess"
+		},
+		"privacyGroupId": "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8=",
+		"transactionHash": "0x71a38acb7a5d4a970854f6d638ceb1fa10a4b59cbf4ed7674273a1a8dc8b36b8"
+	}`)
+
+	em.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == "ns1:"+operationID.String() &&
+			update.Status == core.OpStatusSucceeded &&
+			update.Output.GetString("privacyGroupId") == "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8="
+	})).Return(nil)
+
+	err := json.Unmarshal(data.Bytes(), &reply)
+	assert.NoError(t, err)
+
+	common.HandleReceipt(context.Background(), e, &reply, e.callbacks)
+
+	em.AssertExpectations(t)
+}
+
+func TestInvokeContractPrivateOptions(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	signingKey := ethHexFormatB32(fftypes.NewRandB32())
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	options := map[string]interface{}{
+		"privateFrom": "A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo=",
+		"privateFor":  []interface{}{"Ko2bVqD+nNlNYL5EE7y3IdOnviftjiizpjRt+HTuFBs="},
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	httpmock.RegisterResponder("POST", `http://localhost:12345/`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo=", body["privateFrom"])
+			assert.Equal(t, []interface{}{"Ko2bVqD+nNlNYL5EE7y3IdOnviftjiizpjRt+HTuFBs="}, body["privateFor"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+	err = e.InvokeContract(context.Background(), "", signingKey, fftypes.JSONAnyPtrBytes(locationBytes), method, params, errors, options, nil)
+	assert.NoError(t, err)
+}
+
+func TestInvokeContractConflictingPrivateOptions(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	signingKey := ethHexFormatB32(fftypes.NewRandB32())
+	location := &Location{
+		Address: "0x12345",
+	}
+	method := testFFIMethod()
+	errors := testFFIErrors()
+	params := map[string]interface{}{
+		"x": float64(1),
+		"y": float64(2),
+	}
+	options := map[string]interface{}{
+		"privateFor":     []interface{}{"Ko2bVqD+nNlNYL5EE7y3IdOnviftjiizpjRt+HTuFBs="},
+		"privacyGroupId": "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8=",
+	}
+	locationBytes, err := json.Marshal(location)
+	assert.NoError(t, err)
+	err = e.InvokeContract(context.Background(), "", signingKey, fftypes.JSONAnyPtrBytes(locationBytes), method, params, errors, options, nil)
+	assert.Regexp(t, "FF10459", err)
+}
+
 func TestHandleReceiptTXUpdateEVMConnect(t *testing.T) {
 	em := &coremocks.OperationCallbacks{}
 	wsm := &wsmocks.WSClient{}
@@ -1949,6 +2098,45 @@ func TestAddSubscription(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestAddSubscriptionWithConfirmations(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streamID = "es-1"
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	confirmations := 20
+	sub := &core.ContractListener{
+		Event: &core.FFISerializedEvent{
+			FFIEventDefinition: fftypes.FFIEventDefinition{
+				Name: "Changed",
+			},
+		},
+		Options: &core.ContractListenerOptions{
+			FirstEvent:    string(core.SubOptsFirstEventNewest),
+			Confirmations: &confirmations,
+		},
+	}
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/subscriptions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body subscription
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, 20, body.Confirmations)
+			assert.Equal(t, "latest", body.FromBlock)
+			return httpmock.NewJsonResponderOrPanic(200, &subscription{ID: "sub1"})(req)
+		})
+
+	err := e.AddContractListener(context.Background(), sub)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sub1", sub.BackendID)
+}
+
 func TestAddSubscriptionWithoutLocation(t *testing.T) {
 	e, cancel := newTestEthereum()
 	defer cancel()
@@ -2359,6 +2547,108 @@ func TestHandleMessageContractEventWithNamespace(t *testing.T) {
 	em.AssertExpectations(t)
 }
 
+func TestHandleMessageContractEventPrivate(t *testing.T) {
+	data := fftypes.JSONAnyPtr(`
+[
+  {
+		"address": "0x1C197604587F046FD40684A8f21f4609FB811A7b",
+		"blockNumber": "38011",
+		"transactionIndex": "0x0",
+		"transactionHash": "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628",
+		"privacyGroupId": "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8=",
+		"data": {
+			"from": "0x91D2B4381A4CD5C7C0F27565A7D4B829844C8635",
+			"value": "1"
+    },
+		"subId": "sub2",
+		"signature": "Changed(address,uint256)",
+		"logIndex": "50",
+		"timestamp": "1640811383"
+  }
+]`)
+
+	em := &blockchainmocks.Callbacks{}
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub2",
+		httpmock.NewJsonResponderOrPanic(200, subscription{
+			ID: "sub2", Stream: "es12345", Name: "ff-sub-ns1-1132312312312",
+		}))
+
+	e.callbacks = common.NewBlockchainCallbacks()
+	e.SetHandler("ns1", em)
+	e.streams = newTestStreamManager(e.client)
+
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(batch []*blockchain.EventToDispatch) bool {
+		return len(batch) == 1
+	})).Return(nil)
+
+	var events []interface{}
+	err := json.Unmarshal(data.Bytes(), &events)
+	assert.NoError(t, err)
+	err = e.handleMessageBatch(context.Background(), 0, events)
+	assert.NoError(t, err)
+
+	ev := em.Calls[0].Arguments[0].([]*blockchain.EventToDispatch)[0]
+	assert.Equal(t, "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8=", ev.ForListener.Event.Info.GetString("privacyGroupId"))
+
+	em.AssertExpectations(t)
+}
+
+func TestHandleMessageContractEventRemoved(t *testing.T) {
+	data := fftypes.JSONAnyPtr(`
+[
+  {
+		"address": "0x1C197604587F046FD40684A8f21f4609FB811A7b",
+		"blockNumber": "38011",
+		"transactionIndex": "0x0",
+		"transactionHash": "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628",
+		"data": {
+			"from": "0x91D2B4381A4CD5C7C0F27565A7D4B829844C8635",
+			"value": "1"
+    },
+		"subId": "sub2",
+		"signature": "Changed(address,uint256)",
+		"logIndex": "50",
+		"timestamp": "1640811383",
+		"removed": true
+  }
+]`)
+
+	em := &blockchainmocks.Callbacks{}
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub2",
+		httpmock.NewJsonResponderOrPanic(200, subscription{
+			ID: "sub2", Stream: "es12345", Name: "ff-sub-ns1-1132312312312",
+		}))
+
+	e.callbacks = common.NewBlockchainCallbacks()
+	e.SetHandler("ns1", em)
+	e.streams = newTestStreamManager(e.client)
+
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(batch []*blockchain.EventToDispatch) bool {
+		return len(batch) == 1 &&
+			batch[0].Type == blockchain.EventTypeRemoved &&
+			batch[0].Removed.ProtocolID == "000000038011/000000/000050" &&
+			batch[0].Removed.BlockchainTXID == "0xc26df2bf1a733e9249372d61eb11bd8662d26c8129df76890b1beb2f6fa72628"
+	})).Return(nil)
+
+	var events []interface{}
+	err := json.Unmarshal(data.Bytes(), &events)
+	assert.NoError(t, err)
+	err = e.handleMessageBatch(context.Background(), 0, events)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+}
+
 func TestHandleMessageContractEventNoNamespaceHandlers(t *testing.T) {
 	data := fftypes.JSONAnyPtr(`
 [
@@ -4166,6 +4456,46 @@ func TestGetTransactionStatusSuccess(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetTransactionStatusSuccessPrivate(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	em := &coremocks.OperationCallbacks{}
+	e.callbacks = common.NewBlockchainCallbacks()
+	e.SetOperationHandler("ns1", em)
+
+	op := &core.Operation{
+		Namespace: "ns1",
+		ID:        fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+		Status:    "Pending",
+	}
+
+	httpmock.RegisterResponder("GET", `http://localhost:12345/transactions/ns1:9ffc50ff-6bfe-4502-adc7-93aea54cc059`,
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"id":              "ns1:9ffc50ff-6bfe-4502-adc7-93aea54cc059",
+			"status":          "Succeeded",
+			"transactionHash": "0x71a38acb7a5d4a970854f6d638ceb1fa10a4b59cbf4ed7674273a1a8dc8b36b8",
+			"receipt": fftypes.JSONObject{
+				"protocolId":     "000000000010/000020/000030",
+				"privacyGroupId": "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8=",
+			},
+		}))
+
+	em.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == "ns1:9ffc50ff-6bfe-4502-adc7-93aea54cc059" &&
+			update.Status == core.OpStatusSucceeded &&
+			update.Output.GetString("privacyGroupId") == "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8="
+	})).Return(nil)
+
+	status, err := e.GetTransactionStatus(context.Background(), op)
+	assert.NotNil(t, status)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+}
+
 func TestGetTransactionStatusFailed(t *testing.T) {
 	e, cancel := newTestEthereum()
 	defer cancel()
@@ -4264,3 +4594,119 @@ func TestValidateInvokeRequest(t *testing.T) {
 	err = e.ValidateInvokeRequest(context.Background(), testFFIMethod(), nil, nil, true)
 	assert.Regexp(t, "FF10443", err)
 }
+
+func TestGetNetworkStatus(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client)
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Status: "started"}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "eth_blockNumber", body["method"])
+			return httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      1,
+				"result":  "0x1b4",
+			})(req)
+		})
+
+	status, err := e.GetNetworkStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(436), status.ChainHead)
+	assert.Equal(t, "es12345", status.EventStream.ID)
+	assert.True(t, status.EventStream.Connected)
+}
+
+func TestGetNetworkStatusSuspended(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client)
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345", Suspended: true}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"result": "0x0"}))
+
+	status, err := e.GetNetworkStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, status.ChainHead)
+	assert.False(t, status.EventStream.Connected)
+}
+
+func TestGetNetworkStatusStreamFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client)
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, err := e.GetNetworkStatus(context.Background())
+	assert.Regexp(t, "FF10111", err)
+}
+
+func TestGetNetworkStatusChainHeadFail(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client)
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, err := e.GetNetworkStatus(context.Background())
+	assert.Regexp(t, "FF10111", err)
+}
+
+func TestGetNetworkStatusChainHeadRPCError(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client)
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"error": map[string]interface{}{"code": -32601, "message": "method not found"},
+		}))
+
+	_, err := e.GetNetworkStatus(context.Background())
+	assert.Regexp(t, "FF10111.*method not found", err)
+}
+
+func TestGetNetworkStatusChainHeadMissing(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client)
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{}))
+
+	_, err := e.GetNetworkStatus(context.Background())
+	assert.Regexp(t, "FF10693", err)
+}
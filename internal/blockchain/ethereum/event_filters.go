@@ -0,0 +1,103 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly-signer/pkg/ethtypes"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// resolveEventDecodingHint finds the event in a set of supplementary ABI fragments, that should be used
+// by the connector to decode events instead of the ABI generated from the FFI. This allows the full
+// ABI definition (including indexed and anonymous flags) to be supplied for events that the FFI cannot
+// describe precisely. The hint can be a single ABI entry, or an array of entries.
+func resolveEventDecodingHint(ctx context.Context, event *abi.Entry, hint *fftypes.JSONAny) (*abi.Entry, error) {
+	var fragments abi.ABI
+	hintBytes := []byte(strings.TrimSpace(hint.String()))
+	if len(hintBytes) > 0 && hintBytes[0] == '{' {
+		var entry abi.Entry
+		if err := json.Unmarshal(hintBytes, &entry); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEventDecodingHint, event.Name, err)
+		}
+		fragments = abi.ABI{&entry}
+	} else if err := json.Unmarshal(hintBytes, &fragments); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEventDecodingHint, event.Name, err)
+	}
+
+	// Prefer an entry with the same number of inputs, to distinguish overloaded events
+	var match *abi.Entry
+	for _, entry := range fragments {
+		if entry.Type == abi.Event && entry.Name == event.Name {
+			if match == nil || len(entry.Inputs) == len(event.Inputs) {
+				match = entry
+			}
+		}
+	}
+	if match == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEventDecodingHint, event.Name, "no matching event")
+	}
+	if err := match.ValidateCtx(ctx); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEventDecodingHint, event.Name, err)
+	}
+	return match, nil
+}
+
+// parseEventTopicFilters validates raw topic filters for an event, returning them in the format
+// expected by the connector. Topics are positional (as in eth_getLogs) - so for non-anonymous events
+// the first topic is the event signature hash. Empty strings are wildcards, that match any value.
+func parseEventTopicFilters(ctx context.Context, event *abi.Entry, topics []string) ([]*string, error) {
+	if len(topics) == 0 {
+		return nil, nil
+	}
+
+	maxTopics := 0
+	if !event.Anonymous {
+		maxTopics++
+	}
+	for _, input := range event.Inputs {
+		if input.Indexed {
+			maxTopics++
+		}
+	}
+	if len(topics) > maxTopics {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEventTopicFilter, event.Name, len(topics), maxTopics)
+	}
+
+	filters := make([]*string, len(topics))
+	for i, topic := range topics {
+		if topic == "" {
+			continue
+		}
+		b, err := ethtypes.NewHexBytes0xPrefix(topic)
+		if err != nil || len(b) != 32 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEventTopic, topic)
+		}
+		normalized := b.String()
+		if i == 0 && !event.Anonymous && normalized != event.SignatureHashBytes().String() {
+			return nil, i18n.NewError(ctx, coremsgs.MsgEventTopicSignatureMismatch, topic, event.Name)
+		}
+		filters[i] = &normalized
+	}
+	return filters, nil
+}
@@ -0,0 +1,254 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+const transferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+const testAddressTopic = "0x000000000000000000000000d8da6bf26964af9d7eed9e03e53415d37aa96045"
+
+func testTransferEvent(anonymous bool) *core.FFISerializedEvent {
+	event := &core.FFISerializedEvent{
+		FFIEventDefinition: fftypes.FFIEventDefinition{
+			Name: "Transfer",
+			Params: fftypes.FFIParams{
+				{Name: "from", Schema: fftypes.JSONAnyPtr(`{"type": "string", "details": {"type": "address", "indexed": true}}`)},
+				{Name: "to", Schema: fftypes.JSONAnyPtr(`{"type": "string", "details": {"type": "address", "indexed": true}}`)},
+				{Name: "value", Schema: fftypes.JSONAnyPtr(`{"type": "integer", "details": {"type": "uint256"}}`)},
+			},
+		},
+	}
+	if anonymous {
+		event.Details = fftypes.JSONObject{"anonymous": true}
+	}
+	return event
+}
+
+func testTransferABI() *abi.Entry {
+	return &abi.Entry{
+		Type: abi.Event,
+		Name: "Transfer",
+		Inputs: abi.ParameterArray{
+			{Name: "from", Type: "address", Indexed: true},
+			{Name: "to", Type: "address", Indexed: true},
+			{Name: "value", Type: "uint256"},
+		},
+	}
+}
+
+func testTransferABIJSON() string {
+	b, _ := json.Marshal(testTransferABI())
+	return string(b)
+}
+
+func TestAddContractListenerTopicFilters(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streamID = "es-1"
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	listener := &core.ContractListener{
+		Event: testTransferEvent(false),
+		Options: &core.ContractListenerOptions{
+			Topics: []string{"", "", testAddressTopic},
+		},
+	}
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/subscriptions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, []interface{}{nil, nil, testAddressTopic}, body["topics"])
+			return httpmock.NewJsonResponderOrPanic(200, &subscription{ID: "sub1"})(req)
+		})
+
+	err := e.AddContractListener(context.Background(), listener)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub1", listener.BackendID)
+}
+
+func TestAddContractListenerAnonymous(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streamID = "es-1"
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	listener := &core.ContractListener{
+		Event: testTransferEvent(true),
+		Options: &core.ContractListenerOptions{
+			Topics: []string{testAddressTopic},
+		},
+	}
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/subscriptions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, []interface{}{testAddressTopic}, body["topics"])
+			assert.Equal(t, true, body["event"].(map[string]interface{})["anonymous"])
+			return httpmock.NewJsonResponderOrPanic(200, &subscription{ID: "sub1"})(req)
+		})
+
+	err := e.AddContractListener(context.Background(), listener)
+	assert.NoError(t, err)
+}
+
+func TestAddContractListenerAnonymousNoTopics(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	err := e.AddContractListener(context.Background(), &core.ContractListener{
+		Event: testTransferEvent(true),
+	})
+	assert.Regexp(t, "FF10474", err)
+}
+
+func TestAddContractListenerDecodingHint(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streamID = "es-1"
+	e.streams = &streamManager{
+		client: e.client,
+	}
+
+	// The FFI does not describe the indexed params, so the hint supplies the full ABI
+	listener := &core.ContractListener{
+		Event: &core.FFISerializedEvent{
+			FFIEventDefinition: fftypes.FFIEventDefinition{
+				Name: "Transfer",
+			},
+		},
+		Options: &core.ContractListenerOptions{
+			Topics:       []string{transferTopic, testAddressTopic},
+			DecodingHint: fftypes.JSONAnyPtr(`[{"type":"function","name":"Transfer"},` + testTransferABIJSON() + `]`),
+		},
+	}
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/subscriptions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Len(t, body["event"].(map[string]interface{})["inputs"], 3)
+			assert.Equal(t, []interface{}{transferTopic, testAddressTopic}, body["topics"])
+			return httpmock.NewJsonResponderOrPanic(200, &subscription{ID: "sub1"})(req)
+		})
+
+	err := e.AddContractListener(context.Background(), listener)
+	assert.NoError(t, err)
+}
+
+func TestAddContractListenerBadDecodingHint(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	err := e.AddContractListener(context.Background(), &core.ContractListener{
+		Event: testTransferEvent(false),
+		Options: &core.ContractListenerOptions{
+			DecodingHint: fftypes.JSONAnyPtr(`"bad"`),
+		},
+	})
+	assert.Regexp(t, "FF10470", err)
+}
+
+func TestAddContractListenerBadTopics(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+
+	err := e.AddContractListener(context.Background(), &core.ContractListener{
+		Event: testTransferEvent(false),
+		Options: &core.ContractListenerOptions{
+			Topics: []string{"0x1234"},
+		},
+	})
+	assert.Regexp(t, "FF10472", err)
+}
+
+func TestResolveEventDecodingHint(t *testing.T) {
+	ctx := context.Background()
+	event := testTransferABI()
+
+	// A single entry
+	match, err := resolveEventDecodingHint(ctx, &abi.Entry{Name: "Transfer"}, fftypes.JSONAnyPtr(testTransferABIJSON()))
+	assert.NoError(t, err)
+	assert.Len(t, match.Inputs, 3)
+
+	// Overloads are matched on the number of inputs
+	overload := &abi.Entry{Type: abi.Event, Name: "Transfer", Inputs: abi.ParameterArray{{Name: "value", Type: "uint256"}}}
+	hint, _ := json.Marshal(abi.ABI{event, overload})
+	match, err = resolveEventDecodingHint(ctx, &abi.Entry{Name: "Transfer", Inputs: abi.ParameterArray{{Type: "uint256"}}}, fftypes.JSONAnyPtrBytes(hint))
+	assert.NoError(t, err)
+	assert.Len(t, match.Inputs, 1)
+
+	_, err = resolveEventDecodingHint(ctx, &abi.Entry{Name: "Approval"}, fftypes.JSONAnyPtrBytes(hint))
+	assert.Regexp(t, "FF10470.*no matching event", err)
+
+	_, err = resolveEventDecodingHint(ctx, &abi.Entry{Name: "Transfer"}, fftypes.JSONAnyPtr(`{"type":"event","name":"Transfer","inputs":[{"type":"wrong"}]}`))
+	assert.Regexp(t, "FF10470", err)
+
+	_, err = resolveEventDecodingHint(ctx, &abi.Entry{Name: "Transfer"}, fftypes.JSONAnyPtr(`{!json`))
+	assert.Regexp(t, "FF10470", err)
+}
+
+func TestParseEventTopicFilters(t *testing.T) {
+	ctx := context.Background()
+	event := testTransferABI()
+
+	topics, err := parseEventTopicFilters(ctx, event, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, topics)
+
+	topics, err = parseEventTopicFilters(ctx, event, []string{"0xDDF252AD1BE2C89B69C2B068FC378DAA952BA7F163C4A11628F55A4DF523B3EF", "", testAddressTopic})
+	assert.NoError(t, err)
+	assert.Equal(t, transferTopic, *topics[0])
+	assert.Nil(t, topics[1])
+	assert.Equal(t, testAddressTopic, *topics[2])
+
+	_, err = parseEventTopicFilters(ctx, event, []string{"", "", "", ""})
+	assert.Regexp(t, "FF10471.*4 supplied.*at most 3", err)
+
+	_, err = parseEventTopicFilters(ctx, event, []string{testAddressTopic})
+	assert.Regexp(t, "FF10473", err)
+
+	_, err = parseEventTopicFilters(ctx, event, []string{"!hex"})
+	assert.Regexp(t, "FF10472", err)
+
+	event.Anonymous = true
+	topics, err = parseEventTopicFilters(ctx, event, []string{testAddressTopic, testAddressTopic})
+	assert.NoError(t, err)
+	assert.Len(t, topics, 2)
+}
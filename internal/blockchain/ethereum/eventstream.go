@@ -49,6 +49,8 @@ type eventStream struct {
 	Type           string               `json:"type"`
 	WebSocket      eventStreamWebsocket `json:"websocket"`
 	Timestamps     bool                 `json:"timestamps"`
+	Suspended      bool                 `json:"suspended,omitempty"` // ethconnect
+	Status         string               `json:"status,omitempty"`    // evmconnect
 }
 
 type subscription struct {
@@ -58,7 +60,9 @@ type subscription struct {
 	FromBlock        string            `json:"fromBlock"`
 	EthCompatAddress string            `json:"address,omitempty"`
 	EthCompatEvent   *abi.Entry        `json:"event,omitempty"`
+	EthCompatTopics  []*string         `json:"topics,omitempty"`
 	Filters          []fftypes.JSONAny `json:"filters"`
+	Confirmations    int               `json:"confirmations,omitempty"`
 	subscriptionCheckpoint
 }
 
@@ -87,6 +91,17 @@ func (s *streamManager) getEventStreams(ctx context.Context) (streams []*eventSt
 	return streams, nil
 }
 
+func (s *streamManager) getEventStream(ctx context.Context, eventStreamID string) (stream *eventStream, err error) {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&stream).
+		Get("/eventstreams/" + eventStreamID)
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgEthConnectorRESTErr)
+	}
+	return stream, nil
+}
+
 func buildEventStream(topic string, batchSize, batchTimeout uint) *eventStream {
 	return &eventStream{
 		Name:           topic,
@@ -182,7 +197,7 @@ func (s *streamManager) getSubscriptionName(ctx context.Context, subID string) (
 	return sub.Name, nil
 }
 
-func (s *streamManager) createSubscription(ctx context.Context, location *Location, stream, subName, firstEvent string, abi *abi.Entry) (*subscription, error) {
+func (s *streamManager) createSubscription(ctx context.Context, location *Location, stream, subName, firstEvent string, confirmations int, abi *abi.Entry, topics []*string) (*subscription, error) {
 	// Map FireFly "firstEvent" values to Ethereum "fromBlock" values
 	switch firstEvent {
 	case string(core.SubOptsFirstEventOldest):
@@ -191,10 +206,12 @@ func (s *streamManager) createSubscription(ctx context.Context, location *Locati
 		firstEvent = "latest"
 	}
 	sub := subscription{
-		Name:           subName,
-		Stream:         stream,
-		FromBlock:      firstEvent,
-		EthCompatEvent: abi,
+		Name:            subName,
+		Stream:          stream,
+		FromBlock:       firstEvent,
+		EthCompatEvent:  abi,
+		EthCompatTopics: topics,
+		Confirmations:   confirmations,
 	}
 
 	if location != nil {
@@ -267,7 +284,7 @@ func (s *streamManager) ensureFireFlySubscription(ctx context.Context, namespace
 		name = v1Name
 	}
 	location := &Location{Address: instancePath}
-	if sub, err = s.createSubscription(ctx, location, stream, name, firstEvent, abi); err != nil {
+	if sub, err = s.createSubscription(ctx, location, stream, name, firstEvent, 0, abi, nil); err != nil {
 		return nil, err
 	}
 	log.L(ctx).Infof("%s subscription: %s", abi.Name, sub.ID)
@@ -0,0 +1,63 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// Options understood by the connector for private transactions, such as on Besu with Tessera.
+// These are passed through to the connector, but validated first so that a malformed request
+// fails synchronously rather than being rejected by the private transaction manager.
+const (
+	privateFromOption    = "privateFrom"
+	privateForOption     = "privateFor"
+	privacyGroupIDOption = "privacyGroupId"
+)
+
+func validatePrivacyOptions(ctx context.Context, options map[string]interface{}) error {
+	if v, ok := options[privateFromOption]; ok {
+		if _, isString := v.(string); !isString {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidPrivacyOption, privateFromOption, "string")
+		}
+	}
+	if v, ok := options[privacyGroupIDOption]; ok {
+		if _, isString := v.(string); !isString {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidPrivacyOption, privacyGroupIDOption, "string")
+		}
+		if _, hasPrivateFor := options[privateForOption]; hasPrivateFor {
+			return i18n.NewError(ctx, coremsgs.MsgConflictingPrivacyOptions, privateForOption, privacyGroupIDOption)
+		}
+	}
+	if v, ok := options[privateForOption]; ok {
+		switch vt := v.(type) {
+		case []string:
+		case []interface{}:
+			for _, recipient := range vt {
+				if _, isString := recipient.(string); !isString {
+					return i18n.NewError(ctx, coremsgs.MsgInvalidPrivacyOption, privateForOption, "array of strings")
+				}
+			}
+		default:
+			return i18n.NewError(ctx, coremsgs.MsgInvalidPrivacyOption, privateForOption, "array of strings")
+		}
+	}
+	return nil
+}
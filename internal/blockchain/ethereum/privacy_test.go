@@ -0,0 +1,74 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePrivacyOptionsOK(t *testing.T) {
+	ctx := context.Background()
+	assert.NoError(t, validatePrivacyOptions(ctx, nil))
+	assert.NoError(t, validatePrivacyOptions(ctx, map[string]interface{}{
+		"privateFrom": "A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo=",
+		"privateFor":  []interface{}{"Ko2bVqD+nNlNYL5EE7y3IdOnviftjiizpjRt+HTuFBs="},
+	}))
+	assert.NoError(t, validatePrivacyOptions(ctx, map[string]interface{}{
+		"privateFor": []string{"Ko2bVqD+nNlNYL5EE7y3IdOnviftjiizpjRt+HTuFBs="},
+	}))
+	assert.NoError(t, validatePrivacyOptions(ctx, map[string]interface{}{
+		"privateFrom":    "A1aVtMxLCUHmBVHXoZzzBgPbW/wj5axDpW9X8l91SGo=",
+		"privacyGroupId": "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8=",
+	}))
+}
+
+func TestValidatePrivacyOptionsBadPrivateFrom(t *testing.T) {
+	err := validatePrivacyOptions(context.Background(), map[string]interface{}{
+		"privateFrom": 12345,
+	})
+	assert.Regexp(t, "FF10458.*privateFrom", err)
+}
+
+func TestValidatePrivacyOptionsBadPrivacyGroup(t *testing.T) {
+	err := validatePrivacyOptions(context.Background(), map[string]interface{}{
+		"privacyGroupId": []string{"group1"},
+	})
+	assert.Regexp(t, "FF10458.*privacyGroupId", err)
+}
+
+func TestValidatePrivacyOptionsBadPrivateFor(t *testing.T) {
+	err := validatePrivacyOptions(context.Background(), map[string]interface{}{
+		"privateFor": "Ko2bVqD+nNlNYL5EE7y3IdOnviftjiizpjRt+HTuFBs=",
+	})
+	assert.Regexp(t, "FF10458.*privateFor", err)
+
+	err = validatePrivacyOptions(context.Background(), map[string]interface{}{
+		"privateFor": []interface{}{12345},
+	})
+	assert.Regexp(t, "FF10458.*privateFor", err)
+}
+
+func TestValidatePrivacyOptionsConflict(t *testing.T) {
+	err := validatePrivacyOptions(context.Background(), map[string]interface{}{
+		"privateFor":     []interface{}{"Ko2bVqD+nNlNYL5EE7y3IdOnviftjiizpjRt+HTuFBs="},
+		"privacyGroupId": "DyAOiF/ynpc+JXa9YY6Uj8OA3DoVE+JHZWMondp9Ps8=",
+	})
+	assert.Regexp(t, "FF10459", err)
+}
@@ -0,0 +1,143 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// queryQuorum fans out contract queries to multiple independent connector endpoints, and only
+// returns a result when enough of them agree. This protects against a single compromised or
+// lagging RPC provider feeding stale state into business logic.
+type queryQuorum struct {
+	clients     []*resty.Client
+	minMatching int
+}
+
+type quorumResult struct {
+	index  int
+	output interface{}
+	key    string
+	err    error
+}
+
+func newQueryQuorum(ctx context.Context, conf config.Section, primary *resty.Client) (*queryQuorum, error) {
+	urls := conf.GetStringSlice(EthconnectQueryQuorumURLs)
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	// Additional endpoints share the HTTP config (auth, TLS etc.) of the primary connector
+	clients := []*resty.Client{primary}
+	for _, u := range urls {
+		client, err := ffresty.New(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		clients = append(clients, client.SetBaseURL(u))
+	}
+
+	minMatching := conf.GetInt(EthconnectQueryQuorumMinMatching)
+	if minMatching == 0 {
+		minMatching = len(clients)/2 + 1
+	}
+	// A single endpoint must not be able to decide the result on its own
+	if minMatching < 2 || minMatching > len(clients) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidQueryQuorum, minMatching, len(clients))
+	}
+	return &queryQuorum{
+		clients:     clients,
+		minMatching: minMatching,
+	}, nil
+}
+
+func (q *queryQuorum) queryEndpoint(ctx context.Context, client *resty.Client, body interface{}) (result quorumResult) {
+	var resErr ethError
+	res, err := client.R().
+		SetContext(ctx).
+		SetBody(body).
+		SetError(&resErr).
+		Post("/")
+	if err != nil || !res.IsSuccess() {
+		result.err = wrapError(ctx, &resErr, res, err)
+		return result
+	}
+	if result.err = json.Unmarshal(res.Body(), &result.output); result.err != nil {
+		return result
+	}
+	// Re-serialize to get a canonical form for comparison (map keys are sorted)
+	key, _ := json.Marshal(result.output)
+	result.key = string(key)
+	return result
+}
+
+func (q *queryQuorum) query(ctx context.Context, body interface{}) (interface{}, error) {
+	// The outstanding queries are cancelled as soon as enough endpoints agree, and have
+	// finished by the time the result is returned
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	queryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan quorumResult, len(q.clients))
+	for i, client := range q.clients {
+		wg.Add(1)
+		go func(i int, client *resty.Client) {
+			defer wg.Done()
+			result := q.queryEndpoint(queryCtx, client, body)
+			result.index = i
+			results <- result
+		}(i, client)
+	}
+
+	errs := make([]error, len(q.clients))
+	matches := make(map[string]int)
+	best := 0
+	for range q.clients {
+		result := <-results
+		if result.err != nil {
+			log.L(ctx).Warnf("Query to connector endpoint %s failed: %s", q.clients[result.index].BaseURL, result.err)
+			errs[result.index] = result.err
+			continue
+		}
+		matches[result.key]++
+		if matches[result.key] >= q.minMatching {
+			return result.output, nil
+		}
+		if matches[result.key] > best {
+			best = matches[result.key]
+		}
+	}
+	if len(matches) == 0 {
+		// Every endpoint failed, so the error is more useful than a quorum failure - preferring that of the primary
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	log.L(ctx).Warnf("Query results did not reach quorum: %d distinct results from %d endpoints", len(matches), len(q.clients))
+	return nil, i18n.NewError(ctx, coremsgs.MsgQueryQuorumNotReached, best, len(q.clients), q.minMatching)
+}
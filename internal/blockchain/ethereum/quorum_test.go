@@ -0,0 +1,197 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethereum
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestQueryQuorum(t *testing.T, e *Ethereum, minMatching int) func() {
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectQueryQuorumURLs, []string{"http://endpoint2:12345", "http://endpoint3:12345"})
+	utEthconnectConf.Set(EthconnectQueryQuorumMinMatching, minMatching)
+
+	var err error
+	e.client, err = ffresty.New(e.ctx, utEthconnectConf)
+	assert.NoError(t, err)
+	e.queryQuorum, err = newQueryQuorum(e.ctx, utEthconnectConf, e.client)
+	assert.NoError(t, err)
+	return httpmock.DeactivateAndReset
+}
+
+func TestNewQueryQuorumNotConfigured(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+
+	q, err := newQueryQuorum(e.ctx, utEthconnectConf, e.client)
+	assert.NoError(t, err)
+	assert.Nil(t, q)
+}
+
+func TestNewQueryQuorumDefaultMajority(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	defer newTestQueryQuorum(t, e, 0)()
+
+	assert.Len(t, e.queryQuorum.clients, 3)
+	assert.Equal(t, 2, e.queryQuorum.minMatching)
+	assert.Equal(t, "http://endpoint3:12345", e.queryQuorum.clients[2].BaseURL)
+}
+
+func TestNewQueryQuorumInvalidMinMatching(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+	utEthconnectConf.Set(EthconnectQueryQuorumURLs, []string{"http://endpoint2:12345"})
+	utEthconnectConf.Set(EthconnectQueryQuorumMinMatching, 3)
+
+	_, err := newQueryQuorum(e.ctx, utEthconnectConf, e.client)
+	assert.Regexp(t, "FF10468", err)
+}
+
+func TestNewQueryQuorumSingleMatching(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+	utEthconnectConf.Set(EthconnectQueryQuorumURLs, []string{"http://endpoint2:12345"})
+	utEthconnectConf.Set(EthconnectQueryQuorumMinMatching, 1)
+
+	_, err := newQueryQuorum(e.ctx, utEthconnectConf, e.client)
+	assert.Regexp(t, "FF10468.*1 matching results required from 2", err)
+}
+
+func TestNewQueryQuorumBadTLS(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+	utEthconnectConf.Set(EthconnectQueryQuorumURLs, []string{"http://endpoint2:12345"})
+	utEthconnectConf.Set("tls.enabled", true)
+	utEthconnectConf.Set("tls.caFile", "!!!badness")
+
+	_, err := newQueryQuorum(e.ctx, utEthconnectConf, e.client)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestQueryContractQuorumOK(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	defer newTestQueryQuorum(t, e, 2)()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"output": "3", "other": "a"}))
+	httpmock.RegisterResponder("POST", "http://endpoint2:12345/",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"output": "4"}))
+	httpmock.RegisterResponder("POST", "http://endpoint3:12345/",
+		httpmock.NewStringResponder(200, `{"other":"a","output":"3"}`))
+
+	result, err := e.QueryContract(context.Background(), "0x01020304", fftypes.JSONAnyPtr(`{"address":"0x12345"}`), testFFIMethod(), map[string]interface{}{}, testFFIErrors(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"output": "3", "other": "a"}, result)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestQueryContractQuorumReturnsEarly(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	defer newTestQueryQuorum(t, e, 2)()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"output": "3"}))
+	httpmock.RegisterResponder("POST", "http://endpoint2:12345/",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"output": "3"}))
+	// The slow endpoint is cancelled once the others agree, rather than holding up the result
+	httpmock.RegisterResponder("POST", "http://endpoint3:12345/", func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+
+	result, err := e.QueryContract(context.Background(), "0x01020304", fftypes.JSONAnyPtr(`{"address":"0x12345"}`), testFFIMethod(), map[string]interface{}{}, testFFIErrors(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"output": "3"}, result)
+}
+
+func TestQueryContractQuorumNotReached(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	defer newTestQueryQuorum(t, e, 2)()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"output": "3"}))
+	httpmock.RegisterResponder("POST", "http://endpoint2:12345/",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"output": "4"}))
+	httpmock.RegisterResponder("POST", "http://endpoint3:12345/",
+		httpmock.NewJsonResponderOrPanic(500, ethError{Error: "pop"}))
+
+	_, err := e.QueryContract(context.Background(), "0x01020304", fftypes.JSONAnyPtr(`{"address":"0x12345"}`), testFFIMethod(), map[string]interface{}{}, testFFIErrors(), nil)
+	assert.Regexp(t, "FF10469.*1 of 3.*2 required", err)
+}
+
+func TestQueryContractQuorumAllFailed(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	defer newTestQueryQuorum(t, e, 2)()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewJsonResponderOrPanic(500, ethError{Error: "pop"}))
+	httpmock.RegisterResponder("POST", "http://endpoint2:12345/",
+		httpmock.NewStringResponder(200, "!json"))
+	httpmock.RegisterResponder("POST", "http://endpoint3:12345/",
+		httpmock.NewJsonResponderOrPanic(500, ethError{Error: "pop"}))
+
+	_, err := e.QueryContract(context.Background(), "0x01020304", fftypes.JSONAnyPtr(`{"address":"0x12345"}`), testFFIMethod(), map[string]interface{}{}, testFFIErrors(), nil)
+	assert.Regexp(t, "FF10111.*pop", err)
+}
+
+func TestQueryContractQuorumBadOptions(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	defer newTestQueryQuorum(t, e, 2)()
+
+	options := map[string]interface{}{
+		"params": "shouldn't be allowed",
+	}
+	_, err := e.QueryContract(context.Background(), "0x01020304", fftypes.JSONAnyPtr(`{"address":"0x12345"}`), testFFIMethod(), map[string]interface{}{}, testFFIErrors(), options)
+	assert.Regexp(t, "FF10398", err)
+	assert.Zero(t, httpmock.GetTotalCallCount())
+}
+
+func TestInitQueryQuorumInvalid(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+	utEthconnectConf.Set(EthconnectQueryQuorumURLs, []string{"http://endpoint2:12345"})
+	utEthconnectConf.Set(EthconnectQueryQuorumMinMatching, -1)
+
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10468", err)
+}
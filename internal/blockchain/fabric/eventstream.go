@@ -46,6 +46,7 @@ type eventStream struct {
 	Type           string               `json:"type"`
 	WebSocket      eventStreamWebsocket `json:"websocket"`
 	Timestamps     bool                 `json:"timestamps"`
+	Suspended      bool                 `json:"suspended,omitempty"`
 }
 
 type subscription struct {
@@ -111,6 +112,17 @@ func (s *streamManager) createEventStream(ctx context.Context, topic string) (*e
 	return stream, nil
 }
 
+func (s *streamManager) getEventStream(ctx context.Context, eventStreamID string) (stream *eventStream, err error) {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&stream).
+		Get("/eventstreams/" + eventStreamID)
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
+	}
+	return stream, nil
+}
+
 func (s *streamManager) ensureEventStream(ctx context.Context, topic string) (*eventStream, error) {
 	existingStreams, err := s.getEventStreams(ctx)
 	if err != nil {
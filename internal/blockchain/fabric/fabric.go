@@ -427,6 +427,10 @@ func (f *Fabric) AddFireflySubscription(ctx context.Context, namespace *core.Nam
 	if err != nil {
 		return "", err
 	}
+	if contract.ChainID != "" {
+		// Fabric networks have no chain ID - the channel in the location identifies the ledger
+		return "", i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+	}
 
 	version, err := f.GetNetworkVersion(ctx, contract.Location)
 	if err != nil {
@@ -998,6 +1002,20 @@ func (f *Fabric) GetAndConvertDeprecatedContractConfig(ctx context.Context) (loc
 	return location, fromBlock, err
 }
 
+func (f *Fabric) GetNetworkStatus(ctx context.Context) (*core.BlockchainNetworkStatus, error) {
+	stream, err := f.streams.getEventStream(ctx, f.streamID)
+	if err != nil {
+		return nil, err
+	}
+	// Fabconnect does not report a chain head
+	return &core.BlockchainNetworkStatus{
+		EventStream: &core.EventStreamNetworkStatus{
+			ID:        stream.ID,
+			Connected: !stream.Suspended,
+		},
+	}, nil
+}
+
 func (f *Fabric) GetTransactionStatus(ctx context.Context, operation *core.Operation) (interface{}, error) {
 	txHash := operation.Output.GetString("transactionHash")
 
@@ -3313,3 +3313,51 @@ func TestValidateInvokeRequest(t *testing.T) {
 	err := e.ValidateInvokeRequest(context.Background(), nil, nil, nil, false)
 	assert.NoError(t, err)
 }
+
+func TestGetNetworkStatus(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer")
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+
+	status, err := e.GetNetworkStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, status.ChainHead)
+	assert.Equal(t, "es12345", status.EventStream.ID)
+	assert.True(t, status.EventStream.Connected)
+}
+
+func TestGetNetworkStatusFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer")
+	e.streamID = "es12345"
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, err := e.GetNetworkStatus(context.Background())
+	assert.Regexp(t, "FF10284", err)
+}
+
+func TestAddFireflySubscriptionChainIDUnsupported(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+	_, err := e.AddFireflySubscription(e.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location: location,
+		ChainID:  "1337",
+	})
+	assert.Regexp(t, "FF10429", err)
+}
@@ -0,0 +1,64 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hedera
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+)
+
+const (
+	defaultBatchSize    = 50
+	defaultBatchTimeout = 500
+
+	defaultBackgroundInitialDelay = "5s"
+	defaultBackgroundRetryFactor  = 2.0
+	defaultBackgroundMaxDelay     = "1m"
+)
+
+const (
+	// HederaconnectConfigKey is a sub-key in the config to contain all the Hedera connector specific config
+	HederaconnectConfigKey = "hederaconnect"
+
+	// HederaconnectConfigTopic is the websocket listen topic that the node should register on, which is important if there are multiple
+	// nodes using a single Hedera connector
+	HederaconnectConfigTopic = "topic"
+	// HederaconnectConfigBatchSize is the batch size to configure on event streams, when auto-defining them
+	HederaconnectConfigBatchSize = "batchSize"
+	// HederaconnectConfigBatchTimeout is the batch timeout to configure on event streams, when auto-defining them
+	HederaconnectConfigBatchTimeout = "batchTimeout"
+	// HederaconnectBackgroundStart is used to not fail the hedera plugin on init and retry to start it in the background
+	HederaconnectBackgroundStart = "backgroundStart.enabled"
+	// HederaconnectBackgroundStartInitialDelay is delay between restarts in the case where we retry to restart in the hedera plugin
+	HederaconnectBackgroundStartInitialDelay = "backgroundStart.initialDelay"
+	// HederaconnectBackgroundStartMaxDelay is the max delay between restarts in the case where we retry to restart in the hedera plugin
+	HederaconnectBackgroundStartMaxDelay = "backgroundStart.maxDelay"
+	// HederaconnectBackgroundStartFactor is to set the factor by which the delay increases when retrying
+	HederaconnectBackgroundStartFactor = "backgroundStart.factor"
+)
+
+func (h *Hedera) InitConfig(config config.Section) {
+	h.hederaconnectConf = config.SubSection(HederaconnectConfigKey)
+	wsclient.InitConfig(h.hederaconnectConf)
+	h.hederaconnectConf.AddKnownKey(HederaconnectConfigTopic)
+	h.hederaconnectConf.AddKnownKey(HederaconnectConfigBatchSize, defaultBatchSize)
+	h.hederaconnectConf.AddKnownKey(HederaconnectConfigBatchTimeout, defaultBatchTimeout)
+	h.hederaconnectConf.AddKnownKey(HederaconnectBackgroundStart)
+	h.hederaconnectConf.AddKnownKey(HederaconnectBackgroundStartFactor, defaultBackgroundRetryFactor)
+	h.hederaconnectConf.AddKnownKey(HederaconnectBackgroundStartInitialDelay, defaultBackgroundInitialDelay)
+	h.hederaconnectConf.AddKnownKey(HederaconnectBackgroundStartMaxDelay, defaultBackgroundMaxDelay)
+}
@@ -0,0 +1,174 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hedera
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type streamManager struct {
+	client         *resty.Client
+	batchSize      uint
+	batchTimeoutMS uint
+}
+
+type eventStream struct {
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	ErrorHandling  string               `json:"errorHandling"`
+	BatchSize      uint                 `json:"batchSize"`
+	BatchTimeoutMS uint                 `json:"batchTimeoutMS"`
+	Type           string               `json:"type"`
+	WebSocket      eventStreamWebsocket `json:"websocket"`
+	Suspended      bool                 `json:"suspended,omitempty"`
+}
+
+type subscription struct {
+	ID           string `json:"id"`
+	Name         string `json:"name,omitempty"`
+	Stream       string `json:"stream"`
+	TopicID      string `json:"topicId"`
+	FromSequence string `json:"fromSequence"`
+}
+
+func newStreamManager(client *resty.Client, batchSize, batchTimeout uint) *streamManager {
+	return &streamManager{
+		client:         client,
+		batchSize:      batchSize,
+		batchTimeoutMS: batchTimeout,
+	}
+}
+
+func (s *streamManager) getEventStreams(ctx context.Context) (streams []*eventStream, err error) {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&streams).
+		Get("/eventstreams")
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgHederaconnectRESTErr)
+	}
+	return streams, nil
+}
+
+func (s *streamManager) createEventStream(ctx context.Context, topic string) (*eventStream, error) {
+	stream := &eventStream{
+		Name:           topic,
+		ErrorHandling:  "block",
+		BatchSize:      s.batchSize,
+		BatchTimeoutMS: s.batchTimeoutMS,
+		Type:           "websocket",
+		WebSocket:      eventStreamWebsocket{Topic: topic},
+	}
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetBody(stream).
+		SetResult(stream).
+		Post("/eventstreams")
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgHederaconnectRESTErr)
+	}
+	return stream, nil
+}
+
+func (s *streamManager) getEventStream(ctx context.Context, eventStreamID string) (stream *eventStream, err error) {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&stream).
+		Get("/eventstreams/" + eventStreamID)
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgHederaconnectRESTErr)
+	}
+	return stream, nil
+}
+
+func (s *streamManager) ensureEventStream(ctx context.Context, topic string) (*eventStream, error) {
+	existingStreams, err := s.getEventStreams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, stream := range existingStreams {
+		if stream.Name == topic {
+			return stream, nil
+		}
+	}
+	return s.createEventStream(ctx, topic)
+}
+
+func (s *streamManager) getSubscriptions(ctx context.Context) (subs []*subscription, err error) {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&subs).
+		Get("/subscriptions")
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgHederaconnectRESTErr)
+	}
+	return subs, nil
+}
+
+func (s *streamManager) createSubscription(ctx context.Context, location *Location, stream, name, firstEvent string) (*subscription, error) {
+	// Map FireFly "firstEvent" values to HCS topic sequence numbers
+	switch firstEvent {
+	case string(core.SubOptsFirstEventOldest):
+		firstEvent = "0"
+	case string(core.SubOptsFirstEventNewest):
+		firstEvent = "latest"
+	}
+	sub := subscription{
+		Name:         name,
+		Stream:       stream,
+		TopicID:      location.TopicID,
+		FromSequence: firstEvent,
+	}
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetBody(&sub).
+		SetResult(&sub).
+		Post("/subscriptions")
+	if err != nil || !res.IsSuccess() {
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgHederaconnectRESTErr)
+	}
+	return &sub, nil
+}
+
+// ensureFireFlySubscription ensures a subscription to the HCS topic used for pinning by a namespace.
+// Each namespace has its own topic, so there are no shared (V1 style) subscriptions.
+func (s *streamManager) ensureFireFlySubscription(ctx context.Context, namespace string, location *Location, firstEvent, stream string) (sub *subscription, err error) {
+	existingSubs, err := s.getSubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := fmt.Sprintf("%s_%s_%s", namespace, batchPinSubscriptionName, location.TopicID)
+	for _, s := range existingSubs {
+		if s.Stream == stream && s.Name == name {
+			return s, nil
+		}
+	}
+
+	if sub, err = s.createSubscription(ctx, location, stream, name, firstEvent); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("%s subscription: %s", batchPinSubscriptionName, sub.ID)
+	return sub, nil
+}
@@ -0,0 +1,617 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hedera
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	batchPinSubscriptionName = "BatchPin"
+	// Each namespace pins to its own HCS topic, so the namespace is never passed in the message (as with V2 contracts)
+	hederaNetworkVersion = 2
+)
+
+// Hedera is a blockchain plugin that uses messages on a Hedera Consensus Service (HCS) topic to pin batches,
+// instead of a smart contract. Smart contracts on Hedera are EVM based, and are supported via the ethereum
+// plugin through a JSON-RPC relay. Tokens on the Hedera Token Service are supported via a token connector.
+type Hedera struct {
+	ctx               context.Context
+	cancelCtx         context.CancelFunc
+	topic             string
+	capabilities      *blockchain.Capabilities
+	callbacks         common.BlockchainCallbacks
+	client            *resty.Client
+	streams           *streamManager
+	streamID          string
+	wsconn            wsclient.WSClient
+	closed            chan struct{}
+	metrics           metrics.Manager
+	hederaconnectConf config.Section
+	subs              common.FireflySubscriptions
+	backgroundRetry   *retry.Retry
+	backgroundStart   bool
+}
+
+type eventStreamWebsocket struct {
+	Topic string `json:"topic"`
+}
+
+type hederaWSCommandPayload struct {
+	Type  string `json:"type"`
+	Topic string `json:"topic,omitempty"`
+}
+
+type hederaError struct {
+	Error string `json:"error,omitempty"`
+}
+
+type hederaMessageHeaders struct {
+	ID   string `json:"id,omitempty"`
+	Type string `json:"type"`
+}
+
+// hcsPinMessage is the JSON content of each message FireFly submits to an HCS topic
+type hcsPinMessage struct {
+	Action     string   `json:"action,omitempty"`
+	UUIDs      string   `json:"uuids,omitempty"`
+	BatchHash  string   `json:"batchHash,omitempty"`
+	PayloadRef string   `json:"payloadRef,omitempty"`
+	Contexts   []string `json:"contexts,omitempty"`
+}
+
+type Location struct {
+	TopicID string `json:"topicId"`
+}
+
+// Hedera entity IDs (accounts and topics) are in the format shard.realm.num
+var entityIDPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+func (h *Hedera) Name() string {
+	return "hedera"
+}
+
+func (h *Hedera) VerifierType() core.VerifierType {
+	return core.VerifierTypeHederaAccountID
+}
+
+func (h *Hedera) Init(ctx context.Context, cancelCtx context.CancelFunc, conf config.Section, metrics metrics.Manager, cacheManager cache.Manager) (err error) {
+	h.InitConfig(conf)
+	hederaconnectConf := h.hederaconnectConf
+
+	h.ctx = log.WithLogField(ctx, "proto", "hedera")
+	h.cancelCtx = cancelCtx
+	h.metrics = metrics
+	h.capabilities = &blockchain.Capabilities{}
+	h.callbacks = common.NewBlockchainCallbacks()
+	h.subs = common.NewFireflySubscriptions()
+
+	if hederaconnectConf.GetString(ffresty.HTTPConfigURL) == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "url", "blockchain.hedera.hederaconnect")
+	}
+
+	wsConfig, err := wsclient.GenerateConfig(ctx, hederaconnectConf)
+	if err == nil {
+		h.client, err = ffresty.New(h.ctx, hederaconnectConf)
+	}
+	if err != nil {
+		return err
+	}
+
+	h.topic = hederaconnectConf.GetString(HederaconnectConfigTopic)
+	if h.topic == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "topic", "blockchain.hedera.hederaconnect")
+	}
+
+	if wsConfig.WSKeyPath == "" {
+		wsConfig.WSKeyPath = "/ws"
+	}
+	h.wsconn, err = wsclient.New(h.ctx, wsConfig, nil, h.afterConnect)
+	if err != nil {
+		return err
+	}
+
+	h.streams = newStreamManager(h.client, hederaconnectConf.GetUint(HederaconnectConfigBatchSize), uint(hederaconnectConf.GetDuration(HederaconnectConfigBatchTimeout).Milliseconds()))
+
+	h.backgroundStart = hederaconnectConf.GetBool(HederaconnectBackgroundStart)
+	if h.backgroundStart {
+		h.backgroundRetry = &retry.Retry{
+			InitialDelay: hederaconnectConf.GetDuration(HederaconnectBackgroundStartInitialDelay),
+			MaximumDelay: hederaconnectConf.GetDuration(HederaconnectBackgroundStartMaxDelay),
+			Factor:       hederaconnectConf.GetFloat64(HederaconnectBackgroundStartFactor),
+		}
+		return nil
+	}
+
+	stream, err := h.streams.ensureEventStream(h.ctx, h.topic)
+	if err != nil {
+		return err
+	}
+	h.streamID = stream.ID
+	log.L(h.ctx).Infof("Event stream: %s", h.streamID)
+
+	h.closed = make(chan struct{})
+	go h.eventLoop()
+
+	return nil
+}
+
+func (h *Hedera) SetHandler(namespace string, handler blockchain.Callbacks) {
+	h.callbacks.SetHandler(namespace, handler)
+}
+
+func (h *Hedera) SetOperationHandler(namespace string, handler core.OperationCallbacks) {
+	h.callbacks.SetOperationalHandler(namespace, handler)
+}
+
+func (h *Hedera) backgroundStartLoop() {
+	_ = h.backgroundRetry.Do(h.ctx, fmt.Sprintf("hedera connector %s", h.Name()), func(attempt int) (retry bool, err error) {
+		stream, err := h.streams.ensureEventStream(h.ctx, h.topic)
+		if err != nil {
+			return true, err
+		}
+
+		h.streamID = stream.ID
+		log.L(h.ctx).Infof("Event stream: %s (topic=%s)", h.streamID, h.topic)
+
+		err = h.wsconn.Connect()
+		if err != nil {
+			return true, err
+		}
+
+		h.closed = make(chan struct{})
+		go h.eventLoop()
+
+		return false, nil
+	})
+}
+
+func (h *Hedera) Start() (err error) {
+	if h.backgroundStart {
+		go h.backgroundStartLoop()
+		return nil
+	}
+	return h.wsconn.Connect()
+}
+
+func (h *Hedera) Capabilities() *blockchain.Capabilities {
+	return h.capabilities
+}
+
+func (h *Hedera) afterConnect(ctx context.Context, w wsclient.WSClient) error {
+	// Send a subscribe to our topic after each connect/reconnect
+	b, _ := json.Marshal(&hederaWSCommandPayload{
+		Type:  "listen",
+		Topic: h.topic,
+	})
+	err := w.Send(ctx, b)
+	if err == nil {
+		b, _ = json.Marshal(&hederaWSCommandPayload{
+			Type: "listenreplies",
+		})
+		err = w.Send(ctx, b)
+	}
+	return err
+}
+
+// parseConsensusTimestamp parses an HCS consensus timestamp, in the format seconds.nanoseconds
+func parseConsensusTimestamp(ctx context.Context, timestamp string) *fftypes.FFTime {
+	secs, nanos, _ := strings.Cut(timestamp, ".")
+	s, err := strconv.ParseInt(secs, 10, 64)
+	if err == nil && nanos != "" {
+		var n int64
+		if n, err = strconv.ParseInt((nanos + "000000000")[0:9], 10, 64); err == nil {
+			t := fftypes.FFTime(time.Unix(s, n))
+			return &t
+		}
+	}
+	if err != nil {
+		log.L(ctx).Warnf("Invalid consensus timestamp '%s': %s", timestamp, err)
+	}
+	return fftypes.UnixTime(s)
+}
+
+func (h *Hedera) parseBlockchainEvent(ctx context.Context, msgJSON fftypes.JSONObject) (*blockchain.Event, *hcsPinMessage) {
+	messageString := msgJSON.GetString("message")
+	messageBytes, err := base64.StdEncoding.DecodeString(messageString)
+	if err != nil {
+		log.L(ctx).Errorf("HCS message is not valid - bad message content: %s", messageString)
+		return nil, nil
+	}
+	var message hcsPinMessage
+	if err := json.Unmarshal(messageBytes, &message); err != nil {
+		log.L(ctx).Errorf("HCS message is not valid - bad JSON message: %s", messageBytes)
+		return nil, nil
+	}
+	output, _ := fftypes.JSONAnyPtrBytes(messageBytes).JSONObjectOk()
+
+	delete(msgJSON, "message")
+	return &blockchain.Event{
+		BlockchainTXID: msgJSON.GetString("transactionId"),
+		Source:         h.Name(),
+		Name:           batchPinSubscriptionName,
+		ProtocolID:     fmt.Sprintf("%.12d", msgJSON.GetInt64("sequenceNumber")),
+		Output:         output,
+		Info:           msgJSON,
+		Timestamp:      parseConsensusTimestamp(ctx, msgJSON.GetString("consensusTimestamp")),
+		Location:       fmt.Sprintf("topicId=%s", msgJSON.GetString("topicId")),
+		Signature:      batchPinSubscriptionName,
+	}, &message
+}
+
+func (h *Hedera) processBatchPinEvent(ctx context.Context, events common.EventsToDispatch, location *fftypes.JSONAny, subInfo *common.SubscriptionInfo, msgJSON fftypes.JSONObject) {
+	event, message := h.parseBlockchainEvent(ctx, msgJSON)
+	if event == nil {
+		return // move on
+	}
+
+	// The payer of the HCS transaction is the account that submitted the message
+	payer := msgJSON.GetString("payerAccountId")
+	if !entityIDPattern.MatchString(payer) {
+		log.L(ctx).Errorf("HCS message is not valid - bad payer account (%s): %+v", payer, msgJSON)
+		return // move on
+	}
+
+	params := &common.BatchPinParams{
+		UUIDs:      message.UUIDs,
+		BatchHash:  message.BatchHash,
+		PayloadRef: message.PayloadRef,
+		Contexts:   message.Contexts,
+		NsOrAction: message.Action,
+	}
+	verifier := &core.VerifierRef{
+		Type:  core.VerifierTypeHederaAccountID,
+		Value: payer,
+	}
+
+	h.callbacks.PrepareBatchPinOrNetworkAction(ctx, events, subInfo, location, event, verifier, params)
+}
+
+func (h *Hedera) AddFireflySubscription(ctx context.Context, namespace *core.Namespace, contract *blockchain.MultipartyContract) (string, error) {
+	location, err := parseContractLocation(ctx, contract.Location)
+	if err != nil {
+		return "", err
+	}
+	if contract.ChainID != "" {
+		return "", i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+	}
+
+	sub, err := h.streams.ensureFireFlySubscription(ctx, namespace.Name, location, contract.FirstEvent, h.streamID)
+	if err != nil {
+		return "", err
+	}
+
+	h.subs.AddSubscription(ctx, namespace, hederaNetworkVersion, sub.ID, nil)
+	return sub.ID, nil
+}
+
+func (h *Hedera) RemoveFireflySubscription(ctx context.Context, subID string) {
+	// Don't actually delete the subscription from the connector, as this may be called while processing
+	// events from the subscription
+	h.subs.RemoveSubscription(ctx, subID)
+}
+
+func (h *Hedera) handleMessageBatch(ctx context.Context, messages []interface{}) error {
+	// Build the set of events that need handling
+	events := make(common.EventsToDispatch)
+	count := len(messages)
+	for i, msgI := range messages {
+		msgMap, ok := msgI.(map[string]interface{})
+		if !ok {
+			log.L(ctx).Errorf("Message cannot be parsed as JSON: %+v", msgI)
+			return nil // Swallow this and move on
+		}
+		msgJSON := fftypes.JSONObject(msgMap)
+
+		topicID := msgJSON.GetString("topicId")
+		sub := msgJSON.GetString("subId")
+		logger := log.L(ctx)
+		logger.Infof("[Hedera:%d/%d]: topic '%s' on '%s'", i+1, count, topicID, sub)
+		logger.Tracef("Message: %+v", msgJSON)
+
+		// Only FireFly BatchPin subscriptions are supported - there are no custom contract listeners
+		subInfo := h.subs.GetSubscription(sub)
+		if subInfo == nil {
+			log.L(ctx).Infof("Ignoring message from unknown subscription: %s", sub)
+			continue
+		}
+		location, err := encodeContractLocation(ctx, &Location{TopicID: topicID})
+		if err != nil {
+			log.L(ctx).Errorf("HCS message is not valid - bad topic: %s", err)
+			continue
+		}
+		h.processBatchPinEvent(ctx, events, location, subInfo, msgJSON)
+	}
+	// Dispatch all the events from this patch that were successfully parsed and routed to namespaces
+	// (could be zero - that's ok)
+	return h.callbacks.DispatchBlockchainEvents(ctx, events)
+}
+
+func (h *Hedera) eventLoop() {
+	defer h.wsconn.Close()
+	defer close(h.closed)
+	l := log.L(h.ctx).WithField("role", "event-loop")
+	ctx := log.WithLogger(h.ctx, l)
+	for {
+		select {
+		case <-ctx.Done():
+			l.Debugf("Event loop exiting (context cancelled)")
+			return
+		case msgBytes, ok := <-h.wsconn.Receive():
+			if !ok {
+				l.Debugf("Event loop exiting (receive channel closed). Terminating server!")
+				h.cancelCtx()
+				return
+			}
+
+			var msgParsed interface{}
+			err := json.Unmarshal(msgBytes, &msgParsed)
+			if err != nil {
+				l.Errorf("Message cannot be parsed as JSON: %s\n%s", err, string(msgBytes))
+				continue // Swallow this and move on
+			}
+			switch msgTyped := msgParsed.(type) {
+			case []interface{}:
+				err = h.handleMessageBatch(ctx, msgTyped)
+				var ackOrNack []byte
+				if err == nil {
+					ackOrNack, _ = json.Marshal(map[string]string{"type": "ack", "topic": h.topic})
+				} else {
+					log.L(ctx).Errorf("Rejecting batch due error: %s", err)
+					ackOrNack, _ = json.Marshal(map[string]string{"type": "error", "topic": h.topic, "message": err.Error()})
+				}
+				err = h.wsconn.Send(ctx, ackOrNack)
+			case map[string]interface{}:
+				var receipt common.BlockchainReceiptNotification
+				_ = json.Unmarshal(msgBytes, &receipt)
+
+				err := common.HandleReceipt(ctx, h, &receipt, h.callbacks)
+				if err != nil {
+					l.Errorf("Failed to process receipt: %+v", msgTyped)
+				}
+			default:
+				l.Errorf("Message unexpected: %+v", msgTyped)
+				continue
+			}
+
+			if err != nil {
+				l.Errorf("Event loop exiting (%s). Terminating server!", err)
+				h.cancelCtx()
+				return
+			}
+		}
+	}
+}
+
+func (h *Hedera) ResolveSigningKey(ctx context.Context, key string, intent blockchain.ResolveKeyIntent) (string, error) {
+	// Signing keys are Hedera accounts, which are managed by the connector
+	if !entityIDPattern.MatchString(key) {
+		return "", i18n.NewError(ctx, coremsgs.MsgInvalidHederaAccountID, key)
+	}
+	return key, nil
+}
+
+func wrapError(ctx context.Context, errRes *hederaError, res *resty.Response, err error) error {
+	if errRes != nil && errRes.Error != "" {
+		return i18n.WrapError(ctx, err, coremsgs.MsgHederaconnectRESTErr, errRes.Error)
+	}
+	return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgHederaconnectRESTErr)
+}
+
+func hexFormatB32(b *fftypes.Bytes32) string {
+	if b == nil {
+		return "0x0000000000000000000000000000000000000000000000000000000000000000"
+	}
+	return "0x" + hex.EncodeToString(b[0:32])
+}
+
+// submitMessage submits a message to an HCS topic. The connector is responsible for chunking
+// messages that exceed the HCS message size limit.
+func (h *Hedera) submitMessage(ctx context.Context, location *fftypes.JSONAny, signingKey, requestID string, message *hcsPinMessage) error {
+	hederaLocation, err := parseContractLocation(ctx, location)
+	if err != nil {
+		return err
+	}
+	messageBytes, _ := json.Marshal(message)
+	body := map[string]interface{}{
+		"headers": &hederaMessageHeaders{
+			ID:   requestID,
+			Type: "SubmitMessage",
+		},
+		"from":    signingKey,
+		"message": base64.StdEncoding.EncodeToString(messageBytes),
+	}
+	var resErr hederaError
+	res, err := h.client.R().
+		SetContext(ctx).
+		SetHeader("x-firefly-sync", "false").
+		SetBody(body).
+		SetError(&resErr).
+		Post(fmt.Sprintf("/topics/%s/messages", hederaLocation.TopicID))
+	if err != nil || !res.IsSuccess() {
+		return wrapError(ctx, &resErr, res, err)
+	}
+	return nil
+}
+
+func (h *Hedera) SubmitBatchPin(ctx context.Context, nsOpID, networkNamespace, signingKey string, batch *blockchain.BatchPin, location *fftypes.JSONAny) error {
+	contexts := make([]string, len(batch.Contexts))
+	for i, v := range batch.Contexts {
+		contexts[i] = hexFormatB32(v)
+	}
+	var uuids fftypes.Bytes32
+	copy(uuids[0:16], (*batch.TransactionID)[:])
+	copy(uuids[16:32], (*batch.BatchID)[:])
+
+	return h.submitMessage(ctx, location, signingKey, nsOpID, &hcsPinMessage{
+		UUIDs:      hexFormatB32(&uuids),
+		BatchHash:  hexFormatB32(batch.BatchHash),
+		PayloadRef: batch.BatchPayloadRef,
+		Contexts:   contexts,
+	})
+}
+
+func (h *Hedera) SubmitNetworkAction(ctx context.Context, nsOpID string, signingKey string, action core.NetworkActionType, location *fftypes.JSONAny) error {
+	return h.submitMessage(ctx, location, signingKey, nsOpID, &hcsPinMessage{
+		Action: blockchain.FireFlyActionPrefix + string(action),
+	})
+}
+
+func (h *Hedera) DeployContract(ctx context.Context, nsOpID, signingKey string, definition, contract *fftypes.JSONAny, input []interface{}, options map[string]interface{}) error {
+	return i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (h *Hedera) ValidateInvokeRequest(ctx context.Context, method *fftypes.FFIMethod, input map[string]interface{}, errors []*fftypes.FFIError, hasMessage bool) error {
+	return i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (h *Hedera) InvokeContract(ctx context.Context, nsOpID string, signingKey string, location *fftypes.JSONAny, method *fftypes.FFIMethod, input map[string]interface{}, errors []*fftypes.FFIError, options map[string]interface{}, batch *blockchain.BatchPin) error {
+	return i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (h *Hedera) QueryContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, method *fftypes.FFIMethod, input map[string]interface{}, errors []*fftypes.FFIError, options map[string]interface{}) (interface{}, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (h *Hedera) NormalizeContractLocation(ctx context.Context, ntype blockchain.NormalizeType, location *fftypes.JSONAny) (result *fftypes.JSONAny, err error) {
+	parsed, err := parseContractLocation(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+	return encodeContractLocation(ctx, parsed)
+}
+
+func parseContractLocation(ctx context.Context, location *fftypes.JSONAny) (*Location, error) {
+	if location == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgContractLocationInvalid, "'topicId' not set")
+	}
+	hederaLocation := Location{}
+	if err := json.Unmarshal(location.Bytes(), &hederaLocation); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgContractLocationInvalid, err)
+	}
+	if hederaLocation.TopicID == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgContractLocationInvalid, "'topicId' not set")
+	}
+	return &hederaLocation, nil
+}
+
+func encodeContractLocation(ctx context.Context, location *Location) (result *fftypes.JSONAny, err error) {
+	if !entityIDPattern.MatchString(location.TopicID) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgContractLocationInvalid, "'topicId' must be in the format shard.realm.num")
+	}
+	normalized, err := json.Marshal(location)
+	if err == nil {
+		result = fftypes.JSONAnyPtrBytes(normalized)
+	}
+	return result, err
+}
+
+func (h *Hedera) AddContractListener(ctx context.Context, listener *core.ContractListener) error {
+	return i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (h *Hedera) DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error {
+	return i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (h *Hedera) GetContractListenerStatus(ctx context.Context, subID string, okNotFound bool) (bool, interface{}, error) {
+	return false, nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (h *Hedera) GetFFIParamValidator(ctx context.Context) (fftypes.FFIParamValidator, error) {
+	return nil, nil
+}
+
+func (h *Hedera) GenerateFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgFFIGenerationUnsupported)
+}
+
+func (h *Hedera) GenerateEventSignature(ctx context.Context, event *fftypes.FFIEventDefinition) string {
+	return event.Name
+}
+
+func (h *Hedera) GenerateErrorSignature(ctx context.Context, errorDef *fftypes.FFIErrorDefinition) string {
+	// not relevant to HCS topics
+	return ""
+}
+
+func (h *Hedera) GetNetworkVersion(ctx context.Context, location *fftypes.JSONAny) (version int, err error) {
+	if _, err := parseContractLocation(ctx, location); err != nil {
+		return 0, err
+	}
+	return hederaNetworkVersion, nil
+}
+
+func (h *Hedera) GetAndConvertDeprecatedContractConfig(ctx context.Context) (location *fftypes.JSONAny, fromBlock string, err error) {
+	// There is no deprecated config for Hedera - the topic must be configured on the namespace
+	return nil, "", i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "topicId", "namespaces.predefined[].multiparty.contract[].location")
+}
+
+func (h *Hedera) GetNetworkStatus(ctx context.Context) (*core.BlockchainNetworkStatus, error) {
+	stream, err := h.streams.getEventStream(ctx, h.streamID)
+	if err != nil {
+		return nil, err
+	}
+	return &core.BlockchainNetworkStatus{
+		EventStream: &core.EventStreamNetworkStatus{
+			ID:        stream.ID,
+			Connected: !stream.Suspended,
+		},
+	}, nil
+}
+
+func (h *Hedera) GetTransactionStatus(ctx context.Context, operation *core.Operation) (interface{}, error) {
+	txnID := (&core.PreparedOperation{ID: operation.ID, Namespace: operation.Namespace}).NamespacedIDString()
+
+	var resErr hederaError
+	var statusResponse fftypes.JSONObject
+	res, err := h.client.R().
+		SetContext(ctx).
+		SetError(&resErr).
+		SetResult(&statusResponse).
+		Get(fmt.Sprintf("/transactions/%s", txnID))
+	if err != nil || !res.IsSuccess() {
+		if res.StatusCode() == 404 {
+			return nil, nil
+		}
+		return nil, wrapError(ctx, &resErr, res, err)
+	}
+	return statusResponse, nil
+}
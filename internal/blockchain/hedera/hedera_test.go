@@ -0,0 +1,821 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hedera
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/coremocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var utConfig = config.RootSection("hedera_unit_tests")
+var utHederaconnectConf = utConfig.SubSection(HederaconnectConfigKey)
+
+var testTopicLocation = fftypes.JSONAnyPtr(`{"topicId":"0.0.1001"}`)
+
+func resetConf(h *Hedera) {
+	coreconfig.Reset()
+	h.InitConfig(utConfig)
+}
+
+func newTestHedera() (*Hedera, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	wsm := &wsmocks.WSClient{}
+	h := &Hedera{
+		ctx:       ctx,
+		cancelCtx: cancel,
+		client:    resty.New().SetBaseURL("http://localhost:12345"),
+		topic:     "topic1",
+		wsconn:    wsm,
+		callbacks: common.NewBlockchainCallbacks(),
+		subs:      common.NewFireflySubscriptions(),
+	}
+	h.streams = newStreamManager(h.client, defaultBatchSize, defaultBatchTimeout)
+	return h, func() {
+		cancel()
+		if h.closed != nil {
+			// We've init'd, wait to close
+			<-h.closed
+		}
+	}
+}
+
+func testHCSMessage(t *testing.T, message *hcsPinMessage) string {
+	b, err := json.Marshal(message)
+	assert.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func testBatchPinMessage() *hcsPinMessage {
+	return &hcsPinMessage{
+		UUIDs:      "0xe19af8b390604051812d7597d19adfb9847d3bfd074249efb65d3fed15f5b0a6",
+		BatchHash:  "0xd71eb138d74c229a388eb0e1abc03f4c7cbb21d4fc4b839fbf0ec73e4263f6be",
+		PayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts:   []string{"0x68e4da79f805bca5b912bcda9c63d03e6e867108dabb9b944109aea541ef522a"},
+	}
+}
+
+func TestInitMissingURL(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	resetConf(h)
+	err := h.Init(h.ctx, h.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitMissingTopic(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	resetConf(h)
+	utHederaconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	err := h.Init(h.ctx, h.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10138.*topic", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	resetConf(h)
+	utHederaconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utHederaconnectConf.Set("tls.enabled", true)
+	utHederaconnectConf.Set("tls.caFile", "!!!badness")
+	err := h.Init(h.ctx, h.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestInitStreamFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(500, hederaError{Error: "pop"}))
+
+	resetConf(h)
+	utHederaconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utHederaconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utHederaconnectConf.Set(HederaconnectConfigTopic, "topic1")
+	err := h.Init(h.ctx, h.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.Regexp(t, "FF10466", err)
+}
+
+func TestInitAllNewStreamsAndWSEvent(t *testing.T) {
+	log.SetLevel("trace")
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	toServer, fromServer, wsURL, done := wsclient.NewTestWSServer(nil)
+	defer done()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+
+	resetConf(h)
+	utHederaconnectConf.Set(ffresty.HTTPConfigURL, httpURL)
+	utHederaconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utHederaconnectConf.Set(HederaconnectConfigTopic, "topic1")
+
+	err := h.Init(h.ctx, h.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "hedera", h.Name())
+	assert.Equal(t, core.VerifierTypeHederaAccountID, h.VerifierType())
+
+	err = h.Start()
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	assert.Equal(t, "es12345", h.streamID)
+	assert.NotNil(t, h.Capabilities())
+
+	startupMessage := <-toServer
+	assert.Equal(t, `{"type":"listen","topic":"topic1"}`, startupMessage)
+	startupMessage = <-toServer
+	assert.Equal(t, `{"type":"listenreplies"}`, startupMessage)
+	fromServer <- `{"bad":"receipt"}` // will be ignored - no ack
+	fromServer <- `[]`                // empty batch, will be ignored, but acked
+	reply := <-toServer
+	assert.Equal(t, `{"topic":"topic1","type":"ack"}`, reply)
+
+	// Bad data will be ignored
+	fromServer <- `!json`
+	fromServer <- `42`
+}
+
+func TestBackgroundStart(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	toServer, _, wsURL, done := wsclient.NewTestWSServer(nil)
+	defer done()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1"}}))
+
+	resetConf(h)
+	utHederaconnectConf.Set(ffresty.HTTPConfigURL, httpURL)
+	utHederaconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utHederaconnectConf.Set(HederaconnectConfigTopic, "topic1")
+	utHederaconnectConf.Set(HederaconnectBackgroundStart, true)
+
+	err := h.Init(h.ctx, h.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.NoError(t, err)
+	assert.Empty(t, h.streamID)
+
+	err = h.Start()
+	assert.NoError(t, err)
+
+	startupMessage := <-toServer
+	assert.Equal(t, `{"type":"listen","topic":"topic1"}`, startupMessage)
+	assert.Equal(t, "es12345", h.streamID)
+}
+
+func TestBackgroundStartFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(500, hederaError{Error: "pop"}))
+
+	resetConf(h)
+	utHederaconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utHederaconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utHederaconnectConf.Set(HederaconnectConfigTopic, "topic1")
+	utHederaconnectConf.Set(HederaconnectBackgroundStart, true)
+
+	err := h.Init(h.ctx, h.cancelCtx, utConfig, &metricsmocks.Manager{}, &cachemocks.Manager{})
+	assert.NoError(t, err)
+
+	capturedErr := make(chan error)
+	h.backgroundRetry = &retry.Retry{
+		ErrCallback: func(err error) {
+			capturedErr <- err
+		},
+	}
+
+	err = h.Start()
+	assert.NoError(t, err)
+
+	err = <-capturedErr
+	assert.Regexp(t, "FF10466", err)
+}
+
+func TestAddFireflySubscriptionNewSub(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.streamID = "es12345"
+
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		func(req *http.Request) (*http.Response, error) {
+			var body subscription
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "ns1_BatchPin_0.0.1001", body.Name)
+			assert.Equal(t, "0.0.1001", body.TopicID)
+			assert.Equal(t, "es12345", body.Stream)
+			assert.Equal(t, "0", body.FromSequence)
+			body.ID = "sub1"
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
+		})
+
+	subID, err := h.AddFireflySubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location:   testTopicLocation,
+		FirstEvent: string(core.SubOptsFirstEventOldest),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sub1", subID)
+	assert.NotNil(t, h.subs.GetSubscription("sub1"))
+
+	h.RemoveFireflySubscription(h.ctx, "sub1")
+	assert.Nil(t, h.subs.GetSubscription("sub1"))
+}
+
+func TestAddFireflySubscriptionExistingSub(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.streamID = "es12345"
+
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub1", Stream: "es12345", Name: "ns1_BatchPin_0.0.1001"},
+		}))
+
+	subID, err := h.AddFireflySubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location:   testTopicLocation,
+		FirstEvent: string(core.SubOptsFirstEventNewest),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "sub1", subID)
+}
+
+func TestAddFireflySubscriptionCreateFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, err := h.AddFireflySubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location:   testTopicLocation,
+		FirstEvent: string(core.SubOptsFirstEventNewest),
+	})
+	assert.Regexp(t, "FF10466", err)
+}
+
+func TestAddFireflySubscriptionListFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, err := h.AddFireflySubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location: testTopicLocation,
+	})
+	assert.Regexp(t, "FF10466", err)
+}
+
+func TestAddFireflySubscriptionBadLocation(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	_, err := h.AddFireflySubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location: fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestResolveSigningKey(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	key, err := h.ResolveSigningKey(h.ctx, "0.0.1234", blockchain.ResolveKeyIntentSign)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.1234", key)
+
+	_, err = h.ResolveSigningKey(h.ctx, "0x12345", blockchain.ResolveKeyIntentSign)
+	assert.Regexp(t, "FF10467", err)
+}
+
+func TestSubmitBatchPinOK(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	batch := &blockchain.BatchPin{
+		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
+		BatchHash:       fftypes.NewRandB32(),
+		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts: []*fftypes.Bytes32{
+			fftypes.NewRandB32(),
+		},
+	}
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/0.0.1001/messages",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "false", req.Header.Get("x-firefly-sync"))
+			assert.Equal(t, "0.0.1234", body["from"])
+			headers := body["headers"].(map[string]interface{})
+			assert.Equal(t, "ns1:opid", headers["id"])
+			assert.Equal(t, "SubmitMessage", headers["type"])
+
+			messageBytes, err := base64.StdEncoding.DecodeString(body["message"].(string))
+			assert.NoError(t, err)
+			var message hcsPinMessage
+			err = json.Unmarshal(messageBytes, &message)
+			assert.NoError(t, err)
+			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", message.UUIDs)
+			assert.Equal(t, hexFormatB32(batch.BatchHash), message.BatchHash)
+			assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", message.PayloadRef)
+			assert.Equal(t, []string{hexFormatB32(batch.Contexts[0])}, message.Contexts)
+			assert.Empty(t, message.Action)
+			return httpmock.NewJsonResponderOrPanic(202, map[string]interface{}{})(req)
+		})
+
+	err := h.SubmitBatchPin(h.ctx, "ns1:opid", "ns1", "0.0.1234", batch, testTopicLocation)
+	assert.NoError(t, err)
+}
+
+func TestSubmitBatchPinFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	batch := &blockchain.BatchPin{
+		TransactionID: fftypes.NewUUID(),
+		BatchID:       fftypes.NewUUID(),
+		BatchHash:     fftypes.NewRandB32(),
+		Contexts:      []*fftypes.Bytes32{},
+	}
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/0.0.1001/messages",
+		httpmock.NewJsonResponderOrPanic(500, hederaError{Error: "INSUFFICIENT_PAYER_BALANCE"}))
+
+	err := h.SubmitBatchPin(h.ctx, "ns1:opid", "ns1", "0.0.1234", batch, testTopicLocation)
+	assert.Regexp(t, "FF10466.*INSUFFICIENT_PAYER_BALANCE", err)
+}
+
+func TestSubmitBatchPinBadLocation(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	batch := &blockchain.BatchPin{
+		TransactionID: fftypes.NewUUID(),
+		BatchID:       fftypes.NewUUID(),
+	}
+	err := h.SubmitBatchPin(h.ctx, "ns1:opid", "ns1", "0.0.1234", batch, nil)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestSubmitNetworkActionOK(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/0.0.1001/messages",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			messageBytes, _ := base64.StdEncoding.DecodeString(body["message"].(string))
+			assert.Equal(t, `{"action":"firefly:terminate"}`, string(messageBytes))
+			return httpmock.NewJsonResponderOrPanic(202, map[string]interface{}{})(req)
+		})
+
+	err := h.SubmitNetworkAction(h.ctx, "ns1:opid", "0.0.1234", core.NetworkActionTerminate, testTopicLocation)
+	assert.NoError(t, err)
+}
+
+func TestHandleMessageBatchPinOK(t *testing.T) {
+	em := &blockchainmocks.Callbacks{}
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.SetHandler("ns1", em)
+	h.subs.AddSubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, hederaNetworkVersion, "sub1", nil)
+
+	events := []interface{}{
+		map[string]interface{}{
+			"subId":              "sub1",
+			"topicId":            "0.0.1001",
+			"sequenceNumber":     "42",
+			"consensusTimestamp": "1620576488.123456789",
+			"payerAccountId":     "0.0.1234",
+			"transactionId":      "0.0.1234@1620576480.000000001",
+			"message":            testHCSMessage(t, testBatchPinMessage()),
+		},
+		map[string]interface{}{
+			"subId":   "unknown",
+			"topicId": "0.0.1001",
+		},
+	}
+
+	expectedSigningKeyRef := &core.VerifierRef{
+		Type:  core.VerifierTypeHederaAccountID,
+		Value: "0.0.1234",
+	}
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(events []*blockchain.EventToDispatch) bool {
+		return len(events) == 1 &&
+			events[0].Type == blockchain.EventTypeBatchPinComplete &&
+			*events[0].BatchPinComplete.SigningKey == *expectedSigningKeyRef
+	})).Return(nil)
+
+	err := h.handleMessageBatch(h.ctx, events)
+	assert.NoError(t, err)
+
+	b := em.Calls[0].Arguments[0].([]*blockchain.EventToDispatch)[0].BatchPinComplete
+	assert.Equal(t, "e19af8b3-9060-4051-812d-7597d19adfb9", b.Batch.TransactionID.String())
+	assert.Equal(t, "847d3bfd-0742-49ef-b65d-3fed15f5b0a6", b.Batch.BatchID.String())
+	assert.Equal(t, "d71eb138d74c229a388eb0e1abc03f4c7cbb21d4fc4b839fbf0ec73e4263f6be", b.Batch.BatchHash.String())
+	assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", b.Batch.BatchPayloadRef)
+	assert.Len(t, b.Batch.Contexts, 1)
+	assert.Equal(t, "000000000042", b.Batch.Event.ProtocolID)
+	assert.Equal(t, "0.0.1234@1620576480.000000001", b.Batch.Event.BlockchainTXID)
+	assert.Equal(t, "topicId=0.0.1001", b.Batch.Event.Location)
+	assert.Equal(t, int64(1620576488123456789), b.Batch.Event.Timestamp.UnixNano())
+
+	em.AssertExpectations(t)
+}
+
+func TestHandleMessageBatchNetworkAction(t *testing.T) {
+	em := &blockchainmocks.Callbacks{}
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.SetHandler("ns1", em)
+	h.subs.AddSubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, hederaNetworkVersion, "sub1", nil)
+
+	events := []interface{}{
+		map[string]interface{}{
+			"subId":              "sub1",
+			"topicId":            "0.0.1001",
+			"sequenceNumber":     43,
+			"consensusTimestamp": "1620576488",
+			"payerAccountId":     "0.0.1234",
+			"message":            testHCSMessage(t, &hcsPinMessage{Action: "firefly:terminate"}),
+		},
+	}
+
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(events []*blockchain.EventToDispatch) bool {
+		return len(events) == 1 &&
+			events[0].Type == blockchain.EventTypeNetworkAction &&
+			events[0].NetworkAction.Action == "terminate"
+	})).Return(nil)
+
+	err := h.handleMessageBatch(h.ctx, events)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+}
+
+func TestHandleMessageBatchBadMessages(t *testing.T) {
+	em := &blockchainmocks.Callbacks{}
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.SetHandler("ns1", em)
+	h.subs.AddSubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, hederaNetworkVersion, "sub1", nil)
+
+	events := []interface{}{
+		map[string]interface{}{
+			"subId":   "sub1",
+			"topicId": "bad",
+		},
+		map[string]interface{}{
+			"subId":   "sub1",
+			"topicId": "0.0.1001",
+			"message": "!base64",
+		},
+		map[string]interface{}{
+			"subId":   "sub1",
+			"topicId": "0.0.1001",
+			"message": base64.StdEncoding.EncodeToString([]byte("!json")),
+		},
+		map[string]interface{}{
+			"subId":          "sub1",
+			"topicId":        "0.0.1001",
+			"payerAccountId": "bad",
+			"message":        testHCSMessage(t, testBatchPinMessage()),
+		},
+	}
+
+	err := h.handleMessageBatch(h.ctx, events)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+}
+
+func TestHandleMessageBatchNotJSON(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	err := h.handleMessageBatch(h.ctx, []interface{}{"bad"})
+	assert.NoError(t, err)
+}
+
+func TestParseConsensusTimestamp(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, int64(1620576488100000000), parseConsensusTimestamp(ctx, "1620576488.1").UnixNano())
+	assert.Equal(t, int64(1620576488000000000), parseConsensusTimestamp(ctx, "1620576488").UnixNano())
+	assert.Equal(t, int64(1620576488000000000), parseConsensusTimestamp(ctx, "1620576488.bad").UnixNano())
+	assert.Equal(t, int64(0), parseConsensusTimestamp(ctx, "bad").UnixNano())
+}
+
+func TestEventLoopContextCancelled(t *testing.T) {
+	h, cancel := newTestHedera()
+	cancel()
+	r := make(<-chan []byte)
+	wsm := h.wsconn.(*wsmocks.WSClient)
+	wsm.On("Receive").Return(r)
+	wsm.On("Close").Return()
+	h.closed = make(chan struct{})
+	h.eventLoop() // we're simply looking for it exiting
+	wsm.AssertExpectations(t)
+}
+
+func TestEventLoopReceiveClosed(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	r := make(chan []byte)
+	wsm := h.wsconn.(*wsmocks.WSClient)
+	close(r)
+	wsm.On("Receive").Return((<-chan []byte)(r))
+	wsm.On("Close").Return()
+	h.closed = make(chan struct{})
+	h.eventLoop() // we're simply looking for it exiting
+	wsm.AssertExpectations(t)
+}
+
+func TestEventLoopSendFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	r := make(chan []byte, 1)
+	wsm := h.wsconn.(*wsmocks.WSClient)
+	r <- []byte(`[]`)
+	wsm.On("Receive").Return((<-chan []byte)(r))
+	wsm.On("Send", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	wsm.On("Close").Return()
+	h.closed = make(chan struct{})
+	h.eventLoop() // we're simply looking for it exiting
+	wsm.AssertExpectations(t)
+}
+
+func TestHandleReceiptTXSuccess(t *testing.T) {
+	em := &coremocks.OperationCallbacks{}
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.SetOperationHandler("ns1", em)
+
+	var reply common.BlockchainReceiptNotification
+	operationID := fftypes.NewUUID()
+	data := []byte(`{
+		"headers": {
+			"requestId": "ns1:` + operationID.String() + `",
+			"type": "TransactionSuccess"
+		},
+		"transactionHash": "0.0.1234@1620576480.000000001"
+	}`)
+
+	em.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == "ns1:"+operationID.String() &&
+			update.Status == core.OpStatusSucceeded &&
+			update.BlockchainTXID == "0.0.1234@1620576480.000000001" &&
+			update.Plugin == "hedera"
+	})).Return(nil)
+
+	err := json.Unmarshal(data, &reply)
+	assert.NoError(t, err)
+	common.HandleReceipt(context.Background(), h, &reply, h.callbacks)
+
+	em.AssertExpectations(t)
+}
+
+func TestContractOperationsNotSupported(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	err := h.DeployContract(h.ctx, "ns1:opid", "0.0.1234", nil, nil, nil, nil)
+	assert.Regexp(t, "FF10429", err)
+	err = h.ValidateInvokeRequest(h.ctx, &fftypes.FFIMethod{}, nil, nil, false)
+	assert.Regexp(t, "FF10429", err)
+	err = h.InvokeContract(h.ctx, "ns1:opid", "0.0.1234", testTopicLocation, &fftypes.FFIMethod{}, nil, nil, nil, nil)
+	assert.Regexp(t, "FF10429", err)
+	_, err = h.QueryContract(h.ctx, "0.0.1234", testTopicLocation, &fftypes.FFIMethod{}, nil, nil, nil)
+	assert.Regexp(t, "FF10429", err)
+	err = h.AddContractListener(h.ctx, &core.ContractListener{})
+	assert.Regexp(t, "FF10429", err)
+	err = h.DeleteContractListener(h.ctx, &core.ContractListener{}, true)
+	assert.Regexp(t, "FF10429", err)
+	_, _, err = h.GetContractListenerStatus(h.ctx, "sub1", true)
+	assert.Regexp(t, "FF10429", err)
+	_, err = h.GenerateFFI(h.ctx, &fftypes.FFIGenerationRequest{})
+	assert.Regexp(t, "FF10347", err)
+
+	validator, err := h.GetFFIParamValidator(h.ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, validator)
+	assert.Equal(t, "Changed", h.GenerateEventSignature(h.ctx, &fftypes.FFIEventDefinition{Name: "Changed"}))
+	assert.Empty(t, h.GenerateErrorSignature(h.ctx, &fftypes.FFIErrorDefinition{Name: "CustomError"}))
+}
+
+func TestNormalizeContractLocation(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	result, err := h.NormalizeContractLocation(h.ctx, blockchain.NormalizeListener, fftypes.JSONAnyPtr(`{"topicId":"0.0.1001","extra":"ignored"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"topicId":"0.0.1001"}`, result.String())
+
+	_, err = h.NormalizeContractLocation(h.ctx, blockchain.NormalizeListener, fftypes.JSONAnyPtr(`{"topicId":"topic1"}`))
+	assert.Regexp(t, "FF10310.*shard.realm.num", err)
+
+	_, err = h.NormalizeContractLocation(h.ctx, blockchain.NormalizeListener, fftypes.JSONAnyPtr(`bad`))
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestGetNetworkVersion(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	version, err := h.GetNetworkVersion(h.ctx, testTopicLocation)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+
+	_, err = h.GetNetworkVersion(h.ctx, nil)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestGetAndConvertDeprecatedContractConfig(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	_, _, err := h.GetAndConvertDeprecatedContractConfig(h.ctx)
+	assert.Regexp(t, "FF10138", err)
+}
+
+func TestGetNetworkStatus(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.streamID = "es12345"
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+
+	status, err := h.GetNetworkStatus(h.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", status.EventStream.ID)
+	assert.True(t, status.EventStream.Connected)
+}
+
+func TestGetNetworkStatusFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	h.streamID = "es12345"
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewStringResponder(500, "pop"))
+
+	_, err := h.GetNetworkStatus(h.ctx)
+	assert.Regexp(t, "FF10466", err)
+}
+
+func TestGetTransactionStatus(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	op := &core.Operation{
+		Namespace: "ns1",
+		ID:        fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+	}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/transactions/ns1:9ffc50ff-6bfe-4502-adc7-93aea54cc059",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"status": "SUCCESS"}))
+
+	status, err := h.GetTransactionStatus(h.ctx, op)
+	assert.NoError(t, err)
+	assert.Equal(t, "SUCCESS", status.(fftypes.JSONObject).GetString("status"))
+}
+
+func TestGetTransactionStatusNotFound(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	op := &core.Operation{
+		Namespace: "ns1",
+		ID:        fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+	}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/transactions/ns1:9ffc50ff-6bfe-4502-adc7-93aea54cc059",
+		httpmock.NewStringResponder(404, "not found"))
+
+	status, err := h.GetTransactionStatus(h.ctx, op)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
+func TestGetTransactionStatusFail(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+	httpmock.ActivateNonDefault(h.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	op := &core.Operation{
+		Namespace: "ns1",
+		ID:        fftypes.NewUUID(),
+	}
+	httpmock.RegisterResponder("GET", fmt.Sprintf("http://localhost:12345/transactions/ns1:%s", op.ID),
+		httpmock.NewJsonResponderOrPanic(500, hederaError{Error: "pop"}))
+
+	_, err := h.GetTransactionStatus(h.ctx, op)
+	assert.Regexp(t, "FF10466.*pop", err)
+}
+
+func TestAddFireflySubscriptionChainIDUnsupported(t *testing.T) {
+	h, cancel := newTestHedera()
+	defer cancel()
+
+	_, err := h.AddFireflySubscription(h.ctx, &core.Namespace{Name: "ns1", NetworkName: "ns1"}, &blockchain.MultipartyContract{
+		Location: testTopicLocation,
+		ChainID:  "mainnet",
+	})
+	assert.Regexp(t, "FF10429", err)
+}
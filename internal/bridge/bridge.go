@@ -0,0 +1,340 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// IdempotencyKeyPrefix is the prefix of the idempotency key of every message published by a bridge,
+// which is also used to avoid bridging a bridged message again
+const IdempotencyKeyPrefix = "bridge:"
+
+// Bridge re-publishes the confirmed messages of one namespace into another namespace on the same node
+type Bridge interface {
+	Start()
+	WaitStop()
+}
+
+// OrchestratorResolver returns the orchestrator of a started namespace
+type OrchestratorResolver func(ctx context.Context, ns string) (orchestrator.Orchestrator, error)
+
+// Config is the parsed configuration of a single bridge
+type Config struct {
+	Name           string
+	Target         string
+	FirstEvent     core.SubOptsFirstEvent
+	Topics         *regexp.Regexp
+	Tag            *regexp.Regexp
+	Author         *regexp.Regexp
+	IncludePrivate bool
+	TopicMap       map[string]string
+	IdentityMap    map[string]string
+	Members        []string
+}
+
+type bridge struct {
+	ctx          context.Context
+	namespace    string
+	conf         *Config
+	database     database.Plugin
+	source       orchestrator.Orchestrator
+	resolve      OrchestratorResolver
+	offsetName   string
+	offsetID     int64
+	offset       int64
+	batchSize    int
+	pollInterval time.Duration
+	retry        *retry.Retry
+	closed       chan struct{}
+}
+
+func NewBridge(ctx context.Context, ns string, conf *Config, di database.Plugin, source orchestrator.Orchestrator, resolve OrchestratorResolver) Bridge {
+	return &bridge{
+		ctx:          log.WithLogField(ctx, "bridge", fmt.Sprintf("%s:%s", ns, conf.Name)),
+		namespace:    ns,
+		conf:         conf,
+		database:     di,
+		source:       source,
+		resolve:      resolve,
+		offsetName:   fmt.Sprintf("%s:%s", ns, conf.Name),
+		batchSize:    config.GetInt(coreconfig.BridgeBatchSize),
+		pollInterval: config.GetDuration(coreconfig.BridgePollInterval),
+		retry: &retry.Retry{
+			InitialDelay: config.GetDuration(coreconfig.BridgeRetryInitDelay),
+			MaximumDelay: config.GetDuration(coreconfig.BridgeRetryMaxDelay),
+			Factor:       config.GetFloat64(coreconfig.BridgeRetryFactor),
+		},
+		closed: make(chan struct{}),
+	}
+}
+
+func (b *bridge) Start() {
+	go b.bridgeLoop()
+}
+
+func (b *bridge) WaitStop() {
+	<-b.closed
+}
+
+func (b *bridge) restoreOffset() error {
+	return b.retry.Do(b.ctx, "restore bridge offset", func(attempt int) (retry bool, err error) {
+		offset, err := b.database.GetOffset(b.ctx, core.OffsetTypeBridge, b.offsetName)
+		if err != nil {
+			return true, err
+		}
+		if offset == nil {
+			offset = &core.Offset{
+				Type: core.OffsetTypeBridge,
+				Name: b.offsetName,
+			}
+			if offset.Current, err = b.firstOffset(); err != nil {
+				return true, err
+			}
+			if err = b.database.UpsertOffset(b.ctx, offset, false); err != nil {
+				return true, err
+			}
+		}
+		b.offsetID = offset.RowID
+		b.offset = offset.Current
+		log.L(b.ctx).Infof("Bridge offset restored %d", b.offset)
+		return false, nil
+	})
+}
+
+func (b *bridge) firstOffset() (int64, error) {
+	if b.conf.FirstEvent == core.SubOptsFirstEventOldest {
+		return -1, nil
+	}
+	f := database.EventQueryFactory.NewFilter(b.ctx).And().Sort("sequence").Descending().Limit(1)
+	newestEvents, _, err := b.database.GetEvents(b.ctx, b.namespace, f)
+	if err != nil || len(newestEvents) == 0 {
+		return -1, err
+	}
+	return newestEvents[0].Sequence, nil
+}
+
+func (b *bridge) commitOffset(offset int64) error {
+	return b.retry.Do(b.ctx, "commit bridge offset", func(attempt int) (retry bool, err error) {
+		u := database.OffsetQueryFactory.NewUpdate(b.ctx).Set("current", offset)
+		if err = b.database.UpdateOffset(b.ctx, b.offsetID, u); err != nil {
+			return true, err
+		}
+		b.offset = offset
+		return false, nil
+	})
+}
+
+func (b *bridge) readPage() (events []*core.Event, err error) {
+	err = b.retry.Do(b.ctx, "read bridge events", func(attempt int) (retry bool, err error) {
+		fb := database.EventQueryFactory.NewFilter(b.ctx)
+		filter := fb.And(
+			fb.Gt("sequence", b.offset),
+			fb.Eq("type", core.EventTypeMessageConfirmed),
+		).Sort("sequence").Limit(uint64(b.batchSize))
+		events, _, err = b.database.GetEvents(b.ctx, b.namespace, filter)
+		return err != nil, err
+	})
+	return events, err
+}
+
+func (b *bridge) bridgeLoop() {
+	defer close(b.closed)
+	l := log.L(b.ctx)
+	if err := b.restoreOffset(); err != nil {
+		l.Errorf("Bridge context closed before the offset was restored: %s", err)
+		return
+	}
+	l.Infof("Bridge started from namespace '%s' to namespace '%s'", b.namespace, b.conf.Target)
+	for {
+		events, err := b.readPage()
+		if err != nil {
+			l.Debugf("Bridge exiting: %s", err)
+			return
+		}
+		if len(events) == 0 {
+			select {
+			case <-time.After(b.pollInterval):
+				continue
+			case <-b.ctx.Done():
+				l.Debugf("Bridge exiting")
+				return
+			}
+		}
+		for _, event := range events {
+			if err := b.bridgeEvent(event); err != nil {
+				l.Debugf("Bridge exiting: %s", err)
+				return
+			}
+		}
+		if err := b.commitOffset(events[len(events)-1].Sequence); err != nil {
+			l.Debugf("Bridge exiting: %s", err)
+			return
+		}
+	}
+}
+
+// isClientError returns true for errors that will not be resolved by a retry
+func isClientError(err error) (status int, isClient bool) {
+	status, ok := i18n.GetStatusHint(strings.SplitN(err.Error(), ":", 2)[0])
+	return status, ok && status >= 400 && status < 500
+}
+
+func (b *bridge) bridgeEvent(event *core.Event) error {
+	l := log.L(b.ctx)
+	return b.retry.Do(b.ctx, "bridge message", func(attempt int) (retry bool, err error) {
+		msg, err := b.source.GetMessageByIDWithData(b.ctx, event.Reference.String())
+		if err != nil {
+			if _, isClient := isClientError(err); isClient {
+				l.Warnf("Bridge skipped message '%s': %s", event.Reference, err)
+				return false, nil
+			}
+			return true, err
+		}
+		in := b.mapMessage(msg)
+		if in == nil {
+			return false, nil
+		}
+		target, err := b.resolve(b.ctx, b.conf.Target)
+		if err != nil {
+			return true, err
+		}
+		if err = b.publish(target, in); err != nil {
+			status, isClient := isClientError(err)
+			switch {
+			case status == 409:
+				l.Debugf("Message '%s' has already been bridged", msg.Header.ID)
+			case isClient:
+				l.Warnf("Bridge skipped message '%s': %s", msg.Header.ID, err)
+			default:
+				return true, err
+			}
+			return false, nil
+		}
+		l.Infof("Bridged message '%s' to namespace '%s' as message '%s'", msg.Header.ID, b.conf.Target, in.Header.ID)
+		return false, nil
+	})
+}
+
+func (b *bridge) publish(target orchestrator.Orchestrator, in *core.MessageInOut) (err error) {
+	if in.Group != nil {
+		pm := target.PrivateMessaging()
+		if pm == nil {
+			return i18n.NewError(b.ctx, coremsgs.MsgBridgeInvalid, b.conf.Name, b.namespace, "target namespace is not multiparty")
+		}
+		_, err = pm.SendMessage(b.ctx, in, false)
+		return err
+	}
+	bm := target.Broadcast()
+	if bm == nil {
+		return i18n.NewError(b.ctx, coremsgs.MsgBridgeInvalid, b.conf.Name, b.namespace, "target namespace is not multiparty")
+	}
+	_, err = bm.BroadcastMessage(b.ctx, in, false)
+	return err
+}
+
+func (b *bridge) matches(msg *core.MessageInOut) bool {
+	switch msg.Header.Type {
+	case core.MessageTypeBroadcast:
+	case core.MessageTypePrivate:
+		if !b.conf.IncludePrivate {
+			return false
+		}
+	default:
+		return false
+	}
+	if strings.HasPrefix(string(msg.IdempotencyKey), IdempotencyKeyPrefix) {
+		return false
+	}
+	if b.conf.Tag != nil && !b.conf.Tag.MatchString(msg.Header.Tag) {
+		return false
+	}
+	if b.conf.Author != nil && !b.conf.Author.MatchString(msg.Header.Author) {
+		return false
+	}
+	if b.conf.Topics != nil {
+		for _, topic := range msg.Header.Topics {
+			if b.conf.Topics.MatchString(topic) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// mapMessage builds the message to publish into the target namespace, or returns nil if the message is not bridged
+func (b *bridge) mapMessage(msg *core.MessageInOut) *core.MessageInOut {
+	if !b.matches(msg) {
+		return nil
+	}
+
+	data := make(core.InlineData, len(msg.InlineData))
+	for i, d := range msg.InlineData {
+		if d.Blob != nil {
+			log.L(b.ctx).Warnf("Bridge skipped message '%s' as data '%s' has a blob", msg.Header.ID, d.ID)
+			return nil
+		}
+		data[i] = &core.DataRefOrValue{Value: d.Value}
+	}
+
+	topics := make(fftypes.FFStringArray, len(msg.Header.Topics))
+	for i, topic := range msg.Header.Topics {
+		if mapped, ok := b.conf.TopicMap[topic]; ok {
+			topic = mapped
+		}
+		topics[i] = topic
+	}
+
+	in := &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				CID:    msg.Header.ID,
+				Tag:    msg.Header.Tag,
+				Topics: topics,
+			},
+			IdempotencyKey: core.IdempotencyKey(IdempotencyKeyPrefix + b.conf.Name + ":" + msg.Header.ID.String()),
+		},
+		InlineData: data,
+	}
+	// Authors without a mapping are sent as the default identity of the target namespace
+	in.Header.Author = b.conf.IdentityMap[msg.Header.Author]
+	if len(b.conf.Members) > 0 {
+		in.Group = &core.InputGroup{
+			Members: make([]core.MemberInput, len(b.conf.Members)),
+		}
+		for i, member := range b.conf.Members {
+			in.Group.Members[i] = core.MemberInput{Identity: member}
+		}
+	}
+	return in
+}
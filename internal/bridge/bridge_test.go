@@ -0,0 +1,385 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bridge
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testBridge struct {
+	bridge
+	cancel func()
+	mdi    *databasemocks.Plugin
+	mos    *orchestratormocks.Orchestrator
+	mot    *orchestratormocks.Orchestrator
+	mbm    *broadcastmocks.Manager
+	mpm    *privatemessagingmocks.Manager
+}
+
+func (tb *testBridge) cleanup(t *testing.T) {
+	tb.cancel()
+	tb.mdi.AssertExpectations(t)
+	tb.mos.AssertExpectations(t)
+	tb.mot.AssertExpectations(t)
+	tb.mbm.AssertExpectations(t)
+	tb.mpm.AssertExpectations(t)
+}
+
+func newTestBridge(t *testing.T, conf *Config) *testBridge {
+	coreconfig.Reset()
+	config.Set(coreconfig.BridgeRetryInitDelay, "1us")
+	config.Set(coreconfig.BridgeRetryMaxDelay, "1us")
+	config.Set(coreconfig.BridgePollInterval, "1us")
+	ctx, cancel := context.WithCancel(context.Background())
+	tb := &testBridge{
+		cancel: cancel,
+		mdi:    &databasemocks.Plugin{},
+		mos:    &orchestratormocks.Orchestrator{},
+		mot:    &orchestratormocks.Orchestrator{},
+		mbm:    &broadcastmocks.Manager{},
+		mpm:    &privatemessagingmocks.Manager{},
+	}
+	tb.mot.On("Broadcast").Return(tb.mbm).Maybe()
+	tb.mot.On("PrivateMessaging").Return(tb.mpm).Maybe()
+	b := NewBridge(ctx, "ns1", conf, tb.mdi, tb.mos, func(ctx context.Context, ns string) (orchestrator.Orchestrator, error) {
+		assert.Equal(t, "ns2", ns)
+		return tb.mot, nil
+	}).(*bridge)
+	tb.bridge = *b
+	return tb
+}
+
+func newTestMessage(msgType core.MessageType) *core.MessageInOut {
+	return &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				ID:     fftypes.NewUUID(),
+				Type:   msgType,
+				Tag:    "tag1",
+				Topics: fftypes.FFStringArray{"topic1", "topic2"},
+				SignerRef: core.SignerRef{
+					Author: "did:firefly:org/org1",
+				},
+			},
+		},
+		InlineData: core.InlineData{
+			{DataRef: core.DataRef{ID: fftypes.NewUUID()}, Value: fftypes.JSONAnyPtr(`{"some":"data"}`)},
+		},
+	}
+}
+
+func TestBridgeLoopBroadcast(t *testing.T) {
+	tb := newTestBridge(t, &Config{
+		Name:        "bridge1",
+		Target:      "ns2",
+		TopicMap:    map[string]string{"topic1": "mapped1"},
+		IdentityMap: map[string]string{"did:firefly:org/org1": "org2"},
+	})
+	defer tb.cleanup(t)
+
+	msg := newTestMessage(core.MessageTypeBroadcast)
+	event := &core.Event{Sequence: 12345, Reference: msg.Header.ID}
+
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(&core.Offset{RowID: 11, Current: 12344}, nil)
+	tb.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{event}, nil, nil).Once()
+	tb.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msg.Header.ID.String()).Return(msg, nil)
+	tb.mbm.On("BroadcastMessage", mock.Anything, mock.MatchedBy(func(in *core.MessageInOut) bool {
+		return in.Header.CID.Equals(msg.Header.ID) &&
+			in.Header.Tag == "tag1" &&
+			in.Header.Author == "org2" &&
+			in.Header.Topics.String() == "mapped1,topic2" &&
+			in.IdempotencyKey == core.IdempotencyKey("bridge:bridge1:"+msg.Header.ID.String()) &&
+			len(in.InlineData) == 1 &&
+			in.InlineData[0].ID == nil &&
+			in.InlineData[0].Value.String() == `{"some":"data"}`
+	}), false).Return(&msg.Message, nil)
+	tb.mdi.On("UpdateOffset", mock.Anything, int64(11), mock.Anything).Return(nil)
+
+	tb.Start()
+	tb.WaitStop()
+
+	assert.Equal(t, int64(12345), tb.offset)
+}
+
+func TestBridgeLoopPrivateMembers(t *testing.T) {
+	tb := newTestBridge(t, &Config{
+		Name:           "bridge1",
+		Target:         "ns2",
+		IncludePrivate: true,
+		Members:        []string{"org2", "org3"},
+	})
+	defer tb.cleanup(t)
+
+	msg := newTestMessage(core.MessageTypePrivate)
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msg.Header.ID.String()).Return(msg, nil)
+	tb.mpm.On("SendMessage", mock.Anything, mock.MatchedBy(func(in *core.MessageInOut) bool {
+		return in.Header.Author == "" &&
+			len(in.Group.Members) == 2 &&
+			in.Group.Members[0].Identity == "org2" &&
+			in.Group.Members[1].Identity == "org3"
+	}), false).Return(&msg.Message, nil)
+
+	err := tb.bridgeEvent(&core.Event{Reference: msg.Header.ID})
+	assert.NoError(t, err)
+}
+
+func TestBridgeLoopRestoreOffsetFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+
+	tb.Start()
+	tb.WaitStop()
+}
+
+func TestBridgeLoopReadPageFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(&core.Offset{RowID: 11, Current: 12344}, nil)
+	tb.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+
+	tb.Start()
+	tb.WaitStop()
+}
+
+func TestBridgeLoopBridgeEventFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	msgID := fftypes.NewUUID()
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(&core.Offset{RowID: 11, Current: 12344}, nil)
+	tb.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Sequence: 12345, Reference: msgID}}, nil, nil)
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msgID.String()).Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+
+	tb.Start()
+	tb.WaitStop()
+}
+
+func TestBridgeLoopCommitOffsetFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2", Tag: regexp.MustCompile("^other$")})
+	defer tb.cleanup(t)
+
+	msg := newTestMessage(core.MessageTypeBroadcast)
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(&core.Offset{RowID: 11, Current: 12344}, nil)
+	tb.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Sequence: 12345, Reference: msg.Header.ID}}, nil, nil)
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msg.Header.ID.String()).Return(msg, nil)
+	tb.mdi.On("UpdateOffset", mock.Anything, int64(11), mock.Anything).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+
+	tb.Start()
+	tb.WaitStop()
+}
+
+func TestRestoreOffsetNewest(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2", FirstEvent: core.SubOptsFirstEventNewest})
+	defer tb.cleanup(t)
+
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(nil, nil)
+	tb.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Sequence: 12345}}, nil, nil)
+	tb.mdi.On("UpsertOffset", mock.Anything, mock.MatchedBy(func(o *core.Offset) bool {
+		return o.Type == core.OffsetTypeBridge && o.Name == "ns1:bridge1" && o.Current == 12345
+	}), false).Return(nil)
+
+	err := tb.restoreOffset()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), tb.offset)
+}
+
+func TestRestoreOffsetOldest(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2", FirstEvent: core.SubOptsFirstEventOldest})
+	defer tb.cleanup(t)
+
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(nil, nil)
+	tb.mdi.On("UpsertOffset", mock.Anything, mock.Anything, false).Return(nil)
+
+	err := tb.restoreOffset()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(-1), tb.offset)
+}
+
+func TestRestoreOffsetNewestFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(nil, nil)
+	tb.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+
+	err := tb.restoreOffset()
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestRestoreOffsetUpsertFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2", FirstEvent: core.SubOptsFirstEventOldest})
+	defer tb.cleanup(t)
+
+	tb.mdi.On("GetOffset", mock.Anything, core.OffsetTypeBridge, "ns1:bridge1").Return(nil, nil)
+	tb.mdi.On("UpsertOffset", mock.Anything, mock.Anything, false).Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+
+	err := tb.restoreOffset()
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestBridgeEventSourceNotFound(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	msgID := fftypes.NewUUID()
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msgID.String()).Return(nil, i18n.NewError(context.Background(), coremsgs.Msg404NotFound))
+
+	err := tb.bridgeEvent(&core.Event{Reference: msgID})
+	assert.NoError(t, err)
+}
+
+func TestBridgeEventResolveFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	tb.resolve = func(ctx context.Context, ns string) (orchestrator.Orchestrator, error) {
+		tb.cancel()
+		return nil, i18n.NewError(ctx, coremsgs.MsgNamespaceInitializing, ns)
+	}
+	msg := newTestMessage(core.MessageTypeBroadcast)
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msg.Header.ID.String()).Return(msg, nil)
+
+	err := tb.bridgeEvent(&core.Event{Reference: msg.Header.ID})
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestBridgeEventAlreadyBridged(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	msg := newTestMessage(core.MessageTypeBroadcast)
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msg.Header.ID.String()).Return(msg, nil)
+	tb.mbm.On("BroadcastMessage", mock.Anything, mock.Anything, false).Return(nil, i18n.NewError(context.Background(), coremsgs.MsgIdempotencyKeyDuplicateMessage, "bridge:bridge1", fftypes.NewUUID()))
+
+	err := tb.bridgeEvent(&core.Event{Reference: msg.Header.ID})
+	assert.NoError(t, err)
+}
+
+func TestBridgeEventRejected(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	msg := newTestMessage(core.MessageTypeBroadcast)
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msg.Header.ID.String()).Return(msg, nil)
+	tb.mbm.On("BroadcastMessage", mock.Anything, mock.Anything, false).Return(nil, i18n.NewError(context.Background(), coremsgs.MsgAuthorInvalid))
+
+	err := tb.bridgeEvent(&core.Event{Reference: msg.Header.ID})
+	assert.NoError(t, err)
+}
+
+func TestBridgeEventPublishFail(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	msg := newTestMessage(core.MessageTypeBroadcast)
+	tb.mos.On("GetMessageByIDWithData", mock.Anything, msg.Header.ID.String()).Return(msg, nil)
+	tb.mbm.On("BroadcastMessage", mock.Anything, mock.Anything, false).Return(nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		tb.cancel()
+	})
+
+	err := tb.bridgeEvent(&core.Event{Reference: msg.Header.ID})
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestPublishNotMultiparty(t *testing.T) {
+	tb := newTestBridge(t, &Config{Name: "bridge1", Target: "ns2"})
+	defer tb.cleanup(t)
+
+	mot := &orchestratormocks.Orchestrator{}
+	mot.On("Broadcast").Return(nil)
+	mot.On("PrivateMessaging").Return(nil)
+
+	err := tb.publish(mot, &core.MessageInOut{})
+	assert.Regexp(t, "FF10654", err)
+	err = tb.publish(mot, &core.MessageInOut{Group: &core.InputGroup{}})
+	assert.Regexp(t, "FF10654", err)
+}
+
+func TestMapMessageFilters(t *testing.T) {
+	tb := newTestBridge(t, &Config{
+		Name:   "bridge1",
+		Target: "ns2",
+		Topics: regexp.MustCompile("^topic2$"),
+		Tag:    regexp.MustCompile("^tag"),
+		Author: regexp.MustCompile("org1$"),
+	})
+	defer tb.cleanup(t)
+
+	msg := newTestMessage(core.MessageTypeBroadcast)
+	assert.NotNil(t, tb.mapMessage(msg))
+
+	msg = newTestMessage(core.MessageTypePrivate)
+	assert.Nil(t, tb.mapMessage(msg))
+
+	msg = newTestMessage(core.MessageTypeDefinition)
+	assert.Nil(t, tb.mapMessage(msg))
+
+	msg = newTestMessage(core.MessageTypeBroadcast)
+	msg.IdempotencyKey = "bridge:other:12345"
+	assert.Nil(t, tb.mapMessage(msg))
+
+	msg = newTestMessage(core.MessageTypeBroadcast)
+	msg.Header.Tag = "other"
+	assert.Nil(t, tb.mapMessage(msg))
+
+	msg = newTestMessage(core.MessageTypeBroadcast)
+	msg.Header.Author = "did:firefly:org/org2"
+	assert.Nil(t, tb.mapMessage(msg))
+
+	msg = newTestMessage(core.MessageTypeBroadcast)
+	msg.Header.Topics = fftypes.FFStringArray{"topic1"}
+	assert.Nil(t, tb.mapMessage(msg))
+
+	msg = newTestMessage(core.MessageTypeBroadcast)
+	msg.InlineData[0].Blob = &core.BlobRef{Hash: fftypes.NewRandB32()}
+	assert.Nil(t, tb.mapMessage(msg))
+}
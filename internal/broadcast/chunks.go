@@ -0,0 +1,125 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broadcast
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// chunkOverhead is the space reserved in the batch payload for the message and data that wrap each chunk
+const chunkOverhead = int64(4096)
+
+// chunkSize is the number of bytes of the data of a message that are sent in each chunk.
+// The chunks are base64 encoded, so each is 4/3 of the size of the bytes it holds.
+func (bm *broadcastManager) chunkSize() int64 {
+	return (bm.maxBatchPayloadLength - chunkOverhead) / 4 * 3
+}
+
+// canChunk checks whether a message that is too large for a batch can be split into chunk messages.
+// Blobs are already transferred separately to the batch, so a message with blobs is never chunked.
+func (bm *broadcastManager) canChunk(newMsg *data.NewMessage) bool {
+	msg := newMsg.Message
+	if !bm.chunkingEnabled ||
+		msg.Header.Type != core.MessageTypeBroadcast ||
+		msg.Header.TxType != core.TransactionTypeBatchPin ||
+		msg.SendTime != nil ||
+		bm.chunkSize() <= 0 {
+		return false
+	}
+	for _, d := range newMsg.AllData {
+		if d.Blob != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// sendChunks splits the data of the message into chunk messages, and writes them ahead of the message.
+// The chunks are sent on the same topics as the message, so they are confirmed before it by every member.
+func (s *broadcastSender) sendChunks(ctx context.Context) error {
+	msg := s.msg.Message
+	batchData := make(core.DataArray, len(s.msg.AllData))
+	for i, d := range s.msg.AllData {
+		batchData[i] = d.BatchData(core.BatchTypeBroadcast)
+	}
+	payload, _ := json.Marshal(batchData)
+
+	chunkSize := int(s.mgr.chunkSize())
+	chunkIDs := make(fftypes.FFStringArray, 0, len(payload)/chunkSize+1)
+	for start := 0; start < len(payload); start += chunkSize {
+		end := start + chunkSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		chunk, err := s.newChunk(ctx, payload[start:end])
+		if err != nil {
+			return err
+		}
+		if err := s.mgr.data.WriteNewMessage(ctx, chunk); err != nil {
+			return err
+		}
+		chunkIDs = append(chunkIDs, chunk.Message.Header.ID.String())
+	}
+	msg.Chunks = chunkIDs
+	log.L(ctx).Infof("Split broadcast message %s into %d chunks", msg.Header.ID, len(chunkIDs))
+	return nil
+}
+
+func (s *broadcastSender) newChunk(ctx context.Context, piece []byte) (*data.NewMessage, error) {
+	msg := s.msg.Message
+	d := &core.Data{
+		Namespace: msg.LocalNamespace,
+		Value:     fftypes.JSONAnyPtr(strconv.Quote(base64.StdEncoding.EncodeToString(piece))),
+	}
+	if err := d.Seal(ctx, nil); err != nil {
+		return nil, err
+	}
+	chunk := &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				CID:       msg.Header.ID,
+				Type:      core.MessageTypeChunk,
+				TxType:    msg.Header.TxType,
+				SignerRef: msg.Header.SignerRef,
+				Namespace: msg.Header.Namespace,
+				Topics:    msg.Header.Topics,
+			},
+			LocalNamespace: msg.LocalNamespace,
+			State:          msg.State,
+			Priority:       msg.Priority,
+			Expires:        msg.Expires,
+			Data:           core.DataArray{d}.Refs(),
+		},
+	}
+	if err := chunk.Seal(ctx); err != nil {
+		return nil, err
+	}
+	return &data.NewMessage{
+		Message: chunk,
+		AllData: core.DataArray{d},
+		NewData: core.DataArray{d},
+	}, nil
+}
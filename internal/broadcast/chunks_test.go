@@ -0,0 +1,204 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broadcast
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLargeData(t *testing.T, size int) *core.Data {
+	d := &core.Data{
+		Namespace: "ns1",
+		Value:     fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, strings.Repeat("a", size))),
+	}
+	err := d.Seal(context.Background(), nil)
+	assert.NoError(t, err)
+	return d
+}
+
+func mockResolveLargeData(mdm *datamocks.Manager, d *core.Data) {
+	mdm.On("ResolveInlineData", mock.Anything, mock.Anything).Run(
+		func(args mock.Arguments) {
+			newMsg := args[1].(*data.NewMessage)
+			newMsg.AllData = core.DataArray{d}
+			newMsg.Message.Data = newMsg.AllData.Refs()
+		}).
+		Return(nil)
+}
+
+func TestBroadcastMessageChunked(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	bm.maxBatchPayloadLength = 10000
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	d := newLargeData(t, 20000)
+	mockResolveLargeData(mdm, d)
+	mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
+	var chunks []*data.NewMessage
+	mdm.On("WriteNewMessage", mock.Anything, mock.MatchedBy(func(newMsg *data.NewMessage) bool {
+		return newMsg.Message.Header.Type == core.MessageTypeChunk
+	})).Run(func(args mock.Arguments) {
+		chunks = append(chunks, args[1].(*data.NewMessage))
+	}).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.MatchedBy(func(newMsg *data.NewMessage) bool {
+		return newMsg.Message.Header.Type == core.MessageTypeBroadcast
+	})).Return(nil)
+
+	msg, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Topics: fftypes.FFStringArray{"topic1"},
+			},
+			Priority: core.MessagePriorityHigh,
+		},
+		InlineData: core.InlineData{
+			{Value: d.Value},
+		},
+	}, false)
+	assert.NoError(t, err)
+
+	assert.Len(t, chunks, 5)
+	assert.Len(t, msg.Chunks, 5)
+	var payload []byte
+	for i, chunk := range chunks {
+		assert.Equal(t, msg.Chunks[i], chunk.Message.Header.ID.String())
+		assert.Equal(t, msg.Header.ID, chunk.Message.Header.CID)
+		assert.Equal(t, msg.Header.Topics, chunk.Message.Header.Topics)
+		assert.Equal(t, core.MessagePriorityHigh, chunk.Message.Priority)
+		assert.NotNil(t, chunk.Message.Hash)
+		assert.LessOrEqual(t, chunk.Message.EstimateSize(true), bm.maxBatchPayloadLength)
+		var encoded string
+		err := json.Unmarshal(chunk.AllData[0].Value.Bytes(), &encoded)
+		assert.NoError(t, err)
+		piece, err := base64.StdEncoding.DecodeString(encoded)
+		assert.NoError(t, err)
+		payload = append(payload, piece...)
+	}
+	var reassembled core.DataArray
+	err = json.Unmarshal(payload, &reassembled)
+	assert.NoError(t, err)
+	assert.Equal(t, d.Hash, reassembled[0].Hash)
+	assert.Equal(t, d.Value, reassembled[0].Value)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageChunkedWriteFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	bm.maxBatchPayloadLength = 10000
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	d := newLargeData(t, 20000)
+	mockResolveLargeData(mdm, d)
+	mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Once()
+
+	_, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		InlineData: core.InlineData{
+			{Value: d.Value},
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageChunkedSealFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	bm.maxBatchPayloadLength = 10000
+	defer cancel()
+
+	bm.data.(*datamocks.Manager).On("WriteNewMessage", mock.Anything, mock.Anything).Return(nil).Maybe()
+	s := &broadcastSender{
+		mgr: bm,
+		msg: &data.NewMessage{
+			Message: &core.MessageInOut{
+				Message: core.Message{
+					Header: core.MessageHeader{
+						ID:     fftypes.NewUUID(),
+						TxType: core.TransactionTypeBatchPin,
+						Topics: fftypes.FFStringArray{"!bad"},
+					},
+				},
+			},
+			AllData: core.DataArray{newLargeData(t, 20000)},
+		},
+	}
+	err := s.sendChunks(context.Background())
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestCanChunk(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	bm.maxBatchPayloadLength = 10000
+	defer cancel()
+
+	newMsg := func() *data.NewMessage {
+		return &data.NewMessage{
+			Message: &core.MessageInOut{
+				Message: core.Message{
+					Header: core.MessageHeader{
+						Type:   core.MessageTypeBroadcast,
+						TxType: core.TransactionTypeBatchPin,
+					},
+				},
+			},
+			AllData: core.DataArray{{Value: fftypes.JSONAnyPtr(`"value"`)}},
+		}
+	}
+	assert.True(t, bm.canChunk(newMsg()))
+
+	m := newMsg()
+	m.Message.Header.Type = core.MessageTypeDefinition
+	assert.False(t, bm.canChunk(m))
+
+	m = newMsg()
+	m.Message.Header.TxType = core.TransactionTypeContractInvokePin
+	assert.False(t, bm.canChunk(m))
+
+	m = newMsg()
+	m.Message.SendTime = fftypes.Now()
+	assert.False(t, bm.canChunk(m))
+
+	m = newMsg()
+	m.AllData[0].Blob = &core.BlobRef{Hash: fftypes.NewRandB32()}
+	assert.False(t, bm.canChunk(m))
+
+	bm.maxBatchPayloadLength = chunkOverhead
+	assert.False(t, bm.canChunk(newMsg()))
+
+	bm.maxBatchPayloadLength = 10000
+	bm.chunkingEnabled = false
+	assert.False(t, bm.canChunk(newMsg()))
+}
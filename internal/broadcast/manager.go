@@ -64,12 +64,14 @@ type broadcastManager struct {
 	database              database.Plugin
 	identity              identity.Manager
 	data                  data.Manager
+	batch                 batch.Manager
 	blockchain            blockchain.Plugin
 	exchange              dataexchange.Plugin
 	sharedstorage         sharedstorage.Plugin
 	syncasync             syncasync.Bridge
 	multiparty            multiparty.Manager
 	maxBatchPayloadLength int64
+	chunkingEnabled       bool
 	metrics               metrics.Manager
 	operations            operations.Manager
 	txHelper              txcommon.Helper
@@ -85,12 +87,14 @@ func NewBroadcastManager(ctx context.Context, ns *core.Namespace, di database.Pl
 		database:              di,
 		identity:              im,
 		data:                  dm,
+		batch:                 ba,
 		blockchain:            bi,
 		exchange:              dx,
 		sharedstorage:         si,
 		syncasync:             sa,
 		multiparty:            mult,
 		maxBatchPayloadLength: config.GetByteSize(coreconfig.BroadcastBatchPayloadLimit),
+		chunkingEnabled:       config.GetBool(coreconfig.BroadcastChunkingEnabled),
 		metrics:               mm,
 		operations:            om,
 		txHelper:              txHelper,
@@ -112,6 +116,7 @@ func NewBroadcastManager(ctx context.Context, ns *core.Namespace, di database.Pl
 				core.MessageTypeDefinition,
 				core.MessageTypeDeprecatedTransferBroadcast,
 				core.MessageTypeDeprecatedApprovalBroadcast,
+				core.MessageTypeChunk,
 			}, bm.dispatchBatch, bo)
 
 		ba.RegisterDispatcher(broadcastDispatcherName,
@@ -64,6 +64,7 @@ func newTestBroadcastCommon(t *testing.T, metricsEnabled bool) (*broadcastManage
 	mbi.On("Name").Return("ut_blockchain").Maybe()
 	mpi.On("Name").Return("ut_sharedstorage").Maybe()
 
+	mba.On("CheckBackpressure", mock.Anything, mock.Anything).Return(nil).Maybe()
 	mba.On("RegisterDispatcher",
 		broadcastDispatcherName,
 		core.TransactionTypeBatchPin,
@@ -72,6 +73,7 @@ func newTestBroadcastCommon(t *testing.T, metricsEnabled bool) (*broadcastManage
 			core.MessageTypeDefinition,
 			core.MessageTypeDeprecatedTransferBroadcast,
 			core.MessageTypeDeprecatedApprovalBroadcast,
+			core.MessageTypeChunk,
 		}, mock.Anything, mock.Anything).Return()
 
 	mba.On("RegisterDispatcher",
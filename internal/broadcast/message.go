@@ -18,6 +18,7 @@ package broadcast
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -57,6 +58,7 @@ type broadcastSender struct {
 	mgr      *broadcastManager
 	msg      *data.NewMessage
 	resolved bool
+	chunked  bool
 }
 
 // sendMethod is the specific operation requested of the broadcastSender.
@@ -110,7 +112,10 @@ func (s *broadcastSender) resolveAndSend(ctx context.Context, method sendMethod)
 		}
 		msgSizeEstimate := s.msg.Message.EstimateSize(true)
 		if msgSizeEstimate > s.mgr.maxBatchPayloadLength {
-			return i18n.NewError(ctx, coremsgs.MsgTooLargeBroadcast, float64(msgSizeEstimate)/1024, float64(s.mgr.maxBatchPayloadLength)/1024)
+			if !s.mgr.canChunk(s.msg) {
+				return i18n.NewError(ctx, coremsgs.MsgTooLargeBroadcast, float64(msgSizeEstimate)/1024, float64(s.mgr.maxBatchPayloadLength)/1024)
+			}
+			s.chunked = true
 		}
 		s.resolved = true
 	}
@@ -120,6 +125,31 @@ func (s *broadcastSender) resolveAndSend(ctx context.Context, method sendMethod)
 func (s *broadcastSender) resolve(ctx context.Context) error {
 	msg := s.msg.Message
 
+	// Check the priority class, which is case insensitive on input
+	if msg.Priority != "" {
+		priority, err := fftypes.FFEnumParseString(ctx, "messagepriority", msg.Priority.String())
+		if err != nil {
+			return err
+		}
+		msg.Priority = priority
+	}
+
+	// Set the expiry from the TTL, which starts from the send time of a scheduled message
+	if msg.TTL != nil {
+		if msg.Draft {
+			return i18n.NewError(ctx, coremsgs.MsgDraftWithTTL)
+		}
+		if *msg.TTL <= 0 {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidMessageTTL, msg.TTL)
+		}
+		start := time.Now()
+		if msg.SendTime != nil && time.Time(*msg.SendTime).After(start) {
+			start = time.Time(*msg.SendTime)
+		}
+		expires := fftypes.FFTime(start.Add(time.Duration(*msg.TTL)))
+		msg.Expires = &expires
+	}
+
 	// Resolve the sending identity
 	if msg.Header.Type != core.MessageTypeDefinition || msg.Header.Tag != core.SystemTagIdentityClaim {
 		if err := s.mgr.identity.ResolveInputSigningIdentity(ctx, &msg.Header.SignerRef); err != nil {
@@ -127,6 +157,13 @@ func (s *broadcastSender) resolve(ctx context.Context) error {
 		}
 	}
 
+	// A reply in a thread must be to a message this node has
+	if msg.Header.ParentMessage != nil {
+		if err := s.mgr.data.CheckParentMessage(ctx, &msg.Message); err != nil {
+			return err
+		}
+	}
+
 	// The data manager is responsible for the heavy lifting of storing/validating all our in-line data elements
 	err := s.mgr.data.ResolveInlineData(ctx, s.msg)
 	return err
@@ -134,6 +171,12 @@ func (s *broadcastSender) resolve(ctx context.Context) error {
 
 func (s *broadcastSender) sendInternal(ctx context.Context, method sendMethod) (err error) {
 	if method == methodSendAndWait {
+		if s.msg.Message.SendTime != nil {
+			return i18n.NewError(ctx, coremsgs.MsgSendTimeWithWait)
+		}
+		if s.msg.Message.Draft {
+			return i18n.NewError(ctx, coremsgs.MsgDraftWithWait)
+		}
 		out, err := s.mgr.syncasync.WaitForMessage(ctx, s.msg.Message.Header.ID, s.Send)
 		if out != nil {
 			s.msg.Message.Message = *out
@@ -150,6 +193,30 @@ func (s *broadcastSender) sendInternal(ctx context.Context, method sendMethod) (
 		return nil
 	}
 
+	// Hold the message for the batch manager to dispatch at its send time
+	if msg.SendTime != nil && time.Time(*msg.SendTime).After(time.Now()) {
+		msg.State = core.MessageStateScheduled
+	}
+
+	// Hold a draft until it is committed, unless the caller has set its own state (such as for a token transfer)
+	if msg.Draft && (msg.State == core.MessageStateReady || msg.State == core.MessageStateScheduled) {
+		msg.State = core.MessageStateDraft
+	}
+
+	// Reject a message that is ready to send, while the batch queue of any of its topics is over its limit
+	if msg.State == core.MessageStateReady && s.mgr.batch != nil {
+		if err := s.mgr.batch.CheckBackpressure(ctx, msg.Header.Topics); err != nil {
+			return err
+		}
+	}
+
+	// Send the data of a message that is too large for a batch in chunk messages ahead of it
+	if s.chunked {
+		if err := s.sendChunks(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Write the message
 	if err := s.mgr.data.WriteNewMessage(ctx, s.msg); err != nil {
 		return err
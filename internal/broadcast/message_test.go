@@ -20,10 +20,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
@@ -140,9 +142,319 @@ func TestBroadcastMessageWaitConfirmOk(t *testing.T) {
 	mdm.AssertExpectations(t)
 }
 
+func TestBroadcastMessageScheduled(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.MatchedBy(func(newMsg *data.NewMessage) bool {
+		return newMsg.Message.State == core.MessageStateScheduled
+	})).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	sendTime := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				SignerRef: core.SignerRef{
+					Author: "did:firefly:org/abcd",
+					Key:    "0x12345",
+				},
+			},
+			SendTime: &sendTime,
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateScheduled, msg.State)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageSendTimePast(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	sendTime := fftypes.FFTime(time.Now().Add(-1 * time.Hour))
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			SendTime: &sendTime,
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateReady, msg.State)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageSendTimeWaitConfirm(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	_, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			SendTime: fftypes.Now(),
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, true)
+	assert.Regexp(t, "FF10617", err)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageHighPriority(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			Priority: "HIGH",
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessagePriorityHigh, msg.Priority)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageBadPriority(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	_, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		Message: core.Message{
+			Priority: "urgent",
+		},
+	}, false)
+	assert.Regexp(t, "FF00172", err)
+}
+
+func TestBroadcastMessageTTL(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	ttl := fftypes.FFDuration(1 * time.Minute)
+	before := time.Now()
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		TTL: &ttl,
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.False(t, time.Time(*msg.Expires).Before(before.Add(1*time.Minute)))
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageBadTTL(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	ttl := fftypes.FFDuration(0)
+	_, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		TTL: &ttl,
+	}, false)
+	assert.Regexp(t, "FF10619", err)
+}
+
+func TestBroadcastMessageParentMessage(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	parentID := fftypes.NewUUID()
+	mdm.On("CheckParentMessage", ctx, mock.MatchedBy(func(msg *core.Message) bool {
+		return msg.Header.ParentMessage.Equals(parentID)
+	})).Return(nil)
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{ParentMessage: parentID},
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, parentID, msg.Header.ParentMessage)
+
+	mdm.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestBroadcastMessageParentMessageFail(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("CheckParentMessage", ctx, mock.Anything).Return(fmt.Errorf("pop"))
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	_, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{ParentMessage: fftypes.NewUUID()},
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestBroadcastMessageDraft(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", mock.Anything, mock.MatchedBy(func(newMsg *data.NewMessage) bool {
+		return newMsg.Message.State == core.MessageStateDraft
+	})).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	sendTime := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	msg, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			SendTime: &sendTime,
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+		Draft: true,
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateDraft, msg.State)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageDraftWaitConfirm(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+
+	_, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+		Draft: true,
+	}, true)
+	assert.Regexp(t, "FF10661", err)
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestBroadcastMessageDraftTTL(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	ttl := fftypes.FFDuration(1 * time.Hour)
+	_, err := bm.BroadcastMessage(context.Background(), &core.MessageInOut{
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+		TTL:   &ttl,
+		Draft: true,
+	}, false)
+	assert.Regexp(t, "FF10662", err)
+}
+
+func TestBroadcastMessageBackpressure(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+	mdm := bm.data.(*datamocks.Manager)
+	mim := bm.identity.(*identitymanagermocks.Manager)
+	mba := &batchmocks.Manager{}
+	bm.batch = mba
+
+	ctx := context.Background()
+	mdm.On("ResolveInlineData", ctx, mock.Anything).Return(nil)
+	mim.On("ResolveInputSigningIdentity", ctx, mock.Anything).Return(nil)
+	mba.On("CheckBackpressure", ctx, []string{"topic1"}).Return(fmt.Errorf("pop"))
+
+	_, err := bm.BroadcastMessage(ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Topics: fftypes.FFStringArray{"topic1"},
+			},
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"hello": "world"}`)},
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+	mba.AssertExpectations(t)
+}
+
 func TestBroadcastMessageTooLarge(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	bm.maxBatchPayloadLength = 1000000
+	bm.chunkingEnabled = false
 	defer cancel()
 	mdm := bm.data.(*datamocks.Manager)
 	mim := bm.identity.(*identitymanagermocks.Manager)
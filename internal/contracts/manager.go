@@ -58,6 +58,8 @@ type Manager interface {
 	DeployContract(ctx context.Context, req *core.ContractDeployRequest, waitConfirm bool) (interface{}, error)
 	InvokeContract(ctx context.Context, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error)
 	InvokeContractAPI(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error)
+	ResolveInvokeContractRequest(ctx context.Context, req *core.ContractCallRequest) error
+	NewInvokeOperation(txid *fftypes.UUID, opType core.OpType, req *core.ContractCallRequest) (*core.Operation, error)
 	GetContractAPI(ctx context.Context, httpServerURL, apiName string) (*core.ContractAPI, error)
 	GetContractAPIInterface(ctx context.Context, apiName string) (*fftypes.FFI, error)
 	GetContractAPIs(ctx context.Context, httpServerURL string, filter ffapi.AndFilter) ([]*core.ContractAPI, *ffapi.FilterResult, error)
@@ -80,6 +82,7 @@ type Manager interface {
 
 type contractManager struct {
 	namespace         string
+	confirmations     int
 	database          database.Plugin
 	data              data.Manager
 	broadcast         broadcast.Manager        // optional
@@ -93,7 +96,7 @@ type contractManager struct {
 	syncasync         syncasync.Bridge
 }
 
-func NewContractManager(ctx context.Context, ns string, di database.Plugin, bi blockchain.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, bp batch.Manager, im identity.Manager, om operations.Manager, txHelper txcommon.Helper, sa syncasync.Bridge) (Manager, error) {
+func NewContractManager(ctx context.Context, ns string, confirmations int, di database.Plugin, bi blockchain.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, bp batch.Manager, im identity.Manager, om operations.Manager, txHelper txcommon.Helper, sa syncasync.Bridge) (Manager, error) {
 	if di == nil || im == nil || bi == nil || dm == nil || om == nil || txHelper == nil || sa == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "ContractManager")
 	}
@@ -104,6 +107,7 @@ func NewContractManager(ctx context.Context, ns string, di database.Plugin, bi b
 
 	cm := &contractManager{
 		namespace:         ns,
+		confirmations:     confirmations,
 		database:          di,
 		data:              dm,
 		broadcast:         bm,
@@ -329,6 +333,12 @@ func (cm *contractManager) DeployContract(ctx context.Context, req *core.Contrac
 		return nil, err
 	}
 
+	if req.API != nil {
+		if err := cm.validateDeployAPI(ctx, req.API); err != nil {
+			return nil, err
+		}
+	}
+
 	var op *core.Operation
 	err = cm.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
 		op, err = cm.writeDeployTransaction(ctx, req)
@@ -434,6 +444,35 @@ func (cm *contractManager) InvokeContract(ctx context.Context, req *core.Contrac
 	}
 }
 
+// ResolveInvokeContractRequest resolves the signing key and method of an invoke request, and validates its input,
+// without submitting it. This allows other managers to submit the invoke as part of their own transaction.
+func (cm *contractManager) ResolveInvokeContractRequest(ctx context.Context, req *core.ContractCallRequest) (err error) {
+	if req.Message != nil {
+		return i18n.NewError(ctx, coremsgs.MsgContractInvokeMessageNotSupported)
+	}
+	if req.Key, err = cm.identity.ResolveInputSigningKey(ctx, req.Key, identity.KeyNormalizationBlockchainPlugin); err != nil {
+		return err
+	}
+	if err = cm.resolveInvokeContractRequest(ctx, req); err != nil {
+		return err
+	}
+	return cm.validateInvokeContractRequest(ctx, req)
+}
+
+// NewInvokeOperation builds an operation (not yet persisted) that invokes a resolved request via the blockchain plugin,
+// so that updates from the plugin are routed back to the operation.
+func (cm *contractManager) NewInvokeOperation(txid *fftypes.UUID, opType core.OpType, req *core.ContractCallRequest) (*core.Operation, error) {
+	op := core.NewOperation(
+		cm.blockchain,
+		cm.namespace,
+		txid,
+		opType)
+	if err := addBlockchainReqInputs(op, req); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
 func (cm *contractManager) InvokeContractAPI(ctx context.Context, apiName, methodPath string, req *core.ContractCallRequest, waitConfirm bool) (interface{}, error) {
 	api, err := cm.database.GetContractAPIByName(ctx, cm.namespace, apiName)
 	if err != nil {
@@ -532,6 +571,56 @@ func (cm *contractManager) ResolveContractAPI(ctx context.Context, httpServerURL
 	return nil
 }
 
+func (cm *contractManager) validateDeployAPI(ctx context.Context, deployAPI *core.ContractDeployAPI) error {
+	if err := fftypes.ValidateFFNameField(ctx, deployAPI.Name, "api.name"); err != nil {
+		return err
+	}
+	if existing, err := cm.database.GetContractAPIByName(ctx, cm.namespace, deployAPI.Name); err != nil {
+		return err
+	} else if existing != nil {
+		return i18n.NewError(ctx, coremsgs.MsgNameExists)
+	}
+	return cm.ResolveFFIReference(ctx, deployAPI.Interface)
+}
+
+// createDeployedContractAPI records a contract API for a contract deployed by this node, using the
+// location reported by the blockchain connector on successful deployment. The deployment itself has
+// succeeded, so an API that cannot be created is recorded in the operation output rather than failing
+// the update - only database errors (which can be retried) are returned.
+func (cm *contractManager) createDeployedContractAPI(ctx context.Context, op *core.Operation, update *core.OperationUpdate, location *fftypes.JSONAny) error {
+	req, err := retrieveBlockchainDeployInputs(ctx, op)
+	if err != nil || req.API == nil {
+		return nil
+	}
+
+	api := &core.ContractAPI{
+		ID:        fftypes.NewUUID(),
+		Namespace: cm.namespace,
+		Name:      req.API.Name,
+		Interface: req.API.Interface,
+		Location:  location,
+	}
+	if err := cm.ResolveContractAPI(ctx, "", api); err != nil {
+		log.L(ctx).Errorf("Unable to create contract API '%s' for deployed contract: %s", api.Name, err)
+		update.Output["apiError"] = err.Error()
+		return nil
+	}
+	existing, err := cm.database.InsertOrGetContractAPI(ctx, api)
+	if err != nil {
+		return err
+	} else if existing != nil {
+		err = i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "contract API", api.Name, existing.ID)
+		log.L(ctx).Errorf("Unable to create contract API '%s' for deployed contract: %s", api.Name, err)
+		update.Output["apiError"] = err.Error()
+		return nil
+	}
+
+	log.L(ctx).Infof("Contract API created id=%s for deployed contract %s", api.ID, location)
+	update.Output["api"] = api.ID.String()
+	event := core.NewEvent(core.EventTypeContractAPIConfirmed, api.Namespace, api.ID, op.Transaction, core.SystemTopicDefinitions)
+	return cm.database.InsertEvent(ctx, event)
+}
+
 func (cm *contractManager) ResolveFFIReference(ctx context.Context, ref *fftypes.FFIReference) error {
 	switch {
 	case ref == nil:
@@ -727,6 +816,25 @@ func (cm *contractManager) checkContractListenerExists(ctx context.Context, list
 		database.ContractListenerQueryFactory.NewUpdate(ctx).Set("backendid", listener.BackendID))
 }
 
+func sameTopicFilters(a, b *core.ContractListenerOptions) bool {
+	var aTopics, bTopics []string
+	if a != nil {
+		aTopics = a.Topics
+	}
+	if b != nil {
+		bTopics = b.Topics
+	}
+	if len(aTopics) != len(bTopics) {
+		return false
+	}
+	for i := range aTopics {
+		if aTopics[i] != bTopics[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (cm *contractManager) AddContractListener(ctx context.Context, listener *core.ContractListenerInput) (output *core.ContractListener, err error) {
 	listener.ID = fftypes.NewUUID()
 	listener.Namespace = cm.namespace
@@ -751,6 +859,12 @@ func (cm *contractManager) AddContractListener(ctx context.Context, listener *co
 	} else if listener.Options.FirstEvent == "" {
 		listener.Options.FirstEvent = cm.getDefaultContractListenerOptions().FirstEvent
 	}
+	if listener.Options.Confirmations == nil && cm.confirmations > 0 {
+		confirmations := cm.confirmations
+		listener.Options.Confirmations = &confirmations
+	} else if listener.Options.Confirmations != nil && *listener.Options.Confirmations < 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidConfirmations, *listener.Options.Confirmations)
+	}
 
 	err = cm.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
 		// Namespace + Name must be unique
@@ -783,8 +897,13 @@ func (cm *contractManager) AddContractListener(ctx context.Context, listener *co
 			fb.Eq("signature", listener.Signature),
 		)); err != nil {
 			return err
-		} else if len(existing) > 0 {
-			return i18n.NewError(ctx, coremsgs.MsgContractListenerExists)
+		} else {
+			// Listeners on the same event with different raw topic filters match different events
+			for _, l := range existing {
+				if sameTopicFilters(l.Options, listener.Options) {
+					return i18n.NewError(ctx, coremsgs.MsgContractListenerExists)
+				}
+			}
 		}
 		return nil
 	})
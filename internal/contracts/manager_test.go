@@ -79,13 +79,13 @@ func newTestContractManager() *contractManager {
 			a[1].(func(context.Context) error)(a[0].(context.Context)),
 		}
 	}
-	cm, _ := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, msa)
+	cm, _ := NewContractManager(context.Background(), "ns1", 0, mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, msa)
 	cm.(*contractManager).txHelper = &txcommonmocks.Helper{}
 	return cm.(*contractManager)
 }
 
 func TestNewContractManagerFail(t *testing.T) {
-	_, err := NewContractManager(context.Background(), "", nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	_, err := NewContractManager(context.Background(), "", 0, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -109,7 +109,7 @@ func TestNewContractManagerFFISchemaLoaderFail(t *testing.T) {
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
 	msa := &syncasyncmocks.Bridge{}
 	mbi.On("GetFFIParamValidator", mock.Anything).Return(nil, fmt.Errorf("pop"))
-	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, msa)
+	_, err := NewContractManager(context.Background(), "ns1", 0, mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, msa)
 	assert.Regexp(t, "pop", err)
 }
 
@@ -130,7 +130,7 @@ func TestNewContractManagerFFISchemaLoader(t *testing.T) {
 	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.Anything).Return(nil, nil, nil)
 	mbi.On("GetFFIParamValidator", mock.Anything).Return(&ffi2abi.ParamValidator{}, nil)
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
-	_, err := NewContractManager(context.Background(), "ns1", mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, msa)
+	_, err := NewContractManager(context.Background(), "ns1", 0, mdi, mbi, mdm, mbm, mpm, mbp, mim, mom, txHelper, msa)
 	assert.NoError(t, err)
 }
 
@@ -703,6 +703,58 @@ func TestAddContractListenerInline(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestAddContractListenerDefaultConfirmations(t *testing.T) {
+	cm := newTestContractManager()
+	cm.confirmations = 12
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	sub := &core.ContractListenerInput{
+		ContractListener: core.ContractListener{
+			Event: &core.FFISerializedEvent{
+				FFIEventDefinition: fftypes.FFIEventDefinition{
+					Name: "changed",
+				},
+			},
+			Topic: "test-topic",
+		},
+	}
+
+	mbi.On("GenerateEventSignature", context.Background(), mock.Anything).Return("changed")
+	mdi.On("GetContractListeners", context.Background(), "ns1", mock.Anything).Return(nil, nil, nil)
+	mbi.On("AddContractListener", context.Background(), &sub.ContractListener).Return(nil)
+	mdi.On("InsertContractListener", context.Background(), &sub.ContractListener).Return(nil)
+
+	result, err := cm.AddContractListener(context.Background(), sub)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, *result.Options.Confirmations)
+
+	mbi.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestAddContractListenerBadConfirmations(t *testing.T) {
+	cm := newTestContractManager()
+	confirmations := -1
+
+	sub := &core.ContractListenerInput{
+		ContractListener: core.ContractListener{
+			Event: &core.FFISerializedEvent{
+				FFIEventDefinition: fftypes.FFIEventDefinition{
+					Name: "changed",
+				},
+			},
+			Options: &core.ContractListenerOptions{
+				Confirmations: &confirmations,
+			},
+			Topic: "test-topic",
+		},
+	}
+
+	_, err := cm.AddContractListener(context.Background(), sub)
+	assert.Regexp(t, "FF10457", err)
+}
+
 func TestAddContractListenerNoLocationOK(t *testing.T) {
 	cm := newTestContractManager()
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
@@ -1738,6 +1790,89 @@ func TestDeployContractSync(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestDeployContractWithAPI(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	mth := cm.txHelper.(*txcommonmocks.Helper)
+	mom := cm.operations.(*operationmocks.Manager)
+	ffiID := fftypes.NewUUID()
+	req := &core.ContractDeployRequest{
+		Definition: fftypes.JSONAnyPtr("[]"),
+		Contract:   fftypes.JSONAnyPtr("\"0x123456\""),
+		API: &core.ContractDeployAPI{
+			Name:      "myapi",
+			Interface: &fftypes.FFIReference{ID: ffiID},
+		},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdi.On("GetContractAPIByName", mock.Anything, "ns1", "myapi").Return(nil, nil)
+	mdi.On("GetFFIByID", mock.Anything, "ns1", ffiID).Return(&fftypes.FFI{}, nil)
+	mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeContractDeploy, core.IdempotencyKey("")).Return(fftypes.NewUUID(), nil)
+	mom.On("AddOrReuseOperation", mock.Anything, mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Input.GetObject("api").GetString("name") == "myapi"
+	})).Return(nil)
+	mom.On("RunOperation", mock.Anything, mock.Anything).Return(nil, nil)
+
+	_, err := cm.DeployContract(context.Background(), req, false)
+	assert.NoError(t, err)
+
+	mth.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestDeployContractWithAPIBadName(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	req := &core.ContractDeployRequest{
+		API: &core.ContractDeployAPI{
+			Name: "!bad",
+		},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+
+	_, err := cm.DeployContract(context.Background(), req, false)
+	assert.Regexp(t, "FF00140.*api.name", err)
+}
+
+func TestDeployContractWithAPINameExists(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	req := &core.ContractDeployRequest{
+		API: &core.ContractDeployAPI{
+			Name: "myapi",
+		},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdi.On("GetContractAPIByName", mock.Anything, "ns1", "myapi").Return(&core.ContractAPI{}, nil)
+
+	_, err := cm.DeployContract(context.Background(), req, false)
+	assert.Regexp(t, "FF10447", err)
+}
+
+func TestDeployContractWithAPIQueryFail(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mdi := cm.database.(*databasemocks.Plugin)
+	req := &core.ContractDeployRequest{
+		API: &core.ContractDeployAPI{
+			Name: "myapi",
+		},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mdi.On("GetContractAPIByName", mock.Anything, "ns1", "myapi").Return(nil, fmt.Errorf("pop"))
+
+	_, err := cm.DeployContract(context.Background(), req, false)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestDeployContractResolveInputSigningKeyFail(t *testing.T) {
 	cm := newTestContractManager()
 	mim := cm.identity.(*identitymanagermocks.Manager)
@@ -3489,3 +3624,102 @@ func TestDeleteContractAPIPublished(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func TestSameTopicFilters(t *testing.T) {
+	assert.True(t, sameTopicFilters(nil, &core.ContractListenerOptions{}))
+	assert.True(t, sameTopicFilters(&core.ContractListenerOptions{Topics: []string{"", "0x01"}}, &core.ContractListenerOptions{Topics: []string{"", "0x01"}}))
+	assert.False(t, sameTopicFilters(nil, &core.ContractListenerOptions{Topics: []string{"0x01"}}))
+	assert.False(t, sameTopicFilters(&core.ContractListenerOptions{Topics: []string{"0x02"}}, &core.ContractListenerOptions{Topics: []string{"0x01"}}))
+}
+
+func TestResolveInvokeContractRequest(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+
+	req := &core.ContractCallRequest{
+		Type:      core.CallTypeInvoke,
+		Interface: fftypes.NewUUID(),
+		Location:  fftypes.JSONAnyPtr(""),
+		Method: &fftypes.FFIMethod{
+			Name:    "doStuff",
+			ID:      fftypes.NewUUID(),
+			Params:  fftypes.FFIParams{},
+			Returns: fftypes.FFIParams{},
+		},
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, req.Method, req.Input, req.Errors, false).Return(nil)
+
+	err := cm.ResolveInvokeContractRequest(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, "key-resolved", req.Key)
+
+	mim.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestResolveInvokeContractRequestWithMessage(t *testing.T) {
+	cm := newTestContractManager()
+
+	req := &core.ContractCallRequest{
+		Type:    core.CallTypeInvoke,
+		Message: &core.MessageInOut{},
+	}
+
+	err := cm.ResolveInvokeContractRequest(context.Background(), req)
+	assert.Regexp(t, "FF10487", err)
+}
+
+func TestResolveInvokeContractRequestBadKey(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+
+	req := &core.ContractCallRequest{
+		Type: core.CallTypeInvoke,
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
+
+	err := cm.ResolveInvokeContractRequest(context.Background(), req)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestNewInvokeOperation(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("Name").Return("mockblockchain")
+
+	txid := fftypes.NewUUID()
+	req := &core.ContractCallRequest{
+		Type:     core.CallTypeInvoke,
+		Key:      "key",
+		Location: fftypes.JSONAnyPtr(`{"address":"0x1"}`),
+	}
+
+	op, err := cm.NewInvokeOperation(txid, core.OpTypeTokenSwapInvoke, req)
+	assert.NoError(t, err)
+	assert.Equal(t, "mockblockchain", op.Plugin)
+	assert.Equal(t, core.OpTypeTokenSwapInvoke, op.Type)
+	assert.Equal(t, txid, op.Transaction)
+	assert.Equal(t, "key", op.Input.GetString("key"))
+}
+
+func TestResolveInvokeContractRequestNoMethod(t *testing.T) {
+	cm := newTestContractManager()
+	mim := cm.identity.(*identitymanagermocks.Manager)
+
+	req := &core.ContractCallRequest{
+		Type: core.CallTypeInvoke,
+	}
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+
+	err := cm.ResolveInvokeContractRequest(context.Background(), req)
+	assert.Regexp(t, "FF10313", err)
+
+	mim.AssertExpectations(t)
+}
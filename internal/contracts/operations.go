@@ -154,6 +154,11 @@ func (cm *contractManager) OnOperationUpdate(ctx context.Context, op *core.Opera
 		}
 	case core.OpTypeBlockchainContractDeploy:
 		if update.Status == core.OpStatusSucceeded {
+			if location := update.Output.GetObject("contractLocation"); len(location) > 0 {
+				if err := cm.createDeployedContractAPI(ctx, op, update, fftypes.JSONAnyPtr(location.String())); err != nil {
+					return err
+				}
+			}
 			event := core.NewEvent(core.EventTypeBlockchainContractDeployOpSucceeded, op.Namespace, op.ID, op.Transaction, "")
 			if err := cm.database.InsertEvent(ctx, event); err != nil {
 				return err
@@ -598,6 +598,222 @@ func TestOperationUpdateDeploySucceed(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestOperationUpdateDeploySucceedCreateAPI(t *testing.T) {
+	cm := newTestContractManager()
+	ffiID := fftypes.NewUUID()
+	location := fftypes.JSONAnyPtr(`{"address":"0x123"}`)
+
+	op := &core.Operation{
+		ID:          fftypes.NewUUID(),
+		Type:        core.OpTypeBlockchainContractDeploy,
+		Transaction: fftypes.NewUUID(),
+		Input: fftypes.JSONObject{
+			"api": map[string]interface{}{
+				"name":      "myapi",
+				"interface": map[string]interface{}{"id": ffiID.String()},
+			},
+		},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{
+			"contractLocation": map[string]interface{}{"address": "0x123"},
+		},
+	}
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, location).Return(location, nil)
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "myapi").Return(nil, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", ffiID).Return(&fftypes.FFI{}, nil)
+	mdi.On("InsertOrGetContractAPI", context.Background(), mock.MatchedBy(func(api *core.ContractAPI) bool {
+		return api.Name == "myapi" && api.Location.String() == location.String() && api.Interface.ID.Equals(ffiID)
+	})).Return(nil, nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeContractAPIConfirmed && event.Transaction == op.Transaction
+	})).Return(nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded && *event.Reference == *op.ID
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, update.Output.GetString("api"))
+
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedNoAPI(t *testing.T) {
+	cm := newTestContractManager()
+
+	op := &core.Operation{
+		ID:    fftypes.NewUUID(),
+		Type:  core.OpTypeBlockchainContractDeploy,
+		Input: fftypes.JSONObject{},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{
+			"contractLocation": map[string]interface{}{"address": "0x123"},
+		},
+	}
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedAPIResolveFail(t *testing.T) {
+	cm := newTestContractManager()
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeBlockchainContractDeploy,
+		Input: fftypes.JSONObject{
+			"api": map[string]interface{}{
+				"name": "!bad",
+			},
+		},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{
+			"contractLocation": map[string]interface{}{"address": "0x123"},
+		},
+	}
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF00140", update.Output.GetString("apiError"))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedAPIInterfaceNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	ffiID := fftypes.NewUUID()
+	location := fftypes.JSONAnyPtr(`{"address":"0x123"}`)
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeBlockchainContractDeploy,
+		Input: fftypes.JSONObject{
+			"api": map[string]interface{}{
+				"name":      "myapi",
+				"interface": map[string]interface{}{"id": ffiID.String()},
+			},
+		},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{
+			"contractLocation": map[string]interface{}{"address": "0x123"},
+		},
+	}
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, location).Return(location, nil)
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "myapi").Return(nil, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", ffiID).Return(nil, nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF10303", update.Output.GetString("apiError"))
+
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedAPIConflict(t *testing.T) {
+	cm := newTestContractManager()
+	ffiID := fftypes.NewUUID()
+	location := fftypes.JSONAnyPtr(`{"address":"0x123"}`)
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeBlockchainContractDeploy,
+		Input: fftypes.JSONObject{
+			"api": map[string]interface{}{
+				"name":      "myapi",
+				"interface": map[string]interface{}{"id": ffiID.String()},
+			},
+		},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{
+			"contractLocation": map[string]interface{}{"address": "0x123"},
+		},
+	}
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, location).Return(location, nil)
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "myapi").Return(nil, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", ffiID).Return(&fftypes.FFI{}, nil)
+	mdi.On("InsertOrGetContractAPI", context.Background(), mock.Anything).Return(&core.ContractAPI{ID: fftypes.NewUUID()}, nil)
+	mdi.On("InsertEvent", context.Background(), mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeBlockchainContractDeployOpSucceeded
+	})).Return(nil)
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF10407", update.Output.GetString("apiError"))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestOperationUpdateDeploySucceedAPIInsertFail(t *testing.T) {
+	cm := newTestContractManager()
+	ffiID := fftypes.NewUUID()
+	location := fftypes.JSONAnyPtr(`{"address":"0x123"}`)
+
+	op := &core.Operation{
+		ID:   fftypes.NewUUID(),
+		Type: core.OpTypeBlockchainContractDeploy,
+		Input: fftypes.JSONObject{
+			"api": map[string]interface{}{
+				"name":      "myapi",
+				"interface": map[string]interface{}{"id": ffiID.String()},
+			},
+		},
+	}
+	update := &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: fftypes.JSONObject{
+			"contractLocation": map[string]interface{}{"address": "0x123"},
+		},
+	}
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeCall, location).Return(location, nil)
+	mdi.On("GetContractAPIByName", context.Background(), "ns1", "myapi").Return(nil, nil)
+	mdi.On("GetFFIByID", context.Background(), "ns1", ffiID).Return(&fftypes.FFI{}, nil)
+	mdi.On("InsertOrGetContractAPI", context.Background(), mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	err := cm.OnOperationUpdate(context.Background(), op, update)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
 func TestOperationUpdateDeployFail(t *testing.T) {
 	cm := newTestContractManager()
 
@@ -35,8 +35,66 @@ const (
 	NamespaceName = "name"
 	// NamespaceName is the long description for a pre-defined namespace
 	NamespaceDescription = "description"
+	// NamespaceTenant is the tenant a pre-defined namespace belongs to
+	NamespaceTenant = "tenant"
 	// NamespacePlugins is the list of namespace plugins
 	NamespacePlugins = "plugins"
+	// NamespaceBridges is the list of bridges that re-publish messages from a namespace into other namespaces
+	NamespaceBridges = "bridges"
+	// NamespaceBridgeName is the name of a bridge, which must be unique within its namespace
+	NamespaceBridgeName = "name"
+	// NamespaceBridgeTarget is the namespace a bridge publishes messages into
+	NamespaceBridgeTarget = "target"
+	// NamespaceBridgeFirstEvent is the first event a new bridge processes
+	NamespaceBridgeFirstEvent = "firstEvent"
+	// NamespaceBridgeFilter is the filter on the messages a bridge publishes
+	NamespaceBridgeFilter = "filter"
+	// NamespaceBridgeFilterTopics is a regular expression to match against the topics of a message
+	NamespaceBridgeFilterTopics = "topics"
+	// NamespaceBridgeFilterTag is a regular expression to match against the tag of a message
+	NamespaceBridgeFilterTag = "tag"
+	// NamespaceBridgeFilterAuthor is a regular expression to match against the author of a message
+	NamespaceBridgeFilterAuthor = "author"
+	// NamespaceBridgeFilterIncludePrivate includes private messages, as well as broadcasts
+	NamespaceBridgeFilterIncludePrivate = "includePrivate"
+	// NamespaceBridgeTopicMap is a list of topics to replace when publishing into the target namespace
+	NamespaceBridgeTopicMap = "topicMap"
+	// NamespaceBridgeIdentityMap is a list of authors to replace when publishing into the target namespace
+	NamespaceBridgeIdentityMap = "identityMap"
+	// NamespaceBridgeMapSource is the value in the source namespace
+	NamespaceBridgeMapSource = "source"
+	// NamespaceBridgeMapTarget is the value in the target namespace
+	NamespaceBridgeMapTarget = "target"
+	// NamespaceBridgeMembers is a list of identities to send messages to privately in the target namespace
+	NamespaceBridgeMembers = "members"
+	// NamespaceSLA is the section for the time within which the messages sent by this node are expected to be confirmed
+	NamespaceSLA = "sla"
+	// NamespaceSLAConfirmTime is the SLA for the messages on topics without their own SLA
+	NamespaceSLAConfirmTime = "confirmTime"
+	// NamespaceSLATopics is the list of topics with their own SLA
+	NamespaceSLATopics = "topics"
+	// NamespaceTransforms is the list of transforms that run on the data of the messages of a namespace
+	NamespaceTransforms = "transforms"
+	// NamespaceTransformPlugin is the type of transform plugin to run
+	NamespaceTransformPlugin = "plugin"
+	// NamespaceTransformTopics is the list of topics a transform runs on
+	NamespaceTransformTopics = "topics"
+	// NamespaceTransformOptions is the plugin-specific options of a transform
+	NamespaceTransformOptions = "options"
+	// NamespaceSLATopic is the topic an SLA applies to
+	NamespaceSLATopic = "topic"
+	// NamespaceDeferredConfirm is the section for holding received messages until the local application accepts or rejects them
+	NamespaceDeferredConfirm = "deferredConfirm"
+	// NamespaceDeferredConfirmEnabled enables deferred confirmation for the namespace
+	NamespaceDeferredConfirmEnabled = "enabled"
+	// NamespaceDeferredConfirmTopics is the list of topics that are deferred, or all topics if empty
+	NamespaceDeferredConfirmTopics = "topics"
+	// NamespaceBlobScanner is the section for the plugin that scans the content of blobs on the namespace
+	NamespaceBlobScanner = "blobScanner"
+	// NamespaceBlobScannerPlugin is the type of blob scanner plugin to run
+	NamespaceBlobScannerPlugin = "plugin"
+	// NamespaceBlobScannerOptions is the plugin-specific options of the blob scanner
+	NamespaceBlobScannerOptions = "options"
 	// NamespaceTLSConfigName is the user-supplied name for the TLS Config
 	NamespaceTLSConfigName = "name"
 	// NamespaceTLSConfigs is the list of tls configs
@@ -45,6 +103,8 @@ const (
 	NamespaceTLSConfigTLSSection = "tls"
 	// NamespaceDefaultKey is the default signing key for blockchain transactions within this namespace
 	NamespaceDefaultKey = "defaultKey"
+	// NamespaceConfirmations is the default number of block confirmations required for events delivered to this namespace
+	NamespaceConfirmations = "confirmations"
 	// NamespaceAssetKeyNormalization mechanism to normalize keys before using them. Valid options: "blockchain_plugin" - use blockchain plugin (default), "none" - do not attempt normalization
 	NamespaceAssetKeyNormalization = "asset.manager.keyNormalization"
 	// NamespaceMultiparty contains the multiparty configuration for a namespace
@@ -71,6 +131,8 @@ const (
 	NamespaceMultipartyContractLocation = "location"
 	// NamespaceMultipartyContractOptions is an object of additional blockchain-specific configuration
 	NamespaceMultipartyContractOptions = "options"
+	// NamespaceMultipartyContractChainID is the expected chain ID of the blockchain the contract is deployed to
+	NamespaceMultipartyContractChainID = "chainId"
 )
 
 // The following keys can be access from the root configuration.
@@ -80,6 +142,8 @@ var (
 	APIDefaultFilterLimit = ffc("api.defaultFilterLimit")
 	// APIMaxFilterLimit is the maximum limit that can be specified by an API call
 	APIMaxFilterLimit = ffc("api.maxFilterLimit")
+	// APIMaxStreamLimit is the maximum limit that can be specified by an API call that streams its results as NDJSON
+	APIMaxStreamLimit = ffc("api.maxStreamLimit")
 	// APIMaxFilterSkip is the maximum skip value that can be specified on the API
 	APIMaxFilterSkip = ffc("api.maxFilterLimit")
 	// APIRequestTimeout is the server side timeout for API calls (context timeout), to avoid the server continuing processing when the client gives up
@@ -90,6 +154,10 @@ var (
 	APIOASPanicOnMissingDescription = ffc("api.oas.panicOnMissingDescription")
 	// APIPassThroughHeaders is a list of HTTP request headers to pass through to requests made to dependency microservices
 	APIPassthroughHeaders = ffc("api.passthroughHeaders")
+	// BatchBackpressureMaxQueueDepth is the number of unsent messages on a topic, above which new sends on the topic are rejected
+	BatchBackpressureMaxQueueDepth = ffc("batch.backpressure.maxQueueDepth")
+	// BatchBackpressureMaxMessageAge is the age of the oldest unsent message on a topic, above which new sends on the topic are rejected
+	BatchBackpressureMaxMessageAge = ffc("batch.backpressure.maxMessageAge")
 	// BatchManagerReadPageSize is the size of each page of messages read from the database into memory when assembling batches
 	BatchManagerReadPageSize = ffc("batch.manager.readPageSize")
 	// BatchManagerReadPollTimeout is how long without any notifications of new messages to wait, before doing a page query
@@ -102,6 +170,8 @@ var (
 	BatchRetryInitDelay = ffc("batch.retry.initDelay")
 	// BatchRetryMaxDelay is the maximum delay between retry attempts
 	BatchRetryMaxDelay = ffc("batch.retry.maxDelay")
+	// BlobDeduplicationEnabled stores blobs with the same content once, referencing the existing payload from each data item
+	BlobDeduplicationEnabled = ffc("blobdeduplication.enabled")
 	// BlobReceiverRetryInitDelay is the initial retry delay
 	BlobReceiverRetryInitDelay = ffc("blobreceiver.retry.initialDelay")
 	// BlobReceiverRetryMaxDelay is the maximum retry delay
@@ -114,6 +184,8 @@ var (
 	BlobReceiverWorkerBatchTimeout = ffc("blobreceiver.worker.batchTimeout")
 	// BlobReceiverWorkerBatchMaxInserts
 	BlobReceiverWorkerBatchMaxInserts = ffc("blobreceiver.worker.batchMaxInserts")
+	// BlobUploadDirectory is the directory the chunks of resumable blob uploads are staged in, until the upload is complete
+	BlobUploadDirectory = ffc("blobupload.directory")
 
 	// BroadcastBatchAgentTimeout how long to keep around a batching agent for a sending identity before disposal
 	BroadcastBatchAgentTimeout = ffc("broadcast.batch.agentTimeout")
@@ -123,6 +195,18 @@ var (
 	BroadcastBatchPayloadLimit = ffc("broadcast.batch.payloadLimit")
 	// BroadcastBatchTimeout is the timeout to wait for a batch to fill, before sending
 	BroadcastBatchTimeout = ffc("broadcast.batch.timeout")
+	// BroadcastChunkingEnabled splits broadcast messages that are too large for a batch into chunk messages
+	BroadcastChunkingEnabled = ffc("broadcast.chunking.enabled")
+	// BridgeBatchSize is the number of events read from the source namespace of a bridge in each page
+	BridgeBatchSize = ffc("bridge.batchSize")
+	// BridgePollInterval is how often a bridge checks for new events when it has caught up with its source namespace
+	BridgePollInterval = ffc("bridge.pollInterval")
+	// BridgeRetryFactor is the backoff factor to use for retries of bridge operations
+	BridgeRetryFactor = ffc("bridge.retry.factor")
+	// BridgeRetryInitDelay is the initial delay to use for retries of bridge operations
+	BridgeRetryInitDelay = ffc("bridge.retry.initDelay")
+	// BridgeRetryMaxDelay is the maximum delay to use for retries of bridge operations
+	BridgeRetryMaxDelay = ffc("bridge.retry.maxDelay")
 
 	// ConfigAutoReload starts a filesystem listener against the config file, and if it changes analyzes the config file for changes that require individual namespaces to restart
 	ConfigAutoReload = ffc("config.autoReload")
@@ -165,6 +249,10 @@ var (
 	CacheTokenPoolTTL   = ffc("cache.tokenpool.ttl")
 	CacheTokenPoolLimit = ffc("cache.tokenpool.limit")
 
+	// Token fees cache config
+	CacheTokenFeesTTL   = ffc("cache.tokenfees.ttl")
+	CacheTokenFeesLimit = ffc("cache.tokenfees.limit")
+
 	// DataManager Validator cache config
 	CacheValidatorSize = ffc("cache.validator.size")
 	CacheValidatorTTL  = ffc("cache.validator.ttl")
@@ -197,12 +285,60 @@ var (
 	PrivateMessagingBatchPayloadLimit = ffc("privatemessaging.batch.payloadLimit")
 	// PrivateMessagingBatchTimeout is the timeout to wait for a batch to fill, before sending
 	PrivateMessagingBatchTimeout = ffc("privatemessaging.batch.timeout")
+	// PrivateMessagingDisclosureEnabled whether to generate salted hashes of the fields of the data in pinned private batches sent by this node, so they can be selectively disclosed
+	PrivateMessagingDisclosureEnabled = ffc("privatemessaging.disclosure.enabled")
+	// PrivateMessagingEncryptionEnabled whether to encrypt the data of private messages sent by this node
+	PrivateMessagingEncryptionEnabled = ffc("privatemessaging.encryption.enabled")
+	// PrivateMessagingEncryptionPrivateKey the private key used to decrypt the data of private messages received by this node
+	PrivateMessagingEncryptionPrivateKey = ffc("privatemessaging.encryption.privateKey")
+	// PrivateMessagingInboundAction what to do with private batches from an org that is over the inbound message rate - throttle or quarantine
+	PrivateMessagingInboundAction = ffc("privatemessaging.inbound.action")
+	// PrivateMessagingInboundBurst is the number of messages an org can send in a burst above the inbound message rate
+	PrivateMessagingInboundBurst = ffc("privatemessaging.inbound.burst")
+	// PrivateMessagingInboundDailyQuota is the total size of the private batches received from each org per UTC day
+	PrivateMessagingInboundDailyQuota = ffc("privatemessaging.inbound.dailyQuota")
+	// PrivateMessagingInboundMessagesPerSecond is the sustained rate of private messages received from each org
+	PrivateMessagingInboundMessagesPerSecond = ffc("privatemessaging.inbound.messagesPerSecond")
+	// PrivateMessagingReceiptsDelivery whether to send delivery receipts for private messages confirmed by this node
+	PrivateMessagingReceiptsDelivery = ffc("privatemessaging.receipts.delivery")
 	// PrivateMessagingRetryFactor the backoff factor to use for retry of database operations
 	PrivateMessagingRetryFactor = ffc("privatemessaging.retry.factor")
 	// PrivateMessagingRetryInitDelay the initial delay to use for retry of data base operations
 	PrivateMessagingRetryInitDelay = ffc("privatemessaging.retry.initDelay")
 	// PrivateMessagingRetryMaxDelay the maximum delay to use for retry of data base operations
 	PrivateMessagingRetryMaxDelay = ffc("privatemessaging.retry.maxDelay")
+	// RBACEnabled enforces the roles bound to principals on the namespaced routes of the API
+	RBACEnabled = ffc("rbac.enabled")
+	// RBACPrincipalHeader is a header set by a trusted authenticating proxy to the principal of each request
+	RBACPrincipalHeader = ffc("rbac.principalHeader")
+	// AuditEnabled records the mutating calls to the API and the gRPC API in the audit log of each namespace
+	AuditEnabled = ffc("audit.enabled")
+	// AuditExportFile is a file each audit log entry is also appended to, as a line of JSON
+	AuditExportFile = ffc("audit.exportFile")
+	// RateLimitEnabled enforces the rate limits and daily quotas on the API server
+	RateLimitEnabled = ffc("ratelimit.enabled")
+	// RateLimitPrincipalRequestsPerSecond is the sustained rate of requests allowed for each principal
+	RateLimitPrincipalRequestsPerSecond = ffc("ratelimit.principal.requestsPerSecond")
+	// RateLimitPrincipalBurst is the number of requests a principal can make in a burst above the sustained rate
+	RateLimitPrincipalBurst = ffc("ratelimit.principal.burst")
+	// RateLimitPrincipalDailyQuota is the number of requests allowed for each principal per UTC day
+	RateLimitPrincipalDailyQuota = ffc("ratelimit.principal.dailyQuota")
+	// RateLimitNamespaceRequestsPerSecond is the sustained rate of requests allowed for each namespace
+	RateLimitNamespaceRequestsPerSecond = ffc("ratelimit.namespace.requestsPerSecond")
+	// RateLimitNamespaceBurst is the number of requests a namespace can take in a burst above the sustained rate
+	RateLimitNamespaceBurst = ffc("ratelimit.namespace.burst")
+	// RateLimitNamespaceDailyQuota is the number of requests allowed for each namespace per UTC day
+	RateLimitNamespaceDailyQuota = ffc("ratelimit.namespace.dailyQuota")
+	// ConnLimitEnabled enforces the limits on concurrent HTTP requests and WebSocket connections on the API and SPI servers
+	ConnLimitEnabled = ffc("connlimit.enabled")
+	// ConnLimitListenerMaxRequests is the maximum concurrent HTTP requests on each listener
+	ConnLimitListenerMaxRequests = ffc("connlimit.listener.maxRequests")
+	// ConnLimitListenerMaxWebSockets is the maximum concurrent WebSocket connections on each listener
+	ConnLimitListenerMaxWebSockets = ffc("connlimit.listener.maxWebSockets")
+	// ConnLimitPrincipalMaxRequests is the maximum concurrent HTTP requests for each principal
+	ConnLimitPrincipalMaxRequests = ffc("connlimit.principal.maxRequests")
+	// ConnLimitPrincipalMaxWebSockets is the maximum concurrent WebSocket connections for each principal
+	ConnLimitPrincipalMaxWebSockets = ffc("connlimit.principal.maxWebSockets")
 	// DatabaseType the type of the database interface plugin to use
 	HistogramsMaxChartRows = ffc("histograms.maxChartRows")
 	// TokensList is the root key containing a list of supported token connectors
@@ -249,6 +385,8 @@ var (
 	EventAggregatorRetryInitDelay = ffc("event.aggregator.retry.initDelay")
 	// EventAggregatorRetryMaxDelay the maximum delay to use for retry of data base operations
 	EventAggregatorRetryMaxDelay = ffc("event.aggregator.retry.maxDelay")
+	// EventAggregatorStrictTopics the topics on which messages are confirmed in the order of their topic sequence, across all senders
+	EventAggregatorStrictTopics = ffc("event.aggregator.strictTopics")
 	// EventDispatcherPollTimeout the time to wait without a notification of new events, before trying a select on the table
 	EventDispatcherPollTimeout = ffc("event.dispatcher.pollTimeout")
 	// EventDispatcherBufferLength the number of events + attachments an individual dispatcher should hold in memory ready for delivery to the subscription
@@ -275,6 +413,12 @@ var (
 	SPIWebSocketReadBufferSize = ffc("spi.ws.readBufferSize")
 	// SPIWebSocketWriteBufferSize is the WebSocket write buffer size for the admin change-event WebSocket
 	SPIWebSocketWriteBufferSize = ffc("spi.ws.writeBufferSize")
+	// JobsWorkers is the number of jobs each namespace performs at once
+	JobsWorkers = ffc("jobs.workers")
+	// JobsOutputDirectory is the directory the output files of jobs, such as exports, are written to
+	JobsOutputDirectory = ffc("jobs.outputDirectory")
+	// MessageExpiryMaskConfirmed hides confirmed messages that are past their expiry time from message queries, unless they are requested
+	MessageExpiryMaskConfirmed = ffc("message.expiry.maskConfirmed")
 	// MessageWriterCount
 	MessageWriterCount = ffc("message.writer.count")
 	// MessageWriterBatchTimeout
@@ -285,6 +429,10 @@ var (
 	MetricsEnabled = ffc("metrics.enabled")
 	// MetricsPath determines what path to serve the Prometheus metrics from
 	MetricsPath = ffc("metrics.path")
+	// MetricsSlowRequestsSize is the number of the slowest recent API requests kept for the admin API
+	MetricsSlowRequestsSize = ffc("metrics.slowRequests.size")
+	// MetricsSlowRequestsWindow is how long a request is kept as one of the slowest recent requests
+	MetricsSlowRequestsWindow = ffc("metrics.slowRequests.window")
 	// NamespacesDefault is the default namespace - must be in the predefines list
 	NamespacesDefault = ffc("namespaces.default")
 	// NamespacesPredefined is a list of namespaces to ensure exists, without requiring a broadcast from the network
@@ -299,6 +447,8 @@ var (
 	NodeName = ffc("node.name")
 	// NodeDescription is a description for the node
 	NodeDescription = ffc("node.description")
+	// OpUpdateConfirmationsPollInterval is how often to check the chain head for operations waiting for confirmations
+	OpUpdateConfirmationsPollInterval = ffc("opupdate.confirmations.pollInterval")
 	// OpUpdateRetryInitDelay is the initial retry delay
 	OpUpdateRetryInitDelay = ffc("opupdate.retry.initialDelay")
 	// OpUpdatedRetryMaxDelay is the maximum retry delay
@@ -323,6 +473,8 @@ var (
 	OrchestratorStartupAttempts = ffc("orchestrator.startupAttempts")
 	// SubscriptionDefaultsReadAhead default read ahead to enable for subscriptions that do not explicitly configure readahead
 	SubscriptionDefaultsReadAhead = ffc("subscription.defaults.batchSize")
+	// SubscriptionDefaultsBatchTimeout is the default time to wait for a batch to fill, for subscriptions that deliver events in batches
+	SubscriptionDefaultsBatchTimeout = ffc("subscription.defaults.batchTimeout")
 	// SubscriptionMax maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)
 	SubscriptionMax = ffc("subscription.max")
 	// SubscriptionsRetryInitialDelay is the initial retry delay
@@ -334,6 +486,66 @@ var (
 
 	// AssetManagerKeyNormalization mechanism to normalize keys before using them. Valid options: "blockchain_plugin" - use blockchain plugin (default), "none" - do not attempt normalization
 	AssetManagerKeyNormalization = ffc("asset.manager.keyNormalization")
+	// AssetApprovalExpiryCheckInterval how often to check for token approvals that have reached their expiry
+	AssetApprovalExpiryCheckInterval = ffc("asset.approvalExpiry.checkInterval")
+	// AssetApprovalExpiryBatchSize the maximum number of expired approvals to revoke on each check
+	AssetApprovalExpiryBatchSize = ffc("asset.approvalExpiry.batchSize")
+	// AssetMetadataEnabled enables fetching, validating and caching the metadata from the URIs of non-fungible tokens
+	AssetMetadataEnabled = ffc("asset.metadata.enabled")
+	// AssetMetadataIPFSGateway the IPFS gateway used to dereference ipfs:// token URIs
+	AssetMetadataIPFSGateway = ffc("asset.metadata.ipfsGateway")
+	// AssetMetadataAllowedHosts if set, the only hosts token URIs are fetched from
+	AssetMetadataAllowedHosts = ffc("asset.metadata.allowedHosts")
+	// AssetMetadataMaxRedirects the maximum number of redirects followed when fetching a token URI
+	AssetMetadataMaxRedirects = ffc("asset.metadata.maxRedirects")
+	// AssetMetadataRequestTimeout the timeout for each request to fetch token metadata
+	AssetMetadataRequestTimeout = ffc("asset.metadata.requestTimeout")
+	// AssetMetadataMaxSize the maximum size of a token metadata document
+	AssetMetadataMaxSize = ffc("asset.metadata.maxSize")
+	// AssetMetadataRetryInterval the minimum time before a failed fetch of token metadata is retried
+	AssetMetadataRetryInterval = ffc("asset.metadata.retryInterval")
+	// AssetMetadataWorkers the number of workers fetching token metadata in parallel
+	AssetMetadataWorkers = ffc("asset.metadata.workers")
+	// AssetMetadataQueueLength the number of token URIs that can be queued for fetching, before further URIs are skipped until a later query
+	AssetMetadataQueueLength = ffc("asset.metadata.queueLength")
+	// AssetMetadataMediaEnabled enables the API route that serves cached copies of the images referenced by token metadata
+	AssetMetadataMediaEnabled = ffc("asset.metadata.media.enabled")
+	// AssetMetadataMediaMaxSize the maximum size of an image referenced by token metadata
+	AssetMetadataMediaMaxSize = ffc("asset.metadata.media.maxSize")
+	// AssetMetadataMediaContentTypes the content types of images that will be cached and served
+	AssetMetadataMediaContentTypes = ffc("asset.metadata.media.contentTypes")
+	// AssetMetadataMediaStore where cached images are stored - "database", or "sharedstorage" to use the shared storage plugin of the namespace
+	AssetMetadataMediaStore = ffc("asset.metadata.media.store")
+	// AssetSwapDefaultTimeout the time the legs of a token swap are held in escrow, if no timeout is specified on the swap
+	AssetSwapDefaultTimeout = ffc("asset.swap.defaultTimeout")
+	// AssetSwapCheckInterval how often to check the operations of in-flight token swaps
+	AssetSwapCheckInterval = ffc("asset.swap.checkInterval")
+	// AssetSwapBatchSize the maximum number of in-flight token swaps to process on each check
+	AssetSwapBatchSize = ffc("asset.swap.batchSize")
+	// AssetReconciliationInterval how often to reconcile the balances of every token pool with the chain, or 0 to only reconcile on demand
+	AssetReconciliationInterval = ffc("asset.reconciliation.interval")
+	// AssetReconciliationBatchSize the number of balances to read from the database at a time when reconciling a token pool
+	AssetReconciliationBatchSize = ffc("asset.reconciliation.batchSize")
+	// AssetSignoffThreshold the amount in whole tokens above which token transfers and mints are held for sign-off, or empty to disable sign-off
+	AssetSignoffThreshold = ffc("asset.signoff.threshold")
+	// AssetSignoffApprovers the authenticated principals that can sign off token transfers and mints above the threshold
+	AssetSignoffApprovers = ffc("asset.signoff.approvers")
+	// AssetSignoffRequired the number of approvers that must sign off a token transfer or mint before it is submitted
+	AssetSignoffRequired = ffc("asset.signoff.required")
+	// AssetBulkMintBatchSize the number of mints submitted in each batch of a bulk mint, if no batch size is specified on the request
+	AssetBulkMintBatchSize = ffc("asset.bulkMint.batchSize")
+	// AssetBulkMintMaxBatchSize the maximum batch size that can be requested for a bulk mint
+	AssetBulkMintMaxBatchSize = ffc("asset.bulkMint.maxBatchSize")
+	// AssetBulkMintMaxItems the maximum number of tokens in a single bulk mint
+	AssetBulkMintMaxItems = ffc("asset.bulkMint.maxItems")
+	// AssetBulkMintBatchInterval the interval between the batches of each bulk mint
+	AssetBulkMintBatchInterval = ffc("asset.bulkMint.batchInterval")
+	// AssetBulkMintMaxInFlight the maximum number of bulk mints that are progressed on each batch interval
+	AssetBulkMintMaxInFlight = ffc("asset.bulkMint.maxInFlight")
+	// AssetCircuitBreakerFailureThreshold the number of consecutive failed calls to a token connector before requests to it are failed fast, or 0 to disable
+	AssetCircuitBreakerFailureThreshold = ffc("asset.circuitBreaker.failureThreshold")
+	// AssetCircuitBreakerProbeInterval how long requests to a token connector are failed fast, before a single request is let through to probe for recovery
+	AssetCircuitBreakerProbeInterval = ffc("asset.circuitBreaker.probeInterval")
 	// UIEnabled set to false to disable the UI (default is true, so UI will be enabled if ui.path is valid)
 	UIEnabled = ffc("ui.enabled")
 	// UIPath the path on which to serve the UI
@@ -346,12 +558,42 @@ func setDefaults() {
 	viper.SetDefault(string(APIRequestTimeout), "120s")
 	viper.SetDefault(string(APIRequestMaxTimeout), "10m")
 	viper.SetDefault(string(APIMaxFilterLimit), 250)
+	viper.SetDefault(string(APIMaxStreamLimit), 1000000)
 	viper.SetDefault(string(APIMaxFilterSkip), 1000) // protects database (skip+limit pagination is not for bulk operations)
 	viper.SetDefault(string(APIRequestTimeout), "120s")
 	viper.SetDefault(string(APIPassthroughHeaders), []string{})
 	viper.SetDefault(string(AssetManagerKeyNormalization), "blockchain_plugin")
+	viper.SetDefault(string(AssetApprovalExpiryCheckInterval), "1m")
+	viper.SetDefault(string(AssetApprovalExpiryBatchSize), 50)
+	viper.SetDefault(string(AssetMetadataEnabled), false)
+	viper.SetDefault(string(AssetMetadataIPFSGateway), "https://ipfs.io")
+	viper.SetDefault(string(AssetMetadataMaxRedirects), 3)
+	viper.SetDefault(string(AssetMetadataRequestTimeout), "30s")
+	viper.SetDefault(string(AssetMetadataMaxSize), "1Mb")
+	viper.SetDefault(string(AssetMetadataRetryInterval), "1h")
+	viper.SetDefault(string(AssetMetadataWorkers), 2)
+	viper.SetDefault(string(AssetMetadataQueueLength), 100)
+	viper.SetDefault(string(AssetMetadataMediaEnabled), false)
+	viper.SetDefault(string(AssetMetadataMediaMaxSize), "10Mb")
+	viper.SetDefault(string(AssetMetadataMediaContentTypes), []string{"image/png", "image/jpeg", "image/gif", "image/webp"})
+	viper.SetDefault(string(AssetMetadataMediaStore), "database")
+	viper.SetDefault(string(AssetSwapDefaultTimeout), "24h")
+	viper.SetDefault(string(AssetSwapCheckInterval), "5s")
+	viper.SetDefault(string(AssetSwapBatchSize), 50)
+	viper.SetDefault(string(AssetReconciliationInterval), "0")
+	viper.SetDefault(string(AssetReconciliationBatchSize), 100)
+	viper.SetDefault(string(AssetSignoffRequired), 1)
+	viper.SetDefault(string(AssetBulkMintBatchSize), 50)
+	viper.SetDefault(string(AssetBulkMintMaxBatchSize), 500)
+	viper.SetDefault(string(AssetBulkMintMaxItems), 10000)
+	viper.SetDefault(string(AssetBulkMintBatchInterval), "5s")
+	viper.SetDefault(string(AssetBulkMintMaxInFlight), 10)
+	viper.SetDefault(string(AssetCircuitBreakerFailureThreshold), 5)
+	viper.SetDefault(string(AssetCircuitBreakerProbeInterval), "30s")
 	viper.SetDefault(string(CacheBatchLimit), 100)
 	viper.SetDefault(string(CacheBatchTTL), "5m")
+	viper.SetDefault(string(BatchBackpressureMaxQueueDepth), 0)
+	viper.SetDefault(string(BatchBackpressureMaxMessageAge), "0s")
 	viper.SetDefault(string(BatchManagerReadPageSize), 100)
 	viper.SetDefault(string(BatchManagerReadPollTimeout), "30s")
 	viper.SetDefault(string(BatchManagerMinimumPollDelay), "100ms")
@@ -361,6 +603,7 @@ func setDefaults() {
 	viper.SetDefault(string(BatchRetryInitDelay), "250ms")
 	viper.SetDefault(string(BatchRetryMaxDelay), "30s")
 	viper.SetDefault(string(BatchRetryMaxDelay), "30s")
+	viper.SetDefault(string(BlobDeduplicationEnabled), false)
 	viper.SetDefault(string(BlobReceiverRetryInitDelay), "250ms")
 	viper.SetDefault(string(BlobReceiverRetryMaxDelay), "1m")
 	viper.SetDefault(string(BlobReceiverRetryFactor), 2.0)
@@ -373,6 +616,12 @@ func setDefaults() {
 	viper.SetDefault(string(BroadcastBatchSize), 200)
 	viper.SetDefault(string(BroadcastBatchPayloadLimit), "800Kb")
 	viper.SetDefault(string(BroadcastBatchTimeout), "1s")
+	viper.SetDefault(string(BroadcastChunkingEnabled), true)
+	viper.SetDefault(string(BridgeBatchSize), 50)
+	viper.SetDefault(string(BridgePollInterval), "1s")
+	viper.SetDefault(string(BridgeRetryFactor), 2.0)
+	viper.SetDefault(string(BridgeRetryInitDelay), "250ms")
+	viper.SetDefault(string(BridgeRetryMaxDelay), "30s")
 	viper.SetDefault(string(CacheBlockchainLimit), 100)
 	viper.SetDefault(string(CacheBlockchainTTL), "5m")
 	viper.SetDefault(string(CacheAddressResolverLimit), 1000)
@@ -398,6 +647,7 @@ func setDefaults() {
 	viper.SetDefault(string(EventAggregatorRetryFactor), 2.0)
 	viper.SetDefault(string(EventAggregatorRetryInitDelay), "100ms")
 	viper.SetDefault(string(EventAggregatorRetryMaxDelay), "30s")
+	viper.SetDefault(string(EventAggregatorStrictTopics), []string{})
 	viper.SetDefault(string(EventDBEventsBufferSize), 100)
 	viper.SetDefault(string(EventDispatcherBufferLength), 5)
 	viper.SetDefault(string(EventDispatcherBatchTimeout), "250ms")
@@ -415,6 +665,8 @@ func setDefaults() {
 	viper.SetDefault(string(SPIWebSocketEventQueueLength), 250)
 	viper.SetDefault(string(CacheMessageSize), "50Mb")
 	viper.SetDefault(string(CacheMessageTTL), "5m")
+	viper.SetDefault(string(JobsWorkers), 2)
+	viper.SetDefault(string(MessageExpiryMaskConfirmed), false)
 	viper.SetDefault(string(MessageWriterBatchMaxInserts), 200)
 	viper.SetDefault(string(MessageWriterBatchTimeout), "10ms")
 	viper.SetDefault(string(MessageWriterCount), 5)
@@ -423,6 +675,7 @@ func setDefaults() {
 	viper.SetDefault(string(NamespacesRetryMaxDelay), "1m")
 	viper.SetDefault(string(NamespacesRetryInitDelay), "5s")
 	viper.SetDefault(string(OrchestratorStartupAttempts), 5)
+	viper.SetDefault(string(OpUpdateConfirmationsPollInterval), "5s")
 	viper.SetDefault(string(OpUpdateRetryInitDelay), "250ms")
 	viper.SetDefault(string(OpUpdateRetryMaxDelay), "1m")
 	viper.SetDefault(string(OpUpdateRetryFactor), 2.0)
@@ -436,8 +689,32 @@ func setDefaults() {
 	viper.SetDefault(string(PrivateMessagingBatchAgentTimeout), "2m")
 	viper.SetDefault(string(PrivateMessagingBatchSize), 200)
 	viper.SetDefault(string(PrivateMessagingBatchTimeout), "1s")
+	viper.SetDefault(string(PrivateMessagingReceiptsDelivery), false)
+	viper.SetDefault(string(PrivateMessagingEncryptionEnabled), false)
+	viper.SetDefault(string(PrivateMessagingDisclosureEnabled), false)
+	viper.SetDefault(string(PrivateMessagingInboundAction), "throttle")
+	viper.SetDefault(string(PrivateMessagingInboundBurst), 0)
+	viper.SetDefault(string(PrivateMessagingInboundDailyQuota), "0")
+	viper.SetDefault(string(PrivateMessagingInboundMessagesPerSecond), 0)
 	viper.SetDefault(string(PrivateMessagingBatchPayloadLimit), "800Kb")
+	viper.SetDefault(string(RBACEnabled), false)
+	viper.SetDefault(string(AuditEnabled), false)
+	viper.SetDefault(string(RateLimitEnabled), false)
+	viper.SetDefault(string(RateLimitPrincipalRequestsPerSecond), 0)
+	viper.SetDefault(string(RateLimitPrincipalBurst), 0)
+	viper.SetDefault(string(RateLimitPrincipalDailyQuota), 0)
+	viper.SetDefault(string(RateLimitNamespaceRequestsPerSecond), 0)
+	viper.SetDefault(string(RateLimitNamespaceBurst), 0)
+	viper.SetDefault(string(RateLimitNamespaceDailyQuota), 0)
+	viper.SetDefault(string(ConnLimitEnabled), false)
+	viper.SetDefault(string(ConnLimitListenerMaxRequests), 0)
+	viper.SetDefault(string(ConnLimitListenerMaxWebSockets), 0)
+	viper.SetDefault(string(ConnLimitPrincipalMaxRequests), 0)
+	viper.SetDefault(string(ConnLimitPrincipalMaxWebSockets), 0)
+	viper.SetDefault(string(MetricsSlowRequestsSize), 20)
+	viper.SetDefault(string(MetricsSlowRequestsWindow), "10m")
 	viper.SetDefault(string(SubscriptionDefaultsReadAhead), 0)
+	viper.SetDefault(string(SubscriptionDefaultsBatchTimeout), "250ms")
 	viper.SetDefault(string(SubscriptionMax), 500)
 	viper.SetDefault(string(SubscriptionsRetryInitialDelay), "250ms")
 	viper.SetDefault(string(SubscriptionsRetryMaxDelay), "30s")
@@ -451,6 +728,8 @@ func setDefaults() {
 	viper.SetDefault(string(CacheIdentityTTL), "1h")
 	viper.SetDefault(string(CacheTokenPoolLimit), 100)
 	viper.SetDefault(string(CacheTokenPoolTTL), "1h")
+	viper.SetDefault(string(CacheTokenFeesLimit), 1000)
+	viper.SetDefault(string(CacheTokenFeesTTL), "5m")
 }
 
 func Reset() {
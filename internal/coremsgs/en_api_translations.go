@@ -25,9 +25,20 @@ var (
 	APIParamsOperationIDGet                 = ffm("api.params.operationID.get", "The operation ID key to get")
 	APIParamsOperationNamespacedID          = ffm("api.params.spiOperationID", "The operation ID as passed to the connector when the operation was performed, including the 'namespace:' prefix")
 	APIParamsNamespace                      = ffm("api.params.namespace", "The namespace which scopes this request")
+	APIParamsTenant                         = ffm("api.params.tenant", "The tenant which owns the namespace")
 	APIParamsContractListenerNameOrID       = ffm("api.params.contractListenerNameOrID", "The contract listener name or ID")
 	APIParamsContractListenerID             = ffm("api.params.contractListenerID", "The contract listener ID")
 	APIParamsSubscriptionID                 = ffm("api.params.subscriptionID", "The subscription ID")
+	APIParamsDeadLetterID                   = ffm("api.params.deadLetterID", "The dead letter ID")
+	APIParamsQuarantinedBatchID             = ffm("api.params.quarantinedBatchID", "The quarantined batch ID")
+	APIParamsQuarantinedBlobID              = ffm("api.params.quarantinedBlobID", "The quarantined blob ID")
+	APIParamsSLABreachID                    = ffm("api.params.slaBreachID", "The SLA breach ID")
+	APIParamsRoleBindingID                  = ffm("api.params.roleBindingID", "The role binding ID")
+	APIParamsJobID                          = ffm("api.params.jobID", "The job ID")
+	APIParamsFields                         = ffm("api.params.fields", "Comma separated list of the JSON fields to return, such as header.id,state. Nested fields use dot notation")
+	APIParamsQuery                          = ffm("api.params.q", "Query of the collection, such as type=broadcast AND (topic=t1 OR topic=t2) ORDER BY sequence DESC. Combined with AND with the other filters")
+	APIParamsSubscriptionTemplateNameOrID   = ffm("api.params.subscriptionTemplateNameOrID", "The subscription template name or ID")
+	APIParamsEventRuleNameOrID              = ffm("api.params.eventRuleNameOrID", "The event rule name or ID")
 	APIParamsBatchID                        = ffm("api.params.batchId", "The batch ID")
 	APIParamsBlockchainEventID              = ffm("api.params.blockchainEventID", "The blockchain event ID")
 	APIParamsCollectionID                   = ffm("api.params.collectionID", "The collection ID")
@@ -49,11 +60,23 @@ var (
 	APIParamsFetchVerifiers                 = ffm("api.params.fetchVerifiers", "When set, the API will return the verifier for this identity")
 	APIParamsIdentityID                     = ffm("api.params.identityID", "The identity ID, which is a UUID generated by FireFly")
 	APIParamsMessageID                      = ffm("api.params.messageID", "The message ID")
+	APIParamsBatchPolicyTopic               = ffm("api.params.batchPolicyTopic", "The topic of the batch policy")
 	APIParamsDID                            = ffm("api.params.DID", "The identity DID")
 	APIParamsNodeNameOrID                   = ffm("api.params.nodeNameOrID", "The name or ID of the node")
 	APIParamsOrgNameOrID                    = ffm("api.params.orgNameOrID", "The name or ID of the org")
 	APIParamsTokenAccountKey                = ffm("api.params.tokenAccountKey", "The key for the token account. The exact format may vary based on the token connector use")
 	APIParamsTokenPoolNameOrID              = ffm("api.params.tokenPoolNameOrID", "The token pool name or ID")
+	APIParamsTokenSnapshotID                = ffm("api.params.tokenSnapshotID", "The token snapshot ID")
+	APIParamsTokenBulkMintID                = ffm("api.params.tokenBulkMintID", "The bulk mint ID")
+	APIParamsTokenExportFormat              = ffm("api.params.tokenExportFormat", "The file format to export - csv (default) or parquet")
+	APIParamsTokenExportPool                = ffm("api.params.tokenExportPool", "The name or ID of a token pool, to only export records in that pool")
+	APIParamsTokenExportAccount             = ffm("api.params.tokenExportAccount", "A token account key, to only export records for that account")
+	APIParamsTokenExportColumns             = ffm("api.params.tokenExportColumns", "Comma-separated list of the columns to export, in order. Defaults to all columns")
+	APIParamsTokenExportStartTime           = ffm("api.params.tokenExportStartTime", "Only export records created at or after this time")
+	APIParamsTokenExportEndTime             = ffm("api.params.tokenExportEndTime", "Only export records created before this time")
+	APIParamsTokenMediaURI                  = ffm("api.params.tokenMediaURI", "The URI of the token metadata that references the image, as returned in the 'uri' field of the token metadata")
+	APIParamsTokenSwapID                    = ffm("api.params.tokenSwapID", "The token swap ID")
+	APIParamsTokenTransferRequestID         = ffm("api.params.tokenTransferRequestID", "The ID of the token transfer request")
 	APIParamsTokenTransferFromOrTo          = ffm("api.params.tokenTransferFromOrTo", "The sending or receiving token account for a token transfer")
 	APIParamsTokenTransferID                = ffm("api.params.tokenTransferID", "The token transfer ID")
 	APIParamsTransactionID                  = ffm("api.params.transactionID", "The transaction ID")
@@ -66,23 +89,51 @@ var (
 	APIParamsAutometa                       = ffm("api.params.autometa", "When set, FireFly will automatically generate JSON metadata with the upload details")
 	APIParamsContractAPIID                  = ffm("api.params.contractAPIID", "The ID of the contract API")
 	APIParamsFetchStatus                    = ffm("api.params.fetchStatus", "When set, the API will return additional status information if available")
+	APIParamsUploadID                       = ffm("api.params.uploadID", "The ID of the chunked blob upload")
+	APIParamsUploadOffset                   = ffm("api.params.uploadOffset", "The offset in bytes of the chunk in the blob, which must be the current offset of the upload")
+	APIParamsUploadChunkHash                = ffm("api.params.uploadChunkHash", "The SHA-256 hash of the chunk. If set, the chunk is rejected if its hash does not match")
 
-	APIEndpointsAdminGetNamespaceByName = ffm("api.endpoints.adminGetNamespaceByName", "Gets a namespace by name")
-	APIEndpointsAdminGetNamespaces      = ffm("api.endpoints.adminGetNamespaces", "List namespaces")
-	APIEndpointsAdminGetOpByID          = ffm("api.endpoints.adminGetOpByID", "Gets an operation by ID")
-	APIEndpointsAdminGetOps             = ffm("api.endpoints.adminGetOps", "Lists operations")
-	APIEndpointsAdminPostReset          = ffm("api.endpoints.adminPostResetConfig", "Restarts FireFly Core HTTP servers and apply all configuration updates")
-	APIEndpointsAdminPatchOpByID        = ffm("api.endpoints.adminPatchOpByID", "Updates an operation by ID")
-	APIEndpointsAdminGetListenerByID    = ffm("api.endpoints.adminGetListenerByID", "Gets a contract listener by ID")
-	APIEndpointsAdminGetListeners       = ffm("api.endpoints.adminGetListeners", "Lists contract listeners")
+	APIEndpointsAdminGetNamespaceByName         = ffm("api.endpoints.adminGetNamespaceByName", "Gets a namespace by name")
+	APIEndpointsAdminGetNamespaces              = ffm("api.endpoints.adminGetNamespaces", "List namespaces")
+	APIEndpointsAdminGetOpByID                  = ffm("api.endpoints.adminGetOpByID", "Gets an operation by ID")
+	APIEndpointsAdminGetOps                     = ffm("api.endpoints.adminGetOps", "Lists operations")
+	APIEndpointsAdminPostReset                  = ffm("api.endpoints.adminPostResetConfig", "Restarts FireFly Core HTTP servers and apply all configuration updates")
+	APIEndpointsAdminPatchOpByID                = ffm("api.endpoints.adminPatchOpByID", "Updates an operation by ID")
+	APIEndpointsAdminGetListenerByID            = ffm("api.endpoints.adminGetListenerByID", "Gets a contract listener by ID")
+	APIEndpointsAdminGetListeners               = ffm("api.endpoints.adminGetListeners", "Lists contract listeners")
+	APIEndpointsAdminGetDeadLetters             = ffm("api.endpoints.adminGetDeadLetters", "Lists the dead letters of events that could not be delivered to a subscription")
+	APIEndpointsAdminGetDeadLetterByID          = ffm("api.endpoints.adminGetDeadLetterByID", "Gets a dead letter by ID, including the original event and the history of delivery attempts")
+	APIEndpointsAdminPostDeadLetterRequeue      = ffm("api.endpoints.adminPostDeadLetterRequeue", "Redelivers the event of a pending dead letter to its subscription")
+	APIEndpointsAdminPostDeadLetterDiscard      = ffm("api.endpoints.adminPostDeadLetterDiscard", "Discards a pending dead letter, so its event is never redelivered")
+	APIEndpointsAdminGetQuarantine              = ffm("api.endpoints.adminGetQuarantine", "Lists the private batches received from other orgs that were quarantined for exceeding the inbound limits")
+	APIEndpointsAdminGetQuarantineByID          = ffm("api.endpoints.adminGetQuarantineByID", "Gets a quarantined batch by ID")
+	APIEndpointsAdminPostQuarantineRelease      = ffm("api.endpoints.adminPostQuarantineRelease", "Processes a pending quarantined batch in the same way as when it was received, without applying the inbound limits")
+	APIEndpointsAdminPostQuarantineDiscard      = ffm("api.endpoints.adminPostQuarantineDiscard", "Discards a pending quarantined batch, so it is never processed")
+	APIEndpointsAdminGetQuarantinedBlobs        = ffm("api.endpoints.adminGetQuarantinedBlobs", "Lists the blobs received from other orgs that were quarantined because the blob scanner flagged them")
+	APIEndpointsAdminGetQuarantinedBlobByID     = ffm("api.endpoints.adminGetQuarantinedBlobByID", "Gets a quarantined blob by ID")
+	APIEndpointsAdminPostQuarantinedBlobRelease = ffm("api.endpoints.adminPostQuarantinedBlobRelease", "Attaches a pending quarantined blob to its data, so any messages waiting on it can be confirmed")
+	APIEndpointsAdminPostQuarantinedBlobDiscard = ffm("api.endpoints.adminPostQuarantinedBlobDiscard", "Discards a pending quarantined blob, and deletes its content from the data exchange")
+	APIEndpointsAdminPostSubscriptionRewind     = ffm("api.endpoints.adminPostSubscriptionRewind", "Rewinds the offset of a durable subscription to a sequence or timestamp, so events are redelivered")
+	APIEndpointsAdminGetRoleBindings            = ffm("api.endpoints.adminGetRoleBindings", "Lists the roles bound to principals in the namespace")
+	APIEndpointsAdminGetRoleBindingByID         = ffm("api.endpoints.adminGetRoleBindingByID", "Gets a role binding by ID")
+	APIEndpointsAdminPostRoleBinding            = ffm("api.endpoints.adminPostRoleBinding", "Binds a role in the namespace to a principal")
+	APIEndpointsAdminDeleteRoleBinding          = ffm("api.endpoints.adminDeleteRoleBinding", "Deletes a role binding, revoking the role from the principal")
+	APIEndpointsAdminGetAuditLog                = ffm("api.endpoints.adminGetAuditLog", "Lists the audit log of the calls that changed the namespace, with the principal, route, request hash and result of each")
+	APIEndpointsAdminGetSlowRequests            = ffm("api.endpoints.adminGetSlowRequests", "Lists the slowest recent requests to the API and admin API, slowest first")
 
 	APIEndpointsDeleteContractAPI               = ffm("api.endpoints.deleteContractAPI", "Delete a contract API")
 	APIEndpointsDeleteContractInterface         = ffm("api.endpoints.deleteContractInterface", "Delete a contract interface")
 	APIEndpointsDeleteContractListener          = ffm("api.endpoints.deleteContractListener", "Deletes a contract listener referenced by its name or its ID")
 	APIEndpointsDeleteSubscription              = ffm("api.endpoints.deleteSubscription", "Deletes a subscription")
+	APIEndpointsDeleteSubscriptionTemplate      = ffm("api.endpoints.deleteSubscriptionTemplate", "Deletes a subscription template. Subscriptions already provisioned from the template are not deleted")
+	APIEndpointsDeleteEventRule                 = ffm("api.endpoints.deleteEventRule", "Deletes an event rule")
 	APIEndpointsDeleteTokenPool                 = ffm("api.endpoints.deleteTokenPool", "Delete a token pool")
+	APIEndpointsDeleteTokenPoolPolicy           = ffm("api.endpoints.deleteTokenPoolPolicy", "Removes the transfer policy from a token pool")
+	APIEndpointsDeleteBatchPolicy               = ffm("api.endpoints.deleteBatchPolicy", "Removes the batch policy from a topic, so its messages are batched with the timeout and size of the dispatcher")
 	APIEndpointsGetBatchBbyID                   = ffm("api.endpoints.getBatchByID", "Gets a message batch")
 	APIEndpointsGetBatches                      = ffm("api.endpoints.getBatches", "Gets a list of message batches")
+	APIEndpointsGetBatchPolicies                = ffm("api.endpoints.getBatchPolicies", "Gets the batch policies of the topics in the namespace")
+	APIEndpointsGetBatchPolicy                  = ffm("api.endpoints.getBatchPolicy", "Gets the batch policy for a topic")
 	APIEndpointsGetBlockchainEventByID          = ffm("api.endpoints.getBlockchainEventByID", "Gets a blockchain event")
 	APIEndpointsListBlockchainEvents            = ffm("api.endpoints.getBlockchainEvents", "Gets a list of blockchain events")
 	APIEndpointsGetChartHistogram               = ffm("api.endpoints.getChartHistogram", "Gets a JSON object containing statistics data that can be used to build a graphical representation of recent activity in a given database collection")
@@ -95,6 +146,7 @@ var (
 	APIEndpointsGetContractListeners            = ffm("api.endpoints.getContractListeners", "Gets a list of contract listeners")
 	APIEndpointsGetDataBlob                     = ffm("api.endpoints.getDataBlob", "Downloads the original file that was previously uploaded or received")
 	APIEndpointsGetDataValue                    = ffm("api.endpoints.getDataValue", "Downloads the JSON value of the data resource, without the associated metadata")
+	APIEndpointsGetDataValueJSON                = ffm("api.endpoints.getDataValueJSON", "Downloads the value of the data resource transcoded to JSON, decoding any Avro or Protobuf binary encoding")
 	APIEndpointsGetDataByID                     = ffm("api.endpoints.getDataByID", "Gets a data item by its ID, including metadata about this item")
 	APIEndpointsDeleteData                      = ffm("api.endpoints.deleteData", "Deletes a data item by its ID, including metadata about this item")
 	APIEndpointsGetDataMsgs                     = ffm("api.endpoints.getDataMsgs", "Gets a list of the messages associated with a data item")
@@ -110,13 +162,21 @@ var (
 	APIEndpointsGetIdentityByID                 = ffm("api.endpoints.getIdentityByID", "Gets an identity by its ID")
 	APIEndpointsGetIdentityDID                  = ffm("api.endpoints.getIdentityDID", "Gets the DID for an identity based on its ID")
 	APIEndpointsGetIdentityVerifiers            = ffm("api.endpoints.getIdentityVerifiers", "Gets the verifiers for an identity")
+	APIEndpointsGetJobByID                      = ffm("api.endpoints.getJobByID", "Gets a job by its ID, including its status and progress")
+	APIEndpointsGetJobOutput                    = ffm("api.endpoints.getJobOutput", "Downloads the file written by a job that has succeeded, such as an export")
+	APIEndpointsGetJobs                         = ffm("api.endpoints.getJobs", "Gets a list of jobs")
 	APIEndpointsGetMsgByID                      = ffm("api.endpoints.getMsgByID", "Gets a message by its ID")
 	APIEndpointsGetMsgData                      = ffm("api.endpoints.getMsgData", "Gets the list of data items that are attached to a message")
 	APIEndpointsGetMsgEvents                    = ffm("api.endpoints.getMsgEvents", "Gets the list of events for a message")
+	APIEndpointsGetMsgReceipts                  = ffm("api.endpoints.getMsgReceipts", "Gets the delivery and read receipts of a private message, for each member of its group on another node")
+	APIEndpointsGetMsgThread                    = ffm("api.endpoints.getMsgThread", "Gets the thread of a message, from the root message of the thread through all of the replies to it")
 	APIEndpointsGetMsgTxn                       = ffm("api.endpoints.getMsgTxn", "Gets the transaction for a message")
 	APIEndpointsGetMsgs                         = ffm("api.endpoints.getMsgs", "Gets a list of messages")
+	APIEndpointsGetScheduledMsgs                = ffm("api.endpoints.getScheduledMsgs", "Gets a list of the messages that are scheduled to be sent at a future send time")
+	APIEndpointsGetDraftMsgs                    = ffm("api.endpoints.getDraftMsgs", "Gets a list of the draft messages that are waiting to be committed")
 	APIEndpointsGetNamespace                    = ffm("api.endpoints.getNamespace", "Gets a namespace")
 	APIEndpointsGetNamespaces                   = ffm("api.endpoints.getNamespaces", "Gets a list of namespaces")
+	APIEndpointsGetTenantNamespaces             = ffm("api.endpoints.getTenantNamespaces", "Gets a list of the namespaces of a tenant")
 	APIEndpointsGetNetworkIdentityByDID         = ffm("api.endpoints.getNetworkIdentityByDID", "Gets an identity by its DID (deprecated - use /identities/{did} instead of /network/identities/{did})")
 	APIEndpointsGetIdentityByDID                = ffm("api.endpoints.getIdentityByDID", "Gets an identity by its DID")
 	APIEndpointsGetDIDDocByDID                  = ffm("api.endpoints.getDIDDocByDID", "Gets a DID document by its DID")
@@ -125,24 +185,54 @@ var (
 	APIEndpointsGetNetworkNodes                 = ffm("api.endpoints.getNetworkNodes", "Gets a list of nodes in the network")
 	APIEndpointsGetNetworkOrg                   = ffm("api.endpoints.getNetworkOrg", "Gets information about a specific org in the network")
 	APIEndpointsGetNetworkOrgs                  = ffm("api.endpoints.APIEndpointsGetNetworkOrgs", "Gets a list of orgs in the network")
+	APIEndpointsGetNetworkStatus                = ffm("api.endpoints.getNetworkStatus", "Gets the chain head, event stream health and event processing progress of this namespace")
 	APIEndpointsGetOpByID                       = ffm("api.endpoints.getOpByID", "Gets an operation by ID")
 	APIEndpointsGetOps                          = ffm("api.endpoints.getOps", "Gets a a list of operations")
 	APIEndpointsGetStatusBatchManager           = ffm("api.endpoints.getStatusBatchManager", "Gets the status of the batch manager")
+	APIEndpointsGetStatusBatchQueues            = ffm("api.endpoints.getStatusBatchQueues", "Gets the depth and age of the queue of unsent messages on each topic, and the lag of the batch dispatcher")
 	APIEndpointsGetPins                         = ffm("api.endpoints.getPins", "Queries the list of pins received from the blockchain")
 	APIEndpointsGetNextPins                     = ffm("api.endpoints.getNextPins", "Queries the list of next-pins that determine the next masked message sequence for each member of a privacy group, on each context/topic")
+	APIEndpointsGetSLABreaches                  = ffm("api.endpoints.getSLABreaches", "Lists the messages sent by this node that took longer than their SLA to be confirmed by this node, or by the node of another member")
+	APIEndpointsGetSLABreachByID                = ffm("api.endpoints.getSLABreachByID", "Gets an SLA breach by ID")
+	APIEndpointsGetTopicSequences               = ffm("api.endpoints.getTopicSequences", "Queries the next topicSequence expected on each context of a strict topic, along with the topic and group of the context")
 	APIEndpointsGetWebSockets                   = ffm("api.endpoints.getStatusWebSockets", "Gets a list of the current WebSocket connections to this node")
 	APIEndpointsGetStatus                       = ffm("api.endpoints.getStatus", "Gets the status of this namespace")
 	APIEndpointsGetSubscriptionByID             = ffm("api.endpoints.getSubscriptionByID", "Gets a subscription by its ID")
 	APIEndpointsGetSubscriptions                = ffm("api.endpoints.getSubscriptions", "Gets a list of subscriptions")
+	APIEndpointsGetSubscriptionTemplateByID     = ffm("api.endpoints.getSubscriptionTemplateByID", "Gets a subscription template by its name or ID")
+	APIEndpointsGetSubscriptionTemplates        = ffm("api.endpoints.getSubscriptionTemplates", "Gets a list of subscription templates")
+	APIEndpointsGetEventRuleByID                = ffm("api.endpoints.getEventRuleByID", "Gets an event rule by its name or ID")
+	APIEndpointsGetEventRules                   = ffm("api.endpoints.getEventRules", "Gets a list of event rules")
+	APIEndpointsGetTokenAccountActivity         = ffm("api.endpoints.getTokenAccountActivity", "Gets a combined list of the token transfers, mints, burns and approvals involving a given token account key, across all pools")
 	APIEndpointsGetTokenAccountPools            = ffm("api.endpoints.getTokenAccountPools", "Gets a list of token pools that contain a given token account key")
 	APIEndpointsGetTokenAccounts                = ffm("api.endpoints.getTokenAccounts", "Gets a list of token accounts")
 	APIEndpointsGetTokenApprovals               = ffm("api.endpoints.getTokenApprovals", "Gets a list of token approvals")
+	APIEndpointsGetTokenAssociatedAccounts      = ffm("api.endpoints.getTokenAssociatedAccounts", "Gets a list of the token accounts created by token connectors to hold the balances of owners, such as Solana SPL associated token accounts")
 	APIEndpointsGetTokenBalances                = ffm("api.endpoints.getTokenBalances", "Gets a list of token balances")
+	APIEndpointsGetTokenBulkMintByID            = ffm("api.endpoints.getTokenBulkMintByID", "Gets a bulk mint by its ID, including the number of mints that have been submitted, have succeeded and have failed")
+	APIEndpointsGetTokenBulkMints               = ffm("api.endpoints.getTokenBulkMints", "Gets a list of bulk mints")
 	APIEndpointsGetTokenConnectors              = ffm("api.endpoints.getTokenConnectors", "Gets the list of token connectors currently in use")
+	APIEndpointsGetTokenMedia                   = ffm("api.endpoints.getTokenMedia", "Gets the image referenced by the metadata of a token, served from FireFly's cache. The image is fetched and cached on first request")
 	APIEndpointsGetTokenPoolByNameOrID          = ffm("api.endpoints.getTokenPoolByNameOrID", "Gets a token pool by its name or its ID")
+	APIEndpointsGetTokenPoolHolders             = ffm("api.endpoints.getTokenPoolHolders", "Gets the number of tokens held by each key in a non-fungible token pool, counted across every token index")
+	APIEndpointsGetTokenPoolIndexes             = ffm("api.endpoints.getTokenPoolIndexes", "Gets the owner of each token index in a non-fungible token pool, along with the number of keys that hold a balance of it")
+	APIEndpointsGetTokenPoolMigrations          = ffm("api.endpoints.getTokenPoolMigrations", "Gets the history of migrations of a token pool to new token connectors")
+	APIEndpointsGetTokenPoolPolicy              = ffm("api.endpoints.getTokenPoolPolicy", "Gets the transfer policy for a token pool")
+	APIEndpointsGetTokenLedger                  = ffm("api.endpoints.getTokenLedger", "Gets the token accounting ledger - a debit or credit entry for each account in each confirmed token transfer, with the running balance of the account")
+	APIEndpointsGetTokenLedgerExport            = ffm("api.endpoints.getTokenLedgerExport", "Exports the token accounting ledger as a CSV or Parquet file, optionally for a single pool or account")
 	APIEndpointsGetTokenPools                   = ffm("api.endpoints.getTokenPools", "Gets a list of token pools")
+	APIEndpointsGetTokenBalanceMismatches       = ffm("api.endpoints.getTokenBalanceMismatches", "Gets a list of the account balances that did not match the chain when they were last reconciled")
+	APIEndpointsGetTokenSnapshotBalances        = ffm("api.endpoints.getTokenSnapshotBalances", "Gets the account balances recorded in a token snapshot")
+	APIEndpointsGetTokenSnapshotByID            = ffm("api.endpoints.getTokenSnapshotByID", "Gets a token snapshot by its ID")
+	APIEndpointsGetTokenSnapshotExport          = ffm("api.endpoints.getTokenSnapshotExport", "Exports all account balances recorded in a token snapshot as CSV")
+	APIEndpointsGetTokenSnapshots               = ffm("api.endpoints.getTokenSnapshots", "Gets a list of token snapshots")
+	APIEndpointsGetTokenSwapByID                = ffm("api.endpoints.getTokenSwapByID", "Gets a token swap by its ID")
+	APIEndpointsGetTokenSwaps                   = ffm("api.endpoints.getTokenSwaps", "Gets a list of token swaps")
+	APIEndpointsGetTokenTransferRequestByID     = ffm("api.endpoints.getTokenTransferRequestByID", "Gets a token transfer request that requires sign-off by its ID")
+	APIEndpointsGetTokenTransferRequests        = ffm("api.endpoints.getTokenTransferRequests", "Gets a list of token transfers and mints that were held for sign-off, because they were above the configured threshold")
 	APIEndpointsGetTokenTransferByID            = ffm("api.endpoints.getTokenTransferByID", "Gets a token transfer by its ID")
 	APIEndpointsGetTokenTransfers               = ffm("api.endpoints.getTokenTransfers", "Gets a list of token transfers")
+	APIEndpointsGetTokenTransfersExport         = ffm("api.endpoints.getTokenTransfersExport", "Exports the history of token transfers, mints and burns as a CSV or Parquet file, optionally for a single pool or account")
 	APIEndpointsGetTxnBlockchainEvents          = ffm("api.endpoints.getTxnBlockchainEvents", "Gets a list blockchain events for a specific transaction")
 	APIEndpointsGetTxnByID                      = ffm("api.endpoints.getTxnByID", "Gets a transaction by its ID")
 	APIEndpointsGetTxnOps                       = ffm("api.endpoints.getTxnOps", "Gets a list of operations in a specific transaction")
@@ -164,6 +254,27 @@ var (
 	APIEndpointsPostData                        = ffm("api.endpoints.postData", "Creates a new data item in this FireFly node")
 	APIEndpointsPostDataValuePublish            = ffm("api.endpoints.postDataValuePublish", "Publishes the JSON value from the specified data resource, to shared storage")
 	APIEndpointsPostDataBlobPublish             = ffm("api.endpoints.postDataBlobPublish", "Publishes the binary blob attachment stored in your local data exchange, to shared storage")
+	APIEndpointsPostDataUpload                  = ffm("api.endpoints.postDataUpload", "Starts a chunked upload of a blob, which can be resumed from its offset if the upload is interrupted")
+	APIEndpointsPostDataUploadComplete          = ffm("api.endpoints.postDataUploadComplete", "Completes a chunked upload of a blob, verifying its size and hash, and creates the data item for it")
+	APIEndpointsPatchDataUpload                 = ffm("api.endpoints.patchDataUpload", "Appends the next chunk of a blob to a chunked upload, at the current offset of the upload")
+	APIEndpointsGetDataUploads                  = ffm("api.endpoints.getDataUploads", "Lists the chunked blob uploads in progress")
+	APIEndpointsGetDataUploadByID               = ffm("api.endpoints.getDataUploadByID", "Gets a chunked blob upload in progress, including the offset to resume it from")
+	APIEndpointsDeleteDataUpload                = ffm("api.endpoints.deleteDataUpload", "Cancels a chunked blob upload, and deletes the chunks uploaded so far")
+	APIEndpointsPostGraphQL                     = ffm("api.endpoints.postGraphQL", "Executes a GraphQL query over the messages, data, events, transactions, tokens and contract APIs of the namespace")
+	APIEndpointsPostGroupMembers                = ffm("api.endpoints.postGroupMembers", "Adds and removes members of a private group, by creating the next generation of the group with a new hash")
+	APIEndpointsPostMsgRead                     = ffm("api.endpoints.postMsgRead", "Sends a read receipt for a private message received by this node, back to the node that sent it")
+	APIEndpointsPostMsgDisclosure               = ffm("api.endpoints.postMsgDisclosure", "Generates a proof of the values of selected fields of the data of a pinned private message, that a third party can verify without seeing the other fields")
+	APIEndpointsPostDisclosureVerify            = ffm("api.endpoints.postDisclosureVerify", "Verifies a disclosure proof against the batch pins received by this node from the blockchain")
+	APIEndpointsPostMsgRecall                   = ffm("api.endpoints.postMsgRecall", "Recalls a private message sent by this node, by sending a recall message to the members of its group")
+	APIEndpointsPostMsgAccept                   = ffm("api.endpoints.postMsgAccept", "Accepts a message held for the application in the pending_app state, which confirms the message")
+	APIEndpointsPostMsgReject                   = ffm("api.endpoints.postMsgReject", "Rejects a message held for the application in the pending_app state, which rejects the message")
+	APIEndpointsGetMsgAcks                      = ffm("api.endpoints.getMsgAcks", "Lists the decisions of the application on the messages that were held for it in the pending_app state")
+	APIEndpointsPostBatchesFlush                = ffm("api.endpoints.postBatchesFlush", "Flushes the batches being assembled in the namespace immediately, optionally only those containing a message on a topic")
+	APIEndpointsPostJobCancel                   = ffm("api.endpoints.postJobCancel", "Cancels a job that is pending or running")
+	APIEndpointsPostDraftMsgCommit              = ffm("api.endpoints.postDraftMsgCommit", "Commits a draft message, so that it is batched and sent")
+	APIEndpointsPostDraftMsgDiscard             = ffm("api.endpoints.postDraftMsgDiscard", "Discards a draft message, so that it is never sent")
+	APIEndpointsPostScheduledMsgCancel          = ffm("api.endpoints.postScheduledMsgCancel", "Cancels a scheduled message before its send time, so that it is never sent")
+	APIEndpointsPostNewJob                      = ffm("api.endpoints.postNewJob", "Submits a long-running administrative action, such as a subscription rewind or an export, as a job that runs in the background")
 	APIEndpointsPostNewContractAPI              = ffm("api.endpoints.postNewContractAPI", "Creates and broadcasts a new custom smart contract API")
 	APIEndpointsPostNewContractInterface        = ffm("api.endpoints.postNewContractInterface", "Creates and broadcasts a new custom smart contract interface")
 	APIEndpointsPostNewContractListener         = ffm("api.endpoints.postNewContractListener", "Creates a new blockchain listener for events emitted by custom smart contracts")
@@ -177,16 +288,33 @@ var (
 	APIEndpointsPostNewOrganizationSelf         = ffm("api.endpoints.postNewOrganizationSelf", "Instructs this FireFly node to register its org on the network")
 	APIEndpointsPostNewOrganization             = ffm("api.endpoints.postNewOrganization", "Registers a new org in the network")
 	APIEndpointsPostNewSubscription             = ffm("api.endpoints.postNewSubscription", "Creates a new subscription for an application to receive events from FireFly")
+	APIEndpointsPostSubscriptionPull            = ffm("api.endpoints.postSubscriptionPull", "Pulls the next events of a subscription that uses the pull transport, waiting for events to arrive if none are available")
+	APIEndpointsPostSubscriptionAck             = ffm("api.endpoints.postSubscriptionAck", "Acknowledges or rejects events pulled from a subscription that uses the pull transport")
+	APIEndpointsPostNewSubscriptionTemplate     = ffm("api.endpoints.postNewSubscriptionTemplate", "Creates a subscription template, that provisions a subscription each time a matching contract API, token pool or topic is created")
+	APIEndpointsPostNewEventRule                = ffm("api.endpoints.postNewEventRule", "Creates an event rule, that routes, labels or suppresses matching events for a set of subscriptions")
 	APIEndpointsPostOpRetry                     = ffm("api.endpoints.postOpRetry", "Retries a failed operation")
 	APIEndpointsPostPinsRewind                  = ffm("api.endpoints.postPinsRewind", "Force a rewind of the event aggregator to a previous position, to re-evaluate (and possibly dispatch) that pin and others after it. Only accepts a sequence or batch ID for a currently undispatched pin")
 	APIEndpointsPostTokenApproval               = ffm("api.endpoints.postTokenApproval", "Creates a token approval")
 	APIEndpointsPostTokenBurn                   = ffm("api.endpoints.postTokenBurn", "Burns some tokens")
+	APIEndpointsPostTokenBulkMint               = ffm("api.endpoints.postTokenBulkMint", "Mints a large number of non-fungible tokens, such as an NFT drop. The mints are submitted to the token connector in batches in the background, and the progress is tracked by a single parent operation")
 	APIEndpointsPostTokenMint                   = ffm("api.endpoints.postTokenMint", "Mints some tokens")
 	APIEndpointsPostTokenPool                   = ffm("api.endpoints.postTokenPool", "Creates a new token pool")
+	APIEndpointsPostTokenPoolMigrate            = ffm("api.endpoints.postTokenPoolMigrate", "Migrates a token pool to a different token connector, keeping all of its transfer history. The pool is re-pointed once the new connector has resolved the pool and its details have been verified")
+	APIEndpointsPostTokenPoolPause              = ffm("api.endpoints.postTokenPoolPause", "Pauses a token pool, so that new transfers and approvals are rejected")
 	APIEndpointsPostTokenPoolPublish            = ffm("api.endpoints.postTokenPoolPublish", "Publish a token pool to all other members of the multiparty network")
+	APIEndpointsPostTokenPoolResume             = ffm("api.endpoints.postTokenPoolResume", "Resumes a paused token pool")
+	APIEndpointsPostTokenPoolRetire             = ffm("api.endpoints.postTokenPoolRetire", "Permanently retires a token pool, so that it can no longer be used")
+	APIEndpointsPostTokenPoolReconcile          = ffm("api.endpoints.postTokenPoolReconcile", "Compares the balance of every known account in a token pool with the on-chain balance reported by the token connector, and records any mismatches")
+	APIEndpointsPostTokenPoolSnapshot           = ffm("api.endpoints.postTokenPoolSnapshot", "Records the balance of every account in a token pool at a given block number or time")
 	APIEndpointsPostTokenTransfer               = ffm("api.endpoints.postTokenTransfer", "Transfers some tokens")
+	APIEndpointsPostTokenSwap                   = ffm("api.endpoints.postTokenSwap", "Swaps two legs atomically, locking each token transfer in the escrow contract of the token connector until every leg has succeeded")
+	APIEndpointsPostTokenTransferBatch          = ffm("api.endpoints.postTokenTransferBatch", "Transfers several token index/amount pairs between the same accounts in a single blockchain transaction")
+	APIEndpointsPostTokenTransferCheck          = ffm("api.endpoints.postTokenTransferCheck", "Checks with the token connector if a transfer would be permitted, without submitting it")
+	APIEndpointsPostTokenTransferRequestSignoff = ffm("api.endpoints.postTokenTransferRequestSignoff", "Signs off a pending token transfer request as the authenticated principal of the request, which must be one of the configured approvers. The transfer is submitted to the token connector once the required number of approvers have signed it off")
 	APIEndpointsPutContractAPI                  = ffm("api.endpoints.putContractAPI", "Updates an existing contract API")
 	APIEndpointsPutSubscription                 = ffm("api.endpoints.putSubscription", "Update an existing subscription")
+	APIEndpointsPutTokenPoolPolicy              = ffm("api.endpoints.putTokenPoolPolicy", "Sets the transfer policy for a token pool, which is checked before this node submits any transfer, mint or burn in the pool")
+	APIEndpointsPutBatchPolicy                  = ffm("api.endpoints.putBatchPolicy", "Sets the batch policy for a topic, which overrides the batch timeout and size of the dispatcher for messages on the topic")
 	APIEndpointsGetContractAPIInterface         = ffm("api.endpoints.getContractAPIInterface", "Gets a contract interface for a contract API")
 	APIEndpointsPostNetworkAction               = ffm("api.endpoints.postNetworkAction", "Notify all nodes in the network of a new governance action")
 	APIEndpointsPostVerifiersResolve            = ffm("api.endpoints.postVerifiersResolve", "Resolves an input key to a signing key")
@@ -199,6 +327,7 @@ var (
 	APIFilterLimitDesc         = ffm("api.filterLimit", "The maximum number of records to return (max: %d)")
 	APIFilterCountDesc         = ffm("api.filterCount", "Return a total count as well as items (adds extra database processing)")
 	APIFetchDataDesc           = ffm("api.fetchData", "Fetch the data and include it in the messages returned")
+	APIIncludeExpiredDesc      = ffm("api.includeExpired", "Include confirmed messages that are past their expiry time, when message.expiry.maskConfirmed is set")
 	APIConfirmQueryParam       = ffm("api.confirmQueryParam", "When true the HTTP request blocks until the message is confirmed")
 	APIPublishQueryParam       = ffm("api.publishQueryParam", "When true the definition will be published to all other members of the multiparty network")
 	APIHistogramStartTimeParam = ffm("api.histogramStartTime", "Start time of the data to be fetched")
@@ -45,21 +45,75 @@ var (
 	ConfigSPIReadTimeout  = ffc("config.spi.readTimeout", "The maximum time to wait when reading from an HTTP connection", i18n.TimeDurationType)
 	ConfigSPIWriteTimeout = ffc("config.spi.writeTimeout", "The maximum time to wait when writing to an HTTP connection", i18n.TimeDurationType)
 
+	ConfigSPIListeners                    = ffc("config.spi.listeners", "Additional listeners for the admin HTTP API, each on a TCP port or a Unix domain socket, with optional TLS. The timeouts and auth of the spi section apply to every listener", "List "+i18n.StringType)
+	ConfigSPIListenersAddress             = ffc("config.spi.listeners[].address", "The IP address on which the listener should listen", "IP Address "+i18n.StringType)
+	ConfigSPIListenersPort                = ffc("config.spi.listeners[].port", "The port on which the listener should listen", i18n.IntType)
+	ConfigSPIListenersSocket              = ffc("config.spi.listeners[].socket", "The path of a Unix domain socket to listen on, instead of a TCP port", i18n.StringType)
+	ConfigSPIListenersSocketMode          = ffc("config.spi.listeners[].socketMode", "The octal file permissions of the Unix domain socket, which control the users that can connect to it", i18n.StringType)
+	ConfigSPIRouteGroups                  = ffc("config.spi.routeGroups", "The route groups served on the main listener, such as `messages`, `tokens` or `swagger`. All route groups are served when empty", "List "+i18n.StringType)
+	ConfigSPIDisabledRouteGroups          = ffc("config.spi.disabledRouteGroups", "The route groups that are not served on the main listener, which answers their requests with a 404", "List "+i18n.StringType)
+	ConfigSPIListenersRouteGroups         = ffc("config.spi.listeners[].routeGroups", "The route groups served on the listener. All route groups are served when empty", "List "+i18n.StringType)
+	ConfigSPIListenersDisabledRouteGroups = ffc("config.spi.listeners[].disabledRouteGroups", "The route groups that are not served on the listener, which answers their requests with a 404", "List "+i18n.StringType)
+
+	ConfigGRPCAddress = ffc("config.grpc.address", "The IP address on which the gRPC API should listen", "IP Address "+i18n.StringType)
+	ConfigGRPCEnabled = ffc("config.grpc.enabled", "Enables the gRPC API, for typed access to sending messages, invoking contracts, transferring tokens and querying collections", i18n.BooleanType)
+	ConfigGRPCPort    = ffc("config.grpc.port", "The port on which the gRPC API should listen", i18n.IntType)
+
 	ConfigAPIDefaultFilterLimit = ffc("config.api.defaultFilterLimit", "The maximum number of rows to return if no limit is specified on an API request", i18n.IntType)
 	ConfigAPIMaxFilterLimit     = ffc("config.api.maxFilterLimit", "The largest value of `limit` that an HTTP client can specify in a request", i18n.IntType)
+	ConfigAPIMaxStreamLimit     = ffc("config.api.maxStreamLimit", "The largest value of `limit` that an HTTP client can specify in a request for newline delimited JSON, on the collections that stream their results. Zero removes the limit", i18n.IntType)
 	ConfigAPIRequestMaxTimeout  = ffc("config.api.requestMaxTimeout", "The maximum amount of time that an HTTP client can specify in a `Request-Timeout` header to keep a specific request open", i18n.TimeDurationType)
 	ConfigAPIPassthroughHeaders = ffc("config.api.passthroughHeaders", "A list of HTTP request headers to pass through to dependency microservices", i18n.ArrayStringType)
 
-	ConfigAssetManagerKeyNormalization = ffc("config.asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization (deprecated - use namespaces.predefined[].asset.manager.keyNormalization)", i18n.StringType)
-
-	ConfigBatchManagerMinimumPollDelay = ffc("config.batch.manager.minimumPollDelay", "The minimum time the batch manager waits between polls on the DB - to prevent thrashing", i18n.TimeDurationType)
-	ConfigBatchManagerPollTimeout      = ffc("config.batch.manager.pollTimeout", "How long to wait without any notifications of new messages before doing a page query", i18n.TimeDurationType)
-	ConfigBatchManagerReadPageSize     = ffc("config.batch.manager.readPageSize", "The size of each page of messages read from the database into memory when assembling batches", i18n.IntType)
+	ConfigAuditEnabled    = ffc("config.audit.enabled", "Records the principal, route, request hash, idempotency key and result of every mutating call to the API and the gRPC API in the audit log of the namespace", i18n.BooleanType)
+	ConfigAuditExportFile = ffc("config.audit.exportFile", "A file each audit log entry is also appended to as a line of JSON, for shipping to an external log store", i18n.StringType)
+
+	ConfigAssetApprovalExpiryCheckInterval    = ffc("config.asset.approvalExpiry.checkInterval", "How often to check for token approvals that have reached their expiry, and submit revocations for them", i18n.TimeDurationType)
+	ConfigAssetApprovalExpiryBatchSize        = ffc("config.asset.approvalExpiry.batchSize", "The maximum number of expired token approvals to revoke on each check", i18n.IntType)
+	ConfigAssetManagerKeyNormalization        = ffc("config.asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization (deprecated - use namespaces.predefined[].asset.manager.keyNormalization)", i18n.StringType)
+	ConfigAssetMetadataAllowedHosts           = ffc("config.asset.metadata.allowedHosts", "If set, token metadata and media are only fetched from these hosts, including the IPFS gateway. Hosts in this list may resolve to private addresses. Otherwise any host is fetched from, but not if it resolves to a loopback, private or link-local address", i18n.ArrayStringType)
+	ConfigAssetMetadataEnabled                = ffc("config.asset.metadata.enabled", "Whether to fetch, validate and cache the ERC-721 / ERC-1155 metadata from the URIs of tokens, and return it on token transfer and balance queries", i18n.BooleanType)
+	ConfigAssetMetadataIPFSGateway            = ffc("config.asset.metadata.ipfsGateway", "The IPFS gateway used to dereference `ipfs://` token URIs", i18n.StringType)
+	ConfigAssetMetadataRequestTimeout         = ffc("config.asset.metadata.requestTimeout", "The timeout for each request to fetch token metadata", i18n.TimeDurationType)
+	ConfigAssetMetadataMaxRedirects           = ffc("config.asset.metadata.maxRedirects", "The maximum number of redirects followed when fetching a token URI", i18n.IntType)
+	ConfigAssetMetadataMaxSize                = ffc("config.asset.metadata.maxSize", "The maximum size of a token metadata document", i18n.ByteSizeType)
+	ConfigAssetMetadataRetryInterval          = ffc("config.asset.metadata.retryInterval", "The minimum time before a failed fetch of token metadata is retried", i18n.TimeDurationType)
+	ConfigAssetMetadataWorkers                = ffc("config.asset.metadata.workers", "The number of workers fetching token metadata in parallel", i18n.IntType)
+	ConfigAssetMetadataQueueLength            = ffc("config.asset.metadata.queueLength", "The number of token URIs that can be queued for fetching. Further URIs are skipped, and queued again on a later query", i18n.IntType)
+	ConfigAssetMetadataMediaEnabled           = ffc("config.asset.metadata.media.enabled", "Whether to serve cached copies of the images referenced by token metadata from the FireFly API, so that applications do not need to fetch them from third-party gateways. Requires asset.metadata.enabled", i18n.BooleanType)
+	ConfigAssetMetadataMediaMaxSize           = ffc("config.asset.metadata.media.maxSize", "The maximum size of an image referenced by token metadata", i18n.ByteSizeType)
+	ConfigAssetMetadataMediaContentTypes      = ffc("config.asset.metadata.media.contentTypes", "The content types of images that will be cached and served. Only image/* and video/* content types are allowed. Note that SVG images can contain scripts, so are not included by default", i18n.ArrayStringType)
+	ConfigAssetMetadataMediaStore             = ffc("config.asset.metadata.media.store", "Where cached images are stored - 'database', or 'sharedstorage' to store them in the shared storage plugin of the namespace", i18n.StringType)
+	ConfigAssetSwapDefaultTimeout             = ffc("config.asset.swap.defaultTimeout", "The time that the legs of a token swap are held in escrow before they can be refunded, if no timeout is specified on the swap", i18n.TimeDurationType)
+	ConfigAssetSwapCheckInterval              = ffc("config.asset.swap.checkInterval", "How often to check the operations of in-flight token swaps, and submit the next step of each swap", i18n.TimeDurationType)
+	ConfigAssetSwapBatchSize                  = ffc("config.asset.swap.batchSize", "The number of in-flight token swaps to read in each page, as every in-flight swap is processed on each check", i18n.IntType)
+	ConfigAssetReconciliationInterval         = ffc("config.asset.reconciliation.interval", "How often to compare the balances of every token pool with the on-chain balances reported by the token connector. Set to 0 to only reconcile on demand", i18n.TimeDurationType)
+	ConfigAssetReconciliationBatchSize        = ffc("config.asset.reconciliation.batchSize", "The number of balances to read from the database at a time when reconciling a token pool", i18n.IntType)
+	ConfigAssetSignoffThreshold               = ffc("config.asset.signoff.threshold", "The amount above which token transfers and mints are held until they are signed off by the configured approvers, in whole tokens scaled by the decimals of the pool. Leave empty to submit all transfers and mints immediately", i18n.StringType)
+	ConfigAssetSignoffApprovers               = ffc("config.asset.signoff.approvers", "The authenticated principals that can sign off token transfers and mints above the threshold - the identity mapped from a client certificate, or the RBAC principal of the request", i18n.ArrayStringType)
+	ConfigAssetSignoffRequired                = ffc("config.asset.signoff.required", "The number of approvers that must sign off a token transfer or mint above the threshold before it is submitted to the token connector", i18n.IntType)
+	ConfigAssetBulkMintBatchSize              = ffc("config.asset.bulkMint.batchSize", "The number of mints submitted to the token connector in each batch of a bulk mint, if no batch size is specified on the request", i18n.IntType)
+	ConfigAssetBulkMintMaxBatchSize           = ffc("config.asset.bulkMint.maxBatchSize", "The maximum batch size that can be requested for a bulk mint. Set this to suit the throughput of the token connector and blockchain", i18n.IntType)
+	ConfigAssetBulkMintMaxItems               = ffc("config.asset.bulkMint.maxItems", "The maximum number of tokens that can be minted in a single bulk mint", i18n.IntType)
+	ConfigAssetBulkMintBatchInterval          = ffc("config.asset.bulkMint.batchInterval", "The interval between submitting each batch of a bulk mint", i18n.TimeDurationType)
+	ConfigAssetBulkMintMaxInFlight            = ffc("config.asset.bulkMint.maxInFlight", "The maximum number of bulk mints that have a batch submitted on each interval", i18n.IntType)
+	ConfigAssetCircuitBreakerFailureThreshold = ffc("config.asset.circuitBreaker.failureThreshold", "The number of consecutive calls to a token connector that fail to connect or return a 5xx status, before further requests to that connector are failed fast. Set to 0 to disable the circuit breaker", i18n.IntType)
+	ConfigAssetCircuitBreakerProbeInterval    = ffc("config.asset.circuitBreaker.probeInterval", "How long requests to a token connector are failed fast after the circuit breaker trips, before a single request is let through to check whether the connector has recovered", i18n.TimeDurationType)
+
+	ConfigBatchBackpressureMaxQueueDepth = ffc("config.batch.backpressure.maxQueueDepth", "The number of messages on a topic that have been read for batching and not yet sent, at or above which new messages on the topic are rejected with a 429 status. Set to 0 to disable", i18n.IntType)
+	ConfigBatchBackpressureMaxMessageAge = ffc("config.batch.backpressure.maxMessageAge", "The age of the oldest message on a topic that has been read for batching and not yet sent, at or above which new messages on the topic are rejected with a 429 status. Set to 0 to disable", i18n.TimeDurationType)
+	ConfigBatchManagerMinimumPollDelay   = ffc("config.batch.manager.minimumPollDelay", "The minimum time the batch manager waits between polls on the DB - to prevent thrashing", i18n.TimeDurationType)
+	ConfigBatchManagerPollTimeout        = ffc("config.batch.manager.pollTimeout", "How long to wait without any notifications of new messages before doing a page query", i18n.TimeDurationType)
+	ConfigBatchManagerReadPageSize       = ffc("config.batch.manager.readPageSize", "The size of each page of messages read from the database into memory when assembling batches", i18n.IntType)
+
+	ConfigBlobdeduplicationEnabled = ffc("config.blobdeduplication.enabled", "Whether a blob uploaded to or received by this node, with the same hash as a blob already stored, references the stored payload instead of storing a duplicate", i18n.BooleanType)
 
 	ConfigBlobreceiverWorkerBatchMaxInserts = ffc("config.blobreceiver.worker.batchMaxInserts", "The maximum number of items the blob receiver worker will insert in a batch", i18n.IntType)
 	ConfigBlobreceiverWorkerBatchTimeout    = ffc("config.blobreceiver.worker.batchTimeout", "The maximum amount of the the blob receiver worker will wait", i18n.TimeDurationType)
 	ConfigBlobreceiverWorkerCount           = ffc("config.blobreceiver.worker.count", "The number of blob receiver workers", i18n.IntType)
 
+	ConfigBlobuploadDirectory = ffc("config.blobupload.directory", "The directory the chunks of resumable blob uploads are staged in, until the upload is complete. Defaults to a firefly-uploads directory in the temporary directory of the OS", i18n.StringType)
+
 	ConfigBlockchainType = ffc("config.blockchain.type", "A string defining which type of blockchain plugin to use. This tells FireFly which type of configuration to load for the rest of the `blockchain` section", i18n.StringType)
 
 	ConfigBlockchainEthereumAddressResolverAlwaysResolve         = ffc("config.blockchain.ethereum.addressResolver.alwaysResolve", "Causes the address resolver to be invoked on every API call that submits a signing key, regardless of whether the input string conforms to an 0x address. Also disables any result caching", i18n.BooleanType)
@@ -129,6 +183,8 @@ var (
 	ConfigCacheOperationsTTL           = ffc("config.cache.operations.ttl", "Time to live of cached items for operations", i18n.StringType)
 	ConfigCacheTokenPoolLimit          = ffc("config.cache.tokenpool.limit", "Max number of cached items for token pools", i18n.IntType)
 	ConfigCacheTokenPoolTTL            = ffc("config.cache.tokenpool.ttl", "Time to live of cached items for token pool", i18n.StringType)
+	ConfigCacheTokenFeesLimit          = ffc("config.cache.tokenfees.limit", "Max number of cached royalty and transfer fee lookups from token connectors", i18n.IntType)
+	ConfigCacheTokenFeesTTL            = ffc("config.cache.tokenfees.ttl", "Time to live of cached royalty and transfer fee lookups from token connectors", i18n.StringType)
 
 	ConfigPluginDatabase     = ffc("config.plugins.database", "The list of configured Database plugins", i18n.StringType)
 	ConfigPluginDatabaseName = ffc("config.plugins.database[].name", "The name of the Database plugin", i18n.StringType)
@@ -170,6 +226,13 @@ var (
 	ConfigPluginBlockchainEthereumEthconnectBackgroundStartInitialDelay = ffc("config.plugins.blockchain[].ethereum.ethconnect.backgroundStart.initialDelay", "Delay between restarts in the case where we retry to restart the ethereum plugin", i18n.TimeDurationType)
 	ConfigPluginBlockchainEthereumEthconnectBackgroundStartMaxDelay     = ffc("config.plugins.blockchain[].ethereum.ethconnect.backgroundStart.maxDelay", "Max delay between restarts in the case where we retry to restart the ethereum plugin", i18n.TimeDurationType)
 	ConfigPluginBlockchainEthereumEthconnectBackgroundStartFactor       = ffc("config.plugins.blockchain[].ethereum.ethconnect.backgroundStart.factor", "Set the factor by which the delay increases when retrying", i18n.FloatType)
+	ConfigPluginBlockchainEthereumEthconnectFailoverURLs                = ffc("config.plugins.blockchain[].ethereum.ethconnect.failover.urls", "A list of additional connector URLs to fail over to when the primary URL is unhealthy. All URLs must be replicas sharing the same event stream and subscription state", i18n.ArrayStringType)
+	ConfigPluginBlockchainEthereumEthconnectFailoverHealthCheckPath     = ffc("config.plugins.blockchain[].ethereum.ethconnect.failover.healthCheckPath", "The HTTP path used to check the health of each connector URL, when failover URLs are configured", i18n.StringType)
+	ConfigPluginBlockchainEthereumEthconnectFailoverHealthCheckInterval = ffc("config.plugins.blockchain[].ethereum.ethconnect.failover.healthCheckInterval", "How often the health of the active connector URL is checked, when failover URLs are configured. Set to 0 to only check on reconnect", i18n.TimeDurationType)
+	ConfigPluginBlockchainEthereumEthconnectCatchupBatchInterval        = ffc("config.plugins.blockchain[].ethereum.ethconnect.catchup.batchInterval", "The minimum time between processing event batches while catching up on events missed before connecting to Ethconnect. Catch-up ends when Ethconnect delivers a batch smaller than the configured batch size. Set to 0 to disable rate limiting", i18n.TimeDurationType)
+	ConfigPluginBlockchainEthereumEthconnectQueryQuorumURLs             = ffc("config.plugins.blockchain[].ethereum.ethconnect.queryQuorum.urls", "A list of additional independent connector URLs that contract queries are sent to, in addition to the primary URL. Results are only returned when enough endpoints return a matching result", i18n.ArrayStringType)
+	ConfigPluginBlockchainEthereumEthconnectQueryQuorumMinMatching      = ffc("config.plugins.blockchain[].ethereum.ethconnect.queryQuorum.minMatching", "The number of connector endpoints (including the primary URL) that must return matching results for a contract query. Must be at least 2. Defaults to a majority of the endpoints", i18n.IntType)
+	ConfigPluginBlockchainEthereumEthconnectChainIDPath                 = ffc("config.plugins.blockchain[].ethereum.ethconnect.chainIdPath", "The HTTP path on the connector that returns a JSON object containing a 'chainId' field. Only used when a chain ID is configured on a namespace multiparty contract", i18n.StringType)
 	ConfigPluginBlockchainEthereumEthconnectBatchSize                   = ffc("config.plugins.blockchain[].ethereum.ethconnect.batchSize", "The number of events Ethconnect should batch together for delivery to FireFly core. Only applies when automatically creating a new event stream", i18n.IntType)
 	ConfigPluginBlockchainEthereumEthconnectBatchTimeout                = ffc("config.plugins.blockchain[].ethereum.ethconnect.batchTimeout", "How long Ethconnect should wait for new events to arrive and fill a batch, before sending the batch to FireFly core. Only applies when automatically creating a new event stream", i18n.TimeDurationType)
 	ConfigPluginBlockchainEthereumEthconnectInstance                    = ffc("config.plugins.blockchain[].ethereum.ethconnect.instance", "The Ethereum address of the FireFly BatchPin smart contract that has been deployed to the blockchain", "Address "+i18n.StringType)
@@ -180,6 +243,15 @@ var (
 	ConfigPluginBlockchainEthereumEthconnectURL                         = ffc("config.plugins.blockchain[].ethereum.ethconnect.url", "The URL of the Ethconnect instance", "URL "+i18n.StringType)
 	ConfigPluginBlockchainEthereumEthconnectProxyURL                    = ffc("config.plugins.blockchain[].ethereum.ethconnect.proxy.url", "Optional HTTP proxy server to use when connecting to Ethconnect", "URL "+i18n.StringType)
 
+	ConfigPluginBlockchainEthereumAccountAbstractionSmartAccounts = ffc("config.plugins.blockchain[].ethereum.accountAbstraction.smartAccounts", "A list of signing keys that are ERC-4337 smart accounts. Transactions signed by these keys are submitted by the connector as UserOperations through a bundler", i18n.ArrayStringType)
+	ConfigPluginBlockchainEthereumAccountAbstractionEntryPoint    = ffc("config.plugins.blockchain[].ethereum.accountAbstraction.entryPoint", "The address of the ERC-4337 EntryPoint contract", i18n.StringType)
+	ConfigPluginBlockchainEthereumAccountAbstractionPaymaster     = ffc("config.plugins.blockchain[].ethereum.accountAbstraction.paymaster", "The address of a paymaster contract to sponsor the gas for UserOperations, enabling gasless transactions", i18n.StringType)
+
+	ConfigPluginBlockchainEthereumBatchPinMode = ffc("config.plugins.blockchain[].ethereum.batchPin.mode", "How batch pins are encoded on chain. `standard` calls `pinBatch` with ABI encoded parameters. `packed` calls `pinBatchPacked` with a single tightly packed payload, to reduce calldata costs on L2 rollups. Requires version 3 or later of the FireFly contract, which implements `pinBatchPacked`. The version is checked when the subscription is created", i18n.StringType)
+
+	ConfigPluginBlockchainEthereumENSEnabled  = ffc("config.plugins.blockchain[].ethereum.ens.enabled", "Enables resolution of ENS names under `.eth` (such as `alice.eth`) wherever a signing key or address is accepted", i18n.BooleanType)
+	ConfigPluginBlockchainEthereumENSRegistry = ffc("config.plugins.blockchain[].ethereum.ens.registry", "The address of the ENS registry contract used to resolve ENS names", i18n.StringType)
+
 	ConfigPluginBlockchainEthereumFFTMURL      = ffc("config.plugins.blockchain[].ethereum.fftm.url", "The URL of the FireFly Transaction Manager runtime, if enabled", i18n.StringType)
 	ConfigPluginBlockchainEthereumFFTMProxyURL = ffc("config.plugins.blockchain[].ethereum.fftm.proxy.url", "Optional HTTP proxy server to use when connecting to the Transaction Manager", i18n.StringType)
 
@@ -198,10 +270,27 @@ var (
 	ConfigPluginBlockchainFabricFabconnectChaincode                   = ffc("config.plugins.blockchain[].fabric.fabconnect.chaincode", "The name of the Fabric chaincode that FireFly will use for BatchPin transactions (deprecated - use fireflyContract[].chaincode)", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectChannel                     = ffc("config.plugins.blockchain[].fabric.fabconnect.channel", "The Fabric channel that FireFly will use for BatchPin transactions", i18n.StringType)
 
+	ConfigPluginBlockchainHederaHederaconnectBackgroundStart             = ffc("config.plugins.blockchain[].hedera.hederaconnect.backgroundStart.enabled", "Start the hedera plugin in the background and enter retry loop if failed to start", i18n.BooleanType)
+	ConfigPluginBlockchainHederaHederaconnectBackgroundStartInitialDelay = ffc("config.plugins.blockchain[].hedera.hederaconnect.backgroundStart.initialDelay", "Delay between restarts in the case where we retry to restart the hedera plugin", i18n.TimeDurationType)
+	ConfigPluginBlockchainHederaHederaconnectBackgroundStartMaxDelay     = ffc("config.plugins.blockchain[].hedera.hederaconnect.backgroundStart.maxDelay", "Max delay between restarts in the case where we retry to restart the hedera plugin", i18n.TimeDurationType)
+	ConfigPluginBlockchainHederaHederaconnectBackgroundStartFactor       = ffc("config.plugins.blockchain[].hedera.hederaconnect.backgroundStart.factor", "Set the factor by which the delay increases when retrying", i18n.FloatType)
+	ConfigPluginBlockchainHederaHederaconnectBatchSize                   = ffc("config.plugins.blockchain[].hedera.hederaconnect.batchSize", "The number of HCS topic messages the Hedera connector should batch together for delivery to FireFly core. Only applies when automatically creating a new event stream", i18n.IntType)
+	ConfigPluginBlockchainHederaHederaconnectBatchTimeout                = ffc("config.plugins.blockchain[].hedera.hederaconnect.batchTimeout", "The maximum amount of time to wait for a batch to complete", i18n.TimeDurationType)
+	ConfigPluginBlockchainHederaHederaconnectTopic                       = ffc("config.plugins.blockchain[].hedera.hederaconnect.topic", "The websocket listen topic that the node should register on, which is important if there are multiple nodes using a single Hedera connector", i18n.StringType)
+	ConfigPluginBlockchainHederaHederaconnectURL                         = ffc("config.plugins.blockchain[].hedera.hederaconnect.url", "The URL of the Hedera connector instance", "URL "+i18n.StringType)
+	ConfigPluginBlockchainHederaHederaconnectProxyURL                    = ffc("config.plugins.blockchain[].hedera.hederaconnect.proxy.url", "Optional HTTP proxy server to use when connecting to the Hedera connector", "URL "+i18n.StringType)
+
 	ConfigBroadcastBatchAgentTimeout = ffc("config.broadcast.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.StringType)
 	ConfigBroadcastBatchPayloadLimit = ffc("config.broadcast.batch.payloadLimit", "The maximum payload size of a batch for broadcast messages", i18n.ByteSizeType)
 	ConfigBroadcastBatchSize         = ffc("config.broadcast.batch.size", "The maximum number of messages that can be packed into a batch", i18n.IntType)
 	ConfigBroadcastBatchTimeout      = ffc("config.broadcast.batch.timeout", "The timeout to wait for a batch to fill, before sending", i18n.TimeDurationType)
+	ConfigBroadcastChunkingEnabled   = ffc("config.broadcast.chunking.enabled", "Whether to split broadcast messages that are too large for a batch into chunk messages, which are reassembled by each member before the message is confirmed", i18n.BooleanType)
+
+	ConfigBridgeBatchSize      = ffc("config.bridge.batchSize", "The number of events each namespace bridge reads from its source namespace at a time", i18n.IntType)
+	ConfigBridgePollInterval   = ffc("config.bridge.pollInterval", "How often a namespace bridge checks for new events, once it has bridged all the events in its source namespace", i18n.TimeDurationType)
+	ConfigBridgeRetryFactor    = ffc("config.bridge.retry.factor", "The retry backoff factor", i18n.FloatType)
+	ConfigBridgeRetryInitDelay = ffc("config.bridge.retry.initDelay", "The initial retry delay", i18n.TimeDurationType)
+	ConfigBridgeRetryMaxDelay  = ffc("config.bridge.retry.maxDelay", "The maximum retry delay", i18n.TimeDurationType)
 
 	ConfigDatabaseType = ffc("config.database.type", "The type of the database interface plugin to use", i18n.IntType)
 
@@ -252,6 +341,7 @@ var (
 	ConfigEventAggregatorRewindQueueLength = ffc("config.event.aggregator.rewindQueueLength", "The size of the queue into the rewind dispatcher", i18n.IntType)
 	ConfigEventAggregatorRewindTimout      = ffc("config.event.aggregator.rewindTimeout", "The minimum time to wait for rewinds to accumulate before resolving them", i18n.TimeDurationType)
 	ConfigEventAggregatorRewindQueryLimit  = ffc("config.event.aggregator.rewindQueryLimit", "Safety limit on the maximum number of records to search when performing queries to search for rewinds", i18n.IntType)
+	ConfigEventAggregatorStrictTopics      = ffc("config.event.aggregator.strictTopics", "Topics on which messages are only confirmed in the order of the topicSequence in their header, across all senders. Out of order messages are parked until the gap is filled", i18n.ArrayStringType)
 	ConfigEventDbeventsBufferSize          = ffc("config.event.dbevents.bufferSize", "The size of the buffer of change events", i18n.ByteSizeType)
 
 	ConfigEventDispatcherBatchTimeout = ffc("config.event.dispatcher.batchTimeout", "A short time to wait for new events to arrive before re-polling for new events", i18n.TimeDurationType)
@@ -269,12 +359,25 @@ var (
 	ConfigHTTPReadTimeout  = ffc("config.http.readTimeout", "The maximum time to wait when reading from an HTTP connection", i18n.TimeDurationType)
 	ConfigHTTPWriteTimeout = ffc("config.http.writeTimeout", "The maximum time to wait when writing to an HTTP connection", i18n.TimeDurationType)
 
+	ConfigHTTPListeners                    = ffc("config.http.listeners", "Additional listeners for the HTTP API, each on a TCP port or a Unix domain socket, with optional TLS. The timeouts and auth of the http section apply to every listener", "List "+i18n.StringType)
+	ConfigHTTPListenersAddress             = ffc("config.http.listeners[].address", "The IP address on which the listener should listen", "IP Address "+i18n.StringType)
+	ConfigHTTPListenersPort                = ffc("config.http.listeners[].port", "The port on which the listener should listen", i18n.IntType)
+	ConfigHTTPListenersSocket              = ffc("config.http.listeners[].socket", "The path of a Unix domain socket to listen on, instead of a TCP port", i18n.StringType)
+	ConfigHTTPListenersSocketMode          = ffc("config.http.listeners[].socketMode", "The octal file permissions of the Unix domain socket, which control the users that can connect to it", i18n.StringType)
+	ConfigHTTPRouteGroups                  = ffc("config.http.routeGroups", "The route groups served on the main listener, such as `messages`, `tokens` or `swagger`. All route groups are served when empty", "List "+i18n.StringType)
+	ConfigHTTPDisabledRouteGroups          = ffc("config.http.disabledRouteGroups", "The route groups that are not served on the main listener, which answers their requests with a 404", "List "+i18n.StringType)
+	ConfigHTTPListenersRouteGroups         = ffc("config.http.listeners[].routeGroups", "The route groups served on the listener. All route groups are served when empty", "List "+i18n.StringType)
+	ConfigHTTPListenersDisabledRouteGroups = ffc("config.http.listeners[].disabledRouteGroups", "The route groups that are not served on the listener, which answers their requests with a 404", "List "+i18n.StringType)
+
 	ConfigPluginIdentity     = ffc("config.plugins.identity", "The list of available Identity plugins", i18n.StringType)
 	ConfigPluginIdentityType = ffc("config.plugins.identity[].type", "The type of a configured Identity plugin", i18n.StringType)
 	ConfigPluginIdentityName = ffc("config.plugins.identity[].name", "The name of a configured Identity plugin", i18n.StringType)
 
 	ConfigIdentityManagerLegacySystemIdentitites = ffc("config.identity.manager.legacySystemIdentities", "Whether the identity manager should resolve legacy identities registered on the ff_system namespace", i18n.BooleanType)
 
+	ConfigJobsWorkers         = ffc("config.jobs.workers", "The number of jobs each namespace performs at once. Further jobs wait in the pending status", i18n.IntType)
+	ConfigJobsOutputDirectory = ffc("config.jobs.outputDirectory", "The directory the output files of jobs, such as exports, are written to. Defaults to a firefly-jobs directory in the temporary directory of the OS", i18n.StringType)
+
 	ConfigLogCompress   = ffc("config.log.compress", "Determines if the rotated log files should be compressed using gzip", i18n.BooleanType)
 	ConfigLogFilename   = ffc("config.log.filename", "Filename is the file to write logs to.  Backup log files will be retained in the same directory", i18n.StringType)
 	ConfigLogFilesize   = ffc("config.log.filesize", "MaxSize is the maximum size the log file before it gets rotated", i18n.ByteSizeType)
@@ -286,27 +389,86 @@ var (
 	ConfigLogTimeFormat = ffc("config.log.timeFormat", "Custom time format for logs", i18n.TimeFormatType)
 	ConfigLogUtc        = ffc("config.log.utc", "Use UTC timestamps for logs", i18n.BooleanType)
 
+	ConfigMessageExpiryMaskConfirmed   = ffc("config.message.expiry.maskConfirmed", "Hide confirmed messages that are past their expiry time from message queries, unless includeexpired is set on the query", i18n.BooleanType)
 	ConfigMessageWriterBatchMaxInserts = ffc("config.message.writer.batchMaxInserts", "The maximum number of database inserts to include when writing a single batch of messages + data", i18n.IntType)
 	ConfigMessageWriterBatchTimeout    = ffc("config.message.writer.batchTimeout", "How long to wait for more messages to arrive before flushing the batch", i18n.TimeDurationType)
 	ConfigMessageWriterCount           = ffc("config.message.writer.count", "The number of message writer workers", i18n.IntType)
 
-	ConfigMetricsAddress      = ffc("config.metrics.address", "The IP address on which the metrics HTTP API should listen", i18n.IntType)
-	ConfigMetricsEnabled      = ffc("config.metrics.enabled", "Enables the metrics API", i18n.BooleanType)
-	ConfigMetricsPath         = ffc("config.metrics.path", "The path from which to serve the Prometheus metrics", i18n.StringType)
-	ConfigMetricsPort         = ffc("config.metrics.port", "The port on which the metrics HTTP API should listen", i18n.IntType)
-	ConfigMetricsPublicURL    = ffc("config.metrics.publicURL", "The fully qualified public URL for the metrics API. This is used for building URLs in HTTP responses and in OpenAPI Spec generation", "URL "+i18n.StringType)
-	ConfigMetricsReadTimeout  = ffc("config.metrics.readTimeout", "The maximum time to wait when reading from an HTTP connection", i18n.TimeDurationType)
-	ConfigMetricsWriteTimeout = ffc("config.metrics.writeTimeout", "The maximum time to wait when writing to an HTTP connection", i18n.TimeDurationType)
-
-	ConfigNamespacesDefault                    = ffc("config.namespaces.default", "The default namespace - must be in the predefined list", i18n.StringType)
-	ConfigNamespacesPredefined                 = ffc("config.namespaces.predefined", "A list of namespaces to ensure exists, without requiring a broadcast from the network", "List "+i18n.StringType)
-	ConfigNamespacesPredefinedName             = ffc("config.namespaces.predefined[].name", "The name of the namespace (must be unique)", i18n.StringType)
-	ConfigNamespacesPredefinedDescription      = ffc("config.namespaces.predefined[].description", "A description for the namespace", i18n.StringType)
-	ConfigNamespacesPredefinedPlugins          = ffc("config.namespaces.predefined[].plugins", "The list of plugins for this namespace", i18n.StringType)
-	ConfigNamespacesPredefinedDefaultKey       = ffc("config.namespaces.predefined[].defaultKey", "A default signing key for blockchain transactions within this namespace", i18n.StringType)
-	ConfigNamespacesPredefinedKeyNormalization = ffc("config.namespaces.predefined[].asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization", i18n.StringType)
-	ConfigNamespacesPredefinedTLSConfigs       = ffc("config.namespaces.predefined[].tlsConfigs", "Supply a set of tls certificates to be used by subscriptions for this namespace", "List "+i18n.StringType)
-	ConfigNamespacesPredefinedTLSConfigsName   = ffc("config.namespaces.predefined[].tlsConfigs[].name", "Name of the TLS Config", i18n.StringType)
+	ConfigMetricsAddress            = ffc("config.metrics.address", "The IP address on which the metrics HTTP API should listen", i18n.IntType)
+	ConfigMetricsEnabled            = ffc("config.metrics.enabled", "Enables the metrics API", i18n.BooleanType)
+	ConfigMetricsPath               = ffc("config.metrics.path", "The path from which to serve the Prometheus metrics", i18n.StringType)
+	ConfigMetricsSlowRequestsSize   = ffc("config.metrics.slowRequests.size", "The number of the slowest recent requests to the API and admin API that are kept, to be listed on the admin API. Disabled when 0", i18n.IntType)
+	ConfigMetricsSlowRequestsWindow = ffc("config.metrics.slowRequests.window", "How long a request is kept as one of the slowest recent requests", i18n.TimeDurationType)
+	ConfigMetricsPort               = ffc("config.metrics.port", "The port on which the metrics HTTP API should listen", i18n.IntType)
+	ConfigMetricsPublicURL          = ffc("config.metrics.publicURL", "The fully qualified public URL for the metrics API. This is used for building URLs in HTTP responses and in OpenAPI Spec generation", "URL "+i18n.StringType)
+	ConfigMetricsReadTimeout        = ffc("config.metrics.readTimeout", "The maximum time to wait when reading from an HTTP connection", i18n.TimeDurationType)
+	ConfigMetricsWriteTimeout       = ffc("config.metrics.writeTimeout", "The maximum time to wait when writing to an HTTP connection", i18n.TimeDurationType)
+
+	ConfigMtlsRequired           = ffc("config.mtls.required", "Rejects requests to the API that do not present a verified client certificate mapped to an identity. Requires tls.clientAuth to be enabled on the http listener", i18n.BooleanType)
+	ConfigMtlsIdentities         = ffc("config.mtls.identities", "A list of mappings from client certificates to FireFly identities. The first mapping that matches the certificate of a request applies", "List "+i18n.StringType)
+	ConfigMtlsIdentitiesSubject  = ffc("config.mtls.identities[].subject", "A regular expression matched against the subject DN of the certificate, such as `CN=app1,O=Org1`", i18n.StringType)
+	ConfigMtlsIdentitiesSan      = ffc("config.mtls.identities[].san", "A DNS name, email address or URI that must be one of the subject alternative names of the certificate", i18n.StringType)
+	ConfigMtlsIdentitiesIdentity = ffc("config.mtls.identities[].identity", "The identity the certificate maps to. This is the principal of the request, and the default author of messages", i18n.StringType)
+	ConfigMtlsIdentitiesKeys     = ffc("config.mtls.identities[].keys", "The signing keys the identity is allowed to use, the first of which is the default. Any key can be used when empty", "List "+i18n.StringType)
+
+	ConfigTenancyEnabled           = ffc("config.tenancy.enabled", "Enables the tenant routes of the API, and restricts the namespaces that belong to a tenant to the principals bound to it", i18n.BooleanType)
+	ConfigTenancyTenants           = ffc("config.tenancy.tenants", "The list of tenants, each of which can own namespaces with namespaces.predefined[].tenant", "List "+i18n.StringType)
+	ConfigTenancyTenantsName       = ffc("config.tenancy.tenants[].name", "The name of the tenant (must be unique)", i18n.StringType)
+	ConfigTenancyTenantsPrincipals = ffc("config.tenancy.tenants[].principals", "The principals bound to the tenant, which can access its namespaces", "List "+i18n.StringType)
+
+	ConfigHardeningHeadersEnabled                 = ffc("config.hardening.headers.enabled", "Adds the security headers to every response of the API and admin HTTP listeners", i18n.BooleanType)
+	ConfigHardeningHeadersContentTypeOptions      = ffc("config.hardening.headers.contentTypeOptions", "The value of the X-Content-Type-Options header. The header is not set when empty", i18n.StringType)
+	ConfigHardeningHeadersFrameOptions            = ffc("config.hardening.headers.frameOptions", "The value of the X-Frame-Options header. The header is not set when empty", i18n.StringType)
+	ConfigHardeningHeadersReferrerPolicy          = ffc("config.hardening.headers.referrerPolicy", "The value of the Referrer-Policy header. The header is not set when empty", i18n.StringType)
+	ConfigHardeningHeadersContentSecurityPolicy   = ffc("config.hardening.headers.contentSecurityPolicy", "The value of the Content-Security-Policy header. The header is not set when empty", i18n.StringType)
+	ConfigHardeningHeadersStrictTransportSecurity = ffc("config.hardening.headers.strictTransportSecurity", "The value of the Strict-Transport-Security header. The header is not set when empty", i18n.StringType)
+	ConfigHardeningCorsRules                      = ffc("config.hardening.cors.rules", "CORS rules for paths of the API and admin HTTP listeners. The first rule that matches the path of a request applies in place of the cors section", "List "+i18n.StringType)
+	ConfigHardeningCorsRulesPaths                 = ffc("config.hardening.cors.rules[].paths", "The path prefixes the rule applies to, such as `/api/v1/namespaces/*/tokens`, where a * matches any one segment of the path", "List "+i18n.StringType)
+	ConfigHardeningCorsRulesOrigins               = ffc("config.hardening.cors.rules[].origins", "The origins allowed to make requests to the paths", "List "+i18n.StringType)
+	ConfigHardeningCorsRulesMethods               = ffc("config.hardening.cors.rules[].methods", "The methods allowed in requests to the paths", "List "+i18n.StringType)
+	ConfigHardeningCorsRulesHeaders               = ffc("config.hardening.cors.rules[].headers", "The headers allowed in requests to the paths", "List "+i18n.StringType)
+	ConfigHardeningCorsRulesExposedHeaders        = ffc("config.hardening.cors.rules[].exposedHeaders", "The response headers that browsers may read", "List "+i18n.StringType)
+	ConfigHardeningCorsRulesCredentials           = ffc("config.hardening.cors.rules[].credentials", "Allows requests to the paths to include credentials", i18n.BooleanType)
+	ConfigHardeningCorsRulesMaxAge                = ffc("config.hardening.cors.rules[].maxAge", "The number of seconds browsers may cache the result of a preflight request", i18n.IntType)
+
+	ConfigNamespacesDefault                               = ffc("config.namespaces.default", "The default namespace - must be in the predefined list", i18n.StringType)
+	ConfigNamespacesPredefined                            = ffc("config.namespaces.predefined", "A list of namespaces to ensure exists, without requiring a broadcast from the network", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedName                        = ffc("config.namespaces.predefined[].name", "The name of the namespace (must be unique)", i18n.StringType)
+	ConfigNamespacesPredefinedDescription                 = ffc("config.namespaces.predefined[].description", "A description for the namespace", i18n.StringType)
+	ConfigNamespacesPredefinedTenant                      = ffc("config.namespaces.predefined[].tenant", "The tenant the namespace belongs to. When tenancy is enabled, only the principals bound to the tenant can access the namespace", i18n.StringType)
+	ConfigNamespacesPredefinedPlugins                     = ffc("config.namespaces.predefined[].plugins", "The list of plugins for this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedDefaultKey                  = ffc("config.namespaces.predefined[].defaultKey", "A default signing key for blockchain transactions within this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedConfirmations               = ffc("config.namespaces.predefined[].confirmations", "The default number of block confirmations the blockchain and token connectors should wait for before delivering events to this namespace, which can be overridden on individual contract listeners and token pools. Blockchain operations are also held as Pending until their transaction has this many confirmations. Zero uses the connector default", i18n.IntType)
+	ConfigNamespacesPredefinedKeyNormalization            = ffc("config.namespaces.predefined[].asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization", i18n.StringType)
+	ConfigNamespacesPredefinedTLSConfigs                  = ffc("config.namespaces.predefined[].tlsConfigs", "Supply a set of tls certificates to be used by subscriptions for this namespace", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedTLSConfigsName              = ffc("config.namespaces.predefined[].tlsConfigs[].name", "Name of the TLS Config", i18n.StringType)
+	ConfigNamespacesPredefinedBridges                     = ffc("config.namespaces.predefined[].bridges", "A list of bridges that re-publish the confirmed messages of this namespace into other namespaces on this node", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedBridgesName                 = ffc("config.namespaces.predefined[].bridges[].name", "The name of the bridge (must be unique within the namespace)", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesTarget               = ffc("config.namespaces.predefined[].bridges[].target", "The multiparty namespace to publish the messages into", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesFirstEvent           = ffc("config.namespaces.predefined[].bridges[].firstEvent", "The first event the bridge processes when it is first started. Valid options are `newest` or `oldest`", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesMembers              = ffc("config.namespaces.predefined[].bridges[].members", "The identities to send the messages to privately in the target namespace. The messages are broadcast when empty", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedBridgesFilterTopics         = ffc("config.namespaces.predefined[].bridges[].filter.topics", "A regular expression that at least one topic of a message must match for it to be bridged", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesFilterTag            = ffc("config.namespaces.predefined[].bridges[].filter.tag", "A regular expression that the tag of a message must match for it to be bridged", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesFilterAuthor         = ffc("config.namespaces.predefined[].bridges[].filter.author", "A regular expression that the author of a message must match for it to be bridged", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesFilterIncludePrivate = ffc("config.namespaces.predefined[].bridges[].filter.includePrivate", "Whether to bridge private messages, as well as broadcast messages", i18n.BooleanType)
+	ConfigNamespacesPredefinedBridgesTopicMap             = ffc("config.namespaces.predefined[].bridges[].topicMap", "A list of topics to replace when publishing into the target namespace", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedBridgesTopicMapSource       = ffc("config.namespaces.predefined[].bridges[].topicMap[].source", "The topic in this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesTopicMapTarget       = ffc("config.namespaces.predefined[].bridges[].topicMap[].target", "The topic to use in the target namespace", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesIdentityMap          = ffc("config.namespaces.predefined[].bridges[].identityMap", "A list of authors to replace when publishing into the target namespace. Messages from other authors are sent as the root org of the target namespace", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedBridgesIdentityMapSource    = ffc("config.namespaces.predefined[].bridges[].identityMap[].source", "The DID of the author in this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedBridgesIdentityMapTarget    = ffc("config.namespaces.predefined[].bridges[].identityMap[].target", "The identity to send as in the target namespace", i18n.StringType)
+	ConfigNamespacesPredefinedTransforms                  = ffc("config.namespaces.predefined[].transforms", "A list of transforms that run, in order, on the data of the messages of this namespace before they are sent, and in reverse order when they are received", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedTransformsPlugin            = ffc("config.namespaces.predefined[].transforms[].plugin", "The type of transform plugin to run. Valid options are `tokenize` or `upcast`", i18n.StringType)
+	ConfigNamespacesPredefinedTransformsTopics            = ffc("config.namespaces.predefined[].transforms[].topics", "The topics of the messages the transform runs on. The transform runs on all topics when empty", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedTransformsOptions           = ffc("config.namespaces.predefined[].transforms[].options", "Plugin-specific options for the transform", i18n.StringType)
+	ConfigNamespacesPredefinedBlobScannerPlugin           = ffc("config.namespaces.predefined[].blobScanner.plugin", "The type of blob scanner plugin that checks the content of the blobs uploaded to this node and received from other nodes. Valid options are `clamav`. Blobs are not scanned when empty", i18n.StringType)
+	ConfigNamespacesPredefinedBlobScannerOptions          = ffc("config.namespaces.predefined[].blobScanner.options", "Plugin-specific options for the blob scanner", i18n.StringType)
+	ConfigNamespacesPredefinedDeferredConfirmEnabled      = ffc("config.namespaces.predefined[].deferredConfirm.enabled", "Holds the messages received from other nodes in the pending_app state, until the local application accepts or rejects them", i18n.BooleanType)
+	ConfigNamespacesPredefinedDeferredConfirmTopics       = ffc("config.namespaces.predefined[].deferredConfirm.topics", "The topics of the messages that are held. All topics are held when empty", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedSLAConfirmTime              = ffc("config.namespaces.predefined[].sla.confirmTime", "The time within which the messages sent by this node are expected to be confirmed, for topics without their own SLA. An SLA of zero is not tracked", i18n.TimeDurationType)
+	ConfigNamespacesPredefinedSLATopics                   = ffc("config.namespaces.predefined[].sla.topics", "A list of topics with their own SLA", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedSLATopicsTopic              = ffc("config.namespaces.predefined[].sla.topics[].topic", "The topic the SLA applies to", i18n.StringType)
+	ConfigNamespacesPredefinedSLATopicsConfirmTime        = ffc("config.namespaces.predefined[].sla.topics[].confirmTime", "The time within which the messages on the topic are expected to be confirmed. An SLA of zero is not tracked", i18n.TimeDurationType)
 	// ConfigNamespacesPredefinedTLSConfigsTLS      = ffc("config.namespaces.predefined[].tlsConfigs[].tls", "Specify the path to a CA, Cert and Key for TLS communication", i18n.StringType)
 	ConfigNamespacesMultipartyEnabled            = ffc("config.namespaces.predefined[].multiparty.enabled", "Enables multi-party mode for this namespace (defaults to true if an org name or key is configured, either here or at the root level)", i18n.BooleanType)
 	ConfigNamespacesMultipartyNetworkNamespace   = ffc("config.namespaces.predefined[].multiparty.networknamespace", "The shared namespace name to be sent in multiparty messages, if it differs from the local namespace name", i18n.StringType)
@@ -319,14 +481,16 @@ var (
 	ConfigNamespacesMultipartyContractFirstEvent = ffc("config.namespaces.predefined[].multiparty.contract[].firstEvent", "The first event the contract should process. Valid options are `oldest` or `newest`", i18n.StringType)
 	ConfigNamespacesMultipartyContractLocation   = ffc("config.namespaces.predefined[].multiparty.contract[].location", "A blockchain-specific contract location. For example, an Ethereum contract address, or a Fabric chaincode name and channel", i18n.StringType)
 	ConfigNamespacesMultipartyContractOptions    = ffc("config.namespaces.predefined[].multiparty.contract[].options", "Blockchain-specific contract options", i18n.StringType)
+	ConfigNamespacesMultipartyContractChainID    = ffc("config.namespaces.predefined[].multiparty.contract[].chainId", "The expected chain ID of the blockchain the contract is deployed to. When set, the chain ID reported by the connector is verified on startup and on every reconnect, and events are not processed if it does not match", i18n.StringType)
 
 	ConfigNodeDescription = ffc("config.node.description", "The description of this FireFly node", i18n.StringType)
 	ConfigNodeName        = ffc("config.node.name", "The name of this FireFly node", i18n.StringType)
 
-	ConfigOpupdateWorkerBatchMaxInserts = ffc("config.opupdate.worker.batchMaxInserts", "The maximum number of database inserts to include when writing a single batch of messages + data", i18n.IntType)
-	ConfigOpupdateWorkerBatchTimeout    = ffc("config.opupdate.worker.batchTimeout", "How long to wait for more messages to arrive before flushing the batch", i18n.TimeDurationType)
-	ConfigOpupdateWorkerCount           = ffc("config.opupdate.worker.count", "The number of operation update works", i18n.IntType)
-	ConfigOpupdateWorkerQueueLength     = ffc("config.opupdate.worker.queueLength", "The size of the queue for the Operation Update worker", i18n.IntType)
+	ConfigOpupdateConfirmationsPollInterval = ffc("config.opupdate.confirmations.pollInterval", "How often to check the chain head, while blockchain operations are waiting for the confirmations configured on their namespace before being marked succeeded", i18n.TimeDurationType)
+	ConfigOpupdateWorkerBatchMaxInserts     = ffc("config.opupdate.worker.batchMaxInserts", "The maximum number of database inserts to include when writing a single batch of messages + data", i18n.IntType)
+	ConfigOpupdateWorkerBatchTimeout        = ffc("config.opupdate.worker.batchTimeout", "How long to wait for more messages to arrive before flushing the batch", i18n.TimeDurationType)
+	ConfigOpupdateWorkerCount               = ffc("config.opupdate.worker.count", "The number of operation update works", i18n.IntType)
+	ConfigOpupdateWorkerQueueLength         = ffc("config.opupdate.worker.queueLength", "The size of the queue for the Operation Update worker", i18n.IntType)
 
 	ConfigOrchestratorStartupAttempts = ffc("config.orchestrator.startupAttempts", "The number of times to attempt to connect to core infrastructure on startup", i18n.StringType)
 
@@ -334,10 +498,34 @@ var (
 	ConfigOrgKey         = ffc("config.org.key", "The signing key allocated to the organization (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
 	ConfigOrgName        = ffc("config.org.name", "The name of the organization to which this FireFly node belongs (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
 
-	ConfigPrivatemessagingBatchAgentTimeout = ffc("config.privatemessaging.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.TimeDurationType)
-	ConfigPrivatemessagingBatchPayloadLimit = ffc("config.privatemessaging.batch.payloadLimit", "The maximum payload size of a private message Data Exchange payload", i18n.ByteSizeType)
-	ConfigPrivatemessagingBatchSize         = ffc("config.privatemessaging.batch.size", "The maximum number of messages in a batch for private messages", i18n.IntType)
-	ConfigPrivatemessagingBatchTimeout      = ffc("config.privatemessaging.batch.timeout", "The timeout to wait for a batch to fill, before sending", i18n.TimeDurationType)
+	ConfigPrivatemessagingBatchAgentTimeout        = ffc("config.privatemessaging.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.TimeDurationType)
+	ConfigPrivatemessagingBatchPayloadLimit        = ffc("config.privatemessaging.batch.payloadLimit", "The maximum payload size of a private message Data Exchange payload", i18n.ByteSizeType)
+	ConfigPrivatemessagingBatchSize                = ffc("config.privatemessaging.batch.size", "The maximum number of messages in a batch for private messages", i18n.IntType)
+	ConfigPrivatemessagingBatchTimeout             = ffc("config.privatemessaging.batch.timeout", "The timeout to wait for a batch to fill, before sending", i18n.TimeDurationType)
+	ConfigPrivatemessagingDisclosureEnabled        = ffc("config.privatemessaging.disclosure.enabled", "Whether to include a Merkle root of the salted hashes of the fields of each data item in the pinned private batches sent by this node, so that members can later prove individual fields to a third party", i18n.BooleanType)
+	ConfigPrivatemessagingEncryptionEnabled        = ffc("config.privatemessaging.encryption.enabled", "Whether to encrypt the data of private messages sent by this node to the encryption key of each member node, before passing it to data exchange", i18n.BooleanType)
+	ConfigPrivatemessagingEncryptionPrivateKey     = ffc("config.privatemessaging.encryption.privateKey", "The base64 encoded X25519 private key this node uses to decrypt the data of private messages it receives. The public key is added to the profile of the node when it is registered", i18n.StringType)
+	ConfigPrivatemessagingInboundAction            = ffc("config.privatemessaging.inbound.action", "What to do with the private batches received from an org that is over the message rate - 'throttle' to delay processing them (quarantining any beyond one burst over the rate), or 'quarantine' to hold them for an administrator to release", i18n.StringType)
+	ConfigPrivatemessagingInboundBurst             = ffc("config.privatemessaging.inbound.burst", "The number of messages an org can send at once above the sustained rate. Defaults to the messages per second", i18n.IntType)
+	ConfigPrivatemessagingInboundDailyQuota        = ffc("config.privatemessaging.inbound.dailyQuota", "The total size of the private batches received from each org per UTC day. Batches over the quota are quarantined. 0 for no quota", i18n.ByteSizeType)
+	ConfigPrivatemessagingInboundMessagesPerSecond = ffc("config.privatemessaging.inbound.messagesPerSecond", "The sustained number of private messages per second received from each org, counted across the messages in each batch. 0 for no limit", i18n.FloatType)
+	ConfigPrivatemessagingReceiptsDelivery         = ffc("config.privatemessaging.receipts.delivery", "Whether to send a delivery receipt back to the sending node, for each private message confirmed by this node", i18n.BooleanType)
+
+	ConfigRbacEnabled         = ffc("config.rbac.enabled", "Enforces the roles bound to principals in each namespace on the namespaced routes of the API and the gRPC API", i18n.BooleanType)
+	ConfigRbacPrincipalHeader = ffc("config.rbac.principalHeader", "An HTTP header set by a trusted authenticating proxy to the principal of each request. The username of HTTP basic auth is used when not set", i18n.StringType)
+
+	ConfigRatelimitEnabled                    = ffc("config.ratelimit.enabled", "Enforces rate limits and daily quotas for each principal and namespace on the API, rejecting requests over the limits with a 429 status", i18n.BooleanType)
+	ConfigRatelimitPrincipalRequestsPerSecond = ffc("config.ratelimit.principal.requestsPerSecond", "The sustained number of requests per second allowed for each principal. Requests with no principal are limited by client address. 0 for no limit", i18n.FloatType)
+	ConfigRatelimitPrincipalBurst             = ffc("config.ratelimit.principal.burst", "The number of requests a principal can make at once above the sustained rate. Defaults to the requests per second", i18n.IntType)
+	ConfigRatelimitPrincipalDailyQuota        = ffc("config.ratelimit.principal.dailyQuota", "The number of requests allowed for each principal per UTC day. 0 for no quota", i18n.IntType)
+	ConfigRatelimitNamespaceRequestsPerSecond = ffc("config.ratelimit.namespace.requestsPerSecond", "The sustained number of requests per second allowed to each namespace, across all principals. 0 for no limit", i18n.FloatType)
+	ConfigRatelimitNamespaceBurst             = ffc("config.ratelimit.namespace.burst", "The number of requests a namespace can take at once above the sustained rate. Defaults to the requests per second", i18n.IntType)
+	ConfigRatelimitNamespaceDailyQuota        = ffc("config.ratelimit.namespace.dailyQuota", "The number of requests allowed to each namespace per UTC day, across all principals. 0 for no quota", i18n.IntType)
+	ConfigConnlimitEnabled                    = ffc("config.connlimit.enabled", "Enforces limits on the concurrent HTTP requests and WebSocket connections for each listener and principal on the API and SPI, shedding those over the limits", i18n.BooleanType)
+	ConfigConnlimitListenerMaxRequests        = ffc("config.connlimit.listener.maxRequests", "The maximum number of concurrent HTTP requests on each listener. Requests over the limit are rejected with a 503 status. 0 for no limit", i18n.IntType)
+	ConfigConnlimitListenerMaxWebSockets      = ffc("config.connlimit.listener.maxWebSockets", "The maximum number of concurrent WebSocket connections on each listener. Connections over the limit are closed with a 1013 (try again later) close frame. 0 for no limit", i18n.IntType)
+	ConfigConnlimitPrincipalMaxRequests       = ffc("config.connlimit.principal.maxRequests", "The maximum number of concurrent HTTP requests for each principal. Requests with no principal are limited by client address. 0 for no limit", i18n.IntType)
+	ConfigConnlimitPrincipalMaxWebSockets     = ffc("config.connlimit.principal.maxWebSockets", "The maximum number of concurrent WebSocket connections for each principal. Connections with no principal are limited by client address. 0 for no limit", i18n.IntType)
 
 	ConfigSharedstorageType                = ffc("config.sharedstorage.type", "The Shared Storage plugin to use", i18n.StringType)
 	ConfigSharedstorageIpfsAPIURL          = ffc("config.sharedstorage.ipfs.api.url", "The URL for the IPFS API", "URL "+i18n.StringType)
@@ -353,8 +541,9 @@ var (
 	ConfigPluginSharedstorageIpfsGatewayURL      = ffc("config.plugins.sharedstorage[].ipfs.gateway.url", "The URL for the IPFS Gateway", "URL "+i18n.StringType)
 	ConfigPluginSharedstorageIpfsGatewayProxyURL = ffc("config.plugins.sharedstorage[].ipfs.gateway.proxy.url", "Optional HTTP proxy server to use when connecting to the IPFS Gateway", "URL "+i18n.StringType)
 
-	ConfigSubscriptionMax               = ffc("config.subscription.max", "The maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)", i18n.IntType)
-	ConfigSubscriptionDefaultsBatchSize = ffc("config.subscription.defaults.batchSize", "Default read ahead to enable for subscriptions that do not explicitly configure readahead", i18n.IntType)
+	ConfigSubscriptionMax                  = ffc("config.subscription.max", "The maximum number of pre-defined subscriptions that can exist (note for high fan-out consider connecting a dedicated pub/sub broker to the dispatcher)", i18n.IntType)
+	ConfigSubscriptionDefaultsBatchSize    = ffc("config.subscription.defaults.batchSize", "Default read ahead to enable for subscriptions that do not explicitly configure readahead", i18n.IntType)
+	ConfigSubscriptionDefaultsBatchTimeout = ffc("config.subscription.defaults.batchTimeout", "Default time to wait for a batch to fill, for subscriptions that deliver events in batches without a timeout", i18n.TimeDurationType)
 
 	ConfigTokensName     = ffc("config.tokens[].name", "A name to identify this token plugin", i18n.StringType)
 	ConfigTokensPlugin   = ffc("config.tokens[].plugin", "The type of the token plugin to use", i18n.StringType)
@@ -372,6 +561,18 @@ var (
 	ConfigPluginTokensBackgroundStartMaxDelay     = ffc("config.plugins.tokens[].fftokens.backgroundStart.maxDelay", "Max delay between restarts in the case where we retry to restart the token plugin", i18n.TimeDurationType)
 	ConfigPluginTokensBackgroundStartFactor       = ffc("config.plugins.tokens[].fftokens.backgroundStart.factor", "Set the factor by which the delay increases when retrying", i18n.FloatType)
 
+	ConfigPluginTokensAccountAbstractionSmartAccounts = ffc("config.plugins.tokens[].fftokens.accountAbstraction.smartAccounts", "A list of signing keys that are ERC-4337 smart accounts. Token operations signed by these keys are submitted by the token connector as UserOperations through a bundler", i18n.ArrayStringType)
+	ConfigPluginTokensAccountAbstractionEntryPoint    = ffc("config.plugins.tokens[].fftokens.accountAbstraction.entryPoint", "The address of the ERC-4337 EntryPoint contract", i18n.StringType)
+	ConfigPluginTokensAccountAbstractionPaymaster     = ffc("config.plugins.tokens[].fftokens.accountAbstraction.paymaster", "The address of a paymaster contract to sponsor the gas for UserOperations, enabling gasless token operations", i18n.StringType)
+
+	ConfigPluginTokensHTSURL                         = ffc("config.plugins.tokens[].hts.url", "The URL of the token connector for the Hedera Token Service", "URL "+i18n.StringType)
+	ConfigPluginTokensHTSProxyURL                    = ffc("config.plugins.tokens[].hts.proxy.url", "Optional HTTP proxy server to use when connecting to the token connector", "URL "+i18n.StringType)
+	ConfigPluginTokensHTSAutoAssociate               = ffc("config.plugins.tokens[].hts.autoAssociate", "Associate the recipient account with the token before minting or transferring to it, which requires the connector to hold the key of the recipient", i18n.BooleanType)
+	ConfigPluginTokensHTSBackgroundStart             = ffc("config.plugins.tokens[].hts.backgroundStart.enabled", "Start the tokens plugin in the background and enter retry loop if failed to start", i18n.BooleanType)
+	ConfigPluginTokensHTSBackgroundStartInitialDelay = ffc("config.plugins.tokens[].hts.backgroundStart.initialDelay", "Delay between restarts in the case where we retry to restart the token plugin", i18n.TimeDurationType)
+	ConfigPluginTokensHTSBackgroundStartMaxDelay     = ffc("config.plugins.tokens[].hts.backgroundStart.maxDelay", "Max delay between restarts in the case where we retry to restart the token plugin", i18n.TimeDurationType)
+	ConfigPluginTokensHTSBackgroundStartFactor       = ffc("config.plugins.tokens[].hts.backgroundStart.factor", "Set the factor by which the delay increases when retrying", i18n.FloatType)
+
 	ConfigUIEnabled = ffc("config.ui.enabled", "Enables the web user interface", i18n.BooleanType)
 	ConfigUIPath    = ffc("config.ui.path", "The file system path which contains the static HTML, CSS, and JavaScript files for the user interface", i18n.StringType)
 
@@ -384,8 +585,69 @@ var (
 	ConfigPluginsAuthName = ffc("config.plugins.auth[].name", "The name of the auth plugin to use", i18n.StringType)
 	ConfigPluginsAuthType = ffc("config.plugins.auth[].type", "The type of the auth plugin to use", i18n.StringType)
 
-	ConfigPluginsEventSystemReadAhead           = ffc("config.events.system.readAhead", "", i18n.IgnoredType)
-	ConfigPluginsEventWebhooksURL               = ffc("config.events.webhooks.url", "", i18n.IgnoredType)
-	ConfigPluginsEventWebSocketsReadBufferSize  = ffc("config.events.websockets.readBufferSize", "WebSocket read buffer size", i18n.ByteSizeType)
-	ConfigPluginsEventWebSocketsWriteBufferSize = ffc("config.events.websockets.writeBufferSize", "WebSocket write buffer size", i18n.ByteSizeType)
+	ConfigPluginsEventAMQPURL                   = ffc("config.events.amqp.url", "The amqp:// or amqps:// URL of the AMQP 1.0 broker to send events to, such as RabbitMQ or Azure Service Bus", "URL "+i18n.StringType)
+	ConfigPluginsEventAMQPUsername              = ffc("config.events.amqp.username", "The username for SASL PLAIN authentication with the broker", i18n.StringType)
+	ConfigPluginsEventAMQPPassword              = ffc("config.events.amqp.password", "The password for SASL PLAIN authentication with the broker", i18n.StringType)
+	ConfigPluginsEventAMQPQueue                 = ffc("config.events.amqp.queue", "The queue to send events to, for subscriptions that do not set a queue option", i18n.StringType)
+	ConfigPluginsEventAMQPSendTimeout           = ffc("config.events.amqp.sendTimeout", "How long to wait for the broker to accept each event, before it is redelivered", i18n.TimeDurationType)
+	ConfigPluginsEventAMQPReconnectInitialDelay = ffc("config.events.amqp.reconnect.initialDelay", "The delay before the first attempt to reconnect to the broker", i18n.TimeDurationType)
+	ConfigPluginsEventAMQPReconnectMaxDelay     = ffc("config.events.amqp.reconnect.maxDelay", "The maximum delay between attempts to reconnect to the broker", i18n.TimeDurationType)
+	ConfigPluginsEventAMQPReconnectFactor       = ffc("config.events.amqp.reconnect.factor", "The factor the delay increases by on each attempt to reconnect to the broker", i18n.FloatType)
+	ConfigPluginsEventAMQPReconnectMaxAttempts  = ffc("config.events.amqp.reconnect.maxAttempts", "The number of attempts to connect to the broker for each event. If they all fail, the event is rejected so that it is redelivered later", i18n.IntType)
+
+	ConfigPluginsEventMQTTURL                   = ffc("config.events.mqtt.url", "The mqtt:// or mqtts:// URL of the MQTT 5 broker to publish events to", "URL "+i18n.StringType)
+	ConfigPluginsEventMQTTClientID              = ffc("config.events.mqtt.clientId", "The client identifier to connect to the broker with. Defaults to a generated identifier", i18n.StringType)
+	ConfigPluginsEventMQTTUsername              = ffc("config.events.mqtt.username", "The username to connect to the broker with", i18n.StringType)
+	ConfigPluginsEventMQTTPassword              = ffc("config.events.mqtt.password", "The password to connect to the broker with", i18n.StringType)
+	ConfigPluginsEventMQTTKeepAlive             = ffc("config.events.mqtt.keepAlive", "The keep alive interval to request from the broker", i18n.TimeDurationType)
+	ConfigPluginsEventMQTTTopicPrefix           = ffc("config.events.mqtt.topicPrefix", "The prefix of the topic for subscriptions that do not set a topic option. Events are published to <prefix>/<namespace>/<subscription name>", i18n.StringType)
+	ConfigPluginsEventMQTTRetainStatus          = ffc("config.events.mqtt.retainStatus", "Whether to publish a retained message with the last event delivered to the <topic>/status topic of each subscription", i18n.BooleanType)
+	ConfigPluginsEventMQTTPublishTimeout        = ffc("config.events.mqtt.publishTimeout", "How long to wait for the broker to acknowledge each event, before it is redelivered", i18n.TimeDurationType)
+	ConfigPluginsEventMQTTReconnectInitialDelay = ffc("config.events.mqtt.reconnect.initialDelay", "The delay before the first attempt to reconnect to the broker", i18n.TimeDurationType)
+	ConfigPluginsEventMQTTReconnectMaxDelay     = ffc("config.events.mqtt.reconnect.maxDelay", "The maximum delay between attempts to reconnect to the broker", i18n.TimeDurationType)
+	ConfigPluginsEventMQTTReconnectFactor       = ffc("config.events.mqtt.reconnect.factor", "The factor the delay increases by on each attempt to reconnect to the broker", i18n.FloatType)
+
+	ConfigPluginsEventGRPCAddress     = ffc("config.events.grpc.address", "The local address to accept gRPC event streams on", i18n.StringType)
+	ConfigPluginsEventGRPCPort        = ffc("config.events.grpc.port", "The port to accept gRPC event streams on", i18n.IntType)
+	ConfigPluginsEventSSEPingInterval = ffc("config.events.sse.pingInterval", "How often a comment is sent on an idle SSE connection, to keep proxies from closing it", i18n.TimeDurationType)
+
+	ConfigPluginsEventKafkaURL                        = ffc("config.events.kafka.url", "The URL of the Kafka REST Proxy to write events through", "URL "+i18n.StringType)
+	ConfigPluginsEventKafkaProxyURL                   = ffc("config.events.kafka.proxy.url", "Optional HTTP proxy server to use when connecting to the Kafka REST Proxy", "URL "+i18n.StringType)
+	ConfigPluginsEventKafkaTopic                      = ffc("config.events.kafka.topic", "The Kafka topic to write events to, for subscriptions that do not set a topic option", i18n.StringType)
+	ConfigPluginsEventKafkaPartitionKey               = ffc("config.events.kafka.partitionKey", "The field used as the key of each record, for subscriptions that do not set a partitionKey option. Events with the same key are kept in order on one partition - topic, group or author", i18n.StringType)
+	ConfigPluginsEventSQSURL                          = ffc("config.events.sqs.url", "Optional endpoint URL that overrides the regional endpoint of SQS", "URL "+i18n.StringType)
+	ConfigPluginsEventSQSProxyURL                     = ffc("config.events.sqs.proxy.url", "Optional HTTP proxy server to use when connecting to SQS", "URL "+i18n.StringType)
+	ConfigPluginsEventSQSRegion                       = ffc("config.events.sqs.region", "The AWS region of the queues. Defaults to the AWS_REGION environment variable", i18n.StringType)
+	ConfigPluginsEventSQSAccessKeyID                  = ffc("config.events.sqs.accessKeyId", "The access key ID of the IAM user or role. Defaults to the AWS_ACCESS_KEY_ID environment variable", i18n.StringType)
+	ConfigPluginsEventSQSSecretAccessKey              = ffc("config.events.sqs.secretAccessKey", "The secret access key of the IAM user or role. Defaults to the AWS_SECRET_ACCESS_KEY environment variable", i18n.StringType)
+	ConfigPluginsEventSQSSessionToken                 = ffc("config.events.sqs.sessionToken", "The session token for temporary security credentials. Defaults to the AWS_SESSION_TOKEN environment variable", i18n.StringType)
+	ConfigPluginsEventSQSQueueURL                     = ffc("config.events.sqs.queueUrl", "The URL of the SQS queue to send events to, for subscriptions that do not set a queueUrl option", i18n.StringType)
+	ConfigPluginsEventSNSURL                          = ffc("config.events.sns.url", "Optional endpoint URL that overrides the regional endpoint of SNS", "URL "+i18n.StringType)
+	ConfigPluginsEventSNSProxyURL                     = ffc("config.events.sns.proxy.url", "Optional HTTP proxy server to use when connecting to SNS", "URL "+i18n.StringType)
+	ConfigPluginsEventSNSRegion                       = ffc("config.events.sns.region", "The AWS region of the topics. Defaults to the AWS_REGION environment variable", i18n.StringType)
+	ConfigPluginsEventSNSAccessKeyID                  = ffc("config.events.sns.accessKeyId", "The access key ID of the IAM user or role. Defaults to the AWS_ACCESS_KEY_ID environment variable", i18n.StringType)
+	ConfigPluginsEventSNSSecretAccessKey              = ffc("config.events.sns.secretAccessKey", "The secret access key of the IAM user or role. Defaults to the AWS_SECRET_ACCESS_KEY environment variable", i18n.StringType)
+	ConfigPluginsEventSNSSessionToken                 = ffc("config.events.sns.sessionToken", "The session token for temporary security credentials. Defaults to the AWS_SESSION_TOKEN environment variable", i18n.StringType)
+	ConfigPluginsEventSNSTopicARN                     = ffc("config.events.sns.topicArn", "The ARN of the SNS topic to publish events to, for subscriptions that do not set a topicArn option", i18n.StringType)
+	ConfigPluginsEventPubSubURL                       = ffc("config.events.pubsub.url", "Optional endpoint URL that overrides the public endpoint of Google Cloud Pub/Sub", "URL "+i18n.StringType)
+	ConfigPluginsEventPubSubProxyURL                  = ffc("config.events.pubsub.proxy.url", "Optional HTTP proxy server to use when connecting to Google Cloud Pub/Sub", "URL "+i18n.StringType)
+	ConfigPluginsEventPubSubProject                   = ffc("config.events.pubsub.project", "The Google Cloud project of topics that are not qualified with a project. Defaults to the project of the service account, or the GOOGLE_CLOUD_PROJECT environment variable", i18n.StringType)
+	ConfigPluginsEventPubSubTopic                     = ffc("config.events.pubsub.topic", "The Pub/Sub topic to publish events to, for subscriptions that do not set a topic option", i18n.StringType)
+	ConfigPluginsEventPubSubCredentialsFile           = ffc("config.events.pubsub.credentialsFile", "A JSON service account key file used to obtain access tokens. When not set, the token of the service account attached to the instance is requested from the metadata server", i18n.StringType)
+	ConfigPluginsEventPubSubMetadataURL               = ffc("config.events.pubsub.metadataUrl", "The URL of the Google Cloud metadata server", "URL "+i18n.StringType)
+	ConfigPluginsEventPullAckTimeout                  = ffc("config.events.pull.ackTimeout", "How long a pulled event can go without being acknowledged, before it can be pulled again", i18n.TimeDurationType)
+	ConfigPluginsEventPullMaxWait                     = ffc("config.events.pull.maxWait", "The longest a pull request can wait for events to arrive. Must be less than the request timeout of the API", i18n.TimeDurationType)
+	ConfigPluginsEventPullReadAhead                   = ffc("config.events.pull.readAhead", "The number of events buffered for pulling, for subscriptions that do not set a readAhead option. This is the largest batch a consumer can pull at once", i18n.IntType)
+	ConfigPluginsEventSystemReadAhead                 = ffc("config.events.system.readAhead", "", i18n.IgnoredType)
+	ConfigPluginsEventWebhooksURL                     = ffc("config.events.webhooks.url", "", i18n.IgnoredType)
+	ConfigPluginsEventWebSocketsCompression           = ffc("config.events.websockets.compression", "Whether to negotiate permessage-deflate compression with WebSocket clients that support it", i18n.BooleanType)
+	ConfigPluginsEventWebSocketsCompressionLevel      = ffc("config.events.websockets.compressionLevel", "The deflate compression level, from -2 (Huffman only) to 9 (best compression)", i18n.IntType)
+	ConfigPluginsEventWebSocketsJWTSecret             = ffc("config.events.websockets.jwt.secret", "The shared secret used to verify HS256, HS384 and HS512 signed JWT bearer tokens on WebSocket connections. JWT authentication is enabled when either a secret or a public key is configured", i18n.StringType)
+	ConfigPluginsEventWebSocketsJWTPublicKeyFile      = ffc("config.events.websockets.jwt.publicKeyFile", "A PEM file containing the RSA public key used to verify RS256, RS384 and RS512 signed JWT bearer tokens on WebSocket connections", i18n.StringType)
+	ConfigPluginsEventWebSocketsJWTIssuer             = ffc("config.events.websockets.jwt.issuer", "If set, the 'iss' claim of the token must match this issuer", i18n.StringType)
+	ConfigPluginsEventWebSocketsJWTAudience           = ffc("config.events.websockets.jwt.audience", "If set, the 'aud' claim of the token must include this audience", i18n.StringType)
+	ConfigPluginsEventWebSocketsJWTNamespacesClaim    = ffc("config.events.websockets.jwt.namespacesClaim", "The claim listing the namespaces the connection can start subscriptions in, or '*' for all namespaces", i18n.StringType)
+	ConfigPluginsEventWebSocketsJWTSubscriptionsClaim = ffc("config.events.websockets.jwt.subscriptionsClaim", "The claim listing the durable subscriptions the connection can start, as names or namespace:name pairs, or '*' for all subscriptions including ephemeral ones. All subscriptions in the permitted namespaces can be started if the claim is not in the token", i18n.StringType)
+	ConfigPluginsEventWebSocketsReadBufferSize        = ffc("config.events.websockets.readBufferSize", "WebSocket read buffer size", i18n.ByteSizeType)
+	ConfigPluginsEventWebSocketsWriteBufferSize       = ffc("config.events.websockets.writeBufferSize", "WebSocket write buffer size", i18n.ByteSizeType)
 )
@@ -292,4 +292,242 @@ var (
 	MsgInvalidMessageIdentity             = ffe("FF10453", "Invalid message '%s'. Author '%s' does not match identity registered to %s: %s (%s)")
 	MsgDuplicateTLSConfig                 = ffe("FF10454", "Found duplicate TLS Config '%s'", 400)
 	MsgNotFoundTLSConfig                  = ffe("FF10455", "Provided TLS Config name '%s' not found for namespace '%s'", 400)
+	MsgNoHealthyConnectorEndpoint         = ffe("FF10456", "None of the %d configured connector endpoints are healthy")
+	MsgInvalidConfirmations               = ffe("FF10457", "Invalid confirmations '%d' - must be zero or greater", 400)
+	MsgInvalidPrivacyOption               = ffe("FF10458", "Private transaction option '%s' must be a %s", 400)
+	MsgConflictingPrivacyOptions          = ffe("FF10459", "Private transaction options '%s' and '%s' cannot both be specified", 400)
+	MsgInvalidEthAddressChecksum          = ffe("FF10460", "Supplied ethereum address '%s' has an invalid EIP-55 checksum", 400)
+	MsgENSResolveFailed                   = ffe("FF10461", "Failed to resolve ENS name '%s'", 500)
+	MsgENSNameNotFound                    = ffe("FF10462", "ENS name '%s' does not resolve to an address", 404)
+	MsgInvalidBatchPinMode                = ffe("FF10463", "Invalid batch pin mode '%s'")
+	MsgPackedBatchPinRequiresV3           = ffe("FF10464", "Packed batch pins require version 3 or later of the FireFly contract, which implements pinBatchPacked (found version %d)")
+	MsgInvalidPackedBatchPin              = ffe("FF10465", "Invalid packed batch pin payload: %s")
+	MsgHederaconnectRESTErr               = ffe("FF10466", "Error from Hedera connector: %s")
+	MsgInvalidHederaAccountID             = ffe("FF10467", "Supplied Hedera account ID '%s' is invalid - must be in the format shard.realm.num", 400)
+	MsgInvalidQueryQuorum                 = ffe("FF10468", "Invalid query quorum - %d matching results required from %d connector endpoints. At least 2 are required, and at most all of the endpoints")
+	MsgQueryQuorumNotReached              = ffe("FF10469", "Query quorum not reached - at most %d of %d connector endpoints returned matching results (%d required)", 502)
+	MsgInvalidEventDecodingHint           = ffe("FF10470", "Invalid decoding hint for event '%s': %s", 400)
+	MsgInvalidEventTopicFilter            = ffe("FF10471", "Too many topic filters for event '%s' - %d supplied, but the event has at most %d topics", 400)
+	MsgInvalidEventTopic                  = ffe("FF10472", "Invalid topic filter '%s' - must be a 32 byte hex string, or empty to match any value", 400)
+	MsgEventTopicSignatureMismatch        = ffe("FF10473", "Topic filter '%s' does not match the signature of event '%s'", 400)
+	MsgAnonymousEventNoTopics             = ffe("FF10474", "Listening to anonymous event '%s' requires topic filters, as it cannot be identified by signature", 400)
+	MsgChainIDMismatch                    = ffe("FF10475", "Connector is connected to chain '%s', but the multiparty contract for namespace '%s' expects chain '%s'")
+	MsgChainIDUnavailable                 = ffe("FF10476", "Connector did not report a chain ID from '%s'")
+	MsgTokenMetadataFetchFailed           = ffe("FF10477", "Error fetching token metadata or media: %s")
+	MsgTokenMetadataInvalid               = ffe("FF10478", "Token metadata from '%s' is invalid: %s")
+	MsgTokenMetadataUnsupportedURI        = ffe("FF10479", "Token URI '%s' cannot be dereferenced - only http, https, ipfs and data URIs are supported")
+	MsgTokenMetadataTooLarge              = ffe("FF10480", "Content from token URI '%s' exceeds the maximum size of %d bytes")
+	MsgInvalidApprovalExpiry              = ffe("FF10481", "Approval expiry must be in the future, and can only be set when granting an approval", 400)
+	MsgTokenSnapshotPointRequired         = ffe("FF10482", "Exactly one of 'blockNumber' or 'timestamp' must be specified for a token snapshot", 400)
+	MsgTokenTransferBatchEmpty            = ffe("FF10483", "A batch token transfer must include at least one leg", 400)
+	MsgTokenPoolPaused                    = ffe("FF10484", "Token pool '%s' is paused", 409)
+	MsgTokenPoolRetired                   = ffe("FF10485", "Token pool '%s' has been retired", 409)
+	MsgTokenPoolInvalidStateChange        = ffe("FF10486", "Token pool cannot move from state '%s' to '%s'", 409)
+	MsgContractInvokeMessageNotSupported  = ffe("FF10487", "A message cannot be attached to this contract invoke", 400)
+	MsgTokenSwapInvalidLegs               = ffe("FF10488", "A token swap must have exactly two legs, and the first leg must be a token transfer", 400)
+	MsgTokenSwapInvokeRequired            = ffe("FF10489", "Leg %d of the token swap is an invoke, but does not include an 'invoke' request", 400)
+	MsgTokenSwapNotFound                  = ffe("FF10490", "Token swap '%s' not found", 404)
+	MsgTokenPolicyAccountDenied           = ffe("FF10491", "Account '%s' is denied by the transfer policy for token pool '%s'", 403)
+	MsgTokenPolicySenderNotAllowed        = ffe("FF10492", "Account '%s' is not an allowed sender in the transfer policy for token pool '%s'", 403)
+	MsgTokenPolicyRecipientNotAllowed     = ffe("FF10493", "Account '%s' is not an allowed recipient in the transfer policy for token pool '%s'", 403)
+	MsgTokenPolicyMaxAmount               = ffe("FF10494", "Amount %s exceeds the maximum of %s in the transfer policy for token pool '%s'", 403)
+	MsgTokenPolicyDailyLimit              = ffe("FF10495", "Amount %s would take key '%s' over the daily limit of %s in the transfer policy for token pool '%s'", 403)
+	MsgTokenPolicyNegativeAmount          = ffe("FF10496", "Token transfer policy field '%s' cannot be negative", 400)
+	MsgTokenExportInvalidFormat           = ffe("FF10497", "Unsupported export format '%s' - must be one of: %s", 400)
+	MsgTokenExportInvalidColumn           = ffe("FF10498", "Unknown column '%s' for token export - must be one of: %s", 400)
+	MsgTokenExportInvalidTime             = ffe("FF10499", "Invalid '%s' for token export: %s", 400)
+	MsgInvalidSignoffThreshold            = ffe("FF10500", "Invalid asset.signoff.threshold '%s' - must be a positive decimal amount")
+	MsgInvalidSignoffApprovers            = ffe("FF10501", "asset.signoff.required is %d, but it must be at least 1 and no more than the %d principals configured in asset.signoff.approvers")
+	MsgTokenTransferRequestNotFound       = ffe("FF10502", "Token transfer request '%s' not found", 404)
+	MsgTokenTransferRequestNotPending     = ffe("FF10503", "Token transfer request '%s' is not pending sign-off (state=%s)", 409)
+	MsgTokenTransferSignoffNotApprover    = ffe("FF10504", "Principal '%s' is not a configured approver for token transfer requests", 403)
+	MsgTokenTransferAlreadySignedOff      = ffe("FF10505", "Principal '%s' has already signed off token transfer request '%s'", 409)
+	MsgTokenTransferBatchRequiresSignoff  = ffe("FF10506", "The total amount %s of the batch transfer is above the sign-off threshold of %s - submit the transfers individually for sign-off", 400)
+	MsgTokenMediaNotFound                 = ffe("FF10507", "No image has been indexed from the token metadata at '%s'", 404)
+	MsgTokenMediaContentType              = ffe("FF10508", "Token media from '%s' has content type '%s', which is not one of the allowed content types", 415)
+	MsgTokenMediaInvalidStore             = ffe("FF10509", "Invalid asset.metadata.media.store '%s' - must be 'database' or 'sharedstorage'")
+	MsgTokenMediaNoSharedStorage          = ffe("FF10510", "asset.metadata.media.store is 'sharedstorage', but no shared storage plugin is configured for namespace '%s'")
+	MsgTokenPoolMigrating                 = ffe("FF10511", "Token pool '%s' is being migrated to a new connector", 409)
+	MsgTokenPoolMigrationSameConnector    = ffe("FF10512", "Token pool '%s' is already using connector '%s'", 400)
+	MsgTokenPoolMigrationNotPending       = ffe("FF10513", "Token pool migration '%s' is no longer pending", 409)
+	MsgTokenPoolMigrationMismatch         = ffe("FF10514", "Token pool resolved by connector '%s' does not match the existing pool: %s does not match")
+	MsgTokenPoolNotNonFungible            = ffe("FF10515", "Token pool '%s' is not a non-fungible pool", 400)
+	MsgTokenBulkMintEmpty                 = ffe("FF10516", "A bulk mint must include at least one item", 400)
+	MsgTokenBulkMintTooLarge              = ffe("FF10517", "A bulk mint of %d items is above the maximum of %d", 400)
+	MsgTokenBulkMintBatchSizeTooLarge     = ffe("FF10518", "Bulk mint batch size %d is above the maximum of %d", 400)
+	MsgTokenBulkMintRequiresSignoff       = ffe("FF10519", "Mints are held for sign-off above a threshold of %s - submit the mints individually for sign-off", 400)
+	MsgTokenBulkMintFailed                = ffe("FF10520", "%d of %d mints failed")
+	MsgTokenConnectorUnavailable          = ffe("FF10521", "Token connector '%s' is unavailable after %d consecutive failures", 503)
+	MsgKafkaInvalidTopic                  = ffe("FF10522", "Invalid Kafka topic '%s' - must be 1-249 characters of a-z, A-Z, 0-9, '.', '_' or '-'", 400)
+	MsgKafkaInvalidPartitionKey           = ffe("FF10523", "Invalid Kafka partition key '%s' - must be one of: topic, group, author", 400)
+	MsgKafkaRESTErr                       = ffe("FF10524", "Error from Kafka REST proxy: %s")
+	MsgKafkaProduceFailed                 = ffe("FF10525", "Failed to write event to Kafka topic '%s': %s")
+	MsgAMQPQueueEmpty                     = ffe("FF10526", "AMQP subscription option 'queue' cannot be empty, as no default queue is configured", 400)
+	MsgAMQPSendFailed                     = ffe("FF10527", "Failed to send event to AMQP queue '%s'")
+	MsgMQTTInvalidURL                     = ffe("FF10528", "Invalid MQTT broker URL '%s'")
+	MsgMQTTInvalidTopic                   = ffe("FF10529", "Invalid MQTT topic '%s' - topics for publishing cannot contain the wildcards '+' or '#'", 400)
+	MsgMQTTPublishFailed                  = ffe("FF10530", "Failed to publish event to MQTT topic '%s'")
+	MsgGRPCListenFailed                   = ffe("FF10531", "Failed to listen for gRPC event streams on '%s'")
+	MsgGRPCStreamNotActive                = ffe("FF10532", "gRPC event stream '%s' no longer active")
+	MsgGRPCClientNoAction                 = ffe("FF10533", "A message must contain either a start or an ack")
+	MsgSubscriptionNotFound               = ffe("FF10534", "Subscription '%s' not found", 404)
+	MsgSSEConnectionNotActive             = ffe("FF10535", "SSE connection '%s' no longer active")
+	MsgSSEStreamingUnsupported            = ffe("FF10536", "Streaming responses are not supported on this connection", 500)
+	MsgSSEInvalidLastEventID              = ffe("FF10537", "Invalid Last-Event-ID '%s' - must be the sequence of an event", 400)
+	MsgSSENoData                          = ffe("FF10538", "SSE subscriptions do not support streaming the full data payload, just the references (withData must be false)", 400)
+	MsgInvalidNamespaceConfirmations      = ffe("FF10539", "Invalid confirmations '%d' for namespace '%s' - must be zero or greater")
+	MsgTokenURIAddressBlocked             = ffe("FF10540", "Token URI host '%s' resolves to non-public address '%s', which cannot be fetched from")
+	MsgTokenURIHostNotAllowed             = ffe("FF10541", "Token URI host '%s' is not in asset.metadata.allowedHosts")
+	MsgTokenURITooManyRedirects           = ffe("FF10542", "Token URI '%s' exceeded the maximum of %d redirects")
+	MsgTokenTransferSignoffConflict       = ffe("FF10543", "Token transfer request '%s' was updated concurrently on every attempt to sign off - retry the sign-off", 409)
+	MsgTokenSwapLegRequiresSignoff        = ffe("FF10544", "The amount %s of a swap leg is above the sign-off threshold of %s - swaps cannot be held for sign-off", 400)
+	MsgTokenMediaInvalidContentType       = ffe("FF10545", "Invalid asset.metadata.media.contentTypes entry '%s' - only image/* and video/* content types can be served")
+	MsgWebhookFailedStatus                = ffe("FF10546", "Webhook returned status %d")
+	MsgWebhookInvalidRetryOption          = ffe("FF10547", "Webhook subscription retry option '%s' is invalid: %v", 400)
+	MsgExpressionCompileFailed            = ffe("FF10548", "Unable to compile '%s' expression '%s'", 400)
+	MsgExpressionNotBoolean               = ffe("FF10549", "The '%s' expression '%s' must evaluate to a boolean, not %s", 400)
+	MsgTokenTransferFilterAmountRange     = ffe("FF10550", "Subscription filter 'tokentransfer.minAmount' %s is greater than 'tokentransfer.maxAmount' %s", 400)
+	MsgInvalidSubscriptionFormat          = ffe("FF10551", "Invalid subscription format '%s'", 400)
+	MsgSubscriptionFormatNotSupported     = ffe("FF10552", "Subscription format '%s' is not supported by the '%s' transport", 400)
+	MsgWebhookInvalidCloudEventsMode      = ffe("FF10553", "Webhook subscription option 'cloudEventsMode' must be 'structured' or 'binary': %s", 400)
+	MsgSubscriptionRewindTarget           = ffe("FF10554", "Exactly one of 'sequence' or 'timestamp' must be specified to rewind a subscription", 400)
+	MsgSubscriptionRewindForward          = ffe("FF10555", "Cannot rewind subscription to offset %d, as it is ahead of the current offset %d", 409)
+	MsgConsumerGroupsNotSupported         = ffe("FF10556", "Consumer groups are not supported by the '%s' transport", 400)
+	MsgInvalidConsumerGroupOption         = ffe("FF10557", "Invalid consumer group option '%s': %s", 400)
+	MsgInvalidDeliveryOption              = ffe("FF10558", "Invalid subscription delivery option '%s': %v", 400)
+	MsgWSInvalidEncoding                  = ffe("FF10559", "Invalid WebSocket encoding '%s'", 400)
+	MsgWSInvalidCompressionLevel          = ffe("FF10560", "Invalid WebSocket compression level %d")
+	MsgWSInvalidToken                     = ffe("FF10561", "Invalid WebSocket bearer token: %s", 401)
+	MsgWSTokenNotPermitted                = ffe("FF10562", "The WebSocket bearer token does not permit subscription '%s:%s'", 403)
+	MsgWSTokenExpired                     = ffe("FF10563", "The WebSocket bearer token has expired")
+	MsgWSTokenAuthNotEnabled              = ffe("FF10564", "JWT authentication is not enabled for WebSockets")
+	MsgWSInvalidJWTPublicKey              = ffe("FF10565", "Invalid JWT public key file '%s'")
+	MsgInvalidDedupWindow                 = ffe("FF10566", "Invalid deduplication window '%s': must be greater than zero", 400)
+	MsgBatchDeliveryNotSupported          = ffe("FF10567", "Batch delivery is not supported by the '%s' transport", 400)
+	MsgInvalidBatchOption                 = ffe("FF10568", "Invalid subscription batch option '%s': %v", 400)
+	MsgWebhooksBatchOption                = ffe("FF10569", "The webhook option '%s' cannot be used with batch delivery", 400)
+	MsgAWSMissingCredentials              = ffe("FF10570", "No AWS credentials or region configured for the '%s' transport")
+	MsgEventTransportTargetMissing        = ffe("FF10571", "The '%s' transport requires the '%s' option on the subscription, or a default in its configuration", 400)
+	MsgInvalidMessageAttributes           = ffe("FF10572", "Invalid message attributes option: %s", 400)
+	MsgAWSRequestFailed                   = ffe("FF10573", "Error from AWS %s: %s")
+	MsgPubSubRESTErr                      = ffe("FF10574", "Error from Google Cloud Pub/Sub: %s")
+	MsgPubSubInvalidCredentials           = ffe("FF10575", "Invalid Google Cloud service account credentials file '%s': %s")
+	MsgPubSubTokenFailed                  = ffe("FF10576", "Failed to obtain an access token for Google Cloud Pub/Sub: %s")
+	MsgPubSubInvalidTopic                 = ffe("FF10577", "Invalid Google Cloud Pub/Sub topic '%s' - must be a topic name, or projects/<project>/topics/<topic>", 400)
+	MsgSubscriptionNotPull                = ffe("FF10578", "Subscription '%s' does not use the pull transport", 400)
+	MsgPullInvalidBatchSize               = ffe("FF10579", "Invalid batch size %d - must be between 1 and %d", 400)
+	MsgSubscriptionTemplateInvalid        = ffe("FF10580", "Invalid subscription template '%s': %s", 400)
+	MsgDeadLetterNotPending               = ffe("FF10581", "Dead letter '%s' has already been %s", 409)
+	MsgDeadLetterSubscriptionNotActive    = ffe("FF10582", "Subscription '%s' has no active connection to redeliver dead letter '%s' to", 409)
+	MsgDeadLetterEventNotFound            = ffe("FF10583", "Event '%s' of dead letter '%s' was not found", 404)
+	MsgEventRuleInvalid                   = ffe("FF10584", "Invalid event rule '%s': %s", 400)
+	MsgWebhookTemplateInvalid             = ffe("FF10585", "Webhook subscription option '%s' is invalid: %s", 400)
+	MsgGRPCAPIListenFailed                = ffe("FF10586", "Failed to listen for gRPC API requests on '%s'")
+	MsgGRPCInvalidJSON                    = ffe("FF10587", "Field '%s' must contain valid JSON: %s", 400)
+	MsgGRPCUnknownCollection              = ffe("FF10588", "Unknown collection '%s'", 400)
+	MsgIdempotencyKeyHeaderMismatch       = ffe("FF10589", "Idempotency-Key header '%s' does not match the idempotencyKey '%s' in the request body", 400)
+	MsgRBACNoPrincipal                    = ffe("FF10590", "Request has no authenticated principal to check roles for", 401)
+	MsgRBACForbidden                      = ffe("FF10591", "Principal '%s' does not have a role in namespace '%s' permitting '%s' access", 403)
+	MsgRateLimitExceeded                  = ffe("FF10592", "Rate limit of %v requests per second exceeded for %s '%s'", 429)
+	MsgDailyQuotaExceeded                 = ffe("FF10593", "Daily quota of %d requests exceeded for %s '%s'", 429)
+	MsgInvalidCertIdentityMapping         = ffe("FF10594", "Invalid client certificate identity mapping %d: %s")
+	MsgCertIdentityRequired               = ffe("FF10595", "A client certificate mapped to an identity is required", 401)
+	MsgCertIdentityAuthorNotAllowed       = ffe("FF10596", "Identity '%s' of the client certificate cannot send as author '%s'", 403)
+	MsgCertIdentityKeyNotAllowed          = ffe("FF10597", "Identity '%s' of the client certificate is not allowed to use signing key '%s'", 403)
+	MsgInvalidFieldsParam                 = ffe("FF10598", "Unknown field '%s' in the fields query parameter", 400)
+	MsgUnknownJobType                     = ffe("FF10599", "Unknown job type '%s'", 400)
+	MsgJobInvalidInput                    = ffe("FF10600", "Invalid input for a job of type '%s': %s", 400)
+	MsgJobAlreadyComplete                 = ffe("FF10601", "Job '%s' has already completed with status '%s'", 409)
+	MsgJobInterrupted                     = ffe("FF10602", "The job was interrupted by a restart of the node before it completed")
+	MsgJobNoOutputFile                    = ffe("FF10603", "Job '%s' has no output file", 404)
+	MsgAuditExportFileFailed              = ffe("FF10604", "Failed to open audit log export file '%s': %s")
+	MsgInvalidListenerSocketMode          = ffe("FF10605", "Invalid socketMode '%s' for %s listener %d - must be octal file permissions such as 0660")
+	MsgConnectionLimitExceeded            = ffe("FF10606", "Limit of %d concurrent %s exceeded for %s '%s'", 503)
+	MsgInvalidQuery                       = ffe("FF10607", "Invalid query at position %d - expected %s", 400)
+	MsgQueryTooComplex                    = ffe("FF10608", "Query exceeds the maximum of %d characters, %d conditions or %d levels of parentheses", 400)
+	MsgQueryUnknownField                  = ffe("FF10609", "Unknown field '%s' in query", 400)
+	MsgInvalidTenant                      = ffe("FF10610", "Invalid tenant %d: %s")
+	MsgDefaultNamespaceTenant             = ffe("FF10611", "The default namespace '%s' cannot belong to tenant '%s'")
+	MsgTenantNotFound                     = ffe("FF10612", "Tenant '%s' not found", 404)
+	MsgTenantNamespaceNotFound            = ffe("FF10613", "Namespace '%s' not found in tenant '%s'", 404)
+	MsgTenantForbidden                    = ffe("FF10614", "Principal '%s' is not bound to tenant '%s'", 403)
+	MsgInvalidCORSRule                    = ffe("FF10615", "Invalid CORS rule %d: %s")
+	MsgUnknownRouteGroup                  = ffe("FF10616", "Unknown route group '%s' in %s")
+	MsgSendTimeWithWait                   = ffe("FF10617", "A message with a 'sendTime' cannot be sent with confirm=true, or as a request/reply", 400)
+	MsgMessageNotScheduled                = ffe("FF10618", "Message '%s' is not scheduled, and is in state '%s'", 409)
+	MsgInvalidMessageTTL                  = ffe("FF10619", "Invalid message 'ttl' '%s' - must be greater than zero", 400)
+	MsgExpired                            = ffe("FF10620", "Message with ID '%s' expired before it was confirmed")
+	MsgGroupInvalidGeneration             = ffe("FF10621", "Invalid group generation %d - a group must have a previous group if, and only if, its generation is greater than zero", 400)
+	MsgGroupSuperseded                    = ffe("FF10622", "Group '%s' has been superseded by a membership change, and messages must be sent to the next generation of the group", 409)
+	MsgGroupMembersUnchanged              = ffe("FF10623", "The request does not change the members of group '%s'", 400)
+	MsgGroupChangeAuthorNotMember         = ffe("FF10624", "The author '%s' must be a member of group '%s' both before and after the membership change", 400)
+	MsgNotPrivateMessage                  = ffe("FF10625", "Message '%s' is not a private message", 400)
+	MsgMessageNotConfirmed                = ffe("FF10626", "Message '%s' is in state '%s', and must be confirmed before it can be marked as read", 409)
+	MsgReceiptNotLocalMember              = ffe("FF10627", "Identity '%s' is not a member of group '%s' on the local node", 400)
+	MsgReceiptOwnMessage                  = ffe("FF10628", "Message '%s' was sent by this node", 400)
+	MsgInvalidEncryptionKey               = ffe("FF10629", "Invalid encryption key in '%s' - must be a base64 encoded 32 byte X25519 key")
+	MsgNodeNoEncryptionKey                = ffe("FF10630", "Node '%s' does not have a valid '%s' in its profile, so data cannot be encrypted for it")
+	MsgRecallNotOwnMessage                = ffe("FF10631", "Message '%s' was not sent by this node, so it cannot be recalled", 400)
+	MsgMessageAlreadyRecalled             = ffe("FF10632", "Message '%s' has already been recalled", 409)
+	MsgRecallNotConfirmed                 = ffe("FF10633", "Message '%s' is in state '%s', and must be confirmed before it can be recalled", 409)
+	MsgBatchPolicyInvalid                 = ffe("FF10634", "Invalid batch policy for topic '%s' - at least one of maxLatency and maxSize must be set, and maxLatency must be greater than zero", 400)
+	MsgParentMessageNotFound              = ffe("FF10635", "Parent message '%s' not found", 400)
+	MsgParentMessageGroupMismatch         = ffe("FF10636", "Parent message '%s' is not in the same group as the message", 400)
+	MsgInvalidInboundAction               = ffe("FF10637", "Invalid inbound limit action '%s' - must be 'throttle' or 'quarantine'")
+	MsgQuarantinedBatchNotPending         = ffe("FF10638", "Quarantined batch '%s' has already been %s", 409)
+	MsgDisclosureNoFields                 = ffe("FF10639", "Data '%s' does not have a JSON object value with at least one field, so its fields cannot be disclosed", 400)
+	MsgDisclosureFieldNotFound            = ffe("FF10640", "Field '%s' not found in data '%s'", 400)
+	MsgDisclosureNotEnabled               = ffe("FF10641", "Message '%s' was not sent in a pinned batch with selective disclosure", 400)
+	MsgDisclosureNotConfirmed             = ffe("FF10642", "Message '%s' is in state '%s', and must be confirmed before its data can be disclosed", 409)
+	MsgDisclosureDataRequired             = ffe("FF10643", "Message '%s' has %d data items - the data to disclose must be specified", 400)
+	MsgDisclosureDataNotInMessage         = ffe("FF10644", "Data '%s' is not in message '%s'", 400)
+	MsgDisclosureNoFieldsRequested        = ffe("FF10645", "At least one field must be specified", 400)
+	MsgDisclosureProofIncomplete          = ffe("FF10646", "The proof must include the batch manifest, the message, the data ID and at least one field", 400)
+	MsgDisclosureMessageNotInBatch        = ffe("FF10647", "Message '%s' with hash '%s' is not in batch '%s'")
+	MsgDisclosureDataNotInBatch           = ffe("FF10648", "Data '%s' does not have a disclosure root in batch '%s'")
+	MsgDisclosureFieldMismatch            = ffe("FF10649", "The value of field '%s' does not match the disclosure root of data '%s'")
+	MsgDisclosureBatchNotPinned           = ffe("FF10650", "Batch '%s' with hash '%s' has not been pinned to the blockchain")
+	MsgTopicSequenceMissing               = ffe("FF10651", "Message '%s' is on strict topic '%s', but does not have a topicSequence")
+	MsgTopicSequenceStale                 = ffe("FF10652", "Message '%s' has topicSequence %d on strict topic '%s', but the next sequence on the topic is %d")
+	MsgTopicSequenceDuplicate             = ffe("FF10653", "Message '%s' has topicSequence %d on strict topic '%s', which is already held by parked message '%s'")
+	MsgBridgeInvalid                      = ffe("FF10654", "Invalid bridge '%s' in namespace '%s': %s")
+	MsgSLATopicInvalid                    = ffe("FF10655", "Invalid SLA for topic '%s' in namespace '%s': %s")
+	MsgReplyQuorumInvalid                 = ffe("FF10656", "Reply quorum %d is invalid for reply mode '%s'", 400)
+	MsgReplyDatatypeRequired              = ffe("FF10657", "The datatype of the reply must have a name and a version", 400)
+	MsgInvalidReplyTimeout                = ffe("FF10658", "Invalid reply 'timeout' '%s' - must be greater than zero", 400)
+	MsgRequestReplyIncomplete             = ffe("FF10659", "The request with id '%s' timed out after %.2fms with %d of %d required replies (%d replies did not match the datatype)", 408)
+	MsgReplyDatatypeMismatch              = ffe("FF10660", "Reply '%s' does not have data of datatype '%s'")
+	MsgDraftWithWait                      = ffe("FF10661", "A draft message cannot be sent with confirm=true, or as a request/reply", 400)
+	MsgDraftWithTTL                       = ffe("FF10662", "A draft message cannot have a 'ttl', as it is not sent until it is committed", 400)
+	MsgMessageNotDraft                    = ffe("FF10663", "Message '%s' is not a draft, and is in state '%s'", 409)
+	MsgBatchQueueBackpressure             = ffe("FF10664", "The batch queue for topic '%s' is over its limit (depth=%d oldest=%s), retry later", 429)
+	MsgUnknownTransformPlugin             = ffe("FF10665", "Unknown transform plugin '%s'")
+	MsgTransformOptionsInvalid            = ffe("FF10666", "Invalid options for transform plugin '%s': %s")
+	MsgTransformFailed                    = ffe("FF10667", "Transform '%s' failed on data item %d: %s", 400)
+	MsgMessageNotPendingApp               = ffe("FF10668", "Message '%s' is in state '%s', so cannot be accepted or rejected by the application", 409)
+	MsgMessageRejectedByApp               = ffe("FF10669", "Message rejected by the application: %s")
+	MsgBlobUploadBadOffset                = ffe("FF10670", "Upload '%s' is at offset %d, so cannot append a chunk at offset %d", 409)
+	MsgBlobUploadTooLarge                 = ffe("FF10671", "Chunk would take upload '%s' past its size of %d bytes", 400)
+	MsgBlobUploadChunkHashMismatch        = ffe("FF10672", "Hash of the chunk is '%s', but '%s' was expected", 400)
+	MsgBlobUploadIncomplete               = ffe("FF10673", "Upload '%s' has %d of its %d bytes, so cannot be completed", 400)
+	MsgBlobUploadHashMismatch             = ffe("FF10674", "Hash of upload '%s' is '%s', but '%s' was expected", 400)
+	MsgBlobUploadStagingFailed            = ffe("FF10675", "Failed to stage the data of upload '%s'")
+	MsgBlobUploadChunkRequired            = ffe("FF10676", "The chunk must be sent as the file of a multipart/form-data request", 400)
+	MsgBlobUploadBusy                     = ffe("FF10677", "Upload '%s' is being written by another request", 409)
+	MsgBlobUploadInvalidOffset            = ffe("FF10678", "Invalid offset '%s' - must be a number of bytes", 400)
+	MsgBlobRangeNotSatisfiable            = ffe("FF10679", "Range cannot be satisfied for a blob of %d bytes", 416)
+	MsgUnknownBlobScannerPlugin           = ffe("FF10680", "Unknown blob scanner plugin '%s'")
+	MsgBlobScannerOptionsInvalid          = ffe("FF10681", "Invalid options for blob scanner plugin '%s': %s")
+	MsgBlobScanFailed                     = ffe("FF10682", "Blob scanner '%s' failed: %s")
+	MsgBlobFlagged                        = ffe("FF10683", "Blob content was flagged by the blob scanner: %s", 422)
+	MsgQuarantinedBlobNotPending          = ffe("FF10684", "Quarantined blob '%s' has already been %s", 409)
+	MsgDatatypeIncompatible               = ffe("FF10685", "Version '%s' of datatype '%s' is not %s compatible with version '%s': %s", 400)
+	MsgDataInvalidPerSchema               = ffe("FF10686", "Data does not conform to the %s schema of datatype '%s': %s", 400)
+	MsgDatatypeCompatibilityUnsupported   = ffe("FF10687", "Compatibility '%s' cannot be checked for datatypes with validator '%s'", 400)
+	MsgDatatypeValidatorChanged           = ffe("FF10688", "Version '%s' of datatype '%s' uses validator '%s', but version '%s' uses validator '%s'", 400)
+	MsgTokenTransferSignoffNoPrincipal    = ffe("FF10690", "Token transfer requests can only be signed off by an authenticated principal", 401)
+	MsgHTSUnsupported                     = ffe("FF10692", "The Hedera Token Service plugin does not support %s", 400)
+	MsgChainHeadMissing                   = ffe("FF10693", "The blockchain connector did not return the block number of the chain head: %s")
+	MsgQuarantinedBatchNotDecrypted       = ffe("FF10694", "Quarantined batch '%s' still cannot be decrypted by this node", 409)
+	MsgENSNameInvalid                     = ffe("FF10695", "'%s' is not a valid ENS name", 400)
 )
@@ -59,6 +59,8 @@ var (
 	MessageHeaderTag       = ffm("MessageHeader.tag", "The message tag indicates the purpose of the message to the applications that process it")
 	MessageHeaderDataHash  = ffm("MessageHeader.datahash", "A single hash representing all data in the message. Derived from the array of data ids+hashes attached to this message")
 	MessageTxParent        = ffm("MessageHeader.txparent", "The parent transaction that originally triggered this message")
+	MessageParentMessage   = ffm("MessageHeader.parentMessage", "The ID of the message this message replies to in a thread. The parent must exist on this node, and be in the same group when it is private")
+	MessageTopicSequence   = ffm("MessageHeader.topicSequence", "The position of the message in the sequence of each of its strict topics. Required on strict topics, where the first message on each topic has sequence 1")
 
 	// Message field descriptions
 	MessageHeader         = ffm("Message.header", "The message header contains all fields that are used to build the message hash")
@@ -72,10 +74,67 @@ var (
 	MessagePins           = ffm("Message.pins", "For private messages, a unique pin hash:nonce is assigned for each topic")
 	MessageTransactionID  = ffm("Message.txid", "The ID of the transaction used to order/deliver this message")
 	MessageIdempotencyKey = ffm("Message.idempotencyKey", "An optional unique identifier for a message. Cannot be duplicated within a namespace, thus allowing idempotent submission of messages to the API. Local only - not transferred when the message is sent to other members of the network")
+	MessageSendTime       = ffm("Message.sendTime", "An optional time in the future to send the message. The message is held in the scheduled state until this time, and can be cancelled until then. Local only - not transferred when the message is sent to other members of the network")
+	MessagePriority       = ffm("Message.priority", "The priority of the message in batch assembly. A high priority message is sent ahead of normal messages, and flushes the batch it is assembled into. Local only - not transferred when the message is sent to other members of the network")
+	MessageChunks         = ffm("Message.chunks", "The IDs of the chunk messages that carry the data of this message, in order, when the data was too large to send in a single batch")
+	MessageExpires        = ffm("Message.expires", "The time the message expires, set from the ttl when the message is sent. A message that has not been confirmed by this time moves to the expired state. Local only - not transferred when the message is sent to other members of the network")
 
 	// MessageInOut field descriptions
-	MessageInOutData  = ffm("MessageInOut.data", "For input allows you to specify data in-line in the message, that will be turned into data attachments. For output when fetchdata is used on API calls, includes the in-line data payloads of all data attachments")
-	MessageInOutGroup = ffm("MessageInOut.group", "Allows you to specify details of the private group of recipients in-line in the message. Alternative to using the header.group to specify the hash of a group that has been previously resolved")
+	MessageInOutData    = ffm("MessageInOut.data", "For input allows you to specify data in-line in the message, that will be turned into data attachments. For output when fetchdata is used on API calls, includes the in-line data payloads of all data attachments")
+	MessageInOutTTL     = ffm("MessageInOut.ttl", "An optional time to live for the message, such as '30s'. The message expires if it has not been confirmed within this time of being sent, or of its sendTime")
+	MessageInOutGroup   = ffm("MessageInOut.group", "Allows you to specify details of the private group of recipients in-line in the message. Alternative to using the header.group to specify the hash of a group that has been previously resolved")
+	MessageInOutReply   = ffm("MessageInOut.reply", "For request messages, the options on the replies to wait for, including the datatype of the reply, how many replies are needed, and a timeout")
+	MessageInOutDraft   = ffm("MessageInOut.draft", "Stores the message and its data as a draft, which is validated but not sent until it is committed. Local only - not transferred when the message is sent to other members of the network")
+	MessageInOutReplies = ffm("MessageInOut.replies", "For the reply to a request with a quorum of more than one, all of the replies that made up the quorum")
+
+	// ReplyOptions field descriptions
+	ReplyOptionsDatatype = ffm("ReplyOptions.datatype", "The datatype that all of the data of a reply must have. Replies that do not match are ignored")
+	ReplyOptionsMode     = ffm("ReplyOptions.mode", "How replies are collected - 'first' returns the first valid reply, and 'quorum' waits for valid replies from a number of different authors")
+	ReplyOptionsQuorum   = ffm("ReplyOptions.quorum", "The number of valid replies from different authors needed when the mode is 'quorum'")
+	ReplyOptionsTimeout  = ffm("ReplyOptions.timeout", "The time to wait for the replies, such as '10s'. Can only shorten the timeout of the API request")
+
+	// ReplyMessage field descriptions
+	ReplyMessageData = ffm("ReplyMessage.data", "The in-line data of the reply")
+
+	// MessageReceipt field descriptions
+	MessageReceiptID          = ffm("MessageReceipt.id", "The UUID of the receipt")
+	MessageReceiptNamespace   = ffm("MessageReceipt.namespace", "The namespace of the receipt")
+	MessageReceiptMessage     = ffm("MessageReceipt.message", "The UUID of the private message the receipt is for")
+	MessageReceiptMessageHash = ffm("MessageReceipt.messageHash", "The hash of the message received by the member, which must match the message that was sent")
+	MessageReceiptType        = ffm("MessageReceipt.type", "The type of the receipt - delivered when the member's node has confirmed the message, or read when an application has marked it as read")
+	MessageReceiptIdentity    = ffm("MessageReceipt.identity", "The DID of the group member that sent the receipt")
+	MessageReceiptNode        = ffm("MessageReceipt.node", "The UUID of the node of the group member that sent the receipt")
+	MessageReceiptCreated     = ffm("MessageReceipt.created", "The time the receipt was created on the node of the group member")
+
+	// MessageReadInput field descriptions
+	MessageReadInputIdentity = ffm("MessageReadInput.identity", "The group member that has read the message. Defaults to the member of the group on the local node")
+
+	// MessageRecallInput field descriptions
+	MessageRecallInputPurge = ffm("MessageRecallInput.purge", "Set to true to delete the data of the message on each member node once the recall is confirmed, as well as marking the message recalled")
+
+	// MessageAck field descriptions
+	MessageAckID        = ffm("MessageAck.id", "The UUID of the message ack")
+	MessageAckNamespace = ffm("MessageAck.namespace", "The namespace of the message")
+	MessageAckMessage   = ffm("MessageAck.message", "The UUID of the message that was held for the application")
+	MessageAckOutcome   = ffm("MessageAck.outcome", "Whether the application accepted or rejected the message")
+	MessageAckReason    = ffm("MessageAck.reason", "The reason given by the application")
+	MessageAckCreated   = ffm("MessageAck.created", "The time the message was accepted or rejected")
+
+	// MessageAckInput field descriptions
+	MessageAckInputReason = ffm("MessageAckInput.reason", "The reason for accepting or rejecting the message, which is recorded with the decision")
+
+	// MessageDeliveryStatus field descriptions
+	MessageDeliveryStatusMessage    = ffm("MessageDeliveryStatus.message", "The UUID of the private message")
+	MessageDeliveryStatusGroup      = ffm("MessageDeliveryStatus.group", "The hash of the group the message was sent to")
+	MessageDeliveryStatusDelivered  = ffm("MessageDeliveryStatus.delivered", "The number of recipients that have confirmed delivery of the message")
+	MessageDeliveryStatusRead       = ffm("MessageDeliveryStatus.read", "The number of recipients that have read the message")
+	MessageDeliveryStatusRecipients = ffm("MessageDeliveryStatus.recipients", "The status of each member of the group on the other nodes of the network")
+
+	// MessageRecipientStatus field descriptions
+	MessageRecipientStatusIdentity  = ffm("MessageRecipientStatus.identity", "The DID of the group member")
+	MessageRecipientStatusNode      = ffm("MessageRecipientStatus.node", "The UUID of the node of the group member")
+	MessageRecipientStatusDelivered = ffm("MessageRecipientStatus.delivered", "The time the node of the member confirmed delivery of the message, if it has")
+	MessageRecipientStatusRead      = ffm("MessageRecipientStatus.read", "The time the member read the message, if it has")
 
 	// InputGroup field descriptions
 	InputGroupName    = ffm("InputGroup.name", "Optional name for the group. Allows you to have multiple separate groups with the same list of participants")
@@ -99,6 +158,14 @@ var (
 	GroupMessage        = ffm("Group.message", "The message used to broadcast this group privately to the members")
 	GroupHash           = ffm("Group.hash", "The identifier hash of this group. Derived from the name and group members")
 	GroupCreated        = ffm("Group.created", "The time when the group was first used to send a message in the network")
+	GroupPrevious       = ffm("Group.previous", "The hash of the previous generation of the group, when this group was created by a membership change")
+	GroupGeneration     = ffm("Group.generation", "The number of membership changes since the group was first created")
+	GroupSuccessor      = ffm("Group.successor", "The hash of the next generation of the group, if this node is a member of it")
+	GroupSuperseded     = ffm("Group.superseded", "The time the membership change that replaced this group was confirmed. Messages can no longer be sent to the group")
+
+	// GroupMembersInput field descriptions
+	GroupMembersInputAdd    = ffm("GroupMembersInput.add", "The members to add to the group")
+	GroupMembersInputRemove = ffm("GroupMembersInput.remove", "The members to remove from the group. All the nodes of an identity are removed if a node is not specified")
 
 	// MemberInput field descriptions
 	MemberInputIdentity = ffm("MemberInput.identity", "The DID of the group member. On input can be a UUID or org name, and will be resolved to a DID")
@@ -119,6 +186,21 @@ var (
 	BlobRefPath   = ffm("BlobRef.path", "If a name is specified, this field stores the '/' prefixed and separated path extracted from the full name")
 	BlobRefPublic = ffm("BlobRef.public", "If the blob data has been published to shared storage, this field is the id of the data in the shared storage plugin (IPFS hash etc.)")
 
+	// BlobUpload field descriptions
+	BlobUploadID        = ffm("BlobUpload.id", "The UUID of the chunked blob upload")
+	BlobUploadNamespace = ffm("BlobUpload.namespace", "The namespace of the upload")
+	BlobUploadOffset    = ffm("BlobUpload.offset", "The number of bytes uploaded so far, which is the offset the next chunk must be sent at")
+	BlobUploadSize      = ffm("BlobUpload.size", "The total size of the blob in bytes. If set, chunks past this size are rejected, and the upload can only be completed when all the bytes are uploaded")
+	BlobUploadHash      = ffm("BlobUpload.hash", "The SHA-256 hash of the whole blob. If set, the upload is only completed if the hash of the uploaded bytes matches")
+	BlobUploadFilename  = ffm("BlobUpload.filename", "The filename of the blob, which is added to the metadata of the data item when autometa is set")
+	BlobUploadMimetype  = ffm("BlobUpload.mimetype", "The MIME type of the blob, which is added to the metadata of the data item when autometa is set")
+	BlobUploadAutoMeta  = ffm("BlobUpload.autometa", "When set, FireFly will automatically generate JSON metadata with the upload details")
+	BlobUploadValidator = ffm("BlobUpload.validator", "The data validator type of the data item that is created when the upload is complete")
+	BlobUploadDatatype  = ffm("BlobUpload.datatype", "The optional datatype of the data item that is created when the upload is complete")
+	BlobUploadValue     = ffm("BlobUpload.value", "The JSON metadata of the data item that is created when the upload is complete")
+	BlobUploadCreated   = ffm("BlobUpload.created", "The time the upload was started")
+	BlobUploadUpdated   = ffm("BlobUpload.updated", "The time the last chunk was uploaded")
+
 	// Data field descriptions
 	DataID        = ffm("Data.id", "The UUID of the data resource")
 	DataValidator = ffm("Data.validator", "The data validator type")
@@ -132,18 +214,19 @@ var (
 
 	// DatatypeRef field descriptions
 	DatatypeRefName    = ffm("DatatypeRef.name", "The name of the datatype")
-	DatatypeRefVersion = ffm("DatatypeRef.version", "The version of the datatype. Semantic versioning is encouraged, such as v1.0.1")
+	DatatypeRefVersion = ffm("DatatypeRef.version", "The version of the datatype. Semantic versioning is encouraged, such as v1.0.1. If omitted when creating data, the latest version of the datatype is used and pinned on the data")
 
 	// Datatype field descriptions
-	DatatypeID        = ffm("Datatype.id", "The UUID of the datatype")
-	DatatypeMessage   = ffm("Datatype.message", "The UUID of the broadcast message that was used to publish this datatype to the network")
-	DatatypeValidator = ffm("Datatype.validator", "The validator that should be used to verify this datatype")
-	DatatypeNamespace = ffm("Datatype.namespace", "The namespace of the datatype. Data resources can only be created referencing datatypes in the same namespace")
-	DatatypeName      = ffm("Datatype.name", "The name of the datatype")
-	DatatypeVersion   = ffm("Datatype.version", "The version of the datatype. Multiple versions can exist with the same name. Use of semantic versioning is encourages, such as v1.0.1")
-	DatatypeHash      = ffm("Datatype.hash", "The hash of the value, such as the JSON schema. Allows all parties to be confident they have the exact same rules for verifying data created against a datatype")
-	DatatypeCreated   = ffm("Datatype.created", "The time the datatype was created")
-	DatatypeValue     = ffm("Datatype.value", "The definition of the datatype, in the syntax supported by the validator (such as a JSON Schema definition)")
+	DatatypeID            = ffm("Datatype.id", "The UUID of the datatype")
+	DatatypeMessage       = ffm("Datatype.message", "The UUID of the broadcast message that was used to publish this datatype to the network")
+	DatatypeValidator     = ffm("Datatype.validator", "The validator that should be used to verify this datatype")
+	DatatypeNamespace     = ffm("Datatype.namespace", "The namespace of the datatype. Data resources can only be created referencing datatypes in the same namespace")
+	DatatypeName          = ffm("Datatype.name", "The name of the datatype")
+	DatatypeVersion       = ffm("Datatype.version", "The version of the datatype. Multiple versions can exist with the same name. Use of semantic versioning is encourages, such as v1.0.1")
+	DatatypeHash          = ffm("Datatype.hash", "The hash of the value, such as the JSON schema. Allows all parties to be confident they have the exact same rules for verifying data created against a datatype")
+	DatatypeCreated       = ffm("Datatype.created", "The time the datatype was created")
+	DatatypeValue         = ffm("Datatype.value", "The definition of the datatype, in the syntax supported by the validator (such as a JSON Schema definition)")
+	DatatypeCompatibility = ffm("Datatype.compatibility", "The compatibility each new version of the datatype must have with the previous version. Inherited from the previous version if not set, and none for the first version")
 
 	// SignerRef field descriptions
 	SignerRefAuthor = ffm("SignerRef.author", "The DID of identity of the submitter")
@@ -161,11 +244,12 @@ var (
 	BatchHeaderCreated   = ffm("BatchHeader.created", "The time the batch was sealed")
 
 	// BatchManifest field descriptions
-	BatchManifestVersion  = ffm("BatchManifest.version", "The version of the manifest generated")
-	BatchManifestID       = ffm("BatchManifest.id", "The UUID of the batch")
-	BatchManifestTX       = ffm("BatchManifest.tx", "The FireFly transaction associated with this batch")
-	BatchManifestMessages = ffm("BatchManifest.messages", "Array of manifest entries, succinctly summarizing the messages in the batch")
-	BatchManifestData     = ffm("BatchManifest.data", "Array of manifest entries, succinctly summarizing the data in the batch")
+	BatchManifestVersion    = ffm("BatchManifest.version", "The version of the manifest generated")
+	BatchManifestID         = ffm("BatchManifest.id", "The UUID of the batch")
+	BatchManifestTX         = ffm("BatchManifest.tx", "The FireFly transaction associated with this batch")
+	BatchManifestMessages   = ffm("BatchManifest.messages", "Array of manifest entries, succinctly summarizing the messages in the batch")
+	BatchManifestData       = ffm("BatchManifest.data", "Array of manifest entries, succinctly summarizing the data in the batch")
+	BatchManifestDisclosure = ffm("BatchManifest.disclosure", "The disclosure root of each data item with fields that can be disclosed, for a pinned private batch sent with selective disclosure")
 
 	// BatchPersisted field descriptions
 	BatchPersistedHash       = ffm("Batch.hash", "The hash of the manifest of the batch")
@@ -184,33 +268,35 @@ var (
 	TransactionBlockchainIDs  = ffm("Transaction.blockchainIds", "The blockchain transaction ID, in the format specific to the blockchain involved in the transaction. Not all FireFly transactions include a blockchain. FireFly transactions are extensible to support multiple blockchain transactions")
 
 	// Operation field description
-	OperationID          = ffm("Operation.id", "The UUID of the operation")
-	OperationNamespace   = ffm("Operation.namespace", "The namespace of the operation")
-	OperationTransaction = ffm("Operation.tx", "The UUID of the FireFly transaction the operation is part of")
-	OperationType        = ffm("Operation.type", "The type of the operation")
-	OperationStatus      = ffm("Operation.status", "The current status of the operation")
-	OperationPlugin      = ffm("Operation.plugin", "The plugin responsible for performing the operation")
-	OperationInput       = ffm("Operation.input", "The input to this operation")
-	OperationOutput      = ffm("Operation.output", "Any output reported back from the plugin for this operation")
-	OperationError       = ffm("Operation.error", "Any error reported back from the plugin for this operation")
-	OperationCreated     = ffm("Operation.created", "The time the operation was created")
-	OperationUpdated     = ffm("Operation.updated", "The last update time of the operation")
-	OperationRetry       = ffm("Operation.retry", "If this operation was initiated as a retry to a previous operation, this field points to the UUID of the operation being retried")
+	OperationID            = ffm("Operation.id", "The UUID of the operation")
+	OperationNamespace     = ffm("Operation.namespace", "The namespace of the operation")
+	OperationTransaction   = ffm("Operation.tx", "The UUID of the FireFly transaction the operation is part of")
+	OperationType          = ffm("Operation.type", "The type of the operation")
+	OperationStatus        = ffm("Operation.status", "The current status of the operation")
+	OperationPlugin        = ffm("Operation.plugin", "The plugin responsible for performing the operation")
+	OperationInput         = ffm("Operation.input", "The input to this operation")
+	OperationOutput        = ffm("Operation.output", "Any output reported back from the plugin for this operation")
+	OperationError         = ffm("Operation.error", "Any error reported back from the plugin for this operation")
+	OperationCreated       = ffm("Operation.created", "The time the operation was created")
+	OperationUpdated       = ffm("Operation.updated", "The last update time of the operation")
+	OperationRetry         = ffm("Operation.retry", "If this operation was initiated as a retry to a previous operation, this field points to the UUID of the operation being retried")
+	OperationFailureReason = ffm("Operation.failureReason", "If the operation failed, and the plugin was able to classify the cause, this field contains the reason - such as a rejection by the compliance rules of a permissioned token")
 
 	// OperationWithDetail field description
 	OperationWithDetail = ffm("OperationWithDetail.detail", "Additional detailed information about an operation provided by the connector")
 
 	// BlockchainEvent field descriptions
-	BlockchainEventID         = ffm("BlockchainEvent.id", "The UUID assigned to the event by FireFly")
-	BlockchainEventSource     = ffm("BlockchainEvent.source", "The blockchain plugin or token service that detected the event")
-	BlockchainEventNamespace  = ffm("BlockchainEvent.namespace", "The namespace of the listener that detected this blockchain event")
-	BlockchainEventName       = ffm("BlockchainEvent.name", "The name of the event in the blockchain smart contract")
-	BlockchainEventListener   = ffm("BlockchainEvent.listener", "The UUID of the listener that detected this event, or nil for built-in events in the system namespace")
-	BlockchainEventProtocolID = ffm("BlockchainEvent.protocolId", "An alphanumerically sortable string that represents this event uniquely on the blockchain (convention for plugins is zero-padded values BLOCKNUMBER/TXN_INDEX/EVENT_INDEX)")
-	BlockchainEventOutput     = ffm("BlockchainEvent.output", "The data output by the event, parsed to JSON according to the interface of the smart contract")
-	BlockchainEventInfo       = ffm("BlockchainEvent.info", "Detailed blockchain specific information about the event, as generated by the blockchain connector")
-	BlockchainEventTimestamp  = ffm("BlockchainEvent.timestamp", "The time allocated to this event by the blockchain. This is the block timestamp for most blockchain connectors")
-	BlockchainEventTX         = ffm("BlockchainEvent.tx", "If this blockchain event is coorelated to FireFly transaction such as a FireFly submitted token transfer, this field is set to the UUID of the FireFly transaction")
+	BlockchainEventID          = ffm("BlockchainEvent.id", "The UUID assigned to the event by FireFly")
+	BlockchainEventSource      = ffm("BlockchainEvent.source", "The blockchain plugin or token service that detected the event")
+	BlockchainEventNamespace   = ffm("BlockchainEvent.namespace", "The namespace of the listener that detected this blockchain event")
+	BlockchainEventName        = ffm("BlockchainEvent.name", "The name of the event in the blockchain smart contract")
+	BlockchainEventListener    = ffm("BlockchainEvent.listener", "The UUID of the listener that detected this event, or nil for built-in events in the system namespace")
+	BlockchainEventProtocolID  = ffm("BlockchainEvent.protocolId", "An alphanumerically sortable string that represents this event uniquely on the blockchain (convention for plugins is zero-padded values BLOCKNUMBER/TXN_INDEX/EVENT_INDEX)")
+	BlockchainEventOutput      = ffm("BlockchainEvent.output", "The data output by the event, parsed to JSON according to the interface of the smart contract")
+	BlockchainEventInfo        = ffm("BlockchainEvent.info", "Detailed blockchain specific information about the event, as generated by the blockchain connector")
+	BlockchainEventTimestamp   = ffm("BlockchainEvent.timestamp", "The time allocated to this event by the blockchain. This is the block timestamp for most blockchain connectors")
+	BlockchainEventTX          = ffm("BlockchainEvent.tx", "If this blockchain event is coorelated to FireFly transaction such as a FireFly submitted token transfer, this field is set to the UUID of the FireFly transaction")
+	BlockchainEventInvalidated = ffm("BlockchainEvent.invalidated", "True if the connector has notified that this event was removed from the canonical chain, such as by a chain reorganization")
 
 	// ChartHistogram field descriptions
 	ChartHistogramCount     = ffm("ChartHistogram.count", "Total count of entries in this time bucket within the histogram")
@@ -315,7 +401,10 @@ var (
 	ContractListenerState     = ffm("ContractListener.state", "This field is provided for the event listener implementation of the blockchain provider to record state, such as checkpoint information")
 
 	// ContractListenerOptions field descriptions
-	ContractListenerOptionsFirstEvent = ffm("ContractListenerOptions.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors. Default is 'newest'")
+	ContractListenerOptionsConfirmations = ffm("ContractListenerOptions.confirmations", "The number of block confirmations the blockchain connector should wait for before delivering events to this listener. Default is the namespace confirmations setting, or the connector default if that is not set")
+	ContractListenerOptionsFirstEvent    = ffm("ContractListenerOptions.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors. Default is 'newest'")
+	ContractListenerOptionsTopics        = ffm("ContractListenerOptions.topics", "A blockchain specific list of raw topic filters, used to match events that cannot be identified by signature alone - such as anonymous Ethereum events. For Ethereum these are positional 32 byte hex log topics, where an empty string matches any value")
+	ContractListenerOptionsDecodingHint  = ffm("ContractListenerOptions.decodingHint", "Blockchain specific type information used by the connector to decode matched events, when the event definition alone is not sufficient. For Ethereum this is one or more ABI fragments, containing the full definition of the event")
 
 	// DIDDocument field descriptions
 	DIDDocumentContext            = ffm("DIDDocument.@context", "See https://www.w3.org/TR/did-core/#json-ld")
@@ -331,6 +420,100 @@ var (
 	DIDVerificationMethodMSPIdentityString   = ffm("DIDVerificationMethod.mspIdentityString", "For Hyperledger Fabric where the signing identity is represented by an MSP identifier (containing X509 certificate DN strings) that were validated by your local MSP")
 	DIDVerificationMethodDataExchangePeerID  = ffm("DIDVerificationMethod.dataExchangePeerID", "A string provided by your Data Exchange plugin, that it uses a technology specific mechanism to validate against when messages arrive from this identity")
 
+	// DeadLetter field descriptions
+	DeadLetterID            = ffm("DeadLetter.id", "The UUID of the dead letter")
+	DeadLetterNamespace     = ffm("DeadLetter.namespace", "The namespace of the subscription")
+	DeadLetterSubscription  = ffm("DeadLetter.subscription", "The subscription the event could not be delivered to")
+	DeadLetterEvent         = ffm("DeadLetter.event", "The UUID of the event that could not be delivered")
+	DeadLetterEventType     = ffm("DeadLetter.eventType", "The type of the event that could not be delivered")
+	DeadLetterReference     = ffm("DeadLetter.reference", "The UUID of the resource referenced by the event")
+	DeadLetterAttempts      = ffm("DeadLetter.attempts", "The number of delivery attempts made before the event was dead lettered")
+	DeadLetterStatus        = ffm("DeadLetter.status", "The HTTP status code returned by the final delivery attempt, if a response was received")
+	DeadLetterError         = ffm("DeadLetter.error", "The error from the final delivery attempt")
+	DeadLetterHistory       = ffm("DeadLetter.history", "The outcome of each delivery attempt, in the order the attempts were made")
+	DeadLetterOriginalEvent = ffm("DeadLetter.originalEvent", "The event as it was delivered to the subscription, including its enrichment")
+	DeadLetterState         = ffm("DeadLetter.state", "The state of the dead letter - pending until an administrator requeues or discards it")
+	DeadLetterCreated       = ffm("DeadLetter.created", "The time the dead letter was recorded")
+	DeadLetterUpdated       = ffm("DeadLetter.updated", "The time the dead letter was requeued or discarded")
+
+	// QuarantinedBatch field descriptions
+	QuarantinedBatchID        = ffm("QuarantinedBatch.id", "The UUID of the quarantined batch")
+	QuarantinedBatchNamespace = ffm("QuarantinedBatch.namespace", "The namespace the batch was received in")
+	QuarantinedBatchOrg       = ffm("QuarantinedBatch.org", "The DID of the org that owns the node the batch was received from")
+	QuarantinedBatchNode      = ffm("QuarantinedBatch.node", "The UUID of the node the batch was received from")
+	QuarantinedBatchPeer      = ffm("QuarantinedBatch.peer", "The data exchange peer the batch was received from")
+	QuarantinedBatchBatch     = ffm("QuarantinedBatch.batch", "The UUID of the batch")
+	QuarantinedBatchReason    = ffm("QuarantinedBatch.reason", "Why the batch was quarantined - the org was over the message rate, or the daily size quota, when the batch was received, or the data of the batch could not be decrypted")
+	QuarantinedBatchMessages  = ffm("QuarantinedBatch.messages", "The number of messages in the batch")
+	QuarantinedBatchSize      = ffm("QuarantinedBatch.size", "The size of the batch counted against the daily quota of the org")
+	QuarantinedBatchState     = ffm("QuarantinedBatch.state", "The state of the quarantined batch - pending until an administrator releases or discards it")
+	QuarantinedBatchCreated   = ffm("QuarantinedBatch.created", "The time the batch was quarantined")
+	QuarantinedBatchUpdated   = ffm("QuarantinedBatch.updated", "The time the batch was released or discarded")
+
+	// QuarantinedBlob field descriptions
+	QuarantinedBlobID         = ffm("QuarantinedBlob.id", "The UUID of the quarantined blob")
+	QuarantinedBlobNamespace  = ffm("QuarantinedBlob.namespace", "The namespace the blob was received in")
+	QuarantinedBlobData       = ffm("QuarantinedBlob.data", "The UUID of the data item the blob is attached to")
+	QuarantinedBlobHash       = ffm("QuarantinedBlob.hash", "The SHA-256 hash of the blob")
+	QuarantinedBlobSize       = ffm("QuarantinedBlob.size", "The size of the blob in bytes")
+	QuarantinedBlobPeer       = ffm("QuarantinedBlob.peer", "The data exchange peer the blob was received from, if it was sent privately")
+	QuarantinedBlobPayloadRef = ffm("QuarantinedBlob.payloadRef", "The reference of the blob in data exchange")
+	QuarantinedBlobFinding    = ffm("QuarantinedBlob.finding", "The threat the blob scanner found in the content of the blob")
+	QuarantinedBlobState      = ffm("QuarantinedBlob.state", "The state of the quarantined blob - pending until an administrator releases or discards it")
+	QuarantinedBlobCreated    = ffm("QuarantinedBlob.created", "The time the blob was quarantined")
+	QuarantinedBlobUpdated    = ffm("QuarantinedBlob.updated", "The time the blob was released or discarded")
+
+	// SLABreach field descriptions
+	SLABreachID        = ffm("SLABreach.id", "The UUID of the SLA breach")
+	SLABreachNamespace = ffm("SLABreach.namespace", "The namespace of the message")
+	SLABreachType      = ffm("SLABreach.type", "Whether the message was confirmed late by this node, or delivered late to the node of another member")
+	SLABreachMessage   = ffm("SLABreach.message", "The UUID of the message sent by this node")
+	SLABreachTopic     = ffm("SLABreach.topic", "The topic of the message the SLA applies to")
+	SLABreachOrg       = ffm("SLABreach.org", "The DID of the org that owns the node that confirmed the message")
+	SLABreachNode      = ffm("SLABreach.node", "The UUID of the node that confirmed the message")
+	SLABreachSLA       = ffm("SLABreach.sla", "The SLA of the topic")
+	SLABreachLatency   = ffm("SLABreach.latency", "The time from the message being created to it being confirmed, measured on the clock of this node")
+	SLABreachCreated   = ffm("SLABreach.created", "The time the SLA breach was recorded")
+
+	// DisclosureRoot field descriptions
+	DisclosureRootID   = ffm("DisclosureRoot.id", "The UUID of the data item")
+	DisclosureRootRoot = ffm("DisclosureRoot.root", "The root of the Merkle tree of the salted hashes of the fields of the data value")
+
+	// DisclosureInput field descriptions
+	DisclosureInputData   = ffm("DisclosureInput.data", "The UUID of the data item of the message to disclose fields of. Can be omitted if the message has a single data item")
+	DisclosureInputFields = ffm("DisclosureInput.fields", "The names of the top-level fields of the data value to disclose")
+
+	// DisclosureProof field descriptions
+	DisclosureProofBatch   = ffm("DisclosureProof.batch", "The manifest of the batch the message was pinned in, the hash of which is the batch hash pinned to the blockchain")
+	DisclosureProofMessage = ffm("DisclosureProof.message", "The header and data references of the message, which are covered by the hash of the message in the batch manifest")
+	DisclosureProofData    = ffm("DisclosureProof.data", "The UUID of the data item the fields are disclosed from")
+	DisclosureProofFields  = ffm("DisclosureProof.fields", "The disclosed fields, each with the proof that it is part of the disclosure root of the data in the batch manifest")
+
+	// DisclosedField field descriptions
+	DisclosedFieldField = ffm("DisclosedField.field", "The name of the field")
+	DisclosedFieldValue = ffm("DisclosedField.value", "The value of the field")
+	DisclosedFieldSalt  = ffm("DisclosedField.salt", "The salt hashed with the value of the field")
+	DisclosedFieldPath  = ffm("DisclosedField.path", "The sibling hashes on the path from the salted hash of the field to the disclosure root of the data")
+
+	// DisclosurePathStep field descriptions
+	DisclosurePathStepHash = ffm("DisclosurePathStep.hash", "The sibling hash")
+	DisclosurePathStepLeft = ffm("DisclosurePathStep.left", "True if the sibling hash is on the left of the hash being proved")
+
+	// DisclosureVerification field descriptions
+	DisclosureVerificationValid     = ffm("DisclosureVerification.valid", "True if the disclosed fields are part of a batch pinned to the blockchain")
+	DisclosureVerificationReason    = ffm("DisclosureVerification.reason", "The reason the proof is not valid")
+	DisclosureVerificationBatch     = ffm("DisclosureVerification.batch", "The UUID of the pinned batch")
+	DisclosureVerificationBatchHash = ffm("DisclosureVerification.batchHash", "The hash of the pinned batch")
+	DisclosureVerificationSigner    = ffm("DisclosureVerification.signer", "The blockchain key that pinned the batch")
+	DisclosureVerificationMessage   = ffm("DisclosureVerification.message", "The UUID of the message the fields were disclosed from")
+	DisclosureVerificationData      = ffm("DisclosureVerification.data", "The UUID of the data item the fields were disclosed from")
+	DisclosureVerificationFields    = ffm("DisclosureVerification.fields", "The names of the fields that were verified")
+
+	// DeadLetterAttempt field descriptions
+	DeadLetterAttemptTime   = ffm("DeadLetterAttempt.time", "The time the delivery attempt failed")
+	DeadLetterAttemptStatus = ffm("DeadLetterAttempt.status", "The HTTP status code returned by the delivery attempt, if a response was received")
+	DeadLetterAttemptError  = ffm("DeadLetterAttempt.error", "The error from the delivery attempt")
+
 	// Event field descriptions
 	EventID          = ffm("Event.id", "The UUID assigned to this event by your local FireFly node")
 	EventSequence    = ffm("Event.sequence", "A sequence indicating the order in which events are delivered to your application. Assure to be unique per event in your local FireFly database (unlike the created timestamp)")
@@ -347,13 +530,108 @@ var (
 	EnrichedEventContractAPI       = ffm("EnrichedEvent.contractAPI", "A Contract API if referenced by the FireFly event")
 	EnrichedEventContractInterface = ffm("EnrichedEvent.contractInterface", "A Contract Interface (FFI) if referenced by the FireFly event")
 	EnrichedEventDatatype          = ffm("EnrichedEvent.datatype", "A Datatype if referenced by the FireFly event")
+	EnrichedEventDeadLetter        = ffm("EnrichedEvent.deadLetter", "A Dead Letter if referenced by the FireFly event")
 	EnrichedEventIdentity          = ffm("EnrichedEvent.identity", "An Identity if referenced by the FireFly event")
 	EnrichedEventMessage           = ffm("EnrichedEvent.message", "A Message if  referenced by the FireFly event")
+	EnrichedEventParentMessage     = ffm("EnrichedEvent.parentMessage", "The message that the referenced Message replies to in a thread, if it is available on this node")
+	EnrichedEventQuarantinedBatch  = ffm("EnrichedEvent.quarantinedBatch", "A Quarantined Batch if referenced by the FireFly event")
+	EnrichedEventQuarantinedBlob   = ffm("EnrichedEvent.quarantinedBlob", "A Quarantined Blob if referenced by the FireFly event")
+	EnrichedEventSLABreach         = ffm("EnrichedEvent.slaBreach", "An SLA Breach if referenced by the FireFly event")
 	EnrichedEventNamespaceDetails  = ffm("EnrichedEvent.namespaceDetails", "Full resource detail of a Namespace if referenced by the FireFly event")
 	EnrichedEventTokenApproval     = ffm("EnrichedEvent.tokenApproval", "A Token Approval if referenced by the FireFly event")
 	EnrichedEventTokenPool         = ffm("EnrichedEvent.tokenPool", "A Token Pool if referenced by the FireFly event")
+	EnrichedEventTokenSwap         = ffm("EnrichedEvent.tokenSwap", "A Token Swap if referenced by the FireFly event")
+	EnrichedEventTokenMismatch     = ffm("EnrichedEvent.tokenMismatch", "A Token Balance Mismatch if referenced by the FireFly event")
 	EnrichedEventTokenTransfer     = ffm("EnrichedEvent.tokenTransfer", "A Token Transfer if referenced by the FireFly event")
 	EnrichedEventTransaction       = ffm("EnrichedEvent.transaction", "A Transaction if associated with the FireFly event")
+	EnrichedEventOperation         = ffm("EnrichedEvent.operation", "An Operation if referenced by the FireFly event")
+	EnrichedEventReceipt           = ffm("EnrichedEvent.receipt", "The receipt of the blockchain transaction behind a blockchain_event_received or transaction_submitted event, if the subscription is set to include receipts")
+
+	// EventDelivery field descriptions
+	EventDeliverySubscription = ffm("EventDelivery.subscription", "The subscription the event was delivered on")
+	EventDeliveryLabels       = ffm("EventDelivery.labels", "Labels added to the event by the event rules of the namespace")
+
+	// EventRule field descriptions
+	EventRuleID        = ffm("EventRule.id", "The UUID of the event rule")
+	EventRuleNamespace = ffm("EventRule.namespace", "The namespace of the event rule")
+	EventRuleName      = ffm("EventRule.name", "The name of the event rule")
+	EventRuleAction    = ffm("EventRule.action", "What the rule does with matching events for the subscriptions it targets - route, label or suppress")
+	EventRuleMatch     = ffm("EventRule.match", "The conditions an event must meet for the rule to apply to it. Matches all events if not set")
+	EventRuleTargets   = ffm("EventRule.targets", "The subscriptions the rule applies to. Applies to all subscriptions in the namespace if not set")
+	EventRuleLabels    = ffm("EventRule.labels", "Labels to add to matching events when they are delivered. Required for label rules, and optional for route rules")
+	EventRuleCreated   = ffm("EventRule.created", "Creation time of the event rule")
+
+	// EventRuleMatch field descriptions
+	EventRuleMatchEvents     = ffm("EventRuleMatch.events", "A regular expression the event type must match")
+	EventRuleMatchTopic      = ffm("EventRuleMatch.topic", "A regular expression the topic of the event must match")
+	EventRuleMatchExpression = ffm("EventRuleMatch.expression", "A CEL expression over the enriched event, available as 'event', that must evaluate to true")
+
+	// EventRuleTargets field descriptions
+	EventRuleTargetsSubscriptions = ffm("EventRuleTargets.subscriptions", "The names of the subscriptions the rule applies to")
+	EventRuleTargetsTransports    = ffm("EventRuleTargets.transports", "The transports of the subscriptions the rule applies to")
+
+	// JobInput field descriptions
+	JobInputType  = ffm("JobInput.type", "The type of job - subscription_rewind, token_pool_snapshot, token_snapshot_export, token_transfers_export or token_ledger_export")
+	JobInputInput = ffm("JobInput.input", "The input of the job, which depends on its type")
+
+	// JobProgress field descriptions
+	JobProgressDone  = ffm("JobProgress.done", "The amount of work the job has completed, in units specific to the type of job, such as bytes written for an export")
+	JobProgressTotal = ffm("JobProgress.total", "The total amount of work the job has to do, when it is known")
+
+	// Job field descriptions
+	JobID        = ffm("Job.id", "The UUID of the job")
+	JobNamespace = ffm("Job.namespace", "The namespace of the job")
+	JobType      = ffm("Job.type", "The type of job")
+	JobStatus    = ffm("Job.status", "The status of the job - pending until a worker is free, running, then succeeded, failed or cancelled")
+	JobInput     = ffm("Job.input", "The input of the job, which depends on its type")
+	JobOutput    = ffm("Job.output", "The output of the job once it has succeeded, which depends on its type. Jobs that write a file have the name and size of the file, which can be downloaded from the output route of the job")
+	JobProgress  = ffm("Job.progress", "The progress of the job")
+	JobError     = ffm("Job.error", "The error that caused the job to fail")
+	JobCreated   = ffm("Job.created", "The time the job was submitted")
+	JobUpdated   = ffm("Job.updated", "The time the job was last updated")
+
+	// JobSubscriptionRewindInput field descriptions
+	JobSubscriptionRewindInputSubscription = ffm("JobSubscriptionRewindInput.subscription", "The ID of the durable subscription to rewind")
+
+	// JobTokenPoolSnapshotInput field descriptions
+	JobTokenPoolSnapshotInputPool = ffm("JobTokenPoolSnapshotInput.pool", "The name or ID of the token pool to snapshot")
+
+	// JobTokenSnapshotExportInput field descriptions
+	JobTokenSnapshotExportInputSnapshot = ffm("JobTokenSnapshotExportInput.snapshot", "The ID of the token snapshot to export")
+
+	// RoleBinding field descriptions
+	RoleBindingID        = ffm("RoleBinding.id", "The UUID of the role binding")
+	RoleBindingNamespace = ffm("RoleBinding.namespace", "The namespace the role is bound in")
+	RoleBindingPrincipal = ffm("RoleBinding.principal", "The authenticated principal the role is bound to - the username of HTTP basic auth, or the value of the configured principal header")
+	RoleBindingRole      = ffm("RoleBinding.role", "The role bound to the principal - reader, sender, token-admin or ns-admin")
+	RoleBindingCreated   = ffm("RoleBinding.created", "Creation time of the role binding")
+
+	// AuditLogEntry field descriptions
+	AuditLogEntryID             = ffm("AuditLogEntry.id", "The UUID of the audit log entry")
+	AuditLogEntryNamespace      = ffm("AuditLogEntry.namespace", "The namespace the call was made to")
+	AuditLogEntryPrincipal      = ffm("AuditLogEntry.principal", "The authenticated principal that made the call, when there is one")
+	AuditLogEntryMethod         = ffm("AuditLogEntry.method", "The HTTP method of the call, or GRPC for calls to the gRPC API")
+	AuditLogEntryRoute          = ffm("AuditLogEntry.route", "The route that was called, such as messages/broadcast, or the full name of the gRPC method")
+	AuditLogEntryPath           = ffm("AuditLogEntry.path", "The path of the HTTP request")
+	AuditLogEntryRequestHash    = ffm("AuditLogEntry.requestHash", "The SHA256 hash of the body of the request")
+	AuditLogEntryIdempotencyKey = ffm("AuditLogEntry.idempotencyKey", "The idempotency key of the request, when one was supplied")
+	AuditLogEntryTransaction    = ffm("AuditLogEntry.tx", "The UUID of the FireFly transaction started by the call, when there is one")
+	AuditLogEntryStatus         = ffm("AuditLogEntry.status", "The HTTP status of the result of the call")
+	AuditLogEntryError          = ffm("AuditLogEntry.error", "The error returned by the call, if it failed")
+	AuditLogEntryCreated        = ffm("AuditLogEntry.created", "The time the call completed")
+
+	// SlowRequest field descriptions
+	SlowRequestServer    = ffm("SlowRequest.server", "The server the request was made to - rest for the API, or admin for the SPI")
+	SlowRequestNamespace = ffm("SlowRequest.namespace", "The namespace of the request, when the route is within a namespace")
+	SlowRequestPrincipal = ffm("SlowRequest.principal", "The principal that made the request, when there is one")
+	SlowRequestMethod    = ffm("SlowRequest.method", "The HTTP method of the request")
+	SlowRequestRoute     = ffm("SlowRequest.route", "The route of the request, such as messages/{msgid}")
+	SlowRequestPath      = ffm("SlowRequest.path", "The path of the request")
+	SlowRequestQuery     = ffm("SlowRequest.query", "The query string of the request")
+	SlowRequestStatus    = ffm("SlowRequest.status", "The HTTP status of the response")
+	SlowRequestTraceID   = ffm("SlowRequest.traceId", "The trace ID of the request, from its traceparent header, or otherwise its FireFly request ID")
+	SlowRequestStarted   = ffm("SlowRequest.started", "The time the request was received")
+	SlowRequestDuration  = ffm("SlowRequest.duration", "The time taken to complete the request")
 
 	// IdentityMessages field descriptions
 	IdentityMessagesClaim        = ffm("IdentityMessages.claim", "The UUID of claim message")
@@ -405,6 +683,7 @@ var (
 	NamespaceName                  = ffm("Namespace.name", "The local namespace name")
 	NamespaceNetworkName           = ffm("Namespace.networkName", "The shared namespace name within the multiparty network")
 	NamespaceDescription           = ffm("Namespace.description", "A description of the namespace")
+	NamespaceTenant                = ffm("Namespace.tenant", "The tenant the namespace belongs to, if any")
 	NamespaceCreated               = ffm("Namespace.created", "The time the namespace was created")
 	MultipartyContractsActive      = ffm("MultipartyContracts.active", "The currently active FireFly smart contract")
 	MultipartyContractsTerminated  = ffm("MultipartyContracts.terminated", "Previously-terminated FireFly smart contracts")
@@ -453,8 +732,49 @@ var (
 	NamespaceStatusPluginsTokens        = ffm("NamespaceStatusPlugins.tokens", "The token plugins on this namespace")
 
 	// NamespaceStatusPlugin field descriptions
-	NamespaceStatusPluginName = ffm("NamespaceStatusPlugin.name", "The name of the plugin")
-	NamespaceStatusPluginType = ffm("NamespaceStatusPlugin.pluginType", "The type of the plugin")
+	NamespaceStatusPluginName    = ffm("NamespaceStatusPlugin.name", "The name of the plugin")
+	NamespaceStatusPluginType    = ffm("NamespaceStatusPlugin.pluginType", "The type of the plugin")
+	NamespaceStatusPluginBreaker = ffm("NamespaceStatusPlugin.breaker", "For token connectors, the state of the circuit breaker in front of the REST calls to the connector")
+
+	// NetworkStatus field descriptions
+	NetworkStatusBlockchain          = ffm("NetworkStatus.blockchain", "The status of the connection to the blockchain, for each blockchain plugin on this namespace")
+	NetworkStatusListeners           = ffm("NetworkStatus.listeners", "The last block processed by FireFly for each contract listener on this namespace, for the page of listeners matching the filter")
+	NetworkStatusTotalListeners      = ffm("NetworkStatus.totalListeners", "The total number of contract listeners matching the filter, when the count is requested")
+	NetworkStatusPendingTransactions = ffm("NetworkStatus.pendingTransactions", "The number of blockchain operations submitted by this node that are still pending")
+
+	// BlockchainNetworkStatus field descriptions
+	BlockchainNetworkStatusName        = ffm("BlockchainNetworkStatus.name", "The name of the blockchain plugin")
+	BlockchainNetworkStatusPluginType  = ffm("BlockchainNetworkStatus.pluginType", "The type of the blockchain plugin")
+	BlockchainNetworkStatusChainHead   = ffm("BlockchainNetworkStatus.chainHead", "The block number of the head of the chain, for blockchain connectors that report it")
+	BlockchainNetworkStatusEventStream = ffm("BlockchainNetworkStatus.eventStream", "The status of the event stream the plugin uses to receive events from the connector")
+	BlockchainNetworkStatusCatchup     = ffm("BlockchainNetworkStatus.catchup", "The progress of replaying events missed while the plugin was disconnected from the connector, if supported by the plugin")
+	BlockchainNetworkStatusError       = ffm("BlockchainNetworkStatus.error", "Set if the status could not be retrieved from the blockchain connector")
+
+	// BlockchainCatchupStatus field descriptions
+	BlockchainCatchupStatusActive    = ffm("BlockchainCatchupStatus.active", "Whether the plugin is currently catching up on a backlog of events from the connector")
+	BlockchainCatchupStatusStarted   = ffm("BlockchainCatchupStatus.started", "The time the most recent catch-up started, when the plugin connected to the connector")
+	BlockchainCatchupStatusCompleted = ffm("BlockchainCatchupStatus.completed", "The time the most recent catch-up completed")
+	BlockchainCatchupStatusEvents    = ffm("BlockchainCatchupStatus.events", "The number of events replayed during the most recent catch-up")
+	BlockchainCatchupStatusLastBlock = ffm("BlockchainCatchupStatus.lastBlock", "The highest block number replayed during the most recent catch-up")
+	BlockchainCatchupStatusListeners = ffm("BlockchainCatchupStatus.listeners", "The replay progress of each listener on the event stream during the most recent catch-up")
+
+	// BlockchainCatchupListenerStatus field descriptions
+	BlockchainCatchupListenerStatusBackendID = ffm("BlockchainCatchupListenerStatus.backendId", "The ID of the listener in the blockchain connector")
+	BlockchainCatchupListenerStatusName      = ffm("BlockchainCatchupListenerStatus.name", "The name of the listener in the blockchain connector")
+	BlockchainCatchupListenerStatusFromBlock = ffm("BlockchainCatchupListenerStatus.fromBlock", "The checkpoint block of the listener when catch-up started, which the replay resumes from")
+	BlockchainCatchupListenerStatusLastBlock = ffm("BlockchainCatchupListenerStatus.lastBlock", "The highest block number replayed for this listener")
+	BlockchainCatchupListenerStatusEvents    = ffm("BlockchainCatchupListenerStatus.events", "The number of events replayed for this listener")
+
+	// EventStreamNetworkStatus field descriptions
+	EventStreamNetworkStatusID        = ffm("EventStreamNetworkStatus.id", "The ID of the event stream in the blockchain connector")
+	EventStreamNetworkStatusConnected = ffm("EventStreamNetworkStatus.connected", "Whether the event stream is active and delivering events")
+
+	// ListenerNetworkStatus field descriptions
+	ListenerNetworkStatusID                 = ffm("ListenerNetworkStatus.id", "The UUID of the contract listener")
+	ListenerNetworkStatusName               = ffm("ListenerNetworkStatus.name", "The name of the contract listener")
+	ListenerNetworkStatusBackendID          = ffm("ListenerNetworkStatus.backendId", "An ID assigned by the blockchain connector to this listener")
+	ListenerNetworkStatusLastProcessedBlock = ffm("ListenerNetworkStatus.lastProcessedBlock", "The block number of the most recent event FireFly has processed for this listener. Omitted if no events have been processed for the listener")
+	ListenerNetworkStatusBlockLag           = ffm("ListenerNetworkStatus.blockLag", "How many blocks the last processed event is behind the chain head. Omitted if the chain head or the last processed block is not known")
 
 	// NamespaceStatusMultiparty field descriptions
 	NamespaceMultipartyEnabled  = ffm("NamespaceStatusMultiparty.enabled", "Whether multi-party mode is enabled for this namespace")
@@ -468,6 +788,45 @@ var (
 	BatchProcessorStatusName       = ffm("BatchProcessorStatus.name", "The name of the processor, which includes details of the attributes of message are allocated to this processor")
 	BatchProcessorStatusStatus     = ffm("BatchProcessorStatus.status", "The flush status for this batch processor")
 
+	// BatchQueueStatus field descriptions
+	BatchQueueStatusDepth         = ffm("BatchQueueStatus.depth", "The number of messages that have been read for batching and not yet sent, across all topics")
+	BatchQueueStatusDispatcherLag = ffm("BatchQueueStatus.dispatcherLag", "The time between the most recently read message being ready to send, and it being read for batching")
+	BatchQueueStatusTopics        = ffm("BatchQueueStatus.topics", "The queue of unsent messages on each topic that has at least one")
+
+	// BatchTopicQueueStatus field descriptions
+	BatchTopicQueueStatusTopic        = ffm("BatchTopicQueueStatus.topic", "The topic")
+	BatchTopicQueueStatusDepth        = ffm("BatchTopicQueueStatus.depth", "The number of messages on the topic that have been read for batching and not yet sent")
+	BatchTopicQueueStatusOldest       = ffm("BatchTopicQueueStatus.oldest", "When the oldest unsent message on the topic was ready to send")
+	BatchTopicQueueStatusOldestAge    = ffm("BatchTopicQueueStatus.oldestAge", "How long the oldest unsent message on the topic has been waiting to be sent")
+	BatchTopicQueueStatusBackpressure = ffm("BatchTopicQueueStatus.backpressure", "True if new messages on the topic are being rejected, because the queue is over its configured limits")
+
+	// BatchFlushRequest field descriptions
+	BatchFlushRequestTopic = ffm("BatchFlushRequest.topic", "If set, only the batches being assembled with a message on this topic are flushed. Otherwise all batches being assembled in the namespace are flushed")
+
+	// BatchFlushResult field descriptions
+	BatchFlushResultProcessors = ffm("BatchFlushResult.processors", "The number of batch processors that were asked to flush the batch they are assembling")
+
+	// MessageThread field descriptions
+	MessageThreadRoot      = ffm("MessageThread.root", "The ID of the root message of the thread, which is the earliest message in the thread that is available on this node")
+	MessageThreadMessages  = ffm("MessageThread.messages", "The messages in the thread, starting from the root message, with each generation of replies in the order they were received")
+	MessageThreadTruncated = ffm("MessageThread.truncated", "True if the thread has more messages than can be returned")
+
+	// TopicSequence field descriptions
+	TopicSequenceNamespace = ffm("TopicSequence.namespace", "The namespace of the topic")
+	TopicSequenceContext   = ffm("TopicSequence.context", "The hash of the topic, and of the group for private messages, which is the same context that pins are sequenced on")
+	TopicSequenceTopic     = ffm("TopicSequence.topic", "The strict topic")
+	TopicSequenceGroup     = ffm("TopicSequence.group", "The privacy group, for private messages")
+	TopicSequenceNext      = ffm("TopicSequence.next", "The topicSequence of the next message to be confirmed on the context. Messages with a later sequence are parked until this message is confirmed")
+	TopicSequenceUpdated   = ffm("TopicSequence.updated", "The time the sequence was last updated")
+
+	// BatchPolicy field descriptions
+	BatchPolicyTopic      = ffm("BatchPolicy.topic", "The topic the policy applies to")
+	BatchPolicyNamespace  = ffm("BatchPolicy.namespace", "The namespace of the topic")
+	BatchPolicyMaxLatency = ffm("BatchPolicy.maxLatency", "The longest time a message on the topic waits in a batch before the batch is flushed, if shorter than the batch timeout of the dispatcher")
+	BatchPolicyMaxSize    = ffm("BatchPolicy.maxSize", "The number of messages at which a batch containing a message on the topic is flushed, if smaller than the batch size of the dispatcher")
+	BatchPolicyCreated    = ffm("BatchPolicy.created", "The time the policy was first set")
+	BatchPolicyUpdated    = ffm("BatchPolicy.updated", "The last time the policy was changed")
+
 	// BatchFlushStatus field descriptions
 	BatchFlushStatusLastFlushTime        = ffm("BatchFlushStatus.lastFlushStartTime", "The last time a flush was performed")
 	BatchFlushStatusFlushing             = ffm("BatchFlushStatus.flushing", "If a flush is in progress, this is the UUID of the batch being flushed")
@@ -519,6 +878,8 @@ var (
 	SubscriptionFilterMessage          = ffm("SubscriptionFilter.message", "Filters specific to message events. If an event is not a message event, these filters are ignored")
 	SubscriptionFilterTransaction      = ffm("SubscriptionFilter.transaction", "Filters specific to events with a transaction. If an event is not associated with a transaction, this filter is ignored")
 	SubscriptionFilterBlockchainEvent  = ffm("SubscriptionFilter.blockchainevent", "Filters specific to blockchain events. If an event is not a blockchain event, these filters are ignored")
+	SubscriptionFilterTokenTransfer    = ffm("SubscriptionFilter.tokentransfer", "Filters specific to token transfer events. If set, events that do not reference a token transfer are not delivered")
+	SubscriptionFilterExpression       = ffm("SubscriptionFilter.expression", "A CEL expression evaluated against the enriched event as the variable 'event', which must return true for the event to be dispatched. For example: event.tokenTransfer.to == '0x1234' && int(event.tokenTransfer.amount) > 1000")
 	SubscriptionFilterDeprecatedTopics = ffm("SubscriptionFilter.topics", "Deprecated: Please use 'topic' instead")
 	SubscriptionFilterDeprecatedTag    = ffm("SubscriptionFilter.tag", "Deprecated: Please use 'message.tag' instead")
 	SubscriptionFilterDeprecatedGroup  = ffm("SubscriptionFilter.group", "Deprecated: Please use 'message.group' instead")
@@ -532,33 +893,92 @@ var (
 	// SubscriptionTransactionFilter field descriptions
 	SubscriptionTransactionFilterType = ffm("SubscriptionTransactionFilter.type", "Regular expression to apply to the transaction 'type' field")
 
+	// SubscriptionTokenTransferFilter field descriptions
+	SubscriptionTokenTransferFilterPool       = ffm("SubscriptionTokenTransferFilter.pool", "Regular expression to apply to the token transfer 'pool' field, which is the UUID of the token pool")
+	SubscriptionTokenTransferFilterTokenIndex = ffm("SubscriptionTokenTransferFilter.tokenIndex", "Regular expression to apply to the token transfer 'tokenIndex' field, for non-fungible tokens")
+	SubscriptionTokenTransferFilterFrom       = ffm("SubscriptionTokenTransferFilter.from", "Regular expression to apply to the token transfer 'from' field")
+	SubscriptionTokenTransferFilterTo         = ffm("SubscriptionTokenTransferFilter.to", "Regular expression to apply to the token transfer 'to' field")
+	SubscriptionTokenTransferFilterMinAmount  = ffm("SubscriptionTokenTransferFilter.minAmount", "Only deliver token transfers with an amount greater than or equal to this value")
+	SubscriptionTokenTransferFilterMaxAmount  = ffm("SubscriptionTokenTransferFilter.maxAmount", "Only deliver token transfers with an amount less than or equal to this value")
+
 	// SubscriptionBlockchainEventFilter field descriptions
 	SubscriptionBlockchainEventFilterName     = ffm("SubscriptionBlockchainEventFilter.name", "Regular expression to apply to the blockchain event 'name' field, which is the name of the event in the underlying blockchain smart contract")
 	SubscriptionBlockchainEventFilterListener = ffm("SubscriptionBlockchainEventFilter.listener", "Regular expression to apply to the blockchain event 'listener' field, which is the UUID of the event listener. So you can restrict your subscription to certain blockchain listeners. Alternatively to avoid your application need to know listener UUIDs you can set the 'topic' field of blockchain event listeners, and use a topic filter on your subscriptions")
 
 	// SubscriptionCoreOptions field descriptions
-	SubscriptionCoreOptionsFirstEvent = ffm("SubscriptionCoreOptions.firstEvent", "Whether your application would like to receive events from the 'oldest' event emitted by your FireFly node (from the beginning of time), or the 'newest' event (from now), or a specific event sequence. Default is 'newest'")
-	SubscriptionCoreOptionsReadAhead  = ffm("SubscriptionCoreOptions.readAhead", "The number of events to stream ahead to your application, while waiting for confirmation of consumption of those events. At least once delivery semantics are used in FireFly, so if your application crashes/reconnects this is the maximum number of events you would expect to be redelivered after it restarts")
-	SubscriptionCoreOptionsWithData   = ffm("SubscriptionCoreOptions.withData", "Whether message events delivered over the subscription, should be packaged with the full data of those messages in-line as part of the event JSON payload. Or if the application should make separate REST calls to download that data. May not be supported on some transports.")
+	SubscriptionCoreOptionsFirstEvent    = ffm("SubscriptionCoreOptions.firstEvent", "Whether your application would like to receive events from the 'oldest' event emitted by your FireFly node (from the beginning of time), or the 'newest' event (from now), or a specific event sequence. Default is 'newest'")
+	SubscriptionCoreOptionsReadAhead     = ffm("SubscriptionCoreOptions.readAhead", "The number of events to stream ahead to your application, while waiting for confirmation of consumption of those events. At least once delivery semantics are used in FireFly, so if your application crashes/reconnects this is the maximum number of events you would expect to be redelivered after it restarts")
+	SubscriptionCoreOptionsFormat        = ffm("SubscriptionCoreOptions.format", "The envelope to deliver events in. 'native' (the default) delivers the FireFly event, and 'cloudevents' wraps it in a CloudEvents 1.0 envelope. Only supported by the websockets, webhooks, kafka, sqs, sns and pubsub transports")
+	SubscriptionCoreOptionsConsumerGroup = ffm("SubscriptionCoreOptions.consumerGroup", "Shares the events of the subscription across all the connections that start it, instead of delivering to one connection at a time. Only supported by the websockets transport")
+	SubscriptionCoreOptionsBatch         = ffm("SubscriptionCoreOptions.batch", "Delivers events to the transport in batches, for transports that support it")
+	SubscriptionCoreOptionsDedupWindow   = ffm("SubscriptionCoreOptions.dedupWindow", "Suppresses the delivery of an event with the same correlator and type as an event already delivered, if it was created within this window of that event")
+	SubscriptionCoreOptionsDelivery      = ffm("SubscriptionCoreOptions.delivery", "Controls the rate and concurrency of event delivery to the consumer")
+	SubscriptionCoreOptionsWithReceipt   = ffm("SubscriptionCoreOptions.withReceipt", "Whether blockchain_event_received and transaction_submitted events delivered over the subscription, should be packaged with the receipt of the blockchain transaction in-line. Saves the application a separate REST call to get the status of the transaction")
+	SubscriptionCoreOptionsWithData      = ffm("SubscriptionCoreOptions.withData", "Whether message events delivered over the subscription, should be packaged with the full data of those messages in-line as part of the event JSON payload. Or if the application should make separate REST calls to download that data. May not be supported on some transports.")
+
+	// SubscriptionRewind field descriptions
+	SubscriptionRewindSequence       = ffm("SubscriptionRewind.sequence", "The sequence of the first event to redeliver. Either sequence or timestamp must be specified")
+	SubscriptionRewindTimestamp      = ffm("SubscriptionRewind.timestamp", "Redeliver events created at or after this time. Either sequence or timestamp must be specified")
+	SubscriptionRewindDryRun         = ffm("SubscriptionRewind.dryRun", "When true the offset of the subscription is not changed, and the response reports what the rewind would do")
+	SubscriptionRewindPreviousOffset = ffm("SubscriptionRewind.previousOffset", "The offset of the subscription before the rewind")
+	SubscriptionRewindOffset         = ffm("SubscriptionRewind.offset", "The offset of the subscription after the rewind. Delivery resumes from the next event after this sequence")
+	SubscriptionRewindEvents         = ffm("SubscriptionRewind.events", "The number of events in the namespace between the new and previous offsets. Events that do not match the subscription filter are counted, but not redelivered")
+
+	// SubscriptionTemplate field descriptions
+	SubscriptionTemplateID        = ffm("SubscriptionTemplate.id", "The UUID of the subscription template")
+	SubscriptionTemplateNamespace = ffm("SubscriptionTemplate.namespace", "The namespace of the subscription template")
+	SubscriptionTemplateName      = ffm("SubscriptionTemplate.name", "The name of the subscription template. Each subscription provisioned from the template is named '<template>-<resource>'")
+	SubscriptionTemplateTrigger   = ffm("SubscriptionTemplate.trigger", "The kind of resource whose creation provisions a subscription from the template")
+	SubscriptionTemplateMatch     = ffm("SubscriptionTemplate.match", "A regular expression the name of the contract API, token pool or topic must match. Matches all resources of the trigger type if not set")
+	SubscriptionTemplateTransport = ffm("SubscriptionTemplate.transport", "The transport plugin of the provisioned subscriptions")
+	SubscriptionTemplateFilter    = ffm("SubscriptionTemplate.filter", "The filter of the provisioned subscriptions. String values can refer to the resource with the Go template actions {{.Name}}, {{.ID}} and {{.Trigger}}")
+	SubscriptionTemplateOptions   = ffm("SubscriptionTemplate.options", "The options of the provisioned subscriptions. String values can refer to the resource with the Go template actions {{.Name}}, {{.ID}} and {{.Trigger}}")
+	SubscriptionTemplateCreated   = ffm("SubscriptionTemplate.created", "Creation time of the subscription template")
+
+	// SubscriptionPull field descriptions
+	SubscriptionPullBatchSize = ffm("SubscriptionPull.batchSize", "The maximum number of events to return. Defaults to 1, and is limited by the readAhead of the subscription")
+	SubscriptionPullWait      = ffm("SubscriptionPull.wait", "How long to wait for an event if none are available. Defaults to returning immediately, and is limited by the maxWait of the pull transport")
+
+	// SubscriptionAck field descriptions
+	SubscriptionAckEvents = ffm("SubscriptionAck.events", "The IDs of the pulled events to acknowledge")
+	SubscriptionAckReject = ffm("SubscriptionAck.reject", "When true the events are rejected instead, and the events are redelivered from the earliest rejected event")
+
+	// PulledEvent field descriptions
+	PulledEventData = ffm("PulledEvent.data", "The data of the message, if the subscription includes data")
+
+	// SubscriptionConsumerGroup field descriptions
+	SubscriptionConsumerGroupDelivery     = ffm("SubscriptionConsumerGroup.delivery", "How events are shared between the connections in the group. 'roundrobin' (the default) delivers to each connection in turn, and 'partition' delivers all events with the same partition key to the same connection")
+	SubscriptionConsumerGroupPartitionKey = ffm("SubscriptionConsumerGroup.partitionKey", "The field events are partitioned on with 'partition' delivery - 'topic' (the default), 'group' or 'author'")
+
+	// SubscriptionBatchOptions field descriptions
+	SubscriptionBatchOptionsSize    = ffm("SubscriptionBatchOptions.size", "The maximum number of events in a batch")
+	SubscriptionBatchOptionsTimeout = ffm("SubscriptionBatchOptions.timeout", "The maximum time to wait for a batch to fill, after the first event arrives")
+
+	// SubscriptionDeliveryOptions field descriptions
+	SubscriptionDeliveryOptionsMode        = ffm("SubscriptionDeliveryOptions.mode", "'ordered' delivers one event at a time, waiting for each to be acknowledged. 'parallel' invokes the transport concurrently for each in flight event, without ordering. By default events are streamed in order up to the read ahead")
+	SubscriptionDeliveryOptionsMaxInflight = ffm("SubscriptionDeliveryOptions.maxInflight", "The maximum number of events delivered but not yet acknowledged. Overrides readAhead, and is ignored in 'ordered' mode")
+	SubscriptionDeliveryOptionsRateLimit   = ffm("SubscriptionDeliveryOptions.rateLimit", "The maximum number of events delivered per second. Unlimited if not set")
 
 	// TokenApproval field descriptions
-	TokenApprovalLocalID         = ffm("TokenApproval.localId", "The UUID of this token approval, in the local FireFly node")
-	TokenApprovalPool            = ffm("TokenApproval.pool", "The UUID the token pool this approval applies to")
-	TokenApprovalConnector       = ffm("TokenApproval.connector", "The name of the token connector, as specified in the FireFly core configuration file. Required on input when there are more than one token connectors configured")
-	TokenApprovalKey             = ffm("TokenApproval.key", "The blockchain signing key for the approval request. On input defaults to the first signing key of the organization that operates the node")
-	TokenApprovalOperator        = ffm("TokenApproval.operator", "The blockchain identity that is granted the approval")
-	TokenApprovalApproved        = ffm("TokenApproval.approved", "Whether this record grants permission for an operator to perform actions on the token balance (true), or revokes permission (false)")
-	TokenApprovalInfo            = ffm("TokenApproval.info", "Token connector specific information about the approval operation, such as whether it applied to a limited balance of a fungible token. See your chosen token connector documentation for details")
-	TokenApprovalNamespace       = ffm("TokenApproval.namespace", "The namespace for the approval, which must match the namespace of the token pool")
-	TokenApprovalProtocolID      = ffm("TokenApproval.protocolId", "An alphanumerically sortable string that represents this event uniquely with respect to the blockchain")
-	TokenApprovalSubject         = ffm("TokenApproval.subject", "A string identifying the parties and entities in the scope of this approval, as provided by the token connector")
-	TokenApprovalActive          = ffm("TokenApproval.active", "Indicates if this approval is currently active (only one approval can be active per subject)")
-	TokenApprovalMessage         = ffm("TokenApproval.message", "The UUID of a message that has been correlated with this approval using the data field of the approval in a compatible token connector")
-	TokenApprovalMessageHash     = ffm("TokenApproval.messageHash", "The hash of a message that has been correlated with this approval using the data field of the approval in a compatible token connector")
-	TokenApprovalCreated         = ffm("TokenApproval.created", "The creation time of the token approval")
-	TokenApprovalTX              = ffm("TokenApproval.tx", "If submitted via FireFly, this will reference the UUID of the FireFly transaction (if the token connector in use supports attaching data)")
-	TokenApprovalBlockchainEvent = ffm("TokenApproval.blockchainEvent", "The UUID of the blockchain event")
-	TokenApprovalConfig          = ffm("TokenApproval.config", "Input only field, with token connector specific configuration of the approval.  See your chosen token connector documentation for details")
+	TokenApprovalLocalID          = ffm("TokenApproval.localId", "The UUID of this token approval, in the local FireFly node")
+	TokenApprovalPool             = ffm("TokenApproval.pool", "The UUID the token pool this approval applies to")
+	TokenApprovalConnector        = ffm("TokenApproval.connector", "The name of the token connector, as specified in the FireFly core configuration file. Required on input when there are more than one token connectors configured")
+	TokenApprovalKey              = ffm("TokenApproval.key", "The blockchain signing key for the approval request. On input defaults to the first signing key of the organization that operates the node")
+	TokenApprovalOperator         = ffm("TokenApproval.operator", "The blockchain identity that is granted the approval")
+	TokenApprovalApproved         = ffm("TokenApproval.approved", "Whether this record grants permission for an operator to perform actions on the token balance (true), or revokes permission (false)")
+	TokenApprovalInfo             = ffm("TokenApproval.info", "Token connector specific information about the approval operation, such as whether it applied to a limited balance of a fungible token. See your chosen token connector documentation for details")
+	TokenApprovalNamespace        = ffm("TokenApproval.namespace", "The namespace for the approval, which must match the namespace of the token pool")
+	TokenApprovalProtocolID       = ffm("TokenApproval.protocolId", "An alphanumerically sortable string that represents this event uniquely with respect to the blockchain")
+	TokenApprovalSubject          = ffm("TokenApproval.subject", "A string identifying the parties and entities in the scope of this approval, as provided by the token connector")
+	TokenApprovalActive           = ffm("TokenApproval.active", "Indicates if this approval is currently active (only one approval can be active per subject)")
+	TokenApprovalMessage          = ffm("TokenApproval.message", "The UUID of a message that has been correlated with this approval using the data field of the approval in a compatible token connector")
+	TokenApprovalMessageHash      = ffm("TokenApproval.messageHash", "The hash of a message that has been correlated with this approval using the data field of the approval in a compatible token connector")
+	TokenApprovalCreated          = ffm("TokenApproval.created", "The creation time of the token approval")
+	TokenApprovalTX               = ffm("TokenApproval.tx", "If submitted via FireFly, this will reference the UUID of the FireFly transaction (if the token connector in use supports attaching data)")
+	TokenApprovalBlockchainEvent  = ffm("TokenApproval.blockchainEvent", "The UUID of the blockchain event")
+	TokenApprovalExpiry           = ffm("TokenApproval.expiry", "An optional time at which FireFly automatically revokes this approval. Can only be set when granting an approval submitted by this node")
+	TokenApprovalExpiryRevocation = ffm("TokenApproval.expiryRevocation", "The UUID of the FireFly transaction that revokes this approval once it has expired, after the revocation has been submitted")
+	TokenApprovalConfig           = ffm("TokenApproval.config", "Input only field, with token connector specific configuration of the approval.  See your chosen token connector documentation for details")
 
 	// TokenApprovalInput field descriptions
 	TokenApprovalInputMessage        = ffm("TokenApprovalInput.message", "You can specify a message to correlate with the approval, which can be of type broadcast or private. Your chosen token connector and on-chain smart contract must support on-chain/off-chain correlation by taking a `data` input on the approval")
@@ -574,36 +994,122 @@ var (
 	TokenBalanceKey        = ffm("TokenBalance.key", "The blockchain signing identity this balance applies to")
 	TokenBalanceBalance    = ffm("TokenBalance.balance", "The numeric balance. For non-fungible tokens will always be 1. For fungible tokens, the number of decimals for the token pool should be considered when interpreting the balance. For example, with 18 decimals a fractional balance of 10.234 will be returned as 10,234,000,000,000,000,000")
 	TokenBalanceUpdated    = ffm("TokenBalance.updated", "The last time the balance was updated by applying a transfer event")
+	TokenBalanceMetadata   = ffm("TokenBalance.metadata", "The metadata of the token, if the metadata indexer is enabled and has fetched it from the token URI")
+
+	// TokenMetadata field descriptions
+	TokenMetadataURI         = ffm("TokenMetadata.uri", "The URI the metadata was fetched from, with any ERC-1155 {id} placeholder substituted")
+	TokenMetadataNamespace   = ffm("TokenMetadata.namespace", "The namespace of the token metadata")
+	TokenMetadataName        = ffm("TokenMetadata.name", "The name of the asset the token represents")
+	TokenMetadataDescription = ffm("TokenMetadata.description", "A description of the asset the token represents")
+	TokenMetadataImage       = ffm("TokenMetadata.image", "A URI pointing to an image representing the asset")
+	TokenMetadataContent     = ffm("TokenMetadata.content", "The full metadata JSON document fetched from the token URI")
+	TokenMetadataError       = ffm("TokenMetadata.error", "The error from the last attempt to fetch or validate the metadata, if it failed")
+	TokenMetadataUpdated     = ffm("TokenMetadata.updated", "The time the metadata was last fetched")
 
 	// TokenBalance field descriptions
-	TokenConnectorName = ffm("TokenConnector.name", "The name of the token connector, as configured in the FireFly core configuration file")
+	TokenConnectorName    = ffm("TokenConnector.name", "The name of the token connector, as configured in the FireFly core configuration file")
+	TokenConnectorBreaker = ffm("TokenConnector.breaker", "The state of the circuit breaker in front of the REST calls to the token connector")
+
+	// TokenConnectorBreaker field descriptions
+	TokenConnectorBreakerState               = ffm("TokenConnectorBreaker.state", "The state of the circuit breaker. Requests are failed fast while it is open, until a single request is let through to probe for recovery in the half_open state")
+	TokenConnectorBreakerConsecutiveFailures = ffm("TokenConnectorBreaker.consecutiveFailures", "The number of consecutive failed calls to the token connector")
+	TokenConnectorBreakerLastError           = ffm("TokenConnectorBreaker.lastError", "The error from the most recent failed call to the token connector")
+	TokenConnectorBreakerLastFailure         = ffm("TokenConnectorBreaker.lastFailure", "The time of the most recent failed call to the token connector")
+	TokenConnectorBreakerNextProbe           = ffm("TokenConnectorBreaker.nextProbe", "While the circuit breaker is open, the time after which a request will be let through to probe for recovery")
 
 	// TokenPool field descriptions
-	TokenPoolID              = ffm("TokenPool.id", "The UUID of the token pool")
-	TokenPoolType            = ffm("TokenPool.type", "The type of token the pool contains, such as fungible/non-fungible")
-	TokenPoolNamespace       = ffm("TokenPool.namespace", "The namespace for the token pool")
-	TokenPoolName            = ffm("TokenPool.name", "The name of the token pool. Note the name is not validated against the description of the token on the blockchain")
-	TokenPoolNetworkName     = ffm("TokenPool.networkName", "The published name of the token pool within the multiparty network")
-	TokenPoolStandard        = ffm("TokenPool.standard", "The ERC standard the token pool conforms to, as reported by the token connector")
-	TokenPoolLocator         = ffm("TokenPool.locator", "A unique identifier for the pool, as provided by the token connector")
-	TokenPoolKey             = ffm("TokenPool.key", "The signing key used to create the token pool. On input for token connectors that support on-chain deployment of new tokens (vs. only index existing ones) this determines the signing key used to create the token on-chain")
-	TokenPoolSymbol          = ffm("TokenPool.symbol", "The token symbol. If supplied on input for an existing on-chain token, this must match the on-chain information")
-	TokenPoolDecimals        = ffm("TokenPool.decimals", "Number of decimal places that this token has")
-	TokenPoolConnector       = ffm("TokenPool.connector", "The name of the token connector, as specified in the FireFly core configuration file that is responsible for the token pool. Required on input when multiple token connectors are configured")
-	TokenPoolMessage         = ffm("TokenPool.message", "The UUID of the broadcast message used to inform the network to index this pool")
-	TokenPoolState           = ffm("TokenPool.state", "The current state of the token pool")
-	TokenPoolCreated         = ffm("TokenPool.created", "The creation time of the pool")
-	TokenPoolConfig          = ffm("TokenPool.config", "Input only field, with token connector specific configuration of the pool, such as an existing Ethereum address and block number to used to index the pool. See your chosen token connector documentation for details")
-	TokenPoolInfo            = ffm("TokenPool.info", "Token connector specific information about the pool. See your chosen token connector documentation for details")
-	TokenPoolTX              = ffm("TokenPool.tx", "Reference to the FireFly transaction used to create and broadcast this pool to the network")
-	TokenPoolInterface       = ffm("TokenPool.interface", "A reference to an existing FFI, containing pre-registered type information for the token contract")
-	TokenPoolInterfaceFormat = ffm("TokenPool.interfaceFormat", "The interface encoding format supported by the connector for this token pool")
-	TokenPoolMethods         = ffm("TokenPool.methods", "The method definitions resolved by the token connector to be used by each token operation")
-	TokenPoolPublished       = ffm("TokenPool.published", "Indicates if the token pool is published to other members of the multiparty network")
+	TokenPoolID               = ffm("TokenPool.id", "The UUID of the token pool")
+	TokenPoolType             = ffm("TokenPool.type", "The type of token the pool contains, such as fungible/non-fungible")
+	TokenPoolNamespace        = ffm("TokenPool.namespace", "The namespace for the token pool")
+	TokenPoolName             = ffm("TokenPool.name", "The name of the token pool. Note the name is not validated against the description of the token on the blockchain")
+	TokenPoolNetworkName      = ffm("TokenPool.networkName", "The published name of the token pool within the multiparty network")
+	TokenPoolStandard         = ffm("TokenPool.standard", "The ERC standard the token pool conforms to, as reported by the token connector")
+	TokenPoolLocator          = ffm("TokenPool.locator", "A unique identifier for the pool, as provided by the token connector")
+	TokenPoolKey              = ffm("TokenPool.key", "The signing key used to create the token pool. On input for token connectors that support on-chain deployment of new tokens (vs. only index existing ones) this determines the signing key used to create the token on-chain")
+	TokenPoolSymbol           = ffm("TokenPool.symbol", "The token symbol. If supplied on input for an existing on-chain token, this must match the on-chain information")
+	TokenPoolDecimals         = ffm("TokenPool.decimals", "Number of decimal places that this token has")
+	TokenPoolConnector        = ffm("TokenPool.connector", "The name of the token connector, as specified in the FireFly core configuration file that is responsible for the token pool. Required on input when multiple token connectors are configured")
+	TokenPoolMessage          = ffm("TokenPool.message", "The UUID of the broadcast message used to inform the network to index this pool")
+	TokenPoolState            = ffm("TokenPool.state", "The current state of the token pool")
+	TokenPoolCreated          = ffm("TokenPool.created", "The creation time of the pool")
+	TokenPoolConfig           = ffm("TokenPool.config", "Input only field, with token connector specific configuration of the pool, such as an existing Ethereum address and block number to used to index the pool. See your chosen token connector documentation for details")
+	TokenPoolInfo             = ffm("TokenPool.info", "Token connector specific information about the pool. See your chosen token connector documentation for details")
+	TokenPoolTX               = ffm("TokenPool.tx", "Reference to the FireFly transaction used to create and broadcast this pool to the network")
+	TokenPoolInterface        = ffm("TokenPool.interface", "A reference to an existing FFI, containing pre-registered type information for the token contract")
+	TokenPoolInterfaceFormat  = ffm("TokenPool.interfaceFormat", "The interface encoding format supported by the connector for this token pool")
+	TokenPoolMethods          = ffm("TokenPool.methods", "The method definitions resolved by the token connector to be used by each token operation")
+	TokenPoolPublished        = ffm("TokenPool.published", "Indicates if the token pool is published to other members of the multiparty network")
+	TokenPoolIdentityRegistry = ffm("TokenPool.identityRegistry", "For permissioned tokens (such as ERC-3643), the location of the identity registry that determines which accounts are eligible to hold and transfer tokens, as reported by the connector")
+	TokenPoolFees             = ffm("TokenPool.fees", "The royalty and transfer fee of the pool, as reported by the connector. Only returned when querying a single pool")
+
+	// TokenPoolHolder field descriptions
+	TokenPoolHolderKey     = ffm("TokenPoolHolder.key", "The blockchain signing identity that holds tokens in the pool")
+	TokenPoolHolderTokens  = ffm("TokenPoolHolder.tokens", "The number of token indexes in the pool for which the key has a non-zero balance")
+	TokenPoolHolderUpdated = ffm("TokenPoolHolder.updated", "The last time the balance of any token in the pool was updated for the key")
+
+	// TokenIndexOwnership field descriptions
+	TokenIndexOwnershipTokenIndex = ffm("TokenIndexOwnership.tokenIndex", "The index of the token within the pool")
+	TokenIndexOwnershipURI        = ffm("TokenIndexOwnership.uri", "The URI of the token")
+	TokenIndexOwnershipOwners     = ffm("TokenIndexOwnership.owners", "The number of keys that have a non-zero balance of the token")
+	TokenIndexOwnershipKey        = ffm("TokenIndexOwnership.key", "The key that owns the token, if it has exactly one owner")
+	TokenIndexOwnershipUpdated    = ffm("TokenIndexOwnership.updated", "The last time the balance of the token was updated for any key")
 
 	// TokenPoolInput field descriptions
 	TokenPoolInputIdempotencyKey = ffm("TokenPoolInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
 
+	// TokenPoolMigration field descriptions
+	TokenPoolMigrationID            = ffm("TokenPoolMigration.id", "The UUID of the token pool migration")
+	TokenPoolMigrationNamespace     = ffm("TokenPoolMigration.namespace", "The namespace of the token pool migration")
+	TokenPoolMigrationPool          = ffm("TokenPoolMigration.pool", "The UUID of the token pool being migrated")
+	TokenPoolMigrationState         = ffm("TokenPoolMigration.state", "The state of the migration - pending until the new connector has resolved the pool, then succeeded or failed")
+	TokenPoolMigrationFromConnector = ffm("TokenPoolMigration.fromConnector", "The name of the token connector the pool was using before the migration")
+	TokenPoolMigrationFromLocator   = ffm("TokenPoolMigration.fromLocator", "The locator of the pool, as provided by the previous token connector")
+	TokenPoolMigrationToConnector   = ffm("TokenPoolMigration.toConnector", "The name of the token connector the pool is being migrated to")
+	TokenPoolMigrationToLocator     = ffm("TokenPoolMigration.toLocator", "The locator of the pool, as provided by the new token connector once the migration has succeeded")
+	TokenPoolMigrationPoolState     = ffm("TokenPoolMigration.poolState", "The state of the pool before the migration, which it returns to once the migration is complete")
+	TokenPoolMigrationConfig        = ffm("TokenPoolMigration.config", "Token connector specific configuration passed to the new connector to resolve the existing pool, such as the Ethereum address of the token contract")
+	TokenPoolMigrationTX            = ffm("TokenPoolMigration.tx", "Reference to the FireFly transaction used to migrate the pool")
+	TokenPoolMigrationError         = ffm("TokenPoolMigration.error", "The reason the migration failed, if it did")
+	TokenPoolMigrationCreated       = ffm("TokenPoolMigration.created", "The time the migration was requested")
+	TokenPoolMigrationUpdated       = ffm("TokenPoolMigration.updated", "The time the migration was last updated")
+
+	// TokenPoolMigrationInput field descriptions
+	TokenPoolMigrationInputConnector = ffm("TokenPoolMigrationInput.connector", "The name of the token connector to migrate the pool to, as specified in the FireFly core configuration file")
+	TokenPoolMigrationInputConfig    = ffm("TokenPoolMigrationInput.config", "Token connector specific configuration used by the new connector to resolve the existing pool, such as the Ethereum address of the token contract. See your chosen token connector documentation for details")
+
+	// TokenBulkMint field descriptions
+	TokenBulkMintID        = ffm("TokenBulkMint.id", "The UUID of the bulk mint")
+	TokenBulkMintNamespace = ffm("TokenBulkMint.namespace", "The namespace of the bulk mint")
+	TokenBulkMintPool      = ffm("TokenBulkMint.pool", "The UUID of the non-fungible token pool the tokens are minted in")
+	TokenBulkMintConnector = ffm("TokenBulkMint.connector", "The name of the token connector the mints are submitted to")
+	TokenBulkMintState     = ffm("TokenBulkMint.state", "The state of the bulk mint - pending while batches are being submitted, submitted once every mint has been submitted, then completed or failed once the outcome of every mint is known")
+	TokenBulkMintKey       = ffm("TokenBulkMint.key", "The blockchain signing key used for every mint")
+	TokenBulkMintTo        = ffm("TokenBulkMint.to", "The account that receives every minted token")
+	TokenBulkMintItems     = ffm("TokenBulkMint.items", "The tokens to be minted, in the order they are submitted")
+	TokenBulkMintBatchSize = ffm("TokenBulkMint.batchSize", "The number of mints submitted to the token connector in each batch")
+	TokenBulkMintTotal     = ffm("TokenBulkMint.total", "The total number of tokens to be minted")
+	TokenBulkMintSubmitted = ffm("TokenBulkMint.submitted", "The number of mints that have been submitted to the token connector")
+	TokenBulkMintSucceeded = ffm("TokenBulkMint.succeeded", "The number of mints that have been confirmed by the token connector")
+	TokenBulkMintFailed    = ffm("TokenBulkMint.failed", "The number of mints that failed. Each failed mint can be retried individually via its operation")
+	TokenBulkMintConfig    = ffm("TokenBulkMint.config", "Token connector specific configuration passed with every mint")
+	TokenBulkMintTX        = ffm("TokenBulkMint.tx", "Reference to the FireFly transaction containing every mint")
+	TokenBulkMintOperation = ffm("TokenBulkMint.operation", "The UUID of the parent operation that tracks the progress of the bulk mint. Each mint is a token_transfer operation in the same transaction")
+	TokenBulkMintCreated   = ffm("TokenBulkMint.created", "The time the bulk mint was requested")
+	TokenBulkMintUpdated   = ffm("TokenBulkMint.updated", "The time the bulk mint was last updated")
+
+	// TokenBulkMintItem field descriptions
+	TokenBulkMintItemTokenIndex = ffm("TokenBulkMintItem.tokenIndex", "The index of the token to mint. Can be omitted if the token connector assigns the index")
+	TokenBulkMintItemURI        = ffm("TokenBulkMintItem.uri", "The URI of the token to mint")
+
+	// TokenBulkMintInput field descriptions
+	TokenBulkMintInputPool           = ffm("TokenBulkMintInput.pool", "The name or UUID of the non-fungible token pool to mint in. Defaults to the only token pool, if there is only one")
+	TokenBulkMintInputKey            = ffm("TokenBulkMintInput.key", "The blockchain signing key for every mint. Defaults to the first signing key of the organization that operates the node")
+	TokenBulkMintInputTo             = ffm("TokenBulkMintInput.to", "The account that receives every minted token. Defaults to the value of 'key'")
+	TokenBulkMintInputItems          = ffm("TokenBulkMintInput.items", "The tokens to mint")
+	TokenBulkMintInputBatchSize      = ffm("TokenBulkMintInput.batchSize", "The number of mints to submit to the token connector in each batch. Defaults to asset.bulkMint.batchSize in the FireFly core configuration")
+	TokenBulkMintInputConfig         = ffm("TokenBulkMintInput.config", "Token connector specific configuration passed with every mint. See your chosen token connector documentation for details")
+	TokenBulkMintInputIdempotencyKey = ffm("TokenBulkMintInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+
 	// TokenTransfer field descriptions
 	TokenTransferType            = ffm("TokenTransfer.type", "The type of transfer such as mint/burn/transfer")
 	TokenTransferLocalID         = ffm("TokenTransfer.localId", "The UUID of this token transfer, in the local FireFly node")
@@ -622,6 +1128,10 @@ var (
 	TokenTransferCreated         = ffm("TokenTransfer.created", "The creation time of the transfer")
 	TokenTransferTX              = ffm("TokenTransfer.tx", "If submitted via FireFly, this will reference the UUID of the FireFly transaction (if the token connector in use supports attaching data)")
 	TokenTransferBlockchainEvent = ffm("TokenTransfer.blockchainEvent", "The UUID of the blockchain event")
+	TokenTransferInvalidated     = ffm("TokenTransfer.invalidated", "True if the blockchain event for this transfer was invalidated, and the transfer has been reversed from the token balances")
+	TokenTransferMetadata        = ffm("TokenTransfer.metadata", "The metadata of the token, if the metadata indexer is enabled and has fetched it from the token URI")
+	TokenTransferFees            = ffm("TokenTransfer.fees", "The royalty and transfer fee of the token, as reported by the connector. Only returned when querying a single transfer")
+	TokenTransferRequest         = ffm("TokenTransfer.request", "The ID of the token transfer request, if the amount is above the sign-off threshold and the transfer has been held for sign-off instead of being submitted")
 	TokenTransferConfig          = ffm("TokenTransfer.config", "Input only field, with token connector specific configuration of the transfer. See your chosen token connector documentation for details")
 
 	// TokenTransferInput field descriptions
@@ -629,6 +1139,216 @@ var (
 	TokenTransferInputPool           = ffm("TokenTransferInput.pool", "The name or UUID of a token pool")
 	TokenTransferInputIdempotencyKey = ffm("TokenTransferInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
 
+	// TokenTransferBatchLeg field descriptions
+	TokenTransferBatchLegTokenIndex = ffm("TokenTransferBatchLeg.tokenIndex", "The index of the token within the pool that this leg of the batch applies to")
+	TokenTransferBatchLegAmount     = ffm("TokenTransferBatchLeg.amount", "The amount of the token to transfer in this leg of the batch")
+
+	// TokenTransferBatchInput field descriptions
+	TokenTransferBatchInputPool           = ffm("TokenTransferBatchInput.pool", "The name or UUID of a token pool")
+	TokenTransferBatchInputKey            = ffm("TokenTransferBatchInput.key", "The blockchain signing key for the batch transfer. On input defaults to the first signing key of the organization that operates the node")
+	TokenTransferBatchInputFrom           = ffm("TokenTransferBatchInput.from", "The source account for every leg of the batch. On input defaults to the value of 'key'")
+	TokenTransferBatchInputTo             = ffm("TokenTransferBatchInput.to", "The target account for every leg of the batch. On input defaults to the value of 'key'")
+	TokenTransferBatchInputLegs           = ffm("TokenTransferBatchInput.legs", "The token index and amount pairs to move in a single blockchain transaction")
+	TokenTransferBatchInputConfig         = ffm("TokenTransferBatchInput.config", "Input only field, with token connector specific configuration of the batch transfer. See your chosen token connector documentation for details")
+	TokenTransferBatchInputIdempotencyKey = ffm("TokenTransferBatchInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+
+	// TokenTransferBatch field descriptions
+	TokenTransferBatchTX        = ffm("TokenTransferBatch.tx", "The FireFly transaction that contains every leg of the batch")
+	TokenTransferBatchTransfers = ffm("TokenTransferBatch.transfers", "A token transfer record for each leg of the batch, in the order the legs were supplied")
+
+	// TokenFees field descriptions
+	TokenFeesRoyalty     = ffm("TokenFees.royalty", "The creator royalty on sales of the token, such as the EIP-2981 royalty info of an ERC-721 contract")
+	TokenFeesTransferFee = ffm("TokenFees.transferFee", "The fee deducted by the token contract from every transfer")
+
+	// TokenRoyalty field descriptions
+	TokenRoyaltyReceiver    = ffm("TokenRoyalty.receiver", "The account that royalties should be paid to")
+	TokenRoyaltyBasisPoints = ffm("TokenRoyalty.basisPoints", "The royalty as a proportion of the sale price, in basis points (hundredths of a percent)")
+
+	// TokenTransferFee field descriptions
+	TokenTransferFeeBasisPoints = ffm("TokenTransferFee.basisPoints", "The fee as a proportion of the amount transferred, in basis points (hundredths of a percent)")
+	TokenTransferFeeMaximum     = ffm("TokenTransferFee.maximum", "The maximum fee deducted from a single transfer, if the fee is capped")
+	TokenTransferFeeAmount      = ffm("TokenTransferFee.amount", "For a single transfer, the fee deducted from the amount transferred")
+
+	// TokenTransferEligibility field descriptions
+	TokenTransferEligibilityEligible = ffm("TokenTransferEligibility.eligible", "True if the connector reports that the transfer would be permitted by the token's compliance rules")
+	TokenTransferEligibilityReason   = ffm("TokenTransferEligibility.reason", "The reason the transfer would be rejected, if it is not eligible")
+	TokenTransferEligibilityInfo     = ffm("TokenTransferEligibility.info", "Additional connector-specific detail on the checks that were performed")
+
+	// TokenSnapshot field descriptions
+	TokenSnapshotID          = ffm("TokenSnapshot.id", "The UUID of the token snapshot")
+	TokenSnapshotNamespace   = ffm("TokenSnapshot.namespace", "The namespace for the token snapshot")
+	TokenSnapshotPool        = ffm("TokenSnapshot.pool", "The UUID of the token pool that was snapshotted")
+	TokenSnapshotBlockNumber = ffm("TokenSnapshot.blockNumber", "The block number at which balances were recorded. Includes all transfers up to and including this block")
+	TokenSnapshotTimestamp   = ffm("TokenSnapshot.timestamp", "The time at which balances were recorded. Includes all transfers indexed by FireFly up to and including this time")
+	TokenSnapshotTransfers   = ffm("TokenSnapshot.transfers", "The number of token transfers that were replayed to calculate the balances")
+	TokenSnapshotAccounts    = ffm("TokenSnapshot.accounts", "The number of accounts with a non-zero balance in the snapshot")
+	TokenSnapshotCreated     = ffm("TokenSnapshot.created", "The creation time of the token snapshot")
+
+	// TokenSnapshotInput field descriptions
+	TokenSnapshotInputBlockNumber = ffm("TokenSnapshotInput.blockNumber", "The block number at which to record balances. Requires the connector to use protocol IDs prefixed with a zero-padded block number, as the standard connectors do. Cannot be combined with timestamp")
+	TokenSnapshotInputTimestamp   = ffm("TokenSnapshotInput.timestamp", "The time at which to record balances, compared with the time each transfer was indexed by FireFly. Cannot be combined with blockNumber")
+
+	// TokenSnapshotBalance field descriptions
+	TokenSnapshotBalanceSnapshot   = ffm("TokenSnapshotBalance.snapshot", "The UUID of the token snapshot")
+	TokenSnapshotBalanceNamespace  = ffm("TokenSnapshotBalance.namespace", "The namespace of the token snapshot")
+	TokenSnapshotBalanceTokenIndex = ffm("TokenSnapshotBalance.tokenIndex", "The index of the token within the pool that this balance is for")
+	TokenSnapshotBalanceKey        = ffm("TokenSnapshotBalance.key", "The blockchain signing identity this balance applies to")
+	TokenSnapshotBalanceBalance    = ffm("TokenSnapshotBalance.balance", "The balance of the account at the point of the snapshot")
+
+	// TokenActivity field descriptions
+	TokenActivityType            = ffm("TokenActivity.type", "The type of the activity - a mint, burn or transfer of tokens, or a token approval")
+	TokenActivityLocalID         = ffm("TokenActivity.localId", "The UUID of the token transfer or token approval this activity refers to")
+	TokenActivityPool            = ffm("TokenActivity.pool", "The UUID of the token pool")
+	TokenActivityTokenIndex      = ffm("TokenActivity.tokenIndex", "The index of the token within the pool, for a token transfer")
+	TokenActivityConnector       = ffm("TokenActivity.connector", "The name of the token connector")
+	TokenActivityNamespace       = ffm("TokenActivity.namespace", "The namespace of the activity")
+	TokenActivityKey             = ffm("TokenActivity.key", "The blockchain signing key that submitted the transfer or approval")
+	TokenActivityFrom            = ffm("TokenActivity.from", "The source account of a token transfer, or the owner account of a token approval")
+	TokenActivityTo              = ffm("TokenActivity.to", "The target account of a token transfer")
+	TokenActivityOperator        = ffm("TokenActivity.operator", "The blockchain identity granted or revoked a token approval")
+	TokenActivityAmount          = ffm("TokenActivity.amount", "The amount of tokens transferred")
+	TokenActivityApproved        = ffm("TokenActivity.approved", "Whether a token approval was granted (true) or revoked (false)")
+	TokenActivityProtocolID      = ffm("TokenActivity.protocolId", "An alphanumerically sortable string that represents this event uniquely with respect to the blockchain")
+	TokenActivityMessage         = ffm("TokenActivity.message", "The UUID of a message that has been correlated with the activity")
+	TokenActivityTX              = ffm("TokenActivity.tx", "If submitted via FireFly, this will reference the FireFly transaction")
+	TokenActivityBlockchainEvent = ffm("TokenActivity.blockchainEvent", "The UUID of the blockchain event")
+	TokenActivityCreated         = ffm("TokenActivity.created", "The creation time of the transfer or approval")
+
+	// TokenBalanceMismatch field descriptions
+	TokenBalanceMismatchID           = ffm("TokenBalanceMismatch.id", "The UUID of the token balance mismatch")
+	TokenBalanceMismatchNamespace    = ffm("TokenBalanceMismatch.namespace", "The namespace of the token pool")
+	TokenBalanceMismatchPool         = ffm("TokenBalanceMismatch.pool", "The UUID of the token pool")
+	TokenBalanceMismatchTokenIndex   = ffm("TokenBalanceMismatch.tokenIndex", "The index of the token within the pool that the balance is for")
+	TokenBalanceMismatchKey          = ffm("TokenBalanceMismatch.key", "The blockchain signing identity the balance applies to")
+	TokenBalanceMismatchBalance      = ffm("TokenBalanceMismatch.balance", "The balance FireFly has computed from the token transfers it has indexed")
+	TokenBalanceMismatchChainBalance = ffm("TokenBalanceMismatch.chainBalance", "The balance reported on-chain by the token connector")
+	TokenBalanceMismatchCreated      = ffm("TokenBalanceMismatch.created", "The time the mismatch was first detected")
+	TokenBalanceMismatchUpdated      = ffm("TokenBalanceMismatch.updated", "The last time the mismatch was detected with different balances")
+
+	// TokenTransferPolicy field descriptions
+	TokenTransferPolicyPool       = ffm("TokenTransferPolicy.pool", "The UUID of the token pool the policy applies to")
+	TokenTransferPolicyNamespace  = ffm("TokenTransferPolicy.namespace", "The namespace of the token pool")
+	TokenTransferPolicyMaxAmount  = ffm("TokenTransferPolicy.maxAmount", "The largest amount that can be moved in a single transfer, mint or burn")
+	TokenTransferPolicyDailyLimit = ffm("TokenTransferPolicy.dailyLimit", "The largest total amount that a single signing key can move in the pool in any 24 hour period")
+	TokenTransferPolicySenders    = ffm("TokenTransferPolicy.senders", "If set, only these accounts can send tokens in transfers and burns")
+	TokenTransferPolicyRecipients = ffm("TokenTransferPolicy.recipients", "If set, only these accounts can receive tokens in transfers and mints")
+	TokenTransferPolicyDenied     = ffm("TokenTransferPolicy.denied", "Accounts that cannot sign, send or receive any transfer in the pool")
+	TokenTransferPolicyCreated    = ffm("TokenTransferPolicy.created", "The time the policy was first set")
+	TokenTransferPolicyUpdated    = ffm("TokenTransferPolicy.updated", "The last time the policy was changed")
+
+	// TokenTransferExport field descriptions
+	TokenTransferExportFormat    = ffm("TokenTransferExport.format", "The format to write the export in - csv or parquet")
+	TokenTransferExportPool      = ffm("TokenTransferExport.pool", "The name or UUID of a token pool, to only export transfers in that pool")
+	TokenTransferExportAccount   = ffm("TokenTransferExport.account", "A token account key, to only export transfers to or from that account")
+	TokenTransferExportColumns   = ffm("TokenTransferExport.columns", "The columns to include, in order. Defaults to all columns")
+	TokenTransferExportStartTime = ffm("TokenTransferExport.startTime", "Only export transfers created at or after this time")
+	TokenTransferExportEndTime   = ffm("TokenTransferExport.endTime", "Only export transfers created before this time")
+
+	// TokenLedgerEntry field descriptions
+	TokenLedgerEntryID              = ffm("TokenLedgerEntry.id", "The UUID of the ledger entry")
+	TokenLedgerEntrySequence        = ffm("TokenLedgerEntry.sequence", "The order in which the entry was recorded, across all pools and accounts")
+	TokenLedgerEntryNamespace       = ffm("TokenLedgerEntry.namespace", "The namespace of the token pool")
+	TokenLedgerEntryPool            = ffm("TokenLedgerEntry.pool", "The UUID of the token pool")
+	TokenLedgerEntryAccount         = ffm("TokenLedgerEntry.account", "The account key that was debited or credited")
+	TokenLedgerEntryCounterparty    = ffm("TokenLedgerEntry.counterparty", "The account key on the other side of the transfer. Empty for a mint or burn")
+	TokenLedgerEntryTokenIndex      = ffm("TokenLedgerEntry.tokenIndex", "The index of the token within the pool, for non-fungible tokens")
+	TokenLedgerEntryTransferType    = ffm("TokenLedgerEntry.transferType", "The type of the token transfer")
+	TokenLedgerEntryTransfer        = ffm("TokenLedgerEntry.transfer", "The local UUID of the token transfer")
+	TokenLedgerEntryDebit           = ffm("TokenLedgerEntry.debit", "The number of tokens that left the account")
+	TokenLedgerEntryCredit          = ffm("TokenLedgerEntry.credit", "The number of tokens that arrived in the account")
+	TokenLedgerEntryBalance         = ffm("TokenLedgerEntry.balance", "The running balance of the account in the pool, after this entry")
+	TokenLedgerEntryReversal        = ffm("TokenLedgerEntry.reversal", "True if this entry reverses a transfer that was invalidated by a blockchain rewind")
+	TokenLedgerEntryTX              = ffm("TokenLedgerEntry.tx", "The transaction of the token transfer")
+	TokenLedgerEntryBlockchainEvent = ffm("TokenLedgerEntry.blockchainEvent", "The UUID of the blockchain event of the token transfer")
+	TokenLedgerEntryCreated         = ffm("TokenLedgerEntry.created", "The time the entry was recorded")
+
+	// TokenLedgerExport field descriptions
+	TokenLedgerExportFormat    = ffm("TokenLedgerExport.format", "The format to write the export in - csv or parquet")
+	TokenLedgerExportPool      = ffm("TokenLedgerExport.pool", "The name or UUID of a token pool, to only export entries in that pool")
+	TokenLedgerExportAccount   = ffm("TokenLedgerExport.account", "A token account key, to only export entries for that account")
+	TokenLedgerExportColumns   = ffm("TokenLedgerExport.columns", "The columns to include, in order. Defaults to all columns")
+	TokenLedgerExportStartTime = ffm("TokenLedgerExport.startTime", "Only export entries recorded at or after this time")
+	TokenLedgerExportEndTime   = ffm("TokenLedgerExport.endTime", "Only export entries recorded before this time")
+
+	// TokenReconciliation field descriptions
+	TokenReconciliationPool       = ffm("TokenReconciliation.pool", "The UUID of the token pool that was reconciled")
+	TokenReconciliationAccounts   = ffm("TokenReconciliation.accounts", "The number of account balances that were compared with the chain")
+	TokenReconciliationMismatches = ffm("TokenReconciliation.mismatches", "The account balances that did not match the chain")
+	TokenReconciliationCreated    = ffm("TokenReconciliation.created", "The time the reconciliation completed")
+
+	// TokenSwap field descriptions
+	TokenSwapID        = ffm("TokenSwap.id", "The UUID of the token swap")
+	TokenSwapNamespace = ffm("TokenSwap.namespace", "The namespace for the token swap")
+	TokenSwapState     = ffm("TokenSwap.state", "The current state of the token swap")
+	TokenSwapLegs      = ffm("TokenSwap.legs", "The two legs of the swap. The first is always a token transfer, and the second is a token transfer or a contract invoke")
+	TokenSwapHashLock  = ffm("TokenSwap.hashLock", "The SHA-256 hash lock the token transfers are escrowed under. The preimage is known only to this node, and is revealed when the escrowed tokens are claimed")
+	TokenSwapTimeout   = ffm("TokenSwap.timeout", "The time after which the escrow contract allows the owners to reclaim tokens that have not been claimed. A swap that is still pending, or where no leg has been claimed, is refunded after this time")
+	TokenSwapTX        = ffm("TokenSwap.tx", "The FireFly transaction that contains every operation of the swap")
+	TokenSwapCreated   = ffm("TokenSwap.created", "The creation time of the token swap")
+	TokenSwapUpdated   = ffm("TokenSwap.updated", "The last time the state of the token swap changed")
+
+	// TokenSwapLeg field descriptions
+	TokenSwapLegType       = ffm("TokenSwapLeg.type", "The type of the leg - a token transfer via the escrow contract, or a contract invoke. Defaults to a token transfer")
+	TokenSwapLegLockID     = ffm("TokenSwapLeg.lockId", "The identifier of the escrowed tokens for this leg within the escrow contract")
+	TokenSwapLegPool       = ffm("TokenSwapLeg.pool", "The UUID of the token pool for a token transfer leg")
+	TokenSwapLegConnector  = ffm("TokenSwapLeg.connector", "The name of the token connector for a token transfer leg")
+	TokenSwapLegTokenIndex = ffm("TokenSwapLeg.tokenIndex", "The index of the token within the pool that this leg applies to")
+	TokenSwapLegKey        = ffm("TokenSwapLeg.key", "The blockchain signing key for the token transfer. On input defaults to the first signing key of the organization that operates the node")
+	TokenSwapLegFrom       = ffm("TokenSwapLeg.from", "The source account for the token transfer. On input defaults to the value of 'key'")
+	TokenSwapLegTo         = ffm("TokenSwapLeg.to", "The recipient of the token transfer, once the escrowed tokens are claimed")
+	TokenSwapLegAmount     = ffm("TokenSwapLeg.amount", "The amount of the token to transfer")
+	TokenSwapLegConfig     = ffm("TokenSwapLeg.config", "Input only field, with token connector specific configuration of the token transfer. See your chosen token connector documentation for details")
+	TokenSwapLegInvoke     = ffm("TokenSwapLeg.invoke", "The contract invoke for a contract invoke leg. Messages cannot be attached to the invoke")
+
+	// TokenSwapLegInput field descriptions
+	TokenSwapLegInputPool = ffm("TokenSwapLegInput.pool", "The name or UUID of the token pool for a token transfer leg")
+
+	// TokenSwapInput field descriptions
+	TokenSwapInputLegs           = ffm("TokenSwapInput.legs", "The two legs of the swap. The first must be a token transfer")
+	TokenSwapInputTimeout        = ffm("TokenSwapInput.timeout", "How long the token transfers are held in escrow before they can be reclaimed by their owners. Defaults to the asset.swap.defaultTimeout configuration")
+	TokenSwapInputIdempotencyKey = ffm("TokenSwapInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
+
+	// TokenTransferRequest field descriptions
+	TokenTransferRequestID        = ffm("TokenTransferRequest.id", "The UUID of the token transfer request")
+	TokenTransferRequestNamespace = ffm("TokenTransferRequest.namespace", "The namespace for the token transfer request")
+	TokenTransferRequestState     = ffm("TokenTransferRequest.state", "The current state of the request - pending sign-off, submitted to the token connector, or failed to submit")
+	TokenTransferRequestType      = ffm("TokenTransferRequest.type", "The type of the held transfer - a mint or a transfer")
+	TokenTransferRequestPool      = ffm("TokenTransferRequest.pool", "The UUID of the token pool for the held transfer")
+	TokenTransferRequestKey       = ffm("TokenTransferRequest.key", "The blockchain signing key of the held transfer")
+	TokenTransferRequestAmount    = ffm("TokenTransferRequest.amount", "The amount of the held transfer")
+	TokenTransferRequestTransfer  = ffm("TokenTransferRequest.transfer", "The resolved transfer input, which is submitted unchanged once the request has been signed off")
+	TokenTransferRequestRequired  = ffm("TokenTransferRequest.required", "The number of approvers that must sign off the request before the transfer is submitted")
+	TokenTransferRequestSignoffs  = ffm("TokenTransferRequest.signoffs", "The sign-offs recorded against the request so far")
+	TokenTransferRequestTX        = ffm("TokenTransferRequest.tx", "The FireFly transaction of the transfer, once it has been submitted")
+	TokenTransferRequestError     = ffm("TokenTransferRequest.error", "The error from submitting the transfer, if it failed")
+	TokenTransferRequestCreated   = ffm("TokenTransferRequest.created", "The creation time of the request")
+	TokenTransferRequestUpdated   = ffm("TokenTransferRequest.updated", "The last time the request was updated")
+
+	// TokenMedia field descriptions
+	TokenMediaURI         = ffm("TokenMedia.uri", "The URI of the image, as referenced by the token metadata")
+	TokenMediaNamespace   = ffm("TokenMedia.namespace", "The namespace of the cached image")
+	TokenMediaContentType = ffm("TokenMedia.contentType", "The content type of the image")
+	TokenMediaSize        = ffm("TokenMedia.size", "The size of the image in bytes")
+	TokenMediaHash        = ffm("TokenMedia.hash", "The SHA-256 hash of the image")
+	TokenMediaPayloadRef  = ffm("TokenMedia.payloadRef", "The reference to the image in shared storage, if it is not held in the database")
+	TokenMediaCreated     = ffm("TokenMedia.created", "The time the image was cached")
+
+	// TokenAssociatedAccount field descriptions
+	TokenAssociatedAccountPool            = ffm("TokenAssociatedAccount.pool", "The UUID of the token pool")
+	TokenAssociatedAccountNamespace       = ffm("TokenAssociatedAccount.namespace", "The namespace of the token pool")
+	TokenAssociatedAccountOwner           = ffm("TokenAssociatedAccount.owner", "The owner address, against which balances and transfers are recorded")
+	TokenAssociatedAccountAccount         = ffm("TokenAssociatedAccount.account", "The address of the token account that holds the owner's balance, such as a Solana SPL associated token account")
+	TokenAssociatedAccountPayer           = ffm("TokenAssociatedAccount.payer", "The key that paid for the creation of the token account, if reported by the connector")
+	TokenAssociatedAccountTX              = ffm("TokenAssociatedAccount.tx", "The FireFly transaction of the transfer or mint that created the token account")
+	TokenAssociatedAccountBlockchainEvent = ffm("TokenAssociatedAccount.blockchainEvent", "The UUID of the blockchain event of the transfer or mint that created the token account")
+	TokenAssociatedAccountCreated         = ffm("TokenAssociatedAccount.created", "The time the token account was recorded by FireFly")
+
+	// TokenTransferSignoff field descriptions
+	TokenTransferSignoffPrincipal = ffm("TokenTransferSignoff.principal", "The authenticated principal of the approver that signed off the request")
+	TokenTransferSignoffAuthor    = ffm("TokenTransferSignoff.author", "The DID of the identity that signed off the request")
+	TokenTransferSignoffKey       = ffm("TokenTransferSignoff.key", "The resolved signing key of the identity that signed off the request")
+	TokenTransferSignoffCreated   = ffm("TokenTransferSignoff.created", "The time of the sign-off")
+
 	// TransactionStatus field descriptions
 	TransactionStatusStatus  = ffm("TransactionStatus.status", "The overall computed status of the transaction, after analyzing the details during the API call")
 	TransactionStatusDetails = ffm("TransactionStatus.details", "A set of records describing the activities within the transaction known by the local FireFly node")
@@ -642,6 +1362,13 @@ var (
 	TransactionStatusDetailsError     = ffm("TransactionStatusDetails.error", "If an error occurred related to the detail entry, it is included here")
 	TransactionStatusDetailsInfo      = ffm("TransactionStatusDetails.info", "Output details for this entry")
 
+	// TransactionReceipt field descriptions
+	TransactionReceiptStatus          = ffm("TransactionReceipt.status", "The status of the transaction, from the operations submitted to the blockchain connector. A transaction with no operations, such as one submitted by another member of the network, is reported as succeeded")
+	TransactionReceiptBlockNumber     = ffm("TransactionReceipt.blockNumber", "The block number the transaction was mined in, if reported by the blockchain connector")
+	TransactionReceiptTransactionHash = ffm("TransactionReceipt.transactionHash", "The hash of the blockchain transaction")
+	TransactionReceiptGasUsed         = ffm("TransactionReceipt.gasUsed", "The gas used by the transaction, if reported by the blockchain connector")
+	TransactionReceiptError           = ffm("TransactionReceipt.error", "The error of the first failed operation of the transaction")
+
 	// ContractDeployRequest field descriptions
 	ContractDeployRequestKey            = ffm("ContractDeployRequest.key", "The blockchain signing key that will be used to deploy the contract. Defaults to the first signing key of the organization that operates the node")
 	ContractDeployRequestInput          = ffm("ContractDeployRequest.input", "An optional array of inputs passed to the smart contract's constructor, if applicable")
@@ -649,8 +1376,13 @@ var (
 	ContractDeployRequestContract       = ffm("ContractDeployRequest.contract", "The smart contract to deploy. This should be pre-compiled if required by the blockchain connector")
 	ContractDeployRequestErrors         = ffm("ContractDeployRequest.errors", "An in-line FFI errors definition for the constructor")
 	ContractDeployRequestOptions        = ffm("ContractDeployRequest.options", "A map of named inputs that will be passed through to the blockchain connector")
+	ContractDeployRequestAPI            = ffm("ContractDeployRequest.api", "If specified, a contract API is created automatically with the address of the deployed contract, once the deployment succeeds. The ID of the API is recorded as `api` in the operation output, or the reason it could not be created as `apiError`")
 	ContractDeployRequestIdempotencyKey = ffm("ContractDeployRequest.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
 
+	// ContractDeployAPI field descriptions
+	ContractDeployAPIName      = ffm("ContractDeployAPI.name", "The name of the contract API to create for the deployed contract")
+	ContractDeployAPIInterface = ffm("ContractDeployAPI.interface", "A reference to the FireFly Interface (FFI) of the deployed contract")
+
 	// ContractCallRequest field descriptions
 	ContractCallRequestType       = ffm("ContractCallRequest.type", "Invocations cause transactions on the blockchain. Whereas queries simply execute logic in your local node to query data at a given current/historical block")
 	ContractCallRequestInterface  = ffm("ContractCallRequest.interface", "The UUID of a method within a pre-configured FireFly interface (FFI) definition for a smart contract. Required if the 'method' is omitted. Also see Contract APIs as a way to configure a dedicated API for your FFI, including all methods and an OpenAPI/Swagger interface")
@@ -681,26 +1413,55 @@ var (
 	WSSubscriptionStatusFilter    = ffm("WSSubscriptionStatus.filter", "The subscription filter specification")
 	WSSubscriptionStatusStartTime = ffm("WSSubscriptionStatus.startTime", "The time the subscription started (reset on dynamic namespace reload)")
 
-	WebhooksOptJSON          = ffm("WebhookSubOptions.json", "Webhooks only: Whether to assume the response body is JSON, regardless of the returned Content-Type")
-	WebhooksOptReply         = ffm("WebhookSubOptions.reply", "Webhooks only: Whether to automatically send a reply event, using the body returned by the webhook")
-	WebhooksOptHeaders       = ffm("WebhookSubOptions.headers", "Webhooks only: Static headers to set on the webhook request")
-	WebhooksOptQuery         = ffm("WebhookSubOptions.query", "Webhooks only: Static query params to set on the webhook request")
-	WebhooksOptInput         = ffm("WebhookSubOptions.input", "Webhooks only: A set of options to extract data from the first JSON input data in the incoming message. Only applies if withData=true")
-	WebhooksOptFastAck       = ffm("WebhookSubOptions.fastack", "Webhooks only: When true the event will be acknowledged before the webhook is invoked, allowing parallel invocations")
-	WebhooksOptURL           = ffm("WebhookSubOptions.url", "Webhooks only: HTTP url to invoke. Can be relative if a base URL is set in the webhook plugin config")
-	WebhooksOptMethod        = ffm("WebhookSubOptions.method", "Webhooks only: HTTP method to invoke. Default=POST")
-	WebhooksOptReplyTag      = ffm("WebhookSubOptions.replytag", "Webhooks only: The tag to set on the reply message")
-	WebhooksOptReplyTx       = ffm("WebhookSubOptions.replytx", "Webhooks only: The transaction type to set on the reply message")
-	WebhooksOptTLSConfigName = ffm("WebhookSubOptions.tlsConfigName", "The name of an existing TLS configuration associated to the namespace to use")
-	WebhooksOptInputQuery    = ffm("WebhookInputOptions.query", "A top-level property of the first data input, to use for query parameters")
-	WebhooksOptInputHeaders  = ffm("WebhookInputOptions.headers", "A top-level property of the first data input, to use for headers")
-	WebhooksOptInputBody     = ffm("WebhookInputOptions.body", "A top-level property of the first data input, to use for the request body. Default is the whole first body")
-	WebhooksOptInputPath     = ffm("WebhookInputOptions.path", "A top-level property of the first data input, to use for a path to append with escaping to the webhook path")
-	WebhooksOptInputReplyTx  = ffm("WebhookInputOptions.replytx", "A top-level property of the first data input, to use to dynamically set whether to pin the response (so the requester can choose)")
+	WebhooksOptJSON            = ffm("WebhookSubOptions.json", "Webhooks only: Whether to assume the response body is JSON, regardless of the returned Content-Type")
+	WebhooksOptReply           = ffm("WebhookSubOptions.reply", "Webhooks only: Whether to automatically send a reply event, using the body returned by the webhook")
+	WebhooksOptHeaders         = ffm("WebhookSubOptions.headers", "Webhooks only: Static headers to set on the webhook request")
+	WebhooksOptQuery           = ffm("WebhookSubOptions.query", "Webhooks only: Static query params to set on the webhook request")
+	WebhooksOptInput           = ffm("WebhookSubOptions.input", "Webhooks only: A set of options to extract data from the first JSON input data in the incoming message. Only applies if withData=true")
+	WebhooksOptFastAck         = ffm("WebhookSubOptions.fastack", "Webhooks only: When true the event will be acknowledged before the webhook is invoked, allowing parallel invocations")
+	WebhooksOptURL             = ffm("WebhookSubOptions.url", "Webhooks only: HTTP url to invoke. Can be relative if a base URL is set in the webhook plugin config")
+	WebhooksOptMethod          = ffm("WebhookSubOptions.method", "Webhooks only: HTTP method to invoke. Default=POST")
+	WebhooksOptReplyTag        = ffm("WebhookSubOptions.replytag", "Webhooks only: The tag to set on the reply message")
+	WebhooksOptReplyTx         = ffm("WebhookSubOptions.replytx", "Webhooks only: The transaction type to set on the reply message")
+	WebhooksOptTLSConfigName   = ffm("WebhookSubOptions.tlsConfigName", "The name of an existing TLS configuration associated to the namespace to use")
+	WebhooksOptRetry           = ffm("WebhookSubOptions.retry", "Webhooks only: A retry policy for failed invocations. When set, events that exhaust the policy are recorded as dead letters and acknowledged")
+	WebhooksOptCloudEventsMode = ffm("WebhookSubOptions.cloudEventsMode", "Webhooks only: How events are delivered when the subscription format is 'cloudevents'. 'structured' (the default) sends a CloudEvents JSON body, 'binary' sends the usual body with 'ce-' headers")
+	WebhooksOptTemplate        = ffm("WebhookSubOptions.template", "Webhooks only: Go templates, executed against the JSON of each event, to set URL path parameters and headers and to transform the request body")
+	WebhooksOptInputQuery      = ffm("WebhookInputOptions.query", "A top-level property of the first data input, to use for query parameters")
+	WebhooksOptInputHeaders    = ffm("WebhookInputOptions.headers", "A top-level property of the first data input, to use for headers")
+	WebhooksOptInputBody       = ffm("WebhookInputOptions.body", "A top-level property of the first data input, to use for the request body. Default is the whole first body")
+	WebhooksOptInputPath       = ffm("WebhookInputOptions.path", "A top-level property of the first data input, to use for a path to append with escaping to the webhook path")
+	WebhooksOptInputReplyTx    = ffm("WebhookInputOptions.replytx", "A top-level property of the first data input, to use to dynamically set whether to pin the response (so the requester can choose)")
+	WebhooksOptTemplatePath    = ffm("WebhookTemplateOptions.path", "URL path parameters - each {name} placeholder in the url is replaced with the escaped result of the template for the name")
+	WebhooksOptTemplateHeaders = ffm("WebhookTemplateOptions.headers", "Headers to set on the request, from the result of each template")
+	WebhooksOptTemplateBody    = ffm("WebhookTemplateOptions.body", "A template for the request body, that replaces the body FireFly would otherwise send. The message data is available as .data")
+	WebhooksRetryMaxAttempts   = ffm("WebhookRetryOptions.maxAttempts", "The maximum number of attempts to invoke the webhook, including the first. Default=5")
+	WebhooksRetryInitDelay     = ffm("WebhookRetryOptions.initialDelay", "The delay before the first retry. Default=1s")
+	WebhooksRetryMaxDelay      = ffm("WebhookRetryOptions.maxDelay", "The maximum delay between retries. Default=30s")
+	WebhooksRetryFactor        = ffm("WebhookRetryOptions.factor", "The factor the delay is multiplied by after each retry. Default=2")
+	WebhooksRetryStatusCodes   = ffm("WebhookRetryOptions.statusCodes", "The HTTP status codes that are retried. Connection errors are always retried. Default=[408,429,500,502,503,504]")
 
 	// PublishInput field descriptions
 	PublishInputIdempotencyKey = ffm("PublishInput.idempotencyKey", "An optional identifier to allow idempotent submission of requests. Stored on the transaction uniquely within a namespace")
 
 	// DefinitionPublish field descriptions
 	DefinitionPublishNetworkName = ffm("DefinitionPublish.networkName", "An optional name to be used for publishing this definition to the multiparty network, which may differ from the local name")
+
+	// GraphQLRequest field descriptions
+	GraphQLRequestQuery         = ffm("GraphQLRequest.query", "The GraphQL query document")
+	GraphQLRequestOperationName = ffm("GraphQLRequest.operationName", "The name of the operation to execute, when the query document contains more than one")
+	GraphQLRequestVariables     = ffm("GraphQLRequest.variables", "Values for the variables of the operation")
+
+	// GraphQLResponse field descriptions
+	GraphQLResponseData   = ffm("GraphQLResponse.data", "The result of the query")
+	GraphQLResponseErrors = ffm("GraphQLResponse.errors", "Errors parsing or validating the query, or resolving individual fields")
+
+	// GraphQLError field descriptions
+	GraphQLErrorMessage   = ffm("GraphQLError.message", "A description of the error")
+	GraphQLErrorLocations = ffm("GraphQLError.locations", "The locations in the query document the error relates to")
+	GraphQLErrorPath      = ffm("GraphQLError.path", "The path of the field in the result that could not be resolved")
+
+	// GraphQLErrorLocation field descriptions
+	GraphQLErrorLocationLine   = ffm("GraphQLErrorLocation.line", "The line in the query document")
+	GraphQLErrorLocationColumn = ffm("GraphQLErrorLocation.column", "The column in the query document")
 )
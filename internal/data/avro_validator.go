@@ -0,0 +1,124 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/linkedin/goavro/v2"
+)
+
+// avroValidator validates data against an Apache Avro schema, stored as the value of the datatype.
+// Data values can be supplied either in the Avro JSON encoding, or as a base64 string containing
+// the Avro binary encoding - which can be transcoded to the JSON encoding for query and display.
+type avroValidator struct {
+	id       *fftypes.UUID
+	size     int64
+	ns       string
+	datatype *core.DatatypeRef
+	codec    *goavro.Codec
+}
+
+func newAvroValidator(ctx context.Context, ns string, datatype *core.Datatype) (*avroValidator, error) {
+	av := &avroValidator{
+		id: datatype.ID,
+		ns: ns,
+		datatype: &core.DatatypeRef{
+			Name:    datatype.Name,
+			Version: datatype.Version,
+		},
+	}
+
+	codec, err := goavro.NewCodec(datatype.Value.String())
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgSchemaLoadFailed, av.datatype)
+	}
+	av.codec = codec
+	av.size = datatype.Value.Length()
+
+	log.L(ctx).Debugf("Found Avro schema validator for avro:%s:%s: %v", av.ns, datatype, av.id)
+	return av, nil
+}
+
+func (av *avroValidator) Validate(ctx context.Context, data *core.Data) error {
+	return av.ValidateValue(ctx, data.Value, data.Hash)
+}
+
+func (av *avroValidator) ValidateValue(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error {
+	if err := checkValueHash(ctx, value, expectedHash); err != nil {
+		return err
+	}
+	_, _, err := av.decode(ctx, value)
+	return err
+}
+
+func (av *avroValidator) TranscodeJSON(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	native, binary, err := av.decode(ctx, value)
+	if err != nil || !binary {
+		return value, err
+	}
+	textual, err := av.codec.TextualFromNative(nil, native)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDataInvalidPerSchema, core.ValidatorTypeAvro, av.datatype, err)
+	}
+	return fftypes.JSONAnyPtrBytes(textual), nil
+}
+
+// decode parses the value using the codec, returning whether it was supplied in the binary encoding
+func (av *avroValidator) decode(ctx context.Context, value *fftypes.JSONAny) (native interface{}, binary bool, err error) {
+	var remaining []byte
+	if encoded, isString := jsonStringValue(value); isString {
+		binary = true
+		var input []byte
+		if input, err = base64.StdEncoding.DecodeString(encoded); err == nil {
+			native, remaining, err = av.codec.NativeFromBinary(input)
+		}
+	} else {
+		native, remaining, err = av.codec.NativeFromTextual(value.Bytes())
+		remaining = []byte(strings.TrimSpace(string(remaining)))
+	}
+	if err == nil && len(remaining) > 0 {
+		err = fmt.Errorf("%d bytes of trailing data", len(remaining))
+	}
+	if err != nil {
+		log.L(ctx).Warnf("Avro schema %s [%v] validation failed: %s", av.datatype, av.id, err)
+		return nil, binary, i18n.NewError(ctx, coremsgs.MsgDataInvalidPerSchema, core.ValidatorTypeAvro, av.datatype, err)
+	}
+	return native, binary, nil
+}
+
+func (av *avroValidator) Size() int64 {
+	return av.size
+}
+
+// jsonStringValue returns the unquoted string, if the value is a JSON string
+func jsonStringValue(value *fftypes.JSONAny) (string, bool) {
+	var s string
+	if !strings.HasPrefix(strings.TrimSpace(value.String()), `"`) || json.Unmarshal(value.Bytes(), &s) != nil {
+		return "", false
+	}
+	return s, true
+}
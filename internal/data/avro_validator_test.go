@@ -0,0 +1,127 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+const avroTestSchema = `{
+	"type": "record",
+	"name": "Customer",
+	"fields": [
+		{"name": "name", "type": "string"},
+		{"name": "age", "type": "int"}
+	]
+}`
+
+func newTestAvroValidator(t *testing.T) *avroValidator {
+	av, err := newAvroValidator(context.Background(), "ns1", &core.Datatype{
+		Validator: core.ValidatorTypeAvro,
+		Name:      "customer",
+		Version:   "0.0.1",
+		Value:     fftypes.JSONAnyPtr(avroTestSchema),
+	})
+	assert.NoError(t, err)
+	return av
+}
+
+func avroTestBinary(t *testing.T, av *avroValidator, native map[string]interface{}) *fftypes.JSONAny {
+	b, err := av.codec.BinaryFromNative(nil, native)
+	assert.NoError(t, err)
+	return fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, base64.StdEncoding.EncodeToString(b)))
+}
+
+func TestAvroValidatorJSON(t *testing.T) {
+	av := newTestAvroValidator(t)
+	ctx := context.Background()
+
+	value := fftypes.JSONAnyPtr(`{"name": "John", "age": 30}`)
+	err := av.ValidateValue(ctx, value, value.Hash())
+	assert.NoError(t, err)
+
+	transcoded, err := av.TranscodeJSON(ctx, value)
+	assert.NoError(t, err)
+	assert.Equal(t, value, transcoded)
+
+	err = av.ValidateValue(ctx, fftypes.JSONAnyPtr(`{"name": "John"}`), nil)
+	assert.Regexp(t, "FF10686.*avro.*customer", err)
+
+	err = av.ValidateValue(ctx, fftypes.JSONAnyPtr(`{"name": "John", "age": "thirty"}`), nil)
+	assert.Regexp(t, "FF10686", err)
+
+	err = av.ValidateValue(ctx, fftypes.JSONAnyPtr(`{"name": "John", "age": 30} {}`), nil)
+	assert.Regexp(t, "FF10686.*trailing data", err)
+
+	assert.Equal(t, int64(len(avroTestSchema)), av.Size())
+}
+
+func TestAvroValidatorBinary(t *testing.T) {
+	av := newTestAvroValidator(t)
+	ctx := context.Background()
+
+	value := avroTestBinary(t, av, map[string]interface{}{"name": "John", "age": 30})
+	err := av.Validate(ctx, &core.Data{Value: value, Hash: value.Hash()})
+	assert.NoError(t, err)
+
+	transcoded, err := av.TranscodeJSON(ctx, value)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name": "John", "age": 30}`, transcoded.String())
+
+	err = av.ValidateValue(ctx, fftypes.JSONAnyPtr(`"!not base64"`), nil)
+	assert.Regexp(t, "FF10686", err)
+
+	err = av.ValidateValue(ctx, fftypes.JSONAnyPtr(`"CEpvaG4="`), nil)
+	assert.Regexp(t, "FF10686", err)
+
+	err = av.ValidateValue(ctx, fftypes.JSONAnyPtr(`"CEpvaG48AA=="`), nil)
+	assert.Regexp(t, "FF10686.*1 bytes of trailing data", err)
+
+	_, err = av.TranscodeJSON(ctx, fftypes.JSONAnyPtr(`"CEpvaG4="`))
+	assert.Regexp(t, "FF10686", err)
+}
+
+func TestAvroValidatorBadHash(t *testing.T) {
+	av := newTestAvroValidator(t)
+
+	err := av.ValidateValue(context.Background(), fftypes.JSONAnyPtr(`{"name": "John", "age": 30}`), fftypes.NewRandB32())
+	assert.Regexp(t, "FF10201", err)
+}
+
+func TestAvroValidatorNilData(t *testing.T) {
+	av := newTestAvroValidator(t)
+
+	err := av.Validate(context.Background(), &core.Data{})
+	assert.Regexp(t, "FF10199", err)
+}
+
+func TestAvroValidatorParseSchemaFail(t *testing.T) {
+	_, err := newAvroValidator(context.Background(), "ns1", &core.Datatype{
+		Validator: core.ValidatorTypeAvro,
+		Name:      "customer",
+		Version:   "0.0.1",
+		Value:     fftypes.JSONAnyPtr(`{"type": "record", "name": "Customer"}`),
+	})
+	assert.Regexp(t, "FF10196", err)
+}
@@ -0,0 +1,84 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// hashLock serialises the updates to the blobs with one hash
+type hashLock struct {
+	mux     sync.Mutex
+	waiters int
+}
+
+// lockHash serialises deduplicating an upload onto a stored blob, and inserting the new blob, with deleting a blob that
+// has the same content. All blobs that share a payload have the same hash, so otherwise a delete could count the references
+// to a payload and remove it from data exchange, while an upload is about to reference it. The returned function unlocks.
+func (bs *blobStore) lockHash(hash *fftypes.Bytes32) func() {
+	bs.hashMux.Lock()
+	if bs.hashLocks == nil {
+		bs.hashLocks = make(map[fftypes.Bytes32]*hashLock)
+	}
+	hl := bs.hashLocks[*hash]
+	if hl == nil {
+		hl = &hashLock{}
+		bs.hashLocks[*hash] = hl
+	}
+	hl.waiters++
+	bs.hashMux.Unlock()
+
+	hl.mux.Lock()
+	return func() {
+		hl.mux.Unlock()
+		bs.hashMux.Lock()
+		defer bs.hashMux.Unlock()
+		if hl.waiters--; hl.waiters == 0 {
+			delete(bs.hashLocks, *hash)
+		}
+	}
+}
+
+// dedupUploadedBlob checks whether a blob just uploaded to data exchange has the same content as a blob already stored
+// in the namespace. If it does, the new copy is deleted and the payload of the stored blob is returned to reference
+// instead. Deduplication is best effort, so the new copy is kept if the stored blob cannot be checked or the copy cannot be deleted.
+// The caller must hold the lock of the hash until the new blob is inserted.
+func (bs *blobStore) dedupUploadedBlob(ctx context.Context, hash *fftypes.Bytes32, payloadRef string) string {
+	if !bs.dedup {
+		return payloadRef
+	}
+	fb := database.BlobQueryFactory.NewFilter(ctx)
+	existing, _, err := bs.database.GetBlobs(ctx, bs.dm.namespace.Name, fb.And(fb.Eq("hash", hash), fb.Neq("payloadref", payloadRef)).Limit(1))
+	if err != nil {
+		log.L(ctx).Warnf("Failed to check for a stored blob with hash %s: %s", hash, err)
+		return payloadRef
+	}
+	if len(existing) == 0 {
+		return payloadRef
+	}
+	if err := bs.exchange.DeleteBlob(ctx, payloadRef); err != nil {
+		log.L(ctx).Warnf("Failed to delete duplicate blob '%s' from data exchange: %s", payloadRef, err)
+		return payloadRef
+	}
+	log.L(ctx).Infof("Uploaded blob with hash %s is a duplicate of stored blob '%s'", hash, existing[0].PayloadRef)
+	return existing[0].PayloadRef
+}
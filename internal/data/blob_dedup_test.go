@@ -0,0 +1,192 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockDedupUpload(dm *dataManager, ctx context.Context, b []byte) (*databasemocks.Plugin, *dataexchangemocks.Plugin) {
+	dm.blobStore.dedup = true
+	mdi := dm.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	mdi.On("UpsertData", mock.Anything, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		_, err := ioutil.ReadAll(a[3].(io.Reader))
+		var hash fftypes.Bytes32 = sha256.Sum256(b)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob2", &hash, int64(len(b)), err}
+	}
+	return mdi, mdx
+}
+
+func TestUploadBlobDuplicate(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	b := []byte("same content")
+	mdi, mdx := mockDedupUpload(dm, ctx, b)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{{PayloadRef: "ns1/blob1"}}, nil, nil)
+	mdx.On("DeleteBlob", ctx, "ns1/blob2").Return(nil)
+	mdi.On("InsertBlob", ctx, mock.MatchedBy(func(blob *core.Blob) bool {
+		return blob.PayloadRef == "ns1/blob1"
+	})).Return(nil)
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestUploadBlobNoDuplicate(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	b := []byte("new content")
+	mdi, mdx := mockDedupUpload(dm, ctx, b)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{}, nil, nil)
+	mdi.On("InsertBlob", ctx, mock.MatchedBy(func(blob *core.Blob) bool {
+		return blob.PayloadRef == "ns1/blob2"
+	})).Return(nil)
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestUploadBlobDuplicateLookupFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	b := []byte("same content")
+	mdi, mdx := mockDedupUpload(dm, ctx, b)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mdi.On("InsertBlob", ctx, mock.MatchedBy(func(blob *core.Blob) bool {
+		return blob.PayloadRef == "ns1/blob2"
+	})).Return(nil)
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestUploadBlobDuplicateDeleteFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	b := []byte("same content")
+	mdi, mdx := mockDedupUpload(dm, ctx, b)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{{PayloadRef: "ns1/blob1"}}, nil, nil)
+	mdx.On("DeleteBlob", ctx, "ns1/blob2").Return(fmt.Errorf("pop"))
+	mdi.On("InsertBlob", ctx, mock.MatchedBy(func(blob *core.Blob) bool {
+		return blob.PayloadRef == "ns1/blob2"
+	})).Return(nil)
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestDeleteBlobWaitsForDuplicateUpload(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	b := []byte("same content")
+	var hash fftypes.Bytes32 = sha256.Sum256(b)
+	stored := &core.Blob{Sequence: 1, Hash: &hash, PayloadRef: "ns1/blob1"}
+
+	// The upload is held after it finds the stored blob, and before it inserts the new one
+	mdi, mdx := mockDedupUpload(dm, ctx, b)
+	lookedUp := make(chan struct{})
+	release := make(chan struct{})
+	mdi.On("GetBlobs", ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.Contains(fi.String(), "hash")
+	})).Run(func(args mock.Arguments) {
+		close(lookedUp)
+		<-release
+	}).Return([]*core.Blob{stored}, nil, nil)
+	mdx.On("DeleteBlob", ctx, "ns1/blob2").Return(nil)
+	inserted := false
+	mdi.On("InsertBlob", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		inserted = true
+	}).Return(nil)
+
+	// So the delete of the stored blob must count the references after the insert
+	mdi.On("GetBlobs", ctx, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return !strings.Contains(fi.String(), "hash")
+	})).Run(func(args mock.Arguments) {
+		assert.True(t, inserted)
+	}).Return([]*core.Blob{stored, {Sequence: 2, Hash: &hash, PayloadRef: "ns1/blob1"}}, nil, nil)
+	mdi.On("DeleteBlob", ctx, int64(1)).Return(nil)
+
+	uploadDone := make(chan error)
+	go func() {
+		_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+		uploadDone <- err
+	}()
+	<-lookedUp
+
+	deleteDone := make(chan error)
+	go func() {
+		deleteDone <- dm.DeleteBlob(ctx, stored)
+	}()
+	select {
+	case <-deleteDone:
+		assert.Fail(t, "delete did not wait for the upload")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(release)
+	assert.NoError(t, <-uploadDone)
+	assert.NoError(t, <-deleteDone)
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	assert.Empty(t, dm.blobStore.hashLocks)
+}
@@ -0,0 +1,64 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// ScanBlob runs the blob scanner of the namespace over the content of a blob in data exchange, and returns the
+// threat that was found, or an empty string if the content is clean or the namespace does not scan blobs
+func (bs *blobStore) ScanBlob(ctx context.Context, payloadRef string) (string, error) {
+	if bs.scanner == nil || bs.exchange == nil {
+		return "", nil
+	}
+	reader, err := bs.exchange.DownloadBlob(ctx, payloadRef)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	return bs.scanner.Scan(ctx, reader)
+}
+
+// scanUploadedBlob rejects a blob uploaded to this node if the scanner flags it, or if it cannot be scanned,
+// deleting it from data exchange so the content is never sent to other nodes
+func (bs *blobStore) scanUploadedBlob(ctx context.Context, payloadRef string) error {
+	finding, err := bs.ScanBlob(ctx, payloadRef)
+	if err == nil && finding == "" {
+		return nil
+	}
+	if dxErr := bs.DeleteBlobPayload(ctx, payloadRef); dxErr != nil {
+		log.L(ctx).Errorf("Failed to delete rejected blob '%s' from data exchange: %s", payloadRef, dxErr)
+	}
+	if err != nil {
+		return err
+	}
+	log.L(ctx).Warnf("Rejected uploaded blob '%s' flagged by the blob scanner: %s", payloadRef, finding)
+	return i18n.NewError(ctx, coremsgs.MsgBlobFlagged, finding)
+}
+
+// DeleteBlobPayload deletes the content of a blob from data exchange, for blobs that have not been attached to their data
+func (bs *blobStore) DeleteBlobPayload(ctx context.Context, payloadRef string) error {
+	if bs.exchange == nil {
+		return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	return bs.exchange.DeleteBlob(ctx, payloadRef)
+}
@@ -0,0 +1,157 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/scannermocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockScannedUpload(dm *dataManager, ctx context.Context, b []byte) *dataexchangemocks.Plugin {
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		_, err := ioutil.ReadAll(a[3].(io.Reader))
+		var hash fftypes.Bytes32 = sha256.Sum256(b)
+		dxUpload.ReturnArguments = mock.Arguments{"ns1/blob1", &hash, int64(len(b)), err}
+	}
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(ioutil.NopCloser(bytes.NewReader(b)), nil)
+	return mdx
+}
+
+func TestScanBlobNoScanner(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	finding, err := dm.ScanBlob(ctx, "ns1/blob1")
+	assert.NoError(t, err)
+	assert.Empty(t, finding)
+}
+
+func TestScanBlobDownloadFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.scanner = &scannermocks.Scanner{}
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.ScanBlob(ctx, "ns1/blob1")
+	assert.Regexp(t, "pop", err)
+}
+
+func TestUploadBlobScannedClean(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	msc := &scannermocks.Scanner{}
+	dm.scanner = msc
+
+	b := []byte("clean content")
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(nil)
+	mdx := mockScannedUpload(dm, ctx, b)
+	msc.On("Scan", ctx, mock.Anything).Return("", nil)
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.NoError(t, err)
+
+	mdx.AssertExpectations(t)
+	msc.AssertExpectations(t)
+}
+
+func TestUploadBlobScannedFlagged(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	msc := &scannermocks.Scanner{}
+	dm.scanner = msc
+
+	b := []byte("infected content")
+	mdx := mockScannedUpload(dm, ctx, b)
+	mdx.On("DeleteBlob", ctx, "ns1/blob1").Return(nil)
+	msc.On("Scan", ctx, mock.Anything).Return("Eicar-Test-Signature", nil)
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.Regexp(t, "FF10683.*Eicar-Test-Signature", err)
+
+	mdx.AssertExpectations(t)
+	msc.AssertExpectations(t)
+}
+
+func TestUploadBlobScanFailDeleteFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	msc := &scannermocks.Scanner{}
+	dm.scanner = msc
+
+	b := []byte("some content")
+	mdx := mockScannedUpload(dm, ctx, b)
+	mdx.On("DeleteBlob", ctx, "ns1/blob1").Return(fmt.Errorf("pop"))
+	msc.On("Scan", ctx, mock.Anything).Return("", fmt.Errorf("scanner down"))
+
+	_, err := dm.UploadBlob(ctx, &core.DataRefOrValue{}, &ffapi.Multipart{Data: bytes.NewReader(b)}, false)
+	assert.Regexp(t, "scanner down", err)
+
+	mdx.AssertExpectations(t)
+	msc.AssertExpectations(t)
+}
+
+func TestDeleteBlobPayload(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DeleteBlob", ctx, "ns1/blob1").Return(nil)
+
+	err := dm.DeleteBlobPayload(ctx, "ns1/blob1")
+	assert.NoError(t, err)
+	mdx.AssertExpectations(t)
+}
+
+func TestDeleteBlobPayloadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	err := dm.DeleteBlobPayload(ctx, "ns1/blob1")
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestInitBadBlobScanner(t *testing.T) {
+	ctx := context.Background()
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	ns := &core.Namespace{Name: "ns1", BlobScanner: &core.BlobScannerConfig{Plugin: "wrong"}}
+	_, err := NewDataManager(ctx, ns, &databasemocks.Plugin{}, &dataexchangemocks.Plugin{}, cmi)
+	assert.Regexp(t, "FF10680", err)
+}
@@ -0,0 +1,273 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// Chunked uploads stage the bytes of a blob in a local file, with the offset recorded in the
+// database after each chunk. Anything written past the recorded offset, by a chunk that failed
+// part way through, is discarded by the next chunk. The staged file is only sent to data exchange
+// when the upload is complete.
+
+func (bs *blobStore) uploadPath(id *fftypes.UUID) string {
+	return filepath.Join(bs.uploadDir, id.String())
+}
+
+// beginUploadWrite ensures only one request writes to the staged file of an upload at a time
+func (bs *blobStore) beginUploadWrite(ctx context.Context, id *fftypes.UUID) (func(), error) {
+	bs.uploadMux.Lock()
+	defer bs.uploadMux.Unlock()
+	if bs.uploading[*id] {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadBusy, id)
+	}
+	bs.uploading[*id] = true
+	return func() {
+		bs.uploadMux.Lock()
+		defer bs.uploadMux.Unlock()
+		delete(bs.uploading, *id)
+	}, nil
+}
+
+func (bs *blobStore) getBlobUploadByID(ctx context.Context, id *fftypes.UUID) (*core.BlobUpload, error) {
+	upload, err := bs.database.GetBlobUploadByID(ctx, bs.dm.namespace.Name, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+	return upload, nil
+}
+
+func (bs *blobStore) InitBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+
+	upload := &core.BlobUpload{
+		ID:              fftypes.NewUUID(),
+		Namespace:       bs.dm.namespace.Name,
+		BlobUploadInput: *input,
+	}
+	if err := os.MkdirAll(bs.uploadDir, 0700); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobUploadStagingFailed, upload.ID)
+	}
+	f, err := os.Create(bs.uploadPath(upload.ID))
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobUploadStagingFailed, upload.ID)
+	}
+	_ = f.Close()
+
+	if err := bs.database.InsertBlobUpload(ctx, upload); err != nil {
+		_ = os.Remove(bs.uploadPath(upload.ID))
+		return nil, err
+	}
+	log.L(ctx).Infof("Started blob upload %s (size=%d)", upload.ID, upload.Size)
+	return upload, nil
+}
+
+func (bs *blobStore) GetBlobUpload(ctx context.Context, id string) (*core.BlobUpload, error) {
+	uploadID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return bs.getBlobUploadByID(ctx, uploadID)
+}
+
+func (bs *blobStore) GetBlobUploads(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlobUpload, *ffapi.FilterResult, error) {
+	return bs.database.GetBlobUploads(ctx, bs.dm.namespace.Name, filter)
+}
+
+func (bs *blobStore) AppendBlobUpload(ctx context.Context, id string, offset int64, chunkHash *fftypes.Bytes32, chunk *ffapi.Multipart) (*core.BlobUpload, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	uploadID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	done, err := bs.beginUploadWrite(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	upload, err := bs.getBlobUploadByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != upload.Offset {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadBadOffset, upload.ID, upload.Offset, offset)
+	}
+
+	f, err := os.OpenFile(bs.uploadPath(upload.ID), os.O_WRONLY, 0)
+	if err == nil {
+		defer f.Close()
+		err = f.Truncate(upload.Offset)
+	}
+	if err == nil {
+		_, err = f.Seek(upload.Offset, io.SeekStart)
+	}
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobUploadStagingFailed, upload.ID)
+	}
+
+	// Read one byte more than the upload has space for, so a chunk that is too large is detected
+	var reader io.Reader = chunk.Data
+	if upload.Size > 0 {
+		reader = io.LimitReader(reader, upload.Size-upload.Offset+1)
+	}
+	hashCalc := sha256.New()
+	written, err := io.Copy(io.MultiWriter(f, hashCalc), reader)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobStreamingFailed)
+	}
+	if upload.Size > 0 && upload.Offset+written > upload.Size {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadTooLarge, upload.ID, upload.Size)
+	}
+	hash := fftypes.HashResult(hashCalc)
+	if chunkHash != nil && !chunkHash.Equals(hash) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadChunkHashMismatch, hash, chunkHash)
+	}
+	if err := f.Sync(); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobUploadStagingFailed, upload.ID)
+	}
+
+	newOffset := upload.Offset + written
+	update := database.BlobUploadQueryFactory.NewUpdate(ctx).Set("offset", newOffset)
+	if err := bs.database.UpdateBlobUpload(ctx, bs.dm.namespace.Name, upload.ID, update); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Debugf("Appended %d bytes to blob upload %s at offset %d", written, upload.ID, upload.Offset)
+	upload.Offset = newOffset
+	return upload, nil
+}
+
+func (bs *blobStore) CompleteBlobUpload(ctx context.Context, id string) (*core.Data, error) {
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	uploadID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	done, err := bs.beginUploadWrite(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	upload, err := bs.getBlobUploadByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Size > 0 && upload.Offset != upload.Size {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadIncomplete, upload.ID, upload.Offset, upload.Size)
+	}
+
+	f, err := os.Open(bs.uploadPath(upload.ID))
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgBlobUploadStagingFailed, upload.ID)
+	}
+	defer f.Close()
+
+	data := &core.Data{
+		ID:        fftypes.NewUUID(),
+		Namespace: bs.dm.namespace.Name,
+		Created:   fftypes.Now(),
+		Validator: upload.Validator,
+		Datatype:  upload.Datatype,
+		Value:     upload.Value,
+	}
+	hash, blobSize, payloadRef, err := bs.uploadVerifyBlob(ctx, data.ID, io.LimitReader(f, upload.Offset))
+	if err != nil {
+		return nil, err
+	}
+	if upload.Hash != nil && !upload.Hash.Equals(hash) {
+		if dxErr := bs.exchange.DeleteBlob(ctx, payloadRef); dxErr != nil {
+			log.L(ctx).Warnf("Failed to delete blob '%s' of upload %s: %s", payloadRef, upload.ID, dxErr)
+		}
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobUploadHashMismatch, upload.ID, hash, upload.Hash)
+	}
+	unlock := bs.lockHash(hash)
+	defer unlock()
+	payloadRef = bs.dedupUploadedBlob(ctx, hash, payloadRef)
+
+	blob, err := bs.sealBlobData(ctx, data, hash, blobSize, payloadRef, upload.Filename, upload.Mimetype, upload.AutoMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	err = bs.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		err := bs.database.UpsertData(ctx, data, database.UpsertOptimizationNew)
+		if err == nil {
+			err = bs.database.InsertBlob(ctx, blob)
+		}
+		if err == nil {
+			err = bs.database.DeleteBlobUpload(ctx, bs.dm.namespace.Name, upload.ID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bs.removeStagedUpload(ctx, upload.ID)
+	return data, nil
+}
+
+func (bs *blobStore) DeleteBlobUpload(ctx context.Context, id string) error {
+	uploadID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+	done, err := bs.beginUploadWrite(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	upload, err := bs.getBlobUploadByID(ctx, uploadID)
+	if err != nil {
+		return err
+	}
+	if err := bs.database.DeleteBlobUpload(ctx, bs.dm.namespace.Name, upload.ID); err != nil {
+		return err
+	}
+	bs.removeStagedUpload(ctx, upload.ID)
+	return nil
+}
+
+func (bs *blobStore) removeStagedUpload(ctx context.Context, id *fftypes.UUID) {
+	if err := os.Remove(bs.uploadPath(id)); err != nil && !os.IsNotExist(err) {
+		log.L(ctx).Warnf("Failed to remove staged file of blob upload %s: %s", id, err)
+	}
+}
@@ -0,0 +1,546 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestBlobUpload(t *testing.T, dm *dataManager, ctx context.Context, input *core.BlobUploadInput) *core.BlobUpload {
+	dm.uploadDir = t.TempDir()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("InsertBlobUpload", ctx, mock.Anything).Return(nil).Once()
+	upload, err := dm.InitBlobUpload(ctx, input)
+	assert.NoError(t, err)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", upload.ID).Return(upload, nil)
+	return upload
+}
+
+func TestBlobUploadE2E(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	b := []byte(`some data uploaded in two chunks`)
+	var hash fftypes.Bytes32 = sha256.Sum256(b)
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{
+		Size:     int64(len(b)),
+		Hash:     &hash,
+		Filename: "file.txt",
+		Mimetype: "text/plain",
+		AutoMeta: true,
+	})
+	assert.Equal(t, "ns1", upload.Namespace)
+	assert.Equal(t, int64(0), upload.Offset)
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", upload.ID, mock.Anything).Return(nil)
+
+	upload, err := dm.AppendBlobUpload(ctx, upload.ID.String(), 0, nil, &ffapi.Multipart{Data: bytes.NewReader(b[:10])})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), upload.Offset)
+
+	var chunkHash fftypes.Bytes32 = sha256.Sum256(b[10:])
+	upload, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 10, &chunkHash, &ffapi.Multipart{Data: bytes.NewReader(b[10:])})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(b)), upload.Offset)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		readBytes, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		assert.Equal(t, b, readBytes)
+		dxUpload.ReturnArguments = mock.Arguments{fmt.Sprintf("ns1/%s", a[2].(fftypes.UUID)), &hash, int64(len(b)), nil}
+	}
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	mdi.On("UpsertData", mock.Anything, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("InsertBlob", mock.Anything, mock.MatchedBy(func(blob *core.Blob) bool {
+		return blob.Hash.Equals(&hash) && blob.Size == int64(len(b))
+	})).Return(nil)
+	mdi.On("DeleteBlobUpload", mock.Anything, "ns1", upload.ID).Return(nil)
+
+	data, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, &hash, data.Blob.Hash)
+	assert.Equal(t, core.ValidatorTypeJSON, data.Validator)
+	assert.Equal(t, "file.txt", data.Value.JSONObject().GetString("filename"))
+	assert.Equal(t, "text/plain", data.Value.JSONObject().GetString("mimetype"))
+
+	_, err = os.Stat(dm.uploadPath(upload.ID))
+	assert.True(t, os.IsNotExist(err))
+
+	mdi.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestInitBlobUploadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.InitBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestInitBlobUploadMkdirFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	file := filepath.Join(t.TempDir(), "file")
+	err := os.WriteFile(file, []byte{}, 0600)
+	assert.NoError(t, err)
+	dm.uploadDir = filepath.Join(file, "uploads")
+
+	_, err = dm.InitBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "FF10675", err)
+}
+
+func TestInitBlobUploadCreateFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.uploadDir = t.TempDir()
+	err := os.Chmod(dm.uploadDir, 0500)
+	assert.NoError(t, err)
+	defer os.Chmod(dm.uploadDir, 0700)
+	if f, err := os.Create(filepath.Join(dm.uploadDir, "check")); err == nil {
+		f.Close()
+		t.Skip("directory permissions are not enforced")
+	}
+
+	_, err = dm.InitBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "FF10675", err)
+}
+
+func TestInitBlobUploadInsertFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.uploadDir = t.TempDir()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("InsertBlobUpload", ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := dm.InitBlobUpload(ctx, &core.BlobUploadInput{})
+	assert.Regexp(t, "pop", err)
+
+	files, err := os.ReadDir(dm.uploadDir)
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestGetBlobUploads(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploads", ctx, "ns1", mock.Anything).Return([]*core.BlobUpload{}, nil, nil)
+
+	f := database.BlobUploadQueryFactory.NewFilter(ctx).And()
+	uploads, _, err := dm.GetBlobUploads(ctx, f)
+	assert.NoError(t, err)
+	assert.Empty(t, uploads)
+}
+
+func TestGetBlobUpload(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	uploadRead, err := dm.GetBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, upload, uploadRead)
+}
+
+func TestGetBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.GetBlobUpload(ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := dm.GetBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestGetBlobUploadFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.GetBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestAppendBlobUploadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.AppendBlobUpload(ctx, fftypes.NewUUID().String(), 0, nil, &ffapi.Multipart{})
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestAppendBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.AppendBlobUpload(ctx, "bad", 0, nil, &ffapi.Multipart{})
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestAppendBlobUploadBusy(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	dm.uploading[*id] = true
+
+	_, err := dm.AppendBlobUpload(ctx, id.String(), 0, nil, &ffapi.Multipart{})
+	assert.Regexp(t, "FF10677", err)
+}
+
+func TestAppendBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := dm.AppendBlobUpload(ctx, fftypes.NewUUID().String(), 0, nil, &ffapi.Multipart{})
+	assert.Regexp(t, "FF10143", err)
+	assert.Empty(t, dm.uploading)
+}
+
+func TestAppendBlobUploadBadOffset(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+	upload.Offset = 10
+
+	_, err := dm.AppendBlobUpload(ctx, upload.ID.String(), 5, nil, &ffapi.Multipart{})
+	assert.Regexp(t, "FF10670.*10.*5", err)
+}
+
+func TestAppendBlobUploadStagedFileMissing(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+	os.Remove(dm.uploadPath(upload.ID))
+
+	_, err := dm.AppendBlobUpload(ctx, upload.ID.String(), 0, nil, &ffapi.Multipart{})
+	assert.Regexp(t, "FF10675", err)
+}
+
+func TestAppendBlobUploadReadFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	_, err := dm.AppendBlobUpload(ctx, upload.ID.String(), 0, nil, &ffapi.Multipart{Data: iotest.ErrReader(fmt.Errorf("pop"))})
+	assert.Regexp(t, "FF10217.*pop", err)
+}
+
+func TestAppendBlobUploadTooLarge(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{Size: 5})
+
+	_, err := dm.AppendBlobUpload(ctx, upload.ID.String(), 0, nil, &ffapi.Multipart{Data: bytes.NewReader([]byte(`too large`))})
+	assert.Regexp(t, "FF10671", err)
+	assert.Equal(t, int64(0), upload.Offset)
+}
+
+func TestAppendBlobUploadChunkHashMismatch(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	_, err := dm.AppendBlobUpload(ctx, upload.ID.String(), 0, fftypes.NewRandB32(), &ffapi.Multipart{Data: bytes.NewReader([]byte(`chunk`))})
+	assert.Regexp(t, "FF10672", err)
+	assert.Equal(t, int64(0), upload.Offset)
+}
+
+func TestAppendBlobUploadUpdateFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", upload.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := dm.AppendBlobUpload(ctx, upload.ID.String(), 0, nil, &ffapi.Multipart{Data: bytes.NewReader([]byte(`chunk`))})
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, int64(0), upload.Offset)
+}
+
+func TestAppendBlobUploadDiscardsInterruptedChunk(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+	upload.Offset = 5
+	err := os.WriteFile(dm.uploadPath(upload.ID), []byte(`firstpartial chunk`), 0600)
+	assert.NoError(t, err)
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("UpdateBlobUpload", ctx, "ns1", upload.ID, mock.Anything).Return(nil)
+
+	upload, err = dm.AppendBlobUpload(ctx, upload.ID.String(), 5, nil, &ffapi.Multipart{Data: bytes.NewReader([]byte(`second`))})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), upload.Offset)
+
+	b, err := os.ReadFile(dm.uploadPath(upload.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, "firstsecond", string(b))
+}
+
+func TestCompleteBlobUploadDisabled(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.CompleteBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestCompleteBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	_, err := dm.CompleteBlobUpload(ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestCompleteBlobUploadBusy(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	dm.uploading[*id] = true
+
+	_, err := dm.CompleteBlobUpload(ctx, id.String())
+	assert.Regexp(t, "FF10677", err)
+}
+
+func TestCompleteBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := dm.CompleteBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestCompleteBlobUploadIncomplete(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{Size: 100})
+	upload.Offset = 50
+
+	_, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10673.*50.*100", err)
+}
+
+func TestCompleteBlobUploadStagedFileMissing(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+	os.Remove(dm.uploadPath(upload.ID))
+
+	_, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10675", err)
+}
+
+func TestCompleteBlobUploadDXFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything).Return("", nil, int64(0), fmt.Errorf("pop"))
+
+	_, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "pop", err)
+}
+
+func mockDXUploadStaged(t *testing.T, mdx *dataexchangemocks.Plugin, ctx context.Context, b []byte) {
+	var hash fftypes.Bytes32 = sha256.Sum256(b)
+	dxUpload := mdx.On("UploadBlob", ctx, "ns1", mock.Anything, mock.Anything).Return("ns1/blob", &hash, int64(len(b)), nil)
+	dxUpload.RunFn = func(a mock.Arguments) {
+		readBytes, err := ioutil.ReadAll(a[3].(io.Reader))
+		assert.Nil(t, err)
+		assert.Equal(t, b, readBytes)
+	}
+}
+
+func TestCompleteBlobUploadHashMismatch(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{Hash: fftypes.NewRandB32()})
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mockDXUploadStaged(t, mdx, ctx, []byte{})
+	mdx.On("DeleteBlob", ctx, "ns1/blob").Return(fmt.Errorf("pop"))
+
+	_, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF10674", err)
+
+	mdx.AssertExpectations(t)
+}
+
+func TestCompleteBlobUploadBadValue(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{Validator: "wrong"})
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mockDXUploadStaged(t, mdx, ctx, []byte{})
+
+	_, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "FF00108", err)
+}
+
+func TestCompleteBlobUploadInsertFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mockDXUploadStaged(t, mdx, ctx, []byte{})
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := dm.CompleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "pop", err)
+
+	_, err = os.Stat(dm.uploadPath(upload.ID))
+	assert.NoError(t, err)
+}
+
+func TestDeleteBlobUpload(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("DeleteBlobUpload", ctx, "ns1", upload.ID).Return(nil)
+
+	err := dm.DeleteBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+
+	_, err = os.Stat(dm.uploadPath(upload.ID))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDeleteBlobUploadStagedFileMissing(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+	os.Remove(dm.uploadPath(upload.ID))
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("DeleteBlobUpload", ctx, "ns1", upload.ID).Return(nil)
+
+	err := dm.DeleteBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+}
+
+func TestDeleteBlobUploadRemoveFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+	os.Remove(dm.uploadPath(upload.ID))
+	err := os.MkdirAll(filepath.Join(dm.uploadPath(upload.ID), "notempty"), 0700)
+	assert.NoError(t, err)
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("DeleteBlobUpload", ctx, "ns1", upload.ID).Return(nil)
+
+	err = dm.DeleteBlobUpload(ctx, upload.ID.String())
+	assert.NoError(t, err)
+}
+
+func TestDeleteBlobUploadFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	upload := newTestBlobUpload(t, dm, ctx, &core.BlobUploadInput{})
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("DeleteBlobUpload", ctx, "ns1", upload.ID).Return(fmt.Errorf("pop"))
+
+	err := dm.DeleteBlobUpload(ctx, upload.ID.String())
+	assert.Regexp(t, "pop", err)
+}
+
+func TestDeleteBlobUploadBadID(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	err := dm.DeleteBlobUpload(ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDeleteBlobUploadBusy(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	id := fftypes.NewUUID()
+	dm.uploading[*id] = true
+
+	err := dm.DeleteBlobUpload(ctx, id.String())
+	assert.Regexp(t, "FF10677", err)
+}
+
+func TestDeleteBlobUploadNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetBlobUploadByID", ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	err := dm.DeleteBlobUpload(ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10143", err)
+}
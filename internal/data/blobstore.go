@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"io"
+	"sync"
 
 	"github.com/docker/go-units"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -31,12 +32,20 @@ import (
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
+	"github.com/hyperledger/firefly/pkg/scanner"
 )
 
 type blobStore struct {
-	dm       *dataManager
-	database database.Plugin
-	exchange dataexchange.Plugin // optional
+	dm        *dataManager
+	database  database.Plugin
+	exchange  dataexchange.Plugin // optional
+	scanner   scanner.Scanner     // optional
+	dedup     bool
+	uploadDir string
+	uploadMux sync.Mutex
+	uploading map[fftypes.UUID]bool
+	hashMux   sync.Mutex
+	hashLocks map[fftypes.Bytes32]*hashLock
 }
 
 func (bs *blobStore) uploadVerifyBlob(ctx context.Context, id *fftypes.UUID, reader io.Reader) (hash *fftypes.Bytes32, written int64, payloadRef string, err error) {
@@ -72,37 +81,23 @@ func (bs *blobStore) uploadVerifyBlob(ctx context.Context, id *fftypes.UUID, rea
 	if uploadSize > 0 && uploadSize != written {
 		return nil, -1, "", i18n.NewError(ctx, coremsgs.MsgDXBadSize, uploadSize, written)
 	}
+	if err := bs.scanUploadedBlob(ctx, payloadRef); err != nil {
+		return nil, -1, "", err
+	}
 
 	return hash, written, payloadRef, nil
 
 }
 
-func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue, mpart *ffapi.Multipart, autoMeta bool) (*core.Data, error) {
-
-	if bs.exchange == nil {
-		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
-	}
-
-	data := &core.Data{
-		ID:        fftypes.NewUUID(),
-		Namespace: bs.dm.namespace.Name,
-		Created:   fftypes.Now(),
-		Validator: inData.Validator,
-		Datatype:  inData.Datatype,
-		Value:     inData.Value,
-	}
-
-	hash, blobSize, payloadRef, err := bs.uploadVerifyBlob(ctx, data.ID, mpart.Data)
-	if err != nil {
-		return nil, err
-	}
+// sealBlobData validates and seals the data for a blob that has been uploaded to data exchange
+func (bs *blobStore) sealBlobData(ctx context.Context, data *core.Data, hash *fftypes.Bytes32, blobSize int64, payloadRef, filename, mimetype string, autoMeta bool) (*core.Blob, error) {
 	data.Blob = &core.BlobRef{Hash: hash}
 
 	// autoMeta will create/update JSON metadata with the upload details
 	if autoMeta {
 		do := data.Value.JSONObject()
-		do["filename"] = mpart.Filename
-		do["mimetype"] = mpart.Mimetype
+		do["filename"] = filename
+		do["mimetype"] = mimetype
 		b, _ := json.Marshal(&do)
 		data.Value = fftypes.JSONAnyPtrBytes(b)
 	}
@@ -119,7 +114,7 @@ func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue
 		Created:    fftypes.Now(),
 	}
 
-	err = bs.dm.checkValidation(ctx, data.Validator, data.Datatype, data.Value)
+	err := bs.dm.checkValidation(ctx, data.Validator, data.Datatype, data.Value)
 	if err == nil {
 		err = data.Seal(ctx, blob)
 	}
@@ -127,6 +122,36 @@ func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue
 		return nil, err
 	}
 	log.L(ctx).Infof("Uploaded Blob blobhash=%s hash=%s (%s)", data.Blob.Hash, data.Hash, units.HumanSizeWithPrecision(float64(blobSize), 2))
+	return blob, nil
+}
+
+func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue, mpart *ffapi.Multipart, autoMeta bool) (*core.Data, error) {
+
+	if bs.exchange == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+
+	data := &core.Data{
+		ID:        fftypes.NewUUID(),
+		Namespace: bs.dm.namespace.Name,
+		Created:   fftypes.Now(),
+		Validator: inData.Validator,
+		Datatype:  inData.Datatype,
+		Value:     inData.Value,
+	}
+
+	hash, blobSize, payloadRef, err := bs.uploadVerifyBlob(ctx, data.ID, mpart.Data)
+	if err != nil {
+		return nil, err
+	}
+	unlock := bs.lockHash(hash)
+	defer unlock()
+	payloadRef = bs.dedupUploadedBlob(ctx, hash, payloadRef)
+
+	blob, err := bs.sealBlobData(ctx, data, hash, blobSize, payloadRef, mpart.Filename, mpart.Mimetype, autoMeta)
+	if err != nil {
+		return nil, err
+	}
 
 	err = bs.database.RunAsGroup(ctx, func(ctx context.Context) error {
 		err := bs.database.UpsertData(ctx, data, database.UpsertOptimizationNew)
@@ -142,50 +167,96 @@ func (bs *blobStore) UploadBlob(ctx context.Context, inData *core.DataRefOrValue
 	return data, nil
 }
 
-func (bs *blobStore) DownloadBlob(ctx context.Context, dataID string) (*core.Blob, io.ReadCloser, error) {
+// BlobDownload is a blob, or a range of bytes of a blob, being streamed out of storage
+type BlobDownload struct {
+	Blob    *core.Blob
+	Reader  io.ReadCloser
+	Partial bool
+	First   int64
+	Last    int64
+}
+
+func (bs *blobStore) getDataBlob(ctx context.Context, dataID string) (*core.Blob, error) {
 
 	if bs.exchange == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 	}
 
 	id, err := fftypes.ParseUUID(ctx, dataID)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	data, err := bs.database.GetDataByID(ctx, bs.dm.namespace.Name, id, false)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if data == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
 	}
 	if data.Blob == nil || data.Blob.Hash == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.MsgDataDoesNotHaveBlob)
+		return nil, i18n.NewError(ctx, coremsgs.MsgDataDoesNotHaveBlob)
 	}
 	fb := database.BlobQueryFactory.NewFilter(ctx)
 	blobs, _, err := bs.database.GetBlobs(ctx, bs.dm.namespace.Name, fb.And(fb.Eq("data_id", data.ID), fb.Eq("hash", data.Blob.Hash)))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if len(blobs) == 0 || blobs[0] == nil {
-		return nil, nil, i18n.NewError(ctx, coremsgs.MsgBlobNotFound, data.Blob.Hash)
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobNotFound, data.Blob.Hash)
 	}
-	blob := blobs[0]
+	return blobs[0], nil
+}
 
+func (bs *blobStore) DownloadBlob(ctx context.Context, dataID string) (*core.Blob, io.ReadCloser, error) {
+	blob, err := bs.getDataBlob(ctx, dataID)
+	if err != nil {
+		return nil, nil, err
+	}
 	reader, err := bs.exchange.DownloadBlob(ctx, blob.PayloadRef)
 	return blob, reader, err
 }
 
+// DownloadBlobRange streams the requested range of a blob. The whole blob is streamed if there is no range,
+// if the size of the blob is not known, or if the If-Range of the request does not match the blob.
+func (bs *blobStore) DownloadBlobRange(ctx context.Context, dataID string, br *core.BlobRange) (*BlobDownload, error) {
+	blob, err := bs.getDataBlob(ctx, dataID)
+	if err != nil {
+		return nil, err
+	}
+	dl := &BlobDownload{Blob: blob}
+	if br == nil || blob.Size <= 0 || !br.Matches(blob.Hash) {
+		dl.Reader, err = bs.exchange.DownloadBlob(ctx, blob.PayloadRef)
+		if err != nil {
+			return nil, err
+		}
+		return dl, nil
+	}
+
+	first, last, ok := br.Resolve(blob.Size)
+	if !ok {
+		// The blob is returned with the error, so the size can be reported to the client
+		return &BlobDownload{Blob: blob}, i18n.NewError(ctx, coremsgs.MsgBlobRangeNotSatisfiable, blob.Size)
+	}
+	dl.Partial, dl.First, dl.Last = true, first, last
+	dl.Reader, err = bs.exchange.DownloadBlobRange(ctx, blob.PayloadRef, first, last-first+1)
+	if err != nil {
+		return nil, err
+	}
+	return dl, nil
+}
+
 func (bs *blobStore) DeleteBlob(ctx context.Context, blob *core.Blob) error {
 	if bs.exchange == nil {
 		return i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
 	}
 
-	// Compatibility check: Previous versions of FireFly could have had multiple
-	// data items pointing at the same blob. We should NOT delete the blob if other
-	// data items still reference this blob! Look at the payloadRef to determine
-	// uniqueness, as of FireFly 1.2.x this will be unique per data item.
+	// Multiple data items can reference the same blob, either from previous versions of
+	// FireFly or when blob deduplication is enabled. We should NOT delete the blob if other
+	// data items still reference this blob! Count the references to the payloadRef, and
+	// only delete the payload from data exchange with the last one.
+	unlock := bs.lockHash(blob.Hash)
+	defer unlock()
 	fb := database.BlobQueryFactory.NewFilter(ctx)
 	blobs, _, err := bs.database.GetBlobs(ctx, bs.dm.namespace.Name, fb.Eq("payloadref", blob.PayloadRef))
 	if err != nil {
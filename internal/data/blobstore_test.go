@@ -295,6 +295,131 @@ func TestDownloadBlobOk(t *testing.T) {
 
 }
 
+func mockBlobForRange(dm *dataManager, ctx context.Context, dataID *fftypes.UUID, blobHash *fftypes.Bytes32, size int64) {
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDataByID", ctx, "ns1", dataID, false).Return(&core.Data{
+		ID:        dataID,
+		Namespace: "ns1",
+		Blob: &core.BlobRef{
+			Hash: blobHash,
+		},
+	}, nil)
+	mdi.On("GetBlobs", ctx, "ns1", mock.Anything).Return([]*core.Blob{{
+		Hash:       blobHash,
+		PayloadRef: "ns1/blob1",
+		Size:       size,
+	}}, nil, nil)
+}
+
+func TestDownloadBlobRangeOk(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	mockBlobForRange(dm, ctx, dataID, blobHash, 9)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlobRange", ctx, "ns1/blob1", int64(5), int64(4)).Return(
+		ioutil.NopCloser(bytes.NewReader([]byte("blob"))),
+		nil)
+
+	dl, err := dm.DownloadBlobRange(ctx, dataID.String(), &core.BlobRange{First: 5, Last: -1, IfRange: core.BlobETag(blobHash)})
+	assert.NoError(t, err)
+	assert.True(t, dl.Partial)
+	assert.Equal(t, int64(5), dl.First)
+	assert.Equal(t, int64(8), dl.Last)
+	b, err := ioutil.ReadAll(dl.Reader)
+	dl.Reader.Close()
+	assert.Equal(t, "blob", string(b))
+
+}
+
+func TestDownloadBlobRangeIfRangeMismatch(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	mockBlobForRange(dm, ctx, dataID, blobHash, 9)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(
+		ioutil.NopCloser(bytes.NewReader([]byte("some blob"))),
+		nil)
+
+	dl, err := dm.DownloadBlobRange(ctx, dataID.String(), &core.BlobRange{First: 5, Last: -1, IfRange: `"other"`})
+	assert.NoError(t, err)
+	assert.False(t, dl.Partial)
+	b, err := ioutil.ReadAll(dl.Reader)
+	dl.Reader.Close()
+	assert.Equal(t, "some blob", string(b))
+
+}
+
+func TestDownloadBlobRangeWholeFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	mockBlobForRange(dm, ctx, dataID, blobHash, 9)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", ctx, "ns1/blob1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.DownloadBlobRange(ctx, dataID.String(), nil)
+	assert.Regexp(t, "pop", err)
+
+}
+
+func TestDownloadBlobRangeNotSatisfiable(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	mockBlobForRange(dm, ctx, dataID, blobHash, 9)
+
+	dl, err := dm.DownloadBlobRange(ctx, dataID.String(), &core.BlobRange{First: 9, Last: -1})
+	assert.Regexp(t, "FF10679", err)
+	assert.Equal(t, int64(9), dl.Blob.Size)
+	assert.Nil(t, dl.Reader)
+
+}
+
+func TestDownloadBlobRangeFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	mockBlobForRange(dm, ctx, dataID, blobHash, 9)
+
+	mdx := dm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlobRange", ctx, "ns1/blob1", int64(0), int64(2)).Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.DownloadBlobRange(ctx, dataID.String(), &core.BlobRange{First: 0, Last: 1})
+	assert.Regexp(t, "pop", err)
+
+}
+
+func TestDownloadBlobRangeLookupFail(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	dm.exchange = nil
+
+	_, err := dm.DownloadBlobRange(ctx, "", nil)
+	assert.Regexp(t, "FF10414", err)
+
+}
+
 func TestDownloadBlobDisabled(t *testing.T) {
 
 	dm, ctx, cancel := newTestDataManager(t)
@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -29,6 +31,8 @@ import (
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/scanner/scfactory"
+	"github.com/hyperledger/firefly/internal/transform"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/dataexchange"
@@ -36,7 +40,10 @@ import (
 
 type Manager interface {
 	CheckDatatype(ctx context.Context, datatype *core.Datatype) error
+	GetLatestDatatype(ctx context.Context, name string) (*core.Datatype, error)
+	CheckDatatypeCompatibility(ctx context.Context, previous, datatype *core.Datatype) error
 	ValidateAll(ctx context.Context, data core.DataArray) (valid bool, err error)
+	TranscodeDataValue(ctx context.Context, data *core.Data) (*fftypes.JSONAny, error)
 	GetMessageWithDataCached(ctx context.Context, msgID *fftypes.UUID, options ...CacheReadOption) (msg *core.Message, data core.DataArray, foundAllData bool, err error)
 	GetMessageDataCached(ctx context.Context, msg *core.Message, options ...CacheReadOption) (data core.DataArray, foundAll bool, err error)
 	PeekMessageCache(ctx context.Context, id *fftypes.UUID, options ...CacheReadOption) (msg *core.Message, data core.DataArray)
@@ -44,12 +51,22 @@ type Manager interface {
 	UpdateMessageIfCached(ctx context.Context, msg *core.Message)
 	UpdateMessageStateIfCached(ctx context.Context, id *fftypes.UUID, state core.MessageState, confirmed *fftypes.FFTime, rejectReason string)
 	ResolveInlineData(ctx context.Context, msg *NewMessage) error
+	CheckParentMessage(ctx context.Context, msg *core.Message) error
 	WriteNewMessage(ctx context.Context, newMsg *NewMessage) error
 	BlobsEnabled() bool
 
 	UploadJSON(ctx context.Context, inData *core.DataRefOrValue) (*core.Data, error)
 	UploadBlob(ctx context.Context, inData *core.DataRefOrValue, blob *ffapi.Multipart, autoMeta bool) (*core.Data, error)
 	DownloadBlob(ctx context.Context, dataID string) (*core.Blob, io.ReadCloser, error)
+	DownloadBlobRange(ctx context.Context, dataID string, br *core.BlobRange) (*BlobDownload, error)
+	ScanBlob(ctx context.Context, payloadRef string) (finding string, err error)
+	DeleteBlobPayload(ctx context.Context, payloadRef string) error
+	InitBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error)
+	GetBlobUpload(ctx context.Context, id string) (*core.BlobUpload, error)
+	GetBlobUploads(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlobUpload, *ffapi.FilterResult, error)
+	AppendBlobUpload(ctx context.Context, id string, offset int64, chunkHash *fftypes.Bytes32, chunk *ffapi.Multipart) (*core.BlobUpload, error)
+	CompleteBlobUpload(ctx context.Context, id string) (*core.Data, error)
+	DeleteBlobUpload(ctx context.Context, id string) error
 	DeleteData(ctx context.Context, dataID string) error
 	HydrateBatch(ctx context.Context, persistedBatch *core.BatchPersisted) (*core.Batch, error)
 	Start()
@@ -63,6 +80,7 @@ type dataManager struct {
 	validatorCache cache.CInterface
 	messageCache   cache.CInterface
 	messageWriter  *messageWriter
+	transforms     *transform.Pipeline
 }
 
 type messageCacheEntry struct {
@@ -101,10 +119,17 @@ func NewDataManager(ctx context.Context, ns *core.Namespace, di database.Plugin,
 		namespace: ns,
 		database:  di,
 	}
+	uploadDir := config.GetString(coreconfig.BlobUploadDirectory)
+	if uploadDir == "" {
+		uploadDir = filepath.Join(os.TempDir(), "firefly-uploads")
+	}
 	dm.blobStore = blobStore{
-		dm:       dm,
-		database: di,
-		exchange: dx,
+		dm:        dm,
+		database:  di,
+		exchange:  dx,
+		uploadDir: filepath.Join(uploadDir, ns.Name),
+		dedup:     config.GetBool(coreconfig.BlobDeduplicationEnabled),
+		uploading: make(map[fftypes.UUID]bool),
 	}
 
 	validatorCache, err := cacheManager.GetCache(
@@ -132,6 +157,12 @@ func NewDataManager(ctx context.Context, ns *core.Namespace, di database.Plugin,
 		return nil, err
 	}
 	dm.messageCache = messageCache
+	if dm.transforms, err = transform.NewPipeline(ctx, ns.Transforms); err != nil {
+		return nil, err
+	}
+	if dm.blobStore.scanner, err = scfactory.NewScanner(ctx, ns.BlobScanner); err != nil {
+		return nil, err
+	}
 	dm.messageWriter = newMessageWriter(ctx, di, &messageWriterConf{
 		workerCount:  config.GetInt(coreconfig.MessageWriterCount),
 		batchTimeout: config.GetDuration(coreconfig.MessageWriterBatchTimeout),
@@ -149,7 +180,7 @@ func (dm *dataManager) BlobsEnabled() bool {
 }
 
 func (dm *dataManager) CheckDatatype(ctx context.Context, datatype *core.Datatype) error {
-	_, err := newJSONValidator(ctx, dm.namespace.Name, datatype)
+	_, err := newValidator(ctx, dm.namespace.Name, datatype)
 	return err
 }
 
@@ -176,7 +207,11 @@ func (dm *dataManager) getValidatorForDatatype(ctx context.Context, validator co
 	if datatype == nil {
 		return nil, nil
 	}
-	v, err := newJSONValidator(ctx, dm.namespace.Name, datatype)
+	if datatype.Validator != validator && !(datatype.Validator == "" && validator == core.ValidatorTypeJSON) {
+		log.L(ctx).Warnf("Datatype '%s:%s' uses validator '%s' not '%s'", dm.namespace.Name, datatypeRef, datatype.Validator, validator)
+		return nil, nil
+	}
+	v, err := newValidator(ctx, dm.namespace.Name, datatype)
 	if err != nil {
 		log.L(ctx).Errorf("Invalid validator stored for '%s:%s:%s': %s", validator, dm.namespace.Name, datatypeRef, err)
 		return nil, nil
@@ -380,9 +415,20 @@ func (dm *dataManager) checkValidation(ctx context.Context, validator core.Valid
 	}
 	// If a datatype is specified, we need to verify the payload conforms
 	if datatype != nil && validator != core.ValidatorTypeNone {
-		if datatype.Name == "" || datatype.Version == "" {
+		if datatype.Name == "" {
 			return i18n.NewError(ctx, coremsgs.MsgDatatypeNotFound, datatype)
 		}
+		if datatype.Version == "" {
+			// Pin the data to the latest version, so every node validates it against the same schema
+			latest, err := dm.GetLatestDatatype(ctx, datatype.Name)
+			if err != nil {
+				return err
+			}
+			if latest == nil {
+				return i18n.NewError(ctx, coremsgs.MsgDatatypeNotFound, datatype)
+			}
+			datatype.Version = latest.Version
+		}
 		if validator != core.ValidatorTypeNone {
 			v, err := dm.getValidatorForDatatype(ctx, validator, datatype)
 			if err != nil {
@@ -400,6 +446,24 @@ func (dm *dataManager) checkValidation(ctx context.Context, validator core.Valid
 	return nil
 }
 
+// TranscodeDataValue returns the value of the data in JSON form, decoding any binary
+// encoding that the validator of its datatype supports
+func (dm *dataManager) TranscodeDataValue(ctx context.Context, data *core.Data) (*fftypes.JSONAny, error) {
+	switch data.Validator {
+	case core.ValidatorTypeAvro, core.ValidatorTypeProtobuf:
+	default:
+		return data.Value, nil
+	}
+	v, err := dm.getValidatorForDatatype(ctx, data.Validator, data.Datatype)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDatatypeNotFound, data.Datatype)
+	}
+	return v.TranscodeJSON(ctx, data.Value)
+}
+
 func (dm *dataManager) validateInputData(ctx context.Context, inData *core.DataRefOrValue) (data *core.Data, err error) {
 
 	validator := inData.Validator
@@ -470,6 +534,12 @@ func (dm *dataManager) ResolveInlineData(ctx context.Context, newMessage *NewMes
 				return err
 			}
 		case dataOrValue.Value != nil || dataOrValue.Blob != nil:
+			// Run the transforms configured on the namespace, before the value is validated and hashed
+			if transform.AppliesTo(newMessage.Message.Header.Type) {
+				if dataOrValue.Value, err = dm.transforms.Send(ctx, newMessage.Message.Header.Topics, i, dataOrValue.Value); err != nil {
+					return err
+				}
+			}
 			// We've got a Value, so we can validate + store it
 			if d, err = dm.validateInputData(ctx, dataOrValue); err != nil {
 				return err
@@ -486,6 +556,22 @@ func (dm *dataManager) ResolveInlineData(ctx context.Context, newMessage *NewMes
 	return nil
 }
 
+// CheckParentMessage verifies that the message a new message replies to exists, and that it is in the same
+// group - so a reply cannot be broadcast to a private thread, or sent privately to the members of another group
+func (dm *dataManager) CheckParentMessage(ctx context.Context, msg *core.Message) error {
+	parent, err := dm.database.GetMessageByID(ctx, dm.namespace.Name, msg.Header.ParentMessage)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return i18n.NewError(ctx, coremsgs.MsgParentMessageNotFound, msg.Header.ParentMessage)
+	}
+	if !parent.Header.Group.Equals(msg.Header.Group) {
+		return i18n.NewError(ctx, coremsgs.MsgParentMessageGroupMismatch, msg.Header.ParentMessage)
+	}
+	return nil
+}
+
 // HydrateBatch fetches the full messages for a persisted batch, ready for transmission
 func (dm *dataManager) HydrateBatch(ctx context.Context, persistedBatch *core.BatchPersisted) (*core.Batch, error) {
 
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/transform"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
@@ -444,6 +445,107 @@ func TestCheckDatatypeVerifiesTheSchema(t *testing.T) {
 	assert.Regexp(t, "FF10196", err)
 }
 
+func TestCheckDatatypeByValidator(t *testing.T) {
+
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	err := dm.CheckDatatype(ctx, &core.Datatype{Validator: core.ValidatorTypeAvro, Value: fftypes.JSONAnyPtr(`"string"`)})
+	assert.NoError(t, err)
+	err = dm.CheckDatatype(ctx, &core.Datatype{Validator: core.ValidatorTypeAvro, Value: fftypes.JSONAnyPtr(`{}`)})
+	assert.Regexp(t, "FF10196", err)
+	err = dm.CheckDatatype(ctx, &core.Datatype{Validator: core.ValidatorTypeProtobuf, Value: fftypes.JSONAnyPtr(`{}`)})
+	assert.Regexp(t, "FF10196", err)
+	err = dm.CheckDatatype(ctx, &core.Datatype{Validator: "wrong", Value: fftypes.JSONAnyPtr(`{}`)})
+	assert.Regexp(t, "FF00108", err)
+}
+
+func TestValidatorLookupValidatorMismatch(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	ref := &core.DatatypeRef{
+		Name:    "customer",
+		Version: "0.0.1",
+	}
+	dt := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeAvro,
+		Value:     fftypes.JSONAnyPtr(`"string"`),
+		Name:      "customer",
+		Version:   "0.0.1",
+	}
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(dt, nil)
+	v, err := dm.getValidatorForDatatype(ctx, core.ValidatorTypeJSON, ref)
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = dm.getValidatorForDatatype(ctx, core.ValidatorTypeAvro, ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "customer", v.(*avroValidator).datatype.Name)
+}
+
+func TestTranscodeDataValueJSON(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	value := fftypes.JSONAnyPtr(`{"some":"data"}`)
+	transcoded, err := dm.TranscodeDataValue(ctx, &core.Data{
+		Validator: core.ValidatorTypeJSON,
+		Datatype:  &core.DatatypeRef{Name: "customer", Version: "0.0.1"},
+		Value:     value,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, value, transcoded)
+}
+
+func TestTranscodeDataValueAvro(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(&core.Datatype{
+		Validator: core.ValidatorTypeAvro,
+		Value:     fftypes.JSONAnyPtr(`"string"`),
+		Name:      "customer",
+		Version:   "0.0.1",
+	}, nil)
+
+	transcoded, err := dm.TranscodeDataValue(ctx, &core.Data{
+		Validator: core.ValidatorTypeAvro,
+		Datatype:  &core.DatatypeRef{Name: "customer", Version: "0.0.1"},
+		Value:     fftypes.JSONAnyPtr(`"CEpvaG4="`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, `"John"`, transcoded.String())
+}
+
+func TestTranscodeDataValueNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, nil)
+
+	_, err := dm.TranscodeDataValue(ctx, &core.Data{
+		Validator: core.ValidatorTypeProtobuf,
+		Datatype:  &core.DatatypeRef{Name: "customer", Version: "0.0.1"},
+		Value:     fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.Regexp(t, "FF10195", err)
+}
+
+func TestTranscodeDataValueLookupFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "customer", "0.0.1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := dm.TranscodeDataValue(ctx, &core.Data{
+		Validator: core.ValidatorTypeProtobuf,
+		Datatype:  &core.DatatypeRef{Name: "customer", Version: "0.0.1"},
+		Value:     fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.Regexp(t, "pop", err)
+}
+
 func TestResolveInlineDataEmpty(t *testing.T) {
 
 	dm, ctx, cancel := newTestDataManager(t)
@@ -601,6 +703,73 @@ func TestResolveInlineDataValueNoValidatorOK(t *testing.T) {
 	assert.NotNil(t, newMsg.AllData[0].Hash)
 }
 
+func TestResolveInlineDataValueTransformed(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	var err error
+	dm.transforms, err = transform.NewPipeline(ctx, []*core.TransformStep{{
+		Plugin:  "upcast",
+		Options: fftypes.JSONObject{"defaults": map[string]interface{}{"version": "2"}},
+	}})
+	assert.NoError(t, err)
+
+	_, _, newMsg := testNewMessage()
+	newMsg.Message.Header.Type = core.MessageTypeBroadcast
+	newMsg.Message.InlineData = core.InlineData{
+		{Value: fftypes.JSONAnyPtr(`{"some":"json"}`)},
+	}
+
+	err = dm.ResolveInlineData(ctx, newMsg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"some":"json","version":"2"}`, newMsg.AllData[0].Value.String())
+	assert.Equal(t, newMsg.AllData[0].Value.Hash(), newMsg.AllData[0].Hash)
+
+	// Definitions are not transformed
+	_, _, newMsg = testNewMessage()
+	newMsg.Message.Header.Type = core.MessageTypeDefinition
+	newMsg.Message.InlineData = core.InlineData{
+		{Value: fftypes.JSONAnyPtr(`{"some":"json"}`)},
+	}
+	err = dm.ResolveInlineData(ctx, newMsg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"some":"json"}`, newMsg.AllData[0].Value.String())
+}
+
+func TestResolveInlineDataValueTokenized(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	var err error
+	dm.transforms, err = transform.NewPipeline(ctx, []*core.TransformStep{{
+		Plugin: "tokenize",
+		Options: fftypes.JSONObject{
+			"key":    "secret",
+			"fields": []interface{}{"ssn"},
+		},
+	}})
+	assert.NoError(t, err)
+
+	_, _, newMsg := testNewMessage()
+	newMsg.Message.Header.Type = core.MessageTypePrivate
+	newMsg.Message.InlineData = core.InlineData{
+		{Value: fftypes.JSONAnyPtr(`{"ssn":"123"}`)},
+	}
+
+	err = dm.ResolveInlineData(ctx, newMsg)
+	assert.NoError(t, err)
+	assert.Regexp(t, "tok_", newMsg.AllData[0].Value.String())
+}
+
+func TestInitBadTransform(t *testing.T) {
+	ctx := context.Background()
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	ns := &core.Namespace{Name: "ns1", Transforms: []*core.TransformStep{{Plugin: "wrong"}}}
+	_, err := NewDataManager(ctx, ns, &databasemocks.Plugin{}, &dataexchangemocks.Plugin{}, cmi)
+	assert.Regexp(t, "FF10665", err)
+}
+
 func TestResolveInlineDataValueWithValidation(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
@@ -831,6 +1000,75 @@ func TestValidateAllStoredValidatorInvalid(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestCheckParentMessageOK(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	groupID := fftypes.NewRandB32()
+	parentID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, "ns1", parentID).Return(&core.Message{
+		Header: core.MessageHeader{ID: parentID, Group: groupID},
+	}, nil)
+
+	err := dm.CheckParentMessage(ctx, &core.Message{
+		Header: core.MessageHeader{ParentMessage: parentID, Group: groupID},
+	})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckParentMessageNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	parentID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, "ns1", parentID).Return(nil, nil)
+
+	err := dm.CheckParentMessage(ctx, &core.Message{
+		Header: core.MessageHeader{ParentMessage: parentID},
+	})
+	assert.Regexp(t, "FF10635", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckParentMessageGroupMismatch(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	parentID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, "ns1", parentID).Return(&core.Message{
+		Header: core.MessageHeader{ID: parentID, Group: fftypes.NewRandB32()},
+	}, nil)
+
+	err := dm.CheckParentMessage(ctx, &core.Message{
+		Header: core.MessageHeader{ParentMessage: parentID},
+	})
+	assert.Regexp(t, "FF10636", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestCheckParentMessageFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	parentID := fftypes.NewUUID()
+	mdi.On("GetMessageByID", ctx, "ns1", parentID).Return(nil, fmt.Errorf("pop"))
+
+	err := dm.CheckParentMessage(ctx, &core.Message{
+		Header: core.MessageHeader{ParentMessage: parentID},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
 func TestHydrateBatchOK(t *testing.T) {
 	dm, ctx, cancel := newTestDataManager(t)
 	defer cancel()
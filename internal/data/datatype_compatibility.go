@@ -0,0 +1,313 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+var (
+	// schemaAnnotations do not affect which data a schema accepts
+	schemaAnnotations = map[string]bool{
+		"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+		"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+	}
+	// schemaUncheckedKeywords cannot be compared structurally, so any change to them is treated as incompatible
+	schemaUncheckedKeywords = []string{
+		"$ref", "$defs", "definitions", "allOf", "anyOf", "oneOf", "not", "if", "then", "else",
+		"dependentRequired", "dependentSchemas", "patternProperties", "propertyNames",
+		"prefixItems", "contains", "unevaluatedItems", "unevaluatedProperties",
+	}
+	schemaLowerBounds  = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties"}
+	schemaUpperBounds  = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties"}
+	schemaExactMatches = []string{"pattern", "format", "multipleOf", "uniqueItems"}
+)
+
+// schemaCompatibility checks whether one JSON schema (the reader) accepts all of the data that is valid for
+// another JSON schema (the writer). The check is conservative - any change it cannot show to be compatible is
+// reported as a problem.
+type schemaCompatibility struct {
+	problems []string
+}
+
+func (sc *schemaCompatibility) problem(path, format string, args ...interface{}) {
+	sc.problems = append(sc.problems, fmt.Sprintf("%s %s", path, fmt.Sprintf(format, args...)))
+}
+
+func schemaAcceptsAll(schema interface{}) bool {
+	switch s := schema.(type) {
+	case bool:
+		return s
+	case map[string]interface{}:
+		for k := range s {
+			if !schemaAnnotations[k] {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func schemaRejectsAll(schema interface{}) bool {
+	b, ok := schema.(bool)
+	return ok && !b
+}
+
+func schemaObject(schema interface{}) map[string]interface{} {
+	if m, ok := schema.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{}
+}
+
+func schemaSubschema(s map[string]interface{}, keyword string) interface{} {
+	if sub, ok := s[keyword]; ok {
+		return sub
+	}
+	return true
+}
+
+func schemaStrings(v interface{}) []string {
+	switch t := v.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		strs := make([]string, 0, len(t))
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	}
+	return nil
+}
+
+func containsSchemaString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaValues(s map[string]interface{}) ([]interface{}, bool) {
+	if c, ok := s["const"]; ok {
+		return []interface{}{c}, true
+	}
+	if e, ok := s["enum"].([]interface{}); ok {
+		return e, true
+	}
+	return nil, false
+}
+
+func sortedSchemaKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (sc *schemaCompatibility) checkAccepts(path string, reader, writer interface{}) {
+	if reflect.DeepEqual(reader, writer) || schemaAcceptsAll(reader) || schemaRejectsAll(writer) {
+		return
+	}
+	if schemaRejectsAll(reader) {
+		sc.problem(path, "no longer accepts any value")
+		return
+	}
+	r := schemaObject(reader)
+	w := schemaObject(writer)
+	for _, kw := range schemaUncheckedKeywords {
+		if !reflect.DeepEqual(r[kw], w[kw]) {
+			sc.problem(path, "changes '%s', which cannot be checked for compatibility", kw)
+		}
+	}
+	sc.checkTypes(path, r, w)
+	sc.checkValues(path, r, w)
+	sc.checkBounds(path, r, w)
+	sc.checkRequired(path, r, w)
+	sc.checkProperties(path, r, w)
+	if _, ok := r["items"]; ok {
+		sc.checkAccepts(path+"[]", r["items"], schemaSubschema(w, "items"))
+	}
+}
+
+func (sc *schemaCompatibility) checkTypes(path string, r, w map[string]interface{}) {
+	rTypes := schemaStrings(r["type"])
+	if rTypes == nil {
+		return
+	}
+	wTypes := schemaStrings(w["type"])
+	if wTypes == nil {
+		sc.problem(path, "is now restricted to type %s", strings.Join(rTypes, ", "))
+		return
+	}
+	for _, t := range wTypes {
+		if !containsSchemaString(rTypes, t) && !(t == "integer" && containsSchemaString(rTypes, "number")) {
+			sc.problem(path, "no longer accepts type %s", t)
+		}
+	}
+}
+
+func (sc *schemaCompatibility) checkValues(path string, r, w map[string]interface{}) {
+	rValues, restricted := schemaValues(r)
+	if !restricted {
+		return
+	}
+	wValues, wasRestricted := schemaValues(w)
+	if !wasRestricted {
+		sc.problem(path, "is now restricted to a fixed set of values")
+		return
+	}
+	for _, wv := range wValues {
+		found := false
+		for _, rv := range rValues {
+			if reflect.DeepEqual(rv, wv) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			b, _ := json.Marshal(wv)
+			sc.problem(path, "no longer accepts the value %s", b)
+		}
+	}
+}
+
+func (sc *schemaCompatibility) checkBounds(path string, r, w map[string]interface{}) {
+	for _, kw := range schemaLowerBounds {
+		if rb, ok := r[kw].(float64); ok {
+			if wb, ok := w[kw].(float64); !ok || wb < rb {
+				sc.problem(path, "has a higher '%s'", kw)
+			}
+		}
+	}
+	for _, kw := range schemaUpperBounds {
+		if rb, ok := r[kw].(float64); ok {
+			if wb, ok := w[kw].(float64); !ok || wb > rb {
+				sc.problem(path, "has a lower '%s'", kw)
+			}
+		}
+	}
+	for _, kw := range schemaExactMatches {
+		if rv, ok := r[kw]; ok && !reflect.DeepEqual(rv, w[kw]) {
+			sc.problem(path, "changes '%s'", kw)
+		}
+	}
+}
+
+func (sc *schemaCompatibility) checkRequired(path string, r, w map[string]interface{}) {
+	wRequired := schemaStrings(w["required"])
+	for _, name := range schemaStrings(r["required"]) {
+		if !containsSchemaString(wRequired, name) {
+			sc.problem(path, "now requires property '%s'", name)
+		}
+	}
+}
+
+func (sc *schemaCompatibility) checkProperties(path string, r, w map[string]interface{}) {
+	rProps := schemaObject(r["properties"])
+	wProps := schemaObject(w["properties"])
+	rAdditional := schemaSubschema(r, "additionalProperties")
+	wAdditional := schemaSubschema(w, "additionalProperties")
+	for _, name := range sortedSchemaKeys(rProps) {
+		wProp, ok := wProps[name]
+		if !ok {
+			wProp = wAdditional
+		}
+		sc.checkAccepts(path+"."+name, rProps[name], wProp)
+	}
+	for _, name := range sortedSchemaKeys(wProps) {
+		if _, ok := rProps[name]; !ok {
+			if schemaRejectsAll(rAdditional) {
+				sc.problem(path, "no longer accepts property '%s'", name)
+			} else {
+				sc.checkAccepts(path+"."+name, rAdditional, wProps[name])
+			}
+		}
+	}
+	if !schemaRejectsAll(wAdditional) {
+		if schemaRejectsAll(rAdditional) {
+			sc.problem(path, "no longer accepts additional properties")
+		} else {
+			sc.checkAccepts(path+".*", rAdditional, wAdditional)
+		}
+	}
+}
+
+// GetLatestDatatype returns the version of a datatype that was defined most recently, or nil if the datatype has no versions
+func (dm *dataManager) GetLatestDatatype(ctx context.Context, name string) (*core.Datatype, error) {
+	fb := database.DatatypeQueryFactory.NewFilter(ctx)
+	datatypes, _, err := dm.database.GetDatatypes(ctx, dm.namespace.Name, fb.Eq("name", name).Limit(1))
+	if err != nil || len(datatypes) == 0 {
+		return nil, err
+	}
+	return datatypes[0], nil
+}
+
+// CheckDatatypeCompatibility resolves the compatibility of a new version of a datatype, inheriting the compatibility of
+// the previous version if it is not set, and checks the schema of the new version has that compatibility with the previous one
+func (dm *dataManager) CheckDatatypeCompatibility(ctx context.Context, previous, datatype *core.Datatype) error {
+	if datatype.Compatibility == "" {
+		datatype.Compatibility = core.DatatypeCompatibilityNone
+		if previous != nil && previous.Compatibility != "" {
+			datatype.Compatibility = previous.Compatibility
+		}
+	}
+	if previous == nil || datatype.Compatibility == core.DatatypeCompatibilityNone {
+		return nil
+	}
+	if previous.Validator != datatype.Validator {
+		return i18n.NewError(ctx, coremsgs.MsgDatatypeValidatorChanged, datatype.Version, datatype.Name, datatype.Validator, previous.Version, previous.Validator)
+	}
+	if datatype.Validator != core.ValidatorTypeJSON && datatype.Validator != "" {
+		// The rules are defined in terms of JSON Schema, so cannot be applied to Avro or Protobuf schemas
+		return i18n.NewError(ctx, coremsgs.MsgDatatypeCompatibilityUnsupported, datatype.Compatibility, datatype.Validator)
+	}
+
+	// Both schemas have been compiled by the JSON validator, so are known to be valid JSON
+	var previousSchema, newSchema interface{}
+	_ = json.Unmarshal(previous.Value.Bytes(), &previousSchema)
+	_ = json.Unmarshal(datatype.Value.Bytes(), &newSchema)
+
+	sc := &schemaCompatibility{}
+	if datatype.Compatibility == core.DatatypeCompatibilityBackward || datatype.Compatibility == core.DatatypeCompatibilityFull {
+		sc.checkAccepts("$", newSchema, previousSchema)
+	}
+	if datatype.Compatibility == core.DatatypeCompatibilityForward || datatype.Compatibility == core.DatatypeCompatibilityFull {
+		sc.checkAccepts("$", previousSchema, newSchema)
+	}
+	if len(sc.problems) > 0 {
+		return i18n.NewError(ctx, coremsgs.MsgDatatypeIncompatible, datatype.Version, datatype.Name, datatype.Compatibility, previous.Version, strings.Join(sc.problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckDatatypeCompatibility(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	tests := []struct {
+		name          string
+		compatibility core.DatatypeCompatibility
+		previous      string
+		new           string
+		problem       string
+	}{
+		{
+			name:          "identical",
+			compatibility: core.DatatypeCompatibilityFull,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"}}}`,
+		},
+		{
+			name:          "annotations only",
+			compatibility: core.DatatypeCompatibilityFull,
+			previous:      `{"type":"object","title":"old"}`,
+			new:           `{"type":"object","title":"new","description":"changed"}`,
+		},
+		{
+			name:          "backward add optional property to open schema",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"integer"}}}`,
+			problem:       `\$.b is now restricted to type integer`,
+		},
+		{
+			name:          "backward add optional property to closed schema",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}},"additionalProperties":false}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"integer"}},"additionalProperties":false}`,
+		},
+		{
+			name:          "forward add optional property",
+			compatibility: core.DatatypeCompatibilityForward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"integer"}}}`,
+		},
+		{
+			name:          "backward add required property",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"}},"required":["a"]}`,
+			problem:       `\$ now requires property 'a'`,
+		},
+		{
+			name:          "forward add required property",
+			compatibility: core.DatatypeCompatibilityForward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"}},"required":["a"]}`,
+		},
+		{
+			name:          "backward remove property with additional properties disallowed",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"}},"additionalProperties":false}`,
+			problem:       `\$ no longer accepts property 'b'; \$ no longer accepts additional properties`,
+		},
+		{
+			name:          "backward additional properties schema",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}},"additionalProperties":{"type":"string"}}`,
+			new:           `{"type":"object","additionalProperties":{"type":"string"}}`,
+		},
+		{
+			name:          "backward widen integer to number",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"integer"}`,
+			new:           `{"type":["number","null"]}`,
+		},
+		{
+			name:          "full widen integer to number",
+			compatibility: core.DatatypeCompatibilityFull,
+			previous:      `{"type":"integer"}`,
+			new:           `{"type":"number"}`,
+			problem:       `\$ no longer accepts type number`,
+		},
+		{
+			name:          "backward enum values",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"enum":["a","b"]}`,
+			new:           `{"enum":["a","c"]}`,
+			problem:       `\$ no longer accepts the value "b"`,
+		},
+		{
+			name:          "backward const to enum",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"const":"a"}`,
+			new:           `{"enum":["a","b"]}`,
+		},
+		{
+			name:          "backward add enum",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"string"}`,
+			new:           `{"type":"string","enum":["a"]}`,
+			problem:       `\$ is now restricted to a fixed set of values`,
+		},
+		{
+			name:          "backward tighten bounds",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"string","minLength":1,"maxLength":10}`,
+			new:           `{"type":"string","minLength":2,"maxLength":5,"pattern":"^[a-z]+$"}`,
+			problem:       `\$ has a higher 'minLength'; \$ has a lower 'maxLength'; \$ changes 'pattern'`,
+		},
+		{
+			name:          "backward relax bounds",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"string","minLength":2,"maxLength":5,"pattern":"^[a-z]+$"}`,
+			new:           `{"type":"string","minLength":1,"maxLength":10}`,
+		},
+		{
+			name:          "backward add type",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{}`,
+			new:           `{"type":"object"}`,
+			problem:       `\$ is now restricted to type object`,
+		},
+		{
+			name:          "backward array items",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"array","items":{"type":"string"}}`,
+			new:           `{"type":"array","items":{"type":"boolean"}}`,
+			problem:       `\$\[\] no longer accepts type string`,
+		},
+		{
+			name:          "backward reject all",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":false}}`,
+			problem:       `\$.a no longer accepts any value`,
+		},
+		{
+			name:          "backward from reject all",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":false}}`,
+			new:           `{"type":"object","properties":{"a":{"type":"string"}}}`,
+		},
+		{
+			name:          "backward changed reference",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"$ref":"#/$defs/a","$defs":{"a":{"type":"string"}}}`,
+			new:           `{"$ref":"#/$defs/a","$defs":{"a":{"type":"integer"}}}`,
+			problem:       `\$ changes '\$defs', which cannot be checked for compatibility`,
+		},
+		{
+			name:          "backward accept all",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `true`,
+		},
+		{
+			name:          "backward annotations only",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"string"}`,
+			new:           `{"description":"anything"}`,
+		},
+		{
+			name:          "backward ignores values that are not schemas",
+			compatibility: core.DatatypeCompatibilityBackward,
+			previous:      `{"type":"object","properties":{"a":{"type":"string"}}}`,
+			new:           `{"type":"object","properties":{"a":null}}`,
+		},
+		{
+			name:          "none",
+			compatibility: core.DatatypeCompatibilityNone,
+			previous:      `{"type":"string"}`,
+			new:           `{"type":"integer"}`,
+		},
+	}
+	for _, test := range tests {
+		previous := &core.Datatype{Name: "customer", Version: "0.0.1", Value: fftypes.JSONAnyPtr(test.previous)}
+		datatype := &core.Datatype{Name: "customer", Version: "0.0.2", Value: fftypes.JSONAnyPtr(test.new), Compatibility: test.compatibility}
+		err := dm.CheckDatatypeCompatibility(ctx, previous, datatype)
+		if test.problem == "" {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.Regexp(t, "FF10685.*'0.0.2'.*'customer'.*"+test.compatibility.String()+".*'0.0.1': "+test.problem+"$", err, test.name)
+		}
+	}
+}
+
+func TestCheckDatatypeCompatibilityInherited(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	previous := &core.Datatype{Name: "customer", Version: "0.0.1", Value: fftypes.JSONAnyPtr(`{"type":"string"}`), Compatibility: core.DatatypeCompatibilityBackward}
+	datatype := &core.Datatype{Name: "customer", Version: "0.0.2", Value: fftypes.JSONAnyPtr(`{"type":"integer"}`)}
+	err := dm.CheckDatatypeCompatibility(ctx, previous, datatype)
+	assert.Regexp(t, "FF10685", err)
+	assert.Equal(t, core.DatatypeCompatibilityBackward, datatype.Compatibility)
+}
+
+func TestCheckDatatypeCompatibilityDefault(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	previous := &core.Datatype{Name: "customer", Version: "0.0.1", Value: fftypes.JSONAnyPtr(`{"type":"string"}`)}
+	datatype := &core.Datatype{Name: "customer", Version: "0.0.2", Value: fftypes.JSONAnyPtr(`{"type":"integer"}`)}
+	err := dm.CheckDatatypeCompatibility(ctx, previous, datatype)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DatatypeCompatibilityNone, datatype.Compatibility)
+
+	datatype = &core.Datatype{Name: "customer", Version: "0.0.1", Value: fftypes.JSONAnyPtr(`{"type":"integer"}`), Compatibility: core.DatatypeCompatibilityFull}
+	err = dm.CheckDatatypeCompatibility(ctx, nil, datatype)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DatatypeCompatibilityFull, datatype.Compatibility)
+}
+
+func TestCheckDatatypeCompatibilityValidators(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+
+	previous := &core.Datatype{Name: "customer", Version: "0.0.1", Validator: core.ValidatorTypeJSON, Value: fftypes.JSONAnyPtr(`{"type":"string"}`)}
+	datatype := &core.Datatype{Name: "customer", Version: "0.0.2", Validator: core.ValidatorTypeAvro, Value: fftypes.JSONAnyPtr(`"string"`), Compatibility: core.DatatypeCompatibilityBackward}
+	err := dm.CheckDatatypeCompatibility(ctx, previous, datatype)
+	assert.Regexp(t, "FF10688.*'0.0.2'.*'customer'.*'avro'.*'0.0.1'.*'json'", err)
+
+	previous.Validator = core.ValidatorTypeAvro
+	err = dm.CheckDatatypeCompatibility(ctx, previous, datatype)
+	assert.Regexp(t, "FF10687.*backward.*avro", err)
+
+	datatype.Compatibility = core.DatatypeCompatibilityNone
+	err = dm.CheckDatatypeCompatibility(ctx, previous, datatype)
+	assert.NoError(t, err)
+}
+
+func TestGetLatestDatatype(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	latest := &core.Datatype{Name: "customer", Version: "0.0.2"}
+	mdi.On("GetDatatypes", ctx, "ns1", mock.Anything).Return([]*core.Datatype{latest}, nil, nil).Once()
+	mdi.On("GetDatatypes", ctx, "ns1", mock.Anything).Return([]*core.Datatype{}, nil, nil).Once()
+	mdi.On("GetDatatypes", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Once()
+
+	dt, err := dm.GetLatestDatatype(ctx, "customer")
+	assert.NoError(t, err)
+	assert.Equal(t, latest, dt)
+
+	dt, err = dm.GetLatestDatatype(ctx, "customer")
+	assert.NoError(t, err)
+	assert.Nil(t, dt)
+
+	_, err = dm.GetLatestDatatype(ctx, "customer")
+	assert.EqualError(t, err, "pop")
+}
+
+func TestValidateInputDataPinsLatestDatatype(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypes", ctx, "ns1", mock.Anything).Return([]*core.Datatype{{Name: "customer", Version: "0.0.2"}}, nil, nil)
+	mdi.On("GetDatatypeByName", ctx, "ns1", "customer", "0.0.2").Return(&core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Namespace: "ns1",
+		Name:      "customer",
+		Version:   "0.0.2",
+		Value:     fftypes.JSONAnyPtr(`{"type":"object","required":["field1"]}`),
+	}, nil)
+
+	data, err := dm.validateInputData(ctx, &core.DataRefOrValue{
+		Datatype: &core.DatatypeRef{Name: "customer"},
+		Value:    fftypes.JSONAnyPtr(`{"field1":"value1"}`),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "0.0.2", data.Datatype.Version)
+
+	_, err = dm.validateInputData(ctx, &core.DataRefOrValue{
+		Datatype: &core.DatatypeRef{Name: "customer"},
+		Value:    fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.Regexp(t, "FF10198", err)
+}
+
+func TestValidateInputDataLatestDatatypeNotFound(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypes", ctx, "ns1", mock.Anything).Return([]*core.Datatype{}, nil, nil)
+
+	_, err := dm.validateInputData(ctx, &core.DataRefOrValue{
+		Datatype: &core.DatatypeRef{Name: "customer"},
+		Value:    fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.Regexp(t, "FF10195", err)
+
+	_, err = dm.validateInputData(ctx, &core.DataRefOrValue{
+		Datatype: &core.DatatypeRef{Version: "0.0.1"},
+		Value:    fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.Regexp(t, "FF10195", err)
+}
+
+func TestValidateInputDataLatestDatatypeFail(t *testing.T) {
+	dm, ctx, cancel := newTestDataManager(t)
+	defer cancel()
+	mdi := dm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetDatatypes", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := dm.validateInputData(ctx, &core.DataRefOrValue{
+		Datatype: &core.DatatypeRef{Name: "customer"},
+		Value:    fftypes.JSONAnyPtr(`{}`),
+	})
+	assert.EqualError(t, err, "pop")
+}
@@ -1,4 +1,4 @@
-// Copyright © 2022 Kaleido, Inc.
+// Copyright © 2023 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -99,6 +99,11 @@ func (jv *jsonValidator) validateJSONString(ctx context.Context, input string) e
 	return nil
 }
 
+// TranscodeJSON has nothing to do, as the value is already JSON
+func (jv *jsonValidator) TranscodeJSON(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	return value, nil
+}
+
 func (jv *jsonValidator) Size() int64 {
 	return jv.size
 }
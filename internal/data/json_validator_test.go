@@ -55,6 +55,11 @@ func TestJSONValidator(t *testing.T) {
 	err = jv.validateJSONString(context.Background(), `{!bad json`)
 	assert.Regexp(t, "FF00127", err)
 
+	value := fftypes.JSONAnyPtr(`{"prop1": "a value"}`)
+	transcoded, err := jv.TranscodeJSON(context.Background(), value)
+	assert.NoError(t, err)
+	assert.Equal(t, value, transcoded)
+
 	assert.Equal(t, int64(len(schemaBinary)), jv.Size())
 
 }
@@ -0,0 +1,176 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// protobufSchema is the value of a datatype with the protobuf validator
+type protobufSchema struct {
+	Proto   string `json:"proto"`
+	Message string `json:"message"`
+}
+
+// protobufValidator validates data against a message of a Protocol Buffers schema, which is compiled
+// from the .proto source stored in the datatype. Data values can be supplied either in the canonical
+// protobuf JSON mapping, or as a base64 string containing the binary wire format - which can be
+// transcoded to the JSON mapping for query and display.
+type protobufValidator struct {
+	id         *fftypes.UUID
+	size       int64
+	ns         string
+	datatype   *core.DatatypeRef
+	descriptor protoreflect.MessageDescriptor
+}
+
+func newProtobufValidator(ctx context.Context, ns string, datatype *core.Datatype) (*protobufValidator, error) {
+	pv := &protobufValidator{
+		id: datatype.ID,
+		ns: ns,
+		datatype: &core.DatatypeRef{
+			Name:    datatype.Name,
+			Version: datatype.Version,
+		},
+	}
+
+	var schema protobufSchema
+	err := json.Unmarshal(datatype.Value.Bytes(), &schema)
+	if err == nil && (schema.Proto == "" || schema.Message == "") {
+		err = fmt.Errorf("the 'proto' source and 'message' name are both required")
+	}
+	if err == nil {
+		pv.descriptor, err = compileProtobufMessage(ctx, datatype.Name, &schema)
+	}
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgSchemaLoadFailed, pv.datatype)
+	}
+	pv.size = datatype.Value.Length()
+
+	log.L(ctx).Debugf("Found Protobuf schema validator for protobuf:%s:%s: %v", pv.ns, datatype, pv.id)
+	return pv, nil
+}
+
+func compileProtobufMessage(ctx context.Context, name string, schema *protobufSchema) (protoreflect.MessageDescriptor, error) {
+	filename := name + ".proto"
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{filename: schema.Proto}),
+		}),
+	}
+	files, err := compiler.Compile(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	md, ok := files[0].FindDescriptorByName(protoreflect.FullName(schema.Message)).(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("message '%s' is not defined", schema.Message)
+	}
+	return md, nil
+}
+
+func (pv *protobufValidator) Validate(ctx context.Context, data *core.Data) error {
+	return pv.ValidateValue(ctx, data.Value, data.Hash)
+}
+
+func (pv *protobufValidator) ValidateValue(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error {
+	if err := checkValueHash(ctx, value, expectedHash); err != nil {
+		return err
+	}
+	_, _, err := pv.decode(ctx, value)
+	return err
+}
+
+func (pv *protobufValidator) TranscodeJSON(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	msg, binary, err := pv.decode(ctx, value)
+	if err != nil || !binary {
+		return value, err
+	}
+	textual, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDataInvalidPerSchema, core.ValidatorTypeProtobuf, pv.datatype, err)
+	}
+	// The protojson output deliberately varies its whitespace, so compact it for a stable result
+	var buf bytes.Buffer
+	_ = json.Compact(&buf, textual)
+	return fftypes.JSONAnyPtrBytes(buf.Bytes()), nil
+}
+
+// decode parses the value into a dynamic message, returning whether it was supplied in the binary wire format
+func (pv *protobufValidator) decode(ctx context.Context, value *fftypes.JSONAny) (msg *dynamicpb.Message, binary bool, err error) {
+	msg = dynamicpb.NewMessage(pv.descriptor)
+	if encoded, isString := jsonStringValue(value); isString {
+		binary = true
+		var input []byte
+		if input, err = base64.StdEncoding.DecodeString(encoded); err == nil {
+			err = proto.Unmarshal(input, msg)
+		}
+		if err == nil {
+			// The wire format silently retains fields the schema does not define, so reject them
+			err = checkNoUnknownFields(msg)
+		}
+	} else {
+		err = protojson.Unmarshal(value.Bytes(), msg)
+	}
+	if err != nil {
+		log.L(ctx).Warnf("Protobuf schema %s [%v] validation failed: %s", pv.datatype, pv.id, err)
+		return nil, binary, i18n.NewError(ctx, coremsgs.MsgDataInvalidPerSchema, core.ValidatorTypeProtobuf, pv.datatype, err)
+	}
+	return msg, binary, nil
+}
+
+func (pv *protobufValidator) Size() int64 {
+	return pv.size
+}
+
+func checkNoUnknownFields(msg protoreflect.Message) (err error) {
+	if len(msg.GetUnknown()) > 0 {
+		return fmt.Errorf("message '%s' contains fields that are not in the schema", msg.Descriptor().FullName())
+	}
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			for i := 0; i < v.List().Len() && err == nil; i++ {
+				err = checkNoUnknownFields(v.List().Get(i).Message())
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				err = checkNoUnknownFields(mv.Message())
+				return err == nil
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			err = checkNoUnknownFields(v.Message())
+		}
+		return err == nil
+	})
+	return err
+}
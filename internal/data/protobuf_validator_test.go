@@ -0,0 +1,194 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package data
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const protobufTestSource = `
+syntax = "proto3";
+package example;
+
+import "google/protobuf/timestamp.proto";
+
+message Address {
+	string city = 1;
+}
+
+message Customer {
+	string name = 1;
+	int32 age = 2;
+	repeated Address addresses = 3;
+	map<string, Address> named = 4;
+	Address primary = 5;
+	google.protobuf.Timestamp joined = 6;
+}
+`
+
+func protobufTestDatatype(source, message string) *core.Datatype {
+	schema, _ := json.Marshal(&protobufSchema{Proto: source, Message: message})
+	return &core.Datatype{
+		Validator: core.ValidatorTypeProtobuf,
+		Name:      "customer",
+		Version:   "0.0.1",
+		Value:     fftypes.JSONAnyPtrBytes(schema),
+	}
+}
+
+func newTestProtobufValidator(t *testing.T) *protobufValidator {
+	pv, err := newProtobufValidator(context.Background(), "ns1", protobufTestDatatype(protobufTestSource, "example.Customer"))
+	assert.NoError(t, err)
+	return pv
+}
+
+func protobufTestBinary(t *testing.T, pv *protobufValidator, jsonValue string, extra ...byte) *fftypes.JSONAny {
+	msg := dynamicpb.NewMessage(pv.descriptor)
+	err := protojson.Unmarshal([]byte(jsonValue), msg)
+	assert.NoError(t, err)
+	b, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+	return fftypes.JSONAnyPtr(fmt.Sprintf(`"%s"`, base64.StdEncoding.EncodeToString(append(b, extra...))))
+}
+
+func TestProtobufValidatorJSON(t *testing.T) {
+	pv := newTestProtobufValidator(t)
+	ctx := context.Background()
+
+	value := fftypes.JSONAnyPtr(`{"name": "John", "age": 30, "addresses": [{"city": "London"}], "joined": "2023-01-01T00:00:00Z"}`)
+	err := pv.ValidateValue(ctx, value, value.Hash())
+	assert.NoError(t, err)
+
+	transcoded, err := pv.TranscodeJSON(ctx, value)
+	assert.NoError(t, err)
+	assert.Equal(t, value, transcoded)
+
+	err = pv.ValidateValue(ctx, fftypes.JSONAnyPtr(`{"name": "John", "unknown": true}`), nil)
+	assert.Regexp(t, "FF10686.*protobuf.*customer", err)
+
+	err = pv.ValidateValue(ctx, fftypes.JSONAnyPtr(`{"age": "thirty"}`), nil)
+	assert.Regexp(t, "FF10686", err)
+
+	assert.Equal(t, protobufTestDatatype(protobufTestSource, "example.Customer").Value.Length(), pv.Size())
+}
+
+func TestProtobufValidatorBinary(t *testing.T) {
+	pv := newTestProtobufValidator(t)
+	ctx := context.Background()
+
+	value := protobufTestBinary(t, pv, `{
+		"name": "John",
+		"age": 30,
+		"addresses": [{"city": "London"}],
+		"named": {"home": {"city": "Paris"}},
+		"primary": {"city": "Rome"}
+	}`)
+	err := pv.Validate(ctx, &core.Data{Value: value, Hash: value.Hash()})
+	assert.NoError(t, err)
+
+	transcoded, err := pv.TranscodeJSON(ctx, value)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"name": "John",
+		"age": 30,
+		"addresses": [{"city": "London"}],
+		"named": {"home": {"city": "Paris"}},
+		"primary": {"city": "Rome"}
+	}`, transcoded.String())
+	assert.NotContains(t, transcoded.String(), " ")
+
+	err = pv.ValidateValue(ctx, fftypes.JSONAnyPtr(`"!not base64"`), nil)
+	assert.Regexp(t, "FF10686", err)
+
+	err = pv.ValidateValue(ctx, fftypes.JSONAnyPtr(`"Cg=="`), nil)
+	assert.Regexp(t, "FF10686", err)
+
+	_, err = pv.TranscodeJSON(ctx, fftypes.JSONAnyPtr(`"Cg=="`))
+	assert.Regexp(t, "FF10686", err)
+}
+
+func TestProtobufValidatorBinaryUnknownFields(t *testing.T) {
+	pv := newTestProtobufValidator(t)
+	ctx := context.Background()
+
+	unknown := protowire.AppendTag(nil, 99, protowire.VarintType)
+	unknown = protowire.AppendVarint(unknown, 1)
+
+	err := pv.ValidateValue(ctx, protobufTestBinary(t, pv, `{"name": "John"}`, unknown...), nil)
+	assert.Regexp(t, "FF10686.*example.Customer.*not in the schema", err)
+
+	nested := func(field protowire.Number) []byte {
+		b := protowire.AppendTag(nil, field, protowire.BytesType)
+		return protowire.AppendBytes(b, unknown)
+	}
+	err = pv.ValidateValue(ctx, protobufTestBinary(t, pv, `{}`, nested(5)...), nil)
+	assert.Regexp(t, "FF10686.*example.Address.*not in the schema", err)
+
+	err = pv.ValidateValue(ctx, protobufTestBinary(t, pv, `{}`, nested(3)...), nil)
+	assert.Regexp(t, "FF10686.*example.Address.*not in the schema", err)
+
+	mapEntry := protowire.AppendTag(nil, 2, protowire.BytesType)
+	mapEntry = protowire.AppendBytes(mapEntry, unknown)
+	mapField := protowire.AppendTag(nil, 4, protowire.BytesType)
+	mapField = protowire.AppendBytes(mapField, mapEntry)
+	err = pv.ValidateValue(ctx, protobufTestBinary(t, pv, `{}`, mapField...), nil)
+	assert.Regexp(t, "FF10686.*example.Address.*not in the schema", err)
+}
+
+func TestProtobufValidatorBadHash(t *testing.T) {
+	pv := newTestProtobufValidator(t)
+
+	err := pv.ValidateValue(context.Background(), fftypes.JSONAnyPtr(`{"name": "John"}`), fftypes.NewRandB32())
+	assert.Regexp(t, "FF10201", err)
+}
+
+func TestProtobufValidatorNilData(t *testing.T) {
+	pv := newTestProtobufValidator(t)
+
+	err := pv.Validate(context.Background(), &core.Data{})
+	assert.Regexp(t, "FF10199", err)
+}
+
+func TestProtobufValidatorParseSchemaFail(t *testing.T) {
+	ctx := context.Background()
+
+	dt := protobufTestDatatype(protobufTestSource, "example.Customer")
+	dt.Value = fftypes.JSONAnyPtr(`"not an object"`)
+	_, err := newProtobufValidator(ctx, "ns1", dt)
+	assert.Regexp(t, "FF10196", err)
+
+	_, err = newProtobufValidator(ctx, "ns1", protobufTestDatatype(protobufTestSource, ""))
+	assert.Regexp(t, "FF10196.*required", err)
+
+	_, err = newProtobufValidator(ctx, "ns1", protobufTestDatatype(`syntax = "proto3"; message {`, "Customer"))
+	assert.Regexp(t, "FF10196", err)
+
+	_, err = newProtobufValidator(ctx, "ns1", protobufTestDatatype(protobufTestSource, "example.Missing"))
+	assert.Regexp(t, "FF10196.*example.Missing", err)
+}
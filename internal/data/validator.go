@@ -1,4 +1,4 @@
-// Copyright © 2022 Kaleido, Inc.
+// Copyright © 2023 Kaleido, Inc.
 //
 // SPDX-License-Identifier: Apache-2.0
 //
@@ -20,11 +20,42 @@ import (
 	"context"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
 type Validator interface {
 	Validate(ctx context.Context, data *core.Data) error
 	ValidateValue(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error
+	TranscodeJSON(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error)
 	Size() int64 // for cache management
 }
+
+// newValidator builds the validator declared by the datatype, compiling its schema
+func newValidator(ctx context.Context, ns string, datatype *core.Datatype) (Validator, error) {
+	switch datatype.Validator {
+	case core.ValidatorTypeJSON, "":
+		return newJSONValidator(ctx, ns, datatype)
+	case core.ValidatorTypeAvro:
+		return newAvroValidator(ctx, ns, datatype)
+	case core.ValidatorTypeProtobuf:
+		return newProtobufValidator(ctx, ns, datatype)
+	default:
+		return nil, i18n.NewError(ctx, i18n.MsgUnknownValidatorType, datatype.Validator)
+	}
+}
+
+// checkValueHash performs the null and hash checks common to all validators
+func checkValueHash(ctx context.Context, value *fftypes.JSONAny, expectedHash *fftypes.Bytes32) error {
+	if value == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDataValueIsNull)
+	}
+	if expectedHash != nil {
+		hash := value.Hash()
+		if *hash != *expectedHash {
+			return i18n.NewError(ctx, coremsgs.MsgDataInvalidHash, hash, expectedHash)
+		}
+	}
+	return nil
+}
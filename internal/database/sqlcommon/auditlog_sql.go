@@ -0,0 +1,135 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	auditLogColumns = []string{
+		"id",
+		"namespace",
+		"principal",
+		"method",
+		"route",
+		"path",
+		"request_hash",
+		"idempotency_key",
+		"tx_id",
+		"status",
+		"error",
+		"created",
+	}
+	auditLogFilterFieldMap = map[string]string{
+		"requesthash":    "request_hash",
+		"idempotencykey": "idempotency_key",
+		"tx":             "tx_id",
+	}
+)
+
+const auditLogTable = "auditlog"
+
+func (s *SQLCommon) InsertAuditLogEntry(ctx context.Context, entry *core.AuditLogEntry) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if entry.Created == nil {
+		entry.Created = fftypes.Now()
+	}
+	if _, err = s.InsertTx(ctx, auditLogTable, tx,
+		sq.Insert(auditLogTable).
+			Columns(auditLogColumns...).
+			Values(
+				entry.ID,
+				entry.Namespace,
+				entry.Principal,
+				entry.Method,
+				entry.Route,
+				entry.Path,
+				entry.RequestHash,
+				entry.IdempotencyKey,
+				entry.Transaction,
+				entry.Status,
+				entry.Error,
+				entry.Created,
+			),
+		nil, // no change events for the audit log
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) auditLogResult(ctx context.Context, row *sql.Rows) (*core.AuditLogEntry, error) {
+	entry := core.AuditLogEntry{}
+	err := row.Scan(
+		&entry.ID,
+		&entry.Namespace,
+		&entry.Principal,
+		&entry.Method,
+		&entry.Route,
+		&entry.Path,
+		&entry.RequestHash,
+		&entry.IdempotencyKey,
+		&entry.Transaction,
+		&entry.Status,
+		&entry.Error,
+		&entry.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, auditLogTable)
+	}
+	return &entry, nil
+}
+
+func (s *SQLCommon) GetAuditLog(ctx context.Context, namespace string, filter ffapi.Filter) (entries []*core.AuditLogEntry, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(auditLogColumns...).From(auditLogTable),
+		filter, auditLogFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, auditLogTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	entries = []*core.AuditLogEntry{}
+	for rows.Next() {
+		entry, err := s.auditLogResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, s.QueryRes(ctx, auditLogTable, tx, fop, fi), err
+}
@@ -0,0 +1,128 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLogE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new audit log entry
+	entry := &core.AuditLogEntry{
+		ID:             fftypes.NewUUID(),
+		Namespace:      "ns1",
+		Principal:      "alice",
+		Method:         "POST",
+		Route:          "messages/broadcast",
+		Path:           "/api/v1/namespaces/ns1/messages/broadcast",
+		RequestHash:    fftypes.NewRandB32(),
+		IdempotencyKey: "key1",
+		Transaction:    fftypes.NewUUID(),
+		Status:         202,
+	}
+	err := s.InsertAuditLogEntry(ctx, entry)
+	assert.NoError(t, err)
+	assert.NotNil(t, entry.Created)
+	entryJson, _ := json.Marshal(&entry)
+
+	// Query back the entry (by query filter)
+	fb := database.AuditLogQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("principal", "alice"),
+		fb.Eq("requesthash", entry.RequestHash),
+		fb.Eq("tx", entry.Transaction),
+		fb.Gte("status", 200),
+	)
+	entries, res, err := s.GetAuditLog(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	entryReadJson, _ := json.Marshal(entries[0])
+	assert.Equal(t, string(entryJson), string(entryReadJson))
+
+	// Other namespaces do not see the entry
+	entries, _, err = s.GetAuditLog(ctx, "ns2", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestInsertAuditLogEntryFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertAuditLogEntry(context.Background(), &core.AuditLogEntry{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertAuditLogEntryFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertAuditLogEntry(context.Background(), &core.AuditLogEntry{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertAuditLogEntryFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertAuditLogEntry(context.Background(), &core.AuditLogEntry{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuditLogQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.AuditLogQueryFactory.NewFilter(context.Background()).Eq("principal", "")
+	_, _, err := s.GetAuditLog(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuditLogBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.AuditLogQueryFactory.NewFilter(context.Background()).Eq("principal", map[bool]bool{true: false})
+	_, _, err := s.GetAuditLog(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*principal", err)
+}
+
+func TestGetAuditLogScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.AuditLogQueryFactory.NewFilter(context.Background()).Eq("principal", "")
+	_, _, err := s.GetAuditLog(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -51,7 +51,8 @@ func TestBatch2EWithDB(t *testing.T) {
 			Node:      fftypes.NewUUID(),
 			Created:   fftypes.Now(),
 		},
-		Hash: fftypes.NewRandB32(),
+		Hash:           fftypes.NewRandB32(),
+		DisclosureSalt: fftypes.NewRandB32(),
 		TX: core.TransactionRef{
 			Type: core.TransactionTypeUnpinned,
 		},
@@ -75,6 +76,7 @@ func TestBatch2EWithDB(t *testing.T) {
 	batchJson, _ := json.Marshal(&batch)
 	batchReadJson, _ := json.Marshal(&batchRead)
 	assert.Equal(t, string(batchJson), string(batchReadJson))
+	assert.Equal(t, batch.DisclosureSalt, batchRead.DisclosureSalt)
 
 	// Try to insert again - should get back the existing row
 	existing, err = s.InsertOrGetBatch(ctx, batch)
@@ -0,0 +1,195 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	batchPolicyColumns = []string{
+		"namespace",
+		"topic",
+		"max_latency",
+		"max_size",
+		"created",
+		"updated",
+	}
+)
+
+const batchpolicyTable = "batchpolicy"
+
+// UpsertBatchPolicy replaces the policy for a topic. If the topic already has a policy, its
+// creation time is retained and set on the supplied policy.
+func (s *SQLCommon) UpsertBatchPolicy(ctx context.Context, policy *core.BatchPolicy) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	topic := sq.Eq{
+		"namespace": policy.Namespace,
+		"topic":     policy.Topic,
+	}
+	rows, _, err := s.QueryTx(ctx, batchpolicyTable, tx,
+		sq.Select("created").
+			From(batchpolicyTable).
+			Where(topic),
+	)
+	if err != nil {
+		return err
+	}
+	existing := rows.Next()
+	if existing {
+		err = rows.Scan(&policy.Created)
+	}
+	rows.Close()
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, batchpolicyTable)
+	}
+
+	policy.Updated = fftypes.Now()
+	if existing {
+		if _, err = s.UpdateTx(ctx, batchpolicyTable, tx,
+			sq.Update(batchpolicyTable).
+				Set("max_latency", batchPolicyMaxLatencyMS(policy)).
+				Set("max_size", policy.MaxSize).
+				Set("updated", policy.Updated).
+				Where(topic),
+			nil, // no change events for batch policies
+		); err != nil {
+			return err
+		}
+	} else {
+		policy.Created = policy.Updated
+		if _, err = s.InsertTx(ctx, batchpolicyTable, tx,
+			sq.Insert(batchpolicyTable).
+				Columns(batchPolicyColumns...).
+				Values(
+					policy.Namespace,
+					policy.Topic,
+					batchPolicyMaxLatencyMS(policy),
+					policy.MaxSize,
+					policy.Created,
+					policy.Updated,
+				),
+			nil, // no change events for batch policies
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+// batchPolicyMaxLatencyMS stores the max latency as a number of milliseconds, or null if it is not set
+func batchPolicyMaxLatencyMS(policy *core.BatchPolicy) interface{} {
+	if policy.MaxLatency == nil {
+		return nil
+	}
+	return time.Duration(*policy.MaxLatency).Milliseconds()
+}
+
+func (s *SQLCommon) batchPolicyResult(ctx context.Context, row *sql.Rows) (*core.BatchPolicy, error) {
+	policy := core.BatchPolicy{}
+	var maxLatency fftypes.FFDuration
+	var maxSize sql.NullInt64
+	err := row.Scan(
+		&policy.Namespace,
+		&policy.Topic,
+		&maxLatency,
+		&maxSize,
+		&policy.Created,
+		&policy.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, batchpolicyTable)
+	}
+	if maxLatency > 0 {
+		policy.MaxLatency = &maxLatency
+	}
+	policy.MaxSize = uint(maxSize.Int64)
+	return &policy, nil
+}
+
+func (s *SQLCommon) GetBatchPolicies(ctx context.Context, namespace string) ([]*core.BatchPolicy, error) {
+	rows, _, err := s.Query(ctx, batchpolicyTable,
+		sq.Select(batchPolicyColumns...).
+			From(batchpolicyTable).
+			Where(sq.Eq{"namespace": namespace}).
+			OrderBy("topic"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policies := []*core.BatchPolicy{}
+	for rows.Next() {
+		policy, err := s.batchPolicyResult(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+func (s *SQLCommon) GetBatchPolicy(ctx context.Context, namespace, topic string) (*core.BatchPolicy, error) {
+	rows, _, err := s.Query(ctx, batchpolicyTable,
+		sq.Select(batchPolicyColumns...).
+			From(batchpolicyTable).
+			Where(sq.Eq{"namespace": namespace, "topic": topic}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Batch policy for topic '%s' not found", topic)
+		return nil, nil
+	}
+
+	return s.batchPolicyResult(ctx, rows)
+}
+
+func (s *SQLCommon) DeleteBatchPolicy(ctx context.Context, namespace, topic string) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, batchpolicyTable, tx,
+		sq.Delete(batchpolicyTable).Where(sq.Eq{"namespace": namespace, "topic": topic}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,186 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchPolicyE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	maxLatency := fftypes.FFDuration(50 * time.Millisecond)
+	policy := &core.BatchPolicy{
+		Namespace:  "ns1",
+		Topic:      "topic1",
+		MaxLatency: &maxLatency,
+	}
+	err := s.UpsertBatchPolicy(ctx, policy)
+	assert.NoError(t, err)
+	assert.NotNil(t, policy.Created)
+	policyJson, _ := json.Marshal(&policy)
+
+	// Query back the policy
+	policyRead, err := s.GetBatchPolicy(ctx, "ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Zero(t, policyRead.MaxSize)
+	policyReadJson, _ := json.Marshal(&policyRead)
+	assert.Equal(t, string(policyJson), string(policyReadJson))
+
+	// Replace the policy, which keeps the original creation time
+	updated := &core.BatchPolicy{
+		Namespace: "ns1",
+		Topic:     "topic1",
+		MaxSize:   10,
+	}
+	err = s.UpsertBatchPolicy(ctx, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.Created.String(), updated.Created.String())
+	policyRead, err = s.GetBatchPolicy(ctx, "ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Nil(t, policyRead.MaxLatency)
+	assert.Equal(t, uint(10), policyRead.MaxSize)
+
+	// List the policies of the namespace
+	err = s.UpsertBatchPolicy(ctx, &core.BatchPolicy{Namespace: "ns2", Topic: "topic1", MaxSize: 1})
+	assert.NoError(t, err)
+	policies, err := s.GetBatchPolicies(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+	assert.Equal(t, uint(10), policies[0].MaxSize)
+
+	// Delete the policy
+	err = s.DeleteBatchPolicy(ctx, "ns1", "topic1")
+	assert.NoError(t, err)
+	policyRead, err = s.GetBatchPolicy(ctx, "ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Nil(t, policyRead)
+}
+
+func TestUpsertBatchPolicyFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertBatchPolicy(context.Background(), &core.BatchPolicy{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertBatchPolicyFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertBatchPolicy(context.Background(), &core.BatchPolicy{})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertBatchPolicyFailScan(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"created", "updated"}).AddRow(0, 0))
+	mock.ExpectRollback()
+	err := s.UpsertBatchPolicy(context.Background(), &core.BatchPolicy{})
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertBatchPolicyFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertBatchPolicy(context.Background(), &core.BatchPolicy{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertBatchPolicyFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"created"}).AddRow(0))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertBatchPolicy(context.Background(), &core.BatchPolicy{})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertBatchPolicyFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertBatchPolicy(context.Background(), &core.BatchPolicy{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchPoliciesSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetBatchPolicies(context.Background(), "ns1")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchPoliciesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	_, err := s.GetBatchPolicies(context.Background(), "ns1")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBatchPolicySelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetBatchPolicy(context.Background(), "ns1", "topic1")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBatchPolicyFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteBatchPolicy(context.Background(), "ns1", "topic1")
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBatchPolicyFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteBatchPolicy(context.Background(), "ns1", "topic1")
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
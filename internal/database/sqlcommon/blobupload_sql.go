@@ -0,0 +1,208 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	blobUploadColumns = []string{
+		"id",
+		"namespace",
+		"upload_offset",
+		"size",
+		"hash",
+		"filename",
+		"mimetype",
+		"autometa",
+		"validator",
+		"datatype_name",
+		"datatype_version",
+		"value",
+		"created",
+		"updated",
+	}
+	blobUploadFilterFieldMap = map[string]string{
+		"offset":           "upload_offset",
+		"datatype.name":    "datatype_name",
+		"datatype.version": "datatype_version",
+	}
+)
+
+const blobUploadsTable = "blobuploads"
+
+func (s *SQLCommon) InsertBlobUpload(ctx context.Context, upload *core.BlobUpload) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	datatype := upload.Datatype
+	if datatype == nil {
+		datatype = &core.DatatypeRef{}
+	}
+	upload.Created = fftypes.Now()
+	upload.Updated = upload.Created
+	if _, err = s.InsertTx(ctx, blobUploadsTable, tx,
+		sq.Insert(blobUploadsTable).
+			Columns(blobUploadColumns...).
+			Values(
+				upload.ID,
+				upload.Namespace,
+				upload.Offset,
+				upload.Size,
+				upload.Hash,
+				upload.Filename,
+				upload.Mimetype,
+				upload.AutoMeta,
+				upload.Validator,
+				datatype.Name,
+				datatype.Version,
+				upload.Value,
+				upload.Created,
+				upload.Updated,
+			),
+		nil, // no change events for blob uploads
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) UpdateBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(blobUploadsTable), update, blobUploadFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	if _, err = s.UpdateTx(ctx, blobUploadsTable, tx, query, nil /* no change events for blob uploads */); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) blobUploadResult(ctx context.Context, row *sql.Rows) (*core.BlobUpload, error) {
+	upload := core.BlobUpload{
+		BlobUploadInput: core.BlobUploadInput{
+			Datatype: &core.DatatypeRef{},
+		},
+	}
+	err := row.Scan(
+		&upload.ID,
+		&upload.Namespace,
+		&upload.Offset,
+		&upload.Size,
+		&upload.Hash,
+		&upload.Filename,
+		&upload.Mimetype,
+		&upload.AutoMeta,
+		&upload.Validator,
+		&upload.Datatype.Name,
+		&upload.Datatype.Version,
+		&upload.Value,
+		&upload.Created,
+		&upload.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, blobUploadsTable)
+	}
+	if upload.Datatype.Name == "" && upload.Datatype.Version == "" {
+		upload.Datatype = nil
+	}
+	return &upload, nil
+}
+
+func (s *SQLCommon) GetBlobUploadByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BlobUpload, error) {
+	rows, _, err := s.Query(ctx, blobUploadsTable,
+		sq.Select(blobUploadColumns...).
+			From(blobUploadsTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Blob upload '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.blobUploadResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetBlobUploads(ctx context.Context, namespace string, filter ffapi.Filter) (uploads []*core.BlobUpload, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(blobUploadColumns...).From(blobUploadsTable),
+		filter, blobUploadFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, blobUploadsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	uploads = []*core.BlobUpload{}
+	for rows.Next() {
+		upload, err := s.blobUploadResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		uploads = append(uploads, upload)
+	}
+
+	return uploads, s.QueryRes(ctx, blobUploadsTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) DeleteBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, blobUploadsTable, tx, sq.Delete(blobUploadsTable).Where(sq.Eq{"id": id, "namespace": namespace}),
+		nil /* no change events for blob uploads */)
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,209 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlobUploadE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new blob upload entry
+	upload := &core.BlobUpload{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		BlobUploadInput: core.BlobUploadInput{
+			Size:     1024,
+			Hash:     fftypes.NewRandB32(),
+			Filename: "file.bin",
+			Mimetype: "application/octet-stream",
+			AutoMeta: true,
+			Datatype: &core.DatatypeRef{Name: "file", Version: "1.0"},
+			Value:    fftypes.JSONAnyPtr(`{"some":"metadata"}`),
+		},
+	}
+	err := s.InsertBlobUpload(ctx, upload)
+	assert.NoError(t, err)
+	assert.NotNil(t, upload.Created)
+	uploadJson, _ := json.Marshal(&upload)
+
+	// Query back the upload (by ID)
+	uploadRead, err := s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, uploadRead)
+	uploadReadJson, _ := json.Marshal(&uploadRead)
+	assert.Equal(t, string(uploadJson), string(uploadReadJson))
+
+	// Query back the upload (by query filter)
+	fb := database.BlobUploadQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("filename", "file.bin"),
+		fb.Eq("offset", 0),
+	)
+	uploads, res, err := s.GetBlobUploads(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(uploads))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	uploadReadJson, _ = json.Marshal(uploads[0])
+	assert.Equal(t, string(uploadJson), string(uploadReadJson))
+
+	// Update the offset
+	up := database.BlobUploadQueryFactory.NewUpdate(ctx).Set("offset", 512)
+	err = s.UpdateBlobUpload(ctx, "ns1", upload.ID, up)
+	assert.NoError(t, err)
+	uploadRead, err = s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(512), uploadRead.Offset)
+
+	// Other namespaces do not see the upload
+	uploadRead, err = s.GetBlobUploadByID(ctx, "ns2", upload.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, uploadRead)
+
+	// Delete the upload
+	err = s.DeleteBlobUpload(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	uploadRead, err = s.GetBlobUploadByID(ctx, "ns1", upload.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, uploadRead)
+}
+
+func TestInsertBlobUploadFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertBlobUpload(context.Background(), &core.BlobUpload{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertBlobUploadFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertBlobUpload(context.Background(), &core.BlobUpload{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertBlobUploadFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertBlobUpload(context.Background(), &core.BlobUpload{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateBlobUploadBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.BlobUploadQueryFactory.NewUpdate(context.Background()).Set("offset", 1)
+	err := s.UpdateBlobUpload(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateBlobUploadBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.BlobUploadQueryFactory.NewUpdate(context.Background()).Set("offset", map[bool]bool{true: false})
+	err := s.UpdateBlobUpload(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*offset", err)
+}
+
+func TestUpdateBlobUploadFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.BlobUploadQueryFactory.NewUpdate(context.Background()).Set("offset", 1)
+	err := s.UpdateBlobUpload(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+}
+
+func TestGetBlobUploadByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetBlobUploadByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlobUploadByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetBlobUploadByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlobUploadsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.BlobUploadQueryFactory.NewFilter(context.Background()).Eq("filename", "")
+	_, _, err := s.GetBlobUploads(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlobUploadsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.BlobUploadQueryFactory.NewFilter(context.Background()).Eq("filename", map[bool]bool{true: false})
+	_, _, err := s.GetBlobUploads(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*filename", err)
+}
+
+func TestGetBlobUploadsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.BlobUploadQueryFactory.NewFilter(context.Background()).Eq("filename", "")
+	_, _, err := s.GetBlobUploads(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlobUploadFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteBlobUpload(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlobUploadFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteBlobUpload(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
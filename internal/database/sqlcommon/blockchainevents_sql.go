@@ -45,6 +45,7 @@ var (
 		"tx_type",
 		"tx_id",
 		"tx_blockchain_id",
+		"invalidated",
 	}
 	blockchainEventFilterFieldMap = map[string]string{
 		"protocolid":      "protocol_id",
@@ -71,6 +72,7 @@ func (s *SQLCommon) setBlockchainEventInsertValues(query sq.InsertBuilder, event
 		event.TX.Type,
 		event.TX.ID,
 		event.TX.BlockchainID,
+		event.Invalidated,
 	)
 }
 
@@ -120,6 +122,7 @@ func (s *SQLCommon) blockchainEventResult(ctx context.Context, row *sql.Rows) (*
 		&event.TX.Type,
 		&event.TX.ID,
 		&event.TX.BlockchainID,
+		&event.Invalidated,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, blockchaineventsTable)
@@ -178,14 +181,68 @@ func (s *SQLCommon) GetBlockchainEvents(ctx context.Context, namespace string, f
 	}
 	defer rows.Close()
 
+	it := s.rowIterator(ctx)
 	events := []*core.BlockchainEvent{}
 	for rows.Next() {
 		event, err := s.blockchainEventResult(ctx, rows)
 		if err != nil {
 			return nil, nil, err
 		}
+		if it != nil {
+			if err := it(event); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
 		events = append(events, event)
 	}
 
 	return events, s.QueryRes(ctx, blockchaineventsTable, tx, fop, fi), err
 }
+
+func (s *SQLCommon) GetLatestBlockchainEvents(ctx context.Context, namespace string, listeners []*fftypes.UUID) ([]*core.BlockchainEvent, error) {
+	query := sq.Select(blockchainEventColumns...).
+		From(blockchaineventsTable).
+		Where(sq.Eq{"namespace": namespace, "listener_id": listeners, "invalidated": false}).
+		Where(sq.Expr("protocol_id = (SELECT MAX(l.protocol_id) FROM "+blockchaineventsTable+" AS l"+
+			" WHERE l.namespace = ? AND l.listener_id = "+blockchaineventsTable+".listener_id AND l.invalidated = ?)", namespace, false))
+
+	rows, _, err := s.Query(ctx, blockchaineventsTable, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*core.BlockchainEvent{}
+	for rows.Next() {
+		event, err := s.blockchainEventResult(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (s *SQLCommon) UpdateBlockchainEvent(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(blockchaineventsTable), update, blockchainEventFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	_, err = s.UpdateTx(ctx, blockchaineventsTable, tx, query, func() {
+		s.callbacks.UUIDCollectionNSEvent(database.CollectionBlockchainEvents, core.ChangeEventTypeUpdated, namespace, id)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
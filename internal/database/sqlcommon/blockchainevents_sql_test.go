@@ -27,6 +27,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestBlockchainEventsE2EWithDB(t *testing.T) {
@@ -115,6 +116,17 @@ func TestBlockchainEventsE2EWithDB(t *testing.T) {
 	existing, err = s.InsertOrGetBlockchainEvent(ctx, event4)
 	assert.NoError(t, err)
 	assert.Equal(t, event3.ID, existing.ID)
+
+	// Invalidate the event, and query it back
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBlockchainEvents, core.ChangeEventTypeUpdated, "ns", event.ID).Return().Once()
+	up := database.BlockchainEventQueryFactory.NewUpdate(ctx).Set("invalidated", true)
+	err = s.UpdateBlockchainEvent(ctx, "ns", event.ID, up)
+	assert.NoError(t, err)
+	events, _, err = s.GetBlockchainEvents(ctx, "ns", fb.Eq("invalidated", true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(events))
+	assert.Equal(t, event.ID, events[0].ID)
+	assert.True(t, events[0].Invalidated)
 }
 
 func TestInsertBlockchainEventFailBegin(t *testing.T) {
@@ -171,6 +183,60 @@ func TestGetBlockchainEventByIDScanFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetLatestBlockchainEventsWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBlockchainEvents, mock.Anything, mock.Anything, mock.Anything).Return()
+
+	l1, l2, l3 := fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()
+	insert := func(namespace string, listener *fftypes.UUID, protocolID string, invalidated bool) *core.BlockchainEvent {
+		event := &core.BlockchainEvent{
+			ID:          fftypes.NewUUID(),
+			Namespace:   namespace,
+			Listener:    listener,
+			ProtocolID:  protocolID,
+			Timestamp:   fftypes.Now(),
+			Invalidated: invalidated,
+		}
+		_, err := s.InsertOrGetBlockchainEvent(ctx, event)
+		assert.NoError(t, err)
+		return event
+	}
+	latest1 := insert("ns", l1, "000000000012/000000/000000", false)
+	insert("ns", l1, "000000000010/000000/000000", false)
+	insert("ns", l1, "000000000013/000000/000000", true)
+	insert("ns2", l1, "000000000020/000000/000000", false)
+	latest2 := insert("ns", l2, "000000000005/000000/000000", false)
+	insert("ns", l3, "000000000007/000000/000000", false)
+
+	events, err := s.GetLatestBlockchainEvents(ctx, "ns", []*fftypes.UUID{l1, l2, fftypes.NewUUID()})
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	latest := map[fftypes.UUID]*fftypes.UUID{}
+	for _, event := range events {
+		latest[*event.Listener] = event.ID
+	}
+	assert.Equal(t, latest1.ID, latest[*l1])
+	assert.Equal(t, latest2.ID, latest[*l2])
+}
+
+func TestGetLatestBlockchainEventsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetLatestBlockchainEvents(context.Background(), "ns", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLatestBlockchainEventsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetLatestBlockchainEvents(context.Background(), "ns", []*fftypes.UUID{fftypes.NewUUID()})
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetBlockchainEventsQueryFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
@@ -195,3 +261,29 @@ func TestGetBlockchainEventsScanFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpdateBlockchainEventBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.BlockchainEventQueryFactory.NewUpdate(context.Background()).Set("invalidated", true)
+	err := s.UpdateBlockchainEvent(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateBlockchainEventBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.BlockchainEventQueryFactory.NewUpdate(context.Background()).Set("id", map[bool]bool{true: false})
+	err := s.UpdateBlockchainEvent(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestUpdateBlockchainEventFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.BlockchainEventQueryFactory.NewUpdate(context.Background()).Set("invalidated", true)
+	err := s.UpdateBlockchainEvent(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+}
@@ -313,12 +313,19 @@ func (s *SQLCommon) GetData(ctx context.Context, namespace string, filter ffapi.
 	}
 	defer rows.Close()
 
+	it := s.rowIterator(ctx)
 	data := core.DataArray{}
 	for rows.Next() {
 		d, err := s.dataResult(ctx, rows, true)
 		if err != nil {
 			return nil, nil, err
 		}
+		if it != nil {
+			if err := it(d); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
 		data = append(data, d)
 	}
 
@@ -42,6 +42,7 @@ var (
 		"hash",
 		"created",
 		"value",
+		"compatibility",
 	}
 	datatypeFilterFieldMap = map[string]string{
 		"message": "message_id",
@@ -87,6 +88,7 @@ func (s *SQLCommon) UpsertDatatype(ctx context.Context, datatype *core.Datatype,
 				Set("hash", datatype.Hash).
 				Set("created", datatype.Created).
 				Set("value", datatype.Value).
+				Set("compatibility", datatype.Compatibility).
 				Where(sq.Eq{"id": datatype.ID}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionDataTypes, core.ChangeEventTypeUpdated, datatype.Namespace, datatype.ID)
@@ -108,6 +110,7 @@ func (s *SQLCommon) UpsertDatatype(ctx context.Context, datatype *core.Datatype,
 					datatype.Hash,
 					datatype.Created,
 					datatype.Value,
+					datatype.Compatibility,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionDataTypes, core.ChangeEventTypeCreated, datatype.Namespace, datatype.ID)
@@ -132,6 +135,7 @@ func (s *SQLCommon) datatypeResult(ctx context.Context, row *sql.Rows) (*core.Da
 		&datatype.Hash,
 		&datatype.Created,
 		&datatype.Value,
+		&datatype.Compatibility,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, datatypesTable)
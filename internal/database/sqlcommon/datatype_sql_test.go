@@ -48,13 +48,14 @@ func TestDatatypeE2EWithDB(t *testing.T) {
 		},
 	}
 	datatype := &core.Datatype{
-		ID:        datatypeID,
-		Message:   fftypes.NewUUID(),
-		Validator: core.ValidatorTypeJSON,
-		Namespace: "ns1",
-		Hash:      randB32,
-		Created:   fftypes.Now(),
-		Value:     fftypes.JSONAnyPtr(val.String()),
+		ID:            datatypeID,
+		Message:       fftypes.NewUUID(),
+		Validator:     core.ValidatorTypeJSON,
+		Namespace:     "ns1",
+		Hash:          randB32,
+		Created:       fftypes.Now(),
+		Value:         fftypes.JSONAnyPtr(val.String()),
+		Compatibility: core.DatatypeCompatibilityBackward,
 	}
 
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDataTypes, core.ChangeEventTypeCreated, "ns1", datatypeID, mock.Anything).Return()
@@ -0,0 +1,204 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	deadLetterColumns = []string{
+		"id",
+		"namespace",
+		"sub_id",
+		"sub_name",
+		"event_id",
+		"event_type",
+		"reference",
+		"attempts",
+		"status",
+		"error",
+		"history",
+		"original_event",
+		"state",
+		"created",
+		"updated",
+	}
+	deadLetterFilterFieldMap = map[string]string{
+		"subscription.id":   "sub_id",
+		"subscription.name": "sub_name",
+		"event":             "event_id",
+		"eventtype":         "event_type",
+		"originalevent":     "original_event",
+	}
+)
+
+const deadletterTable = "deadletter"
+
+func (s *SQLCommon) InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if deadLetter.Created == nil {
+		deadLetter.Created = fftypes.Now()
+	}
+	if deadLetter.State == "" {
+		deadLetter.State = core.DeadLetterStatePending
+	}
+	if _, err = s.InsertTx(ctx, deadletterTable, tx,
+		sq.Insert(deadletterTable).
+			Columns(deadLetterColumns...).
+			Values(
+				deadLetter.ID,
+				deadLetter.Namespace,
+				deadLetter.Subscription.ID,
+				deadLetter.Subscription.Name,
+				deadLetter.Event,
+				deadLetter.EventType,
+				deadLetter.Reference,
+				deadLetter.Attempts,
+				deadLetter.Status,
+				deadLetter.Error,
+				deadLetter.History,
+				deadLetter.OriginalEvent,
+				deadLetter.State,
+				deadLetter.Created,
+				deadLetter.Updated,
+			),
+		nil, // no change events for dead letters
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) deadLetterResult(ctx context.Context, row *sql.Rows) (*core.DeadLetter, error) {
+	deadLetter := core.DeadLetter{}
+	var status sql.NullInt64
+	var errorMsg, state sql.NullString
+	err := row.Scan(
+		&deadLetter.ID,
+		&deadLetter.Namespace,
+		&deadLetter.Subscription.ID,
+		&deadLetter.Subscription.Name,
+		&deadLetter.Event,
+		&deadLetter.EventType,
+		&deadLetter.Reference,
+		&deadLetter.Attempts,
+		&status,
+		&errorMsg,
+		&deadLetter.History,
+		&deadLetter.OriginalEvent,
+		&state,
+		&deadLetter.Created,
+		&deadLetter.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, deadletterTable)
+	}
+	deadLetter.Subscription.Namespace = deadLetter.Namespace
+	deadLetter.Status = int(status.Int64)
+	deadLetter.Error = errorMsg.String
+	deadLetter.State = core.DeadLetterState(state.String)
+	return &deadLetter, nil
+}
+
+func (s *SQLCommon) GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DeadLetter, error) {
+	rows, _, err := s.Query(ctx, deadletterTable,
+		sq.Select(deadLetterColumns...).
+			From(deadletterTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Dead letter '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.deadLetterResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) (deadLetters []*core.DeadLetter, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(deadLetterColumns...).From(deadletterTable),
+		filter, deadLetterFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, deadletterTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	deadLetters = []*core.DeadLetter{}
+	for rows.Next() {
+		d, err := s.deadLetterResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		deadLetters = append(deadLetters, d)
+	}
+
+	return deadLetters, s.QueryRes(ctx, deadletterTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(deadletterTable), update, deadLetterFilterFieldMap)
+	if err != nil {
+		return false, err
+	}
+
+	if filter != nil {
+		query, err = s.FilterUpdate(ctx, query, filter, deadLetterFilterFieldMap)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"namespace": namespace, "id": id})
+
+	ra, err := s.UpdateTx(ctx, deadletterTable, tx, query, nil /* no change events for dead letters */)
+	if err != nil {
+		return false, err
+	}
+	return ra > 0, s.CommitTx(ctx, tx, autoCommit)
+}
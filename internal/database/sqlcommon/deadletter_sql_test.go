@@ -0,0 +1,203 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	deadLetter := &core.DeadLetter{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Subscription: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Event:     fftypes.NewUUID(),
+		EventType: core.EventTypeMessageConfirmed,
+		Reference: fftypes.NewUUID(),
+		Attempts:  3,
+		Status:    503,
+		Error:     "FF10546: Webhook returned status 503",
+		History: core.DeadLetterAttempts{
+			{Time: fftypes.Now(), Status: 503, Error: "FF10546: Webhook returned status 503"},
+		},
+		OriginalEvent: fftypes.JSONAnyPtr(`{"id":"event1"}`),
+	}
+	err := s.InsertDeadLetter(ctx, deadLetter)
+	assert.NoError(t, err)
+	assert.NotNil(t, deadLetter.Created)
+	assert.Equal(t, core.DeadLetterStatePending, deadLetter.State)
+	deadLetterJson, _ := json.Marshal(&deadLetter)
+
+	// Query back the dead letter (by ID)
+	deadLetterRead, err := s.GetDeadLetterByID(ctx, "ns1", deadLetter.ID)
+	assert.NoError(t, err)
+	deadLetterReadJson, _ := json.Marshal(&deadLetterRead)
+	assert.Equal(t, string(deadLetterJson), string(deadLetterReadJson))
+
+	// Query back the dead letter (by query filter)
+	fb := database.DeadLetterQueryFactory.NewFilter(ctx)
+	deadLetters, res, err := s.GetDeadLetters(ctx, "ns1", fb.And(
+		fb.Eq("subscription.id", deadLetter.Subscription.ID),
+		fb.Eq("eventtype", core.EventTypeMessageConfirmed),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(deadLetters))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	deadLetterReadJson, _ = json.Marshal(deadLetters[0])
+	assert.Equal(t, string(deadLetterJson), string(deadLetterReadJson))
+
+	// Update the state, only if it is still pending
+	f := database.DeadLetterQueryFactory.NewFilter(ctx).Eq("state", core.DeadLetterStatePending)
+	u := database.DeadLetterQueryFactory.NewUpdate(ctx).Set("state", core.DeadLetterStateRequeued)
+	updated, err := s.UpdateDeadLetter(ctx, "ns1", deadLetter.ID, f, u)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	deadLetterRead, err = s.GetDeadLetterByID(ctx, "ns1", deadLetter.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DeadLetterStateRequeued, deadLetterRead.State)
+	assert.NotNil(t, deadLetterRead.Updated)
+	updated, err = s.UpdateDeadLetter(ctx, "ns1", deadLetter.ID, f, u)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	// Not found
+	deadLetterRead, err = s.GetDeadLetterByID(ctx, "ns2", deadLetter.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, deadLetterRead)
+}
+
+func TestInsertDeadLetterFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDeadLetterFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDeadLetterFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDeadLetterByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetDeadLetterByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLettersQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("event", fftypes.NewUUID())
+	_, _, err := s.GetDeadLetters(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLettersBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("error", map[bool]bool{true: false})
+	_, _, err := s.GetDeadLetters(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*error", err)
+}
+
+func TestGetDeadLettersScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("error", "")
+	_, _, err := s.GetDeadLetters(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateDeadLetterFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.DeadLetterQueryFactory.NewUpdate(context.Background()).Set("state", core.DeadLetterStateDiscarded)
+	_, err := s.UpdateDeadLetter(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateDeadLetterBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.DeadLetterQueryFactory.NewUpdate(context.Background()).Set("state", map[bool]bool{true: false})
+	_, err := s.UpdateDeadLetter(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestUpdateDeadLetterFilterFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	u := database.DeadLetterQueryFactory.NewUpdate(context.Background()).Set("state", core.DeadLetterStateDiscarded)
+	_, err := s.UpdateDeadLetter(context.Background(), "ns1", fftypes.NewUUID(), f, u)
+	assert.Regexp(t, "FF00143", err)
+}
+
+func TestUpdateDeadLetterFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.DeadLetterQueryFactory.NewUpdate(context.Background()).Set("state", core.DeadLetterStateDiscarded)
+	_, err := s.UpdateDeadLetter(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00178", err)
+}
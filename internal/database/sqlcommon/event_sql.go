@@ -213,12 +213,19 @@ func (s *SQLCommon) GetEvents(ctx context.Context, namespace string, filter ffap
 	}
 	defer rows.Close()
 
+	it := s.rowIterator(ctx)
 	events := []*core.Event{}
 	for rows.Next() {
 		event, err := s.eventResult(ctx, rows)
 		if err != nil {
 			return nil, nil, err
 		}
+		if it != nil {
+			if err := it(event); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
 		events = append(events, event)
 	}
 
@@ -0,0 +1,168 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	eventRuleColumns = []string{
+		"id",
+		"namespace",
+		"name",
+		"action",
+		"match_conditions",
+		"targets",
+		"labels",
+		"created",
+	}
+	eventRuleFilterFieldMap = map[string]string{
+		"match": "match_conditions",
+	}
+)
+
+const eventRulesTable = "eventrules"
+
+func (s *SQLCommon) InsertEventRule(ctx context.Context, rule *core.EventRule) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if rule.Created == nil {
+		rule.Created = fftypes.Now()
+	}
+	if _, err = s.InsertTx(ctx, eventRulesTable, tx,
+		sq.Insert(eventRulesTable).
+			Columns(eventRuleColumns...).
+			Values(
+				rule.ID,
+				rule.Namespace,
+				rule.Name,
+				rule.Action,
+				rule.Match,
+				rule.Targets,
+				rule.Labels,
+				rule.Created,
+			),
+		nil, // no change events for event rules
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) eventRuleResult(ctx context.Context, row *sql.Rows) (*core.EventRule, error) {
+	rule := core.EventRule{}
+	err := row.Scan(
+		&rule.ID,
+		&rule.Namespace,
+		&rule.Name,
+		&rule.Action,
+		&rule.Match,
+		&rule.Targets,
+		&rule.Labels,
+		&rule.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, eventRulesTable)
+	}
+	return &rule, nil
+}
+
+func (s *SQLCommon) getEventRuleEq(ctx context.Context, eq sq.Eq, textName string) (*core.EventRule, error) {
+	rows, _, err := s.Query(ctx, eventRulesTable,
+		sq.Select(eventRuleColumns...).
+			From(eventRulesTable).
+			Where(eq),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Event rule '%s' not found", textName)
+		return nil, nil
+	}
+
+	return s.eventRuleResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetEventRuleByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.EventRule, error) {
+	return s.getEventRuleEq(ctx, sq.Eq{"namespace": namespace, "id": id}, id.String())
+}
+
+func (s *SQLCommon) GetEventRuleByName(ctx context.Context, namespace, name string) (*core.EventRule, error) {
+	return s.getEventRuleEq(ctx, sq.Eq{"namespace": namespace, "name": name}, fmt.Sprintf("%s:%s", namespace, name))
+}
+
+func (s *SQLCommon) GetEventRules(ctx context.Context, namespace string, filter ffapi.Filter) (rules []*core.EventRule, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(eventRuleColumns...).From(eventRulesTable),
+		filter, eventRuleFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, eventRulesTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	rules = []*core.EventRule{}
+	for rows.Next() {
+		r, err := s.eventRuleResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, s.QueryRes(ctx, eventRulesTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) DeleteEventRuleByID(ctx context.Context, namespace string, id *fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, eventRulesTable, tx, sq.Delete(eventRulesTable).Where(sq.Eq{
+		"namespace": namespace,
+		"id":        id,
+	}), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
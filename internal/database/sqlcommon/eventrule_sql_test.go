@@ -0,0 +1,182 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventRuleE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	rule := &core.EventRule{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Name:      "orders",
+		Action:    core.EventRuleActionRoute,
+		Match: core.EventRuleMatch{
+			Events: "message_confirmed",
+			Topic:  "^orders$",
+		},
+		Targets: core.EventRuleTargets{
+			Subscriptions: []string{"fulfilment"},
+		},
+		Labels: fftypes.JSONObject{"team": "fulfilment"},
+	}
+	err := s.InsertEventRule(ctx, rule)
+	assert.NoError(t, err)
+	assert.NotNil(t, rule.Created)
+	ruleJson, _ := json.Marshal(&rule)
+
+	// Query back the rule (by ID)
+	ruleRead, err := s.GetEventRuleByID(ctx, "ns1", rule.ID)
+	assert.NoError(t, err)
+	ruleReadJson, _ := json.Marshal(&ruleRead)
+	assert.Equal(t, string(ruleJson), string(ruleReadJson))
+
+	// Query back the rule (by name)
+	ruleRead, err = s.GetEventRuleByName(ctx, "ns1", "orders")
+	assert.NoError(t, err)
+	ruleReadJson, _ = json.Marshal(&ruleRead)
+	assert.Equal(t, string(ruleJson), string(ruleReadJson))
+
+	// Query back the rule (by query filter)
+	fb := database.EventRuleQueryFactory.NewFilter(ctx)
+	rules, res, err := s.GetEventRules(ctx, "ns1", fb.And(
+		fb.Eq("action", core.EventRuleActionRoute),
+		fb.Contains("match", "orders"),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(rules))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	ruleReadJson, _ = json.Marshal(rules[0])
+	assert.Equal(t, string(ruleJson), string(ruleReadJson))
+
+	// Delete the rule
+	err = s.DeleteEventRuleByID(ctx, "ns1", rule.ID)
+	assert.NoError(t, err)
+	ruleRead, err = s.GetEventRuleByID(ctx, "ns1", rule.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, ruleRead)
+}
+
+func TestInsertEventRuleFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertEventRule(context.Background(), &core.EventRule{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertEventRuleFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertEventRule(context.Background(), &core.EventRule{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertEventRuleFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertEventRule(context.Background(), &core.EventRule{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventRuleByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetEventRuleByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventRuleByNameNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	rule, err := s.GetEventRuleByName(context.Background(), "ns1", "rule1")
+	assert.NoError(t, err)
+	assert.Nil(t, rule)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventRuleByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetEventRuleByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventRulesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.EventRuleQueryFactory.NewFilter(context.Background()).Eq("name", "rule1")
+	_, _, err := s.GetEventRules(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventRulesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.EventRuleQueryFactory.NewFilter(context.Background()).Eq("name", map[bool]bool{true: false})
+	_, _, err := s.GetEventRules(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*name", err)
+}
+
+func TestGetEventRulesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.EventRuleQueryFactory.NewFilter(context.Background()).Eq("name", "")
+	_, _, err := s.GetEventRules(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteEventRuleFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteEventRuleByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteEventRuleFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteEventRuleByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -39,6 +39,10 @@ var (
 		"name",
 		"hash",
 		"created",
+		"previous",
+		"generation",
+		"successor",
+		"superseded",
 	}
 	groupFilterFieldMap = map[string]string{
 		"message": "message_id",
@@ -102,6 +106,31 @@ func (s *SQLCommon) UpsertGroup(ctx context.Context, group *core.Group, optimiza
 	return s.CommitTx(ctx, tx, autoCommit)
 }
 
+// UpdateGroup updates the local state of a group. The identity fields of a group cannot be updated, as they are part of its hash.
+func (s *SQLCommon) UpdateGroup(ctx context.Context, namespace string, hash *fftypes.Bytes32, update ffapi.Update) (err error) {
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(groupsTable), update, groupFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Where(sq.Eq{"hash": hash, "namespace_local": namespace})
+
+	_, err = s.UpdateTx(ctx, groupsTable, tx, query, func() {
+		s.callbacks.HashCollectionNSEvent(database.CollectionGroups, core.ChangeEventTypeUpdated, namespace, hash)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
 func (s *SQLCommon) attemptGroupUpdate(ctx context.Context, tx *dbsql.TXWrapper, group *core.Group) (int64, error) {
 	// Update the group
 	return s.UpdateTx(ctx, groupsTable, tx,
@@ -128,6 +157,10 @@ func (s *SQLCommon) attemptGroupInsert(ctx context.Context, tx *dbsql.TXWrapper,
 				group.Name,
 				group.Hash,
 				group.Created,
+				group.Previous,
+				group.Generation,
+				group.Successor,
+				group.Superseded,
 			),
 		func() {
 			s.callbacks.HashCollectionNSEvent(database.CollectionGroups, core.ChangeEventTypeCreated, group.LocalNamespace, group.Hash)
@@ -240,6 +273,10 @@ func (s *SQLCommon) groupResult(ctx context.Context, row *sql.Rows) (*core.Group
 		&group.Name,
 		&group.Hash,
 		&group.Created,
+		&group.Previous,
+		&group.Generation,
+		&group.Successor,
+		&group.Superseded,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, groupsTable)
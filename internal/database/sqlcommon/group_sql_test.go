@@ -91,6 +91,21 @@ func TestUpsertGroupE2EWithDB(t *testing.T) {
 	groupReadJson, _ = json.Marshal(&groupRead)
 	assert.Equal(t, string(groupJson), string(groupReadJson))
 
+	// Update the local state of the group
+	successor := fftypes.NewRandB32()
+	superseded := fftypes.Now()
+	err = s.UpdateGroup(ctx, "ns1", group.Hash, database.GroupQueryFactory.NewUpdate(ctx).
+		Set("successor", successor).
+		Set("superseded", superseded))
+	assert.NoError(t, err)
+	groupUpdated.Successor = successor
+	groupUpdated.Superseded = superseded
+	groupRead, err = s.GetGroupByHash(ctx, "ns1", group.Hash)
+	assert.NoError(t, err)
+	groupJson, _ = json.Marshal(&groupUpdated)
+	groupReadJson, _ = json.Marshal(&groupRead)
+	assert.Equal(t, string(groupJson), string(groupReadJson))
+
 	// Query back the group
 	fb := database.GroupQueryFactory.NewFilter(ctx)
 	filter := fb.And(
@@ -104,6 +119,29 @@ func TestUpsertGroupE2EWithDB(t *testing.T) {
 	groupReadJson, _ = json.Marshal(groups[0])
 	assert.Equal(t, string(groupJson), string(groupReadJson))
 
+	// Create the next generation of the group
+	nextGroup := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Name:       "group1",
+			Namespace:  "ns1",
+			Members:    group.Members[0:1],
+			Previous:   groupHash,
+			Generation: 1,
+		},
+		LocalNamespace: "ns1",
+		Hash:           successor,
+		Created:        fftypes.Now(),
+	}
+	s.callbacks.On("HashCollectionNSEvent", database.CollectionGroups, core.ChangeEventTypeCreated, "ns1", successor, mock.Anything).Return()
+	err = s.UpsertGroup(ctx, nextGroup, database.UpsertOptimizationNew)
+	assert.NoError(t, err)
+	groups, _, err = s.GetGroups(ctx, "ns1", fb.And(fb.Eq("previous", groupHash), fb.Eq("generation", 1)))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(groups))
+	groupJson, _ = json.Marshal(&nextGroup)
+	groupReadJson, _ = json.Marshal(groups[0])
+	assert.Equal(t, string(groupJson), string(groupReadJson))
+
 	// Negative test on filter
 	filter = fb.And(
 		fb.Eq("hash", groupUpdated.Hash.String()),
@@ -332,7 +370,7 @@ func TestGetGroupByIDLoadMembersFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	groupID := fftypes.NewRandB32()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(groupColumns).
-		AddRow(nil, "ns1", "ns1", "name1", fftypes.NewRandB32(), fftypes.Now()))
+		AddRow(nil, "ns1", "ns1", "name1", fftypes.NewRandB32(), fftypes.Now(), nil, 0, nil, nil))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	_, err := s.GetGroupByHash(context.Background(), "ns1", groupID)
 	assert.Regexp(t, "FF00176", err)
@@ -367,10 +405,36 @@ func TestGetGroupsReadGroupFail(t *testing.T) {
 func TestGetGroupsLoadMembersFail(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(groupColumns).
-		AddRow(nil, "ns1", "ns1", "group1", fftypes.NewRandB32(), fftypes.Now()))
+		AddRow(nil, "ns1", "ns1", "group1", fftypes.NewRandB32(), fftypes.Now(), nil, 0, nil, nil))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	f := database.GroupQueryFactory.NewFilter(context.Background()).Gt("created", "0")
 	_, _, err := s.GetGroups(context.Background(), "ns1", f)
 	assert.Regexp(t, "FF00176", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpdateGroupFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.GroupQueryFactory.NewUpdate(context.Background()).Set("superseded", fftypes.Now())
+	err := s.UpdateGroup(context.Background(), "ns1", fftypes.NewRandB32(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateGroupBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.GroupQueryFactory.NewUpdate(context.Background()).Set("successor", map[bool]bool{true: false})
+	err := s.UpdateGroup(context.Background(), "ns1", fftypes.NewRandB32(), u)
+	assert.Regexp(t, "FF00143.*successor", err)
+}
+
+func TestUpdateGroupFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.GroupQueryFactory.NewUpdate(context.Background()).Set("superseded", fftypes.Now())
+	err := s.UpdateGroup(context.Background(), "ns1", fftypes.NewRandB32(), u)
+	assert.Regexp(t, "FF00178", err)
+}
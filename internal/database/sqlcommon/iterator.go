@@ -0,0 +1,34 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// rowIterator returns the iterator requested on the context for a collection query, or nil if the results
+// should be returned in a list. The connection is held until every row has been passed to the iterator,
+// so when the database is limited to a single connection the rows are always returned in the list.
+func (s *SQLCommon) rowIterator(ctx context.Context) database.RowIterator {
+	it := database.Iterator(ctx)
+	if it == nil || s.DB().Stats().MaxOpenConnections == 1 {
+		return nil
+	}
+	return it
+}
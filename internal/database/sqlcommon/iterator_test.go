@@ -0,0 +1,163 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func collectRows(ctx context.Context, rows *[]interface{}) context.Context {
+	return database.WithIterator(ctx, func(row interface{}) error {
+		*rows = append(*rows, row)
+		return nil
+	})
+}
+
+func newTestMsgRows(s *mockProvider, ids []*fftypes.UUID) *sqlmock.Rows {
+	cols := append([]string{}, msgColumns...)
+	rows := sqlmock.NewRows(append(cols, s.SequenceColumn()))
+	for i, id := range ids {
+		rows.AddRow(id.String(), nil, "broadcast", "did:firefly:org/org1", "0x12345", nil, "ns1", "ns1", "topic1", "", nil, nil, nil, "", "confirmed", nil, "", "", nil, "", nil, nil, "", nil, "", nil, "", nil, int64(0), int64(i+1))
+	}
+	return rows
+}
+
+func TestGetEventsIterator(t *testing.T) {
+	s, mock := newMockProvider().init()
+	eventID := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT .* FROM events").WillReturnRows(sqlmock.NewRows(append(append([]string{}, eventColumns...), s.SequenceColumn())).
+		AddRow(eventID.String(), "message_confirmed", "ns1", nil, nil, nil, "topic1", nil, int64(1)))
+	var rows []interface{}
+	ctx := collectRows(context.Background(), &rows)
+	f := database.EventQueryFactory.NewFilter(ctx).Gt("sequence", 0)
+	events, _, err := s.GetEvents(ctx, "ns1", f)
+	assert.NoError(t, err)
+	assert.Empty(t, events)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, eventID, rows[0].(*core.Event).ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEventsIteratorFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .* FROM events").WillReturnRows(sqlmock.NewRows(append(append([]string{}, eventColumns...), s.SequenceColumn())).
+		AddRow(fftypes.NewUUID().String(), "message_confirmed", "ns1", nil, nil, nil, "topic1", nil, int64(1)).
+		AddRow(fftypes.NewUUID().String(), "message_confirmed", "ns1", nil, nil, nil, "topic1", nil, int64(2)))
+	calls := 0
+	ctx := database.WithIterator(context.Background(), func(row interface{}) error {
+		calls++
+		return fmt.Errorf("pop")
+	})
+	f := database.EventQueryFactory.NewFilter(ctx).Gt("sequence", 0)
+	_, _, err := s.GetEvents(ctx, "ns1", f)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, 1, calls)
+}
+
+func TestGetEventsIteratorSingleConnection(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	event := &core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed, Namespace: "ns1", Created: fftypes.Now()}
+	err := s.InsertEvent(context.Background(), event)
+	assert.NoError(t, err)
+
+	var rows []interface{}
+	ctx := collectRows(context.Background(), &rows)
+	f := database.EventQueryFactory.NewFilter(ctx).Gt("sequence", 0)
+	events, _, err := s.GetEvents(ctx, "ns1", f)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Empty(t, rows)
+}
+
+func TestGetMessagesIterator(t *testing.T) {
+	s, mock := newMockProvider().init()
+	ids := make([]*fftypes.UUID, msgIteratorBatchSize+1)
+	for i := range ids {
+		ids[i] = fftypes.NewUUID()
+	}
+	dataID := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT .* FROM messages").WillReturnRows(newTestMsgRows(s, ids))
+	mock.ExpectQuery("SELECT .* FROM messages_data").WillReturnRows(sqlmock.NewRows([]string{"message_id", "data_id", "data_hash", "data_idx"}).
+		AddRow(ids[0].String(), dataID.String(), fftypes.NewRandB32().String(), 0))
+	mock.ExpectQuery("SELECT .* FROM messages_data").WillReturnRows(sqlmock.NewRows([]string{"message_id", "data_id", "data_hash", "data_idx"}))
+	var rows []interface{}
+	ctx := collectRows(context.Background(), &rows)
+	f := database.MessageQueryFactory.NewFilter(ctx).Gt("sequence", 0)
+	msgs, _, err := s.GetMessages(ctx, "ns1", f)
+	assert.NoError(t, err)
+	assert.Empty(t, msgs)
+	assert.Len(t, rows, len(ids))
+	assert.Equal(t, dataID, rows[0].(*core.Message).Data[0].ID)
+	assert.Equal(t, ids[msgIteratorBatchSize], rows[msgIteratorBatchSize].(*core.Message).Header.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessagesIteratorBatchFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	ids := make([]*fftypes.UUID, msgIteratorBatchSize)
+	for i := range ids {
+		ids[i] = fftypes.NewUUID()
+	}
+	mock.ExpectQuery("SELECT .* FROM messages").WillReturnRows(newTestMsgRows(s, ids))
+	mock.ExpectQuery("SELECT .* FROM messages_data").WillReturnError(fmt.Errorf("pop"))
+	var rows []interface{}
+	ctx := collectRows(context.Background(), &rows)
+	f := database.MessageQueryFactory.NewFilter(ctx).Gt("sequence", 0)
+	_, _, err := s.GetMessages(ctx, "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.Empty(t, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessagesIteratorFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT id, state, seq FROM messages").WillReturnRows(sqlmock.NewRows([]string{"id", "state", "seq"}).
+		AddRow(fftypes.NewUUID().String(), "confirmed", 12345))
+	ctx := database.WithIterator(context.Background(), func(row interface{}) error {
+		return fmt.Errorf("pop")
+	})
+	f := database.MessageQueryFactory.NewFilter(ctx).Gt("sequence", 0)
+	_, _, err := s.GetMessages(ctx, "ns1", f, "header.id", "state")
+	assert.EqualError(t, err, "pop")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOperationsIterator(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT id, opstatus FROM operations").WillReturnRows(sqlmock.NewRows([]string{"id", "opstatus"}).
+		AddRow(fftypes.NewUUID().String(), "Succeeded"))
+	var rows []interface{}
+	ctx := collectRows(context.Background(), &rows)
+	ops, _, err := s.GetOperations(ctx, "ns1", database.OperationQueryFactory.NewFilter(ctx).Gt("created", 0), "id", "status")
+	assert.NoError(t, err)
+	assert.Empty(t, ops)
+	assert.Len(t, rows, 1)
+	assert.Equal(t, core.OpStatusSucceeded, rows[0].(*core.Operation).Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,172 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	jobColumns = []string{
+		"id",
+		"namespace",
+		"jtype",
+		"status",
+		"input",
+		"output",
+		"progress_done",
+		"progress_total",
+		"error",
+		"created",
+		"updated",
+	}
+	jobFilterFieldMap = map[string]string{
+		"type":           "jtype",
+		"progress.done":  "progress_done",
+		"progress.total": "progress_total",
+	}
+)
+
+const jobsTable = "jobs"
+
+func (s *SQLCommon) InsertJob(ctx context.Context, job *core.Job) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	job.Created = fftypes.Now()
+	job.Updated = job.Created
+	if _, err = s.InsertTx(ctx, jobsTable, tx,
+		sq.Insert(jobsTable).
+			Columns(jobColumns...).
+			Values(
+				job.ID,
+				job.Namespace,
+				job.Type,
+				job.Status,
+				job.Input,
+				job.Output,
+				job.Progress.Done,
+				job.Progress.Total,
+				job.Error,
+				job.Created,
+				job.Updated,
+			),
+		nil, // no change events for jobs
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) UpdateJob(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(jobsTable), update, jobFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	if _, err = s.UpdateTx(ctx, jobsTable, tx, query, nil /* no change events for jobs */); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) jobResult(ctx context.Context, row *sql.Rows) (*core.Job, error) {
+	job := core.Job{}
+	err := row.Scan(
+		&job.ID,
+		&job.Namespace,
+		&job.Type,
+		&job.Status,
+		&job.Input,
+		&job.Output,
+		&job.Progress.Done,
+		&job.Progress.Total,
+		&job.Error,
+		&job.Created,
+		&job.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, jobsTable)
+	}
+	return &job, nil
+}
+
+func (s *SQLCommon) GetJobByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Job, error) {
+	rows, _, err := s.Query(ctx, jobsTable,
+		sq.Select(jobColumns...).
+			From(jobsTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Job '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.jobResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetJobs(ctx context.Context, namespace string, filter ffapi.Filter) (jobs []*core.Job, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(jobColumns...).From(jobsTable),
+		filter, jobFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, jobsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	jobs = []*core.Job{}
+	for rows.Next() {
+		job, err := s.jobResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, s.QueryRes(ctx, jobsTable, tx, fop, fi), err
+}
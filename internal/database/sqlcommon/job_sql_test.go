@@ -0,0 +1,185 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new job entry
+	job := &core.Job{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      core.JobTypeTokenTransfersExport,
+		Status:    core.JobStatusPending,
+		Input:     fftypes.JSONObject{"format": "csv"},
+	}
+	err := s.InsertJob(ctx, job)
+	assert.NoError(t, err)
+	assert.NotNil(t, job.Created)
+	jobJson, _ := json.Marshal(&job)
+
+	// Query back the job (by ID)
+	jobRead, err := s.GetJobByID(ctx, "ns1", job.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, jobRead)
+	jobReadJson, _ := json.Marshal(&jobRead)
+	assert.Equal(t, string(jobJson), string(jobReadJson))
+
+	// Query back the job (by query filter)
+	fb := database.JobQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("type", core.JobTypeTokenTransfersExport),
+		fb.Eq("status", core.JobStatusPending),
+	)
+	jobs, res, err := s.GetJobs(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(jobs))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	jobReadJson, _ = json.Marshal(jobs[0])
+	assert.Equal(t, string(jobJson), string(jobReadJson))
+
+	// Update the progress and output
+	up := database.JobQueryFactory.NewUpdate(ctx).
+		Set("status", core.JobStatusSucceeded).
+		Set("progress.done", 100).
+		Set("progress.total", 100).
+		Set("output", fftypes.JSONObject{"file": "transfers.csv"})
+	err = s.UpdateJob(ctx, "ns1", job.ID, up)
+	assert.NoError(t, err)
+	jobRead, err = s.GetJobByID(ctx, "ns1", job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.JobStatusSucceeded, jobRead.Status)
+	assert.Equal(t, int64(100), jobRead.Progress.Done)
+	assert.Equal(t, int64(100), jobRead.Progress.Total)
+	assert.Equal(t, "transfers.csv", jobRead.Output.GetString("file"))
+
+	// Other namespaces do not see the job
+	jobRead, err = s.GetJobByID(ctx, "ns2", job.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, jobRead)
+}
+
+func TestInsertJobFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertJob(context.Background(), &core.Job{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertJobFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertJob(context.Background(), &core.Job{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertJobFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertJob(context.Background(), &core.Job{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateJobBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.JobQueryFactory.NewUpdate(context.Background()).Set("status", core.JobStatusRunning)
+	err := s.UpdateJob(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateJobBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.JobQueryFactory.NewUpdate(context.Background()).Set("status", map[bool]bool{true: false})
+	err := s.UpdateJob(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*status", err)
+}
+
+func TestUpdateJobFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.JobQueryFactory.NewUpdate(context.Background()).Set("status", core.JobStatusRunning)
+	err := s.UpdateJob(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+}
+
+func TestGetJobByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetJobByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJobByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetJobByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJobsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.JobQueryFactory.NewFilter(context.Background()).Eq("status", "")
+	_, _, err := s.GetJobs(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetJobsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.JobQueryFactory.NewFilter(context.Background()).Eq("status", map[bool]bool{true: false})
+	_, _, err := s.GetJobs(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*status", err)
+}
+
+func TestGetJobsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.JobQueryFactory.NewFilter(context.Background()).Eq("status", "")
+	_, _, err := s.GetJobs(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -57,6 +57,12 @@ var (
 		"tx_parent_id",
 		"batch_id",
 		"idempotency_key",
+		"send_time",
+		"priority",
+		"expires",
+		"chunks",
+		"parent_message",
+		"topic_sequence",
 	}
 	msgFilterFieldMap = map[string]string{
 		"type":           "mtype",
@@ -68,12 +74,51 @@ var (
 		"group":          "group_hash",
 		"idempotencykey": "idempotency_key",
 		"rejectreason":   "reject_reason",
+		"sendtime":       "send_time",
+		"parentmessage":  "parent_message",
+		"topicsequence":  "topic_sequence",
+	}
+	// msgFieldColumns maps the JSON fields of a message to the columns they are stored in
+	msgFieldColumns = map[string]string{
+		"header.id":            "id",
+		"header.cid":           "cid",
+		"header.type":          "mtype",
+		"header.author":        "author",
+		"header.key":           "key",
+		"header.created":       "created",
+		"header.namespace":     "namespace",
+		"header.topics":        "topics",
+		"header.tag":           "tag",
+		"header.group":         "group_hash",
+		"header.datahash":      "datahash",
+		"header.txtype":        "tx_type",
+		"header.txparent.type": "tx_parent_type",
+		"header.txparent.id":   "tx_parent_id",
+		"localNamespace":       "namespace_local",
+		"hash":                 "hash",
+		"pins":                 "pins",
+		"state":                "state",
+		"confirmed":            "confirmed",
+		"rejectReason":         "reject_reason",
+		"txid":                 "tx_id",
+		"batch":                "batch_id",
+		"idempotencyKey":       "idempotency_key",
+		"sendTime":             "send_time",
+		"priority":             "priority",
+		"expires":              "expires",
+		"chunks":               "chunks",
+		"header.parentMessage": "parent_message",
+		"header.topicSequence": "topic_sequence",
 	}
 )
 
 const messagesTable = "messages"
 const messagesDataJoinTable = "messages_data"
 
+// msgIteratorBatchSize is the number of messages read before their data references are loaded, when the
+// messages are passed to an iterator
+const msgIteratorBatchSize = 100
+
 func (s *SQLCommon) attemptMessageUpdate(ctx context.Context, tx *dbsql.TXWrapper, message *core.Message) (int64, error) {
 	var txParentID *fftypes.UUID
 	var txParentType core.TransactionType
@@ -104,6 +149,12 @@ func (s *SQLCommon) attemptMessageUpdate(ctx context.Context, tx *dbsql.TXWrappe
 			Set("tx_parent_id", txParentID).
 			Set("batch_id", message.BatchID).
 			Set("idempotency_key", message.IdempotencyKey).
+			Set("send_time", message.SendTime).
+			Set("priority", message.Priority).
+			Set("expires", message.Expires).
+			Set("chunks", message.Chunks).
+			Set("parent_message", message.Header.ParentMessage).
+			Set("topic_sequence", message.Header.TopicSequence).
 			Where(sq.Eq{
 				"id":              message.Header.ID,
 				"hash":            message.Hash,
@@ -147,6 +198,12 @@ func (s *SQLCommon) setMessageInsertValues(query sq.InsertBuilder, message *core
 		txParentID,
 		message.BatchID,
 		message.IdempotencyKey,
+		message.SendTime,
+		message.Priority,
+		message.Expires,
+		message.Chunks,
+		message.Header.ParentMessage,
+		message.Header.TopicSequence,
 	)
 }
 
@@ -434,36 +491,43 @@ func (s *SQLCommon) loadDataRefs(ctx context.Context, namespace string, msgs []*
 	return nil
 }
 
-func (s *SQLCommon) msgResult(ctx context.Context, row *sql.Rows) (*core.Message, error) {
+func (s *SQLCommon) msgResult(ctx context.Context, row *sql.Rows, cols []string) (*core.Message, error) {
 	var msg core.Message
 	var txParent core.TransactionRef
-	err := row.Scan(
-		&msg.Header.ID,
-		&msg.Header.CID,
-		&msg.Header.Type,
-		&msg.Header.Author,
-		&msg.Header.Key,
-		&msg.Header.Created,
-		&msg.Header.Namespace,
-		&msg.LocalNamespace,
-		&msg.Header.Topics,
-		&msg.Header.Tag,
-		&msg.Header.Group,
-		&msg.Header.DataHash,
-		&msg.Hash,
-		&msg.Pins,
-		&msg.State,
-		&msg.Confirmed,
-		&msg.RejectReason,
-		&msg.Header.TxType,
-		&msg.TransactionID,
-		&txParent.Type,
-		&txParent.ID,
-		&msg.BatchID,
-		&msg.IdempotencyKey,
+	err := row.Scan(scanDest(cols, map[string]interface{}{
+		"id":              &msg.Header.ID,
+		"cid":             &msg.Header.CID,
+		"mtype":           &msg.Header.Type,
+		"author":          &msg.Header.Author,
+		"key":             &msg.Header.Key,
+		"created":         &msg.Header.Created,
+		"namespace":       &msg.Header.Namespace,
+		"namespace_local": &msg.LocalNamespace,
+		"topics":          &msg.Header.Topics,
+		"tag":             &msg.Header.Tag,
+		"group_hash":      &msg.Header.Group,
+		"datahash":        &msg.Header.DataHash,
+		"hash":            &msg.Hash,
+		"pins":            &msg.Pins,
+		"state":           &msg.State,
+		"confirmed":       &msg.Confirmed,
+		"reject_reason":   &msg.RejectReason,
+		"tx_type":         &msg.Header.TxType,
+		"tx_id":           &msg.TransactionID,
+		"tx_parent_type":  &txParent.Type,
+		"tx_parent_id":    &txParent.ID,
+		"batch_id":        &msg.BatchID,
+		"idempotency_key": &msg.IdempotencyKey,
+		"send_time":       &msg.SendTime,
+		"priority":        &msg.Priority,
+		"expires":         &msg.Expires,
+		"chunks":          &msg.Chunks,
+		"parent_message":  &msg.Header.ParentMessage,
+		"topic_sequence":  &msg.Header.TopicSequence,
+	},
 		// Must be added to the list of columns in all selects
 		&msg.Sequence,
-	)
+	)...)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, messagesTable)
 	}
@@ -492,7 +556,7 @@ func (s *SQLCommon) GetMessageByID(ctx context.Context, namespace string, id *ff
 		return nil, nil
 	}
 
-	msg, err := s.msgResult(ctx, rows)
+	msg, err := s.msgResult(ctx, rows, msgColumns)
 	if err != nil {
 		return nil, err
 	}
@@ -505,7 +569,7 @@ func (s *SQLCommon) GetMessageByID(ctx context.Context, namespace string, id *ff
 	return msg, nil
 }
 
-func (s *SQLCommon) getMessagesQuery(ctx context.Context, namespace string, query sq.SelectBuilder, fop sq.Sqlizer, fi *ffapi.FilterInfo, allowCount bool) (message []*core.Message, fr *ffapi.FilterResult, err error) {
+func (s *SQLCommon) getMessagesQuery(ctx context.Context, namespace string, cols []string, query sq.SelectBuilder, fop sq.Sqlizer, fi *ffapi.FilterInfo, allowCount, withDataRefs bool) (message []*core.Message, fr *ffapi.FilterResult, err error) {
 	if fi.Count && !allowCount {
 		return nil, nil, i18n.NewError(ctx, coremsgs.MsgFilterCountNotSupported)
 	}
@@ -516,17 +580,29 @@ func (s *SQLCommon) getMessagesQuery(ctx context.Context, namespace string, quer
 	}
 	defer rows.Close()
 
+	it := s.rowIterator(ctx)
 	msgs := []*core.Message{}
 	for rows.Next() {
-		msg, err := s.msgResult(ctx, rows)
+		msg, err := s.msgResult(ctx, rows, cols)
 		if err != nil {
 			return nil, nil, err
 		}
 		msgs = append(msgs, msg)
+		if it != nil && len(msgs) == msgIteratorBatchSize {
+			if err := s.iterateMessages(ctx, namespace, msgs, withDataRefs, it); err != nil {
+				return nil, nil, err
+			}
+			msgs = []*core.Message{}
+		}
 	}
 
 	rows.Close()
-	if len(msgs) > 0 {
+	if it != nil {
+		if err = s.iterateMessages(ctx, namespace, msgs, withDataRefs, it); err != nil {
+			return nil, nil, err
+		}
+		msgs = []*core.Message{}
+	} else if len(msgs) > 0 && withDataRefs {
 		if err = s.loadDataRefs(ctx, namespace, msgs); err != nil {
 			return nil, nil, err
 		}
@@ -534,6 +610,22 @@ func (s *SQLCommon) getMessagesQuery(ctx context.Context, namespace string, quer
 	return msgs, s.QueryRes(ctx, messagesTable, tx, fop, fi), err
 }
 
+// iterateMessages passes a batch of messages to an iterator, after loading their data references in a
+// single query
+func (s *SQLCommon) iterateMessages(ctx context.Context, namespace string, msgs []*core.Message, withDataRefs bool, it database.RowIterator) error {
+	if len(msgs) > 0 && withDataRefs {
+		if err := s.loadDataRefs(ctx, namespace, msgs); err != nil {
+			return err
+		}
+	}
+	for _, msg := range msgs {
+		if err := it(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *SQLCommon) GetMessageIDs(ctx context.Context, namespace string, filter ffapi.Filter) (ids []*core.IDAndSequence, err error) {
 	query, _, _, err := s.FilterSelect(ctx, "", sq.Select("id", s.SequenceColumn()).From(messagesTable), filter, msgFilterFieldMap,
 		[]interface{}{
@@ -595,10 +687,12 @@ func (s *SQLCommon) queryBatchIDs(ctx context.Context, query sq.SelectBuilder) (
 	return batchIDs, nil
 }
 
-func (s *SQLCommon) GetMessages(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Message, fr *ffapi.FilterResult, err error) {
-	cols := append([]string{}, msgColumns...)
-	cols = append(cols, s.SequenceColumn())
-	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(messagesTable), filter, msgFilterFieldMap,
+func (s *SQLCommon) GetMessages(ctx context.Context, namespace string, filter ffapi.Filter, fields ...string) (message []*core.Message, fr *ffapi.FilterResult, err error) {
+	// The data references are only loaded when all the fields are requested, as they are not a column of the table
+	cols, projected := projectColumns(fields, msgColumns, msgFieldColumns)
+	selectCols := append([]string{}, cols...)
+	selectCols = append(selectCols, s.SequenceColumn())
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(selectCols...).From(messagesTable), filter, msgFilterFieldMap,
 		[]interface{}{
 			&ffapi.SortField{Field: "confirmed", Descending: true, Nulls: ffapi.NullsFirst},
 			&ffapi.SortField{Field: "created", Descending: true},
@@ -606,7 +700,7 @@ func (s *SQLCommon) GetMessages(ctx context.Context, namespace string, filter ff
 	if err != nil {
 		return nil, nil, err
 	}
-	return s.getMessagesQuery(ctx, namespace, query, fop, fi, true)
+	return s.getMessagesQuery(ctx, namespace, cols, query, fop, fi, true, !projected)
 }
 
 func (s *SQLCommon) GetMessagesForData(ctx context.Context, namespace string, dataID *fftypes.UUID, filter ffapi.Filter) (message []*core.Message, fr *ffapi.FilterResult, err error) {
@@ -624,7 +718,7 @@ func (s *SQLCommon) GetMessagesForData(ctx context.Context, namespace string, da
 	}
 
 	query = query.LeftJoin("messages AS m ON m.id = md.message_id")
-	return s.getMessagesQuery(ctx, namespace, query, fop, fi, false)
+	return s.getMessagesQuery(ctx, namespace, msgColumns, query, fop, fi, false, true)
 }
 
 func (s *SQLCommon) UpdateMessage(ctx context.Context, namespace string, msgid *fftypes.UUID, update ffapi.Update) (err error) {
@@ -117,6 +117,8 @@ func TestUpsertE2EWithDB(t *testing.T) {
 				Type: core.TransactionTypeTokenTransfer,
 				ID:   fftypes.NewUUID(),
 			},
+			ParentMessage: fftypes.NewUUID(),
+			TopicSequence: 12,
 		},
 		Hash:           fftypes.NewRandB32(),
 		Pins:           []string{fftypes.NewRandB32().String(), fftypes.NewRandB32().String()},
@@ -124,6 +126,10 @@ func TestUpsertE2EWithDB(t *testing.T) {
 		Confirmed:      fftypes.Now(),
 		BatchID:        bid,
 		IdempotencyKey: "myBusinessIdentifier",
+		SendTime:       fftypes.Now(),
+		Priority:       core.MessagePriorityHigh,
+		Expires:        fftypes.Now(),
+		Chunks:         fftypes.FFStringArray{fftypes.NewUUID().String(), fftypes.NewUUID().String()},
 		Data: []*core.DataRef{
 			{ID: dataID1, Hash: rand1},
 			{ID: dataID2, Hash: rand2}, // Note the data refs cannot change, as it would affect the hash, and the hash is immutable
@@ -164,9 +170,14 @@ func TestUpsertE2EWithDB(t *testing.T) {
 		fb.Eq("topics", msgUpdated.Header.Topics),
 		fb.Eq("group", msgUpdated.Header.Group),
 		fb.Eq("cid", msgUpdated.Header.CID),
+		fb.Eq("parentmessage", msgUpdated.Header.ParentMessage),
+		fb.Eq("topicsequence", msgUpdated.Header.TopicSequence),
 		fb.Eq("idempotencykey", msgUpdated.IdempotencyKey),
 		fb.Gt("created", "0"),
 		fb.Gt("confirmed", "0"),
+		fb.Gt("sendtime", "0"),
+		fb.Eq("priority", core.MessagePriorityHigh),
+		fb.Gt("expires", "0"),
 	)
 	msgs, res, err := s.GetMessages(ctx, "ns12345", filter.Count(true))
 	assert.NoError(t, err)
@@ -565,7 +576,7 @@ func TestGetMessageByIDLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", 0))
+		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", nil, "", nil, "", nil, 0, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	_, err := s.GetMessageByID(context.Background(), "ns1", msgID)
 	assert.Regexp(t, "FF00176", err)
@@ -612,7 +623,7 @@ func TestGetMessagesLoadRefsFail(t *testing.T) {
 	cols := append([]string{}, msgColumns...)
 	cols = append(cols, "id()")
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(cols).
-		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", 0))
+		AddRow(msgID.String(), nil, core.MessageTypeBroadcast, "author1", "0x12345", 0, "ns1", "ns1", "t1", "c1", nil, b32.String(), b32.String(), b32.String(), "confirmed", 0, "", "pin", nil, "", nil, nil, "bob", nil, "", nil, "", nil, 0, 0))
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
 	f := database.MessageQueryFactory.NewFilter(context.Background()).Gt("confirmed", "0")
 	_, _, err := s.GetMessages(context.Background(), "ns1", f)
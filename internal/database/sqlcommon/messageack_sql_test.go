@@ -0,0 +1,128 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageAcksE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	msgID := fftypes.NewUUID()
+	ack := &core.MessageAck{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Message:   msgID,
+		Outcome:   core.MessageAckOutcomeRejected,
+		Reason:    "credit limit exceeded",
+	}
+	err := s.InsertMessageAck(ctx, ack)
+	assert.NoError(t, err)
+	assert.NotNil(t, ack.Created)
+	ackJson, _ := json.Marshal(&ack)
+
+	// Query back the ack (by query filter)
+	fb := database.MessageAckQueryFactory.NewFilter(ctx)
+	acks, res, err := s.GetMessageAcks(ctx, "ns1", fb.And(
+		fb.Eq("message", msgID),
+		fb.Eq("outcome", core.MessageAckOutcomeRejected),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(acks))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	ackReadJson, _ := json.Marshal(acks[0])
+	assert.Equal(t, string(ackJson), string(ackReadJson))
+
+	// A message can only be acked once
+	err = s.InsertMessageAck(ctx, &core.MessageAck{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Message:   msgID,
+		Outcome:   core.MessageAckOutcomeAccepted,
+	})
+	assert.Regexp(t, "FF00177", err)
+
+	// Other namespace
+	acks, _, err = s.GetMessageAcks(ctx, "ns2", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, acks)
+}
+
+func TestInsertMessageAckFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertMessageAck(context.Background(), &core.MessageAck{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertMessageAckFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertMessageAck(context.Background(), &core.MessageAck{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertMessageAckFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertMessageAck(context.Background(), &core.MessageAck{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessageAcksQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.MessageAckQueryFactory.NewFilter(context.Background()).Eq("message", fftypes.NewUUID())
+	_, _, err := s.GetMessageAcks(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessageAcksBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.MessageAckQueryFactory.NewFilter(context.Background()).Eq("reason", map[bool]bool{true: false})
+	_, _, err := s.GetMessageAcks(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*reason", err)
+}
+
+func TestGetMessageAcksScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.MessageAckQueryFactory.NewFilter(context.Background()).Eq("reason", "")
+	_, _, err := s.GetMessageAcks(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
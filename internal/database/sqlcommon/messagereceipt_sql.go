@@ -0,0 +1,124 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	messageReceiptColumns = []string{
+		"id",
+		"namespace",
+		"message_id",
+		"message_hash",
+		"rtype",
+		"identity",
+		"node_id",
+		"created",
+	}
+	messageReceiptFilterFieldMap = map[string]string{
+		"message":     "message_id",
+		"messagehash": "message_hash",
+		"type":        "rtype",
+		"node":        "node_id",
+	}
+)
+
+const messageReceiptsTable = "messagereceipts"
+
+func (s *SQLCommon) InsertMessageReceipt(ctx context.Context, receipt *core.MessageReceipt) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if receipt.Created == nil {
+		receipt.Created = fftypes.Now()
+	}
+	if _, err = s.InsertTx(ctx, messageReceiptsTable, tx,
+		sq.Insert(messageReceiptsTable).
+			Columns(messageReceiptColumns...).
+			Values(
+				receipt.ID,
+				receipt.Namespace,
+				receipt.Message,
+				receipt.MessageHash,
+				receipt.Type,
+				receipt.Identity,
+				receipt.Node,
+				receipt.Created,
+			),
+		nil, // no change events for receipts
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) messageReceiptResult(ctx context.Context, row *sql.Rows) (*core.MessageReceipt, error) {
+	receipt := core.MessageReceipt{}
+	err := row.Scan(
+		&receipt.ID,
+		&receipt.Namespace,
+		&receipt.Message,
+		&receipt.MessageHash,
+		&receipt.Type,
+		&receipt.Identity,
+		&receipt.Node,
+		&receipt.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, messageReceiptsTable)
+	}
+	return &receipt, nil
+}
+
+func (s *SQLCommon) GetMessageReceipts(ctx context.Context, namespace string, filter ffapi.Filter) (receipts []*core.MessageReceipt, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(messageReceiptColumns...).From(messageReceiptsTable),
+		filter, messageReceiptFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, messageReceiptsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	receipts = []*core.MessageReceipt{}
+	for rows.Next() {
+		receipt, err := s.messageReceiptResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	return receipts, s.QueryRes(ctx, messageReceiptsTable, tx, fop, fi), err
+}
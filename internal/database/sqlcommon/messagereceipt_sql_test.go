@@ -0,0 +1,136 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageReceiptsE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new receipt
+	receipt := &core.MessageReceipt{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Message:     fftypes.NewUUID(),
+		MessageHash: fftypes.NewRandB32(),
+		Type:        core.MessageReceiptTypeDelivered,
+		Identity:    "did:firefly:org/org2",
+		Node:        fftypes.NewUUID(),
+	}
+	err := s.InsertMessageReceipt(ctx, receipt)
+	assert.NoError(t, err)
+	assert.NotNil(t, receipt.Created)
+	receiptJson, _ := json.Marshal(&receipt)
+
+	// A second receipt of the same type from the same member is rejected
+	err = s.InsertMessageReceipt(ctx, &core.MessageReceipt{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Message:     receipt.Message,
+		MessageHash: receipt.MessageHash,
+		Type:        core.MessageReceiptTypeDelivered,
+		Identity:    receipt.Identity,
+		Node:        receipt.Node,
+	})
+	assert.Regexp(t, "FF00177", err)
+
+	// Query back the receipt
+	fb := database.MessageReceiptQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("message", receipt.Message),
+		fb.Eq("type", core.MessageReceiptTypeDelivered),
+		fb.Eq("node", receipt.Node),
+	)
+	receipts, res, err := s.GetMessageReceipts(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(receipts))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	receiptReadJson, _ := json.Marshal(receipts[0])
+	assert.Equal(t, string(receiptJson), string(receiptReadJson))
+
+	// Other namespaces do not see the receipt
+	receipts, _, err = s.GetMessageReceipts(ctx, "ns2", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, receipts)
+}
+
+func TestInsertMessageReceiptFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertMessageReceipt(context.Background(), &core.MessageReceipt{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertMessageReceiptFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertMessageReceipt(context.Background(), &core.MessageReceipt{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertMessageReceiptFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertMessageReceipt(context.Background(), &core.MessageReceipt{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessageReceiptsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.MessageReceiptQueryFactory.NewFilter(context.Background()).Eq("identity", "")
+	_, _, err := s.GetMessageReceipts(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessageReceiptsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.MessageReceiptQueryFactory.NewFilter(context.Background()).Eq("identity", map[bool]bool{true: false})
+	_, _, err := s.GetMessageReceipts(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*identity", err)
+}
+
+func TestGetMessageReceiptsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.MessageReceiptQueryFactory.NewFilter(context.Background()).Eq("identity", "")
+	_, _, err := s.GetMessageReceipts(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -44,12 +44,30 @@ var (
 		"input",
 		"output",
 		"retry_id",
+		"failure_reason",
 	}
 	opFilterFieldMap = map[string]string{
-		"tx":     "tx_id",
-		"type":   "optype",
-		"status": "opstatus",
-		"retry":  "retry_id",
+		"tx":            "tx_id",
+		"type":          "optype",
+		"status":        "opstatus",
+		"retry":         "retry_id",
+		"failurereason": "failure_reason",
+	}
+	// opFieldColumns maps the JSON fields of an operation to the columns they are stored in
+	opFieldColumns = map[string]string{
+		"id":            "id",
+		"namespace":     "namespace",
+		"tx":            "tx_id",
+		"type":          "optype",
+		"status":        "opstatus",
+		"plugin":        "plugin",
+		"created":       "created",
+		"updated":       "updated",
+		"error":         "error",
+		"input":         "input",
+		"output":        "output",
+		"retry":         "retry_id",
+		"failureReason": "failure_reason",
 	}
 )
 
@@ -78,6 +96,7 @@ func (s *SQLCommon) InsertOperation(ctx context.Context, operation *core.Operati
 				operation.Input,
 				operation.Output,
 				operation.Retry,
+				operation.FailureReason,
 			),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionOperations, core.ChangeEventTypeCreated, operation.Namespace, operation.ID)
@@ -92,22 +111,23 @@ func (s *SQLCommon) InsertOperation(ctx context.Context, operation *core.Operati
 	return s.CommitTx(ctx, tx, autoCommit)
 }
 
-func (s *SQLCommon) opResult(ctx context.Context, row *sql.Rows) (*core.Operation, error) {
+func (s *SQLCommon) opResult(ctx context.Context, row *sql.Rows, cols []string) (*core.Operation, error) {
 	var op core.Operation
-	err := row.Scan(
-		&op.ID,
-		&op.Namespace,
-		&op.Transaction,
-		&op.Type,
-		&op.Status,
-		&op.Plugin,
-		&op.Created,
-		&op.Updated,
-		&op.Error,
-		&op.Input,
-		&op.Output,
-		&op.Retry,
-	)
+	err := row.Scan(scanDest(cols, map[string]interface{}{
+		"id":             &op.ID,
+		"namespace":      &op.Namespace,
+		"tx_id":          &op.Transaction,
+		"optype":         &op.Type,
+		"opstatus":       &op.Status,
+		"plugin":         &op.Plugin,
+		"created":        &op.Created,
+		"updated":        &op.Updated,
+		"error":          &op.Error,
+		"input":          &op.Input,
+		"output":         &op.Output,
+		"retry_id":       &op.Retry,
+		"failure_reason": &op.FailureReason,
+	})...)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, operationsTable)
 	}
@@ -131,7 +151,7 @@ func (s *SQLCommon) GetOperationByID(ctx context.Context, namespace string, id *
 		return nil, nil
 	}
 
-	op, err := s.opResult(ctx, rows)
+	op, err := s.opResult(ctx, rows, opColumns)
 	if err != nil {
 		return nil, err
 	}
@@ -139,9 +159,10 @@ func (s *SQLCommon) GetOperationByID(ctx context.Context, namespace string, id *
 	return op, nil
 }
 
-func (s *SQLCommon) GetOperations(ctx context.Context, namespace string, filter ffapi.Filter) (operation []*core.Operation, fr *ffapi.FilterResult, err error) {
+func (s *SQLCommon) GetOperations(ctx context.Context, namespace string, filter ffapi.Filter, fields ...string) (operation []*core.Operation, fr *ffapi.FilterResult, err error) {
 
-	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(opColumns...).From(operationsTable), filter, opFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	cols, _ := projectColumns(fields, opColumns, opFieldColumns)
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(operationsTable), filter, opFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -152,12 +173,19 @@ func (s *SQLCommon) GetOperations(ctx context.Context, namespace string, filter
 	}
 	defer rows.Close()
 
+	it := s.rowIterator(ctx)
 	ops := []*core.Operation{}
 	for rows.Next() {
-		op, err := s.opResult(ctx, rows)
+		op, err := s.opResult(ctx, rows, cols)
 		if err != nil {
 			return nil, nil, err
 		}
+		if it != nil {
+			if err := it(op); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
 		ops = append(ops, op)
 	}
 
@@ -38,17 +38,18 @@ func TestOperationE2EWithDB(t *testing.T) {
 	// Create a new operation entry
 	operationID := fftypes.NewUUID()
 	operation := &core.Operation{
-		ID:          operationID,
-		Namespace:   "ns1",
-		Type:        core.OpTypeBlockchainPinBatch,
-		Transaction: fftypes.NewUUID(),
-		Status:      core.OpStatusFailed,
-		Plugin:      "ethereum",
-		Error:       "pop",
-		Input:       fftypes.JSONObject{"some": "input-info"},
-		Output:      fftypes.JSONObject{"some": "output-info"},
-		Created:     fftypes.Now(),
-		Updated:     fftypes.Now(),
+		ID:            operationID,
+		Namespace:     "ns1",
+		Type:          core.OpTypeBlockchainPinBatch,
+		Transaction:   fftypes.NewUUID(),
+		Status:        core.OpStatusFailed,
+		Plugin:        "ethereum",
+		Error:         "pop",
+		FailureReason: core.OpFailureReasonComplianceRejected,
+		Input:         fftypes.JSONObject{"some": "input-info"},
+		Output:        fftypes.JSONObject{"some": "output-info"},
+		Created:       fftypes.Now(),
+		Updated:       fftypes.Now(),
 	}
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeCreated, "ns1", operationID).Return()
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeUpdated, "ns1", operationID).Return()
@@ -0,0 +1,61 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"strings"
+)
+
+// projectColumns returns the columns to select for the requested fields, where fieldColumns maps the JSON
+// paths of the resource to their columns. All the columns are selected when no fields are requested, or a
+// field is not stored in a single column of the table (such as the data of a message).
+func projectColumns(fields []string, columns []string, fieldColumns map[string]string) (selected []string, projected bool) {
+	if len(fields) == 0 {
+		return columns, false
+	}
+	required := make(map[string]bool)
+	for _, field := range fields {
+		matched := false
+		for path, column := range fieldColumns {
+			// A field can be a parent of a column, such as "header", or within a column holding JSON
+			if path == field || strings.HasPrefix(path, field+".") || strings.HasPrefix(field, path+".") {
+				required[column] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return columns, false
+		}
+	}
+	selected = make([]string, 0, len(required))
+	for _, column := range columns {
+		if required[column] {
+			selected = append(selected, column)
+		}
+	}
+	return selected, true
+}
+
+// scanDest returns the destinations to scan a row of the given columns into, from the fields of a
+// resource for each of its columns
+func scanDest(columns []string, fields map[string]interface{}, extra ...interface{}) []interface{} {
+	dest := make([]interface{}, 0, len(columns)+len(extra))
+	for _, column := range columns {
+		dest = append(dest, fields[column])
+	}
+	return append(dest, extra...)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectColumns(t *testing.T) {
+	cols, projected := projectColumns(nil, msgColumns, msgFieldColumns)
+	assert.False(t, projected)
+	assert.Equal(t, msgColumns, cols)
+
+	cols, projected = projectColumns([]string{"state", "header.id"}, msgColumns, msgFieldColumns)
+	assert.True(t, projected)
+	assert.Equal(t, []string{"id", "state"}, cols)
+
+	cols, projected = projectColumns([]string{"header.txparent"}, msgColumns, msgFieldColumns)
+	assert.True(t, projected)
+	assert.Equal(t, []string{"tx_parent_type", "tx_parent_id"}, cols)
+
+	cols, projected = projectColumns([]string{"input.amount"}, opColumns, opFieldColumns)
+	assert.True(t, projected)
+	assert.Equal(t, []string{"input"}, cols)
+
+	cols, projected = projectColumns([]string{"state", "data"}, msgColumns, msgFieldColumns)
+	assert.False(t, projected)
+	assert.Equal(t, msgColumns, cols)
+}
+
+func TestGetMessagesProjected(t *testing.T) {
+	s, mock := newMockProvider().init()
+	msgID := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT id, state, seq FROM messages").WillReturnRows(sqlmock.NewRows([]string{"id", "state", "seq"}).
+		AddRow(msgID.String(), "confirmed", 12345))
+	ctx := context.Background()
+	f := database.MessageQueryFactory.NewFilter(ctx).Gt("confirmed", "0")
+	msgs, _, err := s.GetMessages(ctx, "ns1", f, "header.id", "state")
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 1)
+	assert.Equal(t, msgID, msgs[0].Header.ID)
+	assert.Equal(t, core.MessageStateConfirmed, msgs[0].State)
+	assert.Equal(t, int64(12345), msgs[0].Sequence)
+	assert.Nil(t, msgs[0].Data)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetOperationsProjected(t *testing.T) {
+	s, mock := newMockProvider().init()
+	opID := fftypes.NewUUID()
+	mock.ExpectQuery("SELECT id, opstatus FROM operations").WillReturnRows(sqlmock.NewRows([]string{"id", "opstatus"}).
+		AddRow(opID.String(), "Succeeded"))
+	ctx := context.Background()
+	f := database.OperationQueryFactory.NewFilter(ctx).Gt("created", "0")
+	ops, _, err := s.GetOperations(ctx, "ns1", f, "id", "status")
+	assert.NoError(t, err)
+	assert.Len(t, ops, 1)
+	assert.Equal(t, opID, ops[0].ID)
+	assert.Equal(t, core.OpStatusSucceeded, ops[0].Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
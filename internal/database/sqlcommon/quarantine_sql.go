@@ -0,0 +1,191 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	quarantineColumns = []string{
+		"id",
+		"namespace",
+		"org",
+		"node_id",
+		"peer",
+		"batch_id",
+		"reason",
+		"messages",
+		"size",
+		"transport",
+		"state",
+		"created",
+		"updated",
+	}
+	quarantineFilterFieldMap = map[string]string{
+		"node":  "node_id",
+		"batch": "batch_id",
+	}
+)
+
+const quarantineTable = "quarantine"
+
+func (s *SQLCommon) InsertQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if quarantined.Created == nil {
+		quarantined.Created = fftypes.Now()
+	}
+	if quarantined.State == "" {
+		quarantined.State = core.QuarantineStatePending
+	}
+	if _, err = s.InsertTx(ctx, quarantineTable, tx,
+		sq.Insert(quarantineTable).
+			Columns(quarantineColumns...).
+			Values(
+				quarantined.ID,
+				quarantined.Namespace,
+				quarantined.Org,
+				quarantined.Node,
+				quarantined.Peer,
+				quarantined.Batch,
+				quarantined.Reason,
+				quarantined.Messages,
+				quarantined.Size,
+				quarantined.Transport,
+				quarantined.State,
+				quarantined.Created,
+				quarantined.Updated,
+			),
+		nil, // no change events for quarantined batches
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) quarantineResult(ctx context.Context, row *sql.Rows) (*core.QuarantinedBatch, error) {
+	quarantined := core.QuarantinedBatch{
+		Transport: &core.TransportWrapper{},
+	}
+	err := row.Scan(
+		&quarantined.ID,
+		&quarantined.Namespace,
+		&quarantined.Org,
+		&quarantined.Node,
+		&quarantined.Peer,
+		&quarantined.Batch,
+		&quarantined.Reason,
+		&quarantined.Messages,
+		&quarantined.Size,
+		quarantined.Transport,
+		&quarantined.State,
+		&quarantined.Created,
+		&quarantined.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, quarantineTable)
+	}
+	return &quarantined, nil
+}
+
+func (s *SQLCommon) GetQuarantinedBatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.QuarantinedBatch, error) {
+	rows, _, err := s.Query(ctx, quarantineTable,
+		sq.Select(quarantineColumns...).
+			From(quarantineTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Quarantined batch '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.quarantineResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetQuarantinedBatches(ctx context.Context, namespace string, filter ffapi.Filter) (quarantined []*core.QuarantinedBatch, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(quarantineColumns...).From(quarantineTable),
+		filter, quarantineFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, quarantineTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	quarantined = []*core.QuarantinedBatch{}
+	for rows.Next() {
+		q, err := s.quarantineResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		quarantined = append(quarantined, q)
+	}
+
+	return quarantined, s.QueryRes(ctx, quarantineTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) UpdateQuarantinedBatch(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(quarantineTable), update, quarantineFilterFieldMap)
+	if err != nil {
+		return false, err
+	}
+
+	if filter != nil {
+		query, err = s.FilterUpdate(ctx, query, filter, quarantineFilterFieldMap)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"namespace": namespace, "id": id})
+
+	ra, err := s.UpdateTx(ctx, quarantineTable, tx, query, nil /* no change events for quarantined batches */)
+	if err != nil {
+		return false, err
+	}
+	return ra > 0, s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,200 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuarantineE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	batchID := fftypes.NewUUID()
+	quarantined := &core.QuarantinedBatch{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Org:       "did:firefly:org/org2",
+		Node:      fftypes.NewUUID(),
+		Peer:      "peer2",
+		Batch:     batchID,
+		Reason:    core.QuarantineReasonRate,
+		Messages:  10,
+		Size:      12345,
+		Transport: &core.TransportWrapper{
+			Batch: &core.Batch{BatchHeader: core.BatchHeader{ID: batchID}},
+		},
+	}
+	err := s.InsertQuarantinedBatch(ctx, quarantined)
+	assert.NoError(t, err)
+	assert.NotNil(t, quarantined.Created)
+	assert.Equal(t, core.QuarantineStatePending, quarantined.State)
+	quarantinedJson, _ := json.Marshal(&quarantined)
+
+	// Query back the quarantined batch (by ID)
+	quarantinedRead, err := s.GetQuarantinedBatchByID(ctx, "ns1", quarantined.ID)
+	assert.NoError(t, err)
+	quarantinedReadJson, _ := json.Marshal(&quarantinedRead)
+	assert.Equal(t, string(quarantinedJson), string(quarantinedReadJson))
+	assert.Equal(t, batchID, quarantinedRead.Transport.Batch.ID)
+
+	// Query back the quarantined batch (by query filter)
+	fb := database.QuarantineQueryFactory.NewFilter(ctx)
+	quarantinedBatches, res, err := s.GetQuarantinedBatches(ctx, "ns1", fb.And(
+		fb.Eq("org", "did:firefly:org/org2"),
+		fb.Eq("batch", batchID),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(quarantinedBatches))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	quarantinedReadJson, _ = json.Marshal(quarantinedBatches[0])
+	assert.Equal(t, string(quarantinedJson), string(quarantinedReadJson))
+
+	// Update the state, only if it is still pending
+	f := database.QuarantineQueryFactory.NewFilter(ctx).Eq("state", core.QuarantineStatePending)
+	u := database.QuarantineQueryFactory.NewUpdate(ctx).Set("state", core.QuarantineStateReleased)
+	updated, err := s.UpdateQuarantinedBatch(ctx, "ns1", quarantined.ID, f, u)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	quarantinedRead, err = s.GetQuarantinedBatchByID(ctx, "ns1", quarantined.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateReleased, quarantinedRead.State)
+	assert.NotNil(t, quarantinedRead.Updated)
+	updated, err = s.UpdateQuarantinedBatch(ctx, "ns1", quarantined.ID, f, u)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	// Not found
+	quarantinedRead, err = s.GetQuarantinedBatchByID(ctx, "ns2", quarantined.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, quarantinedRead)
+}
+
+func TestInsertQuarantinedBatchFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertQuarantinedBatch(context.Background(), &core.QuarantinedBatch{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertQuarantinedBatchFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertQuarantinedBatch(context.Background(), &core.QuarantinedBatch{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertQuarantinedBatchFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertQuarantinedBatch(context.Background(), &core.QuarantinedBatch{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBatchByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetQuarantinedBatchByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBatchByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetQuarantinedBatchByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBatchesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.QuarantineQueryFactory.NewFilter(context.Background()).Eq("batch", fftypes.NewUUID())
+	_, _, err := s.GetQuarantinedBatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBatchesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.QuarantineQueryFactory.NewFilter(context.Background()).Eq("org", map[bool]bool{true: false})
+	_, _, err := s.GetQuarantinedBatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*org", err)
+}
+
+func TestGetQuarantinedBatchesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.QuarantineQueryFactory.NewFilter(context.Background()).Eq("org", "")
+	_, _, err := s.GetQuarantinedBatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateQuarantinedBatchFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.QuarantineQueryFactory.NewUpdate(context.Background()).Set("state", core.QuarantineStateDiscarded)
+	_, err := s.UpdateQuarantinedBatch(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateQuarantinedBatchBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.QuarantineQueryFactory.NewUpdate(context.Background()).Set("state", map[bool]bool{true: false})
+	_, err := s.UpdateQuarantinedBatch(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestUpdateQuarantinedBatchFilterFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	f := database.QuarantineQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	u := database.QuarantineQueryFactory.NewUpdate(context.Background()).Set("state", core.QuarantineStateDiscarded)
+	_, err := s.UpdateQuarantinedBatch(context.Background(), "ns1", fftypes.NewUUID(), f, u)
+	assert.Regexp(t, "FF00143", err)
+}
+
+func TestUpdateQuarantinedBatchFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.QuarantineQueryFactory.NewUpdate(context.Background()).Set("state", core.QuarantineStateDiscarded)
+	_, err := s.UpdateQuarantinedBatch(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00178", err)
+}
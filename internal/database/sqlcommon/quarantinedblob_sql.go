@@ -0,0 +1,183 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	quarantinedBlobColumns = []string{
+		"id",
+		"namespace",
+		"data_id",
+		"hash",
+		"size",
+		"peer",
+		"payload_ref",
+		"finding",
+		"state",
+		"created",
+		"updated",
+	}
+	quarantinedBlobFilterFieldMap = map[string]string{
+		"data":       "data_id",
+		"payloadref": "payload_ref",
+	}
+)
+
+const quarantinedBlobsTable = "quarantinedblobs"
+
+func (s *SQLCommon) InsertQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if quarantined.Created == nil {
+		quarantined.Created = fftypes.Now()
+	}
+	if quarantined.State == "" {
+		quarantined.State = core.QuarantineStatePending
+	}
+	if _, err = s.InsertTx(ctx, quarantinedBlobsTable, tx,
+		sq.Insert(quarantinedBlobsTable).
+			Columns(quarantinedBlobColumns...).
+			Values(
+				quarantined.ID,
+				quarantined.Namespace,
+				quarantined.Data,
+				quarantined.Hash,
+				quarantined.Size,
+				quarantined.Peer,
+				quarantined.PayloadRef,
+				quarantined.Finding,
+				quarantined.State,
+				quarantined.Created,
+				quarantined.Updated,
+			),
+		nil, // no change events for quarantined blobs
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) quarantinedBlobResult(ctx context.Context, row *sql.Rows) (*core.QuarantinedBlob, error) {
+	var quarantined core.QuarantinedBlob
+	err := row.Scan(
+		&quarantined.ID,
+		&quarantined.Namespace,
+		&quarantined.Data,
+		&quarantined.Hash,
+		&quarantined.Size,
+		&quarantined.Peer,
+		&quarantined.PayloadRef,
+		&quarantined.Finding,
+		&quarantined.State,
+		&quarantined.Created,
+		&quarantined.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, quarantinedBlobsTable)
+	}
+	return &quarantined, nil
+}
+
+func (s *SQLCommon) GetQuarantinedBlobByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.QuarantinedBlob, error) {
+	rows, _, err := s.Query(ctx, quarantinedBlobsTable,
+		sq.Select(quarantinedBlobColumns...).
+			From(quarantinedBlobsTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Quarantined blob '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.quarantinedBlobResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetQuarantinedBlobs(ctx context.Context, namespace string, filter ffapi.Filter) (quarantined []*core.QuarantinedBlob, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(quarantinedBlobColumns...).From(quarantinedBlobsTable),
+		filter, quarantinedBlobFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, quarantinedBlobsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	quarantined = []*core.QuarantinedBlob{}
+	for rows.Next() {
+		q, err := s.quarantinedBlobResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		quarantined = append(quarantined, q)
+	}
+
+	return quarantined, s.QueryRes(ctx, quarantinedBlobsTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) UpdateQuarantinedBlob(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(quarantinedBlobsTable), update, quarantinedBlobFilterFieldMap)
+	if err != nil {
+		return false, err
+	}
+
+	if filter != nil {
+		query, err = s.FilterUpdate(ctx, query, filter, quarantinedBlobFilterFieldMap)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"namespace": namespace, "id": id})
+
+	ra, err := s.UpdateTx(ctx, quarantinedBlobsTable, tx, query, nil /* no change events for quarantined blobs */)
+	if err != nil {
+		return false, err
+	}
+	return ra > 0, s.CommitTx(ctx, tx, autoCommit)
+}
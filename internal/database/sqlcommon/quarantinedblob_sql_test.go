@@ -0,0 +1,195 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuarantinedBlobE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	dataID := fftypes.NewUUID()
+	quarantined := &core.QuarantinedBlob{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Data:       dataID,
+		Hash:       fftypes.NewRandB32(),
+		Size:       12345,
+		Peer:       "peer2",
+		PayloadRef: "ns1/blob1",
+		Finding:    "Eicar-Test-Signature",
+	}
+	err := s.InsertQuarantinedBlob(ctx, quarantined)
+	assert.NoError(t, err)
+	assert.NotNil(t, quarantined.Created)
+	assert.Equal(t, core.QuarantineStatePending, quarantined.State)
+	quarantinedJson, _ := json.Marshal(&quarantined)
+
+	// Query back the quarantined blob (by ID)
+	quarantinedRead, err := s.GetQuarantinedBlobByID(ctx, "ns1", quarantined.ID)
+	assert.NoError(t, err)
+	quarantinedReadJson, _ := json.Marshal(&quarantinedRead)
+	assert.Equal(t, string(quarantinedJson), string(quarantinedReadJson))
+
+	// Query back the quarantined blob (by query filter)
+	fb := database.QuarantinedBlobQueryFactory.NewFilter(ctx)
+	quarantinedBlobs, res, err := s.GetQuarantinedBlobs(ctx, "ns1", fb.And(
+		fb.Eq("data", dataID),
+		fb.Eq("payloadref", "ns1/blob1"),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(quarantinedBlobs))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	quarantinedReadJson, _ = json.Marshal(quarantinedBlobs[0])
+	assert.Equal(t, string(quarantinedJson), string(quarantinedReadJson))
+
+	// Update the state, only if it is still pending
+	f := database.QuarantinedBlobQueryFactory.NewFilter(ctx).Eq("state", core.QuarantineStatePending)
+	u := database.QuarantinedBlobQueryFactory.NewUpdate(ctx).Set("state", core.QuarantineStateReleased)
+	updated, err := s.UpdateQuarantinedBlob(ctx, "ns1", quarantined.ID, f, u)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	quarantinedRead, err = s.GetQuarantinedBlobByID(ctx, "ns1", quarantined.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateReleased, quarantinedRead.State)
+	assert.NotNil(t, quarantinedRead.Updated)
+	updated, err = s.UpdateQuarantinedBlob(ctx, "ns1", quarantined.ID, f, u)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+
+	// Not found
+	quarantinedRead, err = s.GetQuarantinedBlobByID(ctx, "ns2", quarantined.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, quarantinedRead)
+}
+
+func TestInsertQuarantinedBlobFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertQuarantinedBlob(context.Background(), &core.QuarantinedBlob{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertQuarantinedBlobFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertQuarantinedBlob(context.Background(), &core.QuarantinedBlob{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertQuarantinedBlobFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertQuarantinedBlob(context.Background(), &core.QuarantinedBlob{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBlobByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetQuarantinedBlobByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBlobByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetQuarantinedBlobByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBlobsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.QuarantinedBlobQueryFactory.NewFilter(context.Background()).Eq("data", fftypes.NewUUID())
+	_, _, err := s.GetQuarantinedBlobs(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetQuarantinedBlobsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.QuarantinedBlobQueryFactory.NewFilter(context.Background()).Eq("finding", map[bool]bool{true: false})
+	_, _, err := s.GetQuarantinedBlobs(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*finding", err)
+}
+
+func TestGetQuarantinedBlobsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.QuarantinedBlobQueryFactory.NewFilter(context.Background()).Eq("finding", "")
+	_, _, err := s.GetQuarantinedBlobs(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateQuarantinedBlobFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.QuarantinedBlobQueryFactory.NewUpdate(context.Background()).Set("state", core.QuarantineStateDiscarded)
+	_, err := s.UpdateQuarantinedBlob(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateQuarantinedBlobBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.QuarantinedBlobQueryFactory.NewUpdate(context.Background()).Set("state", map[bool]bool{true: false})
+	_, err := s.UpdateQuarantinedBlob(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestUpdateQuarantinedBlobFilterFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	f := database.QuarantinedBlobQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	u := database.QuarantinedBlobQueryFactory.NewUpdate(context.Background()).Set("state", core.QuarantineStateDiscarded)
+	_, err := s.UpdateQuarantinedBlob(context.Background(), "ns1", fftypes.NewUUID(), f, u)
+	assert.Regexp(t, "FF00143", err)
+}
+
+func TestUpdateQuarantinedBlobFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.QuarantinedBlobQueryFactory.NewUpdate(context.Background()).Set("state", core.QuarantineStateDiscarded)
+	_, err := s.UpdateQuarantinedBlob(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00178", err)
+}
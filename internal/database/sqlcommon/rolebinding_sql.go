@@ -0,0 +1,148 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	roleBindingColumns = []string{
+		"id",
+		"namespace",
+		"principal",
+		"role",
+		"created",
+	}
+	roleBindingFilterFieldMap = map[string]string{}
+)
+
+const roleBindingsTable = "rolebindings"
+
+func (s *SQLCommon) InsertRoleBinding(ctx context.Context, binding *core.RoleBinding) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if binding.Created == nil {
+		binding.Created = fftypes.Now()
+	}
+	if _, err = s.InsertTx(ctx, roleBindingsTable, tx,
+		sq.Insert(roleBindingsTable).
+			Columns(roleBindingColumns...).
+			Values(
+				binding.ID,
+				binding.Namespace,
+				binding.Principal,
+				binding.Role,
+				binding.Created,
+			),
+		nil, // no change events for role bindings
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) roleBindingResult(ctx context.Context, row *sql.Rows) (*core.RoleBinding, error) {
+	binding := core.RoleBinding{}
+	err := row.Scan(
+		&binding.ID,
+		&binding.Namespace,
+		&binding.Principal,
+		&binding.Role,
+		&binding.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, roleBindingsTable)
+	}
+	return &binding, nil
+}
+
+func (s *SQLCommon) GetRoleBindingByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.RoleBinding, error) {
+	rows, _, err := s.Query(ctx, roleBindingsTable,
+		sq.Select(roleBindingColumns...).
+			From(roleBindingsTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Role binding '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.roleBindingResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetRoleBindings(ctx context.Context, namespace string, filter ffapi.Filter) (bindings []*core.RoleBinding, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(roleBindingColumns...).From(roleBindingsTable),
+		filter, roleBindingFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, roleBindingsTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	bindings = []*core.RoleBinding{}
+	for rows.Next() {
+		b, err := s.roleBindingResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		bindings = append(bindings, b)
+	}
+
+	return bindings, s.QueryRes(ctx, roleBindingsTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) DeleteRoleBindingByID(ctx context.Context, namespace string, id *fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, roleBindingsTable, tx, sq.Delete(roleBindingsTable).Where(sq.Eq{
+		"namespace": namespace,
+		"id":        id,
+	}), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
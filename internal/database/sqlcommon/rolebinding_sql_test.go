@@ -0,0 +1,178 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleBindingE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	binding := &core.RoleBinding{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Principal: "team-a",
+		Role:      core.RoleSender,
+	}
+	err := s.InsertRoleBinding(ctx, binding)
+	assert.NoError(t, err)
+	assert.NotNil(t, binding.Created)
+	bindingJson, _ := json.Marshal(&binding)
+
+	// Query back the binding (by ID)
+	bindingRead, err := s.GetRoleBindingByID(ctx, "ns1", binding.ID)
+	assert.NoError(t, err)
+	bindingReadJson, _ := json.Marshal(&bindingRead)
+	assert.Equal(t, string(bindingJson), string(bindingReadJson))
+
+	// Query back the binding (by query filter)
+	fb := database.RoleBindingQueryFactory.NewFilter(ctx)
+	bindings, res, err := s.GetRoleBindings(ctx, "ns1", fb.And(
+		fb.Eq("principal", "team-a"),
+		fb.Eq("role", core.RoleSender),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(bindings))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	bindingReadJson, _ = json.Marshal(bindings[0])
+	assert.Equal(t, string(bindingJson), string(bindingReadJson))
+
+	// The same role cannot be bound twice to a principal
+	err = s.InsertRoleBinding(ctx, &core.RoleBinding{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Principal: "team-a",
+		Role:      core.RoleSender,
+	})
+	assert.Regexp(t, "FF00177", err)
+
+	// Delete the binding
+	err = s.DeleteRoleBindingByID(ctx, "ns1", binding.ID)
+	assert.NoError(t, err)
+	bindingRead, err = s.GetRoleBindingByID(ctx, "ns1", binding.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, bindingRead)
+}
+
+func TestInsertRoleBindingFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertRoleBinding(context.Background(), &core.RoleBinding{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertRoleBindingFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertRoleBinding(context.Background(), &core.RoleBinding{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertRoleBindingFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertRoleBinding(context.Background(), &core.RoleBinding{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoleBindingByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetRoleBindingByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoleBindingByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetRoleBindingByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoleBindingsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.RoleBindingQueryFactory.NewFilter(context.Background()).Eq("principal", "team-a")
+	_, _, err := s.GetRoleBindings(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoleBindingsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.RoleBindingQueryFactory.NewFilter(context.Background()).Eq("principal", map[bool]bool{true: false})
+	_, _, err := s.GetRoleBindings(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*principal", err)
+}
+
+func TestGetRoleBindingsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.RoleBindingQueryFactory.NewFilter(context.Background()).Eq("principal", "")
+	_, _, err := s.GetRoleBindings(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRoleBindingFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteRoleBindingByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRoleBindingFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteRoleBindingByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteRoleBindingFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteRoleBindingByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,151 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	slaBreachColumns = []string{
+		"id",
+		"namespace",
+		"btype",
+		"message_id",
+		"topic",
+		"org",
+		"node_id",
+		"sla",
+		"latency",
+		"created",
+	}
+	slaBreachFilterFieldMap = map[string]string{
+		"type":    "btype",
+		"message": "message_id",
+		"node":    "node_id",
+	}
+)
+
+const slaBreachesTable = "slabreaches"
+
+func (s *SQLCommon) InsertSLABreach(ctx context.Context, breach *core.SLABreach) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if breach.Created == nil {
+		breach.Created = fftypes.Now()
+	}
+	// The SLA and latency are stored as a number of milliseconds
+	if _, err = s.InsertTx(ctx, slaBreachesTable, tx,
+		sq.Insert(slaBreachesTable).
+			Columns(slaBreachColumns...).
+			Values(
+				breach.ID,
+				breach.Namespace,
+				breach.Type,
+				breach.Message,
+				breach.Topic,
+				breach.Org,
+				breach.Node,
+				time.Duration(breach.SLA).Milliseconds(),
+				time.Duration(breach.Latency).Milliseconds(),
+				breach.Created,
+			),
+		nil, // no change events for SLA breaches
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) slaBreachResult(ctx context.Context, row *sql.Rows) (*core.SLABreach, error) {
+	breach := core.SLABreach{}
+	err := row.Scan(
+		&breach.ID,
+		&breach.Namespace,
+		&breach.Type,
+		&breach.Message,
+		&breach.Topic,
+		&breach.Org,
+		&breach.Node,
+		&breach.SLA,
+		&breach.Latency,
+		&breach.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, slaBreachesTable)
+	}
+	return &breach, nil
+}
+
+func (s *SQLCommon) GetSLABreachByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.SLABreach, error) {
+	rows, _, err := s.Query(ctx, slaBreachesTable,
+		sq.Select(slaBreachColumns...).
+			From(slaBreachesTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("SLA breach '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.slaBreachResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetSLABreaches(ctx context.Context, namespace string, filter ffapi.Filter) (breaches []*core.SLABreach, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(slaBreachColumns...).From(slaBreachesTable),
+		filter, slaBreachFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, slaBreachesTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	breaches = []*core.SLABreach{}
+	for rows.Next() {
+		breach, err := s.slaBreachResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		breaches = append(breaches, breach)
+	}
+
+	return breaches, s.QueryRes(ctx, slaBreachesTable, tx, fop, fi), err
+}
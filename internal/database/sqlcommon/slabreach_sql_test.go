@@ -0,0 +1,147 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLABreachesE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	msgID := fftypes.NewUUID()
+	breach := &core.SLABreach{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      core.SLABreachTypeDelivered,
+		Message:   msgID,
+		Topic:     "topic1",
+		Org:       "did:firefly:org/org2",
+		Node:      fftypes.NewUUID(),
+		SLA:       fftypes.FFDuration(5 * time.Second),
+		Latency:   fftypes.FFDuration(7500 * time.Millisecond),
+	}
+	err := s.InsertSLABreach(ctx, breach)
+	assert.NoError(t, err)
+	assert.NotNil(t, breach.Created)
+	breachJson, _ := json.Marshal(&breach)
+
+	// Query back the breach (by ID)
+	breachRead, err := s.GetSLABreachByID(ctx, "ns1", breach.ID)
+	assert.NoError(t, err)
+	breachReadJson, _ := json.Marshal(&breachRead)
+	assert.Equal(t, string(breachJson), string(breachReadJson))
+
+	// Query back the breach (by query filter)
+	fb := database.SLABreachQueryFactory.NewFilter(ctx)
+	breaches, res, err := s.GetSLABreaches(ctx, "ns1", fb.And(
+		fb.Eq("message", msgID),
+		fb.Eq("type", core.SLABreachTypeDelivered),
+		fb.Eq("org", "did:firefly:org/org2"),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(breaches))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	breachReadJson, _ = json.Marshal(breaches[0])
+	assert.Equal(t, string(breachJson), string(breachReadJson))
+
+	// Not found
+	breachRead, err = s.GetSLABreachByID(ctx, "ns2", breach.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, breachRead)
+}
+
+func TestInsertSLABreachFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertSLABreach(context.Background(), &core.SLABreach{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertSLABreachFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertSLABreach(context.Background(), &core.SLABreach{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertSLABreachFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertSLABreach(context.Background(), &core.SLABreach{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSLABreachByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetSLABreachByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSLABreachByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetSLABreachByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSLABreachesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.SLABreachQueryFactory.NewFilter(context.Background()).Eq("message", fftypes.NewUUID())
+	_, _, err := s.GetSLABreaches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSLABreachesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.SLABreachQueryFactory.NewFilter(context.Background()).Eq("org", map[bool]bool{true: false})
+	_, _, err := s.GetSLABreaches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*org", err)
+}
+
+func TestGetSLABreachesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.SLABreachQueryFactory.NewFilter(context.Background()).Eq("org", "")
+	_, _, err := s.GetSLABreaches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
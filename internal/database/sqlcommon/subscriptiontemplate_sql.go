@@ -0,0 +1,174 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	subscriptionTemplateColumns = []string{
+		"id",
+		"namespace",
+		"name",
+		"trigger_type",
+		"match_name",
+		"transport",
+		"filters",
+		"options",
+		"created",
+	}
+	subscriptionTemplateFilterFieldMap = map[string]string{
+		"trigger": "trigger_type",
+		"match":   "match_name",
+	}
+)
+
+const subscriptionTemplatesTable = "subscriptiontemplates"
+
+func (s *SQLCommon) InsertSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if template.Created == nil {
+		template.Created = fftypes.Now()
+	}
+	if _, err = s.InsertTx(ctx, subscriptionTemplatesTable, tx,
+		sq.Insert(subscriptionTemplatesTable).
+			Columns(subscriptionTemplateColumns...).
+			Values(
+				template.ID,
+				template.Namespace,
+				template.Name,
+				template.Trigger,
+				template.Match,
+				template.Transport,
+				template.Filter,
+				template.Options,
+				template.Created,
+			),
+		nil, // no change events for subscription templates
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) subscriptionTemplateResult(ctx context.Context, row *sql.Rows) (*core.SubscriptionTemplate, error) {
+	template := core.SubscriptionTemplate{}
+	var match sql.NullString
+	err := row.Scan(
+		&template.ID,
+		&template.Namespace,
+		&template.Name,
+		&template.Trigger,
+		&match,
+		&template.Transport,
+		&template.Filter,
+		&template.Options,
+		&template.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, subscriptionTemplatesTable)
+	}
+	template.Match = match.String
+	return &template, nil
+}
+
+func (s *SQLCommon) getSubscriptionTemplateEq(ctx context.Context, eq sq.Eq, textName string) (*core.SubscriptionTemplate, error) {
+	rows, _, err := s.Query(ctx, subscriptionTemplatesTable,
+		sq.Select(subscriptionTemplateColumns...).
+			From(subscriptionTemplatesTable).
+			Where(eq),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Subscription template '%s' not found", textName)
+		return nil, nil
+	}
+
+	return s.subscriptionTemplateResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetSubscriptionTemplateByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.SubscriptionTemplate, error) {
+	return s.getSubscriptionTemplateEq(ctx, sq.Eq{"namespace": namespace, "id": id}, id.String())
+}
+
+func (s *SQLCommon) GetSubscriptionTemplateByName(ctx context.Context, namespace, name string) (*core.SubscriptionTemplate, error) {
+	return s.getSubscriptionTemplateEq(ctx, sq.Eq{"namespace": namespace, "name": name}, fmt.Sprintf("%s:%s", namespace, name))
+}
+
+func (s *SQLCommon) GetSubscriptionTemplates(ctx context.Context, namespace string, filter ffapi.Filter) (templates []*core.SubscriptionTemplate, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(subscriptionTemplateColumns...).From(subscriptionTemplatesTable),
+		filter, subscriptionTemplateFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, subscriptionTemplatesTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	templates = []*core.SubscriptionTemplate{}
+	for rows.Next() {
+		t, err := s.subscriptionTemplateResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		templates = append(templates, t)
+	}
+
+	return templates, s.QueryRes(ctx, subscriptionTemplatesTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) DeleteSubscriptionTemplateByID(ctx context.Context, namespace string, id *fftypes.UUID) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, subscriptionTemplatesTable, tx, sq.Delete(subscriptionTemplatesTable).Where(sq.Eq{
+		"namespace": namespace,
+		"id":        id,
+	}), nil)
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
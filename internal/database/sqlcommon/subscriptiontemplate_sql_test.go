@@ -0,0 +1,189 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionTemplateE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	yes := true
+	template := &core.SubscriptionTemplate{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Name:      "pooltransfers",
+		Trigger:   core.SubscriptionTemplateTriggerTokenPool,
+		Match:     "^erc20-",
+		Transport: "webhooks",
+		Filter: core.SubscriptionFilter{
+			Events: "token_transfer_confirmed",
+			TokenTransfer: &core.TokenTransferFilter{
+				Pool: "{{.ID}}",
+			},
+		},
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				WithData: &yes,
+			},
+		},
+	}
+	template.Options.TransportOptions()["url"] = "https://example.com/{{.Name}}"
+	err := s.InsertSubscriptionTemplate(ctx, template)
+	assert.NoError(t, err)
+	assert.NotNil(t, template.Created)
+	templateJson, _ := json.Marshal(&template)
+
+	// Query back the template (by ID)
+	templateRead, err := s.GetSubscriptionTemplateByID(ctx, "ns1", template.ID)
+	assert.NoError(t, err)
+	templateReadJson, _ := json.Marshal(&templateRead)
+	assert.Equal(t, string(templateJson), string(templateReadJson))
+
+	// Query back the template (by name)
+	templateRead, err = s.GetSubscriptionTemplateByName(ctx, "ns1", "pooltransfers")
+	assert.NoError(t, err)
+	templateReadJson, _ = json.Marshal(&templateRead)
+	assert.Equal(t, string(templateJson), string(templateReadJson))
+
+	// Query back the template (by query filter)
+	fb := database.SubscriptionTemplateQueryFactory.NewFilter(ctx)
+	templates, res, err := s.GetSubscriptionTemplates(ctx, "ns1", fb.And(
+		fb.Eq("trigger", core.SubscriptionTemplateTriggerTokenPool),
+		fb.Eq("match", "^erc20-"),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(templates))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	templateReadJson, _ = json.Marshal(templates[0])
+	assert.Equal(t, string(templateJson), string(templateReadJson))
+
+	// Delete the template
+	err = s.DeleteSubscriptionTemplateByID(ctx, "ns1", template.ID)
+	assert.NoError(t, err)
+	templateRead, err = s.GetSubscriptionTemplateByID(ctx, "ns1", template.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, templateRead)
+}
+
+func TestInsertSubscriptionTemplateFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertSubscriptionTemplate(context.Background(), &core.SubscriptionTemplate{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertSubscriptionTemplateFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertSubscriptionTemplate(context.Background(), &core.SubscriptionTemplate{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertSubscriptionTemplateFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertSubscriptionTemplate(context.Background(), &core.SubscriptionTemplate{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubscriptionTemplateByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetSubscriptionTemplateByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubscriptionTemplateByNameNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	template, err := s.GetSubscriptionTemplateByName(context.Background(), "ns1", "template1")
+	assert.NoError(t, err)
+	assert.Nil(t, template)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubscriptionTemplateByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetSubscriptionTemplateByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubscriptionTemplatesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.SubscriptionTemplateQueryFactory.NewFilter(context.Background()).Eq("name", "template1")
+	_, _, err := s.GetSubscriptionTemplates(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSubscriptionTemplatesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.SubscriptionTemplateQueryFactory.NewFilter(context.Background()).Eq("name", map[bool]bool{true: false})
+	_, _, err := s.GetSubscriptionTemplates(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*name", err)
+}
+
+func TestGetSubscriptionTemplatesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.SubscriptionTemplateQueryFactory.NewFilter(context.Background()).Eq("name", "")
+	_, _, err := s.GetSubscriptionTemplates(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSubscriptionTemplateFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteSubscriptionTemplateByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteSubscriptionTemplateFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteSubscriptionTemplateByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
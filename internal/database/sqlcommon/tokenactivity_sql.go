@@ -0,0 +1,139 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// tokenActivityTable is a union of the transfer and approval tables, projected onto a common set of columns.
+// Transfers that have been invalidated by a blockchain re-org are excluded, as they did not affect any balance.
+const tokenActivityTable = "(" +
+	"SELECT type, local_id, pool_id, token_index, connector, namespace, key, from_key, to_key, " +
+	"'' AS operator_key, amount, false AS approved, protocol_id, message_id, tx_type, tx_id, blockchain_event, created " +
+	"FROM " + tokentransferTable + " WHERE invalidated = false " +
+	"UNION ALL " +
+	"SELECT 'approval' AS type, local_id, pool_id, '' AS token_index, connector, namespace, key, key AS from_key, '' AS to_key, " +
+	"operator_key, '' AS amount, approved, protocol_id, message_id, tx_type, tx_id, blockchain_event, created " +
+	"FROM " + tokenapprovalTable +
+	") AS tokenactivity"
+
+var (
+	tokenActivityColumns = []string{
+		"type",
+		"local_id",
+		"pool_id",
+		"token_index",
+		"connector",
+		"namespace",
+		"key",
+		"from_key",
+		"to_key",
+		"operator_key",
+		"amount",
+		"approved",
+		"protocol_id",
+		"message_id",
+		"tx_type",
+		"tx_id",
+		"blockchain_event",
+		"created",
+	}
+	tokenActivityFilterFieldMap = map[string]string{
+		"localid":         "local_id",
+		"pool":            "pool_id",
+		"tokenindex":      "token_index",
+		"from":            "from_key",
+		"to":              "to_key",
+		"operator":        "operator_key",
+		"protocolid":      "protocol_id",
+		"message":         "message_id",
+		"tx.type":         "tx_type",
+		"tx.id":           "tx_id",
+		"blockchainevent": "blockchain_event",
+	}
+)
+
+func (s *SQLCommon) tokenActivityResult(ctx context.Context, row sq.RowScanner) (*core.TokenActivity, error) {
+	activity := core.TokenActivity{}
+	var amount fftypes.FFBigInt
+	var approved bool
+	err := row.Scan(
+		&activity.Type,
+		&activity.LocalID,
+		&activity.Pool,
+		&activity.TokenIndex,
+		&activity.Connector,
+		&activity.Namespace,
+		&activity.Key,
+		&activity.From,
+		&activity.To,
+		&activity.Operator,
+		&amount,
+		&approved,
+		&activity.ProtocolID,
+		&activity.Message,
+		&activity.TX.Type,
+		&activity.TX.ID,
+		&activity.BlockchainEvent,
+		&activity.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, "tokenactivity")
+	}
+	if activity.Type == core.TokenActivityTypeApproval {
+		activity.Approved = &approved
+	} else {
+		activity.Amount = &amount
+	}
+	return &activity, nil
+}
+
+func (s *SQLCommon) GetTokenAccountActivity(ctx context.Context, namespace, key string, filter ffapi.Filter) ([]*core.TokenActivity, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "",
+		sq.Select(tokenActivityColumns...).From(tokenActivityTable),
+		filter, tokenActivityFilterFieldMap, []interface{}{"created"},
+		sq.Eq{"namespace": namespace},
+		sq.Or{sq.Eq{"from_key": key}, sq.Eq{"to_key": key}, sq.Eq{"operator_key": key}})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, "tokenactivity", query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	activity := []*core.TokenActivity{}
+	for rows.Next() {
+		d, err := s.tokenActivityResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		activity = append(activity, d)
+	}
+
+	return activity, s.QueryRes(ctx, tokenActivityTable, tx, fop, fi), err
+}
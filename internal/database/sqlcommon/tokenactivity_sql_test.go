@@ -0,0 +1,168 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTokenActivityE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("UUIDCollectionNSEvent", mock.Anything, mock.Anything, "ns1", mock.Anything, mock.Anything).Return()
+
+	pool := fftypes.NewUUID()
+	mint := &core.TokenTransfer{
+		LocalID:    fftypes.NewUUID(),
+		Type:       core.TokenTransferTypeMint,
+		Pool:       pool,
+		TokenIndex: "1",
+		Connector:  "erc1155",
+		Namespace:  "ns1",
+		To:         "0x01",
+		ProtocolID: "0001",
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenTransfer,
+			ID:   fftypes.NewUUID(),
+		},
+	}
+	mint.Amount.Int().SetInt64(10)
+	_, err := s.InsertOrGetTokenTransfer(ctx, mint)
+	assert.NoError(t, err)
+
+	approval := &core.TokenApproval{
+		LocalID:    fftypes.NewUUID(),
+		Pool:       pool,
+		Connector:  "erc1155",
+		Namespace:  "ns1",
+		Key:        "0x01",
+		Operator:   "0x03",
+		Approved:   true,
+		ProtocolID: "0002",
+		Subject:    "0x01:0x03",
+		Active:     true,
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenApproval,
+			ID:   fftypes.NewUUID(),
+		},
+	}
+	err = s.UpsertTokenApproval(ctx, approval)
+	assert.NoError(t, err)
+
+	transfer := &core.TokenTransfer{
+		LocalID:    fftypes.NewUUID(),
+		Type:       core.TokenTransferTypeTransfer,
+		Pool:       fftypes.NewUUID(),
+		TokenIndex: "1",
+		Connector:  "erc1155",
+		Namespace:  "ns1",
+		Key:        "0x03",
+		From:       "0x01",
+		To:         "0x02",
+		ProtocolID: "0003",
+	}
+	transfer.Amount.Int().SetInt64(5)
+	_, err = s.InsertOrGetTokenTransfer(ctx, transfer)
+	assert.NoError(t, err)
+
+	invalidated := &core.TokenTransfer{
+		LocalID:     fftypes.NewUUID(),
+		Type:        core.TokenTransferTypeBurn,
+		Pool:        pool,
+		Connector:   "erc1155",
+		Namespace:   "ns1",
+		From:        "0x01",
+		ProtocolID:  "0004",
+		Invalidated: true,
+	}
+	_, err = s.InsertOrGetTokenTransfer(ctx, invalidated)
+	assert.NoError(t, err)
+
+	// All activity for the account, newest first
+	fb := database.TokenActivityQueryFactory.NewFilter(ctx)
+	activity, res, err := s.GetTokenAccountActivity(ctx, "ns1", "0x01", fb.And().Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), *res.TotalCount)
+	assert.Equal(t, 3, len(activity))
+	assert.Equal(t, core.TokenActivityTypeTransfer, activity[0].Type)
+	assert.Equal(t, transfer.LocalID, activity[0].LocalID)
+	assert.Equal(t, int64(5), activity[0].Amount.Int().Int64())
+	assert.Nil(t, activity[0].Approved)
+	assert.Equal(t, core.TokenActivityTypeApproval, activity[1].Type)
+	assert.Equal(t, approval.LocalID, activity[1].LocalID)
+	assert.Equal(t, "0x01", activity[1].From)
+	assert.Equal(t, "0x03", activity[1].Operator)
+	assert.True(t, *activity[1].Approved)
+	assert.Nil(t, activity[1].Amount)
+	assert.Equal(t, approval.TX.ID, activity[1].TX.ID)
+	assert.Equal(t, core.TokenActivityTypeMint, activity[2].Type)
+	assert.Equal(t, int64(10), activity[2].Amount.Int().Int64())
+
+	// Operators see the approvals granted to them
+	activity, _, err = s.GetTokenAccountActivity(ctx, "ns1", "0x03", fb.And())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(activity))
+	assert.Equal(t, approval.LocalID, activity[0].LocalID)
+
+	// Filter and page across the combined feed
+	activity, _, err = s.GetTokenAccountActivity(ctx, "ns1", "0x01", fb.And(
+		fb.Eq("pool", pool),
+	).Sort("created").Limit(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(activity))
+	assert.Equal(t, mint.LocalID, activity[0].LocalID)
+
+	activity, _, err = s.GetTokenAccountActivity(ctx, "ns2", "0x01", fb.And())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(activity))
+}
+
+func TestGetTokenAccountActivityQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenActivityQueryFactory.NewFilter(context.Background()).And()
+	_, _, err := s.GetTokenAccountActivity(context.Background(), "ns1", "0x01", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenAccountActivityBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenActivityQueryFactory.NewFilter(context.Background()).Eq("pool", map[bool]bool{true: false})
+	_, _, err := s.GetTokenAccountActivity(context.Background(), "ns1", "0x01", f)
+	assert.Regexp(t, "FF00143.*pool", err)
+}
+
+func TestGetTokenAccountActivityScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"type"}).AddRow("only one"))
+	f := database.TokenActivityQueryFactory.NewFilter(context.Background()).And()
+	_, _, err := s.GetTokenAccountActivity(context.Background(), "ns1", "0x01", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
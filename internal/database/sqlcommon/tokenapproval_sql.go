@@ -49,20 +49,23 @@ var (
 		"created",
 		"message_id",
 		"message_hash",
+		"expiry",
+		"expiry_revocation",
 	}
 	tokenApprovalFilterFieldMap = map[string]string{
-		"localid":         "local_id",
-		"protocolid":      "protocol_id",
-		"pool":            "pool_id",
-		"approved":        "approved",
-		"key":             "key",
-		"operator":        "operator_key",
-		"tx.type":         "tx_type",
-		"tx.id":           "tx_id",
-		"blockchainevent": "blockchain_event",
-		"created":         "created",
-		"message":         "message_id",
-		"messagehash":     "message_hash",
+		"localid":          "local_id",
+		"protocolid":       "protocol_id",
+		"pool":             "pool_id",
+		"approved":         "approved",
+		"key":              "key",
+		"operator":         "operator_key",
+		"tx.type":          "tx_type",
+		"tx.id":            "tx_id",
+		"blockchainevent":  "blockchain_event",
+		"created":          "created",
+		"message":          "message_id",
+		"messagehash":      "message_hash",
+		"expiryrevocation": "expiry_revocation",
 	}
 )
 
@@ -106,6 +109,8 @@ func (s *SQLCommon) UpsertTokenApproval(ctx context.Context, approval *core.Toke
 				Set("blockchain_event", approval.BlockchainEvent).
 				Set("message_id", approval.Message).
 				Set("message_hash", approval.MessageHash).
+				Set("expiry", approval.Expiry).
+				// expiry_revocation is only set by the approval expiry check, so is preserved
 				Where(sq.Eq{"protocol_id": approval.ProtocolID}),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenApprovals, core.ChangeEventTypeUpdated, approval.Namespace, approval.LocalID)
@@ -136,6 +141,8 @@ func (s *SQLCommon) UpsertTokenApproval(ctx context.Context, approval *core.Toke
 					approval.Created,
 					approval.Message,
 					approval.MessageHash,
+					approval.Expiry,
+					approval.ExpiryRevocation,
 				),
 			func() {
 				s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenApprovals, core.ChangeEventTypeCreated, approval.Namespace, approval.LocalID)
@@ -167,6 +174,8 @@ func (s *SQLCommon) tokenApprovalResult(ctx context.Context, row *sql.Rows) (*co
 		&approval.Created,
 		&approval.Message,
 		&approval.MessageHash,
+		&approval.Expiry,
+		&approval.ExpiryRevocation,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenapprovalTable)
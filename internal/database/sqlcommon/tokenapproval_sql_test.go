@@ -46,6 +46,7 @@ func TestApprovalE2EWithDB(t *testing.T) {
 		ProtocolID: "0001/01/01",
 		Subject:    "12345",
 		Active:     true,
+		Expiry:     fftypes.Now(),
 		TX: core.TransactionRef{
 			Type: core.TransactionTypeTokenApproval,
 			ID:   fftypes.NewUUID(),
@@ -56,7 +57,7 @@ func TestApprovalE2EWithDB(t *testing.T) {
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenApprovals, core.ChangeEventTypeCreated, approval.Namespace, approval.LocalID, mock.Anything).
 		Return().Once()
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenApprovals, core.ChangeEventTypeUpdated, approval.Namespace, approval.LocalID, mock.Anything).
-		Return().Once()
+		Return().Twice()
 
 	// Initial list is empty
 	fb := database.TokenApprovalQueryFactory.NewFilter(ctx)
@@ -112,6 +113,18 @@ func TestApprovalE2EWithDB(t *testing.T) {
 	assert.NoError(t, err)
 	approval.Active = false
 
+	// Record the revocation of the approval, which is preserved by later upserts
+	revocation := fftypes.NewUUID()
+	update = database.TokenApprovalQueryFactory.NewUpdate(ctx).Set("expiryrevocation", revocation)
+	err = s.UpdateTokenApprovals(ctx, fb.And(fb.Eq("localid", approval.LocalID)), update)
+	assert.NoError(t, err)
+	err = s.UpsertTokenApproval(ctx, approval)
+	assert.NoError(t, err)
+	approval.ExpiryRevocation = revocation
+	approvals, _, err = s.GetTokenApprovals(ctx, "ns1", fb.And(fb.Eq("expiryrevocation", nil)))
+	assert.NoError(t, err)
+	assert.Empty(t, approvals)
+
 	// Query back token approval by ID
 	approvalRead, err = s.GetTokenApprovalByID(ctx, "ns1", approval.LocalID)
 	assert.NoError(t, err)
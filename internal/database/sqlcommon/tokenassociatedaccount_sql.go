@@ -0,0 +1,147 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenAssociatedAccountColumns = []string{
+		"namespace",
+		"pool_id",
+		"owner",
+		"account",
+		"payer",
+		"tx_type",
+		"tx_id",
+		"blockchain_event",
+		"created",
+	}
+	tokenAssociatedAccountFilterFieldMap = map[string]string{
+		"pool":            "pool_id",
+		"tx.type":         "tx_type",
+		"tx.id":           "tx_id",
+		"blockchainevent": "blockchain_event",
+	}
+)
+
+const tokenassociatedaccountTable = "tokenassociatedaccount"
+
+func (s *SQLCommon) InsertTokenAssociatedAccount(ctx context.Context, account *core.TokenAssociatedAccount) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if account.Created == nil {
+		account.Created = fftypes.Now()
+	}
+	if _, err = s.InsertTx(ctx, tokenassociatedaccountTable, tx,
+		sq.Insert(tokenassociatedaccountTable).
+			Columns(tokenAssociatedAccountColumns...).
+			Values(
+				account.Namespace,
+				account.Pool,
+				account.Owner,
+				account.Account,
+				account.Payer,
+				account.TX.Type,
+				account.TX.ID,
+				account.BlockchainEvent,
+				account.Created,
+			),
+		nil, // no change events for token associated accounts
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenAssociatedAccountResult(ctx context.Context, row *sql.Rows) (*core.TokenAssociatedAccount, error) {
+	account := core.TokenAssociatedAccount{}
+	err := row.Scan(
+		&account.Namespace,
+		&account.Pool,
+		&account.Owner,
+		&account.Account,
+		&account.Payer,
+		&account.TX.Type,
+		&account.TX.ID,
+		&account.BlockchainEvent,
+		&account.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenassociatedaccountTable)
+	}
+	return &account, nil
+}
+
+func (s *SQLCommon) GetTokenAssociatedAccount(ctx context.Context, namespace string, poolID *fftypes.UUID, owner string) (*core.TokenAssociatedAccount, error) {
+	rows, _, err := s.Query(ctx, tokenassociatedaccountTable,
+		sq.Select(tokenAssociatedAccountColumns...).
+			From(tokenassociatedaccountTable).
+			Where(sq.Eq{"namespace": namespace, "pool_id": poolID, "owner": owner}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token associated account for owner '%s' in pool '%s' not found", owner, poolID)
+		return nil, nil
+	}
+
+	return s.tokenAssociatedAccountResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenAssociatedAccounts(ctx context.Context, namespace string, filter ffapi.Filter) (accounts []*core.TokenAssociatedAccount, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenAssociatedAccountColumns...).From(tokenassociatedaccountTable),
+		filter, tokenAssociatedAccountFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenassociatedaccountTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	accounts = []*core.TokenAssociatedAccount{}
+	for rows.Next() {
+		d, err := s.tokenAssociatedAccountResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		accounts = append(accounts, d)
+	}
+
+	return accounts, s.QueryRes(ctx, tokenassociatedaccountTable, tx, fop, fi), err
+}
@@ -0,0 +1,143 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenAssociatedAccountE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new token associated account entry
+	account := &core.TokenAssociatedAccount{
+		Namespace: "ns1",
+		Pool:      fftypes.NewUUID(),
+		Owner:     "7o36UsWR1JQLpZ9PE2gn9L4SQ69CNNiWAXd4Jt7rqz9Z",
+		Account:   "3emsAVdmGKERbHjmGfQ6oZ1e35dkf5iYcS6U4CPKFVaa",
+		Payer:     "7o36UsWR1JQLpZ9PE2gn9L4SQ69CNNiWAXd4Jt7rqz9Z",
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenTransfer,
+			ID:   fftypes.NewUUID(),
+		},
+		BlockchainEvent: fftypes.NewUUID(),
+	}
+	err := s.InsertTokenAssociatedAccount(ctx, account)
+	assert.NoError(t, err)
+	assert.NotNil(t, account.Created)
+	accountJson, _ := json.Marshal(&account)
+
+	// Query back the account (by owner)
+	accountRead, err := s.GetTokenAssociatedAccount(ctx, "ns1", account.Pool, account.Owner)
+	assert.NoError(t, err)
+	assert.NotNil(t, accountRead)
+	accountReadJson, _ := json.Marshal(&accountRead)
+	assert.Equal(t, string(accountJson), string(accountReadJson))
+
+	// Query back the account (by query filter)
+	fb := database.TokenAssociatedAccountQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", account.Pool),
+		fb.Eq("account", account.Account),
+	)
+	accounts, res, err := s.GetTokenAssociatedAccounts(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(accounts))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	accountReadJson, _ = json.Marshal(accounts[0])
+	assert.Equal(t, string(accountJson), string(accountReadJson))
+
+	// Each owner has only one associated account in a pool
+	err = s.InsertTokenAssociatedAccount(ctx, account)
+	assert.Regexp(t, "FF00177", err)
+
+	// Other namespaces do not see the account
+	accountRead, err = s.GetTokenAssociatedAccount(ctx, "ns2", account.Pool, account.Owner)
+	assert.NoError(t, err)
+	assert.Nil(t, accountRead)
+}
+
+func TestInsertTokenAssociatedAccountFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenAssociatedAccount(context.Background(), &core.TokenAssociatedAccount{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenAssociatedAccountFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenAssociatedAccount(context.Background(), &core.TokenAssociatedAccount{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenAssociatedAccountSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenAssociatedAccount(context.Background(), "ns1", fftypes.NewUUID(), "owner1")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenAssociatedAccountScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	_, err := s.GetTokenAssociatedAccount(context.Background(), "ns1", fftypes.NewUUID(), "owner1")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenAssociatedAccountsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenAssociatedAccountQueryFactory.NewFilter(context.Background()).Eq("owner", "")
+	_, _, err := s.GetTokenAssociatedAccounts(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenAssociatedAccountsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenAssociatedAccountQueryFactory.NewFilter(context.Background()).Eq("owner", map[bool]bool{true: false})
+	_, _, err := s.GetTokenAssociatedAccounts(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*owner", err)
+}
+
+func TestGetTokenAssociatedAccountsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	f := database.TokenAssociatedAccountQueryFactory.NewFilter(context.Background()).Eq("owner", "")
+	_, _, err := s.GetTokenAssociatedAccounts(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -260,6 +260,69 @@ func (s *SQLCommon) GetTokenAccountPools(ctx context.Context, namespace, key str
 	return pools, s.QueryRes(ctx, tokenbalanceTable, tx, fop, fi), err
 }
 
+// GetTokenPoolHolders counts the token indexes each key holds in a pool. Balances are stored as hex strings, so are
+// not summed - this is intended for non-fungible pools, where every balance of a token index is one.
+func (s *SQLCommon) GetTokenPoolHolders(ctx context.Context, namespace string, poolID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "",
+		sq.Select("key", "COUNT(*) AS tokens", "MAX(updated) AS updated", "MAX(seq) AS seq").From(tokenbalanceTable).GroupBy("key"),
+		filter, tokenBalanceFilterFieldMap, []interface{}{"seq"},
+		sq.Eq{"pool_id": poolID, "namespace": namespace}, sq.NotEq{"balance": "0"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenbalanceTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	holders := make([]*core.TokenPoolHolder, 0)
+	for rows.Next() {
+		var holder core.TokenPoolHolder
+		var seq int64
+		if err := rows.Scan(&holder.Key, &holder.Tokens, &holder.Updated, &seq); err != nil {
+			return nil, nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenbalanceTable)
+		}
+		holders = append(holders, &holder)
+	}
+
+	return holders, s.QueryRes(ctx, tokenbalanceTable, tx, fop, fi), err
+}
+
+// GetTokenIndexOwnership counts the keys that hold each token index in a pool, including the owning key
+// for any index that has exactly one owner (as is always the case in a non-fungible pool)
+func (s *SQLCommon) GetTokenIndexOwnership(ctx context.Context, namespace string, poolID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "",
+		sq.Select("token_index", "MAX(uri) AS uri", "COUNT(*) AS owners", "MAX(key) AS key", "MAX(updated) AS updated", "MAX(seq) AS seq").From(tokenbalanceTable).GroupBy("token_index"),
+		filter, tokenBalanceFilterFieldMap, []interface{}{"seq"},
+		sq.Eq{"pool_id": poolID, "namespace": namespace}, sq.NotEq{"balance": "0"})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenbalanceTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	indexes := make([]*core.TokenIndexOwnership, 0)
+	for rows.Next() {
+		var index core.TokenIndexOwnership
+		var seq int64
+		if err := rows.Scan(&index.TokenIndex, &index.URI, &index.Owners, &index.Key, &index.Updated, &seq); err != nil {
+			return nil, nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenbalanceTable)
+		}
+		if index.Owners != 1 {
+			index.Key = ""
+		}
+		indexes = append(indexes, &index)
+	}
+
+	return indexes, s.QueryRes(ctx, tokenbalanceTable, tx, fop, fi), err
+}
+
 func (s *SQLCommon) DeleteTokenBalances(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
@@ -132,6 +132,34 @@ func TestTokenBalanceE2EWithDB(t *testing.T) {
 	assert.Equal(t, 1, len(pools))
 	assert.Equal(t, *transfer.Pool, *pools[0].Pool)
 
+	// Query the roll-ups for the pool
+	holders, _, err := s.GetTokenPoolHolders(ctx, "ns1", transfer.Pool, database.TokenPoolHolderQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(holders))
+	assert.Equal(t, "0x1", holders[0].Key)
+	assert.Equal(t, int64(1), holders[0].Tokens)
+	indexes, _, err := s.GetTokenIndexOwnership(ctx, "ns1", transfer.Pool, database.TokenIndexOwnershipQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(indexes))
+	assert.Equal(t, "1", indexes[0].TokenIndex)
+	assert.Equal(t, uri, indexes[0].URI)
+	assert.Equal(t, int64(2), indexes[0].Owners)
+	assert.Equal(t, "", indexes[0].Key)
+
+	// Transfer the remainder, so the token index has a single owner
+	transfer.Amount = *fftypes.NewFFBigInt(5)
+	err = s.UpdateTokenBalances(ctx, transfer)
+	assert.NoError(t, err)
+	holders, _, err = s.GetTokenPoolHolders(ctx, "ns1", transfer.Pool, database.TokenPoolHolderQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(holders))
+	assert.Equal(t, "0x1", holders[0].Key)
+	indexes, _, err = s.GetTokenIndexOwnership(ctx, "ns1", transfer.Pool, database.TokenIndexOwnershipQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(indexes))
+	assert.Equal(t, int64(1), indexes[0].Owners)
+	assert.Equal(t, "0x1", indexes[0].Key)
+
 	// Delete the token balances
 	err = s.DeleteTokenBalances(ctx, "ns1", transfer.Pool)
 	assert.NoError(t, err)
@@ -290,6 +318,56 @@ func TestGetTokenAccountPoolsScanFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetTokenPoolHoldersQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenPoolHolderQueryFactory.NewFilter(context.Background()).And()
+	_, _, err := s.GetTokenPoolHolders(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenPoolHoldersBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenPoolHolderQueryFactory.NewFilter(context.Background()).Eq("key", map[bool]bool{true: false})
+	_, _, err := s.GetTokenPoolHolders(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF00143.*key", err)
+}
+
+func TestGetTokenPoolHoldersScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"key", "bad"}).AddRow("too many", "columns"))
+	f := database.TokenPoolHolderQueryFactory.NewFilter(context.Background()).And()
+	_, _, err := s.GetTokenPoolHolders(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenIndexOwnershipQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenIndexOwnershipQueryFactory.NewFilter(context.Background()).And()
+	_, _, err := s.GetTokenIndexOwnership(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenIndexOwnershipBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenIndexOwnershipQueryFactory.NewFilter(context.Background()).Eq("tokenindex", map[bool]bool{true: false})
+	_, _, err := s.GetTokenIndexOwnership(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF00143.*tokenindex", err)
+}
+
+func TestGetTokenIndexOwnershipScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"token_index", "bad"}).AddRow("too many", "columns"))
+	f := database.TokenIndexOwnershipQueryFactory.NewFilter(context.Background()).And()
+	_, _, err := s.GetTokenIndexOwnership(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteTokenBalancesFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
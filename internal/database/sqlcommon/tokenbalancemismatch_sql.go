@@ -0,0 +1,220 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenBalanceMismatchColumns = []string{
+		"id",
+		"namespace",
+		"pool_id",
+		"token_index",
+		"key",
+		"balance",
+		"chain_balance",
+		"created",
+		"updated",
+	}
+	tokenBalanceMismatchFilterFieldMap = map[string]string{
+		"pool":         "pool_id",
+		"tokenindex":   "token_index",
+		"chainbalance": "chain_balance",
+	}
+)
+
+const tokenbalancemismatchTable = "tokenbalancemismatch"
+
+// UpsertTokenBalanceMismatch records the latest mismatch for an account. If there is already a mismatch recorded
+// for the account, its ID and creation time are retained and set on the supplied mismatch.
+func (s *SQLCommon) UpsertTokenBalanceMismatch(ctx context.Context, mismatch *core.TokenBalanceMismatch) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	account := sq.Eq{
+		"namespace":   mismatch.Namespace,
+		"pool_id":     mismatch.Pool,
+		"token_index": mismatch.TokenIndex,
+		"key":         mismatch.Key,
+	}
+	rows, _, err := s.QueryTx(ctx, tokenbalancemismatchTable, tx,
+		sq.Select("id", "created").
+			From(tokenbalancemismatchTable).
+			Where(account),
+	)
+	if err != nil {
+		return err
+	}
+	existing := rows.Next()
+	if existing {
+		err = rows.Scan(&mismatch.ID, &mismatch.Created)
+	}
+	rows.Close()
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenbalancemismatchTable)
+	}
+
+	mismatch.Updated = fftypes.Now()
+	if existing {
+		if _, err = s.UpdateTx(ctx, tokenbalancemismatchTable, tx,
+			sq.Update(tokenbalancemismatchTable).
+				Set("balance", mismatch.Balance).
+				Set("chain_balance", mismatch.ChainBalance).
+				Set("updated", mismatch.Updated).
+				Where(account),
+			nil, // no change events for token balance mismatches
+		); err != nil {
+			return err
+		}
+	} else {
+		mismatch.Created = mismatch.Updated
+		if _, err = s.InsertTx(ctx, tokenbalancemismatchTable, tx,
+			sq.Insert(tokenbalancemismatchTable).
+				Columns(tokenBalanceMismatchColumns...).
+				Values(
+					mismatch.ID,
+					mismatch.Namespace,
+					mismatch.Pool,
+					mismatch.TokenIndex,
+					mismatch.Key,
+					mismatch.Balance,
+					mismatch.ChainBalance,
+					mismatch.Created,
+					mismatch.Updated,
+				),
+			nil, // no change events for token balance mismatches
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenBalanceMismatchResult(ctx context.Context, row *sql.Rows) (*core.TokenBalanceMismatch, error) {
+	mismatch := core.TokenBalanceMismatch{}
+	err := row.Scan(
+		&mismatch.ID,
+		&mismatch.Namespace,
+		&mismatch.Pool,
+		&mismatch.TokenIndex,
+		&mismatch.Key,
+		&mismatch.Balance,
+		&mismatch.ChainBalance,
+		&mismatch.Created,
+		&mismatch.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenbalancemismatchTable)
+	}
+	return &mismatch, nil
+}
+
+func (s *SQLCommon) getTokenBalanceMismatchPred(ctx context.Context, desc string, pred interface{}) (*core.TokenBalanceMismatch, error) {
+	rows, _, err := s.Query(ctx, tokenbalancemismatchTable,
+		sq.Select(tokenBalanceMismatchColumns...).
+			From(tokenbalancemismatchTable).
+			Where(pred),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token balance mismatch '%s' not found", desc)
+		return nil, nil
+	}
+
+	return s.tokenBalanceMismatchResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenBalanceMismatch(ctx context.Context, namespace string, poolID *fftypes.UUID, tokenIndex, key string) (*core.TokenBalanceMismatch, error) {
+	desc := core.TokenBalanceIdentifier(poolID, tokenIndex, key)
+	return s.getTokenBalanceMismatchPred(ctx, desc, sq.Eq{
+		"namespace":   namespace,
+		"pool_id":     poolID,
+		"token_index": tokenIndex,
+		"key":         key,
+	})
+}
+
+func (s *SQLCommon) GetTokenBalanceMismatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBalanceMismatch, error) {
+	return s.getTokenBalanceMismatchPred(ctx, id.String(), sq.Eq{"namespace": namespace, "id": id})
+}
+
+func (s *SQLCommon) GetTokenBalanceMismatches(ctx context.Context, namespace string, filter ffapi.Filter) (mismatches []*core.TokenBalanceMismatch, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenBalanceMismatchColumns...).From(tokenbalancemismatchTable),
+		filter, tokenBalanceMismatchFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenbalancemismatchTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	mismatches = []*core.TokenBalanceMismatch{}
+	for rows.Next() {
+		d, err := s.tokenBalanceMismatchResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		mismatches = append(mismatches, d)
+	}
+
+	return mismatches, s.QueryRes(ctx, tokenbalancemismatchTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) DeleteTokenBalanceMismatch(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	return s.deleteTokenBalanceMismatches(ctx, sq.Eq{"namespace": namespace, "id": id})
+}
+
+func (s *SQLCommon) DeleteTokenBalanceMismatches(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
+	return s.deleteTokenBalanceMismatches(ctx, sq.Eq{"namespace": namespace, "pool_id": poolID})
+}
+
+func (s *SQLCommon) deleteTokenBalanceMismatches(ctx context.Context, pred sq.Eq) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, tokenbalancemismatchTable, tx, sq.Delete(tokenbalancemismatchTable).Where(pred), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,224 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBalanceMismatchE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	mismatch := &core.TokenBalanceMismatch{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Pool:       fftypes.NewUUID(),
+		TokenIndex: "1",
+		Key:        "0x01",
+	}
+	mismatch.Balance.Int().SetInt64(10)
+	mismatch.ChainBalance.Int().SetInt64(5)
+	err := s.UpsertTokenBalanceMismatch(ctx, mismatch)
+	assert.NoError(t, err)
+	assert.NotNil(t, mismatch.Created)
+	mismatchJson, _ := json.Marshal(&mismatch)
+
+	// Query back the mismatch (by account)
+	mismatchRead, err := s.GetTokenBalanceMismatch(ctx, "ns1", mismatch.Pool, "1", "0x01")
+	assert.NoError(t, err)
+	mismatchReadJson, _ := json.Marshal(&mismatchRead)
+	assert.Equal(t, string(mismatchJson), string(mismatchReadJson))
+
+	// Query back the mismatch (by ID)
+	mismatchRead, err = s.GetTokenBalanceMismatchByID(ctx, "ns1", mismatch.ID)
+	assert.NoError(t, err)
+	mismatchReadJson, _ = json.Marshal(&mismatchRead)
+	assert.Equal(t, string(mismatchJson), string(mismatchReadJson))
+
+	// Upsert again for the same account, which keeps the original ID and creation time
+	updated := &core.TokenBalanceMismatch{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Pool:       mismatch.Pool,
+		TokenIndex: "1",
+		Key:        "0x01",
+	}
+	updated.Balance.Int().SetInt64(10)
+	updated.ChainBalance.Int().SetInt64(7)
+	err = s.UpsertTokenBalanceMismatch(ctx, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, *mismatch.ID, *updated.ID)
+	assert.Equal(t, mismatch.Created.String(), updated.Created.String())
+
+	// Query back the mismatch (by query filter)
+	fb := database.TokenBalanceMismatchQueryFactory.NewFilter(ctx)
+	mismatches, res, err := s.GetTokenBalanceMismatches(ctx, "ns1", fb.And(
+		fb.Eq("pool", mismatch.Pool),
+		fb.Eq("key", "0x01"),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mismatches))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	assert.Equal(t, int64(7), mismatches[0].ChainBalance.Int().Int64())
+
+	// Delete the mismatch
+	err = s.DeleteTokenBalanceMismatch(ctx, "ns1", mismatch.ID)
+	assert.NoError(t, err)
+	mismatchRead, err = s.GetTokenBalanceMismatchByID(ctx, "ns1", mismatch.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, mismatchRead)
+
+	// Delete all mismatches for the pool
+	err = s.UpsertTokenBalanceMismatch(ctx, mismatch)
+	assert.NoError(t, err)
+	err = s.DeleteTokenBalanceMismatches(ctx, "ns1", mismatch.Pool)
+	assert.NoError(t, err)
+	mismatches, _, err = s.GetTokenBalanceMismatches(ctx, "ns1", fb.And())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(mismatches))
+}
+
+func TestUpsertTokenBalanceMismatchFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceMismatchFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceMismatchFailScan(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceMismatchFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceMismatchFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id", "created"}).AddRow(fftypes.NewUUID().String(), 0))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenBalanceMismatchFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenBalanceMismatch(context.Background(), &core.TokenBalanceMismatch{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenBalanceMismatch(context.Background(), "ns1", fftypes.NewUUID(), "1", "0x01")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetTokenBalanceMismatchByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background()).Eq("key", "")
+	_, _, err := s.GetTokenBalanceMismatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBalanceMismatchesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background()).Eq("key", map[bool]bool{true: false})
+	_, _, err := s.GetTokenBalanceMismatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*key", err)
+}
+
+func TestGetTokenBalanceMismatchesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenBalanceMismatchQueryFactory.NewFilter(context.Background()).Eq("key", "")
+	_, _, err := s.GetTokenBalanceMismatches(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTokenBalanceMismatchFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteTokenBalanceMismatch(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTokenBalanceMismatchesFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteTokenBalanceMismatches(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
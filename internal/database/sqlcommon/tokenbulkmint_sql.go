@@ -0,0 +1,199 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenBulkMintColumns = []string{
+		"id",
+		"namespace",
+		"pool_id",
+		"connector",
+		"state",
+		"key",
+		"to_key",
+		"items",
+		"batch_size",
+		"total",
+		"submitted",
+		"succeeded",
+		"failed",
+		"config",
+		"tx_type",
+		"tx_id",
+		"operation_id",
+		"created",
+		"updated",
+	}
+	tokenBulkMintFilterFieldMap = map[string]string{
+		"pool":      "pool_id",
+		"to":        "to_key",
+		"batchsize": "batch_size",
+		"tx.type":   "tx_type",
+		"tx.id":     "tx_id",
+		"operation": "operation_id",
+	}
+)
+
+const tokenbulkmintTable = "tokenbulkmint"
+
+func (s *SQLCommon) InsertTokenBulkMint(ctx context.Context, bulkMint *core.TokenBulkMint) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	bulkMint.Created = fftypes.Now()
+	bulkMint.Updated = bulkMint.Created
+	if _, err = s.InsertTx(ctx, tokenbulkmintTable, tx,
+		sq.Insert(tokenbulkmintTable).
+			Columns(tokenBulkMintColumns...).
+			Values(
+				bulkMint.ID,
+				bulkMint.Namespace,
+				bulkMint.Pool,
+				bulkMint.Connector,
+				bulkMint.State,
+				bulkMint.Key,
+				bulkMint.To,
+				bulkMint.Items,
+				bulkMint.BatchSize,
+				bulkMint.Total,
+				bulkMint.Submitted,
+				bulkMint.Succeeded,
+				bulkMint.Failed,
+				bulkMint.Config,
+				bulkMint.TX.Type,
+				bulkMint.TX.ID,
+				bulkMint.Operation,
+				bulkMint.Created,
+				bulkMint.Updated,
+			),
+		nil, // no change events for bulk mints
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) UpdateTokenBulkMint(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(tokenbulkmintTable), update, tokenBulkMintFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	if _, err = s.UpdateTx(ctx, tokenbulkmintTable, tx, query, nil /* no change events for bulk mints */); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenBulkMintResult(ctx context.Context, row *sql.Rows) (*core.TokenBulkMint, error) {
+	bulkMint := core.TokenBulkMint{}
+	err := row.Scan(
+		&bulkMint.ID,
+		&bulkMint.Namespace,
+		&bulkMint.Pool,
+		&bulkMint.Connector,
+		&bulkMint.State,
+		&bulkMint.Key,
+		&bulkMint.To,
+		&bulkMint.Items,
+		&bulkMint.BatchSize,
+		&bulkMint.Total,
+		&bulkMint.Submitted,
+		&bulkMint.Succeeded,
+		&bulkMint.Failed,
+		&bulkMint.Config,
+		&bulkMint.TX.Type,
+		&bulkMint.TX.ID,
+		&bulkMint.Operation,
+		&bulkMint.Created,
+		&bulkMint.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenbulkmintTable)
+	}
+	return &bulkMint, nil
+}
+
+func (s *SQLCommon) GetTokenBulkMintByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBulkMint, error) {
+	rows, _, err := s.Query(ctx, tokenbulkmintTable,
+		sq.Select(tokenBulkMintColumns...).
+			From(tokenbulkmintTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Bulk mint '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.tokenBulkMintResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenBulkMints(ctx context.Context, namespace string, filter ffapi.Filter) (bulkMints []*core.TokenBulkMint, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenBulkMintColumns...).From(tokenbulkmintTable),
+		filter, tokenBulkMintFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenbulkmintTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	bulkMints = []*core.TokenBulkMint{}
+	for rows.Next() {
+		d, err := s.tokenBulkMintResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		bulkMints = append(bulkMints, d)
+	}
+
+	return bulkMints, s.QueryRes(ctx, tokenbulkmintTable, tx, fop, fi), err
+}
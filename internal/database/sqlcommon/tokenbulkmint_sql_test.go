@@ -0,0 +1,199 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBulkMintE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new bulk mint entry
+	bulkMint := &core.TokenBulkMint{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Pool:      fftypes.NewUUID(),
+		Connector: "erc721",
+		State:     core.TokenBulkMintStatePending,
+		Key:       "0x12345",
+		To:        "0x23456",
+		Items: core.TokenBulkMintItems{
+			{TokenIndex: "1", URI: "ipfs://drop/1"},
+			{TokenIndex: "2", URI: "ipfs://drop/2"},
+		},
+		BatchSize: 1,
+		Total:     2,
+		Config:    fftypes.JSONObject{"gas": "100"},
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenTransfer,
+			ID:   fftypes.NewUUID(),
+		},
+		Operation: fftypes.NewUUID(),
+	}
+	err := s.InsertTokenBulkMint(ctx, bulkMint)
+	assert.NoError(t, err)
+	assert.NotNil(t, bulkMint.Created)
+	bulkMintJson, _ := json.Marshal(&bulkMint)
+
+	// Query back the bulk mint (by ID)
+	bulkMintRead, err := s.GetTokenBulkMintByID(ctx, "ns1", bulkMint.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, bulkMintRead)
+	bulkMintReadJson, _ := json.Marshal(&bulkMintRead)
+	assert.Equal(t, string(bulkMintJson), string(bulkMintReadJson))
+
+	// Query back the bulk mint (by query filter)
+	fb := database.TokenBulkMintQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", bulkMint.Pool),
+		fb.Eq("state", core.TokenBulkMintStatePending),
+		fb.Eq("to", "0x23456"),
+		fb.Eq("operation", bulkMint.Operation),
+	)
+	bulkMints, res, err := s.GetTokenBulkMints(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(bulkMints))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	bulkMintReadJson, _ = json.Marshal(bulkMints[0])
+	assert.Equal(t, string(bulkMintJson), string(bulkMintReadJson))
+
+	// Update the progress
+	up := database.TokenBulkMintQueryFactory.NewUpdate(ctx).
+		Set("state", core.TokenBulkMintStateCompleted).
+		Set("submitted", 2).
+		Set("succeeded", 2)
+	err = s.UpdateTokenBulkMint(ctx, "ns1", bulkMint.ID, up)
+	assert.NoError(t, err)
+	bulkMintRead, err = s.GetTokenBulkMintByID(ctx, "ns1", bulkMint.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenBulkMintStateCompleted, bulkMintRead.State)
+	assert.Equal(t, 2, bulkMintRead.Submitted)
+	assert.Equal(t, 2, bulkMintRead.Succeeded)
+
+	// Other namespaces do not see the bulk mint
+	bulkMintRead, err = s.GetTokenBulkMintByID(ctx, "ns2", bulkMint.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, bulkMintRead)
+}
+
+func TestInsertTokenBulkMintFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenBulkMint(context.Background(), &core.TokenBulkMint{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenBulkMintFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertTokenBulkMint(context.Background(), &core.TokenBulkMint{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenBulkMintFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenBulkMint(context.Background(), &core.TokenBulkMint{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTokenBulkMintBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.TokenBulkMintQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenBulkMintStateCompleted)
+	err := s.UpdateTokenBulkMint(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateTokenBulkMintBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.TokenBulkMintQueryFactory.NewUpdate(context.Background()).Set("state", map[bool]bool{true: false})
+	err := s.UpdateTokenBulkMint(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestUpdateTokenBulkMintFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.TokenBulkMintQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenBulkMintStateCompleted)
+	err := s.UpdateTokenBulkMint(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+}
+
+func TestGetTokenBulkMintByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenBulkMintByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBulkMintByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetTokenBulkMintByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBulkMintsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenBulkMintQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenBulkMints(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenBulkMintsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenBulkMintQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	_, _, err := s.GetTokenBulkMints(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestGetTokenBulkMintsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenBulkMintQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenBulkMints(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
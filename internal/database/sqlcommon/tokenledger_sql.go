@@ -0,0 +1,153 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenLedgerColumns = []string{
+		"id",
+		"namespace",
+		"pool_id",
+		"account",
+		"counterparty",
+		"token_index",
+		"transfer_type",
+		"transfer_id",
+		"debit",
+		"credit",
+		"balance",
+		"reversal",
+		"tx_type",
+		"tx_id",
+		"blockchain_event",
+		"created",
+	}
+	tokenLedgerFilterFieldMap = map[string]string{
+		"pool":            "pool_id",
+		"tokenindex":      "token_index",
+		"transfertype":    "transfer_type",
+		"transfer":        "transfer_id",
+		"tx.type":         "tx_type",
+		"tx.id":           "tx_id",
+		"blockchainevent": "blockchain_event",
+	}
+)
+
+const tokenledgerTable = "tokenledger"
+
+func (s *SQLCommon) InsertTokenLedgerEntry(ctx context.Context, entry *core.TokenLedgerEntry) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	entry.Created = fftypes.Now()
+	if entry.Sequence, err = s.InsertTx(ctx, tokenledgerTable, tx,
+		sq.Insert(tokenledgerTable).
+			Columns(tokenLedgerColumns...).
+			Values(
+				entry.ID,
+				entry.Namespace,
+				entry.Pool,
+				entry.Account,
+				entry.Counterparty,
+				entry.TokenIndex,
+				entry.TransferType,
+				entry.Transfer,
+				entry.Debit,
+				entry.Credit,
+				entry.Balance,
+				entry.Reversal,
+				entry.TX.Type,
+				entry.TX.ID,
+				entry.BlockchainEvent,
+				entry.Created,
+			),
+		nil, // no change events for ledger entries
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenLedgerResult(ctx context.Context, row *sql.Rows) (*core.TokenLedgerEntry, error) {
+	entry := core.TokenLedgerEntry{}
+	err := row.Scan(
+		&entry.ID,
+		&entry.Namespace,
+		&entry.Pool,
+		&entry.Account,
+		&entry.Counterparty,
+		&entry.TokenIndex,
+		&entry.TransferType,
+		&entry.Transfer,
+		&entry.Debit,
+		&entry.Credit,
+		&entry.Balance,
+		&entry.Reversal,
+		&entry.TX.Type,
+		&entry.TX.ID,
+		&entry.BlockchainEvent,
+		&entry.Created,
+		// Must be added to the list of columns in all selects
+		&entry.Sequence,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenledgerTable)
+	}
+	return &entry, nil
+}
+
+func (s *SQLCommon) GetTokenLedgerEntries(ctx context.Context, namespace string, filter ffapi.Filter) (entries []*core.TokenLedgerEntry, fr *ffapi.FilterResult, err error) {
+	cols := append([]string{}, tokenLedgerColumns...)
+	cols = append(cols, s.SequenceColumn())
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(tokenledgerTable),
+		filter, tokenLedgerFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenledgerTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	entries = []*core.TokenLedgerEntry{}
+	for rows.Next() {
+		d, err := s.tokenLedgerResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		entries = append(entries, d)
+	}
+
+	return entries, s.QueryRes(ctx, tokenledgerTable, tx, fop, fi), err
+}
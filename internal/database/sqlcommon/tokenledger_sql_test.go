@@ -0,0 +1,147 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenLedgerE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create the debit and credit entries for a transfer
+	pool := fftypes.NewUUID()
+	transfer := fftypes.NewUUID()
+	debit := &core.TokenLedgerEntry{
+		ID:           fftypes.NewUUID(),
+		Namespace:    "ns1",
+		Pool:         pool,
+		Account:      "0x12345",
+		Counterparty: "0x23456",
+		TokenIndex:   "1",
+		TransferType: core.TokenTransferTypeTransfer,
+		Transfer:     transfer,
+		Debit:        *fftypes.NewFFBigInt(10),
+		Balance:      *fftypes.NewFFBigInt(90),
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenTransfer,
+			ID:   fftypes.NewUUID(),
+		},
+		BlockchainEvent: fftypes.NewUUID(),
+	}
+	credit := *debit
+	credit.ID = fftypes.NewUUID()
+	credit.Account, credit.Counterparty = debit.Counterparty, debit.Account
+	credit.Debit = *fftypes.NewFFBigInt(0)
+	credit.Credit = *fftypes.NewFFBigInt(10)
+	credit.Balance = *fftypes.NewFFBigInt(10)
+	err := s.InsertTokenLedgerEntry(ctx, debit)
+	assert.NoError(t, err)
+	assert.NotNil(t, debit.Created)
+	err = s.InsertTokenLedgerEntry(ctx, &credit)
+	assert.NoError(t, err)
+	assert.Greater(t, credit.Sequence, debit.Sequence)
+	debitJson, _ := json.Marshal(&debit)
+
+	// Query back the entries for the transfer
+	fb := database.TokenLedgerQueryFactory.NewFilter(ctx)
+	entries, res, err := s.GetTokenLedgerEntries(ctx, "ns1", fb.And(fb.Eq("transfer", transfer)).Sort("sequence").Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(entries))
+	assert.Equal(t, int64(2), *res.TotalCount)
+	entryReadJson, _ := json.Marshal(entries[0])
+	assert.Equal(t, string(debitJson), string(entryReadJson))
+
+	// Query back the latest entry for an account
+	entries, _, err = s.GetTokenLedgerEntries(ctx, "ns1", fb.And(
+		fb.Eq("pool", pool),
+		fb.Eq("account", "0x23456"),
+	).Sort("-sequence").Limit(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, int64(10), entries[0].Balance.Int64())
+
+	// Other namespaces do not see the entries
+	entries, _, err = s.GetTokenLedgerEntries(ctx, "ns2", fb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestInsertTokenLedgerEntryFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenLedgerEntry(context.Background(), &core.TokenLedgerEntry{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenLedgerEntryFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertTokenLedgerEntry(context.Background(), &core.TokenLedgerEntry{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenLedgerEntryFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenLedgerEntry(context.Background(), &core.TokenLedgerEntry{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenLedgerEntriesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenLedgerQueryFactory.NewFilter(context.Background()).Eq("account", "")
+	_, _, err := s.GetTokenLedgerEntries(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenLedgerEntriesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenLedgerQueryFactory.NewFilter(context.Background()).Eq("account", map[bool]bool{true: false})
+	_, _, err := s.GetTokenLedgerEntries(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*account", err)
+}
+
+func TestGetTokenLedgerEntriesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenLedgerQueryFactory.NewFilter(context.Background()).Eq("account", "")
+	_, _, err := s.GetTokenLedgerEntries(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
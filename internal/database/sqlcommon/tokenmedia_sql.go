@@ -0,0 +1,112 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenMediaColumns = []string{
+		"namespace",
+		"uri",
+		"content_type",
+		"size",
+		"hash",
+		"payload_ref",
+		"content",
+		"created",
+	}
+)
+
+const tokenmediaTable = "tokenmedia"
+
+func (s *SQLCommon) InsertTokenMedia(ctx context.Context, media *core.TokenMedia) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if media.Created == nil {
+		media.Created = fftypes.Now()
+	}
+	if _, err = s.InsertTx(ctx, tokenmediaTable, tx,
+		sq.Insert(tokenmediaTable).
+			Columns(tokenMediaColumns...).
+			Values(
+				media.Namespace,
+				media.URI,
+				media.ContentType,
+				media.Size,
+				media.Hash,
+				media.PayloadRef,
+				media.Content,
+				media.Created,
+			),
+		nil, // no change events for token media
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenMediaResult(ctx context.Context, row *sql.Rows) (*core.TokenMedia, error) {
+	media := core.TokenMedia{}
+	err := row.Scan(
+		&media.Namespace,
+		&media.URI,
+		&media.ContentType,
+		&media.Size,
+		&media.Hash,
+		&media.PayloadRef,
+		&media.Content,
+		&media.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenmediaTable)
+	}
+	return &media, nil
+}
+
+func (s *SQLCommon) GetTokenMediaByURI(ctx context.Context, namespace, uri string) (*core.TokenMedia, error) {
+	rows, _, err := s.Query(ctx, tokenmediaTable,
+		sq.Select(tokenMediaColumns...).
+			From(tokenmediaTable).
+			Where(sq.Eq{"namespace": namespace, "uri": uri}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token media '%s' not found", uri)
+		return nil, nil
+	}
+
+	return s.tokenMediaResult(ctx, rows)
+}
@@ -0,0 +1,118 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenMediaE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new token media entry, with the content held in the database
+	content := []byte("some image")
+	media := &core.TokenMedia{
+		Namespace:   "ns1",
+		URI:         "ipfs://QmImage",
+		ContentType: "image/png",
+		Size:        int64(len(content)),
+		Hash:        fftypes.HashString(string(content)),
+		Content:     content,
+	}
+	err := s.InsertTokenMedia(ctx, media)
+	assert.NoError(t, err)
+	assert.NotNil(t, media.Created)
+	mediaJson, _ := json.Marshal(&media)
+
+	// Query back the media
+	mediaRead, err := s.GetTokenMediaByURI(ctx, "ns1", media.URI)
+	assert.NoError(t, err)
+	assert.NotNil(t, mediaRead)
+	mediaReadJson, _ := json.Marshal(&mediaRead)
+	assert.Equal(t, string(mediaJson), string(mediaReadJson))
+	assert.Equal(t, content, mediaRead.Content)
+
+	// Create a new token media entry, with the content held in shared storage
+	media2 := &core.TokenMedia{
+		Namespace:   "ns1",
+		URI:         "https://example.com/image.gif",
+		ContentType: "image/gif",
+		Size:        int64(len(content)),
+		Hash:        fftypes.HashString(string(content)),
+		PayloadRef:  "QmPayload",
+	}
+	err = s.InsertTokenMedia(ctx, media2)
+	assert.NoError(t, err)
+	mediaRead, err = s.GetTokenMediaByURI(ctx, "ns1", media2.URI)
+	assert.NoError(t, err)
+	assert.Equal(t, "QmPayload", mediaRead.PayloadRef)
+	assert.Empty(t, mediaRead.Content)
+
+	// Each URI is only cached once
+	err = s.InsertTokenMedia(ctx, media)
+	assert.Regexp(t, "FF00177", err)
+
+	// Other namespaces do not see the media
+	mediaRead, err = s.GetTokenMediaByURI(ctx, "ns2", media.URI)
+	assert.NoError(t, err)
+	assert.Nil(t, mediaRead)
+}
+
+func TestInsertTokenMediaFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenMedia(context.Background(), &core.TokenMedia{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenMediaFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenMedia(context.Background(), &core.TokenMedia{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenMediaByURISelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenMediaByURI(context.Background(), "ns1", "ipfs://QmImage")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenMediaByURIScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	_, err := s.GetTokenMediaByURI(context.Background(), "ns1", "ipfs://QmImage")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,170 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenMetadataColumns = []string{
+		"namespace",
+		"uri",
+		"name",
+		"description",
+		"image",
+		"content",
+		"error",
+		"updated",
+	}
+	tokenMetadataFilterFieldMap = map[string]string{}
+)
+
+const tokenmetadataTable = "tokenmetadata"
+
+func (s *SQLCommon) UpsertTokenMetadata(ctx context.Context, metadata *core.TokenMetadata) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	rows, _, err := s.QueryTx(ctx, tokenmetadataTable, tx,
+		sq.Select("seq").
+			From(tokenmetadataTable).
+			Where(sq.Eq{
+				"namespace": metadata.Namespace,
+				"uri":       metadata.URI,
+			}),
+	)
+	if err != nil {
+		return err
+	}
+	existing := rows.Next()
+	rows.Close()
+
+	metadata.Updated = fftypes.Now()
+	if existing {
+		if _, err = s.UpdateTx(ctx, tokenmetadataTable, tx,
+			sq.Update(tokenmetadataTable).
+				Set("name", metadata.Name).
+				Set("description", metadata.Description).
+				Set("image", metadata.Image).
+				Set("content", metadata.Content).
+				Set("error", metadata.Error).
+				Set("updated", metadata.Updated).
+				Where(sq.Eq{
+					"namespace": metadata.Namespace,
+					"uri":       metadata.URI,
+				}),
+			nil, // no change events for token metadata
+		); err != nil {
+			return err
+		}
+	} else {
+		if _, err = s.InsertTx(ctx, tokenmetadataTable, tx,
+			sq.Insert(tokenmetadataTable).
+				Columns(tokenMetadataColumns...).
+				Values(
+					metadata.Namespace,
+					metadata.URI,
+					metadata.Name,
+					metadata.Description,
+					metadata.Image,
+					metadata.Content,
+					metadata.Error,
+					metadata.Updated,
+				),
+			nil, // no change events for token metadata
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenMetadataResult(ctx context.Context, row *sql.Rows) (*core.TokenMetadata, error) {
+	metadata := core.TokenMetadata{}
+	err := row.Scan(
+		&metadata.Namespace,
+		&metadata.URI,
+		&metadata.Name,
+		&metadata.Description,
+		&metadata.Image,
+		&metadata.Content,
+		&metadata.Error,
+		&metadata.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenmetadataTable)
+	}
+	return &metadata, nil
+}
+
+func (s *SQLCommon) GetTokenMetadataByURI(ctx context.Context, namespace, uri string) (*core.TokenMetadata, error) {
+	rows, _, err := s.Query(ctx, tokenmetadataTable,
+		sq.Select(tokenMetadataColumns...).
+			From(tokenmetadataTable).
+			Where(sq.Eq{"namespace": namespace, "uri": uri}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token metadata '%s' not found", uri)
+		return nil, nil
+	}
+
+	return s.tokenMetadataResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenMetadata(ctx context.Context, namespace string, filter ffapi.Filter) (metadata []*core.TokenMetadata, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenMetadataColumns...).From(tokenmetadataTable),
+		filter, tokenMetadataFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenmetadataTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	metadata = []*core.TokenMetadata{}
+	for rows.Next() {
+		d, err := s.tokenMetadataResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		metadata = append(metadata, d)
+	}
+
+	return metadata, s.QueryRes(ctx, tokenmetadataTable, tx, fop, fi), err
+}
@@ -0,0 +1,177 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenMetadataE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Cache a failed fetch
+	metadata := &core.TokenMetadata{
+		URI:       "https://example.com/token/1",
+		Namespace: "ns1",
+		Error:     "pop",
+	}
+	err := s.UpsertTokenMetadata(ctx, metadata)
+	assert.NoError(t, err)
+	assert.NotNil(t, metadata.Updated)
+
+	// Replace it with a successful fetch
+	metadata = &core.TokenMetadata{
+		URI:         "https://example.com/token/1",
+		Namespace:   "ns1",
+		Name:        "Token 1",
+		Description: "The first token",
+		Image:       "ipfs://QmImage",
+		Content:     fftypes.JSONAnyPtr(`{"name":"Token 1","description":"The first token","image":"ipfs://QmImage"}`),
+	}
+	err = s.UpsertTokenMetadata(ctx, metadata)
+	assert.NoError(t, err)
+	metadataJson, _ := json.Marshal(&metadata)
+
+	// Query back the metadata (by URI)
+	metadataRead, err := s.GetTokenMetadataByURI(ctx, "ns1", metadata.URI)
+	assert.NoError(t, err)
+	assert.NotNil(t, metadataRead)
+	metadataReadJson, _ := json.Marshal(&metadataRead)
+	assert.Equal(t, string(metadataJson), string(metadataReadJson))
+
+	// Other namespaces do not share the cache
+	metadataRead, err = s.GetTokenMetadataByURI(ctx, "ns2", metadata.URI)
+	assert.NoError(t, err)
+	assert.Nil(t, metadataRead)
+
+	// Query back the metadata (by query filter)
+	fb := database.TokenMetadataQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.In("uri", []driver.Value{metadata.URI, "https://example.com/token/2"}),
+	)
+	metadataList, res, err := s.GetTokenMetadata(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(metadataList))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	metadataReadJson, _ = json.Marshal(metadataList[0])
+	assert.Equal(t, string(metadataJson), string(metadataReadJson))
+}
+
+func TestUpsertTokenMetadataFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenMetadata(context.Background(), &core.TokenMetadata{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenMetadataFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenMetadata(context.Background(), &core.TokenMetadata{})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenMetadataFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenMetadata(context.Background(), &core.TokenMetadata{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenMetadataFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(1))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenMetadata(context.Background(), &core.TokenMetadata{})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenMetadataFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenMetadata(context.Background(), &core.TokenMetadata{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenMetadataByURISelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenMetadataByURI(context.Background(), "ns1", "https://example.com/token/1")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenMetadataByURIScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"uri"}).AddRow("only one"))
+	_, err := s.GetTokenMetadataByURI(context.Background(), "ns1", "https://example.com/token/1")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenMetadataQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenMetadataQueryFactory.NewFilter(context.Background()).Eq("uri", "")
+	_, _, err := s.GetTokenMetadata(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenMetadataBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenMetadataQueryFactory.NewFilter(context.Background()).Eq("uri", map[bool]bool{true: false})
+	_, _, err := s.GetTokenMetadata(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*uri", err)
+}
+
+func TestGetTokenMetadataScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"uri"}).AddRow("only one"))
+	f := database.TokenMetadataQueryFactory.NewFilter(context.Background()).Eq("uri", "")
+	_, _, err := s.GetTokenMetadata(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
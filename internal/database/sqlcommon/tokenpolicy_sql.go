@@ -0,0 +1,169 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenPolicyColumns = []string{
+		"namespace",
+		"pool_id",
+		"max_amount",
+		"daily_limit",
+		"senders",
+		"recipients",
+		"denied",
+		"created",
+		"updated",
+	}
+)
+
+const tokenpolicyTable = "tokenpolicy"
+
+// UpsertTokenTransferPolicy replaces the policy for a pool. If the pool already has a policy, its
+// creation time is retained and set on the supplied policy.
+func (s *SQLCommon) UpsertTokenTransferPolicy(ctx context.Context, policy *core.TokenTransferPolicy) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	pool := sq.Eq{
+		"namespace": policy.Namespace,
+		"pool_id":   policy.Pool,
+	}
+	rows, _, err := s.QueryTx(ctx, tokenpolicyTable, tx,
+		sq.Select("created").
+			From(tokenpolicyTable).
+			Where(pool),
+	)
+	if err != nil {
+		return err
+	}
+	existing := rows.Next()
+	if existing {
+		err = rows.Scan(&policy.Created)
+	}
+	rows.Close()
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenpolicyTable)
+	}
+
+	policy.Updated = fftypes.Now()
+	if existing {
+		if _, err = s.UpdateTx(ctx, tokenpolicyTable, tx,
+			sq.Update(tokenpolicyTable).
+				Set("max_amount", policy.MaxAmount).
+				Set("daily_limit", policy.DailyLimit).
+				Set("senders", policy.Senders).
+				Set("recipients", policy.Recipients).
+				Set("denied", policy.Denied).
+				Set("updated", policy.Updated).
+				Where(pool),
+			nil, // no change events for token policies
+		); err != nil {
+			return err
+		}
+	} else {
+		policy.Created = policy.Updated
+		if _, err = s.InsertTx(ctx, tokenpolicyTable, tx,
+			sq.Insert(tokenpolicyTable).
+				Columns(tokenPolicyColumns...).
+				Values(
+					policy.Namespace,
+					policy.Pool,
+					policy.MaxAmount,
+					policy.DailyLimit,
+					policy.Senders,
+					policy.Recipients,
+					policy.Denied,
+					policy.Created,
+					policy.Updated,
+				),
+			nil, // no change events for token policies
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenPolicyResult(ctx context.Context, row *sql.Rows) (*core.TokenTransferPolicy, error) {
+	policy := core.TokenTransferPolicy{}
+	err := row.Scan(
+		&policy.Namespace,
+		&policy.Pool,
+		&policy.MaxAmount,
+		&policy.DailyLimit,
+		&policy.Senders,
+		&policy.Recipients,
+		&policy.Denied,
+		&policy.Created,
+		&policy.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenpolicyTable)
+	}
+	return &policy, nil
+}
+
+func (s *SQLCommon) GetTokenTransferPolicy(ctx context.Context, namespace string, poolID *fftypes.UUID) (*core.TokenTransferPolicy, error) {
+	rows, _, err := s.Query(ctx, tokenpolicyTable,
+		sq.Select(tokenPolicyColumns...).
+			From(tokenpolicyTable).
+			Where(sq.Eq{"namespace": namespace, "pool_id": poolID}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token transfer policy for pool '%s' not found", poolID)
+		return nil, nil
+	}
+
+	return s.tokenPolicyResult(ctx, rows)
+}
+
+func (s *SQLCommon) DeleteTokenTransferPolicy(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	err = s.DeleteTx(ctx, tokenpolicyTable, tx,
+		sq.Delete(tokenpolicyTable).Where(sq.Eq{"namespace": namespace, "pool_id": poolID}), nil)
+	if err != nil && err != fftypes.DeleteRecordNotFound {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
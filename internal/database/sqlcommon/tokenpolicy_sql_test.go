@@ -0,0 +1,174 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenTransferPolicyE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	policy := &core.TokenTransferPolicy{
+		Namespace:  "ns1",
+		Pool:       fftypes.NewUUID(),
+		MaxAmount:  fftypes.NewFFBigInt(100),
+		Senders:    fftypes.FFStringArray{"0x01", "0x02"},
+		Recipients: fftypes.FFStringArray{"0x03"},
+		Denied:     fftypes.FFStringArray{},
+	}
+	err := s.UpsertTokenTransferPolicy(ctx, policy)
+	assert.NoError(t, err)
+	assert.NotNil(t, policy.Created)
+	policyJson, _ := json.Marshal(&policy)
+
+	// Query back the policy
+	policyRead, err := s.GetTokenTransferPolicy(ctx, "ns1", policy.Pool)
+	assert.NoError(t, err)
+	assert.Nil(t, policyRead.DailyLimit)
+	policyReadJson, _ := json.Marshal(&policyRead)
+	assert.Equal(t, string(policyJson), string(policyReadJson))
+
+	// Replace the policy, which keeps the original creation time
+	updated := &core.TokenTransferPolicy{
+		Namespace:  "ns1",
+		Pool:       policy.Pool,
+		DailyLimit: fftypes.NewFFBigInt(1000),
+		Denied:     fftypes.FFStringArray{"0x04"},
+	}
+	err = s.UpsertTokenTransferPolicy(ctx, updated)
+	assert.NoError(t, err)
+	assert.Equal(t, policy.Created.String(), updated.Created.String())
+	policyRead, err = s.GetTokenTransferPolicy(ctx, "ns1", policy.Pool)
+	assert.NoError(t, err)
+	assert.Nil(t, policyRead.MaxAmount)
+	assert.Equal(t, int64(1000), policyRead.DailyLimit.Int64())
+	assert.Equal(t, fftypes.FFStringArray{}, policyRead.Senders)
+	assert.Equal(t, fftypes.FFStringArray{"0x04"}, policyRead.Denied)
+
+	// Delete the policy
+	err = s.DeleteTokenTransferPolicy(ctx, "ns1", policy.Pool)
+	assert.NoError(t, err)
+	policyRead, err = s.GetTokenTransferPolicy(ctx, "ns1", policy.Pool)
+	assert.NoError(t, err)
+	assert.Nil(t, policyRead)
+}
+
+func TestUpsertTokenTransferPolicyFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenTransferPolicy(context.Background(), &core.TokenTransferPolicy{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenTransferPolicyFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenTransferPolicy(context.Background(), &core.TokenTransferPolicy{})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenTransferPolicyFailScan(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"created", "updated"}).AddRow(0, 0))
+	mock.ExpectRollback()
+	err := s.UpsertTokenTransferPolicy(context.Background(), &core.TokenTransferPolicy{})
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenTransferPolicyFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenTransferPolicy(context.Background(), &core.TokenTransferPolicy{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenTransferPolicyFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"created"}).AddRow(0))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTokenTransferPolicy(context.Background(), &core.TokenTransferPolicy{})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTokenTransferPolicyFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTokenTransferPolicy(context.Background(), &core.TokenTransferPolicy{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenTransferPolicySelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenTransferPolicy(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenTransferPolicyScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	_, err := s.GetTokenTransferPolicy(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTokenTransferPolicyFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteTokenTransferPolicy(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTokenTransferPolicyFailDelete(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.DeleteTokenTransferPolicy(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
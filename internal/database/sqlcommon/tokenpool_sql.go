@@ -54,13 +54,15 @@ var (
 		"methods",
 		"published",
 		"plugin_data",
+		"identity_registry",
 	}
 	tokenPoolFilterFieldMap = map[string]string{
-		"message":         "message_id",
-		"tx.type":         "tx_type",
-		"tx.id":           "tx_id",
-		"interfaceformat": "interface_format",
-		"networkname":     "network_name",
+		"message":          "message_id",
+		"tx.type":          "tx_type",
+		"tx.id":            "tx_id",
+		"interfaceformat":  "interface_format",
+		"networkname":      "network_name",
+		"identityregistry": "identity_registry",
 	}
 )
 
@@ -95,6 +97,7 @@ func (s *SQLCommon) attemptTokenPoolUpdate(ctx context.Context, tx *dbsql.TXWrap
 			Set("methods", pool.Methods).
 			Set("published", pool.Published).
 			Set("plugin_data", pool.PluginData).
+			Set("identity_registry", pool.IdentityRegistry).
 			Where(sq.Eq{"id": pool.ID}),
 		func() {
 			s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenPools, core.ChangeEventTypeUpdated, pool.Namespace, pool.ID)
@@ -133,6 +136,7 @@ func (s *SQLCommon) setTokenPoolInsertValues(query sq.InsertBuilder, pool *core.
 		pool.Methods,
 		pool.Published,
 		pool.PluginData,
+		pool.IdentityRegistry,
 	)
 }
 
@@ -254,6 +258,7 @@ func (s *SQLCommon) tokenPoolResult(ctx context.Context, row *sql.Rows) (*core.T
 		&pool.Methods,
 		&pool.Published,
 		&pool.PluginData,
+		&pool.IdentityRegistry,
 	)
 	if iface.ID != nil {
 		pool.Interface = &iface
@@ -60,7 +60,8 @@ func TestTokenPoolE2EWithDB(t *testing.T) {
 		Interface: &fftypes.FFIReference{
 			ID: fftypes.NewUUID(),
 		},
-		InterfaceFormat: "abi",
+		InterfaceFormat:  "abi",
+		IdentityRegistry: "0xregistry",
 	}
 
 	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenPools, core.ChangeEventTypeCreated, "ns1", poolID, mock.Anything).
@@ -0,0 +1,189 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenPoolMigrationColumns = []string{
+		"id",
+		"namespace",
+		"pool_id",
+		"state",
+		"from_connector",
+		"from_locator",
+		"to_connector",
+		"to_locator",
+		"pool_state",
+		"config",
+		"tx_type",
+		"tx_id",
+		"error",
+		"created",
+		"updated",
+	}
+	tokenPoolMigrationFilterFieldMap = map[string]string{
+		"pool":          "pool_id",
+		"fromconnector": "from_connector",
+		"fromlocator":   "from_locator",
+		"toconnector":   "to_connector",
+		"tolocator":     "to_locator",
+		"poolstate":     "pool_state",
+		"tx.type":       "tx_type",
+		"tx.id":         "tx_id",
+	}
+)
+
+const tokenpoolmigrationTable = "tokenpoolmigration"
+
+func (s *SQLCommon) InsertTokenPoolMigration(ctx context.Context, migration *core.TokenPoolMigration) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	migration.Created = fftypes.Now()
+	migration.Updated = migration.Created
+	if _, err = s.InsertTx(ctx, tokenpoolmigrationTable, tx,
+		sq.Insert(tokenpoolmigrationTable).
+			Columns(tokenPoolMigrationColumns...).
+			Values(
+				migration.ID,
+				migration.Namespace,
+				migration.Pool,
+				migration.State,
+				migration.FromConnector,
+				migration.FromLocator,
+				migration.ToConnector,
+				migration.ToLocator,
+				migration.PoolState,
+				migration.Config,
+				migration.TX.Type,
+				migration.TX.ID,
+				migration.Error,
+				migration.Created,
+				migration.Updated,
+			),
+		nil, // no change events for token pool migrations
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) UpdateTokenPoolMigration(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(tokenpoolmigrationTable), update, tokenPoolMigrationFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	if _, err = s.UpdateTx(ctx, tokenpoolmigrationTable, tx, query, nil /* no change events for token pool migrations */); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenPoolMigrationResult(ctx context.Context, row *sql.Rows) (*core.TokenPoolMigration, error) {
+	migration := core.TokenPoolMigration{}
+	err := row.Scan(
+		&migration.ID,
+		&migration.Namespace,
+		&migration.Pool,
+		&migration.State,
+		&migration.FromConnector,
+		&migration.FromLocator,
+		&migration.ToConnector,
+		&migration.ToLocator,
+		&migration.PoolState,
+		&migration.Config,
+		&migration.TX.Type,
+		&migration.TX.ID,
+		&migration.Error,
+		&migration.Created,
+		&migration.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenpoolmigrationTable)
+	}
+	return &migration, nil
+}
+
+func (s *SQLCommon) GetTokenPoolMigrationByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenPoolMigration, error) {
+	rows, _, err := s.Query(ctx, tokenpoolmigrationTable,
+		sq.Select(tokenPoolMigrationColumns...).
+			From(tokenpoolmigrationTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token pool migration '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.tokenPoolMigrationResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenPoolMigrations(ctx context.Context, namespace string, filter ffapi.Filter) (migrations []*core.TokenPoolMigration, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenPoolMigrationColumns...).From(tokenpoolmigrationTable),
+		filter, tokenPoolMigrationFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenpoolmigrationTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	migrations = []*core.TokenPoolMigration{}
+	for rows.Next() {
+		d, err := s.tokenPoolMigrationResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		migrations = append(migrations, d)
+	}
+
+	return migrations, s.QueryRes(ctx, tokenpoolmigrationTable, tx, fop, fi), err
+}
@@ -0,0 +1,190 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenPoolMigrationE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new token pool migration entry
+	migration := &core.TokenPoolMigration{
+		ID:            fftypes.NewUUID(),
+		Namespace:     "ns1",
+		Pool:          fftypes.NewUUID(),
+		State:         core.TokenPoolMigrationStatePending,
+		FromConnector: "erc1155",
+		FromLocator:   "address=0x12345&id=F1",
+		ToConnector:   "erc20_erc721",
+		PoolState:     core.TokenPoolStateConfirmed,
+		Config:        fftypes.JSONObject{"address": "0x12345"},
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenPool,
+			ID:   fftypes.NewUUID(),
+		},
+	}
+	err := s.InsertTokenPoolMigration(ctx, migration)
+	assert.NoError(t, err)
+	assert.NotNil(t, migration.Created)
+	migrationJson, _ := json.Marshal(&migration)
+
+	// Query back the migration (by ID)
+	migrationRead, err := s.GetTokenPoolMigrationByID(ctx, "ns1", migration.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, migrationRead)
+	migrationReadJson, _ := json.Marshal(&migrationRead)
+	assert.Equal(t, string(migrationJson), string(migrationReadJson))
+
+	// Query back the migration (by query filter)
+	fb := database.TokenPoolMigrationQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", migration.Pool),
+		fb.Eq("state", core.TokenPoolMigrationStatePending),
+		fb.Eq("toconnector", "erc20_erc721"),
+	)
+	migrations, res, err := s.GetTokenPoolMigrations(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(migrations))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	migrationReadJson, _ = json.Marshal(migrations[0])
+	assert.Equal(t, string(migrationJson), string(migrationReadJson))
+
+	// Update the state
+	up := database.TokenPoolMigrationQueryFactory.NewUpdate(ctx).
+		Set("state", core.TokenPoolMigrationStateSucceeded).
+		Set("tolocator", "address=0x12345&schema=ERC20WithData&type=fungible")
+	err = s.UpdateTokenPoolMigration(ctx, "ns1", migration.ID, up)
+	assert.NoError(t, err)
+	migrationRead, err = s.GetTokenPoolMigrationByID(ctx, "ns1", migration.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenPoolMigrationStateSucceeded, migrationRead.State)
+	assert.Equal(t, "address=0x12345&schema=ERC20WithData&type=fungible", migrationRead.ToLocator)
+
+	// Other namespaces do not see the migration
+	migrationRead, err = s.GetTokenPoolMigrationByID(ctx, "ns2", migration.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, migrationRead)
+}
+
+func TestInsertTokenPoolMigrationFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenPoolMigration(context.Background(), &core.TokenPoolMigration{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenPoolMigrationFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertTokenPoolMigration(context.Background(), &core.TokenPoolMigration{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenPoolMigrationFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenPoolMigration(context.Background(), &core.TokenPoolMigration{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTokenPoolMigrationBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.TokenPoolMigrationQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenPoolMigrationStateSucceeded)
+	err := s.UpdateTokenPoolMigration(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateTokenPoolMigrationBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.TokenPoolMigrationQueryFactory.NewUpdate(context.Background()).Set("state", map[bool]bool{true: false})
+	err := s.UpdateTokenPoolMigration(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestUpdateTokenPoolMigrationFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.TokenPoolMigrationQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenPoolMigrationStateSucceeded)
+	err := s.UpdateTokenPoolMigration(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+}
+
+func TestGetTokenPoolMigrationByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenPoolMigrationByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenPoolMigrationByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetTokenPoolMigrationByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenPoolMigrationsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenPoolMigrationQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenPoolMigrations(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenPoolMigrationsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenPoolMigrationQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	_, _, err := s.GetTokenPoolMigrations(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestGetTokenPoolMigrationsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenPoolMigrationQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenPoolMigrations(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
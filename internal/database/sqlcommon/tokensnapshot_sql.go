@@ -0,0 +1,221 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenSnapshotColumns = []string{
+		"id",
+		"namespace",
+		"pool_id",
+		"block_number",
+		"timestamp",
+		"transfers",
+		"accounts",
+		"created",
+	}
+	tokenSnapshotFilterFieldMap = map[string]string{
+		"pool":        "pool_id",
+		"blocknumber": "block_number",
+	}
+	tokenSnapshotBalanceColumns = []string{
+		"snapshot_id",
+		"namespace",
+		"token_index",
+		"key",
+		"balance",
+	}
+	tokenSnapshotBalanceFilterFieldMap = map[string]string{
+		"tokenindex": "token_index",
+	}
+)
+
+const tokensnapshotTable = "tokensnapshot"
+const tokensnapshotbalanceTable = "tokensnapshotbalance"
+
+func (s *SQLCommon) InsertTokenSnapshot(ctx context.Context, snapshot *core.TokenSnapshot, balances []*core.TokenSnapshotBalance) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if _, err = s.InsertTx(ctx, tokensnapshotTable, tx,
+		sq.Insert(tokensnapshotTable).
+			Columns(tokenSnapshotColumns...).
+			Values(
+				snapshot.ID,
+				snapshot.Namespace,
+				snapshot.Pool,
+				snapshot.BlockNumber,
+				snapshot.Timestamp,
+				snapshot.Transfers,
+				snapshot.Accounts,
+				snapshot.Created,
+			),
+		nil, // no change events for token snapshots
+	); err != nil {
+		return err
+	}
+
+	if len(balances) > 0 {
+		if s.Features().MultiRowInsert {
+			query := sq.Insert(tokensnapshotbalanceTable).Columns(tokenSnapshotBalanceColumns...)
+			for _, balance := range balances {
+				query = s.setTokenSnapshotBalanceInsertValues(query, balance)
+			}
+			sequences := make([]int64, len(balances))
+			if err = s.InsertTxRows(ctx, tokensnapshotbalanceTable, tx, query, nil, sequences, false); err != nil {
+				return err
+			}
+		} else {
+			// Fall back to individual inserts grouped in a TX
+			for _, balance := range balances {
+				query := s.setTokenSnapshotBalanceInsertValues(sq.Insert(tokensnapshotbalanceTable).Columns(tokenSnapshotBalanceColumns...), balance)
+				if _, err = s.InsertTx(ctx, tokensnapshotbalanceTable, tx, query, nil); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) setTokenSnapshotBalanceInsertValues(query sq.InsertBuilder, balance *core.TokenSnapshotBalance) sq.InsertBuilder {
+	return query.Values(
+		balance.Snapshot,
+		balance.Namespace,
+		balance.TokenIndex,
+		balance.Key,
+		&balance.Balance,
+	)
+}
+
+func (s *SQLCommon) tokenSnapshotResult(ctx context.Context, row *sql.Rows) (*core.TokenSnapshot, error) {
+	snapshot := core.TokenSnapshot{}
+	err := row.Scan(
+		&snapshot.ID,
+		&snapshot.Namespace,
+		&snapshot.Pool,
+		&snapshot.BlockNumber,
+		&snapshot.Timestamp,
+		&snapshot.Transfers,
+		&snapshot.Accounts,
+		&snapshot.Created,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokensnapshotTable)
+	}
+	return &snapshot, nil
+}
+
+func (s *SQLCommon) tokenSnapshotBalanceResult(ctx context.Context, row *sql.Rows) (*core.TokenSnapshotBalance, error) {
+	balance := core.TokenSnapshotBalance{}
+	err := row.Scan(
+		&balance.Snapshot,
+		&balance.Namespace,
+		&balance.TokenIndex,
+		&balance.Key,
+		&balance.Balance,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokensnapshotbalanceTable)
+	}
+	return &balance, nil
+}
+
+func (s *SQLCommon) GetTokenSnapshotByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenSnapshot, error) {
+	rows, _, err := s.Query(ctx, tokensnapshotTable,
+		sq.Select(tokenSnapshotColumns...).
+			From(tokensnapshotTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token snapshot '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.tokenSnapshotResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenSnapshots(ctx context.Context, namespace string, filter ffapi.Filter) (snapshots []*core.TokenSnapshot, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenSnapshotColumns...).From(tokensnapshotTable),
+		filter, tokenSnapshotFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokensnapshotTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	snapshots = []*core.TokenSnapshot{}
+	for rows.Next() {
+		d, err := s.tokenSnapshotResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		snapshots = append(snapshots, d)
+	}
+
+	return snapshots, s.QueryRes(ctx, tokensnapshotTable, tx, fop, fi), err
+}
+
+func (s *SQLCommon) GetTokenSnapshotBalances(ctx context.Context, namespace string, snapshotID *fftypes.UUID, filter ffapi.Filter) (balances []*core.TokenSnapshotBalance, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenSnapshotBalanceColumns...).From(tokensnapshotbalanceTable),
+		filter, tokenSnapshotBalanceFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace, "snapshot_id": snapshotID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokensnapshotbalanceTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	balances = []*core.TokenSnapshotBalance{}
+	for rows.Next() {
+		d, err := s.tokenSnapshotBalanceResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		balances = append(balances, d)
+	}
+
+	return balances, s.QueryRes(ctx, tokensnapshotbalanceTable, tx, fop, fi), err
+}
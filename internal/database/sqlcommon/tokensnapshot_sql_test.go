@@ -0,0 +1,242 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTokenSnapshot() (*core.TokenSnapshot, []*core.TokenSnapshotBalance) {
+	blockNumber := int64(12345)
+	snapshot := &core.TokenSnapshot{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Pool:        fftypes.NewUUID(),
+		BlockNumber: &blockNumber,
+		Transfers:   3,
+		Accounts:    2,
+		Created:     fftypes.Now(),
+	}
+	balance1 := &core.TokenSnapshotBalance{
+		Snapshot:   snapshot.ID,
+		Namespace:  "ns1",
+		TokenIndex: "1",
+		Key:        "0x01",
+	}
+	balance1.Balance.Int().SetInt64(10)
+	balance2 := &core.TokenSnapshotBalance{
+		Snapshot:   snapshot.ID,
+		Namespace:  "ns1",
+		TokenIndex: "1",
+		Key:        "0x02",
+	}
+	balance2.Balance.Int().SetInt64(5)
+	return snapshot, []*core.TokenSnapshotBalance{balance1, balance2}
+}
+
+func TestTokenSnapshotE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new snapshot
+	snapshot, balances := newTestTokenSnapshot()
+	err := s.InsertTokenSnapshot(ctx, snapshot, balances)
+	assert.NoError(t, err)
+	snapshotJson, _ := json.Marshal(&snapshot)
+
+	// Query back the snapshot (by ID)
+	snapshotRead, err := s.GetTokenSnapshotByID(ctx, "ns1", snapshot.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, snapshotRead)
+	snapshotReadJson, _ := json.Marshal(&snapshotRead)
+	assert.Equal(t, string(snapshotJson), string(snapshotReadJson))
+
+	// Other namespaces cannot see the snapshot
+	snapshotRead, err = s.GetTokenSnapshotByID(ctx, "ns2", snapshot.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, snapshotRead)
+
+	// Query back the snapshot (by query filter)
+	fb := database.TokenSnapshotQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", snapshot.Pool),
+		fb.Eq("blocknumber", 12345),
+	)
+	snapshots, res, err := s.GetTokenSnapshots(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(snapshots))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	snapshotReadJson, _ = json.Marshal(snapshots[0])
+	assert.Equal(t, string(snapshotJson), string(snapshotReadJson))
+
+	// Query back the balances
+	bfb := database.TokenSnapshotBalanceQueryFactory.NewFilter(ctx)
+	balancesRead, res, err := s.GetTokenSnapshotBalances(ctx, "ns1", snapshot.ID, bfb.Eq("tokenindex", "1").Sort("key").Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), *res.TotalCount)
+	balancesJson, _ := json.Marshal(balances)
+	balancesReadJson, _ := json.Marshal(balancesRead)
+	assert.Equal(t, string(balancesJson), string(balancesReadJson))
+
+	// Balances are scoped to the snapshot
+	balancesRead, _, err = s.GetTokenSnapshotBalances(ctx, "ns1", fftypes.NewUUID(), bfb.And())
+	assert.NoError(t, err)
+	assert.Empty(t, balancesRead)
+}
+
+func TestInsertTokenSnapshotFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	snapshot, balances := newTestTokenSnapshot()
+	err := s.InsertTokenSnapshot(context.Background(), snapshot, balances)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenSnapshotFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	snapshot, balances := newTestTokenSnapshot()
+	err := s.InsertTokenSnapshot(context.Background(), snapshot, balances)
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenSnapshotFailInsertBalance(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	snapshot, balances := newTestTokenSnapshot()
+	err := s.InsertTokenSnapshot(context.Background(), snapshot, balances)
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenSnapshotMultiRowOK(t *testing.T) {
+	s := newMockProvider()
+	s.multiRowInsert = true
+	s.fakePSQLInsert = true
+	s, mock := s.init()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT.*").WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).AddRow(int64(1000)))
+	mock.ExpectQuery("INSERT.*").WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).
+		AddRow(int64(1001)).
+		AddRow(int64(1002)),
+	)
+	mock.ExpectCommit()
+	snapshot, balances := newTestTokenSnapshot()
+	err := s.InsertTokenSnapshot(context.Background(), snapshot, balances)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenSnapshotMultiRowFail(t *testing.T) {
+	s := newMockProvider()
+	s.multiRowInsert = true
+	s.fakePSQLInsert = true
+	s, mock := s.init()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT.*").WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).AddRow(int64(1000)))
+	mock.ExpectQuery("INSERT.*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	snapshot, balances := newTestTokenSnapshot()
+	err := s.InsertTokenSnapshot(context.Background(), snapshot, balances)
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSnapshotByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenSnapshotByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSnapshotByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetTokenSnapshotByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSnapshotsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenSnapshotQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetTokenSnapshots(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSnapshotsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenSnapshotQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, _, err := s.GetTokenSnapshots(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestGetTokenSnapshotsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenSnapshotQueryFactory.NewFilter(context.Background()).Eq("id", "")
+	_, _, err := s.GetTokenSnapshots(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSnapshotBalancesQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenSnapshotBalanceQueryFactory.NewFilter(context.Background()).Eq("key", "")
+	_, _, err := s.GetTokenSnapshotBalances(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSnapshotBalancesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenSnapshotBalanceQueryFactory.NewFilter(context.Background()).Eq("key", map[bool]bool{true: false})
+	_, _, err := s.GetTokenSnapshotBalances(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF00143.*key", err)
+}
+
+func TestGetTokenSnapshotBalancesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("only one"))
+	f := database.TokenSnapshotBalanceQueryFactory.NewFilter(context.Background()).Eq("key", "")
+	_, _, err := s.GetTokenSnapshotBalances(context.Background(), "ns1", fftypes.NewUUID(), f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,172 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenSwapColumns = []string{
+		"id",
+		"namespace",
+		"state",
+		"legs",
+		"hash_lock",
+		"preimage",
+		"timeout",
+		"tx_type",
+		"tx_id",
+		"created",
+		"updated",
+	}
+	tokenSwapFilterFieldMap = map[string]string{
+		"hashlock": "hash_lock",
+		"tx.type":  "tx_type",
+		"tx.id":    "tx_id",
+	}
+)
+
+const tokenswapTable = "tokenswap"
+
+func (s *SQLCommon) InsertTokenSwap(ctx context.Context, swap *core.TokenSwap) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	swap.Created = fftypes.Now()
+	swap.Updated = swap.Created
+	if _, err = s.InsertTx(ctx, tokenswapTable, tx,
+		sq.Insert(tokenswapTable).
+			Columns(tokenSwapColumns...).
+			Values(
+				swap.ID,
+				swap.Namespace,
+				swap.State,
+				swap.Legs,
+				swap.HashLock,
+				swap.Preimage,
+				swap.Timeout,
+				swap.TX.Type,
+				swap.TX.ID,
+				swap.Created,
+				swap.Updated,
+			),
+		nil, // no change events for token swaps
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) UpdateTokenSwap(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(tokenswapTable), update, tokenSwapFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	if _, err = s.UpdateTx(ctx, tokenswapTable, tx, query, nil /* no change events for token swaps */); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenSwapResult(ctx context.Context, row *sql.Rows) (*core.TokenSwap, error) {
+	swap := core.TokenSwap{}
+	err := row.Scan(
+		&swap.ID,
+		&swap.Namespace,
+		&swap.State,
+		&swap.Legs,
+		&swap.HashLock,
+		&swap.Preimage,
+		&swap.Timeout,
+		&swap.TX.Type,
+		&swap.TX.ID,
+		&swap.Created,
+		&swap.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokenswapTable)
+	}
+	return &swap, nil
+}
+
+func (s *SQLCommon) GetTokenSwapByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenSwap, error) {
+	rows, _, err := s.Query(ctx, tokenswapTable,
+		sq.Select(tokenSwapColumns...).
+			From(tokenswapTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token swap '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.tokenSwapResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenSwaps(ctx context.Context, namespace string, filter ffapi.Filter) (swaps []*core.TokenSwap, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenSwapColumns...).From(tokenswapTable),
+		filter, tokenSwapFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokenswapTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	swaps = []*core.TokenSwap{}
+	for rows.Next() {
+		d, err := s.tokenSwapResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		swaps = append(swaps, d)
+	}
+
+	return swaps, s.QueryRes(ctx, tokenswapTable, tx, fop, fi), err
+}
@@ -0,0 +1,198 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenSwapE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new token swap entry
+	swap := &core.TokenSwap{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		State:     core.TokenSwapStatePending,
+		Legs: core.TokenSwapLegs{
+			{
+				Type:   core.TokenSwapLegTypeTransfer,
+				LockID: "lock1",
+				Pool:   fftypes.NewUUID(),
+				From:   "0x01",
+				To:     "0x02",
+				Amount: *fftypes.NewFFBigInt(10),
+			},
+			{
+				Type:   core.TokenSwapLegTypeInvoke,
+				Invoke: &core.ContractCallRequest{MethodPath: "pay"},
+			},
+		},
+		HashLock: fftypes.NewRandB32(),
+		Preimage: fftypes.NewRandB32(),
+		Timeout:  fftypes.Now(),
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenSwap,
+			ID:   fftypes.NewUUID(),
+		},
+	}
+	err := s.InsertTokenSwap(ctx, swap)
+	assert.NoError(t, err)
+	assert.NotNil(t, swap.Created)
+	swapJson, _ := json.Marshal(&swap)
+
+	// Query back the swap (by ID)
+	swapRead, err := s.GetTokenSwapByID(ctx, "ns1", swap.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, swapRead)
+	assert.Equal(t, *swap.Preimage, *swapRead.Preimage)
+	swapReadJson, _ := json.Marshal(&swapRead)
+	assert.Equal(t, string(swapJson), string(swapReadJson))
+
+	// Query back the swap (by query filter)
+	fb := database.TokenSwapQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("tx.id", swap.TX.ID),
+		fb.Eq("state", core.TokenSwapStatePending),
+	)
+	swaps, res, err := s.GetTokenSwaps(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(swaps))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	swapReadJson, _ = json.Marshal(swaps[0])
+	assert.Equal(t, string(swapJson), string(swapReadJson))
+
+	// Update the state
+	up := database.TokenSwapQueryFactory.NewUpdate(ctx).Set("state", core.TokenSwapStateClaiming)
+	err = s.UpdateTokenSwap(ctx, "ns1", swap.ID, up)
+	assert.NoError(t, err)
+	swapRead, err = s.GetTokenSwapByID(ctx, "ns1", swap.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenSwapStateClaiming, swapRead.State)
+
+	// Other namespaces do not see the swap
+	swapRead, err = s.GetTokenSwapByID(ctx, "ns2", swap.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, swapRead)
+}
+
+func TestInsertTokenSwapFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenSwap(context.Background(), &core.TokenSwap{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenSwapFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertTokenSwap(context.Background(), &core.TokenSwap{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenSwapFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenSwap(context.Background(), &core.TokenSwap{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTokenSwapBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.TokenSwapQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenSwapStateClaiming)
+	err := s.UpdateTokenSwap(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateTokenSwapBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.TokenSwapQueryFactory.NewUpdate(context.Background()).Set("state", map[bool]bool{true: false})
+	err := s.UpdateTokenSwap(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestUpdateTokenSwapFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.TokenSwapQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenSwapStateClaiming)
+	err := s.UpdateTokenSwap(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+}
+
+func TestGetTokenSwapByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenSwapByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSwapByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetTokenSwapByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSwapsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenSwapQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenSwaps(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenSwapsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenSwapQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	_, _, err := s.GetTokenSwaps(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestGetTokenSwapsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenSwapQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenSwaps(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
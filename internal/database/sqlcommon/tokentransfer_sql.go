@@ -51,6 +51,7 @@ var (
 		"tx_id",
 		"blockchain_event",
 		"created",
+		"invalidated",
 	}
 	tokenTransferFilterFieldMap = map[string]string{
 		"type":            "type",
@@ -65,6 +66,7 @@ var (
 		"tx.type":         "tx_type",
 		"tx.id":           "tx_id",
 		"blockchainevent": "blockchain_event",
+		"sequence":        "seq",
 	}
 )
 
@@ -90,6 +92,7 @@ func (s *SQLCommon) setTokenTransferEventInsertValues(query sq.InsertBuilder, tr
 		transfer.TX.ID,
 		transfer.BlockchainEvent,
 		transfer.Created,
+		transfer.Invalidated,
 	)
 }
 
@@ -147,6 +150,9 @@ func (s *SQLCommon) tokenTransferResult(ctx context.Context, row *sql.Rows) (*co
 		&transfer.TX.ID,
 		&transfer.BlockchainEvent,
 		&transfer.Created,
+		&transfer.Invalidated,
+		// Must be added to the list of columns in all selects
+		&transfer.Sequence,
 	)
 	if err != nil {
 		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokentransferTable)
@@ -155,8 +161,10 @@ func (s *SQLCommon) tokenTransferResult(ctx context.Context, row *sql.Rows) (*co
 }
 
 func (s *SQLCommon) getTokenTransferPred(ctx context.Context, desc string, pred interface{}) (*core.TokenTransfer, error) {
+	cols := append([]string{}, tokenTransferColumns...)
+	cols = append(cols, s.SequenceColumn())
 	rows, _, err := s.Query(ctx, tokentransferTable,
-		sq.Select(tokenTransferColumns...).
+		sq.Select(cols...).
 			From(tokentransferTable).
 			Where(pred),
 	)
@@ -191,7 +199,9 @@ func (s *SQLCommon) GetTokenTransferByProtocolID(ctx context.Context, namespace
 }
 
 func (s *SQLCommon) GetTokenTransfers(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.TokenTransfer, fr *ffapi.FilterResult, err error) {
-	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenTransferColumns...).From(tokentransferTable),
+	cols := append([]string{}, tokenTransferColumns...)
+	cols = append(cols, s.SequenceColumn())
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(cols...).From(tokentransferTable),
 		filter, tokenTransferFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
 	if err != nil {
 		return nil, nil, err
@@ -215,6 +225,29 @@ func (s *SQLCommon) GetTokenTransfers(ctx context.Context, namespace string, fil
 	return transfers, s.QueryRes(ctx, tokentransferTable, tx, fop, fi), err
 }
 
+func (s *SQLCommon) UpdateTokenTransfer(ctx context.Context, namespace string, localID *fftypes.UUID, update ffapi.Update) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(tokentransferTable), update, tokenTransferFilterFieldMap)
+	if err != nil {
+		return err
+	}
+	query = query.Where(sq.Eq{"local_id": localID, "namespace": namespace})
+
+	_, err = s.UpdateTx(ctx, tokentransferTable, tx, query, func() {
+		s.callbacks.UUIDCollectionNSEvent(database.CollectionTokenTransfers, core.ChangeEventTypeUpdated, namespace, localID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
 func (s *SQLCommon) DeleteTokenTransfers(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
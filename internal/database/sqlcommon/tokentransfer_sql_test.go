@@ -100,6 +100,14 @@ func TestTokenTransferE2EWithDB(t *testing.T) {
 	transferReadJson, _ = json.Marshal(transfers[0])
 	assert.Equal(t, string(transferJson), string(transferReadJson))
 
+	// Invalidate the token transfer
+	up := database.TokenTransferQueryFactory.NewUpdate(ctx).Set("invalidated", true)
+	err = s.UpdateTokenTransfer(ctx, "ns1", transfer.LocalID, up)
+	assert.NoError(t, err)
+	transferRead, err = s.GetTokenTransferByID(ctx, "ns1", transfer.LocalID)
+	assert.NoError(t, err)
+	assert.True(t, transferRead.Invalidated)
+
 	// Delete the token transfer
 	err = s.DeleteTokenTransfers(ctx, "ns1", transfer.Pool)
 	assert.NoError(t, err)
@@ -203,3 +211,29 @@ func TestDeleteTokenTransfersFailDelete(t *testing.T) {
 	assert.Regexp(t, "FF00179", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpdateTokenTransferBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.TokenTransferQueryFactory.NewUpdate(context.Background()).Set("invalidated", true)
+	err := s.UpdateTokenTransfer(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateTokenTransferBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.TokenTransferQueryFactory.NewUpdate(context.Background()).Set("localid", map[bool]bool{true: false})
+	err := s.UpdateTokenTransfer(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00143.*localid", err)
+}
+
+func TestUpdateTokenTransferFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.TokenTransferQueryFactory.NewUpdate(context.Background()).Set("invalidated", true)
+	err := s.UpdateTokenTransfer(context.Background(), "ns1", fftypes.NewUUID(), u)
+	assert.Regexp(t, "FF00178", err)
+}
@@ -0,0 +1,192 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	tokenTransferRequestColumns = []string{
+		"id",
+		"namespace",
+		"state",
+		"type",
+		"pool_id",
+		"key",
+		"amount",
+		"transfer",
+		"required",
+		"signoffs",
+		"tx_type",
+		"tx_id",
+		"error",
+		"created",
+		"updated",
+	}
+	tokenTransferRequestFilterFieldMap = map[string]string{
+		"pool":    "pool_id",
+		"tx.type": "tx_type",
+		"tx.id":   "tx_id",
+	}
+)
+
+const tokentransferrequestTable = "tokentransferrequest"
+
+func (s *SQLCommon) InsertTokenTransferRequest(ctx context.Context, request *core.TokenTransferRequest) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	request.Created = fftypes.Now()
+	request.Updated = request.Created
+	if _, err = s.InsertTx(ctx, tokentransferrequestTable, tx,
+		sq.Insert(tokentransferrequestTable).
+			Columns(tokenTransferRequestColumns...).
+			Values(
+				request.ID,
+				request.Namespace,
+				request.State,
+				request.Type,
+				request.Pool,
+				request.Key,
+				request.Amount,
+				request.Transfer,
+				request.Required,
+				request.Signoffs,
+				request.TX.Type,
+				request.TX.ID,
+				request.Error,
+				request.Created,
+				request.Updated,
+			),
+		nil, // no change events for token transfer requests
+	); err != nil {
+		return err
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) UpdateTokenTransferRequest(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query, err := s.BuildUpdate(sq.Update(tokentransferrequestTable), update, tokenTransferRequestFilterFieldMap)
+	if err != nil {
+		return false, err
+	}
+
+	if filter != nil {
+		query, err = s.FilterUpdate(ctx, query, filter, tokenTransferRequestFilterFieldMap)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	query = query.Set("updated", fftypes.Now())
+	query = query.Where(sq.Eq{"id": id, "namespace": namespace})
+
+	ra, err := s.UpdateTx(ctx, tokentransferrequestTable, tx, query, nil /* no change events for token transfer requests */)
+	if err != nil {
+		return false, err
+	}
+	return ra > 0, s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) tokenTransferRequestResult(ctx context.Context, row *sql.Rows) (*core.TokenTransferRequest, error) {
+	request := core.TokenTransferRequest{}
+	err := row.Scan(
+		&request.ID,
+		&request.Namespace,
+		&request.State,
+		&request.Type,
+		&request.Pool,
+		&request.Key,
+		&request.Amount,
+		&request.Transfer,
+		&request.Required,
+		&request.Signoffs,
+		&request.TX.Type,
+		&request.TX.ID,
+		&request.Error,
+		&request.Created,
+		&request.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokentransferrequestTable)
+	}
+	return &request, nil
+}
+
+func (s *SQLCommon) GetTokenTransferRequestByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenTransferRequest, error) {
+	rows, _, err := s.Query(ctx, tokentransferrequestTable,
+		sq.Select(tokenTransferRequestColumns...).
+			From(tokentransferrequestTable).
+			Where(sq.Eq{"namespace": namespace, "id": id}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Token transfer request '%s' not found", id)
+		return nil, nil
+	}
+
+	return s.tokenTransferRequestResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTokenTransferRequests(ctx context.Context, namespace string, filter ffapi.Filter) (requests []*core.TokenTransferRequest, fr *ffapi.FilterResult, err error) {
+	query, fop, fi, err := s.FilterSelect(ctx, "", sq.Select(tokenTransferRequestColumns...).From(tokentransferrequestTable),
+		filter, tokenTransferRequestFilterFieldMap, []interface{}{"seq"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, tokentransferrequestTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	requests = []*core.TokenTransferRequest{}
+	for rows.Next() {
+		d, err := s.tokenTransferRequestResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		requests = append(requests, d)
+	}
+
+	return requests, s.QueryRes(ctx, tokentransferrequestTable, tx, fop, fi), err
+}
@@ -0,0 +1,222 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenTransferRequestE2EWithDB(t *testing.T) {
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Create a new token transfer request entry
+	request := &core.TokenTransferRequest{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		State:     core.TokenTransferRequestStatePending,
+		Type:      core.TokenTransferTypeTransfer,
+		Pool:      fftypes.NewUUID(),
+		Key:       "0x01",
+		Amount:    *fftypes.NewFFBigInt(1000),
+		Transfer: &core.TokenTransferInput{
+			TokenTransfer: core.TokenTransfer{
+				Key:    "0x01",
+				To:     "0x02",
+				Amount: *fftypes.NewFFBigInt(1000),
+			},
+			Pool: "pool1",
+		},
+		Required: 2,
+		Signoffs: core.TokenTransferSignoffs{
+			{Author: "did:firefly:org/org1", Key: "0x03", Created: fftypes.Now()},
+		},
+	}
+	err := s.InsertTokenTransferRequest(ctx, request)
+	assert.NoError(t, err)
+	assert.NotNil(t, request.Created)
+	requestJson, _ := json.Marshal(&request)
+
+	// Query back the request (by ID)
+	requestRead, err := s.GetTokenTransferRequestByID(ctx, "ns1", request.ID)
+	assert.NoError(t, err)
+	assert.NotNil(t, requestRead)
+	requestReadJson, _ := json.Marshal(&requestRead)
+	assert.Equal(t, string(requestJson), string(requestReadJson))
+
+	// Query back the request (by query filter)
+	fb := database.TokenTransferRequestQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", request.Pool),
+		fb.Eq("state", core.TokenTransferRequestStatePending),
+	)
+	requests, res, err := s.GetTokenTransferRequests(ctx, "ns1", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(requests))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	requestReadJson, _ = json.Marshal(requests[0])
+	assert.Equal(t, string(requestJson), string(requestReadJson))
+
+	// Update the state
+	txID := fftypes.NewUUID()
+	up := database.TokenTransferRequestQueryFactory.NewUpdate(ctx).
+		Set("state", core.TokenTransferRequestStateSubmitted).
+		Set("tx.id", txID)
+	updated, err := s.UpdateTokenTransferRequest(ctx, "ns1", request.ID, nil, up)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	requestRead, err = s.GetTokenTransferRequestByID(ctx, "ns1", request.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, core.TokenTransferRequestStateSubmitted, requestRead.State)
+	assert.Equal(t, *txID, *requestRead.TX.ID)
+
+	// A conditional update against the state that was read applies once, and a second one
+	// conditional on the same (now stale) state does not
+	conditional := fb.And(fb.Eq("updated", requestRead.Updated))
+	up = database.TokenTransferRequestQueryFactory.NewUpdate(ctx).Set("error", "first")
+	updated, err = s.UpdateTokenTransferRequest(ctx, "ns1", request.ID, conditional, up)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	up = database.TokenTransferRequestQueryFactory.NewUpdate(ctx).Set("error", "second")
+	updated, err = s.UpdateTokenTransferRequest(ctx, "ns1", request.ID, conditional, up)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	requestRead, err = s.GetTokenTransferRequestByID(ctx, "ns1", request.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", requestRead.Error)
+
+	// Other namespaces do not see the request
+	requestRead, err = s.GetTokenTransferRequestByID(ctx, "ns2", request.ID)
+	assert.NoError(t, err)
+	assert.Nil(t, requestRead)
+}
+
+func TestInsertTokenTransferRequestFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenTransferRequest(context.Background(), &core.TokenTransferRequest{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenTransferRequestFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertTokenTransferRequest(context.Background(), &core.TokenTransferRequest{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertTokenTransferRequestFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertTokenTransferRequest(context.Background(), &core.TokenTransferRequest{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateTokenTransferRequestBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	u := database.TokenTransferRequestQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenTransferRequestStateSubmitted)
+	_, err := s.UpdateTokenTransferRequest(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestUpdateTokenTransferRequestBuildQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	u := database.TokenTransferRequestQueryFactory.NewUpdate(context.Background()).Set("state", map[bool]bool{true: false})
+	_, err := s.UpdateTokenTransferRequest(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestUpdateTokenTransferRequestFilterFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	f := database.TokenTransferRequestQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	u := database.TokenTransferRequestQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenTransferRequestStateSubmitted)
+	_, err := s.UpdateTokenTransferRequest(context.Background(), "ns1", fftypes.NewUUID(), f, u)
+	assert.Regexp(t, "FF00143", err)
+}
+
+func TestUpdateTokenTransferRequestFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	u := database.TokenTransferRequestQueryFactory.NewUpdate(context.Background()).Set("state", core.TokenTransferRequestStateSubmitted)
+	_, err := s.UpdateTokenTransferRequest(context.Background(), "ns1", fftypes.NewUUID(), nil, u)
+	assert.Regexp(t, "FF00178", err)
+}
+
+func TestGetTokenTransferRequestByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTokenTransferRequestByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenTransferRequestByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetTokenTransferRequestByID(context.Background(), "ns1", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenTransferRequestsQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenTransferRequestQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenTransferRequests(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenTransferRequestsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenTransferRequestQueryFactory.NewFilter(context.Background()).Eq("state", map[bool]bool{true: false})
+	_, _, err := s.GetTokenTransferRequests(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*state", err)
+}
+
+func TestGetTokenTransferRequestsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.TokenTransferRequestQueryFactory.NewFilter(context.Background()).Eq("state", "")
+	_, _, err := s.GetTokenTransferRequests(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
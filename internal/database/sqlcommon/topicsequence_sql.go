@@ -0,0 +1,151 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var (
+	topicSequenceColumns = []string{
+		"namespace",
+		"context",
+		"topic",
+		"group_hash",
+		"next_sequence",
+		"updated",
+	}
+	topicSequenceFilterFieldMap = map[string]string{
+		"group": "group_hash",
+		"next":  "next_sequence",
+	}
+)
+
+const topicsequencesTable = "topicsequences"
+
+// UpsertTopicSequence sets the next sequence for a context, inserting the context the first time it is used
+func (s *SQLCommon) UpsertTopicSequence(ctx context.Context, ts *core.TopicSequence) (err error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	ts.Updated = fftypes.Now()
+	updated, err := s.UpdateTx(ctx, topicsequencesTable, tx,
+		sq.Update(topicsequencesTable).
+			Set("next_sequence", ts.Next).
+			Set("updated", ts.Updated).
+			Where(sq.Eq{
+				"namespace": ts.Namespace,
+				"context":   ts.Context,
+			}),
+		nil, // no change events for topic sequences
+	)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		if _, err = s.InsertTx(ctx, topicsequencesTable, tx,
+			sq.Insert(topicsequencesTable).
+				Columns(topicSequenceColumns...).
+				Values(
+					ts.Namespace,
+					ts.Context,
+					ts.Topic,
+					ts.Group,
+					ts.Next,
+					ts.Updated,
+				),
+			nil, // no change events for topic sequences
+		); err != nil {
+			return err
+		}
+	}
+
+	return s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) topicSequenceResult(ctx context.Context, row *sql.Rows) (*core.TopicSequence, error) {
+	ts := core.TopicSequence{}
+	err := row.Scan(
+		&ts.Namespace,
+		&ts.Context,
+		&ts.Topic,
+		&ts.Group,
+		&ts.Next,
+		&ts.Updated,
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, topicsequencesTable)
+	}
+	return &ts, nil
+}
+
+func (s *SQLCommon) GetTopicSequence(ctx context.Context, namespace string, context *fftypes.Bytes32) (*core.TopicSequence, error) {
+	rows, _, err := s.Query(ctx, topicsequencesTable,
+		sq.Select(topicSequenceColumns...).
+			From(topicsequencesTable).
+			Where(sq.Eq{"namespace": namespace, "context": context}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		log.L(ctx).Debugf("Topic sequence for context '%s' not found", context)
+		return nil, nil
+	}
+
+	return s.topicSequenceResult(ctx, rows)
+}
+
+func (s *SQLCommon) GetTopicSequences(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TopicSequence, *ffapi.FilterResult, error) {
+	query, fop, fi, err := s.FilterSelect(
+		ctx, "", sq.Select(topicSequenceColumns...).From(topicsequencesTable),
+		filter, topicSequenceFilterFieldMap, []interface{}{"topic"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, tx, err := s.Query(ctx, topicsequencesTable, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	sequences := []*core.TopicSequence{}
+	for rows.Next() {
+		ts, err := s.topicSequenceResult(ctx, rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		sequences = append(sequences, ts)
+	}
+
+	return sequences, s.QueryRes(ctx, topicsequencesTable, tx, fop, fi), err
+}
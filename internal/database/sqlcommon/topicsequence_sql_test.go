@@ -0,0 +1,158 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicSequenceE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	ts := &core.TopicSequence{
+		Namespace: "ns1",
+		Context:   fftypes.NewRandB32(),
+		Topic:     "topic1",
+		Group:     fftypes.NewRandB32(),
+		Next:      1,
+	}
+	err := s.UpsertTopicSequence(ctx, ts)
+	assert.NoError(t, err)
+	assert.NotNil(t, ts.Updated)
+	tsJson, _ := json.Marshal(&ts)
+
+	// Query back the sequence
+	tsRead, err := s.GetTopicSequence(ctx, "ns1", ts.Context)
+	assert.NoError(t, err)
+	tsReadJson, _ := json.Marshal(&tsRead)
+	assert.Equal(t, string(tsJson), string(tsReadJson))
+
+	// Move the sequence on
+	ts.Next = 2
+	err = s.UpsertTopicSequence(ctx, ts)
+	assert.NoError(t, err)
+	tsRead, err = s.GetTopicSequence(ctx, "ns1", ts.Context)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), tsRead.Next)
+
+	// Query with a filter
+	fb := database.TopicSequenceQueryFactory.NewFilter(ctx)
+	sequences, res, err := s.GetTopicSequences(ctx, "ns1", fb.And(
+		fb.Eq("topic", "topic1"),
+		fb.Eq("group", ts.Group),
+		fb.Eq("next", 2),
+	).Count(true))
+	assert.NoError(t, err)
+	assert.Len(t, sequences, 1)
+	assert.Equal(t, int64(1), *res.TotalCount)
+
+	// Other namespaces are not returned
+	tsRead, err = s.GetTopicSequence(ctx, "ns2", ts.Context)
+	assert.NoError(t, err)
+	assert.Nil(t, tsRead)
+}
+
+func TestUpsertTopicSequenceFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTopicSequence(context.Background(), &core.TopicSequence{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTopicSequenceFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTopicSequence(context.Background(), &core.TopicSequence{})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTopicSequenceFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertTopicSequence(context.Background(), &core.TopicSequence{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertTopicSequenceFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertTopicSequence(context.Background(), &core.TopicSequence{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTopicSequenceSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetTopicSequence(context.Background(), "ns1", fftypes.NewRandB32())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTopicSequenceScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	_, err := s.GetTopicSequence(context.Background(), "ns1", fftypes.NewRandB32())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTopicSequencesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TopicSequenceQueryFactory.NewFilter(context.Background()).Eq("topic", map[bool]bool{true: false})
+	_, _, err := s.GetTopicSequences(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*topic", err)
+}
+
+func TestGetTopicSequencesSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.TopicSequenceQueryFactory.NewFilter(context.Background()).Eq("topic", "topic1")
+	_, _, err := s.GetTopicSequences(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTopicSequencesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"namespace"}).AddRow("only one"))
+	f := database.TopicSequenceQueryFactory.NewFilter(context.Background()).Eq("topic", "topic1")
+	_, _, err := s.GetTopicSequences(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -154,12 +154,19 @@ func (s *SQLCommon) GetTransactions(ctx context.Context, namespace string, filte
 	}
 	defer rows.Close()
 
+	it := s.rowIterator(ctx)
 	transactions := []*core.Transaction{}
 	for rows.Next() {
 		transaction, err := s.transactionResult(ctx, rows)
 		if err != nil {
 			return nil, nil, err
 		}
+		if it != nil {
+			if err := it(transaction); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
 		transactions = append(transactions, transaction)
 	}
 
@@ -165,10 +165,14 @@ func (h *FFDX) dispatchEvent(msg *wsEvent) {
 		switch {
 		case err != nil:
 			err = fmt.Errorf("invalid transmission from peer '%s': %s", msg.Sender, err)
-		case wrapper.Batch == nil:
+		case wrapper.Batch == nil && len(wrapper.Receipts) == 0:
 			err = fmt.Errorf("invalid transmission from peer '%s': nil batch", msg.Sender)
 		default:
-			namespace = wrapper.Batch.Namespace
+			if wrapper.Batch != nil {
+				namespace = wrapper.Batch.Namespace
+			} else {
+				namespace = wrapper.Receipts[0].Namespace
+			}
 			e.dxType = dataexchange.DXEventTypeMessageReceived
 			e.messageReceived = &dataexchange.MessageReceived{
 				PeerID:    msg.Sender,
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"sync"
 
@@ -393,6 +394,31 @@ func (h *FFDX) DownloadBlob(ctx context.Context, payloadRef string) (content io.
 	return res.RawBody(), nil
 }
 
+func (h *FFDX) DownloadBlobRange(ctx context.Context, payloadRef string, offset, length int64) (content io.ReadCloser, err error) {
+	res, err := h.client.R().SetContext(ctx).
+		SetDoNotParseResponse(true).
+		SetHeader("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)).
+		Get(fmt.Sprintf("/api/v1/blobs/%s", payloadRef))
+	if err != nil || !res.IsSuccess() {
+		if err == nil {
+			_ = res.RawBody().Close()
+		}
+		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgDXRESTErr)
+	}
+	body := res.RawBody()
+	if res.StatusCode() != http.StatusPartialContent {
+		// DX returned the whole blob, so skip to the start of the range
+		if _, err := io.CopyN(io.Discard, body, offset); err != nil {
+			_ = body.Close()
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgDXBadResponse, "content", payloadRef)
+		}
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(body, length), body}, nil
+}
+
 func (h *FFDX) DeleteBlob(ctx context.Context, payloadRef string) (err error) {
 	res, err := h.client.R().SetContext(ctx).
 		SetDoNotParseResponse(true).
@@ -345,6 +345,65 @@ func TestDownloadBlobError(t *testing.T) {
 	assert.Regexp(t, "FF10229", err)
 }
 
+func TestDownloadBlobRangePartial(t *testing.T) {
+
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	u := fftypes.NewUUID()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/blobs/ns1/%s", httpURL, u),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "bytes=5-8", req.Header.Get("Range"))
+			return httpmock.NewBytesResponse(206, []byte(`data`)), nil
+		})
+
+	rc, err := h.DownloadBlobRange(context.Background(), fmt.Sprintf("ns1/%s", u), 5, 4)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, `data`, string(b))
+}
+
+func TestDownloadBlobRangeWholeBlob(t *testing.T) {
+
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	u := fftypes.NewUUID()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/blobs/ns1/%s", httpURL, u),
+		httpmock.NewBytesResponder(200, []byte(`some data here`)))
+
+	rc, err := h.DownloadBlobRange(context.Background(), fmt.Sprintf("ns1/%s", u), 5, 4)
+	assert.NoError(t, err)
+	b, err := ioutil.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, `data`, string(b))
+}
+
+func TestDownloadBlobRangeShortBlob(t *testing.T) {
+
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	u := fftypes.NewUUID()
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/blobs/ns1/%s", httpURL, u),
+		httpmock.NewBytesResponder(200, []byte(`some`)))
+
+	_, err := h.DownloadBlobRange(context.Background(), fmt.Sprintf("ns1/%s", u), 5, 4)
+	assert.Regexp(t, "FF10237", err)
+}
+
+func TestDownloadBlobRangeError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFDX(t, false)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/blobs/bad", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	_, err := h.DownloadBlobRange(context.Background(), "bad", 0, 10)
+	assert.Regexp(t, "FF10229", err)
+}
+
 func TestSendMessage(t *testing.T) {
 
 	h, _, _, httpURL, done := newTestFFDX(t, false)
@@ -533,6 +592,16 @@ func TestMessageEventsBackgroundStart(t *testing.T) {
 	msg = <-toServer
 	assert.Equal(t, `{"action":"ack","id":"4","manifest":"{\"manifest\":true}"}`, string(msg))
 
+	mcb.On("DXEvent", h, mock.MatchedBy(func(ev dataexchange.DXEvent) bool {
+		return ev.EventID() == "5" &&
+			ev.Type() == dataexchange.DXEventTypeMessageReceived &&
+			ev.MessageReceived().PeerID == "peer2" &&
+			len(ev.MessageReceived().Transport.Receipts) == 1
+	})).Run(acker()).Return(nil)
+	fromServer <- `{"id":"5","type":"message-received","sender":"peer2","recipient":"peer1","message":"{\"receipts\":[{\"namespace\":\"ns1\"}]}"}`
+	msg = <-toServer
+	assert.Equal(t, `{"action":"ack","id":"5"}`, string(msg))
+
 	mcb.AssertExpectations(t)
 	ocb.AssertExpectations(t)
 }
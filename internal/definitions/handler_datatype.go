@@ -46,6 +46,15 @@ func (dh *definitionHandler) handleDatatypeBroadcast(ctx context.Context, state
 		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedConflict, "datatype", dt.ID, existing.ID)
 	}
 
+	// Versions are checked for compatibility in the order they are confirmed, so every node reaches the same result
+	previous, err := dh.data.GetLatestDatatype(ctx, dt.Name)
+	if err != nil {
+		return HandlerResult{Action: core.ActionRetry}, err
+	}
+	if err := dh.data.CheckDatatypeCompatibility(ctx, previous, &dt); err != nil {
+		return HandlerResult{Action: core.ActionReject}, i18n.NewError(ctx, coremsgs.MsgDefRejectedSchemaFail, "datatype", dt.ID, err)
+	}
+
 	if err = dh.database.UpsertDatatype(ctx, &dt, false); err != nil {
 		return HandlerResult{Action: core.ActionRetry}, err
 	}
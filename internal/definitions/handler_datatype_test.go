@@ -49,6 +49,8 @@ func TestHandleDefinitionBroadcastDatatypeOk(t *testing.T) {
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, nil)
+	dh.mdm.On("GetLatestDatatype", mock.Anything, "name1").Return(nil, nil)
+	dh.mdm.On("CheckDatatypeCompatibility", mock.Anything, (*core.Datatype)(nil), mock.Anything).Return(nil)
 	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(nil)
 	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(nil)
 
@@ -84,6 +86,8 @@ func TestHandleDefinitionBroadcastDatatypeEventFail(t *testing.T) {
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, nil)
+	dh.mdm.On("GetLatestDatatype", mock.Anything, "name1").Return(nil, nil)
+	dh.mdm.On("CheckDatatypeCompatibility", mock.Anything, (*core.Datatype)(nil), mock.Anything).Return(nil)
 	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(nil)
 	dh.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
 	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
@@ -234,6 +238,8 @@ func TestHandleDefinitionBroadcastUpsertFail(t *testing.T) {
 
 	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
 	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver1").Return(nil, nil)
+	dh.mdm.On("GetLatestDatatype", mock.Anything, "name1").Return(nil, nil)
+	dh.mdm.On("CheckDatatypeCompatibility", mock.Anything, (*core.Datatype)(nil), mock.Anything).Return(nil)
 	dh.mdi.On("UpsertDatatype", mock.Anything, mock.Anything, false).Return(fmt.Errorf("pop"))
 	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
 		Header: core.MessageHeader{
@@ -277,3 +283,71 @@ func TestHandleDefinitionBroadcastDatatypeDuplicate(t *testing.T) {
 
 	bs.assertNoFinalizers()
 }
+
+func TestHandleDefinitionBroadcastDatatypeLatestFail(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	dt := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Namespace: "ns1",
+		Name:      "name1",
+		Version:   "ver2",
+		Value:     fftypes.JSONAnyPtr(`{}`),
+	}
+	dt.Hash = dt.Value.Hash()
+	b, err := json.Marshal(&dt)
+	assert.NoError(t, err)
+	data := &core.Data{
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+
+	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver2").Return(nil, nil)
+	dh.mdm.On("GetLatestDatatype", mock.Anything, "name1").Return(nil, fmt.Errorf("pop"))
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Tag: core.SystemTagDefineDatatype,
+		},
+	}, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionRetry}, action)
+	assert.EqualError(t, err, "pop")
+
+	bs.assertNoFinalizers()
+}
+
+func TestHandleDefinitionBroadcastDatatypeIncompatible(t *testing.T) {
+	dh, bs := newTestDefinitionHandler(t)
+	defer dh.cleanup(t)
+
+	dt := &core.Datatype{
+		ID:        fftypes.NewUUID(),
+		Validator: core.ValidatorTypeJSON,
+		Namespace: "ns1",
+		Name:      "name1",
+		Version:   "ver2",
+		Value:     fftypes.JSONAnyPtr(`{}`),
+	}
+	dt.Hash = dt.Value.Hash()
+	b, err := json.Marshal(&dt)
+	assert.NoError(t, err)
+	data := &core.Data{
+		Value: fftypes.JSONAnyPtrBytes(b),
+	}
+
+	previous := &core.Datatype{Name: "name1", Version: "ver1"}
+	dh.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	dh.mdi.On("GetDatatypeByName", mock.Anything, "ns1", "name1", "ver2").Return(nil, nil)
+	dh.mdm.On("GetLatestDatatype", mock.Anything, "name1").Return(previous, nil)
+	dh.mdm.On("CheckDatatypeCompatibility", mock.Anything, previous, mock.Anything).Return(fmt.Errorf("pop"))
+	action, err := dh.HandleDefinitionBroadcast(context.Background(), &bs.BatchState, &core.Message{
+		Header: core.MessageHeader{
+			Tag: core.SystemTagDefineDatatype,
+		},
+	}, core.DataArray{data}, fftypes.NewUUID())
+	assert.Equal(t, HandlerResult{Action: core.ActionReject}, action)
+	assert.Regexp(t, "FF10406.*pop", err)
+
+	bs.assertNoFinalizers()
+}
@@ -110,7 +110,7 @@ func (dh *definitionHandler) handleTokenPoolDefinition(ctx context.Context, stat
 
 func (dh *definitionHandler) reconcilePublishedPool(ctx context.Context, existing, pool *core.TokenPool, isAuthor bool) (core.MessageAction, error) {
 	if existing.Message.Equals(pool.Message) {
-		if existing.State == core.TokenPoolStateConfirmed {
+		if existing.State != core.TokenPoolStatePending {
 			// Pool was previously activated - this must be a rewind to confirm the message
 			return core.ActionConfirm, nil
 		} else {
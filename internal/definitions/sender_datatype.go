@@ -42,6 +42,14 @@ func (ds *definitionSender) DefineDatatype(ctx context.Context, datatype *core.D
 		if err := ds.data.CheckDatatype(ctx, datatype); err != nil {
 			return err
 		}
+		// Check the new version is compatible with the latest version of the datatype
+		previous, err := ds.data.GetLatestDatatype(ctx, datatype.Name)
+		if err != nil {
+			return err
+		}
+		if err := ds.data.CheckDatatypeCompatibility(ctx, previous, datatype); err != nil {
+			return err
+		}
 
 		datatype.Namespace = ""
 		msg, err := ds.getSenderDefault(ctx, datatype, core.SystemTagDefineDatatype).send(ctx, waitConfirm)
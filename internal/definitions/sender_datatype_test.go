@@ -44,6 +44,8 @@ func TestBroadcastDatatypeBadValue(t *testing.T) {
 	ds.multiparty = true
 
 	ds.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	ds.mdm.On("GetLatestDatatype", mock.Anything, "ent1").Return(nil, nil)
+	ds.mdm.On("CheckDatatypeCompatibility", mock.Anything, (*core.Datatype)(nil), mock.Anything).Return(nil)
 	ds.mim.On("GetRootOrg", context.Background()).Return(&core.Identity{
 		IdentityBase: core.IdentityBase{
 			DID: "firefly:org1",
@@ -76,6 +78,42 @@ func TestDefineDatatypeInvalid(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestDefineDatatypeLatestFail(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	ds.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	ds.mdm.On("GetLatestDatatype", mock.Anything, "ent1").Return(nil, fmt.Errorf("pop"))
+
+	err := ds.DefineDatatype(context.Background(), &core.Datatype{
+		Namespace: "ns1",
+		Name:      "ent1",
+		Version:   "0.0.2",
+		Value:     fftypes.JSONAnyPtr(`{"some": "data"}`),
+	}, false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDefineDatatypeIncompatible(t *testing.T) {
+	ds := newTestDefinitionSender(t)
+	defer ds.cleanup(t)
+	ds.multiparty = true
+
+	previous := &core.Datatype{Name: "ent1", Version: "0.0.1"}
+	ds.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	ds.mdm.On("GetLatestDatatype", mock.Anything, "ent1").Return(previous, nil)
+	ds.mdm.On("CheckDatatypeCompatibility", mock.Anything, previous, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := ds.DefineDatatype(context.Background(), &core.Datatype{
+		Namespace: "ns1",
+		Name:      "ent1",
+		Version:   "0.0.2",
+		Value:     fftypes.JSONAnyPtr(`{"some": "data"}`),
+	}, false)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestBroadcastOk(t *testing.T) {
 	ds := newTestDefinitionSender(t)
 	defer ds.cleanup(t)
@@ -89,6 +127,8 @@ func TestBroadcastOk(t *testing.T) {
 	}, nil)
 	ds.mim.On("ResolveInputSigningIdentity", mock.Anything, mock.Anything).Return(nil)
 	ds.mdm.On("CheckDatatype", mock.Anything, mock.Anything).Return(nil)
+	ds.mdm.On("GetLatestDatatype", mock.Anything, "ent1").Return(nil, nil)
+	ds.mdm.On("CheckDatatypeCompatibility", mock.Anything, (*core.Datatype)(nil), mock.Anything).Return(nil)
 	ds.mbm.On("NewBroadcast", mock.Anything).Return(mms)
 	mms.On("Send", context.Background()).Return(nil)
 
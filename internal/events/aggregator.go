@@ -60,6 +60,9 @@ type aggregator struct {
 	metrics      metrics.Manager
 	batchCache   cache.CInterface
 	rewinder     *rewinder
+	strictTopics map[string]bool
+	sla          *messageSLA
+	deferred     *core.DeferredConfirmPolicy
 }
 
 type batchCacheEntry struct {
@@ -91,11 +94,11 @@ func privatePinHash(topic string, group *fftypes.Bytes32, identity string, nonce
 	return fftypes.HashResult(h)
 }
 
-func newAggregator(ctx context.Context, ns string, di database.Plugin, bi blockchain.Plugin, pm privatemessaging.Manager, sh definitions.Handler, im identity.Manager, dm data.Manager, en *eventNotifier, mm metrics.Manager, cacheManager cache.Manager) (*aggregator, error) {
+func newAggregator(ctx context.Context, ns *core.Namespace, di database.Plugin, bi blockchain.Plugin, pm privatemessaging.Manager, sh definitions.Handler, im identity.Manager, dm data.Manager, en *eventNotifier, mm metrics.Manager, cacheManager cache.Manager) (*aggregator, error) {
 	batchSize := config.GetInt(coreconfig.EventAggregatorBatchSize)
 	ag := &aggregator{
 		ctx:          log.WithLogField(ctx, "role", "aggregator"),
-		namespace:    ns,
+		namespace:    ns.Name,
 		database:     di,
 		messaging:    pm,
 		definitions:  sh,
@@ -103,6 +106,12 @@ func newAggregator(ctx context.Context, ns string, di database.Plugin, bi blockc
 		data:         dm,
 		verifierType: bi.VerifierType(),
 		metrics:      mm,
+		strictTopics: make(map[string]bool),
+		sla:          newMessageSLA(ns, di, im, mm),
+		deferred:     ns.DeferredConfirm,
+	}
+	for _, topic := range config.GetStringSlice(coreconfig.EventAggregatorStrictTopics) {
+		ag.strictTopics[topic] = true
 	}
 
 	batchCache, err := cacheManager.GetCache(
@@ -110,7 +119,7 @@ func newAggregator(ctx context.Context, ns string, di database.Plugin, bi blockc
 			ctx,
 			coreconfig.CacheBatchLimit,
 			coreconfig.CacheBatchTTL,
-			ns,
+			ns.Name,
 		),
 	)
 	if err != nil {
@@ -129,7 +138,7 @@ func newAggregator(ctx context.Context, ns string, di database.Plugin, bi blockc
 			Factor:       config.GetFloat64(coreconfig.EventAggregatorRetryFactor),
 		},
 		firstEvent:       &firstEvent,
-		namespace:        ns,
+		namespace:        ns.Name,
 		offsetType:       core.OffsetTypeAggregator,
 		offsetName:       aggregatorOffsetName,
 		newEventsHandler: ag.processPinsEventsHandler,
@@ -243,6 +252,7 @@ func (ag *aggregator) processWithBatchState(callback func(ctx context.Context, s
 		}
 	}
 	state.queueRewinds(ag)
+	state.sendDeliveryReceipts(ag.ctx)
 	return nil
 }
 
@@ -462,6 +472,7 @@ func (ag *aggregator) processMessage(ctx context.Context, manifest *core.BatchMa
 	nextPins := make([]*nextPinState, 0)
 	action := core.ActionWait
 	var correlator *fftypes.UUID
+	var gaps []string
 
 	var cro data.CacheReadOption
 	if pin.Masked {
@@ -470,6 +481,13 @@ func (ag *aggregator) processMessage(ctx context.Context, manifest *core.BatchMa
 		cro = data.CRORequirePublicBlobRefs
 	}
 	msg, data, dataAvailable, err := ag.data.GetMessageWithDataCached(ctx, msgEntry.ID, cro)
+	if err == nil && msg != nil && !dataAvailable && len(msg.Chunks) > 0 {
+		// The chunks of the message are on the same topics, so have been dispatched ahead of it
+		var reassembled bool
+		if reassembled, err = ag.reassembleChunks(ctx, msg); reassembled {
+			msg, data, dataAvailable, err = ag.data.GetMessageWithDataCached(ctx, msgEntry.ID, cro)
+		}
+	}
 	switch {
 	case err != nil:
 		return err
@@ -527,6 +545,10 @@ func (ag *aggregator) processMessage(ctx context.Context, manifest *core.BatchMa
 			l.Debugf("Attempt dispatch msg=%s broadcastContexts=%v privatePins=%v", msg.Header.ID, unmaskedContexts, msg.Pins)
 			action, correlator, err = ag.readyForDispatch(ctx, msg, data, manifest.TX.ID, state, pin)
 		}
+		if action == core.ActionConfirm && len(ag.strictTopics) > 0 {
+			// Messages on strict topics are only confirmed in the order of their topic sequence
+			action, gaps, err = ag.checkTopicSequence(ctx, msg, state)
+		}
 	}
 
 	if action == core.ActionRetry {
@@ -544,7 +566,33 @@ func (ag *aggregator) processMessage(ctx context.Context, manifest *core.BatchMa
 		msg.RejectReason = err.Error()
 	}
 
-	newState := ag.completeDispatch(action, correlator, msg, manifest.TX.ID, state)
+	var newState core.MessageState
+	switch {
+	case len(gaps) > 0:
+		l.Infof("Message '%s' with topicSequence %d parked on strict topics %v", msg.Header.ID, msg.Header.TopicSequence, gaps)
+		ag.parkMessage(msg, gaps, manifest.TX.ID, state)
+		newState = core.MessageStateParked
+	case action == core.ActionConfirm && ag.deferConfirm(msg):
+		l.Infof("Message '%s' held for the application to accept or reject", msg.Header.ID)
+		ag.holdMessage(msg, manifest.TX.ID, state)
+		newState = core.MessageStatePendingApp
+	default:
+		newState = ag.completeDispatch(action, correlator, msg, manifest.TX.ID, state)
+		if newState == core.MessageStateConfirmed {
+			if err := ag.checkConfirmSLA(ctx, msg, batch.Node, manifest.TX.ID, state); err != nil {
+				return err
+			}
+		}
+		if newState == core.MessageStateConfirmed && pin.Masked && msg.Header.Type == core.MessageTypePrivate {
+			state.markMessageDelivered(manifest.ID, batch.Node, manifest.TX.ID, msg)
+		}
+	}
+	if (newState == core.MessageStateConfirmed || newState == core.MessageStatePendingApp) && len(ag.strictTopics) > 0 {
+		// A message held for the application has taken its place in the sequence, even if it is later rejected
+		if err := ag.advanceTopicSequence(ctx, msg, state); err != nil {
+			return err
+		}
+	}
 
 	// Mark all message pins dispatched, and increment all nextPins
 	for _, np := range nextPins {
@@ -626,8 +674,22 @@ func (ag *aggregator) readyForDispatch(ctx context.Context, msg *core.Message, d
 		action = handlerResult.Action
 
 	case msg.Header.Type == core.MessageTypeGroupInit:
-		// Already handled as part of resolving the context
-		action = core.ActionConfirm
+		// The group is handled as part of resolving the context, but changes to the members of a group
+		// are notified with an additional event
+		var changed bool
+		action, changed, err = ag.messaging.ResolveGroupChange(ctx, msg, data)
+		if changed && action == core.ActionConfirm {
+			ag.addGroupMembershipChangedEvents(msg, tx, state)
+		}
+
+	case msg.Header.Type == core.MessageTypePrivate && msg.Header.Tag == core.SystemTagRecallMessage:
+		// A recall marks an earlier message from the same author in the group as recalled, once it is confirmed
+		var recalled *core.Message
+		var purge bool
+		action, recalled, purge, err = ag.messaging.ResolveRecall(ctx, msg, data)
+		if recalled != nil && action == core.ActionConfirm {
+			ag.addMessageRecalled(msg, recalled, purge, tx, state)
+		}
 
 	case len(msg.Data) > 0:
 		var valid bool
@@ -654,6 +716,10 @@ func (ag *aggregator) completeDispatch(action core.MessageAction, correlator *ff
 	}
 
 	state.AddFinalize(func(ctx context.Context) error {
+		if msg.Header.Type == core.MessageTypeChunk {
+			// Chunks are delivered as part of the message they belong to, so do not have events
+			return nil
+		}
 		// Generate the appropriate event - one per topic (events cover a single topic)
 		for _, topic := range msg.Header.Topics {
 			event := core.NewEvent(eventType, ag.namespace, msg.Header.ID, tx, topic)
@@ -674,6 +740,70 @@ func (ag *aggregator) completeDispatch(action core.MessageAction, correlator *ff
 	return newState
 }
 
+func (ag *aggregator) addGroupMembershipChangedEvents(msg *core.Message, tx *fftypes.UUID, state *batchState) {
+	state.AddFinalize(func(ctx context.Context) error {
+		for _, topic := range msg.Header.Topics {
+			event := core.NewEvent(core.EventTypeGroupMembershipChanged, ag.namespace, msg.Header.ID, tx, topic)
+			event.Correlator = msg.Header.CID
+			if err := ag.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (ag *aggregator) addMessageRecalled(msg, recalled *core.Message, purge bool, tx *fftypes.UUID, state *batchState) {
+	state.markMessageRecalled(recalled.Header.ID)
+	if purge {
+		// Purging deletes blobs from data exchange, so is performed outside the database transaction
+		state.AddPreFinalize(func(ctx context.Context) error {
+			return ag.purgeMessageData(ctx, recalled)
+		})
+	}
+	state.AddFinalize(func(ctx context.Context) error {
+		update := database.MessageQueryFactory.NewUpdate(ctx).Set("state", core.MessageStateRecalled)
+		if err := ag.database.UpdateMessage(ctx, ag.namespace, recalled.Header.ID, update); err != nil {
+			return err
+		}
+		ag.data.UpdateMessageStateIfCached(ctx, recalled.Header.ID, core.MessageStateRecalled, recalled.Confirmed, "")
+		for _, topic := range recalled.Header.Topics {
+			event := core.NewEvent(core.EventTypeMessageRecalled, ag.namespace, recalled.Header.ID, tx, topic)
+			event.Correlator = msg.Header.ID
+			if err := ag.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// purgeMessageData deletes the data of a recalled message, and any blobs, unless the data is also referenced by
+// another message. Data that has already been deleted is skipped, so the purge is safe to retry.
+func (ag *aggregator) purgeMessageData(ctx context.Context, msg *core.Message) error {
+	for _, dataRef := range msg.Data {
+		d, err := ag.database.GetDataByID(ctx, ag.namespace, dataRef.ID, false)
+		if err != nil {
+			return err
+		}
+		if d == nil {
+			continue
+		}
+		msgs, _, err := ag.database.GetMessagesForData(ctx, ag.namespace, dataRef.ID, database.MessageQueryFactory.NewFilter(ctx).And())
+		if err != nil {
+			return err
+		}
+		if len(msgs) > 1 {
+			log.L(ctx).Infof("Data %s of recalled message %s is referenced by other messages, and will not be purged", dataRef.ID, msg.Header.ID)
+			continue
+		}
+		if err := ag.data.DeleteData(ctx, dataRef.ID.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // resolveBlobs ensures that the blobs for all the attachments in the data array, have been received into the
 // local data exchange blob store. Either because of a private transfer, or by downloading them from the shared storage
 func (ag *aggregator) resolveBlobs(ctx context.Context, data core.DataArray) (resolved bool, err error) {
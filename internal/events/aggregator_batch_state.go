@@ -38,6 +38,8 @@ func newBatchState(ag *aggregator) *batchState {
 		maskedContexts:     make(map[fftypes.Bytes32]*nextPinGroupState),
 		unmaskedContexts:   make(map[fftypes.Bytes32]*contextState),
 		dispatchedMessages: make([]*dispatchedMessage, 0),
+		deliveredBatches:   make(map[fftypes.UUID]*deliveredBatch),
+		topicSequences:     make(map[fftypes.Bytes32]*topicSequenceState),
 		BatchState: core.BatchState{
 			PendingConfirms: make(map[fftypes.UUID]*core.Message),
 		},
@@ -79,6 +81,14 @@ type dispatchedMessage struct {
 	rejectReason  string
 }
 
+// deliveredBatch tracks the private messages confirmed from a batch sent by another node,
+// so delivery receipts can be sent back to that node once the confirmations are committed
+type deliveredBatch struct {
+	node     *fftypes.UUID
+	txID     *fftypes.UUID
+	messages []*core.Message
+}
+
 // batchState is the object that tracks the in-memory state that builds up while processing a batch of pins,
 // that needs to be reconciled at the point the batch closes.
 // There are three phases:
@@ -103,6 +113,8 @@ type batchState struct {
 	maskedContexts     map[fftypes.Bytes32]*nextPinGroupState
 	unmaskedContexts   map[fftypes.Bytes32]*contextState
 	dispatchedMessages []*dispatchedMessage
+	deliveredBatches   map[fftypes.UUID]*deliveredBatch
+	topicSequences     map[fftypes.Bytes32]*topicSequenceState
 }
 
 func (bs *batchState) RunPreFinalize(ctx context.Context) error {
@@ -113,6 +125,9 @@ func (bs *batchState) RunFinalize(ctx context.Context) error {
 	if err := bs.BatchState.RunFinalize(ctx); err != nil {
 		return err
 	}
+	if err := bs.flushTopicSequences(ctx); err != nil {
+		return err
+	}
 	return bs.flushPins(ctx)
 }
 
@@ -122,6 +137,23 @@ func (bs *batchState) queueRewinds(ag *aggregator) {
 	}
 }
 
+func (bs *batchState) markMessageDelivered(batchID, node, txID *fftypes.UUID, msg *core.Message) {
+	delivered, ok := bs.deliveredBatches[*batchID]
+	if !ok {
+		delivered = &deliveredBatch{node: node, txID: txID}
+		bs.deliveredBatches[*batchID] = delivered
+	}
+	delivered.messages = append(delivered.messages, msg)
+}
+
+func (bs *batchState) sendDeliveryReceipts(ctx context.Context) {
+	for batchID, delivered := range bs.deliveredBatches {
+		if err := bs.messaging.SendDeliveryReceipts(ctx, delivered.node, delivered.txID, delivered.messages); err != nil {
+			log.L(ctx).Warnf("Failed to send delivery receipts for batch '%s': %s", &batchID, err)
+		}
+	}
+}
+
 func (bs *batchState) checkUnmaskedContextReady(ctx context.Context, contextUnmasked *fftypes.Bytes32, msg *core.Message, firstMsgPinSequence int64) (bool, error) {
 
 	ucs, found := bs.unmaskedContexts[*contextUnmasked]
@@ -208,6 +240,16 @@ func (bs *batchState) markMessageDispatched(batchID *fftypes.UUID, msg *core.Mes
 	})
 }
 
+// markMessageRecalled updates the state of a message that was dispatched earlier in this batch, and has been
+// recalled by a later message in the batch, so the recall is not overwritten when the pins are flushed
+func (bs *batchState) markMessageRecalled(msgID *fftypes.UUID) {
+	for _, dm := range bs.dispatchedMessages {
+		if dm.msgID.Equals(msgID) {
+			dm.newState = core.MessageStateRecalled
+		}
+	}
+}
+
 func (bs *batchState) SetContextBlockedBy(ctx context.Context, unmaskedContext fftypes.Bytes32, blockedBy int64) {
 	ucs, found := bs.unmaskedContexts[unmaskedContext]
 	if !found {
@@ -303,7 +345,12 @@ func (bs *batchState) flushPins(ctx context.Context) error {
 
 	// Also do the same for each type of state update, to mark messages dispatched with a new state
 	for msgState, msgIDs := range msgStateUpdates {
-		if err := bs.confirmMessages(ctx, msgIDs, msgState, confirmTime, ""); err != nil {
+		msgConfirmed := confirmTime
+		if msgState == core.MessageStateParked || msgState == core.MessageStatePendingApp {
+			// Parked messages are not confirmed until they are released, and held messages until they are accepted
+			msgConfirmed = nil
+		}
+		if err := bs.confirmMessages(ctx, msgIDs, msgState, msgConfirmed, ""); err != nil {
 			return err
 		}
 	}
@@ -79,3 +79,21 @@ func TestSetContextBlockedByNoState(t *testing.T) {
 	assert.NoError(t, err)
 	assert.False(t, ready)
 }
+
+func TestSendDeliveryReceipts(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	batchID := fftypes.NewUUID()
+	nodeID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	msg1 := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	msg2 := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	bs.markMessageDelivered(batchID, nodeID, txID, msg1)
+	bs.markMessageDelivered(batchID, nodeID, txID, msg2)
+
+	ag.mpm.On("SendDeliveryReceipts", ag.ctx, nodeID, txID, []*core.Message{msg1, msg2}).Return(fmt.Errorf("pop"))
+
+	bs.sendDeliveryReceipts(ag.ctx)
+}
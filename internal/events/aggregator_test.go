@@ -89,7 +89,7 @@ func newTestAggregatorCommon(metrics bool) *testAggregator {
 	}
 	mmi.On("IsMetricsEnabled").Return(metrics).Maybe()
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
-	ag, _ := newAggregator(ctx, "ns1", mdi, mbi, mpm, mdh, mim, mdm, newEventNotifier(ctx, "ut"), mmi, cmi)
+	ag, _ := newAggregator(ctx, &core.Namespace{Name: "ns1"}, mdi, mbi, mpm, mdh, mim, mdm, newEventNotifier(ctx, "ut"), mmi, cmi)
 	cancel := func() {
 		ctxCancel()
 		if ag.batchCache != nil {
@@ -187,14 +187,14 @@ func TestNewAggregator(t *testing.T) {
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	mbi := &blockchainmocks.Plugin{}
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
-	ns := "ns1"
+	ns := &core.Namespace{Name: "ns1"}
 	_, err := newAggregator(ctx, ns, mdi, mbi, mpm, mdh, mim, mdm, newEventNotifier(ctx, "ut"), mmi, cmi)
 	assert.NoError(t, err)
 	cmi.AssertCalled(t, "GetCache", cache.NewCacheConfig(
 		ctx,
 		coreconfig.CacheBatchLimit,
 		coreconfig.CacheBatchTTL,
-		ns,
+		ns.Name,
 	))
 }
 func TestCacheInitFail(t *testing.T) {
@@ -212,7 +212,7 @@ func TestCacheInitFail(t *testing.T) {
 	cmi.On("GetCache", mock.Anything).Return(nil, cacheInitError)
 	mbi := &blockchainmocks.Plugin{}
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress)
-	ns := "ns1"
+	ns := &core.Namespace{Name: "ns1"}
 	_, err := newAggregator(ctx, ns, mdi, mbi, mpm, mdh, mim, mdm, newEventNotifier(ctx, "ut"), mmi, cmi)
 	assert.Equal(t, cacheInitError, err)
 }
@@ -2023,16 +2023,243 @@ func TestReadyForDispatchGroupInit(t *testing.T) {
 	bs := newBatchState(&ag.aggregator)
 	org1 := newTestOrg("org1")
 
-	action, _, err := ag.readyForDispatch(ag.ctx, &core.Message{
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Type:      core.MessageTypeGroupInit,
+			SignerRef: core.SignerRef{Key: "0x12345", Author: org1.DID},
+		},
+	}
+	ag.mpm.On("ResolveGroupChange", ag.ctx, msg, core.DataArray(nil)).Return(core.ActionConfirm, false, nil)
+
+	action, _, err := ag.readyForDispatch(ag.ctx, msg, nil, nil, bs, &core.Pin{Signer: "0x12345"})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+
+	err = bs.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+}
+
+func TestReadyForDispatchGroupMembershipChanged(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	org1 := newTestOrg("org1")
+
+	msg := &core.Message{
 		Header: core.MessageHeader{
 			ID:        fftypes.NewUUID(),
+			CID:       fftypes.NewUUID(),
 			Type:      core.MessageTypeGroupInit,
+			Tag:       core.SystemTagChangeGroup,
+			Topics:    fftypes.FFStringArray{"topic1"},
 			SignerRef: core.SignerRef{Key: "0x12345", Author: org1.DID},
 		},
-	}, nil, nil, bs, &core.Pin{Signer: "0x12345"})
+	}
+	tx := fftypes.NewUUID()
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	ag.mpm.On("ResolveGroupChange", ag.ctx, msg, data).Return(core.ActionConfirm, true, nil)
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeGroupMembershipChanged && e.Reference.Equals(msg.Header.ID) &&
+			e.Correlator.Equals(msg.Header.CID) && e.Topic == "topic1" && e.Transaction.Equals(tx)
+	})).Return(nil)
+
+	action, _, err := ag.readyForDispatch(ag.ctx, msg, data, tx, bs, &core.Pin{Signer: "0x12345"})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+
+	err = bs.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+}
+
+func TestReadyForDispatchGroupMembershipChangedEventFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypeGroupInit,
+			Tag:    core.SystemTagChangeGroup,
+			Topics: fftypes.FFStringArray{"topic1"},
+		},
+	}
+	ag.mpm.On("ResolveGroupChange", ag.ctx, msg, core.DataArray(nil)).Return(core.ActionConfirm, true, nil)
+	ag.mdi.On("InsertEvent", ag.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, _, err := ag.readyForDispatch(ag.ctx, msg, nil, nil, bs, &core.Pin{})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+
+	err = bs.RunFinalize(ag.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestReadyForDispatchGroupChangeRetry(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:   fftypes.NewUUID(),
+			Type: core.MessageTypeGroupInit,
+			Tag:  core.SystemTagChangeGroup,
+		},
+	}
+	ag.mpm.On("ResolveGroupChange", ag.ctx, msg, core.DataArray(nil)).Return(core.ActionRetry, false, fmt.Errorf("pop"))
+
+	action, _, err := ag.readyForDispatch(ag.ctx, msg, nil, nil, bs, &core.Pin{})
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, core.ActionRetry, action)
+}
+
+func newTestRecalledMessage() (recall, recalled *core.Message) {
+	recalled = &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			Type:   core.MessageTypePrivate,
+			Topics: fftypes.FFStringArray{"topic1", "topic2"},
+		},
+		Data: core.DataRefs{
+			{ID: fftypes.NewUUID()},
+			{ID: fftypes.NewUUID()},
+			{ID: fftypes.NewUUID()},
+		},
+		Confirmed: fftypes.Now(),
+	}
+	recall = &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			CID:    recalled.Header.ID,
+			Type:   core.MessageTypePrivate,
+			Tag:    core.SystemTagRecallMessage,
+			Topics: recalled.Header.Topics,
+		},
+	}
+	return recall, recalled
+}
+
+func TestReadyForDispatchRecall(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg, recalled := newTestRecalledMessage()
+	bs.markMessageDispatched(fftypes.NewUUID(), recalled, 0, core.MessageStateConfirmed)
+	tx := fftypes.NewUUID()
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	ag.mpm.On("ResolveRecall", ag.ctx, msg, data).Return(core.ActionConfirm, recalled, true, nil)
+	ag.mdi.On("GetDataByID", ag.ctx, "ns1", recalled.Data[0].ID, false).Return(&core.Data{ID: recalled.Data[0].ID}, nil)
+	ag.mdi.On("GetMessagesForData", ag.ctx, "ns1", recalled.Data[0].ID, mock.Anything).Return([]*core.Message{recalled}, nil, nil)
+	ag.mdm.On("DeleteData", ag.ctx, recalled.Data[0].ID.String()).Return(nil)
+	ag.mdi.On("GetDataByID", ag.ctx, "ns1", recalled.Data[1].ID, false).Return(nil, nil)
+	ag.mdi.On("GetDataByID", ag.ctx, "ns1", recalled.Data[2].ID, false).Return(&core.Data{ID: recalled.Data[2].ID}, nil)
+	ag.mdi.On("GetMessagesForData", ag.ctx, "ns1", recalled.Data[2].ID, mock.Anything).Return([]*core.Message{recalled, {}}, nil, nil)
+	ag.mdi.On("UpdateMessage", ag.ctx, "ns1", recalled.Header.ID, mock.Anything).Return(nil)
+	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, recalled.Header.ID, core.MessageStateRecalled, recalled.Confirmed, "").Return()
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeMessageRecalled && e.Reference.Equals(recalled.Header.ID) &&
+			e.Correlator.Equals(msg.Header.ID) && e.Transaction.Equals(tx)
+	})).Return(nil).Twice()
+
+	action, _, err := ag.readyForDispatch(ag.ctx, msg, data, tx, bs, &core.Pin{})
 	assert.NoError(t, err)
 	assert.Equal(t, core.ActionConfirm, action)
+	assert.Equal(t, core.MessageStateRecalled, bs.dispatchedMessages[0].newState)
+
+	err = bs.RunPreFinalize(ag.ctx)
+	assert.NoError(t, err)
+	err = bs.BatchState.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+}
+
+func TestReadyForDispatchRecallRejected(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg, _ := newTestRecalledMessage()
+	ag.mpm.On("ResolveRecall", ag.ctx, msg, core.DataArray(nil)).Return(core.ActionReject, nil, false, nil)
+
+	action, _, err := ag.readyForDispatch(ag.ctx, msg, nil, nil, bs, &core.Pin{})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+	assert.Empty(t, bs.PreFinalize)
+	assert.Empty(t, bs.Finalize)
+}
 
+func TestReadyForDispatchRecallUpdateFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg, recalled := newTestRecalledMessage()
+	ag.mpm.On("ResolveRecall", ag.ctx, msg, core.DataArray(nil)).Return(core.ActionConfirm, recalled, false, nil)
+	ag.mdi.On("UpdateMessage", ag.ctx, "ns1", recalled.Header.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, _, err := ag.readyForDispatch(ag.ctx, msg, nil, nil, bs, &core.Pin{})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.Empty(t, bs.PreFinalize)
+
+	err = bs.BatchState.RunFinalize(ag.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestReadyForDispatchRecallEventFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg, recalled := newTestRecalledMessage()
+	ag.mpm.On("ResolveRecall", ag.ctx, msg, core.DataArray(nil)).Return(core.ActionConfirm, recalled, false, nil)
+	ag.mdi.On("UpdateMessage", ag.ctx, "ns1", recalled.Header.ID, mock.Anything).Return(nil)
+	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, recalled.Header.ID, core.MessageStateRecalled, recalled.Confirmed, "").Return()
+	ag.mdi.On("InsertEvent", ag.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, _, err := ag.readyForDispatch(ag.ctx, msg, nil, nil, bs, &core.Pin{})
+	assert.NoError(t, err)
+
+	err = bs.BatchState.RunFinalize(ag.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPurgeMessageDataGetDataFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	_, recalled := newTestRecalledMessage()
+	ag.mdi.On("GetDataByID", ag.ctx, "ns1", recalled.Data[0].ID, false).Return(nil, fmt.Errorf("pop"))
+
+	err := ag.purgeMessageData(ag.ctx, recalled)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPurgeMessageDataGetMessagesFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	_, recalled := newTestRecalledMessage()
+	ag.mdi.On("GetDataByID", ag.ctx, "ns1", recalled.Data[0].ID, false).Return(&core.Data{ID: recalled.Data[0].ID}, nil)
+	ag.mdi.On("GetMessagesForData", ag.ctx, "ns1", recalled.Data[0].ID, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := ag.purgeMessageData(ag.ctx, recalled)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestPurgeMessageDataDeleteFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	_, recalled := newTestRecalledMessage()
+	ag.mdi.On("GetDataByID", ag.ctx, "ns1", recalled.Data[0].ID, false).Return(&core.Data{ID: recalled.Data[0].ID}, nil)
+	ag.mdi.On("GetMessagesForData", ag.ctx, "ns1", recalled.Data[0].ID, mock.Anything).Return([]*core.Message{recalled}, nil, nil)
+	ag.mdm.On("DeleteData", ag.ctx, recalled.Data[0].ID.String()).Return(fmt.Errorf("pop"))
+
+	err := ag.purgeMessageData(ag.ctx, recalled)
+	assert.Regexp(t, "pop", err)
 }
 
 func TestRewindOffchainBatchesNoBatches(t *testing.T) {
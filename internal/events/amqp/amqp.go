@@ -0,0 +1,292 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	goamqp "github.com/Azure/go-amqp"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// AMQP sends events to queues on an AMQP 1.0 broker, such as RabbitMQ or Azure Service Bus.
+// Each event is sent unsettled, and is only acknowledged once the broker accepts it. If the
+// broker rejects the event, or the connection is lost before it is accepted, the event is
+// rejected so that it is redelivered - along with any others that were not yet accepted - once
+// the connection has been re-established.
+type AMQP struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	connID       string
+	url          string
+	connOptions  *goamqp.ConnOptions
+	queue        string
+	sendTimeout  time.Duration
+	retry        *retry.Retry
+	maxAttempts  int
+	dial         func(ctx context.Context) (connection, error)
+
+	connMux sync.Mutex
+	conn    connection
+	senders map[string]sender
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+// connection is the session on the broker that senders are attached to
+type connection interface {
+	NewSender(ctx context.Context, address string) (sender, error)
+	Close() error
+}
+
+type sender interface {
+	Send(ctx context.Context, msg *goamqp.Message, opts *goamqp.SendOptions) error
+}
+
+type amqpConnection struct {
+	conn    *goamqp.Conn
+	session *goamqp.Session
+}
+
+type messageBody struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+func (a *AMQP) Name() string { return "amqp" }
+
+func (a *AMQP) Init(ctx context.Context, config config.Section) (err error) {
+	url := config.GetString(AMQPConfigURL)
+	if url == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, config.Resolve(AMQPConfigURL), "amqp")
+	}
+	tlsConfig, err := fftls.ConstructTLSConfig(ctx, config.SubSection("tls"), fftls.ClientType)
+	if err != nil {
+		return err
+	}
+	connOptions := &goamqp.ConnOptions{
+		TLSConfig: tlsConfig,
+	}
+	if username := config.GetString(AMQPConfigUsername); username != "" {
+		connOptions.SASLType = goamqp.SASLTypePlain(username, config.GetString(AMQPConfigPassword))
+	}
+
+	connID := fftypes.ShortID()
+	*a = AMQP{
+		ctx:          log.WithLogField(ctx, "amqp", connID),
+		capabilities: &events.Capabilities{},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		connID:      connID,
+		url:         url,
+		connOptions: connOptions,
+		queue:       config.GetString(AMQPConfigQueue),
+		sendTimeout: config.GetDuration(AMQPConfigSendTimeout),
+		retry: &retry.Retry{
+			InitialDelay: config.GetDuration(AMQPConfigReconnectInitialDelay),
+			MaximumDelay: config.GetDuration(AMQPConfigReconnectMaxDelay),
+			Factor:       config.GetFloat64(AMQPConfigReconnectFactor),
+		},
+		maxAttempts: config.GetInt(AMQPConfigReconnectMaxAttempts),
+		senders:     make(map[string]sender),
+	}
+	a.dial = a.dialBroker
+	return nil
+}
+
+func (a *AMQP) SetHandler(namespace string, handler events.Callbacks) error {
+	a.callbacks.writeLock.Lock()
+	defer a.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(a.callbacks.handlers, namespace)
+		return nil
+	}
+	a.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(a.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (a *AMQP) Capabilities() *events.Capabilities {
+	return a.capabilities
+}
+
+func (a *AMQP) subscriptionQueue(options *core.SubscriptionOptions) string {
+	if queue := options.TransportOptions().GetString("queue"); queue != "" {
+		return queue
+	}
+	return a.queue
+}
+
+func (a *AMQP) ValidateOptions(options *core.SubscriptionOptions) error {
+	if a.subscriptionQueue(options) == "" {
+		return i18n.NewError(a.ctx, coremsgs.MsgAMQPQueueEmpty)
+	}
+	return nil
+}
+
+func (a *AMQP) dialBroker(ctx context.Context) (connection, error) {
+	conn, err := goamqp.Dial(ctx, a.url, a.connOptions)
+	if err != nil {
+		return nil, err
+	}
+	session, err := conn.NewSession(ctx, nil)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return &amqpConnection{conn: conn, session: session}, nil
+}
+
+func (c *amqpConnection) NewSender(ctx context.Context, address string) (sender, error) {
+	return c.session.NewSender(ctx, address, &goamqp.SenderOptions{
+		SettlementMode: goamqp.SenderSettleModeUnsettled.Ptr(),
+	})
+}
+
+func (c *amqpConnection) Close() error {
+	return c.conn.Close()
+}
+
+// getSender returns the sender for a queue, connecting to the broker first if required. Connecting is
+// retried up to the configured number of attempts, so that a broker that stays down causes the event to be
+// rejected (and redelivered later) rather than blocking every delivery behind the connection lock.
+func (a *AMQP) getSender(address string) (sender, error) {
+	a.connMux.Lock()
+	defer a.connMux.Unlock()
+	if a.conn == nil {
+		err := a.retry.Do(a.ctx, "connect to AMQP broker", func(attempt int) (retry bool, err error) {
+			a.conn, err = a.dial(a.ctx)
+			return attempt < a.maxAttempts, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.L(a.ctx).Infof("Connected to AMQP broker")
+	}
+	s, ok := a.senders[address]
+	if !ok {
+		var err error
+		if s, err = a.conn.NewSender(a.ctx, address); err != nil {
+			a.disconnectLocked()
+			return nil, err
+		}
+		a.senders[address] = s
+	}
+	return s, nil
+}
+
+// disconnect drops the connection after a failure, so that the next delivery reconnects
+func (a *AMQP) disconnect() {
+	a.connMux.Lock()
+	defer a.connMux.Unlock()
+	a.disconnectLocked()
+}
+
+func (a *AMQP) disconnectLocked() {
+	if a.conn != nil {
+		_ = a.conn.Close()
+		a.conn = nil
+		a.senders = make(map[string]sender)
+	}
+}
+
+func (a *AMQP) publish(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	queue := a.subscriptionQueue(&sub.Options)
+	body := &messageBody{EventDelivery: event}
+	if sub.Options.WithData != nil && *sub.Options.WithData {
+		body.Data = data
+	}
+	b, _ := json.Marshal(body)
+	contentType := "application/json"
+	subject := string(event.Type)
+	msg := &goamqp.Message{
+		Header: &goamqp.MessageHeader{Durable: true},
+		Properties: &goamqp.MessageProperties{
+			MessageID:   event.ID.String(),
+			ContentType: &contentType,
+			Subject:     &subject,
+		},
+		ApplicationProperties: map[string]any{
+			"namespace": event.Namespace,
+			"topic":     event.Topic,
+		},
+		Data: [][]byte{b},
+	}
+
+	s, err := a.getSender(queue)
+	if err != nil {
+		return i18n.WrapError(a.ctx, err, coremsgs.MsgAMQPSendFailed, queue)
+	}
+	log.L(a.ctx).Debugf("AMQP-> queue %s event %s on subscription %s", queue, event.ID, sub.ID)
+	ctx, cancel := context.WithTimeout(a.ctx, a.sendTimeout)
+	defer cancel()
+	if err := s.Send(ctx, msg, nil); err != nil {
+		// A rejection by the broker leaves the link usable. Any other failure means we cannot
+		// know the state of the link, so we reconnect before sending anything else.
+		var rejected *goamqp.Error
+		if !errors.As(err, &rejected) {
+			a.disconnect()
+		}
+		return i18n.WrapError(a.ctx, err, coremsgs.MsgAMQPSendFailed, queue)
+	}
+	log.L(a.ctx).Infof("AMQP<- queue %s event %s on subscription %s accepted", queue, event.ID, sub.ID)
+	return nil
+}
+
+func (a *AMQP) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(a.ctx, coremsgs.MsgBatchDeliveryNotSupported, a.Name())
+}
+
+func (a *AMQP) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	response := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+	if err := a.publish(sub, event, data); err != nil {
+		log.L(a.ctx).Errorf("Failed to deliver event %s over AMQP: %s", event.ID, err)
+		response.Rejected = true
+		response.Info = err.Error()
+	}
+	a.callbacks.writeLock.Lock()
+	cb, ok := a.callbacks.handlers[sub.Namespace]
+	a.callbacks.writeLock.Unlock()
+	if ok {
+		cb.DeliveryResponse(connID, response)
+	}
+	return nil
+}
+
+func (a *AMQP) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
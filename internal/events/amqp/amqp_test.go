@@ -0,0 +1,353 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	goamqp "github.com/Azure/go-amqp"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testConnection struct {
+	senders   map[string]*testSender
+	senderErr error
+	closed    bool
+}
+
+func (c *testConnection) NewSender(ctx context.Context, address string) (sender, error) {
+	if c.senderErr != nil {
+		return nil, c.senderErr
+	}
+	s := &testSender{}
+	c.senders[address] = s
+	return s, nil
+}
+
+func (c *testConnection) Close() error {
+	c.closed = true
+	return nil
+}
+
+type testSender struct {
+	sent []*goamqp.Message
+	err  error
+}
+
+func (s *testSender) Send(ctx context.Context, msg *goamqp.Message, opts *goamqp.SendOptions) error {
+	s.sent = append(s.sent, msg)
+	return s.err
+}
+
+func newTestAMQP(t *testing.T) (a *AMQP, cbs *eventsmocks.Callbacks, done func()) {
+	coreconfig.Reset()
+
+	cbs = &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	a = &AMQP{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	utConfig := config.RootSection("ut.amqp")
+	a.InitConfig(utConfig)
+	utConfig.Set(AMQPConfigURL, "amqp://localhost:5672")
+	utConfig.Set(AMQPConfigUsername, "user")
+	utConfig.Set(AMQPConfigPassword, "pass")
+	utConfig.Set(AMQPConfigReconnectInitialDelay, "1ms")
+	err := a.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	err = a.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.Equal(t, "amqp", a.Name())
+	assert.NotNil(t, a.Capabilities())
+	return a, cbs, func() {
+		cancelCtx()
+		cbs.AssertExpectations(t)
+	}
+}
+
+func newTestConnection(a *AMQP) *testConnection {
+	conn := &testConnection{senders: make(map[string]*testSender)}
+	a.dial = func(ctx context.Context) (connection, error) {
+		return conn, nil
+	}
+	return conn
+}
+
+func newTestAMQPSubscription(queue string) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	if queue != "" {
+		sub.Options.TransportOptions()["queue"] = queue
+	}
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Type:      core.EventTypeMessageConfirmed,
+				Namespace: "ns1",
+				Topic:     "topic1",
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+func TestInitMissingURL(t *testing.T) {
+	coreconfig.Reset()
+	a := &AMQP{}
+	utConfig := config.RootSection("ut.amqp")
+	a.InitConfig(utConfig)
+	err := a.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	a := &AMQP{}
+	utConfig := config.RootSection("ut.amqp")
+	a.InitConfig(utConfig)
+	utConfig.Set(AMQPConfigURL, "amqps://localhost:5671")
+	tlsConfig := utConfig.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCAFile, "BADCA")
+	err := a.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestSetHandlerRemove(t *testing.T) {
+	a, _, done := newTestAMQP(t)
+	defer done()
+
+	err := a.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, a.callbacks.handlers)
+}
+
+func TestValidateOptions(t *testing.T) {
+	a, _, done := newTestAMQP(t)
+	defer done()
+
+	sub, _ := newTestAMQPSubscription("orders")
+	assert.NoError(t, a.ValidateOptions(&sub.Options))
+
+	a.queue = ""
+	sub, _ = newTestAMQPSubscription("")
+	assert.Regexp(t, "FF10526", a.ValidateOptions(&sub.Options))
+}
+
+func TestDeliveryRequestDefaultQueue(t *testing.T) {
+	a, cbs, done := newTestAMQP(t)
+	defer done()
+
+	conn := newTestConnection(a)
+	sub, event := newTestAMQPSubscription("")
+	yes := true
+	sub.Options.WithData = &yes
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":"b"}`)}}
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Return().Twice()
+
+	err := a.DeliveryRequest(a.connID, sub, event, data)
+	assert.NoError(t, err)
+	err = a.DeliveryRequest(a.connID, sub, event, data)
+	assert.NoError(t, err)
+
+	s := conn.senders["firefly-events"]
+	assert.Len(t, s.sent, 2)
+	msg := s.sent[0]
+	assert.Equal(t, event.ID.String(), msg.Properties.MessageID)
+	assert.Equal(t, "message_confirmed", *msg.Properties.Subject)
+	assert.Equal(t, "topic1", msg.ApplicationProperties["topic"])
+	var body fftypes.JSONObject
+	err = json.Unmarshal(msg.Data[0], &body)
+	assert.NoError(t, err)
+	assert.Equal(t, event.ID.String(), body.GetString("id"))
+	assert.Len(t, body.GetObjectArray("data"), 1)
+}
+
+func TestDeliveryRequestRejectedByBroker(t *testing.T) {
+	a, cbs, done := newTestAMQP(t)
+	defer done()
+
+	conn := newTestConnection(a)
+	sub, event := newTestAMQPSubscription("orders")
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return().Once()
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected
+	})).Return().Once()
+
+	_, err := a.getSender("orders")
+	assert.NoError(t, err)
+	conn.senders["orders"].err = &goamqp.Error{Condition: goamqp.ErrCondResourceLimitExceeded}
+	err = a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+
+	// Still connected on the same link
+	assert.False(t, conn.closed)
+	conn.senders["orders"].err = nil
+	err = a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Len(t, conn.senders["orders"].sent, 2)
+}
+
+func TestDeliveryRequestReconnectAfterLinkFailure(t *testing.T) {
+	a, cbs, done := newTestAMQP(t)
+	defer done()
+
+	conn := newTestConnection(a)
+	sub, event := newTestAMQPSubscription("")
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return().Once()
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected
+	})).Return().Once()
+
+	_, err := a.getSender("firefly-events")
+	assert.NoError(t, err)
+	conn.senders["firefly-events"].err = &goamqp.ConnError{}
+	err = a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.True(t, conn.closed)
+	assert.Nil(t, a.conn)
+
+	// The next delivery fails to connect once, and then reconnects
+	newConn := &testConnection{senders: make(map[string]*testSender)}
+	attempts := 0
+	a.dial = func(ctx context.Context) (connection, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, fmt.Errorf("pop")
+		}
+		return newConn, nil
+	}
+	err = a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Len(t, newConn.senders["firefly-events"].sent, 1)
+}
+
+func TestDeliveryRequestNewSenderFail(t *testing.T) {
+	a, cbs, done := newTestAMQP(t)
+	defer done()
+
+	conn := newTestConnection(a)
+	conn.senderErr = fmt.Errorf("pop")
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return()
+
+	sub, event := newTestAMQPSubscription("")
+	err := a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.True(t, conn.closed)
+	assert.Nil(t, a.conn)
+}
+
+func TestDeliveryRequestConnectCancelled(t *testing.T) {
+	a, cbs, done := newTestAMQP(t)
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a.ctx = ctx
+	a.dial = func(ctx context.Context) (connection, error) {
+		return nil, fmt.Errorf("pop")
+	}
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return()
+
+	sub, event := newTestAMQPSubscription("")
+	err := a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestConnectAttemptsExhausted(t *testing.T) {
+	a, cbs, done := newTestAMQP(t)
+	defer done()
+
+	attempts := 0
+	a.dial = func(ctx context.Context) (connection, error) {
+		attempts++
+		return nil, fmt.Errorf("pop")
+	}
+	cbs.On("DeliveryResponse", a.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return()
+
+	sub, event := newTestAMQPSubscription("")
+	err := a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultReconnectMaxAttempts, attempts)
+	assert.Nil(t, a.conn)
+}
+
+func TestDeliveryRequestNoHandler(t *testing.T) {
+	a, _, done := newTestAMQP(t)
+	defer done()
+
+	newTestConnection(a)
+	sub, event := newTestAMQPSubscription("")
+	sub.Namespace = "ns2"
+
+	err := a.DeliveryRequest(a.connID, sub, event, nil)
+	assert.NoError(t, err)
+	a.NamespaceRestarted("ns1", time.Now())
+}
+
+func TestDialBrokerFail(t *testing.T) {
+	a, _, done := newTestAMQP(t)
+	defer done()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}()
+	defer l.Close()
+
+	a.url = fmt.Sprintf("amqp://%s", l.Addr())
+	_, err = a.dialBroker(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,63 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amqp
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+)
+
+const (
+	// AMQPConfigURL is the amqp:// or amqps:// URL of the broker
+	AMQPConfigURL = "url"
+	// AMQPConfigUsername is the username for SASL PLAIN authentication
+	AMQPConfigUsername = "username"
+	// AMQPConfigPassword is the password for SASL PLAIN authentication
+	AMQPConfigPassword = "password"
+	// AMQPConfigQueue is the queue events are sent to, for subscriptions that do not set their own
+	AMQPConfigQueue = "queue"
+	// AMQPConfigSendTimeout is how long to wait for the broker to confirm each event
+	AMQPConfigSendTimeout = "sendTimeout"
+	// AMQPConfigReconnectInitialDelay is the delay before the first attempt to reconnect to the broker
+	AMQPConfigReconnectInitialDelay = "reconnect.initialDelay"
+	// AMQPConfigReconnectMaxDelay is the maximum delay between attempts to reconnect to the broker
+	AMQPConfigReconnectMaxDelay = "reconnect.maxDelay"
+	// AMQPConfigReconnectFactor is the factor the delay increases by on each attempt to reconnect
+	AMQPConfigReconnectFactor = "reconnect.factor"
+	// AMQPConfigReconnectMaxAttempts is the number of attempts to connect to the broker for each event, before it is rejected
+	AMQPConfigReconnectMaxAttempts = "reconnect.maxAttempts"
+
+	defaultQueue                 = "firefly-events"
+	defaultSendTimeout           = "30s"
+	defaultReconnectInitialDelay = "250ms"
+	defaultReconnectMaxDelay     = "30s"
+	defaultReconnectFactor       = 2.0
+	defaultReconnectMaxAttempts  = 5
+)
+
+func (a *AMQP) InitConfig(config config.Section) {
+	config.AddKnownKey(AMQPConfigURL)
+	config.AddKnownKey(AMQPConfigUsername)
+	config.AddKnownKey(AMQPConfigPassword)
+	config.AddKnownKey(AMQPConfigQueue, defaultQueue)
+	config.AddKnownKey(AMQPConfigSendTimeout, defaultSendTimeout)
+	config.AddKnownKey(AMQPConfigReconnectInitialDelay, defaultReconnectInitialDelay)
+	config.AddKnownKey(AMQPConfigReconnectMaxDelay, defaultReconnectMaxDelay)
+	config.AddKnownKey(AMQPConfigReconnectFactor, defaultReconnectFactor)
+	config.AddKnownKey(AMQPConfigReconnectMaxAttempts, defaultReconnectMaxAttempts)
+	fftls.InitTLSConfig(config.SubSection("tls"))
+}
@@ -0,0 +1,248 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// Both SQS and SNS allow 10 attributes on a message, and we set the four event attributes on every message
+const maxCustomAttributes = 6
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+type messageBody struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+// awsTransport is the common part of the SQS and SNS transports, which make signed HTTP requests to the
+// regional endpoint of the service. An event is only acknowledged once the service accepts the message,
+// and is otherwise rejected so that it is redelivered.
+type awsTransport struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	client       *resty.Client
+	connID       string
+	service      string
+	region       string
+	endpoint     *url.URL
+	creds        *credentials
+}
+
+func configOrEnv(config config.Section, key, envVar string) string {
+	if v := config.GetString(key); v != "" {
+		return v
+	}
+	return os.Getenv(envVar)
+}
+
+func (t *awsTransport) init(ctx context.Context, config config.Section, name, service string) (err error) {
+	creds := &credentials{
+		accessKeyID:     configOrEnv(config, AWSConfigAccessKeyID, "AWS_ACCESS_KEY_ID"),
+		secretAccessKey: configOrEnv(config, AWSConfigSecretAccessKey, "AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    configOrEnv(config, AWSConfigSessionToken, "AWS_SESSION_TOKEN"),
+	}
+	region := configOrEnv(config, AWSConfigRegion, "AWS_REGION")
+	if creds.accessKeyID == "" || creds.secretAccessKey == "" || region == "" {
+		return i18n.NewError(ctx, coremsgs.MsgAWSMissingCredentials, name)
+	}
+
+	endpoint := config.GetString(ffresty.HTTPConfigURL)
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", service, region)
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+
+	client, err := ffresty.New(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	connID := fftypes.ShortID()
+	*t = awsTransport{
+		ctx:          log.WithLogField(ctx, name, connID),
+		capabilities: &events.Capabilities{CloudEvents: true},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		client:   client,
+		connID:   connID,
+		service:  service,
+		region:   region,
+		endpoint: u,
+		creds:    creds,
+	}
+	return nil
+}
+
+func (t *awsTransport) SetHandler(namespace string, handler events.Callbacks) error {
+	t.callbacks.writeLock.Lock()
+	defer t.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(t.callbacks.handlers, namespace)
+		return nil
+	}
+	t.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(t.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (t *awsTransport) Capabilities() *events.Capabilities {
+	return t.capabilities
+}
+
+// validateTarget checks a queue or topic is set on the subscription, or in the plugin config
+func (t *awsTransport) validateTarget(options *core.SubscriptionOptions, name, option, defaultTarget string) error {
+	if options.TransportOptions().GetString(option) == "" && defaultTarget == "" {
+		return i18n.NewError(t.ctx, coremsgs.MsgEventTransportTargetMissing, name, option)
+	}
+	return validateAttributes(t.ctx, options)
+}
+
+// validateAttributes checks the static attributes of a subscription are a map of string values
+func validateAttributes(ctx context.Context, options *core.SubscriptionOptions) error {
+	attributes := options.TransportOptions().GetObject("attributes")
+	if len(attributes) > maxCustomAttributes {
+		return i18n.NewError(ctx, coremsgs.MsgInvalidMessageAttributes, fmt.Sprintf("a maximum of %d attributes can be set", maxCustomAttributes))
+	}
+	for k, v := range attributes {
+		if _, ok := v.(string); !ok {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidMessageAttributes, fmt.Sprintf("%s=%T must be a string", k, v))
+		}
+	}
+	return nil
+}
+
+// messageAttributes returns the static attributes of the subscription, and the attributes of the event,
+// which can be used to filter messages on the queue or topic
+func messageAttributes(sub *core.Subscription, event *core.EventDelivery) map[string]string {
+	attributes := map[string]string{}
+	for k, v := range sub.Options.TransportOptions().GetObject("attributes") {
+		attributes[k] = v.(string)
+	}
+	attributes["id"] = event.ID.String()
+	attributes["type"] = string(event.Type)
+	attributes["namespace"] = event.Namespace
+	if event.Topic != "" {
+		attributes["topic"] = event.Topic
+	}
+	return attributes
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func buildMessageBody(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) string {
+	body := &messageBody{EventDelivery: event}
+	if sub.Options.WithData != nil && *sub.Options.WithData {
+		body.Data = data
+	}
+	var message interface{} = body
+	if sub.Options.Format == core.SubOptsFormatCloudEvents {
+		message = core.NewCloudEvent(event, body)
+	}
+	b, _ := json.Marshal(message)
+	return string(b)
+}
+
+func isFIFO(target string) bool {
+	return strings.HasSuffix(target, ".fifo")
+}
+
+// fifoGroup returns the message group for a FIFO queue or topic, which keeps the events on each FireFly topic in order
+func fifoGroup(event *core.EventDelivery) string {
+	if event.Topic == "" {
+		return "default"
+	}
+	return event.Topic
+}
+
+// post signs and sends a request to the service, returning the error from the service if it is not successful
+func (t *awsTransport) post(contentType string, headers map[string]string, body []byte, result interface{}) error {
+	headers["Content-Type"] = contentType
+	req := t.client.R().
+		SetContext(t.ctx).
+		SetHeaders(headers).
+		SetHeaders(signV4(t.creds, t.region, t.service, "POST", t.endpoint, headers, body, time.Now())).
+		SetBody(body)
+	if result != nil {
+		req.SetResult(result)
+	}
+	resp, err := req.Post(t.endpoint.String())
+	if err != nil || !resp.IsSuccess() {
+		return ffresty.WrapRestErr(t.ctx, resp, err, coremsgs.MsgAWSRequestFailed)
+	}
+	return nil
+}
+
+func (t *awsTransport) deliver(connID string, sub *core.Subscription, event *core.EventDelivery, send func() error) {
+	response := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+	if err := send(); err != nil {
+		log.L(t.ctx).Errorf("Failed to deliver event %s to %s: %s", event.ID, t.service, err)
+		response.Rejected = true
+		response.Info = err.Error()
+	}
+	t.callbacks.writeLock.Lock()
+	cb, ok := t.callbacks.handlers[sub.Namespace]
+	t.callbacks.writeLock.Unlock()
+	if ok {
+		cb.DeliveryResponse(connID, response)
+	}
+}
+
+func (t *awsTransport) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(t.ctx, coremsgs.MsgBatchDeliveryNotSupported, t.service)
+}
+
+func (t *awsTransport) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
@@ -0,0 +1,192 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testTransport interface {
+	events.Plugin
+	base() *awsTransport
+}
+
+func (s *SQS) base() *awsTransport { return &s.awsTransport }
+func (s *SNS) base() *awsTransport { return &s.awsTransport }
+
+func newTestTransport(t *testing.T, p testTransport, extraConfig map[string]string) (cbs *eventsmocks.Callbacks, done func()) {
+	coreconfig.Reset()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+
+	cbs = &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	utConfig := config.RootSection("ut." + p.Name())
+	p.InitConfig(utConfig)
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345/")
+	utConfig.Set(ffresty.HTTPCustomClient, mockedClient)
+	utConfig.Set(AWSConfigRegion, "us-east-1")
+	utConfig.Set(AWSConfigAccessKeyID, "AKIDEXAMPLE")
+	utConfig.Set(AWSConfigSecretAccessKey, "secret")
+	for k, v := range extraConfig {
+		utConfig.Set(k, v)
+	}
+	err := p.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	err = p.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.True(t, p.Capabilities().CloudEvents)
+	return cbs, func() {
+		cancelCtx()
+		httpmock.DeactivateAndReset()
+		cbs.AssertExpectations(t)
+	}
+}
+
+func newTestAWSSubscription(options fftypes.JSONObject) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	for k, v := range options {
+		sub.Options.TransportOptions()[k] = v
+	}
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Type:      core.EventTypeMessageConfirmed,
+				Namespace: "ns1",
+				Topic:     "topic1",
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+func TestInitMissingCredentials(t *testing.T) {
+	coreconfig.Reset()
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	s := &SQS{}
+	utConfig := config.RootSection("ut.sqs")
+	s.InitConfig(utConfig)
+	err := s.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10570.*sqs", err)
+}
+
+func TestInitCredentialsFromEnv(t *testing.T) {
+	coreconfig.Reset()
+	t.Setenv("AWS_REGION", "eu-west-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token1")
+	s := &SNS{}
+	utConfig := config.RootSection("ut.sns")
+	s.InitConfig(utConfig)
+	err := s.Init(context.Background(), utConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-2", s.region)
+	assert.Equal(t, "token1", s.creds.sessionToken)
+	assert.Equal(t, "https://sns.eu-west-2.amazonaws.com/", s.endpoint.String())
+}
+
+func TestInitBadURL(t *testing.T) {
+	coreconfig.Reset()
+	s := &SQS{}
+	utConfig := config.RootSection("ut.sqs")
+	s.InitConfig(utConfig)
+	utConfig.Set(AWSConfigRegion, "us-east-1")
+	utConfig.Set(AWSConfigAccessKeyID, "AKIDEXAMPLE")
+	utConfig.Set(AWSConfigSecretAccessKey, "secret")
+	utConfig.Set(ffresty.HTTPConfigURL, ":::")
+	err := s.Init(context.Background(), utConfig)
+	assert.Error(t, err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	s := &SQS{}
+	utConfig := config.RootSection("ut.sqs")
+	s.InitConfig(utConfig)
+	utConfig.Set(AWSConfigRegion, "us-east-1")
+	utConfig.Set(AWSConfigAccessKeyID, "AKIDEXAMPLE")
+	utConfig.Set(AWSConfigSecretAccessKey, "secret")
+	tlsConfig := utConfig.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCAFile, "BADCA")
+	err := s.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestSetHandlerRemove(t *testing.T) {
+	s := &SQS{}
+	_, done := newTestTransport(t, s, nil)
+	defer done()
+
+	err := s.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, s.callbacks.handlers)
+}
+
+func TestValidateAttributes(t *testing.T) {
+	ctx := context.Background()
+	sub, _ := newTestAWSSubscription(fftypes.JSONObject{"attributes": fftypes.JSONObject{"env": "prod"}})
+	assert.NoError(t, validateAttributes(ctx, &sub.Options))
+
+	sub, _ = newTestAWSSubscription(fftypes.JSONObject{"attributes": fftypes.JSONObject{"count": 1}})
+	assert.Regexp(t, "FF10572.*count", validateAttributes(ctx, &sub.Options))
+
+	sub, _ = newTestAWSSubscription(fftypes.JSONObject{"attributes": fftypes.JSONObject{
+		"a": "1", "b": "2", "c": "3", "d": "4", "e": "5", "f": "6", "g": "7",
+	}})
+	assert.Regexp(t, "FF10572.*6", validateAttributes(ctx, &sub.Options))
+}
+
+func TestBatchDeliveryNotSupported(t *testing.T) {
+	s := &SNS{}
+	_, done := newTestTransport(t, s, nil)
+	defer done()
+
+	sub, _ := newTestAWSSubscription(fftypes.JSONObject{})
+	err := s.BatchDeliveryRequest(s.connID, sub, nil)
+	assert.Regexp(t, "FF10567", err)
+	s.NamespaceRestarted("ns1", time.Now())
+}
@@ -0,0 +1,56 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	// AWSConfigRegion is the AWS region of the queues or topics. Defaults to the AWS_REGION environment variable
+	AWSConfigRegion = "region"
+	// AWSConfigAccessKeyID is the access key of the IAM user or role. Defaults to the AWS_ACCESS_KEY_ID environment variable
+	AWSConfigAccessKeyID = "accessKeyId"
+	// AWSConfigSecretAccessKey is the secret key of the IAM user or role. Defaults to the AWS_SECRET_ACCESS_KEY environment variable
+	AWSConfigSecretAccessKey = "secretAccessKey"
+	// AWSConfigSessionToken is the session token for temporary credentials. Defaults to the AWS_SESSION_TOKEN environment variable
+	AWSConfigSessionToken = "sessionToken"
+	// SQSConfigQueueURL is the queue events are sent to, for subscriptions that do not set their own
+	SQSConfigQueueURL = "queueUrl"
+	// SNSConfigTopicARN is the topic events are published to, for subscriptions that do not set their own
+	SNSConfigTopicARN = "topicArn"
+)
+
+func initAWSConfig(config config.Section) {
+	// The URL is optional, and overrides the regional endpoint of the service
+	ffresty.InitConfig(config)
+	config.AddKnownKey(AWSConfigRegion)
+	config.AddKnownKey(AWSConfigAccessKeyID)
+	config.AddKnownKey(AWSConfigSecretAccessKey)
+	config.AddKnownKey(AWSConfigSessionToken)
+}
+
+func (s *SQS) InitConfig(config config.Section) {
+	initAWSConfig(config)
+	config.AddKnownKey(SQSConfigQueueURL)
+}
+
+func (s *SNS) InitConfig(config config.Section) {
+	initAWSConfig(config)
+	config.AddKnownKey(SNSConfigTopicARN)
+}
@@ -0,0 +1,115 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+type credentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// signV4 returns the headers to add to a POST or GET request to authenticate it with AWS Signature Version 4.
+// All the headers passed in are signed, along with the host of the endpoint.
+func signV4(creds *credentials, region, service, method string, endpoint *url.URL, headers map[string]string, body []byte, now time.Time) map[string]string {
+	now = now.UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", now.Format(sigV4DateFormat), region, service)
+
+	signed := map[string]string{
+		"host":       endpoint.Host,
+		"x-amz-date": amzDate,
+	}
+	if creds.sessionToken != "" {
+		signed["x-amz-security-token"] = creds.sessionToken
+	}
+	for h, v := range headers {
+		signed[strings.ToLower(h)] = strings.TrimSpace(v)
+	}
+	names := make([]string, 0, len(signed))
+	for h := range signed {
+		names = append(names, h)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, h := range names {
+		canonicalHeaders.WriteString(h + ":" + signed[h] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := endpoint.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		endpoint.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.secretAccessKey), now.Format(sigV4DateFormat))
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	result := map[string]string{
+		"X-Amz-Date": amzDate,
+		"Authorization": fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			sigV4Algorithm, creds.accessKeyID, scope, signedHeaders, signature),
+	}
+	if creds.sessionToken != "" {
+		result["X-Amz-Security-Token"] = creds.sessionToken
+	}
+	return result
+}
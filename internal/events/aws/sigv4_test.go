@@ -0,0 +1,51 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignV4TestSuiteGetVanilla(t *testing.T) {
+	// "get-vanilla" from the AWS Signature Version 4 test suite
+	creds := &credentials{
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	u, _ := url.Parse("https://example.amazonaws.com/")
+	now, _ := time.Parse(sigV4TimeFormat, "20150830T123600Z")
+	headers := signV4(creds, "us-east-1", "service", "GET", u, map[string]string{}, []byte{}, now)
+	assert.Equal(t, "20150830T123600Z", headers["X-Amz-Date"])
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31", headers["Authorization"])
+	assert.NotContains(t, headers, "X-Amz-Security-Token")
+}
+
+func TestSignV4SessionToken(t *testing.T) {
+	creds := &credentials{
+		accessKeyID:     "AKIDEXAMPLE",
+		secretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		sessionToken:    "token1",
+	}
+	u, _ := url.Parse("https://sqs.us-east-1.amazonaws.com")
+	headers := signV4(creds, "us-east-1", "sqs", "POST", u, map[string]string{"Content-Type": "application/json"}, []byte("{}"), time.Now())
+	assert.Equal(t, "token1", headers["X-Amz-Security-Token"])
+	assert.Contains(t, headers["Authorization"], "SignedHeaders=content-type;host;x-amz-date;x-amz-security-token,")
+}
@@ -0,0 +1,84 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// SNS publishes each event as a message to an Amazon SNS topic, using the query protocol of the SNS API
+type SNS struct {
+	awsTransport
+	topicARN string
+}
+
+func (s *SNS) Name() string { return "sns" }
+
+func (s *SNS) Init(ctx context.Context, config config.Section) (err error) {
+	if err := s.awsTransport.init(ctx, config, s.Name(), "sns"); err != nil {
+		return err
+	}
+	s.topicARN = config.GetString(SNSConfigTopicARN)
+	return nil
+}
+
+func (s *SNS) ValidateOptions(options *core.SubscriptionOptions) error {
+	return s.validateTarget(options, s.Name(), SNSConfigTopicARN, s.topicARN)
+}
+
+func (s *SNS) publish(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	topicARN := sub.Options.TransportOptions().GetString(SNSConfigTopicARN)
+	if topicARN == "" {
+		topicARN = s.topicARN
+	}
+	form := url.Values{}
+	form.Set("Action", "Publish")
+	form.Set("Version", "2010-03-31")
+	form.Set("TopicArn", topicARN)
+	form.Set("Message", buildMessageBody(sub, event, data))
+	attributes := messageAttributes(sub, event)
+	for i, k := range sortedKeys(attributes) {
+		prefix := fmt.Sprintf("MessageAttributes.entry.%d.", i+1)
+		form.Set(prefix+"Name", k)
+		form.Set(prefix+"Value.DataType", "String")
+		form.Set(prefix+"Value.StringValue", attributes[k])
+	}
+	if isFIFO(topicARN) {
+		form.Set("MessageGroupId", fifoGroup(event))
+		form.Set("MessageDeduplicationId", event.ID.String())
+	}
+
+	err := s.post("application/x-www-form-urlencoded; charset=utf-8", map[string]string{}, []byte(form.Encode()), nil)
+	if err != nil {
+		return err
+	}
+	log.L(s.ctx).Infof("SNS<- topic %s event %s on subscription %s published", topicARN, event.ID, sub.ID)
+	return nil
+}
+
+func (s *SNS) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	s.deliver(connID, sub, event, func() error {
+		return s.publish(sub, event, data)
+	})
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSNSValidateOptions(t *testing.T) {
+	s := &SNS{}
+	_, done := newTestTransport(t, s, map[string]string{
+		SNSConfigTopicARN: "arn:aws:sns:us-east-1:123:topic1",
+	})
+	defer done()
+	assert.Equal(t, "sns", s.Name())
+
+	sub, _ := newTestAWSSubscription(fftypes.JSONObject{})
+	assert.NoError(t, s.ValidateOptions(&sub.Options))
+
+	sub, _ = newTestAWSSubscription(fftypes.JSONObject{"attributes": "wrong"})
+	assert.NoError(t, s.ValidateOptions(&sub.Options))
+
+	sub, _ = newTestAWSSubscription(fftypes.JSONObject{"attributes": fftypes.JSONObject{"a": true}})
+	assert.Regexp(t, "FF10572", s.ValidateOptions(&sub.Options))
+}
+
+func TestSNSDeliveryRequestFIFO(t *testing.T) {
+	s := &SNS{}
+	cbs, done := newTestTransport(t, s, nil)
+	defer done()
+
+	sub, event := newTestAWSSubscription(fftypes.JSONObject{
+		"topicArn":   "arn:aws:sns:us-east-1:123:topic1.fifo",
+		"attributes": fftypes.JSONObject{"env": "prod"},
+	})
+	event.Topic = ""
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Contains(t, req.Header.Get("Authorization"), "/us-east-1/sns/aws4_request")
+			err := req.ParseForm()
+			assert.NoError(t, err)
+			assert.Equal(t, "Publish", req.PostForm.Get("Action"))
+			assert.Equal(t, "arn:aws:sns:us-east-1:123:topic1.fifo", req.PostForm.Get("TopicArn"))
+			assert.Equal(t, "default", req.PostForm.Get("MessageGroupId"))
+			assert.Equal(t, event.ID.String(), req.PostForm.Get("MessageDeduplicationId"))
+			// Attributes are in sorted order
+			assert.Equal(t, "env", req.PostForm.Get("MessageAttributes.entry.1.Name"))
+			assert.Equal(t, "prod", req.PostForm.Get("MessageAttributes.entry.1.Value.StringValue"))
+			assert.Equal(t, "id", req.PostForm.Get("MessageAttributes.entry.2.Name"))
+			assert.Equal(t, "type", req.PostForm.Get("MessageAttributes.entry.4.Name"))
+			assert.Empty(t, req.PostForm.Get("MessageAttributes.entry.5.Name"))
+			assert.Contains(t, req.PostForm.Get("Message"), event.ID.String())
+			return httpmock.NewStringResponse(200, `<PublishResponse/>`), nil
+		})
+	cbs.On("DeliveryResponse", s.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Return()
+
+	err := s.DeliveryRequest(s.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestSNSDeliveryRequestError(t *testing.T) {
+	s := &SNS{}
+	cbs, done := newTestTransport(t, s, map[string]string{
+		SNSConfigTopicARN: "arn:aws:sns:us-east-1:123:topic1",
+	})
+	defer done()
+
+	sub, event := newTestAWSSubscription(fftypes.JSONObject{})
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewStringResponder(403, `<ErrorResponse><Error><Code>AuthorizationError</Code></Error></ErrorResponse>`))
+	cbs.On("DeliveryResponse", s.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return()
+
+	err := s.DeliveryRequest(s.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestSNSDeliveryRequestNoHandler(t *testing.T) {
+	s := &SNS{}
+	_, done := newTestTransport(t, s, map[string]string{
+		SNSConfigTopicARN: "arn:aws:sns:us-east-1:123:topic1",
+	})
+	defer done()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewStringResponder(200, `<PublishResponse/>`))
+	sub, event := newTestAWSSubscription(fftypes.JSONObject{})
+	sub.Namespace = "ns2"
+
+	err := s.DeliveryRequest(s.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,100 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// SQS sends each event as a message to an Amazon SQS queue, using the JSON protocol of the SQS API
+type SQS struct {
+	awsTransport
+	queueURL string
+}
+
+type sqsMessageAttribute struct {
+	DataType    string `json:"DataType"`
+	StringValue string `json:"StringValue"`
+}
+
+type sqsSendMessageInput struct {
+	QueueURL               string                          `json:"QueueUrl"`
+	MessageBody            string                          `json:"MessageBody"`
+	MessageAttributes      map[string]*sqsMessageAttribute `json:"MessageAttributes,omitempty"`
+	MessageGroupID         string                          `json:"MessageGroupId,omitempty"`
+	MessageDeduplicationID string                          `json:"MessageDeduplicationId,omitempty"`
+}
+
+type sqsSendMessageOutput struct {
+	MessageID string `json:"MessageId"`
+}
+
+func (s *SQS) Name() string { return "sqs" }
+
+func (s *SQS) Init(ctx context.Context, config config.Section) (err error) {
+	if err := s.awsTransport.init(ctx, config, s.Name(), "sqs"); err != nil {
+		return err
+	}
+	s.queueURL = config.GetString(SQSConfigQueueURL)
+	return nil
+}
+
+func (s *SQS) ValidateOptions(options *core.SubscriptionOptions) error {
+	return s.validateTarget(options, s.Name(), SQSConfigQueueURL, s.queueURL)
+}
+
+func (s *SQS) sendMessage(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	queueURL := sub.Options.TransportOptions().GetString(SQSConfigQueueURL)
+	if queueURL == "" {
+		queueURL = s.queueURL
+	}
+	input := &sqsSendMessageInput{
+		QueueURL:          queueURL,
+		MessageBody:       buildMessageBody(sub, event, data),
+		MessageAttributes: map[string]*sqsMessageAttribute{},
+	}
+	for k, v := range messageAttributes(sub, event) {
+		input.MessageAttributes[k] = &sqsMessageAttribute{DataType: "String", StringValue: v}
+	}
+	if isFIFO(queueURL) {
+		input.MessageGroupID = fifoGroup(event)
+		input.MessageDeduplicationID = event.ID.String()
+	}
+	body, _ := json.Marshal(input)
+
+	var output sqsSendMessageOutput
+	err := s.post("application/x-amz-json-1.0", map[string]string{
+		"X-Amz-Target": "AmazonSQS.SendMessage",
+	}, body, &output)
+	if err != nil {
+		return err
+	}
+	log.L(s.ctx).Infof("SQS<- queue %s event %s on subscription %s sent as message %s", queueURL, event.ID, sub.ID, output.MessageID)
+	return nil
+}
+
+func (s *SQS) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	s.deliver(connID, sub, event, func() error {
+		return s.sendMessage(sub, event, data)
+	})
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aws
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSQSValidateOptions(t *testing.T) {
+	s := &SQS{}
+	_, done := newTestTransport(t, s, nil)
+	defer done()
+	assert.Equal(t, "sqs", s.Name())
+
+	sub, _ := newTestAWSSubscription(fftypes.JSONObject{"queueUrl": "https://sqs.us-east-1.amazonaws.com/123/queue1"})
+	assert.NoError(t, s.ValidateOptions(&sub.Options))
+
+	sub, _ = newTestAWSSubscription(fftypes.JSONObject{})
+	assert.Regexp(t, "FF10571.*queueUrl", s.ValidateOptions(&sub.Options))
+}
+
+func TestSQSDeliveryRequestFIFO(t *testing.T) {
+	s := &SQS{}
+	cbs, done := newTestTransport(t, s, map[string]string{
+		SQSConfigQueueURL: "https://sqs.us-east-1.amazonaws.com/123/queue1.fifo",
+	})
+	defer done()
+
+	sub, event := newTestAWSSubscription(fftypes.JSONObject{"attributes": fftypes.JSONObject{"env": "prod"}})
+	yes := true
+	sub.Options.WithData = &yes
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":"b"}`)}}
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "AmazonSQS.SendMessage", req.Header.Get("X-Amz-Target"))
+			assert.Equal(t, "application/x-amz-json-1.0", req.Header.Get("Content-Type"))
+			assert.Contains(t, req.Header.Get("Authorization"), "Credential=AKIDEXAMPLE/")
+			var input sqsSendMessageInput
+			err := json.NewDecoder(req.Body).Decode(&input)
+			assert.NoError(t, err)
+			assert.Equal(t, "https://sqs.us-east-1.amazonaws.com/123/queue1.fifo", input.QueueURL)
+			assert.Equal(t, "topic1", input.MessageGroupID)
+			assert.Equal(t, event.ID.String(), input.MessageDeduplicationID)
+			assert.Equal(t, "prod", input.MessageAttributes["env"].StringValue)
+			assert.Equal(t, "message_confirmed", input.MessageAttributes["type"].StringValue)
+			var body fftypes.JSONObject
+			err = json.Unmarshal([]byte(input.MessageBody), &body)
+			assert.NoError(t, err)
+			assert.Equal(t, event.ID.String(), body["id"])
+			assert.Len(t, body["data"], 1)
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"MessageId": "msg1"})(req)
+		})
+	cbs.On("DeliveryResponse", s.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Return()
+
+	err := s.DeliveryRequest(s.connID, sub, event, data)
+	assert.NoError(t, err)
+}
+
+func TestSQSDeliveryRequestCloudEvents(t *testing.T) {
+	s := &SQS{}
+	cbs, done := newTestTransport(t, s, nil)
+	defer done()
+
+	sub, event := newTestAWSSubscription(fftypes.JSONObject{"queueUrl": "https://sqs.us-east-1.amazonaws.com/123/queue1"})
+	sub.Options.Format = core.SubOptsFormatCloudEvents
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		func(req *http.Request) (*http.Response, error) {
+			var input sqsSendMessageInput
+			err := json.NewDecoder(req.Body).Decode(&input)
+			assert.NoError(t, err)
+			assert.Empty(t, input.MessageGroupID)
+			var ce core.CloudEvent
+			err = json.Unmarshal([]byte(input.MessageBody), &ce)
+			assert.NoError(t, err)
+			assert.Equal(t, event.ID.String(), ce.ID)
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"MessageId": "msg1"})(req)
+		})
+	cbs.On("DeliveryResponse", s.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected
+	})).Return()
+
+	err := s.DeliveryRequest(s.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestSQSDeliveryRequestError(t *testing.T) {
+	s := &SQS{}
+	cbs, done := newTestTransport(t, s, nil)
+	defer done()
+
+	sub, event := newTestAWSSubscription(fftypes.JSONObject{"queueUrl": "queue1"})
+	httpmock.RegisterResponder("POST", "http://localhost:12345/",
+		httpmock.NewStringResponder(400, `{"__type":"com.amazonaws.sqs#QueueDoesNotExist"}`))
+	cbs.On("DeliveryResponse", s.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && r.Rejected && r.Info != ""
+	})).Return()
+
+	err := s.DeliveryRequest(s.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
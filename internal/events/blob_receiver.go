@@ -59,6 +59,7 @@ type blobReceiverConf struct {
 	workerCount  int
 	batchTimeout time.Duration
 	maxInserts   int
+	dedup        bool
 }
 
 func newBlobReceiver(ctx context.Context, ag *aggregator) *blobReceiver {
@@ -69,6 +70,7 @@ func newBlobReceiver(ctx context.Context, ag *aggregator) *blobReceiver {
 			workerCount:  config.GetInt(coreconfig.BlobReceiverWorkerCount),
 			batchTimeout: config.GetDuration(coreconfig.BlobReceiverWorkerBatchTimeout),
 			maxInserts:   config.GetInt(coreconfig.BlobReceiverWorkerBatchMaxInserts),
+			dedup:        config.GetBool(coreconfig.BlobDeduplicationEnabled),
 		},
 		retry: &retry.Retry{
 			InitialDelay: config.GetDuration(coreconfig.BlobReceiverRetryInitDelay),
@@ -167,9 +169,10 @@ func (br *blobReceiver) handleBlobNotificationsRetry(ctx context.Context, notifi
 	// We process the event in a retry loop (which will break only if the context is closed), so that
 	// we only confirm consumption of the event to the plugin once we've processed it.
 	var newHashes []*fftypes.Bytes32
+	var duplicatePayloads []string
 	err := br.retry.Do(ctx, "blob reference insert", func(attempt int) (retry bool, err error) {
 		return true, br.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
-			newHashes, err = br.insertNewBlobs(ctx, notifications)
+			newHashes, duplicatePayloads, err = br.insertNewBlobs(ctx, notifications)
 			return err
 		})
 	})
@@ -177,6 +180,12 @@ func (br *blobReceiver) handleBlobNotificationsRetry(ctx context.Context, notifi
 	if err != nil {
 		return err
 	}
+	// Delete the duplicate copies of blobs that now reference a stored payload
+	for _, payloadRef := range duplicatePayloads {
+		if err := br.aggregator.data.DeleteBlobPayload(ctx, payloadRef); err != nil {
+			log.L(ctx).Warnf("Failed to delete duplicate blob '%s' from data exchange: %s", payloadRef, err)
+		}
+	}
 	// Notify all callbacks we completed
 	for _, notification := range notifications {
 		if notification.onComplete != nil {
@@ -190,7 +199,30 @@ func (br *blobReceiver) handleBlobNotificationsRetry(ctx context.Context, notifi
 	return nil
 }
 
-func (br *blobReceiver) insertNewBlobs(ctx context.Context, notifications []*blobNotification) ([]*fftypes.Bytes32, error) {
+// matchStoredBlob checks a received blob against the stored blobs with the same hash. It returns whether the blob is
+// already stored, and when deduplicating, a stored blob with the same content that the received blob can reference
+func (br *blobReceiver) matchStoredBlob(blob *core.Blob, stored []*core.Blob) (found bool, duplicateOf *core.Blob) {
+	for _, existing := range stored {
+		if !existing.Hash.Equals(blob.Hash) {
+			continue
+		}
+		if existing.PayloadRef == blob.PayloadRef {
+			return true, nil
+		}
+		if br.conf.dedup {
+			if blob.DataID != nil && blob.DataID.Equals(existing.DataID) {
+				// Already stored for this data item, referencing a deduplicated payload
+				return true, existing
+			}
+			if duplicateOf == nil {
+				duplicateOf = existing
+			}
+		}
+	}
+	return false, duplicateOf
+}
+
+func (br *blobReceiver) insertNewBlobs(ctx context.Context, notifications []*blobNotification) ([]*fftypes.Bytes32, []string, error) {
 
 	allHashes := make([]driver.Value, len(notifications))
 	for i, n := range notifications {
@@ -199,34 +231,41 @@ func (br *blobReceiver) insertNewBlobs(ctx context.Context, notifications []*blo
 
 	// We want just one record in our DB for each entry in DX, so make the logic idempotent.
 	// Note that we do create a record for each separate receipt of data on a new payload ref,
-	// even if the hash of that data is the same.
+	// even if the hash of that data is the same. When deduplicating, the new record references
+	// the payload already stored, and the new copy is deleted from DX once the records are committed.
 	fb := database.BlobQueryFactory.NewFilter(ctx)
 	filter := fb.In("hash", allHashes)
 	existingBlobs, _, err := br.database.GetBlobs(ctx, br.aggregator.namespace, filter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	newBlobs := make([]*core.Blob, 0, len(existingBlobs))
 	newHashes := make([]*fftypes.Bytes32, 0, len(existingBlobs))
+	duplicatePayloads := make([]string, 0)
 	for _, notification := range notifications {
-		foundExisting := false
-		// Check for duplicates in the DB
-		for _, existing := range existingBlobs {
-			if existing.Hash.Equals(notification.blob.Hash) && existing.PayloadRef == notification.blob.PayloadRef {
-				foundExisting = true
-				break
+		// Check for duplicates in the DB, and then in the notifications
+		foundExisting, duplicateOf := br.matchStoredBlob(notification.blob, existingBlobs)
+		if !foundExisting {
+			var inBatchDuplicate *core.Blob
+			foundExisting, inBatchDuplicate = br.matchStoredBlob(notification.blob, newBlobs)
+			if duplicateOf == nil {
+				duplicateOf = inBatchDuplicate
 			}
 		}
-		// Check for duplicates in the notifications
-		for _, inBatch := range newBlobs {
-			if inBatch.Hash.Equals(notification.blob.Hash) && inBatch.PayloadRef == notification.blob.PayloadRef {
-				foundExisting = true
-				break
-			}
+		if duplicateOf != nil && !br.payloadReferenced(notification.blob.PayloadRef, existingBlobs, newBlobs) && !containsPayloadRef(duplicatePayloads, notification.blob.PayloadRef) {
+			duplicatePayloads = append(duplicatePayloads, notification.blob.PayloadRef)
 		}
 		if !foundExisting {
-			newBlobs = append(newBlobs, notification.blob)
-			newHashes = append(newHashes, notification.blob.Hash)
+			blob := notification.blob
+			if duplicateOf != nil {
+				// Copy the blob, so a retry of the insert sees the payload ref that was received
+				deduped := *notification.blob
+				deduped.PayloadRef = duplicateOf.PayloadRef
+				blob = &deduped
+				log.L(ctx).Infof("Received blob '%s' is a duplicate of stored blob '%s'", notification.blob.PayloadRef, duplicateOf.PayloadRef)
+			}
+			newBlobs = append(newBlobs, blob)
+			newHashes = append(newHashes, blob.Hash)
 		}
 	}
 
@@ -234,9 +273,30 @@ func (br *blobReceiver) insertNewBlobs(ctx context.Context, notifications []*blo
 	if len(newBlobs) > 0 {
 		err = br.database.InsertBlobs(ctx, newBlobs)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
-	return newHashes, nil
+	return newHashes, duplicatePayloads, nil
+
+}
 
+// payloadReferenced checks whether any stored blob references a payload, in which case it cannot be deleted
+func (br *blobReceiver) payloadReferenced(payloadRef string, stored ...[]*core.Blob) bool {
+	for _, blobs := range stored {
+		for _, blob := range blobs {
+			if blob.PayloadRef == payloadRef {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsPayloadRef(payloadRefs []string, payloadRef string) bool {
+	for _, ref := range payloadRefs {
+		if ref == payloadRef {
+			return true
+		}
+	}
+	return false
 }
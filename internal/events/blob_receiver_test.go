@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -113,3 +114,128 @@ func TestBlobReceiverDispatchDup(t *testing.T) {
 	})
 
 }
+
+func TestBlobReceiverDedupStored(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.blobReceiver.conf.dedup = true
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	em.mdi.On("GetBlobs", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Blob{
+		{Hash: blobHash, PayloadRef: "peer1/blob1", DataID: fftypes.NewUUID()},
+	}, nil, nil)
+	em.mdi.On("InsertBlobs", mock.Anything, mock.MatchedBy(func(blobs []*core.Blob) bool {
+		return len(blobs) == 1 && blobs[0].PayloadRef == "peer1/blob1" && blobs[0].DataID.Equals(dataID)
+	})).Return(nil)
+	em.mdm.On("DeleteBlobPayload", mock.Anything, "peer2/blob2").Return(nil)
+
+	blob := &core.Blob{Hash: blobHash, PayloadRef: "peer2/blob2", DataID: dataID}
+	em.blobReceiver.blobReceived(em.ctx, &blobNotification{blob: blob})
+
+	assert.Equal(t, "peer2/blob2", blob.PayloadRef)
+	em.mdi.AssertExpectations(t)
+	em.mdm.AssertExpectations(t)
+
+}
+
+func TestBlobReceiverDedupRedelivered(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.blobReceiver.conf.dedup = true
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	em.mdi.On("GetBlobs", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Blob{
+		{Hash: blobHash, PayloadRef: "peer1/blob1", DataID: dataID},
+	}, nil, nil)
+	em.mdm.On("DeleteBlobPayload", mock.Anything, "peer2/blob2").Return(fmt.Errorf("pop"))
+
+	em.blobReceiver.blobReceived(em.ctx, &blobNotification{
+		blob: &core.Blob{Hash: blobHash, PayloadRef: "peer2/blob2", DataID: dataID},
+	})
+
+	em.mdi.AssertExpectations(t)
+	em.mdm.AssertExpectations(t)
+
+}
+
+func TestBlobReceiverDedupInBatch(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.blobReceiver.conf.dedup = true
+
+	blobHash := fftypes.NewRandB32()
+	dataID1 := fftypes.NewUUID()
+	dataID2 := fftypes.NewUUID()
+	em.mdi.On("GetBlobs", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
+	em.mdi.On("InsertBlobs", mock.Anything, mock.MatchedBy(func(blobs []*core.Blob) bool {
+		return len(blobs) == 2 &&
+			blobs[0].PayloadRef == "peer1/blob1" && blobs[0].DataID.Equals(dataID1) &&
+			blobs[1].PayloadRef == "peer1/blob1" && blobs[1].DataID.Equals(dataID2)
+	})).Return(nil)
+
+	newHashes, duplicates, err := em.blobReceiver.insertNewBlobs(em.ctx, []*blobNotification{
+		{blob: &core.Blob{Hash: blobHash, PayloadRef: "peer1/blob1", DataID: dataID1}},
+		{blob: &core.Blob{Hash: blobHash, PayloadRef: "peer2/blob2", DataID: dataID2}},
+		{blob: &core.Blob{Hash: blobHash, PayloadRef: "peer2/blob2", DataID: dataID2}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, newHashes, 2)
+	assert.Equal(t, []string{"peer2/blob2"}, duplicates)
+
+	em.mdi.AssertExpectations(t)
+
+}
+
+func TestBlobReceiverDedupDisabled(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	blobHash := fftypes.NewRandB32()
+	em.mdi.On("GetBlobs", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Blob{
+		{Hash: blobHash, PayloadRef: "peer1/blob1", DataID: fftypes.NewUUID()},
+	}, nil, nil)
+	em.mdi.On("InsertBlobs", mock.Anything, mock.MatchedBy(func(blobs []*core.Blob) bool {
+		return len(blobs) == 1 && blobs[0].PayloadRef == "peer2/blob2"
+	})).Return(nil)
+
+	newHashes, duplicates, err := em.blobReceiver.insertNewBlobs(em.ctx, []*blobNotification{
+		{blob: &core.Blob{Hash: blobHash, PayloadRef: "peer2/blob2", DataID: fftypes.NewUUID()}},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, newHashes, 1)
+	assert.Empty(t, duplicates)
+
+	em.mdi.AssertExpectations(t)
+
+}
+
+func TestBlobReceiverDedupPayloadStillReferenced(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.blobReceiver.conf.dedup = true
+
+	blobHash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+	em.mdi.On("GetBlobs", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Blob{
+		{Hash: fftypes.NewRandB32(), PayloadRef: "peer1/other", DataID: fftypes.NewUUID()},
+		{Hash: blobHash, PayloadRef: "peer1/blob1", DataID: dataID},
+		{Hash: blobHash, PayloadRef: "peer2/blob2", DataID: fftypes.NewUUID()},
+	}, nil, nil)
+
+	newHashes, duplicates, err := em.blobReceiver.insertNewBlobs(em.ctx, []*blobNotification{
+		{blob: &core.Blob{Hash: blobHash, PayloadRef: "peer2/blob2", DataID: dataID}},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, newHashes)
+	assert.Empty(t, duplicates)
+
+	em.mdi.AssertExpectations(t)
+
+}
@@ -0,0 +1,120 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// scanReceivedBlob runs the blob scanner of the namespace over a blob received from another node. If the scanner
+// flags the blob it is quarantined, and true is returned so the blob is not attached to its data.
+func (em *eventManager) scanReceivedBlob(blob *core.Blob) (quarantined bool, err error) {
+	var finding string
+	err = em.retry.Do(em.ctx, "scan blob", func(attempt int) (bool, error) {
+		var scanErr error
+		finding, scanErr = em.data.ScanBlob(em.ctx, blob.PayloadRef)
+		return true, scanErr
+	})
+	if err != nil || finding == "" {
+		return false, err
+	}
+
+	qb := &core.QuarantinedBlob{
+		ID:         fftypes.NewUUID(),
+		Namespace:  em.namespace.Name,
+		Data:       blob.DataID,
+		Hash:       blob.Hash,
+		Size:       blob.Size,
+		Peer:       blob.Peer,
+		PayloadRef: blob.PayloadRef,
+		Finding:    finding,
+	}
+	err = em.retry.Do(em.ctx, "quarantine blob", func(attempt int) (bool, error) {
+		return true, em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
+			if err := em.database.InsertQuarantinedBlob(ctx, qb); err != nil {
+				return err
+			}
+			event := core.NewEvent(core.EventTypeBlobQuarantined, em.namespace.Name, qb.ID, nil, blob.DataID.String())
+			return em.database.InsertEvent(ctx, event)
+		})
+	})
+	if err != nil {
+		return false, err
+	}
+	log.L(em.ctx).Warnf("Blob '%s' for data '%s' quarantined as %s, flagged by the blob scanner: %s", blob.Hash, blob.DataID, qb.ID, finding)
+	return true, nil
+}
+
+// ReleaseQuarantinedBlob attaches a pending quarantined blob to its data, in the same way as when a clean blob is received
+func (em *eventManager) ReleaseQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) (*core.QuarantinedBlob, error) {
+	if quarantined.State != core.QuarantineStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgQuarantinedBlobNotPending, quarantined.ID, quarantined.State)
+	}
+	if err := em.updateQuarantinedBlobState(ctx, quarantined, core.QuarantineStateReleased); err != nil {
+		return nil, err
+	}
+	em.blobReceiver.blobReceived(ctx, &blobNotification{blob: quarantined.Blob()})
+	log.L(ctx).Infof("Released quarantined blob %s for data '%s'", quarantined.ID, quarantined.Data)
+	return quarantined, nil
+}
+
+// DiscardQuarantinedBlob marks a pending quarantined blob as discarded, and deletes its content from data exchange.
+// The blob is never attached to its data.
+func (em *eventManager) DiscardQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) (*core.QuarantinedBlob, error) {
+	if quarantined.State != core.QuarantineStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgQuarantinedBlobNotPending, quarantined.ID, quarantined.State)
+	}
+	if err := em.updateQuarantinedBlobState(ctx, quarantined, core.QuarantineStateDiscarded); err != nil {
+		return nil, err
+	}
+	if err := em.data.DeleteBlobPayload(ctx, quarantined.PayloadRef); err != nil {
+		log.L(ctx).Errorf("Failed to delete discarded blob '%s' from data exchange: %s", quarantined.PayloadRef, err)
+	}
+	log.L(ctx).Infof("Discarded quarantined blob %s for data '%s'", quarantined.ID, quarantined.Data)
+	return quarantined, nil
+}
+
+// updateQuarantinedBlobState moves a quarantined blob out of the pending state, failing if a concurrent request already did
+func (em *eventManager) updateQuarantinedBlobState(ctx context.Context, quarantined *core.QuarantinedBlob, state core.QuarantineState) error {
+	fb := database.QuarantinedBlobQueryFactory.NewFilter(ctx)
+	updated, err := em.database.UpdateQuarantinedBlob(ctx, em.namespace.Name, quarantined.ID,
+		fb.Eq("state", core.QuarantineStatePending),
+		database.QuarantinedBlobQueryFactory.NewUpdate(ctx).Set("state", state))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		current, err := em.database.GetQuarantinedBlobByID(ctx, em.namespace.Name, quarantined.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return i18n.NewError(ctx, coremsgs.MsgQuarantinedBlobNotPending, quarantined.ID, current.State)
+	}
+	quarantined.State = state
+	quarantined.Updated = fftypes.Now()
+	return nil
+}
@@ -0,0 +1,237 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockBlobScan replaces the data manager of the event manager, so the result of the blob scanner can be set
+func mockBlobScan(em *testEventManager, payloadRef, finding string, err error) *datamocks.Manager {
+	mdm := &datamocks.Manager{}
+	mdm.On("ScanBlob", em.ctx, payloadRef).Return(finding, err)
+	em.data = mdm
+	return mdm
+}
+
+func newTestQuarantinedBlob() *core.QuarantinedBlob {
+	return &core.QuarantinedBlob{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Data:       fftypes.NewUUID(),
+		Hash:       fftypes.NewRandB32(),
+		Size:       12345,
+		Peer:       "peer1",
+		PayloadRef: "ns1/path1",
+		Finding:    "Eicar-Test-Signature",
+		State:      core.QuarantineStatePending,
+	}
+}
+
+func TestPrivateBlobReceivedQuarantined(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	hash := fftypes.NewRandB32()
+	dataID := fftypes.NewUUID()
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	em.mpm.On("DecryptBlob", em.ctx, mock.Anything).Return(nil)
+	mdm := mockBlobScan(em, "ns1/path1", "Eicar-Test-Signature", nil)
+
+	em.mdi.On("InsertQuarantinedBlob", em.ctx, mock.MatchedBy(func(qb *core.QuarantinedBlob) bool {
+		return qb.Data.Equals(dataID) && qb.Hash.Equals(hash) && qb.Peer == "peer1" && qb.Finding == "Eicar-Test-Signature"
+	})).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBlobQuarantined && e.Topic == dataID.String()
+	})).Return(nil)
+
+	mde := newPrivateBlobReceived("peer1", hash, 12345, "ns1/path1", dataID)
+	em.DXEvent(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestPrivateBlobReceivedScanFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+	hash := fftypes.NewRandB32()
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	em.mpm.On("DecryptBlob", em.ctx, mock.Anything).Return(nil)
+	mdm := mockBlobScan(em, "ns1/path1", "", fmt.Errorf("pop"))
+
+	// Not acknowledged
+	mde := newPrivateBlobReceivedNoAck("peer1", hash, 12345, "ns1/path1", fftypes.NewUUID())
+	em.DXEvent(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestSharedStorageBlobDownloadedQuarantined(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	mss := &sharedstoragemocks.Plugin{}
+	mss.On("Name").Return("utsd")
+	mdm := mockBlobScan(em, "payload1", "Eicar-Test-Signature", nil)
+	em.mdi.On("InsertQuarantinedBlob", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(nil)
+
+	err := em.SharedStorageBlobDownloaded(mss, *fftypes.NewRandB32(), 12345, "payload1", fftypes.NewUUID())
+	assert.NoError(t, err)
+
+	mss.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
+func TestSharedStorageBlobDownloadedQuarantineFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+
+	mss := &sharedstoragemocks.Plugin{}
+	mss.On("Name").Return("utsd")
+	mockBlobScan(em, "payload1", "Eicar-Test-Signature", nil)
+	em.mdi.On("InsertQuarantinedBlob", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.SharedStorageBlobDownloaded(mss, *fftypes.NewRandB32(), 12345, "payload1", fftypes.NewUUID())
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestReleaseQuarantinedBlobOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	em.mdi.On("UpdateQuarantinedBlob", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(true, nil)
+	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
+	em.mdi.On("InsertBlobs", em.ctx, mock.MatchedBy(func(blobs []*core.Blob) bool {
+		return len(blobs) == 1 && blobs[0].DataID.Equals(qb.Data) && blobs[0].PayloadRef == qb.PayloadRef
+	})).Return(nil)
+
+	res, err := em.ReleaseQuarantinedBlob(em.ctx, qb)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateReleased, res.State)
+	assert.NotNil(t, res.Updated)
+
+	brw := <-em.aggregator.rewinder.rewindRequests
+	assert.Equal(t, rewind{hash: *qb.Hash, rewindType: rewindBlob}, brw)
+}
+
+func TestReleaseQuarantinedBlobNotPending(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	qb.State = core.QuarantineStateDiscarded
+	_, err := em.ReleaseQuarantinedBlob(em.ctx, qb)
+	assert.Regexp(t, "FF10684.*discarded", err)
+}
+
+func TestReleaseQuarantinedBlobUpdateFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	em.mdi.On("UpdateQuarantinedBlob", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+
+	_, err := em.ReleaseQuarantinedBlob(em.ctx, qb)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDiscardQuarantinedBlobOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	em.mdi.On("UpdateQuarantinedBlob", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(true, nil)
+	em.mdm.On("DeleteBlobPayload", em.ctx, "ns1/path1").Return(nil)
+
+	res, err := em.DiscardQuarantinedBlob(em.ctx, qb)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateDiscarded, res.State)
+}
+
+func TestDiscardQuarantinedBlobDeleteFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	em.mdi.On("UpdateQuarantinedBlob", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(true, nil)
+	em.mdm.On("DeleteBlobPayload", em.ctx, "ns1/path1").Return(fmt.Errorf("pop"))
+
+	res, err := em.DiscardQuarantinedBlob(em.ctx, qb)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateDiscarded, res.State)
+}
+
+func TestDiscardQuarantinedBlobNotPending(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	qb.State = core.QuarantineStateReleased
+	_, err := em.DiscardQuarantinedBlob(em.ctx, qb)
+	assert.Regexp(t, "FF10684.*released", err)
+}
+
+func TestDiscardQuarantinedBlobConcurrentUpdate(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	em.mdi.On("UpdateQuarantinedBlob", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetQuarantinedBlobByID", em.ctx, "ns1", qb.ID).Return(&core.QuarantinedBlob{State: core.QuarantineStateReleased}, nil)
+	_, err := em.DiscardQuarantinedBlob(em.ctx, qb)
+	assert.Regexp(t, "FF10684.*released", err)
+}
+
+func TestDiscardQuarantinedBlobDeleted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	em.mdi.On("UpdateQuarantinedBlob", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetQuarantinedBlobByID", em.ctx, "ns1", qb.ID).Return(nil, nil)
+	_, err := em.DiscardQuarantinedBlob(em.ctx, qb)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDiscardQuarantinedBlobLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBlob()
+	em.mdi.On("UpdateQuarantinedBlob", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetQuarantinedBlobByID", em.ctx, "ns1", qb.ID).Return(nil, fmt.Errorf("pop"))
+	_, err := em.DiscardQuarantinedBlob(em.ctx, qb)
+	assert.EqualError(t, err, "pop")
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
 func buildBlockchainEvent(ns string, subID *fftypes.UUID, event *blockchain.Event, tx *core.BlockchainTransactionRef) *core.BlockchainEvent {
@@ -50,6 +51,12 @@ func (em *eventManager) getChainListenerByProtocolIDCached(ctx context.Context,
 	})
 }
 
+func (em *eventManager) getChainListenerByIDCached(ctx context.Context, id *fftypes.UUID) (*core.ContractListener, error) {
+	return em.getChainListenerCached(fmt.Sprintf("id:%s", id), func() (*core.ContractListener, error) {
+		return em.database.GetContractListenerByID(ctx, em.namespace.Name, id)
+	})
+}
+
 func (em *eventManager) getChainListenerCached(cacheKey string, getter func() (*core.ContractListener, error)) (*core.ContractListener, error) {
 
 	if cachedValue := em.chainListenerCache.Get(cacheKey); cachedValue != nil {
@@ -77,8 +84,20 @@ func (em *eventManager) getTopicForChainListener(listener *core.ContractListener
 }
 
 func (em *eventManager) maybePersistBlockchainEvent(ctx context.Context, chainEvent *core.BlockchainEvent, listener *core.ContractListener) error {
+	eventType := core.EventTypeBlockchainEventReceived
 	if existing, err := em.txHelper.InsertOrGetBlockchainEvent(ctx, chainEvent); err != nil {
 		return err
+	} else if existing != nil && existing.Invalidated {
+		// The event was removed by a chain reorganization, and has now been re-confirmed
+		log.L(ctx).Infof("Blockchain event %s re-confirmed after previous invalidation", chainEvent.ProtocolID)
+		update := database.BlockchainEventQueryFactory.NewUpdate(ctx).Set("invalidated", false)
+		if err := em.database.UpdateBlockchainEvent(ctx, existing.Namespace, existing.ID, update); err != nil {
+			return err
+		}
+		existing.Invalidated = false
+		chainEvent.ID = existing.ID
+		// Subscribers already received this event before it was invalidated
+		eventType = core.EventTypeBlockchainEventReconfirmed
 	} else if existing != nil {
 		log.L(ctx).Debugf("Ignoring duplicate blockchain event %s", chainEvent.ProtocolID)
 		// Return the ID of the existing event
@@ -86,7 +105,7 @@ func (em *eventManager) maybePersistBlockchainEvent(ctx context.Context, chainEv
 		return nil
 	}
 	topic := em.getTopicForChainListener(listener)
-	ffEvent := core.NewEvent(core.EventTypeBlockchainEventReceived, chainEvent.Namespace, chainEvent.ID, chainEvent.TX.ID, topic)
+	ffEvent := core.NewEvent(eventType, chainEvent.Namespace, chainEvent.ID, chainEvent.TX.ID, topic)
 	if err := em.database.InsertEvent(ctx, ffEvent); err != nil {
 		return err
 	}
@@ -116,6 +135,10 @@ func (em *eventManager) BlockchainEventBatch(batch []*blockchain.EventToDispatch
 					if err := em.handleBlockchainNetworkAction(ctx, event.NetworkAction); err != nil {
 						return err
 					}
+				case blockchain.EventTypeRemoved:
+					if err := em.handleBlockchainEventRemoved(ctx, event.Removed); err != nil {
+						return err
+					}
 				}
 			}
 			return nil
@@ -147,3 +170,48 @@ func (em *eventManager) handleBlockchainEventForListener(ctx context.Context, ev
 	em.emitBlockchainEventMetric(event.Event)
 	return nil
 }
+
+// handleBlockchainEventRemoved marks any blockchain events (and token transfers derived from them) that match
+// the removed event as invalidated, and emits compensating events so that applications can react.
+// If the event is later re-confirmed on the canonical chain, the invalidation is cleared.
+func (em *eventManager) handleBlockchainEventRemoved(ctx context.Context, event *blockchain.Event) error {
+	fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("protocolid", event.ProtocolID),
+		fb.Eq("tx.blockchainid", event.BlockchainTXID),
+		fb.Eq("invalidated", false),
+	)
+	chainEvents, _, err := em.database.GetBlockchainEvents(ctx, em.namespace.Name, filter)
+	if err != nil {
+		return err
+	}
+	if len(chainEvents) == 0 {
+		log.L(ctx).Debugf("No blockchain events to invalidate for removed event %s", event.ProtocolID)
+		return nil
+	}
+
+	for _, chainEvent := range chainEvents {
+		var listener *core.ContractListener
+		if chainEvent.Listener != nil {
+			if listener, err = em.getChainListenerByIDCached(ctx, chainEvent.Listener); err != nil {
+				return err
+			}
+		}
+
+		log.L(ctx).Infof("Invalidating blockchain event %s (%s)", chainEvent.ID, chainEvent.ProtocolID)
+		update := database.BlockchainEventQueryFactory.NewUpdate(ctx).Set("invalidated", true)
+		if err := em.database.UpdateBlockchainEvent(ctx, chainEvent.Namespace, chainEvent.ID, update); err != nil {
+			return err
+		}
+		if err := em.invalidateTokenTransfers(ctx, chainEvent); err != nil {
+			return err
+		}
+
+		topic := em.getTopicForChainListener(listener)
+		ffEvent := core.NewEvent(core.EventTypeBlockchainEventInvalidated, chainEvent.Namespace, chainEvent.ID, chainEvent.TX.ID, topic)
+		if err := em.database.InsertEvent(ctx, ffEvent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
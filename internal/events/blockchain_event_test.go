@@ -200,3 +200,195 @@ func TestBlockchainEventMetric(t *testing.T) {
 
 	em.emitBlockchainEventMetric(&event)
 }
+
+func TestPersistBlockchainEventReconfirmed(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	ev := &core.BlockchainEvent{
+		ID:         fftypes.NewUUID(),
+		Name:       "Changed",
+		Namespace:  "ns1",
+		ProtocolID: "10/20/30",
+	}
+	existing := &core.BlockchainEvent{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Invalidated: true,
+	}
+
+	em.mth.On("InsertOrGetBlockchainEvent", mock.Anything, ev).Return(existing, nil)
+	em.mdi.On("UpdateBlockchainEvent", mock.Anything, "ns1", existing.ID, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBlockchainEventReconfirmed && e.Reference == existing.ID
+	})).Return(nil)
+
+	err := em.maybePersistBlockchainEvent(em.ctx, ev, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, existing.ID, ev.ID)
+	assert.False(t, existing.Invalidated)
+}
+
+func TestPersistBlockchainEventReconfirmedFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	ev := &core.BlockchainEvent{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		ProtocolID: "10/20/30",
+	}
+	existing := &core.BlockchainEvent{
+		ID:          fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Invalidated: true,
+	}
+
+	em.mth.On("InsertOrGetBlockchainEvent", mock.Anything, ev).Return(existing, nil)
+	em.mdi.On("UpdateBlockchainEvent", mock.Anything, "ns1", existing.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.maybePersistBlockchainEvent(em.ctx, ev, nil)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBlockchainEventRemoved(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	listener := &core.ContractListener{
+		Namespace: "ns1",
+		ID:        fftypes.NewUUID(),
+		Topic:     "topic1",
+	}
+	chainEvent := &core.BlockchainEvent{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Listener:   listener.ID,
+		ProtocolID: "10/20/30",
+	}
+	transfer := &core.TokenTransfer{
+		LocalID:   fftypes.NewUUID(),
+		Namespace: "ns1",
+		Pool:      fftypes.NewUUID(),
+		From:      "0x1",
+		To:        "0x2",
+	}
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{chainEvent}, nil, nil)
+	em.mdi.On("GetContractListenerByID", mock.Anything, "ns1", listener.ID).Return(listener, nil)
+	em.mdi.On("UpdateBlockchainEvent", mock.Anything, "ns1", chainEvent.ID, mock.Anything).Return(nil)
+	em.mdi.On("GetTokenTransfers", mock.Anything, "ns1", mock.Anything).Return([]*core.TokenTransfer{transfer}, nil, nil)
+	em.mdi.On("UpdateTokenTransfer", mock.Anything, "ns1", transfer.LocalID, mock.Anything).Return(nil)
+	em.mdi.On("UpdateTokenBalances", mock.Anything, mock.MatchedBy(func(t *core.TokenTransfer) bool {
+		return t.From == "0x2" && t.To == "0x1"
+	})).Return(nil)
+	em.mdi.On("GetTokenLedgerEntries", mock.Anything, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", mock.Anything, mock.MatchedBy(func(e *core.TokenLedgerEntry) bool {
+		return e.Reversal
+	})).Return(nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeTransferInvalidated && e.Reference == transfer.LocalID
+	})).Return(nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBlockchainEventInvalidated && e.Reference == chainEvent.ID && e.Topic == "topic1"
+	})).Return(nil)
+
+	err := em.BlockchainEventBatch([]*blockchain.EventToDispatch{
+		{
+			Type: blockchain.EventTypeRemoved,
+			Removed: &blockchain.Event{
+				BlockchainTXID: "0xabcd1234",
+				ProtocolID:     "10/20/30",
+			},
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestBlockchainEventRemovedNotFound(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+
+	err := em.handleBlockchainEventRemoved(em.ctx, &blockchain.Event{ProtocolID: "10/20/30"})
+	assert.NoError(t, err)
+}
+
+func TestBlockchainEventRemovedQueryFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.handleBlockchainEventRemoved(em.ctx, &blockchain.Event{ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBlockchainEventRemovedListenerFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	chainEvent := &core.BlockchainEvent{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Listener:  fftypes.NewUUID(),
+	}
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{chainEvent}, nil, nil)
+	em.mdi.On("GetContractListenerByID", mock.Anything, "ns1", chainEvent.Listener).Return(nil, fmt.Errorf("pop"))
+
+	err := em.handleBlockchainEventRemoved(em.ctx, &blockchain.Event{ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBlockchainEventRemovedUpdateFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	chainEvent := &core.BlockchainEvent{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{chainEvent}, nil, nil)
+	em.mdi.On("UpdateBlockchainEvent", mock.Anything, "ns1", chainEvent.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.handleBlockchainEventRemoved(em.ctx, &blockchain.Event{ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBlockchainEventRemovedTransfersFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	chainEvent := &core.BlockchainEvent{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{chainEvent}, nil, nil)
+	em.mdi.On("UpdateBlockchainEvent", mock.Anything, "ns1", chainEvent.ID, mock.Anything).Return(nil)
+	em.mdi.On("GetTokenTransfers", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.handleBlockchainEventRemoved(em.ctx, &blockchain.Event{ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestBlockchainEventRemovedInsertEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	chainEvent := &core.BlockchainEvent{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{chainEvent}, nil, nil)
+	em.mdi.On("UpdateBlockchainEvent", mock.Anything, "ns1", chainEvent.ID, mock.Anything).Return(nil)
+	em.mdi.On("GetTokenTransfers", mock.Anything, "ns1", mock.Anything).Return([]*core.TokenTransfer{}, nil, nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.handleBlockchainEventRemoved(em.ctx, &blockchain.Event{ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
@@ -34,6 +34,10 @@ func (bc *boundCallbacks) EphemeralSubscription(connID, namespace string, filter
 	return bc.sm.ephemeralSubscription(bc.ei, connID, namespace, filter, options)
 }
 
+func (bc *boundCallbacks) ResumeSubscription(name string, lastSequence int64) error {
+	return bc.sm.resumeSubscription(bc.ei, name, lastSequence)
+}
+
 func (bc *boundCallbacks) DeliveryResponse(connID string, inflight *core.EventDeliveryResponse) {
 	bc.sm.deliveryResponse(bc.ei, connID, inflight)
 }
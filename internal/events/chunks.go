@@ -0,0 +1,95 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// reassembleChunks rebuilds the data of a chunked message from its chunk messages, and stores it.
+// The data must match the data references of the message, which are covered by the message hash.
+// It returns false if the chunks are not available, or do not contain the data of the message.
+func (ag *aggregator) reassembleChunks(ctx context.Context, msg *core.Message) (bool, error) {
+	l := log.L(ctx)
+	var payload []byte
+	for i, chunkID := range msg.Chunks {
+		id, err := fftypes.ParseUUID(ctx, chunkID)
+		if err != nil {
+			l.Errorf("Message '%s' has invalid chunk %d '%s'", msg.Header.ID, i, chunkID)
+			return false, nil
+		}
+		chunk, chunkData, foundAll, err := ag.data.GetMessageWithDataCached(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		if chunk == nil || !foundAll || len(chunkData) != 1 ||
+			chunk.Header.Type != core.MessageTypeChunk ||
+			!chunk.Header.CID.Equals(msg.Header.ID) ||
+			chunk.Header.Author != msg.Header.Author {
+			l.Errorf("Chunk %d '%s' of message '%s' is not available", i, chunkID, msg.Header.ID)
+			return false, nil
+		}
+		var encoded string
+		if err := json.Unmarshal(chunkData[0].Value.Bytes(), &encoded); err != nil {
+			l.Errorf("Chunk %d '%s' of message '%s' is invalid: %s", i, chunkID, msg.Header.ID, err)
+			return false, nil
+		}
+		piece, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			l.Errorf("Chunk %d '%s' of message '%s' is invalid: %s", i, chunkID, msg.Header.ID, err)
+			return false, nil
+		}
+		payload = append(payload, piece...)
+	}
+
+	var data core.DataArray
+	if err := json.Unmarshal(payload, &data); err != nil || len(data) != len(msg.Data) {
+		l.Errorf("Chunks of message '%s' do not contain the data of the message", msg.Header.ID)
+		return false, nil
+	}
+	for i, d := range data {
+		if d == nil || d.ID == nil || !d.ID.Equals(msg.Data[i].ID) || !d.Hash.Equals(msg.Data[i].Hash) {
+			l.Errorf("Chunks of message '%s' have mismatched data %d", msg.Header.ID, i)
+			return false, nil
+		}
+		hash, err := d.CalcHash(ctx)
+		if err != nil || !d.Hash.Equals(hash) {
+			l.Errorf("Chunks of message '%s' have invalid data %d: Hash=%v Expected=%v", msg.Header.ID, i, d.Hash, hash)
+			return false, nil
+		}
+	}
+
+	for i, d := range data {
+		d.Namespace = ag.namespace
+		if err := ag.database.UpsertData(ctx, d, database.UpsertOptimizationNew); err != nil {
+			if err == database.HashMismatch {
+				l.Errorf("Chunks of message '%s' have data %d with a hash mismatch with the existing record with UUID '%s'", msg.Header.ID, i, d.ID)
+				return false, nil
+			}
+			return false, err
+		}
+	}
+	l.Infof("Reassembled data of message '%s' from %d chunks", msg.Header.ID, len(msg.Chunks))
+	return true, nil
+}
@@ -0,0 +1,269 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestChunkedMessage(t *testing.T, ag *testAggregator, chunkValues ...string) (*core.Message, *core.Data) {
+	d := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"some":"data"}`)}
+	err := d.Seal(ag.ctx, nil)
+	assert.NoError(t, err)
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Type:      core.MessageTypeBroadcast,
+			SignerRef: core.SignerRef{Author: "org1", Key: "0x12345"},
+		},
+		Data: core.DataArray{d}.Refs(),
+	}
+	if len(chunkValues) == 0 {
+		payload, _ := json.Marshal(core.DataArray{d.BatchData(core.BatchTypeBroadcast)})
+		half := len(payload) / 2
+		chunkValues = []string{
+			strconv.Quote(base64.StdEncoding.EncodeToString(payload[:half])),
+			strconv.Quote(base64.StdEncoding.EncodeToString(payload[half:])),
+		}
+	}
+	for _, v := range chunkValues {
+		chunk := &core.Message{
+			Header: core.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				CID:       msg.Header.ID,
+				Type:      core.MessageTypeChunk,
+				SignerRef: msg.Header.SignerRef,
+			},
+		}
+		msg.Chunks = append(msg.Chunks, chunk.Header.ID.String())
+		ag.mdm.On("GetMessageWithDataCached", ag.ctx, chunk.Header.ID).Return(chunk, core.DataArray{
+			{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(v)},
+		}, true, nil).Maybe()
+	}
+	return msg, d
+}
+
+func TestReassembleChunksOk(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, d := newTestChunkedMessage(t, ag)
+	ag.mdi.On("UpsertData", ag.ctx, mock.MatchedBy(func(upserted *core.Data) bool {
+		return upserted.ID.Equals(d.ID) && upserted.Hash.Equals(d.Hash) && upserted.Namespace == "ns1"
+	}), database.UpsertOptimizationNew).Return(nil)
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.True(t, reassembled)
+}
+
+func TestReassembleChunksBadChunkID(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag)
+	msg.Chunks[0] = "bad"
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksGetFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{ID: fftypes.NewUUID()},
+		Chunks: fftypes.FFStringArray{fftypes.NewUUID().String()},
+	}
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything).Return(nil, nil, false, fmt.Errorf("pop"))
+
+	_, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReassembleChunksNotFound(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{ID: fftypes.NewUUID()},
+		Chunks: fftypes.FFStringArray{fftypes.NewUUID().String()},
+	}
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything).Return(nil, nil, false, nil)
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksWrongAuthor(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag)
+	msg.Header.Author = "org2"
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksNotString(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag, `{}`)
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksBadBase64(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag, `"!!!"`)
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksBadPayload(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag, strconv.Quote(base64.StdEncoding.EncodeToString([]byte(`[]`))))
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksMismatchedData(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag)
+	msg.Data[0].Hash = fftypes.NewRandB32()
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksBadDataHash(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	d := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"tampered"`), Hash: fftypes.NewRandB32()}
+	payload, _ := json.Marshal(core.DataArray{d})
+	msg, _ := newTestChunkedMessage(t, ag, strconv.Quote(base64.StdEncoding.EncodeToString(payload)))
+	msg.Data = core.DataArray{d}.Refs()
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksUpsertHashMismatch(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag)
+	ag.mdi.On("UpsertData", ag.ctx, mock.Anything, database.UpsertOptimizationNew).Return(database.HashMismatch)
+
+	reassembled, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.NoError(t, err)
+	assert.False(t, reassembled)
+}
+
+func TestReassembleChunksUpsertFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag)
+	ag.mdi.On("UpsertData", ag.ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+
+	_, err := ag.reassembleChunks(ag.ctx, msg)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestProcessMsgChunkedReassembled(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg, _ := newTestChunkedMessage(t, ag)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg.Header.ID, data.CRORequirePublicBlobRefs).Return(msg, nil, false, nil).Once()
+	ag.mdi.On("UpsertData", ag.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg.Header.ID, data.CRORequirePublicBlobRefs).Return(nil, nil, false, fmt.Errorf("pop")).Once()
+
+	err := ag.processMessage(ag.ctx, &core.BatchManifest{},
+		&core.Pin{Sequence: 12345},
+		10,
+		&core.MessageManifestEntry{MessageRef: core.MessageRef{ID: msg.Header.ID}},
+		&core.BatchPersisted{},
+		nil)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestProcessMsgChunkedMissing(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg := &core.Message{
+		Header: core.MessageHeader{ID: fftypes.NewUUID()},
+		Chunks: fftypes.FFStringArray{fftypes.NewUUID().String()},
+	}
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg.Header.ID, data.CRORequirePublicBlobRefs).Return(msg, nil, false, nil)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, mock.Anything).Return(nil, nil, false, nil)
+
+	err := ag.processMessage(ag.ctx, &core.BatchManifest{},
+		&core.Pin{Sequence: 12345},
+		10,
+		&core.MessageManifestEntry{MessageRef: core.MessageRef{ID: msg.Header.ID}},
+		&core.BatchPersisted{},
+		nil)
+	assert.NoError(t, err)
+}
+
+func TestCompleteDispatchChunkNoEvents(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	msg1, _, _, _ := newTestManifest(core.MessageTypeChunk, nil)
+
+	newState := ag.completeDispatch(core.ActionConfirm, nil, msg1, nil, bs)
+	assert.Equal(t, core.MessageStateConfirmed, newState)
+
+	err := bs.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+}
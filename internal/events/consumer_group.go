@@ -0,0 +1,139 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// consumerGroup tracks the connections that share a durable subscription. The elected dispatcher polls
+// and tracks the events for the whole group, but delivers each one to a single member connection.
+type consumerGroup struct {
+	mux          sync.Mutex
+	delivery     core.SubOptsGroupDelivery
+	partitionKey core.SubOptsPartitionKey
+	members      []string
+	next         int
+	leader       *eventDispatcher
+}
+
+func newConsumerGroup(ctx context.Context, options *core.SubscriptionConsumerGroup) (*consumerGroup, error) {
+	cg := &consumerGroup{
+		delivery:     options.Delivery,
+		partitionKey: options.PartitionKey,
+	}
+	switch cg.delivery {
+	case "":
+		cg.delivery = core.SubOptsGroupDeliveryRoundRobin
+	case core.SubOptsGroupDeliveryRoundRobin, core.SubOptsGroupDeliveryPartition:
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidConsumerGroupOption, "delivery", cg.delivery)
+	}
+	switch cg.partitionKey {
+	case "":
+		cg.partitionKey = core.SubOptsPartitionKeyTopic
+	case core.SubOptsPartitionKeyTopic, core.SubOptsPartitionKeyGroup, core.SubOptsPartitionKeyAuthor:
+		if cg.delivery != core.SubOptsGroupDeliveryPartition {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidConsumerGroupOption, "partitionKey", "only applies to 'partition' delivery")
+		}
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidConsumerGroupOption, "partitionKey", cg.partitionKey)
+	}
+	return cg, nil
+}
+
+func (cg *consumerGroup) join(connID string) {
+	cg.mux.Lock()
+	defer cg.mux.Unlock()
+	cg.members = append(cg.members, connID)
+}
+
+// leave removes the connection of the dispatcher from the group, and asks the leader to
+// redeliver any events that are in flight on that connection to the remaining members
+func (cg *consumerGroup) leave(ed *eventDispatcher) {
+	cg.mux.Lock()
+	for i, connID := range cg.members {
+		if connID == ed.connID {
+			cg.members = append(cg.members[:i], cg.members[i+1:]...)
+			break
+		}
+	}
+	leader := cg.leader
+	if leader == ed {
+		cg.leader = nil
+	}
+	cg.mux.Unlock()
+
+	if leader != nil && leader != ed {
+		select {
+		case leader.rebalance <- ed.connID:
+		case <-leader.ctx.Done():
+		}
+	}
+}
+
+func (cg *consumerGroup) elected(ed *eventDispatcher) {
+	cg.mux.Lock()
+	defer cg.mux.Unlock()
+	cg.leader = ed
+}
+
+func (cg *consumerGroup) getLeader() *eventDispatcher {
+	cg.mux.Lock()
+	defer cg.mux.Unlock()
+	return cg.leader
+}
+
+// pick chooses the member connection to deliver an event to, returning the fallback if there are no members
+func (cg *consumerGroup) pick(event *core.EventDelivery, fallback string) string {
+	cg.mux.Lock()
+	defer cg.mux.Unlock()
+	if len(cg.members) == 0 {
+		return fallback
+	}
+	if cg.delivery == core.SubOptsGroupDeliveryPartition {
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(partitionKey(cg.partitionKey, event)))
+		return cg.members[h.Sum32()%uint32(len(cg.members))]
+	}
+	idx := cg.next % len(cg.members)
+	cg.next = idx + 1
+	return cg.members[idx]
+}
+
+func partitionKey(key core.SubOptsPartitionKey, event *core.EventDelivery) string {
+	switch key {
+	case core.SubOptsPartitionKeyGroup:
+		if event.Message != nil && event.Message.Header.Group != nil {
+			return event.Message.Header.Group.String()
+		}
+		return ""
+	case core.SubOptsPartitionKeyAuthor:
+		if event.Message != nil {
+			return event.Message.Header.Author
+		}
+		return ""
+	default:
+		return event.Topic
+	}
+}
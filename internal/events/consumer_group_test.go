@@ -0,0 +1,204 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestGroupSubscription(t *testing.T, options *core.SubscriptionConsumerGroup) *subscription {
+	group, err := newConsumerGroup(context.Background(), options)
+	assert.NoError(t, err)
+	return &subscription{
+		dispatcherElection: make(chan bool, 1),
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{ConsumerGroup: options},
+			},
+		},
+		group: group,
+	}
+}
+
+func newTestGroupEvent(topic string) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: fftypes.NewUUID(), Namespace: "ns1", Topic: topic, Type: core.EventTypeMessageConfirmed},
+		},
+	}
+}
+
+func TestNewConsumerGroupOptions(t *testing.T) {
+	ctx := context.Background()
+	cg, err := newConsumerGroup(ctx, &core.SubscriptionConsumerGroup{})
+	assert.NoError(t, err)
+	assert.Equal(t, core.SubOptsGroupDeliveryRoundRobin, cg.delivery)
+	assert.Equal(t, core.SubOptsPartitionKeyTopic, cg.partitionKey)
+
+	cg, err = newConsumerGroup(ctx, &core.SubscriptionConsumerGroup{
+		Delivery:     core.SubOptsGroupDeliveryPartition,
+		PartitionKey: core.SubOptsPartitionKeyAuthor,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.SubOptsPartitionKeyAuthor, cg.partitionKey)
+
+	_, err = newConsumerGroup(ctx, &core.SubscriptionConsumerGroup{Delivery: "broadcast"})
+	assert.Regexp(t, "FF10557.*delivery.*broadcast", err)
+
+	_, err = newConsumerGroup(ctx, &core.SubscriptionConsumerGroup{
+		Delivery:     core.SubOptsGroupDeliveryPartition,
+		PartitionKey: "tag",
+	})
+	assert.Regexp(t, "FF10557.*partitionKey.*tag", err)
+
+	_, err = newConsumerGroup(ctx, &core.SubscriptionConsumerGroup{PartitionKey: core.SubOptsPartitionKeyGroup})
+	assert.Regexp(t, "FF10557.*partitionKey", err)
+}
+
+func TestConsumerGroupPickRoundRobin(t *testing.T) {
+	cg, _ := newConsumerGroup(context.Background(), &core.SubscriptionConsumerGroup{})
+	assert.Equal(t, "fallback", cg.pick(newTestGroupEvent("topic1"), "fallback"))
+
+	cg.join("conn1")
+	cg.join("conn2")
+	cg.join("conn3")
+	assert.Equal(t, "conn1", cg.pick(newTestGroupEvent("topic1"), "fallback"))
+	assert.Equal(t, "conn2", cg.pick(newTestGroupEvent("topic1"), "fallback"))
+	assert.Equal(t, "conn3", cg.pick(newTestGroupEvent("topic1"), "fallback"))
+	assert.Equal(t, "conn1", cg.pick(newTestGroupEvent("topic1"), "fallback"))
+}
+
+func TestConsumerGroupPickPartition(t *testing.T) {
+	cg, _ := newConsumerGroup(context.Background(), &core.SubscriptionConsumerGroup{
+		Delivery: core.SubOptsGroupDeliveryPartition,
+	})
+	cg.join("conn1")
+	cg.join("conn2")
+	cg.join("conn3")
+	for _, topic := range []string{"topic1", "topic2", "topic3", "topic4"} {
+		connID := cg.pick(newTestGroupEvent(topic), "fallback")
+		assert.NotEqual(t, "fallback", connID)
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, connID, cg.pick(newTestGroupEvent(topic), "fallback"))
+		}
+	}
+}
+
+func TestPartitionKey(t *testing.T) {
+	group := fftypes.NewRandB32()
+	event := newTestGroupEvent("topic1")
+	assert.Equal(t, "topic1", partitionKey(core.SubOptsPartitionKeyTopic, event))
+	assert.Equal(t, "", partitionKey(core.SubOptsPartitionKeyGroup, event))
+	assert.Equal(t, "", partitionKey(core.SubOptsPartitionKeyAuthor, event))
+
+	event.Message = &core.Message{
+		Header: core.MessageHeader{
+			SignerRef: core.SignerRef{Author: "did:firefly:org/org1"},
+			Group:     group,
+		},
+	}
+	assert.Equal(t, group.String(), partitionKey(core.SubOptsPartitionKeyGroup, event))
+	assert.Equal(t, "did:firefly:org/org1", partitionKey(core.SubOptsPartitionKeyAuthor, event))
+}
+
+func TestConsumerGroupDeliveryRebalance(t *testing.T) {
+	sub := newTestGroupSubscription(t, &core.SubscriptionConsumerGroup{})
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed2, cancel2 := newTestEventDispatcher(sub)
+	defer cancel2()
+	sub.group.join(ed.connID)
+	sub.group.join(ed2.connID)
+	sub.group.elected(ed)
+	go ed.deliverEvents()
+
+	type delivery struct {
+		connID string
+		event  *core.EventDelivery
+	}
+	deliveries := make(chan delivery)
+	mei := ed.transport.(*eventsmocks.Plugin)
+	dr := mei.On("DeliveryRequest", mock.Anything, sub.definition, mock.Anything, mock.Anything).Return(nil)
+	dr.RunFn = func(a mock.Arguments) {
+		deliveries <- delivery{connID: a.String(0), event: a.Get(2).(*core.EventDelivery)}
+	}
+
+	events := []*core.EventDelivery{newTestGroupEvent("topic1"), newTestGroupEvent("topic1"), newTestGroupEvent("topic1"), newTestGroupEvent("topic1")}
+	for i, event := range events {
+		event.Sequence = int64(i + 1)
+		ed.inflight[*event.ID] = &event.Event
+	}
+	go func() {
+		for _, event := range events {
+			ed.eventDelivery <- event
+		}
+	}()
+	d := <-deliveries
+	assert.Equal(t, ed.connID, d.connID)
+	assert.Equal(t, events[0].ID, d.event.ID)
+	d = <-deliveries
+	assert.Equal(t, ed2.connID, d.connID)
+	assert.Equal(t, events[1].ID, d.event.ID)
+	d = <-deliveries
+	assert.Equal(t, ed.connID, d.connID)
+	assert.Equal(t, events[2].ID, d.event.ID)
+	d = <-deliveries
+	assert.Equal(t, ed2.connID, d.connID)
+	assert.Equal(t, events[3].ID, d.event.ID)
+
+	// The second member leaves, so its events move to the leader's connection in order
+	go sub.group.leave(ed2)
+	d = <-deliveries
+	assert.Equal(t, ed.connID, d.connID)
+	assert.Equal(t, events[1].ID, d.event.ID)
+	d = <-deliveries
+	assert.Equal(t, ed.connID, d.connID)
+	assert.Equal(t, events[3].ID, d.event.ID)
+	assert.Equal(t, ed.connID, ed.groupInflight[*events[1].ID].connID)
+
+	// Acks clear the tracking of the member connection
+	ed.handleAckOffsetUpdate(ackNack{id: *events[1].ID, offset: 2})
+	assert.Nil(t, ed.groupInflight[*events[1].ID])
+	assert.Len(t, ed.groupInflight, 3)
+
+	// The leader leaving clears the leader, without a rebalance
+	sub.group.leave(ed)
+	assert.Nil(t, sub.group.getLeader())
+	assert.Empty(t, sub.group.members)
+}
+
+func TestConsumerGroupLeaveLeaderClosed(t *testing.T) {
+	sub := newTestGroupSubscription(t, &core.SubscriptionConsumerGroup{})
+	ed, cancel := newTestEventDispatcher(sub)
+	ed2, cancel2 := newTestEventDispatcher(sub)
+	defer cancel2()
+	sub.group.join(ed.connID)
+	sub.group.join(ed2.connID)
+	sub.group.elected(ed)
+	cancel()
+
+	sub.group.leave(ed2)
+	assert.Equal(t, []string{ed.connID}, sub.group.members)
+}
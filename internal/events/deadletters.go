@@ -0,0 +1,93 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// RequeueDeadLetter redelivers the event of a pending dead letter to its subscription, via an active connection
+// of the subscription on this node. If delivery fails again, a new dead letter is recorded.
+func (em *eventManager) RequeueDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (*core.DeadLetter, error) {
+	if deadLetter.State != core.DeadLetterStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDeadLetterNotPending, deadLetter.ID, deadLetter.State)
+	}
+	dispatcher := em.subManager.getDurableDispatcher(deadLetter.Subscription.ID)
+	if dispatcher == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDeadLetterSubscriptionNotActive, deadLetter.Subscription.Name, deadLetter.ID)
+	}
+	event, err := em.database.GetEventByID(ctx, em.namespace.Name, deadLetter.Event)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDeadLetterEventNotFound, deadLetter.Event, deadLetter.ID)
+	}
+	delivery, err := dispatcher.prepareRequeue(event)
+	if err != nil {
+		return nil, err
+	}
+	if err := em.updateDeadLetterState(ctx, deadLetter, core.DeadLetterStateRequeued); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Requeuing dead letter %s for event %s to subscription %s", deadLetter.ID, deadLetter.Event, deadLetter.Subscription.Name)
+	dispatcher.requeue(delivery)
+	return deadLetter, nil
+}
+
+// DiscardDeadLetter marks a pending dead letter as discarded, so it remains on record but is never redelivered
+func (em *eventManager) DiscardDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (*core.DeadLetter, error) {
+	if deadLetter.State != core.DeadLetterStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDeadLetterNotPending, deadLetter.ID, deadLetter.State)
+	}
+	if err := em.updateDeadLetterState(ctx, deadLetter, core.DeadLetterStateDiscarded); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Discarded dead letter %s for event %s", deadLetter.ID, deadLetter.Event)
+	return deadLetter, nil
+}
+
+// updateDeadLetterState moves a dead letter out of the pending state, failing if a concurrent request already did
+func (em *eventManager) updateDeadLetterState(ctx context.Context, deadLetter *core.DeadLetter, state core.DeadLetterState) error {
+	fb := database.DeadLetterQueryFactory.NewFilter(ctx)
+	updated, err := em.database.UpdateDeadLetter(ctx, em.namespace.Name, deadLetter.ID,
+		fb.Eq("state", core.DeadLetterStatePending),
+		database.DeadLetterQueryFactory.NewUpdate(ctx).Set("state", state))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		current, err := em.database.GetDeadLetterByID(ctx, em.namespace.Name, deadLetter.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return i18n.NewError(ctx, coremsgs.MsgDeadLetterNotPending, deadLetter.ID, current.State)
+	}
+	deadLetter.State = state
+	deadLetter.Updated = fftypes.Now()
+	return nil
+}
@@ -0,0 +1,226 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestDeadLetter(subID *fftypes.UUID) *core.DeadLetter {
+	return &core.DeadLetter{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Subscription: core.SubscriptionRef{
+			ID:        subID,
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Event:     fftypes.NewUUID(),
+		EventType: core.EventTypeDatatypeConfirmed,
+		Attempts:  3,
+		State:     core.DeadLetterStatePending,
+	}
+}
+
+func newTestRequeueDispatcher(em *testEventManager, subID *fftypes.UUID) (*eventDispatcher, func()) {
+	ed, cancel := newTestEventDispatcher(&subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+		},
+	})
+	em.subManager.connections["conn1"] = &connection{
+		id:          "conn1",
+		dispatchers: map[fftypes.UUID]*eventDispatcher{*subID: ed},
+	}
+	return ed, cancel
+}
+
+func TestRequeueDeadLetterOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	subID := fftypes.NewUUID()
+	ed, cancel := newTestRequeueDispatcher(em, subID)
+	defer cancel()
+	dl := newTestDeadLetter(subID)
+	event := &core.Event{ID: dl.Event, Type: core.EventTypeDatatypeConfirmed, Namespace: "ns1", Reference: fftypes.NewUUID()}
+
+	em.mdi.On("GetEventByID", em.ctx, "ns1", dl.Event).Return(event, nil)
+	em.mdi.On("UpdateDeadLetter", em.ctx, "ns1", dl.ID, mock.Anything, mock.Anything).Return(true, nil)
+	ed.database.(*databasemocks.Plugin).On("GetDatatypeByID", mock.Anything, "ns1", event.Reference).Return(&core.Datatype{}, nil)
+	delivered := make(chan *core.EventDelivery)
+	ed.transport.(*eventsmocks.Plugin).On("DeliveryRequest", ed.connID, mock.Anything, mock.Anything, core.DataArray(nil)).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			delivered <- args[2].(*core.EventDelivery)
+		})
+
+	requeued, err := em.RequeueDeadLetter(em.ctx, dl)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DeadLetterStateRequeued, requeued.State)
+	assert.NotNil(t, requeued.Updated)
+
+	delivery := <-delivered
+	assert.Equal(t, *event.ID, *delivery.ID)
+	assert.Equal(t, *subID, *delivery.Subscription.ID)
+
+	// The response is not acknowledged, as the event is not in flight
+	ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID})
+	assert.Empty(t, ed.requeued)
+}
+
+func TestRequeueDeadLetterNotPending(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	dl := newTestDeadLetter(fftypes.NewUUID())
+	dl.State = core.DeadLetterStateDiscarded
+	_, err := em.RequeueDeadLetter(em.ctx, dl)
+	assert.Regexp(t, "FF10581.*discarded", err)
+}
+
+func TestRequeueDeadLetterNoDispatcher(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	_, err := em.RequeueDeadLetter(em.ctx, newTestDeadLetter(fftypes.NewUUID()))
+	assert.Regexp(t, "FF10582.*sub1", err)
+}
+
+func TestRequeueDeadLetterGetEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	subID := fftypes.NewUUID()
+	_, cancel := newTestRequeueDispatcher(em, subID)
+	defer cancel()
+	dl := newTestDeadLetter(subID)
+
+	em.mdi.On("GetEventByID", em.ctx, "ns1", dl.Event).Return(nil, fmt.Errorf("pop"))
+	_, err := em.RequeueDeadLetter(em.ctx, dl)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRequeueDeadLetterEventNotFound(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	subID := fftypes.NewUUID()
+	_, cancel := newTestRequeueDispatcher(em, subID)
+	defer cancel()
+	dl := newTestDeadLetter(subID)
+
+	em.mdi.On("GetEventByID", em.ctx, "ns1", dl.Event).Return(nil, nil)
+	_, err := em.RequeueDeadLetter(em.ctx, dl)
+	assert.Regexp(t, "FF10583", err)
+}
+
+func TestRequeueDeadLetterEnrichFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	subID := fftypes.NewUUID()
+	ed, cancel := newTestRequeueDispatcher(em, subID)
+	defer cancel()
+	dl := newTestDeadLetter(subID)
+	event := &core.Event{ID: dl.Event, Type: core.EventTypeDatatypeConfirmed, Namespace: "ns1", Reference: fftypes.NewUUID()}
+
+	em.mdi.On("GetEventByID", em.ctx, "ns1", dl.Event).Return(event, nil)
+	ed.database.(*databasemocks.Plugin).On("GetDatatypeByID", mock.Anything, "ns1", event.Reference).Return(nil, fmt.Errorf("pop"))
+	_, err := em.RequeueDeadLetter(em.ctx, dl)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRequeueDeadLetterUpdateFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	subID := fftypes.NewUUID()
+	ed, cancel := newTestRequeueDispatcher(em, subID)
+	defer cancel()
+	dl := newTestDeadLetter(subID)
+	event := &core.Event{ID: dl.Event, Type: core.EventTypeDatatypeConfirmed, Namespace: "ns1", Reference: fftypes.NewUUID()}
+
+	em.mdi.On("GetEventByID", em.ctx, "ns1", dl.Event).Return(event, nil)
+	em.mdi.On("UpdateDeadLetter", em.ctx, "ns1", dl.ID, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+	ed.database.(*databasemocks.Plugin).On("GetDatatypeByID", mock.Anything, "ns1", event.Reference).Return(&core.Datatype{}, nil)
+	_, err := em.RequeueDeadLetter(em.ctx, dl)
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, ed.requeued)
+}
+
+func TestDiscardDeadLetterOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	dl := newTestDeadLetter(fftypes.NewUUID())
+	em.mdi.On("UpdateDeadLetter", em.ctx, "ns1", dl.ID, mock.Anything, mock.Anything).Return(true, nil)
+	discarded, err := em.DiscardDeadLetter(em.ctx, dl)
+	assert.NoError(t, err)
+	assert.Equal(t, core.DeadLetterStateDiscarded, discarded.State)
+}
+
+func TestDiscardDeadLetterNotPending(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	dl := newTestDeadLetter(fftypes.NewUUID())
+	dl.State = core.DeadLetterStateRequeued
+	_, err := em.DiscardDeadLetter(em.ctx, dl)
+	assert.Regexp(t, "FF10581.*requeued", err)
+}
+
+func TestDiscardDeadLetterConcurrentUpdate(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	dl := newTestDeadLetter(fftypes.NewUUID())
+	em.mdi.On("UpdateDeadLetter", em.ctx, "ns1", dl.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", dl.ID).Return(&core.DeadLetter{State: core.DeadLetterStateRequeued}, nil)
+	_, err := em.DiscardDeadLetter(em.ctx, dl)
+	assert.Regexp(t, "FF10581.*requeued", err)
+}
+
+func TestDiscardDeadLetterDeleted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	dl := newTestDeadLetter(fftypes.NewUUID())
+	em.mdi.On("UpdateDeadLetter", em.ctx, "ns1", dl.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", dl.ID).Return(nil, nil)
+	_, err := em.DiscardDeadLetter(em.ctx, dl)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDiscardDeadLetterLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	dl := newTestDeadLetter(fftypes.NewUUID())
+	em.mdi.On("UpdateDeadLetter", em.ctx, "ns1", dl.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", dl.ID).Return(nil, fmt.Errorf("pop"))
+	_, err := em.DiscardDeadLetter(em.ctx, dl)
+	assert.EqualError(t, err, "pop")
+}
@@ -0,0 +1,92 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type dedupKey struct {
+	correlator fftypes.UUID
+	eventType  core.EventType
+}
+
+type dedupEntry struct {
+	id      fftypes.UUID
+	created time.Time
+}
+
+// eventDeduplicator suppresses events with the same correlator and type as an event already
+// delivered on the subscription, if they were created within the window of that event.
+// The state is held in memory, so is reset when the dispatcher restarts.
+type eventDeduplicator struct {
+	window time.Duration
+	seen   map[dedupKey]*dedupEntry
+}
+
+func newEventDeduplicator(window *fftypes.FFDuration) *eventDeduplicator {
+	if window == nil {
+		return nil
+	}
+	return &eventDeduplicator{
+		window: time.Duration(*window),
+		seen:   make(map[dedupKey]*dedupEntry),
+	}
+}
+
+func (d *eventDeduplicator) filter(ctx context.Context, events []*core.EventDelivery) []*core.EventDelivery {
+	unique := make([]*core.EventDelivery, 0, len(events))
+	var newest time.Time
+	for _, event := range events {
+		if event.Correlator == nil || event.Created == nil {
+			unique = append(unique, event)
+			continue
+		}
+		key := dedupKey{correlator: *event.Correlator, eventType: event.Type}
+		created := *event.Created.Time()
+		// The same event is redelivered after a nack, so is never a duplicate of itself
+		if prev, ok := d.seen[key]; ok && prev.id != *event.ID && absDuration(created.Sub(prev.created)) < d.window {
+			log.L(ctx).Debugf("Suppressing duplicate %s event %s for correlator %s (duplicate of %s)", event.Type, event.ID, event.Correlator, &prev.id)
+			continue
+		}
+		d.seen[key] = &dedupEntry{id: *event.ID, created: created}
+		if created.After(newest) {
+			newest = created
+		}
+		unique = append(unique, event)
+	}
+
+	// Forget the events that have moved outside of the window
+	for key, entry := range d.seen {
+		if newest.Sub(entry.created) >= d.window {
+			delete(d.seen, key)
+		}
+	}
+	return unique
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,78 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDedupEvent(correlator *fftypes.UUID, eventType core.EventType, created *fftypes.FFTime) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:         fftypes.NewUUID(),
+				Type:       eventType,
+				Correlator: correlator,
+				Created:    created,
+			},
+		},
+	}
+}
+
+func TestNewEventDeduplicatorDisabled(t *testing.T) {
+	assert.Nil(t, newEventDeduplicator(nil))
+}
+
+func TestEventDeduplicatorFilter(t *testing.T) {
+	window := fftypes.FFDuration(10 * time.Second)
+	d := newEventDeduplicator(&window)
+	ctx := context.Background()
+
+	correlator := fftypes.NewUUID()
+	t0 := time.Now()
+	at := func(offset time.Duration) *fftypes.FFTime {
+		t := fftypes.FFTime(t0.Add(offset))
+		return &t
+	}
+	e1 := newTestDedupEvent(correlator, core.EventTypeMessageConfirmed, at(0))
+	e2 := newTestDedupEvent(correlator, core.EventTypeMessageConfirmed, at(1*time.Second))
+	e3 := newTestDedupEvent(correlator, core.EventTypeTransferConfirmed, at(1*time.Second))
+	e4 := newTestDedupEvent(nil, core.EventTypeMessageConfirmed, at(1*time.Second))
+	e5 := newTestDedupEvent(correlator, core.EventTypeMessageConfirmed, nil)
+
+	unique := d.filter(ctx, []*core.EventDelivery{e1, e2, e3, e4, e5})
+	assert.Equal(t, []*core.EventDelivery{e1, e3, e4, e5}, unique)
+	assert.Len(t, d.seen, 2)
+
+	// A redelivery of the same event after a nack is not suppressed, but an older duplicate is
+	e0 := newTestDedupEvent(correlator, core.EventTypeMessageConfirmed, at(-1*time.Second))
+	unique = d.filter(ctx, []*core.EventDelivery{e0, e1, e2})
+	assert.Equal(t, []*core.EventDelivery{e1}, unique)
+
+	// Outside of the window the event is delivered, and the old entries are forgotten
+	e6 := newTestDedupEvent(correlator, core.EventTypeMessageConfirmed, at(20*time.Second))
+	unique = d.filter(ctx, []*core.EventDelivery{e6})
+	assert.Equal(t, []*core.EventDelivery{e6}, unique)
+	assert.Len(t, d.seen, 1)
+	assert.Equal(t, *e6.ID, d.seen[dedupKey{correlator: *correlator, eventType: core.EventTypeMessageConfirmed}].id)
+}
@@ -126,10 +126,103 @@ func (em *eventManager) privateBatchReceived(peerID string, batch *core.Batch, w
 	// Poke the aggregator to do its stuff - after we have committed the transaction so the pins are visible
 	if core.IsPinned(batch.Payload.TX.Type) {
 		em.aggregator.queueBatchRewind(batch.ID)
+	} else if manifest != "" {
+		// Unpinned messages are confirmed on receipt, so can be acknowledged straight away
+		if err := em.messaging.SendDeliveryReceipts(em.ctx, batch.Node, batch.Payload.TX.ID, batch.Payload.Messages); err != nil {
+			log.L(em.ctx).Warnf("Failed to send delivery receipts for batch '%s': %s", batch.ID, err)
+		}
 	}
 	return manifest, err
 }
 
+// Receipts are sent by the recipients of private messages sent by this node. Each receipt must be
+// sent by the peer of the node of the member it is for, and refer to a message in a group containing that member.
+func (em *eventManager) privateReceiptsReceived(peerID string, receipts []*core.MessageReceipt) error {
+	if em.multiparty == nil {
+		log.L(em.ctx).Errorf("Ignoring message receipts from non-multiparty network!")
+		return nil
+	}
+
+	return em.retry.Do(em.ctx, "message receipts received", func(attempt int) (bool, error) {
+		return true, em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
+			for _, receipt := range receipts {
+				if err := em.privateReceiptReceived(ctx, peerID, receipt); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (em *eventManager) privateReceiptReceived(ctx context.Context, peerID string, receipt *core.MessageReceipt) error {
+	l := log.L(ctx)
+	if receipt.Namespace != em.namespace.NetworkName {
+		l.Debugf("Ignoring message receipt from different namespace '%s'", receipt.Namespace)
+		return nil
+	}
+	if receipt.ID == nil || receipt.Message == nil || receipt.Node == nil ||
+		(receipt.Type != core.MessageReceiptTypeDelivered && receipt.Type != core.MessageReceiptTypeRead) {
+		l.Errorf("Invalid message receipt from peer '%s': %+v", peerID, receipt)
+		return nil
+	}
+
+	if valid, err := em.checkReceivedOffchainIdentity(ctx, peerID, receipt.Identity, receipt.Node); err != nil {
+		return err
+	} else if !valid {
+		l.Errorf("Message receipt '%s' received from invalid identity '%s' for peer '%s'", receipt.ID, receipt.Identity, peerID)
+		return nil
+	}
+
+	msg, err := em.database.GetMessageByID(ctx, em.namespace.Name, receipt.Message)
+	if err != nil {
+		return err
+	}
+	if msg == nil || msg.Header.Group == nil || !msg.Hash.Equals(receipt.MessageHash) {
+		l.Errorf("Message receipt '%s' does not match a private message: %s", receipt.ID, receipt.Message)
+		return nil
+	}
+
+	group, err := em.database.GetGroupByHash(ctx, em.namespace.Name, msg.Header.Group)
+	if err != nil {
+		return err
+	}
+	isMember := false
+	if group != nil {
+		for _, member := range group.Members {
+			if member.Identity == receipt.Identity && member.Node.Equals(receipt.Node) {
+				isMember = true
+				break
+			}
+		}
+	}
+	if !isMember {
+		l.Errorf("Message receipt '%s' received from '%s', which is not a member of group '%s'", receipt.ID, receipt.Identity, msg.Header.Group)
+		return nil
+	}
+
+	fb := database.MessageReceiptQueryFactory.NewFilter(ctx)
+	existing, _, err := em.database.GetMessageReceipts(ctx, em.namespace.Name, fb.And(
+		fb.Eq("message", receipt.Message),
+		fb.Eq("type", receipt.Type),
+		fb.Eq("identity", receipt.Identity),
+		fb.Eq("node", receipt.Node),
+	))
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		l.Debugf("Ignoring duplicate %s receipt for message '%s' from '%s'", receipt.Type, receipt.Message, receipt.Identity)
+		return nil
+	}
+
+	receipt.Namespace = em.namespace.Name
+	if err := em.database.InsertMessageReceipt(ctx, receipt); err != nil {
+		return err
+	}
+	return em.checkDeliverySLA(ctx, msg, receipt)
+}
+
 func (em *eventManager) markUnpinnedMessagesConfirmed(ctx context.Context, batch *core.Batch) error {
 
 	// Update all the messages in the batch with the batch ID
@@ -185,17 +278,93 @@ func (em *eventManager) messageReceived(dx dataexchange.Plugin, event dataexchan
 	l := log.L(em.ctx)
 
 	mr := event.MessageReceived()
+	if mr.Transport.Batch == nil {
+		l.Infof("Message receipts received from %s peer '%s'", dx.Name(), mr.PeerID)
+		if err := em.privateReceiptsReceived(mr.PeerID, mr.Transport.Receipts); err != nil {
+			l.Warnf("Exited while persisting receipts: %s", err)
+			return
+		}
+		event.Ack()
+		return
+	}
 	l.Infof("Private batch received from %s peer '%s'", dx.Name(), mr.PeerID)
 
-	manifestString, err := em.privateBatchReceived(mr.PeerID, mr.Transport.Batch, mr.Transport.Group)
+	handled, err := em.checkInboundLimits(mr.PeerID, mr.Transport, event.AckWithManifest)
+	if err != nil {
+		l.Warnf("Exited while applying inbound limits: %s", err)
+		return
+	}
+	if handled {
+		return
+	}
+
+	manifestString, err := em.receivedBatch(mr.PeerID, mr.Transport)
 	if err != nil {
-		l.Warnf("Exited while persisting batch: %s", err)
 		// We do NOT ack here as we broke out of the retry
 		return
 	}
 	event.AckWithManifest(manifestString)
 }
 
+// receivedBatch decrypts a private batch if it was encrypted, and persists it - returning the manifest to acknowledge it with.
+// A batch that cannot be decrypted is quarantined, so that it can be released once the node is able to decrypt it.
+func (em *eventManager) receivedBatch(peerID string, transport *core.TransportWrapper) (manifest string, err error) {
+	l := log.L(em.ctx)
+
+	valid, err := em.decryptBatch(transport)
+	if err != nil {
+		l.Warnf("Exited while decrypting batch: %s", err)
+		return "", err
+	}
+	if !valid {
+		return "", em.quarantineUndecryptableBatch(peerID, transport)
+	}
+
+	manifest, err = em.privateBatchReceived(peerID, transport.Batch, transport.Group)
+	if err != nil {
+		l.Warnf("Exited while persisting batch: %s", err)
+	}
+	return manifest, err
+}
+
+// decryptBatch restores the data of a batch received with an encrypted payload, returning false if it cannot be decrypted
+func (em *eventManager) decryptBatch(transport *core.TransportWrapper) (valid bool, err error) {
+	if transport.Encrypted == nil || em.multiparty == nil {
+		return true, nil
+	}
+	err = em.retry.Do(em.ctx, "decrypt private batch", func(attempt int) (retry bool, err error) {
+		valid, err = em.messaging.DecryptTransport(em.ctx, transport)
+		return true, err
+	})
+	return valid, err
+}
+
+// decryptBlob replaces a blob received with encrypted content by its decrypted content, before it is scanned or stored
+func (em *eventManager) decryptBlob(blob *core.Blob) error {
+	if em.multiparty == nil {
+		return nil
+	}
+	return em.retry.Do(em.ctx, "decrypt blob", func(attempt int) (retry bool, err error) {
+		return true, em.messaging.DecryptBlob(em.ctx, blob)
+	})
+}
+
+func (em *eventManager) quarantineUndecryptableBatch(peerID string, transport *core.TransportWrapper) error {
+	var node, org *core.Identity
+	err := em.retry.Do(em.ctx, "resolve batch sender", func(attempt int) (retry bool, err error) {
+		node, org, err = em.inboundSender(em.ctx, peerID)
+		return true, err
+	})
+	if err != nil {
+		return err
+	}
+	if org == nil {
+		log.L(em.ctx).Errorf("Ignoring batch '%s' that could not be decrypted from unknown peer '%s'", transport.Batch.ID, peerID)
+		return nil
+	}
+	return em.quarantineBatch(peerID, node, org, transport, len(transport.Batch.Payload.Messages), inboundSize(transport), core.QuarantineReasonDecryption)
+}
+
 func (em *eventManager) privateBlobReceived(dx dataexchange.Plugin, event dataexchange.DXEvent) {
 	br := event.PrivateBlobReceived()
 	log.L(em.ctx).Infof("Blob received event from data exchange %s: Peer='%s' Hash='%v' PayloadRef='%s'", dx.Name(), br.PeerID, &br.Hash, br.PayloadRef)
@@ -217,17 +386,35 @@ func (em *eventManager) privateBlobReceived(dx dataexchange.Plugin, event dataex
 		return
 	}
 
+	blob := &core.Blob{
+		Namespace:  em.namespace.Name,
+		Peer:       br.PeerID,
+		PayloadRef: br.PayloadRef,
+		Hash:       &br.Hash,
+		Size:       br.Size,
+		Created:    fftypes.Now(),
+		DataID:     dataID,
+	}
+	if err := em.decryptBlob(blob); err != nil {
+		log.L(em.ctx).Warnf("Exited while decrypting blob: %s", err)
+		// We do NOT ack here as we broke out of the retry
+		return
+	}
+	quarantined, err := em.scanReceivedBlob(blob)
+	if err != nil {
+		log.L(em.ctx).Warnf("Exited while scanning blob: %s", err)
+		// We do NOT ack here as we broke out of the retry
+		return
+	}
+	if quarantined {
+		// The blob is held for an administrator to release, so is not attached to its data
+		event.Ack()
+		return
+	}
+
 	// Dispatch to the blob receiver for efficient batch DB operations
 	em.blobReceiver.blobReceived(em.ctx, &blobNotification{
-		blob: &core.Blob{
-			Namespace:  em.namespace.Name,
-			Peer:       br.PeerID,
-			PayloadRef: br.PayloadRef,
-			Hash:       &br.Hash,
-			Size:       br.Size,
-			Created:    fftypes.Now(),
-			DataID:     dataID,
-		},
+		blob: blob,
 		onComplete: func() {
 			event.Ack()
 		},
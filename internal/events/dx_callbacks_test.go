@@ -267,6 +267,143 @@ func TestMessageReceivedNonMultiparty(t *testing.T) {
 
 }
 
+func TestMessageReceivedDecryptFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	b.Encrypted = &core.EncryptedPayload{}
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mpm.On("DecryptTransport", em.ctx, b).Return(false, fmt.Errorf("pop"))
+
+	// no ack as we are simulating termination mid retry
+	mde := newMessageReceivedNoAck("peer1", b)
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceivedDecryptInvalid(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	b.Encrypted = &core.EncryptedPayload{}
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mpm.On("DecryptTransport", em.ctx, b).Return(false, nil)
+	node1, org1 := mockInboundSender(em)
+	em.mdi.On("InsertQuarantinedBatch", em.ctx, mock.MatchedBy(func(qb *core.QuarantinedBatch) bool {
+		return qb.Reason == core.QuarantineReasonDecryption &&
+			qb.Org == org1.DID &&
+			qb.Node.Equals(node1.ID) &&
+			qb.Batch.Equals(b.Batch.ID) &&
+			qb.Transport == b
+	})).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBatchQuarantined
+	})).Return(nil)
+
+	// The batch is acknowledged without a manifest, as it is held in quarantine
+	mde := newMessageReceived("peer1", b, "")
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceivedDecryptInvalidUnknownSender(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	b.Encrypted = &core.EncryptedPayload{}
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mpm.On("DecryptTransport", em.ctx, b).Return(false, nil)
+	em.mim.On("FindIdentityForVerifier", em.ctx, mock.Anything, mock.Anything).Return(nil, nil)
+
+	mde := newMessageReceived("peer1", b, "")
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceivedDecryptInvalidSenderLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	b.Encrypted = &core.EncryptedPayload{}
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mpm.On("DecryptTransport", em.ctx, b).Return(false, nil)
+	em.mim.On("FindIdentityForVerifier", em.ctx, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	// no ack as we are simulating termination mid retry
+	mde := newMessageReceivedNoAck("peer1", b)
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceivedDecryptInvalidQuarantineFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	b.Encrypted = &core.EncryptedPayload{}
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mpm.On("DecryptTransport", em.ctx, b).Return(false, nil)
+	mockInboundSender(em)
+	em.mdi.On("InsertQuarantinedBatch", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	// no ack as we are simulating termination mid retry
+	mde := newMessageReceivedNoAck("peer1", b)
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceivedDecryptedWrongNS(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	b.Batch.Namespace = "ns2"
+	b.Encrypted = &core.EncryptedPayload{}
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mpm.On("DecryptTransport", em.ctx, b).Return(true, nil)
+
+	mde := newMessageReceived("peer1", b, "")
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
 func TestMessageReceiveNodeLookupError(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -414,6 +551,7 @@ func TestPrivateBlobReceivedTriggersRewindOk(t *testing.T) {
 
 	mdx := &dataexchangemocks.Plugin{}
 	mdx.On("Name").Return("utdx")
+	em.mpm.On("DecryptBlob", em.ctx, mock.Anything).Return(nil)
 
 	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
 	em.mdi.On("InsertBlobs", em.ctx, mock.Anything).Return(nil)
@@ -432,6 +570,76 @@ func TestPrivateBlobReceivedTriggersRewindOk(t *testing.T) {
 	mde.AssertExpectations(t)
 }
 
+func TestPrivateBlobReceivedDecrypted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	hash := fftypes.NewRandB32()
+	decryptedHash := fftypes.NewRandB32()
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	em.mpm.On("DecryptBlob", em.ctx, mock.MatchedBy(func(b *core.Blob) bool {
+		return b.PayloadRef == "peer1/encrypted/node1/ns1/path1" && b.Hash.Equals(hash)
+	})).Run(func(args mock.Arguments) {
+		b := args[1].(*core.Blob)
+		b.PayloadRef = "ns1/path1"
+		b.Hash = decryptedHash
+		b.Size = 1234
+	}).Return(nil)
+
+	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
+	em.mdi.On("InsertBlobs", em.ctx, mock.MatchedBy(func(blobs []*core.Blob) bool {
+		return len(blobs) == 1 && blobs[0].PayloadRef == "ns1/path1" && blobs[0].Hash.Equals(decryptedHash) && blobs[0].Size == 1234
+	})).Return(nil)
+
+	done := make(chan struct{})
+	mde := newPrivateBlobReceivedNoAck("peer1", hash, 12345, "peer1/encrypted/node1/ns1/path1", fftypes.NewUUID())
+	mde.PrivateBlobReceived().Namespace = "ns1"
+	mde.On("Ack").Run(func(args mock.Arguments) {
+		close(done)
+	})
+	em.DXEvent(mdx, mde)
+	<-done
+
+	brw := <-em.aggregator.rewinder.rewindRequests
+	assert.Equal(t, rewind{hash: *decryptedHash, rewindType: rewindBlob}, brw)
+
+	mde.AssertExpectations(t)
+}
+
+func TestPrivateBlobReceivedDecryptFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	em.mpm.On("DecryptBlob", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	// no ack as we are simulating termination mid retry
+	mde := newPrivateBlobReceivedNoAck("peer1", fftypes.NewRandB32(), 12345, "ns1/path1", fftypes.NewUUID())
+	em.privateBlobReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+}
+
+func TestPrivateBlobReceivedNoMultiparty(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.multiparty = nil
+	em.cancel() // to stop retry
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	mdm := mockBlobScan(em, "ns1/path1", "", fmt.Errorf("pop"))
+
+	mde := newPrivateBlobReceivedNoAck("peer1", fftypes.NewRandB32(), 12345, "ns1/path1", fftypes.NewUUID())
+	em.privateBlobReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
 func TestPrivateBlobReceivedBadEvent(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -465,6 +673,7 @@ func TestPrivateBlobReceivedInsertBlobFails(t *testing.T) {
 
 	mdx := &dataexchangemocks.Plugin{}
 	mdx.On("Name").Return("utdx")
+	em.mpm.On("DecryptBlob", em.ctx, mock.Anything).Return(nil)
 
 	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return([]*core.Blob{}, nil, nil)
 	em.mdi.On("InsertBlobs", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
@@ -484,6 +693,7 @@ func TestPrivateBlobReceivedGetBlobsFails(t *testing.T) {
 
 	mdx := &dataexchangemocks.Plugin{}
 	mdx.On("Name").Return("utdx")
+	em.mpm.On("DecryptBlob", em.ctx, mock.Anything).Return(nil)
 
 	em.mdi.On("GetBlobs", em.ctx, mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 
@@ -694,6 +904,7 @@ func TestMessageReceiveUnpinnedBatchOk(t *testing.T) {
 	em.mdi.On("UpdateMessages", em.ctx, "ns1", mock.Anything, mock.Anything).Return(nil)
 	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(nil)
 	em.mdm.On("UpdateMessageCache", mock.Anything, mock.Anything).Return()
+	em.mpm.On("SendDeliveryReceipts", em.ctx, node1.ID, batch.Payload.TX.ID, batch.Payload.Messages).Return(fmt.Errorf("pop"))
 
 	mde := newMessageReceived("peer1", tw, batch.Payload.Manifest(batch.ID).String())
 	em.messageReceived(mdx, mde)
@@ -835,3 +1046,203 @@ func TestMessageReceiveMessageEnsureLocalGroupReject(t *testing.T) {
 	mde.AssertExpectations(t)
 	mdx.AssertExpectations(t)
 }
+
+func newTestReceipts(t *testing.T) (*core.Message, *core.Group, *core.Identity, *core.Identity, *core.TransportWrapper) {
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members: core.Members{
+				{Identity: org1.DID, Node: node1.ID},
+			},
+		},
+	}
+	group.Seal()
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID:    fftypes.NewUUID(),
+			Type:  core.MessageTypePrivate,
+			Group: group.Hash,
+		},
+		Hash: fftypes.NewRandB32(),
+	}
+	tw := &core.TransportWrapper{
+		Receipts: []*core.MessageReceipt{{
+			ID:          fftypes.NewUUID(),
+			Namespace:   "ns1",
+			Message:     msg.Header.ID,
+			MessageHash: msg.Hash,
+			Type:        core.MessageReceiptTypeDelivered,
+			Identity:    org1.DID,
+			Node:        node1.ID,
+		}},
+	}
+	return msg, group, org1, node1, tw
+}
+
+func mockReceiptPeer(em *testEventManager, org1, node1 *core.Identity) {
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, &core.VerifierRef{
+		Type:  core.VerifierTypeFFDXPeerID,
+		Value: "peer1",
+	}).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, org1.DID).Return(org1, false, nil)
+	em.mim.On("ValidateNodeOwner", em.ctx, node1, org1).Return(true, nil)
+}
+
+func TestMessageReceiveReceiptsOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	msg, group, org1, node1, tw := newTestReceipts(t)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	mockReceiptPeer(em, org1, node1)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(msg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", group.Hash).Return(group, nil)
+	em.mdi.On("GetMessageReceipts", em.ctx, "ns1", mock.Anything).Return([]*core.MessageReceipt{}, nil, nil)
+	em.mdi.On("InsertMessageReceipt", em.ctx, tw.Receipts[0]).Return(nil)
+
+	mde := newMessageReceivedNoAck("peer1", tw)
+	mde.On("Ack").Return()
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceiveReceiptsNonMultiparty(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.multiparty = nil
+
+	_, _, _, _, tw := newTestReceipts(t)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	mde := newMessageReceivedNoAck("peer1", tw)
+	mde.On("Ack").Return()
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceiveReceiptsIgnored(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	msg, group, org1, node1, tw := newTestReceipts(t)
+	valid := tw.Receipts[0]
+
+	wrongNS := *valid
+	wrongNS.Namespace = "ns2"
+	badType := *valid
+	badType.Type = "unknown"
+	otherOrg := newTestOrg("org2")
+	badIdentity := *valid
+	badIdentity.Identity = otherOrg.DID
+	badHash := *valid
+	badHash.ID = fftypes.NewUUID()
+	badHash.MessageHash = fftypes.NewRandB32()
+	notMember := *valid
+	notMember.ID = fftypes.NewUUID()
+	notMember.Type = core.MessageReceiptTypeRead
+	tw.Receipts = []*core.MessageReceipt{&wrongNS, &badType, &badIdentity, &badHash, &notMember, valid}
+	group.Members[0].Identity = "did:firefly:org/other"
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	mockReceiptPeer(em, org1, node1)
+	em.mim.On("CachedIdentityLookupMustExist", em.ctx, otherOrg.DID).Return(nil, false, fmt.Errorf("not found"))
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(msg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", group.Hash).Return(group, nil).Once()
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", group.Hash).Return(&core.Group{
+		GroupIdentity: core.GroupIdentity{Members: core.Members{{Identity: org1.DID, Node: node1.ID}}},
+	}, nil).Once()
+	em.mdi.On("GetMessageReceipts", em.ctx, "ns1", mock.Anything).Return([]*core.MessageReceipt{valid}, nil, nil)
+
+	mde := newMessageReceivedNoAck("peer1", tw)
+	mde.On("Ack").Return()
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	em.mdi.AssertNotCalled(t, "InsertMessageReceipt", mock.Anything, mock.Anything)
+}
+
+func TestMessageReceiveReceiptsInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to avoid infinite retry
+
+	msg, group, org1, node1, tw := newTestReceipts(t)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	mockReceiptPeer(em, org1, node1)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(msg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", group.Hash).Return(group, nil)
+	em.mdi.On("GetMessageReceipts", em.ctx, "ns1", mock.Anything).Return([]*core.MessageReceipt{}, nil, nil)
+	em.mdi.On("InsertMessageReceipt", em.ctx, tw.Receipts[0]).Return(fmt.Errorf("pop"))
+
+	// no ack as we are simulating termination mid retry
+	mde := newMessageReceivedNoAck("peer1", tw)
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceiveReceiptsLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to avoid infinite retry
+
+	msg, group, org1, node1, tw := newTestReceipts(t)
+	second := *tw.Receipts[0]
+	third := *tw.Receipts[0]
+	tw.Receipts = append(tw.Receipts, &second, &third)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	mockReceiptPeer(em, org1, node1)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(msg, nil).Twice()
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(nil, fmt.Errorf("pop")).Once()
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", group.Hash).Return(group, nil).Once()
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", group.Hash).Return(nil, fmt.Errorf("pop")).Once()
+	em.mdi.On("GetMessageReceipts", em.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	mde := newMessageReceivedNoAck("peer1", tw)
+	for i := 0; i < 3; i++ {
+		em.messageReceived(mdx, mde)
+	}
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestMessageReceiveReceiptsIdentityFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to avoid infinite retry
+
+	_, _, _, _, tw := newTestReceipts(t)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	mde := newMessageReceivedNoAck("peer1", tw)
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
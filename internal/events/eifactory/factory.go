@@ -22,6 +22,14 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/events/amqp"
+	"github.com/hyperledger/firefly/internal/events/aws"
+	"github.com/hyperledger/firefly/internal/events/grpcstream"
+	"github.com/hyperledger/firefly/internal/events/kafka"
+	"github.com/hyperledger/firefly/internal/events/mqtt"
+	"github.com/hyperledger/firefly/internal/events/pubsub"
+	"github.com/hyperledger/firefly/internal/events/pull"
+	"github.com/hyperledger/firefly/internal/events/sse"
 	"github.com/hyperledger/firefly/internal/events/system"
 	"github.com/hyperledger/firefly/internal/events/webhooks"
 	"github.com/hyperledger/firefly/internal/events/websockets"
@@ -31,6 +39,15 @@ import (
 var plugins = []events.Plugin{
 	&websockets.WebSockets{},
 	&webhooks.WebHooks{},
+	&kafka.Kafka{},
+	&amqp.AMQP{},
+	&mqtt.MQTT{},
+	&aws.SQS{},
+	&aws.SNS{},
+	&pubsub.PubSub{},
+	&pull.Pull{},
+	&grpcstream.GRPC{},
+	&sse.SSE{},
 	&system.Events{},
 }
 
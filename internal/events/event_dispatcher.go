@@ -19,7 +19,9 @@ package events
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
@@ -48,6 +50,12 @@ type ackNack struct {
 	offset int64
 }
 
+// groupDelivery is an in flight event of a consumer group, and the member connection it was delivered to
+type groupDelivery struct {
+	connID string
+	event  *core.EventDelivery
+}
+
 type eventDispatcher struct {
 	acksNacks     chan ackNack
 	cancelCtx     func()
@@ -63,20 +71,60 @@ type eventDispatcher struct {
 	elected       bool
 	eventPoller   *eventPoller
 	inflight      map[fftypes.UUID]*core.Event
+	groupInflight map[fftypes.UUID]*groupDelivery
+	requeued      map[fftypes.UUID]bool
+	rebalance     chan string
 	eventDelivery chan *core.EventDelivery
 	mux           sync.Mutex
 	namespace     string
 	readAhead     int
+	parallel      bool
+	rateInterval  time.Duration
+	nextDelivery  time.Time
+	batchSize     int
+	batchTimeout  time.Duration
+	withReceipt   bool
+	dedup         *eventDeduplicator
 	subscription  *subscription
+	rules         *eventRules
 	txHelper      txcommon.Helper
 }
 
-func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.Plugin, di database.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, connID string, sub *subscription, en *eventNotifier, txHelper txcommon.Helper) *eventDispatcher {
+func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.Plugin, di database.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, connID string, sub *subscription, rules *eventRules, en *eventNotifier, txHelper txcommon.Helper) *eventDispatcher {
 	ctx, cancelCtx := context.WithCancel(ctx)
 	readAhead := config.GetUint(coreconfig.SubscriptionDefaultsReadAhead)
 	if sub.definition.Options.ReadAhead != nil {
 		readAhead = uint(*sub.definition.Options.ReadAhead)
 	}
+	var parallel bool
+	var rateInterval time.Duration
+	if delivery := sub.definition.Options.Delivery; delivery != nil {
+		if delivery.MaxInflight != nil {
+			readAhead = uint(*delivery.MaxInflight) - 1
+		}
+		switch delivery.Mode {
+		case core.SubOptsDeliveryModeOrdered:
+			readAhead = 0
+		case core.SubOptsDeliveryModeParallel:
+			parallel = true
+		}
+		if delivery.RateLimit > 0 {
+			rateInterval = time.Duration(float64(time.Second) / delivery.RateLimit)
+		}
+	}
+	var batchSize int
+	var batchTimeout time.Duration
+	if batch := sub.definition.Options.Batch; batch != nil {
+		batchSize = int(batch.Size)
+		batchTimeout = config.GetDuration(coreconfig.SubscriptionDefaultsBatchTimeout)
+		if batch.Timeout != nil {
+			batchTimeout = time.Duration(*batch.Timeout)
+		}
+		// A batch can only fill if enough events are allowed in flight
+		if readAhead < uint(batchSize-1) {
+			readAhead = uint(batchSize - 1)
+		}
+	}
 	if readAhead > maxReadAhead {
 		readAhead = maxReadAhead
 	}
@@ -93,10 +141,20 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 		connID:        connID,
 		cancelCtx:     cancelCtx,
 		subscription:  sub,
+		rules:         rules,
 		namespace:     sub.definition.Namespace,
 		inflight:      make(map[fftypes.UUID]*core.Event),
+		requeued:      make(map[fftypes.UUID]bool),
+		groupInflight: make(map[fftypes.UUID]*groupDelivery),
+		rebalance:     make(chan string),
 		eventDelivery: make(chan *core.EventDelivery, readAhead+1),
 		readAhead:     int(readAhead),
+		parallel:      parallel,
+		rateInterval:  rateInterval,
+		batchSize:     batchSize,
+		batchTimeout:  batchTimeout,
+		withReceipt:   sub.definition.Options.WithReceipt != nil && *sub.definition.Options.WithReceipt,
+		dedup:         newEventDeduplicator(sub.definition.Options.DedupWindow),
 		acksNacks:     make(chan ackNack),
 		closed:        make(chan struct{}),
 		txHelper:      txHelper,
@@ -134,10 +192,19 @@ func (ed *eventDispatcher) start() {
 func (ed *eventDispatcher) electAndStart() {
 	defer close(ed.closed)
 	l := log.L(ed.ctx)
+	group := ed.subscription.group
+	if group != nil {
+		// All dispatchers are members of the group, but only the elected one polls for events
+		group.join(ed.connID)
+		defer group.leave(ed)
+	}
 	l.Debugf("Dispatcher attempting to become leader")
 	select {
 	case ed.subscription.dispatcherElection <- true:
 		l.Debugf("Dispatcher became leader")
+		if group != nil {
+			group.elected(ed)
+		}
 		defer func() {
 			// Unelect ourselves on close, to let another dispatcher in
 			<-ed.subscription.dispatcherElection
@@ -169,6 +236,9 @@ func (ed *eventDispatcher) enrichEvents(events []core.LocallySequenced) ([]*core
 	for i, ls := range events {
 		e := ls.(*core.Event)
 		enrichedEvent, err := ed.enricher.enrichEvent(ed.ctx, e)
+		if err == nil && ed.withReceipt {
+			err = ed.enricher.enrichReceipt(ed.ctx, enrichedEvent)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -181,81 +251,102 @@ func (ed *eventDispatcher) enrichEvents(events []core.LocallySequenced) ([]*core
 }
 
 func (ed *eventDispatcher) filterEvents(candidates []*core.EventDelivery) []*core.EventDelivery {
+	rules := ed.rules.forSubscription(ed.subscription.definition)
 	matchingEvents := make([]*core.EventDelivery, 0, len(candidates))
 	for _, event := range candidates {
-		filter := ed.subscription
-		if filter.eventMatcher != nil && !filter.eventMatcher.MatchString(string(event.Type)) {
+		outcome := applyEventRules(ed.ctx, rules, &event.EnrichedEvent)
+		if outcome.suppress || (!outcome.route && !ed.subscription.matches(ed.ctx, event)) {
 			continue
 		}
-
-		msg := event.Message
-		tx := event.Transaction
-		be := event.BlockchainEvent
-		tag := ""
-		topic := event.Topic
-		group := ""
-		author := ""
-		txType := ""
-		beName := ""
-		beListener := ""
-
-		if msg != nil {
-			tag = msg.Header.Tag
-			author = msg.Header.Author
-			if msg.Header.Group != nil {
-				group = msg.Header.Group.String()
-			}
+		if outcome.labels != nil {
+			event.Labels = outcome.labels
 		}
+		matchingEvents = append(matchingEvents, event)
+	}
+	return matchingEvents
+}
 
-		if tx != nil {
-			txType = tx.Type.String()
-		}
+// matches checks an event against the filters of the subscription
+func (sub *subscription) matches(ctx context.Context, event *core.EventDelivery) bool {
+	filter := sub
+	if filter.eventMatcher != nil && !filter.eventMatcher.MatchString(string(event.Type)) {
+		return false
+	}
 
-		if be != nil {
-			beName = be.Name
-			beListener = be.Listener.String()
+	msg := event.Message
+	tx := event.Transaction
+	be := event.BlockchainEvent
+	tag := ""
+	topic := event.Topic
+	group := ""
+	author := ""
+	txType := ""
+	beName := ""
+	beListener := ""
+
+	if msg != nil {
+		tag = msg.Header.Tag
+		author = msg.Header.Author
+		if msg.Header.Group != nil {
+			group = msg.Header.Group.String()
 		}
+	}
 
-		if filter.topicFilter != nil {
-			topicsMatch := false
-			if filter.topicFilter.MatchString(topic) {
-				topicsMatch = true
-			}
-			if !topicsMatch {
-				continue
-			}
+	if tx != nil {
+		txType = tx.Type.String()
+	}
+
+	if be != nil {
+		beName = be.Name
+		beListener = be.Listener.String()
+	}
+
+	if filter.topicFilter != nil {
+		topicsMatch := false
+		if filter.topicFilter.MatchString(topic) {
+			topicsMatch = true
 		}
+		if !topicsMatch {
+			return false
+		}
+	}
 
-		if filter.messageFilter != nil {
-			if filter.messageFilter.tagFilter != nil && !filter.messageFilter.tagFilter.MatchString(tag) {
-				continue
-			}
-			if filter.messageFilter.authorFilter != nil && !filter.messageFilter.authorFilter.MatchString(author) {
-				continue
-			}
-			if filter.messageFilter.groupFilter != nil && !filter.messageFilter.groupFilter.MatchString(group) {
-				continue
-			}
+	if filter.messageFilter != nil {
+		if filter.messageFilter.tagFilter != nil && !filter.messageFilter.tagFilter.MatchString(tag) {
+			return false
+		}
+		if filter.messageFilter.authorFilter != nil && !filter.messageFilter.authorFilter.MatchString(author) {
+			return false
 		}
+		if filter.messageFilter.groupFilter != nil && !filter.messageFilter.groupFilter.MatchString(group) {
+			return false
+		}
+	}
 
-		if filter.transactionFilter != nil {
-			if filter.transactionFilter.typeFilter != nil && !filter.transactionFilter.typeFilter.MatchString(txType) {
-				continue
-			}
+	if filter.transactionFilter != nil {
+		if filter.transactionFilter.typeFilter != nil && !filter.transactionFilter.typeFilter.MatchString(txType) {
+			return false
 		}
+	}
 
-		if filter.blockchainFilter != nil {
-			if filter.blockchainFilter.nameFilter != nil && !filter.blockchainFilter.nameFilter.MatchString(beName) {
-				continue
-			}
-			if filter.blockchainFilter.listenerFilter != nil && !filter.blockchainFilter.listenerFilter.MatchString(beListener) {
-				continue
-			}
+	if filter.blockchainFilter != nil {
+		if filter.blockchainFilter.nameFilter != nil && !filter.blockchainFilter.nameFilter.MatchString(beName) {
+			return false
+		}
+		if filter.blockchainFilter.listenerFilter != nil && !filter.blockchainFilter.listenerFilter.MatchString(beListener) {
+			return false
 		}
+	}
 
-		matchingEvents = append(matchingEvents, event)
+	if filter.transferFilter != nil && !filter.transferFilter.matches(event.TokenTransfer) {
+		return false
 	}
-	return matchingEvents
+
+	if filter.expressionFilter != nil && !filter.expressionFilter.matches(ctx, &event.EnrichedEvent) {
+		return false
+	}
+
+	return true
 }
 
 func (ed *eventDispatcher) bufferedDelivery(events []core.LocallySequenced) (bool, error) {
@@ -275,8 +366,14 @@ func (ed *eventDispatcher) bufferedDelivery(events []core.LocallySequenced) (boo
 	if err != nil {
 		return false, err
 	}
+	if err := ed.rules.load(ed.ctx, ed.namespace, ed.database); err != nil {
+		return false, err
+	}
 
 	matching := ed.filterEvents(candidates)
+	if ed.dedup != nil {
+		matching = ed.dedup.filter(ed.ctx, matching)
+	}
 	matchCount := len(matching)
 	dispatched := 0
 
@@ -345,12 +442,14 @@ func (ed *eventDispatcher) handleNackOffsetUpdate(nack ackNack) {
 		ed.eventPoller.rewindPollingOffset(nack.offset - 1)
 	}
 	ed.inflight = map[fftypes.UUID]*core.Event{}
+	ed.groupInflight = map[fftypes.UUID]*groupDelivery{}
 }
 
 func (ed *eventDispatcher) handleAckOffsetUpdate(ack ackNack) {
 	oldOffset := ed.eventPoller.getPollingOffset()
 	ed.mux.Lock()
 	delete(ed.inflight, ack.id)
+	delete(ed.groupInflight, ack.id)
 	lowestInflight := int64(-1)
 	for _, inflight := range ed.inflight {
 		if lowestInflight < 0 || inflight.Sequence < lowestInflight {
@@ -366,27 +465,163 @@ func (ed *eventDispatcher) handleAckOffsetUpdate(ack ackNack) {
 
 func (ed *eventDispatcher) deliverEvents() {
 	withData := ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+	// In parallel mode each delivery is made on its own goroutine, up to the maximum in flight
+	if ed.batchSize > 0 {
+		ed.deliverBatches(withData)
+		return
+	}
+	var workers chan struct{}
+	if ed.parallel {
+		workers = make(chan struct{}, ed.readAhead+1)
+	}
 	for {
 		select {
 		case event, ok := <-ed.eventDelivery:
 			if !ok {
 				return
 			}
-			log.L(ed.ctx).Debugf("Dispatching %s event: %.10d/%s [%s]: ref=%s/%s", ed.transport.Name(), event.Sequence, event.ID, event.Type, event.Namespace, event.Reference)
-			var data []*core.Data
-			var err error
-			if withData && event.Message != nil {
-				data, _, err = ed.data.GetMessageDataCached(ed.ctx, event.Message)
+			if !ed.throttle() {
+				return
+			}
+			if workers == nil {
+				ed.deliverEvent(withData, event)
+				continue
 			}
-			if err == nil {
-				err = ed.transport.DeliveryRequest(ed.connID, ed.subscription.definition, event, data)
+			select {
+			case workers <- struct{}{}:
+			case <-ed.ctx.Done():
+				return
 			}
-			if err != nil {
-				ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID, Rejected: true})
+			go func() {
+				defer func() { <-workers }()
+				ed.deliverEvent(withData, event)
+			}()
+		case connID := <-ed.rebalance:
+			ed.redeliverGroupEvents(withData, connID)
+		case <-ed.ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverBatches collects events into batches of up to the batch size, waiting at most the batch timeout
+// after the first event of each batch for it to fill, and delivers each batch in a single request
+func (ed *eventDispatcher) deliverBatches(withData bool) {
+	for {
+		var batch []*core.EventDelivery
+		select {
+		case event, ok := <-ed.eventDelivery:
+			if !ok {
+				return
 			}
+			batch = append(batch, event)
 		case <-ed.ctx.Done():
 			return
 		}
+		timeout := time.NewTimer(ed.batchTimeout)
+	fill:
+		for len(batch) < ed.batchSize {
+			select {
+			case event, ok := <-ed.eventDelivery:
+				if !ok {
+					timeout.Stop()
+					return
+				}
+				batch = append(batch, event)
+			case <-timeout.C:
+				break fill
+			case <-ed.ctx.Done():
+				timeout.Stop()
+				return
+			}
+		}
+		timeout.Stop()
+		if !ed.throttle() {
+			return
+		}
+		ed.deliverBatch(withData, batch)
+	}
+}
+
+func (ed *eventDispatcher) deliverBatch(withData bool, batch []*core.EventDelivery) {
+	log.L(ed.ctx).Debugf("Dispatching batch of %d %s events to %s: %.10d-%.10d", len(batch), ed.transport.Name(), ed.connID, batch[0].Sequence, batch[len(batch)-1].Sequence)
+	deliveries := make([]*core.CombinedEventDataDelivery, len(batch))
+	var err error
+	for i, event := range batch {
+		deliveries[i] = &core.CombinedEventDataDelivery{Event: event}
+		if withData && event.Message != nil {
+			if deliveries[i].Data, _, err = ed.data.GetMessageDataCached(ed.ctx, event.Message); err != nil {
+				break
+			}
+		}
+	}
+	if err == nil {
+		err = ed.transport.BatchDeliveryRequest(ed.connID, ed.subscription.definition, deliveries)
+	}
+	if err != nil {
+		// Rejecting the first event redelivers the whole batch, as all events after it are rewound
+		ed.deliveryResponse(&core.EventDeliveryResponse{ID: batch[0].ID, Rejected: true})
+	}
+}
+
+// throttle blocks until the rate limit of the subscription allows another delivery, returning false if the dispatcher closes
+func (ed *eventDispatcher) throttle() bool {
+	if ed.rateInterval == 0 {
+		return true
+	}
+	now := time.Now()
+	if wait := ed.nextDelivery.Sub(now); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-ed.ctx.Done():
+			return false
+		}
+		now = ed.nextDelivery
+	}
+	ed.nextDelivery = now.Add(ed.rateInterval)
+	return true
+}
+
+func (ed *eventDispatcher) deliverEvent(withData bool, event *core.EventDelivery) {
+	connID := ed.connID
+	if group := ed.subscription.group; group != nil {
+		connID = group.pick(event, ed.connID)
+		ed.mux.Lock()
+		if _, inflight := ed.inflight[*event.ID]; inflight {
+			ed.groupInflight[*event.ID] = &groupDelivery{connID: connID, event: event}
+		}
+		ed.mux.Unlock()
+	}
+	log.L(ed.ctx).Debugf("Dispatching %s event to %s: %.10d/%s [%s]: ref=%s/%s", ed.transport.Name(), connID, event.Sequence, event.ID, event.Type, event.Namespace, event.Reference)
+	var data []*core.Data
+	var err error
+	if withData && event.Message != nil {
+		data, _, err = ed.data.GetMessageDataCached(ed.ctx, event.Message)
+	}
+	if err == nil {
+		err = ed.transport.DeliveryRequest(connID, ed.subscription.definition, event, data)
+	}
+	if err != nil {
+		ed.deliveryResponse(&core.EventDeliveryResponse{ID: event.ID, Rejected: true})
+	}
+}
+
+// redeliverGroupEvents moves the events in flight on a connection that has left the consumer group
+// to the remaining members, so they are not lost or delivered twice
+func (ed *eventDispatcher) redeliverGroupEvents(withData bool, connID string) {
+	ed.mux.Lock()
+	var orphaned []*core.EventDelivery
+	for _, gd := range ed.groupInflight {
+		if gd.connID == connID {
+			orphaned = append(orphaned, gd.event)
+		}
+	}
+	ed.mux.Unlock()
+	sort.Slice(orphaned, func(i, j int) bool { return orphaned[i].Sequence < orphaned[j].Sequence })
+
+	log.L(ed.ctx).Infof("Rebalancing %d in flight events from connection %s", len(orphaned), connID)
+	for _, event := range orphaned {
+		ed.deliverEvent(withData, event)
 	}
 }
 
@@ -401,9 +636,27 @@ func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse
 		an.offset = event.Sequence
 		an.isNack = response.Rejected
 	}
+	requeued := ed.requeued[*response.ID]
+	delete(ed.requeued, *response.ID)
 	ed.mux.Unlock()
 
+	// Record any dead letter before anything else, as in fastack mode the event is no longer in flight
+	if response.DeadLetter != nil {
+		if err := ed.recordDeadLetter(response.DeadLetter); err != nil && found {
+			// Have the event redelivered, rather than lose it
+			an.isNack = true
+		}
+		if !found {
+			return
+		}
+	}
+
 	// Do some extra logging and persistent actions now we're out of lock
+	if !found && requeued {
+		// Requeued events are behind the offset of the subscription, so there is nothing to acknowledge
+		l.Infof("Response for requeued event %s rejected=%t info='%s'", response.ID, response.Rejected, response.Info)
+		return
+	}
 	if !found {
 		l.Warnf("Response for event not in flight: %s rejected=%t info='%s' (likely previous reject)", response.ID, response.Rejected, response.Info)
 		return
@@ -426,6 +679,48 @@ func (ed *eventDispatcher) deliveryResponse(response *core.EventDeliveryResponse
 	}
 }
 
+// prepareRequeue enriches an event that was previously dead lettered, ready to be redelivered
+func (ed *eventDispatcher) prepareRequeue(event *core.Event) (*core.EventDelivery, error) {
+	deliveries, err := ed.enrichEvents([]core.LocallySequenced{event})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries[0], nil
+}
+
+// requeue redelivers an event that was previously dead lettered, outside of the ordered delivery of the subscription.
+// The event is not tracked in flight, and if it fails again a new dead letter is recorded for it.
+func (ed *eventDispatcher) requeue(event *core.EventDelivery) {
+	ed.mux.Lock()
+	ed.requeued[*event.ID] = true
+	ed.mux.Unlock()
+	withData := ed.subscription.definition.Options.WithData != nil && *ed.subscription.definition.Options.WithData
+	go ed.deliverEvent(withData, event)
+}
+
+func (ed *eventDispatcher) recordDeadLetter(deadLetter *core.DeadLetter) error {
+	deadLetter.ID = fftypes.NewUUID()
+	deadLetter.Namespace = ed.namespace
+	deadLetter.Subscription = ed.subscription.definition.SubscriptionRef
+	err := ed.database.RunAsGroup(ed.ctx, func(ctx context.Context) error {
+		if err := ed.database.InsertDeadLetter(ctx, deadLetter); err != nil {
+			return err
+		}
+		if deadLetter.EventType == core.EventTypeDeadLetterCreated {
+			// Do not emit an event for a dead letter of a dead letter event, to avoid a loop
+			return nil
+		}
+		event := core.NewEvent(core.EventTypeDeadLetterCreated, ed.namespace, deadLetter.ID, nil, deadLetter.Subscription.ID.String())
+		return ed.database.InsertEvent(ctx, event)
+	})
+	if err != nil {
+		log.L(ed.ctx).Errorf("Failed to record dead letter for event %s after %d attempts: %s", deadLetter.Event, deadLetter.Attempts, err)
+		return err
+	}
+	log.L(ed.ctx).Warnf("Event %s recorded as dead letter %s after %d attempts: %s", deadLetter.Event, deadLetter.ID, deadLetter.Attempts, deadLetter.Error)
+	return nil
+}
+
 func (ed *eventDispatcher) close() {
 	log.L(ed.ctx).Infof("Dispatcher closing for conn=%s subscription=%s", ed.connID, ed.subscription.definition.ID)
 	ed.cancelCtx()
@@ -59,7 +59,7 @@ func newTestEventDispatcher(sub *subscription) (*eventDispatcher, func()) {
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
 	enricher := newEventEnricher("ns1", mdi, mdm, mom, txHelper)
 	ctx, cancel := context.WithCancel(context.Background())
-	return newEventDispatcher(ctx, enricher, mei, mdi, mdm, mbm, mpm, fftypes.NewUUID().String(), sub, newEventNotifier(ctx, "ut"), txHelper), func() {
+	return newEventDispatcher(ctx, enricher, mei, mdi, mdm, mbm, mpm, fftypes.NewUUID().String(), sub, &eventRules{loaded: true}, newEventNotifier(ctx, "ut"), txHelper), func() {
 		cancel()
 		coreconfig.Reset()
 	}
@@ -111,6 +111,279 @@ func TestMaxReadAhead(t *testing.T) {
 	assert.Equal(t, int(65536), ed.readAhead)
 }
 
+func newTestDeliverySubscription(delivery *core.SubscriptionDeliveryOptions) *subscription {
+	ten := uint16(10)
+	return &subscription{
+		dispatcherElection: make(chan bool, 1),
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					ReadAhead: &ten,
+					Delivery:  delivery,
+				},
+			},
+		},
+	}
+}
+
+func TestDeliveryOptionsMaxInflight(t *testing.T) {
+	three := uint16(3)
+	ed, cancel := newTestEventDispatcher(newTestDeliverySubscription(&core.SubscriptionDeliveryOptions{
+		MaxInflight: &three,
+		RateLimit:   4,
+	}))
+	defer cancel()
+	assert.Equal(t, 2, ed.readAhead)
+	assert.False(t, ed.parallel)
+	assert.Equal(t, 250*time.Millisecond, ed.rateInterval)
+}
+
+func TestDeliveryOptionsOrdered(t *testing.T) {
+	three := uint16(3)
+	ed, cancel := newTestEventDispatcher(newTestDeliverySubscription(&core.SubscriptionDeliveryOptions{
+		Mode:        core.SubOptsDeliveryModeOrdered,
+		MaxInflight: &three,
+	}))
+	defer cancel()
+	assert.Equal(t, 0, ed.readAhead)
+	assert.False(t, ed.parallel)
+	assert.Zero(t, ed.rateInterval)
+}
+
+func TestDeliveryOptionsParallel(t *testing.T) {
+	ed, cancel := newTestEventDispatcher(newTestDeliverySubscription(&core.SubscriptionDeliveryOptions{
+		Mode: core.SubOptsDeliveryModeParallel,
+	}))
+	defer cancel()
+	assert.Equal(t, 10, ed.readAhead)
+	assert.True(t, ed.parallel)
+}
+
+func TestDeliverEventsParallel(t *testing.T) {
+	two := uint16(2)
+	ed, cancel := newTestEventDispatcher(newTestDeliverySubscription(&core.SubscriptionDeliveryOptions{
+		Mode:        core.SubOptsDeliveryModeParallel,
+		MaxInflight: &two,
+	}))
+	defer cancel()
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	delivered := make(chan *core.EventDelivery)
+	release := make(chan struct{})
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		delivered <- a.Get(2).(*core.EventDelivery)
+		<-release
+	})
+
+	go ed.deliverEvents()
+	go func() {
+		for i := 0; i < 3; i++ {
+			ed.eventDelivery <- &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Sequence: int64(i)}}}
+		}
+	}()
+
+	// Both the in flight slots are taken, before either delivery completes
+	<-delivered
+	<-delivered
+	select {
+	case <-delivered:
+		assert.Fail(t, "delivered beyond max in flight")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	// Completing one delivery frees a slot for the next
+	release <- struct{}{}
+	<-delivered
+	close(release)
+}
+
+func TestDeliverEventsParallelClosed(t *testing.T) {
+	one := uint16(1)
+	ed, cancel := newTestEventDispatcher(newTestDeliverySubscription(&core.SubscriptionDeliveryOptions{
+		Mode:        core.SubOptsDeliveryModeParallel,
+		MaxInflight: &one,
+	}))
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	delivered := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once().Run(func(a mock.Arguments) {
+		close(delivered)
+		<-release
+	})
+
+	done := make(chan struct{})
+	go func() {
+		ed.deliverEvents()
+		close(done)
+	}()
+	ed.eventDelivery <- &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}}
+	<-delivered
+	ed.eventDelivery <- &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}}
+	cancel()
+	<-done
+}
+
+func TestDeliverEventsThrottle(t *testing.T) {
+	ed, cancel := newTestEventDispatcher(newTestDeliverySubscription(&core.SubscriptionDeliveryOptions{
+		RateLimit: 100,
+	}))
+	defer cancel()
+
+	start := time.Now()
+	assert.True(t, ed.throttle())
+	assert.True(t, ed.throttle())
+	assert.True(t, ed.throttle())
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func TestDeliverEventsThrottleClosed(t *testing.T) {
+	ed, cancel := newTestEventDispatcher(newTestDeliverySubscription(&core.SubscriptionDeliveryOptions{
+		RateLimit: 0.1,
+	}))
+	mei := ed.transport.(*eventsmocks.Plugin)
+	delivered := make(chan struct{})
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once().Run(func(a mock.Arguments) {
+		close(delivered)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		ed.deliverEvents()
+		close(done)
+	}()
+	ed.eventDelivery <- &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}}
+	<-delivered
+	ed.eventDelivery <- &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}}
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	<-done
+	mei.AssertExpectations(t)
+}
+
+func newTestBatchSubscription(batch *core.SubscriptionBatchOptions) *subscription {
+	sub := newTestDeliverySubscription(nil)
+	sub.definition.Options.ReadAhead = nil
+	sub.definition.Options.Batch = batch
+	return sub
+}
+
+func TestBatchOptions(t *testing.T) {
+	timeout := fftypes.FFDuration(50 * time.Millisecond)
+	ed, cancel := newTestEventDispatcher(newTestBatchSubscription(&core.SubscriptionBatchOptions{
+		Size:    25,
+		Timeout: &timeout,
+	}))
+	defer cancel()
+	assert.Equal(t, 24, ed.readAhead)
+	assert.Equal(t, 25, ed.batchSize)
+	assert.Equal(t, 50*time.Millisecond, ed.batchTimeout)
+}
+
+func TestBatchOptionsDefaultTimeout(t *testing.T) {
+	sub := newTestBatchSubscription(&core.SubscriptionBatchOptions{Size: 5})
+	hundred := uint16(100)
+	sub.definition.Options.ReadAhead = &hundred
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	assert.Equal(t, 100, ed.readAhead)
+	assert.Equal(t, 250*time.Millisecond, ed.batchTimeout)
+}
+
+func TestDeliverBatchesFullAndTimeout(t *testing.T) {
+	timeout := fftypes.FFDuration(10 * time.Millisecond)
+	ed, cancel := newTestEventDispatcher(newTestBatchSubscription(&core.SubscriptionBatchOptions{
+		Size:    2,
+		Timeout: &timeout,
+	}))
+	defer cancel()
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	batches := make(chan []*core.CombinedEventDataDelivery)
+	mei.On("BatchDeliveryRequest", ed.connID, ed.subscription.definition, mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		batches <- a.Get(2).([]*core.CombinedEventDataDelivery)
+	})
+
+	go ed.deliverEvents()
+	for i := 0; i < 3; i++ {
+		ed.eventDelivery <- &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Sequence: int64(i)}}}
+	}
+
+	// The first batch is delivered when full, and the second when the timeout expires
+	batch := <-batches
+	assert.Len(t, batch, 2)
+	assert.Equal(t, int64(0), batch[0].Event.Sequence)
+	assert.Equal(t, int64(1), batch[1].Event.Sequence)
+	batch = <-batches
+	assert.Len(t, batch, 1)
+	assert.Equal(t, int64(2), batch[0].Event.Sequence)
+}
+
+func TestDeliverBatchWithData(t *testing.T) {
+	ed, cancel := newTestEventDispatcher(newTestBatchSubscription(&core.SubscriptionBatchOptions{Size: 2}))
+	defer cancel()
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"test"`)}}
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", ed.ctx, msg).Return(data, true, nil)
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.On("BatchDeliveryRequest", ed.connID, ed.subscription.definition, mock.MatchedBy(func(deliveries []*core.CombinedEventDataDelivery) bool {
+		return len(deliveries) == 2 && deliveries[0].Data[0] == data[0] && deliveries[1].Data == nil
+	})).Return(nil)
+
+	ed.deliverBatch(true, []*core.EventDelivery{
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}, Message: msg}},
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}},
+	})
+
+	mdm.AssertExpectations(t)
+	mei.AssertExpectations(t)
+}
+
+func TestDeliverBatchFailRejectsFirst(t *testing.T) {
+	ed, cancel := newTestEventDispatcher(newTestBatchSubscription(&core.SubscriptionBatchOptions{Size: 2}))
+	defer cancel()
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", ed.ctx, msg).Return(nil, false, fmt.Errorf("pop"))
+
+	event1 := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Sequence: 10}, Message: msg}}
+	event2 := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Sequence: 11}}}
+	ed.inflight[*event1.ID] = &event1.Event
+	ed.inflight[*event2.ID] = &event2.Event
+
+	go ed.deliverBatch(true, []*core.EventDelivery{event1, event2})
+	an := <-ed.acksNacks
+	assert.True(t, an.isNack)
+	assert.Equal(t, *event1.ID, an.id)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestDeliverBatchesClosed(t *testing.T) {
+	timeout := fftypes.FFDuration(1 * time.Minute)
+	ed, cancel := newTestEventDispatcher(newTestBatchSubscription(&core.SubscriptionBatchOptions{
+		Size:    2,
+		Timeout: &timeout,
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		ed.deliverEvents()
+		close(done)
+	}()
+	ed.eventDelivery <- &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID()}}}
+	cancel()
+	<-done
+
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.AssertNotCalled(t, "BatchDeliveryRequest", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestEventDispatcherLeaderElection(t *testing.T) {
 	log.SetLevel("debug")
 
@@ -151,6 +424,54 @@ func TestEventDispatcherLeaderElection(t *testing.T) {
 
 }
 
+func TestEventDispatcherConsumerGroupElection(t *testing.T) {
+	sub := newTestGroupSubscription(t, &core.SubscriptionConsumerGroup{})
+	subID := sub.definition.ID
+
+	ed1, cancel1 := newTestEventDispatcher(sub)
+	ed2, cancel2 := newTestEventDispatcher(sub /* same sub */)
+
+	gev1Wait := make(chan bool)
+	gev1Done := make(chan struct{})
+	mdi1 := ed1.database.(*databasemocks.Plugin)
+	gev1 := mdi1.On("GetEvents", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Event{}, nil, nil)
+	mdi1.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subID.String()).Return(&core.Offset{
+		Type:    core.OffsetTypeSubscription,
+		Name:    subID.String(),
+		Current: 12345,
+		RowID:   333333,
+	}, nil)
+	gev1.RunFn = func(a mock.Arguments) {
+		gev1Wait <- true
+		<-gev1Done
+	}
+
+	ed1.start()
+	<-gev1Wait
+	assert.Equal(t, ed1, sub.group.getLeader())
+	ed2.start()
+
+	// Both dispatchers are members, even though only the leader is polling
+	members := func() []string {
+		sub.group.mux.Lock()
+		defer sub.group.mux.Unlock()
+		return append([]string{}, sub.group.members...)
+	}
+	for len(members()) < 2 {
+		time.Sleep(1 * time.Millisecond)
+	}
+	assert.Equal(t, []string{ed1.connID, ed2.connID}, members())
+
+	go func() {
+		<-ed1.rebalance
+	}()
+	cancel2()
+	ed2.close() // while ed1 is active
+	assert.Equal(t, []string{ed1.connID}, members())
+	close(gev1Done)
+	cancel1()
+}
+
 func TestEventDispatcherReadAheadOutOfOrderAcks(t *testing.T) {
 	log.SetLevel("debug")
 	var five = uint16(5)
@@ -394,6 +715,127 @@ func TestEnrichEventsFailGetBlockchainEvents(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestEnrichEventsWithReceipt(t *testing.T) {
+
+	withReceipt := true
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{WithReceipt: &withReceipt},
+			},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	txID := fftypes.NewUUID()
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", txID).Return(&core.Transaction{ID: txID}, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{Status: core.OpStatusSucceeded, Output: fftypes.JSONObject{"transactionHash": "0x12345"}},
+	}, nil, nil)
+
+	events, err := ed.enrichEvents([]core.LocallySequenced{&core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeTransactionSubmitted, Reference: txID}})
+	assert.NoError(t, err)
+	assert.Equal(t, "0x12345", events[0].Receipt.TransactionHash)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEnrichEventsWithReceiptFail(t *testing.T) {
+
+	withReceipt := true
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{WithReceipt: &withReceipt},
+			},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	txID := fftypes.NewUUID()
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", txID).Return(&core.Transaction{ID: txID}, nil)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := ed.enrichEvents([]core.LocallySequenced{&core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeTransactionSubmitted, Reference: txID}})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestFilterEventsExpression(t *testing.T) {
+
+	ef, err := newExpressionFilter(context.Background(), "filter.expression", "int(event.tokenTransfer.amount) > 1000")
+	assert.NoError(t, err)
+	sub := &subscription{
+		definition:       &core.Subscription{},
+		expressionFilter: ef,
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	match := newTestTransferEvent("0x01", 1001)
+	events := ed.filterEvents([]*core.EventDelivery{
+		{EnrichedEvent: *match},
+		{EnrichedEvent: *newTestTransferEvent("0x01", 5)},
+	})
+	assert.Len(t, events, 1)
+	assert.Equal(t, *match.ID, *events[0].ID)
+}
+
+func TestFilterEventsTokenTransfer(t *testing.T) {
+
+	pool := fftypes.NewUUID()
+	tf, err := parseTokenTransferFilter(context.Background(), &core.TokenTransferFilter{
+		Pool:       pool.String(),
+		TokenIndex: "^1$",
+		From:       "0x01",
+		To:         "0x02",
+		MinAmount:  fftypes.NewFFBigInt(100),
+		MaxAmount:  fftypes.NewFFBigInt(1000),
+	})
+	assert.NoError(t, err)
+	sub := &subscription{
+		definition:     &core.Subscription{},
+		transferFilter: tf,
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	newTransfer := func(mod func(t *core.TokenTransfer)) core.EventDelivery {
+		event := newTestTransferEvent("0x02", 100)
+		event.TokenTransfer.Pool = pool
+		event.TokenTransfer.TokenIndex = "1"
+		event.TokenTransfer.From = "0x01"
+		if mod != nil {
+			mod(event.TokenTransfer)
+		}
+		return core.EventDelivery{EnrichedEvent: *event}
+	}
+	matchMin := newTransfer(nil)
+	matchMax := newTransfer(func(t *core.TokenTransfer) { t.Amount.Int().SetInt64(1000) })
+	events := ed.filterEvents([]*core.EventDelivery{
+		&matchMin,
+		&matchMax,
+		{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed}}},
+		ptrTo(newTransfer(func(t *core.TokenTransfer) { t.Amount.Int().SetInt64(99) })),
+		ptrTo(newTransfer(func(t *core.TokenTransfer) { t.Amount.Int().SetInt64(1001) })),
+		ptrTo(newTransfer(func(t *core.TokenTransfer) { t.Pool = fftypes.NewUUID() })),
+		ptrTo(newTransfer(func(t *core.TokenTransfer) { t.Pool = nil })),
+		ptrTo(newTransfer(func(t *core.TokenTransfer) { t.TokenIndex = "10" })),
+		ptrTo(newTransfer(func(t *core.TokenTransfer) { t.From = "0x03" })),
+		ptrTo(newTransfer(func(t *core.TokenTransfer) { t.To = "0x03" })),
+	})
+	assert.Len(t, events, 2)
+	assert.Equal(t, *matchMin.ID, *events[0].ID)
+	assert.Equal(t, *matchMax.ID, *events[1].ID)
+}
+
+func ptrTo(event core.EventDelivery) *core.EventDelivery {
+	return &event
+}
+
 func TestFilterEventsMatch(t *testing.T) {
 
 	sub := &subscription{
@@ -862,6 +1304,54 @@ func TestBufferedDeliveryNackRewind(t *testing.T) {
 	assert.Equal(t, int64(100000), ed.eventPoller.pollingOffset)
 }
 
+func TestBufferedDeliveryDedup(t *testing.T) {
+	window := fftypes.FFDuration(1 * time.Minute)
+	sub := &subscription{
+		definition: &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					DedupWindow: &window,
+				},
+			},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	go ed.deliverEvents()
+	ed.eventPoller.offsetCommitted = make(chan int64, 2)
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mdi.On("UpdateOffset", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	delivered := make(chan *core.EventDelivery)
+	mei.On("DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil).Once().Run(func(a mock.Arguments) {
+		delivered <- a.Get(2).(*core.EventDelivery)
+	})
+
+	correlator := fftypes.NewUUID()
+	created := fftypes.Now()
+	ev1 := &core.Event{ID: fftypes.NewUUID(), Sequence: 100001, Correlator: correlator, Created: created}
+	ev2 := &core.Event{ID: fftypes.NewUUID(), Sequence: 100002, Correlator: correlator, Created: created}
+	bdDone := make(chan struct{})
+	go func() {
+		repoll, err := ed.bufferedDelivery([]core.LocallySequenced{ev1, ev2})
+		assert.NoError(t, err)
+		assert.True(t, repoll)
+		close(bdDone)
+	}()
+
+	event := <-delivered
+	assert.Equal(t, *ev1.ID, *event.ID)
+	ed.deliveryResponse(&core.EventDeliveryResponse{ID: ev1.ID})
+
+	<-bdDone
+	// The offset moves past the suppressed duplicate
+	assert.Equal(t, int64(100001), <-ed.eventPoller.offsetCommitted)
+	assert.Equal(t, int64(100002), <-ed.eventPoller.offsetCommitted)
+	mei.AssertExpectations(t)
+}
+
 func TestBufferedDeliveryFailNack(t *testing.T) {
 	log.SetLevel("trace")
 
@@ -945,6 +1435,114 @@ func TestAckClosed(t *testing.T) {
 	ed.deliveryResponse(&core.EventDeliveryResponse{ID: id1})
 }
 
+func TestDeadLetterNotInFlight(t *testing.T) {
+
+	subID := fftypes.NewUUID()
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
+	}
+	mdi.On("InsertDeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		return dl.ID != nil && dl.Namespace == "ns1" && *dl.Subscription.ID == *subID && dl.Attempts == 3
+	})).Return(nil)
+	mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeDeadLetterCreated && e.Topic == subID.String()
+	})).Return(nil)
+
+	ed.deliveryResponse(&core.EventDeliveryResponse{
+		ID: fftypes.NewUUID(),
+		DeadLetter: &core.DeadLetter{
+			EventType: core.EventTypeMessageConfirmed,
+			Attempts:  3,
+		},
+	})
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeadLetterOfDeadLetterNoEvent(t *testing.T) {
+
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
+	}
+	mdi.On("InsertDeadLetter", mock.Anything, mock.Anything).Return(nil)
+
+	ed.deliveryResponse(&core.EventDeliveryResponse{
+		ID: fftypes.NewUUID(),
+		DeadLetter: &core.DeadLetter{
+			EventType: core.EventTypeDeadLetterCreated,
+		},
+	})
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDeadLetterInFlightAcked(t *testing.T) {
+
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Return(nil)
+
+	id1 := fftypes.NewUUID()
+	ed.inflight[*id1] = &core.Event{ID: id1, Sequence: 12345}
+	go ed.deliveryResponse(&core.EventDeliveryResponse{ID: id1, DeadLetter: &core.DeadLetter{}})
+
+	an := <-ed.acksNacks
+	assert.False(t, an.isNack)
+	assert.Equal(t, int64(12345), an.offset)
+}
+
+func TestDeadLetterRecordFailNacked(t *testing.T) {
+
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	rag := mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{a[1].(func(context.Context) error)(a[0].(context.Context))}
+	}
+	mdi.On("InsertDeadLetter", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	id1 := fftypes.NewUUID()
+	ed.inflight[*id1] = &core.Event{ID: id1}
+	go ed.deliveryResponse(&core.EventDeliveryResponse{ID: id1, DeadLetter: &core.DeadLetter{}})
+
+	an := <-ed.acksNacks
+	assert.True(t, an.isNack)
+}
+
 func TestGetEvents(t *testing.T) {
 	ag := newTestAggregator()
 	defer ag.cleanup(t)
@@ -19,6 +19,7 @@ package events
 import (
 	"context"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/txcommon"
@@ -56,18 +57,31 @@ func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*c
 			return nil, err
 		}
 		e.Transaction = tx
-	case core.EventTypeMessageConfirmed, core.EventTypeMessageRejected:
+	case core.EventTypeMessageConfirmed, core.EventTypeMessageRejected, core.EventTypeMessageExpired, core.EventTypeGroupMembershipChanged, core.EventTypeMessageRecalled, core.EventTypeTopicSequenceGap, core.EventTypeMessagePendingApp:
 		msg, _, _, err := em.data.GetMessageWithDataCached(ctx, event.Reference)
 		if err != nil {
 			return nil, err
 		}
 		e.Message = msg
+		// Include the message a reply is to, so applications can process threads without a further query
+		if msg != nil && msg.Header.ParentMessage != nil {
+			if e.ParentMessage, _, _, err = em.data.GetMessageWithDataCached(ctx, msg.Header.ParentMessage); err != nil {
+				return nil, err
+			}
+		}
 	case core.EventTypeBlockchainEventReceived:
 		be, err := em.txHelper.GetBlockchainEventByIDCached(ctx, event.Reference)
 		if err != nil {
 			return nil, err
 		}
 		e.BlockchainEvent = be
+	case core.EventTypeBlockchainEventInvalidated, core.EventTypeBlockchainEventReconfirmed:
+		// Read from the database, as the cached copy does not reflect the invalidation
+		be, err := em.database.GetBlockchainEventByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.BlockchainEvent = be
 	case core.EventTypeContractAPIConfirmed:
 		contractAPI, err := em.database.GetContractAPIByID(ctx, em.namespace, event.Reference)
 		if err != nil {
@@ -86,25 +100,62 @@ func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*c
 			return nil, err
 		}
 		e.Datatype = dt
-	case core.EventTypeIdentityConfirmed, core.EventTypeIdentityUpdated:
+	case core.EventTypeIdentityConfirmed, core.EventTypeIdentityUpdated, core.EventTypeSenderThrottled:
 		identity, err := em.database.GetIdentityByID(ctx, em.namespace, event.Reference)
 		if err != nil {
 			return nil, err
 		}
 		e.Identity = identity
-	case core.EventTypePoolConfirmed:
+	case core.EventTypePoolConfirmed, core.EventTypePoolPaused, core.EventTypePoolResumed, core.EventTypePoolRetired,
+		core.EventTypePoolMigrated, core.EventTypePoolMigrationFailed:
 		tokenPool, err := em.database.GetTokenPoolByID(ctx, em.namespace, event.Reference)
 		if err != nil {
 			return nil, err
 		}
 		e.TokenPool = tokenPool
-	case core.EventTypeApprovalConfirmed:
+	case core.EventTypeApprovalConfirmed, core.EventTypeApprovalExpired:
 		approval, err := em.database.GetTokenApprovalByID(ctx, em.namespace, event.Reference)
 		if err != nil {
 			return nil, err
 		}
 		e.TokenApproval = approval
-	case core.EventTypeTransferConfirmed:
+	case core.EventTypeSwapCompleted, core.EventTypeSwapRefunded, core.EventTypeSwapFailed:
+		swap, err := em.database.GetTokenSwapByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.TokenSwap = swap
+	case core.EventTypeDeadLetterCreated:
+		deadLetter, err := em.database.GetDeadLetterByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.DeadLetter = deadLetter
+	case core.EventTypeBatchQuarantined:
+		quarantined, err := em.database.GetQuarantinedBatchByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.QuarantinedBatch = quarantined
+	case core.EventTypeBlobQuarantined:
+		quarantined, err := em.database.GetQuarantinedBlobByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.QuarantinedBlob = quarantined
+	case core.EventTypeSLABreached:
+		breach, err := em.database.GetSLABreachByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.SLABreach = breach
+	case core.EventTypeReconciliationMismatch:
+		mismatch, err := em.database.GetTokenBalanceMismatchByID(ctx, em.namespace, event.Reference)
+		if err != nil {
+			return nil, err
+		}
+		e.TokenMismatch = mismatch
+	case core.EventTypeTransferConfirmed, core.EventTypeTransferInvalidated, core.EventTypeTransferReconfirmed:
 		transfer, err := em.database.GetTokenTransferByID(ctx, em.namespace, event.Reference)
 		if err != nil {
 			return nil, err
@@ -119,3 +170,63 @@ func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*c
 	}
 	return e, nil
 }
+
+// enrichReceipt adds the receipt of the blockchain transaction behind blockchain_event_received and transaction_submitted
+// events, built from the operations of the FireFly transaction and the details of the blockchain event
+func (em *eventEnricher) enrichReceipt(ctx context.Context, e *core.EnrichedEvent) error {
+	receipt := &core.TransactionReceipt{Status: core.OpStatusSucceeded}
+	var txID *fftypes.UUID
+	switch e.Type {
+	case core.EventTypeTransactionSubmitted:
+		txID = e.Reference
+	case core.EventTypeBlockchainEventReceived:
+		if e.BlockchainEvent == nil {
+			return nil
+		}
+		txID = e.BlockchainEvent.TX.ID
+		setReceiptInfo(receipt, e.BlockchainEvent.Info)
+	default:
+		return nil
+	}
+
+	if txID != nil {
+		fb := database.OperationQueryFactory.NewFilter(ctx)
+		ops, _, err := em.database.GetOperations(ctx, em.namespace, fb.And(fb.Eq("tx", txID)))
+		if err != nil {
+			return err
+		}
+		for _, op := range ops {
+			if op.Retry != nil {
+				// Superseded by the retry operation
+				continue
+			}
+			switch op.Status {
+			case core.OpStatusSucceeded:
+			case core.OpStatusFailed:
+				if receipt.Status != core.OpStatusFailed {
+					receipt.Status = core.OpStatusFailed
+					receipt.Error = op.Error
+				}
+			default:
+				if receipt.Status != core.OpStatusFailed {
+					receipt.Status = core.OpStatusPending
+				}
+			}
+			setReceiptInfo(receipt, op.Output)
+		}
+	}
+	e.Receipt = receipt
+	return nil
+}
+
+func setReceiptInfo(receipt *core.TransactionReceipt, info fftypes.JSONObject) {
+	if receipt.BlockNumber == "" {
+		receipt.BlockNumber = info.GetString("blockNumber")
+	}
+	if receipt.TransactionHash == "" {
+		receipt.TransactionHash = info.GetString("transactionHash")
+	}
+	if receipt.GasUsed == "" {
+		receipt.GasUsed = info.GetString("gasUsed")
+	}
+}
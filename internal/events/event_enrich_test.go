@@ -70,6 +70,58 @@ func TestEnrichMessageConfirmed(t *testing.T) {
 	assert.Equal(t, ref1, enriched.Message.Header.ID)
 }
 
+func TestEnrichMessageConfirmedWithParent(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	ref1 := fftypes.NewUUID()
+	parentID := fftypes.NewUUID()
+	mdm := em.data.(*datamocks.Manager)
+	mdm.On("GetMessageWithDataCached", mock.Anything, ref1).Return(&core.Message{
+		Header: core.MessageHeader{ID: ref1, ParentMessage: parentID},
+	}, nil, true, nil)
+	mdm.On("GetMessageWithDataCached", mock.Anything, parentID).Return(&core.Message{
+		Header: core.MessageHeader{ID: parentID},
+	}, nil, true, nil)
+
+	event := &core.Event{
+		ID:        fftypes.NewUUID(),
+		Type:      core.EventTypeMessageConfirmed,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.Message.Header.ID)
+	assert.Equal(t, parentID, enriched.ParentMessage.Header.ID)
+
+	mdm.AssertExpectations(t)
+}
+
+func TestEnrichMessageParentFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	ref1 := fftypes.NewUUID()
+	parentID := fftypes.NewUUID()
+	mdm := em.data.(*datamocks.Manager)
+	mdm.On("GetMessageWithDataCached", mock.Anything, ref1).Return(&core.Message{
+		Header: core.MessageHeader{ID: ref1, ParentMessage: parentID},
+	}, nil, true, nil)
+	mdm.On("GetMessageWithDataCached", mock.Anything, parentID).Return(nil, nil, false, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        fftypes.NewUUID(),
+		Type:      core.EventTypeMessageConfirmed,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+}
+
 func TestEnrichMessageFail(t *testing.T) {
 	em := newTestEventEnricher()
 	ctx := context.Background()
@@ -211,6 +263,54 @@ func TestEnrichBlockchainEventFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestEnrichBlockchainEventInvalidated(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetBlockchainEventByID", mock.Anything, "ns1", ref1).Return(&core.BlockchainEvent{
+		ID:          ref1,
+		Invalidated: true,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeBlockchainEventInvalidated,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.True(t, enriched.BlockchainEvent.Invalidated)
+}
+
+func TestEnrichBlockchainEventInvalidatedFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetBlockchainEventByID", mock.Anything, "ns1", ref1).Return(nil, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeBlockchainEventInvalidated,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestEnrichContractAPISubmitted(t *testing.T) {
 	em := newTestEventEnricher()
 	ctx := context.Background()
@@ -473,6 +573,31 @@ func TestEnrichTokenApprovalConfirmed(t *testing.T) {
 	assert.Equal(t, ref1, enriched.TokenApproval.LocalID)
 }
 
+func TestEnrichTokenApprovalExpired(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenApprovalByID", mock.Anything, "ns1", ref1).Return(&core.TokenApproval{
+		LocalID: ref1,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeApprovalExpired,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.TokenApproval.LocalID)
+}
+
 func TestEnrichTokenApprovalFailed(t *testing.T) {
 	em := newTestEventEnricher()
 	ctx := context.Background()
@@ -520,6 +645,288 @@ func TestEnrichTokenApprovalConfirmedFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestEnrichTokenSwapCompleted(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", mock.Anything, "ns1", ref1).Return(&core.TokenSwap{
+		ID: ref1,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeSwapCompleted,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.TokenSwap.ID)
+}
+
+func TestEnrichTokenSwapFailedFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenSwapByID", mock.Anything, "ns1", ref1).Return(nil, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeSwapFailed,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEnrichDeadLetterCreated(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetDeadLetterByID", mock.Anything, "ns1", ref1).Return(&core.DeadLetter{
+		ID: ref1,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeDeadLetterCreated,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.DeadLetter.ID)
+}
+
+func TestEnrichDeadLetterCreatedFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetDeadLetterByID", mock.Anything, "ns1", ref1).Return(nil, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeDeadLetterCreated,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEnrichBatchQuarantined(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetQuarantinedBatchByID", mock.Anything, "ns1", ref1).Return(&core.QuarantinedBatch{
+		ID: ref1,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeBatchQuarantined,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.QuarantinedBatch.ID)
+}
+
+func TestEnrichBatchQuarantinedFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetQuarantinedBatchByID", mock.Anything, "ns1", ref1).Return(nil, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeBatchQuarantined,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEnrichBlobQuarantined(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetQuarantinedBlobByID", mock.Anything, "ns1", ref1).Return(&core.QuarantinedBlob{
+		ID: ref1,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeBlobQuarantined,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.QuarantinedBlob.ID)
+}
+
+func TestEnrichBlobQuarantinedFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetQuarantinedBlobByID", mock.Anything, "ns1", ref1).Return(nil, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeBlobQuarantined,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEnrichSLABreached(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetSLABreachByID", mock.Anything, "ns1", ref1).Return(&core.SLABreach{
+		ID: ref1,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeSLABreached,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.SLABreach.ID)
+}
+
+func TestEnrichSLABreachedFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetSLABreachByID", mock.Anything, "ns1", ref1).Return(nil, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeSLABreached,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestEnrichReconciliationMismatch(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenBalanceMismatchByID", mock.Anything, "ns1", ref1).Return(&core.TokenBalanceMismatch{
+		ID: ref1,
+	}, nil)
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeReconciliationMismatch,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, ref1, enriched.TokenMismatch.ID)
+}
+
+func TestEnrichReconciliationMismatchFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	// Setup the IDs
+	ref1 := fftypes.NewUUID()
+	ev1 := fftypes.NewUUID()
+
+	// Setup enrichment
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetTokenBalanceMismatchByID", mock.Anything, "ns1", ref1).Return(nil, fmt.Errorf("pop"))
+
+	event := &core.Event{
+		ID:        ev1,
+		Type:      core.EventTypeReconciliationMismatch,
+		Reference: ref1,
+	}
+
+	_, err := em.enrichEvent(ctx, event)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestEnrichTokenTransferConfirmed(t *testing.T) {
 	em := newTestEventEnricher()
 	ctx := context.Background()
@@ -613,3 +1020,140 @@ func TestEnrichOperationFail(t *testing.T) {
 	_, err := em.enrichEvent(ctx, event)
 	assert.EqualError(t, err, "pop")
 }
+
+func TestEnrichReceiptTransactionSubmitted(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	txID := fftypes.NewUUID()
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{Status: core.OpStatusFailed, Error: "retried", Retry: fftypes.NewUUID()},
+		{Status: core.OpStatusSucceeded, Output: fftypes.JSONObject{
+			"transactionHash": "0x12345",
+			"blockNumber":     float64(100),
+			"gasUsed":         "21000",
+		}},
+	}, nil, nil)
+
+	e := &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeTransactionSubmitted, Reference: txID}}
+	err := em.enrichReceipt(ctx, e)
+	assert.NoError(t, err)
+	assert.Equal(t, &core.TransactionReceipt{
+		Status:          core.OpStatusSucceeded,
+		BlockNumber:     "100",
+		TransactionHash: "0x12345",
+		GasUsed:         "21000",
+	}, e.Receipt)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEnrichReceiptTransactionFailed(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{Status: core.OpStatusPending},
+		{Status: core.OpStatusFailed, Error: "pop"},
+		{Status: core.OpStatusFailed, Error: "bang"},
+		{Status: core.OpStatusInitialized},
+	}, nil, nil)
+
+	e := &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeTransactionSubmitted, Reference: fftypes.NewUUID()}}
+	err := em.enrichReceipt(ctx, e)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusFailed, e.Receipt.Status)
+	assert.Equal(t, "pop", e.Receipt.Error)
+}
+
+func TestEnrichReceiptTransactionPending(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{Status: core.OpStatusInitialized},
+	}, nil, nil)
+
+	e := &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeTransactionSubmitted, Reference: fftypes.NewUUID()}}
+	err := em.enrichReceipt(ctx, e)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusPending, e.Receipt.Status)
+}
+
+func TestEnrichReceiptBlockchainEvent(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{Status: core.OpStatusSucceeded, Output: fftypes.JSONObject{
+			"transactionHash": "0xfromop",
+			"gasUsed":         "21000",
+		}},
+	}, nil, nil)
+
+	e := &core.EnrichedEvent{
+		Event: core.Event{Type: core.EventTypeBlockchainEventReceived},
+		BlockchainEvent: &core.BlockchainEvent{
+			Info: fftypes.JSONObject{
+				"blockNumber":     "100",
+				"transactionHash": "0x12345",
+			},
+			TX: core.BlockchainTransactionRef{ID: fftypes.NewUUID()},
+		},
+	}
+	err := em.enrichReceipt(ctx, e)
+	assert.NoError(t, err)
+	assert.Equal(t, &core.TransactionReceipt{
+		Status:          core.OpStatusSucceeded,
+		BlockNumber:     "100",
+		TransactionHash: "0x12345",
+		GasUsed:         "21000",
+	}, e.Receipt)
+}
+
+func TestEnrichReceiptBlockchainEventNoTransaction(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	e := &core.EnrichedEvent{
+		Event: core.Event{Type: core.EventTypeBlockchainEventReceived},
+		BlockchainEvent: &core.BlockchainEvent{
+			Info: fftypes.JSONObject{"transactionHash": "0x12345"},
+		},
+	}
+	err := em.enrichReceipt(ctx, e)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpStatusSucceeded, e.Receipt.Status)
+	assert.Equal(t, "0x12345", e.Receipt.TransactionHash)
+}
+
+func TestEnrichReceiptNotApplicable(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	e := &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeMessageConfirmed}}
+	err := em.enrichReceipt(ctx, e)
+	assert.NoError(t, err)
+	assert.Nil(t, e.Receipt)
+
+	e = &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeBlockchainEventReceived}}
+	err = em.enrichReceipt(ctx, e)
+	assert.NoError(t, err)
+	assert.Nil(t, e.Receipt)
+}
+
+func TestEnrichReceiptFail(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	e := &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeTransactionSubmitted, Reference: fftypes.NewUUID()}}
+	err := em.enrichReceipt(ctx, e)
+	assert.EqualError(t, err, "pop")
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/definitions"
 	"github.com/hyperledger/firefly/internal/events/eifactory"
+	"github.com/hyperledger/firefly/internal/events/pull"
 	"github.com/hyperledger/firefly/internal/events/system"
 	"github.com/hyperledger/firefly/internal/identity"
 	"github.com/hyperledger/firefly/internal/metrics"
@@ -42,6 +43,7 @@ import (
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/shareddownload"
+	"github.com/hyperledger/firefly/internal/transform"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -60,6 +62,20 @@ type EventManager interface {
 	DeletedSubscriptions() chan<- *fftypes.UUID
 	DeleteDurableSubscription(ctx context.Context, subDef *core.Subscription) (err error)
 	CreateUpdateDurableSubscription(ctx context.Context, subDef *core.Subscription, mustNew bool) (err error)
+	RewindDurableSubscription(ctx context.Context, subDef *core.Subscription, rewind *core.SubscriptionRewind) (*core.SubscriptionRewind, error)
+	PullSubscriptionEvents(ctx context.Context, subDef *core.Subscription, req *core.SubscriptionPull) ([]*core.PulledEvent, error)
+	AckSubscriptionEvents(ctx context.Context, subDef *core.Subscription, ack *core.SubscriptionAck) error
+	CreateSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) error
+	DeleteSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) error
+	RequeueDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (*core.DeadLetter, error)
+	DiscardDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (*core.DeadLetter, error)
+	ReleaseQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) (*core.QuarantinedBatch, error)
+	DiscardQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) (*core.QuarantinedBatch, error)
+	ReleaseQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) (*core.QuarantinedBlob, error)
+	DiscardQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) (*core.QuarantinedBlob, error)
+	AckMessage(ctx context.Context, msg *core.Message, outcome core.MessageAckOutcome, input *core.MessageAckInput) (*core.MessageAck, error)
+	CreateEventRule(ctx context.Context, rule *core.EventRule) error
+	DeleteEventRule(ctx context.Context, rule *core.EventRule) error
 	EnrichEvent(ctx context.Context, event *core.Event) (*core.EnrichedEvent, error)
 	QueueBatchRewind(batchID *fftypes.UUID)
 	Start() error
@@ -79,6 +95,7 @@ type EventManager interface {
 	TokenPoolCreated(ctx context.Context /* allows security context to be propagated when called in-line with the send TX */, ti tokens.Plugin, pool *tokens.TokenPool) error
 	TokensTransferred(ti tokens.Plugin, transfer *tokens.TokenTransfer) error
 	TokensApproved(ti tokens.Plugin, approval *tokens.TokenApproval) error
+	TokenEventRemoved(ti tokens.Plugin, event *blockchain.Event) error
 
 	GetPlugins() []*core.NamespaceStatusPlugin
 
@@ -111,6 +128,10 @@ type eventManager struct {
 	metrics            metrics.Manager
 	chainListenerCache cache.CInterface
 	multiparty         multiparty.Manager // optional
+	templates          *subscriptionTemplates
+	inbound            *inboundLimits
+	sla                *messageSLA
+	transforms         *transform.Pipeline
 }
 
 func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin, bi blockchain.Plugin, im identity.Manager, dh definitions.Handler, dm data.Manager, ds definitions.Sender, bm broadcast.Manager, pm privatemessaging.Manager, am assets.Manager, sd shareddownload.Manager, mm metrics.Manager, om operations.Manager, txHelper txcommon.Helper, transports map[string]events.Plugin, mp multiparty.Manager, cacheManager cache.Manager) (EventManager, error) {
@@ -156,11 +177,21 @@ func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin
 		newPinNotifier:     newPinNotifier,
 		metrics:            mm,
 		chainListenerCache: eventListenerCache,
+		templates: &subscriptionTemplates{
+			topics: make(map[string]bool),
+		},
+	}
+	em.sla = newMessageSLA(ns, di, im, mm)
+	if em.transforms, err = transform.NewPipeline(ctx, ns.Transforms); err != nil {
+		return nil, err
+	}
+	if em.inbound, err = newInboundLimits(ctx); err != nil {
+		return nil, err
 	}
 	ie, _ := eifactory.GetPlugin(ctx, system.SystemEventsTransport)
 	em.internalEvents = ie.(*system.Events)
 	if bi != nil {
-		aggregator, err := newAggregator(ctx, ns.Name, di, bi, pm, dh, im, dm, newPinNotifier, mm, cacheManager)
+		aggregator, err := newAggregator(ctx, ns, di, bi, pm, dh, im, dm, newPinNotifier, mm, cacheManager)
 		if err != nil {
 			return nil, err
 		}
@@ -179,6 +210,9 @@ func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin
 
 func (em *eventManager) Start() (err error) {
 	err = em.subManager.start()
+	if err == nil {
+		err = em.AddSystemEventListener(em.namespace.Name, em.subscriptionTemplateListener)
+	}
 	if err == nil {
 		if em.aggregator != nil {
 			em.aggregator.start()
@@ -271,6 +305,73 @@ func (em *eventManager) DeleteDurableSubscription(ctx context.Context, subDef *c
 	return em.database.DeleteSubscriptionByID(ctx, em.namespace.Name, subDef.ID)
 }
 
+func (em *eventManager) RewindDurableSubscription(ctx context.Context, subDef *core.Subscription, rewind *core.SubscriptionRewind) (*core.SubscriptionRewind, error) {
+	offset, err := calcRewindOffset(ctx, em.namespace.Name, em.database, rewind)
+	if err != nil {
+		return nil, err
+	}
+
+	// A subscription that has not started yet will start from its locked in first event
+	current, err := em.database.GetOffset(ctx, core.OffsetTypeSubscription, subDef.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		rewind.PreviousOffset = current.Current
+	} else if rewind.PreviousOffset, err = calcFirstOffset(ctx, em.namespace.Name, em.database, subDef.Options.FirstEvent); err != nil {
+		return nil, err
+	}
+	if offset > rewind.PreviousOffset {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionRewindForward, offset, rewind.PreviousOffset)
+	}
+	rewind.Offset = offset
+
+	fb := database.EventQueryFactory.NewFilter(ctx)
+	_, res, err := em.database.GetEvents(ctx, em.namespace.Name, fb.And(
+		fb.Gt("sequence", rewind.Offset),
+		fb.Lte("sequence", rewind.PreviousOffset),
+	).Count(true).Limit(1))
+	if err != nil {
+		return nil, err
+	}
+	if res != nil && res.TotalCount != nil {
+		rewind.Events = *res.TotalCount
+	}
+
+	if rewind.DryRun {
+		return rewind, nil
+	}
+	log.L(ctx).Infof("Rewinding subscription %s:%s [%s] from offset %d to %d", subDef.Namespace, subDef.Name, subDef.ID, rewind.PreviousOffset, rewind.Offset)
+	return rewind, em.subManager.rewindDurableSubscription(ctx, subDef.ID, rewind.Offset)
+}
+
+func (em *eventManager) pullTransport(ctx context.Context, subDef *core.Subscription) (*pull.Pull, error) {
+	if subDef.Transport != pull.PullTransport {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionNotPull, subDef.Name)
+	}
+	transport, ok := em.subManager.transports[pull.PullTransport]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnknownEventTransportPlugin, pull.PullTransport)
+	}
+	return transport.(*pull.Pull), nil
+}
+
+func (em *eventManager) PullSubscriptionEvents(ctx context.Context, subDef *core.Subscription, req *core.SubscriptionPull) ([]*core.PulledEvent, error) {
+	transport, err := em.pullTransport(ctx, subDef)
+	if err != nil {
+		return nil, err
+	}
+	return transport.Pull(ctx, subDef, req)
+}
+
+func (em *eventManager) AckSubscriptionEvents(ctx context.Context, subDef *core.Subscription, ack *core.SubscriptionAck) error {
+	transport, err := em.pullTransport(ctx, subDef)
+	if err != nil {
+		return err
+	}
+	return transport.Ack(ctx, subDef, ack)
+}
+
 func (em *eventManager) AddSystemEventListener(ns string, el system.EventListener) error {
 	return em.internalEvents.AddListener(ns, el)
 }
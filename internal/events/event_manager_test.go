@@ -24,9 +24,11 @@ import (
 	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/events/pull"
 	"github.com/hyperledger/firefly/internal/events/system"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/mocks/assetmocks"
@@ -145,6 +147,7 @@ func newTestEventManagerCommon(t *testing.T, metrics, dbconcurrency bool) *testE
 	met.On("Name").Return("ut").Maybe()
 	mbi.On("VerifierType").Return(core.VerifierTypeEthAddress).Maybe()
 	mdi.On("Capabilities").Return(&database.Capabilities{Concurrency: dbconcurrency}).Maybe()
+	mdm.On("ScanBlob", mock.Anything, mock.Anything).Return("", nil).Maybe()
 	mev.On("SetHandler", "ns1", mock.Anything).Return(nil).Maybe()
 	mev.On("ValidateOptions", mock.Anything).Return(nil).Maybe()
 	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
@@ -346,6 +349,60 @@ func TestStartStopEventListenerFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestNewEventManagerBadInboundAction(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingInboundAction, "drop")
+	defer coreconfig.Reset()
+	mdi := &databasemocks.Plugin{}
+	mbi := &blockchainmocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	mdm := &datamocks.Manager{}
+	msh := &definitionsmocks.Handler{}
+	mds := &definitionsmocks.Sender{}
+	mbm := &broadcastmocks.Manager{}
+	mpm := &privatemessagingmocks.Manager{}
+	mam := &assetmocks.Manager{}
+	msd := &shareddownloadmocks.Manager{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+	mev := &eventsmocks.Plugin{}
+	events := map[string]events.Plugin{"websockets": mev}
+	mmp := &multipartymocks.Manager{}
+	ctx := context.Background()
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
+	assert.Regexp(t, "FF10637", err)
+}
+
+func TestNewEventManagerBadTransform(t *testing.T) {
+	coreconfig.Reset()
+	mdi := &databasemocks.Plugin{}
+	mbi := &blockchainmocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	mdm := &datamocks.Manager{}
+	msh := &definitionsmocks.Handler{}
+	mds := &definitionsmocks.Sender{}
+	mbm := &broadcastmocks.Manager{}
+	mpm := &privatemessagingmocks.Manager{}
+	mam := &assetmocks.Manager{}
+	msd := &shareddownloadmocks.Manager{}
+	mm := &metricsmocks.Manager{}
+	mom := &operationmocks.Manager{}
+	mev := &eventsmocks.Plugin{}
+	events := map[string]events.Plugin{"websockets": mev}
+	mmp := &multipartymocks.Manager{}
+	ctx := context.Background()
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
+	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1", Transforms: []*core.TransformStep{{Plugin: "wrong"}}}
+	_, err := NewEventManager(context.Background(), ns, mdi, mbi, mim, msh, mdm, mds, mbm, mpm, mam, msd, mm, mom, txHelper, events, mmp, cmi)
+	assert.Regexp(t, "FF10665", err)
+}
+
 func TestEmitSubscriptionEventsNoops(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -571,6 +628,127 @@ func TestCreateDeleteDurableSubscriptionOk(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func newTestRewindSubscription() *core.Subscription {
+	firstEvent := core.SubOptsFirstEvent("5")
+	return &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{FirstEvent: &firstEvent},
+		},
+	}
+}
+
+func TestRewindDurableSubscriptionBadTarget(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	seq := int64(10)
+	_, err := em.RewindDurableSubscription(em.ctx, newTestRewindSubscription(), &core.SubscriptionRewind{})
+	assert.Regexp(t, "FF10554", err)
+	_, err = em.RewindDurableSubscription(em.ctx, newTestRewindSubscription(), &core.SubscriptionRewind{Sequence: &seq, Timestamp: fftypes.Now()})
+	assert.Regexp(t, "FF10554", err)
+	seq = -1
+	_, err = em.RewindDurableSubscription(em.ctx, newTestRewindSubscription(), &core.SubscriptionRewind{Sequence: &seq})
+	assert.Regexp(t, "FF10192", err)
+}
+
+func TestRewindDurableSubscriptionTimestampFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := em.RewindDurableSubscription(em.ctx, newTestRewindSubscription(), &core.SubscriptionRewind{Timestamp: fftypes.Now()})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRewindDurableSubscriptionGetOffsetFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	seq := int64(10)
+	sub := newTestRewindSubscription()
+	em.mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(nil, fmt.Errorf("pop"))
+	_, err := em.RewindDurableSubscription(em.ctx, sub, &core.SubscriptionRewind{Sequence: &seq})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRewindDurableSubscriptionNotStartedBadFirstEvent(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	seq := int64(1)
+	sub := newTestRewindSubscription()
+	badFirstEvent := core.SubOptsFirstEvent("!one")
+	sub.Options.FirstEvent = &badFirstEvent
+	em.mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(nil, nil)
+	_, err := em.RewindDurableSubscription(em.ctx, sub, &core.SubscriptionRewind{Sequence: &seq})
+	assert.Regexp(t, "FF10191", err)
+}
+
+func TestRewindDurableSubscriptionForward(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	seq := int64(10)
+	sub := newTestRewindSubscription()
+	em.mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(nil, nil)
+	_, err := em.RewindDurableSubscription(em.ctx, sub, &core.SubscriptionRewind{Sequence: &seq})
+	assert.Regexp(t, "FF10555.*9.*5", err)
+}
+
+func TestRewindDurableSubscriptionCountFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	seq := int64(10)
+	sub := newTestRewindSubscription()
+	em.mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(&core.Offset{Current: 20}, nil)
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	_, err := em.RewindDurableSubscription(em.ctx, sub, &core.SubscriptionRewind{Sequence: &seq})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRewindDurableSubscriptionTimestampDryRun(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	sub := newTestRewindSubscription()
+	count := int64(11)
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Sequence: 10}}, nil, nil).Once()
+	em.mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(&core.Offset{Current: 20}, nil)
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, &ffapi.FilterResult{TotalCount: &count}, nil).Once()
+	rewind, err := em.RewindDurableSubscription(em.ctx, sub, &core.SubscriptionRewind{Timestamp: fftypes.Now(), DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), rewind.PreviousOffset)
+	assert.Equal(t, int64(9), rewind.Offset)
+	assert.Equal(t, int64(11), rewind.Events)
+}
+
+func TestRewindDurableSubscriptionTimestampNoEvents(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	sub := newTestRewindSubscription()
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil).Once()
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{{Sequence: 20}}, nil, nil).Once()
+	em.mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(&core.Offset{Current: 20}, nil)
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil).Once()
+	rewind, err := em.RewindDurableSubscription(em.ctx, sub, &core.SubscriptionRewind{Timestamp: fftypes.Now(), DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), rewind.Offset)
+	assert.Equal(t, int64(0), rewind.Events)
+}
+
+func TestRewindDurableSubscriptionOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	seq := int64(3)
+	sub := newTestRewindSubscription()
+	count := int64(3)
+	em.mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub.ID.String()).Return(nil, nil)
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, &ffapi.FilterResult{TotalCount: &count}, nil)
+	em.mdi.On("UpsertOffset", mock.Anything, mock.MatchedBy(func(o *core.Offset) bool {
+		return o.Type == core.OffsetTypeSubscription && o.Name == sub.ID.String() && o.Current == 2
+	}), true).Return(nil)
+	rewind, err := em.RewindDurableSubscription(em.ctx, sub, &core.SubscriptionRewind{Sequence: &seq})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), rewind.PreviousOffset)
+	assert.Equal(t, int64(2), rewind.Offset)
+	assert.Equal(t, int64(3), rewind.Events)
+}
+
 func TestAddInternalListener(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -603,3 +781,32 @@ func TestGetPlugins(t *testing.T) {
 
 	assert.ElementsMatch(t, em.GetPlugins(), expectedPlugins)
 }
+
+func TestPullSubscriptionEvents(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		Transport:       "websockets",
+	}
+	_, err := em.PullSubscriptionEvents(em.ctx, sub, &core.SubscriptionPull{})
+	assert.Regexp(t, "FF10578", err)
+	err = em.AckSubscriptionEvents(em.ctx, sub, &core.SubscriptionAck{})
+	assert.Regexp(t, "FF10578", err)
+
+	sub.Transport = "pull"
+	_, err = em.PullSubscriptionEvents(em.ctx, sub, &core.SubscriptionPull{})
+	assert.Regexp(t, "FF10172", err)
+
+	p := &pull.Pull{}
+	conf := config.RootSection("ut.pull")
+	p.InitConfig(conf)
+	p.Init(em.ctx, conf)
+	em.subManager.transports["pull"] = p
+	pulled, err := em.PullSubscriptionEvents(em.ctx, sub, &core.SubscriptionPull{})
+	assert.NoError(t, err)
+	assert.Empty(t, pulled)
+	err = em.AckSubscriptionEvents(em.ctx, sub, &core.SubscriptionAck{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,216 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"regexp"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/events/system"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// eventRules is the set of event rules of the namespace, shared by all its dispatchers. The rules are loaded
+// from the database when first needed, and again after any change to them through this node.
+type eventRules struct {
+	mux    sync.Mutex
+	loaded bool
+	rules  []*parsedEventRule
+}
+
+type parsedEventRule struct {
+	definition       *core.EventRule
+	eventMatcher     *regexp.Regexp
+	topicFilter      *regexp.Regexp
+	expressionFilter *expressionFilter
+	subscriptions    map[string]bool
+	transports       map[string]bool
+}
+
+// eventRuleOutcome is the combined effect of the event rules on the delivery of an event to a subscription
+type eventRuleOutcome struct {
+	route    bool
+	suppress bool
+	labels   fftypes.JSONObject
+}
+
+func parseEventRule(ctx context.Context, r *core.EventRule) (pr *parsedEventRule, err error) {
+	switch r.Action {
+	case core.EventRuleActionRoute:
+		if len(r.Targets.Subscriptions) == 0 && len(r.Targets.Transports) == 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgEventRuleInvalid, r.Name, "a route rule must target at least one subscription or transport")
+		}
+	case core.EventRuleActionLabel:
+		if len(r.Labels) == 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgEventRuleInvalid, r.Name, "a label rule must set at least one label")
+		}
+	}
+
+	pr = &parsedEventRule{
+		definition:    r,
+		subscriptions: make(map[string]bool),
+		transports:    make(map[string]bool),
+	}
+	if r.Match.Events != "" {
+		if pr.eventMatcher, err = regexp.Compile(r.Match.Events); err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgRegexpCompileFailed, "match.events", r.Match.Events)
+		}
+	}
+	if r.Match.Topic != "" {
+		if pr.topicFilter, err = regexp.Compile(r.Match.Topic); err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgRegexpCompileFailed, "match.topic", r.Match.Topic)
+		}
+	}
+	if r.Match.Expression != "" {
+		if pr.expressionFilter, err = newExpressionFilter(ctx, "match.expression", r.Match.Expression); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range r.Targets.Subscriptions {
+		pr.subscriptions[name] = true
+	}
+	for _, transport := range r.Targets.Transports {
+		pr.transports[transport] = true
+	}
+	return pr, nil
+}
+
+func (pr *parsedEventRule) targets(sub *core.Subscription) bool {
+	if len(pr.subscriptions) == 0 && len(pr.transports) == 0 {
+		return true
+	}
+	return pr.subscriptions[sub.Name] || pr.transports[sub.Transport]
+}
+
+func (pr *parsedEventRule) matches(ctx context.Context, event *core.EnrichedEvent) bool {
+	switch {
+	case pr.eventMatcher != nil && !pr.eventMatcher.MatchString(string(event.Type)):
+		return false
+	case pr.topicFilter != nil && !pr.topicFilter.MatchString(event.Topic):
+		return false
+	case pr.expressionFilter != nil && !pr.expressionFilter.matches(ctx, event):
+		return false
+	}
+	return true
+}
+
+func (er *eventRules) reset() {
+	er.mux.Lock()
+	defer er.mux.Unlock()
+	er.loaded = false
+	er.rules = nil
+}
+
+// load reads the rules from the database, if they are not already loaded
+func (er *eventRules) load(ctx context.Context, ns string, di database.Plugin) error {
+	er.mux.Lock()
+	defer er.mux.Unlock()
+	if er.loaded {
+		return nil
+	}
+	rules, _, err := di.GetEventRules(ctx, ns, database.EventRuleQueryFactory.NewFilter(ctx).And())
+	if err != nil {
+		return err
+	}
+	er.rules = make([]*parsedEventRule, 0, len(rules))
+	for _, r := range rules {
+		pr, err := parseEventRule(ctx, r)
+		if err != nil {
+			log.L(ctx).Errorf("Skipping event rule '%s': %s", r.Name, err)
+			continue
+		}
+		er.rules = append(er.rules, pr)
+	}
+	er.loaded = true
+	return nil
+}
+
+// forSubscription returns the loaded rules that target a subscription. Rules never apply to the internal
+// listeners on the system transport.
+func (er *eventRules) forSubscription(sub *core.Subscription) []*parsedEventRule {
+	if sub.Transport == system.SystemEventsTransport {
+		return nil
+	}
+	er.mux.Lock()
+	defer er.mux.Unlock()
+	var rules []*parsedEventRule
+	for _, pr := range er.rules {
+		if pr.targets(sub) {
+			rules = append(rules, pr)
+		}
+	}
+	return rules
+}
+
+// applyEventRules evaluates the rules in the order they were created. Suppression takes precedence over routing,
+// and where rules set the same label the last one wins.
+func applyEventRules(ctx context.Context, rules []*parsedEventRule, event *core.EnrichedEvent) (outcome eventRuleOutcome) {
+	for _, pr := range rules {
+		if !pr.matches(ctx, event) {
+			continue
+		}
+		switch pr.definition.Action {
+		case core.EventRuleActionSuppress:
+			return eventRuleOutcome{suppress: true}
+		case core.EventRuleActionRoute:
+			outcome.route = true
+		}
+		for k, v := range pr.definition.Labels {
+			if outcome.labels == nil {
+				outcome.labels = fftypes.JSONObject{}
+			}
+			outcome.labels[k] = v
+		}
+	}
+	return outcome
+}
+
+func (em *eventManager) CreateEventRule(ctx context.Context, rule *core.EventRule) (err error) {
+	if rule.Action, err = fftypes.FFEnumParseString(ctx, "eventruleaction", rule.Action.String()); err != nil {
+		return err
+	}
+	if _, err = parseEventRule(ctx, rule); err != nil {
+		return err
+	}
+
+	existing, err := em.database.GetEventRuleByName(ctx, rule.Namespace, rule.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return i18n.NewError(ctx, coremsgs.MsgAlreadyExists, "event rule", rule.Namespace, rule.Name)
+	}
+	if err = em.database.InsertEventRule(ctx, rule); err != nil {
+		return err
+	}
+	em.subManager.rules.reset()
+	return nil
+}
+
+func (em *eventManager) DeleteEventRule(ctx context.Context, rule *core.EventRule) error {
+	if err := em.database.DeleteEventRuleByID(ctx, em.namespace.Name, rule.ID); err != nil {
+		return err
+	}
+	em.subManager.rules.reset()
+	return nil
+}
@@ -0,0 +1,305 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/events/system"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestEventRule(action core.EventRuleAction) *core.EventRule {
+	return &core.EventRule{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Name:      "rule1",
+		Action:    action,
+	}
+}
+
+func parseTestEventRules(t *testing.T, defs ...*core.EventRule) *eventRules {
+	er := &eventRules{loaded: true}
+	for _, r := range defs {
+		pr, err := parseEventRule(context.Background(), r)
+		assert.NoError(t, err)
+		er.rules = append(er.rules, pr)
+	}
+	return er
+}
+
+func TestParseEventRuleErrors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := parseEventRule(ctx, newTestEventRule(core.EventRuleActionRoute))
+	assert.Regexp(t, "FF10584.*rule1", err)
+
+	_, err = parseEventRule(ctx, newTestEventRule(core.EventRuleActionLabel))
+	assert.Regexp(t, "FF10584.*rule1", err)
+
+	r := newTestEventRule(core.EventRuleActionSuppress)
+	r.Match.Events = "["
+	_, err = parseEventRule(ctx, r)
+	assert.Regexp(t, "FF10171.*match.events", err)
+
+	r = newTestEventRule(core.EventRuleActionSuppress)
+	r.Match.Topic = "["
+	_, err = parseEventRule(ctx, r)
+	assert.Regexp(t, "FF10171.*match.topic", err)
+
+	r = newTestEventRule(core.EventRuleActionSuppress)
+	r.Match.Expression = "event.("
+	_, err = parseEventRule(ctx, r)
+	assert.Regexp(t, "match.expression", err)
+}
+
+func TestParseEventRuleMatches(t *testing.T) {
+	ctx := context.Background()
+
+	r := newTestEventRule(core.EventRuleActionSuppress)
+	r.Match = core.EventRuleMatch{
+		Events:     "^token_transfer_confirmed$",
+		Topic:      "^topic1$",
+		Expression: "int(event.tokenTransfer.amount) > 1000",
+	}
+	pr, err := parseEventRule(ctx, r)
+	assert.NoError(t, err)
+
+	event := newTestTransferEvent("0x01", 1001)
+	event.Topic = "topic1"
+	assert.True(t, pr.matches(ctx, event))
+
+	event.Topic = "topic2"
+	assert.False(t, pr.matches(ctx, event))
+
+	event = newTestTransferEvent("0x01", 5)
+	event.Topic = "topic1"
+	assert.False(t, pr.matches(ctx, event))
+
+	event.Type = core.EventTypeMessageConfirmed
+	assert.False(t, pr.matches(ctx, event))
+}
+
+func TestEventRulesForSubscription(t *testing.T) {
+	all := newTestEventRule(core.EventRuleActionSuppress)
+	bySub := newTestEventRule(core.EventRuleActionRoute)
+	bySub.Targets.Subscriptions = []string{"sub1"}
+	byTransport := newTestEventRule(core.EventRuleActionRoute)
+	byTransport.Targets.Transports = []string{"webhooks"}
+	er := parseTestEventRules(t, all, bySub, byTransport)
+
+	rules := er.forSubscription(&core.Subscription{SubscriptionRef: core.SubscriptionRef{Name: "sub1"}, Transport: "websockets"})
+	assert.Len(t, rules, 2)
+	assert.Equal(t, bySub, rules[1].definition)
+
+	rules = er.forSubscription(&core.Subscription{SubscriptionRef: core.SubscriptionRef{Name: "sub2"}, Transport: "webhooks"})
+	assert.Len(t, rules, 2)
+	assert.Equal(t, byTransport, rules[1].definition)
+
+	rules = er.forSubscription(&core.Subscription{SubscriptionRef: core.SubscriptionRef{Name: "sub1"}, Transport: system.SystemEventsTransport})
+	assert.Empty(t, rules)
+}
+
+func TestEventRulesLoad(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	defer mdi.AssertExpectations(t)
+	ctx := context.Background()
+
+	good := newTestEventRule(core.EventRuleActionSuppress)
+	bad := newTestEventRule(core.EventRuleActionRoute)
+	mdi.On("GetEventRules", ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Once()
+	mdi.On("GetEventRules", ctx, "ns1", mock.Anything).Return([]*core.EventRule{good, bad}, nil, nil).Once()
+
+	er := &eventRules{}
+	err := er.load(ctx, "ns1", mdi)
+	assert.EqualError(t, err, "pop")
+	assert.False(t, er.loaded)
+
+	err = er.load(ctx, "ns1", mdi)
+	assert.NoError(t, err)
+	assert.True(t, er.loaded)
+	assert.Len(t, er.rules, 1)
+	assert.Equal(t, good, er.rules[0].definition)
+
+	// Cached until reset
+	err = er.load(ctx, "ns1", mdi)
+	assert.NoError(t, err)
+
+	er.reset()
+	assert.False(t, er.loaded)
+	assert.Nil(t, er.rules)
+}
+
+func TestApplyEventRules(t *testing.T) {
+	ctx := context.Background()
+
+	route := newTestEventRule(core.EventRuleActionRoute)
+	route.Match.Topic = "^orders$"
+	route.Targets.Transports = []string{"webhooks"}
+	route.Labels = fftypes.JSONObject{"team": "orders", "priority": "low"}
+	label := newTestEventRule(core.EventRuleActionLabel)
+	label.Match.Events = "^message_confirmed$"
+	label.Labels = fftypes.JSONObject{"priority": "high"}
+	suppress := newTestEventRule(core.EventRuleActionSuppress)
+	suppress.Match.Topic = "^noise$"
+	er := parseTestEventRules(t, route, label, suppress)
+
+	outcome := applyEventRules(ctx, er.rules, &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeMessageConfirmed, Topic: "orders"}})
+	assert.True(t, outcome.route)
+	assert.False(t, outcome.suppress)
+	assert.Equal(t, fftypes.JSONObject{"team": "orders", "priority": "high"}, outcome.labels)
+
+	outcome = applyEventRules(ctx, er.rules, &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeMessageConfirmed, Topic: "noise"}})
+	assert.True(t, outcome.suppress)
+	assert.Nil(t, outcome.labels)
+
+	outcome = applyEventRules(ctx, er.rules, &core.EnrichedEvent{Event: core.Event{Type: core.EventTypeBlockchainEventReceived, Topic: "other"}})
+	assert.Equal(t, eventRuleOutcome{}, outcome)
+}
+
+func TestFilterEventsRules(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{Name: "sub1"},
+			Transport:       "webhooks",
+		},
+		topicFilter: regexp.MustCompile("^topic1$"),
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	route := newTestEventRule(core.EventRuleActionRoute)
+	route.Match.Topic = "^orders$"
+	route.Targets.Subscriptions = []string{"sub1"}
+	route.Labels = fftypes.JSONObject{"team": "orders"}
+	suppress := newTestEventRule(core.EventRuleActionSuppress)
+	suppress.Match.Events = "^message_rejected$"
+	ed.rules = parseTestEventRules(t, route, suppress)
+
+	routed := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed, Topic: "orders"}}}
+	matched := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed, Topic: "topic1"}}}
+	suppressed := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageRejected, Topic: "topic1"}}}
+	unmatched := &core.EventDelivery{EnrichedEvent: core.EnrichedEvent{Event: core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed, Topic: "topic2"}}}
+
+	events := ed.filterEvents([]*core.EventDelivery{routed, matched, suppressed, unmatched})
+	assert.Len(t, events, 2)
+	assert.Equal(t, routed, events[0])
+	assert.Equal(t, fftypes.JSONObject{"team": "orders"}, events[0].Labels)
+	assert.Equal(t, matched, events[1])
+	assert.Nil(t, events[1].Labels)
+}
+
+func TestBufferedDeliveryLoadRulesFail(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.rules = &eventRules{}
+
+	mdi := ed.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
+	mdi.On("GetEventRules", mock.Anything, "", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	repoll, err := ed.bufferedDelivery([]core.LocallySequenced{&core.Event{ID: fftypes.NewUUID(), Type: core.EventTypeIdentityConfirmed}})
+	assert.False(t, repoll)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateEventRuleOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	rule := newTestEventRule("Suppress")
+	em.mdi.On("GetEventRuleByName", em.ctx, "ns1", "rule1").Return(nil, nil)
+	em.mdi.On("InsertEventRule", em.ctx, rule).Return(nil)
+
+	em.subManager.rules.loaded = true
+	err := em.CreateEventRule(em.ctx, rule)
+	assert.NoError(t, err)
+	assert.Equal(t, core.EventRuleActionSuppress, rule.Action)
+	assert.False(t, em.subManager.rules.loaded)
+}
+
+func TestCreateEventRuleBadAction(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	err := em.CreateEventRule(em.ctx, newTestEventRule("wrong"))
+	assert.Regexp(t, "FF00172", err)
+}
+
+func TestCreateEventRuleInvalid(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	err := em.CreateEventRule(em.ctx, newTestEventRule(core.EventRuleActionLabel))
+	assert.Regexp(t, "FF10584", err)
+}
+
+func TestCreateEventRuleExists(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetEventRuleByName", em.ctx, "ns1", "rule1").Return(&core.EventRule{}, nil)
+	err := em.CreateEventRule(em.ctx, newTestEventRule(core.EventRuleActionSuppress))
+	assert.Regexp(t, "FF10193", err)
+}
+
+func TestCreateEventRuleLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetEventRuleByName", em.ctx, "ns1", "rule1").Return(nil, fmt.Errorf("pop"))
+	err := em.CreateEventRule(em.ctx, newTestEventRule(core.EventRuleActionSuppress))
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateEventRuleInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	rule := newTestEventRule(core.EventRuleActionSuppress)
+	em.mdi.On("GetEventRuleByName", em.ctx, "ns1", "rule1").Return(nil, nil)
+	em.mdi.On("InsertEventRule", em.ctx, rule).Return(fmt.Errorf("pop"))
+	err := em.CreateEventRule(em.ctx, rule)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteEventRule(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	rule := newTestEventRule(core.EventRuleActionSuppress)
+	em.mdi.On("DeleteEventRuleByID", em.ctx, "ns1", rule.ID).Return(fmt.Errorf("pop")).Once()
+	em.mdi.On("DeleteEventRuleByID", em.ctx, "ns1", rule.ID).Return(nil).Once()
+
+	err := em.DeleteEventRule(em.ctx, rule)
+	assert.EqualError(t, err, "pop")
+
+	em.subManager.rules.loaded = true
+	err = em.DeleteEventRule(em.ctx, rule)
+	assert.NoError(t, err)
+	assert.False(t, em.subManager.rules.loaded)
+}
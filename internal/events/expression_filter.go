@@ -0,0 +1,76 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/cel-go/cel"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// expressionFilter is a compiled CEL expression, evaluated against the JSON representation of an enriched event
+type expressionFilter struct {
+	expression string
+	program    cel.Program
+}
+
+func newExpressionFilter(ctx context.Context, field, expression string) (*expressionFilter, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("event", cel.DynType),
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgExpressionCompileFailed, field, expression)
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, i18n.WrapError(ctx, issues.Err(), coremsgs.MsgExpressionCompileFailed, field, expression)
+	}
+	// Anything referring to the event is dynamically typed, so we can only reject expressions that are known not to be boolean
+	outputType := ast.OutputType()
+	if !cel.BoolType.IsAssignableType(outputType) && !outputType.IsAssignableType(cel.BoolType) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgExpressionNotBoolean, field, expression, outputType)
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgExpressionCompileFailed, field, expression)
+	}
+	return &expressionFilter{
+		expression: expression,
+		program:    program,
+	}, nil
+}
+
+// matches evaluates the expression against an event. An expression that fails to evaluate, such as one
+// referring to a field that is not set on this event, is treated as not matching.
+func (ef *expressionFilter) matches(ctx context.Context, event *core.EnrichedEvent) bool {
+	var input map[string]interface{}
+	b, _ := json.Marshal(event)
+	_ = json.Unmarshal(b, &input)
+	out, _, err := ef.program.Eval(map[string]interface{}{"event": input})
+	if err != nil {
+		log.L(ctx).Tracef("Expression '%s' did not match event %s: %s", ef.expression, event.ID, err)
+		return false
+	}
+	match, ok := out.Value().(bool)
+	return ok && match
+}
@@ -0,0 +1,87 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTransferEvent(to string, amount int64) *core.EnrichedEvent {
+	transfer := &core.TokenTransfer{To: to}
+	transfer.Amount.Int().SetInt64(amount)
+	return &core.EnrichedEvent{
+		Event: core.Event{
+			ID:   fftypes.NewUUID(),
+			Type: core.EventTypeTransferConfirmed,
+		},
+		TokenTransfer: transfer,
+	}
+}
+
+func TestExpressionFilterTransfers(t *testing.T) {
+	ef, err := newExpressionFilter(context.Background(), "filter.expression",
+		"event.type == 'token_transfer_confirmed' && event.tokenTransfer.to == '0x01' && int(event.tokenTransfer.amount) > 1000")
+	assert.NoError(t, err)
+
+	assert.True(t, ef.matches(context.Background(), newTestTransferEvent("0x01", 1001)))
+	assert.False(t, ef.matches(context.Background(), newTestTransferEvent("0x01", 1000)))
+	assert.False(t, ef.matches(context.Background(), newTestTransferEvent("0x02", 1001)))
+}
+
+func TestExpressionFilterMissingFieldNoMatch(t *testing.T) {
+	ef, err := newExpressionFilter(context.Background(), "filter.expression", "event.message.header.tag == 'tag1'")
+	assert.NoError(t, err)
+
+	assert.False(t, ef.matches(context.Background(), newTestTransferEvent("0x01", 1)))
+	assert.True(t, ef.matches(context.Background(), &core.EnrichedEvent{
+		Message: &core.Message{Header: core.MessageHeader{Tag: "tag1"}},
+	}))
+}
+
+func TestExpressionFilterBlockchainEventOutput(t *testing.T) {
+	ef, err := newExpressionFilter(context.Background(), "filter.expression", "event.blockchainEvent.output.value > 10")
+	assert.NoError(t, err)
+
+	assert.True(t, ef.matches(context.Background(), &core.EnrichedEvent{
+		BlockchainEvent: &core.BlockchainEvent{Output: fftypes.JSONObject{"value": 11}},
+	}))
+	assert.False(t, ef.matches(context.Background(), &core.EnrichedEvent{
+		BlockchainEvent: &core.BlockchainEvent{Output: fftypes.JSONObject{"value": 10}},
+	}))
+}
+
+func TestExpressionFilterNonBoolResultNoMatch(t *testing.T) {
+	ef, err := newExpressionFilter(context.Background(), "filter.expression", "event.type")
+	assert.NoError(t, err)
+
+	assert.False(t, ef.matches(context.Background(), newTestTransferEvent("0x01", 1)))
+}
+
+func TestExpressionFilterNotBoolean(t *testing.T) {
+	_, err := newExpressionFilter(context.Background(), "filter.expression", "1 + 2")
+	assert.Regexp(t, "FF10549", err)
+}
+
+func TestExpressionFilterCompileFail(t *testing.T) {
+	_, err := newExpressionFilter(context.Background(), "filter.expression", "!!!")
+	assert.Regexp(t, "FF10548", err)
+}
@@ -0,0 +1,212 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcstream
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/hyperledger/firefly/pkg/eventstream"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// GRPC accepts bidirectional gRPC streams from consumers, defined by the EventStream service in
+// pkg/eventstream. Like WebSockets, a consumer can start any number of subscriptions on a single
+// stream, and acknowledges each event it receives, but the events are encoded with protobuf.
+type GRPC struct {
+	eventstream.UnimplementedEventStreamServer
+
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	connections  map[string]*streamConnection
+	connMux      sync.Mutex
+	server       *grpc.Server
+	listener     net.Listener
+	auth         core.Authorizer
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+func (g *GRPC) Name() string { return "grpc" }
+
+func (g *GRPC) Init(ctx context.Context, config config.Section) error {
+	tlsConfig, err := fftls.ConstructTLSConfig(ctx, config.SubSection("tls"), fftls.ServerType)
+	if err != nil {
+		return err
+	}
+	if g.server != nil {
+		// Re-initialized after a reset, so release the port before listening again
+		g.server.Stop()
+		_ = g.listener.Close()
+	}
+
+	address := fmt.Sprintf("%s:%d", config.GetString(GRPCConfigAddress), config.GetInt(GRPCConfigPort))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return i18n.WrapError(ctx, err, coremsgs.MsgGRPCListenFailed, address)
+	}
+	var opts []grpc.ServerOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	*g = GRPC{
+		ctx:          ctx,
+		capabilities: &events.Capabilities{},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		connections: make(map[string]*streamConnection),
+		server:      grpc.NewServer(opts...),
+		listener:    listener,
+		auth:        g.auth,
+	}
+	eventstream.RegisterEventStreamServer(g.server, g)
+	go serve(ctx, g.server, listener)
+	return nil
+}
+
+func serve(ctx context.Context, server *grpc.Server, listener net.Listener) {
+	go func() {
+		<-ctx.Done()
+		server.Stop()
+	}()
+	log.L(ctx).Infof("gRPC event streams listening on %s", listener.Addr())
+	if err := server.Serve(listener); err != nil {
+		log.L(ctx).Errorf("gRPC event stream server stopped: %s", err)
+	}
+}
+
+func (g *GRPC) SetAuthorizer(auth core.Authorizer) {
+	g.auth = auth
+}
+
+func (g *GRPC) SetHandler(namespace string, handler events.Callbacks) error {
+	g.callbacks.writeLock.Lock()
+	defer g.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(g.callbacks.handlers, namespace)
+		return nil
+	}
+	g.callbacks.handlers[namespace] = handler
+	return nil
+}
+
+func (g *GRPC) Capabilities() *events.Capabilities {
+	return g.capabilities
+}
+
+func (g *GRPC) ValidateOptions(options *core.SubscriptionOptions) error {
+	return nil
+}
+
+func (g *GRPC) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(g.ctx, coremsgs.MsgBatchDeliveryNotSupported, g.Name())
+}
+
+func (g *GRPC) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	g.connMux.Lock()
+	sc, ok := g.connections[connID]
+	g.connMux.Unlock()
+	if !ok {
+		return i18n.NewError(g.ctx, coremsgs.MsgGRPCStreamNotActive, connID)
+	}
+	return sc.dispatch(event, data)
+}
+
+// Listen is called by gRPC for each stream opened by a consumer, and returns when the stream ends
+func (g *GRPC) Listen(stream eventstream.EventStream_ListenServer) error {
+	sc := newStreamConnection(g.ctx, g, stream)
+	g.connMux.Lock()
+	g.connections[sc.connID] = sc
+	g.connMux.Unlock()
+	defer g.connClosed(sc.connID)
+	return sc.run()
+}
+
+func (g *GRPC) getHandler(namespace string) (events.Callbacks, bool) {
+	g.callbacks.writeLock.Lock()
+	defer g.callbacks.writeLock.Unlock()
+	cb, ok := g.callbacks.handlers[namespace]
+	return cb, ok
+}
+
+func (g *GRPC) ack(connID string, inflight *core.EventDeliveryResponse) {
+	if cb, ok := g.getHandler(inflight.Subscription.Namespace); ok {
+		cb.DeliveryResponse(connID, inflight)
+	}
+}
+
+func (g *GRPC) start(sc *streamConnection, start *core.WSStart) error {
+	if start.Namespace == "" || (!start.Ephemeral && start.Name == "") {
+		return i18n.NewError(g.ctx, coremsgs.MsgWSInvalidStartAction)
+	}
+	if cb, ok := g.getHandler(start.Namespace); ok {
+		if start.Ephemeral {
+			return cb.EphemeralSubscription(sc.connID, start.Namespace, &start.Filter, &start.Options)
+		}
+		// We can have multiple subscriptions on a single stream
+		return cb.RegisterConnection(sc.connID, func(sr core.SubscriptionRef) bool {
+			return sc.durableSubMatcher(sr)
+		})
+	}
+	return i18n.NewError(g.ctx, coremsgs.MsgNamespaceDoesNotExist)
+}
+
+func (g *GRPC) connClosed(connID string) {
+	g.connMux.Lock()
+	delete(g.connections, connID)
+	g.connMux.Unlock()
+	// Drop lock before calling back
+	g.callbacks.writeLock.Lock()
+	handlers := make([]events.Callbacks, 0, len(g.callbacks.handlers))
+	for _, cb := range g.callbacks.handlers {
+		handlers = append(handlers, cb)
+	}
+	g.callbacks.writeLock.Unlock()
+	for _, cb := range handlers {
+		cb.ConnectionClosed(connID)
+	}
+}
+
+func (g *GRPC) NamespaceRestarted(ns string, startTime time.Time) {
+	g.connMux.Lock()
+	connections := make([]*streamConnection, 0, len(g.connections))
+	for _, sc := range g.connections {
+		connections = append(connections, sc)
+	}
+	g.connMux.Unlock()
+
+	for _, sc := range connections {
+		sc.restartForNamespace(ns, startTime)
+	}
+}
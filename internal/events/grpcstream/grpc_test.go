@@ -0,0 +1,506 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcstream
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/eventstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testAuthorizer struct{}
+
+func (t *testAuthorizer) Authorize(ctx context.Context, authReq *fftypes.AuthReq) error {
+	if authReq.Namespace == "ns1" && authReq.Header.Get("Authorization") == "Bearer token1" {
+		return nil
+	}
+	return i18n.NewError(ctx, i18n.MsgUnauthorized)
+}
+
+func newTestGRPC(t *testing.T, cbs *eventsmocks.Callbacks) (g *GRPC, client eventstream.EventStreamClient, cancel func()) {
+	coreconfig.Reset()
+
+	g = &GRPC{}
+	g.SetAuthorizer(&testAuthorizer{})
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	utConfig := config.RootSection("ut.grpc")
+	g.InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+	err := g.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "grpc", g.Name())
+	assert.NotNil(t, g.Capabilities())
+	assert.NoError(t, g.ValidateOptions(&core.SubscriptionOptions{}))
+	err = g.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+
+	conn, err := grpc.Dial(g.listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	return g, eventstream.NewEventStreamClient(conn), func() {
+		_ = conn.Close()
+		cancelCtx()
+		cbs.AssertExpectations(t)
+	}
+}
+
+func listen(t *testing.T, client eventstream.EventStreamClient) (eventstream.EventStream_ListenClient, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer token1")
+	stream, err := client.Listen(ctx)
+	assert.NoError(t, err)
+	return stream, cancel
+}
+
+func newTestEvent(sub core.SubscriptionRef) *core.EventDelivery {
+	created := fftypes.Now()
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:          fftypes.NewUUID(),
+				Sequence:    12345,
+				Type:        core.EventTypeMessageConfirmed,
+				Namespace:   "ns1",
+				Reference:   fftypes.NewUUID(),
+				Transaction: fftypes.NewUUID(),
+				Topic:       "topic1",
+				Created:     created,
+			},
+		},
+		Subscription: sub,
+	}
+}
+
+func startDurable(t *testing.T, g *GRPC, cbs *eventsmocks.Callbacks, stream eventstream.EventStream_ListenClient, name string, autoAck bool) string {
+	registered := make(chan string)
+	cbs.On("RegisterConnection", mock.Anything, mock.MatchedBy(func(m func(core.SubscriptionRef) bool) bool {
+		return m(core.SubscriptionRef{Namespace: "ns1", Name: name}) &&
+			!m(core.SubscriptionRef{Namespace: "ns1", Name: "other"})
+	})).Return(nil).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	}).Once()
+	err := stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Start{
+		Start: &eventstream.Start{Namespace: "ns1", Name: name, AutoAck: autoAck},
+	}})
+	assert.NoError(t, err)
+	return <-registered
+}
+
+func expectStreamError(t *testing.T, stream eventstream.EventStream_ListenClient, code codes.Code, regexp string) {
+	_, err := stream.Recv()
+	assert.Equal(t, code, status.Code(err))
+	assert.Regexp(t, regexp, err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	g := &GRPC{}
+	utConfig := config.RootSection("ut.grpc")
+	g.InitConfig(utConfig)
+	tlsConfig := utConfig.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCAFile, "BADCA")
+	err := g.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestInitTLSListenFail(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+
+	coreconfig.Reset()
+	g := &GRPC{}
+	utConfig := config.RootSection("ut.grpc")
+	g.InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, l.Addr().(*net.TCPAddr).Port)
+	utConfig.SubSection("tls").Set(fftls.HTTPConfTLSEnabled, true)
+	err = g.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10531", err)
+}
+
+func TestInitTLS(t *testing.T) {
+	dir := t.TempDir()
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyBytes, _ := x509.MarshalECPrivateKey(key)
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certBytes}), 0600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600)
+
+	coreconfig.Reset()
+	g := &GRPC{}
+	utConfig := config.RootSection("ut.grpc")
+	g.InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+	tlsConfig := utConfig.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCertFile, certFile)
+	tlsConfig.Set(fftls.HTTPConfTLSKeyFile, keyFile)
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	err = g.Init(ctx, utConfig)
+	assert.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	cert, _ := x509.ParseCertificate(certBytes)
+	pool.AddCert(cert)
+	conn, err := grpc.Dial(g.listener.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12})))
+	assert.NoError(t, err)
+	defer conn.Close()
+	stream, err := eventstream.NewEventStreamClient(conn).Listen(ctx)
+	assert.NoError(t, err)
+	err = stream.Send(&eventstream.ClientMessage{})
+	assert.NoError(t, err)
+	// No handlers are registered, so there are no callbacks on close
+	expectStreamError(t, stream, codes.InvalidArgument, "FF10533")
+}
+
+func TestServeListenerClosed(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	_ = l.Close()
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	serve(ctx, grpc.NewServer(), l)
+}
+
+func TestReinitReleasesPort(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, _, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	// Listening again on the same port succeeds, as the previous server is stopped first
+	utConfig := config.RootSection("ut.grpc")
+	g.InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, g.listener.Addr().(*net.TCPAddr).Port)
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	defer cancelCtx()
+	err := g.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	assert.NotNil(t, g.auth)
+}
+
+func TestSetHandlerRemove(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, _, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	err := g.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, g.callbacks.handlers)
+}
+
+func TestDurableSubscriptionDeliverAndAck(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, client, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	stream, done := listen(t, client)
+	connID := startDurable(t, g, cbs, stream, "sub1", false)
+
+	sub := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"}
+	event := newTestEvent(sub)
+	data := core.DataArray{{ID: fftypes.NewUUID(), Validator: core.ValidatorTypeJSON, Hash: fftypes.NewRandB32(), Value: fftypes.JSONAnyPtr(`{"a":"b"}`)}}
+	err := g.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, event, data)
+	assert.NoError(t, err)
+
+	pe, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, event.ID.String(), pe.Id)
+	assert.Equal(t, int64(12345), pe.Sequence)
+	assert.Equal(t, "message_confirmed", pe.Type)
+	assert.Equal(t, event.Reference.String(), pe.Reference)
+	assert.Equal(t, event.Event.Transaction.String(), pe.Transaction)
+	assert.Equal(t, "", pe.Correlator)
+	assert.Equal(t, "topic1", pe.Topic)
+	assert.Equal(t, event.Created.Time().UnixNano(), pe.Created.AsTime().UnixNano())
+	assert.Equal(t, sub.ID.String(), pe.Subscription.Id)
+	assert.Regexp(t, event.ID.String(), string(pe.Enriched))
+	assert.Len(t, pe.Data, 1)
+	assert.Equal(t, `{"a":"b"}`, string(pe.Data[0].Value))
+	assert.Equal(t, "json", pe.Data[0].Validator)
+
+	acked := make(chan struct{})
+	cbs.On("DeliveryResponse", connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID)
+	})).Run(func(args mock.Arguments) { acked <- struct{}{} }).Twice()
+	err = stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{
+		Ack: &eventstream.Ack{Id: event.ID.String()},
+	}})
+	assert.NoError(t, err)
+	<-acked
+
+	// An ack without an id acknowledges the oldest event in flight
+	err = g.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, event, nil)
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+	err = stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{
+		Ack: &eventstream.Ack{},
+	}})
+	assert.NoError(t, err)
+	<-acked
+
+	closed := make(chan struct{})
+	cbs.On("ConnectionClosed", connID).Run(func(args mock.Arguments) { close(closed) }).Once()
+	done()
+	<-closed
+
+	err = g.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, event, nil)
+	assert.Regexp(t, "FF10532", err)
+}
+
+func TestAckWithSubscription(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, client, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	stream, done := listen(t, client)
+	defer done()
+	connID := startDurable(t, g, cbs, stream, "sub1", false)
+	startDurable(t, g, cbs, stream, "sub2", false)
+
+	sub1 := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"}
+	sub2 := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub2"}
+	event1 := newTestEvent(sub1)
+	event2 := newTestEvent(sub2)
+	assert.NoError(t, g.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub1}, event1, nil))
+	assert.NoError(t, g.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub2}, event2, nil))
+
+	acked := make(chan *core.EventDeliveryResponse, 2)
+	cbs.On("DeliveryResponse", connID, mock.Anything).Run(func(args mock.Arguments) {
+		acked <- args[1].(*core.EventDeliveryResponse)
+	}).Twice()
+	err := stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{
+		Ack: &eventstream.Ack{Id: event2.ID.String(), Subscription: &eventstream.SubscriptionRef{Id: sub2.ID.String()}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, event2.ID, (<-acked).ID)
+	err = stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{
+		Ack: &eventstream.Ack{Id: event1.ID.String(), Subscription: &eventstream.SubscriptionRef{Namespace: "ns1", Name: "sub1"}},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, event1.ID, (<-acked).ID)
+
+	// With two subscriptions started, an ack must say which one it is for
+	assert.NoError(t, g.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub1}, event1, nil))
+	cbs.On("ConnectionClosed", connID).Once()
+	err = stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{
+		Ack: &eventstream.Ack{Id: event1.ID.String()},
+	}})
+	assert.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, _ = stream.Recv()
+	}
+	expectStreamError(t, stream, codes.InvalidArgument, "FF10175")
+}
+
+func TestEphemeralSubscriptionAutoAck(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, client, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	stream, done := listen(t, client)
+	defer done()
+
+	started := make(chan string)
+	cbs.On("EphemeralSubscription", mock.Anything, "ns1", mock.MatchedBy(func(f *core.SubscriptionFilter) bool {
+		return f.Topic == "topic1" && f.Message.Tag == "tag1" && f.BlockchainEvent.Listener == "listener1"
+	}), mock.MatchedBy(func(o *core.SubscriptionOptions) bool {
+		return *o.WithData && *o.ReadAhead == 10
+	})).Return(nil).Run(func(args mock.Arguments) {
+		started <- args[0].(string)
+	})
+	err := stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Start{
+		Start: &eventstream.Start{
+			Namespace: "ns1",
+			Ephemeral: true,
+			AutoAck:   true,
+			WithData:  true,
+			ReadAhead: 10,
+			Filter: &eventstream.Filter{
+				Topic:                   "topic1",
+				MessageTag:              "tag1",
+				BlockchainEventListener: "listener1",
+			},
+		},
+	}})
+	assert.NoError(t, err)
+	connID := <-started
+
+	sub := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "ephemeral1"}
+	event := newTestEvent(sub)
+	cbs.On("DeliveryResponse", connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID)
+	})).Once()
+	err = g.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, event, nil)
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+
+	// Acks are not allowed with auto-ack
+	cbs.On("ConnectionClosed", connID).Once()
+	err = stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{
+		Ack: &eventstream.Ack{},
+	}})
+	assert.NoError(t, err)
+	expectStreamError(t, stream, codes.InvalidArgument, "FF10180")
+}
+
+func TestAutoAckChanged(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, client, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	stream, done := listen(t, client)
+	defer done()
+	connID := startDurable(t, g, cbs, stream, "sub1", false)
+
+	cbs.On("ConnectionClosed", connID).Once()
+	err := stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Start{
+		Start: &eventstream.Start{Namespace: "ns1", Name: "sub2", AutoAck: true},
+	}})
+	assert.NoError(t, err)
+	expectStreamError(t, stream, codes.InvalidArgument, "FF10179")
+}
+
+func TestInvalidMessages(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, client, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+	cbs.On("ConnectionClosed", mock.Anything)
+
+	for _, tc := range []struct {
+		msg    *eventstream.ClientMessage
+		errMsg string
+	}{
+		{&eventstream.ClientMessage{}, "FF10533"},
+		{&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Start{Start: &eventstream.Start{Namespace: "ns1"}}}, "FF10178"},
+		{&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Start{Start: &eventstream.Start{Namespace: "ns2", Ephemeral: true}}}, "FF00169"},
+		{&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{Ack: &eventstream.Ack{Id: "bad"}}}, "FF00138"},
+		{&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{Ack: &eventstream.Ack{Subscription: &eventstream.SubscriptionRef{Id: "bad"}}}}, "FF00138"},
+		{&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{Ack: &eventstream.Ack{}}}, "FF10175"},
+		{&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Ack{Ack: &eventstream.Ack{Id: fftypes.NewUUID().String()}}}, "FF10175"},
+	} {
+		stream, done := listen(t, client)
+		err := stream.Send(tc.msg)
+		assert.NoError(t, err)
+		expectStreamError(t, stream, codes.InvalidArgument, tc.errMsg)
+		done()
+	}
+
+	g.auth = nil
+	stream, done := listen(t, client)
+	defer done()
+	err := stream.Send(&eventstream.ClientMessage{Action: &eventstream.ClientMessage_Start{Start: &eventstream.Start{Namespace: "ns2", Ephemeral: true}}})
+	assert.NoError(t, err)
+	expectStreamError(t, stream, codes.InvalidArgument, "FF10187")
+}
+
+func TestNamespaceRestarted(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, client, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	stream, done := listen(t, client)
+	defer done()
+	connID := startDurable(t, g, cbs, stream, "sub1", false)
+
+	// Restarts before the stream started are ignored
+	g.NamespaceRestarted("ns1", time.Now().Add(-1*time.Hour))
+
+	cbs.On("RegisterConnection", connID, mock.Anything).Return(nil).Once()
+	g.NamespaceRestarted("ns1", time.Now().Add(1*time.Hour))
+
+	closed := make(chan struct{})
+	cbs.On("RegisterConnection", connID, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	cbs.On("ConnectionClosed", connID).Run(func(args mock.Arguments) { close(closed) }).Once()
+	g.NamespaceRestarted("ns1", time.Now().Add(2*time.Hour))
+	expectStreamError(t, stream, codes.Unavailable, "FF10532")
+	<-closed
+}
+
+func TestDeliveryRequestSendFail(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	g, _, cancel := newTestGRPC(t, cbs)
+	defer cancel()
+
+	stream := &testServerStream{ctx: context.Background(), err: fmt.Errorf("pop")}
+	sc := newStreamConnection(g.ctx, g, stream)
+	g.connections[sc.connID] = sc
+
+	sub := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"}
+	err := g.DeliveryRequest(sc.connID, &core.Subscription{SubscriptionRef: sub}, newTestEvent(sub), nil)
+	assert.EqualError(t, err, "pop")
+
+	err = g.DeliveryRequest(sc.connID, &core.Subscription{SubscriptionRef: sub}, newTestEvent(sub), nil)
+	assert.Regexp(t, "FF10532", err)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	err error
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *testServerStream) Send(*eventstream.Event) error {
+	return s.err
+}
+
+func (s *testServerStream) Recv() (*eventstream.ClientMessage, error) {
+	return nil, s.err
+}
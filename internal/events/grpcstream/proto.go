@@ -0,0 +1,129 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcstream
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/eventstream"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func startFromProto(start *eventstream.Start) *core.WSStart {
+	autoAck := start.AutoAck
+	ws := &core.WSStart{
+		AutoAck:   &autoAck,
+		Namespace: start.Namespace,
+		Name:      start.Name,
+		Ephemeral: start.Ephemeral,
+	}
+	if f := start.Filter; f != nil {
+		ws.Filter = core.SubscriptionFilter{
+			Events: f.Events,
+			Topic:  f.Topic,
+			Message: core.MessageFilter{
+				Tag:    f.MessageTag,
+				Group:  f.MessageGroup,
+				Author: f.MessageAuthor,
+			},
+			Transaction: core.TransactionFilter{
+				Type: f.TransactionType,
+			},
+			BlockchainEvent: core.BlockchainEventFilter{
+				Name:     f.BlockchainEventName,
+				Listener: f.BlockchainEventListener,
+			},
+		}
+	}
+	if start.WithData {
+		withData := true
+		ws.Options.WithData = &withData
+	}
+	if start.ReadAhead > 0 {
+		readAhead := uint16(start.ReadAhead)
+		ws.Options.ReadAhead = &readAhead
+	}
+	return ws
+}
+
+func ackFromProto(ctx context.Context, ack *eventstream.Ack) (ws *core.WSAck, err error) {
+	ws = &core.WSAck{}
+	if ack.Id != "" {
+		if ws.ID, err = fftypes.ParseUUID(ctx, ack.Id); err != nil {
+			return nil, err
+		}
+	}
+	if sub := ack.Subscription; sub != nil {
+		ws.Subscription = &core.SubscriptionRef{
+			Namespace: sub.Namespace,
+			Name:      sub.Name,
+		}
+		if sub.Id != "" {
+			if ws.Subscription.ID, err = fftypes.ParseUUID(ctx, sub.Id); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ws, nil
+}
+
+func uuidString(u *fftypes.UUID) string {
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func eventToProto(event *core.EventDelivery, data core.DataArray) *eventstream.Event {
+	enriched, _ := json.Marshal(event)
+	pe := &eventstream.Event{
+		Id:          uuidString(event.ID),
+		Sequence:    event.Sequence,
+		Type:        string(event.Type),
+		Namespace:   event.Namespace,
+		Reference:   uuidString(event.Reference),
+		Correlator:  uuidString(event.Correlator),
+		Transaction: uuidString(event.Event.Transaction),
+		Topic:       event.Topic,
+		Subscription: &eventstream.SubscriptionRef{
+			Id:        uuidString(event.Subscription.ID),
+			Namespace: event.Subscription.Namespace,
+			Name:      event.Subscription.Name,
+		},
+		Enriched: enriched,
+	}
+	if event.Created != nil {
+		pe.Created = timestamppb.New(*event.Created.Time())
+	}
+	for _, d := range data {
+		pd := &eventstream.Data{
+			Id:        uuidString(d.ID),
+			Validator: string(d.Validator),
+		}
+		if d.Hash != nil {
+			pd.Hash = d.Hash.String()
+		}
+		if d.Value != nil {
+			pd.Value = d.Value.Bytes()
+		}
+		pe.Data = append(pe.Data, pd)
+	}
+	return pe
+}
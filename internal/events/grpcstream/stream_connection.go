@@ -0,0 +1,281 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcstream
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/eventstream"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type streamStartedSub struct {
+	core.WSStart
+	startTime *fftypes.FFTime
+}
+
+type streamConnection struct {
+	ctx          context.Context
+	g            *GRPC
+	stream       eventstream.EventStream_ListenServer
+	cancelCtx    func()
+	connID       string
+	receiverDone chan struct{}
+	receiveErr   error
+	sendMux      sync.Mutex
+	autoAck      bool
+	started      []*streamStartedSub
+	inflight     []*core.EventDeliveryResponse
+	mux          sync.Mutex
+	header       http.Header
+}
+
+func newStreamConnection(pCtx context.Context, g *GRPC, stream eventstream.EventStream_ListenServer) *streamConnection {
+	connID := fftypes.NewUUID().String()
+	ctx := log.WithLogField(pCtx, "grpc", connID)
+	ctx, cancelCtx := context.WithCancel(ctx)
+	sc := &streamConnection{
+		ctx:          ctx,
+		g:            g,
+		stream:       stream,
+		cancelCtx:    cancelCtx,
+		connID:       connID,
+		receiverDone: make(chan struct{}),
+		header:       http.Header{},
+	}
+	// Headers are passed to the authorizer in the same way as they are for WebSockets
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	for k, values := range md {
+		for _, v := range values {
+			sc.header.Add(k, v)
+		}
+	}
+	return sc
+}
+
+// run processes messages from the consumer until the stream ends, or is closed by us
+func (sc *streamConnection) run() error {
+	defer sc.cancelCtx()
+	go sc.receiveLoop()
+	select {
+	case <-sc.receiverDone:
+		return sc.receiveErr
+	case <-sc.ctx.Done():
+		log.L(sc.ctx).Debugf("Stream closing")
+		return status.Error(codes.Unavailable, i18n.NewError(sc.ctx, coremsgs.MsgGRPCStreamNotActive, sc.connID).Error())
+	case <-sc.stream.Context().Done():
+		log.L(sc.ctx).Debugf("Stream closed by client")
+		return nil
+	}
+}
+
+func (sc *streamConnection) receiveLoop() {
+	l := log.L(sc.ctx)
+	defer close(sc.receiverDone)
+	for {
+		msg, err := sc.stream.Recv()
+		if err != nil {
+			l.Debugf("Receive ended: %s", err)
+			return
+		}
+		l.Tracef("Received: %s", msg)
+		switch action := msg.Action.(type) {
+		case *eventstream.ClientMessage_Start:
+			start := startFromProto(action.Start)
+			err = sc.authorizeMessage(start.Namespace)
+			if err == nil {
+				err = sc.handleStart(start)
+			}
+		case *eventstream.ClientMessage_Ack:
+			// acks are not authorized, as they are only accepted for events that were
+			// sent on this stream, which was authorized in the start message
+			var ack *core.WSAck
+			ack, err = ackFromProto(sc.ctx, action.Ack)
+			if err == nil {
+				err = sc.handleAck(ack)
+			}
+		default:
+			err = i18n.NewError(sc.ctx, coremsgs.MsgGRPCClientNoAction)
+		}
+		if err != nil {
+			l.Errorf("Invalid request sent on stream: %s", err)
+			sc.receiveErr = status.Error(codes.InvalidArgument, i18n.WrapError(sc.ctx, err, coremsgs.MsgWSClientSentInvalidData).Error())
+			return
+		}
+	}
+}
+
+func (sc *streamConnection) dispatch(event *core.EventDelivery, data core.DataArray) error {
+	inflight := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+
+	var autoAck bool
+	sc.mux.Lock()
+	autoAck = sc.autoAck
+	if !autoAck {
+		sc.inflight = append(sc.inflight, inflight)
+	}
+	sc.mux.Unlock()
+
+	err := sc.send(eventToProto(event, data))
+	if err != nil {
+		return err
+	}
+
+	if autoAck {
+		sc.g.ack(sc.connID, inflight)
+	}
+
+	return nil
+}
+
+func (sc *streamConnection) send(event *eventstream.Event) error {
+	if sc.ctx.Err() != nil {
+		return i18n.NewError(sc.ctx, coremsgs.MsgGRPCStreamNotActive, sc.connID)
+	}
+	// gRPC does not allow concurrent sends on a stream
+	sc.sendMux.Lock()
+	defer sc.sendMux.Unlock()
+	if err := sc.stream.Send(event); err != nil {
+		log.L(sc.ctx).Errorf("Send failed on stream: %s", err)
+		sc.close()
+		return err
+	}
+	return nil
+}
+
+func (sc *streamConnection) restartForNamespace(ns string, startTime time.Time) {
+	sc.mux.Lock()
+	toStart := []*core.WSStart{}
+	for _, s := range sc.started {
+		if s.Namespace == ns && s.startTime.Time().Before(startTime) {
+			log.L(sc.ctx).Infof("Restarting subscription '%s:%s' (ephemeral=%t)", s.Namespace, s.Name, s.Ephemeral)
+			toStart = append(toStart, &s.WSStart)
+			s.startTime = fftypes.Now()
+		}
+	}
+	sc.mux.Unlock()
+	for _, s := range toStart {
+		if err := sc.g.start(sc, s); err != nil {
+			log.L(sc.ctx).Errorf("Failed restart subscription '%s:%s' (closing): %s", s.Namespace, s.Name, err)
+			sc.close()
+		}
+	}
+}
+
+func (sc *streamConnection) handleStart(start *core.WSStart) (err error) {
+	sc.mux.Lock()
+	if *start.AutoAck != sc.autoAck && len(sc.started) > 0 {
+		sc.mux.Unlock()
+		return i18n.NewError(sc.ctx, coremsgs.MsgWSAutoAckChanged)
+	}
+	sc.autoAck = *start.AutoAck
+	sc.started = append(sc.started, &streamStartedSub{
+		startTime: fftypes.Now(),
+		WSStart:   *start,
+	})
+	sc.mux.Unlock()
+	return sc.g.start(sc, start)
+}
+
+func (sc *streamConnection) durableSubMatcher(sr core.SubscriptionRef) bool {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+	for _, startedSub := range sc.started {
+		if !startedSub.Ephemeral && startedSub.Namespace == sr.Namespace && startedSub.Name == sr.Name {
+			return true
+		}
+	}
+	return false
+}
+
+func (sc *streamConnection) checkAck(ack *core.WSAck) (*core.EventDeliveryResponse, error) {
+	var inflight *core.EventDeliveryResponse
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+
+	if sc.autoAck {
+		return nil, i18n.NewError(sc.ctx, coremsgs.MsgWSAutoAckEnabled)
+	}
+
+	if ack.ID != nil {
+		if ack.Subscription == nil && len(sc.started) != 1 {
+			log.L(sc.ctx).Errorf("No subscription specified on ack, and there is not exactly one started subscription")
+			return nil, i18n.NewError(sc.ctx, coremsgs.MsgWSMsgSubNotMatched)
+		}
+		newInflight := make([]*core.EventDeliveryResponse, 0, len(sc.inflight))
+		for _, candidate := range sc.inflight {
+			match := *candidate.ID == *ack.ID && (ack.Subscription == nil ||
+				// A subscription has been explicitly specified, so it must match
+				(ack.Subscription.ID != nil && *ack.Subscription.ID == *candidate.Subscription.ID) ||
+				(ack.Subscription.Name == candidate.Subscription.Name && ack.Subscription.Namespace == candidate.Subscription.Namespace))
+			// Remove from the inflight list
+			if match {
+				inflight = candidate
+			} else {
+				newInflight = append(newInflight, candidate)
+			}
+		}
+		sc.inflight = newInflight
+	} else if len(sc.inflight) > 0 {
+		// Just ack the front of the queue
+		inflight = sc.inflight[0]
+		sc.inflight = sc.inflight[1:]
+	}
+	if inflight == nil {
+		return nil, i18n.NewError(sc.ctx, coremsgs.MsgWSMsgSubNotMatched)
+	}
+	return inflight, nil
+}
+
+func (sc *streamConnection) handleAck(ack *core.WSAck) error {
+	// Perform a locked set of check
+	inflight, err := sc.checkAck(ack)
+	if err != nil {
+		return err
+	}
+
+	// Deliver the ack to the core, now we're unlocked
+	sc.g.ack(sc.connID, inflight)
+	return nil
+}
+
+func (sc *streamConnection) close() {
+	sc.cancelCtx()
+}
+
+func (sc *streamConnection) authorizeMessage(ns string) error {
+	if sc.g.auth != nil {
+		return sc.g.auth.Authorize(sc.ctx, &fftypes.AuthReq{
+			Namespace: ns,
+			Header:    sc.header,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,363 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const (
+	inboundActionThrottle   = "throttle"
+	inboundActionQuarantine = "quarantine"
+)
+
+// inboundLimits is a token bucket rate limit on messages and a daily size quota, applied separately to the
+// private batches received from each org
+type inboundLimits struct {
+	messagesPerSecond float64
+	burst             float64
+	dailyQuota        int64
+	quarantine        bool
+
+	mux  sync.Mutex
+	orgs map[string]*inboundUsage
+}
+
+type inboundUsage struct {
+	tokens    float64
+	updated   time.Time
+	day       string
+	used      int64
+	throttled bool
+	deferred  []*throttledBatch
+}
+
+// throttledBatch is a batch held back from processing until the org that sent it is back within the rate.
+// The data exchange event is only acknowledged once the batch is processed, so if the node stops first the
+// batch is delivered again.
+type throttledBatch struct {
+	peerID    string
+	transport *core.TransportWrapper
+	releaseAt time.Time
+	ack       func(manifest string)
+}
+
+func newInboundLimits(ctx context.Context) (*inboundLimits, error) {
+	action := config.GetString(coreconfig.PrivateMessagingInboundAction)
+	if action != inboundActionThrottle && action != inboundActionQuarantine {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidInboundAction, action)
+	}
+	messagesPerSecond := config.GetFloat64(coreconfig.PrivateMessagingInboundMessagesPerSecond)
+	burst := config.GetInt64(coreconfig.PrivateMessagingInboundBurst)
+	if burst <= 0 {
+		burst = int64(math.Ceil(messagesPerSecond))
+	}
+	return &inboundLimits{
+		messagesPerSecond: messagesPerSecond,
+		burst:             float64(burst),
+		dailyQuota:        config.GetByteSize(coreconfig.PrivateMessagingInboundDailyQuota),
+		quarantine:        action == inboundActionQuarantine,
+		orgs:              make(map[string]*inboundUsage),
+	}, nil
+}
+
+func (il *inboundLimits) enabled() bool {
+	return il.messagesPerSecond > 0 || il.dailyQuota > 0
+}
+
+// take counts a batch against the limits of the org that sent it. A batch over the daily quota, or over the rate
+// when the action is quarantine, is not counted and the reason to quarantine it is returned. A batch over the rate
+// when the action is throttle is counted, and how long to wait before processing it is returned, along with whether
+// the org has just started to be throttled. The debt of a throttled org is capped at one burst, and a batch beyond
+// that is quarantined, so an org cannot build up an unbounded backlog of held batches.
+func (il *inboundLimits) take(org string, messages int, size int64, now time.Time) (wait time.Duration, throttleStarted bool, reason core.QuarantineReason) {
+	il.mux.Lock()
+	defer il.mux.Unlock()
+
+	now = now.UTC()
+	usage, ok := il.orgs[org]
+	if !ok {
+		usage = &inboundUsage{tokens: il.burst, updated: now}
+		il.orgs[org] = usage
+	}
+
+	day := now.Format("2006-01-02")
+	if usage.day != day {
+		usage.day = day
+		usage.used = 0
+	}
+	if il.dailyQuota > 0 && usage.used+size > il.dailyQuota {
+		return 0, false, core.QuarantineReasonQuota
+	}
+
+	if il.messagesPerSecond > 0 {
+		usage.tokens = math.Min(il.burst, usage.tokens+now.Sub(usage.updated).Seconds()*il.messagesPerSecond)
+		usage.updated = now
+		// A batch larger than the burst is let through once the bucket is full
+		needed := math.Min(float64(messages), il.burst)
+		if usage.tokens < needed {
+			if il.quarantine || usage.tokens-needed < -il.burst {
+				return 0, false, core.QuarantineReasonRate
+			}
+			wait = time.Duration((needed - usage.tokens) / il.messagesPerSecond * float64(time.Second))
+		}
+		// Tokens are taken for a throttled batch straight away, so later batches wait behind it
+		usage.tokens -= needed
+	}
+
+	usage.used += size
+	throttleStarted = wait > 0 && !usage.throttled
+	usage.throttled = wait > 0
+	return wait, throttleStarted, ""
+}
+
+// deferBatch holds a batch back if it must wait, or if earlier batches from the same org are still held, so the
+// batches from each org are processed in order. Returns whether the batch was deferred, and whether it is the first
+// held for the org, in which case the caller starts processing the held batches of the org.
+func (il *inboundLimits) deferBatch(org string, batch *throttledBatch) (deferred, first bool) {
+	il.mux.Lock()
+	defer il.mux.Unlock()
+
+	usage := il.orgs[org]
+	if !batch.releaseAt.After(time.Now()) && len(usage.deferred) == 0 {
+		return false, false
+	}
+	usage.deferred = append(usage.deferred, batch)
+	return true, len(usage.deferred) == 1
+}
+
+// nextDeferred returns the oldest batch held for an org, after removing the one that has just been processed, or nil
+// once there are none left
+func (il *inboundLimits) nextDeferred(org string, processed *throttledBatch) *throttledBatch {
+	il.mux.Lock()
+	defer il.mux.Unlock()
+
+	usage := il.orgs[org]
+	if processed != nil {
+		usage.deferred = usage.deferred[1:]
+	}
+	if len(usage.deferred) == 0 {
+		return nil
+	}
+	return usage.deferred[0]
+}
+
+// inboundSize is the size of a received batch counted against the daily quota - the estimated size of its
+// messages and data, including the encrypted data if the batch was encrypted
+func inboundSize(transport *core.TransportWrapper) (size int64) {
+	for _, msg := range transport.Batch.Payload.Messages {
+		size += msg.EstimateSize(false)
+	}
+	for _, data := range transport.Batch.Payload.Data {
+		size += data.EstimateSize()
+	}
+	if transport.Encrypted != nil {
+		size += int64(len(transport.Encrypted.Data))
+	}
+	return size
+}
+
+// inboundSender resolves the node of a data exchange peer, and the org that owns it, which the inbound limits are applied to
+func (em *eventManager) inboundSender(ctx context.Context, peerID string) (node, org *core.Identity, err error) {
+	node, err = em.identity.FindIdentityForVerifier(ctx, []core.IdentityType{core.IdentityTypeNode}, &core.VerifierRef{
+		Type:  core.VerifierTypeFFDXPeerID,
+		Value: peerID,
+	})
+	if err != nil || node == nil {
+		return nil, nil, err
+	}
+	org, err = em.identity.CachedIdentityLookupByID(ctx, node.Parent)
+	if err != nil || org == nil {
+		return nil, nil, err
+	}
+	return node, org, nil
+}
+
+// checkInboundLimits applies the inbound limits to a private batch received from a peer, either deferring the batch
+// until the org is back within the rate, or quarantining it. Returns true if the batch was deferred or quarantined, in
+// which case it has been (or will be) acknowledged with the supplied function, and should not be processed now.
+func (em *eventManager) checkInboundLimits(peerID string, transport *core.TransportWrapper, ack func(manifest string)) (handled bool, err error) {
+	if em.inbound == nil || !em.inbound.enabled() || em.multiparty == nil || transport.Batch.Namespace != em.namespace.NetworkName {
+		return false, nil
+	}
+
+	var node, org *core.Identity
+	err = em.retry.Do(em.ctx, "resolve batch sender", func(attempt int) (bool, error) {
+		node, org, err = em.inboundSender(em.ctx, peerID)
+		return true, err
+	})
+	if err != nil || org == nil {
+		// Batches from peers that cannot be resolved are rejected when they are processed
+		return false, err
+	}
+
+	messages := len(transport.Batch.Payload.Messages)
+	size := inboundSize(transport)
+	now := time.Now()
+	wait, throttleStarted, reason := em.inbound.take(org.DID, messages, size, now)
+	if reason != "" {
+		if err := em.quarantineBatch(peerID, node, org, transport, messages, size, reason); err != nil {
+			return true, err
+		}
+		// The batch is held for an administrator to release, so is not acknowledged as processed
+		ack("")
+		return true, nil
+	}
+
+	if throttleStarted {
+		err = em.retry.Do(em.ctx, "sender throttled", func(attempt int) (bool, error) {
+			return true, em.database.InsertEvent(em.ctx, core.NewEvent(core.EventTypeSenderThrottled, em.namespace.Name, org.ID, nil, org.ID.String()))
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// Throttled batches are processed separately for each org, so the data exchange events of other orgs are
+	// not held up behind them
+	deferred, first := em.inbound.deferBatch(org.DID, &throttledBatch{
+		peerID:    peerID,
+		transport: transport,
+		releaseAt: now.Add(wait),
+		ack:       ack,
+	})
+	if deferred {
+		log.L(em.ctx).Warnf("Throttling batch '%s' from org '%s' for %s", transport.Batch.ID, org.DID, wait)
+		if first {
+			go em.processDeferredBatches(org.DID)
+		}
+	}
+	return deferred, nil
+}
+
+// processDeferredBatches processes the batches held for an org in order, each once its wait is over, until none are left
+func (em *eventManager) processDeferredBatches(org string) {
+	for batch := em.inbound.nextDeferred(org, nil); batch != nil; batch = em.inbound.nextDeferred(org, batch) {
+		select {
+		case <-time.After(time.Until(batch.releaseAt)):
+		case <-em.ctx.Done():
+			log.L(em.ctx).Debugf("Throttled batch processor for org '%s' exiting", org)
+			return
+		}
+		manifest, err := em.receivedBatch(batch.peerID, batch.transport)
+		if err != nil {
+			// We do NOT ack here as we broke out of the retry
+			return
+		}
+		batch.ack(manifest)
+	}
+}
+
+func (em *eventManager) quarantineBatch(peerID string, node, org *core.Identity, transport *core.TransportWrapper, messages int, size int64, reason core.QuarantineReason) error {
+	quarantined := &core.QuarantinedBatch{
+		ID:        fftypes.NewUUID(),
+		Namespace: em.namespace.Name,
+		Org:       org.DID,
+		Node:      node.ID,
+		Peer:      peerID,
+		Batch:     transport.Batch.ID,
+		Reason:    reason,
+		Messages:  messages,
+		Size:      size,
+		Transport: transport,
+	}
+	err := em.retry.Do(em.ctx, "quarantine batch", func(attempt int) (bool, error) {
+		return true, em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
+			if err := em.database.InsertQuarantinedBatch(ctx, quarantined); err != nil {
+				return err
+			}
+			event := core.NewEvent(core.EventTypeBatchQuarantined, em.namespace.Name, quarantined.ID, nil, org.ID.String())
+			return em.database.InsertEvent(ctx, event)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	log.L(em.ctx).Warnf("Batch '%s' from org '%s' quarantined as %s (reason=%s)", transport.Batch.ID, org.DID, quarantined.ID, reason)
+	return nil
+}
+
+// ReleaseQuarantinedBatch processes a pending quarantined batch in the same way as when it was received, without
+// applying the inbound limits to it. A batch that could not be decrypted stays pending until the node can decrypt it.
+func (em *eventManager) ReleaseQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) (*core.QuarantinedBatch, error) {
+	if quarantined.State != core.QuarantineStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgQuarantinedBatchNotPending, quarantined.ID, quarantined.State)
+	}
+	valid, err := em.decryptBatch(quarantined.Transport)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, i18n.NewError(ctx, coremsgs.MsgQuarantinedBatchNotDecrypted, quarantined.ID)
+	}
+	if _, err := em.receivedBatch(quarantined.Peer, quarantined.Transport); err != nil {
+		return nil, err
+	}
+	if err := em.updateQuarantineState(ctx, quarantined, core.QuarantineStateReleased); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Released quarantined batch %s from org '%s'", quarantined.ID, quarantined.Org)
+	return quarantined, nil
+}
+
+// DiscardQuarantinedBatch marks a pending quarantined batch as discarded, so it remains on record but is never processed
+func (em *eventManager) DiscardQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) (*core.QuarantinedBatch, error) {
+	if quarantined.State != core.QuarantineStatePending {
+		return nil, i18n.NewError(ctx, coremsgs.MsgQuarantinedBatchNotPending, quarantined.ID, quarantined.State)
+	}
+	if err := em.updateQuarantineState(ctx, quarantined, core.QuarantineStateDiscarded); err != nil {
+		return nil, err
+	}
+	log.L(ctx).Infof("Discarded quarantined batch %s from org '%s'", quarantined.ID, quarantined.Org)
+	return quarantined, nil
+}
+
+// updateQuarantineState moves a quarantined batch out of the pending state, failing if a concurrent request already did
+func (em *eventManager) updateQuarantineState(ctx context.Context, quarantined *core.QuarantinedBatch, state core.QuarantineState) error {
+	fb := database.QuarantineQueryFactory.NewFilter(ctx)
+	updated, err := em.database.UpdateQuarantinedBatch(ctx, em.namespace.Name, quarantined.ID,
+		fb.Eq("state", core.QuarantineStatePending),
+		database.QuarantineQueryFactory.NewUpdate(ctx).Set("state", state))
+	if err != nil {
+		return err
+	}
+	if !updated {
+		current, err := em.database.GetQuarantinedBatchByID(ctx, em.namespace.Name, quarantined.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return i18n.NewError(ctx, coremsgs.MsgQuarantinedBatchNotPending, quarantined.ID, current.State)
+	}
+	quarantined.State = state
+	quarantined.Updated = fftypes.Now()
+	return nil
+}
@@ -0,0 +1,510 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestInboundLimits(messagesPerSecond float64, burst, dailyQuota int64, quarantine bool) *inboundLimits {
+	return &inboundLimits{
+		messagesPerSecond: messagesPerSecond,
+		burst:             float64(burst),
+		dailyQuota:        dailyQuota,
+		quarantine:        quarantine,
+		orgs:              make(map[string]*inboundUsage),
+	}
+}
+
+func noAck(manifest string) {}
+
+func mockInboundSender(em *testEventManager) (*core.Identity, *core.Identity) {
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	em.mim.On("FindIdentityForVerifier", em.ctx, []core.IdentityType{core.IdentityTypeNode}, &core.VerifierRef{
+		Type:  core.VerifierTypeFFDXPeerID,
+		Value: "peer1",
+	}).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupByID", em.ctx, org1.ID).Return(org1, nil)
+	return node1, org1
+}
+
+func TestNewInboundLimits(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingInboundMessagesPerSecond, 2.5)
+	config.Set(coreconfig.PrivateMessagingInboundDailyQuota, "1Mb")
+	config.Set(coreconfig.PrivateMessagingInboundAction, "quarantine")
+	il, err := newInboundLimits(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, il.enabled())
+	assert.True(t, il.quarantine)
+	assert.Equal(t, float64(3), il.burst)
+	assert.Equal(t, int64(1024*1024), il.dailyQuota)
+}
+
+func TestNewInboundLimitsBadAction(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingInboundAction, "drop")
+	_, err := newInboundLimits(context.Background())
+	assert.Regexp(t, "FF10637.*drop", err)
+}
+
+func TestInboundLimitsThrottle(t *testing.T) {
+	il := newTestInboundLimits(10, 10, 0, false)
+	now := time.Now()
+
+	wait, started, reason := il.take("org1", 10, 100, now)
+	assert.Zero(t, wait)
+	assert.False(t, started)
+	assert.Empty(t, reason)
+
+	wait, started, reason = il.take("org1", 5, 100, now)
+	assert.Equal(t, 500*time.Millisecond, wait)
+	assert.True(t, started)
+	assert.Empty(t, reason)
+
+	// Later batches wait behind the throttled one, without starting a new throttle
+	wait, started, _ = il.take("org1", 5, 100, now)
+	assert.Equal(t, time.Second, wait)
+	assert.False(t, started)
+
+	// The debt is capped at one burst, and a batch beyond it is quarantined rather than held
+	wait, _, reason = il.take("org1", 1, 100, now)
+	assert.Zero(t, wait)
+	assert.Equal(t, core.QuarantineReasonRate, reason)
+	assert.Equal(t, float64(-10), il.orgs["org1"].tokens)
+
+	// Other orgs are not affected
+	wait, _, _ = il.take("org2", 10, 100, now)
+	assert.Zero(t, wait)
+
+	// A batch larger than the burst is let through once the bucket is full
+	wait, _, _ = il.take("org1", 100, 100, now.Add(10*time.Second))
+	assert.Zero(t, wait)
+	assert.False(t, il.orgs["org1"].throttled)
+}
+
+func TestInboundLimitsQuarantineRate(t *testing.T) {
+	il := newTestInboundLimits(10, 10, 0, true)
+	now := time.Now()
+
+	_, _, reason := il.take("org1", 10, 100, now)
+	assert.Empty(t, reason)
+
+	wait, started, reason := il.take("org1", 1, 100, now)
+	assert.Zero(t, wait)
+	assert.False(t, started)
+	assert.Equal(t, core.QuarantineReasonRate, reason)
+
+	// The quarantined batch was not counted
+	_, _, reason = il.take("org1", 1, 100, now.Add(100*time.Millisecond))
+	assert.Empty(t, reason)
+	assert.Equal(t, int64(200), il.orgs["org1"].used)
+}
+
+func TestInboundLimitsQuota(t *testing.T) {
+	il := newTestInboundLimits(0, 0, 100, false)
+	now := time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC)
+
+	_, _, reason := il.take("org1", 1, 60, now)
+	assert.Empty(t, reason)
+
+	_, _, reason = il.take("org1", 1, 50, now)
+	assert.Equal(t, core.QuarantineReasonQuota, reason)
+
+	_, _, reason = il.take("org1", 1, 40, now)
+	assert.Empty(t, reason)
+
+	// The quota resets each UTC day
+	_, _, reason = il.take("org1", 1, 50, now.Add(2*time.Hour))
+	assert.Empty(t, reason)
+}
+
+func TestInboundSize(t *testing.T) {
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	size := inboundSize(b)
+	assert.Greater(t, size, int64(0))
+
+	b.Encrypted = &core.EncryptedPayload{Data: make([]byte, 1000)}
+	assert.Equal(t, size+1000, inboundSize(b))
+}
+
+func TestMessageReceiveQuarantined(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(0, 0, 1, false)
+
+	batch, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	node1, org1 := mockInboundSender(em)
+
+	em.mdi.On("InsertQuarantinedBatch", em.ctx, mock.MatchedBy(func(q *core.QuarantinedBatch) bool {
+		return q.Org == org1.DID && q.Node.Equals(node1.ID) && q.Peer == "peer1" && q.Batch.Equals(batch.ID) &&
+			q.Reason == core.QuarantineReasonQuota && q.Messages == 1 && q.Transport == b
+	})).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBatchQuarantined && e.Topic == org1.ID.String()
+	})).Return(nil)
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	mde := newMessageReceived("peer1", b, "")
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	em.mdi.AssertExpectations(t)
+}
+
+func TestMessageReceiveInboundLimitsFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(0, 0, 1, false)
+	em.cancel() // to stop retry
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	em.mim.On("FindIdentityForVerifier", em.ctx, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+	// no ack as we are simulating termination mid retry
+	mde := newMessageReceivedNoAck("peer1", b)
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+}
+
+func TestCheckInboundLimitsDisabled(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	handled, err := em.checkInboundLimits("peer1", b, noAck)
+	assert.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestCheckInboundLimitsSenderUnknown(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(0, 0, 1, false)
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	em.mim.On("FindIdentityForVerifier", em.ctx, mock.Anything, mock.Anything).Return(nil, nil)
+
+	handled, err := em.checkInboundLimits("peer1", b, noAck)
+	assert.NoError(t, err)
+	assert.False(t, handled)
+}
+
+func TestCheckInboundLimitsOrgLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(0, 0, 1, false)
+	em.cancel() // to stop retry
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	org1 := newTestOrg("org1")
+	em.mim.On("FindIdentityForVerifier", em.ctx, mock.Anything, mock.Anything).Return(newTestNode("node1", org1), nil)
+	em.mim.On("CachedIdentityLookupByID", em.ctx, org1.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.checkInboundLimits("peer1", b, noAck)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestCheckInboundLimitsThrottled(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(0.001, 1, 0, false)
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	_, org1 := mockInboundSender(em)
+	em.inbound.orgs[org1.DID] = &inboundUsage{updated: time.Now()}
+
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeSenderThrottled && e.Reference.Equals(org1.ID) && e.Topic == org1.ID.String()
+	})).Return(nil)
+
+	// The batch is deferred, rather than blocking the data exchange callback until it can be processed
+	handled, err := em.checkInboundLimits("peer1", b, noAck)
+	assert.NoError(t, err)
+	assert.True(t, handled)
+	em.inbound.mux.Lock()
+	assert.True(t, em.inbound.orgs[org1.DID].throttled)
+	assert.Len(t, em.inbound.orgs[org1.DID].deferred, 1)
+	em.inbound.mux.Unlock()
+
+	em.mdi.AssertExpectations(t)
+}
+
+func TestCheckInboundLimitsThrottledEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(1000, 1, 0, false)
+	em.cancel() // to stop retry
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	_, org1 := mockInboundSender(em)
+	em.inbound.orgs[org1.DID] = &inboundUsage{updated: time.Now()}
+
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := em.checkInboundLimits("peer1", b, noAck)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestProcessDeferredBatchesInOrder(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(1000, 1, 0, false)
+	em.multiparty = nil // so the batches are ignored when processed, and only the acks are checked
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	em.inbound.orgs["org1"] = &inboundUsage{}
+	acks := make(chan int, 2)
+
+	// The second batch does not need to wait, but is still held behind the first
+	deferred, first := em.inbound.deferBatch("org1", &throttledBatch{
+		peerID:    "peer1",
+		transport: b,
+		releaseAt: time.Now().Add(10 * time.Millisecond),
+		ack:       func(manifest string) { acks <- 1 },
+	})
+	assert.True(t, deferred)
+	assert.True(t, first)
+	deferred, first = em.inbound.deferBatch("org1", &throttledBatch{
+		peerID:    "peer1",
+		transport: b,
+		releaseAt: time.Now(),
+		ack:       func(manifest string) { acks <- 2 },
+	})
+	assert.True(t, deferred)
+	assert.False(t, first)
+
+	// Batches from other orgs are not held
+	em.inbound.orgs["org2"] = &inboundUsage{}
+	deferred, _ = em.inbound.deferBatch("org2", &throttledBatch{releaseAt: time.Now()})
+	assert.False(t, deferred)
+
+	em.processDeferredBatches("org1")
+	assert.Equal(t, 1, <-acks)
+	assert.Equal(t, 2, <-acks)
+	assert.Nil(t, em.inbound.nextDeferred("org1", nil))
+}
+
+func TestProcessDeferredBatchesCancelled(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(0.001, 1, 0, false)
+	em.cancel()
+
+	em.inbound.orgs["org1"] = &inboundUsage{}
+	em.inbound.deferBatch("org1", &throttledBatch{
+		releaseAt: time.Now().Add(time.Hour),
+		ack:       func(manifest string) { assert.Fail(t, "should not be acked") },
+	})
+
+	// The batch is left unacknowledged, so data exchange delivers it again
+	em.processDeferredBatches("org1")
+	assert.NotNil(t, em.inbound.nextDeferred("org1", nil))
+}
+
+func TestCheckInboundLimitsQuarantineFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.inbound = newTestInboundLimits(1, 1, 0, true)
+	em.cancel() // to stop retry
+
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	_, org1 := mockInboundSender(em)
+	em.inbound.orgs[org1.DID] = &inboundUsage{updated: time.Now()}
+
+	em.mdi.On("InsertQuarantinedBatch", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	handled, err := em.checkInboundLimits("peer1", b, noAck)
+	assert.Regexp(t, "FF00154", err)
+	assert.True(t, handled)
+}
+
+func newTestQuarantinedBatch(t *testing.T) *core.QuarantinedBatch {
+	_, b := sampleBatchTransfer(t, core.TransactionTypeBatchPin)
+	return &core.QuarantinedBatch{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Peer:      "peer1",
+		Batch:     b.Batch.ID,
+		State:     core.QuarantineStatePending,
+		Transport: b,
+	}
+}
+
+func TestReleaseQuarantinedBatchOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	qb.Transport.Batch.Namespace = "ns2" // ignored when processed
+	em.mdi.On("UpdateQuarantinedBatch", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(true, nil)
+
+	res, err := em.ReleaseQuarantinedBatch(em.ctx, qb)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateReleased, res.State)
+	assert.NotNil(t, res.Updated)
+}
+
+func TestReleaseQuarantinedBatchNotPending(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	qb.State = core.QuarantineStateDiscarded
+	_, err := em.ReleaseQuarantinedBatch(em.ctx, qb)
+	assert.Regexp(t, "FF10638.*discarded", err)
+}
+
+func TestReleaseQuarantinedBatchProcessFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+
+	qb := newTestQuarantinedBatch(t)
+	qb.Transport.Encrypted = &core.EncryptedPayload{}
+	em.mpm.On("DecryptTransport", em.ctx, qb.Transport).Return(false, fmt.Errorf("pop"))
+
+	_, err := em.ReleaseQuarantinedBatch(em.ctx, qb)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestReleaseQuarantinedBatchDecrypted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	qb.Reason = core.QuarantineReasonDecryption
+	qb.Transport.Batch.Namespace = "ns2" // ignored when processed
+	qb.Transport.Encrypted = &core.EncryptedPayload{}
+	em.mpm.On("DecryptTransport", em.ctx, qb.Transport).Return(true, nil).Run(func(args mock.Arguments) {
+		args[1].(*core.TransportWrapper).Encrypted = nil
+	}).Once()
+	em.mdi.On("UpdateQuarantinedBatch", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(true, nil)
+
+	res, err := em.ReleaseQuarantinedBatch(em.ctx, qb)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateReleased, res.State)
+}
+
+func TestReleaseQuarantinedBatchStillEncrypted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	qb.Reason = core.QuarantineReasonDecryption
+	qb.Transport.Encrypted = &core.EncryptedPayload{}
+	em.mpm.On("DecryptTransport", em.ctx, qb.Transport).Return(false, nil)
+
+	_, err := em.ReleaseQuarantinedBatch(em.ctx, qb)
+	assert.Regexp(t, "FF10694", err)
+	assert.Equal(t, core.QuarantineStatePending, qb.State)
+}
+
+func TestReleaseQuarantinedBatchPersistFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel() // to stop retry
+
+	qb := newTestQuarantinedBatch(t)
+	em.mim.On("FindIdentityForVerifier", em.ctx, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.ReleaseQuarantinedBatch(em.ctx, qb)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestReleaseQuarantinedBatchUpdateFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	qb.Transport.Batch.Namespace = "ns2"
+	em.mdi.On("UpdateQuarantinedBatch", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, fmt.Errorf("pop"))
+
+	_, err := em.ReleaseQuarantinedBatch(em.ctx, qb)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDiscardQuarantinedBatchOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	em.mdi.On("UpdateQuarantinedBatch", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(true, nil)
+
+	res, err := em.DiscardQuarantinedBatch(em.ctx, qb)
+	assert.NoError(t, err)
+	assert.Equal(t, core.QuarantineStateDiscarded, res.State)
+}
+
+func TestDiscardQuarantinedBatchNotPending(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	qb.State = core.QuarantineStateReleased
+	_, err := em.DiscardQuarantinedBatch(em.ctx, qb)
+	assert.Regexp(t, "FF10638.*released", err)
+}
+
+func TestDiscardQuarantinedBatchConcurrentUpdate(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	em.mdi.On("UpdateQuarantinedBatch", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetQuarantinedBatchByID", em.ctx, "ns1", qb.ID).Return(&core.QuarantinedBatch{State: core.QuarantineStateReleased}, nil)
+	_, err := em.DiscardQuarantinedBatch(em.ctx, qb)
+	assert.Regexp(t, "FF10638.*released", err)
+}
+
+func TestDiscardQuarantinedBatchDeleted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	em.mdi.On("UpdateQuarantinedBatch", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetQuarantinedBatchByID", em.ctx, "ns1", qb.ID).Return(nil, nil)
+	_, err := em.DiscardQuarantinedBatch(em.ctx, qb)
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDiscardQuarantinedBatchLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	qb := newTestQuarantinedBatch(t)
+	em.mdi.On("UpdateQuarantinedBatch", em.ctx, "ns1", qb.ID, mock.Anything, mock.Anything).Return(false, nil)
+	em.mdi.On("GetQuarantinedBatchByID", em.ctx, "ns1", qb.ID).Return(nil, fmt.Errorf("pop"))
+	_, err := em.DiscardQuarantinedBatch(em.ctx, qb)
+	assert.EqualError(t, err, "pop")
+}
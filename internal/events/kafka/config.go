@@ -0,0 +1,38 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	// KafkaConfigTopic is the topic events are written to, for subscriptions that do not set their own
+	KafkaConfigTopic = "topic"
+	// KafkaConfigPartitionKey is the field of each event used as the record key, for subscriptions that do not set their own
+	KafkaConfigPartitionKey = "partitionKey"
+
+	defaultTopic        = "firefly-events"
+	defaultPartitionKey = "topic"
+)
+
+func (k *Kafka) InitConfig(config config.Section) {
+	ffresty.InitConfig(config)
+	config.AddKnownKey(KafkaConfigTopic, defaultTopic)
+	config.AddKnownKey(KafkaConfigPartitionKey, defaultPartitionKey)
+}
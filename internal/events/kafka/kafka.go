@@ -0,0 +1,270 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// Kafka delivers events to Kafka topics, through the Kafka REST Proxy (v2 API). An event is only
+// acknowledged once the proxy confirms the offset the record was committed at, and is otherwise
+// rejected so that it is redelivered. Delivery is at-least-once, and consumers can use the event ID
+// in each record to discard any duplicates written before a failure.
+type Kafka struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	client       *resty.Client
+	connID       string
+	topic        string
+	partitionKey PartitionKey
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+// PartitionKey is the field of an event that is used as the Kafka record key, so all events with the same
+// value are written to the same partition in order
+type PartitionKey string
+
+const (
+	// PartitionKeyTopic keys records on the topic of the event
+	PartitionKeyTopic PartitionKey = "topic"
+	// PartitionKeyGroup keys records on the privacy group of the message. Records for events without a group have no key
+	PartitionKeyGroup PartitionKey = "group"
+	// PartitionKeyAuthor keys records on the author of the message. Records for events without an author have no key
+	PartitionKeyAuthor PartitionKey = "author"
+)
+
+// Kafka topic names can only contain these characters, up to a maximum of 249
+var topicRegex = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+
+type produceRecord struct {
+	Key   *string     `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+type recordValue struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+type produceRequest struct {
+	Records []*produceRecord `json:"records"`
+}
+
+type produceOffset struct {
+	Partition int32   `json:"partition"`
+	Offset    int64   `json:"offset"`
+	ErrorCode *int    `json:"error_code"`
+	Error     *string `json:"error"`
+}
+
+type produceResponse struct {
+	Offsets []*produceOffset `json:"offsets"`
+}
+
+func (k *Kafka) Name() string { return "kafka" }
+
+func (k *Kafka) Init(ctx context.Context, config config.Section) (err error) {
+	if config.GetString(ffresty.HTTPConfigURL) == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, config.Resolve(ffresty.HTTPConfigURL), "kafka")
+	}
+	partitionKey := PartitionKey(config.GetString(KafkaConfigPartitionKey))
+	if err := validatePartitionKey(ctx, partitionKey); err != nil {
+		return err
+	}
+	topic := config.GetString(KafkaConfigTopic)
+	if err := validateTopic(ctx, topic); err != nil {
+		return err
+	}
+
+	client, err := ffresty.New(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	connID := fftypes.ShortID()
+	*k = Kafka{
+		ctx:          log.WithLogField(ctx, "kafka", connID),
+		capabilities: &events.Capabilities{CloudEvents: true},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		client:       client,
+		connID:       connID,
+		topic:        topic,
+		partitionKey: partitionKey,
+	}
+	return nil
+}
+
+func (k *Kafka) SetHandler(namespace string, handler events.Callbacks) error {
+	k.callbacks.writeLock.Lock()
+	defer k.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(k.callbacks.handlers, namespace)
+		return nil
+	}
+	k.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(k.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (k *Kafka) Capabilities() *events.Capabilities {
+	return k.capabilities
+}
+
+func validateTopic(ctx context.Context, topic string) error {
+	if !topicRegex.MatchString(topic) {
+		return i18n.NewError(ctx, coremsgs.MsgKafkaInvalidTopic, topic)
+	}
+	return nil
+}
+
+func validatePartitionKey(ctx context.Context, partitionKey PartitionKey) error {
+	switch partitionKey {
+	case PartitionKeyTopic, PartitionKeyGroup, PartitionKeyAuthor:
+		return nil
+	default:
+		return i18n.NewError(ctx, coremsgs.MsgKafkaInvalidPartitionKey, partitionKey)
+	}
+}
+
+// subscriptionTarget returns the topic and partition key for a subscription, falling back to the plugin defaults
+func (k *Kafka) subscriptionTarget(options *core.SubscriptionOptions) (topic string, partitionKey PartitionKey) {
+	topic = options.TransportOptions().GetString("topic")
+	if topic == "" {
+		topic = k.topic
+	}
+	partitionKey = PartitionKey(options.TransportOptions().GetString("partitionKey"))
+	if partitionKey == "" {
+		partitionKey = k.partitionKey
+	}
+	return topic, partitionKey
+}
+
+func (k *Kafka) ValidateOptions(options *core.SubscriptionOptions) error {
+	topic, partitionKey := k.subscriptionTarget(options)
+	if err := validateTopic(k.ctx, topic); err != nil {
+		return err
+	}
+	return validatePartitionKey(k.ctx, partitionKey)
+}
+
+func recordKey(partitionKey PartitionKey, event *core.EventDelivery) *string {
+	var key string
+	switch partitionKey {
+	case PartitionKeyGroup:
+		if event.Message != nil && event.Message.Header.Group != nil {
+			key = event.Message.Header.Group.String()
+		}
+	case PartitionKeyAuthor:
+		if event.Message != nil {
+			key = event.Message.Header.Author
+		}
+	default:
+		key = event.Topic
+	}
+	if key == "" {
+		return nil
+	}
+	return &key
+}
+
+func (k *Kafka) produce(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	topic, partitionKey := k.subscriptionTarget(&sub.Options)
+	value := &recordValue{EventDelivery: event}
+	if sub.Options.WithData != nil && *sub.Options.WithData {
+		value.Data = data
+	}
+	var recordBody interface{} = value
+	if sub.Options.Format == core.SubOptsFormatCloudEvents {
+		recordBody = core.NewCloudEvent(event, value)
+	}
+
+	var res produceResponse
+	log.L(k.ctx).Debugf("Kafka-> topic %s event %s on subscription %s", topic, event.ID, sub.ID)
+	resp, err := k.client.R().
+		SetContext(k.ctx).
+		SetHeader("Content-Type", "application/vnd.kafka.json.v2+json").
+		SetHeader("Accept", "application/vnd.kafka.v2+json").
+		SetBody(&produceRequest{
+			Records: []*produceRecord{{Key: recordKey(partitionKey, event), Value: recordBody}},
+		}).
+		SetResult(&res).
+		Post("/topics/" + url.PathEscape(topic))
+	if err != nil || !resp.IsSuccess() {
+		return ffresty.WrapRestErr(k.ctx, resp, err, coremsgs.MsgKafkaRESTErr)
+	}
+	if len(res.Offsets) != 1 {
+		return i18n.NewError(k.ctx, coremsgs.MsgKafkaProduceFailed, topic, "no offset returned")
+	}
+	offset := res.Offsets[0]
+	if offset.ErrorCode != nil || offset.Error != nil {
+		var reason string
+		if offset.Error != nil {
+			reason = *offset.Error
+		}
+		return i18n.NewError(k.ctx, coremsgs.MsgKafkaProduceFailed, topic, reason)
+	}
+	log.L(k.ctx).Infof("Kafka<- topic %s event %s on subscription %s committed at partition=%d offset=%d", topic, event.ID, sub.ID, offset.Partition, offset.Offset)
+	return nil
+}
+
+func (k *Kafka) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(k.ctx, coremsgs.MsgBatchDeliveryNotSupported, k.Name())
+}
+
+func (k *Kafka) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	response := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+	if err := k.produce(sub, event, data); err != nil {
+		log.L(k.ctx).Errorf("Failed to deliver event %s to Kafka: %s", event.ID, err)
+		response.Rejected = true
+		response.Info = err.Error()
+	}
+	k.callbacks.writeLock.Lock()
+	cb, ok := k.callbacks.handlers[sub.Namespace]
+	k.callbacks.writeLock.Unlock()
+	if ok {
+		cb.DeliveryResponse(connID, response)
+	}
+	return nil
+}
+
+func (k *Kafka) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
@@ -0,0 +1,336 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestKafka(t *testing.T) (k *Kafka, cbs *eventsmocks.Callbacks, done func()) {
+	coreconfig.Reset()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+
+	cbs = &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	k = &Kafka{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	utConfig := config.RootSection("ut.kafka")
+	k.InitConfig(utConfig)
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utConfig.Set(ffresty.HTTPCustomClient, mockedClient)
+	err := k.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	err = k.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.Equal(t, "kafka", k.Name())
+	assert.NotNil(t, k.Capabilities())
+	return k, cbs, func() {
+		cancelCtx()
+		httpmock.DeactivateAndReset()
+		cbs.AssertExpectations(t)
+	}
+}
+
+func newTestKafkaSubscription(topic, partitionKey string) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	if topic != "" {
+		sub.Options.TransportOptions()["topic"] = topic
+	}
+	if partitionKey != "" {
+		sub.Options.TransportOptions()["partitionKey"] = partitionKey
+	}
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:    fftypes.NewUUID(),
+				Type:  core.EventTypeMessageConfirmed,
+				Topic: "topic1",
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+func TestInitMissingURL(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	utConfig := config.RootSection("ut.kafka")
+	k.InitConfig(utConfig)
+	err := k.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitBadPartitionKey(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	utConfig := config.RootSection("ut.kafka")
+	k.InitConfig(utConfig)
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utConfig.Set(KafkaConfigPartitionKey, "wrong")
+	err := k.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10523.*wrong", err)
+}
+
+func TestInitBadTopic(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	utConfig := config.RootSection("ut.kafka")
+	k.InitConfig(utConfig)
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utConfig.Set(KafkaConfigTopic, "bad topic")
+	err := k.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10522.*bad topic", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	k := &Kafka{}
+	utConfig := config.RootSection("ut.kafka")
+	k.InitConfig(utConfig)
+	utConfig.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	tlsConfig := utConfig.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCAFile, "BADCA")
+	err := k.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestSetHandlerRemove(t *testing.T) {
+	k, _, done := newTestKafka(t)
+	defer done()
+
+	err := k.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, k.callbacks.handlers)
+}
+
+func TestValidateOptions(t *testing.T) {
+	k, _, done := newTestKafka(t)
+	defer done()
+
+	sub, _ := newTestKafkaSubscription("orders", "author")
+	assert.NoError(t, k.ValidateOptions(&sub.Options))
+
+	sub, _ = newTestKafkaSubscription("orders/1", "")
+	assert.Regexp(t, "FF10522", k.ValidateOptions(&sub.Options))
+
+	sub, _ = newTestKafkaSubscription("", "tag")
+	assert.Regexp(t, "FF10523", k.ValidateOptions(&sub.Options))
+}
+
+func TestDeliveryRequestDefaultTopic(t *testing.T) {
+	k, cbs, done := newTestKafka(t)
+	defer done()
+
+	sub, event := newTestKafkaSubscription("", "")
+	yes := true
+	sub.Options.WithData = &yes
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":"b"}`)}}
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/firefly-events",
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "application/vnd.kafka.json.v2+json", req.Header.Get("Content-Type"))
+			var body struct {
+				Records []struct {
+					Key   *string            `json:"key"`
+					Value fftypes.JSONObject `json:"value"`
+				} `json:"records"`
+			}
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Len(t, body.Records, 1)
+			assert.Equal(t, "topic1", *body.Records[0].Key)
+			value := body.Records[0].Value
+			assert.Equal(t, event.ID.String(), value["id"])
+			assert.Len(t, value["data"], 1)
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"offsets": []fftypes.JSONObject{{"partition": 1, "offset": 100}},
+			})(req)
+		})
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Return()
+
+	err := k.DeliveryRequest(k.connID, sub, event, data)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestCloudEvents(t *testing.T) {
+	k, cbs, done := newTestKafka(t)
+	defer done()
+
+	sub, event := newTestKafkaSubscription("", "")
+	sub.Options.Format = core.SubOptsFormatCloudEvents
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/firefly-events",
+		func(req *http.Request) (*http.Response, error) {
+			var body struct {
+				Records []struct {
+					Value core.CloudEvent `json:"value"`
+				} `json:"records"`
+			}
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Len(t, body.Records, 1)
+			ce := body.Records[0].Value
+			assert.Equal(t, core.CloudEventsSpecVersion, ce.SpecVersion)
+			assert.Equal(t, event.ID.String(), ce.ID)
+			assert.Equal(t, "io.hyperledger.firefly."+string(event.Type), ce.Type)
+			assert.Equal(t, event.ID.String(), ce.Data.(map[string]interface{})["id"])
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"offsets": []fftypes.JSONObject{{"partition": 1, "offset": 100}},
+			})(req)
+		})
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Return()
+
+	err := k.DeliveryRequest(k.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestSubscriptionTopicAndKeys(t *testing.T) {
+	k, cbs, done := newTestKafka(t)
+	defer done()
+
+	group := fftypes.NewRandB32()
+	groupSub, event := newTestKafkaSubscription("orders", "group")
+	authorSub, _ := newTestKafkaSubscription("orders", "author")
+	event.Message = &core.Message{
+		Header: core.MessageHeader{
+			SignerRef: core.SignerRef{Author: "did:firefly:org/org1"},
+			Group:     group,
+		},
+	}
+	keys := make(chan *string, 3)
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/orders",
+		func(req *http.Request) (*http.Response, error) {
+			var body produceRequest
+			_ = json.NewDecoder(req.Body).Decode(&body)
+			keys <- body.Records[0].Key
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"offsets": []fftypes.JSONObject{{"partition": 0, "offset": 1}},
+			})(req)
+		})
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected
+	})).Return()
+
+	err := k.DeliveryRequest(k.connID, groupSub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, group.String(), *<-keys)
+
+	err = k.DeliveryRequest(k.connID, authorSub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/org1", *<-keys)
+
+	event.Message = nil
+	err = k.DeliveryRequest(k.connID, groupSub, event, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, <-keys)
+}
+
+func TestDeliveryRequestRESTError(t *testing.T) {
+	k, cbs, done := newTestKafka(t)
+	defer done()
+
+	sub, event := newTestKafkaSubscription("", "")
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/firefly-events",
+		httpmock.NewStringResponder(500, `{"error_code":50001,"message":"pop"}`))
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && r.Rejected && r.Info != ""
+	})).Return()
+
+	err := k.DeliveryRequest(k.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestRecordError(t *testing.T) {
+	k, cbs, done := newTestKafka(t)
+	defer done()
+
+	sub, event := newTestKafkaSubscription("", "")
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/firefly-events",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"offsets": []fftypes.JSONObject{{"error_code": 2, "error": "pop"}},
+		}))
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected && r.Info == fmt.Sprintf("FF10525: Failed to write event to Kafka topic '%s': pop", "firefly-events")
+	})).Return()
+
+	err := k.DeliveryRequest(k.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestNoOffset(t *testing.T) {
+	k, cbs, done := newTestKafka(t)
+	defer done()
+
+	sub, event := newTestKafkaSubscription("", "")
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/firefly-events",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{}))
+	cbs.On("DeliveryResponse", k.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return()
+
+	err := k.DeliveryRequest(k.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestNoHandler(t *testing.T) {
+	k, _, done := newTestKafka(t)
+	defer done()
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/topics/firefly-events",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"offsets": []fftypes.JSONObject{{"partition": 0, "offset": 1}},
+		}))
+	sub, event := newTestKafkaSubscription("", "")
+	sub.Namespace = "ns2"
+
+	err := k.DeliveryRequest(k.connID, sub, event, nil)
+	assert.NoError(t, err)
+	k.NamespaceRestarted("ns1", time.Now())
+}
@@ -0,0 +1,109 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// deferConfirm returns true if a message that is ready to be confirmed should be held for the application instead.
+// Definitions, group init and recall messages are never held, as they are processed by the system rather than by applications.
+func (ag *aggregator) deferConfirm(msg *core.Message) bool {
+	if msg.Header.Type != core.MessageTypeBroadcast && msg.Header.Type != core.MessageTypePrivate ||
+		msg.Header.Tag == core.SystemTagRecallMessage {
+		return false
+	}
+	return ag.deferred.AppliesTo(msg.Header.Topics)
+}
+
+// holdMessage emits a pending_app event on each topic of a message that is held for the application to accept or reject
+func (ag *aggregator) holdMessage(msg *core.Message, tx *fftypes.UUID, state *batchState) {
+	state.AddFinalize(func(ctx context.Context) error {
+		for _, topic := range msg.Header.Topics {
+			event := core.NewEvent(core.EventTypeMessagePendingApp, ag.namespace, msg.Header.ID, tx, topic)
+			event.Correlator = msg.Header.CID
+			if err := ag.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AckMessage records the decision of the application on a message held in the pending_app state, and confirms or
+// rejects the message with the usual events
+func (em *eventManager) AckMessage(ctx context.Context, msg *core.Message, outcome core.MessageAckOutcome, input *core.MessageAckInput) (*core.MessageAck, error) {
+	if msg.State != core.MessageStatePendingApp {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotPendingApp, msg.Header.ID, msg.State)
+	}
+
+	ack := &core.MessageAck{
+		ID:        fftypes.NewUUID(),
+		Namespace: em.namespace.Name,
+		Message:   msg.Header.ID,
+		Outcome:   outcome,
+		Reason:    input.Reason,
+	}
+	newState := core.MessageStateConfirmed
+	eventType := core.EventTypeMessageConfirmed
+	rejectReason := ""
+	if outcome == core.MessageAckOutcomeRejected {
+		newState = core.MessageStateRejected
+		eventType = core.EventTypeMessageRejected
+		rejectReason = i18n.NewError(ctx, coremsgs.MsgMessageRejectedByApp, input.Reason).Error()
+	}
+
+	confirmed := fftypes.Now()
+	err := em.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		// Each message can only be acked once, so a concurrent request for the same message fails the insert
+		if err := em.database.InsertMessageAck(ctx, ack); err != nil {
+			return err
+		}
+		update := database.MessageQueryFactory.NewUpdate(ctx).
+			Set("state", newState).
+			Set("confirmed", confirmed).
+			Set("rejectreason", rejectReason)
+		if err := em.database.UpdateMessage(ctx, em.namespace.Name, msg.Header.ID, update); err != nil {
+			return err
+		}
+		for _, topic := range msg.Header.Topics {
+			event := core.NewEvent(eventType, em.namespace.Name, msg.Header.ID, msg.TransactionID, topic)
+			event.Correlator = msg.Header.CID
+			if err := em.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	em.data.UpdateMessageStateIfCached(ctx, msg.Header.ID, newState, confirmed, rejectReason)
+	if em.metrics.IsMetricsEnabled() {
+		em.metrics.MessageConfirmed(msg, eventType)
+	}
+	log.L(ctx).Infof("Message '%s' %s by the application", msg.Header.ID, outcome)
+	return ack, nil
+}
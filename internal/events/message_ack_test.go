@@ -0,0 +1,223 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAggregationDeferredConfirm(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.deferred = &core.DeferredConfirmPolicy{Topics: map[string]bool{"orders": true}}
+
+	msg := newTestSequencedMessage("orders", 0)
+	msg.Header.SignerRef = core.SignerRef{Key: "0x12345", Author: "did:firefly:org/org1"}
+	msg.Data = core.DataRefs{{ID: fftypes.NewUUID()}}
+	manifest := &core.BatchManifest{
+		ID: fftypes.NewUUID(),
+		TX: core.TransactionRef{ID: fftypes.NewUUID()},
+	}
+	pin := &core.Pin{Sequence: 10001, Hash: broadcastContext("orders"), Signer: "0x12345"}
+
+	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(newTestOrg("org1"), nil)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg.Header.ID, data.CRORequirePublicBlobRefs).Return(msg, core.DataArray{}, true, nil)
+	ag.mdi.On("GetPins", ag.ctx, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Reference.Equals(msg.Header.ID) && e.Type == core.EventTypeMessagePendingApp && e.Topic == "orders"
+	})).Return(nil).Once()
+	ag.mdi.On("UpdatePins", ag.ctx, "ns1", mock.Anything, mock.Anything).Return(nil)
+	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, msg.Header.ID, core.MessageStatePendingApp, (*fftypes.FFTime)(nil), "").Return()
+	ag.mdi.On("UpdateMessages", ag.ctx, "ns1", mock.Anything, mock.Anything).Return(nil)
+
+	err := ag.processMessage(ag.ctx, manifest, pin, 0, &core.MessageManifestEntry{MessageRef: core.MessageRef{ID: msg.Header.ID}}, &core.BatchPersisted{}, bs)
+	assert.NoError(t, err)
+	assert.Len(t, bs.dispatchedMessages, 1)
+	assert.Equal(t, core.MessageStatePendingApp, bs.dispatchedMessages[0].newState)
+
+	err = bs.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+}
+
+func TestDeferConfirm(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+
+	msg := newTestSequencedMessage("topic1", 0)
+	assert.False(t, ag.deferConfirm(msg))
+
+	ag.deferred = &core.DeferredConfirmPolicy{}
+	assert.True(t, ag.deferConfirm(msg))
+
+	msg.Header.Tag = core.SystemTagRecallMessage
+	assert.False(t, ag.deferConfirm(msg))
+
+	msg.Header.Tag = ""
+	msg.Header.Type = core.MessageTypeDefinition
+	assert.False(t, ag.deferConfirm(msg))
+}
+
+func TestAdvanceTopicSequenceReleaseDeferred(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+	ag.deferred = &core.DeferredConfirmPolicy{}
+
+	msg := newTestSequencedMessage("topic1", 1)
+	parked := newTestSequencedMessage("topic1", 2)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{parked}, nil, nil).Once()
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil).Once()
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Reference.Equals(parked.Header.ID) && e.Type == core.EventTypeMessagePendingApp
+	})).Return(nil).Once()
+
+	_, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	err = ag.advanceTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	assert.Len(t, bs.dispatchedMessages, 1)
+	assert.Equal(t, parked.Header.ID, bs.dispatchedMessages[0].msgID)
+	assert.Equal(t, core.MessageStatePendingApp, bs.dispatchedMessages[0].newState)
+
+	err = bs.BatchState.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+}
+
+func TestHoldMessageEventFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg := newTestSequencedMessage("topic1", 0)
+	ag.mdi.On("InsertEvent", ag.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	ag.holdMessage(msg, msg.TransactionID, bs)
+	err := bs.BatchState.RunFinalize(ag.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func newTestPendingAppMessage() *core.Message {
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:     fftypes.NewUUID(),
+			CID:    fftypes.NewUUID(),
+			Type:   core.MessageTypeBroadcast,
+			Topics: fftypes.FFStringArray{"topic1", "topic2"},
+		},
+		State:         core.MessageStatePendingApp,
+		TransactionID: fftypes.NewUUID(),
+	}
+}
+
+func TestAckMessageAccept(t *testing.T) {
+	em := newTestEventManagerWithMetrics(t)
+	defer em.cleanup(t)
+
+	msg := newTestPendingAppMessage()
+	em.mdi.On("InsertMessageAck", em.ctx, mock.MatchedBy(func(ack *core.MessageAck) bool {
+		return ack.Message.Equals(msg.Header.ID) && ack.Outcome == core.MessageAckOutcomeAccepted && ack.Reason == "checked"
+	})).Return(nil)
+	em.mdi.On("UpdateMessage", em.ctx, "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Reference.Equals(msg.Header.ID) && e.Type == core.EventTypeMessageConfirmed &&
+			e.Correlator.Equals(msg.Header.CID) && e.Transaction.Equals(msg.TransactionID)
+	})).Return(nil).Twice()
+	em.mdm.On("UpdateMessageStateIfCached", em.ctx, msg.Header.ID, core.MessageStateConfirmed, mock.Anything, "").Return()
+	em.mmi.On("MessageConfirmed", msg, core.EventTypeMessageConfirmed).Return()
+
+	ack, err := em.AckMessage(em.ctx, msg, core.MessageAckOutcomeAccepted, &core.MessageAckInput{Reason: "checked"})
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1", ack.Namespace)
+	assert.Equal(t, core.MessageAckOutcomeAccepted, ack.Outcome)
+}
+
+func TestAckMessageReject(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	msg := newTestPendingAppMessage()
+	em.mdi.On("InsertMessageAck", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("UpdateMessage", em.ctx, "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Reference.Equals(msg.Header.ID) && e.Type == core.EventTypeMessageRejected
+	})).Return(nil).Twice()
+	em.mdm.On("UpdateMessageStateIfCached", em.ctx, msg.Header.ID, core.MessageStateRejected, mock.Anything, mock.MatchedBy(func(reason string) bool {
+		return reason == "FF10669: Message rejected by the application: over limit"
+	})).Return()
+
+	ack, err := em.AckMessage(em.ctx, msg, core.MessageAckOutcomeRejected, &core.MessageAckInput{Reason: "over limit"})
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageAckOutcomeRejected, ack.Outcome)
+	assert.Equal(t, "over limit", ack.Reason)
+}
+
+func TestAckMessageNotPending(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	msg := newTestPendingAppMessage()
+	msg.State = core.MessageStateConfirmed
+	_, err := em.AckMessage(em.ctx, msg, core.MessageAckOutcomeAccepted, &core.MessageAckInput{})
+	assert.Regexp(t, "FF10668.*confirmed", err)
+}
+
+func TestAckMessageInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	msg := newTestPendingAppMessage()
+	em.mdi.On("InsertMessageAck", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := em.AckMessage(em.ctx, msg, core.MessageAckOutcomeAccepted, &core.MessageAckInput{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestAckMessageUpdateFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	msg := newTestPendingAppMessage()
+	em.mdi.On("InsertMessageAck", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("UpdateMessage", em.ctx, "ns1", msg.Header.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := em.AckMessage(em.ctx, msg, core.MessageAckOutcomeAccepted, &core.MessageAckInput{})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestAckMessageEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	msg := newTestPendingAppMessage()
+	em.mdi.On("InsertMessageAck", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("UpdateMessage", em.ctx, "ns1", msg.Header.ID, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := em.AckMessage(em.ctx, msg, core.MessageAckOutcomeAccepted, &core.MessageAckInput{})
+	assert.Regexp(t, "pop", err)
+}
@@ -0,0 +1,140 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// messageSLA tracks the time taken for the messages sent by this node to be confirmed, against the SLA of their topics.
+// The latency is measured on the clock of this node, from the time the message was created to the time this node
+// confirms it, or receives a delivery receipt for it from another node.
+type messageSLA struct {
+	namespace string
+	policy    *core.SLAPolicy
+	database  database.Plugin
+	identity  identity.Manager
+	metrics   metrics.Manager
+}
+
+func newMessageSLA(ns *core.Namespace, di database.Plugin, im identity.Manager, mm metrics.Manager) *messageSLA {
+	return &messageSLA{
+		namespace: ns.Name,
+		policy:    ns.SLA,
+		database:  di,
+		identity:  im,
+		metrics:   mm,
+	}
+}
+
+func (ms *messageSLA) enabled() bool {
+	return ms.policy != nil || ms.metrics.IsMetricsEnabled()
+}
+
+// nodeOrg returns the DID of the org that owns a node, or an empty string if the node is not known
+func (ms *messageSLA) nodeOrg(ctx context.Context, nodeID *fftypes.UUID) (string, error) {
+	if nodeID == nil {
+		return "", nil
+	}
+	node, err := ms.identity.CachedIdentityLookupByID(ctx, nodeID)
+	if err != nil || node == nil || node.Parent == nil {
+		return "", err
+	}
+	org, err := ms.identity.CachedIdentityLookupByID(ctx, node.Parent)
+	if err != nil || org == nil {
+		return "", err
+	}
+	return org.DID, nil
+}
+
+// check records the latency of a message confirmed by a node, and returns a breach if the latency exceeded the SLA of the message
+func (ms *messageSLA) check(ctx context.Context, breachType core.SLABreachType, msg *core.Message, nodeID *fftypes.UUID, confirmed *fftypes.FFTime) (*core.SLABreach, error) {
+	if msg.Header.Created == nil || confirmed == nil {
+		return nil, nil
+	}
+	org, err := ms.nodeOrg(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	latency := time.Time(*confirmed).Sub(time.Time(*msg.Header.Created))
+	if ms.metrics.IsMetricsEnabled() {
+		ms.metrics.MessageConfirmLatency(ms.namespace, org, latency)
+	}
+
+	topic, sla := ms.policy.ForTopics(msg.Header.Topics)
+	if sla == 0 || latency <= sla {
+		return nil, nil
+	}
+	log.L(ctx).Warnf("Message '%s' on topic '%s' was %s by node '%s' after %s, exceeding its SLA of %s", msg.Header.ID, topic, breachType, nodeID, latency, sla)
+	return &core.SLABreach{
+		ID:        fftypes.NewUUID(),
+		Namespace: ms.namespace,
+		Type:      breachType,
+		Message:   msg.Header.ID,
+		Topic:     topic,
+		Org:       org,
+		Node:      nodeID,
+		SLA:       fftypes.FFDuration(sla),
+		Latency:   fftypes.FFDuration(latency),
+	}, nil
+}
+
+func (ms *messageSLA) recordBreach(ctx context.Context, breach *core.SLABreach, tx *fftypes.UUID) error {
+	if err := ms.database.InsertSLABreach(ctx, breach); err != nil {
+		return err
+	}
+	event := core.NewEvent(core.EventTypeSLABreached, ms.namespace, breach.ID, tx, breach.Topic)
+	event.Correlator = breach.Message
+	return ms.database.InsertEvent(ctx, event)
+}
+
+// checkConfirmSLA checks the time taken for this node to confirm a message it sent. Messages sent by this node are
+// ready or sent until they are confirmed, where messages received from other nodes are pending.
+func (ag *aggregator) checkConfirmSLA(ctx context.Context, msg *core.Message, node, tx *fftypes.UUID, state *batchState) error {
+	if (msg.State != core.MessageStateReady && msg.State != core.MessageStateSent) || !ag.sla.enabled() {
+		return nil
+	}
+	breach, err := ag.sla.check(ctx, core.SLABreachTypeConfirmed, msg, node, fftypes.Now())
+	if err != nil || breach == nil {
+		return err
+	}
+	state.AddFinalize(func(ctx context.Context) error {
+		return ag.sla.recordBreach(ctx, breach, tx)
+	})
+	return nil
+}
+
+// checkDeliverySLA checks the time taken for the node of another member to confirm a private message sent by this node,
+// using the time the delivery receipt is received by this node
+func (em *eventManager) checkDeliverySLA(ctx context.Context, msg *core.Message, receipt *core.MessageReceipt) error {
+	if receipt.Type != core.MessageReceiptTypeDelivered || !em.sla.enabled() {
+		return nil
+	}
+	breach, err := em.sla.check(ctx, core.SLABreachTypeDelivered, msg, receipt.Node, fftypes.Now())
+	if err != nil || breach == nil {
+		return err
+	}
+	return em.sla.recordBreach(ctx, breach, msg.TransactionID)
+}
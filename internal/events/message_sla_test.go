@@ -0,0 +1,312 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSLAMessage(age time.Duration) *core.Message {
+	created := fftypes.FFTime(time.Now().Add(-age))
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:      fftypes.NewUUID(),
+			Topics:  fftypes.FFStringArray{"topic1", "topic2"},
+			Created: &created,
+		},
+		State:         core.MessageStateSent,
+		TransactionID: fftypes.NewUUID(),
+	}
+}
+
+func TestCheckConfirmSLABreached(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.sla.policy = &core.SLAPolicy{
+		ConfirmTime: 10 * time.Second,
+		Topics:      map[string]time.Duration{"topic2": time.Second},
+	}
+	bs := newBatchState(&ag.aggregator)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	msg := newTestSLAMessage(time.Minute)
+	tx := fftypes.NewUUID()
+
+	ag.mim.On("CachedIdentityLookupByID", ag.ctx, node1.ID).Return(node1, nil)
+	ag.mim.On("CachedIdentityLookupByID", ag.ctx, org1.ID).Return(org1, nil)
+	ag.mdi.On("InsertSLABreach", ag.ctx, mock.MatchedBy(func(breach *core.SLABreach) bool {
+		return breach.Type == core.SLABreachTypeConfirmed &&
+			breach.Message.Equals(msg.Header.ID) &&
+			breach.Topic == "topic2" &&
+			breach.Org == org1.DID &&
+			breach.Node.Equals(node1.ID) &&
+			breach.SLA == fftypes.FFDuration(time.Second) &&
+			time.Duration(breach.Latency) >= time.Minute
+	})).Return(nil)
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeSLABreached &&
+			event.Topic == "topic2" &&
+			event.Correlator.Equals(msg.Header.ID) &&
+			event.Transaction.Equals(tx)
+	})).Return(nil)
+
+	err := ag.checkConfirmSLA(ag.ctx, msg, node1.ID, tx, bs)
+	assert.NoError(t, err)
+
+	err = bs.BatchState.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+
+	ag.mdi.AssertExpectations(t)
+}
+
+func TestMessageSLACheckMetrics(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	mim := &identitymanagermocks.Manager{}
+	mmi := &metricsmocks.Manager{}
+	ms := newMessageSLA(&core.Namespace{Name: "ns1"}, mdi, mim, mmi)
+	ctx := context.Background()
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	mim.On("CachedIdentityLookupByID", ctx, node1.ID).Return(node1, nil)
+	mim.On("CachedIdentityLookupByID", ctx, org1.ID).Return(org1, nil)
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("MessageConfirmLatency", "ns1", org1.DID, mock.MatchedBy(func(latency time.Duration) bool {
+		return latency >= time.Minute
+	})).Return()
+
+	assert.True(t, ms.enabled())
+	breach, err := ms.check(ctx, core.SLABreachTypeDelivered, newTestSLAMessage(time.Minute), node1.ID, fftypes.Now())
+	assert.NoError(t, err)
+	assert.Nil(t, breach)
+
+	mim.AssertExpectations(t)
+	mmi.AssertExpectations(t)
+}
+
+func TestCheckConfirmSLAWithinSLA(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.sla.policy = &core.SLAPolicy{ConfirmTime: time.Minute}
+	bs := newBatchState(&ag.aggregator)
+
+	msg := newTestSLAMessage(time.Second)
+	err := ag.checkConfirmSLA(ag.ctx, msg, nil, nil, bs)
+	assert.NoError(t, err)
+
+	assert.Empty(t, bs.Finalize)
+}
+
+func TestCheckConfirmSLAReceivedMessage(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+	bs := newBatchState(&ag.aggregator)
+
+	msg := newTestSLAMessage(time.Minute)
+	msg.State = core.MessageStatePending
+	err := ag.checkConfirmSLA(ag.ctx, msg, fftypes.NewUUID(), nil, bs)
+	assert.NoError(t, err)
+
+	assert.Empty(t, bs.Finalize)
+}
+
+func TestCheckConfirmSLADisabled(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	msg := newTestSLAMessage(time.Minute)
+	err := ag.checkConfirmSLA(ag.ctx, msg, fftypes.NewUUID(), nil, bs)
+	assert.NoError(t, err)
+
+	assert.Empty(t, bs.Finalize)
+}
+
+func TestCheckConfirmSLANoCreated(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+	bs := newBatchState(&ag.aggregator)
+
+	msg := newTestSLAMessage(time.Minute)
+	msg.Header.Created = nil
+	err := ag.checkConfirmSLA(ag.ctx, msg, fftypes.NewUUID(), nil, bs)
+	assert.NoError(t, err)
+
+	assert.Empty(t, bs.Finalize)
+}
+
+func TestCheckConfirmSLANodeLookupFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+	bs := newBatchState(&ag.aggregator)
+
+	nodeID := fftypes.NewUUID()
+	ag.mim.On("CachedIdentityLookupByID", ag.ctx, nodeID).Return(nil, fmt.Errorf("pop"))
+
+	msg := newTestSLAMessage(time.Minute)
+	err := ag.checkConfirmSLA(ag.ctx, msg, nodeID, nil, bs)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckConfirmSLAOrgLookupFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+	bs := newBatchState(&ag.aggregator)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	ag.mim.On("CachedIdentityLookupByID", ag.ctx, node1.ID).Return(node1, nil)
+	ag.mim.On("CachedIdentityLookupByID", ag.ctx, org1.ID).Return(nil, fmt.Errorf("pop"))
+
+	msg := newTestSLAMessage(time.Minute)
+	err := ag.checkConfirmSLA(ag.ctx, msg, node1.ID, nil, bs)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckConfirmSLAUnknownOrg(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	ag.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+	bs := newBatchState(&ag.aggregator)
+
+	org1 := newTestOrg("org1")
+	node1 := newTestNode("node1", org1)
+	ag.mim.On("CachedIdentityLookupByID", ag.ctx, node1.ID).Return(node1, nil)
+	ag.mim.On("CachedIdentityLookupByID", ag.ctx, org1.ID).Return(nil, nil)
+	ag.mdi.On("InsertSLABreach", ag.ctx, mock.MatchedBy(func(breach *core.SLABreach) bool {
+		return breach.Org == "" && breach.Node.Equals(node1.ID)
+	})).Return(fmt.Errorf("pop"))
+
+	msg := newTestSLAMessage(time.Minute)
+	err := ag.checkConfirmSLA(ag.ctx, msg, node1.ID, nil, bs)
+	assert.NoError(t, err)
+
+	err = bs.BatchState.RunFinalize(ag.ctx)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestMessageReceiveReceiptsSLABreached(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+
+	msg, group, org1, node1, tw := newTestReceipts(t)
+	slaMsg := newTestSLAMessage(time.Minute)
+	msg.Header.Created = slaMsg.Header.Created
+	msg.Header.Topics = slaMsg.Header.Topics
+	msg.TransactionID = slaMsg.TransactionID
+
+	mdx := &dataexchangemocks.Plugin{}
+	mdx.On("Name").Return("utdx")
+
+	mockReceiptPeer(em, org1, node1)
+	em.mim.On("CachedIdentityLookupByID", em.ctx, node1.ID).Return(node1, nil)
+	em.mim.On("CachedIdentityLookupByID", em.ctx, org1.ID).Return(org1, nil)
+	em.mdi.On("GetMessageByID", em.ctx, "ns1", msg.Header.ID).Return(msg, nil)
+	em.mdi.On("GetGroupByHash", em.ctx, "ns1", group.Hash).Return(group, nil)
+	em.mdi.On("GetMessageReceipts", em.ctx, "ns1", mock.Anything).Return([]*core.MessageReceipt{}, nil, nil)
+	em.mdi.On("InsertMessageReceipt", em.ctx, tw.Receipts[0]).Return(nil)
+	em.mdi.On("InsertSLABreach", em.ctx, mock.MatchedBy(func(breach *core.SLABreach) bool {
+		return breach.Type == core.SLABreachTypeDelivered &&
+			breach.Message.Equals(msg.Header.ID) &&
+			breach.Topic == "topic1" &&
+			breach.Org == org1.DID &&
+			breach.Node.Equals(node1.ID)
+	})).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(event *core.Event) bool {
+		return event.Type == core.EventTypeSLABreached &&
+			event.Topic == "topic1" &&
+			event.Transaction.Equals(msg.TransactionID)
+	})).Return(nil)
+
+	mde := newMessageReceivedNoAck("peer1", tw)
+	mde.On("Ack").Return()
+	em.messageReceived(mdx, mde)
+
+	mde.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	em.mdi.AssertExpectations(t)
+}
+
+func TestCheckDeliverySLAReadReceipt(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+
+	msg := newTestSLAMessage(time.Minute)
+	err := em.checkDeliverySLA(em.ctx, msg, &core.MessageReceipt{
+		Type: core.MessageReceiptTypeRead,
+		Node: fftypes.NewUUID(),
+	})
+	assert.NoError(t, err)
+}
+
+func TestCheckDeliverySLALookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.sla.policy = &core.SLAPolicy{ConfirmTime: time.Second}
+
+	nodeID := fftypes.NewUUID()
+	em.mim.On("CachedIdentityLookupByID", em.ctx, nodeID).Return(nil, fmt.Errorf("pop"))
+
+	msg := newTestSLAMessage(time.Minute)
+	err := em.checkDeliverySLA(em.ctx, msg, &core.MessageReceipt{
+		Type: core.MessageReceiptTypeDelivered,
+		Node: nodeID,
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCheckDeliverySLAWithinSLA(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.sla.policy = &core.SLAPolicy{ConfirmTime: time.Minute}
+
+	msg := newTestSLAMessage(time.Second)
+	err := em.checkDeliverySLA(em.ctx, msg, &core.MessageReceipt{
+		Type: core.MessageReceiptTypeDelivered,
+	})
+	assert.NoError(t, err)
+}
+
+func TestRecordSLABreachEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("InsertSLABreach", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.sla.recordBreach(em.ctx, &core.SLABreach{ID: fftypes.NewUUID()}, nil)
+	assert.EqualError(t, err, "pop")
+}
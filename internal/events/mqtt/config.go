@@ -0,0 +1,70 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+)
+
+const (
+	// MQTTConfigURL is the mqtt:// or mqtts:// URL of the broker
+	MQTTConfigURL = "url"
+	// MQTTConfigClientID is the client identifier to connect with
+	MQTTConfigClientID = "clientId"
+	// MQTTConfigUsername is the username to connect with
+	MQTTConfigUsername = "username"
+	// MQTTConfigPassword is the password to connect with
+	MQTTConfigPassword = "password"
+	// MQTTConfigKeepAlive is the keep alive interval requested from the broker
+	MQTTConfigKeepAlive = "keepAlive"
+	// MQTTConfigTopicPrefix is the prefix of the topic for subscriptions that do not set their own
+	MQTTConfigTopicPrefix = "topicPrefix"
+	// MQTTConfigRetainStatus enables the retained status message for each subscription
+	MQTTConfigRetainStatus = "retainStatus"
+	// MQTTConfigPublishTimeout is how long to wait for the broker to acknowledge each event
+	MQTTConfigPublishTimeout = "publishTimeout"
+	// MQTTConfigReconnectInitialDelay is the delay before the first attempt to reconnect to the broker
+	MQTTConfigReconnectInitialDelay = "reconnect.initialDelay"
+	// MQTTConfigReconnectMaxDelay is the maximum delay between attempts to reconnect to the broker
+	MQTTConfigReconnectMaxDelay = "reconnect.maxDelay"
+	// MQTTConfigReconnectFactor is the factor the delay increases by on each attempt to reconnect
+	MQTTConfigReconnectFactor = "reconnect.factor"
+
+	defaultKeepAlive             = "30s"
+	defaultTopicPrefix           = "firefly"
+	defaultRetainStatus          = true
+	defaultPublishTimeout        = "30s"
+	defaultReconnectInitialDelay = "250ms"
+	defaultReconnectMaxDelay     = "30s"
+	defaultReconnectFactor       = 2.0
+)
+
+func (m *MQTT) InitConfig(config config.Section) {
+	config.AddKnownKey(MQTTConfigURL)
+	config.AddKnownKey(MQTTConfigClientID)
+	config.AddKnownKey(MQTTConfigUsername)
+	config.AddKnownKey(MQTTConfigPassword)
+	config.AddKnownKey(MQTTConfigKeepAlive, defaultKeepAlive)
+	config.AddKnownKey(MQTTConfigTopicPrefix, defaultTopicPrefix)
+	config.AddKnownKey(MQTTConfigRetainStatus, defaultRetainStatus)
+	config.AddKnownKey(MQTTConfigPublishTimeout, defaultPublishTimeout)
+	config.AddKnownKey(MQTTConfigReconnectInitialDelay, defaultReconnectInitialDelay)
+	config.AddKnownKey(MQTTConfigReconnectMaxDelay, defaultReconnectMaxDelay)
+	config.AddKnownKey(MQTTConfigReconnectFactor, defaultReconnectFactor)
+	fftls.InitTLSConfig(config.SubSection("tls"))
+}
@@ -0,0 +1,330 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// MQTT publishes events to an MQTT 5 broker with QoS 1, so that edge and IoT consumers can receive
+// events without a WebSocket connection to FireFly. Each event is only acknowledged once the broker
+// returns a PUBACK for it. After each event, a retained status message is published that records the
+// last event delivered on the subscription, so that consumers that connect later can see how far the
+// subscription has got.
+type MQTT struct {
+	ctx            context.Context
+	capabilities   *events.Capabilities
+	callbacks      callbacks
+	connID         string
+	address        string
+	tlsConfig      *tls.Config
+	connect        *paho.Connect
+	topicPrefix    string
+	retainStatus   bool
+	publishTimeout time.Duration
+	retry          *retry.Retry
+	dial           func(ctx context.Context) (publisher, error)
+
+	connMux sync.Mutex
+	conn    publisher
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+type publisher interface {
+	Publish(ctx context.Context, p *paho.Publish) (*paho.PublishResponse, error)
+	Disconnect(d *paho.Disconnect) error
+}
+
+type eventPayload struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+// statusPayload is the retained message published to the status topic of a subscription
+type statusPayload struct {
+	Subscription core.SubscriptionRef `json:"subscription"`
+	LastEvent    *lastEvent           `json:"lastEvent"`
+	Updated      *fftypes.FFTime      `json:"updated"`
+}
+
+type lastEvent struct {
+	ID       *fftypes.UUID   `json:"id"`
+	Sequence int64           `json:"sequence"`
+	Type     core.EventType  `json:"type"`
+	Created  *fftypes.FFTime `json:"created"`
+}
+
+func (m *MQTT) Name() string { return "mqtt" }
+
+func (m *MQTT) Init(ctx context.Context, config config.Section) (err error) {
+	brokerURL := config.GetString(MQTTConfigURL)
+	if brokerURL == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, config.Resolve(MQTTConfigURL), "mqtt")
+	}
+	u, err := url.Parse(brokerURL)
+	if err != nil || u.Host == "" {
+		return i18n.NewError(ctx, coremsgs.MsgMQTTInvalidURL, brokerURL)
+	}
+	tlsConfig, err := fftls.ConstructTLSConfig(ctx, config.SubSection("tls"), fftls.ClientType)
+	if err != nil {
+		return err
+	}
+	if tlsConfig == nil && (u.Scheme == "mqtts" || u.Scheme == "ssl" || u.Scheme == "tls") {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	connID := fftypes.ShortID()
+	connect := &paho.Connect{
+		ClientID:   config.GetString(MQTTConfigClientID),
+		KeepAlive:  uint16(config.GetDuration(MQTTConfigKeepAlive).Seconds()),
+		CleanStart: true,
+	}
+	if connect.ClientID == "" {
+		connect.ClientID = "firefly-" + connID
+	}
+	if username := config.GetString(MQTTConfigUsername); username != "" {
+		connect.Username, connect.UsernameFlag = username, true
+	}
+	if password := config.GetString(MQTTConfigPassword); password != "" {
+		connect.Password, connect.PasswordFlag = []byte(password), true
+	}
+
+	*m = MQTT{
+		ctx:          log.WithLogField(ctx, "mqtt", connID),
+		capabilities: &events.Capabilities{},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		connID:         connID,
+		address:        u.Host,
+		tlsConfig:      tlsConfig,
+		connect:        connect,
+		topicPrefix:    config.GetString(MQTTConfigTopicPrefix),
+		retainStatus:   config.GetBool(MQTTConfigRetainStatus),
+		publishTimeout: config.GetDuration(MQTTConfigPublishTimeout),
+		retry: &retry.Retry{
+			InitialDelay: config.GetDuration(MQTTConfigReconnectInitialDelay),
+			MaximumDelay: config.GetDuration(MQTTConfigReconnectMaxDelay),
+			Factor:       config.GetFloat64(MQTTConfigReconnectFactor),
+		},
+	}
+	m.dial = m.dialBroker
+	return nil
+}
+
+func (m *MQTT) SetHandler(namespace string, handler events.Callbacks) error {
+	m.callbacks.writeLock.Lock()
+	defer m.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(m.callbacks.handlers, namespace)
+		return nil
+	}
+	m.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(m.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (m *MQTT) Capabilities() *events.Capabilities {
+	return m.capabilities
+}
+
+// subscriptionTopic returns the topic set on the subscription, or otherwise <prefix>/<namespace>/<subscription name>
+func (m *MQTT) subscriptionTopic(sub *core.Subscription) string {
+	if topic := sub.Options.TransportOptions().GetString("topic"); topic != "" {
+		return topic
+	}
+	return strings.Join([]string{m.topicPrefix, sub.Namespace, sub.Name}, "/")
+}
+
+func (m *MQTT) ValidateOptions(options *core.SubscriptionOptions) error {
+	topic := options.TransportOptions().GetString("topic")
+	if strings.ContainsAny(topic, "+#") {
+		return i18n.NewError(m.ctx, coremsgs.MsgMQTTInvalidTopic, topic)
+	}
+	return nil
+}
+
+func (m *MQTT) dialBroker(ctx context.Context) (publisher, error) {
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{}
+	if m.tlsConfig != nil {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: m.tlsConfig}).DialContext(ctx, "tcp", m.address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", m.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var client *paho.Client
+	client = paho.NewClient(paho.ClientConfig{
+		Conn: conn,
+		OnClientError: func(err error) {
+			log.L(m.ctx).Errorf("MQTT connection failed: %s", err)
+			m.dropConnection(client)
+		},
+		OnServerDisconnect: func(d *paho.Disconnect) {
+			log.L(m.ctx).Warnf("MQTT broker disconnected: reason=%d", d.ReasonCode)
+			m.dropConnection(client)
+		},
+	})
+	if _, err := client.Connect(ctx, m.connect); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// getConnection returns the connection to the broker, connecting first if required. Reconnection
+// is retried until it succeeds, or the plugin is stopped.
+func (m *MQTT) getConnection() (publisher, error) {
+	m.connMux.Lock()
+	defer m.connMux.Unlock()
+	if m.conn == nil {
+		err := m.retry.Do(m.ctx, "connect to MQTT broker", func(attempt int) (retry bool, err error) {
+			m.conn, err = m.dial(m.ctx)
+			return true, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.L(m.ctx).Infof("Connected to MQTT broker %s", m.address)
+	}
+	return m.conn, nil
+}
+
+// dropConnection forgets a connection that has failed, so that the next delivery reconnects
+func (m *MQTT) dropConnection(conn publisher) {
+	m.connMux.Lock()
+	defer m.connMux.Unlock()
+	if m.conn == conn {
+		m.conn = nil
+	}
+}
+
+func (m *MQTT) disconnect(conn publisher) {
+	_ = conn.Disconnect(&paho.Disconnect{ReasonCode: 0})
+	m.dropConnection(conn)
+}
+
+func (m *MQTT) publish(conn publisher, topic string, payload []byte, retain bool) error {
+	ctx, cancel := context.WithTimeout(m.ctx, m.publishTimeout)
+	defer cancel()
+	res, err := conn.Publish(ctx, &paho.Publish{
+		QoS:     1,
+		Topic:   topic,
+		Retain:  retain,
+		Payload: payload,
+		Properties: &paho.PublishProperties{
+			ContentType: "application/json",
+		},
+	})
+	if err != nil {
+		// A PUBACK with a failure reason code leaves the connection usable. Any other failure means
+		// we cannot know the state of the connection, so we reconnect before sending anything else.
+		if res == nil {
+			m.disconnect(conn)
+		}
+		return i18n.WrapError(m.ctx, err, coremsgs.MsgMQTTPublishFailed, topic)
+	}
+	return nil
+}
+
+func (m *MQTT) deliver(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	topic := m.subscriptionTopic(sub)
+	payload := &eventPayload{EventDelivery: event}
+	if sub.Options.WithData != nil && *sub.Options.WithData {
+		payload.Data = data
+	}
+	b, _ := json.Marshal(payload)
+
+	conn, err := m.getConnection()
+	if err != nil {
+		return i18n.WrapError(m.ctx, err, coremsgs.MsgMQTTPublishFailed, topic)
+	}
+	log.L(m.ctx).Debugf("MQTT-> topic %s event %s on subscription %s", topic, event.ID, sub.ID)
+	if err := m.publish(conn, topic, b, false); err != nil {
+		return err
+	}
+	log.L(m.ctx).Infof("MQTT<- topic %s event %s on subscription %s acknowledged", topic, event.ID, sub.ID)
+
+	if m.retainStatus {
+		b, _ = json.Marshal(&statusPayload{
+			Subscription: event.Subscription,
+			LastEvent: &lastEvent{
+				ID:       event.ID,
+				Sequence: event.Sequence,
+				Type:     event.Type,
+				Created:  event.Created,
+			},
+			Updated: fftypes.Now(),
+		})
+		// The event has been delivered, so a failure to update the status does not cause a redelivery
+		if err := m.publish(conn, topic+"/status", b, true); err != nil {
+			log.L(m.ctx).Warnf("Failed to update status for subscription %s: %s", sub.ID, err)
+		}
+	}
+	return nil
+}
+
+func (m *MQTT) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(m.ctx, coremsgs.MsgBatchDeliveryNotSupported, m.Name())
+}
+
+func (m *MQTT) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	response := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+	if err := m.deliver(sub, event, data); err != nil {
+		log.L(m.ctx).Errorf("Failed to deliver event %s over MQTT: %s", event.ID, err)
+		response.Rejected = true
+		response.Info = err.Error()
+	}
+	m.callbacks.writeLock.Lock()
+	cb, ok := m.callbacks.handlers[sub.Namespace]
+	m.callbacks.writeLock.Unlock()
+	if ok {
+		cb.DeliveryResponse(connID, response)
+	}
+	return nil
+}
+
+func (m *MQTT) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
@@ -0,0 +1,510 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/eclipse/paho.golang/packets"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testPublisher struct {
+	published    []*paho.Publish
+	err          error
+	response     *paho.PublishResponse
+	disconnected bool
+}
+
+func (p *testPublisher) Publish(ctx context.Context, pub *paho.Publish) (*paho.PublishResponse, error) {
+	p.published = append(p.published, pub)
+	if p.err != nil {
+		return p.response, p.err
+	}
+	return &paho.PublishResponse{}, nil
+}
+
+func (p *testPublisher) Disconnect(d *paho.Disconnect) error {
+	p.disconnected = true
+	return nil
+}
+
+func newTestMQTT(t *testing.T) (m *MQTT, cbs *eventsmocks.Callbacks, done func()) {
+	coreconfig.Reset()
+
+	cbs = &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	m = &MQTT{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	utConfig := config.RootSection("ut.mqtt")
+	m.InitConfig(utConfig)
+	utConfig.Set(MQTTConfigURL, "mqtt://localhost:1883")
+	utConfig.Set(MQTTConfigUsername, "user")
+	utConfig.Set(MQTTConfigPassword, "pass")
+	utConfig.Set(MQTTConfigReconnectInitialDelay, "1ms")
+	err := m.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	err = m.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.Equal(t, "mqtt", m.Name())
+	assert.NotNil(t, m.Capabilities())
+	return m, cbs, func() {
+		cancelCtx()
+		cbs.AssertExpectations(t)
+	}
+}
+
+func newTestPublisher(m *MQTT) *testPublisher {
+	p := &testPublisher{}
+	m.dial = func(ctx context.Context) (publisher, error) {
+		return p, nil
+	}
+	return p
+}
+
+func newTestMQTTSubscription(topic string) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+	}
+	if topic != "" {
+		sub.Options.TransportOptions()["topic"] = topic
+	}
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Sequence:  12345,
+				Type:      core.EventTypeMessageConfirmed,
+				Namespace: "ns1",
+				Topic:     "topic1",
+				Created:   fftypes.Now(),
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+// runTestBroker accepts a single client, and acknowledges everything it publishes
+func runTestBroker(t *testing.T, l net.Listener, connectReason byte, published chan<- *packets.Publish) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	cp, err := packets.ReadPacket(conn)
+	if err != nil || cp.Type != packets.CONNECT {
+		return
+	}
+	connack := packets.NewControlPacket(packets.CONNACK)
+	connack.Content.(*packets.Connack).ReasonCode = connectReason
+	if _, err := connack.WriteTo(conn); err != nil || connectReason != 0 {
+		return
+	}
+	for {
+		cp, err := packets.ReadPacket(conn)
+		if err != nil || cp.Type != packets.PUBLISH {
+			return
+		}
+		pub := cp.Content.(*packets.Publish)
+		puback := packets.NewControlPacket(packets.PUBACK)
+		puback.Content.(*packets.Puback).PacketID = pub.PacketID
+		if _, err := puback.WriteTo(conn); err != nil {
+			return
+		}
+		published <- pub
+	}
+}
+
+func TestInitMissingURL(t *testing.T) {
+	coreconfig.Reset()
+	m := &MQTT{}
+	utConfig := config.RootSection("ut.mqtt")
+	m.InitConfig(utConfig)
+	err := m.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10138.*url", err)
+}
+
+func TestInitBadURL(t *testing.T) {
+	coreconfig.Reset()
+	m := &MQTT{}
+	utConfig := config.RootSection("ut.mqtt")
+	m.InitConfig(utConfig)
+	utConfig.Set(MQTTConfigURL, "localhost")
+	err := m.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF10528", err)
+}
+
+func TestInitBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	m := &MQTT{}
+	utConfig := config.RootSection("ut.mqtt")
+	m.InitConfig(utConfig)
+	utConfig.Set(MQTTConfigURL, "mqtts://localhost:8883")
+	tlsConfig := utConfig.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCAFile, "BADCA")
+	err := m.Init(context.Background(), utConfig)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestInitTLSScheme(t *testing.T) {
+	coreconfig.Reset()
+	m := &MQTT{}
+	utConfig := config.RootSection("ut.mqtt")
+	m.InitConfig(utConfig)
+	utConfig.Set(MQTTConfigURL, "mqtts://localhost:8883")
+	utConfig.Set(MQTTConfigClientID, "edge1")
+	err := m.Init(context.Background(), utConfig)
+	assert.NoError(t, err)
+	assert.NotNil(t, m.tlsConfig)
+	assert.Equal(t, "localhost:8883", m.address)
+	assert.Equal(t, "edge1", m.connect.ClientID)
+	assert.False(t, m.connect.UsernameFlag)
+}
+
+func TestSetHandlerRemove(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	assert.Equal(t, "firefly-"+m.connID, m.connect.ClientID)
+	err := m.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, m.callbacks.handlers)
+}
+
+func TestValidateOptions(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	sub, _ := newTestMQTTSubscription("plant1/line2/events")
+	assert.NoError(t, m.ValidateOptions(&sub.Options))
+	assert.Equal(t, "plant1/line2/events", m.subscriptionTopic(sub))
+
+	sub, _ = newTestMQTTSubscription("")
+	assert.NoError(t, m.ValidateOptions(&sub.Options))
+	assert.Equal(t, "firefly/ns1/sub1", m.subscriptionTopic(sub))
+
+	sub, _ = newTestMQTTSubscription("plant1/+/events")
+	assert.Regexp(t, "FF10529", m.ValidateOptions(&sub.Options))
+	sub, _ = newTestMQTTSubscription("plant1/#")
+	assert.Regexp(t, "FF10529", m.ValidateOptions(&sub.Options))
+}
+
+func TestDeliveryRequestWithStatus(t *testing.T) {
+	m, cbs, done := newTestMQTT(t)
+	defer done()
+
+	p := newTestPublisher(m)
+	sub, event := newTestMQTTSubscription("")
+	yes := true
+	sub.Options.WithData = &yes
+	data := core.DataArray{{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":"b"}`)}}
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Return()
+
+	err := m.DeliveryRequest(m.connID, sub, event, data)
+	assert.NoError(t, err)
+
+	assert.Len(t, p.published, 2)
+	pub := p.published[0]
+	assert.Equal(t, "firefly/ns1/sub1", pub.Topic)
+	assert.Equal(t, byte(1), pub.QoS)
+	assert.False(t, pub.Retain)
+	assert.Equal(t, "application/json", pub.Properties.ContentType)
+	var body fftypes.JSONObject
+	err = json.Unmarshal(pub.Payload, &body)
+	assert.NoError(t, err)
+	assert.Equal(t, event.ID.String(), body.GetString("id"))
+	assert.Len(t, body.GetObjectArray("data"), 1)
+
+	status := p.published[1]
+	assert.Equal(t, "firefly/ns1/sub1/status", status.Topic)
+	assert.Equal(t, byte(1), status.QoS)
+	assert.True(t, status.Retain)
+	err = json.Unmarshal(status.Payload, &body)
+	assert.NoError(t, err)
+	assert.Equal(t, sub.ID.String(), body.GetObject("subscription").GetString("id"))
+	assert.Equal(t, event.ID.String(), body.GetObject("lastEvent").GetString("id"))
+	assert.Equal(t, "12345", body.GetObject("lastEvent").GetString("sequence"))
+}
+
+func TestDeliveryRequestNoStatus(t *testing.T) {
+	m, cbs, done := newTestMQTT(t)
+	defer done()
+
+	p := newTestPublisher(m)
+	m.retainStatus = false
+	sub, event := newTestMQTTSubscription("plant1/events")
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected
+	})).Return()
+
+	err := m.DeliveryRequest(m.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Len(t, p.published, 1)
+	assert.Equal(t, "plant1/events", p.published[0].Topic)
+}
+
+func TestDeliveryRequestRejectedByBroker(t *testing.T) {
+	m, cbs, done := newTestMQTT(t)
+	defer done()
+
+	p := newTestPublisher(m)
+	p.err = fmt.Errorf("pop")
+	p.response = &paho.PublishResponse{ReasonCode: packets.PubackQuotaExceeded}
+	sub, event := newTestMQTTSubscription("")
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return()
+
+	err := m.DeliveryRequest(m.connID, sub, event, nil)
+	assert.NoError(t, err)
+
+	// Still connected, and the status is not updated
+	assert.False(t, p.disconnected)
+	assert.Equal(t, p, m.conn)
+	assert.Len(t, p.published, 1)
+}
+
+func TestDeliveryRequestReconnectAfterFailure(t *testing.T) {
+	m, cbs, done := newTestMQTT(t)
+	defer done()
+
+	p := newTestPublisher(m)
+	p.err = fmt.Errorf("pop")
+	sub, event := newTestMQTTSubscription("")
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return().Once()
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected
+	})).Return().Once()
+
+	err := m.DeliveryRequest(m.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.True(t, p.disconnected)
+	assert.Nil(t, m.conn)
+
+	// The next delivery fails to connect once, and then reconnects
+	newPub := &testPublisher{}
+	attempts := 0
+	m.dial = func(ctx context.Context) (publisher, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, fmt.Errorf("pop")
+		}
+		return newPub, nil
+	}
+	err = m.DeliveryRequest(m.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Len(t, newPub.published, 2)
+}
+
+func TestDeliveryRequestStatusFail(t *testing.T) {
+	m, cbs, done := newTestMQTT(t)
+	defer done()
+
+	p := newTestPublisher(m)
+	sub, event := newTestMQTTSubscription("")
+	m.dial = func(ctx context.Context) (publisher, error) {
+		return &statusFailPublisher{testPublisher: p}, nil
+	}
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected
+	})).Return()
+
+	err := m.DeliveryRequest(m.connID, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Len(t, p.published, 2)
+}
+
+type statusFailPublisher struct {
+	*testPublisher
+}
+
+func (p *statusFailPublisher) Publish(ctx context.Context, pub *paho.Publish) (*paho.PublishResponse, error) {
+	res, _ := p.testPublisher.Publish(ctx, pub)
+	if pub.Retain {
+		return res, fmt.Errorf("pop")
+	}
+	return res, nil
+}
+
+func TestDeliveryRequestConnectCancelled(t *testing.T) {
+	m, cbs, done := newTestMQTT(t)
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	m.ctx = ctx
+	m.dial = func(ctx context.Context) (publisher, error) {
+		return nil, fmt.Errorf("pop")
+	}
+	cbs.On("DeliveryResponse", m.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected
+	})).Return()
+
+	sub, event := newTestMQTTSubscription("")
+	err := m.DeliveryRequest(m.connID, sub, event, nil)
+	assert.NoError(t, err)
+}
+
+func TestDeliveryRequestNoHandler(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	newTestPublisher(m)
+	sub, event := newTestMQTTSubscription("")
+	sub.Namespace = "ns2"
+
+	err := m.DeliveryRequest(m.connID, sub, event, nil)
+	assert.NoError(t, err)
+	m.NamespaceRestarted("ns1", time.Now())
+}
+
+func TestDialBrokerPublish(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	published := make(chan *packets.Publish, 1)
+	go runTestBroker(t, l, packets.ConnackSuccess, published)
+
+	m.address = l.Addr().String()
+	conn, err := m.dialBroker(context.Background())
+	assert.NoError(t, err)
+	m.conn = conn
+
+	err = m.publish(conn, "firefly/ns1/sub1", []byte(`{}`), false)
+	assert.NoError(t, err)
+	pub := <-published
+	assert.Equal(t, "firefly/ns1/sub1", pub.Topic)
+	assert.Equal(t, byte(1), pub.QoS)
+
+	m.disconnect(conn)
+	assert.Nil(t, m.conn)
+}
+
+func TestDialBrokerServerDisconnect(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	sendDisconnect := make(chan struct{})
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = packets.ReadPacket(conn)
+		_, _ = packets.NewControlPacket(packets.CONNACK).WriteTo(conn)
+		<-sendDisconnect
+		_, _ = packets.NewControlPacket(packets.DISCONNECT).WriteTo(conn)
+		_, _ = packets.ReadPacket(conn)
+	}()
+
+	m.address = l.Addr().String()
+	conn, err := m.getConnection()
+	assert.NoError(t, err)
+	assert.NotNil(t, conn)
+
+	// The broker disconnecting clears the connection, so that the next delivery reconnects
+	close(sendDisconnect)
+	for {
+		m.connMux.Lock()
+		cleared := m.conn == nil
+		m.connMux.Unlock()
+		if cleared {
+			break
+		}
+		time.Sleep(1 * time.Millisecond)
+	}
+}
+
+func TestDialBrokerConnectRejected(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go runTestBroker(t, l, packets.ConnackNotAuthorized, nil)
+
+	m.address = l.Addr().String()
+	_, err = m.dialBroker(context.Background())
+	assert.Error(t, err)
+}
+
+func TestDialBrokerTLSFail(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+	}()
+
+	m.address = l.Addr().String()
+	m.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	_, err = m.dialBroker(context.Background())
+	assert.Error(t, err)
+}
+
+func TestDropConnectionStale(t *testing.T) {
+	m, _, done := newTestMQTT(t)
+	defer done()
+
+	current := &testPublisher{}
+	m.conn = current
+	m.dropConnection(&testPublisher{})
+	assert.Equal(t, current, m.conn)
+}
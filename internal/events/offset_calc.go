@@ -63,3 +63,27 @@ func calcFirstOffset(ctx context.Context, ns string, di database.Plugin, pfe *co
 	log.L(ctx).Debugf("Event poller initial offest: %d (newest=%t)", firstOffset, useNewest)
 	return firstOffset, err
 }
+
+// calcRewindOffset returns the offset that results in delivery resuming from the event at the sequence, or the
+// first event created at or after the timestamp, of the rewind request
+func calcRewindOffset(ctx context.Context, ns string, di database.Plugin, rewind *core.SubscriptionRewind) (offset int64, err error) {
+	if (rewind.Sequence == nil) == (rewind.Timestamp == nil) {
+		return -1, i18n.NewError(ctx, coremsgs.MsgSubscriptionRewindTarget)
+	}
+	if rewind.Sequence != nil {
+		if *rewind.Sequence < 0 {
+			return -1, i18n.NewError(ctx, coremsgs.MsgNumberMustBeGreaterEqual, 0)
+		}
+		return *rewind.Sequence - 1, nil
+	}
+	fb := database.EventQueryFactory.NewFilter(ctx)
+	firstEvents, _, err := di.GetEvents(ctx, ns, fb.And(fb.Gte("created", rewind.Timestamp)).Sort("sequence").Limit(1))
+	if err != nil {
+		return -1, err
+	}
+	if len(firstEvents) > 0 {
+		return firstEvents[0].Sequence - 1, nil
+	}
+	// No events at or after the timestamp, so there is nothing to redeliver
+	return calcFirstOffset(ctx, ns, di, nil)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/transform"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 )
@@ -118,6 +119,11 @@ func (em *eventManager) validateAndPersistBatchContent(ctx context.Context, batc
 	matchedData := make(map[fftypes.UUID]bool)
 	matchedMsgs := make([]*messageAndData, len(batch.Payload.Messages))
 	for iMsg, msg := range batch.Payload.Messages {
+		if len(msg.Chunks) > 0 {
+			// The data of a chunked message is reassembled from its chunk messages by the aggregator
+			matchedMsgs[iMsg] = &messageAndData{message: msg}
+			continue
+		}
 		msgData := make(core.DataArray, len(msg.Data))
 		for di, dataRef := range msg.Data {
 			msgData[di] = dataByID[*dataRef.ID]
@@ -137,9 +143,36 @@ func (em *eventManager) validateAndPersistBatchContent(ctx context.Context, batc
 		return false, nil
 	}
 
+	if em.transforms.Enabled() && !em.sentByUs(ctx, batch) {
+		em.transformReceivedData(ctx, batch, matchedMsgs)
+	}
+
 	return em.persistBatchContent(ctx, batch, matchedMsgs)
 }
 
+// transformReceivedData runs the transforms configured on the namespace on the data of the messages in a batch
+// from another node. The batch itself is valid, so if a transform fails the data is stored as it was received.
+func (em *eventManager) transformReceivedData(ctx context.Context, batch *core.Batch, matchedMsgs []*messageAndData) {
+	transformed := make(map[fftypes.UUID]bool)
+	for _, mm := range matchedMsgs {
+		if !transform.AppliesTo(mm.message.Header.Type) {
+			continue
+		}
+		for i, data := range mm.data {
+			if transformed[*data.ID] {
+				continue
+			}
+			transformed[*data.ID] = true
+			value, err := em.transforms.Receive(ctx, mm.message.Header.Topics, i, data.Value)
+			if err != nil {
+				log.L(ctx).Errorf("Failed to transform data '%s' of message '%s' in batch '%s': %s", data.ID, mm.message.Header.ID, batch.ID, err)
+				continue
+			}
+			data.Value = value
+		}
+	}
+}
+
 func (em *eventManager) validateBatchData(ctx context.Context, batch *core.Batch, i int, data *core.Data) bool {
 
 	l := log.L(ctx)
@@ -293,7 +326,7 @@ func (em *eventManager) persistBatchContent(ctx context.Context, batch *core.Bat
 		// might wake up and notice the cache before we're written the messages. Meaning we'll clash and override the
 		// confirmed updates with un-confirmed batch messages.
 		for _, mm := range matchedMsgs {
-			em.data.UpdateMessageCache(mm.message, mm.data)
+			em.updateMessageCache(mm)
 		}
 	})
 	if err != nil {
@@ -301,8 +334,7 @@ func (em *eventManager) persistBatchContent(ctx context.Context, batch *core.Bat
 		// Fall back to individual upserts
 		for i, msg := range batch.Payload.Messages {
 			postHookUpdateMessageCache := func() {
-				mm := matchedMsgs[i]
-				em.data.UpdateMessageCache(mm.message, mm.data)
+				em.updateMessageCache(matchedMsgs[i])
 			}
 			if err = em.database.UpsertMessage(ctx, msg, database.UpsertOptimizationExisting, postHookUpdateMessageCache); err != nil {
 				if err == database.HashMismatch {
@@ -317,3 +349,11 @@ func (em *eventManager) persistBatchContent(ctx context.Context, batch *core.Bat
 
 	return true, nil
 }
+
+// updateMessageCache caches a message along with its data, except for a chunked message that does
+// not yet have its data. The cache is only for messages with all their data.
+func (em *eventManager) updateMessageCache(mm *messageAndData) {
+	if len(mm.message.Chunks) == 0 {
+		em.data.UpdateMessageCache(mm.message, mm.data)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/transform"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
@@ -150,6 +151,33 @@ func TestPersistBatchExtraDataInBatch(t *testing.T) {
 
 }
 
+func TestPersistBatchContentChunked(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	data := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"test"`)}
+	batch := sampleBatch(t, core.BatchTypeBroadcast, core.TransactionTypeBatchPin, core.DataArray{data})
+	batch.Payload.Data = core.DataArray{}
+	batch.Payload.Messages[0].Chunks = fftypes.FFStringArray{fftypes.NewUUID().String()}
+
+	em.mim.On("GetLocalNode", mock.Anything).Return(testNode, nil)
+	em.mdi.On("InsertDataArray", mock.Anything, core.DataArray{}).Return(nil)
+	em.mdi.On("InsertMessages", mock.Anything, batch.Payload.Messages, mock.AnythingOfType("database.PostCompletionHook")).Return(fmt.Errorf("optimization miss")).Run(func(args mock.Arguments) {
+		args[2].(database.PostCompletionHook)()
+	})
+	em.mdi.On("UpsertMessage", mock.Anything, batch.Payload.Messages[0], database.UpsertOptimizationExisting, mock.AnythingOfType("database.PostCompletionHook")).Return(nil).Run(func(args mock.Arguments) {
+		args[3].(database.PostCompletionHook)()
+	})
+
+	// The message is not cached without its data
+	ok, err := em.validateAndPersistBatchContent(em.ctx, batch)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	em.mdm.AssertNotCalled(t, "UpdateMessageCache", mock.Anything, mock.Anything)
+
+}
+
 func TestPersistBatchNilMessageEntryop(t *testing.T) {
 
 	em := newTestEventManager(t)
@@ -364,3 +392,45 @@ func TestPersistBatchInvalidTXType(t *testing.T) {
 	assert.False(t, ok)
 
 }
+
+func TestPersistBatchTransformReceivedData(t *testing.T) {
+
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	var err error
+	em.transforms, err = transform.NewPipeline(em.ctx, []*core.TransformStep{{
+		Plugin:  "upcast",
+		Topics:  []string{"topic1"},
+		Options: fftypes.JSONObject{"rename": map[string]interface{}{"qty": "quantity"}},
+	}})
+	assert.NoError(t, err)
+
+	shared := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"qty":1}`)}
+	other := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"qty":2}`)}
+	def := &core.Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"qty":3}`)}
+	batch := &core.Batch{BatchHeader: core.BatchHeader{ID: fftypes.NewUUID()}}
+	em.transformReceivedData(em.ctx, batch, []*messageAndData{
+		{
+			message: &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Type: core.MessageTypeBroadcast, Topics: fftypes.FFStringArray{"topic1"}}},
+			data:    core.DataArray{shared},
+		},
+		{
+			message: &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Type: core.MessageTypePrivate, Topics: fftypes.FFStringArray{"topic1"}}},
+			data:    core.DataArray{shared},
+		},
+		{
+			message: &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Type: core.MessageTypePrivate, Topics: fftypes.FFStringArray{"topic2"}}},
+			data:    core.DataArray{other},
+		},
+		{
+			message: &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID(), Type: core.MessageTypeDefinition, Topics: fftypes.FFStringArray{"topic1"}}},
+			data:    core.DataArray{def},
+		},
+	})
+
+	assert.JSONEq(t, `{"quantity":1}`, shared.Value.String())
+	assert.JSONEq(t, `{"qty":2}`, other.Value.String())
+	assert.JSONEq(t, `{"qty":3}`, def.Value.String())
+
+}
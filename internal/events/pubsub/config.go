@@ -0,0 +1,46 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+)
+
+const (
+	// PubSubConfigProject is the Google Cloud project of the topics
+	PubSubConfigProject = "project"
+	// PubSubConfigTopic is the topic events are published to, for subscriptions that do not set their own
+	PubSubConfigTopic = "topic"
+	// PubSubConfigCredentialsFile is a service account key file. When not set, the token of the service account
+	// attached to the instance is requested from the metadata server
+	PubSubConfigCredentialsFile = "credentialsFile"
+	// PubSubConfigMetadataURL is the URL of the metadata server
+	PubSubConfigMetadataURL = "metadataUrl"
+
+	defaultURL         = "https://pubsub.googleapis.com"
+	defaultMetadataURL = "http://metadata.google.internal"
+)
+
+func (p *PubSub) InitConfig(config config.Section) {
+	// The URL is optional, and overrides the public endpoint of the service
+	ffresty.InitConfig(config)
+	config.AddKnownKey(PubSubConfigProject)
+	config.AddKnownKey(PubSubConfigTopic)
+	config.AddKnownKey(PubSubConfigCredentialsFile)
+	config.AddKnownKey(PubSubConfigMetadataURL, defaultMetadataURL)
+}
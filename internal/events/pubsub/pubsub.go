@@ -0,0 +1,261 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// PubSub publishes each event as a message to a Google Cloud Pub/Sub topic, through the REST API of the service.
+// An event is only acknowledged once Pub/Sub returns the ID of the published message, and is otherwise
+// rejected so that it is redelivered.
+type PubSub struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	client       *resty.Client
+	tokens       *tokenSource
+	connID       string
+	project      string
+	topic        string
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+// Pub/Sub topic names, which can be qualified with the project of the topic
+var (
+	topicNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9\-_.~+%]{2,254}$`)
+	topicPathRegex = regexp.MustCompile(`^projects/[^/]+/topics/[a-zA-Z][a-zA-Z0-9\-_.~+%]{2,254}$`)
+)
+
+type messageBody struct {
+	*core.EventDelivery
+	Data core.DataArray `json:"data,omitempty"`
+}
+
+type pubsubMessage struct {
+	Data       []byte            `json:"data"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+type publishRequest struct {
+	Messages []*pubsubMessage `json:"messages"`
+}
+
+type publishResponse struct {
+	MessageIDs []string `json:"messageIds"`
+}
+
+func (p *PubSub) Name() string { return "pubsub" }
+
+func (p *PubSub) Init(ctx context.Context, config config.Section) (err error) {
+	client, err := ffresty.New(ctx, config)
+	if err != nil {
+		return err
+	}
+	if config.GetString(ffresty.HTTPConfigURL) == "" {
+		client.SetBaseURL(defaultURL)
+	}
+
+	tokens := &tokenSource{
+		client:      client,
+		metadataURL: config.GetString(PubSubConfigMetadataURL),
+	}
+	project := config.GetString(PubSubConfigProject)
+	if credentialsFile := config.GetString(PubSubConfigCredentialsFile); credentialsFile != "" {
+		if tokens.account, tokens.privateKey, err = loadServiceAccount(ctx, credentialsFile); err != nil {
+			return err
+		}
+		if project == "" {
+			project = tokens.account.ProjectID
+		}
+	}
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+
+	connID := fftypes.ShortID()
+	*p = PubSub{
+		ctx:          log.WithLogField(ctx, "pubsub", connID),
+		capabilities: &events.Capabilities{CloudEvents: true},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		client:  client,
+		tokens:  tokens,
+		connID:  connID,
+		project: project,
+		topic:   config.GetString(PubSubConfigTopic),
+	}
+	if p.topic != "" {
+		if _, err := p.topicPath(p.topic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PubSub) SetHandler(namespace string, handler events.Callbacks) error {
+	p.callbacks.writeLock.Lock()
+	defer p.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(p.callbacks.handlers, namespace)
+		return nil
+	}
+	p.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(p.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (p *PubSub) Capabilities() *events.Capabilities {
+	return p.capabilities
+}
+
+// topicPath returns the full path of a topic, using the configured project for topics that are not qualified with one
+func (p *PubSub) topicPath(topic string) (string, error) {
+	if topicPathRegex.MatchString(topic) {
+		return topic, nil
+	}
+	if p.project == "" || !topicNameRegex.MatchString(topic) {
+		return "", i18n.NewError(p.ctx, coremsgs.MsgPubSubInvalidTopic, topic)
+	}
+	return fmt.Sprintf("projects/%s/topics/%s", p.project, topic), nil
+}
+
+func (p *PubSub) subscriptionTopic(options *core.SubscriptionOptions) string {
+	if topic := options.TransportOptions().GetString("topic"); topic != "" {
+		return topic
+	}
+	return p.topic
+}
+
+func (p *PubSub) ValidateOptions(options *core.SubscriptionOptions) error {
+	topic := p.subscriptionTopic(options)
+	if topic == "" {
+		return i18n.NewError(p.ctx, coremsgs.MsgEventTransportTargetMissing, p.Name(), "topic")
+	}
+	if _, err := p.topicPath(topic); err != nil {
+		return err
+	}
+	for k, v := range options.TransportOptions().GetObject("attributes") {
+		if _, ok := v.(string); !ok {
+			return i18n.NewError(p.ctx, coremsgs.MsgInvalidMessageAttributes, fmt.Sprintf("%s=%T must be a string", k, v))
+		}
+	}
+	return nil
+}
+
+// messageAttributes returns the static attributes of the subscription, and the attributes of the event,
+// which can be used to filter messages on Pub/Sub subscriptions
+func messageAttributes(sub *core.Subscription, event *core.EventDelivery) map[string]string {
+	attributes := map[string]string{}
+	for k, v := range sub.Options.TransportOptions().GetObject("attributes") {
+		attributes[k] = v.(string)
+	}
+	attributes["id"] = event.ID.String()
+	attributes["type"] = string(event.Type)
+	attributes["namespace"] = event.Namespace
+	if event.Topic != "" {
+		attributes["topic"] = event.Topic
+	}
+	return attributes
+}
+
+func (p *PubSub) publish(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	topic, err := p.topicPath(p.subscriptionTopic(&sub.Options))
+	if err != nil {
+		return err
+	}
+
+	body := &messageBody{EventDelivery: event}
+	if sub.Options.WithData != nil && *sub.Options.WithData {
+		body.Data = data
+	}
+	var message interface{} = body
+	if sub.Options.Format == core.SubOptsFormatCloudEvents {
+		message = core.NewCloudEvent(event, body)
+	}
+	b, _ := json.Marshal(message)
+
+	token, err := p.tokens.accessToken(p.ctx)
+	if err != nil {
+		return err
+	}
+	var result publishResponse
+	resp, err := p.client.R().
+		SetContext(p.ctx).
+		SetAuthToken(token).
+		SetBody(&publishRequest{
+			Messages: []*pubsubMessage{{
+				Data:       b,
+				Attributes: messageAttributes(sub, event),
+			}},
+		}).
+		SetResult(&result).
+		Post(fmt.Sprintf("/v1/%s:publish", topic))
+	if err != nil || !resp.IsSuccess() {
+		return ffresty.WrapRestErr(p.ctx, resp, err, coremsgs.MsgPubSubRESTErr)
+	}
+	log.L(p.ctx).Infof("PubSub<- topic %s event %s on subscription %s published as message %v", topic, event.ID, sub.ID, result.MessageIDs)
+	return nil
+}
+
+func (p *PubSub) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(p.ctx, coremsgs.MsgBatchDeliveryNotSupported, p.Name())
+}
+
+func (p *PubSub) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	response := &core.EventDeliveryResponse{
+		ID:           event.ID,
+		Subscription: event.Subscription,
+	}
+	if err := p.publish(sub, event, data); err != nil {
+		log.L(p.ctx).Errorf("Failed to deliver event %s to Pub/Sub: %s", event.ID, err)
+		response.Rejected = true
+		response.Info = err.Error()
+	}
+	p.callbacks.writeLock.Lock()
+	cb, ok := p.callbacks.handlers[sub.Namespace]
+	p.callbacks.writeLock.Unlock()
+	if ok {
+		cb.DeliveryResponse(connID, response)
+	}
+	return nil
+}
+
+func (p *PubSub) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op
+}
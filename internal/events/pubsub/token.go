@@ -0,0 +1,177 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pubsub
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const (
+	pubsubScope    = "https://www.googleapis.com/auth/pubsub"
+	jwtBearerGrant = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	// tokens are refreshed this long before they expire, so a request never goes out with an expired token
+	tokenExpiryMargin = 1 * time.Minute
+)
+
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// tokenSource obtains OAuth2 access tokens for the IAM identity of FireFly, either by signing a JWT with the key
+// of a service account, or from the metadata server of the instance FireFly runs on, and caches them until they expire
+type tokenSource struct {
+	client      *resty.Client
+	metadataURL string
+	account     *serviceAccountKey
+	privateKey  *rsa.PrivateKey
+
+	mux    sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func loadServiceAccount(ctx context.Context, filename string) (*serviceAccountKey, *rsa.PrivateKey, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgPubSubInvalidCredentials, filename, err)
+	}
+	var account serviceAccountKey
+	if err := json.Unmarshal(b, &account); err != nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgPubSubInvalidCredentials, filename, err)
+	}
+	if account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgPubSubInvalidCredentials, filename, "client_email and token_uri are required")
+	}
+	block, _ := pem.Decode([]byte(account.PrivateKey))
+	if block == nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgPubSubInvalidCredentials, filename, "private_key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgPubSubInvalidCredentials, filename, err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgPubSubInvalidCredentials, filename, "private_key is not an RSA key")
+	}
+	return &account, rsaKey, nil
+}
+
+func base64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// signedJWT builds the RS256 assertion exchanged for an access token, as described in
+// https://developers.google.com/identity/protocols/oauth2/service-account#authorizingrequests
+func (ts *tokenSource) signedJWT(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": ts.account.PrivateKeyID,
+	})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   ts.account.ClientEmail,
+		"scope": pubsubScope,
+		"aud":   ts.account.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	unsigned := base64URL(header) + "." + base64URL(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, ts.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return unsigned + "." + base64URL(signature), nil
+}
+
+func (ts *tokenSource) requestToken(ctx context.Context, now time.Time) (*tokenResponse, error) {
+	var result tokenResponse
+	var resp *resty.Response
+	var err error
+	if ts.account != nil {
+		var assertion string
+		if assertion, err = ts.signedJWT(now); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgPubSubTokenFailed, err)
+		}
+		resp, err = ts.client.R().
+			SetContext(ctx).
+			SetFormData(map[string]string{
+				"grant_type": jwtBearerGrant,
+				"assertion":  assertion,
+			}).
+			SetResult(&result).
+			Post(ts.account.TokenURI)
+	} else {
+		resp, err = ts.client.R().
+			SetContext(ctx).
+			SetHeader("Metadata-Flavor", "Google").
+			SetResult(&result).
+			Get(strings.TrimSuffix(ts.metadataURL, "/") + "/computeMetadata/v1/instance/service-accounts/default/token")
+	}
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgPubSubTokenFailed, err)
+	}
+	if !resp.IsSuccess() || result.AccessToken == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgPubSubTokenFailed, fmt.Sprintf("[%d] %s", resp.StatusCode(), resp.String()))
+	}
+	return &result, nil
+}
+
+// accessToken returns the cached token, or requests a new one if it has expired
+func (ts *tokenSource) accessToken(ctx context.Context) (string, error) {
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+	now := time.Now()
+	if ts.token != "" && now.Before(ts.expiry) {
+		return ts.token, nil
+	}
+	result, err := ts.requestToken(ctx, now)
+	if err != nil {
+		return "", err
+	}
+	ts.token = result.AccessToken
+	ts.expiry = now.Add(time.Duration(result.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return ts.token, nil
+}
@@ -0,0 +1,40 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
+
+const (
+	// PullConfigAckTimeout is how long a pulled event can go without an ack, before it can be pulled again
+	PullConfigAckTimeout = "ackTimeout"
+	// PullConfigMaxWait is the longest a pull request can wait for events to arrive
+	PullConfigMaxWait = "maxWait"
+	// PullConfigReadAhead is the number of events buffered for pulling, for subscriptions that do not set their own
+	PullConfigReadAhead = "readAhead"
+
+	defaultAckTimeout = "30s"
+	defaultMaxWait    = "30s"
+	defaultReadAhead  = 50
+)
+
+func (p *Pull) InitConfig(config config.Section) {
+	config.AddKnownKey(PullConfigAckTimeout, defaultAckTimeout)
+	config.AddKnownKey(PullConfigMaxWait, defaultMaxWait)
+	config.AddKnownKey(PullConfigReadAhead, defaultReadAhead)
+}
@@ -0,0 +1,289 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+const (
+	// PullTransport is the name of the transport, for subscriptions that are consumed through the pull API
+	PullTransport = "pull"
+
+	maxBatchSize = 1000
+)
+
+// Pull buffers the events of each subscription that uses it, until a consumer pulls them through the API.
+// A pulled event is leased to the consumer until it is acknowledged, and can be pulled again if it is
+// not acknowledged within the ack timeout. Delivery is at-least-once, and consumers can use the ID of
+// each event to discard any duplicates.
+type Pull struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	connID       string
+	ackTimeout   time.Duration
+	maxWait      time.Duration
+	readAhead    uint16
+
+	mux    sync.Mutex
+	queues map[fftypes.UUID]*queue
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+type pendingEvent struct {
+	event *core.EventDelivery
+	data  core.DataArray
+	// zero until the event is pulled, and then the time the lease of the consumer expires
+	leaseExpiry time.Time
+}
+
+// queue holds the events of one subscription in sequence order
+type queue struct {
+	namespace string
+	events    []*pendingEvent
+	// closed and replaced each time an event is added, to wake any waiting pull requests
+	changed chan struct{}
+}
+
+func (p *Pull) Name() string { return PullTransport }
+
+func (p *Pull) Init(ctx context.Context, config config.Section) error {
+	connID := fftypes.ShortID()
+	*p = Pull{
+		ctx:          log.WithLogField(ctx, "pull", connID),
+		capabilities: &events.Capabilities{},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		connID:     connID,
+		ackTimeout: config.GetDuration(PullConfigAckTimeout),
+		maxWait:    config.GetDuration(PullConfigMaxWait),
+		readAhead:  uint16(config.GetUint(PullConfigReadAhead)),
+		queues:     make(map[fftypes.UUID]*queue),
+	}
+	return nil
+}
+
+func (p *Pull) SetHandler(namespace string, handler events.Callbacks) error {
+	// Any buffered events belong to the dispatchers of the previous handler, and will be delivered again
+	p.clearNamespace(namespace)
+
+	p.callbacks.writeLock.Lock()
+	defer p.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(p.callbacks.handlers, namespace)
+		return nil
+	}
+	p.callbacks.handlers[namespace] = handler
+	// We have a single logical connection, that matches all subscriptions
+	return handler.RegisterConnection(p.connID, func(sr core.SubscriptionRef) bool { return true })
+}
+
+func (p *Pull) Capabilities() *events.Capabilities {
+	return p.capabilities
+}
+
+func (p *Pull) ValidateOptions(options *core.SubscriptionOptions) error {
+	// The read ahead is the number of events that can be buffered, so is the most a consumer can pull at once
+	if options.ReadAhead == nil {
+		readAhead := p.readAhead
+		options.ReadAhead = &readAhead
+	}
+	return nil
+}
+
+func (p *Pull) getHandler(namespace string) (events.Callbacks, bool) {
+	p.callbacks.writeLock.Lock()
+	defer p.callbacks.writeLock.Unlock()
+	cb, ok := p.callbacks.handlers[namespace]
+	return cb, ok
+}
+
+func (p *Pull) getQueueLocked(sub *core.SubscriptionRef) *queue {
+	q, ok := p.queues[*sub.ID]
+	if !ok {
+		q = &queue{
+			namespace: sub.Namespace,
+			changed:   make(chan struct{}),
+		}
+		p.queues[*sub.ID] = q
+	}
+	return q
+}
+
+func (p *Pull) clearNamespace(namespace string) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	for id, q := range p.queues {
+		if q.namespace == namespace {
+			delete(p.queues, id)
+		}
+	}
+}
+
+func (p *Pull) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(p.ctx, coremsgs.MsgBatchDeliveryNotSupported, p.Name())
+}
+
+func (p *Pull) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	q := p.getQueueLocked(&sub.SubscriptionRef)
+	// Events can be dispatched in parallel, so we keep the queue in sequence order. An event that is
+	// dispatched again replaces the buffered one.
+	i := sort.Search(len(q.events), func(i int) bool { return q.events[i].event.Sequence >= event.Sequence })
+	pe := &pendingEvent{event: event, data: data}
+	if i < len(q.events) && q.events[i].event.ID.Equals(event.ID) {
+		q.events[i] = pe
+	} else {
+		q.events = append(q.events, nil)
+		copy(q.events[i+1:], q.events[i:])
+		q.events[i] = pe
+	}
+	close(q.changed)
+	q.changed = make(chan struct{})
+	log.L(p.ctx).Debugf("Pull<- event %s buffered for subscription %s (%d buffered)", event.ID, sub.ID, len(q.events))
+	return nil
+}
+
+// Pull returns up to the requested batch size of events that are not currently leased to a consumer. If there are
+// none, it waits up to the requested time for an event to be delivered, or for the lease of a pulled event to expire.
+func (p *Pull) Pull(ctx context.Context, sub *core.Subscription, req *core.SubscriptionPull) ([]*core.PulledEvent, error) {
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = 1
+	}
+	if batchSize < 0 || batchSize > maxBatchSize {
+		return nil, i18n.NewError(ctx, coremsgs.MsgPullInvalidBatchSize, req.BatchSize, maxBatchSize)
+	}
+	var wait time.Duration
+	if req.Wait != nil {
+		wait = time.Duration(*req.Wait)
+	}
+	if wait > p.maxWait {
+		wait = p.maxWait
+	}
+	deadline := time.Now().Add(wait)
+
+	for {
+		now := time.Now()
+		pulled, nextExpiry, changed := p.leaseEvents(sub, batchSize, now)
+		if len(pulled) > 0 || !now.Before(deadline) {
+			return pulled, nil
+		}
+		timeout := deadline.Sub(now)
+		if !nextExpiry.IsZero() && nextExpiry.Sub(now) < timeout {
+			timeout = nextExpiry.Sub(now)
+		}
+		timer := time.NewTimer(timeout)
+		select {
+		case <-changed:
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			log.L(ctx).Debugf("Pull request for subscription %s ended while waiting for events", sub.ID)
+			return pulled, nil
+		}
+		timer.Stop()
+	}
+}
+
+func (p *Pull) leaseEvents(sub *core.Subscription, batchSize int, now time.Time) (pulled []*core.PulledEvent, nextExpiry time.Time, changed chan struct{}) {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	q := p.getQueueLocked(&sub.SubscriptionRef)
+	pulled = make([]*core.PulledEvent, 0, batchSize)
+	for _, pe := range q.events {
+		if len(pulled) >= batchSize {
+			break
+		}
+		if pe.leaseExpiry.After(now) {
+			if nextExpiry.IsZero() || pe.leaseExpiry.Before(nextExpiry) {
+				nextExpiry = pe.leaseExpiry
+			}
+			continue
+		}
+		pe.leaseExpiry = now.Add(p.ackTimeout)
+		pulled = append(pulled, &core.PulledEvent{EventDelivery: *pe.event, Data: pe.data})
+	}
+	return pulled, nextExpiry, q.changed
+}
+
+// Ack acknowledges pulled events, so the offset of the subscription can move past them. Rejecting an event
+// has it redelivered, along with every event after it, so all the buffered events of the subscription
+// are discarded to be delivered again.
+func (p *Pull) Ack(ctx context.Context, sub *core.Subscription, ack *core.SubscriptionAck) error {
+	acked := make(map[fftypes.UUID]bool, len(ack.Events))
+	for _, id := range ack.Events {
+		if id != nil {
+			acked[*id] = true
+		}
+	}
+
+	p.mux.Lock()
+	q := p.getQueueLocked(&sub.SubscriptionRef)
+	var responses []*core.EventDeliveryResponse
+	remaining := make([]*pendingEvent, 0, len(q.events))
+	for _, pe := range q.events {
+		if acked[*pe.event.ID] {
+			responses = append(responses, &core.EventDeliveryResponse{
+				ID:           pe.event.ID,
+				Rejected:     ack.Reject,
+				Subscription: pe.event.Subscription,
+			})
+		} else {
+			remaining = append(remaining, pe)
+		}
+	}
+	if ack.Reject && len(responses) > 0 {
+		remaining = nil
+		// Only the earliest rejection matters, as the dispatcher rewinds to that event
+		responses = responses[:1]
+	}
+	q.events = remaining
+	p.mux.Unlock()
+
+	if len(responses) < len(acked) && !ack.Reject {
+		log.L(ctx).Warnf("%d of %d acknowledged events were not pulled from subscription %s", len(acked)-len(responses), len(acked), sub.ID)
+	}
+	if cb, ok := p.getHandler(sub.Namespace); ok {
+		for _, response := range responses {
+			cb.DeliveryResponse(p.connID, response)
+		}
+	}
+	return nil
+}
+
+func (p *Pull) NamespaceRestarted(ns string, startTime time.Time) {
+	// no-op - the buffered events of the namespace were discarded when the new handler was set
+}
@@ -0,0 +1,258 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pull
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestPull(t *testing.T, ackTimeout string) (p *Pull, cbs *eventsmocks.Callbacks, done func()) {
+	coreconfig.Reset()
+
+	cbs = &eventsmocks.Callbacks{}
+	rc := cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	rc.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, true, a[1].(events.SubscriptionMatcher)(core.SubscriptionRef{}))
+	}
+	p = &Pull{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	utConfig := config.RootSection("ut.pull")
+	p.InitConfig(utConfig)
+	utConfig.Set(PullConfigAckTimeout, ackTimeout)
+	utConfig.Set(PullConfigMaxWait, "1s")
+	err := p.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	err = p.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+	assert.Equal(t, "pull", p.Name())
+	assert.NotNil(t, p.Capabilities())
+	return p, cbs, func() {
+		cancelCtx()
+		cbs.AssertExpectations(t)
+	}
+}
+
+func newTestSubscription() *core.Subscription {
+	return &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: PullTransport,
+	}
+}
+
+func newTestEvent(sub *core.Subscription, sequence int64) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:       fftypes.NewUUID(),
+				Sequence: sequence,
+				Type:     core.EventTypeMessageConfirmed,
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+}
+
+func wait(d string) *core.SubscriptionPull {
+	w, _ := fftypes.ParseDurationString(d, time.Millisecond)
+	return &core.SubscriptionPull{Wait: (*fftypes.FFDuration)(&w)}
+}
+
+func TestValidateOptionsDefaultReadAhead(t *testing.T) {
+	p, _, done := newTestPull(t, "30s")
+	defer done()
+
+	options := &core.SubscriptionOptions{}
+	assert.NoError(t, p.ValidateOptions(options))
+	assert.Equal(t, uint16(50), *options.ReadAhead)
+
+	readAhead := uint16(5)
+	options.ReadAhead = &readAhead
+	assert.NoError(t, p.ValidateOptions(options))
+	assert.Equal(t, uint16(5), *options.ReadAhead)
+}
+
+func TestPullInOrderAndAck(t *testing.T) {
+	p, cbs, done := newTestPull(t, "30s")
+	defer done()
+
+	sub := newTestSubscription()
+	event1 := newTestEvent(sub, 1)
+	event2 := newTestEvent(sub, 2)
+	event3 := newTestEvent(sub, 3)
+	data := core.DataArray{{ID: fftypes.NewUUID()}}
+	// Delivered out of order, and one delivered twice
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, event2, nil))
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, event1, data))
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, event3, nil))
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, event2, nil))
+
+	pulled, err := p.Pull(context.Background(), sub, &core.SubscriptionPull{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 2)
+	assert.Equal(t, event1.ID, pulled[0].ID)
+	assert.Equal(t, data, pulled[0].Data)
+	assert.Equal(t, event2.ID, pulled[1].ID)
+
+	// The leased events are not pulled again
+	pulled, err = p.Pull(context.Background(), sub, &core.SubscriptionPull{BatchSize: 10})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 1)
+	assert.Equal(t, event3.ID, pulled[0].ID)
+
+	pulled, err = p.Pull(context.Background(), sub, &core.SubscriptionPull{})
+	assert.NoError(t, err)
+	assert.Empty(t, pulled)
+
+	cbs.On("DeliveryResponse", p.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return !r.Rejected && r.Subscription.ID.Equals(sub.ID) && (r.ID.Equals(event1.ID) || r.ID.Equals(event2.ID))
+	})).Return().Twice()
+	err = p.Ack(context.Background(), sub, &core.SubscriptionAck{
+		Events: []*fftypes.UUID{event1.ID, event2.ID, fftypes.NewUUID(), nil},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, p.queues[*sub.ID].events, 1)
+}
+
+func TestPullWaitForDelivery(t *testing.T) {
+	p, _, done := newTestPull(t, "30s")
+	defer done()
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, 1)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = p.DeliveryRequest(p.connID, sub, event, nil)
+	}()
+	pulled, err := p.Pull(context.Background(), sub, wait("5s"))
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 1)
+	assert.Equal(t, event.ID, pulled[0].ID)
+}
+
+func TestPullWaitTimeout(t *testing.T) {
+	p, _, done := newTestPull(t, "30s")
+	defer done()
+
+	// The wait is limited to the maximum
+	p.maxWait = 10 * time.Millisecond
+	pulled, err := p.Pull(context.Background(), newTestSubscription(), wait("5m"))
+	assert.NoError(t, err)
+	assert.Empty(t, pulled)
+}
+
+func TestPullWaitContextCancelled(t *testing.T) {
+	p, _, done := newTestPull(t, "30s")
+	defer done()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pulled, err := p.Pull(ctx, newTestSubscription(), wait("1s"))
+	assert.NoError(t, err)
+	assert.Empty(t, pulled)
+}
+
+func TestPullLeaseExpiry(t *testing.T) {
+	p, _, done := newTestPull(t, "10ms")
+	defer done()
+
+	sub := newTestSubscription()
+	event := newTestEvent(sub, 1)
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, event, nil))
+
+	pulled, err := p.Pull(context.Background(), sub, &core.SubscriptionPull{})
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 1)
+
+	// Waits for the lease to expire, and is given the event again
+	pulled, err = p.Pull(context.Background(), sub, wait("1s"))
+	assert.NoError(t, err)
+	assert.Len(t, pulled, 1)
+	assert.Equal(t, event.ID, pulled[0].ID)
+}
+
+func TestPullBadBatchSize(t *testing.T) {
+	p, _, done := newTestPull(t, "30s")
+	defer done()
+
+	_, err := p.Pull(context.Background(), newTestSubscription(), &core.SubscriptionPull{BatchSize: 1001})
+	assert.Regexp(t, "FF10579", err)
+}
+
+func TestAckReject(t *testing.T) {
+	p, cbs, done := newTestPull(t, "30s")
+	defer done()
+
+	sub := newTestSubscription()
+	event1 := newTestEvent(sub, 1)
+	event2 := newTestEvent(sub, 2)
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, event1, nil))
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, event2, nil))
+
+	cbs.On("DeliveryResponse", p.connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.Rejected && r.ID.Equals(event1.ID)
+	})).Return().Once()
+	err := p.Ack(context.Background(), sub, &core.SubscriptionAck{
+		Events: []*fftypes.UUID{event2.ID, event1.ID},
+		Reject: true,
+	})
+	assert.NoError(t, err)
+	// Everything is redelivered by the dispatcher
+	assert.Empty(t, p.queues[*sub.ID].events)
+}
+
+func TestSetHandlerClearsNamespace(t *testing.T) {
+	p, _, done := newTestPull(t, "30s")
+	defer done()
+
+	sub := newTestSubscription()
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, newTestEvent(sub, 1), nil))
+	p.NamespaceRestarted("ns1", time.Now())
+	assert.Len(t, p.queues, 1)
+
+	err := p.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, p.queues)
+	assert.Empty(t, p.callbacks.handlers)
+
+	// Acks without a handler are dropped
+	assert.NoError(t, p.DeliveryRequest(p.connID, sub, newTestEvent(sub, 1), nil))
+	err = p.Ack(context.Background(), sub, &core.SubscriptionAck{Events: []*fftypes.UUID{p.queues[*sub.ID].events[0].event.ID}})
+	assert.NoError(t, err)
+}
+
+func TestBatchDeliveryNotSupported(t *testing.T) {
+	p, _, done := newTestPull(t, "30s")
+	defer done()
+
+	err := p.BatchDeliveryRequest(p.connID, newTestSubscription(), nil)
+	assert.Regexp(t, "FF10567", err)
+}
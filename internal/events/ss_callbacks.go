@@ -73,17 +73,21 @@ func (em *eventManager) SharedStorageBlobDownloaded(ss sharedstorage.Plugin, has
 	l := log.L(em.ctx)
 	l.Infof("Blob received event from public storage %s: Hash='%v'", ss.Name(), hash)
 
-	// Dispatch to the blob receiver for efficient batch DB operations
 	blobHash := hash
-	em.blobReceiver.blobReceived(em.ctx, &blobNotification{
-		blob: &core.Blob{
-			Namespace:  em.namespace.Name,
-			PayloadRef: payloadRef,
-			Hash:       &blobHash,
-			Size:       size,
-			Created:    fftypes.Now(),
-			DataID:     dataID,
-		},
-	})
+	blob := &core.Blob{
+		Namespace:  em.namespace.Name,
+		PayloadRef: payloadRef,
+		Hash:       &blobHash,
+		Size:       size,
+		Created:    fftypes.Now(),
+		DataID:     dataID,
+	}
+	quarantined, err := em.scanReceivedBlob(blob)
+	if err != nil || quarantined {
+		return err
+	}
+
+	// Dispatch to the blob receiver for efficient batch DB operations
+	em.blobReceiver.blobReceived(em.ctx, &blobNotification{blob: blob})
 	return nil
 }
@@ -0,0 +1,231 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/events"
+)
+
+// SSE streams the events of a single subscription to each HTTP client that connects, using
+// Server-Sent Events. As the client cannot reply on the same connection, each event is
+// acknowledged once it has been written. The id of each event is its sequence, so a client that
+// reconnects with a Last-Event-ID header resumes from the event after the last one it received.
+type SSE struct {
+	ctx          context.Context
+	capabilities *events.Capabilities
+	callbacks    callbacks
+	connections  map[string]*sseConnection
+	connMux      sync.Mutex
+	pingInterval time.Duration
+	auth         core.Authorizer
+}
+
+type callbacks struct {
+	writeLock sync.Mutex
+	handlers  map[string]events.Callbacks
+}
+
+func (s *SSE) Name() string { return "sse" }
+
+func (s *SSE) Init(ctx context.Context, config config.Section) error {
+	*s = SSE{
+		ctx:          ctx,
+		connections:  make(map[string]*sseConnection),
+		capabilities: &events.Capabilities{},
+		callbacks: callbacks{
+			handlers: make(map[string]events.Callbacks),
+		},
+		pingInterval: config.GetDuration(SSEConfigPingInterval),
+		auth:         s.auth,
+	}
+	return nil
+}
+
+func (s *SSE) SetAuthorizer(auth core.Authorizer) {
+	s.auth = auth
+}
+
+func (s *SSE) SetHandler(namespace string, handler events.Callbacks) error {
+	s.callbacks.writeLock.Lock()
+	defer s.callbacks.writeLock.Unlock()
+	if handler == nil {
+		delete(s.callbacks.handlers, namespace)
+		return nil
+	}
+	s.callbacks.handlers[namespace] = handler
+	return nil
+}
+
+func (s *SSE) Capabilities() *events.Capabilities {
+	return s.capabilities
+}
+
+func (s *SSE) ValidateOptions(options *core.SubscriptionOptions) error {
+	// We don't support streaming the full data over SSE
+	if options.WithData != nil && *options.WithData {
+		return i18n.NewError(s.ctx, coremsgs.MsgSSENoData)
+	}
+	forceFalse := false
+	options.WithData = &forceFalse
+	return nil
+}
+
+func (s *SSE) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(s.ctx, coremsgs.MsgBatchDeliveryNotSupported, s.Name())
+}
+
+func (s *SSE) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
+	s.connMux.Lock()
+	sc, ok := s.connections[connID]
+	s.connMux.Unlock()
+	if !ok {
+		return i18n.NewError(s.ctx, coremsgs.MsgSSEConnectionNotActive, connID)
+	}
+	return sc.dispatch(event)
+}
+
+// ServeHTTP starts a subscription from the query parameters of the request, and streams its events
+// until the client disconnects. The parameters are the same as those used to auto-start a subscription
+// on a WebSocket: namespace, plus name for a durable subscription, or ephemeral with filter.* options.
+func (s *SSE) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		s.writeError(res, req, i18n.NewError(req.Context(), coremsgs.MsgSSEStreamingUnsupported))
+		return
+	}
+	start, lastSequence, err := s.parseStart(req)
+	if err == nil {
+		err = s.authorize(req, start.Namespace)
+	}
+	if err != nil {
+		s.writeError(res, req, err)
+		return
+	}
+
+	sc := newConnection(s.ctx, s, res, flusher, req)
+	s.connMux.Lock()
+	s.connections[sc.connID] = sc
+	s.connMux.Unlock()
+	defer s.connClosed(sc)
+
+	if err := sc.start(start, lastSequence); err != nil {
+		s.writeError(res, req, err)
+		return
+	}
+	sc.sendLoop()
+}
+
+func (s *SSE) parseStart(req *http.Request) (start *core.WSStart, lastSequence int64, err error) {
+	query := req.URL.Query()
+	ephemeral, hasEphemeral := query["ephemeral"]
+	start = &core.WSStart{
+		Ephemeral: hasEphemeral && (len(ephemeral) == 0 || ephemeral[0] != "false"),
+		Namespace: query.Get("namespace"),
+		Name:      query.Get("name"),
+		Filter:    core.NewSubscriptionFilterFromQuery(query),
+	}
+	if start.Namespace == "" || (!start.Ephemeral && start.Name == "") {
+		return nil, -1, i18n.NewError(req.Context(), coremsgs.MsgWSInvalidStartAction)
+	}
+
+	// Browsers send the header when they reconnect, but the query parameter allows a client to resume
+	// on its first connection, as the EventSource API does not allow headers to be set
+	lastEventID := req.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = query.Get("lastEventId")
+	}
+	lastSequence = -1
+	if lastEventID != "" {
+		lastSequence, err = strconv.ParseInt(lastEventID, 10, 64)
+		if err != nil || lastSequence < 0 {
+			return nil, -1, i18n.NewError(req.Context(), coremsgs.MsgSSEInvalidLastEventID, lastEventID)
+		}
+	}
+	return start, lastSequence, nil
+}
+
+func (s *SSE) authorize(req *http.Request, ns string) error {
+	if s.auth != nil {
+		return s.auth.Authorize(req.Context(), &fftypes.AuthReq{
+			Namespace: ns,
+			Header:    req.Header,
+		})
+	}
+	return nil
+}
+
+func (s *SSE) writeError(res http.ResponseWriter, req *http.Request, err error) {
+	log.L(req.Context()).Errorf("SSE request failed: %s", err)
+	status := http.StatusBadRequest
+	if hint, ok := i18n.GetStatusHint(strings.SplitN(err.Error(), ":", 2)[0]); ok {
+		status = hint
+	}
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(status)
+	_ = json.NewEncoder(res).Encode(&fftypes.RESTError{Error: err.Error()})
+}
+
+func (s *SSE) getHandler(namespace string) (events.Callbacks, bool) {
+	s.callbacks.writeLock.Lock()
+	defer s.callbacks.writeLock.Unlock()
+	cb, ok := s.callbacks.handlers[namespace]
+	return cb, ok
+}
+
+func (s *SSE) ack(connID string, inflight *core.EventDeliveryResponse) {
+	if cb, ok := s.getHandler(inflight.Subscription.Namespace); ok {
+		cb.DeliveryResponse(connID, inflight)
+	}
+}
+
+func (s *SSE) connClosed(sc *sseConnection) {
+	sc.close()
+	s.connMux.Lock()
+	delete(s.connections, sc.connID)
+	s.connMux.Unlock()
+	// Drop lock before calling back
+	if cb, ok := s.getHandler(sc.namespace()); ok {
+		cb.ConnectionClosed(sc.connID)
+	}
+}
+
+func (s *SSE) NamespaceRestarted(ns string, startTime time.Time) {
+	s.connMux.Lock()
+	connections := make([]*sseConnection, 0, len(s.connections))
+	for _, sc := range s.connections {
+		connections = append(connections, sc)
+	}
+	s.connMux.Unlock()
+
+	for _, sc := range connections {
+		sc.restartForNamespace(ns, startTime)
+	}
+}
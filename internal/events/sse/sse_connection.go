@@ -0,0 +1,204 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// subscriptionResumer is implemented by the callbacks the subscription manager binds to its
+// event transports. It is not part of the events plugin interface, as only SSE has a use for it.
+type subscriptionResumer interface {
+	ResumeSubscription(name string, lastSequence int64) error
+}
+
+type sseConnection struct {
+	ctx          context.Context
+	s            *SSE
+	res          http.ResponseWriter
+	flusher      http.Flusher
+	reqDone      <-chan struct{}
+	cancelCtx    func()
+	connID       string
+	events       chan *core.EventDelivery
+	mux          sync.Mutex
+	started      *core.WSStart
+	startTime    *fftypes.FFTime
+	lastSequence int64
+}
+
+func newConnection(pCtx context.Context, s *SSE, res http.ResponseWriter, flusher http.Flusher, req *http.Request) *sseConnection {
+	connID := fftypes.NewUUID().String()
+	ctx := log.WithLogField(pCtx, "sse", connID)
+	ctx, cancelCtx := context.WithCancel(ctx)
+	return &sseConnection{
+		ctx:          ctx,
+		s:            s,
+		res:          res,
+		flusher:      flusher,
+		reqDone:      req.Context().Done(),
+		cancelCtx:    cancelCtx,
+		connID:       connID,
+		events:       make(chan *core.EventDelivery),
+		lastSequence: -1,
+	}
+}
+
+func (sc *sseConnection) namespace() string {
+	sc.mux.Lock()
+	defer sc.mux.Unlock()
+	if sc.started == nil {
+		return ""
+	}
+	return sc.started.Namespace
+}
+
+func (sc *sseConnection) start(start *core.WSStart, lastSequence int64) error {
+	sc.mux.Lock()
+	sc.started = start
+	sc.startTime = fftypes.Now()
+	sc.lastSequence = lastSequence
+	sc.mux.Unlock()
+	return sc.register(true)
+}
+
+// register starts the subscription. An ephemeral subscription starts after the last sequence the
+// client received, and a durable subscription is rewound to it if it has already gone past it.
+func (sc *sseConnection) register(resume bool) error {
+	sc.mux.Lock()
+	start := sc.started
+	lastSequence := sc.lastSequence
+	sc.mux.Unlock()
+
+	cb, ok := sc.s.getHandler(start.Namespace)
+	if !ok {
+		return i18n.NewError(sc.ctx, coremsgs.MsgNamespaceDoesNotExist)
+	}
+	if start.Ephemeral {
+		options := start.Options
+		if lastSequence >= 0 {
+			firstEvent := core.SubOptsFirstEvent(strconv.FormatInt(lastSequence, 10))
+			options.FirstEvent = &firstEvent
+		}
+		return cb.EphemeralSubscription(sc.connID, start.Namespace, &start.Filter, &options)
+	}
+	if resume && lastSequence >= 0 {
+		if r, ok := cb.(subscriptionResumer); ok {
+			if err := r.ResumeSubscription(start.Name, lastSequence); err != nil {
+				return err
+			}
+		} else {
+			log.L(sc.ctx).Warnf("Unable to resume subscription '%s' from sequence %d", start.Name, lastSequence)
+		}
+	}
+	return cb.RegisterConnection(sc.connID, func(sr core.SubscriptionRef) bool {
+		return sr.Namespace == start.Namespace && sr.Name == start.Name
+	})
+}
+
+func (sc *sseConnection) sendLoop() {
+	l := log.L(sc.ctx)
+	header := sc.res.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	sc.res.WriteHeader(http.StatusOK)
+	sc.flusher.Flush()
+
+	ping := time.NewTicker(sc.s.pingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case event := <-sc.events:
+			if err := sc.write(event); err != nil {
+				l.Errorf("Write failed on SSE connection: %s", err)
+				return
+			}
+			sc.s.ack(sc.connID, &core.EventDeliveryResponse{
+				ID:           event.ID,
+				Subscription: event.Subscription,
+			})
+		case <-ping.C:
+			if _, err := io.WriteString(sc.res, ": ping\n\n"); err != nil {
+				l.Errorf("Ping failed on SSE connection: %s", err)
+				return
+			}
+			sc.flusher.Flush()
+		case <-sc.reqDone:
+			l.Debugf("SSE connection closed by client")
+			return
+		case <-sc.ctx.Done():
+			l.Debugf("SSE connection closing")
+			return
+		}
+	}
+}
+
+func (sc *sseConnection) write(event *core.EventDelivery) error {
+	b, _ := json.Marshal(event)
+	if _, err := fmt.Fprintf(sc.res, "id: %d\ndata: %s\n\n", event.Sequence, b); err != nil {
+		return err
+	}
+	sc.flusher.Flush()
+	sc.mux.Lock()
+	sc.lastSequence = event.Sequence
+	sc.mux.Unlock()
+	return nil
+}
+
+func (sc *sseConnection) dispatch(event *core.EventDelivery) error {
+	select {
+	case sc.events <- event:
+		return nil
+	case <-sc.ctx.Done():
+		return i18n.NewError(sc.ctx, coremsgs.MsgSSEConnectionNotActive, sc.connID)
+	}
+}
+
+func (sc *sseConnection) restartForNamespace(ns string, startTime time.Time) {
+	sc.mux.Lock()
+	restart := sc.started != nil && sc.started.Namespace == ns && sc.startTime.Time().Before(startTime)
+	if restart {
+		log.L(sc.ctx).Infof("Restarting subscription '%s:%s' (ephemeral=%t)", sc.started.Namespace, sc.started.Name, sc.started.Ephemeral)
+		sc.startTime = fftypes.Now()
+	}
+	sc.mux.Unlock()
+	if restart {
+		// A durable subscription continues from its stored offset, and an ephemeral one from the last event sent
+		if err := sc.register(false); err != nil {
+			log.L(sc.ctx).Errorf("Failed restart subscription (closing): %s", err)
+			sc.close()
+		}
+	}
+}
+
+func (sc *sseConnection) close() {
+	sc.cancelCtx()
+}
@@ -0,0 +1,442 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type resumableCallbacks struct {
+	eventsmocks.Callbacks
+}
+
+func (c *resumableCallbacks) ResumeSubscription(name string, lastSequence int64) error {
+	return c.Called(name, lastSequence).Error(0)
+}
+
+type testAuthorizer struct{}
+
+func (t *testAuthorizer) Authorize(ctx context.Context, authReq *fftypes.AuthReq) error {
+	if authReq.Namespace == "ns1" || authReq.Namespace == "ns2" {
+		return nil
+	}
+	return i18n.NewError(ctx, i18n.MsgUnauthorized)
+}
+
+func newTestSSE(t *testing.T, cbs *resumableCallbacks) (s *SSE, server *httptest.Server, cancel func()) {
+	coreconfig.Reset()
+
+	s = &SSE{}
+	s.SetAuthorizer(&testAuthorizer{})
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	utConfig := config.RootSection("ut.sse")
+	s.InitConfig(utConfig)
+	err := s.Init(ctx, utConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, "sse", s.Name())
+	assert.NotNil(t, s.Capabilities())
+	err = s.SetHandler("ns1", cbs)
+	assert.NoError(t, err)
+
+	server = httptest.NewServer(s)
+	return s, server, func() {
+		server.Close()
+		cancelCtx()
+		cbs.AssertExpectations(t)
+	}
+}
+
+func connect(t *testing.T, server *httptest.Server, query string, header http.Header) (*http.Response, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/sse?%s", server.URL, query), nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	return res, func() {
+		cancel()
+		res.Body.Close()
+	}
+}
+
+func readEvent(t *testing.T, r *bufio.Reader) (id string, event fftypes.JSONObject) {
+	for {
+		line, err := r.ReadString('\n')
+		assert.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			err = json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event)
+			assert.NoError(t, err)
+		case line == "" && event != nil:
+			return id, event
+		}
+	}
+}
+
+func readError(t *testing.T, res *http.Response) string {
+	var body fftypes.JSONObject
+	err := json.NewDecoder(res.Body).Decode(&body)
+	assert.NoError(t, err)
+	return body.GetString("error")
+}
+
+func newTestEvent(sub core.SubscriptionRef, sequence int64) *core.EventDelivery {
+	return &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Sequence:  sequence,
+				Type:      core.EventTypeMessageConfirmed,
+				Namespace: "ns1",
+			},
+		},
+		Subscription: sub,
+	}
+}
+
+func TestDurableSubscriptionResume(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, server, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	registered := make(chan string, 1)
+	cbs.On("ResumeSubscription", "sub1", int64(10)).Return(nil)
+	cbs.On("RegisterConnection", mock.Anything, mock.MatchedBy(func(m func(core.SubscriptionRef) bool) bool {
+		return m(core.SubscriptionRef{Namespace: "ns1", Name: "sub1"}) &&
+			!m(core.SubscriptionRef{Namespace: "ns1", Name: "sub2"})
+	})).Return(nil).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	})
+
+	res, done := connect(t, server, "namespace=ns1&name=sub1&lastEventId=5", http.Header{"Last-Event-Id": []string{"10"}})
+	connID := <-registered
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+	sub := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"}
+	event := newTestEvent(sub, 11)
+	acked := make(chan struct{})
+	cbs.On("DeliveryResponse", connID, mock.MatchedBy(func(r *core.EventDeliveryResponse) bool {
+		return r.ID.Equals(event.ID) && !r.Rejected
+	})).Run(func(args mock.Arguments) { close(acked) })
+	err := s.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, event, nil)
+	assert.NoError(t, err)
+
+	id, body := readEvent(t, bufio.NewReader(res.Body))
+	assert.Equal(t, "11", id)
+	assert.Equal(t, event.ID.String(), body.GetString("id"))
+	assert.Equal(t, "sub1", body.GetObject("subscription").GetString("name"))
+	<-acked
+
+	closed := make(chan struct{})
+	cbs.On("ConnectionClosed", connID).Run(func(args mock.Arguments) { close(closed) })
+	done()
+	<-closed
+
+	err = s.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, event, nil)
+	assert.Regexp(t, "FF10535", err)
+}
+
+func TestDurableSubscriptionResumeFail(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	_, server, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	cbs.On("ResumeSubscription", "sub1", int64(10)).Return(i18n.NewError(context.Background(), i18n.Msg404NotFound))
+	cbs.On("ConnectionClosed", mock.Anything)
+
+	res, done := connect(t, server, "namespace=ns1&name=sub1&lastEventId=10", nil)
+	defer done()
+	assert.Equal(t, 404, res.StatusCode)
+	assert.Regexp(t, "FF00167", readError(t, res))
+}
+
+func TestDurableSubscriptionResumeUnsupported(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, server, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	plain := &eventsmocks.Callbacks{}
+	err := s.SetHandler("ns1", plain)
+	assert.NoError(t, err)
+	registered := make(chan string, 1)
+	plain.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	})
+	plain.On("ConnectionClosed", mock.Anything).Maybe()
+
+	res, done := connect(t, server, "namespace=ns1&name=sub1&lastEventId=10", nil)
+	defer done()
+	<-registered
+	assert.Equal(t, 200, res.StatusCode)
+	plain.AssertNotCalled(t, "ResumeSubscription", mock.Anything, mock.Anything)
+}
+
+func TestEphemeralSubscriptionPing(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, server, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	s.pingInterval = 1 * time.Millisecond
+
+	registered := make(chan string, 1)
+	cbs.On("EphemeralSubscription", mock.Anything, "ns1", mock.MatchedBy(func(f *core.SubscriptionFilter) bool {
+		return f.Topic == "topic1"
+	}), mock.MatchedBy(func(o *core.SubscriptionOptions) bool {
+		return *o.FirstEvent == "10"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	})
+	cbs.On("ConnectionClosed", mock.Anything)
+
+	res, done := connect(t, server, "namespace=ns1&ephemeral&filter.topic=topic1&lastEventId=10", nil)
+	defer done()
+	<-registered
+	line, err := bufio.NewReader(res.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, ": ping\n", line)
+}
+
+func TestNamespaceRestartedEphemeral(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, server, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	registered := make(chan string, 1)
+	cbs.On("EphemeralSubscription", mock.Anything, "ns1", mock.Anything, mock.MatchedBy(func(o *core.SubscriptionOptions) bool {
+		return o.FirstEvent == nil
+	})).Return(nil).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	}).Once()
+	res, done := connect(t, server, "namespace=ns1&ephemeral", nil)
+	defer done()
+	connID := <-registered
+
+	sub := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "ephemeral1"}
+	event := newTestEvent(sub, 20)
+	cbs.On("DeliveryResponse", connID, mock.Anything)
+	err := s.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, event, nil)
+	assert.NoError(t, err)
+	readEvent(t, bufio.NewReader(res.Body))
+
+	// Restarts before the connection started are ignored
+	s.NamespaceRestarted("ns1", time.Now().Add(-1*time.Hour))
+
+	// The restarted subscription continues after the last event sent
+	cbs.On("EphemeralSubscription", connID, "ns1", mock.Anything, mock.MatchedBy(func(o *core.SubscriptionOptions) bool {
+		return o.FirstEvent != nil && *o.FirstEvent == "20"
+	})).Return(nil).Once()
+	s.NamespaceRestarted("ns1", time.Now().Add(1*time.Hour))
+
+	closed := make(chan struct{})
+	cbs.On("EphemeralSubscription", connID, "ns1", mock.Anything, mock.Anything).Return(fmt.Errorf("pop")).Once()
+	cbs.On("ConnectionClosed", connID).Run(func(args mock.Arguments) { close(closed) })
+	s.NamespaceRestarted("ns1", time.Now().Add(2*time.Hour))
+	<-closed
+}
+
+func TestNamespaceRestartedDurable(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, server, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	registered := make(chan string, 1)
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	})
+	cbs.On("ConnectionClosed", mock.Anything)
+	_, done := connect(t, server, "namespace=ns1&name=sub1", nil)
+	defer done()
+	<-registered
+
+	go s.NamespaceRestarted("ns1", time.Now().Add(1*time.Hour))
+	<-registered
+}
+
+func TestStartErrors(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	_, server, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	cbs.On("ConnectionClosed", mock.Anything).Maybe()
+
+	for _, tc := range []struct {
+		query  string
+		status int
+		errMsg string
+	}{
+		{"name=sub1", 400, "FF10178"},
+		{"namespace=ns1", 400, "FF10178"},
+		{"namespace=ns1&name=sub1&lastEventId=bad", 400, "FF10537"},
+		{"namespace=ns1&name=sub1&lastEventId=-1", 400, "FF10537"},
+		{"namespace=ns3&name=sub1", 401, "FF00169"},
+		{"namespace=ns2&name=sub1", 404, "FF10187"},
+	} {
+		res, done := connect(t, server, tc.query, nil)
+		assert.Equal(t, tc.status, res.StatusCode, tc.query)
+		assert.Regexp(t, tc.errMsg, readError(t, res))
+		done()
+	}
+}
+
+func TestNoAuthorizer(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, _, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	s.auth = nil
+
+	req := httptest.NewRequest(http.MethodGet, "/sse?namespace=ns3&name=sub1", nil)
+	assert.NoError(t, s.authorize(req, "ns3"))
+}
+
+type noFlushWriter struct {
+	http.ResponseWriter
+}
+
+func TestStreamingUnsupported(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, _, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	res := httptest.NewRecorder()
+	s.ServeHTTP(&noFlushWriter{ResponseWriter: res}, httptest.NewRequest(http.MethodGet, "/sse", nil))
+	assert.Equal(t, 500, res.Code)
+	assert.Regexp(t, "FF10536", res.Body.String())
+}
+
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	writes chan struct{}
+	once   sync.Once
+}
+
+func (w *failingWriter) Write(b []byte) (int, error) {
+	w.once.Do(func() { close(w.writes) })
+	return 0, fmt.Errorf("pop")
+}
+
+func (w *failingWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func TestWriteFail(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, _, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	registered := make(chan string, 1)
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		registered <- args[0].(string)
+	})
+	cbs.On("ConnectionClosed", mock.Anything)
+
+	w := &failingWriter{ResponseRecorder: httptest.NewRecorder(), writes: make(chan struct{})}
+	served := make(chan struct{})
+	go func() {
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sse?namespace=ns1&name=sub1", nil))
+		close(served)
+	}()
+	connID := <-registered
+
+	sub := core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"}
+	err := s.DeliveryRequest(connID, &core.Subscription{SubscriptionRef: sub}, newTestEvent(sub, 1), nil)
+	assert.NoError(t, err)
+	<-served
+}
+
+func TestPingFail(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, _, cancel := newTestSSE(t, cbs)
+	defer cancel()
+	s.pingInterval = 1 * time.Millisecond
+
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil)
+	cbs.On("ConnectionClosed", mock.Anything)
+
+	w := &failingWriter{ResponseRecorder: httptest.NewRecorder(), writes: make(chan struct{})}
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sse?namespace=ns1&name=sub1", nil))
+	<-w.writes
+}
+
+func TestPluginClosed(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, _, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	s.ctx = ctx
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		cancelCtx()
+	})
+	cbs.On("ConnectionClosed", mock.Anything)
+
+	res := httptest.NewRecorder()
+	s.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/sse?namespace=ns1&name=sub1", nil))
+	assert.Equal(t, 200, res.Code)
+}
+
+func TestDispatchClosed(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, _, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	sc := newConnection(s.ctx, s, httptest.NewRecorder(), nil, httptest.NewRequest(http.MethodGet, "/sse", nil))
+	assert.Equal(t, "", sc.namespace())
+	sc.close()
+	err := sc.dispatch(newTestEvent(core.SubscriptionRef{}, 1))
+	assert.Regexp(t, "FF10535", err)
+	sc.restartForNamespace("ns1", time.Now())
+}
+
+func TestValidateOptions(t *testing.T) {
+	cbs := &resumableCallbacks{}
+	s, _, cancel := newTestSSE(t, cbs)
+	defer cancel()
+
+	yes := true
+	err := s.ValidateOptions(&core.SubscriptionOptions{SubscriptionCoreOptions: core.SubscriptionCoreOptions{WithData: &yes}})
+	assert.Regexp(t, "FF10538", err)
+
+	options := &core.SubscriptionOptions{}
+	err = s.ValidateOptions(options)
+	assert.NoError(t, err)
+	assert.False(t, *options.WithData)
+
+	err = s.SetHandler("ns1", nil)
+	assert.NoError(t, err)
+	assert.Empty(t, s.callbacks.handlers)
+}
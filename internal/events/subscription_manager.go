@@ -45,7 +45,10 @@ type subscription struct {
 	messageFilter      *messageFilter
 	blockchainFilter   *blockchainFilter
 	transactionFilter  *transactionFilter
+	transferFilter     *tokenTransferFilter
 	topicFilter        *regexp.Regexp
+	expressionFilter   *expressionFilter
+	group              *consumerGroup
 }
 
 type messageFilter struct {
@@ -59,6 +62,15 @@ type blockchainFilter struct {
 	listenerFilter *regexp.Regexp
 }
 
+type tokenTransferFilter struct {
+	poolFilter       *regexp.Regexp
+	tokenIndexFilter *regexp.Regexp
+	fromFilter       *regexp.Regexp
+	toFilter         *regexp.Regexp
+	minAmount        *fftypes.FFBigInt
+	maxAmount        *fftypes.FFBigInt
+}
+
 type transactionFilter struct {
 	typeFilter *regexp.Regexp
 }
@@ -86,6 +98,7 @@ type subscriptionManager struct {
 	mux                       sync.Mutex
 	maxSubs                   uint64
 	durableSubs               map[fftypes.UUID]*subscription
+	rules                     *eventRules
 	cancelCtx                 func()
 	newOrUpdatedSubscriptions chan *fftypes.UUID
 	deletedSubscriptions      chan *fftypes.UUID
@@ -103,6 +116,7 @@ func newSubscriptionManager(ctx context.Context, ns *core.Namespace, enricher *e
 		transports:                transports,
 		connections:               make(map[string]*connection),
 		durableSubs:               make(map[fftypes.UUID]*subscription),
+		rules:                     &eventRules{},
 		newOrUpdatedSubscriptions: make(chan *fftypes.UUID),
 		deletedSubscriptions:      make(chan *fftypes.UUID),
 		maxSubs:                   uint64(config.GetUint(coreconfig.SubscriptionMax)),
@@ -249,6 +263,39 @@ func (sm *subscriptionManager) deletedDurableSubscription(id *fftypes.UUID) {
 	}
 }
 
+// rewindDurableSubscription closes any active dispatchers for the subscription before updating its offset,
+// so they cannot commit over the new offset, then restarts them from the new offset
+func (sm *subscriptionManager) rewindDurableSubscription(ctx context.Context, id *fftypes.UUID, offset int64) error {
+	sm.mux.Lock()
+	loaded, dispatchers := sm.closeDurableSubscriptionLocked(id)
+	sm.mux.Unlock()
+
+	for _, dispatcher := range dispatchers {
+		dispatcher.close()
+	}
+	err := sm.database.UpsertOffset(ctx, &core.Offset{
+		Type:    core.OffsetTypeSubscription,
+		Name:    id.String(),
+		Current: offset,
+	}, true)
+	if loaded {
+		sm.newOrUpdatedDurableSubscription(id)
+	}
+	return err
+}
+
+// getDurableDispatcher returns a dispatcher of a durable subscription on any active connection, if there is one
+func (sm *subscriptionManager) getDurableDispatcher(id *fftypes.UUID) *eventDispatcher {
+	sm.mux.Lock()
+	defer sm.mux.Unlock()
+	for _, conn := range sm.connections {
+		if dispatcher, ok := conn.dispatchers[*id]; ok {
+			return dispatcher
+		}
+	}
+	return nil
+}
+
 // nolint: gocyclo
 func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef *core.Subscription) (sub *subscription, err error) {
 	filter := subDef.Filter
@@ -262,6 +309,59 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		return nil, err
 	}
 
+	switch subDef.Options.Format {
+	case "", core.SubOptsFormatNative:
+	case core.SubOptsFormatCloudEvents:
+		if !transport.Capabilities().CloudEvents {
+			return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionFormatNotSupported, subDef.Options.Format, transport.Name())
+		}
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidSubscriptionFormat, subDef.Options.Format)
+	}
+
+	var group *consumerGroup
+	if subDef.Options.ConsumerGroup != nil {
+		if !transport.Capabilities().ConsumerGroups {
+			return nil, i18n.NewError(ctx, coremsgs.MsgConsumerGroupsNotSupported, transport.Name())
+		}
+		if group, err = newConsumerGroup(ctx, subDef.Options.ConsumerGroup); err != nil {
+			return nil, err
+		}
+	}
+
+	if batch := subDef.Options.Batch; batch != nil {
+		if !transport.Capabilities().BatchDelivery {
+			return nil, i18n.NewError(ctx, coremsgs.MsgBatchDeliveryNotSupported, transport.Name())
+		}
+		if batch.Size == 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidBatchOption, "size", batch.Size)
+		}
+		if batch.Timeout != nil && *batch.Timeout <= 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidBatchOption, "timeout", batch.Timeout)
+		}
+		if subDef.Options.Delivery != nil && subDef.Options.Delivery.Mode == core.SubOptsDeliveryModeParallel {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidBatchOption, "size", "batches cannot be delivered in parallel mode")
+		}
+	}
+
+	if subDef.Options.DedupWindow != nil && *subDef.Options.DedupWindow <= 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidDedupWindow, subDef.Options.DedupWindow)
+	}
+
+	if delivery := subDef.Options.Delivery; delivery != nil {
+		switch delivery.Mode {
+		case "", core.SubOptsDeliveryModeOrdered, core.SubOptsDeliveryModeParallel:
+		default:
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidDeliveryOption, "mode", delivery.Mode)
+		}
+		if delivery.MaxInflight != nil && *delivery.MaxInflight == 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidDeliveryOption, "maxInflight", *delivery.MaxInflight)
+		}
+		if delivery.RateLimit < 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidDeliveryOption, "rateLimit", delivery.RateLimit)
+		}
+	}
+
 	var eventFilter *regexp.Regexp
 	if filter.Events != "" {
 		eventFilter, err = regexp.Compile(filter.Events)
@@ -346,6 +446,7 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		definition:         subDef,
 		eventMatcher:       eventFilter,
 		topicFilter:        topicFilter,
+		group:              group,
 		messageFilter: &messageFilter{
 			tagFilter:    tagFilter,
 			groupFilter:  groupFilter,
@@ -353,6 +454,13 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		},
 	}
 
+	if filter.Expression != "" {
+		sub.expressionFilter, err = newExpressionFilter(ctx, "filter.expression", filter.Expression)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if (filter.BlockchainEvent != core.BlockchainEventFilter{}) {
 		var nameFilter *regexp.Regexp
 		if filter.BlockchainEvent.Name != "" {
@@ -377,6 +485,13 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 		sub.blockchainFilter = bf
 	}
 
+	if filter.TokenTransfer != nil {
+		sub.transferFilter, err = parseTokenTransferFilter(ctx, filter.TokenTransfer)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if (filter.Transaction != core.TransactionFilter{}) {
 		var typeFilter *regexp.Regexp
 		if filter.Transaction.Type != "" {
@@ -395,6 +510,54 @@ func (sm *subscriptionManager) parseSubscriptionDef(ctx context.Context, subDef
 	return sub, err
 }
 
+func parseTokenTransferFilter(ctx context.Context, filter *core.TokenTransferFilter) (tf *tokenTransferFilter, err error) {
+	tf = &tokenTransferFilter{
+		minAmount: filter.MinAmount,
+		maxAmount: filter.MaxAmount,
+	}
+	for _, f := range []struct {
+		field  string
+		value  string
+		target **regexp.Regexp
+	}{
+		{"filter.tokentransfer.pool", filter.Pool, &tf.poolFilter},
+		{"filter.tokentransfer.tokenIndex", filter.TokenIndex, &tf.tokenIndexFilter},
+		{"filter.tokentransfer.from", filter.From, &tf.fromFilter},
+		{"filter.tokentransfer.to", filter.To, &tf.toFilter},
+	} {
+		if f.value != "" {
+			*f.target, err = regexp.Compile(f.value)
+			if err != nil {
+				return nil, i18n.WrapError(ctx, err, coremsgs.MsgRegexpCompileFailed, f.field, f.value)
+			}
+		}
+	}
+	if tf.minAmount != nil && tf.maxAmount != nil && tf.minAmount.Int().Cmp(tf.maxAmount.Int()) > 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferFilterAmountRange, tf.minAmount, tf.maxAmount)
+	}
+	return tf, nil
+}
+
+func (tf *tokenTransferFilter) matches(transfer *core.TokenTransfer) bool {
+	switch {
+	case transfer == nil:
+		return false
+	case tf.poolFilter != nil && (transfer.Pool == nil || !tf.poolFilter.MatchString(transfer.Pool.String())):
+		return false
+	case tf.tokenIndexFilter != nil && !tf.tokenIndexFilter.MatchString(transfer.TokenIndex):
+		return false
+	case tf.fromFilter != nil && !tf.fromFilter.MatchString(transfer.From):
+		return false
+	case tf.toFilter != nil && !tf.toFilter.MatchString(transfer.To):
+		return false
+	case tf.minAmount != nil && transfer.Amount.Int().Cmp(tf.minAmount.Int()) < 0:
+		return false
+	case tf.maxAmount != nil && transfer.Amount.Int().Cmp(tf.maxAmount.Int()) > 0:
+		return false
+	}
+	return true
+}
+
 func (sm *subscriptionManager) close() {
 	sm.mux.Lock()
 	conns := make([]*connection, 0, len(sm.connections))
@@ -457,7 +620,7 @@ func (sm *subscriptionManager) matchSubToConnLocked(conn *connection, sub *subsc
 	}
 	if conn.transport == sub.definition.Transport && conn.matcher(sub.definition.SubscriptionRef) {
 		if _, ok := conn.dispatchers[*sub.definition.ID]; !ok {
-			dispatcher := newEventDispatcher(sm.ctx, sm.enricher, conn.ei, sm.database, sm.data, sm.broadcast, sm.messaging, conn.id, sub, sm.eventNotifier, sm.txHelper)
+			dispatcher := newEventDispatcher(sm.ctx, sm.enricher, conn.ei, sm.database, sm.data, sm.broadcast, sm.messaging, conn.id, sub, sm.rules, sm.eventNotifier, sm.txHelper)
 			conn.dispatchers[*sub.definition.ID] = dispatcher
 			dispatcher.start()
 		}
@@ -494,7 +657,7 @@ func (sm *subscriptionManager) ephemeralSubscription(ei events.Plugin, connID, n
 	}
 
 	// Create the dispatcher, and start immediately
-	dispatcher := newEventDispatcher(sm.ctx, sm.enricher, ei, sm.database, sm.data, sm.broadcast, sm.messaging, connID, newSub, sm.eventNotifier, sm.txHelper)
+	dispatcher := newEventDispatcher(sm.ctx, sm.enricher, ei, sm.database, sm.data, sm.broadcast, sm.messaging, connID, newSub, sm.rules, sm.eventNotifier, sm.txHelper)
 	dispatcher.start()
 
 	conn.dispatchers[*subID] = dispatcher
@@ -504,6 +667,51 @@ func (sm *subscriptionManager) ephemeralSubscription(ei events.Plugin, connID, n
 	return nil
 }
 
+func (sm *subscriptionManager) resumeSubscription(ei events.Plugin, name string, lastSequence int64) error {
+	sm.mux.Lock()
+	var sub *subscription
+	for _, s := range sm.durableSubs {
+		if s.definition.Name == name && s.definition.Transport == ei.Name() {
+			sub = s
+			break
+		}
+	}
+	if sub == nil {
+		sm.mux.Unlock()
+		return i18n.NewError(sm.ctx, coremsgs.MsgSubscriptionNotFound, name)
+	}
+	// Any active dispatcher holds its polling offset in memory, and would commit over the top of
+	// our rewind - so we stop them, rewind the stored offset, and then start them afresh
+	var dispatchers []*eventDispatcher
+	for _, conn := range sm.connections {
+		if dispatcher, ok := conn.dispatchers[*sub.definition.ID]; ok {
+			dispatchers = append(dispatchers, dispatcher)
+			delete(conn.dispatchers, *sub.definition.ID)
+		}
+	}
+	sm.mux.Unlock()
+	for _, dispatcher := range dispatchers {
+		dispatcher.close()
+	}
+	defer func() {
+		sm.mux.Lock()
+		defer sm.mux.Unlock()
+		if current, ok := sm.durableSubs[*sub.definition.ID]; ok {
+			for _, conn := range sm.connections {
+				sm.matchSubToConnLocked(conn, current)
+			}
+		}
+	}()
+
+	offset, err := sm.database.GetOffset(sm.ctx, core.OffsetTypeSubscription, sub.definition.ID.String())
+	if err != nil || offset == nil || offset.Current <= lastSequence {
+		return err
+	}
+	log.L(sm.ctx).Infof("Resuming subscription %s:%s from offset %d (was %d)", sm.namespace.Name, name, lastSequence, offset.Current)
+	u := database.OffsetQueryFactory.NewUpdate(sm.ctx).Set("current", lastSequence)
+	return sm.database.UpdateOffset(sm.ctx, offset.RowID, u)
+}
+
 func (sm *subscriptionManager) connectionClosed(ei events.Plugin, connID string) {
 	sm.mux.Lock()
 	conn, ok := sm.connections[connID]
@@ -545,5 +753,11 @@ func (sm *subscriptionManager) deliveryResponse(ei events.Plugin, connID string,
 		return
 	}
 	sm.mux.Unlock()
+	if group := dispatcher.subscription.group; group != nil {
+		// The events of a consumer group are tracked by its elected dispatcher, whichever connection they were delivered to
+		if leader := group.getLeader(); leader != nil {
+			dispatcher = leader
+		}
+	}
 	dispatcher.deliveryResponse(inflight)
 }
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
@@ -296,6 +297,215 @@ func TestCreateSubscriptionBadTransportOptions(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestCreateSubscriptionBadFormat(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{Format: "avro"},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10551.*avro", err)
+}
+
+func TestCreateSubscriptionBadDeliveryOptions(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	zero := uint16(0)
+	for _, tc := range []struct {
+		delivery *core.SubscriptionDeliveryOptions
+		errRegex string
+	}{
+		{&core.SubscriptionDeliveryOptions{Mode: "random"}, "FF10558.*mode.*random"},
+		{&core.SubscriptionDeliveryOptions{MaxInflight: &zero}, "FF10558.*maxInflight"},
+		{&core.SubscriptionDeliveryOptions{RateLimit: -1}, "FF10558.*rateLimit"},
+	} {
+		_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{Delivery: tc.delivery},
+			},
+			Transport: "ut",
+		})
+		assert.Regexp(t, tc.errRegex, err)
+	}
+}
+
+func TestCreateSubscriptionBadDedupWindow(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	window := fftypes.FFDuration(0)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{DedupWindow: &window},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10566", err)
+}
+
+func TestCreateSubscriptionDeliveryOptions(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	five := uint16(5)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Delivery: &core.SubscriptionDeliveryOptions{
+					Mode:        core.SubOptsDeliveryModeParallel,
+					MaxInflight: &five,
+					RateLimit:   2.5,
+				},
+			},
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.SubOptsDeliveryModeParallel, sub.definition.Options.Delivery.Mode)
+}
+
+func TestCreateSubscriptionCloudEventsNotSupported(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{Format: core.SubOptsFormatCloudEvents},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10552.*cloudevents.*ut", err)
+}
+
+func TestCreateSubscriptionCloudEvents(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	mei.On("Capabilities").Return(&events.Capabilities{CloudEvents: true})
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{Format: core.SubOptsFormatCloudEvents},
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.SubOptsFormatCloudEvents, sub.definition.Options.Format)
+}
+
+func TestCreateSubscriptionConsumerGroupNotSupported(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{ConsumerGroup: &core.SubscriptionConsumerGroup{}},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10556.*ut", err)
+}
+
+func TestCreateSubscriptionConsumerGroupBadOption(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	mei.On("Capabilities").Return(&events.Capabilities{ConsumerGroups: true})
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{ConsumerGroup: &core.SubscriptionConsumerGroup{Delivery: "random"}},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10557.*random", err)
+}
+
+func TestCreateSubscriptionConsumerGroup(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	mei.On("Capabilities").Return(&events.Capabilities{ConsumerGroups: true})
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{ConsumerGroup: &core.SubscriptionConsumerGroup{
+				Delivery: core.SubOptsGroupDeliveryPartition,
+			}},
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.SubOptsGroupDeliveryPartition, sub.group.delivery)
+}
+
+func TestCreateSubscriptionBatchNotSupported(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{Batch: &core.SubscriptionBatchOptions{Size: 10}},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10567.*ut", err)
+}
+
+func TestCreateSubscriptionBadBatchOptions(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	mei.On("Capabilities").Return(&events.Capabilities{BatchDelivery: true})
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	zero := fftypes.FFDuration(0)
+	for _, tc := range []struct {
+		batch    *core.SubscriptionBatchOptions
+		delivery *core.SubscriptionDeliveryOptions
+		errRegex string
+	}{
+		{&core.SubscriptionBatchOptions{}, nil, "FF10568.*size"},
+		{&core.SubscriptionBatchOptions{Size: 10, Timeout: &zero}, nil, "FF10568.*timeout"},
+		{&core.SubscriptionBatchOptions{Size: 10}, &core.SubscriptionDeliveryOptions{Mode: core.SubOptsDeliveryModeParallel}, "FF10568.*parallel"},
+	} {
+		_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{Batch: tc.batch, Delivery: tc.delivery},
+			},
+			Transport: "ut",
+		})
+		assert.Regexp(t, tc.errRegex, err)
+	}
+}
+
+func TestCreateSubscriptionBatch(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	mei.On("Capabilities").Return(&events.Capabilities{BatchDelivery: true})
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	timeout := fftypes.FFDuration(50 * time.Millisecond)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{Batch: &core.SubscriptionBatchOptions{Size: 10, Timeout: &timeout}},
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(10), sub.definition.Options.Batch.Size)
+}
+
 func TestCreateSubscriptionBadEventilter(t *testing.T) {
 	mei := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei)
@@ -324,6 +534,68 @@ func TestCreateSubscriptionBadTopicFilter(t *testing.T) {
 	assert.Regexp(t, "FF10171.*topic", err)
 }
 
+func TestCreateSubscriptionBadExpressionFilter(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Filter: core.SubscriptionFilter{
+			Expression: "event.type ==",
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10548.*expression", err)
+}
+
+func TestCreateSubscriptionExpressionFilter(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	sub, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Filter: core.SubscriptionFilter{
+			Expression: "event.type == 'token_transfer_confirmed'",
+		},
+		Transport: "ut",
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, sub.expressionFilter)
+}
+
+func TestCreateSubscriptionBadTokenTransferFilter(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Filter: core.SubscriptionFilter{
+			TokenTransfer: &core.TokenTransferFilter{
+				To: "[[[[! badness",
+			},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10171.*tokentransfer.to", err)
+}
+
+func TestCreateSubscriptionBadTokenTransferAmountRange(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	_, err := sm.parseSubscriptionDef(sm.ctx, &core.Subscription{
+		Filter: core.SubscriptionFilter{
+			TokenTransfer: &core.TokenTransferFilter{
+				MinAmount: fftypes.NewFFBigInt(10),
+				MaxAmount: fftypes.NewFFBigInt(5),
+			},
+		},
+		Transport: "ut",
+	})
+	assert.Regexp(t, "FF10550", err)
+}
+
 func TestCreateSubscriptionBadGroupFilter(t *testing.T) {
 	mei := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei)
@@ -594,6 +866,36 @@ func TestDispatchDeliveryResponseOK(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestDispatchDeliveryResponseConsumerGroupLeader(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+
+	sub := newTestGroupSubscription(t, &core.SubscriptionConsumerGroup{})
+	leader, cancel1 := newTestEventDispatcher(sub)
+	defer cancel1()
+	member, cancel2 := newTestEventDispatcher(sub)
+	defer cancel2()
+	sub.group.elected(leader)
+	sm.connections[member.connID] = &connection{
+		ei: mei,
+		id: member.connID,
+		dispatchers: map[fftypes.UUID]*eventDispatcher{
+			*sub.definition.ID: member,
+		},
+	}
+
+	eventID := fftypes.NewUUID()
+	leader.inflight[*eventID] = &core.Event{ID: eventID, Sequence: 12345}
+	go sm.deliveryResponse(mei, member.connID, &core.EventDeliveryResponse{
+		ID:           eventID,
+		Subscription: sub.definition.SubscriptionRef,
+	})
+	an := <-leader.acksNacks
+	assert.Equal(t, *eventID, an.id)
+	assert.Equal(t, int64(12345), an.offset)
+}
+
 func TestDispatchDeliveryResponseInvalidSubscription(t *testing.T) {
 	mei := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei)
@@ -613,6 +915,98 @@ func TestDispatchDeliveryResponseInvalidSubscription(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestResumeSubscription(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := &databasemocks.Plugin{}
+	sm.database = mdi
+	subID := fftypes.NewUUID()
+	sm.durableSubs[*subID] = &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+			Transport:       "ut",
+		},
+	}
+	be := &boundCallbacks{sm: sm, ei: mei}
+
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subID.String()).Return(&core.Offset{RowID: 12345, Current: 100}, nil)
+	mdi.On("UpdateOffset", mock.Anything, int64(12345), mock.MatchedBy(func(u ffapi.Update) bool {
+		info, _ := u.Finalize()
+		return info.SetOperations[0].Field == "current" && fmt.Sprintf("%v", info.SetOperations[0].Value) == "90"
+	})).Return(nil).Once()
+
+	err := be.ResumeSubscription("sub1", 90)
+	assert.NoError(t, err)
+
+	// The subscription has not yet passed the sequence, so there is nothing to rewind
+	err = be.ResumeSubscription("sub1", 100)
+	assert.NoError(t, err)
+
+	err = be.ResumeSubscription("sub2", 90)
+	assert.Regexp(t, "FF10534", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestResumeSubscriptionRestartsDispatcher(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := &databasemocks.Plugin{}
+	sm.database = mdi
+	subID := fftypes.NewUUID()
+	sub := &subscription{
+		dispatcherElection: make(chan bool, 1),
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+			Transport:       "ut",
+		},
+	}
+	sm.durableSubs[*subID] = sub
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subID.String()).Return(&core.Offset{RowID: 12345, Current: 100}, nil)
+	mdi.On("GetEvents", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Event{}, nil, nil).Maybe()
+	mdi.On("UpdateOffset", mock.Anything, int64(12345), mock.Anything).Return(nil)
+
+	be := &boundCallbacks{sm: sm, ei: mei}
+	err := be.RegisterConnection("conn1", func(sr core.SubscriptionRef) bool { return sr.Name == "sub1" })
+	assert.NoError(t, err)
+	active := sm.connections["conn1"].dispatchers[*subID]
+	assert.NotNil(t, active)
+
+	// The active dispatcher is replaced, so the rewound offset is not overwritten from memory
+	err = be.ResumeSubscription("sub1", 90)
+	assert.NoError(t, err)
+	restarted := sm.connections["conn1"].dispatchers[*subID]
+	assert.NotNil(t, restarted)
+	assert.NotSame(t, active, restarted)
+	<-active.closed
+
+	sm.close()
+	mdi.AssertCalled(t, "UpdateOffset", mock.Anything, int64(12345), mock.Anything)
+}
+
+func TestResumeSubscriptionGetOffsetFail(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := &databasemocks.Plugin{}
+	sm.database = mdi
+	subID := fftypes.NewUUID()
+	sm.durableSubs[*subID] = &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+			Transport:       "ut",
+		},
+	}
+
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, subID.String()).Return(nil, fmt.Errorf("pop"))
+
+	err := sm.resumeSubscription(mei, "sub1", 90)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestConnIDSafetyChecking(t *testing.T) {
 	mei1 := &eventsmocks.Plugin{}
 	sm, cancel := newTestSubManager(t, mei1)
@@ -877,3 +1271,53 @@ func TestDeleteDurableSubscriptionOk(t *testing.T) {
 	assert.Empty(t, sm.durableSubs)
 	<-ed.closed
 }
+
+func TestRewindDurableSubscriptionRestartsDispatchers(t *testing.T) {
+	subID := fftypes.NewUUID()
+	subDef := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        subID,
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: "ut",
+	}
+	sub := &subscription{
+		definition: subDef,
+	}
+	testED1, _ := newTestEventDispatcher(sub)
+
+	mei := testED1.transport.(*eventsmocks.Plugin)
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := sm.database.(*databasemocks.Plugin)
+	mei.On("ValidateOptions", mock.Anything).Return(nil)
+
+	sm.durableSubs[*subID] = sub
+	ed, _ := newTestEventDispatcher(sub)
+	ed.database = mdi
+	ed.start()
+	sm.connections["conn1"] = &connection{
+		ei:        mei,
+		id:        "conn1",
+		transport: "ut",
+		matcher: func(sr core.SubscriptionRef) bool {
+			return false
+		},
+		dispatchers: map[fftypes.UUID]*eventDispatcher{
+			*subID: ed,
+		},
+	}
+
+	mdi.On("UpsertOffset", mock.Anything, mock.MatchedBy(func(o *core.Offset) bool {
+		return o.Name == subID.String() && o.Current == 99
+	}), true).Return(nil)
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subID).Return(subDef, nil)
+	err := sm.rewindDurableSubscription(sm.ctx, subID, 99)
+	assert.NoError(t, err)
+
+	<-ed.closed
+	assert.Empty(t, sm.connections["conn1"].dispatchers)
+	assert.NotNil(t, sm.durableSubs[*subID])
+	assert.NotEqual(t, sub, sm.durableSubs[*subID])
+}
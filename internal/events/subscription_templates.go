@@ -0,0 +1,254 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"sync"
+	"text/template"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// subscriptionTemplates instantiates the subscription templates of the namespace, as contract APIs,
+// token pools and topics are created. It listens to the confirmed events of the namespace on the
+// internal system transport, so only resources created while the node is running are provisioned.
+type subscriptionTemplates struct {
+	mux       sync.Mutex
+	loaded    bool
+	templates []*parsedSubscriptionTemplate
+	// topics we have already provisioned a subscription for, keyed by template ID and topic
+	topics map[string]bool
+}
+
+type parsedSubscriptionTemplate struct {
+	definition *core.SubscriptionTemplate
+	match      *regexp.Regexp
+}
+
+type subscriptionTemplateBody struct {
+	Filter  core.SubscriptionFilter  `json:"filter"`
+	Options core.SubscriptionOptions `json:"options"`
+}
+
+func parseSubscriptionTemplate(ctx context.Context, t *core.SubscriptionTemplate) (*parsedSubscriptionTemplate, error) {
+	pt := &parsedSubscriptionTemplate{definition: t}
+	if t.Match != "" {
+		var err error
+		if pt.match, err = regexp.Compile(t.Match); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionTemplateInvalid, t.Name, err)
+		}
+	}
+	return pt, nil
+}
+
+func (pt *parsedSubscriptionTemplate) matches(name string) bool {
+	return pt.match == nil || pt.match.MatchString(name)
+}
+
+// instantiateSubscriptionTemplate executes the Go template actions in the filter and options of the template
+// against the resource, and returns the resulting subscription definition
+func instantiateSubscriptionTemplate(ctx context.Context, t *core.SubscriptionTemplate, input *core.SubscriptionTemplateInput) (*core.Subscription, error) {
+	body, _ := json.Marshal(&subscriptionTemplateBody{Filter: t.Filter, Options: t.Options})
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(string(body))
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionTemplateInvalid, t.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, input); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionTemplateInvalid, t.Name, err)
+	}
+	var instantiated subscriptionTemplateBody
+	if err := json.Unmarshal(buf.Bytes(), &instantiated); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSubscriptionTemplateInvalid, t.Name, err)
+	}
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: t.Namespace,
+			Name:      t.Name + "-" + input.Name,
+		},
+		Transport: t.Transport,
+		Filter:    instantiated.Filter,
+		Options:   instantiated.Options,
+		Created:   fftypes.Now(),
+	}
+	if err := fftypes.ValidateFFNameFieldNoUUID(ctx, sub.Name, "name"); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (em *eventManager) CreateSubscriptionTemplate(ctx context.Context, t *core.SubscriptionTemplate) (err error) {
+	if t.Transport == "" {
+		t.Transport = em.defaultTransport
+	}
+	if t.Trigger, err = fftypes.FFEnumParseString(ctx, "subtemplatetrigger", t.Trigger.String()); err != nil {
+		return err
+	}
+	if _, err = parseSubscriptionTemplate(ctx, t); err != nil {
+		return err
+	}
+
+	// Check the template produces a valid subscription, by instantiating it for a sample resource
+	sub, err := instantiateSubscriptionTemplate(ctx, t, &core.SubscriptionTemplateInput{
+		Name:    "sample",
+		ID:      fftypes.NewUUID(),
+		Trigger: t.Trigger,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err = em.subManager.parseSubscriptionDef(ctx, sub); err != nil {
+		return err
+	}
+
+	existing, err := em.database.GetSubscriptionTemplateByName(ctx, t.Namespace, t.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return i18n.NewError(ctx, coremsgs.MsgAlreadyExists, "subscription template", t.Namespace, t.Name)
+	}
+	if err = em.database.InsertSubscriptionTemplate(ctx, t); err != nil {
+		return err
+	}
+	em.templates.reset()
+	return nil
+}
+
+func (em *eventManager) DeleteSubscriptionTemplate(ctx context.Context, t *core.SubscriptionTemplate) error {
+	if err := em.database.DeleteSubscriptionTemplateByID(ctx, em.namespace.Name, t.ID); err != nil {
+		return err
+	}
+	em.templates.reset()
+	return nil
+}
+
+func (st *subscriptionTemplates) reset() {
+	st.mux.Lock()
+	defer st.mux.Unlock()
+	st.loaded = false
+	st.templates = nil
+	st.topics = make(map[string]bool)
+}
+
+// get must be called with the lock held
+func (st *subscriptionTemplates) get(ctx context.Context, ns string, di database.Plugin) ([]*parsedSubscriptionTemplate, error) {
+	if st.loaded {
+		return st.templates, nil
+	}
+	templates, _, err := di.GetSubscriptionTemplates(ctx, ns, database.SubscriptionTemplateQueryFactory.NewFilter(ctx).And())
+	if err != nil {
+		return nil, err
+	}
+	st.templates = make([]*parsedSubscriptionTemplate, 0, len(templates))
+	for _, t := range templates {
+		pt, err := parseSubscriptionTemplate(ctx, t)
+		if err != nil {
+			log.L(ctx).Errorf("Skipping subscription template '%s': %s", t.Name, err)
+			continue
+		}
+		st.templates = append(st.templates, pt)
+	}
+	st.loaded = true
+	return st.templates, nil
+}
+
+// subscriptionTemplateListener is the system event listener that provisions subscriptions from the templates
+func (em *eventManager) subscriptionTemplateListener(event *core.EventDelivery) error {
+	var input *core.SubscriptionTemplateInput
+	switch {
+	case event.Type == core.EventTypeContractAPIConfirmed && event.ContractAPI != nil:
+		input = &core.SubscriptionTemplateInput{
+			Name:    event.ContractAPI.Name,
+			ID:      event.ContractAPI.ID,
+			Trigger: core.SubscriptionTemplateTriggerContractAPI,
+		}
+	case event.Type == core.EventTypePoolConfirmed && event.TokenPool != nil:
+		input = &core.SubscriptionTemplateInput{
+			Name:    event.TokenPool.Name,
+			ID:      event.TokenPool.ID,
+			Trigger: core.SubscriptionTemplateTriggerTokenPool,
+		}
+	case event.Type == core.EventTypeMessageConfirmed && event.Topic != "":
+		input = &core.SubscriptionTemplateInput{
+			Name:    event.Topic,
+			Trigger: core.SubscriptionTemplateTriggerTopic,
+		}
+	default:
+		return nil
+	}
+
+	em.templates.mux.Lock()
+	defer em.templates.mux.Unlock()
+	templates, err := em.templates.get(em.ctx, em.namespace.Name, em.database)
+	if err != nil {
+		// We do not block the system event stream, shared with other internal listeners
+		log.L(em.ctx).Errorf("Failed to load subscription templates: %s", err)
+		return nil
+	}
+	for _, pt := range templates {
+		if pt.definition.Trigger != input.Trigger || !pt.matches(input.Name) {
+			continue
+		}
+		topicKey := pt.definition.ID.String() + ":" + input.Name
+		if input.Trigger == core.SubscriptionTemplateTriggerTopic && em.templates.topics[topicKey] {
+			continue
+		}
+		if err := em.provisionSubscription(em.ctx, pt.definition, input); err != nil {
+			log.L(em.ctx).Errorf("Failed to provision subscription from template '%s' for %s '%s': %s", pt.definition.Name, input.Trigger, input.Name, err)
+			continue
+		}
+		if input.Trigger == core.SubscriptionTemplateTriggerTopic {
+			em.templates.topics[topicKey] = true
+		}
+	}
+	return nil
+}
+
+func (em *eventManager) provisionSubscription(ctx context.Context, t *core.SubscriptionTemplate, input *core.SubscriptionTemplateInput) error {
+	sub, err := instantiateSubscriptionTemplate(ctx, t, input)
+	if err != nil {
+		return err
+	}
+	existing, err := em.database.GetSubscriptionByName(ctx, sub.Namespace, sub.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		log.L(ctx).Debugf("Subscription '%s' from template '%s' already exists", sub.Name, t.Name)
+		return nil
+	}
+	if sub.Options.TLSConfigName != "" {
+		if em.namespace.TLSConfigs[sub.Options.TLSConfigName] == nil {
+			return i18n.NewError(ctx, coremsgs.MsgNotFoundTLSConfig, sub.Options.TLSConfigName, sub.Namespace)
+		}
+		sub.Options.TLSConfig = em.namespace.TLSConfigs[sub.Options.TLSConfigName]
+	}
+	log.L(ctx).Infof("Provisioning subscription '%s' from template '%s' for %s '%s'", sub.Name, t.Name, input.Trigger, input.Name)
+	return em.CreateUpdateDurableSubscription(ctx, sub, true)
+}
@@ -0,0 +1,311 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"crypto/tls"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSubscriptionTemplate(trigger core.SubscriptionTemplateTrigger) *core.SubscriptionTemplate {
+	t := &core.SubscriptionTemplate{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Name:      "tmpl1",
+		Trigger:   trigger,
+		Transport: "websockets",
+	}
+	return t
+}
+
+func TestCreateSubscriptionTemplateOk(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate("Token_Pool")
+	tmpl.Transport = ""
+	tmpl.Match = "^erc20"
+	tmpl.Filter.TokenTransfer = &core.TokenTransferFilter{Pool: "^{{.ID}}$"}
+
+	em.mdi.On("GetSubscriptionTemplateByName", em.ctx, "ns1", "tmpl1").Return(nil, nil)
+	em.mdi.On("InsertSubscriptionTemplate", em.ctx, tmpl).Return(nil)
+
+	em.templates.loaded = true
+	err := em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.NoError(t, err)
+	assert.Equal(t, "websockets", tmpl.Transport)
+	assert.Equal(t, core.SubscriptionTemplateTriggerTokenPool, tmpl.Trigger)
+	assert.False(t, em.templates.loaded)
+}
+
+func TestCreateSubscriptionTemplateBadTrigger(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	err := em.CreateSubscriptionTemplate(em.ctx, newTestSubscriptionTemplate("wrong"))
+	assert.Regexp(t, "FF00172", err)
+}
+
+func TestCreateSubscriptionTemplateBadMatch(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	tmpl.Match = "["
+	err := em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.Regexp(t, "FF10580.*tmpl1", err)
+}
+
+func TestCreateSubscriptionTemplateBadTemplate(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	tmpl.Filter.Topic = "{{.Name"
+	err := em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.Regexp(t, "FF10580", err)
+
+	tmpl.Filter.Topic = "{{.Unknown}}"
+	err = em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.Regexp(t, "FF10580", err)
+}
+
+func TestCreateSubscriptionTemplateBadFilter(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	tmpl.Filter.Topic = "[{{.Name}}"
+	err := em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.Regexp(t, "FF10171", err)
+}
+
+func TestCreateSubscriptionTemplateExists(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	em.mdi.On("GetSubscriptionTemplateByName", em.ctx, "ns1", "tmpl1").Return(&core.SubscriptionTemplate{}, nil)
+	err := em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.Regexp(t, "FF10193", err)
+}
+
+func TestCreateSubscriptionTemplateLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	em.mdi.On("GetSubscriptionTemplateByName", em.ctx, "ns1", "tmpl1").Return(nil, fmt.Errorf("pop"))
+	err := em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateSubscriptionTemplateInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	em.mdi.On("GetSubscriptionTemplateByName", em.ctx, "ns1", "tmpl1").Return(nil, nil)
+	em.mdi.On("InsertSubscriptionTemplate", em.ctx, tmpl).Return(fmt.Errorf("pop"))
+	err := em.CreateSubscriptionTemplate(em.ctx, tmpl)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDeleteSubscriptionTemplate(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	em.mdi.On("DeleteSubscriptionTemplateByID", em.ctx, "ns1", tmpl.ID).Return(fmt.Errorf("pop")).Once()
+	em.mdi.On("DeleteSubscriptionTemplateByID", em.ctx, "ns1", tmpl.ID).Return(nil).Once()
+
+	err := em.DeleteSubscriptionTemplate(em.ctx, tmpl)
+	assert.EqualError(t, err, "pop")
+
+	em.templates.loaded = true
+	err = em.DeleteSubscriptionTemplate(em.ctx, tmpl)
+	assert.NoError(t, err)
+	assert.False(t, em.templates.loaded)
+}
+
+func TestInstantiateSubscriptionTemplate(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTokenPool)
+	tmpl.Filter.Events = "token_transfer_confirmed"
+	tmpl.Filter.TokenTransfer = &core.TokenTransferFilter{Pool: "^{{.ID}}$"}
+	tmpl.Options.TransportOptions()["url"] = "https://example.com/{{.Trigger}}/{{.Name}}"
+	poolID := fftypes.NewUUID()
+
+	sub, err := instantiateSubscriptionTemplate(em.ctx, tmpl, &core.SubscriptionTemplateInput{
+		Name:    "pool1",
+		ID:      poolID,
+		Trigger: core.SubscriptionTemplateTriggerTokenPool,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "tmpl1-pool1", sub.Name)
+	assert.Equal(t, "ns1", sub.Namespace)
+	assert.Equal(t, "websockets", sub.Transport)
+	assert.Equal(t, "token_transfer_confirmed", sub.Filter.Events)
+	assert.Equal(t, "^"+poolID.String()+"$", sub.Filter.TokenTransfer.Pool)
+	assert.Equal(t, "https://example.com/token_pool/pool1", sub.Options.TransportOptions().GetString("url"))
+	// The template itself is untouched
+	assert.Equal(t, "^{{.ID}}$", tmpl.Filter.TokenTransfer.Pool)
+
+	_, err = instantiateSubscriptionTemplate(em.ctx, tmpl, &core.SubscriptionTemplateInput{
+		Name: "a-very-long-resource-name-that-does-not-fit-in-a-subscription-name",
+	})
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestInstantiateSubscriptionTemplateBadJSON(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	tmpl.Filter.Topic = `{{.Name}}`
+	_, err := instantiateSubscriptionTemplate(em.ctx, tmpl, &core.SubscriptionTemplateInput{Name: `"`})
+	assert.Regexp(t, "FF10580", err)
+}
+
+func TestSubscriptionTemplateListenerTokenPool(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTokenPool)
+	tmpl.Match = "^erc20"
+	tmpl.Filter.TokenTransfer = &core.TokenTransferFilter{Pool: "^{{.ID}}$"}
+	tmpl.Options.TLSConfigName = "tls1"
+	em.namespace.TLSConfigs = map[string]*tls.Config{"tls1": {}}
+	badTmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTokenPool)
+	badTmpl.Match = "["
+	topicTmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	pool := &core.TokenPool{ID: fftypes.NewUUID(), Name: "erc20pool"}
+
+	em.mdi.On("GetSubscriptionTemplates", em.ctx, "ns1", mock.Anything).Return([]*core.SubscriptionTemplate{badTmpl, tmpl, topicTmpl}, nil, nil).Once()
+	em.mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "tmpl1-erc20pool").Return(nil, nil)
+	em.mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil)
+	em.mdi.On("UpsertSubscription", mock.Anything, mock.MatchedBy(func(sub *core.Subscription) bool {
+		return sub.Name == "tmpl1-erc20pool" &&
+			sub.Filter.TokenTransfer.Pool == "^"+pool.ID.String()+"$" &&
+			sub.Options.TLSConfig != nil
+	}), false).Return(nil).Once()
+
+	err := em.subscriptionTemplateListener(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event:     core.Event{Type: core.EventTypePoolConfirmed},
+			TokenPool: pool,
+		},
+	})
+	assert.NoError(t, err)
+
+	// Not matching the template
+	err = em.subscriptionTemplateListener(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event:     core.Event{Type: core.EventTypePoolConfirmed},
+			TokenPool: &core.TokenPool{ID: fftypes.NewUUID(), Name: "erc721pool"},
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestSubscriptionTemplateListenerContractAPI(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerContractAPI)
+	tmpl.Filter.Topic = "{{.Name}}"
+
+	em.mdi.On("GetSubscriptionTemplates", em.ctx, "ns1", mock.Anything).Return([]*core.SubscriptionTemplate{tmpl}, nil, nil).Once()
+	em.mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "tmpl1-api1").Return(&core.Subscription{}, nil).Once()
+
+	err := em.subscriptionTemplateListener(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event:       core.Event{Type: core.EventTypeContractAPIConfirmed},
+			ContractAPI: &core.ContractAPI{ID: fftypes.NewUUID(), Name: "api1"},
+		},
+	})
+	assert.NoError(t, err)
+}
+
+func TestSubscriptionTemplateListenerTopic(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	tmpl := newTestSubscriptionTemplate(core.SubscriptionTemplateTriggerTopic)
+	tmpl.Filter.Topic = "^{{.Name}}$"
+	tmpl.Options.TLSConfigName = "missing"
+
+	em.mdi.On("GetSubscriptionTemplates", em.ctx, "ns1", mock.Anything).Return([]*core.SubscriptionTemplate{tmpl}, nil, nil).Once()
+	em.mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "tmpl1-topic1").Return(nil, nil).Once()
+	em.mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "tmpl1-topic2").Return(nil, fmt.Errorf("pop")).Once()
+	em.mdi.On("GetSubscriptionByName", mock.Anything, "ns1", "tmpl1-topic2").Return(&core.Subscription{}, nil).Once()
+
+	deliver := func(topic string) {
+		err := em.subscriptionTemplateListener(&core.EventDelivery{
+			EnrichedEvent: core.EnrichedEvent{
+				Event: core.Event{Type: core.EventTypeMessageConfirmed, Topic: topic},
+			},
+		})
+		assert.NoError(t, err)
+	}
+
+	// Fails on the missing TLS config, then fails to look up, then finds the subscription exists
+	deliver("topic1")
+	deliver("topic2")
+	deliver("topic2")
+	// Does not look up again, once a topic is provisioned
+	deliver("topic2")
+	// Topic too long to build a subscription name from
+	deliver("a-very-long-topic-name-that-does-not-fit-in-a-subscription-name")
+	assert.True(t, em.templates.topics[tmpl.ID.String()+":topic2"])
+	assert.False(t, em.templates.topics[tmpl.ID.String()+":topic1"])
+}
+
+func TestSubscriptionTemplateListenerLoadFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetSubscriptionTemplates", em.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Once()
+
+	err := em.subscriptionTemplateListener(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{Type: core.EventTypeMessageConfirmed, Topic: "topic1"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, em.templates.loaded)
+}
+
+func TestSubscriptionTemplateListenerIgnored(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	err := em.subscriptionTemplateListener(&core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{Type: core.EventTypeTransferConfirmed, Topic: "topic1"},
+		},
+	})
+	assert.NoError(t, err)
+}
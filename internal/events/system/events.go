@@ -23,6 +23,8 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/events"
 )
@@ -73,6 +75,10 @@ func (se *Events) Init(ctx context.Context, config config.Section) (err error) {
 func (se *Events) SetHandler(namespace string, handler events.Callbacks) error {
 	se.callbacks.writeLock.Lock()
 	defer se.callbacks.writeLock.Unlock()
+	// Any listeners belong to the previous instance of the namespace
+	se.mux.Lock()
+	delete(se.listeners, namespace)
+	se.mux.Unlock()
 	if handler == nil {
 		delete(se.callbacks.handlers, namespace)
 		return nil
@@ -91,26 +97,41 @@ func (se *Events) ValidateOptions(options *core.SubscriptionOptions) error {
 }
 
 func (se *Events) AddListener(ns string, el EventListener) error {
+	cb, ok := se.callbacks.handlers[ns]
+	if !ok {
+		return nil
+	}
+	se.mux.Lock()
+	first := len(se.listeners[ns]) == 0
+	se.listeners[ns] = append(se.listeners[ns], el)
+	se.mux.Unlock()
+	if !first {
+		return nil
+	}
+
+	// A single ephemeral subscription per namespace delivers each event to all the listeners
 	no := false
 	newest := core.SubOptsFirstEventNewest
-	if cb, ok := se.callbacks.handlers[ns]; ok {
-		err := cb.EphemeralSubscription(se.connID, ns, &core.SubscriptionFilter{ /* all events */ }, &core.SubscriptionOptions{
-			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
-				WithData:   &no,
-				ReadAhead:  &se.readAhead,
-				FirstEvent: &newest,
-			},
-		})
-		if err != nil {
-			return err
-		}
+	err := cb.EphemeralSubscription(se.connID, ns, &core.SubscriptionFilter{ /* all events */ }, &core.SubscriptionOptions{
+		SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+			WithData:   &no,
+			ReadAhead:  &se.readAhead,
+			FirstEvent: &newest,
+		},
+	})
+	if err != nil {
 		se.mux.Lock()
-		se.listeners[ns] = append(se.listeners[ns], el)
+		delete(se.listeners, ns)
 		se.mux.Unlock()
+		return err
 	}
 	return nil
 }
 
+func (se *Events) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(se.ctx, coremsgs.MsgBatchDeliveryNotSupported, se.Name())
+}
+
 func (se *Events) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
 	se.mux.Lock()
 	defer se.mux.Unlock()
@@ -133,6 +133,53 @@ func TestAddListenerFail(t *testing.T) {
 
 	err := se.AddListener("ns1", func(event *core.EventDelivery) error { return nil })
 	assert.EqualError(t, err, "pop")
+	assert.Empty(t, se.listeners["ns1"])
+
+}
+
+func TestAddListenerSharesSubscription(t *testing.T) {
+
+	se, cancel := newTestEvents(t)
+	defer cancel()
+
+	cbs := se.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	cbs.On("EphemeralSubscription", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(nil).Once()
+	cbs.On("DeliveryResponse", se.connID, mock.Anything).Return(nil).Once()
+
+	called := 0
+	for i := 0; i < 2; i++ {
+		err := se.AddListener("ns1", func(event *core.EventDelivery) error {
+			called++
+			return nil
+		})
+		assert.NoError(t, err)
+	}
+
+	err := se.DeliveryRequest(se.connID, &core.Subscription{SubscriptionRef: core.SubscriptionRef{Namespace: "ns1"}}, &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				Namespace: "ns1",
+			},
+		},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, called)
+	cbs.AssertExpectations(t)
+
+	// A new instance of the namespace drops the listeners of the previous one
+	se.SetHandler("ns1", cbs)
+	assert.Empty(t, se.listeners["ns1"])
+
+}
+
+func TestAddListenerNoHandler(t *testing.T) {
+
+	se, cancel := newTestEvents(t)
+	defer cancel()
+
+	err := se.AddListener("ns2", func(event *core.EventDelivery) error { return nil })
+	assert.NoError(t, err)
+	assert.Empty(t, se.listeners["ns2"])
 
 }
 
@@ -0,0 +1,105 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+const tokenLedgerSeedPageSize = 1000
+
+// recordTokenLedgerEntries records a debit against the sending account and a credit to the receiving account of a
+// transfer, once the balances of both have been updated. A mint only credits the recipient, and a burn only debits
+// the owner. When a transfer is invalidated, the reversed transfer is recorded as a new pair of entries, so that
+// the ledger is only ever appended to.
+func (em *eventManager) recordTokenLedgerEntries(ctx context.Context, transfer *core.TokenTransfer, reversal bool) error {
+	if transfer.From != "" {
+		if err := em.recordTokenLedgerEntry(ctx, transfer, transfer.From, transfer.To, true, reversal); err != nil {
+			return err
+		}
+	}
+	if transfer.To != "" {
+		if err := em.recordTokenLedgerEntry(ctx, transfer, transfer.To, transfer.From, false, reversal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (em *eventManager) recordTokenLedgerEntry(ctx context.Context, transfer *core.TokenTransfer, account, counterparty string, debit, reversal bool) error {
+	entry := &core.TokenLedgerEntry{
+		ID:              fftypes.NewUUID(),
+		Namespace:       transfer.Namespace,
+		Pool:            transfer.Pool,
+		Account:         account,
+		Counterparty:    counterparty,
+		TokenIndex:      transfer.TokenIndex,
+		TransferType:    transfer.Type,
+		Transfer:        transfer.LocalID,
+		Reversal:        reversal,
+		TX:              transfer.TX,
+		BlockchainEvent: transfer.BlockchainEvent,
+	}
+	if debit {
+		entry.Debit.Int().Set(transfer.Amount.Int())
+	} else {
+		entry.Credit.Int().Set(transfer.Amount.Int())
+	}
+
+	fb := database.TokenLedgerQueryFactory.NewFilter(ctx)
+	previous, _, err := em.database.GetTokenLedgerEntries(ctx, transfer.Namespace, fb.And(
+		fb.Eq("pool", transfer.Pool),
+		fb.Eq("account", account),
+	).Sort("-sequence").Limit(1))
+	if err != nil {
+		return err
+	}
+	// The running balance carries on from the previous entry for the account. The first entry for an account takes its
+	// running balance from the balances already updated for this transfer, which carries forward any transfers that
+	// were confirmed before the ledger was introduced.
+	if len(previous) > 0 {
+		entry.Balance.Int().Add(previous[0].Balance.Int(), entry.Credit.Int())
+		entry.Balance.Int().Sub(entry.Balance.Int(), entry.Debit.Int())
+	} else if err := em.sumTokenBalances(ctx, transfer.Namespace, transfer.Pool, account, &entry.Balance); err != nil {
+		return err
+	}
+	return em.database.InsertTokenLedgerEntry(ctx, entry)
+}
+
+func (em *eventManager) sumTokenBalances(ctx context.Context, namespace string, pool *fftypes.UUID, key string, total *fftypes.FFBigInt) error {
+	fb := database.TokenBalanceQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("pool", pool),
+		fb.Eq("key", key),
+	).Limit(tokenLedgerSeedPageSize)
+	for skip := uint64(0); ; skip += tokenLedgerSeedPageSize {
+		balances, _, err := em.database.GetTokenBalances(ctx, namespace, filter.Skip(skip))
+		if err != nil {
+			return err
+		}
+		for _, balance := range balances {
+			total.Int().Add(total.Int(), balance.Balance.Int())
+		}
+		if len(balances) < tokenLedgerSeedPageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLedgerTransfer() *core.TokenTransfer {
+	transfer := &core.TokenTransfer{
+		Type:      core.TokenTransferTypeTransfer,
+		LocalID:   fftypes.NewUUID(),
+		Namespace: "ns1",
+		Pool:      fftypes.NewUUID(),
+		From:      "0x1",
+		To:        "0x2",
+	}
+	transfer.Amount.Int().SetInt64(10)
+	return transfer
+}
+
+func TestRecordTokenLedgerEntriesFromPrevious(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newLedgerTransfer()
+	previous := &core.TokenLedgerEntry{}
+	previous.Balance.Int().SetInt64(100)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{previous}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.MatchedBy(func(e *core.TokenLedgerEntry) bool {
+		return e.Account == "0x1" && e.Counterparty == "0x2" && e.Debit.Int().Int64() == 10 &&
+			e.Credit.Int().Int64() == 0 && e.Balance.Int().Int64() == 90 && !e.Reversal
+	})).Return(nil).Once()
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.MatchedBy(func(e *core.TokenLedgerEntry) bool {
+		return e.Account == "0x2" && e.Counterparty == "0x1" && e.Credit.Int().Int64() == 10 &&
+			e.Debit.Int().Int64() == 0 && e.Balance.Int().Int64() == 110 && !e.Reversal
+	})).Return(nil).Once()
+
+	err := em.recordTokenLedgerEntries(em.ctx, transfer, false)
+	assert.NoError(t, err)
+}
+
+func TestRecordTokenLedgerEntriesSeedFromBalances(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newLedgerTransfer()
+	transfer.From = ""
+	transfer.Type = core.TokenTransferTypeMint
+	fullPage := make([]*core.TokenBalance, tokenLedgerSeedPageSize)
+	for i := range fullPage {
+		fullPage[i] = &core.TokenBalance{}
+		fullPage[i].Balance.Int().SetInt64(1)
+	}
+	lastPage := &core.TokenBalance{}
+	lastPage.Balance.Int().SetInt64(5)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{}, nil, nil)
+	em.mdi.On("GetTokenBalances", em.ctx, "ns1", mock.Anything).Return(fullPage, nil, nil).Once()
+	em.mdi.On("GetTokenBalances", em.ctx, "ns1", mock.Anything).Return([]*core.TokenBalance{lastPage}, nil, nil).Once()
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.MatchedBy(func(e *core.TokenLedgerEntry) bool {
+		return e.Account == "0x2" && e.Counterparty == "" && e.Credit.Int().Int64() == 10 &&
+			e.Balance.Int().Int64() == tokenLedgerSeedPageSize+5
+	})).Return(nil).Once()
+
+	err := em.recordTokenLedgerEntries(em.ctx, transfer, false)
+	assert.NoError(t, err)
+}
+
+func TestRecordTokenLedgerEntriesReversal(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newLedgerTransfer()
+	transfer.To = ""
+	transfer.Type = core.TokenTransferTypeBurn
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.MatchedBy(func(e *core.TokenLedgerEntry) bool {
+		return e.Account == "0x1" && e.Debit.Int().Int64() == 10 && e.Balance.Int().Int64() == -10 && e.Reversal
+	})).Return(nil).Once()
+
+	err := em.recordTokenLedgerEntries(em.ctx, transfer, true)
+	assert.NoError(t, err)
+}
+
+func TestRecordTokenLedgerEntriesQueryFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.recordTokenLedgerEntries(em.ctx, newLedgerTransfer(), false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRecordTokenLedgerEntriesBalancesFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{}, nil, nil)
+	em.mdi.On("GetTokenBalances", em.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.recordTokenLedgerEntries(em.ctx, newLedgerTransfer(), false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRecordTokenLedgerEntriesInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(fmt.Errorf("pop")).Once()
+
+	err := em.recordTokenLedgerEntries(em.ctx, newLedgerTransfer(), false)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRecordTokenLedgerEntriesCreditFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(nil).Once()
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(fmt.Errorf("pop")).Once()
+
+	err := em.recordTokenLedgerEntries(em.ctx, newLedgerTransfer(), false)
+	assert.EqualError(t, err, "pop")
+}
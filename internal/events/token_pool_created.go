@@ -47,6 +47,7 @@ func addPoolDetailsFromPlugin(ffPool *core.TokenPool, pluginPool *tokens.TokenPo
 			return fmt.Errorf("token symbol '%s' from blockchain does not match stored symbol '%s'", pluginPool.Symbol, ffPool.Symbol)
 		}
 	}
+	ffPool.IdentityRegistry = pluginPool.IdentityRegistry
 	ffPool.Info = pluginPool.Info
 	return nil
 }
@@ -132,6 +133,7 @@ func (em *eventManager) loadFromOperation(ctx context.Context, pool *tokens.Toke
 func (em *eventManager) TokenPoolCreated(ctx context.Context, ti tokens.Plugin, pool *tokens.TokenPool) (err error) {
 	var msgIDforRewind *fftypes.UUID
 	var stagedPool *core.TokenPool
+	var migratedPool *core.TokenPool
 
 	err = em.retry.Do(ctx, "persist token pool transaction", func(attempt int) (bool, error) {
 		err := em.database.RunAsGroup(ctx, func(ctx context.Context) error {
@@ -141,7 +143,12 @@ func (em *eventManager) TokenPoolCreated(ctx context.Context, ti tokens.Plugin,
 				return err
 			}
 			if existingPool != nil {
-				if existingPool.State == core.TokenPoolStateConfirmed {
+				if existingPool.State == core.TokenPoolStateMigrating {
+					// See if this is the new connector resolving a pool that is being migrated
+					migratedPool, err = em.assets.CompleteTokenPoolMigration(ctx, pool)
+					return err
+				}
+				if existingPool.State != core.TokenPoolStatePending {
 					log.L(ctx).Debugf("Token pool ID=%s Locator='%s' already confirmed", existingPool.ID, pool.PoolLocator)
 					return nil // already confirmed
 				}
@@ -161,6 +168,12 @@ func (em *eventManager) TokenPoolCreated(ctx context.Context, ti tokens.Plugin,
 				return nil // trigger publish after completion of database transaction
 			}
 
+			// See if this is the new connector resolving a pool that is being migrated
+			// (the pool ID is only known to the connector if it resolved the pool synchronously)
+			if migratedPool, err = em.assets.CompleteTokenPoolMigration(ctx, pool); err != nil || migratedPool != nil {
+				return err
+			}
+
 			// Otherwise this event can be ignored
 			var protoID string
 			if pool.Event != nil {
@@ -192,6 +205,12 @@ func (em *eventManager) TokenPoolCreated(ctx context.Context, ti tokens.Plugin,
 			log.L(ctx).Infof("Defining token pool, id=%s", stagedPool.ID)
 			err = em.defsender.DefineTokenPool(ctx, stagedPool, false)
 		}
+
+		if migratedPool != nil {
+			// Start the new connector delivering events for the migrated pool
+			log.L(ctx).Infof("Activating migrated token pool, id=%s", migratedPool.ID)
+			err = em.assets.ActivateTokenPool(ctx, migratedPool)
+		}
 	}
 
 	return err
@@ -54,6 +54,7 @@ func TestTokenPoolCreatedIgnore(t *testing.T) {
 
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "123").Return(nil, nil, nil)
 	em.mth.On("FindOperationInTransaction", em.ctx, txID, core.OpTypeTokenCreatePool).Return(nil, nil)
+	em.mam.On("CompleteTokenPoolMigration", em.ctx, pool).Return(nil, nil)
 
 	err := em.TokenPoolCreated(em.ctx, mti, pool)
 	assert.NoError(t, err)
@@ -99,9 +100,10 @@ func TestTokenPoolCreatedConfirm(t *testing.T) {
 			ID:   txID,
 			Type: core.TransactionTypeTokenPool,
 		},
-		Standard: "ERC1155",
-		Symbol:   "FFT",
-		Info:     info1,
+		Standard:         "ERC1155",
+		Symbol:           "FFT",
+		IdentityRegistry: "0xregistry",
+		Info:             info1,
 		Event: &blockchain.Event{
 			BlockchainTXID: "0xffffeeee",
 			Name:           "TokenPool",
@@ -139,6 +141,7 @@ func TestTokenPoolCreatedConfirm(t *testing.T) {
 
 	assert.Equal(t, "ERC1155", storedPool.Standard)
 	assert.Equal(t, "FFT", storedPool.Symbol)
+	assert.Equal(t, "0xregistry", storedPool.IdentityRegistry)
 	assert.Equal(t, info1, storedPool.Info)
 
 }
@@ -181,6 +184,80 @@ func TestTokenPoolCreatedAlreadyConfirmed(t *testing.T) {
 
 }
 
+func TestTokenPoolCreatedMigrating(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	mti := &tokenmocks.Plugin{}
+
+	poolID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	chainPool := &tokens.TokenPool{
+		ID:          poolID,
+		Type:        core.TokenTypeFungible,
+		PoolLocator: "address=0x12345",
+		Connector:   "erc20_erc721",
+		TX: core.TransactionRef{
+			ID:   txID,
+			Type: core.TransactionTypeTokenPool,
+		},
+	}
+	storedPool := &core.TokenPool{
+		Namespace: "ns1",
+		ID:        poolID,
+		State:     core.TokenPoolStateMigrating,
+		Connector: "erc1155",
+		Locator:   "123",
+	}
+	migratedPool := &core.TokenPool{
+		Namespace: "ns1",
+		ID:        poolID,
+		State:     core.TokenPoolStateConfirmed,
+		Connector: "erc20_erc721",
+		Locator:   "address=0x12345",
+	}
+
+	em.mam.On("GetTokenPoolByID", em.ctx, poolID).Return(storedPool, nil)
+	em.mam.On("CompleteTokenPoolMigration", em.ctx, chainPool).Return(migratedPool, nil)
+	em.mam.On("ActivateTokenPool", em.ctx, migratedPool).Return(nil)
+
+	err := em.TokenPoolCreated(em.ctx, mti, chainPool)
+	assert.NoError(t, err)
+
+}
+
+func TestTokenPoolCreatedMigratedAsync(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	mti := &tokenmocks.Plugin{}
+
+	txID := fftypes.NewUUID()
+	chainPool := &tokens.TokenPool{
+		Type:        core.TokenTypeFungible,
+		PoolLocator: "address=0x12345",
+		Connector:   "erc20_erc721",
+		TX: core.TransactionRef{
+			ID:   txID,
+			Type: core.TransactionTypeTokenPool,
+		},
+	}
+	migratedPool := &core.TokenPool{
+		Namespace: "ns1",
+		ID:        fftypes.NewUUID(),
+		State:     core.TokenPoolStateConfirmed,
+		Connector: "erc20_erc721",
+		Locator:   "address=0x12345",
+	}
+
+	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc20_erc721", "address=0x12345").Return(nil, nil)
+	em.mth.On("FindOperationInTransaction", em.ctx, txID, core.OpTypeTokenCreatePool).Return(nil, nil)
+	em.mam.On("CompleteTokenPoolMigration", em.ctx, chainPool).Return(migratedPool, nil)
+	em.mam.On("ActivateTokenPool", em.ctx, migratedPool).Return(fmt.Errorf("pop"))
+
+	err := em.TokenPoolCreated(em.ctx, mti, chainPool)
+	assert.EqualError(t, err, "pop")
+
+}
+
 func TestTokenPoolCreatedConfirmFailBadSymbol(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -219,6 +296,7 @@ func TestTokenPoolCreatedConfirmFailBadSymbol(t *testing.T) {
 	em.mth.On("FindOperationInTransaction", em.ctx, txID, core.OpTypeTokenCreatePool).Return(&core.Operation{
 		ID: opID,
 	}, nil)
+	em.mam.On("CompleteTokenPoolMigration", em.ctx, chainPool).Return(nil, nil)
 
 	err := em.TokenPoolCreated(em.ctx, mti, chainPool)
 	assert.NoError(t, err)
@@ -446,6 +524,7 @@ func TestTokenPoolCreatedPublishBadOpInputID(t *testing.T) {
 
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "123").Return(nil, nil)
 	em.mth.On("FindOperationInTransaction", em.ctx, txID, core.OpTypeTokenCreatePool).Return(operation, nil)
+	em.mam.On("CompleteTokenPoolMigration", em.ctx, pool).Return(nil, nil)
 
 	err := em.TokenPoolCreated(em.ctx, mti, pool)
 	assert.NoError(t, err)
@@ -483,6 +562,7 @@ func TestTokenPoolCreatedPublishBadOpInputNS(t *testing.T) {
 
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "123").Return(nil, nil)
 	em.mth.On("FindOperationInTransaction", em.ctx, txID, core.OpTypeTokenCreatePool).Return(operation, nil)
+	em.mam.On("CompleteTokenPoolMigration", em.ctx, pool).Return(nil, nil)
 
 	err := em.TokenPoolCreated(em.ctx, mti, pool)
 	assert.NoError(t, err)
@@ -525,6 +605,7 @@ func TestTokenPoolCreatedPublishBadSymbol(t *testing.T) {
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "123").Return(nil, nil).Times(2)
 	em.mth.On("FindOperationInTransaction", em.ctx, txID, core.OpTypeTokenCreatePool).Return(nil, fmt.Errorf("pop")).Once()
 	em.mth.On("FindOperationInTransaction", em.ctx, txID, core.OpTypeTokenCreatePool).Return(operation, nil).Once()
+	em.mam.On("CompleteTokenPoolMigration", em.ctx, pool).Return(nil, nil)
 
 	err := em.TokenPoolCreated(em.ctx, mti, pool)
 	assert.NoError(t, err)
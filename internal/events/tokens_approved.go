@@ -36,6 +36,8 @@ import (
 //     allowed to trigger side-effects in other pools, but only the event from the targeted pool should use the original LocalID.
 //   - The LocalID must not have been used yet. Connectors are allowed to emit multiple events in response to a single operation,
 //     but only the first of them can use the original LocalID.
+//
+// When the LocalID is reused, any expiry requested on the original approval is also carried over.
 func (em *eventManager) loadApprovalID(ctx context.Context, tx *fftypes.UUID, approval *core.TokenApproval) (*fftypes.UUID, error) {
 	op, err := em.txHelper.FindOperationInTransaction(ctx, tx, core.OpTypeTokenApproval)
 	if err != nil {
@@ -52,6 +54,7 @@ func (em *eventManager) loadApprovalID(ctx context.Context, tx *fftypes.UUID, ap
 				return nil, err
 			} else if existing == nil {
 				// Everything matches - use the LocalID that was assigned up-front when the operation was submitted
+				approval.Expiry = input.Expiry
 				return input.LocalID, nil
 			}
 		}
@@ -415,3 +415,29 @@ func TestTokensApprovedWithMessageSend(t *testing.T) {
 
 	mti.AssertExpectations(t)
 }
+
+func TestApprovedWithTransactionCarriesExpiry(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	approval := newApproval()
+	approval.Pool = fftypes.NewUUID()
+	localID := fftypes.NewUUID()
+	expiry := fftypes.Now()
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"localId":   localID.String(),
+			"connector": approval.Connector,
+			"pool":      approval.Pool.String(),
+			"expiry":    expiry.String(),
+		},
+	}
+
+	em.mth.On("FindOperationInTransaction", em.ctx, approval.TX.ID, core.OpTypeTokenApproval).Return(op, nil)
+	em.mdi.On("GetTokenApprovalByID", em.ctx, "ns1", localID).Return(nil, nil)
+
+	id, err := em.loadApprovalID(em.ctx, approval.TX.ID, &approval.TokenApproval)
+	assert.NoError(t, err)
+	assert.Equal(t, *localID, *id)
+	assert.Equal(t, expiry.UnixNano(), approval.Expiry.UnixNano())
+}
@@ -22,7 +22,9 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/hyperledger/firefly/pkg/tokens"
 )
 
@@ -35,23 +37,52 @@ import (
 //     allowed to trigger side-effects in other pools, but only the event from the targeted pool should use the original LocalID.
 //   - The LocalID must not have been used yet. Connectors are allowed to emit multiple events in response to a single operation,
 //     but only the first of them can use the original LocalID.
+//   - For a batch transfer operation, the token index and amount must also match one of the legs of the batch.
 func (em *eventManager) loadTransferID(ctx context.Context, tx *fftypes.UUID, transfer *core.TokenTransfer) (*fftypes.UUID, error) {
 	op, err := em.txHelper.FindOperationInTransaction(ctx, tx, core.OpTypeTokenTransfer)
 	if err != nil {
 		return nil, err
 	}
+	if op == nil {
+		return em.loadTransferBatchLegID(ctx, tx, transfer)
+	}
+
+	// This transfer matches a transfer transaction+operation submitted by this node.
+	// Check the operation inputs to see if they match the connector and pool on this event.
+	if input, err := txcommon.RetrieveTokenTransferInputs(ctx, op); err != nil {
+		log.L(ctx).Warnf("Failed to read operation inputs for token transfer '%s': %s", transfer.ProtocolID, err)
+	} else if input != nil && input.Connector == transfer.Connector && input.Pool.Equals(transfer.Pool) {
+		// Check if the LocalID has already been used
+		if existing, err := em.database.GetTokenTransferByID(ctx, em.namespace.Name, input.LocalID); err != nil {
+			return nil, err
+		} else if existing == nil {
+			// Everything matches - use the LocalID that was assigned up-front when the operation was submitted
+			return input.LocalID, nil
+		}
+	}
+
+	return fftypes.NewUUID(), nil
+}
+
+func (em *eventManager) loadTransferBatchLegID(ctx context.Context, tx *fftypes.UUID, transfer *core.TokenTransfer) (*fftypes.UUID, error) {
+	op, err := em.txHelper.FindOperationInTransaction(ctx, tx, core.OpTypeTokenTransferBatch)
+	if err != nil {
+		return nil, err
+	}
 	if op != nil {
-		// This transfer matches a transfer transaction+operation submitted by this node.
-		// Check the operation inputs to see if they match the connector and pool on this event.
-		if input, err := txcommon.RetrieveTokenTransferInputs(ctx, op); err != nil {
-			log.L(ctx).Warnf("Failed to read operation inputs for token transfer '%s': %s", transfer.ProtocolID, err)
-		} else if input != nil && input.Connector == transfer.Connector && input.Pool.Equals(transfer.Pool) {
-			// Check if the LocalID has already been used
-			if existing, err := em.database.GetTokenTransferByID(ctx, em.namespace.Name, input.LocalID); err != nil {
-				return nil, err
-			} else if existing == nil {
-				// Everything matches - use the LocalID that was assigned up-front when the operation was submitted
-				return input.LocalID, nil
+		legs, err := txcommon.RetrieveTokenTransferBatchInputs(ctx, op)
+		if err != nil {
+			log.L(ctx).Warnf("Failed to read operation inputs for token transfer batch '%s': %s", transfer.ProtocolID, err)
+		}
+		for _, leg := range legs {
+			if leg.Connector == transfer.Connector && leg.Pool.Equals(transfer.Pool) &&
+				leg.TokenIndex == transfer.TokenIndex && leg.Amount.Int().Cmp(transfer.Amount.Int()) == 0 {
+				// Legs with the same index and amount are assigned in order
+				if existing, err := em.database.GetTokenTransferByID(ctx, em.namespace.Name, leg.LocalID); err != nil {
+					return nil, err
+				} else if existing == nil {
+					return leg.LocalID, nil
+				}
 			}
 		}
 	}
@@ -59,15 +90,15 @@ func (em *eventManager) loadTransferID(ctx context.Context, tx *fftypes.UUID, tr
 	return fftypes.NewUUID(), nil
 }
 
-func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *tokens.TokenTransfer) (valid bool, err error) {
+func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *tokens.TokenTransfer) (valid, reconfirmed bool, err error) {
 	// Check that this is from a known pool
 	pool, err := em.getPoolByIDOrLocator(ctx, transfer.Pool, transfer.Connector, transfer.PoolLocator)
 	if err != nil {
-		return false, err
+		return false, false, err
 	}
 	if pool == nil {
 		log.L(ctx).Infof("Token transfer received for unknown pool '%s' - ignoring: %s", transfer.PoolLocator, transfer.Event.ProtocolID)
-		return false, nil
+		return false, false, nil
 	}
 	transfer.Namespace = pool.Namespace
 	transfer.Pool = pool.ID
@@ -76,10 +107,10 @@ func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *toke
 		transfer.LocalID = fftypes.NewUUID()
 	} else {
 		if transfer.LocalID, err = em.loadTransferID(ctx, transfer.TX.ID, &transfer.TokenTransfer); err != nil {
-			return false, err
+			return false, false, err
 		}
 		if valid, err := em.txHelper.PersistTransaction(ctx, transfer.TX.ID, transfer.TX.Type, transfer.Event.BlockchainTXID); err != nil || !valid {
-			return valid, err
+			return valid, false, err
 		}
 	}
 
@@ -90,7 +121,7 @@ func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *toke
 		BlockchainID: transfer.Event.BlockchainTXID,
 	})
 	if err := em.maybePersistBlockchainEvent(ctx, chainEvent, nil); err != nil {
-		return false, err
+		return false, false, err
 	}
 	em.emitBlockchainEventMetric(transfer.Event)
 	transfer.BlockchainEvent = chainEvent.ID
@@ -100,16 +131,32 @@ func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *toke
 
 	if err != nil {
 		log.L(ctx).Errorf("Failed to record token transfer '%s': %s", transfer.ProtocolID, err)
-		return false, err
+		return false, false, err
 	}
-	if existing != nil {
+	if existing != nil && existing.Invalidated {
+		// The transfer was reversed by a chain reorganization, and has now been re-confirmed
+		log.L(ctx).Infof("Token transfer %s re-confirmed after previous invalidation", existing.ProtocolID)
+		update := database.TokenTransferQueryFactory.NewUpdate(ctx).Set("invalidated", false)
+		if err := em.database.UpdateTokenTransfer(ctx, existing.Namespace, existing.LocalID, update); err != nil {
+			return false, false, err
+		}
+		transfer.LocalID = existing.LocalID
+		reconfirmed = true
+	} else if existing != nil {
 		log.L(ctx).Debugf("Ignoring duplicate token transfer event %s", existing.ProtocolID)
-		return false, nil
+		return false, false, nil
 	}
 
 	if err := em.database.UpdateTokenBalances(ctx, &transfer.TokenTransfer); err != nil {
 		log.L(ctx).Errorf("Failed to update accounts %s -> %s for token transfer '%s': %s", transfer.From, transfer.To, transfer.ProtocolID, err)
-		return false, err
+		return false, false, err
+	}
+	if err := em.recordTokenLedgerEntries(ctx, &transfer.TokenTransfer, false); err != nil {
+		return false, false, err
+	}
+
+	if err := em.persistCreatedTokenAccounts(ctx, transfer); err != nil {
+		return false, false, err
 	}
 
 	log.L(ctx).Infof("Token transfer recorded id=%s author=%s", transfer.ProtocolID, transfer.Key)
@@ -117,7 +164,38 @@ func (em *eventManager) persistTokenTransfer(ctx context.Context, transfer *toke
 		em.metrics.TransferConfirmed(&transfer.TokenTransfer)
 	}
 
-	return true, nil
+	return true, reconfirmed, nil
+}
+
+// persistCreatedTokenAccounts records the token accounts that the connector reported were created by this transfer,
+// such as the associated token account created for the recipient of their first SPL tokens in a pool. An owner only
+// has one associated account in each pool, so an account that has already been recorded (such as when a transfer is
+// re-confirmed after a chain reorganization) is left unchanged.
+func (em *eventManager) persistCreatedTokenAccounts(ctx context.Context, transfer *tokens.TokenTransfer) error {
+	for _, created := range transfer.CreatedAccounts {
+		existing, err := em.database.GetTokenAssociatedAccount(ctx, transfer.Namespace, transfer.Pool, created.Owner)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			log.L(ctx).Debugf("Token account '%s' for owner '%s' already recorded", existing.Account, existing.Owner)
+			continue
+		}
+		account := &core.TokenAssociatedAccount{
+			Pool:            transfer.Pool,
+			Namespace:       transfer.Namespace,
+			Owner:           created.Owner,
+			Account:         created.Account,
+			Payer:           created.Payer,
+			TX:              transfer.TX,
+			BlockchainEvent: transfer.BlockchainEvent,
+		}
+		if err := em.database.InsertTokenAssociatedAccount(ctx, account); err != nil {
+			return err
+		}
+		log.L(ctx).Infof("Token account created owner=%s account=%s payer=%s", account.Owner, account.Account, account.Payer)
+	}
+	return nil
 }
 
 func (em *eventManager) TokensTransferred(ti tokens.Plugin, transfer *tokens.TokenTransfer) error {
@@ -125,7 +203,8 @@ func (em *eventManager) TokensTransferred(ti tokens.Plugin, transfer *tokens.Tok
 
 	err := em.retry.Do(em.ctx, "persist token transfer", func(attempt int) (bool, error) {
 		err := em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
-			if valid, err := em.persistTokenTransfer(ctx, transfer); !valid || err != nil {
+			valid, reconfirmed, err := em.persistTokenTransfer(ctx, transfer)
+			if !valid || err != nil {
 				return err
 			}
 
@@ -147,7 +226,11 @@ func (em *eventManager) TokensTransferred(ti tokens.Plugin, transfer *tokens.Tok
 			}
 			em.emitBlockchainEventMetric(transfer.Event)
 
-			event := core.NewEvent(core.EventTypeTransferConfirmed, transfer.Namespace, transfer.LocalID, transfer.TX.ID, transfer.Pool.String())
+			eventType := core.EventTypeTransferConfirmed
+			if reconfirmed {
+				eventType = core.EventTypeTransferReconfirmed
+			}
+			event := core.NewEvent(eventType, transfer.Namespace, transfer.LocalID, transfer.TX.ID, transfer.Pool.String())
 			return em.database.InsertEvent(ctx, event)
 		})
 		return err != nil, err // retry indefinitely (until context closes)
@@ -160,3 +243,51 @@ func (em *eventManager) TokensTransferred(ti tokens.Plugin, transfer *tokens.Tok
 
 	return err
 }
+
+// TokenEventRemoved invalidates the blockchain event of a token transfer or approval that has been removed from
+// the chain, which reverses the balance changes of any token transfers recorded against it
+func (em *eventManager) TokenEventRemoved(ti tokens.Plugin, event *blockchain.Event) error {
+	return em.retry.Do(em.ctx, "invalidate removed token event", func(attempt int) (bool, error) {
+		return true, em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
+			return em.handleBlockchainEventRemoved(ctx, event)
+		})
+	})
+}
+
+// invalidateTokenTransfers reverses the balance changes of any token transfers recorded against an
+// invalidated blockchain event, and emits a token_transfer_invalidated event for each.
+func (em *eventManager) invalidateTokenTransfers(ctx context.Context, chainEvent *core.BlockchainEvent) error {
+	fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("blockchainevent", chainEvent.ID),
+		fb.Eq("invalidated", false),
+	)
+	transfers, _, err := em.database.GetTokenTransfers(ctx, em.namespace.Name, filter)
+	if err != nil {
+		return err
+	}
+
+	for _, transfer := range transfers {
+		log.L(ctx).Infof("Invalidating token transfer %s (%s)", transfer.LocalID, transfer.ProtocolID)
+		update := database.TokenTransferQueryFactory.NewUpdate(ctx).Set("invalidated", true)
+		if err := em.database.UpdateTokenTransfer(ctx, transfer.Namespace, transfer.LocalID, update); err != nil {
+			return err
+		}
+
+		reversal := *transfer
+		reversal.From, reversal.To = transfer.To, transfer.From
+		if err := em.database.UpdateTokenBalances(ctx, &reversal); err != nil {
+			log.L(ctx).Errorf("Failed to reverse accounts %s -> %s for token transfer '%s': %s", transfer.From, transfer.To, transfer.ProtocolID, err)
+			return err
+		}
+		if err := em.recordTokenLedgerEntries(ctx, &reversal, true); err != nil {
+			return err
+		}
+
+		event := core.NewEvent(core.EventTypeTransferInvalidated, transfer.Namespace, transfer.LocalID, transfer.TX.ID, transfer.Pool.String())
+		if err := em.database.InsertEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -82,6 +82,8 @@ func TestTokensTransferredSucceedWithRetries(t *testing.T) {
 	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil, nil).Times(2)
 	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(fmt.Errorf("pop")).Once()
 	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil).Once()
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(nil)
 	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(ev *core.Event) bool {
 		return ev.Type == core.EventTypeTransferConfirmed && ev.Reference == transfer.LocalID && ev.Namespace == pool.Namespace
 	})).Return(nil).Once()
@@ -105,7 +107,7 @@ func TestPersistTransferOpFail(t *testing.T) {
 	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
 	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(nil, fmt.Errorf("pop"))
 
-	valid, err := em.persistTokenTransfer(em.ctx, transfer)
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 }
@@ -130,7 +132,7 @@ func TestPersistTransferBadOp(t *testing.T) {
 	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
 	em.mth.On("PersistTransaction", mock.Anything, transfer.TX.ID, core.TransactionTypeTokenTransfer, "0xffffeeee").Return(false, fmt.Errorf("pop"))
 
-	valid, err := em.persistTokenTransfer(em.ctx, transfer)
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
@@ -156,7 +158,7 @@ func TestPersistTransferTxFail(t *testing.T) {
 	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
 	em.mth.On("PersistTransaction", mock.Anything, transfer.TX.ID, core.TransactionTypeTokenTransfer, "0xffffeeee").Return(false, fmt.Errorf("pop"))
 
-	valid, err := em.persistTokenTransfer(em.ctx, transfer)
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
@@ -184,7 +186,7 @@ func TestPersistTransferGetTransferFail(t *testing.T) {
 	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(op, nil)
 	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(nil, fmt.Errorf("pop"))
 
-	valid, err := em.persistTokenTransfer(em.ctx, transfer)
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
@@ -216,7 +218,7 @@ func TestPersistTransferBlockchainEventFail(t *testing.T) {
 		return e.Namespace == pool.Namespace && e.Name == transfer.Event.Name
 	})).Return(nil, fmt.Errorf("pop"))
 
-	valid, err := em.persistTokenTransfer(em.ctx, transfer)
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.False(t, valid)
 	assert.EqualError(t, err, "pop")
 
@@ -254,8 +256,10 @@ func TestTokensTransferredWithTransactionRegenerateLocalID(t *testing.T) {
 	})).Return(nil)
 	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil, nil)
 	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(nil)
 
-	valid, err := em.persistTokenTransfer(em.ctx, transfer)
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.True(t, valid)
 	assert.NoError(t, err)
 
@@ -264,6 +268,102 @@ func TestTokensTransferredWithTransactionRegenerateLocalID(t *testing.T) {
 	mti.AssertExpectations(t)
 }
 
+func TestLoadTransferIDFromBatch(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Pool = fftypes.NewUUID()
+	usedID := fftypes.NewUUID()
+	legID := fftypes.NewUUID()
+	leg := func(id *fftypes.UUID, tokenIndex, amount string) map[string]interface{} {
+		return map[string]interface{}{
+			"localId":    id.String(),
+			"connector":  transfer.Connector,
+			"pool":       transfer.Pool.String(),
+			"tokenIndex": tokenIndex,
+			"amount":     amount,
+		}
+	}
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"transfers": []interface{}{
+				leg(fftypes.NewUUID(), "1", "1"),
+				leg(fftypes.NewUUID(), "0", "2"),
+				leg(usedID, "0", "1"),
+				leg(legID, "0", "1"),
+			},
+		},
+	}
+
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(nil, nil)
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransferBatch).Return(op, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", usedID).Return(&core.TokenTransfer{}, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", legID).Return(nil, nil)
+
+	id, err := em.loadTransferID(em.ctx, transfer.TX.ID, &transfer.TokenTransfer)
+	assert.NoError(t, err)
+	assert.Equal(t, *legID, *id)
+}
+
+func TestLoadTransferIDFromBatchNoMatch(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	op := &core.Operation{
+		Input: fftypes.JSONObject{"transfers": "bad"},
+	}
+
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(nil, nil)
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransferBatch).Return(op, nil)
+
+	id, err := em.loadTransferID(em.ctx, transfer.TX.ID, &transfer.TokenTransfer)
+	assert.NoError(t, err)
+	assert.NotNil(t, id)
+}
+
+func TestLoadTransferIDFromBatchFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(nil, nil)
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransferBatch).Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.loadTransferID(em.ctx, transfer.TX.ID, &transfer.TokenTransfer)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestLoadTransferIDFromBatchLookupFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Pool = fftypes.NewUUID()
+	legID := fftypes.NewUUID()
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"transfers": []interface{}{
+				map[string]interface{}{
+					"localId":    legID.String(),
+					"connector":  transfer.Connector,
+					"pool":       transfer.Pool.String(),
+					"tokenIndex": "0",
+					"amount":     "1",
+				},
+			},
+		},
+	}
+
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransfer).Return(nil, nil)
+	em.mth.On("FindOperationInTransaction", em.ctx, transfer.TX.ID, core.OpTypeTokenTransferBatch).Return(op, nil)
+	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", legID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.loadTransferID(em.ctx, transfer.TX.ID, &transfer.TokenTransfer)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestTokensTransferredWithExistingTransfer(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -296,7 +396,7 @@ func TestTokensTransferredWithExistingTransfer(t *testing.T) {
 	em.mdi.On("GetTokenTransferByID", em.ctx, "ns1", localID).Return(&core.TokenTransfer{}, nil)
 	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(&core.TokenTransfer{Type: core.TokenTransferTypeMint}, nil)
 
-	valid, err := em.persistTokenTransfer(em.ctx, transfer)
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
 	assert.False(t, valid)
 	assert.NoError(t, err)
 
@@ -305,6 +405,200 @@ func TestTokensTransferredWithExistingTransfer(t *testing.T) {
 	mti.AssertExpectations(t)
 }
 
+func TestTokensTransferredWithInvalidatedTransfer(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.TX = core.TransactionRef{}
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	existing := &core.TokenTransfer{
+		LocalID:     fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Invalidated: true,
+	}
+
+	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
+	em.mth.On("InsertOrGetBlockchainEvent", em.ctx, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(ev *core.Event) bool {
+		return ev.Type == core.EventTypeBlockchainEventReceived
+	})).Return(nil)
+	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(existing, nil)
+	em.mdi.On("UpdateTokenTransfer", em.ctx, "ns1", existing.LocalID, mock.Anything).Return(nil)
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(ev *core.Event) bool {
+		return ev.Type == core.EventTypeTransferReconfirmed && ev.Reference.Equals(existing.LocalID)
+	})).Return(nil)
+
+	err := em.TokensTransferred(&tokenmocks.Plugin{}, transfer)
+	assert.NoError(t, err)
+	assert.Equal(t, existing.LocalID, transfer.LocalID)
+}
+
+func TestTokenEventRemoved(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	chainEvent := &core.BlockchainEvent{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		ProtocolID: "000000000010/000020/000030",
+	}
+	transfer := &core.TokenTransfer{
+		LocalID:   fftypes.NewUUID(),
+		Namespace: "ns1",
+		Pool:      fftypes.NewUUID(),
+		From:      "0x1",
+		To:        "0x2",
+	}
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{chainEvent}, nil, nil)
+	em.mdi.On("UpdateBlockchainEvent", mock.Anything, "ns1", chainEvent.ID, mock.Anything).Return(nil)
+	em.mdi.On("GetTokenTransfers", mock.Anything, "ns1", mock.Anything).Return([]*core.TokenTransfer{transfer}, nil, nil)
+	em.mdi.On("UpdateTokenTransfer", mock.Anything, "ns1", transfer.LocalID, mock.Anything).Return(nil)
+	em.mdi.On("UpdateTokenBalances", mock.Anything, mock.MatchedBy(func(t *core.TokenTransfer) bool {
+		return t.From == "0x2" && t.To == "0x1"
+	})).Return(nil)
+	em.mdi.On("GetTokenLedgerEntries", mock.Anything, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", mock.Anything, mock.MatchedBy(func(e *core.TokenLedgerEntry) bool {
+		return e.Reversal
+	})).Return(nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeTransferInvalidated && e.Reference == transfer.LocalID
+	})).Return(nil)
+	em.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBlockchainEventInvalidated && e.Reference == chainEvent.ID
+	})).Return(nil)
+
+	err := em.TokenEventRemoved(&tokenmocks.Plugin{}, &blockchain.Event{
+		ProtocolID:     "000000000010/000020/000030",
+		BlockchainTXID: "0xffffeeee",
+	})
+	assert.NoError(t, err)
+}
+
+func TestTokenEventRemovedFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.cancel()
+
+	em.mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.TokenEventRemoved(&tokenmocks.Plugin{}, &blockchain.Event{
+		ProtocolID:     "000000000010/000020/000030",
+		BlockchainTXID: "0xffffeeee",
+	})
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestTokensTransferredWithInvalidatedTransferFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.TX = core.TransactionRef{}
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	existing := &core.TokenTransfer{
+		LocalID:     fftypes.NewUUID(),
+		Namespace:   "ns1",
+		Invalidated: true,
+	}
+
+	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
+	em.mth.On("InsertOrGetBlockchainEvent", em.ctx, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(existing, nil)
+	em.mdi.On("UpdateTokenTransfer", em.ctx, "ns1", existing.LocalID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
+	assert.False(t, valid)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestTokensTransferredLedgerFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.TX = core.TransactionRef{}
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+
+	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
+	em.mth.On("InsertOrGetBlockchainEvent", em.ctx, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil, nil)
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
+	assert.False(t, valid)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestInvalidateTokenTransfersUpdateFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := &core.TokenTransfer{LocalID: fftypes.NewUUID(), Namespace: "ns1"}
+	em.mdi.On("GetTokenTransfers", em.ctx, "ns1", mock.Anything).Return([]*core.TokenTransfer{transfer}, nil, nil)
+	em.mdi.On("UpdateTokenTransfer", em.ctx, "ns1", transfer.LocalID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.invalidateTokenTransfers(em.ctx, &core.BlockchainEvent{ID: fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestInvalidateTokenTransfersBalanceFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := &core.TokenTransfer{LocalID: fftypes.NewUUID(), Namespace: "ns1"}
+	em.mdi.On("GetTokenTransfers", em.ctx, "ns1", mock.Anything).Return([]*core.TokenTransfer{transfer}, nil, nil)
+	em.mdi.On("UpdateTokenTransfer", em.ctx, "ns1", transfer.LocalID, mock.Anything).Return(nil)
+	em.mdi.On("UpdateTokenBalances", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.invalidateTokenTransfers(em.ctx, &core.BlockchainEvent{ID: fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestInvalidateTokenTransfersLedgerFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := &core.TokenTransfer{LocalID: fftypes.NewUUID(), Namespace: "ns1", From: "0x1", To: "0x2"}
+	em.mdi.On("GetTokenTransfers", em.ctx, "ns1", mock.Anything).Return([]*core.TokenTransfer{transfer}, nil, nil)
+	em.mdi.On("UpdateTokenTransfer", em.ctx, "ns1", transfer.LocalID, mock.Anything).Return(nil)
+	em.mdi.On("UpdateTokenBalances", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := em.invalidateTokenTransfers(em.ctx, &core.BlockchainEvent{ID: fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestInvalidateTokenTransfersInsertEventFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := &core.TokenTransfer{LocalID: fftypes.NewUUID(), Namespace: "ns1", Pool: fftypes.NewUUID()}
+	em.mdi.On("GetTokenTransfers", em.ctx, "ns1", mock.Anything).Return([]*core.TokenTransfer{transfer}, nil, nil)
+	em.mdi.On("UpdateTokenTransfer", em.ctx, "ns1", transfer.LocalID, mock.Anything).Return(nil)
+	em.mdi.On("UpdateTokenBalances", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.invalidateTokenTransfers(em.ctx, &core.BlockchainEvent{ID: fftypes.NewUUID()})
+	assert.EqualError(t, err, "pop")
+}
+
 func TestTokensTransferredBadPool(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -367,6 +661,8 @@ func TestTokensTransferredWithMessageReceived(t *testing.T) {
 	})).Return(nil).Times(2)
 	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil, nil).Times(2)
 	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil).Times(2)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(nil)
 	em.mdi.On("GetMessageByID", em.ctx, "ns1", transfer.Message).Return(nil, fmt.Errorf("pop")).Once()
 	em.mdi.On("GetMessageByID", em.ctx, "ns1", transfer.Message).Return(message, nil).Once()
 	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(ev *core.Event) bool {
@@ -426,6 +722,8 @@ func TestTokensTransferredWithMessageSend(t *testing.T) {
 	})).Return(nil).Times(2)
 	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil, nil).Times(2)
 	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil).Times(2)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(nil)
 	em.mdi.On("GetMessageByID", em.ctx, "ns1", mock.Anything).Return(message, nil).Times(2)
 	em.mdi.On("ReplaceMessage", em.ctx, mock.MatchedBy(func(msg *core.Message) bool {
 		return msg.State == core.MessageStateReady
@@ -439,3 +737,83 @@ func TestTokensTransferredWithMessageSend(t *testing.T) {
 
 	mti.AssertExpectations(t)
 }
+
+func TestPersistTransferCreatedAccounts(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.Type = core.TokenTransferTypeMint
+	transfer.Namespace = "ns1"
+	transfer.Pool = fftypes.NewUUID()
+	transfer.BlockchainEvent = fftypes.NewUUID()
+	transfer.CreatedAccounts = []*tokens.TokenAccountCreated{
+		{Owner: "Owner1", Account: "Account1", Payer: "Payer1"},
+		{Owner: "Owner2", Account: "Account2"},
+	}
+
+	em.mdi.On("GetTokenAssociatedAccount", em.ctx, "ns1", transfer.Pool, "Owner1").Return(nil, nil)
+	em.mdi.On("GetTokenAssociatedAccount", em.ctx, "ns1", transfer.Pool, "Owner2").Return(&core.TokenAssociatedAccount{
+		Owner:   "Owner2",
+		Account: "Account2",
+	}, nil)
+	em.mdi.On("InsertTokenAssociatedAccount", em.ctx, mock.MatchedBy(func(account *core.TokenAssociatedAccount) bool {
+		return account.Owner == "Owner1" &&
+			account.Account == "Account1" &&
+			account.Payer == "Payer1" &&
+			account.Pool.Equals(transfer.Pool) &&
+			account.TX.ID.Equals(transfer.TX.ID) &&
+			account.BlockchainEvent.Equals(transfer.BlockchainEvent)
+	})).Return(nil).Once()
+
+	err := em.persistCreatedTokenAccounts(em.ctx, transfer)
+	assert.NoError(t, err)
+
+	em.mdi.AssertExpectations(t)
+}
+
+func TestPersistTransferCreatedAccountsInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.CreatedAccounts = []*tokens.TokenAccountCreated{
+		{Owner: "Owner1", Account: "Account1"},
+	}
+
+	em.mdi.On("GetTokenAssociatedAccount", em.ctx, mock.Anything, mock.Anything, "Owner1").Return(nil, nil)
+	em.mdi.On("InsertTokenAssociatedAccount", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := em.persistCreatedTokenAccounts(em.ctx, transfer)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPersistTransferCreatedAccountsFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	transfer := newTransfer()
+	transfer.TX = core.TransactionRef{}
+	transfer.CreatedAccounts = []*tokens.TokenAccountCreated{
+		{Owner: "Owner1", Account: "Account1"},
+	}
+	pool := &core.TokenPool{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+
+	em.mam.On("GetTokenPoolByLocator", em.ctx, "erc1155", "F1").Return(pool, nil)
+	em.mth.On("InsertOrGetBlockchainEvent", em.ctx, mock.Anything).Return(nil, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("InsertOrGetTokenTransfer", em.ctx, &transfer.TokenTransfer).Return(nil, nil)
+	em.mdi.On("UpdateTokenBalances", em.ctx, &transfer.TokenTransfer).Return(nil)
+	em.mdi.On("GetTokenLedgerEntries", em.ctx, "ns1", mock.Anything).Return([]*core.TokenLedgerEntry{{}}, nil, nil)
+	em.mdi.On("InsertTokenLedgerEntry", em.ctx, mock.Anything).Return(nil)
+	em.mdi.On("GetTokenAssociatedAccount", em.ctx, "ns1", pool.ID, "Owner1").Return(nil, fmt.Errorf("pop"))
+
+	valid, _, err := em.persistTokenTransfer(em.ctx, transfer)
+	assert.False(t, valid)
+	assert.EqualError(t, err, "pop")
+
+	em.mdi.AssertExpectations(t)
+}
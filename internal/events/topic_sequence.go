@@ -0,0 +1,241 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// topicSequenceState tracks the next sequence of a context on a strict topic during the batch, along with
+// the messages parked on the context in the batch, as they are not in the database until the batch is flushed
+type topicSequenceState struct {
+	sequence *core.TopicSequence
+	changed  bool
+	parked   map[int64]*core.Message
+}
+
+// strictContexts returns the contexts of the strict topics of a message. Definitions, group init and recall
+// messages are not sequenced, as they are processed by the system rather than by applications.
+func (ag *aggregator) strictContexts(msg *core.Message) (topics []string, contexts []*fftypes.Bytes32) {
+	if msg.Header.Type != core.MessageTypeBroadcast && msg.Header.Type != core.MessageTypePrivate ||
+		msg.Header.Tag == core.SystemTagRecallMessage {
+		return nil, nil
+	}
+	for _, topic := range msg.Header.Topics {
+		if !ag.strictTopics[topic] {
+			continue
+		}
+		topics = append(topics, topic)
+		if msg.Header.Group != nil {
+			contexts = append(contexts, privateContext(topic, msg.Header.Group))
+		} else {
+			contexts = append(contexts, broadcastContext(topic))
+		}
+	}
+	return topics, contexts
+}
+
+func (bs *batchState) stateForTopicSequence(ctx context.Context, topic string, group, context *fftypes.Bytes32) (*topicSequenceState, error) {
+	if tss, exists := bs.topicSequences[*context]; exists {
+		return tss, nil
+	}
+
+	ts, err := bs.database.GetTopicSequence(ctx, bs.namespace, context)
+	if err != nil {
+		return nil, err
+	}
+	if ts == nil {
+		// The first message on a context has sequence 1
+		ts = &core.TopicSequence{
+			Namespace: bs.namespace,
+			Context:   context,
+			Topic:     topic,
+			Group:     group,
+			Next:      1,
+		}
+	}
+	tss := &topicSequenceState{
+		sequence: ts,
+		parked:   make(map[int64]*core.Message),
+	}
+	bs.topicSequences[*context] = tss
+	return tss, nil
+}
+
+// getParkedMessage finds the message parked on a context with a sequence, first in the batch and then in the database
+func (bs *batchState) getParkedMessage(ctx context.Context, tss *topicSequenceState, sequence int64) (*core.Message, error) {
+	if msg, ok := tss.parked[sequence]; ok {
+		return msg, nil
+	}
+	fb := database.MessageQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("state", core.MessageStateParked),
+		fb.Eq("topicsequence", sequence),
+	)
+	msgs, _, err := bs.database.GetMessages(ctx, bs.namespace, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range msgs {
+		if !msg.Header.Group.Equals(tss.sequence.Group) {
+			continue
+		}
+		for _, topic := range msg.Header.Topics {
+			if topic == tss.sequence.Topic {
+				return msg, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// markMessageReleased updates the state of a parked message to confirmed, or to pending_app if it is held for the
+// application. The pins of the message were marked dispatched when it was parked, so only the state is updated.
+func (bs *batchState) markMessageReleased(msg *core.Message, newState core.MessageState) {
+	for _, dm := range bs.dispatchedMessages {
+		if dm.msgID.Equals(msg.Header.ID) {
+			dm.newState = newState
+			return
+		}
+	}
+	bs.dispatchedMessages = append(bs.dispatchedMessages, &dispatchedMessage{
+		batchID:  msg.BatchID,
+		msgID:    msg.Header.ID,
+		newState: newState,
+	})
+}
+
+func (bs *batchState) flushTopicSequences(ctx context.Context) error {
+	for _, tss := range bs.topicSequences {
+		if tss.changed {
+			if err := bs.database.UpsertTopicSequence(ctx, tss.sequence); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkTopicSequence checks a message that is ready to be confirmed against the next sequence of each of its
+// strict topics. A message that is ahead of the sequence on any topic is parked, and the gaps are returned.
+func (ag *aggregator) checkTopicSequence(ctx context.Context, msg *core.Message, state *batchState) (action core.MessageAction, gaps []string, err error) {
+	topics, contexts := ag.strictContexts(msg)
+	sequence := msg.Header.TopicSequence
+	for i, topic := range topics {
+		if sequence <= 0 {
+			return core.ActionReject, nil, i18n.NewError(ctx, coremsgs.MsgTopicSequenceMissing, msg.Header.ID, topic)
+		}
+		tss, err := state.stateForTopicSequence(ctx, topic, msg.Header.Group, contexts[i])
+		if err != nil {
+			return core.ActionRetry, nil, err
+		}
+		next := tss.sequence.Next
+		switch {
+		case sequence < next:
+			return core.ActionReject, nil, i18n.NewError(ctx, coremsgs.MsgTopicSequenceStale, msg.Header.ID, sequence, topic, next)
+		case sequence > next:
+			parked, err := state.getParkedMessage(ctx, tss, sequence)
+			if err != nil {
+				return core.ActionRetry, nil, err
+			}
+			if parked != nil && !parked.Header.ID.Equals(msg.Header.ID) {
+				return core.ActionReject, nil, i18n.NewError(ctx, coremsgs.MsgTopicSequenceDuplicate, msg.Header.ID, sequence, topic, parked.Header.ID)
+			}
+			gaps = append(gaps, topic)
+		}
+	}
+	return core.ActionConfirm, gaps, nil
+}
+
+// parkMessage holds a message that is ahead of the sequence on a strict topic, until the messages before it
+// are confirmed. A gap event is emitted for each topic the message is ahead on.
+func (ag *aggregator) parkMessage(msg *core.Message, gaps []string, tx *fftypes.UUID, state *batchState) {
+	_, contexts := ag.strictContexts(msg)
+	for _, context := range contexts {
+		state.topicSequences[*context].parked[msg.Header.TopicSequence] = msg
+	}
+	state.AddFinalize(func(ctx context.Context) error {
+		for _, topic := range gaps {
+			event := core.NewEvent(core.EventTypeTopicSequenceGap, ag.namespace, msg.Header.ID, tx, topic)
+			event.Correlator = msg.Header.CID
+			if err := ag.database.InsertEvent(ctx, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// advanceTopicSequence moves the strict topics of a confirmed message on to the next sequence, and confirms
+// any parked messages that are now next on all of their strict topics.
+func (ag *aggregator) advanceTopicSequence(ctx context.Context, msg *core.Message, state *batchState) error {
+	confirmed := []*core.Message{msg}
+	for len(confirmed) > 0 {
+		msg, confirmed = confirmed[0], confirmed[1:]
+		_, contexts := ag.strictContexts(msg)
+		for _, context := range contexts {
+			tss := state.topicSequences[*context]
+			tss.sequence.Next = msg.Header.TopicSequence + 1
+			tss.changed = true
+			delete(tss.parked, msg.Header.TopicSequence)
+			parked, err := state.getParkedMessage(ctx, tss, tss.sequence.Next)
+			if err != nil {
+				return err
+			}
+			if parked == nil {
+				continue
+			}
+			ready, err := ag.parkedMessageReady(ctx, parked, state)
+			if err != nil {
+				return err
+			}
+			if ready {
+				log.L(ctx).Infof("Releasing parked message '%s' with topicSequence %d", parked.Header.ID, parked.Header.TopicSequence)
+				newState := core.MessageStatePendingApp
+				if ag.deferConfirm(parked) {
+					ag.holdMessage(parked, parked.TransactionID, state)
+				} else {
+					newState = ag.completeDispatch(core.ActionConfirm, nil, parked, parked.TransactionID, state)
+				}
+				state.markMessageReleased(parked, newState)
+				confirmed = append(confirmed, parked)
+			}
+		}
+	}
+	return nil
+}
+
+func (ag *aggregator) parkedMessageReady(ctx context.Context, msg *core.Message, state *batchState) (bool, error) {
+	topics, contexts := ag.strictContexts(msg)
+	for i, topic := range topics {
+		tss, err := state.stateForTopicSequence(ctx, topic, msg.Header.Group, contexts[i])
+		if err != nil {
+			return false, err
+		}
+		if tss.sequence.Next != msg.Header.TopicSequence {
+			return false, nil
+		}
+	}
+	return true, nil
+}
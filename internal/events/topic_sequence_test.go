@@ -0,0 +1,403 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestSequencedMessage(topic string, sequence int64) *core.Message {
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:            fftypes.NewUUID(),
+			Type:          core.MessageTypeBroadcast,
+			Topics:        fftypes.FFStringArray{topic},
+			TopicSequence: sequence,
+		},
+		BatchID:       fftypes.NewUUID(),
+		TransactionID: fftypes.NewUUID(),
+	}
+}
+
+func TestNewAggregatorStrictTopics(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	assert.Empty(t, ag.strictTopics)
+
+	config.Set(coreconfig.EventAggregatorStrictTopics, []string{"topic1", "topic2"})
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ag.ctx, 100, 5*time.Minute), nil)
+	ag2, err := newAggregator(ag.ctx, &core.Namespace{Name: "ns1"}, ag.mdi, ag.mbi, ag.mpm, ag.mdh, ag.mim, ag.mdm, newEventNotifier(ag.ctx, "ut"), ag.mmi, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]bool{"topic1": true, "topic2": true}, ag2.strictTopics)
+}
+
+func TestAggregationStrictTopicParkAndRelease(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	member1org := newTestOrg("org1")
+	member1key := "0x12345"
+	topic := "strict-topic"
+	ag.strictTopics[topic] = true
+	batchID := fftypes.NewUUID()
+	contextUnmasked := broadcastContext(topic)
+
+	ag.mim.On("FindIdentityForVerifier", ag.ctx, []core.IdentityType{core.IdentityTypeOrg, core.IdentityTypeCustom}, &core.VerifierRef{
+		Type:  core.VerifierTypeEthAddress,
+		Value: member1key,
+	}).Return(member1org, nil)
+
+	// The second message in the topic sequence arrives first
+	newMsg := func(sequence int64) *core.Message {
+		return &core.Message{
+			Header: core.MessageHeader{
+				ID:            fftypes.NewUUID(),
+				Type:          core.MessageTypeBroadcast,
+				Topics:        []string{topic},
+				Namespace:     "ns1",
+				TopicSequence: sequence,
+				SignerRef: core.SignerRef{
+					Author: member1org.DID,
+					Key:    member1key,
+				},
+			},
+			Data: core.DataRefs{
+				{ID: fftypes.NewUUID()},
+			},
+		}
+	}
+	msg2 := newMsg(2)
+	msg1 := newMsg(1)
+	batch := &core.Batch{
+		BatchHeader: core.BatchHeader{
+			ID: batchID,
+		},
+		Payload: core.BatchPayload{
+			Messages: []*core.Message{msg2, msg1},
+		},
+	}
+	bp, _ := batch.Confirmed()
+
+	ag.mdi.On("GetBatchByID", ag.ctx, "ns1", batchID).Return(bp, nil)
+	ag.mdi.On("GetPins", mock.Anything, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg2.Header.ID, data.CRORequirePublicBlobRefs).Return(msg2, core.DataArray{}, true, nil)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg1.Header.ID, data.CRORequirePublicBlobRefs).Return(msg1, core.DataArray{}, true, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", contextUnmasked).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Reference.Equals(msg2.Header.ID) && e.Type == core.EventTypeTopicSequenceGap && e.Topic == topic
+	})).Return(nil).Once()
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Reference.Equals(msg1.Header.ID) && e.Type == core.EventTypeMessageConfirmed
+	})).Return(nil).Once()
+	ag.mdi.On("InsertEvent", ag.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Reference.Equals(msg2.Header.ID) && e.Type == core.EventTypeMessageConfirmed
+	})).Return(nil).Once()
+	ag.mdi.On("UpsertTopicSequence", ag.ctx, mock.MatchedBy(func(ts *core.TopicSequence) bool {
+		return ts.Topic == topic && ts.Context.Equals(contextUnmasked) && ts.Next == 3
+	})).Return(nil)
+	ag.mdi.On("UpdatePins", ag.ctx, "ns1", mock.Anything, mock.Anything).Return(nil)
+	ag.mdm.On("UpdateMessageStateIfCached", ag.ctx, mock.Anything, core.MessageStateConfirmed, mock.Anything, "").Return()
+	ag.mdi.On("UpdateMessages", ag.ctx, "ns1", mock.Anything, mock.Anything).Return(nil)
+
+	err := ag.processPins(ag.ctx, []*core.Pin{
+		{Sequence: 10001, Hash: contextUnmasked, Batch: batchID, Index: 0, Signer: member1key},
+		{Sequence: 10002, Hash: contextUnmasked, Batch: batchID, Index: 1, Signer: member1key},
+	}, bs)
+	assert.NoError(t, err)
+	assert.Len(t, bs.dispatchedMessages, 2)
+	assert.Equal(t, core.MessageStateConfirmed, bs.dispatchedMessages[0].newState)
+	assert.Equal(t, core.MessageStateConfirmed, bs.dispatchedMessages[1].newState)
+
+	err = bs.RunFinalize(ag.ctx)
+	assert.NoError(t, err)
+}
+
+func TestCheckTopicSequenceNotStrict(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic2", 0)
+	action, gaps, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.Empty(t, gaps)
+
+	msg = newTestSequencedMessage("topic1", 0)
+	msg.Header.Type = core.MessageTypePrivate
+	msg.Header.Tag = core.SystemTagRecallMessage
+	action, gaps, err = ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.Empty(t, gaps)
+}
+
+func TestCheckTopicSequenceMissing(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 0)
+	action, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.Regexp(t, "FF10651", err)
+	assert.Equal(t, core.ActionReject, action)
+}
+
+func TestCheckTopicSequenceStale(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	group := fftypes.NewRandB32()
+	msg := newTestSequencedMessage("topic1", 5)
+	msg.Header.Type = core.MessageTypePrivate
+	msg.Header.Group = group
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", privateContext("topic1", group)).Return(&core.TopicSequence{
+		Topic: "topic1",
+		Group: group,
+		Next:  6,
+	}, nil)
+
+	action, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.Regexp(t, "FF10652", err)
+	assert.Equal(t, core.ActionReject, action)
+}
+
+func TestCheckTopicSequenceGetFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 1)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, fmt.Errorf("pop"))
+
+	action, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, core.ActionRetry, action)
+}
+
+func TestCheckTopicSequenceGetParkedFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 3)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	action, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, core.ActionRetry, action)
+}
+
+func TestCheckTopicSequenceDuplicate(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 3)
+	parked := newTestSequencedMessage("topic1", 3)
+	otherGroup := newTestSequencedMessage("topic1", 3)
+	otherGroup.Header.Group = fftypes.NewRandB32()
+	otherTopic := newTestSequencedMessage("topic2", 3)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{otherGroup, otherTopic, parked}, nil, nil)
+
+	action, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.Regexp(t, "FF10653", err)
+	assert.Equal(t, core.ActionReject, action)
+}
+
+func TestParkAndReleaseMultipleTopics(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+	ag.strictTopics["topic2"] = true
+
+	// A message on both topics, that is parked until both are at its sequence
+	both := newTestSequencedMessage("topic1", 2)
+	both.Header.Topics = fftypes.FFStringArray{"topic1", "topic2"}
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(&core.TopicSequence{Topic: "topic1", Next: 1}, nil)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic2")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+
+	action, gaps, err := ag.checkTopicSequence(ag.ctx, both, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.Equal(t, []string{"topic1", "topic2"}, gaps)
+	ag.parkMessage(both, gaps, both.TransactionID, bs)
+	bs.markMessageDispatched(both.BatchID, both, 0, core.MessageStateParked)
+
+	// Confirming the first message on topic1 does not release it
+	msg1 := newTestSequencedMessage("topic1", 1)
+	action, gaps, err = ag.checkTopicSequence(ag.ctx, msg1, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.Empty(t, gaps)
+	err = ag.advanceTopicSequence(ag.ctx, msg1, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateParked, bs.dispatchedMessages[0].newState)
+
+	// Confirming the first message on topic2 does
+	msg2 := newTestSequencedMessage("topic2", 1)
+	err = ag.advanceTopicSequence(ag.ctx, msg2, bs)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateConfirmed, bs.dispatchedMessages[0].newState)
+	assert.Equal(t, int64(3), bs.topicSequences[*broadcastContext("topic1")].sequence.Next)
+	assert.Equal(t, int64(3), bs.topicSequences[*broadcastContext("topic2")].sequence.Next)
+}
+
+func TestAdvanceTopicSequenceReleaseFromDB(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 1)
+	parked := newTestSequencedMessage("topic1", 2)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{parked}, nil, nil).Once()
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil).Once()
+
+	_, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	err = ag.advanceTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	assert.Len(t, bs.dispatchedMessages, 1)
+	assert.Equal(t, parked.Header.ID, bs.dispatchedMessages[0].msgID)
+	assert.Equal(t, core.MessageStateConfirmed, bs.dispatchedMessages[0].newState)
+	assert.Zero(t, bs.dispatchedMessages[0].topicCount)
+}
+
+func TestAdvanceTopicSequenceGetParkedFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 1)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	err = ag.advanceTopicSequence(ag.ctx, msg, bs)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestAdvanceTopicSequenceParkedReadyFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+	ag.strictTopics["topic2"] = true
+
+	msg := newTestSequencedMessage("topic1", 1)
+	parked := newTestSequencedMessage("topic1", 2)
+	parked.Header.Topics = fftypes.FFStringArray{"topic1", "topic2"}
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic2")).Return(nil, fmt.Errorf("pop"))
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{parked}, nil, nil)
+
+	_, _, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	err = ag.advanceTopicSequence(ag.ctx, msg, bs)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestProcessMessageTopicSequenceAdvanceFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 1)
+	msg.Header.SignerRef = core.SignerRef{Key: "0x12345", Author: "did:firefly:org/org1"}
+	msg.Data = core.DataRefs{{ID: fftypes.NewUUID()}}
+	manifest := &core.BatchManifest{
+		ID: fftypes.NewUUID(),
+		TX: core.TransactionRef{ID: fftypes.NewUUID()},
+	}
+	pin := &core.Pin{Sequence: 10001, Hash: broadcastContext("topic1"), Signer: "0x12345"}
+
+	ag.mim.On("FindIdentityForVerifier", ag.ctx, mock.Anything, mock.Anything).Return(newTestOrg("org1"), nil)
+	ag.mdm.On("GetMessageWithDataCached", ag.ctx, msg.Header.ID, data.CRORequirePublicBlobRefs).Return(msg, core.DataArray{}, true, nil)
+	ag.mdi.On("GetPins", ag.ctx, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+	ag.mdm.On("ValidateAll", ag.ctx, mock.Anything).Return(true, nil)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := ag.processMessage(ag.ctx, manifest, pin, 0, &core.MessageManifestEntry{MessageRef: core.MessageRef{ID: msg.Header.ID}}, &core.BatchPersisted{}, bs)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestParkMessageEventFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+	ag.strictTopics["topic1"] = true
+
+	msg := newTestSequencedMessage("topic1", 2)
+	ag.mdi.On("GetTopicSequence", ag.ctx, "ns1", broadcastContext("topic1")).Return(nil, nil)
+	ag.mdi.On("GetMessages", ag.ctx, "ns1", mock.Anything).Return([]*core.Message{}, nil, nil)
+	ag.mdi.On("InsertEvent", ag.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, gaps, err := ag.checkTopicSequence(ag.ctx, msg, bs)
+	assert.NoError(t, err)
+	ag.parkMessage(msg, gaps, msg.TransactionID, bs)
+
+	err = bs.BatchState.RunFinalize(ag.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestFlushTopicSequencesFail(t *testing.T) {
+	ag := newTestAggregator()
+	defer ag.cleanup(t)
+	bs := newBatchState(&ag.aggregator)
+
+	bs.topicSequences[*fftypes.NewRandB32()] = &topicSequenceState{
+		sequence: &core.TopicSequence{Next: 2},
+		changed:  true,
+	}
+	ag.mdi.On("UpsertTopicSequence", ag.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := bs.RunFinalize(ag.ctx)
+	assert.Regexp(t, "pop", err)
+}
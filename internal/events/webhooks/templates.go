@@ -0,0 +1,130 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"text/template"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// requestTemplate is the parsed form of the template options of a subscription
+type requestTemplate struct {
+	path    map[string]*template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func parseTemplateField(ctx context.Context, field, text string) (*template.Template, error) {
+	t, err := template.New(field).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWebhookTemplateInvalid, field, err)
+	}
+	return t, nil
+}
+
+// parseRequestTemplate parses the templates, checking each path parameter has a {name} placeholder in the URL
+func parseRequestTemplate(ctx context.Context, options *core.WebhookTemplateOptions, rawURL string) (rt *requestTemplate, err error) {
+	if options == nil {
+		return nil, nil
+	}
+	rt = &requestTemplate{
+		path:    make(map[string]*template.Template, len(options.Path)),
+		headers: make(map[string]*template.Template, len(options.Headers)),
+	}
+	for name, text := range options.Path {
+		field := "template.path." + name
+		if !strings.Contains(rawURL, "{"+name+"}") {
+			return nil, i18n.NewError(ctx, coremsgs.MsgWebhookTemplateInvalid, field, "no {"+name+"} placeholder in the url")
+		}
+		if rt.path[name], err = parseTemplateField(ctx, field, text); err != nil {
+			return nil, err
+		}
+	}
+	for name, text := range options.Headers {
+		if rt.headers[name], err = parseTemplateField(ctx, "template.headers."+name, text); err != nil {
+			return nil, err
+		}
+	}
+	if options.Body != "" {
+		if rt.body, err = parseTemplateField(ctx, "template.body", options.Body); err != nil {
+			return nil, err
+		}
+	}
+	return rt, nil
+}
+
+// templateInput is the JSON of the event, with the values of the message data under "data"
+func templateInput(event *core.EventDelivery, data []*fftypes.JSONAny) map[string]interface{} {
+	var input map[string]interface{}
+	b, _ := json.Marshal(&whBatchEvent{EventDelivery: event, Data: data})
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	_ = d.Decode(&input)
+	return input
+}
+
+func executeTemplate(ctx context.Context, t *template.Template, input map[string]interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, input); err != nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgWebhookTemplateInvalid, t.Name(), err)
+	}
+	return buf.String(), nil
+}
+
+// apply sets the path parameters and headers of the request for an event, returning the body
+// if the template has one
+func (rt *requestTemplate) apply(ctx context.Context, req *whRequest, event *core.EventDelivery, data []*fftypes.JSONAny) (body *string, err error) {
+	input := templateInput(event, data)
+	for name, t := range rt.path {
+		v, err := executeTemplate(ctx, t, input)
+		if err != nil {
+			return nil, err
+		}
+		req.url = strings.ReplaceAll(req.url, "{"+name+"}", url.PathEscape(v))
+	}
+	for name, t := range rt.headers {
+		v, err := executeTemplate(ctx, t, input)
+		if err != nil {
+			return nil, err
+		}
+		req.r.SetHeader(name, v)
+	}
+	if rt.body != nil {
+		v, err := executeTemplate(ctx, rt.body, input)
+		if err != nil {
+			return nil, err
+		}
+		body = &v
+	}
+	return body, nil
+}
@@ -0,0 +1,228 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestTemplateSubscription(url string, template *core.WebhookTemplateOptions) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	withData := true
+	sub.Options.WithData = &withData
+	sub.Options.TransportOptions()["url"] = url
+	sub.Options.TransportOptions()["headers"] = map[string]interface{}{
+		"x-api-key": "secret",
+	}
+	sub.Options.Template = template
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Type:      core.EventTypeMessageConfirmed,
+				Reference: fftypes.NewUUID(),
+				Topic:     "orders/eu",
+			},
+			Message: &core.Message{
+				Header: core.MessageHeader{
+					ID:  fftypes.NewUUID(),
+					Tag: "order_created",
+				},
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+func TestValidateOptionsBadTemplate(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	for _, tc := range []struct {
+		template *core.WebhookTemplateOptions
+		field    string
+	}{
+		{&core.WebhookTemplateOptions{Path: map[string]string{"topic": "{{.topic}}"}}, "template.path.topic.*placeholder"},
+		{&core.WebhookTemplateOptions{Path: map[string]string{"id": "{{.id"}}, "template.path.id"},
+		{&core.WebhookTemplateOptions{Headers: map[string]string{"x-tag": "{{.tag"}}, "template.headers.x-tag"},
+		{&core.WebhookTemplateOptions{Body: "{{end}}"}, "template.body"},
+	} {
+		opts := &core.SubscriptionOptions{}
+		opts.TransportOptions()["url"] = "/orders/{id}"
+		opts.Template = tc.template
+		err := wh.ValidateOptions(opts)
+		assert.Regexp(t, "FF10585.*"+tc.field, err)
+	}
+
+	opts := &core.SubscriptionOptions{}
+	opts.Format = core.SubOptsFormatCloudEvents
+	opts.TransportOptions()["url"] = "/orders"
+	opts.Template = &core.WebhookTemplateOptions{Body: "{{.id}}"}
+	err := wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10585.*template.body", err)
+
+	opts.TransportOptions()["cloudEventsMode"] = "binary"
+	err = wh.ValidateOptions(opts)
+	assert.NoError(t, err)
+
+	opts.Batch = &core.SubscriptionBatchOptions{Size: 10}
+	err = wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10569.*template", err)
+}
+
+func TestRequestTemplate(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	sub, event := newTestTemplateSubscription("", &core.WebhookTemplateOptions{
+		Path: map[string]string{
+			"topic": "{{.topic}}",
+			"id":    "{{.message.header.id}}",
+		},
+		Headers: map[string]string{
+			"x-event-type": "{{.type}}",
+		},
+		Body: `{"tag":"{{.message.header.tag}}","amount":{{(index .data 0).amount}},"data":{{json .data}}}`,
+	})
+	r := mux.NewRouter()
+	r.HandleFunc("/api/{topic}/orders/{id}", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/api/orders%2Feu/orders/"+event.Message.Header.ID.String(), req.URL.EscapedPath())
+		assert.Equal(t, "secret", req.Header.Get("x-api-key"))
+		assert.Equal(t, "message_confirmed", req.Header.Get("x-event-type"))
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		b, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"tag":"order_created","amount":12345678901234567890,"data":[{"amount":12345678901234567890}]}`, string(b))
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+	r.UseEncodedPath()
+	server := httptest.NewServer(r)
+	defer server.Close()
+	sub.Options.TransportOptions()["url"] = fmt.Sprintf("http://%s/api/{topic}/orders/{id}", server.Listener.Addr())
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"amount":12345678901234567890}`)},
+	})
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestTemplateNoBodyForGet(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/api/order_created", func(res http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Empty(t, b)
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestTemplateSubscription(fmt.Sprintf("http://%s/api/{tag}", server.Listener.Addr()), &core.WebhookTemplateOptions{
+		Path: map[string]string{"tag": "{{.message.header.tag}}"},
+		Body: `{{.id}}`,
+	})
+	sub.Options.TransportOptions()["method"] = http.MethodGet
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestTemplateExecuteFail(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Fail(t, "webhook should not be invoked")
+	}))
+	defer server.Close()
+
+	for _, template := range []*core.WebhookTemplateOptions{
+		{Path: map[string]string{"id": "{{.tokenTransfer.localId}}"}},
+		{Headers: map[string]string{"x-pool": "{{.tokenTransfer.pool}}"}},
+		{Body: "{{.unknown}}"},
+	} {
+		sub, event := newTestTemplateSubscription(fmt.Sprintf("http://%s/{id}", server.Listener.Addr()), template)
+
+		mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+		mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+			return response.ID.Equals(event.ID)
+		})).Return(nil)
+
+		err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+		assert.NoError(t, err)
+
+		mcb.AssertExpectations(t)
+	}
+}
+
+func TestRequestTemplateBadOptions(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		assert.Fail(t, "webhook should not be invoked")
+	}))
+	defer server.Close()
+
+	sub, event := newTestTemplateSubscription(fmt.Sprintf("http://%s/", server.Listener.Addr()), &core.WebhookTemplateOptions{
+		Path: map[string]string{"id": "{{.id}}"},
+	})
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.ID.Equals(event.ID)
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
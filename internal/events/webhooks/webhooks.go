@@ -21,9 +21,11 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -34,11 +36,31 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/events"
 )
 
+const (
+	defaultRetryMaxAttempts  = 5
+	defaultRetryInitialDelay = 1 * time.Second
+	defaultRetryMaxDelay     = 30 * time.Second
+	defaultRetryFactor       = 2.0
+
+	cloudEventsModeStructured = "structured"
+	cloudEventsModeBinary     = "binary"
+)
+
+var defaultRetryStatusCodes = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
 type WebHooks struct {
 	ctx           context.Context
 	capabilities  *events.Capabilities
@@ -60,6 +82,14 @@ type whRequest struct {
 	body      fftypes.JSONObject
 	forceJSON bool
 	replyTx   string
+	ceMode    string
+}
+
+// whBatchEvent is an event in the body of a batch delivery, with the data of its message in-line if the
+// subscription includes data
+type whBatchEvent struct {
+	*core.EventDelivery
+	Data []*fftypes.JSONAny `json:"data,omitempty"`
 }
 
 type whResponse struct {
@@ -68,6 +98,12 @@ type whResponse struct {
 	Body    *fftypes.JSONAny   `json:"body"`
 }
 
+type retryPolicy struct {
+	retry       retry.Retry
+	maxAttempts int
+	statusCodes []int
+}
+
 func (wh *WebHooks) Name() string { return "webhooks" }
 
 func (wh *WebHooks) Init(ctx context.Context, config config.Section) (err error) {
@@ -82,7 +118,7 @@ func (wh *WebHooks) Init(ctx context.Context, config config.Section) (err error)
 
 	*wh = WebHooks{
 		ctx:          log.WithLogField(ctx, "webhook", wh.connID),
-		capabilities: &events.Capabilities{},
+		capabilities: &events.Capabilities{CloudEvents: true, BatchDelivery: true},
 		callbacks: callbacks{
 			handlers: make(map[string]events.Callbacks),
 		},
@@ -118,6 +154,7 @@ func (wh *WebHooks) buildRequest(restyClient *resty.Client, options fftypes.JSON
 		method:    options.GetString("method"),
 		forceJSON: options.GetBool("json"),
 		replyTx:   options.GetString("replytx"),
+		ceMode:    options.GetString("cloudEventsMode"),
 	}
 	if req.url == "" {
 		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookURLEmpty)
@@ -125,6 +162,13 @@ func (wh *WebHooks) buildRequest(restyClient *resty.Client, options fftypes.JSON
 	if req.method == "" {
 		req.method = http.MethodPost
 	}
+	switch req.ceMode {
+	case "":
+		req.ceMode = cloudEventsModeStructured
+	case cloudEventsModeStructured, cloudEventsModeBinary:
+	default:
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidCloudEventsMode, req.ceMode)
+	}
 	headers := options.GetObject("headers")
 	for h, v := range headers {
 		s, ok := v.(string)
@@ -195,15 +239,143 @@ func (wh *WebHooks) buildRequest(restyClient *resty.Client, options fftypes.JSON
 	return req, err
 }
 
+// setCloudEvent applies the CloudEvents mode of the subscription to the request, returning the body to send.
+// In structured mode the body is wrapped in a CloudEvent, and in binary mode the attributes are set as headers.
+func (req *whRequest) setCloudEvent(event *core.EventDelivery, body interface{}) interface{} {
+	ce := core.NewCloudEvent(event, body)
+	if req.ceMode == cloudEventsModeStructured {
+		if body == nil {
+			return nil
+		}
+		req.r.SetHeader("Content-Type", "application/cloudevents+json")
+		return ce
+	}
+	req.r.SetHeader("ce-specversion", ce.SpecVersion)
+	req.r.SetHeader("ce-id", ce.ID)
+	req.r.SetHeader("ce-source", ce.Source)
+	req.r.SetHeader("ce-type", ce.Type)
+	if ce.Subject != "" {
+		req.r.SetHeader("ce-subject", ce.Subject)
+	}
+	if ce.Time != nil {
+		req.r.SetHeader("ce-time", ce.Time.String())
+	}
+	req.r.SetHeader("ce-fireflysequence", strconv.FormatInt(ce.FireFlySequence, 10))
+	if ce.FireFlyTopic != "" {
+		req.r.SetHeader("ce-fireflytopic", ce.FireFlyTopic)
+	}
+	if ce.FireFlySubscription != "" {
+		req.r.SetHeader("ce-fireflysubscription", ce.FireFlySubscription)
+	}
+	return body
+}
+
 func (wh *WebHooks) ValidateOptions(options *core.SubscriptionOptions) error {
 	if options.WithData == nil {
 		defaultTrue := true
 		options.WithData = &defaultTrue
 	}
-	_, err := wh.buildRequest(wh.client, options.TransportOptions(), fftypes.JSONObject{})
+	req, err := wh.buildRequest(wh.client, options.TransportOptions(), fftypes.JSONObject{})
+	if err == nil {
+		_, err = wh.buildRetryPolicy(options.Retry)
+	}
+	if err == nil {
+		err = wh.validateTemplateOptions(options, req)
+	}
+	if err == nil && options.Batch != nil {
+		err = wh.validateBatchOptions(options, req)
+	}
 	return err
 }
 
+func (wh *WebHooks) validateTemplateOptions(options *core.SubscriptionOptions, req *whRequest) error {
+	if _, err := parseRequestTemplate(wh.ctx, options.Template, req.url); err != nil {
+		return err
+	}
+	if options.Template != nil && options.Template.Body != "" && options.Format == core.SubOptsFormatCloudEvents && req.ceMode == cloudEventsModeStructured {
+		return i18n.NewError(wh.ctx, coremsgs.MsgWebhookTemplateInvalid, "template.body", "a body template cannot be used with structured CloudEvents")
+	}
+	return nil
+}
+
+// validateBatchOptions rejects the options that act on an individual event, as a batch is delivered in one request
+func (wh *WebHooks) validateBatchOptions(options *core.SubscriptionOptions, req *whRequest) error {
+	transportOptions := options.TransportOptions()
+	for _, opt := range []string{"reply", "fastack"} {
+		if transportOptions.GetBool(opt) {
+			return i18n.NewError(wh.ctx, coremsgs.MsgWebhooksBatchOption, opt)
+		}
+	}
+	if len(transportOptions.GetObject("input")) > 0 {
+		return i18n.NewError(wh.ctx, coremsgs.MsgWebhooksBatchOption, "input")
+	}
+	if options.Template != nil {
+		return i18n.NewError(wh.ctx, coremsgs.MsgWebhooksBatchOption, "template")
+	}
+	if options.Format == core.SubOptsFormatCloudEvents && req.ceMode == cloudEventsModeBinary {
+		return i18n.NewError(wh.ctx, coremsgs.MsgWebhooksBatchOption, "cloudEventsMode")
+	}
+	return nil
+}
+
+// buildRetryPolicy applies defaults to the retry options of a subscription. A nil policy is returned if
+// the subscription does not have retry options, in which case the webhook is only invoked once.
+func (wh *WebHooks) buildRetryPolicy(options *core.WebhookRetryOptions) (*retryPolicy, error) {
+	if options == nil {
+		return nil, nil
+	}
+	rp := &retryPolicy{
+		retry: retry.Retry{
+			InitialDelay: defaultRetryInitialDelay,
+			MaximumDelay: defaultRetryMaxDelay,
+			Factor:       defaultRetryFactor,
+		},
+		maxAttempts: defaultRetryMaxAttempts,
+		statusCodes: defaultRetryStatusCodes,
+	}
+	if options.MaxAttempts < 0 {
+		return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "maxAttempts", options.MaxAttempts)
+	} else if options.MaxAttempts > 0 {
+		rp.maxAttempts = options.MaxAttempts
+	}
+	if options.InitialDelay != nil {
+		if *options.InitialDelay < 0 {
+			return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "initialDelay", options.InitialDelay)
+		}
+		rp.retry.InitialDelay = time.Duration(*options.InitialDelay)
+	}
+	if options.MaxDelay != nil {
+		if *options.MaxDelay < 0 {
+			return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "maxDelay", options.MaxDelay)
+		}
+		rp.retry.MaximumDelay = time.Duration(*options.MaxDelay)
+	}
+	if options.Factor != 0 {
+		if options.Factor < 1 {
+			return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "factor", options.Factor)
+		}
+		rp.retry.Factor = options.Factor
+	}
+	if len(options.StatusCodes) > 0 {
+		for _, code := range options.StatusCodes {
+			if code < 100 || code > 599 {
+				return nil, i18n.NewError(wh.ctx, coremsgs.MsgWebhookInvalidRetryOption, "statusCodes", code)
+			}
+		}
+		rp.statusCodes = options.StatusCodes
+	}
+	return rp, nil
+}
+
+func (rp *retryPolicy) isRetryable(status int) bool {
+	for _, code := range rp.statusCodes {
+		if code == status {
+			return true
+		}
+	}
+	return false
+}
+
 func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) (req *whRequest, res *whResponse, err error) {
 	withData := sub.Options.WithData != nil && *sub.Options.WithData
 	allData := make([]*fftypes.JSONAny, 0, len(data))
@@ -229,44 +401,72 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		}
 	}
 
-	// Create a new ffresty client from the config
-	// 1) We do not want to modify that global instance
-	// 2) We want to keep the global configuration for webhooks
-	copyFFrestyConfig := *wh.ffrestyConfig
-	if sub.Options.TLSConfig != nil {
-		copyFFrestyConfig.TLSClientConfig = sub.Options.TLSConfig
+	req, err = wh.buildRequest(wh.newClient(sub), sub.Options.TransportOptions(), firstData)
+	if err != nil {
+		return nil, nil, err
 	}
-
-	client := ffresty.NewWithConfig(wh.ctx, copyFFrestyConfig)
-
-	req, err = wh.buildRequest(client, sub.Options.TransportOptions(), firstData)
+	rt, err := parseRequestTemplate(wh.ctx, sub.Options.Template, req.url)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	var body interface{}
 	if req.method == http.MethodPost || req.method == http.MethodPatch || req.method == http.MethodPut {
 		switch {
 		case req.body != nil:
 			// We might have been told to extract a body from the first data record
-			req.r.SetBody(req.body)
+			body = req.body
 		case len(allData) > 1:
 			// We've got an array of data to POST
-			req.r.SetBody(allData)
+			body = allData
 		case len(allData) == 1:
 			// Just send the first object directly
-			req.r.SetBody(firstData)
+			body = firstData
 		default:
 			// Just send the event itself
-			req.r.SetBody(event)
-
+			body = event
+		}
+	}
+	if rt != nil {
+		// A template can set the path parameters and headers from the event, and transform the body
+		templateBody, err := rt.apply(wh.ctx, req, event, allData)
+		if err != nil {
+			return nil, nil, err
+		}
+		if templateBody != nil && body != nil {
+			body = *templateBody
 		}
 	}
+	if sub.Options.Format == core.SubOptsFormatCloudEvents {
+		body = req.setCloudEvent(event, body)
+	}
+	res, err = wh.executeRequest(sub, req, body, fmt.Sprintf("event %s", event.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, res, nil
+}
 
-	log.L(wh.ctx).Debugf("Webhook-> %s %s event %s on subscription %s", req.method, req.url, event.ID, sub.ID)
+// newClient creates a new ffresty client from the config, as we do not want to modify the global
+// instance, but we want to keep the global configuration for webhooks
+func (wh *WebHooks) newClient(sub *core.Subscription) *resty.Client {
+	copyFFrestyConfig := *wh.ffrestyConfig
+	if sub.Options.TLSConfig != nil {
+		copyFFrestyConfig.TLSClientConfig = sub.Options.TLSConfig
+	}
+	return ffresty.NewWithConfig(wh.ctx, copyFFrestyConfig)
+}
+
+func (wh *WebHooks) executeRequest(sub *core.Subscription, req *whRequest, body interface{}, what string) (res *whResponse, err error) {
+	if body != nil {
+		req.r.SetBody(body)
+	}
+
+	log.L(wh.ctx).Debugf("Webhook-> %s %s %s on subscription %s", req.method, req.url, what, sub.ID)
 	resp, err := req.r.Execute(req.method, req.url)
 	if err != nil {
-		log.L(wh.ctx).Errorf("Webhook<- %s %s event %s on subscription %s failed: %s", req.method, req.url, event.ID, sub.ID, err)
-		return nil, nil, err
+		log.L(wh.ctx).Errorf("Webhook<- %s %s %s on subscription %s failed: %s", req.method, req.url, what, sub.ID, err)
+		return nil, err
 	}
 	defer func() { _ = resp.RawBody().Close() }()
 
@@ -274,7 +474,7 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		Status:  resp.StatusCode(),
 		Headers: fftypes.JSONObject{},
 	}
-	log.L(wh.ctx).Infof("Webhook<- %s %s event %s on subscription %s returned %d", req.method, req.url, event.ID, sub.ID, res.Status)
+	log.L(wh.ctx).Infof("Webhook<- %s %s %s on subscription %s returned %d", req.method, req.url, what, sub.ID, res.Status)
 	header := resp.Header()
 	for h := range header {
 		res.Headers[h] = header.Get(h)
@@ -289,7 +489,7 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		var resData interface{}
 		err = json.NewDecoder(resp.RawBody()).Decode(&resData)
 		if err != nil {
-			return nil, nil, i18n.WrapError(wh.ctx, err, coremsgs.MsgWebhooksReplyBadJSON)
+			return nil, i18n.WrapError(wh.ctx, err, coremsgs.MsgWebhooksReplyBadJSON)
 		}
 		b, _ := json.Marshal(&resData) // we know we can re-marshal It
 		res.Body = fftypes.JSONAnyPtrBytes(b)
@@ -304,11 +504,78 @@ func (wh *WebHooks) attemptRequest(sub *core.Subscription, event *core.EventDeli
 		res.Body = fftypes.JSONAnyPtrBytes(buf.Bytes())
 	}
 
-	return req, res, nil
+	return res, nil
+}
+
+// attemptDelivery invokes the webhook, retrying failures according to the retry policy of the subscription.
+// A dead letter is returned if the policy is exhausted, or the webhook fails with a status that is not retryable.
+func (wh *WebHooks) attemptDelivery(sub *core.Subscription, event *core.EventDelivery, data core.DataArray) (req *whRequest, res *whResponse, deadLetter *core.DeadLetter, err error) {
+	req, res, deadLetter, err = wh.retryRequest(sub, func() (*whRequest, *whResponse, error) {
+		return wh.attemptRequest(sub, event, data)
+	})
+	if deadLetter != nil {
+		deadLetter.Event = event.ID
+		deadLetter.EventType = event.Type
+		deadLetter.Reference = event.Reference
+		b, _ := json.Marshal(event)
+		deadLetter.OriginalEvent = fftypes.JSONAnyPtrBytes(b)
+	}
+	return req, res, deadLetter, err
+}
+
+// retryRequest performs a request according to the retry policy of the subscription. The dead letter returned
+// if the request ultimately fails only records the failure, and must be completed with the event by the caller.
+func (wh *WebHooks) retryRequest(sub *core.Subscription, attemptRequest func() (*whRequest, *whResponse, error)) (req *whRequest, res *whResponse, deadLetter *core.DeadLetter, err error) {
+	policy, err := wh.buildRetryPolicy(sub.Options.Retry)
+	if err != nil || policy == nil {
+		if err == nil {
+			req, res, err = attemptRequest()
+		}
+		return req, res, nil, err
+	}
+
+	attempts := 0
+	var history core.DeadLetterAttempts
+	var attemptErr error
+	err = policy.retry.Do(wh.ctx, "webhook", func(attempt int) (bool, error) {
+		attempts = attempt
+		req, res, attemptErr = attemptRequest()
+		switch {
+		case attemptErr != nil:
+			// Failing to get a response at all is always retryable
+			history = append(history, &core.DeadLetterAttempt{Time: fftypes.Now(), Error: attemptErr.Error()})
+			return attempt < policy.maxAttempts, attemptErr
+		case res.Status < 300:
+			return false, nil
+		default:
+			statusErr := i18n.NewError(wh.ctx, coremsgs.MsgWebhookFailedStatus, res.Status)
+			history = append(history, &core.DeadLetterAttempt{Time: fftypes.Now(), Status: res.Status, Error: statusErr.Error()})
+			return policy.isRetryable(res.Status) && attempt < policy.maxAttempts, statusErr
+		}
+	})
+	if err != nil {
+		deadLetter = &core.DeadLetter{
+			Attempts: attempts,
+			Error:    err.Error(),
+			History:  history,
+		}
+		if res != nil {
+			deadLetter.Status = res.Status
+		}
+	}
+	if res == nil {
+		return req, nil, deadLetter, attemptErr
+	}
+	return req, res, deadLetter, nil
 }
 
 func (wh *WebHooks) doDelivery(connID string, reply bool, sub *core.Subscription, event *core.EventDelivery, data core.DataArray, fastAck bool) {
-	req, res, gwErr := wh.attemptRequest(sub, event, data)
+	req, res, deadLetter, gwErr := wh.attemptDelivery(sub, event, data)
+	if deadLetter != nil && wh.ctx.Err() != nil {
+		// We were interrupted while retrying, so leave the event to be redelivered on restart
+		log.L(wh.ctx).Infof("Webhook delivery of event %s interrupted after %d attempts", event.ID, deadLetter.Attempts)
+		return
+	}
 	if gwErr != nil {
 		// Generate a bad-gateway error response - we always want to send something back,
 		// rather than just causing timeouts
@@ -339,6 +606,7 @@ func (wh *WebHooks) doDelivery(connID string, reply bool, sub *core.Subscription
 				ID:           event.ID,
 				Rejected:     false,
 				Subscription: event.Subscription,
+				DeadLetter:   deadLetter,
 				Reply: &core.MessageInOut{
 					Message: core.Message{
 						Header: core.MessageHeader{
@@ -356,12 +624,14 @@ func (wh *WebHooks) doDelivery(connID string, reply bool, sub *core.Subscription
 				},
 			})
 		}
-	} else if !fastAck {
+	} else if !fastAck || deadLetter != nil {
+		// In fastack mode the event has already been acknowledged, but we still need to report the dead letter
 		if cb, ok := wh.callbacks.handlers[sub.Namespace]; ok {
 			cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
 				ID:           event.ID,
 				Rejected:     false,
 				Subscription: event.Subscription,
+				DeadLetter:   deadLetter,
 			})
 		}
 	}
@@ -403,6 +673,89 @@ func (wh *WebHooks) DeliveryRequest(connID string, sub *core.Subscription, event
 	return nil
 }
 
+// attemptBatchRequest POSTs the events of a batch as a JSON array. With CloudEvents each event is wrapped
+// in its own envelope, and the array is sent in the CloudEvents batched content mode.
+func (wh *WebHooks) attemptBatchRequest(sub *core.Subscription, events []*core.CombinedEventDataDelivery) (req *whRequest, res *whResponse, err error) {
+	req, err = wh.buildRequest(wh.newClient(sub), sub.Options.TransportOptions(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	withData := sub.Options.WithData != nil && *sub.Options.WithData
+	cloudEvents := sub.Options.Format == core.SubOptsFormatCloudEvents
+	body := make([]interface{}, len(events))
+	for i, e := range events {
+		be := &whBatchEvent{EventDelivery: e.Event}
+		if withData {
+			be.Data = make([]*fftypes.JSONAny, 0, len(e.Data))
+			for _, d := range e.Data {
+				if d.Value != nil {
+					be.Data = append(be.Data, d.Value)
+				}
+			}
+		}
+		if cloudEvents {
+			body[i] = core.NewCloudEvent(e.Event, be)
+		} else {
+			body[i] = be
+		}
+	}
+	if cloudEvents {
+		req.r.SetHeader("Content-Type", "application/cloudevents-batch+json")
+	}
+
+	res, err = wh.executeRequest(sub, req, body, fmt.Sprintf("batch of %d events", len(events)))
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, res, nil
+}
+
+// BatchDeliveryRequest delivers all the events of the batch in a single request, and acknowledges them together
+// once it completes. If the retry policy is exhausted, every event in the batch is dead lettered.
+func (wh *WebHooks) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	_, _, failure, err := wh.retryRequest(sub, func() (*whRequest, *whResponse, error) {
+		return wh.attemptBatchRequest(sub, events)
+	})
+	if failure != nil && wh.ctx.Err() != nil {
+		// We were interrupted while retrying, so leave the batch to be redelivered on restart
+		log.L(wh.ctx).Infof("Webhook delivery of batch of %d events interrupted after %d attempts", len(events), failure.Attempts)
+		return nil
+	}
+	if err != nil {
+		// We acknowledge the batch even if we could not invoke the webhook, as we do for a single event
+		log.L(wh.ctx).Errorf("Failed to invoke webhook with batch of %d events: %s", len(events), err)
+	}
+
+	cb, ok := wh.callbacks.handlers[sub.Namespace]
+	if !ok {
+		return nil
+	}
+	for _, e := range events {
+		var deadLetter *core.DeadLetter
+		if failure != nil {
+			b, _ := json.Marshal(e.Event)
+			deadLetter = &core.DeadLetter{
+				Event:         e.Event.ID,
+				EventType:     e.Event.Type,
+				Reference:     e.Event.Reference,
+				Attempts:      failure.Attempts,
+				Error:         failure.Error,
+				Status:        failure.Status,
+				History:       failure.History,
+				OriginalEvent: fftypes.JSONAnyPtrBytes(b),
+			}
+		}
+		cb.DeliveryResponse(connID, &core.EventDeliveryResponse{
+			ID:           e.Event.ID,
+			Rejected:     false,
+			Subscription: e.Event.Subscription,
+			DeadLetter:   deadLetter,
+		})
+	}
+	return nil
+}
+
 func (wh *WebHooks) NamespaceRestarted(ns string, startTime time.Time) {
 	// no-op
 }
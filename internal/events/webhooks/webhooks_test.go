@@ -32,6 +32,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -1069,9 +1070,562 @@ func TestDeliveryRequestReplyToReply(t *testing.T) {
 	mcb.AssertExpectations(t)
 }
 
+func TestValidateOptionsBadRetry(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	negative := fftypes.FFDuration(-1)
+	for field, retry := range map[string]*core.WebhookRetryOptions{
+		"maxAttempts":  {MaxAttempts: -1},
+		"initialDelay": {InitialDelay: &negative},
+		"maxDelay":     {MaxDelay: &negative},
+		"factor":       {Factor: 0.5},
+		"statusCodes":  {StatusCodes: []int{503, 999}},
+	} {
+		opts := &core.SubscriptionOptions{}
+		opts.TransportOptions()["url"] = "/anything"
+		opts.Retry = retry
+		err := wh.ValidateOptions(opts)
+		assert.Regexp(t, "FF10547.*"+field, err)
+	}
+}
+
+func newTestRetrySubscription(url string, retry *core.WebhookRetryOptions) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	sub.Options.TransportOptions()["url"] = url
+	delay := fftypes.FFDuration(time.Millisecond)
+	retry.InitialDelay = &delay
+	retry.MaxDelay = &delay
+	sub.Options.Retry = retry
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Type:      core.EventTypeMessageConfirmed,
+				Reference: fftypes.NewUUID(),
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+func TestRequestRetryThenSuccess(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls < 3 {
+			res.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), &core.WebhookRetryOptions{
+		Factor:      1.5,
+		StatusCodes: []int{http.StatusServiceUnavailable},
+	})
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected && response.DeadLetter == nil
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryExhaustedDeadLetter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.WriteHeader(http.StatusBadGateway)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), &core.WebhookRetryOptions{
+		MaxAttempts: 2,
+	})
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		dl := response.DeadLetter
+		return !response.Rejected && dl != nil &&
+			*dl.Event == *event.ID &&
+			dl.EventType == core.EventTypeMessageConfirmed &&
+			*dl.Reference == *event.Reference &&
+			dl.Attempts == 2 &&
+			dl.Status == http.StatusBadGateway &&
+			len(dl.History) == 2 &&
+			dl.History[1].Status == http.StatusBadGateway &&
+			strings.Contains(dl.OriginalEvent.String(), event.ID.String())
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestNotRetryableDeadLetter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.WriteHeader(http.StatusBadRequest)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), &core.WebhookRetryOptions{})
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		dl := response.DeadLetter
+		return dl != nil && dl.Attempts == 1 && dl.Status == http.StatusBadRequest
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryConnectionErrorFastAckDeadLetter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	server := httptest.NewServer(mux.NewRouter())
+	server.Close()
+
+	sub, event := newTestRetrySubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), &core.WebhookRetryOptions{
+		MaxAttempts: 2,
+	})
+	sub.Options.TransportOptions()["fastack"] = true
+
+	deadLettered := make(chan *core.DeadLetter)
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.DeadLetter == nil
+	})).Return(nil).Once()
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return response.DeadLetter != nil
+	})).Return(nil).Run(func(a mock.Arguments) {
+		deadLettered <- a[1].(*core.EventDeliveryResponse).DeadLetter
+	})
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+
+	dl := <-deadLettered
+	assert.Equal(t, 2, dl.Attempts)
+	assert.Zero(t, dl.Status)
+	assert.NotEmpty(t, dl.Error)
+	assert.Len(t, dl.History, 2)
+	assert.NotEmpty(t, dl.History[0].Error)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestRetryInterrupted(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	cancel()
+
+	sub, event := newTestRetrySubscription("http://localhost:1/myapi", &core.WebhookRetryOptions{})
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.AssertNotCalled(t, "DeliveryResponse", mock.Anything, mock.Anything)
+}
+
 func TestNamespaceRestarted(t *testing.T) {
 	wh, cancel := newTestWebHooks(t)
 	defer cancel()
 
 	wh.NamespaceRestarted("ns1", time.Now())
 }
+
+func TestValidateOptionsBadCloudEventsMode(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	opts := &core.SubscriptionOptions{}
+	opts.TransportOptions()["url"] = "/anything"
+	opts.TransportOptions()["cloudEventsMode"] = "batched"
+	err := wh.ValidateOptions(opts)
+	assert.Regexp(t, "FF10553.*batched", err)
+}
+
+func newTestCloudEventsSubscription(url, mode string) (*core.Subscription, *core.EventDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	sub.Options.Format = core.SubOptsFormatCloudEvents
+	sub.Options.TransportOptions()["url"] = url
+	sub.Options.TransportOptions()["cloudEventsMode"] = mode
+	event := &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Namespace: "ns1",
+				Sequence:  12345,
+				Type:      core.EventTypeMessageConfirmed,
+				Reference: fftypes.NewUUID(),
+				Topic:     "topic1",
+				Created:   fftypes.Now(),
+			},
+		},
+		Subscription: sub.SubscriptionRef,
+	}
+	return sub, event
+}
+
+func TestRequestCloudEventsStructured(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/cloudevents+json", req.Header.Get("Content-Type"))
+		var ce core.CloudEvent
+		err := json.NewDecoder(req.Body).Decode(&ce)
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0", ce.SpecVersion)
+		assert.Equal(t, "/namespaces/ns1", ce.Source)
+		assert.Equal(t, "io.hyperledger.firefly.message_confirmed", ce.Type)
+		assert.Equal(t, int64(12345), ce.FireFlySequence)
+		assert.Equal(t, "topic1", ce.FireFlyTopic)
+		assert.Equal(t, ce.ID, ce.Data.(map[string]interface{})["id"])
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestCloudEventsSubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), "")
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestCloudEventsBinary(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	sub, event := newTestCloudEventsSubscription("", "binary")
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		assert.Equal(t, "1.0", req.Header.Get("ce-specversion"))
+		assert.Equal(t, event.ID.String(), req.Header.Get("ce-id"))
+		assert.Equal(t, "/namespaces/ns1", req.Header.Get("ce-source"))
+		assert.Equal(t, "io.hyperledger.firefly.message_confirmed", req.Header.Get("ce-type"))
+		assert.Equal(t, event.Reference.String(), req.Header.Get("ce-subject"))
+		assert.Equal(t, event.Created.String(), req.Header.Get("ce-time"))
+		assert.Equal(t, "12345", req.Header.Get("ce-fireflysequence"))
+		assert.Equal(t, "topic1", req.Header.Get("ce-fireflytopic"))
+		assert.Equal(t, sub.ID.String(), req.Header.Get("ce-fireflysubscription"))
+		var body fftypes.JSONObject
+		err := json.NewDecoder(req.Body).Decode(&body)
+		assert.NoError(t, err)
+		assert.Equal(t, event.ID.String(), body.GetString("id"))
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+	sub.Options.TransportOptions()["url"] = fmt.Sprintf("http://%s/myapi", server.Listener.Addr())
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestRequestCloudEventsStructuredNoBody(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, event := newTestCloudEventsSubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()), "structured")
+	sub.Options.TransportOptions()["method"] = http.MethodGet
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil)
+
+	err := wh.DeliveryRequest(mock.Anything, sub, event, nil)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestValidateOptionsBadBatchOptions(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	for _, tc := range []struct {
+		option string
+		value  interface{}
+	}{
+		{"reply", true},
+		{"fastack", true},
+		{"input", map[string]interface{}{"body": "value"}},
+		{"cloudEventsMode", "binary"},
+	} {
+		opts := &core.SubscriptionOptions{}
+		opts.Format = core.SubOptsFormatCloudEvents
+		opts.Batch = &core.SubscriptionBatchOptions{Size: 10}
+		opts.TransportOptions()["url"] = "/anything"
+		opts.TransportOptions()[tc.option] = tc.value
+		err := wh.ValidateOptions(opts)
+		assert.Regexp(t, "FF10569.*"+tc.option, err)
+	}
+}
+
+func newTestBatchSubscription(url string) (*core.Subscription, []*core.CombinedEventDataDelivery) {
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}
+	sub.Options.Batch = &core.SubscriptionBatchOptions{Size: 10}
+	sub.Options.TransportOptions()["url"] = url
+	events := make([]*core.CombinedEventDataDelivery, 2)
+	for i := range events {
+		events[i] = &core.CombinedEventDataDelivery{
+			Event: &core.EventDelivery{
+				EnrichedEvent: core.EnrichedEvent{
+					Event: core.Event{
+						ID:        fftypes.NewUUID(),
+						Namespace: "ns1",
+						Sequence:  int64(i),
+						Type:      core.EventTypeMessageConfirmed,
+						Reference: fftypes.NewUUID(),
+					},
+				},
+				Subscription: sub.SubscriptionRef,
+			},
+		}
+	}
+	return sub, events
+}
+
+func TestBatchDeliveryRequestWithData(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+		var body []map[string]interface{}
+		err := json.NewDecoder(req.Body).Decode(&body)
+		assert.NoError(t, err)
+		assert.Len(t, body, 2)
+		assert.Equal(t, float64(0), body[0]["sequence"])
+		assert.Equal(t, []interface{}{"value1", "value2"}, body[0]["data"])
+		assert.Equal(t, float64(1), body[1]["sequence"])
+		assert.Empty(t, body[1]["data"])
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, events := newTestBatchSubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	withData := true
+	sub.Options.WithData = &withData
+	events[0].Data = core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value1"`)},
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"value2"`)},
+		{ID: fftypes.NewUUID()},
+	}
+
+	acked := map[fftypes.UUID]bool{}
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected && response.DeadLetter == nil
+	})).Return(nil).Run(func(a mock.Arguments) {
+		acked[*a[1].(*core.EventDeliveryResponse).ID] = true
+	})
+
+	err := wh.BatchDeliveryRequest(mock.Anything, sub, events)
+	assert.NoError(t, err)
+	assert.True(t, acked[*events[0].Event.ID])
+	assert.True(t, acked[*events[1].Event.ID])
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryRequestCloudEvents(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "application/cloudevents-batch+json", req.Header.Get("Content-Type"))
+		var ces []*core.CloudEvent
+		err := json.NewDecoder(req.Body).Decode(&ces)
+		assert.NoError(t, err)
+		assert.Len(t, ces, 2)
+		for _, ce := range ces {
+			assert.Equal(t, "io.hyperledger.firefly.message_confirmed", ce.Type)
+			assert.Equal(t, ce.ID, ce.Data.(map[string]interface{})["id"])
+		}
+		res.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, events := newTestBatchSubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	sub.Options.Format = core.SubOptsFormatCloudEvents
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected
+	})).Return(nil).Twice()
+
+	err := wh.BatchDeliveryRequest(mock.Anything, sub, events)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryRequestRetryExhaustedDeadLetter(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	calls := 0
+	r := mux.NewRouter()
+	r.HandleFunc("/myapi", func(res http.ResponseWriter, req *http.Request) {
+		calls++
+		res.WriteHeader(http.StatusBadGateway)
+	}).Methods(http.MethodPost)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	sub, events := newTestBatchSubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+	delay := fftypes.FFDuration(time.Millisecond)
+	sub.Options.Retry = &core.WebhookRetryOptions{MaxAttempts: 2, InitialDelay: &delay, MaxDelay: &delay}
+
+	deadLetters := map[fftypes.UUID]*core.DeadLetter{}
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected && response.DeadLetter != nil
+	})).Return(nil).Run(func(a mock.Arguments) {
+		response := a[1].(*core.EventDeliveryResponse)
+		deadLetters[*response.ID] = response.DeadLetter
+	})
+
+	err := wh.BatchDeliveryRequest(mock.Anything, sub, events)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	for _, e := range events {
+		dl := deadLetters[*e.Event.ID]
+		assert.Equal(t, *e.Event.ID, *dl.Event)
+		assert.Equal(t, *e.Event.Reference, *dl.Reference)
+		assert.Equal(t, 2, dl.Attempts)
+		assert.Equal(t, http.StatusBadGateway, dl.Status)
+		assert.Len(t, dl.History, 2)
+		assert.Contains(t, dl.OriginalEvent.String(), e.Event.ID.String())
+	}
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryRequestConnectionError(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	server := httptest.NewServer(mux.NewRouter())
+	server.Close()
+
+	sub, events := newTestBatchSubscription(fmt.Sprintf("http://%s/myapi", server.Listener.Addr()))
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.MatchedBy(func(response *core.EventDeliveryResponse) bool {
+		return !response.Rejected && response.DeadLetter == nil
+	})).Return(nil).Twice()
+
+	err := wh.BatchDeliveryRequest(mock.Anything, sub, events)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
+
+func TestBatchDeliveryRequestRetryInterrupted(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	cancel()
+
+	sub, events := newTestBatchSubscription("http://localhost:1/myapi")
+	sub.Options.Retry = &core.WebhookRetryOptions{}
+
+	err := wh.BatchDeliveryRequest(mock.Anything, sub, events)
+	assert.NoError(t, err)
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.AssertNotCalled(t, "DeliveryResponse", mock.Anything, mock.Anything)
+}
+
+func TestBatchDeliveryRequestBadOptions(t *testing.T) {
+	wh, cancel := newTestWebHooks(t)
+	defer cancel()
+
+	sub, events := newTestBatchSubscription("")
+
+	mcb := wh.callbacks.handlers["ns1"].(*eventsmocks.Callbacks)
+	mcb.On("DeliveryResponse", mock.Anything, mock.Anything).Return(nil).Twice()
+
+	err := wh.BatchDeliveryRequest(mock.Anything, sub, events)
+	assert.NoError(t, err)
+
+	mcb.AssertExpectations(t)
+}
@@ -0,0 +1,87 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websockets
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"github.com/ugorji/go/codec"
+)
+
+const (
+	encodingJSON = "json"
+	encodingCBOR = "cbor"
+)
+
+var cborHandle = newCBORHandle()
+
+func newCBORHandle() *codec.CborHandle {
+	h := &codec.CborHandle{}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.Canonical = true
+	return h
+}
+
+// jsonToCBOR encodes a message as CBOR, from its JSON representation, so the fields and
+// formatting of the values are the same in both encodings
+func jsonToCBOR(msg interface{}) ([]byte, error) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	var generic interface{}
+	_ = decoder.Decode(&generic) // we just generated valid JSON
+	var out []byte
+	err = codec.NewEncoderBytes(&out, cborHandle).Encode(cborNumbers(generic))
+	return out, err
+}
+
+// cborNumbers converts JSON numbers to CBOR integers where possible, so sequences
+// and other integer values are not encoded as floating point
+func cborNumbers(v interface{}) interface{} {
+	switch vt := v.(type) {
+	case map[string]interface{}:
+		for k, e := range vt {
+			vt[k] = cborNumbers(e)
+		}
+	case []interface{}:
+		for i, e := range vt {
+			vt[i] = cborNumbers(e)
+		}
+	case json.Number:
+		if i, err := vt.Int64(); err == nil {
+			return i
+		}
+		f, _ := vt.Float64()
+		return f
+	}
+	return v
+}
+
+// cborToJSON converts a CBOR message sent by the client into JSON, for processing
+// in the same way as a JSON message
+func cborToJSON(data []byte) ([]byte, error) {
+	var generic interface{}
+	if err := codec.NewDecoderBytes(data, cborHandle).Decode(&generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
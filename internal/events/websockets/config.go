@@ -16,21 +16,55 @@
 
 package websockets
 
-import "github.com/hyperledger/firefly-common/pkg/config"
+import (
+	"compress/flate"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+)
 
 const (
-	bufferSizeDefault = "16Kb"
+	bufferSizeDefault       = "16Kb"
+	compressionLevelDefault = flate.BestSpeed
 )
 
 const (
+	// Compression enables negotiation of permessage-deflate compression
+	Compression = "compression"
+	// CompressionLevel is the deflate compression level used on compressed connections
+	CompressionLevel = "compressionLevel"
 	// ReadBufferSize is the read buffer size for the socket
 	ReadBufferSize = "readBufferSize"
 	// WriteBufferSize is the write buffer size for the socket
 	WriteBufferSize = "writeBufferSize"
 )
 
+const (
+	// JWTSecret is the shared secret for verifying HMAC signed bearer tokens
+	JWTSecret = "secret"
+	// JWTPublicKeyFile is a PEM file with the RSA public key for verifying RSA signed bearer tokens
+	JWTPublicKeyFile = "publicKeyFile"
+	// JWTIssuer is the required issuer of bearer tokens
+	JWTIssuer = "issuer"
+	// JWTAudience is the required audience of bearer tokens
+	JWTAudience = "audience"
+	// JWTNamespacesClaim is the claim that lists the permitted namespaces
+	JWTNamespacesClaim = "namespacesClaim"
+	// JWTSubscriptionsClaim is the claim that lists the permitted subscriptions
+	JWTSubscriptionsClaim = "subscriptionsClaim"
+)
+
 func (ws *WebSockets) InitConfig(config config.Section) {
+	config.AddKnownKey(Compression, false)
+	config.AddKnownKey(CompressionLevel, compressionLevelDefault)
 	config.AddKnownKey(ReadBufferSize, bufferSizeDefault)
 	config.AddKnownKey(WriteBufferSize, bufferSizeDefault)
 
+	jwtConfig := config.SubSection("jwt")
+	jwtConfig.AddKnownKey(JWTSecret)
+	jwtConfig.AddKnownKey(JWTPublicKeyFile)
+	jwtConfig.AddKnownKey(JWTIssuer)
+	jwtConfig.AddKnownKey(JWTAudience)
+	jwtConfig.AddKnownKey(JWTNamespacesClaim, "namespaces")
+	jwtConfig.AddKnownKey(JWTSubscriptionsClaim, "subscriptions")
+
 }
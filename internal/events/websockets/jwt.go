@@ -0,0 +1,223 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websockets
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // registers SHA-256 for the HS256 and RS256 algorithms
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for the other algorithms
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type jwtAlgorithm struct {
+	hmac bool
+	hash crypto.Hash
+}
+
+var jwtAlgorithms = map[string]jwtAlgorithm{
+	"HS256": {hmac: true, hash: crypto.SHA256},
+	"HS384": {hmac: true, hash: crypto.SHA384},
+	"HS512": {hmac: true, hash: crypto.SHA512},
+	"RS256": {hash: crypto.SHA256},
+	"RS384": {hash: crypto.SHA384},
+	"RS512": {hash: crypto.SHA512},
+}
+
+type jwtVerifier struct {
+	secret             []byte
+	publicKey          *rsa.PublicKey
+	issuer             string
+	audience           string
+	namespacesClaim    string
+	subscriptionsClaim string
+}
+
+// tokenClaims are the permissions granted to a connection by its bearer token
+type tokenClaims struct {
+	expiry           time.Time
+	namespaces       []string
+	subscriptions    []string
+	allSubscriptions bool
+}
+
+// newJWTVerifier returns nil if JWT authentication is not configured
+func newJWTVerifier(ctx context.Context, conf config.Section) (*jwtVerifier, error) {
+	v := &jwtVerifier{
+		secret:             []byte(conf.GetString(JWTSecret)),
+		issuer:             conf.GetString(JWTIssuer),
+		audience:           conf.GetString(JWTAudience),
+		namespacesClaim:    conf.GetString(JWTNamespacesClaim),
+		subscriptionsClaim: conf.GetString(JWTSubscriptionsClaim),
+	}
+	if keyFile := conf.GetString(JWTPublicKeyFile); keyFile != "" {
+		b, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgWSInvalidJWTPublicKey, keyFile)
+		}
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidJWTPublicKey, keyFile)
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgWSInvalidJWTPublicKey, keyFile)
+		}
+		var ok bool
+		if v.publicKey, ok = key.(*rsa.PublicKey); !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidJWTPublicKey, keyFile)
+		}
+	}
+	if len(v.secret) == 0 && v.publicKey == nil {
+		return nil, nil
+	}
+	return v, nil
+}
+
+// bearerToken extracts the token from the Authorization header, or from the access_token
+// query parameter for browser clients that cannot set headers on the upgrade
+func bearerToken(req *http.Request) string {
+	if auth := req.Header.Get("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "bearer ") {
+		return auth[7:]
+	}
+	return req.URL.Query().Get("access_token")
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err == nil {
+		err = json.Unmarshal(b, v)
+	}
+	return err
+}
+
+func (v *jwtVerifier) verify(ctx context.Context, token string) (*tokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "malformed token")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	var claims map[string]interface{}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "malformed header")
+	}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "malformed claims")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "malformed signature")
+	}
+
+	var valid bool
+	signed := []byte(parts[0] + "." + parts[1])
+	alg, ok := jwtAlgorithms[header.Alg]
+	switch {
+	case ok && alg.hmac && len(v.secret) > 0:
+		mac := hmac.New(alg.hash.New, v.secret)
+		mac.Write(signed)
+		valid = hmac.Equal(signature, mac.Sum(nil))
+	case ok && !alg.hmac && v.publicKey != nil:
+		hash := alg.hash.New()
+		hash.Write(signed)
+		valid = rsa.VerifyPKCS1v15(v.publicKey, alg.hash, hash.Sum(nil), signature) == nil
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, fmt.Sprintf("unsupported algorithm '%s'", header.Alg))
+	}
+	if !valid {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "invalid signature")
+	}
+	return v.checkClaims(ctx, claims)
+}
+
+func (v *jwtVerifier) checkClaims(ctx context.Context, claims map[string]interface{}) (*tokenClaims, error) {
+	now := time.Now()
+	tc := &tokenClaims{}
+	if exp, ok := claims["exp"].(float64); ok {
+		tc.expiry = time.Unix(int64(exp), 0)
+		if !now.Before(tc.expiry) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "token expired")
+		}
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Before(time.Unix(int64(nbf), 0)) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "token not yet valid")
+	}
+	if v.issuer != "" && claims["iss"] != v.issuer {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "issuer mismatch")
+	}
+	if v.audience != "" && !claimIncludes(claimStrings(claims["aud"]), v.audience) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgWSInvalidToken, "audience mismatch")
+	}
+	tc.namespaces = claimStrings(claims[v.namespacesClaim])
+	subscriptions, restricted := claims[v.subscriptionsClaim]
+	tc.subscriptions = claimStrings(subscriptions)
+	tc.allSubscriptions = !restricted || claimIncludes(tc.subscriptions, "*")
+	return tc, nil
+}
+
+// claimStrings accepts either an array of strings, or a space separated string like an OAuth scope
+func claimStrings(claim interface{}) []string {
+	switch ct := claim.(type) {
+	case string:
+		return strings.Fields(ct)
+	case []interface{}:
+		values := make([]string, 0, len(ct))
+		for _, v := range ct {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func claimIncludes(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// permits checks a subscription can be started, with the permissions granted by the token.
+// Ephemeral subscriptions cannot be restricted by name, so are only permitted if all subscriptions are.
+func (tc *tokenClaims) permits(start *core.WSStart) bool {
+	if !claimIncludes(tc.namespaces, start.Namespace) && !claimIncludes(tc.namespaces, "*") {
+		return false
+	}
+	return tc.allSubscriptions ||
+		(!start.Ephemeral && (claimIncludes(tc.subscriptions, start.Name) || claimIncludes(tc.subscriptions, start.Namespace+":"+start.Name)))
+}
@@ -0,0 +1,202 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package websockets
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+const testJWTSecret = "testsecret"
+
+func newTestJWTConfig() config.Section {
+	coreconfig.Reset()
+	conf := config.RootSection("ut.websockets")
+	(&WebSockets{}).InitConfig(conf)
+	return conf.SubSection("jwt")
+}
+
+func encodeTestJWTParts(alg string, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func newTestJWT(claims map[string]interface{}) string {
+	signed := encodeTestJWTParts("HS256", claims)
+	mac := hmac.New(sha256.New, []byte(testJWTSecret))
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newTestJWTVerifier(t *testing.T) *jwtVerifier {
+	conf := newTestJWTConfig()
+	conf.Set(JWTSecret, testJWTSecret)
+	v, err := newJWTVerifier(context.Background(), conf)
+	assert.NoError(t, err)
+	return v
+}
+
+func writeTestKeyFile(t *testing.T, contents []byte) string {
+	keyFile := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(keyFile, contents, 0600)
+	assert.NoError(t, err)
+	return keyFile
+}
+
+func TestJWTNotConfigured(t *testing.T) {
+	v, err := newJWTVerifier(context.Background(), newTestJWTConfig())
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestJWTVerifyHMAC(t *testing.T) {
+	v := newTestJWTVerifier(t)
+	exp := time.Now().Add(1 * time.Hour).Unix()
+	claims, err := v.verify(context.Background(), newTestJWT(map[string]interface{}{
+		"exp":           exp,
+		"nbf":           time.Now().Add(-1 * time.Hour).Unix(),
+		"namespaces":    []interface{}{"ns1", "ns2", 12345},
+		"subscriptions": "sub1 ns2:sub2",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, exp, claims.expiry.Unix())
+	assert.Equal(t, []string{"ns1", "ns2"}, claims.namespaces)
+	assert.Equal(t, []string{"sub1", "ns2:sub2"}, claims.subscriptions)
+	assert.False(t, claims.allSubscriptions)
+}
+
+func TestJWTVerifyRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+
+	conf := newTestJWTConfig()
+	conf.Set(JWTPublicKeyFile, writeTestKeyFile(t, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	conf.Set(JWTIssuer, "issuer1")
+	conf.Set(JWTAudience, "firefly")
+	v, err := newJWTVerifier(context.Background(), conf)
+	assert.NoError(t, err)
+
+	signed := encodeTestJWTParts("RS256", map[string]interface{}{
+		"iss":        "issuer1",
+		"aud":        []interface{}{"other", "firefly"},
+		"namespaces": "*",
+	})
+	hash := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	assert.NoError(t, err)
+	claims, err := v.verify(context.Background(), signed+"."+base64.RawURLEncoding.EncodeToString(sig))
+	assert.NoError(t, err)
+	assert.True(t, claims.expiry.IsZero())
+	assert.True(t, claims.allSubscriptions)
+
+	// The secret is not configured, so HMAC tokens are not accepted
+	_, err = v.verify(context.Background(), newTestJWT(map[string]interface{}{}))
+	assert.Regexp(t, "FF10561.*HS256", err)
+
+	_, err = v.verify(context.Background(), signed+".bad")
+	assert.Regexp(t, "FF10561.*invalid signature", err)
+}
+
+func TestJWTVerifyBadPublicKeyFile(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ecDER, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	assert.NoError(t, err)
+
+	for _, keyFile := range []string{
+		filepath.Join(t.TempDir(), "missing.pem"),
+		writeTestKeyFile(t, []byte("not pem")),
+		writeTestKeyFile(t, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("bad")})),
+		writeTestKeyFile(t, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ecDER})),
+	} {
+		conf := newTestJWTConfig()
+		conf.Set(JWTPublicKeyFile, keyFile)
+		_, err := newJWTVerifier(context.Background(), conf)
+		assert.Regexp(t, "FF10565", err)
+	}
+}
+
+func TestJWTVerifyInvalid(t *testing.T) {
+	v := newTestJWTVerifier(t)
+	v.issuer = "issuer1"
+	v.audience = "firefly"
+	valid := map[string]interface{}{"iss": "issuer1", "aud": "firefly"}
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	for token, errRegex := range map[string]string{
+		"not.a.jwt.token":                                                                "malformed token",
+		"!." + b64([]byte("{}")) + ".sig":                                                "malformed header",
+		b64([]byte(`{"alg":"HS256"}`)) + ".!.":                                           "malformed claims",
+		encodeTestJWTParts("HS256", valid) + ".!":                                        "malformed signature",
+		encodeTestJWTParts("none", valid) + ".":                                          "unsupported algorithm 'none'",
+		encodeTestJWTParts("HS256", valid) + "." + b64([]byte("wrong")):                  "invalid signature",
+		newTestJWT(map[string]interface{}{"iss": "issuer1", "aud": "firefly", "exp": 1}): "token expired",
+		newTestJWT(map[string]interface{}{"iss": "issuer1", "aud": "firefly", "nbf": time.Now().Add(1 * time.Hour).Unix()}): "token not yet valid",
+		newTestJWT(map[string]interface{}{"iss": "issuer2", "aud": "firefly"}):                                              "issuer mismatch",
+		newTestJWT(map[string]interface{}{"iss": "issuer1", "aud": "other"}):                                                "audience mismatch",
+	} {
+		_, err := v.verify(context.Background(), token)
+		assert.Regexp(t, "FF10561.*"+errRegex, err)
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	req := httptest.NewRequest("GET", "/ws?access_token=token1", nil)
+	assert.Equal(t, "token1", bearerToken(req))
+	req.Header.Set("Authorization", "Bearer token2")
+	assert.Equal(t, "token2", bearerToken(req))
+	req.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	assert.Equal(t, "token1", bearerToken(req))
+}
+
+func TestTokenClaimsPermits(t *testing.T) {
+	restricted := &tokenClaims{namespaces: []string{"ns1"}, subscriptions: []string{"sub1", "ns1:sub2"}}
+	assert.True(t, restricted.permits(&core.WSStart{Namespace: "ns1", Name: "sub1"}))
+	assert.True(t, restricted.permits(&core.WSStart{Namespace: "ns1", Name: "sub2"}))
+	assert.False(t, restricted.permits(&core.WSStart{Namespace: "ns1", Name: "sub3"}))
+	assert.False(t, restricted.permits(&core.WSStart{Namespace: "ns2", Name: "sub1"}))
+	assert.False(t, restricted.permits(&core.WSStart{Namespace: "ns1", Ephemeral: true}))
+
+	unrestricted := &tokenClaims{namespaces: []string{"*"}, allSubscriptions: true}
+	assert.True(t, unrestricted.permits(&core.WSStart{Namespace: "ns2", Ephemeral: true}))
+
+	none := &tokenClaims{allSubscriptions: true}
+	assert.False(t, none.permits(&core.WSStart{Namespace: "ns1", Name: "sub1"}))
+}
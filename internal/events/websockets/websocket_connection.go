@@ -47,6 +47,7 @@ type websocketConnection struct {
 	senderDone   chan struct{}
 	receiverDone chan struct{}
 	autoAck      bool
+	cbor         bool
 	started      []*websocketStartedSub
 	inflight     []*core.EventDeliveryResponse
 	mux          sync.Mutex
@@ -55,9 +56,12 @@ type websocketConnection struct {
 	userAgent    string
 	header       http.Header
 	auth         core.Authorizer
+	token        *tokenClaims
+	tokenTimer   *time.Timer
+	tokenExpiry  chan struct{}
 }
 
-func newConnection(pCtx context.Context, ws *WebSockets, wsConn *websocket.Conn, req *http.Request, auth core.Authorizer) *websocketConnection {
+func newConnection(pCtx context.Context, ws *WebSockets, wsConn *websocket.Conn, req *http.Request, auth core.Authorizer, token *tokenClaims) *websocketConnection {
 	connID := fftypes.NewUUID().String()
 	ctx := log.WithLogField(pCtx, "websocket", connID)
 	ctx, cancelCtx := context.WithCancel(ctx)
@@ -70,11 +74,14 @@ func newConnection(pCtx context.Context, ws *WebSockets, wsConn *websocket.Conn,
 		sendMessages: make(chan interface{}),
 		senderDone:   make(chan struct{}),
 		receiverDone: make(chan struct{}),
+		tokenExpiry:  make(chan struct{}, 1),
 		remoteAddr:   req.RemoteAddr,
 		userAgent:    req.UserAgent(),
 		header:       req.Header,
 		auth:         auth,
+		cbor:         req.URL.Query().Get("encoding") == encodingCBOR,
 	}
+	wc.setToken(token)
 	go wc.sendLoop()
 	go wc.receiveLoop()
 	return wc
@@ -111,15 +118,18 @@ func (wc *websocketConnection) sendLoop() {
 		select {
 		case msg := <-wc.sendMessages:
 			l.Tracef("Sending: %+v", msg)
-			writer, err := wc.wsConn.NextWriter(websocket.TextMessage)
-			if err == nil {
-				err = json.NewEncoder(writer).Encode(msg)
-				_ = writer.Close()
-			}
-			if err != nil {
+			if err := wc.writeMessage(msg); err != nil {
 				l.Errorf("Write failed on socket: %s", err)
 				return
 			}
+		case <-wc.tokenExpiry:
+			err := i18n.NewError(wc.ctx, coremsgs.MsgWSTokenExpired)
+			l.Errorf("Sender closing - %s", err)
+			_ = wc.writeMessage(&core.WSError{
+				Type:  core.WSProtocolErrorEventType,
+				Error: err.Error(),
+			})
+			return
 		case <-wc.receiverDone:
 			l.Debugf("Sender closing - receiver completed")
 			return
@@ -136,11 +146,16 @@ func (wc *websocketConnection) receiveLoop() {
 	for {
 		var msgData []byte
 		var msgHeader core.WSActionBase
-		_, reader, err := wc.wsConn.NextReader()
+		msgType, reader, err := wc.wsConn.NextReader()
 		if err == nil {
 			msgData, err = io.ReadAll(reader)
 			if err == nil {
-				err = json.Unmarshal(msgData, &msgHeader)
+				if msgType == websocket.BinaryMessage {
+					msgData, err = cborToJSON(msgData)
+				}
+				if err == nil {
+					err = json.Unmarshal(msgData, &msgHeader)
+				}
 				if err != nil {
 					// We can notify the client on this one, before we bail
 					wc.protocolError(i18n.WrapError(wc.ctx, err, coremsgs.MsgWSClientSentInvalidData))
@@ -171,6 +186,12 @@ func (wc *websocketConnection) receiveLoop() {
 				// have previously checked authorization in the start message
 				err = wc.handleAck(&msg)
 			}
+		case core.WSClientActionToken:
+			var msg core.WSToken
+			err = json.Unmarshal(msgData, &msg)
+			if err == nil {
+				err = wc.handleToken(&msg)
+			}
 		default:
 			err = i18n.NewError(wc.ctx, coremsgs.MsgWSClientUnknownAction, msgHeader.Type)
 		}
@@ -182,7 +203,23 @@ func (wc *websocketConnection) receiveLoop() {
 	}
 }
 
-func (wc *websocketConnection) dispatch(event *core.EventDelivery) error {
+func (wc *websocketConnection) writeMessage(msg interface{}) error {
+	if wc.cbor {
+		data, err := jsonToCBOR(msg)
+		if err != nil {
+			return err
+		}
+		return wc.wsConn.WriteMessage(websocket.BinaryMessage, data)
+	}
+	writer, err := wc.wsConn.NextWriter(websocket.TextMessage)
+	if err == nil {
+		err = json.NewEncoder(writer).Encode(msg)
+		_ = writer.Close()
+	}
+	return err
+}
+
+func (wc *websocketConnection) dispatch(event *core.EventDelivery, payload interface{}) error {
 	inflight := &core.EventDeliveryResponse{
 		ID:           event.ID,
 		Subscription: event.Subscription,
@@ -196,7 +233,7 @@ func (wc *websocketConnection) dispatch(event *core.EventDelivery) error {
 	}
 	wc.mux.Unlock()
 
-	err := wc.send(event)
+	err := wc.send(payload)
 	if err != nil {
 		return err
 	}
@@ -252,6 +289,10 @@ func (wc *websocketConnection) restartForNamespace(ns string, startTime time.Tim
 
 func (wc *websocketConnection) handleStart(start *core.WSStart) (err error) {
 	wc.mux.Lock()
+	if wc.token != nil && !wc.token.permits(start) {
+		wc.mux.Unlock()
+		return i18n.NewError(wc.ctx, coremsgs.MsgWSTokenNotPermitted, start.Namespace, start.Name)
+	}
 	if start.AutoAck != nil {
 		if *start.AutoAck != wc.autoAck && len(wc.started) > 0 {
 			wc.mux.Unlock()
@@ -267,6 +308,47 @@ func (wc *websocketConnection) handleStart(start *core.WSStart) (err error) {
 	return wc.ws.start(wc, start)
 }
 
+// setToken replaces the permissions of the connection, and schedules closing the connection
+// when the token expires unless it is refreshed first
+func (wc *websocketConnection) setToken(token *tokenClaims) {
+	wc.mux.Lock()
+	defer wc.mux.Unlock()
+	wc.token = token
+	if wc.tokenTimer != nil {
+		wc.tokenTimer.Stop()
+	}
+	if token != nil && !token.expiry.IsZero() {
+		wc.tokenTimer = time.AfterFunc(time.Until(token.expiry), func() {
+			// The sender loop sends the error and closes the connection
+			select {
+			case wc.tokenExpiry <- struct{}{}:
+			default:
+			}
+		})
+	}
+}
+
+func (wc *websocketConnection) handleToken(msg *core.WSToken) error {
+	if wc.ws.jwt == nil {
+		return i18n.NewError(wc.ctx, coremsgs.MsgWSTokenAuthNotEnabled)
+	}
+	token, err := wc.ws.jwt.verify(wc.ctx, msg.Token)
+	if err != nil {
+		return err
+	}
+	// The new token must still permit all the subscriptions already started
+	wc.mux.Lock()
+	for _, s := range wc.started {
+		if !token.permits(&s.WSStart) {
+			wc.mux.Unlock()
+			return i18n.NewError(wc.ctx, coremsgs.MsgWSTokenNotPermitted, s.Namespace, s.Name)
+		}
+	}
+	wc.mux.Unlock()
+	wc.setToken(token)
+	return nil
+}
+
 func (wc *websocketConnection) durableSubMatcher(sr core.SubscriptionRef) bool {
 	wc.mux.Lock()
 	defer wc.mux.Unlock()
@@ -349,6 +431,9 @@ func (wc *websocketConnection) close() {
 	if !wc.closed {
 		didClosed = true
 		wc.closed = true
+		if wc.tokenTimer != nil {
+			wc.tokenTimer.Stop()
+		}
 		if wc.wsConn != nil {
 			_ = wc.wsConn.Close()
 		}
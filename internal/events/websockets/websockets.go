@@ -17,6 +17,7 @@
 package websockets
 
 import (
+	"compress/flate"
 	"context"
 	"net/http"
 	"sync"
@@ -38,6 +39,8 @@ type WebSockets struct {
 	connections  map[string]*websocketConnection
 	connMux      sync.Mutex
 	upgrader     websocket.Upgrader
+	compression  int
+	jwt          *jwtVerifier
 	auth         core.Authorizer
 }
 
@@ -49,21 +52,32 @@ type callbacks struct {
 func (ws *WebSockets) Name() string { return "websockets" }
 
 func (ws *WebSockets) Init(ctx context.Context, config config.Section) error {
+	compressionLevel := config.GetInt(CompressionLevel)
+	if compressionLevel < flate.HuffmanOnly || compressionLevel > flate.BestCompression {
+		return i18n.NewError(ctx, coremsgs.MsgWSInvalidCompressionLevel, compressionLevel)
+	}
+	jwt, err := newJWTVerifier(ctx, config.SubSection("jwt"))
+	if err != nil {
+		return err
+	}
 	*ws = WebSockets{
 		ctx:          ctx,
 		connections:  make(map[string]*websocketConnection),
-		capabilities: &events.Capabilities{},
+		capabilities: &events.Capabilities{CloudEvents: true, ConsumerGroups: true},
 		callbacks: callbacks{
 			handlers: make(map[string]events.Callbacks),
 		},
 		upgrader: websocket.Upgrader{
-			ReadBufferSize:  int(config.GetByteSize(ReadBufferSize)),
-			WriteBufferSize: int(config.GetByteSize(WriteBufferSize)),
+			ReadBufferSize:    int(config.GetByteSize(ReadBufferSize)),
+			WriteBufferSize:   int(config.GetByteSize(WriteBufferSize)),
+			EnableCompression: config.GetBool(Compression),
 			CheckOrigin: func(r *http.Request) bool {
 				// Cors is handled by the API server that wraps this handler
 				return true
 			},
 		},
+		compression: compressionLevel,
+		jwt:         jwt,
 	}
 	return nil
 }
@@ -97,6 +111,10 @@ func (ws *WebSockets) ValidateOptions(options *core.SubscriptionOptions) error {
 	return nil
 }
 
+func (ws *WebSockets) BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error {
+	return i18n.NewError(ws.ctx, coremsgs.MsgBatchDeliveryNotSupported, ws.Name())
+}
+
 func (ws *WebSockets) DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error {
 	ws.connMux.Lock()
 	conn, ok := ws.connections[connID]
@@ -104,18 +122,43 @@ func (ws *WebSockets) DeliveryRequest(connID string, sub *core.Subscription, eve
 	if !ok {
 		return i18n.NewError(ws.ctx, coremsgs.MsgWSConnectionNotActive, connID)
 	}
-	return conn.dispatch(event)
+	var payload interface{} = event
+	if sub != nil && sub.Options.Format == core.SubOptsFormatCloudEvents {
+		payload = core.NewCloudEvent(event, event)
+	}
+	return conn.dispatch(event, payload)
 }
 
 func (ws *WebSockets) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	switch encoding := req.URL.Query().Get("encoding"); encoding {
+	case "", encodingJSON, encodingCBOR:
+	default:
+		err := i18n.NewError(ws.ctx, coremsgs.MsgWSInvalidEncoding, encoding)
+		log.L(ws.ctx).Errorf("WebSocket upgrade failed: %s", err)
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var claims *tokenClaims
+	if ws.jwt != nil {
+		var err error
+		if claims, err = ws.jwt.verify(ws.ctx, bearerToken(req)); err != nil {
+			log.L(ws.ctx).Errorf("WebSocket upgrade failed: %s", err)
+			http.Error(res, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
+
 	wsConn, err := ws.upgrader.Upgrade(res, req, nil)
 	if err != nil {
 		log.L(ws.ctx).Errorf("WebSocket upgrade failed: %s", err)
 		return
 	}
+	// Only applies if compression was negotiated with the client
+	_ = wsConn.SetCompressionLevel(ws.compression)
 
 	ws.connMux.Lock()
-	wc := newConnection(ws.ctx, ws, wsConn, req, ws.auth)
+	wc := newConnection(ws.ctx, ws, wsConn, req, ws.auth, claims)
 	ws.connections[wc.connID] = wc
 	ws.connMux.Unlock()
 
@@ -27,6 +27,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -39,6 +40,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/events"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/ugorji/go/codec"
 )
 
 type testAuthorizer struct{}
@@ -296,6 +298,76 @@ func TestStartReceiveDurable(t *testing.T) {
 	cbs.AssertExpectations(t)
 }
 
+func TestStartReceiveCloudEvents(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	ws, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+	var connID string
+	sub := cbs.On("RegisterConnection",
+		mock.MatchedBy(func(s string) bool { connID = s; return true }),
+		mock.Anything,
+	).Return(nil)
+	ack := cbs.On("DeliveryResponse",
+		mock.MatchedBy(func(s string) bool { return s == connID }),
+		mock.Anything).Return(nil)
+
+	waitSubscribed := make(chan struct{})
+	sub.RunFn = func(a mock.Arguments) {
+		close(waitSubscribed)
+	}
+
+	waitAcked := make(chan struct{})
+	ack.RunFn = func(a mock.Arguments) {
+		close(waitAcked)
+	}
+
+	err := wsc.Send(context.Background(), []byte(`{"type":"start","namespace":"ns1","name":"sub1"}`))
+	assert.NoError(t, err)
+
+	<-waitSubscribed
+	subDef := &core.Subscription{
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Format: core.SubOptsFormatCloudEvents,
+			},
+		},
+	}
+	ws.DeliveryRequest(connID, subDef, &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{
+				ID:        fftypes.NewUUID(),
+				Type:      core.EventTypeMessageConfirmed,
+				Namespace: "ns1",
+			},
+		},
+		Subscription: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+	}, nil)
+
+	b := <-wsc.Receive()
+	var res struct {
+		core.CloudEvent
+		Data core.EventDelivery `json:"data"`
+	}
+	err = json.Unmarshal(b, &res)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1.0", res.SpecVersion)
+	assert.Equal(t, "io.hyperledger.firefly.message_confirmed", res.Type)
+	assert.Equal(t, "/namespaces/ns1", res.Source)
+	assert.Equal(t, res.ID, res.Data.ID.String())
+	assert.Equal(t, "sub1", res.Data.Subscription.Name)
+	err = wsc.Send(context.Background(), []byte(fmt.Sprintf(`{"type":"ack","id": "%s"}`, res.ID)))
+	assert.NoError(t, err)
+
+	<-waitAcked
+
+	cbs.AssertExpectations(t)
+}
+
 func TestStartReceiveDurableWithAuth(t *testing.T) {
 	cbs := &eventsmocks.Callbacks{}
 	ws, wsc, cancel := newTestWebsockets(t, cbs, &testAuthorizer{})
@@ -641,7 +713,7 @@ func TestConnectionDispatchAfterClose(t *testing.T) {
 	wsc := &websocketConnection{
 		ctx: ctx,
 	}
-	err := wsc.dispatch(&core.EventDelivery{})
+	err := wsc.dispatch(&core.EventDelivery{}, &core.EventDelivery{})
 	assert.Regexp(t, "FF00147", err)
 }
 
@@ -805,3 +877,314 @@ func TestNamespaceRestartedFailClose(t *testing.T) {
 
 	mcb.AssertExpectations(t)
 }
+
+func TestInitBadCompressionLevel(t *testing.T) {
+	coreconfig.Reset()
+	ws := &WebSockets{}
+	svrConfig := config.RootSection("ut.websockets")
+	ws.InitConfig(svrConfig)
+	svrConfig.Set(CompressionLevel, 10)
+	err := ws.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10560.*10", err)
+}
+
+func TestUpgradeBadEncoding(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	_, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+
+	u, _ := url.Parse(wsc.URL())
+	u.Scheme = "http"
+	u.RawQuery = "encoding=avro"
+	res, err := http.Get(u.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 400, res.StatusCode)
+}
+
+func newTestCBORWebsockets(t *testing.T, cbs *eventsmocks.Callbacks, query string) (ws *WebSockets, conn *websocket.Conn, cancel func()) {
+	coreconfig.Reset()
+
+	ws = &WebSockets{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	svrConfig := config.RootSection("ut.websockets")
+	ws.InitConfig(svrConfig)
+	svrConfig.Set(Compression, true)
+	err := ws.Init(ctx, svrConfig)
+	assert.NoError(t, err)
+	ws.SetHandler("ns1", cbs)
+	cbs.On("ConnectionClosed", mock.Anything).Return(nil).Maybe()
+
+	svr := httptest.NewServer(ws)
+	dialer := &websocket.Dialer{EnableCompression: true}
+	conn, res, err := dialer.Dial(fmt.Sprintf("ws://%s?encoding=cbor&%s", svr.Listener.Addr(), query), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "permessage-deflate; server_no_context_takeover; client_no_context_takeover", res.Header.Get("Sec-Websocket-Extensions"))
+
+	return ws, conn, func() {
+		cancelCtx()
+		conn.Close()
+		ws.WaitClosed()
+		svr.Close()
+	}
+}
+
+func TestAutoStartReceiveAckCBOR(t *testing.T) {
+	var connID string
+	cbs := &eventsmocks.Callbacks{}
+	sub := cbs.On("EphemeralSubscription",
+		mock.MatchedBy(func(s string) bool { connID = s; return true }),
+		"ns1", mock.Anything, mock.Anything).Return(nil)
+	ack := cbs.On("DeliveryResponse",
+		mock.MatchedBy(func(s string) bool { return s == connID }),
+		mock.Anything).Return(nil)
+
+	waitSubscribed := make(chan struct{})
+	sub.RunFn = func(a mock.Arguments) {
+		close(waitSubscribed)
+	}
+
+	eventID := fftypes.NewUUID()
+	waitAcked := make(chan struct{})
+	ack.RunFn = func(a mock.Arguments) {
+		assert.Equal(t, *eventID, *a.Get(1).(*core.EventDeliveryResponse).ID)
+		close(waitAcked)
+	}
+
+	ws, conn, cancel := newTestCBORWebsockets(t, cbs, "ephemeral&namespace=ns1")
+	defer cancel()
+
+	<-waitSubscribed
+	err := ws.DeliveryRequest(connID, nil, &core.EventDelivery{
+		EnrichedEvent: core.EnrichedEvent{
+			Event: core.Event{ID: eventID, Sequence: 12345},
+		},
+		Subscription: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+		},
+	}, nil)
+	assert.NoError(t, err)
+
+	msgType, b, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, msgType)
+	var res map[string]interface{}
+	err = codec.NewDecoderBytes(b, cborHandle).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, eventID.String(), res["id"])
+	assert.Equal(t, uint64(12345), res["sequence"])
+
+	var ackMsg []byte
+	err = codec.NewEncoderBytes(&ackMsg, cborHandle).Encode(map[string]interface{}{
+		"type": "ack",
+		"id":   eventID.String(),
+	})
+	assert.NoError(t, err)
+	err = conn.WriteMessage(websocket.BinaryMessage, ackMsg)
+	assert.NoError(t, err)
+
+	<-waitAcked
+	cbs.AssertExpectations(t)
+}
+
+func TestSendBadCBOR(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	_, conn, cancel := newTestCBORWebsockets(t, cbs, "")
+	defer cancel()
+
+	err := conn.WriteMessage(websocket.BinaryMessage, []byte{0xff})
+	assert.NoError(t, err)
+	msgType, b, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, websocket.BinaryMessage, msgType)
+	var res map[string]interface{}
+	err = codec.NewDecoderBytes(b, cborHandle).Decode(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, string(core.WSProtocolErrorEventType), res["type"])
+	assert.Regexp(t, "FF10176", res["error"])
+}
+
+func TestSendLoopBadDataCBOR(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	subscribedConn := make(chan string, 1)
+	cbs.On("EphemeralSubscription",
+		mock.MatchedBy(func(s string) bool {
+			subscribedConn <- s
+			return true
+		}),
+		"ns1", mock.Anything, mock.Anything).Return(nil)
+
+	ws, _, cancel := newTestCBORWebsockets(t, cbs, "ephemeral&namespace=ns1")
+	defer cancel()
+
+	connID := <-subscribedConn
+	ws.connMux.Lock()
+	connection := ws.connections[connID]
+	ws.connMux.Unlock()
+	connection.sendMessages <- map[bool]bool{false: true} // no JSON representation
+
+	// Connection should close on its own with that bad data
+	<-connection.senderDone
+}
+
+func TestCBORConversion(t *testing.T) {
+	b, err := jsonToCBOR(map[string]interface{}{
+		"int":   int64(-1),
+		"float": 1.5,
+		"list":  []interface{}{uint64(1)},
+	})
+	assert.NoError(t, err)
+	j, err := cborToJSON(b)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"float":1.5,"int":-1,"list":[1]}`, string(j))
+
+	_, err = cborToJSON([]byte{0xf9, 0x7e, 0x00}) // NaN has no JSON representation
+	assert.Error(t, err)
+
+	_, err = jsonToCBOR(map[bool]bool{false: true}) // no JSON representation
+	assert.Error(t, err)
+}
+
+func newTestJWTWebsockets(t *testing.T, cbs *eventsmocks.Callbacks, token string, query string) (ws *WebSockets, conn *websocket.Conn, cancel func()) {
+	coreconfig.Reset()
+
+	ws = &WebSockets{}
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	svrConfig := config.RootSection("ut.websockets")
+	ws.InitConfig(svrConfig)
+	svrConfig.SubSection("jwt").Set(JWTSecret, testJWTSecret)
+	err := ws.Init(ctx, svrConfig)
+	assert.NoError(t, err)
+	ws.SetHandler("ns1", cbs)
+	cbs.On("ConnectionClosed", mock.Anything).Return(nil).Maybe()
+
+	svr := httptest.NewServer(ws)
+	conn, _, err = websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s?%s", svr.Listener.Addr(), query), http.Header{
+		"Authorization": []string{"Bearer " + token},
+	})
+	assert.NoError(t, err)
+
+	return ws, conn, func() {
+		cancelCtx()
+		conn.Close()
+		ws.WaitClosed()
+		svr.Close()
+	}
+}
+
+func readTestWSError(t *testing.T, conn *websocket.Conn) string {
+	var res core.WSError
+	err := conn.ReadJSON(&res)
+	assert.NoError(t, err)
+	assert.Equal(t, core.WSProtocolErrorEventType, res.Type)
+	return res.Error
+}
+
+func TestInitBadJWTConfig(t *testing.T) {
+	coreconfig.Reset()
+	ws := &WebSockets{}
+	svrConfig := config.RootSection("ut.websockets")
+	ws.InitConfig(svrConfig)
+	svrConfig.SubSection("jwt").Set(JWTPublicKeyFile, "!missing")
+	err := ws.Init(context.Background(), svrConfig)
+	assert.Regexp(t, "FF10565", err)
+}
+
+func TestUpgradeMissingToken(t *testing.T) {
+	coreconfig.Reset()
+	ws := &WebSockets{}
+	svrConfig := config.RootSection("ut.websockets")
+	ws.InitConfig(svrConfig)
+	svrConfig.SubSection("jwt").Set(JWTSecret, testJWTSecret)
+	err := ws.Init(context.Background(), svrConfig)
+	assert.NoError(t, err)
+
+	svr := httptest.NewServer(ws)
+	defer svr.Close()
+	_, res, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s", svr.Listener.Addr()), nil)
+	assert.Error(t, err)
+	assert.Equal(t, 401, res.StatusCode)
+}
+
+func TestStartPermittedByToken(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	subscribed := make(chan struct{})
+	cbs.On("RegisterConnection", mock.Anything, mock.Anything).Return(nil).Run(func(a mock.Arguments) {
+		close(subscribed)
+	})
+	token := newTestJWT(map[string]interface{}{
+		"namespaces":    "ns1",
+		"subscriptions": "sub1",
+		"exp":           time.Now().Add(1 * time.Hour).Unix(),
+	})
+	_, conn, cancel := newTestJWTWebsockets(t, cbs, token, "namespace=ns1&name=sub1")
+	defer cancel()
+	<-subscribed
+
+	// Refreshing with a token that still permits the started subscription is accepted
+	err := conn.WriteJSON(&core.WSToken{
+		WSActionBase: core.WSActionBase{Type: core.WSClientActionToken},
+		Token: newTestJWT(map[string]interface{}{
+			"namespaces": "ns1",
+			"exp":        time.Now().Add(1 * time.Hour).Unix(),
+		}),
+	})
+	assert.NoError(t, err)
+
+	// Then the connection is closed for a token that does not
+	err = conn.WriteJSON(&core.WSToken{
+		WSActionBase: core.WSActionBase{Type: core.WSClientActionToken},
+		Token:        newTestJWT(map[string]interface{}{"namespaces": "ns2"}),
+	})
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF10562.*ns1:sub1", readTestWSError(t, conn))
+}
+
+func TestStartNotPermittedByToken(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	token := newTestJWT(map[string]interface{}{
+		"namespaces":    "ns1",
+		"subscriptions": "sub1",
+	})
+	_, conn, cancel := newTestJWTWebsockets(t, cbs, token, "namespace=ns1&name=sub2")
+	defer cancel()
+	assert.Regexp(t, "FF10562.*ns1:sub2", readTestWSError(t, conn))
+	cbs.AssertExpectations(t)
+}
+
+func TestRefreshInvalidToken(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	_, conn, cancel := newTestJWTWebsockets(t, cbs, newTestJWT(map[string]interface{}{}), "")
+	defer cancel()
+
+	err := conn.WriteJSON(&core.WSToken{
+		WSActionBase: core.WSActionBase{Type: core.WSClientActionToken},
+		Token:        "bad",
+	})
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF10561", readTestWSError(t, conn))
+}
+
+func TestTokenExpiry(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	token := newTestJWT(map[string]interface{}{
+		"exp": time.Now().Add(1 * time.Second).Unix(),
+	})
+	_, conn, cancel := newTestJWTWebsockets(t, cbs, token, "")
+	defer cancel()
+	assert.Regexp(t, "FF10563", readTestWSError(t, conn))
+}
+
+func TestRefreshTokenNotEnabled(t *testing.T) {
+	cbs := &eventsmocks.Callbacks{}
+	_, wsc, cancel := newTestWebsockets(t, cbs, nil)
+	defer cancel()
+
+	err := wsc.Send(context.Background(), []byte(`{"type":"token","token":"abc"}`))
+	assert.NoError(t, err)
+	b := <-wsc.Receive()
+	var res core.WSError
+	err = json.Unmarshal(b, &res)
+	assert.NoError(t, err)
+	assert.Regexp(t, "FF10564", res.Error)
+}
@@ -0,0 +1,205 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type requestContextKey struct{}
+
+// requestContext is the state of a single query. Objects referenced by more than one result (such as the
+// pool of a list of transfers) are only fetched once per query
+type requestContext struct {
+	or         orchestrator.Orchestrator
+	apiBaseURL string
+	mux        sync.Mutex
+	cache      map[string]interface{}
+}
+
+// Execute runs a GraphQL query against the namespace of the orchestrator
+func Execute(ctx context.Context, or orchestrator.Orchestrator, apiBaseURL string, req *core.GraphQLRequest) *core.GraphQLResponse {
+	rc := &requestContext{
+		or:         or,
+		apiBaseURL: apiBaseURL,
+		cache:      make(map[string]interface{}),
+	}
+	result := gql.Do(gql.Params{
+		Schema:         schema,
+		RequestString:  req.Query,
+		VariableValues: req.Variables,
+		OperationName:  req.OperationName,
+		Context:        context.WithValue(ctx, requestContextKey{}, rc),
+	})
+
+	res := &core.GraphQLResponse{}
+	if result.Data != nil {
+		b, _ := json.Marshal(result.Data)
+		res.Data = fftypes.JSONAnyPtrBytes(b)
+	}
+	for _, e := range result.Errors {
+		gqlErr := &core.GraphQLError{
+			Message: e.Message,
+			Path:    e.Path,
+		}
+		for _, l := range e.Locations {
+			gqlErr.Locations = append(gqlErr.Locations, &core.GraphQLErrorLocation{Line: l.Line, Column: l.Column})
+		}
+		res.Errors = append(res.Errors, gqlErr)
+	}
+	return res
+}
+
+func getRequestContext(p gql.ResolveParams) *requestContext {
+	return p.Context.Value(requestContextKey{}).(*requestContext)
+}
+
+// lookup fetches an object by its ID, once per query
+func (rc *requestContext) lookup(kind, id string, fetch func() (interface{}, error)) (interface{}, error) {
+	if id == "" {
+		return nil, nil
+	}
+	key := kind + ":" + id
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+	if obj, ok := rc.cache[key]; ok {
+		return obj, nil
+	}
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	obj := toObject(v)
+	rc.cache[key] = obj
+	return obj, nil
+}
+
+// add caches an object fetched other than by its ID, so later lookups of the ID use it
+func (rc *requestContext) add(kind string, obj interface{}) interface{} {
+	if id := field(obj, "id"); id != "" {
+		rc.mux.Lock()
+		defer rc.mux.Unlock()
+		rc.cache[kind+":"+id] = obj
+	}
+	return obj
+}
+
+// toObject converts a FireFly object to its JSON representation, which the default resolvers
+// of the schema serve fields from
+func toObject(v interface{}) interface{} {
+	var obj map[string]interface{}
+	b, _ := json.Marshal(v)
+	_ = json.Unmarshal(b, &obj)
+	if obj == nil {
+		return nil
+	}
+	return obj
+}
+
+func toList(v interface{}) []interface{} {
+	list := []interface{}{}
+	b, _ := json.Marshal(v)
+	_ = json.Unmarshal(b, &list)
+	return list
+}
+
+// field returns a string field of an object, following the path through nested objects
+func field(source interface{}, path ...string) string {
+	for _, name := range path {
+		obj, ok := source.(map[string]interface{})
+		if !ok {
+			return ""
+		}
+		source = obj[name]
+	}
+	s, _ := source.(string)
+	return s
+}
+
+func filterCondition(fb ffapi.FilterBuilder, op, name string, value interface{}) ffapi.Filter {
+	switch op {
+	case "NEQ":
+		return fb.Neq(name, value)
+	case "GT":
+		return fb.Gt(name, value)
+	case "GTE":
+		return fb.Gte(name, value)
+	case "LT":
+		return fb.Lt(name, value)
+	case "LTE":
+		return fb.Lte(name, value)
+	case "CONTAINS":
+		return fb.Contains(name, value)
+	case "STARTSWITH":
+		return fb.StartsWith(name, value)
+	case "ENDSWITH":
+		return fb.EndsWith(name, value)
+	default:
+		return fb.Eq(name, value)
+	}
+}
+
+// buildFilter builds a database filter from the filter, sort and paging arguments of a list field,
+// along with any conditions implied by the parent of the field
+func buildFilter(p gql.ResolveParams, qf ffapi.QueryFactory, conditions ...ffapi.Filter) (ffapi.AndFilter, error) {
+	ctx := p.Context
+	fb := qf.NewFilter(ctx)
+	if list, ok := p.Args["filter"].([]interface{}); ok {
+		for _, c := range list {
+			condition := c.(map[string]interface{})
+			conditions = append(conditions, filterCondition(fb, condition["op"].(string), condition["field"].(string), condition["value"]))
+		}
+	}
+	filter := fb.And(conditions...)
+
+	if sort, ok := p.Args["sort"].([]interface{}); ok {
+		for _, s := range sort {
+			filter.Sort(s.(string))
+		}
+		if descending, _ := p.Args["descending"].(bool); descending {
+			filter.Descending()
+		}
+	}
+
+	limit := config.GetUint64(coreconfig.APIDefaultFilterLimit)
+	if l, ok := p.Args["limit"].(int); ok {
+		if l < 0 || uint64(l) > config.GetUint64(coreconfig.APIMaxFilterLimit) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgMaxFilterLimit, config.GetUint64(coreconfig.APIMaxFilterLimit))
+		}
+		limit = uint64(l)
+	}
+	filter.Limit(limit)
+	if s, ok := p.Args["skip"].(int); ok {
+		if s < 0 || uint64(s) > config.GetUint64(coreconfig.APIMaxFilterSkip) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgMaxFilterSkip, config.GetUint64(coreconfig.APIMaxFilterSkip))
+		}
+		filter.Skip(uint64(s))
+	}
+	return filter, nil
+}
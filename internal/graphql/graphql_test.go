@@ -0,0 +1,429 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/assetmocks"
+	"github.com/hyperledger/firefly/mocks/contractmocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testMocks struct {
+	or *orchestratormocks.Orchestrator
+	am *assetmocks.Manager
+	cm *contractmocks.Manager
+}
+
+func newTestMocks() *testMocks {
+	coreconfig.Reset()
+	m := &testMocks{
+		or: &orchestratormocks.Orchestrator{},
+		am: &assetmocks.Manager{},
+		cm: &contractmocks.Manager{},
+	}
+	m.or.On("Assets").Return(m.am).Maybe()
+	m.or.On("Contracts").Return(m.cm).Maybe()
+	return m
+}
+
+func (m *testMocks) assertExpectations(t *testing.T) {
+	m.or.AssertExpectations(t)
+	m.am.AssertExpectations(t)
+	m.cm.AssertExpectations(t)
+}
+
+func (m *testMocks) execute(query string, variables fftypes.JSONObject) *core.GraphQLResponse {
+	return Execute(context.Background(), m.or, "http://localhost:5000/api/v1/namespaces/ns1", &core.GraphQLRequest{
+		Query:     query,
+		Variables: variables,
+	})
+}
+
+func filterString(f ffapi.AndFilter) string {
+	fi, _ := f.Finalize()
+	return fi.String()
+}
+
+func TestMessageDataBlobTransactionEvents(t *testing.T) {
+	m := newTestMocks()
+
+	msgID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	blobHash := fftypes.NewRandB32()
+	m.or.On("GetMessageByID", mock.Anything, msgID.String()).Return(&core.Message{
+		Header: core.MessageHeader{
+			ID:     msgID,
+			Topics: fftypes.FFStringArray{"topic1"},
+			SignerRef: core.SignerRef{
+				Author: "did:firefly:org/org1",
+			},
+		},
+		TransactionID: txID,
+	}, nil)
+	m.or.On("GetMessageData", mock.Anything, msgID.String()).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"some":"value"}`)},
+		{ID: fftypes.NewUUID(), Blob: &core.BlobRef{Hash: blobHash, Size: 12345, Name: "file.txt"}},
+	}, nil)
+	m.or.On("GetTransactionByID", mock.Anything, txID.String()).Return(&core.Transaction{
+		ID:   txID,
+		Type: core.TransactionTypeBatchPin,
+	}, nil).Once()
+	m.or.On("GetMessageEvents", mock.Anything, msgID.String(), mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		return filterString(f) == "( type == 'message_confirmed' ) sort=-sequence skip=5 limit=10"
+	})).Return([]*core.Event{
+		{ID: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed, Reference: msgID, Sequence: 12345, Transaction: txID},
+	}, nil, nil)
+
+	res := m.execute(`query($id: String!) {
+		message(id: $id) {
+			header { id author topics }
+			data { value blob { hash size name } }
+			transaction { id type }
+			events(filter: [{field: "type", value: "message_confirmed"}], sort: ["sequence"], descending: true, limit: 10, skip: 5) {
+				sequence
+				type
+				transaction { id }
+			}
+		}
+	}`, fftypes.JSONObject{"id": msgID.String()})
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, fmt.Sprintf(`{
+		"message": {
+			"header": {"id": "%[1]s", "author": "did:firefly:org/org1", "topics": ["topic1"]},
+			"data": [
+				{"value": {"some": "value"}, "blob": null},
+				{"value": null, "blob": {"hash": "%[2]s", "size": 12345, "name": "file.txt"}}
+			],
+			"transaction": {"id": "%[3]s", "type": "batch_pin"},
+			"events": [
+				{"sequence": 12345, "type": "message_confirmed", "transaction": {"id": "%[3]s"}}
+			]
+		}
+	}`, msgID, blobHash, txID), res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestTokenTransfersPoolContract(t *testing.T) {
+	m := newTestMocks()
+
+	poolID := fftypes.NewUUID()
+	ffiID := fftypes.NewUUID()
+	m.am.On("GetTokenTransfers", mock.Anything, mock.Anything).Return([]*core.TokenTransfer{
+		{LocalID: fftypes.NewUUID(), Pool: poolID, Amount: *fftypes.NewFFBigInt(10)},
+		{LocalID: fftypes.NewUUID(), Pool: poolID, Amount: *fftypes.NewFFBigInt(20)},
+	}, nil, nil)
+	m.am.On("GetTokenPoolByID", mock.Anything, poolID).Return(&core.TokenPool{
+		ID:        poolID,
+		Name:      "pool1",
+		Interface: &fftypes.FFIReference{ID: ffiID},
+	}, nil).Once()
+	m.cm.On("GetFFIByID", mock.Anything, ffiID).Return(&fftypes.FFI{
+		ID:   ffiID,
+		Name: "erc20",
+	}, nil).Once()
+
+	res := m.execute(`{
+		tokenTransfers {
+			amount
+			tokenPool { name contract { name } }
+		}
+	}`, nil)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, `{
+		"tokenTransfers": [
+			{"amount": "10", "tokenPool": {"name": "pool1", "contract": {"name": "erc20"}}},
+			{"amount": "20", "tokenPool": {"name": "pool1", "contract": {"name": "erc20"}}}
+		]
+	}`, res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestTokenPoolTransfersBalances(t *testing.T) {
+	m := newTestMocks()
+
+	poolID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	m.am.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(&core.TokenPool{
+		ID:       poolID,
+		Name:     "pool1",
+		Decimals: 18,
+		TX:       core.TransactionRef{Type: core.TransactionTypeTokenPool, ID: txID},
+	}, nil)
+	m.am.On("GetTokenTransfers", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		return filterString(f) == fmt.Sprintf("( pool == '%s' ) limit=25", poolID)
+	})).Return([]*core.TokenTransfer{
+		{Pool: poolID, Amount: *fftypes.NewFFBigInt(10)},
+	}, nil, nil)
+	m.am.On("GetTokenBalances", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		return filterString(f) == fmt.Sprintf("( pool == '%s' ) && ( key >= '0x1' ) limit=25", poolID)
+	})).Return([]*core.TokenBalance{
+		{Pool: poolID, Key: "0x12345", Balance: *fftypes.NewFFBigInt(10)},
+	}, nil, nil)
+	m.or.On("GetTransactionByID", mock.Anything, txID.String()).Return(&core.Transaction{ID: txID}, nil)
+
+	res := m.execute(`{
+		tokenPool(nameOrId: "pool1") {
+			decimals
+			transaction { id }
+			transfers { amount tokenPool { name } }
+			balances(filter: [{field: "key", op: GTE, value: "0x1"}]) { key balance }
+		}
+	}`, nil)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, fmt.Sprintf(`{
+		"tokenPool": {
+			"decimals": 18,
+			"transaction": {"id": "%s"},
+			"transfers": [{"amount": "10", "tokenPool": {"name": "pool1"}}],
+			"balances": [{"key": "0x12345", "balance": "10"}]
+		}
+	}`, txID), res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestEventReferences(t *testing.T) {
+	m := newTestMocks()
+
+	msgID := fftypes.NewUUID()
+	poolID := fftypes.NewUUID()
+	transferID := fftypes.NewUUID()
+	beID := fftypes.NewUUID()
+	m.or.On("GetEvents", mock.Anything, mock.Anything).Return([]*core.Event{
+		{Type: core.EventTypeMessageConfirmed, Reference: msgID},
+		{Type: core.EventTypePoolConfirmed, Reference: poolID},
+		{Type: core.EventTypeTransferConfirmed, Reference: transferID},
+		{Type: core.EventTypeBlockchainEventReceived, Reference: beID},
+		{Type: core.EventTypeTransferOpFailed, Reference: fftypes.NewUUID()},
+	}, nil, nil)
+	m.or.On("GetMessageByID", mock.Anything, msgID.String()).Return(&core.Message{Header: core.MessageHeader{Tag: "tag1"}}, nil)
+	m.am.On("GetTokenPoolByID", mock.Anything, poolID).Return(&core.TokenPool{Name: "pool1"}, nil)
+	m.am.On("GetTokenTransferByID", mock.Anything, transferID.String()).Return(&core.TokenTransfer{Amount: *fftypes.NewFFBigInt(1)}, nil)
+	m.or.On("GetBlockchainEventByID", mock.Anything, beID.String()).Return(&core.BlockchainEvent{Name: "Changed"}, nil)
+
+	res := m.execute(`{
+		events {
+			type
+			message { header { tag } }
+			tokenPool { name }
+			tokenTransfer { amount }
+			blockchainEvent { name }
+			transaction { id }
+		}
+	}`, nil)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, `{
+		"events": [
+			{"type": "message_confirmed", "message": {"header": {"tag": "tag1"}}, "tokenPool": null, "tokenTransfer": null, "blockchainEvent": null, "transaction": null},
+			{"type": "token_pool_confirmed", "message": null, "tokenPool": {"name": "pool1"}, "tokenTransfer": null, "blockchainEvent": null, "transaction": null},
+			{"type": "token_transfer_confirmed", "message": null, "tokenPool": null, "tokenTransfer": {"amount": "1"}, "blockchainEvent": null, "transaction": null},
+			{"type": "blockchain_event_received", "message": null, "tokenPool": null, "tokenTransfer": null, "blockchainEvent": {"name": "Changed"}, "transaction": null},
+			{"type": "token_transfer_op_failed", "message": null, "tokenPool": null, "tokenTransfer": null, "blockchainEvent": null, "transaction": null}
+		]
+	}`, res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestTransactionOperationsBlockchainEvents(t *testing.T) {
+	m := newTestMocks()
+
+	txID := fftypes.NewUUID()
+	m.or.On("GetTransactions", mock.Anything, mock.Anything).Return([]*core.Transaction{{ID: txID}}, nil, nil)
+	m.or.On("GetTransactionOperations", mock.Anything, txID.String()).Return([]*core.Operation{
+		{Type: core.OpTypeBlockchainPinBatch, Input: fftypes.JSONObject{"some": "input"}},
+	}, nil, nil)
+	m.or.On("GetTransactionBlockchainEvents", mock.Anything, txID.String()).Return([]*core.BlockchainEvent{
+		{Name: "BatchPin", TX: core.BlockchainTransactionRef{BlockchainID: "0x12345"}},
+	}, nil, nil)
+
+	res := m.execute(`{
+		transactions {
+			operations { type input }
+			blockchainEvents { name tx { blockchainId } }
+		}
+	}`, nil)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, `{
+		"transactions": [{
+			"operations": [{"type": "blockchain_pin_batch", "input": {"some": "input"}}],
+			"blockchainEvents": [{"name": "BatchPin", "tx": {"blockchainId": "0x12345"}}]
+		}]
+	}`, res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestRootQueries(t *testing.T) {
+	m := newTestMocks()
+
+	ffiID := fftypes.NewUUID()
+	m.or.On("GetMessages", mock.Anything, mock.Anything).Return([]*core.Message{}, nil, nil)
+	m.or.On("GetData", mock.Anything, mock.Anything).Return(core.DataArray{}, nil, nil)
+	m.or.On("GetDataByID", mock.Anything, "data1").Return(&core.Data{Validator: core.ValidatorTypeJSON}, nil)
+	m.or.On("GetEventByID", mock.Anything, "event1").Return(&core.Event{Type: core.EventTypeMessageConfirmed}, nil)
+	m.or.On("GetTransactionByID", mock.Anything, "tx1").Return(&core.Transaction{Type: core.TransactionTypeBatchPin}, nil)
+	m.or.On("GetBlockchainEventByID", mock.Anything, "be1").Return(&core.BlockchainEvent{Name: "Changed"}, nil)
+	m.am.On("GetTokenPools", mock.Anything, mock.Anything).Return([]*core.TokenPool{}, nil, nil)
+	m.am.On("GetTokenTransferByID", mock.Anything, "transfer1").Return(&core.TokenTransfer{Type: core.TokenTransferTypeMint}, nil)
+	m.am.On("GetTokenBalances", mock.Anything, mock.Anything).Return([]*core.TokenBalance{}, nil, nil)
+	m.cm.On("GetContractAPI", mock.Anything, "http://localhost:5000/api/v1/namespaces/ns1", "api1").Return(&core.ContractAPI{
+		Name:      "api1",
+		Interface: &fftypes.FFIReference{ID: ffiID},
+		URLs:      core.ContractURLs{OpenAPI: "http://localhost:5000/api/v1/namespaces/ns1/apis/api1/api/swagger.json"},
+	}, nil)
+	m.cm.On("GetContractAPIs", mock.Anything, "http://localhost:5000/api/v1/namespaces/ns1", mock.Anything).Return([]*core.ContractAPI{}, nil, nil)
+	m.cm.On("GetFFIByID", mock.Anything, ffiID).Return(nil, nil)
+
+	res := m.execute(`{
+		messages { hash }
+		data { id }
+		dataItem(id: "data1") { validator }
+		event(id: "event1") { type }
+		transaction(id: "tx1") { type }
+		blockchainEvent(id: "be1") { name }
+		tokenPools { name }
+		tokenTransfer(id: "transfer1") { type }
+		tokenBalances { balance }
+		contractAPI(name: "api1") { name urls { openapi } contract { name } }
+		contractAPIs { name }
+	}`, nil)
+	assert.Empty(t, res.Errors)
+	assert.JSONEq(t, `{
+		"messages": [],
+		"data": [],
+		"dataItem": {"validator": "json"},
+		"event": {"type": "message_confirmed"},
+		"transaction": {"type": "batch_pin"},
+		"blockchainEvent": {"name": "Changed"},
+		"tokenPools": [],
+		"tokenTransfer": {"type": "mint"},
+		"tokenBalances": [],
+		"contractAPI": {"name": "api1", "urls": {"openapi": "http://localhost:5000/api/v1/namespaces/ns1/apis/api1/api/swagger.json"}, "contract": null},
+		"contractAPIs": []
+	}`, res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestQueryInvalid(t *testing.T) {
+	m := newTestMocks()
+
+	res := m.execute(`{ messages { unknown } }`, nil)
+	assert.Nil(t, res.Data)
+	assert.Len(t, res.Errors, 1)
+	assert.Regexp(t, "unknown", res.Errors[0].Message)
+	assert.Equal(t, 1, res.Errors[0].Locations[0].Line)
+	assert.Equal(t, 14, res.Errors[0].Locations[0].Column)
+}
+
+func TestQueryLimits(t *testing.T) {
+	m := newTestMocks()
+
+	res := m.execute(`{ messages(limit: 1001) { hash } }`, nil)
+	assert.Regexp(t, "FF10184", res.Errors[0].Message)
+	assert.Equal(t, []interface{}{"messages"}, res.Errors[0].Path)
+
+	res = m.execute(`{ messages(skip: 100000) { hash } }`, nil)
+	assert.Regexp(t, "FF10183", res.Errors[0].Message)
+}
+
+func TestQueryResolveFail(t *testing.T) {
+	m := newTestMocks()
+
+	msgID := fftypes.NewUUID()
+	m.or.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	m.or.On("GetMessageByID", mock.Anything, msgID.String()).Return(&core.Message{Header: core.MessageHeader{ID: msgID}, TransactionID: fftypes.NewUUID()}, nil)
+	m.or.On("GetTransactionByID", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+	m.or.On("GetMessageData", mock.Anything, msgID.String()).Return(nil, fmt.Errorf("pop"))
+	m.am.On("GetTokenTransfers", mock.Anything, mock.Anything).Return([]*core.TokenTransfer{{}}, nil, nil)
+
+	res := m.execute(`query($id: String!) {
+		messages { hash }
+		message(id: $id) { header { id } transaction { id } data { id } }
+		tokenTransfers { tokenPool { id } }
+	}`, fftypes.JSONObject{"id": msgID.String()})
+	assert.Len(t, res.Errors, 3)
+	assert.Equal(t, fmt.Sprintf(`{"message":{"data":null,"header":{"id":"%s"},"transaction":null},"messages":null,"tokenTransfers":[{"tokenPool":null}]}`, msgID), res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestLookupBadUUID(t *testing.T) {
+	m := newTestMocks()
+
+	rc := &requestContext{or: m.or, cache: make(map[string]interface{})}
+	p := gql.ResolveParams{Context: context.WithValue(context.Background(), requestContextKey{}, rc)}
+	_, err := lookupTokenPool(p, "!uuid")
+	assert.Regexp(t, "FF00138", err)
+	_, err = lookupContractInterface(p, "!uuid")
+	assert.Regexp(t, "FF00138", err)
+
+	m.assertExpectations(t)
+}
+
+func TestTokenPoolNotFound(t *testing.T) {
+	m := newTestMocks()
+
+	m.am.On("GetTokenPoolByNameOrID", mock.Anything, "pool1").Return(nil, fmt.Errorf("pop"))
+
+	res := m.execute(`{ tokenPool(nameOrId: "pool1") { name } }`, nil)
+	assert.Regexp(t, "pop", res.Errors[0].Message)
+	assert.Equal(t, `{"tokenPool":null}`, res.Data.String())
+
+	m.assertExpectations(t)
+}
+
+func TestFilterOps(t *testing.T) {
+	m := newTestMocks()
+
+	m.or.On("GetMessages", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		return filterString(f) == "( tag == 'a' ) && ( tag != 'b' ) && ( sequence >> 1 ) && ( sequence >= 2 ) && ( sequence << 3 ) && ( sequence <= 4 ) && ( tag %= 'c' ) && ( tag ^= 'd' ) && ( tag $= 'e' ) limit=25"
+	})).Return([]*core.Message{}, nil, nil)
+
+	res := m.execute(`{
+		messages(filter: [
+			{field: "tag", value: "a"},
+			{field: "tag", op: NEQ, value: "b"},
+			{field: "sequence", op: GT, value: "1"},
+			{field: "sequence", op: GTE, value: "2"},
+			{field: "sequence", op: LT, value: "3"},
+			{field: "sequence", op: LTE, value: "4"},
+			{field: "tag", op: CONTAINS, value: "c"},
+			{field: "tag", op: STARTSWITH, value: "d"},
+			{field: "tag", op: ENDSWITH, value: "e"}
+		]) { hash }
+	}`, nil)
+	assert.Empty(t, res.Errors)
+
+	assert.Empty(t, field("not an object", "id"))
+
+	m.assertExpectations(t)
+}
@@ -0,0 +1,495 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package graphql
+
+import (
+	gql "github.com/graphql-go/graphql"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// schema is the GraphQL schema of the FireFly data model. Objects are served from their JSON
+// representation, so the field names match those of the REST API
+var schema = newSchema()
+
+var jsonScalar = gql.NewScalar(gql.ScalarConfig{
+	Name:        "JSON",
+	Description: "An arbitrary JSON value",
+	Serialize:   func(v interface{}) interface{} { return v },
+})
+
+var filterOpEnum = gql.NewEnum(gql.EnumConfig{
+	Name: "FilterOp",
+	Values: gql.EnumValueConfigMap{
+		"EQ":         {Value: "EQ"},
+		"NEQ":        {Value: "NEQ"},
+		"GT":         {Value: "GT"},
+		"GTE":        {Value: "GTE"},
+		"LT":         {Value: "LT"},
+		"LTE":        {Value: "LTE"},
+		"CONTAINS":   {Value: "CONTAINS"},
+		"STARTSWITH": {Value: "STARTSWITH"},
+		"ENDSWITH":   {Value: "ENDSWITH"},
+	},
+})
+
+var filterConditionInput = gql.NewInputObject(gql.InputObjectConfig{
+	Name: "FilterCondition",
+	Fields: gql.InputObjectConfigFieldMap{
+		"field": {Type: gql.NewNonNull(gql.String)},
+		"op":    {Type: filterOpEnum, DefaultValue: "EQ"},
+		"value": {Type: gql.NewNonNull(gql.String)},
+	},
+})
+
+// listArgs are the arguments of every list field, with the same semantics as the query parameters of the REST API
+func listArgs() gql.FieldConfigArgument {
+	return gql.FieldConfigArgument{
+		"filter":     {Type: gql.NewList(gql.NewNonNull(filterConditionInput))},
+		"sort":       {Type: gql.NewList(gql.NewNonNull(gql.String))},
+		"descending": {Type: gql.Boolean},
+		"limit":      {Type: gql.Int},
+		"skip":       {Type: gql.Int},
+	}
+}
+
+func idArgs(name string) gql.FieldConfigArgument {
+	return gql.FieldConfigArgument{
+		name: {Type: gql.NewNonNull(gql.String)},
+	}
+}
+
+// scalars declares fields that are served directly from the JSON of the object
+func scalars(t gql.Output, names ...string) gql.Fields {
+	fields := make(gql.Fields, len(names))
+	for _, name := range names {
+		fields[name] = &gql.Field{Type: t}
+	}
+	return fields
+}
+
+func merge(fieldSets ...gql.Fields) gql.Fields {
+	merged := gql.Fields{}
+	for _, fields := range fieldSets {
+		for name, f := range fields {
+			merged[name] = f
+		}
+	}
+	return merged
+}
+
+func object(name string, fields func() gql.Fields) *gql.Object {
+	return gql.NewObject(gql.ObjectConfig{
+		Name:   name,
+		Fields: gql.FieldsThunk(fields),
+	})
+}
+
+func list(t gql.Type) gql.Output {
+	return gql.NewList(gql.NewNonNull(t))
+}
+
+// Lookups of related objects, cached for the duration of the query
+
+func lookupMessage(p gql.ResolveParams, id string) (interface{}, error) {
+	rc := getRequestContext(p)
+	return rc.lookup("message", id, func() (interface{}, error) { return rc.or.GetMessageByID(p.Context, id) })
+}
+
+func lookupTransaction(p gql.ResolveParams, id string) (interface{}, error) {
+	rc := getRequestContext(p)
+	return rc.lookup("transaction", id, func() (interface{}, error) { return rc.or.GetTransactionByID(p.Context, id) })
+}
+
+func lookupBlockchainEvent(p gql.ResolveParams, id string) (interface{}, error) {
+	rc := getRequestContext(p)
+	return rc.lookup("blockchainEvent", id, func() (interface{}, error) { return rc.or.GetBlockchainEventByID(p.Context, id) })
+}
+
+func lookupTokenTransfer(p gql.ResolveParams, id string) (interface{}, error) {
+	rc := getRequestContext(p)
+	return rc.lookup("tokenTransfer", id, func() (interface{}, error) { return rc.or.Assets().GetTokenTransferByID(p.Context, id) })
+}
+
+func lookupTokenPool(p gql.ResolveParams, id string) (interface{}, error) {
+	rc := getRequestContext(p)
+	return rc.lookup("tokenPool", id, func() (interface{}, error) {
+		poolID, err := fftypes.ParseUUID(p.Context, id)
+		if err != nil {
+			return nil, err
+		}
+		return rc.or.Assets().GetTokenPoolByID(p.Context, poolID)
+	})
+}
+
+func lookupContractInterface(p gql.ResolveParams, id string) (interface{}, error) {
+	rc := getRequestContext(p)
+	return rc.lookup("contractInterface", id, func() (interface{}, error) {
+		ffiID, err := fftypes.ParseUUID(p.Context, id)
+		if err != nil {
+			return nil, err
+		}
+		return rc.or.Contracts().GetFFIByID(p.Context, ffiID)
+	})
+}
+
+// related resolves a field to the object with the ID found at the path in the source
+func related(lookup func(p gql.ResolveParams, id string) (interface{}, error), path ...string) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		return lookup(p, field(p.Source, path...))
+	}
+}
+
+// eventReference resolves a field of an event to the object it references, for the given event types
+func eventReference(lookup func(p gql.ResolveParams, id string) (interface{}, error), eventTypes ...core.EventType) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		eventType := field(p.Source, "type")
+		for _, t := range eventTypes {
+			if eventType == t.String() {
+				return lookup(p, field(p.Source, "reference"))
+			}
+		}
+		return nil, nil
+	}
+}
+
+// listField declares a list field with the standard list arguments, along with any conditions implied by the source
+func listField(t gql.Type, qf ffapi.QueryFactory, conditions func(p gql.ResolveParams, fb ffapi.FilterBuilder) []ffapi.Filter, get func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error)) *gql.Field {
+	return &gql.Field{
+		Type: list(t),
+		Args: listArgs(),
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			var parentConditions []ffapi.Filter
+			if conditions != nil {
+				parentConditions = conditions(p, qf.NewFilter(p.Context))
+			}
+			filter, err := buildFilter(p, qf, parentConditions...)
+			if err != nil {
+				return nil, err
+			}
+			v, err := get(p, filter)
+			if err != nil {
+				return nil, err
+			}
+			return toList(v), nil
+		},
+	}
+}
+
+func byPool(p gql.ResolveParams, fb ffapi.FilterBuilder) []ffapi.Filter {
+	return []ffapi.Filter{fb.Eq("pool", field(p.Source, "id"))}
+}
+
+var (
+	transactionRefType           *gql.Object
+	blockchainTransactionRefType *gql.Object
+	datatypeRefType              *gql.Object
+	blobType                     *gql.Object
+	dataType                     *gql.Object
+	messageHeaderType            *gql.Object
+	messageType                  *gql.Object
+	eventType                    *gql.Object
+	operationType                *gql.Object
+	blockchainEventType          *gql.Object
+	transactionType              *gql.Object
+	ffiReferenceType             *gql.Object
+	contractInterfaceType        *gql.Object
+	tokenPoolType                *gql.Object
+	tokenTransferType            *gql.Object
+	tokenBalanceType             *gql.Object
+	contractURLsType             *gql.Object
+	contractAPIType              *gql.Object
+)
+
+// initTypes creates the object types, which refer to each other through the thunks of their fields
+func initTypes() {
+	transactionRefType = object("TransactionRef", func() gql.Fields {
+		return scalars(gql.String, "type", "id")
+	})
+
+	blockchainTransactionRefType = object("BlockchainTransactionRef", func() gql.Fields {
+		return scalars(gql.String, "type", "id", "blockchainId")
+	})
+
+	datatypeRefType = object("DatatypeRef", func() gql.Fields {
+		return scalars(gql.String, "name", "version")
+	})
+
+	blobType = object("Blob", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "hash", "name", "path"),
+			scalars(gql.Float, "size"),
+			scalars(gql.Boolean, "public"),
+		)
+	})
+
+	dataType = object("Data", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "validator", "namespace", "hash", "created", "public"),
+			scalars(jsonScalar, "value"),
+			gql.Fields{
+				"datatype": {Type: datatypeRefType},
+				"blob":     {Type: blobType},
+			},
+		)
+	})
+
+	messageHeaderType = object("MessageHeader", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "cid", "type", "txtype", "author", "key", "created", "namespace", "group", "tag", "datahash"),
+			scalars(gql.NewList(gql.String), "topics"),
+			gql.Fields{
+				"txparent": {Type: transactionRefType},
+			},
+		)
+	})
+
+	messageType = object("Message", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "localNamespace", "hash", "batch", "txid", "state", "confirmed", "rejectReason", "idempotencyKey", "sendTime", "priority", "expires"),
+			scalars(gql.NewList(gql.String), "chunks"),
+			gql.Fields{
+				"header": {Type: messageHeaderType},
+				"data": {
+					Type: list(dataType),
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						data, err := getRequestContext(p).or.GetMessageData(p.Context, field(p.Source, "header", "id"))
+						return toList(data), err
+					},
+				},
+				"transaction": {Type: transactionType, Resolve: related(lookupTransaction, "txid")},
+				"events": listField(eventType, database.EventQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+					events, _, err := getRequestContext(p).or.GetMessageEvents(p.Context, field(p.Source, "header", "id"), filter)
+					return events, err
+				}),
+			},
+		)
+	})
+
+	eventType = object("Event", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "type", "namespace", "reference", "correlator", "tx", "topic", "created"),
+			scalars(gql.Float, "sequence"),
+			gql.Fields{
+				"message":         {Type: messageType, Resolve: eventReference(lookupMessage, core.EventTypeMessageConfirmed, core.EventTypeMessageRejected, core.EventTypeMessageExpired, core.EventTypeGroupMembershipChanged, core.EventTypeMessageRecalled, core.EventTypeTopicSequenceGap, core.EventTypeMessagePendingApp)},
+				"transaction":     {Type: transactionType, Resolve: related(lookupTransaction, "tx")},
+				"tokenPool":       {Type: tokenPoolType, Resolve: eventReference(lookupTokenPool, core.EventTypePoolConfirmed)},
+				"tokenTransfer":   {Type: tokenTransferType, Resolve: eventReference(lookupTokenTransfer, core.EventTypeTransferConfirmed)},
+				"blockchainEvent": {Type: blockchainEventType, Resolve: eventReference(lookupBlockchainEvent, core.EventTypeBlockchainEventReceived)},
+			},
+		)
+	})
+
+	operationType = object("Operation", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "namespace", "tx", "type", "status", "plugin", "error", "created", "updated"),
+			scalars(jsonScalar, "input", "output"),
+		)
+	})
+
+	blockchainEventType = object("BlockchainEvent", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "source", "namespace", "name", "listener", "protocolId", "timestamp"),
+			scalars(jsonScalar, "output", "info"),
+			gql.Fields{
+				"tx": {Type: blockchainTransactionRefType},
+			},
+		)
+	})
+
+	transactionType = object("Transaction", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "namespace", "type", "created", "idempotencyKey"),
+			scalars(gql.NewList(gql.String), "blockchainIds"),
+			gql.Fields{
+				"operations": {
+					Type: list(operationType),
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						ops, _, err := getRequestContext(p).or.GetTransactionOperations(p.Context, field(p.Source, "id"))
+						return toList(ops), err
+					},
+				},
+				"blockchainEvents": {
+					Type: list(blockchainEventType),
+					Resolve: func(p gql.ResolveParams) (interface{}, error) {
+						events, _, err := getRequestContext(p).or.GetTransactionBlockchainEvents(p.Context, field(p.Source, "id"))
+						return toList(events), err
+					},
+				},
+			},
+		)
+	})
+
+	ffiReferenceType = object("FFIReference", func() gql.Fields {
+		return scalars(gql.String, "id", "name", "version")
+	})
+
+	contractInterfaceType = object("ContractInterface", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "message", "namespace", "name", "networkName", "description", "version"),
+			scalars(gql.Boolean, "published"),
+		)
+	})
+
+	tokenPoolType = object("TokenPool", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "type", "namespace", "name", "networkName", "standard", "locator", "key", "symbol", "connector", "message", "state", "created"),
+			scalars(gql.Int, "decimals"),
+			scalars(gql.Boolean, "published"),
+			scalars(jsonScalar, "info"),
+			gql.Fields{
+				"tx":          {Type: transactionRefType},
+				"interface":   {Type: ffiReferenceType},
+				"contract":    {Type: contractInterfaceType, Resolve: related(lookupContractInterface, "interface", "id")},
+				"transaction": {Type: transactionType, Resolve: related(lookupTransaction, "tx", "id")},
+				"transfers": listField(tokenTransferType, database.TokenTransferQueryFactory, byPool, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+					transfers, _, err := getRequestContext(p).or.Assets().GetTokenTransfers(p.Context, filter)
+					return transfers, err
+				}),
+				"balances": listField(tokenBalanceType, database.TokenBalanceQueryFactory, byPool, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+					balances, _, err := getRequestContext(p).or.Assets().GetTokenBalances(p.Context, filter)
+					return balances, err
+				}),
+			},
+		)
+	})
+
+	tokenTransferType = object("TokenTransfer", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "type", "localId", "pool", "tokenIndex", "uri", "connector", "namespace", "key", "from", "to", "amount", "protocolId", "message", "messageHash", "created", "blockchainEvent"),
+			gql.Fields{
+				"tx":          {Type: transactionRefType},
+				"tokenPool":   {Type: tokenPoolType, Resolve: related(lookupTokenPool, "pool")},
+				"transaction": {Type: transactionType, Resolve: related(lookupTransaction, "tx", "id")},
+			},
+		)
+	})
+
+	tokenBalanceType = object("TokenBalance", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "pool", "tokenIndex", "uri", "connector", "namespace", "key", "balance", "updated"),
+			gql.Fields{
+				"tokenPool": {Type: tokenPoolType, Resolve: related(lookupTokenPool, "pool")},
+			},
+		)
+	})
+
+	contractURLsType = object("ContractURLs", func() gql.Fields {
+		return scalars(gql.String, "openapi", "ui")
+	})
+
+	contractAPIType = object("ContractAPI", func() gql.Fields {
+		return merge(
+			scalars(gql.String, "id", "namespace", "name", "networkName", "message"),
+			scalars(gql.Boolean, "published"),
+			scalars(jsonScalar, "location"),
+			gql.Fields{
+				"interface": {Type: ffiReferenceType},
+				"urls":      {Type: contractURLsType},
+				"contract":  {Type: contractInterfaceType, Resolve: related(lookupContractInterface, "interface", "id")},
+			},
+		)
+	})
+}
+
+func queryType() *gql.Object {
+	byID := func(t gql.Output, arg string, lookup func(p gql.ResolveParams, id string) (interface{}, error)) *gql.Field {
+		return &gql.Field{
+			Type: t,
+			Args: idArgs(arg),
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				return lookup(p, p.Args[arg].(string))
+			},
+		}
+	}
+	return gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"message": byID(messageType, "id", lookupMessage),
+			"messages": listField(messageType, database.MessageQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				messages, _, err := getRequestContext(p).or.GetMessages(p.Context, filter)
+				return messages, err
+			}),
+			"dataItem": byID(dataType, "id", func(p gql.ResolveParams, id string) (interface{}, error) {
+				rc := getRequestContext(p)
+				return rc.lookup("data", id, func() (interface{}, error) { return rc.or.GetDataByID(p.Context, id) })
+			}),
+			"data": listField(dataType, database.DataQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				data, _, err := getRequestContext(p).or.GetData(p.Context, filter)
+				return data, err
+			}),
+			"event": byID(eventType, "id", func(p gql.ResolveParams, id string) (interface{}, error) {
+				rc := getRequestContext(p)
+				return rc.lookup("event", id, func() (interface{}, error) { return rc.or.GetEventByID(p.Context, id) })
+			}),
+			"events": listField(eventType, database.EventQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				events, _, err := getRequestContext(p).or.GetEvents(p.Context, filter)
+				return events, err
+			}),
+			"transaction": byID(transactionType, "id", lookupTransaction),
+			"transactions": listField(transactionType, database.TransactionQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				txs, _, err := getRequestContext(p).or.GetTransactions(p.Context, filter)
+				return txs, err
+			}),
+			"blockchainEvent": byID(blockchainEventType, "id", lookupBlockchainEvent),
+			"tokenPool": byID(tokenPoolType, "nameOrId", func(p gql.ResolveParams, nameOrID string) (interface{}, error) {
+				rc := getRequestContext(p)
+				pool, err := rc.or.Assets().GetTokenPoolByNameOrID(p.Context, nameOrID)
+				if err != nil {
+					return nil, err
+				}
+				return rc.add("tokenPool", toObject(pool)), nil
+			}),
+			"tokenPools": listField(tokenPoolType, database.TokenPoolQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				pools, _, err := getRequestContext(p).or.Assets().GetTokenPools(p.Context, filter)
+				return pools, err
+			}),
+			"tokenTransfer": byID(tokenTransferType, "id", lookupTokenTransfer),
+			"tokenTransfers": listField(tokenTransferType, database.TokenTransferQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				transfers, _, err := getRequestContext(p).or.Assets().GetTokenTransfers(p.Context, filter)
+				return transfers, err
+			}),
+			"tokenBalances": listField(tokenBalanceType, database.TokenBalanceQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				balances, _, err := getRequestContext(p).or.Assets().GetTokenBalances(p.Context, filter)
+				return balances, err
+			}),
+			"contractAPI": byID(contractAPIType, "name", func(p gql.ResolveParams, name string) (interface{}, error) {
+				rc := getRequestContext(p)
+				api, err := rc.or.Contracts().GetContractAPI(p.Context, rc.apiBaseURL, name)
+				return toObject(api), err
+			}),
+			"contractAPIs": listField(contractAPIType, database.ContractAPIQueryFactory, nil, func(p gql.ResolveParams, filter ffapi.AndFilter) (interface{}, error) {
+				rc := getRequestContext(p)
+				apis, _, err := rc.or.Contracts().GetContractAPIs(p.Context, rc.apiBaseURL, filter)
+				return apis, err
+			}),
+		},
+	})
+}
+
+func newSchema() gql.Schema {
+	initTypes()
+	s, err := gql.NewSchema(gql.SchemaConfig{
+		Query: queryType(),
+	})
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
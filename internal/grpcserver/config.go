@@ -0,0 +1,43 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+)
+
+const (
+	defaultAddress = "127.0.0.1"
+	defaultPort    = 5003
+)
+
+const (
+	// GRPCConfigEnabled enables the gRPC API
+	GRPCConfigEnabled = "enabled"
+	// GRPCConfigAddress is the local address to accept gRPC API requests on
+	GRPCConfigAddress = "address"
+	// GRPCConfigPort is the port to accept gRPC API requests on
+	GRPCConfigPort = "port"
+)
+
+func InitConfig(config config.Section) {
+	config.AddKnownKey(GRPCConfigEnabled, false)
+	config.AddKnownKey(GRPCConfigAddress, defaultAddress)
+	config.AddKnownKey(GRPCConfigPort, defaultPort)
+	fftls.InitTLSConfig(config.SubSection("tls"))
+}
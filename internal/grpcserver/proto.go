@@ -0,0 +1,201 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/coreapi"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// parseJSON parses the JSON of a bytes field, if it is set
+func parseJSON(ctx context.Context, field string, b []byte, v interface{}) error {
+	if len(b) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return i18n.NewError(ctx, coremsgs.MsgGRPCInvalidJSON, field, err)
+	}
+	return nil
+}
+
+func parseJSONAny(ctx context.Context, field string, b []byte) (*fftypes.JSONAny, error) {
+	var v interface{}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	if err := parseJSON(ctx, field, b, &v); err != nil {
+		return nil, err
+	}
+	return fftypes.JSONAnyPtrBytes(b), nil
+}
+
+func messageFromProto(ctx context.Context, req *coreapi.SendMessageRequest) (in *core.MessageInOut, err error) {
+	in = &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				SignerRef: core.SignerRef{
+					Author: req.Author,
+					Key:    req.Key,
+				},
+				Topics: req.Topics,
+				Tag:    req.Tag,
+			},
+			IdempotencyKey: core.IdempotencyKey(req.IdempotencyKey),
+		},
+	}
+	if req.Group != "" {
+		if in.Header.Group, err = fftypes.ParseBytes32(ctx, req.Group); err != nil {
+			return nil, err
+		}
+	} else if len(req.Members) > 0 {
+		in.Group = &core.InputGroup{
+			Members: make([]core.MemberInput, len(req.Members)),
+		}
+		for i, m := range req.Members {
+			in.Group.Members[i] = core.MemberInput{Identity: m.Identity, Node: m.Node}
+		}
+	}
+	for i, d := range req.Data {
+		data := &core.DataRefOrValue{
+			Validator: core.ValidatorType(d.Validator),
+		}
+		if d.Id != "" {
+			if data.ID, err = fftypes.ParseUUID(ctx, d.Id); err != nil {
+				return nil, err
+			}
+		}
+		if d.DatatypeName != "" {
+			data.Datatype = &core.DatatypeRef{Name: d.DatatypeName, Version: d.DatatypeVersion}
+		}
+		if data.Value, err = parseJSONAny(ctx, fmt.Sprintf("data[%d].value", i), d.Value); err != nil {
+			return nil, err
+		}
+		in.InlineData = append(in.InlineData, data)
+	}
+	return in, nil
+}
+
+func contractCallFromProto(ctx context.Context, req *coreapi.InvokeContractRequest) (call *core.ContractCallRequest, err error) {
+	call = &core.ContractCallRequest{
+		Type:           core.CallTypeInvoke,
+		Key:            req.Key,
+		MethodPath:     req.MethodPath,
+		IdempotencyKey: core.IdempotencyKey(req.IdempotencyKey),
+	}
+	if req.Interface != "" {
+		if call.Interface, err = fftypes.ParseUUID(ctx, req.Interface); err != nil {
+			return nil, err
+		}
+	}
+	if call.Location, err = parseJSONAny(ctx, "location", req.Location); err != nil {
+		return nil, err
+	}
+	if err = parseJSON(ctx, "input", req.Input, &call.Input); err != nil {
+		return nil, err
+	}
+	if err = parseJSON(ctx, "options", req.Options, &call.Options); err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+func transferFromProto(req *coreapi.TransferTokensRequest) (*core.TokenTransferInput, error) {
+	in := &core.TokenTransferInput{
+		TokenTransfer: core.TokenTransfer{
+			TokenIndex: req.TokenIndex,
+			Key:        req.Key,
+			From:       req.From,
+			To:         req.To,
+		},
+		Pool:           req.Pool,
+		IdempotencyKey: core.IdempotencyKey(req.IdempotencyKey),
+	}
+	if req.Amount != "" {
+		if err := in.Amount.UnmarshalJSON([]byte(strconv.Quote(req.Amount))); err != nil {
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+func uuidString(u *fftypes.UUID) string {
+	if u == nil {
+		return ""
+	}
+	return u.String()
+}
+
+func bytes32String(b *fftypes.Bytes32) string {
+	if b == nil {
+		return ""
+	}
+	return b.String()
+}
+
+func timestamp(t *fftypes.FFTime) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t.Time())
+}
+
+func messageToProto(msg *core.Message) *coreapi.Message {
+	pm := &coreapi.Message{
+		Id:          uuidString(msg.Header.ID),
+		Type:        msg.Header.Type.String(),
+		Namespace:   msg.Header.Namespace,
+		Author:      msg.Header.Author,
+		Key:         msg.Header.Key,
+		Topics:      msg.Header.Topics,
+		Tag:         msg.Header.Tag,
+		Group:       bytes32String(msg.Header.Group),
+		Hash:        bytes32String(msg.Hash),
+		State:       msg.State.String(),
+		Transaction: uuidString(msg.TransactionID),
+		Created:     timestamp(msg.Header.Created),
+		Confirmed:   timestamp(msg.Confirmed),
+	}
+	for _, d := range msg.Data {
+		pm.Data = append(pm.Data, uuidString(d.ID))
+	}
+	return pm
+}
+
+func transferToProto(transfer *core.TokenTransfer) *coreapi.TokenTransfer {
+	return &coreapi.TokenTransfer{
+		LocalId:     uuidString(transfer.LocalID),
+		Type:        transfer.Type.String(),
+		Pool:        uuidString(transfer.Pool),
+		TokenIndex:  transfer.TokenIndex,
+		From:        transfer.From,
+		To:          transfer.To,
+		Amount:      transfer.Amount.String(),
+		Key:         transfer.Key,
+		ProtocolId:  transfer.ProtocolID,
+		Transaction: uuidString(transfer.TX.ID),
+		Created:     timestamp(transfer.Created),
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/auditlog"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/coreapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var ffMsgCodeExtractor = regexp.MustCompile(`^(FF\d+):`)
+
+// Server serves the FireFly gRPC service defined in pkg/coreapi, for internal services that want
+// typed access to the most frequently used operations of the REST API
+type Server struct {
+	coreapi.UnimplementedFireFlyServer
+
+	mgr         namespace.Manager
+	server      *grpc.Server
+	listener    net.Listener
+	rbacEnabled bool
+	auditLog    *auditlog.Logger
+	tenancy     core.Authorizer
+}
+
+// auditedRequest is a request that changes its namespace, which is recorded in the audit log
+type auditedRequest interface {
+	proto.Message
+	GetNamespace() string
+	GetIdempotencyKey() string
+}
+
+type transactionResponse interface {
+	GetTransaction() string
+}
+
+// NewServer creates the gRPC server. The calls that change a namespace are recorded in the audit log,
+// when one is supplied, and the calls to each namespace are checked by the tenancy authorizer when
+// tenancy is enabled.
+func NewServer(ctx context.Context, conf config.Section, mgr namespace.Manager, auditLog *auditlog.Logger, tenancy core.Authorizer) (*Server, error) {
+	tlsConfig, err := fftls.ConstructTLSConfig(ctx, conf.SubSection("tls"), fftls.ServerType)
+	if err != nil {
+		return nil, err
+	}
+	address := fmt.Sprintf("%s:%d", conf.GetString(GRPCConfigAddress), conf.GetInt(GRPCConfigPort))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgGRPCAPIListenFailed, address)
+	}
+	s := &Server{
+		mgr:         mgr,
+		listener:    listener,
+		rbacEnabled: config.GetBool(coreconfig.RBACEnabled),
+		auditLog:    auditLog,
+		tenancy:     tenancy,
+	}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.server = grpc.NewServer(opts...)
+	coreapi.RegisterFireFlyServer(s.server, s)
+	return s, nil
+}
+
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serve serves requests until the context is closed, then reports the result on the error channel
+func (s *Server) Serve(ctx context.Context, errChan chan error) {
+	go func() {
+		<-ctx.Done()
+		s.server.Stop()
+	}()
+	log.L(ctx).Infof("gRPC API listening on %s", s.listener.Addr())
+	err := s.server.Serve(s.listener)
+	if err == grpc.ErrServerStopped {
+		// We were stopped before we started serving
+		err = nil
+	}
+	if err != nil {
+		log.L(ctx).Errorf("gRPC API server stopped: %s", err)
+	}
+	errChan <- err
+}
+
+// unaryInterceptor logs and audits each call, and returns errors with the gRPC status code matching the
+// HTTP status the REST API would return for the same error
+func (s *Server) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	l := log.L(ctx)
+	l.Infof("--> gRPC %s", info.FullMethod)
+	res, err := handler(ctx, req)
+	if audited, ok := req.(auditedRequest); ok && s.auditLog != nil {
+		s.audit(ctx, info, audited, res, err)
+	}
+	if err != nil {
+		l.Errorf("<-- gRPC %s failed: %s", info.FullMethod, err)
+		return nil, status.Error(statusCode(err), err.Error())
+	}
+	l.Infof("<-- gRPC %s", info.FullMethod)
+	return res, nil
+}
+
+func (s *Server) audit(ctx context.Context, info *grpc.UnaryServerInfo, req auditedRequest, res interface{}, err error) {
+	b, _ := proto.Marshal(req)
+	hash := sha256.Sum256(b)
+	entry := &core.AuditLogEntry{
+		Namespace:      req.GetNamespace(),
+		Principal:      orchestrator.Principal(ctx, incomingHeader(ctx)),
+		Method:         "GRPC",
+		Route:          info.FullMethod,
+		RequestHash:    (*fftypes.Bytes32)(&hash),
+		IdempotencyKey: core.IdempotencyKey(req.GetIdempotencyKey()),
+		Status:         http.StatusOK,
+	}
+	if err != nil {
+		entry.Status = httpStatus(err)
+		entry.Error = err.Error()
+	} else if txRes, ok := res.(transactionResponse); ok && txRes.GetTransaction() != "" {
+		entry.Transaction, _ = fftypes.ParseUUID(ctx, txRes.GetTransaction())
+	}
+	s.auditLog.Record(ctx, entry)
+}
+
+func httpStatus(err error) int {
+	if code := ffMsgCodeExtractor.FindStringSubmatch(err.Error()); code != nil {
+		if hint, ok := i18n.GetStatusHint(code[1]); ok {
+			return hint
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+func statusCode(err error) codes.Code {
+	return grpcCode(httpStatus(err))
+}
+
+// incomingHeader is the metadata of a call, as HTTP headers
+func incomingHeader(ctx context.Context) http.Header {
+	header := http.Header{}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for k, values := range md {
+		for _, v := range values {
+			header.Add(k, v)
+		}
+	}
+	return header
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
+
+// orchestrator returns the orchestrator of the namespace, once the call is authorized. The metadata
+// of the call is passed to the auth plugin as headers, in the same way as for gRPC event streams
+func (s *Server) orchestrator(ctx context.Context, ns, method string, role core.Role) (orchestrator.Orchestrator, error) {
+	or, err := s.mgr.Orchestrator(ctx, ns, false)
+	if err != nil {
+		return nil, err
+	}
+	authReq := &fftypes.AuthReq{
+		Method:    method,
+		Namespace: ns,
+		Header:    incomingHeader(ctx),
+	}
+	if s.tenancy != nil {
+		if err := s.tenancy.Authorize(ctx, authReq); err != nil {
+			return nil, err
+		}
+	}
+	if err := or.Authorize(ctx, authReq); err != nil {
+		return nil, err
+	}
+	if s.rbacEnabled {
+		if err := or.AuthorizeRole(ctx, authReq, role); err != nil {
+			return nil, err
+		}
+	}
+	return or, nil
+}
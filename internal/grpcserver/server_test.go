@@ -0,0 +1,280 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/auditlog"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/mocks/assetmocks"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/hyperledger/firefly/mocks/orchestratormocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/coreapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var utConfig = config.RootSection("grpc_unit_tests")
+
+type testServer struct {
+	ctx    context.Context
+	mgr    *namespacemocks.Manager
+	or     *orchestratormocks.Orchestrator
+	client coreapi.FireFlyClient
+}
+
+func newTestServer(t *testing.T) (*testServer, func()) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ts := &testServer{
+		ctx: ctx,
+		mgr: &namespacemocks.Manager{},
+		or:  &orchestratormocks.Orchestrator{},
+	}
+	s, err := NewServer(ctx, utConfig, ts.mgr, nil, nil)
+	assert.NoError(t, err)
+	errChan := make(chan error, 1)
+	go s.Serve(ctx, errChan)
+
+	conn, err := grpc.Dial(s.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	ts.client = coreapi.NewFireFlyClient(conn)
+	ts.mgr.On("Orchestrator", mock.Anything, "ns1", false).Return(ts.or, nil).Maybe()
+	return ts, func() {
+		conn.Close()
+		cancel()
+		assert.NoError(t, <-errChan)
+		ts.mgr.AssertExpectations(t)
+		ts.or.AssertExpectations(t)
+	}
+}
+
+func TestNewServerBadTLS(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	tlsConf := utConfig.SubSection("tls")
+	tlsConf.Set("enabled", true)
+	tlsConf.Set("caFile", "badfile")
+	_, err := NewServer(context.Background(), utConfig, &namespacemocks.Manager{}, nil, nil)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestNewServerTLS(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+	utConfig.SubSection("tls").Set("enabled", true)
+	s, err := NewServer(context.Background(), utConfig, &namespacemocks.Manager{}, nil, nil)
+	assert.NoError(t, err)
+	s.listener.Close()
+}
+
+func TestNewServerBadAddress(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	utConfig.Set(GRPCConfigAddress, "...://")
+	_, err := NewServer(context.Background(), utConfig, &namespacemocks.Manager{}, nil, nil)
+	assert.Regexp(t, "FF10586", err)
+}
+
+func TestServeFail(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+	s, err := NewServer(context.Background(), utConfig, &namespacemocks.Manager{}, nil, nil)
+	assert.NoError(t, err)
+	s.listener.Close()
+	errChan := make(chan error, 1)
+	s.Serve(context.Background(), errChan)
+	assert.Error(t, <-errChan)
+}
+
+func TestAuthorizeWithMetadata(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+
+	ts.or.On("Authorize", mock.Anything, mock.MatchedBy(func(authReq *fftypes.AuthReq) bool {
+		return authReq.Namespace == "ns1" && authReq.Method == "GET" && authReq.Header.Get("Authorization") == "Basic dGVzdDp0ZXN0"
+	})).Return(i18n.NewError(ts.ctx, i18n.MsgUnauthorized))
+
+	ctx := metadata.AppendToOutgoingContext(ts.ctx, "authorization", "Basic dGVzdDp0ZXN0")
+	_, err := ts.client.Query(ctx, &coreapi.QueryRequest{Namespace: "ns1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestRBACForbidden(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+	config.Set(coreconfig.RBACEnabled, true)
+	mgr := &namespacemocks.Manager{}
+	mor := &orchestratormocks.Orchestrator{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, utConfig, mgr, nil, nil)
+	assert.NoError(t, err)
+	errChan := make(chan error, 1)
+	go s.Serve(ctx, errChan)
+	conn, err := grpc.Dial(s.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := coreapi.NewFireFlyClient(conn)
+
+	mgr.On("Orchestrator", mock.Anything, "ns1", false).Return(mor, nil)
+	mor.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mor.On("AuthorizeRole", mock.Anything, mock.Anything, core.RoleTokenAdmin).Return(i18n.NewError(ctx, coremsgs.MsgRBACForbidden, "alice", "ns1", core.RoleTokenAdmin))
+
+	_, err = client.TransferTokens(ctx, &coreapi.TransferTokensRequest{Namespace: "ns1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Regexp(t, "FF10591", err)
+
+	mgr.AssertExpectations(t)
+	mor.AssertExpectations(t)
+}
+
+func TestTenancyForbidden(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+	mgr := &namespacemocks.Manager{}
+	mor := &orchestratormocks.Orchestrator{}
+	tenancy := &namespacemocks.Manager{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, err := NewServer(ctx, utConfig, mgr, nil, tenancy)
+	assert.NoError(t, err)
+	errChan := make(chan error, 1)
+	go s.Serve(ctx, errChan)
+	conn, err := grpc.Dial(s.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := coreapi.NewFireFlyClient(conn)
+
+	mgr.On("Orchestrator", mock.Anything, "ns1", false).Return(mor, nil)
+	tenancy.On("Authorize", mock.Anything, mock.MatchedBy(func(authReq *fftypes.AuthReq) bool {
+		return authReq.Namespace == "ns1"
+	})).Return(i18n.NewError(ctx, coremsgs.MsgTenantForbidden, "carol", "acme"))
+
+	_, err = client.Query(ctx, &coreapi.QueryRequest{Namespace: "ns1"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Regexp(t, "FF10614", err)
+
+	mgr.AssertExpectations(t)
+	mor.AssertExpectations(t)
+	tenancy.AssertExpectations(t)
+}
+
+func TestAuditLog(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig(utConfig)
+	utConfig.Set(GRPCConfigPort, 0)
+	mgr := &namespacemocks.Manager{}
+	mor := &orchestratormocks.Orchestrator{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	auditLog, err := auditlog.NewLogger(ctx, mgr)
+	assert.NoError(t, err)
+	s, err := NewServer(ctx, utConfig, mgr, auditLog, nil)
+	assert.NoError(t, err)
+	errChan := make(chan error, 1)
+	go s.Serve(ctx, errChan)
+	conn, err := grpc.Dial(s.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+	client := coreapi.NewFireFlyClient(conn)
+
+	txID := fftypes.NewUUID()
+	mam := &assetmocks.Manager{}
+	mgr.On("Orchestrator", mock.Anything, "ns1", false).Return(mor, nil)
+	mor.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mor.On("Assets").Return(mam)
+	mam.On("TransferTokens", mock.Anything, mock.Anything, false).Return(&core.TokenTransfer{
+		TX: core.TransactionRef{ID: txID},
+	}, nil).Once()
+	mam.On("TransferTokens", mock.Anything, mock.Anything, false).Return(nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)).Once()
+	mor.On("RecordAuditLogEntry", mock.Anything, mock.MatchedBy(func(entry *core.AuditLogEntry) bool {
+		return entry.Method == "GRPC" &&
+			entry.Route == "/firefly.coreapi.v1.FireFly/TransferTokens" &&
+			entry.Principal == "alice" &&
+			entry.IdempotencyKey == "idem1" &&
+			entry.RequestHash != nil &&
+			entry.Transaction.Equals(txID) &&
+			entry.Status == 200
+	})).Return(nil).Once()
+	mor.On("RecordAuditLogEntry", mock.Anything, mock.MatchedBy(func(entry *core.AuditLogEntry) bool {
+		return entry.Transaction == nil &&
+			entry.Status == 404 &&
+			entry.Error != ""
+	})).Return(nil).Once()
+
+	config.Set(coreconfig.RBACPrincipalHeader, "x-user")
+	callCtx := metadata.AppendToOutgoingContext(ctx, "x-user", "alice")
+	_, err = client.TransferTokens(callCtx, &coreapi.TransferTokensRequest{Namespace: "ns1", IdempotencyKey: "idem1"})
+	assert.NoError(t, err)
+	_, err = client.TransferTokens(callCtx, &coreapi.TransferTokensRequest{Namespace: "ns1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	mgr.AssertExpectations(t)
+	mor.AssertExpectations(t)
+	mam.AssertExpectations(t)
+}
+
+func TestNamespaceNotFound(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+
+	ts.mgr.On("Orchestrator", mock.Anything, "ns2", false).Return(nil, i18n.NewError(ts.ctx, coremsgs.MsgUnknownNamespace, "ns2"))
+
+	_, err := ts.client.Query(ts.ctx, &coreapi.QueryRequest{Namespace: "ns2"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Regexp(t, "FF10436", err)
+}
+
+func TestStatusCode(t *testing.T) {
+	for httpStatus, code := range map[int]codes.Code{
+		400: codes.InvalidArgument,
+		401: codes.Unauthenticated,
+		403: codes.PermissionDenied,
+		404: codes.NotFound,
+		409: codes.AlreadyExists,
+		408: codes.DeadlineExceeded,
+		504: codes.DeadlineExceeded,
+		501: codes.Unimplemented,
+		503: codes.Unavailable,
+		500: codes.Internal,
+	} {
+		assert.Equal(t, code, grpcCode(httpStatus))
+	}
+	assert.Equal(t, codes.InvalidArgument, statusCode(i18n.NewError(context.Background(), coremsgs.MsgGRPCUnknownCollection, "unknown")))
+	assert.Equal(t, codes.Internal, statusCode(fmt.Errorf("pop")))
+}
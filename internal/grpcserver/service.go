@@ -0,0 +1,248 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/coreapi"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+func (s *Server) SendMessage(ctx context.Context, req *coreapi.SendMessageRequest) (*coreapi.Message, error) {
+	or, err := s.orchestrator(ctx, req.Namespace, http.MethodPost, core.RoleSender)
+	if err != nil {
+		return nil, err
+	}
+	if or.MultiParty() == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	in, err := messageFromProto(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var msg *core.Message
+	if in.Header.Group != nil || in.Group != nil {
+		msg, err = or.PrivateMessaging().SendMessage(ctx, in, req.Confirm)
+	} else {
+		msg, err = or.Broadcast().BroadcastMessage(ctx, in, req.Confirm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return messageToProto(msg), nil
+}
+
+func (s *Server) InvokeContract(ctx context.Context, req *coreapi.InvokeContractRequest) (*coreapi.InvokeContractResponse, error) {
+	or, err := s.orchestrator(ctx, req.Namespace, http.MethodPost, core.RoleSender)
+	if err != nil {
+		return nil, err
+	}
+	if or.Contracts() == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	call, err := contractCallFromProto(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	if req.Api != "" {
+		result, err = or.Contracts().InvokeContractAPI(ctx, req.Api, req.MethodPath, call, req.Confirm)
+	} else {
+		result, err = or.Contracts().InvokeContract(ctx, call, req.Confirm)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b, _ := json.Marshal(result)
+	return &coreapi.InvokeContractResponse{Result: b}, nil
+}
+
+func (s *Server) TransferTokens(ctx context.Context, req *coreapi.TransferTokensRequest) (*coreapi.TokenTransfer, error) {
+	or, err := s.orchestrator(ctx, req.Namespace, http.MethodPost, core.RoleTokenAdmin)
+	if err != nil {
+		return nil, err
+	}
+	in, err := transferFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+	transfer, err := or.Assets().TransferTokens(ctx, in, req.Confirm)
+	if err != nil {
+		return nil, err
+	}
+	return transferToProto(transfer), nil
+}
+
+type collection struct {
+	queryFactory ffapi.QueryFactory
+	get          func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error)
+}
+
+var collections = map[coreapi.Collection]*collection{
+	coreapi.Collection_COLLECTION_MESSAGES: {
+		queryFactory: database.MessageQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.GetMessages(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_DATA: {
+		queryFactory: database.DataQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.GetData(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_EVENTS: {
+		queryFactory: database.EventQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.GetEvents(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_TRANSACTIONS: {
+		queryFactory: database.TransactionQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.GetTransactions(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_OPERATIONS: {
+		queryFactory: database.OperationQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.GetOperations(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_BLOCKCHAIN_EVENTS: {
+		queryFactory: database.BlockchainEventQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.GetBlockchainEvents(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_TOKEN_POOLS: {
+		queryFactory: database.TokenPoolQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.Assets().GetTokenPools(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_TOKEN_TRANSFERS: {
+		queryFactory: database.TokenTransferQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.Assets().GetTokenTransfers(ctx, filter))
+		},
+	},
+	coreapi.Collection_COLLECTION_TOKEN_BALANCES: {
+		queryFactory: database.TokenBalanceQueryFactory,
+		get: func(ctx context.Context, or orchestrator.Orchestrator, filter ffapi.AndFilter) (interface{}, *ffapi.FilterResult, error) {
+			return listResult(or.Assets().GetTokenBalances(ctx, filter))
+		},
+	},
+}
+
+func (s *Server) Query(ctx context.Context, req *coreapi.QueryRequest) (*coreapi.QueryResponse, error) {
+	or, err := s.orchestrator(ctx, req.Namespace, http.MethodGet, core.RoleReader)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := collections[req.Collection]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGRPCUnknownCollection, req.Collection)
+	}
+	filter, err := buildFilter(ctx, c.queryFactory, req)
+	if err != nil {
+		return nil, err
+	}
+	items, fr, err := c.get(ctx, or, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items are returned with the same JSON encoding as the REST API
+	var rawItems []json.RawMessage
+	b, _ := json.Marshal(items)
+	_ = json.Unmarshal(b, &rawItems)
+	res := &coreapi.QueryResponse{
+		Items: make([][]byte, len(rawItems)),
+	}
+	for i, item := range rawItems {
+		res.Items[i] = item
+	}
+	if fr != nil && fr.TotalCount != nil {
+		res.Total = *fr.TotalCount
+	}
+	return res, nil
+}
+
+func buildFilter(ctx context.Context, qf ffapi.QueryFactory, req *coreapi.QueryRequest) (ffapi.AndFilter, error) {
+	fb := qf.NewFilter(ctx)
+	conditions := make([]ffapi.Filter, len(req.Filter))
+	for i, c := range req.Filter {
+		conditions[i] = filterCondition(fb, c)
+	}
+	filter := fb.And(conditions...)
+	for _, field := range req.Sort {
+		filter.Sort(field)
+	}
+	if req.Descending {
+		filter.Descending()
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = config.GetUint64(coreconfig.APIDefaultFilterLimit)
+	} else if maxLimit := config.GetUint64(coreconfig.APIMaxFilterLimit); limit > maxLimit {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMaxFilterLimit, maxLimit)
+	}
+	if maxSkip := config.GetUint64(coreconfig.APIMaxFilterSkip); req.Skip > maxSkip {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMaxFilterSkip, maxSkip)
+	}
+	filter.Limit(limit).Skip(req.Skip).Count(req.Count)
+	return filter, nil
+}
+
+func filterCondition(fb ffapi.FilterBuilder, c *coreapi.Condition) ffapi.Filter {
+	switch c.Op {
+	case coreapi.Op_OP_NEQ:
+		return fb.Neq(c.Field, c.Value)
+	case coreapi.Op_OP_GT:
+		return fb.Gt(c.Field, c.Value)
+	case coreapi.Op_OP_GTE:
+		return fb.Gte(c.Field, c.Value)
+	case coreapi.Op_OP_LT:
+		return fb.Lt(c.Field, c.Value)
+	case coreapi.Op_OP_LTE:
+		return fb.Lte(c.Field, c.Value)
+	case coreapi.Op_OP_CONTAINS:
+		return fb.Contains(c.Field, c.Value)
+	case coreapi.Op_OP_STARTSWITH:
+		return fb.StartsWith(c.Field, c.Value)
+	case coreapi.Op_OP_ENDSWITH:
+		return fb.EndsWith(c.Field, c.Value)
+	default:
+		return fb.Eq(c.Field, c.Value)
+	}
+}
+
+func listResult(items interface{}, fr *ffapi.FilterResult, err error) (interface{}, *ffapi.FilterResult, error) {
+	return items, fr, err
+}
@@ -0,0 +1,428 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/assetmocks"
+	"github.com/hyperledger/firefly/mocks/broadcastmocks"
+	"github.com/hyperledger/firefly/mocks/contractmocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/coreapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (ts *testServer) authorize() {
+	ts.or.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+}
+
+func TestSendMessageBroadcast(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	dataID := fftypes.NewUUID()
+	mbm := &broadcastmocks.Manager{}
+	ts.or.On("MultiParty").Return(&multipartymocks.Manager{})
+	ts.or.On("Broadcast").Return(mbm)
+	mbm.On("BroadcastMessage", mock.Anything, mock.MatchedBy(func(in *core.MessageInOut) bool {
+		return in.Header.Tag == "tag1" &&
+			in.Header.Topics[0] == "topic1" &&
+			in.Header.Author == "did:firefly:org/org1" &&
+			in.IdempotencyKey == "idem1" &&
+			in.InlineData[0].Value.String() == `{"some":"value"}` &&
+			in.InlineData[0].Datatype.Name == "widget" &&
+			in.InlineData[1].ID.Equals(dataID)
+	}), true).Return(&core.Message{
+		Header: core.MessageHeader{
+			ID:      fftypes.NewUUID(),
+			Type:    core.MessageTypeBroadcast,
+			Tag:     "tag1",
+			Created: fftypes.Now(),
+		},
+		State: core.MessageStateConfirmed,
+		Data: core.DataRefs{
+			{ID: dataID},
+		},
+	}, nil)
+
+	msg, err := ts.client.SendMessage(ts.ctx, &coreapi.SendMessageRequest{
+		Namespace:      "ns1",
+		Topics:         []string{"topic1"},
+		Tag:            "tag1",
+		Author:         "did:firefly:org/org1",
+		IdempotencyKey: "idem1",
+		Data: []*coreapi.DataInput{
+			{Value: []byte(`{"some":"value"}`), DatatypeName: "widget", DatatypeVersion: "1.0"},
+			{Id: dataID.String()},
+		},
+		Confirm: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "broadcast", msg.Type)
+	assert.Equal(t, "confirmed", msg.State)
+	assert.Equal(t, []string{dataID.String()}, msg.Data)
+	assert.NotNil(t, msg.Created)
+	assert.Nil(t, msg.Confirmed)
+
+	mbm.AssertExpectations(t)
+}
+
+func TestSendMessagePrivate(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	mpm := &privatemessagingmocks.Manager{}
+	ts.or.On("MultiParty").Return(&multipartymocks.Manager{})
+	ts.or.On("PrivateMessaging").Return(mpm)
+	mpm.On("SendMessage", mock.Anything, mock.MatchedBy(func(in *core.MessageInOut) bool {
+		return in.Group.Members[0].Identity == "did:firefly:org/org2" && in.Group.Members[0].Node == "node2"
+	}), false).Return(&core.Message{}, nil).Once()
+	group := fftypes.NewRandB32()
+	mpm.On("SendMessage", mock.Anything, mock.MatchedBy(func(in *core.MessageInOut) bool {
+		return in.Group == nil && in.Header.Group.Equals(group)
+	}), false).Return(nil, fmt.Errorf("pop")).Once()
+
+	_, err := ts.client.SendMessage(ts.ctx, &coreapi.SendMessageRequest{
+		Namespace: "ns1",
+		Members: []*coreapi.Member{
+			{Identity: "did:firefly:org/org2", Node: "node2"},
+		},
+	})
+	assert.NoError(t, err)
+
+	_, err = ts.client.SendMessage(ts.ctx, &coreapi.SendMessageRequest{
+		Namespace: "ns1",
+		Group:     group.String(),
+	})
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	mpm.AssertExpectations(t)
+}
+
+func TestSendMessageBadInput(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	ts.or.On("MultiParty").Return(&multipartymocks.Manager{})
+
+	for _, req := range []*coreapi.SendMessageRequest{
+		{Namespace: "ns1", Group: "!bytes32"},
+		{Namespace: "ns1", Data: []*coreapi.DataInput{{Id: "!uuid"}}},
+		{Namespace: "ns1", Data: []*coreapi.DataInput{{Value: []byte("!json")}}},
+	} {
+		_, err := ts.client.SendMessage(ts.ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestSendMessageNotMultiparty(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	ts.or.On("MultiParty").Return(nil)
+
+	_, err := ts.client.SendMessage(ts.ctx, &coreapi.SendMessageRequest{Namespace: "ns1"})
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestSendMessageUnauthorized(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+
+	ts.or.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := ts.client.SendMessage(ts.ctx, &coreapi.SendMessageRequest{Namespace: "ns1"})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestInvokeContract(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	ffiID := fftypes.NewUUID()
+	mcm := &contractmocks.Manager{}
+	ts.or.On("Contracts").Return(mcm)
+	mcm.On("InvokeContract", mock.Anything, mock.MatchedBy(func(req *core.ContractCallRequest) bool {
+		return req.Type == core.CallTypeInvoke &&
+			req.Interface.Equals(ffiID) &&
+			req.Location.String() == `{"address":"0x12345"}` &&
+			req.MethodPath == "set" &&
+			req.Input["x"] == float64(42) &&
+			req.Options["gas"] == "1000" &&
+			req.Key == "0x23456"
+	}), false).Return(&core.Operation{Type: core.OpTypeBlockchainInvoke}, nil)
+
+	res, err := ts.client.InvokeContract(ts.ctx, &coreapi.InvokeContractRequest{
+		Namespace:  "ns1",
+		Interface:  ffiID.String(),
+		Location:   []byte(`{"address":"0x12345"}`),
+		MethodPath: "set",
+		Input:      []byte(`{"x":42}`),
+		Options:    []byte(`{"gas":"1000"}`),
+		Key:        "0x23456",
+	})
+	assert.NoError(t, err)
+	var op core.Operation
+	err = json.Unmarshal(res.Result, &op)
+	assert.NoError(t, err)
+	assert.Equal(t, core.OpTypeBlockchainInvoke, op.Type)
+
+	mcm.AssertExpectations(t)
+}
+
+func TestInvokeContractAPI(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	mcm := &contractmocks.Manager{}
+	ts.or.On("Contracts").Return(mcm)
+	mcm.On("InvokeContractAPI", mock.Anything, "api1", "set", mock.Anything, true).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ts.client.InvokeContract(ts.ctx, &coreapi.InvokeContractRequest{
+		Namespace:  "ns1",
+		Api:        "api1",
+		MethodPath: "set",
+		Confirm:    true,
+	})
+	assert.Regexp(t, "pop", err)
+
+	mcm.AssertExpectations(t)
+}
+
+func TestInvokeContractBadInput(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	ts.or.On("Contracts").Return(&contractmocks.Manager{})
+
+	for _, req := range []*coreapi.InvokeContractRequest{
+		{Namespace: "ns1", Interface: "!uuid"},
+		{Namespace: "ns1", Location: []byte("!json")},
+		{Namespace: "ns1", Input: []byte("[]")},
+		{Namespace: "ns1", Options: []byte("!json")},
+	} {
+		_, err := ts.client.InvokeContract(ts.ctx, req)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	}
+}
+
+func TestInvokeContractNotSupported(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	ts.or.On("Contracts").Return(nil)
+
+	_, err := ts.client.InvokeContract(ts.ctx, &coreapi.InvokeContractRequest{Namespace: "ns1"})
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestInvokeContractUnauthorized(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+
+	ts.or.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := ts.client.InvokeContract(ts.ctx, &coreapi.InvokeContractRequest{Namespace: "ns1"})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestTransferTokens(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	poolID := fftypes.NewUUID()
+	txID := fftypes.NewUUID()
+	mam := &assetmocks.Manager{}
+	ts.or.On("Assets").Return(mam)
+	mam.On("TransferTokens", mock.Anything, mock.MatchedBy(func(in *core.TokenTransferInput) bool {
+		return in.Pool == "pool1" &&
+			in.From == "0x1" &&
+			in.To == "0x2" &&
+			in.Amount.String() == "1000000000000000000000" &&
+			in.TokenIndex == "1" &&
+			in.IdempotencyKey == "idem1"
+	}), true).Return(&core.TokenTransfer{
+		Type:    core.TokenTransferTypeTransfer,
+		LocalID: fftypes.NewUUID(),
+		Pool:    poolID,
+		Amount:  *fftypes.NewFFBigInt(10),
+		TX:      core.TransactionRef{ID: txID},
+	}, nil)
+
+	transfer, err := ts.client.TransferTokens(ts.ctx, &coreapi.TransferTokensRequest{
+		Namespace:      "ns1",
+		Pool:           "pool1",
+		From:           "0x1",
+		To:             "0x2",
+		Amount:         "1000000000000000000000",
+		TokenIndex:     "1",
+		IdempotencyKey: "idem1",
+		Confirm:        true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "transfer", transfer.Type)
+	assert.Equal(t, poolID.String(), transfer.Pool)
+	assert.Equal(t, txID.String(), transfer.Transaction)
+	assert.Equal(t, "10", transfer.Amount)
+
+	mam.AssertExpectations(t)
+}
+
+func TestTransferTokensFail(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	mam := &assetmocks.Manager{}
+	ts.or.On("Assets").Return(mam)
+	mam.On("TransferTokens", mock.Anything, mock.Anything, false).Return(nil, fmt.Errorf("pop"))
+
+	_, err := ts.client.TransferTokens(ts.ctx, &coreapi.TransferTokensRequest{Namespace: "ns1", Amount: "1"})
+	assert.Regexp(t, "pop", err)
+
+	_, err = ts.client.TransferTokens(ts.ctx, &coreapi.TransferTokensRequest{Namespace: "ns1", Amount: "one"})
+	assert.Regexp(t, "FF00104", err)
+
+	mam.AssertExpectations(t)
+}
+
+func TestTransferTokensUnauthorized(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+
+	ts.or.On("Authorize", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := ts.client.TransferTokens(ts.ctx, &coreapi.TransferTokensRequest{Namespace: "ns1"})
+	assert.Regexp(t, "pop", err)
+}
+
+func TestQuery(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	total := int64(100)
+	ts.or.On("GetMessages", mock.Anything, mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		fi, _ := f.Finalize()
+		return fi.String() == "( tag == 'a' ) && ( tag != 'b' ) && ( sequence >> 1 ) && ( sequence >= 2 ) && ( sequence << 3 ) && ( sequence <= 4 ) && ( tag %= 'c' ) && ( tag ^= 'd' ) && ( tag $= 'e' ) sort=-sequence,-tag skip=10 limit=5 count=true"
+	})).Return([]*core.Message{
+		{Header: core.MessageHeader{Tag: "tag1"}},
+	}, &ffapi.FilterResult{TotalCount: &total}, nil)
+
+	res, err := ts.client.Query(ts.ctx, &coreapi.QueryRequest{
+		Namespace:  "ns1",
+		Collection: coreapi.Collection_COLLECTION_MESSAGES,
+		Filter: []*coreapi.Condition{
+			{Field: "tag", Value: "a"},
+			{Field: "tag", Op: coreapi.Op_OP_NEQ, Value: "b"},
+			{Field: "sequence", Op: coreapi.Op_OP_GT, Value: "1"},
+			{Field: "sequence", Op: coreapi.Op_OP_GTE, Value: "2"},
+			{Field: "sequence", Op: coreapi.Op_OP_LT, Value: "3"},
+			{Field: "sequence", Op: coreapi.Op_OP_LTE, Value: "4"},
+			{Field: "tag", Op: coreapi.Op_OP_CONTAINS, Value: "c"},
+			{Field: "tag", Op: coreapi.Op_OP_STARTSWITH, Value: "d"},
+			{Field: "tag", Op: coreapi.Op_OP_ENDSWITH, Value: "e"},
+		},
+		Sort:       []string{"sequence", "tag"},
+		Descending: true,
+		Skip:       10,
+		Limit:      5,
+		Count:      true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, res.Items, 1)
+	var msg core.Message
+	err = json.Unmarshal(res.Items[0], &msg)
+	assert.NoError(t, err)
+	assert.Equal(t, "tag1", msg.Header.Tag)
+	assert.Equal(t, int64(100), res.Total)
+}
+
+func TestQueryCollections(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	mam := &assetmocks.Manager{}
+	ts.or.On("Assets").Return(mam)
+	ts.or.On("GetData", mock.Anything, mock.Anything).Return(core.DataArray{}, nil, nil)
+	ts.or.On("GetEvents", mock.Anything, mock.Anything).Return([]*core.Event{}, nil, nil)
+	ts.or.On("GetTransactions", mock.Anything, mock.Anything).Return([]*core.Transaction{}, nil, nil)
+	ts.or.On("GetOperations", mock.Anything, mock.Anything).Return([]*core.Operation{}, nil, nil)
+	ts.or.On("GetBlockchainEvents", mock.Anything, mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+	mam.On("GetTokenPools", mock.Anything, mock.Anything).Return([]*core.TokenPool{}, nil, nil)
+	mam.On("GetTokenTransfers", mock.Anything, mock.Anything).Return([]*core.TokenTransfer{}, nil, nil)
+	mam.On("GetTokenBalances", mock.Anything, mock.Anything).Return([]*core.TokenBalance{}, nil, nil)
+
+	for _, c := range []coreapi.Collection{
+		coreapi.Collection_COLLECTION_DATA,
+		coreapi.Collection_COLLECTION_EVENTS,
+		coreapi.Collection_COLLECTION_TRANSACTIONS,
+		coreapi.Collection_COLLECTION_OPERATIONS,
+		coreapi.Collection_COLLECTION_BLOCKCHAIN_EVENTS,
+		coreapi.Collection_COLLECTION_TOKEN_POOLS,
+		coreapi.Collection_COLLECTION_TOKEN_TRANSFERS,
+		coreapi.Collection_COLLECTION_TOKEN_BALANCES,
+	} {
+		res, err := ts.client.Query(ts.ctx, &coreapi.QueryRequest{Namespace: "ns1", Collection: c})
+		assert.NoError(t, err)
+		assert.Empty(t, res.Items)
+	}
+
+	mam.AssertExpectations(t)
+}
+
+func TestQueryFail(t *testing.T) {
+	ts, done := newTestServer(t)
+	defer done()
+	ts.authorize()
+
+	ts.or.On("GetMessages", mock.Anything, mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := ts.client.Query(ts.ctx, &coreapi.QueryRequest{Namespace: "ns1", Collection: coreapi.Collection_COLLECTION_MESSAGES})
+	assert.Regexp(t, "pop", err)
+
+	_, err = ts.client.Query(ts.ctx, &coreapi.QueryRequest{Namespace: "ns1"})
+	assert.Regexp(t, "FF10588", err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = ts.client.Query(ts.ctx, &coreapi.QueryRequest{Namespace: "ns1", Collection: coreapi.Collection_COLLECTION_MESSAGES, Limit: 1001})
+	assert.Regexp(t, "FF10184", err)
+
+	_, err = ts.client.Query(ts.ctx, &coreapi.QueryRequest{Namespace: "ns1", Collection: coreapi.Collection_COLLECTION_MESSAGES, Skip: 1001})
+	assert.Regexp(t, "FF10183", err)
+}
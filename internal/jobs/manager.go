@@ -0,0 +1,429 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type Manager interface {
+	RegisterHandler(jobType core.JobType, handler *Handler)
+	Start() error
+	WaitStop()
+
+	SubmitJob(ctx context.Context, input *core.JobInput) (*core.Job, error)
+	GetJobByID(ctx context.Context, id string) (*core.Job, error)
+	GetJobs(ctx context.Context, filter ffapi.AndFilter) ([]*core.Job, *ffapi.FilterResult, error)
+	CancelJob(ctx context.Context, id string) (*core.Job, error)
+	GetJobOutputFile(ctx context.Context, id string) (reader io.ReadCloser, name string, err error)
+}
+
+// Handler performs a type of job
+type Handler struct {
+	// NewInput returns a new instance of the input of the job. The input of each job is parsed into it when
+	// the job is submitted, to check it is valid, and again when the job runs.
+	NewInput func() interface{}
+	// Run performs the job with its parsed input, returning the output
+	Run func(ctx context.Context, input interface{}, runner Runner) (output interface{}, err error)
+}
+
+// Runner is passed to the handler of a running job, to report its progress and write its output files
+type Runner interface {
+	Progress(done, total int64)
+	WriteFile(ext string, reader io.Reader) (*OutputFile, error)
+}
+
+// OutputFile is the output of a job that writes a file, which can be downloaded once the job has succeeded
+type OutputFile struct {
+	File string `json:"file"`
+	Size int64  `json:"size"`
+}
+
+const (
+	progressUpdateInterval = 1 * time.Second
+	dispatchRetryDelay     = 5 * time.Second
+)
+
+// jobManager performs the jobs of a namespace in the background, with a fixed number of workers.
+// Jobs are persisted as soon as they are submitted, and a dispatcher starts the oldest pending job
+// whenever a worker is free, so pending jobs are recovered on startup. Jobs that were running when
+// the node stopped are failed on startup, as the handlers cannot resume part way through.
+type jobManager struct {
+	ctx            context.Context
+	cancelCtx      context.CancelFunc
+	namespace      string
+	database       database.Plugin
+	outputDir      string
+	handlers       map[core.JobType]*Handler
+	workers        chan struct{}
+	wake           chan struct{}
+	mux            sync.Mutex
+	running        map[fftypes.UUID]*jobRun
+	runsDone       sync.WaitGroup
+	dispatcherDone chan struct{}
+}
+
+// jobRun is a job being performed by a worker
+type jobRun struct {
+	jm           *jobManager
+	ctx          context.Context
+	cancelCtx    context.CancelFunc
+	job          *core.Job
+	cancelled    bool
+	lastProgress time.Time
+}
+
+func NewJobManager(ctx context.Context, ns string, di database.Plugin) (Manager, error) {
+	if di == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "JobManager")
+	}
+	workers := config.GetInt(coreconfig.JobsWorkers)
+	if workers <= 0 {
+		workers = 1
+	}
+	outputDir := config.GetString(coreconfig.JobsOutputDirectory)
+	if outputDir == "" {
+		outputDir = filepath.Join(os.TempDir(), "firefly-jobs")
+	}
+	jm := &jobManager{
+		namespace: ns,
+		database:  di,
+		outputDir: filepath.Join(outputDir, ns),
+		handlers:  make(map[core.JobType]*Handler),
+		workers:   make(chan struct{}, workers),
+		wake:      make(chan struct{}, 1),
+		running:   make(map[fftypes.UUID]*jobRun),
+	}
+	jm.ctx, jm.cancelCtx = context.WithCancel(ctx)
+	return jm, nil
+}
+
+func (jm *jobManager) RegisterHandler(jobType core.JobType, handler *Handler) {
+	jm.handlers[jobType] = handler
+	log.L(jm.ctx).Debugf("Registered handler for job type '%s'", jobType)
+}
+
+func (jm *jobManager) Start() error {
+	if err := jm.failInterruptedJobs(); err != nil {
+		return err
+	}
+	jm.dispatcherDone = make(chan struct{})
+	go jm.dispatcher()
+	return nil
+}
+
+func (jm *jobManager) WaitStop() {
+	jm.cancelCtx()
+	if jm.dispatcherDone != nil {
+		<-jm.dispatcherDone
+	}
+	jm.runsDone.Wait()
+}
+
+func (jm *jobManager) failInterruptedJobs() error {
+	fb := database.JobQueryFactory.NewFilter(jm.ctx)
+	jobs, _, err := jm.database.GetJobs(jm.ctx, jm.namespace, fb.And(fb.Eq("status", core.JobStatusRunning)))
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		log.L(jm.ctx).Warnf("Job %s of type '%s' was interrupted", job.ID, job.Type)
+		update := database.JobQueryFactory.NewUpdate(jm.ctx).
+			Set("status", core.JobStatusFailed).
+			Set("error", i18n.NewError(jm.ctx, coremsgs.MsgJobInterrupted).Error())
+		if err := jm.database.UpdateJob(jm.ctx, jm.namespace, job.ID, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (jm *jobManager) notify() {
+	select {
+	case jm.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (jm *jobManager) dispatcher() {
+	defer close(jm.dispatcherDone)
+	for {
+		// Wait for a free worker
+		select {
+		case jm.workers <- struct{}{}:
+		case <-jm.ctx.Done():
+			return
+		}
+
+		run, err := jm.nextJob()
+		if run != nil {
+			jm.runsDone.Add(1)
+			go jm.runJob(run)
+			continue
+		}
+
+		// Wait for a new job to be submitted, or to retry after an error
+		<-jm.workers
+		var retry <-chan time.Time
+		if err != nil {
+			log.L(jm.ctx).Errorf("Failed to find the next job: %s", err)
+			retry = time.After(dispatchRetryDelay)
+		}
+		select {
+		case <-jm.wake:
+		case <-retry:
+		case <-jm.ctx.Done():
+			return
+		}
+	}
+}
+
+// nextJob marks the oldest pending job as running
+func (jm *jobManager) nextJob() (*jobRun, error) {
+	jm.mux.Lock()
+	defer jm.mux.Unlock()
+
+	fb := database.JobQueryFactory.NewFilter(jm.ctx)
+	filter := fb.And(fb.Eq("status", core.JobStatusPending)).Sort("created").Ascending().Limit(1)
+	jobs, _, err := jm.database.GetJobs(jm.ctx, jm.namespace, filter)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	job := jobs[0]
+	update := database.JobQueryFactory.NewUpdate(jm.ctx).Set("status", core.JobStatusRunning)
+	if err := jm.database.UpdateJob(jm.ctx, jm.namespace, job.ID, update); err != nil {
+		return nil, err
+	}
+	job.Status = core.JobStatusRunning
+
+	run := &jobRun{jm: jm, job: job}
+	run.ctx, run.cancelCtx = context.WithCancel(log.WithLogField(jm.ctx, "job", job.ID.String()))
+	jm.running[*job.ID] = run
+	return run, nil
+}
+
+func (jm *jobManager) parseInput(ctx context.Context, jobType core.JobType, input fftypes.JSONObject) (interface{}, *Handler, error) {
+	handler, ok := jm.handlers[jobType]
+	if !ok {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgUnknownJobType, jobType)
+	}
+	parsed := handler.NewInput()
+	b, _ := json.Marshal(input)
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(parsed); err != nil {
+		return nil, nil, i18n.NewError(ctx, coremsgs.MsgJobInvalidInput, jobType, err)
+	}
+	return parsed, handler, nil
+}
+
+func (jm *jobManager) runJob(run *jobRun) {
+	defer jm.runsDone.Done()
+	defer func() { <-jm.workers }()
+	defer run.cancelCtx()
+
+	log.L(run.ctx).Infof("Running job of type '%s'", run.job.Type)
+	var output interface{}
+	input, handler, err := jm.parseInput(run.ctx, run.job.Type, run.job.Input)
+	if err == nil {
+		output, err = handler.Run(run.ctx, input, run)
+	}
+
+	jm.mux.Lock()
+	delete(jm.running, *run.job.ID)
+	cancelled := run.cancelled
+	jm.mux.Unlock()
+
+	if jm.ctx.Err() != nil {
+		// The job is left as running, and failed when the node next starts
+		log.L(run.ctx).Warnf("Job interrupted by shutdown")
+		return
+	}
+
+	update := database.JobQueryFactory.NewUpdate(jm.ctx).
+		Set("progress.done", run.job.Progress.Done).
+		Set("progress.total", run.job.Progress.Total)
+	switch {
+	case cancelled:
+		log.L(run.ctx).Infof("Job cancelled")
+		update.Set("status", core.JobStatusCancelled)
+	case err != nil:
+		log.L(run.ctx).Errorf("Job failed: %s", err)
+		update.Set("status", core.JobStatusFailed).Set("error", err.Error())
+	default:
+		log.L(run.ctx).Infof("Job succeeded")
+		var jsonOutput fftypes.JSONObject
+		b, _ := json.Marshal(output)
+		_ = json.Unmarshal(b, &jsonOutput)
+		update.Set("status", core.JobStatusSucceeded).Set("output", jsonOutput)
+	}
+	if err := jm.database.UpdateJob(jm.ctx, jm.namespace, run.job.ID, update); err != nil {
+		log.L(run.ctx).Errorf("Failed to record the completion of the job: %s", err)
+	}
+}
+
+func (run *jobRun) Progress(done, total int64) {
+	run.job.Progress = core.JobProgress{Done: done, Total: total}
+	if time.Since(run.lastProgress) < progressUpdateInterval {
+		return
+	}
+	run.lastProgress = time.Now()
+	update := database.JobQueryFactory.NewUpdate(run.ctx).
+		Set("progress.done", done).
+		Set("progress.total", total)
+	if err := run.jm.database.UpdateJob(run.ctx, run.jm.namespace, run.job.ID, update); err != nil {
+		log.L(run.ctx).Warnf("Failed to record the progress of the job: %s", err)
+	}
+}
+
+// progressReader reports the bytes read as the progress of a job, and stops reading when the job is cancelled
+type progressReader struct {
+	run    *jobRun
+	reader io.Reader
+	done   int64
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	if err := pr.run.ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := pr.reader.Read(p)
+	pr.done += int64(n)
+	pr.run.Progress(pr.done, 0)
+	return n, err
+}
+
+func (run *jobRun) WriteFile(ext string, reader io.Reader) (*OutputFile, error) {
+	if err := os.MkdirAll(run.jm.outputDir, 0700); err != nil {
+		return nil, err
+	}
+	name := run.job.ID.String() + "." + ext
+	path := filepath.Join(run.jm.outputDir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	size, err := io.Copy(f, &progressReader{run: run, reader: reader})
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+	return &OutputFile{File: name, Size: size}, nil
+}
+
+func (jm *jobManager) SubmitJob(ctx context.Context, input *core.JobInput) (*core.Job, error) {
+	if _, _, err := jm.parseInput(ctx, input.Type, input.Input); err != nil {
+		return nil, err
+	}
+	job := &core.Job{
+		ID:        fftypes.NewUUID(),
+		Namespace: jm.namespace,
+		Type:      input.Type,
+		Status:    core.JobStatusPending,
+		Input:     input.Input,
+	}
+	if err := jm.database.InsertJob(ctx, job); err != nil {
+		return nil, err
+	}
+	jm.notify()
+	return job, nil
+}
+
+func (jm *jobManager) GetJobByID(ctx context.Context, id string) (*core.Job, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return jm.database.GetJobByID(ctx, jm.namespace, u)
+}
+
+func (jm *jobManager) getJobByIDNotNil(ctx context.Context, id string) (*core.Job, error) {
+	job, err := jm.GetJobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return job, nil
+}
+
+func (jm *jobManager) GetJobs(ctx context.Context, filter ffapi.AndFilter) ([]*core.Job, *ffapi.FilterResult, error) {
+	return jm.database.GetJobs(ctx, jm.namespace, filter)
+}
+
+// CancelJob cancels a pending job straight away. A running job is cancelled by its handler returning,
+// so it remains running until the handler next checks its context.
+func (jm *jobManager) CancelJob(ctx context.Context, id string) (*core.Job, error) {
+	jm.mux.Lock()
+	defer jm.mux.Unlock()
+
+	job, err := jm.getJobByIDNotNil(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run, ok := jm.running[*job.ID]; ok {
+		run.cancelled = true
+		run.cancelCtx()
+		return job, nil
+	}
+	if job.IsComplete() {
+		return nil, i18n.NewError(ctx, coremsgs.MsgJobAlreadyComplete, job.ID, job.Status)
+	}
+	update := database.JobQueryFactory.NewUpdate(ctx).Set("status", core.JobStatusCancelled)
+	if err := jm.database.UpdateJob(ctx, jm.namespace, job.ID, update); err != nil {
+		return nil, err
+	}
+	job.Status = core.JobStatusCancelled
+	return job, nil
+}
+
+func (jm *jobManager) GetJobOutputFile(ctx context.Context, id string) (reader io.ReadCloser, name string, err error) {
+	job, err := jm.getJobByIDNotNil(ctx, id)
+	if err != nil {
+		return nil, "", err
+	}
+	if job.Status == core.JobStatusSucceeded && job.Output != nil {
+		name, _ = job.Output["file"].(string)
+	}
+	if name == "" {
+		return nil, "", i18n.NewError(ctx, coremsgs.MsgJobNoOutputFile, job.ID)
+	}
+	f, err := os.Open(filepath.Join(jm.outputDir, filepath.Base(name)))
+	if err != nil {
+		return nil, "", err
+	}
+	return f, name, nil
+}
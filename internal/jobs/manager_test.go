@@ -0,0 +1,432 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testInput struct {
+	Value string `json:"value"`
+}
+
+func newTestJobManager(t *testing.T) (*jobManager, *databasemocks.Plugin) {
+	coreconfig.Reset()
+	config.Set(coreconfig.JobsWorkers, 1)
+	config.Set(coreconfig.JobsOutputDirectory, t.TempDir())
+
+	mdi := &databasemocks.Plugin{}
+	jm, err := NewJobManager(context.Background(), "ns1", mdi)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		jm.WaitStop()
+		mdi.AssertExpectations(t)
+	})
+	return jm.(*jobManager), mdi
+}
+
+func testHandler(run func(ctx context.Context, input *testInput, runner Runner) (interface{}, error)) *Handler {
+	return &Handler{
+		NewInput: func() interface{} { return &testInput{} },
+		Run: func(ctx context.Context, input interface{}, runner Runner) (interface{}, error) {
+			return run(ctx, input.(*testInput), runner)
+		},
+	}
+}
+
+func newTestJob(jobType core.JobType) *core.Job {
+	return &core.Job{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      jobType,
+		Status:    core.JobStatusPending,
+		Input:     fftypes.JSONObject{"value": "test"},
+	}
+}
+
+// newTestRun starts a run of a job, as the dispatcher does when a worker is free
+func newTestRun(jm *jobManager, job *core.Job) *jobRun {
+	jm.workers <- struct{}{}
+	run := &jobRun{jm: jm, job: job}
+	run.ctx, run.cancelCtx = context.WithCancel(jm.ctx)
+	jm.running[*job.ID] = run
+	return run
+}
+
+func updateHas(update ffapi.Update, field, value string) bool {
+	info, _ := update.Finalize()
+	for _, op := range info.SetOperations {
+		v, _ := op.Value.Value()
+		if op.Field == field && strings.Contains(fmt.Sprint(v), value) {
+			return true
+		}
+	}
+	return false
+}
+
+func updateSets(field, value string) interface{} {
+	return mock.MatchedBy(func(update ffapi.Update) bool {
+		return updateHas(update, field, value)
+	})
+}
+
+func TestNewJobManagerMissingDeps(t *testing.T) {
+	_, err := NewJobManager(context.Background(), "ns1", nil)
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestNewJobManagerDefaults(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.JobsWorkers, 0)
+	jm, err := NewJobManager(context.Background(), "ns1", &databasemocks.Plugin{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cap(jm.(*jobManager).workers))
+	assert.Equal(t, filepath.Join(os.TempDir(), "firefly-jobs", "ns1"), jm.(*jobManager).outputDir)
+}
+
+func TestJobE2E(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	jm.RegisterHandler(core.JobTypeTokenTransfersExport, testHandler(func(ctx context.Context, input *testInput, runner Runner) (interface{}, error) {
+		assert.Equal(t, "test", input.Value)
+		runner.Progress(0, 1)
+		return runner.WriteFile("csv", strings.NewReader("a,b,c"))
+	}))
+
+	var job *core.Job
+	mdi.On("InsertJob", mock.Anything, mock.MatchedBy(func(j *core.Job) bool {
+		job = j
+		return j.Type == core.JobTypeTokenTransfersExport && j.Status == core.JobStatusPending
+	})).Return(nil)
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return([]*core.Job{}, nil, nil).Once()
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return(func(ctx context.Context, ns string, filter ffapi.Filter) []*core.Job {
+		return []*core.Job{job}
+	}, nil, nil).Once()
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return([]*core.Job{}, nil, nil)
+	mdi.On("UpdateJob", mock.Anything, "ns1", mock.Anything, updateSets("status", "running")).Return(nil)
+	mdi.On("UpdateJob", mock.Anything, "ns1", mock.Anything, updateSets("progress.total", "1")).Return(nil)
+	succeeded := make(chan struct{})
+	mdi.On("UpdateJob", mock.Anything, "ns1", mock.Anything, updateSets("status", "succeeded")).Return(nil).Run(func(args mock.Arguments) {
+		close(succeeded)
+	})
+
+	err := jm.Start()
+	assert.NoError(t, err)
+
+	submitted, err := jm.SubmitJob(context.Background(), &core.JobInput{
+		Type:  core.JobTypeTokenTransfersExport,
+		Input: fftypes.JSONObject{"value": "test"},
+	})
+	assert.NoError(t, err)
+	<-succeeded
+
+	mdi.On("GetJobByID", mock.Anything, "ns1", submitted.ID).Return(&core.Job{
+		ID:     submitted.ID,
+		Status: core.JobStatusSucceeded,
+		Output: fftypes.JSONObject{"file": submitted.ID.String() + ".csv", "size": 5},
+	}, nil)
+	reader, name, err := jm.GetJobOutputFile(context.Background(), submitted.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, submitted.ID.String()+".csv", name)
+	b, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b,c", string(b))
+	reader.Close()
+}
+
+func TestStartFailInterruptedJobs(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	job.Status = core.JobStatusRunning
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return([]*core.Job{job}, nil, nil).Once()
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, updateSets("error", "FF10602")).Return(nil)
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return([]*core.Job{}, nil, nil)
+
+	err := jm.Start()
+	assert.NoError(t, err)
+}
+
+func TestStartGetJobsFail(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := jm.Start()
+	assert.EqualError(t, err, "pop")
+}
+
+func TestStartUpdateJobFail(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	job.Status = core.JobStatusRunning
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return([]*core.Job{job}, nil, nil)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := jm.Start()
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDispatcherGetJobsFail(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	failed := make(chan struct{})
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		close(failed)
+	}).Once()
+
+	jm.dispatcherDone = make(chan struct{})
+	go jm.dispatcher()
+	<-failed
+}
+
+func TestNextJobUpdateFail(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return([]*core.Job{job}, nil, nil)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	run, err := jm.nextJob()
+	assert.EqualError(t, err, "pop")
+	assert.Nil(t, run)
+}
+
+func TestRunJobFailed(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	jm.RegisterHandler(core.JobTypeTokenPoolSnapshot, testHandler(func(ctx context.Context, input *testInput, runner Runner) (interface{}, error) {
+		return nil, fmt.Errorf("pop")
+	}))
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, mock.MatchedBy(func(update ffapi.Update) bool {
+		return updateHas(update, "status", "failed") && updateHas(update, "error", "pop")
+	})).Return(nil)
+
+	jm.runsDone.Add(1)
+	jm.runJob(newTestRun(jm, job))
+	assert.Empty(t, jm.running)
+}
+
+func TestRunJobUnknownType(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, updateSets("error", "FF10599")).Return(fmt.Errorf("pop"))
+
+	jm.runsDone.Add(1)
+	jm.runJob(newTestRun(jm, job))
+}
+
+func TestRunJobCancelled(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	started := make(chan struct{})
+	jm.RegisterHandler(core.JobTypeTokenTransfersExport, testHandler(func(ctx context.Context, input *testInput, runner Runner) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return runner.WriteFile("csv", strings.NewReader("a,b,c"))
+	}))
+	job := newTestJob(core.JobTypeTokenTransfersExport)
+	job.Status = core.JobStatusRunning
+	mdi.On("GetJobByID", mock.Anything, "ns1", job.ID).Return(job, nil)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, updateSets("status", "cancelled")).Return(nil)
+
+	run := newTestRun(jm, job)
+	jm.runsDone.Add(1)
+	go jm.runJob(run)
+	<-started
+	cancelled, err := jm.CancelJob(context.Background(), job.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.JobStatusRunning, cancelled.Status)
+	jm.runsDone.Wait()
+
+	_, err = os.Stat(filepath.Join(jm.outputDir, job.ID.String()+".csv"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRunJobShutdown(t *testing.T) {
+	jm, _ := newTestJobManager(t)
+	jm.RegisterHandler(core.JobTypeTokenPoolSnapshot, testHandler(func(ctx context.Context, input *testInput, runner Runner) (interface{}, error) {
+		jm.cancelCtx()
+		return nil, ctx.Err()
+	}))
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+
+	jm.runsDone.Add(1)
+	jm.runJob(newTestRun(jm, job))
+}
+
+func TestProgressUpdateFail(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, mock.Anything).Return(fmt.Errorf("pop")).Once()
+
+	run := newTestRun(jm, job)
+	run.Progress(1, 10)
+	run.Progress(2, 10)
+	assert.Equal(t, core.JobProgress{Done: 2, Total: 10}, job.Progress)
+}
+
+func TestWriteFileMkdirFail(t *testing.T) {
+	jm, _ := newTestJobManager(t)
+	file := filepath.Join(t.TempDir(), "file")
+	err := os.WriteFile(file, []byte{}, 0600)
+	assert.NoError(t, err)
+	jm.outputDir = filepath.Join(file, "ns1")
+
+	_, err = newTestRun(jm, newTestJob(core.JobTypeTokenTransfersExport)).WriteFile("csv", strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestWriteFileCreateFail(t *testing.T) {
+	jm, _ := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenTransfersExport)
+	err := os.MkdirAll(filepath.Join(jm.outputDir, job.ID.String()+".csv"), 0700)
+	assert.NoError(t, err)
+
+	_, err = newTestRun(jm, job).WriteFile("csv", strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestWriteFileReadFail(t *testing.T) {
+	jm, _ := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenTransfersExport)
+
+	run := newTestRun(jm, job)
+	run.lastProgress = time.Now()
+	_, err := run.WriteFile("csv", iotest.ErrReader(fmt.Errorf("pop")))
+	assert.EqualError(t, err, "pop")
+	_, err = os.Stat(filepath.Join(jm.outputDir, job.ID.String()+".csv"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestSubmitJobUnknownType(t *testing.T) {
+	jm, _ := newTestJobManager(t)
+	_, err := jm.SubmitJob(context.Background(), &core.JobInput{Type: "unknown"})
+	assert.Regexp(t, "FF10599", err)
+}
+
+func TestSubmitJobInvalidInput(t *testing.T) {
+	jm, _ := newTestJobManager(t)
+	jm.RegisterHandler(core.JobTypeTokenPoolSnapshot, testHandler(nil))
+	_, err := jm.SubmitJob(context.Background(), &core.JobInput{
+		Type:  core.JobTypeTokenPoolSnapshot,
+		Input: fftypes.JSONObject{"wrong": "field"},
+	})
+	assert.Regexp(t, "FF10600", err)
+}
+
+func TestSubmitJobInsertFail(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	jm.RegisterHandler(core.JobTypeTokenPoolSnapshot, testHandler(nil))
+	mdi.On("InsertJob", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	_, err := jm.SubmitJob(context.Background(), &core.JobInput{Type: core.JobTypeTokenPoolSnapshot})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetJobs(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	mdi.On("GetJobs", mock.Anything, "ns1", mock.Anything).Return([]*core.Job{}, nil, nil)
+	jobs, _, err := jm.GetJobs(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, jobs)
+}
+
+func TestCancelPendingJob(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	mdi.On("GetJobByID", mock.Anything, "ns1", job.ID).Return(job, nil)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, updateSets("status", "cancelled")).Return(nil)
+
+	cancelled, err := jm.CancelJob(context.Background(), job.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, core.JobStatusCancelled, cancelled.Status)
+}
+
+func TestCancelJobUpdateFail(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	mdi.On("GetJobByID", mock.Anything, "ns1", job.ID).Return(job, nil)
+	mdi.On("UpdateJob", mock.Anything, "ns1", job.ID, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := jm.CancelJob(context.Background(), job.ID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCancelCompleteJob(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	job.Status = core.JobStatusSucceeded
+	mdi.On("GetJobByID", mock.Anything, "ns1", job.ID).Return(job, nil)
+
+	_, err := jm.CancelJob(context.Background(), job.ID.String())
+	assert.Regexp(t, "FF10601", err)
+}
+
+func TestCancelJobNotFound(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	mdi.On("GetJobByID", mock.Anything, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := jm.CancelJob(context.Background(), fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestCancelJobBadID(t *testing.T) {
+	jm, _ := newTestJobManager(t)
+	_, err := jm.CancelJob(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetJobOutputFileNotFound(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	mdi.On("GetJobByID", mock.Anything, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := jm.GetJobOutputFile(context.Background(), fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetJobOutputFileNoFile(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenPoolSnapshot)
+	job.Status = core.JobStatusSucceeded
+	job.Output = fftypes.JSONObject{"id": "snapshot1"}
+	mdi.On("GetJobByID", mock.Anything, "ns1", job.ID).Return(job, nil)
+
+	_, _, err := jm.GetJobOutputFile(context.Background(), job.ID.String())
+	assert.Regexp(t, "FF10603", err)
+}
+
+func TestGetJobOutputFileMissing(t *testing.T) {
+	jm, mdi := newTestJobManager(t)
+	job := newTestJob(core.JobTypeTokenTransfersExport)
+	job.Status = core.JobStatusSucceeded
+	job.Output = fftypes.JSONObject{"file": "../missing.csv"}
+	mdi.On("GetJobByID", mock.Anything, "ns1", job.ID).Return(job, nil)
+
+	_, _, err := jm.GetJobOutputFile(context.Background(), job.ID.String())
+	assert.True(t, os.IsNotExist(err))
+}
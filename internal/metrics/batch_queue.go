@@ -0,0 +1,66 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var BatchQueueDepthGauge *prometheus.GaugeVec
+var BatchQueueOldestAgeGauge *prometheus.GaugeVec
+var BatchDispatcherLagGauge *prometheus.GaugeVec
+var BatchBackpressureRejectedCounter *prometheus.CounterVec
+
+// BatchQueueDepthGaugeName is the prometheus metric for tracking the messages read for batching, and not yet sent, on each topic
+var BatchQueueDepthGaugeName = "ff_batch_queue_depth"
+
+// BatchQueueOldestAgeGaugeName is the prometheus metric for tracking the age of the oldest unsent message on each topic
+var BatchQueueOldestAgeGaugeName = "ff_batch_queue_oldest_age_seconds"
+
+// BatchDispatcherLagGaugeName is the prometheus metric for tracking the time messages wait to be read for batching
+var BatchDispatcherLagGaugeName = "ff_batch_dispatcher_lag_seconds"
+
+// BatchBackpressureRejectedCounterName is the prometheus metric for tracking the total number of sends rejected by backpressure
+var BatchBackpressureRejectedCounterName = "ff_batch_backpressure_rejected_total"
+
+var TopicLabelName = "topic"
+
+func InitBatchQueueMetrics() {
+	BatchQueueDepthGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: BatchQueueDepthGaugeName,
+		Help: "Number of messages read for batching on a topic, that have not yet been sent",
+	}, []string{NamespaceLabelName, TopicLabelName})
+	BatchQueueOldestAgeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: BatchQueueOldestAgeGaugeName,
+		Help: "Age of the oldest message read for batching on a topic, that has not yet been sent",
+	}, []string{NamespaceLabelName, TopicLabelName})
+	BatchDispatcherLagGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: BatchDispatcherLagGaugeName,
+		Help: "Time between the most recently dispatched message being ready to send, and it being read for batching",
+	}, []string{NamespaceLabelName})
+	BatchBackpressureRejectedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: BatchBackpressureRejectedCounterName,
+		Help: "Number of message sends rejected because the batch queue of a topic exceeded its thresholds",
+	}, []string{NamespaceLabelName, TopicLabelName})
+}
+
+func RegisterBatchQueueMetrics() {
+	registry.MustRegister(BatchQueueDepthGauge)
+	registry.MustRegister(BatchQueueOldestAgeGauge)
+	registry.MustRegister(BatchDispatcherLagGauge)
+	registry.MustRegister(BatchBackpressureRejectedCounter)
+}
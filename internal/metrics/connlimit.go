@@ -0,0 +1,48 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var ConnectionsActiveGauge *prometheus.GaugeVec
+var ConnectionsShedCounter *prometheus.CounterVec
+
+// ConnectionsActiveGaugeName is the prometheus metric for tracking the concurrent HTTP requests and WebSocket connections counted against the connection limits
+var ConnectionsActiveGaugeName = "ff_apiserver_connections_active"
+
+// ConnectionsShedCounterName is the prometheus metric for tracking the total number of HTTP requests and WebSocket connections shed by the connection limits
+var ConnectionsShedCounterName = "ff_apiserver_connections_shed_total"
+
+var TypeLabelName = "type"
+
+func InitConnLimitMetrics() {
+	ConnectionsActiveGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: ConnectionsActiveGaugeName,
+		Help: "Number of concurrent HTTP requests and WebSocket connections counted against the connection limits",
+	}, []string{TypeLabelName, ScopeLabelName, KeyLabelName})
+	ConnectionsShedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: ConnectionsShedCounterName,
+		Help: "Number of HTTP requests and WebSocket connections shed by the connection limits",
+	}, []string{TypeLabelName, ScopeLabelName, KeyLabelName})
+}
+
+func RegisterConnLimitMetrics() {
+	registry.MustRegister(ConnectionsActiveGauge)
+	registry.MustRegister(ConnectionsShedCounter)
+}
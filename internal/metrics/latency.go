@@ -0,0 +1,47 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var RouteLatencyHistogram *prometheus.HistogramVec
+
+// RouteLatencyHistogramName is the prometheus metric for tracking the latency of the API and SPI routes, for each namespace
+var RouteLatencyHistogramName = "ff_apiserver_route_latency_seconds"
+
+// TraceIDExemplarLabelName is the label of the exemplars of the latency histogram, holding the trace ID of the request
+var TraceIDExemplarLabelName = "trace_id"
+
+var ServerLabelName = "server"
+var HTTPMethodLabelName = "method"
+var RouteLabelName = "route"
+var NamespaceLabelName = "namespace"
+var CodeLabelName = "code"
+
+func InitLatencyMetrics() {
+	RouteLatencyHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    RouteLatencyHistogramName,
+		Help:    "Latency of the API and SPI routes, for each namespace and status code",
+		Buckets: prometheus.DefBuckets,
+	}, []string{ServerLabelName, HTTPMethodLabelName, RouteLabelName, NamespaceLabelName, CodeLabelName})
+}
+
+func RegisterLatencyMetrics() {
+	registry.MustRegister(RouteLatencyHistogram)
+}
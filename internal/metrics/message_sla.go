@@ -0,0 +1,40 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var MessageConfirmLatencySummary *prometheus.SummaryVec
+
+// MessageConfirmLatencySummaryName is the prometheus metric for tracking the time taken for messages sent by this node to be confirmed, for each org
+var MessageConfirmLatencySummaryName = "ff_message_confirm_latency_seconds"
+
+var OrgLabelName = "org"
+
+func InitMessageSLAMetrics() {
+	MessageConfirmLatencySummary = prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Name:       MessageConfirmLatencySummaryName,
+		Help:       "Time from sending a message to it being confirmed by this node, or receipted by the node of another org",
+		Objectives: map[float64]float64{0.5: 0.05, 0.9: 0.01, 0.99: 0.001},
+	}, []string{NamespaceLabelName, OrgLabelName})
+}
+
+func RegisterMessageSLAMetrics() {
+	registry.MustRegister(MessageConfirmLatencySummary)
+}
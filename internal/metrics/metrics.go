@@ -33,6 +33,7 @@ type Manager interface {
 	CountBatchPin()
 	MessageSubmitted(msg *core.Message)
 	MessageConfirmed(msg *core.Message, eventType fftypes.FFEnum)
+	MessageConfirmLatency(namespace, org string, latency time.Duration)
 	TransferSubmitted(transfer *core.TokenTransfer)
 	TransferConfirmed(transfer *core.TokenTransfer)
 	BlockchainContractDeployment()
@@ -99,6 +100,10 @@ func (mm *metricsManager) MessageConfirmed(msg *core.Message, eventType fftypes.
 	}
 }
 
+func (mm *metricsManager) MessageConfirmLatency(namespace, org string, latency time.Duration) {
+	MessageConfirmLatencySummary.WithLabelValues(namespace, org).Observe(latency.Seconds())
+}
+
 func (mm *metricsManager) TransferSubmitted(transfer *core.TokenTransfer) {
 	if len(transfer.LocalID.String()) > 0 {
 		switch transfer.Type {
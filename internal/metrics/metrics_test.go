@@ -119,6 +119,15 @@ func TestMessageConfirmedPrivateRejected(t *testing.T) {
 	assert.Equal(t, len(mm.timeMap), 0)
 }
 
+func TestMessageConfirmLatency(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.MessageConfirmLatency("ns1", "org1", 2*time.Second)
+	m, err := MessageConfirmLatencySummary.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", OrgLabelName: "org1"})
+	assert.NoError(t, err)
+	assert.NotNil(t, m)
+}
+
 func TestTokenSubmittedMint(t *testing.T) {
 	mm, cancel := newTestMetricsManager(t)
 	defer cancel()
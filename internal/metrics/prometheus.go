@@ -88,6 +88,11 @@ func initMetricsCollectors() {
 	InitTokenBurnMetrics()
 	InitBatchPinMetrics()
 	InitBlockchainMetrics()
+	InitRateLimitMetrics()
+	InitConnLimitMetrics()
+	InitLatencyMetrics()
+	InitMessageSLAMetrics()
+	InitBatchQueueMetrics()
 }
 
 func registerMetricsCollectors() {
@@ -101,4 +106,9 @@ func registerMetricsCollectors() {
 	RegisterTokenTransferMetrics()
 	RegisterTokenBurnMetrics()
 	RegisterBlockchainMetrics()
+	RegisterRateLimitMetrics()
+	RegisterConnLimitMetrics()
+	RegisterLatencyMetrics()
+	RegisterMessageSLAMetrics()
+	RegisterBatchQueueMetrics()
 }
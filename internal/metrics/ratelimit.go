@@ -0,0 +1,50 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var RateLimitRejectedCounter *prometheus.CounterVec
+var QuotaUsageGauge *prometheus.GaugeVec
+
+// RateLimitRejectedCounterName is the prometheus metric for tracking the total number of API requests rejected by rate limits and quotas
+var RateLimitRejectedCounterName = "ff_apiserver_ratelimit_rejected_total"
+
+// QuotaUsageGaugeName is the prometheus metric for tracking the API requests made today against the daily quotas of a scope
+var QuotaUsageGaugeName = "ff_apiserver_quota_used"
+
+var ScopeLabelName = "scope"
+var KeyLabelName = "key"
+var ReasonLabelName = "reason"
+
+func InitRateLimitMetrics() {
+	RateLimitRejectedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: RateLimitRejectedCounterName,
+		Help: "Number of API requests rejected by rate limits and daily quotas",
+	}, []string{ScopeLabelName, ReasonLabelName})
+	QuotaUsageGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: QuotaUsageGaugeName,
+		Help: "Number of API requests made in the current UTC day, counted against the daily quotas of all keys in the scope",
+	}, []string{ScopeLabelName})
+}
+
+func RegisterRateLimitMetrics() {
+	registry.MustRegister(RateLimitRejectedCounter)
+	registry.MustRegister(QuotaUsageGauge)
+}
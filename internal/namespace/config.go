@@ -54,9 +54,11 @@ var (
 func InitConfig() {
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceName)
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceDescription)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceTenant)
 	namespacePredefined.AddKnownKey(coreconfig.NamespacePlugins)
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceDefaultKey)
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceAssetKeyNormalization)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceConfirmations)
 
 	multipartyConf := namespacePredefined.SubSection(coreconfig.NamespaceMultiparty)
 	multipartyConf.AddKnownKey(coreconfig.NamespaceMultipartyEnabled)
@@ -71,12 +73,49 @@ func InitConfig() {
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractFirstEvent, string(core.SubOptsFirstEventOldest))
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractLocation)
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractOptions)
+	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractChainID)
 
 	tlsConfigs := namespacePredefined.SubArray(coreconfig.NamespaceTLSConfigs)
 	tlsConfigs.AddKnownKey(coreconfig.NamespaceTLSConfigName)
 	tlsConf := tlsConfigs.SubSection(coreconfig.NamespaceTLSConfigTLSSection)
 	fftls.InitTLSConfig(tlsConf)
 
+	bridges := namespacePredefined.SubArray(coreconfig.NamespaceBridges)
+	bridges.AddKnownKey(coreconfig.NamespaceBridgeName)
+	bridges.AddKnownKey(coreconfig.NamespaceBridgeTarget)
+	bridges.AddKnownKey(coreconfig.NamespaceBridgeFirstEvent, string(core.SubOptsFirstEventNewest))
+	bridges.AddKnownKey(coreconfig.NamespaceBridgeMembers)
+	bridgeFilter := bridges.SubSection(coreconfig.NamespaceBridgeFilter)
+	bridgeFilter.AddKnownKey(coreconfig.NamespaceBridgeFilterTopics)
+	bridgeFilter.AddKnownKey(coreconfig.NamespaceBridgeFilterTag)
+	bridgeFilter.AddKnownKey(coreconfig.NamespaceBridgeFilterAuthor)
+	bridgeFilter.AddKnownKey(coreconfig.NamespaceBridgeFilterIncludePrivate, false)
+	topicMap := bridges.SubArray(coreconfig.NamespaceBridgeTopicMap)
+	topicMap.AddKnownKey(coreconfig.NamespaceBridgeMapSource)
+	topicMap.AddKnownKey(coreconfig.NamespaceBridgeMapTarget)
+	identityMap := bridges.SubArray(coreconfig.NamespaceBridgeIdentityMap)
+	identityMap.AddKnownKey(coreconfig.NamespaceBridgeMapSource)
+	identityMap.AddKnownKey(coreconfig.NamespaceBridgeMapTarget)
+
+	slaConf := namespacePredefined.SubSection(coreconfig.NamespaceSLA)
+	slaConf.AddKnownKey(coreconfig.NamespaceSLAConfirmTime, "0")
+	slaTopics := slaConf.SubArray(coreconfig.NamespaceSLATopics)
+	slaTopics.AddKnownKey(coreconfig.NamespaceSLATopic)
+	slaTopics.AddKnownKey(coreconfig.NamespaceSLAConfirmTime)
+
+	deferredConfirm := namespacePredefined.SubSection(coreconfig.NamespaceDeferredConfirm)
+	deferredConfirm.AddKnownKey(coreconfig.NamespaceDeferredConfirmEnabled, false)
+	deferredConfirm.AddKnownKey(coreconfig.NamespaceDeferredConfirmTopics)
+
+	transforms := namespacePredefined.SubArray(coreconfig.NamespaceTransforms)
+	transforms.AddKnownKey(coreconfig.NamespaceTransformPlugin)
+	transforms.AddKnownKey(coreconfig.NamespaceTransformTopics)
+	transforms.AddKnownKey(coreconfig.NamespaceTransformOptions)
+
+	blobScanner := namespacePredefined.SubSection(coreconfig.NamespaceBlobScanner)
+	blobScanner.AddKnownKey(coreconfig.NamespaceBlobScannerPlugin)
+	blobScanner.AddKnownKey(coreconfig.NamespaceBlobScannerOptions)
+
 	bifactory.InitConfig(blockchainConfig)
 	difactory.InitConfig(databaseConfig)
 	ssfactory.InitConfig(sharedstorageConfig)
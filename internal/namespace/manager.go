@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"regexp"
 	"strconv"
 	"sync"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly/internal/blockchain/bifactory"
+	"github.com/hyperledger/firefly/internal/bridge"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
@@ -43,9 +45,11 @@ import (
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/internal/scanner/scfactory"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/spievents"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
+	"github.com/hyperledger/firefly/internal/transform"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -84,6 +88,8 @@ type namespace struct {
 	plugins      *orchestrator.Plugins
 	started      bool
 	initError    string
+	bridgeConfs  []*bridge.Config
+	bridges      []bridge.Bridge
 }
 
 type namespaceManager struct {
@@ -111,6 +117,7 @@ type namespaceManager struct {
 	identityFactory      func(ctx context.Context, pluginType string) (identity.Plugin, error)
 	eventsFactory        func(ctx context.Context, pluginType string) (events.Plugin, error)
 	authFactory          func(ctx context.Context, pluginType string) (auth.Plugin, error)
+	bridgeFactory        func(ctx context.Context, ns string, conf *bridge.Config, di database.Plugin, source orchestrator.Orchestrator, resolve bridge.OrchestratorResolver) bridge.Bridge
 }
 
 type pluginCategory string
@@ -174,6 +181,7 @@ func NewNamespaceManager() Manager {
 		identityFactory:      iifactory.GetPlugin,
 		eventsFactory:        eifactory.GetPlugin,
 		authFactory:          authfactory.GetPlugin,
+		bridgeFactory:        bridge.NewBridge,
 		nsStartupRetry: &retry.Retry{
 			InitialDelay: config.GetDuration(coreconfig.NamespacesRetryInitDelay),
 			MaximumDelay: config.GetDuration(coreconfig.NamespacesRetryMaxDelay),
@@ -309,6 +317,7 @@ func (nm *namespaceManager) namespaceStarter(ns *namespace) {
 			nm.nsMux.Lock()
 			ns.started = true
 			ns.initError = ""
+			nm.startBridges(ns)
 			nm.nsMux.Unlock()
 
 			// Notify all the event plugins of the start, so they can re-register their subs.
@@ -430,14 +439,19 @@ func (nm *namespaceManager) startNamespacesAndPlugins(namespacesToStart map[stri
 func (nm *namespaceManager) WaitStop() {
 	nm.nsMux.Lock()
 	namespaces := make(map[string]*namespace, len(nm.namespaces))
+	var bridges []bridge.Bridge
 	for k, v := range nm.namespaces {
 		namespaces[k] = v
+		bridges = append(bridges, v.bridges...)
 	}
 	nm.nsMux.Unlock()
 
 	for _, ns := range namespaces {
 		nm.stopNamespace(nm.ctx, ns)
 	}
+	for _, b := range bridges {
+		b.WaitStop()
+	}
 	nm.adminEvents.WaitStop()
 }
 
@@ -762,6 +776,9 @@ func (nm *namespaceManager) loadNamespaces(ctx context.Context, rawConfig fftype
 			return nil, err
 		}
 	}
+	if err := nm.validateBridges(ctx, newNS); err != nil {
+		return nil, err
+	}
 	// We allow startup with zero namespaces defined, so that we can have a FF Core
 	// ready to accept config updates to add new namespaces.
 	if !foundDefault && size > 0 {
@@ -798,6 +815,178 @@ func (nm *namespaceManager) loadTLSConfig(ctx context.Context, tlsConfigs map[st
 	return nil
 }
 
+func compileBridgeFilter(ctx context.Context, conf config.Section, key string) (*regexp.Regexp, error) {
+	expr := conf.GetString(key)
+	if expr == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgRegexpCompileFailed, key, expr)
+	}
+	return re, nil
+}
+
+func loadBridgeMap(conf config.ArraySection) map[string]string {
+	// Mappings are lists rather than objects, as the keys of config objects are not case sensitive
+	mapping := make(map[string]string)
+	size := conf.ArraySize()
+	for i := 0; i < size; i++ {
+		entry := conf.ArrayEntry(i)
+		mapping[entry.GetString(coreconfig.NamespaceBridgeMapSource)] = entry.GetString(coreconfig.NamespaceBridgeMapTarget)
+	}
+	return mapping
+}
+
+func (nm *namespaceManager) loadBridges(ctx context.Context, ns string, conf config.ArraySection) (bridges []*bridge.Config, err error) {
+	names := make(map[string]bool)
+	size := conf.ArraySize()
+	for i := 0; i < size; i++ {
+		entry := conf.ArrayEntry(i)
+		bc := &bridge.Config{
+			Name:           entry.GetString(coreconfig.NamespaceBridgeName),
+			Target:         entry.GetString(coreconfig.NamespaceBridgeTarget),
+			FirstEvent:     core.SubOptsFirstEvent(entry.GetString(coreconfig.NamespaceBridgeFirstEvent)),
+			IncludePrivate: entry.SubSection(coreconfig.NamespaceBridgeFilter).GetBool(coreconfig.NamespaceBridgeFilterIncludePrivate),
+			TopicMap:       loadBridgeMap(entry.SubArray(coreconfig.NamespaceBridgeTopicMap)),
+			IdentityMap:    loadBridgeMap(entry.SubArray(coreconfig.NamespaceBridgeIdentityMap)),
+			Members:        entry.GetStringSlice(coreconfig.NamespaceBridgeMembers),
+		}
+		if err := fftypes.ValidateFFNameField(ctx, bc.Name, fmt.Sprintf("namespaces.predefined[].bridges[%d].name", i)); err != nil {
+			return nil, err
+		}
+		if names[bc.Name] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgBridgeInvalid, bc.Name, ns, "duplicate name")
+		}
+		names[bc.Name] = true
+		if bc.FirstEvent != core.SubOptsFirstEventNewest && bc.FirstEvent != core.SubOptsFirstEventOldest {
+			return nil, i18n.NewError(ctx, coremsgs.MsgBridgeInvalid, bc.Name, ns, "firstEvent must be 'newest' or 'oldest'")
+		}
+		filterConf := entry.SubSection(coreconfig.NamespaceBridgeFilter)
+		if bc.Topics, err = compileBridgeFilter(ctx, filterConf, coreconfig.NamespaceBridgeFilterTopics); err != nil {
+			return nil, err
+		}
+		if bc.Tag, err = compileBridgeFilter(ctx, filterConf, coreconfig.NamespaceBridgeFilterTag); err != nil {
+			return nil, err
+		}
+		if bc.Author, err = compileBridgeFilter(ctx, filterConf, coreconfig.NamespaceBridgeFilterAuthor); err != nil {
+			return nil, err
+		}
+		bridges = append(bridges, bc)
+	}
+	return bridges, nil
+}
+
+// loadSLA returns the SLA policy of the namespace, or nil if no SLAs are configured
+func (nm *namespaceManager) loadSLA(ctx context.Context, ns string, conf config.Section) (*core.SLAPolicy, error) {
+	policy := &core.SLAPolicy{
+		ConfirmTime: conf.GetDuration(coreconfig.NamespaceSLAConfirmTime),
+		Topics:      make(map[string]time.Duration),
+	}
+	topicsConf := conf.SubArray(coreconfig.NamespaceSLATopics)
+	size := topicsConf.ArraySize()
+	for i := 0; i < size; i++ {
+		entry := topicsConf.ArrayEntry(i)
+		topic := entry.GetString(coreconfig.NamespaceSLATopic)
+		if err := fftypes.ValidateFFNameField(ctx, topic, fmt.Sprintf("namespaces.predefined[].sla.topics[%d].topic", i)); err != nil {
+			return nil, err
+		}
+		if _, exists := policy.Topics[topic]; exists {
+			return nil, i18n.NewError(ctx, coremsgs.MsgSLATopicInvalid, topic, ns, "duplicate topic")
+		}
+		policy.Topics[topic] = entry.GetDuration(coreconfig.NamespaceSLAConfirmTime)
+	}
+	if policy.ConfirmTime <= 0 && len(policy.Topics) == 0 {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// loadDeferredConfirm returns the deferred confirmation policy of the namespace, or nil if it is not enabled
+func (nm *namespaceManager) loadDeferredConfirm(ctx context.Context, conf config.Section) (*core.DeferredConfirmPolicy, error) {
+	if !conf.GetBool(coreconfig.NamespaceDeferredConfirmEnabled) {
+		return nil, nil
+	}
+	policy := &core.DeferredConfirmPolicy{
+		Topics: make(map[string]bool),
+	}
+	for i, topic := range conf.GetStringSlice(coreconfig.NamespaceDeferredConfirmTopics) {
+		if err := fftypes.ValidateFFNameField(ctx, topic, fmt.Sprintf("namespaces.predefined[].deferredConfirm.topics[%d]", i)); err != nil {
+			return nil, err
+		}
+		policy.Topics[topic] = true
+	}
+	return policy, nil
+}
+
+// loadTransforms returns the transform steps configured for the namespace, checking each plugin and its options
+func (nm *namespaceManager) loadTransforms(ctx context.Context, conf config.ArraySection) ([]*core.TransformStep, error) {
+	size := conf.ArraySize()
+	steps := make([]*core.TransformStep, 0, size)
+	for i := 0; i < size; i++ {
+		entry := conf.ArrayEntry(i)
+		steps = append(steps, &core.TransformStep{
+			Plugin:  entry.GetString(coreconfig.NamespaceTransformPlugin),
+			Topics:  entry.GetStringSlice(coreconfig.NamespaceTransformTopics),
+			Options: entry.GetObject(coreconfig.NamespaceTransformOptions),
+		})
+	}
+	if _, err := transform.NewPipeline(ctx, steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// loadBlobScanner returns the blob scanner configured for the namespace, checking its plugin and options, or nil if
+// blobs are not scanned
+func (nm *namespaceManager) loadBlobScanner(ctx context.Context, conf config.Section) (*core.BlobScannerConfig, error) {
+	plugin := conf.GetString(coreconfig.NamespaceBlobScannerPlugin)
+	if plugin == "" {
+		return nil, nil
+	}
+	scannerConf := &core.BlobScannerConfig{
+		Plugin:  plugin,
+		Options: conf.GetObject(coreconfig.NamespaceBlobScannerOptions),
+	}
+	if _, err := scfactory.NewScanner(ctx, scannerConf); err != nil {
+		return nil, err
+	}
+	return scannerConf, nil
+}
+
+// validateBridges checks the target of each bridge, once all the namespaces have been loaded
+func (nm *namespaceManager) validateBridges(ctx context.Context, namespaces map[string]*namespace) error {
+	for _, ns := range namespaces {
+		for _, bc := range ns.bridgeConfs {
+			target, ok := namespaces[bc.Target]
+			switch {
+			case bc.Target == ns.Name:
+				return i18n.NewError(ctx, coremsgs.MsgBridgeInvalid, bc.Name, ns.Name, "the target must be a different namespace")
+			case !ok:
+				return i18n.NewError(ctx, coremsgs.MsgBridgeInvalid, bc.Name, ns.Name, fmt.Sprintf("unknown target namespace '%s'", bc.Target))
+			case !target.config.Multiparty.Enabled:
+				return i18n.NewError(ctx, coremsgs.MsgBridgeInvalid, bc.Name, ns.Name, fmt.Sprintf("target namespace '%s' is not multiparty", bc.Target))
+			}
+		}
+	}
+	return nil
+}
+
+// startBridges must be called with the nsMux held, once the namespace has started.
+// The bridges stop when the context of the namespace is cancelled, but are only waited for on shutdown,
+// as stopNamespace can be called with the nsMux held, which bridges need to resolve their target.
+// A bridge that overlaps with its replacement after a config reload is safe, as the idempotency key
+// of each bridged message rejects the duplicate.
+func (nm *namespaceManager) startBridges(ns *namespace) {
+	ns.bridges = make([]bridge.Bridge, len(ns.bridgeConfs))
+	for i, bc := range ns.bridgeConfs {
+		ns.bridges[i] = nm.bridgeFactory(ns.ctx, ns.Name, bc, ns.plugins.Database.Plugin, ns.orchestrator, func(ctx context.Context, target string) (orchestrator.Orchestrator, error) {
+			return nm.Orchestrator(ctx, target, false)
+		})
+		ns.bridges[i].Start()
+	}
+}
+
 func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, index int, conf config.Section, rawNSConfig fftypes.JSONObject, availablePlugins map[string]*plugin) (ns *namespace, err error) {
 	if err := fftypes.ValidateFFNameField(ctx, name, fmt.Sprintf("namespaces.predefined[%d].name", index)); err != nil {
 		return nil, err
@@ -806,6 +995,17 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 		return nil, i18n.NewError(ctx, coremsgs.MsgFFSystemReservedName, core.LegacySystemNamespace)
 	}
 
+	tenant := conf.GetString(coreconfig.NamespaceTenant)
+	if tenant != "" {
+		if err := fftypes.ValidateFFNameField(ctx, tenant, fmt.Sprintf("namespaces.predefined[%d].tenant", index)); err != nil {
+			return nil, err
+		}
+		// Routes without a namespace in their path act on the default namespace, so it must be open to all
+		if name == config.GetString(coreconfig.NamespacesDefault) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDefaultNamespaceTenant, name, tenant)
+		}
+	}
+
 	keyNormalization := conf.GetString(coreconfig.NamespaceAssetKeyNormalization)
 	if keyNormalization == "" {
 		keyNormalization = config.GetString(coreconfig.AssetManagerKeyNormalization)
@@ -889,10 +1089,41 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 		return nil, err
 	}
 
+	confirmations := conf.GetInt(coreconfig.NamespaceConfirmations)
+	if confirmations < 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidNamespaceConfirmations, confirmations, name)
+	}
+
+	bridgeConfs, err := nm.loadBridges(ctx, name, conf.SubArray(coreconfig.NamespaceBridges))
+	if err != nil {
+		return nil, err
+	}
+
+	sla, err := nm.loadSLA(ctx, name, conf.SubSection(coreconfig.NamespaceSLA))
+	if err != nil {
+		return nil, err
+	}
+
+	transforms, err := nm.loadTransforms(ctx, conf.SubArray(coreconfig.NamespaceTransforms))
+	if err != nil {
+		return nil, err
+	}
+
+	deferredConfirm, err := nm.loadDeferredConfirm(ctx, conf.SubSection(coreconfig.NamespaceDeferredConfirm))
+	if err != nil {
+		return nil, err
+	}
+
+	blobScanner, err := nm.loadBlobScanner(ctx, conf.SubSection(coreconfig.NamespaceBlobScanner))
+	if err != nil {
+		return nil, err
+	}
+
 	config := orchestrator.Config{
 		DefaultKey:          conf.GetString(coreconfig.NamespaceDefaultKey),
 		TokenBroadcastNames: nm.tokenBroadcastNames,
 		KeyNormalization:    keyNormalization,
+		Confirmations:       confirmations,
 	}
 	if multipartyEnabled.(bool) {
 		contractsConf := multipartyConf.SubArray(coreconfig.NamespaceMultipartyContract)
@@ -907,6 +1138,7 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 				Location:   location,
 				FirstEvent: conf.GetString(coreconfig.NamespaceMultipartyContractFirstEvent),
 				Options:    options,
+				ChainID:    conf.GetString(coreconfig.NamespaceMultipartyContractChainID),
 			}
 			contracts[i] = contract
 		}
@@ -922,15 +1154,21 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 
 	ns = &namespace{
 		Namespace: core.Namespace{
-			Name:        name,
-			NetworkName: networkName,
-			Description: conf.GetString(coreconfig.NamespaceDescription),
-			TLSConfigs:  tlsConfigs,
+			Name:            name,
+			NetworkName:     networkName,
+			Description:     conf.GetString(coreconfig.NamespaceDescription),
+			Tenant:          tenant,
+			TLSConfigs:      tlsConfigs,
+			SLA:             sla,
+			Transforms:      transforms,
+			DeferredConfirm: deferredConfirm,
+			BlobScanner:     blobScanner,
 		},
 		loadTime:    fftypes.Now(),
 		config:      config,
 		configHash:  nm.configHash(rawNSConfig),
 		pluginNames: pluginNames,
+		bridgeConfs: bridgeConfs,
 	}
 	log.L(ctx).Tracef("Namespace %s config: %s", name, rawNSConfig.String())
 
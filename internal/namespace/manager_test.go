@@ -40,6 +40,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly/internal/blockchain/bifactory"
+	"github.com/hyperledger/firefly/internal/bridge"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/database/difactory"
@@ -51,6 +52,7 @@ import (
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/bridgemocks"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
@@ -1208,6 +1210,407 @@ func TestLoadNamespacesNetworkName(t *testing.T) {
 	assert.Equal(t, "default", newNS["ns1"].NetworkName)
 }
 
+func TestLoadNamespacesTenant(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+    - name: ns2
+      tenant: acme
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "", newNS["ns1"].Tenant)
+	assert.Equal(t, "acme", newNS["ns2"].Tenant)
+}
+
+func TestLoadNamespacesBadTenant(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+    - name: ns2
+      tenant: "!acme"
+    `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF00140.*tenant", err)
+}
+
+func TestLoadNamespacesDefaultTenant(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      tenant: acme
+    `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF10611.*ns1.*acme", err)
+}
+
+func TestLoadNamespacesBridges(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      bridges:
+      - name: bridge1
+        target: ns2
+        firstEvent: oldest
+        filter:
+          topics: ^orders
+          tag: ^tag1$
+          author: org1$
+          includePrivate: true
+        topicMap:
+        - source: Orders
+          target: gateway-orders
+        identityMap:
+        - source: did:firefly:org/org1
+          target: org2
+        members: [org2, org3]
+    - name: ns2
+      multiparty:
+        enabled: true
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	assert.Len(t, newNS["ns1"].bridgeConfs, 1)
+	bc := newNS["ns1"].bridgeConfs[0]
+	assert.Equal(t, "bridge1", bc.Name)
+	assert.Equal(t, "ns2", bc.Target)
+	assert.Equal(t, core.SubOptsFirstEventOldest, bc.FirstEvent)
+	assert.True(t, bc.Topics.MatchString("orders-eu"))
+	assert.True(t, bc.Tag.MatchString("tag1"))
+	assert.True(t, bc.Author.MatchString("did:firefly:org/org1"))
+	assert.True(t, bc.IncludePrivate)
+	assert.Equal(t, map[string]string{"Orders": "gateway-orders"}, bc.TopicMap)
+	assert.Equal(t, map[string]string{"did:firefly:org/org1": "org2"}, bc.IdentityMap)
+	assert.Equal(t, []string{"org2", "org3"}, bc.Members)
+	assert.Empty(t, newNS["ns2"].bridgeConfs)
+}
+
+func TestLoadNamespacesBridgeDefaults(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      bridges:
+      - name: bridge1
+        target: ns2
+    - name: ns2
+      multiparty:
+        enabled: true
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	bc := newNS["ns1"].bridgeConfs[0]
+	assert.Equal(t, core.SubOptsFirstEventNewest, bc.FirstEvent)
+	assert.Nil(t, bc.Topics)
+	assert.Nil(t, bc.Tag)
+	assert.Nil(t, bc.Author)
+	assert.False(t, bc.IncludePrivate)
+	assert.Empty(t, bc.TopicMap)
+	assert.Empty(t, bc.Members)
+}
+
+func TestLoadNamespacesBridgeErrors(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	for bridge, expected := range map[string]string{
+		"{name: '!bad', target: ns2}":                      "FF00140.*bridges",
+		"{name: b1, target: ns2, firstEvent: '12345'}":     "FF10654.*b1.*ns1.*firstEvent",
+		"{name: b1, target: ns2, filter: {topics: '['}}":   "FF10171.*topics",
+		"{name: b1, target: ns2, filter: {tag: '['}}":      "FF10171.*tag",
+		"{name: b1, target: ns2, filter: {author: '['}}":   "FF10171.*author",
+		"{name: b1, target: ns1}":                          "FF10654.*b1.*ns1.*different namespace",
+		"{name: b1, target: ns3}":                          "FF10654.*b1.*ns1.*unknown target namespace 'ns3'",
+		"{name: b1, target: ns2}, {name: b1, target: ns2}": "FF10654.*b1.*ns1.*duplicate",
+		"{name: b1, target: ns2}, {name: b2, target: ns4}": "FF10654.*b2.*ns1.*'ns4' is not multiparty",
+	} {
+		coreconfig.Reset()
+		viper.SetConfigType("yaml")
+		err := viper.ReadConfig(strings.NewReader(fmt.Sprintf(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      bridges: [%s]
+    - name: ns2
+      multiparty:
+        enabled: true
+    - name: ns4
+    `, bridge)))
+		assert.NoError(t, err)
+
+		_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+		assert.Regexp(t, expected, err, bridge)
+	}
+}
+
+func TestLoadNamespacesSLA(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      sla:
+        confirmTime: 30s
+        topics:
+        - topic: payments
+          confirmTime: 5s
+        - topic: reports
+    - name: ns2
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	assert.Equal(t, &core.SLAPolicy{
+		ConfirmTime: 30 * time.Second,
+		Topics: map[string]time.Duration{
+			"payments": 5 * time.Second,
+			"reports":  0,
+		},
+	}, newNS["ns1"].SLA)
+	assert.Nil(t, newNS["ns2"].SLA)
+}
+
+func TestLoadNamespacesSLAErrors(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	for topics, expected := range map[string]string{
+		"{topic: '!bad', confirmTime: 1s}":                           "FF00140.*sla",
+		"{topic: t1, confirmTime: 1s}, {topic: t1, confirmTime: 2s}": "FF10655.*t1.*ns1.*duplicate",
+	} {
+		coreconfig.Reset()
+		viper.SetConfigType("yaml")
+		err := viper.ReadConfig(strings.NewReader(fmt.Sprintf(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      sla:
+        topics: [%s]
+    `, topics)))
+		assert.NoError(t, err)
+
+		_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+		assert.Regexp(t, expected, err, topics)
+	}
+}
+
+func TestLoadNamespacesTransforms(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      transforms:
+      - plugin: tokenize
+        topics: [customers]
+        options:
+          key: secret
+          fields: [ssn]
+      - plugin: upcast
+        options:
+          defaults:
+            version: 2
+    - name: ns2
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	transforms := newNS["ns1"].Transforms
+	assert.Len(t, transforms, 2)
+	assert.Equal(t, "tokenize", transforms[0].Plugin)
+	assert.Equal(t, []string{"customers"}, transforms[0].Topics)
+	assert.Equal(t, "secret", transforms[0].Options.GetString("key"))
+	assert.Equal(t, "upcast", transforms[1].Plugin)
+	assert.Empty(t, transforms[1].Topics)
+	assert.Empty(t, newNS["ns2"].Transforms)
+}
+
+func TestLoadNamespacesBlobScanner(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      blobScanner:
+        plugin: clamav
+        options:
+          address: localhost:3310
+    - name: ns2
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "clamav", newNS["ns1"].BlobScanner.Plugin)
+	assert.Equal(t, "localhost:3310", newNS["ns1"].BlobScanner.Options.GetString("address"))
+	assert.Nil(t, newNS["ns2"].BlobScanner)
+}
+
+func TestLoadNamespacesBlobScannerErrors(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	for blobScanner, expected := range map[string]string{
+		"{plugin: wrong}":  "FF10680.*wrong",
+		"{plugin: clamav}": "FF10681.*clamav",
+	} {
+		coreconfig.Reset()
+		viper.SetConfigType("yaml")
+		err := viper.ReadConfig(strings.NewReader(fmt.Sprintf(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      blobScanner: %s
+    `, blobScanner)))
+		assert.NoError(t, err)
+
+		_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+		assert.Regexp(t, expected, err, blobScanner)
+	}
+}
+
+func TestLoadNamespacesTransformErrors(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	for transforms, expected := range map[string]string{
+		"{plugin: wrong}":                        "FF10665.*wrong",
+		"{plugin: tokenize, options: {key: k1}}": "FF10666.*tokenize",
+	} {
+		coreconfig.Reset()
+		viper.SetConfigType("yaml")
+		err := viper.ReadConfig(strings.NewReader(fmt.Sprintf(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      transforms: [%s]
+    `, transforms)))
+		assert.NoError(t, err)
+
+		_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+		assert.Regexp(t, expected, err, transforms)
+	}
+}
+
+func TestLoadNamespacesDeferredConfirm(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      deferredConfirm:
+        enabled: true
+        topics: [orders]
+    - name: ns2
+      deferredConfirm:
+        topics: [orders]
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"orders": true}, newNS["ns1"].DeferredConfirm.Topics)
+	assert.Nil(t, newNS["ns2"].DeferredConfirm)
+}
+
+func TestLoadNamespacesDeferredConfirmBadTopic(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      deferredConfirm:
+        enabled: true
+        topics: ["!bad"]
+    `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF00140", err)
+}
+
 func TestLoadNamespacesReservedNetworkName(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1535,6 +1938,25 @@ func TestLoadNamespacesNonMultipartyNoDatabase(t *testing.T) {
 
 }
 
+func TestLoadNamespacesNegativeConfirmations(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      confirmations: -1
+  `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF10539.*ns1", err)
+}
+
 func TestLoadNamespacesMultipartyUnknownPlugin(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1959,6 +2381,48 @@ func TestWaitStop(t *testing.T) {
 	nmm.mae.AssertExpectations(t)
 }
 
+func TestWaitStopBridges(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	waitInit := namespaceInitWaiter(t, nmm, []string{"default"})
+
+	mb := &bridgemocks.Bridge{}
+	mb.On("Start").Return()
+	mb.On("WaitStop").Return()
+	nm.namespaces["default"].bridgeConfs = []*bridge.Config{{Name: "bridge1", Target: "ns2"}}
+	var resolver bridge.OrchestratorResolver
+	nm.bridgeFactory = func(ctx context.Context, ns string, conf *bridge.Config, di database.Plugin, source orchestrator.Orchestrator, resolve bridge.OrchestratorResolver) bridge.Bridge {
+		assert.Equal(t, "default", ns)
+		assert.Equal(t, "bridge1", conf.Name)
+		assert.Equal(t, nmm.mo, source)
+		resolver = resolve
+		return mb
+	}
+
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(nil, nil)
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
+	nmm.mo.On("Init").Return(nil)
+	nmm.mo.On("Start", mock.Anything).Return(nil)
+	nmm.mo.On("WaitStop").Return()
+	nmm.mae.On("WaitStop").Return()
+
+	err := nm.startNamespacesAndPlugins(nm.namespaces, map[string]*plugin{})
+	assert.NoError(t, err)
+
+	waitInit.Wait()
+
+	_, err = resolver(context.Background(), "ns2")
+	assert.Regexp(t, "FF10436.*ns2", err)
+
+	nm.WaitStop()
+
+	mb.AssertExpectations(t)
+	nmm.mo.AssertExpectations(t)
+	nmm.mae.AssertExpectations(t)
+}
+
 func TestReset(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
@@ -49,5 +50,14 @@ func (nm *networkMap) RegisterNode(ctx context.Context, waitConfirm bool) (ident
 		return nil, err
 	}
 
+	// Publish the public key other nodes use to encrypt the data of private messages to this node
+	encryptionKey, err := privatemessaging.LocalEncryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if encryptionKey != "" {
+		nodeRequest.Profile[privatemessaging.EncryptionKeyProfileField] = encryptionKey
+	}
+
 	return nm.RegisterIdentity(ctx, nodeRequest, waitConfirm)
 }
@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/definitionsmocks"
@@ -70,6 +72,70 @@ func TestRegisterNodeOk(t *testing.T) {
 	mmp.AssertExpectations(t)
 }
 
+func TestRegisterNodeEncryptionKey(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	config.Set(coreconfig.PrivateMessagingEncryptionPrivateKey, "AQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eHyA=")
+
+	parentOrg := testOrg("org1")
+	signerRef := &core.SignerRef{Key: "0x23456"}
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetRootOrg", nm.ctx).Return(parentOrg, nil)
+	mim.On("VerifyIdentityChain", nm.ctx, mock.AnythingOfType("*core.Identity")).Return(parentOrg, false, nil)
+	mim.On("ResolveIdentitySigner", nm.ctx, parentOrg).Return(signerRef, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node1").Return(fftypes.JSONObject{
+		"id": "peer1",
+	}, nil)
+
+	mds := nm.defsender.(*definitionsmocks.Sender)
+	mds.On("ClaimIdentity", nm.ctx,
+		mock.AnythingOfType("*core.IdentityClaim"),
+		signerRef,
+		(*core.SignerRef)(nil),
+	).Return(nil)
+
+	mmp := nm.multiparty.(*multipartymocks.Manager)
+	mmp.On("LocalNode").Return(multiparty.LocalNode{Name: "node1"})
+
+	node, err := nm.RegisterNode(nm.ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, "B6N8vBQgk8i3VdwbEOhstCY3StFqqFPtC9/AsrhtHHw=", node.Profile.GetString("encryptionKey"))
+
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mds.AssertExpectations(t)
+	mmp.AssertExpectations(t)
+}
+
+func TestRegisterNodeBadEncryptionKey(t *testing.T) {
+
+	nm, cancel := newTestNetworkmap(t)
+	defer cancel()
+	config.Set(coreconfig.PrivateMessagingEncryptionPrivateKey, "bad")
+
+	parentOrg := testOrg("org1")
+
+	mim := nm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetRootOrg", nm.ctx).Return(parentOrg, nil)
+
+	mdx := nm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("GetEndpointInfo", nm.ctx, "node1").Return(fftypes.JSONObject{}, nil)
+
+	mmp := nm.multiparty.(*multipartymocks.Manager)
+	mmp.On("LocalNode").Return(multiparty.LocalNode{Name: "node1"})
+
+	_, err := nm.RegisterNode(nm.ctx, false)
+	assert.Regexp(t, "FF10629", err)
+
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+	mmp.AssertExpectations(t)
+}
+
 func TestRegisterNodeMissingName(t *testing.T) {
 
 	nm, cancel := newTestNetworkmap(t)
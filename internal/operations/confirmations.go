@@ -0,0 +1,209 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// confirmationTracker holds back the success of blockchain operations until the block containing
+// the transaction has the number of confirmations configured on the namespace. Until then the
+// operation stays Pending, with the receipt recorded as its output.
+type confirmationTracker struct {
+	ctx           context.Context
+	updater       *operationUpdater
+	blockchain    blockchain.Plugin
+	confirmations int64
+	pollInterval  time.Duration
+	mux           sync.Mutex
+	chainHead     int64
+	held          map[string]*heldUpdate
+	loopDone      chan struct{}
+}
+
+type heldUpdate struct {
+	update      *core.OperationUpdate
+	blockNumber int64
+}
+
+func newConfirmationTracker(ctx context.Context, ou *operationUpdater, bi blockchain.Plugin, confirmations int) *confirmationTracker {
+	if bi == nil || confirmations <= 0 {
+		return nil
+	}
+	return &confirmationTracker{
+		ctx:           ctx,
+		updater:       ou,
+		blockchain:    bi,
+		confirmations: int64(confirmations),
+		pollInterval:  config.GetDuration(coreconfig.OpUpdateConfirmationsPollInterval),
+		held:          make(map[string]*heldUpdate),
+	}
+}
+
+// receiptBlockNumber extracts the block number from the protocol ID of a blockchain receipt,
+// which is formatted as "<block>/<txIndex>"
+func receiptBlockNumber(output fftypes.JSONObject) (int64, bool) {
+	protocolID := output.GetString("protocolId")
+	if protocolID == "" {
+		return 0, false
+	}
+	blockNumber, err := strconv.ParseInt(strings.Split(protocolID, "/")[0], 10, 64)
+	return blockNumber, err == nil
+}
+
+// hold returns true if the update marks a blockchain operation as succeeded, but the block
+// containing the transaction does not yet have enough confirmations
+func (ct *confirmationTracker) hold(ctx context.Context, op *core.Operation, update *core.OperationUpdate) bool {
+	if ct == nil || update.Status != core.OpStatusSucceeded || !op.IsBlockchainOperation() {
+		return false
+	}
+	blockNumber, ok := receiptBlockNumber(update.Output)
+	if !ok {
+		log.L(ctx).Debugf("Operation %s succeeded without a block number in the receipt - not waiting for confirmations", op.ID)
+		return false
+	}
+
+	ct.mux.Lock()
+	defer ct.mux.Unlock()
+	if ct.chainHead >= blockNumber+ct.confirmations {
+		delete(ct.held, update.NamespacedOpID)
+		return false
+	}
+	log.L(ctx).Infof("Operation %s mined in block %d - waiting for %d confirmations (chain head %d)", op.ID, blockNumber, ct.confirmations, ct.chainHead)
+	ct.held[update.NamespacedOpID] = &heldUpdate{
+		update: &core.OperationUpdate{
+			Plugin:         update.Plugin,
+			NamespacedOpID: update.NamespacedOpID,
+			Status:         update.Status,
+			BlockchainTXID: update.BlockchainTXID,
+			Output:         update.Output,
+		},
+		blockNumber: blockNumber,
+	}
+	return true
+}
+
+// recoverHeld finds operations that were being held when we last stopped, as the
+// connector will not deliver their receipts again
+func (ct *confirmationTracker) recoverHeld(ctx context.Context) error {
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("status", core.OpStatusPending),
+		fb.In("type", []driver.Value{
+			core.OpTypeBlockchainInvoke,
+			core.OpTypeBlockchainNetworkAction,
+			core.OpTypeBlockchainPinBatch,
+			core.OpTypeBlockchainContractDeploy,
+			core.OpTypeTokenSwapInvoke,
+		}),
+	)
+	ops, _, err := ct.updater.database.GetOperations(ctx, ct.updater.manager.namespace, filter)
+	if err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if op.Output.GetObject("headers").GetString("type") != "TransactionSuccess" {
+			continue
+		}
+		ct.hold(ctx, op, &core.OperationUpdate{
+			Plugin:         op.Plugin,
+			NamespacedOpID: (&core.PreparedOperation{ID: op.ID, Namespace: op.Namespace}).NamespacedIDString(),
+			Status:         core.OpStatusSucceeded,
+			BlockchainTXID: op.Output.GetString("transactionHash"),
+			Output:         op.Output,
+		})
+	}
+	return nil
+}
+
+func (ct *confirmationTracker) start() {
+	ct.loopDone = make(chan struct{})
+	go ct.pollLoop()
+}
+
+func (ct *confirmationTracker) pollLoop() {
+	defer close(ct.loopDone)
+	ctx := log.WithLogField(ct.ctx, "role", "confirmations")
+
+	if err := ct.recoverHeld(ctx); err != nil {
+		log.L(ctx).Errorf("Failed to recover operations waiting for confirmations: %s", err)
+	}
+
+	ticker := time.NewTicker(ct.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ct.checkChainHead(ctx)
+		case <-ctx.Done():
+			log.L(ctx).Debugf("Confirmation tracker exiting")
+			return
+		}
+	}
+}
+
+// checkChainHead releases every held update whose block now has enough confirmations
+func (ct *confirmationTracker) checkChainHead(ctx context.Context) {
+	ct.mux.Lock()
+	waiting := len(ct.held)
+	ct.mux.Unlock()
+	if waiting == 0 {
+		return
+	}
+
+	status, err := ct.blockchain.GetNetworkStatus(ctx)
+	if err != nil {
+		log.L(ctx).Warnf("Unable to query chain head for %d operations waiting for confirmations: %s", waiting, err)
+		return
+	}
+
+	ct.mux.Lock()
+	if status.ChainHead > ct.chainHead {
+		ct.chainHead = status.ChainHead
+	}
+	var ready []*core.OperationUpdate
+	for id, held := range ct.held {
+		if ct.chainHead >= held.blockNumber+ct.confirmations {
+			ready = append(ready, held.update)
+			delete(ct.held, id)
+		}
+	}
+	ct.mux.Unlock()
+
+	for _, update := range ready {
+		ct.updater.SubmitOperationUpdate(ctx, update)
+	}
+}
+
+func (ct *confirmationTracker) waitStop() {
+	if ct.loopDone != nil {
+		<-ct.loopDone
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestConfirmationTracker(t *testing.T) (*operationUpdater, *blockchainmocks.Plugin) {
+	ou := newTestOperationUpdaterNoConcurrency(t)
+	mbi := &blockchainmocks.Plugin{}
+	ou.held = newConfirmationTracker(ou.ctx, ou, mbi, 5)
+	ou.held.pollInterval = 1 * time.Millisecond
+	return ou, mbi
+}
+
+func successReceipt(protocolID string) fftypes.JSONObject {
+	return fftypes.JSONObject{
+		"headers":         map[string]interface{}{"type": "TransactionSuccess"},
+		"protocolId":      protocolID,
+		"transactionHash": "0x123",
+	}
+}
+
+func TestNewConfirmationTrackerDisabled(t *testing.T) {
+	ou := newTestOperationUpdater(t)
+	assert.Nil(t, newConfirmationTracker(ou.ctx, ou, &blockchainmocks.Plugin{}, 0))
+	assert.Nil(t, newConfirmationTracker(ou.ctx, ou, nil, 5))
+	assert.False(t, ou.held.hold(ou.ctx, &core.Operation{Type: core.OpTypeBlockchainInvoke}, &core.OperationUpdate{
+		Status: core.OpStatusSucceeded,
+		Output: successReceipt("000000000100/000000"),
+	}))
+}
+
+func TestDoUpdateHeldUntilConfirmed(t *testing.T) {
+	ou, mbi := newTestConfirmationTracker(t)
+	defer ou.close()
+
+	opID := fftypes.NewUUID()
+	op := &core.Operation{ID: opID, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke}
+	ou.manager.cacheOperation(op)
+	ou.manager.handlers[core.OpTypeBlockchainInvoke] = &mockHandler{UpdateErr: fmt.Errorf("not yet")}
+
+	mdi := ou.database.(*databasemocks.Plugin)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Pending"},
+		{"output", successReceipt("000000000100/000000").String()},
+	}))).Return(true, nil).Once()
+
+	update := &core.OperationUpdate{
+		NamespacedOpID: "ns1:" + opID.String(),
+		Status:         core.OpStatusSucceeded,
+		Output:         successReceipt("000000000100/000000"),
+	}
+	err := ou.doUpdate(ou.ctx, update, []*core.Operation{op}, []*core.Transaction{})
+	assert.NoError(t, err)
+	assert.Len(t, ou.held.held, 1)
+
+	// Not enough confirmations yet
+	mbi.On("GetNetworkStatus", mock.Anything).Return(&core.BlockchainNetworkStatus{ChainHead: 104}, nil).Once()
+	ou.held.checkChainHead(ou.ctx)
+	assert.Len(t, ou.held.held, 1)
+
+	// Now the update is released back to the updater
+	ou.manager.handlers[core.OpTypeBlockchainInvoke] = &mockHandler{}
+	mbi.On("GetNetworkStatus", mock.Anything).Return(&core.BlockchainNetworkStatus{ChainHead: 105}, nil).Once()
+	mdi.On("RunAsGroup", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		err := args[1].(func(context.Context) error)(ou.ctx)
+		assert.NoError(t, err)
+	}).Return(nil)
+	mdi.On("UpdateOperation", mock.Anything, "ns1", opID, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
+		{"status", "Succeeded"},
+		{"error", ""},
+		{"output", successReceipt("000000000100/000000").String()},
+	}))).Return(true, nil).Once()
+	ou.held.checkChainHead(ou.ctx)
+	assert.Empty(t, ou.held.held)
+
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestHoldNoBlockNumber(t *testing.T) {
+	ou, _ := newTestConfirmationTracker(t)
+	defer ou.close()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Type: core.OpTypeBlockchainInvoke}
+	assert.False(t, ou.held.hold(ou.ctx, op, &core.OperationUpdate{Status: core.OpStatusSucceeded}))
+	assert.False(t, ou.held.hold(ou.ctx, op, &core.OperationUpdate{Status: core.OpStatusSucceeded, Output: successReceipt("bad")}))
+	assert.False(t, ou.held.hold(ou.ctx, op, &core.OperationUpdate{Status: core.OpStatusFailed, Output: successReceipt("000000000100/000000")}))
+	assert.False(t, ou.held.hold(ou.ctx, &core.Operation{Type: core.OpTypeTokenTransfer}, &core.OperationUpdate{Status: core.OpStatusSucceeded, Output: successReceipt("000000000100/000000")}))
+	assert.Empty(t, ou.held.held)
+}
+
+func TestCheckChainHeadNothingHeld(t *testing.T) {
+	ou, mbi := newTestConfirmationTracker(t)
+	defer ou.close()
+
+	ou.held.checkChainHead(ou.ctx)
+	mbi.AssertNotCalled(t, "GetNetworkStatus", mock.Anything)
+}
+
+func TestCheckChainHeadFail(t *testing.T) {
+	ou, mbi := newTestConfirmationTracker(t)
+	defer ou.close()
+
+	op := &core.Operation{ID: fftypes.NewUUID(), Type: core.OpTypeBlockchainInvoke}
+	assert.True(t, ou.held.hold(ou.ctx, op, &core.OperationUpdate{
+		NamespacedOpID: "ns1:" + op.ID.String(),
+		Status:         core.OpStatusSucceeded,
+		Output:         successReceipt("000000000100/000000"),
+	}))
+
+	mbi.On("GetNetworkStatus", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	ou.held.checkChainHead(ou.ctx)
+	assert.Len(t, ou.held.held, 1)
+
+	mbi.AssertExpectations(t)
+}
+
+func TestRecoverHeldOnStart(t *testing.T) {
+	ou, mbi := newTestConfirmationTracker(t)
+
+	opID1 := fftypes.NewUUID()
+	opID2 := fftypes.NewUUID()
+	mdi := ou.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return([]*core.Operation{
+		{ID: opID1, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke, Output: successReceipt("000000000100/000000")},
+		{ID: opID2, Namespace: "ns1", Type: core.OpTypeBlockchainInvoke, Output: fftypes.JSONObject{}},
+	}, nil, nil)
+	checked := make(chan struct{})
+	mbi.On("GetNetworkStatus", mock.Anything).Return(&core.BlockchainNetworkStatus{ChainHead: 101}, nil).Run(func(args mock.Arguments) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+	})
+
+	ou.start()
+	<-checked
+	ou.close()
+
+	assert.Len(t, ou.held.held, 1)
+	assert.NotNil(t, ou.held.held["ns1:"+opID1.String()])
+	assert.Equal(t, "0x123", ou.held.held["ns1:"+opID1.String()].update.BlockchainTXID)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestRecoverHeldFail(t *testing.T) {
+	ou, _ := newTestConfirmationTracker(t)
+
+	mdi := ou.database.(*databasemocks.Plugin)
+	mdi.On("GetOperations", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	ou.cancelFunc()
+	ou.held.loopDone = make(chan struct{})
+	ou.held.pollLoop()
+
+	assert.Empty(t, ou.held.held)
+	mdi.AssertExpectations(t)
+}
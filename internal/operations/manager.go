@@ -19,6 +19,7 @@ package operations
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 )
@@ -68,7 +70,7 @@ type operationsManager struct {
 	cache     cache.CInterface
 }
 
-func NewOperationsManager(ctx context.Context, ns string, di database.Plugin, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
+func NewOperationsManager(ctx context.Context, ns string, confirmations int, di database.Plugin, bi blockchain.Plugin, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
 	if di == nil || txHelper == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "OperationsManager")
 	}
@@ -92,7 +94,7 @@ func NewOperationsManager(ctx context.Context, ns string, di database.Plugin, tx
 		database:  di,
 		handlers:  make(map[core.OpType]OperationHandler),
 	}
-	om.updater = newOperationUpdater(ctx, om, di, txHelper)
+	om.updater = newOperationUpdater(ctx, om, di, bi, txHelper, confirmations)
 	om.cache = cache
 	return om, nil
 }
@@ -152,11 +154,17 @@ func (om *operationsManager) RunOperation(ctx context.Context, op *core.Prepared
 	log.L(ctx).Tracef("Operation detail: %+v", op)
 	outputs, complete, err := handler.RunOperation(ctx, op)
 	if err != nil {
+		var failureReason core.OpFailureReason
+		var failure *core.OperationFailure
+		if errors.As(err, &failure) {
+			failureReason = failure.Reason
+		}
 		om.SubmitOperationUpdate(&core.OperationUpdate{
 			NamespacedOpID: op.NamespacedIDString(),
 			Plugin:         op.Plugin,
 			Status:         failState,
 			ErrorMessage:   err.Error(),
+			FailureReason:  failureReason,
 			Output:         outputs,
 		})
 	} else {
@@ -211,7 +219,7 @@ func (om *operationsManager) RetryOperation(ctx context.Context, opID *fftypes.U
 
 		// Update the old operation to point to the new one
 		update := database.OperationQueryFactory.NewUpdate(ctx).Set("retry", op.ID)
-		om.updateCachedOperation(opID, "", nil, nil, op.ID)
+		om.updateCachedOperation(opID, "", nil, "", nil, op.ID)
 		if _, err := om.database.UpdateOperation(ctx, om.namespace, opID, nil, update); err != nil {
 			return err
 		}
@@ -228,7 +236,7 @@ func (om *operationsManager) RetryOperation(ctx context.Context, opID *fftypes.U
 }
 
 func (om *operationsManager) ResolveOperationByID(ctx context.Context, opID *fftypes.UUID, op *core.OperationUpdateDTO) error {
-	return om.updater.resolveOperation(ctx, om.namespace, opID, op.Status, op.Error, op.Output)
+	return om.updater.resolveOperation(ctx, om.namespace, opID, op.Status, op.Error, "", op.Output)
 }
 
 func (om *operationsManager) SubmitOperationUpdate(update *core.OperationUpdate) {
@@ -296,7 +304,7 @@ func (om *operationsManager) cacheOperation(op *core.Operation) {
 	om.cache.Set(op.ID.String(), op)
 }
 
-func (om *operationsManager) updateCachedOperation(id *fftypes.UUID, status core.OpStatus, errorMsg *string, output fftypes.JSONObject, retry *fftypes.UUID) {
+func (om *operationsManager) updateCachedOperation(id *fftypes.UUID, status core.OpStatus, errorMsg *string, failureReason core.OpFailureReason, output fftypes.JSONObject, retry *fftypes.UUID) {
 	if cachedValue := om.cache.Get(id.String()); cachedValue != nil {
 		val := cachedValue.(*core.Operation)
 		if status != "" {
@@ -305,6 +313,9 @@ func (om *operationsManager) updateCachedOperation(id *fftypes.UUID, status core
 		if errorMsg != nil {
 			val.Error = *errorMsg
 		}
+		if failureReason != "" {
+			val.FailureReason = failureReason
+		}
 		if output != nil {
 			val.Output = output
 		}
@@ -82,7 +82,7 @@ func newTestOperations(t *testing.T) (*operationsManager, func()) {
 	}
 
 	ns := "ns1"
-	om, err := NewOperationsManager(ctx, ns, mdi, txHelper, cmi)
+	om, err := NewOperationsManager(ctx, ns, 0, mdi, nil, txHelper, cmi)
 	assert.NoError(t, err)
 	cmi.AssertCalled(t, "GetCache", cache.NewCacheConfig(
 		ctx,
@@ -94,7 +94,7 @@ func newTestOperations(t *testing.T) (*operationsManager, func()) {
 }
 
 func TestInitFail(t *testing.T) {
-	_, err := NewOperationsManager(context.Background(), "ns1", nil, nil, nil)
+	_, err := NewOperationsManager(context.Background(), "ns1", 0, nil, nil, nil, nil)
 	assert.Regexp(t, "FF10128", err)
 }
 
@@ -114,7 +114,7 @@ func TestCacheInitFail(t *testing.T) {
 	ns := "ns1"
 	ecmi := &cachemocks.Manager{}
 	ecmi.On("GetCache", mock.Anything).Return(nil, cacheInitError)
-	_, err := NewOperationsManager(ctx, ns, mdi, txHelper, ecmi)
+	_, err := NewOperationsManager(ctx, ns, 0, mdi, nil, txHelper, ecmi)
 	assert.Equal(t, cacheInitError, err)
 }
 
@@ -213,6 +213,33 @@ func TestRunOperationFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestRunOperationFailWithReason(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	updates := make(chan *core.OperationUpdate, 1)
+	om.updater.workQueues = []chan *core.OperationUpdate{updates}
+
+	ctx := context.Background()
+	op := &core.PreparedOperation{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      core.OpTypeTokenTransfer,
+	}
+
+	om.RegisterHandler(ctx, &mockHandler{RunErr: &core.OperationFailure{
+		Reason: core.OpFailureReasonComplianceRejected,
+		Err:    fmt.Errorf("pop"),
+	}}, []core.OpType{core.OpTypeTokenTransfer})
+	_, err := om.RunOperation(ctx, op)
+	assert.EqualError(t, err, "pop")
+
+	update := <-updates
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+	assert.Equal(t, "pop", update.ErrorMessage)
+	assert.Equal(t, core.OpFailureReasonComplianceRejected, update.FailureReason)
+}
+
 func TestRunOperationFailRemainPending(t *testing.T) {
 	om, cancel := newTestOperations(t)
 	defer cancel()
@@ -545,3 +572,19 @@ func TestResubmitIdempotentOperationError(t *testing.T) {
 
 	mdi.AssertExpectations(t)
 }
+
+func TestUpdateCachedOperationFailureReason(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	opID := fftypes.NewUUID()
+	om.cacheOperation(&core.Operation{ID: opID, Status: core.OpStatusPending})
+
+	errMsg := "pop"
+	om.updateCachedOperation(opID, core.OpStatusFailed, &errMsg, core.OpFailureReasonComplianceRejected, nil, nil)
+
+	op := om.getCachedOperation(opID)
+	assert.Equal(t, core.OpStatusFailed, op.Status)
+	assert.Equal(t, "pop", op.Error)
+	assert.Equal(t, core.OpFailureReasonComplianceRejected, op.FailureReason)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/txcommon"
+	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 )
@@ -52,6 +53,7 @@ type operationUpdater struct {
 	conf        operationUpdaterConf
 	closed      bool
 	retry       *retry.Retry
+	held        *confirmationTracker
 }
 
 type operationUpdaterConf struct {
@@ -61,7 +63,7 @@ type operationUpdaterConf struct {
 	queueLength  int
 }
 
-func newOperationUpdater(ctx context.Context, om *operationsManager, di database.Plugin, txHelper txcommon.Helper) *operationUpdater {
+func newOperationUpdater(ctx context.Context, om *operationsManager, di database.Plugin, bi blockchain.Plugin, txHelper txcommon.Helper, confirmations int) *operationUpdater {
 	ou := &operationUpdater{
 		manager:  om,
 		database: di,
@@ -79,6 +81,7 @@ func newOperationUpdater(ctx context.Context, om *operationsManager, di database
 		},
 	}
 	ou.ctx, ou.cancelFunc = context.WithCancel(ctx)
+	ou.held = newConfirmationTracker(ou.ctx, ou, bi, confirmations)
 	if !di.Capabilities().Concurrency {
 		log.L(ctx).Infof("Database plugin not configured for concurrency. Batched operation updates disabled")
 		ou.conf.workerCount = 0
@@ -135,6 +138,9 @@ func (ou *operationUpdater) start() {
 			go ou.updaterLoop(i)
 		}
 	}
+	if ou.held != nil {
+		ou.held.start()
+	}
 }
 
 func (ou *operationUpdater) updaterLoop(index int) {
@@ -275,6 +281,11 @@ func (ou *operationUpdater) doUpdate(ctx context.Context, update *core.Operation
 		}
 	}
 
+	// Blockchain transactions are not treated as succeeded until they have enough confirmations
+	if ou.held.hold(ctx, op, update) {
+		return ou.resolveOperation(ctx, op.Namespace, op.ID, core.OpStatusPending, nil, "", update.Output)
+	}
+
 	if handler, ok := ou.manager.handlers[op.Type]; ok {
 		if err := handler.OnOperationUpdate(ctx, op, update); err != nil {
 			return err
@@ -288,7 +299,7 @@ func (ou *operationUpdater) doUpdate(ctx context.Context, update *core.Operation
 		}
 	}
 
-	if err := ou.resolveOperation(ctx, op.Namespace, op.ID, update.Status, &update.ErrorMessage, update.Output); err != nil {
+	if err := ou.resolveOperation(ctx, op.Namespace, op.ID, update.Status, &update.ErrorMessage, update.FailureReason, update.Output); err != nil {
 		return err
 	}
 
@@ -339,10 +350,13 @@ func (ou *operationUpdater) close() {
 		for _, workerDone := range ou.workersDone {
 			<-workerDone
 		}
+		if ou.held != nil {
+			ou.held.waitStop()
+		}
 	}
 }
 
-func (ou *operationUpdater) resolveOperation(ctx context.Context, ns string, id *fftypes.UUID, status core.OpStatus, errorMsg *string, output fftypes.JSONObject) (err error) {
+func (ou *operationUpdater) resolveOperation(ctx context.Context, ns string, id *fftypes.UUID, status core.OpStatus, errorMsg *string, failureReason core.OpFailureReason, output fftypes.JSONObject) (err error) {
 	// Never move an operation from Succeeded/Failed back to Pending
 	fb := database.OperationQueryFactory.NewFilter(ctx)
 	var filter ffapi.AndFilter
@@ -360,12 +374,15 @@ func (ou *operationUpdater) resolveOperation(ctx context.Context, ns string, id
 	if errorMsg != nil {
 		update = update.Set("error", *errorMsg)
 	}
+	if failureReason != "" {
+		update = update.Set("failurereason", failureReason)
+	}
 	if output != nil {
 		update = update.Set("output", output)
 	}
 	ok, err := ou.database.UpdateOperation(ctx, ns, id, filter, update)
 	if ok && err == nil {
-		ou.manager.updateCachedOperation(id, status, errorMsg, output, nil)
+		ou.manager.updateCachedOperation(id, status, errorMsg, failureReason, output, nil)
 	}
 	return err
 }
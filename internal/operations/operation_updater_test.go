@@ -67,7 +67,7 @@ func newTestOperationUpdaterCommon(t *testing.T, dbCapabilities *database.Capabi
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
-	return newOperationUpdater(context.Background(), mom, mdi, txHelper)
+	return newOperationUpdater(context.Background(), mom, mdi, nil, txHelper, 0)
 }
 
 func updateMatcher(vals [][]string) func(ffapi.Update) bool {
@@ -213,6 +213,7 @@ func TestSubmitUpdateWorkerE2ESuccess(t *testing.T) {
 	mdi.On("UpdateOperation", mock.Anything, "ns1", opID3, mock.Anything, mock.MatchedBy(updateMatcher([][]string{
 		{"status", "Failed"},
 		{"error", "err2"},
+		{"failurereason", "compliance_rejected"},
 	}))).Return(true, nil).Run(func(args mock.Arguments) {
 		close(done)
 	}).Once()
@@ -233,6 +234,7 @@ func TestSubmitUpdateWorkerE2ESuccess(t *testing.T) {
 		NamespacedOpID: "ns1:" + opID3.String(),
 		Status:         core.OpStatusFailed,
 		ErrorMessage:   "err2",
+		FailureReason:  core.OpFailureReasonComplianceRejected,
 	})
 	<-done
 
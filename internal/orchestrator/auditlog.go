@@ -0,0 +1,37 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+func (or *orchestrator) RecordAuditLogEntry(ctx context.Context, entry *core.AuditLogEntry) error {
+	if entry.ID == nil {
+		entry.ID = fftypes.NewUUID()
+	}
+	entry.Namespace = or.namespace.Name
+	return or.database().InsertAuditLogEntry(ctx, entry)
+}
+
+func (or *orchestrator) GetAuditLog(ctx context.Context, filter ffapi.AndFilter) ([]*core.AuditLogEntry, *ffapi.FilterResult, error) {
+	return or.database().GetAuditLog(ctx, or.namespace.Name, filter)
+}
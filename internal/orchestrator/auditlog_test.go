@@ -0,0 +1,60 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordAuditLogEntry(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("InsertAuditLogEntry", mock.Anything, mock.MatchedBy(func(entry *core.AuditLogEntry) bool {
+		return entry.ID != nil && entry.Namespace == "ns" && entry.Principal == "alice"
+	})).Return(nil)
+
+	err := or.RecordAuditLogEntry(or.ctx, &core.AuditLogEntry{Namespace: "other", Principal: "alice"})
+	assert.NoError(t, err)
+}
+
+func TestRecordAuditLogEntryFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("InsertAuditLogEntry", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := or.RecordAuditLogEntry(or.ctx, &core.AuditLogEntry{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetAuditLog(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetAuditLog", mock.Anything, "ns", mock.Anything).Return([]*core.AuditLogEntry{}, nil, nil)
+
+	fb := database.AuditLogQueryFactory.NewFilter(or.ctx)
+	_, _, err := or.GetAuditLog(or.ctx, fb.And())
+	assert.NoError(t, err)
+}
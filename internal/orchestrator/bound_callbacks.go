@@ -93,3 +93,10 @@ func (bc *boundCallbacks) TokensApproved(plugin tokens.Plugin, approval *tokens.
 	}
 	return bc.o.events.TokensApproved(plugin, approval)
 }
+
+func (bc *boundCallbacks) TokenEventRemoved(plugin tokens.Plugin, event *blockchain.Event) error {
+	if err := bc.checkStopped(); err != nil {
+		return err
+	}
+	return bc.o.events.TokenEventRemoved(plugin, event)
+}
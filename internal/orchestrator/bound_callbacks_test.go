@@ -106,6 +106,10 @@ func TestBoundCallbacks(t *testing.T) {
 	err = bc.TokensApproved(mti, &tokens.TokenApproval{})
 	assert.NoError(t, err)
 
+	mei.On("TokenEventRemoved", mti, &blockchain.Event{}).Return(nil)
+	err = bc.TokenEventRemoved(mti, &blockchain.Event{})
+	assert.NoError(t, err)
+
 	mei.AssertExpectations(t)
 	mss.AssertExpectations(t)
 	mom.AssertExpectations(t)
@@ -136,4 +140,7 @@ func TestBoundCallbacksStopped(t *testing.T) {
 
 	err = bc.TokensApproved(nil, &tokens.TokenApproval{})
 	assert.Regexp(t, "FF10446", err)
+
+	err = bc.TokenEventRemoved(nil, &blockchain.Event{})
+	assert.Regexp(t, "FF10446", err)
 }
@@ -192,8 +192,8 @@ func (or *orchestrator) GetTransactions(ctx context.Context, filter ffapi.AndFil
 	return or.database().GetTransactions(ctx, or.namespace.Name, filter)
 }
 
-func (or *orchestrator) GetMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
-	return or.database().GetMessages(ctx, or.namespace.Name, filter)
+func (or *orchestrator) GetMessages(ctx context.Context, filter ffapi.AndFilter, fields ...string) ([]*core.Message, *ffapi.FilterResult, error) {
+	return or.database().GetMessages(ctx, or.namespace.Name, filter, fields...)
 }
 
 func (or *orchestrator) GetMessagesWithData(ctx context.Context, filter ffapi.AndFilter) ([]*core.MessageInOut, *ffapi.FilterResult, error) {
@@ -279,8 +279,8 @@ func (or *orchestrator) GetDatatypes(ctx context.Context, filter ffapi.AndFilter
 	return or.database().GetDatatypes(ctx, or.namespace.Name, filter)
 }
 
-func (or *orchestrator) GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error) {
-	return or.database().GetOperations(ctx, or.namespace.Name, filter)
+func (or *orchestrator) GetOperations(ctx context.Context, filter ffapi.AndFilter, fields ...string) ([]*core.Operation, *ffapi.FilterResult, error) {
+	return or.database().GetOperations(ctx, or.namespace.Name, filter, fields...)
 }
 
 func (or *orchestrator) GetEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error) {
@@ -316,6 +316,10 @@ func (or *orchestrator) GetNextPins(ctx context.Context, filter ffapi.AndFilter)
 	return or.database().GetNextPins(ctx, or.namespace.Name, filter)
 }
 
+func (or *orchestrator) GetTopicSequences(ctx context.Context, filter ffapi.AndFilter) ([]*core.TopicSequence, *ffapi.FilterResult, error) {
+	return or.database().GetTopicSequences(ctx, or.namespace.Name, filter)
+}
+
 func (or *orchestrator) GetEventsWithReferences(ctx context.Context, filter ffapi.AndFilter) ([]*core.EnrichedEvent, *ffapi.FilterResult, error) {
 	events, fr, err := or.database().GetEvents(ctx, or.namespace.Name, filter)
 	if err != nil {
@@ -848,3 +848,13 @@ func TestGetNextPins(t *testing.T) {
 	_, _, err := or.GetNextPins(context.Background(), f)
 	assert.NoError(t, err)
 }
+
+func TestGetTopicSequences(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.mdi.On("GetTopicSequences", mock.Anything, "ns", mock.Anything).Return([]*core.TopicSequence{}, nil, nil)
+	fb := database.TopicSequenceQueryFactory.NewFilter(context.Background())
+	f := fb.And(fb.Eq("topic", "topic1"))
+	_, _, err := or.GetTopicSequences(context.Background(), f)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,77 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/internal/jobs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// registerJobHandlers registers the long-running actions of the namespace that can be submitted as jobs
+func (or *orchestrator) registerJobHandlers() {
+	or.jobs.RegisterHandler(core.JobTypeSubscriptionRewind, &jobs.Handler{
+		NewInput: func() interface{} { return &core.JobSubscriptionRewindInput{} },
+		Run: func(ctx context.Context, input interface{}, runner jobs.Runner) (interface{}, error) {
+			in := input.(*core.JobSubscriptionRewindInput)
+			return or.RewindSubscription(ctx, in.Subscription, &in.SubscriptionRewind)
+		},
+	})
+	or.jobs.RegisterHandler(core.JobTypeTokenPoolSnapshot, &jobs.Handler{
+		NewInput: func() interface{} { return &core.JobTokenPoolSnapshotInput{} },
+		Run: func(ctx context.Context, input interface{}, runner jobs.Runner) (interface{}, error) {
+			in := input.(*core.JobTokenPoolSnapshotInput)
+			return or.assets.CreateTokenPoolSnapshot(ctx, in.Pool, &in.TokenSnapshotInput)
+		},
+	})
+	or.jobs.RegisterHandler(core.JobTypeTokenSnapshotExport, &jobs.Handler{
+		NewInput: func() interface{} { return &core.JobTokenSnapshotExportInput{} },
+		Run: func(ctx context.Context, input interface{}, runner jobs.Runner) (interface{}, error) {
+			reader, err := or.assets.ExportTokenSnapshot(ctx, input.(*core.JobTokenSnapshotExportInput).Snapshot)
+			if err != nil {
+				return nil, err
+			}
+			defer reader.Close()
+			return runner.WriteFile(string(core.TokenExportFormatCSV), reader)
+		},
+	})
+	or.jobs.RegisterHandler(core.JobTypeTokenTransfersExport, &jobs.Handler{
+		NewInput: func() interface{} { return &core.TokenTransferExport{} },
+		Run: func(ctx context.Context, input interface{}, runner jobs.Runner) (interface{}, error) {
+			export := input.(*core.TokenTransferExport)
+			reader, err := or.assets.ExportTokenTransfers(ctx, export)
+			if err != nil {
+				return nil, err
+			}
+			defer reader.Close()
+			return runner.WriteFile(string(export.Format), reader)
+		},
+	})
+	or.jobs.RegisterHandler(core.JobTypeTokenLedgerExport, &jobs.Handler{
+		NewInput: func() interface{} { return &core.TokenLedgerExport{} },
+		Run: func(ctx context.Context, input interface{}, runner jobs.Runner) (interface{}, error) {
+			export := input.(*core.TokenLedgerExport)
+			reader, err := or.assets.ExportTokenLedger(ctx, export)
+			if err != nil {
+				return nil, err
+			}
+			defer reader.Close()
+			return runner.WriteFile(string(export.Format), reader)
+		},
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/jobs"
+	"github.com/hyperledger/firefly/mocks/jobmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func registerTestJobHandlers(or *testOrchestrator) map[core.JobType]*jobs.Handler {
+	handlers := make(map[core.JobType]*jobs.Handler)
+	or.mjm.On("RegisterHandler", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		handlers[args[0].(core.JobType)] = args[1].(*jobs.Handler)
+	})
+	or.registerJobHandlers()
+	return handlers
+}
+
+func TestJobSubscriptionRewind(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+	}
+	seq := int64(10)
+	input := handlers[core.JobTypeSubscriptionRewind].NewInput().(*core.JobSubscriptionRewindInput)
+	input.Subscription = sub.ID.String()
+	input.Sequence = &seq
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", sub.ID).Return(sub, nil)
+	or.mem.On("RewindDurableSubscription", mock.Anything, sub, &input.SubscriptionRewind).Return(&input.SubscriptionRewind, nil)
+
+	output, err := handlers[core.JobTypeSubscriptionRewind].Run(context.Background(), input, &jobmocks.Runner{})
+	assert.NoError(t, err)
+	assert.Equal(t, &input.SubscriptionRewind, output)
+}
+
+func TestJobTokenPoolSnapshot(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	input := handlers[core.JobTypeTokenPoolSnapshot].NewInput().(*core.JobTokenPoolSnapshotInput)
+	input.Pool = "pool1"
+	snapshot := &core.TokenSnapshot{ID: fftypes.NewUUID()}
+	or.mam.On("CreateTokenPoolSnapshot", mock.Anything, "pool1", &input.TokenSnapshotInput).Return(snapshot, nil)
+
+	output, err := handlers[core.JobTypeTokenPoolSnapshot].Run(context.Background(), input, &jobmocks.Runner{})
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, output)
+}
+
+func TestJobTokenSnapshotExport(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	input := handlers[core.JobTypeTokenSnapshotExport].NewInput().(*core.JobTokenSnapshotExportInput)
+	input.Snapshot = "snapshot1"
+	reader := io.NopCloser(strings.NewReader("a,b,c"))
+	file := &jobs.OutputFile{File: "job1.csv", Size: 5}
+	mr := &jobmocks.Runner{}
+	or.mam.On("ExportTokenSnapshot", mock.Anything, "snapshot1").Return(reader, nil)
+	mr.On("WriteFile", "csv", reader).Return(file, nil)
+
+	output, err := handlers[core.JobTypeTokenSnapshotExport].Run(context.Background(), input, mr)
+	assert.NoError(t, err)
+	assert.Equal(t, file, output)
+
+	mr.AssertExpectations(t)
+}
+
+func TestJobTokenSnapshotExportFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	or.mam.On("ExportTokenSnapshot", mock.Anything, "snapshot1").Return(nil, fmt.Errorf("pop"))
+
+	_, err := handlers[core.JobTypeTokenSnapshotExport].Run(context.Background(), &core.JobTokenSnapshotExportInput{Snapshot: "snapshot1"}, &jobmocks.Runner{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestJobTokenTransfersExport(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	input := handlers[core.JobTypeTokenTransfersExport].NewInput().(*core.TokenTransferExport)
+	reader := io.NopCloser(strings.NewReader("parquet"))
+	file := &jobs.OutputFile{File: "job1.parquet", Size: 7}
+	mr := &jobmocks.Runner{}
+	or.mam.On("ExportTokenTransfers", mock.Anything, input).Return(reader, nil).Run(func(args mock.Arguments) {
+		args[1].(*core.TokenTransferExport).Format = core.TokenExportFormatParquet
+	})
+	mr.On("WriteFile", "parquet", reader).Return(file, nil)
+
+	output, err := handlers[core.JobTypeTokenTransfersExport].Run(context.Background(), input, mr)
+	assert.NoError(t, err)
+	assert.Equal(t, file, output)
+
+	mr.AssertExpectations(t)
+}
+
+func TestJobTokenTransfersExportFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	or.mam.On("ExportTokenTransfers", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := handlers[core.JobTypeTokenTransfersExport].Run(context.Background(), &core.TokenTransferExport{}, &jobmocks.Runner{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestJobTokenLedgerExport(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	input := handlers[core.JobTypeTokenLedgerExport].NewInput().(*core.TokenLedgerExport)
+	input.Format = core.TokenExportFormatCSV
+	reader := io.NopCloser(strings.NewReader("a,b,c"))
+	file := &jobs.OutputFile{File: "job1.csv", Size: 5}
+	mr := &jobmocks.Runner{}
+	or.mam.On("ExportTokenLedger", mock.Anything, input).Return(reader, nil)
+	mr.On("WriteFile", "csv", reader).Return(file, nil)
+
+	output, err := handlers[core.JobTypeTokenLedgerExport].Run(context.Background(), input, mr)
+	assert.NoError(t, err)
+	assert.Equal(t, file, output)
+
+	mr.AssertExpectations(t)
+}
+
+func TestJobTokenLedgerExportFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	handlers := registerTestJobHandlers(or)
+
+	or.mam.On("ExportTokenLedger", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := handlers[core.JobTypeTokenLedgerExport].Run(context.Background(), &core.TokenLedgerExport{}, &jobmocks.Runner{})
+	assert.EqualError(t, err, "pop")
+}
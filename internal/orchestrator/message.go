@@ -18,10 +18,14 @@ package orchestrator
 
 import (
 	"context"
+	"database/sql/driver"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
 func (or *orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut) (reply *core.MessageInOut, err error) {
@@ -30,3 +34,171 @@ func (or *orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut
 	}
 	return or.PrivateMessaging().RequestReply(ctx, msg)
 }
+
+// messageThreadLimit is the most messages returned for a thread, to bound the cost of walking a large thread
+const messageThreadLimit = 1000
+
+// GetMessageThread finds the root of the thread of the message, by following the parentMessage of each message
+// until it reaches one without a parent (or whose parent is not on this node), then collects all the replies
+// to the root a generation at a time.
+func (or *orchestrator) GetMessageThread(ctx context.Context, id string) (*core.MessageThread, error) {
+	msg, err := or.getMessageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	root := msg
+	seen := map[fftypes.UUID]bool{*msg.Header.ID: true}
+	for root.Header.ParentMessage != nil && !seen[*root.Header.ParentMessage] && len(seen) < messageThreadLimit {
+		parent, err := or.database().GetMessageByID(ctx, or.namespace.Name, root.Header.ParentMessage)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			break
+		}
+		seen[*parent.Header.ID] = true
+		root = parent
+	}
+
+	thread := &core.MessageThread{
+		Root:     root.Header.ID,
+		Messages: []*core.Message{root},
+	}
+	seen = map[fftypes.UUID]bool{*root.Header.ID: true}
+	generation := []driver.Value{root.Header.ID}
+	for len(generation) > 0 {
+		remaining := messageThreadLimit - len(thread.Messages)
+		fb := database.MessageQueryFactory.NewFilter(ctx)
+		filter := fb.And(fb.In("parentmessage", generation)).Sort("sequence").Limit(uint64(remaining + 1))
+		replies, _, err := or.database().GetMessages(ctx, or.namespace.Name, filter)
+		if err != nil {
+			return nil, err
+		}
+		if len(replies) > remaining {
+			replies = replies[:remaining]
+			thread.Truncated = true
+		}
+		generation = make([]driver.Value, 0, len(replies))
+		for _, reply := range replies {
+			if !seen[*reply.Header.ID] {
+				seen[*reply.Header.ID] = true
+				thread.Messages = append(thread.Messages, reply)
+				generation = append(generation, reply.Header.ID)
+			}
+		}
+		if thread.Truncated {
+			break
+		}
+	}
+	return thread, nil
+}
+
+func (or *orchestrator) GetQuarantinedBatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.QuarantinedBatch, *ffapi.FilterResult, error) {
+	return or.database().GetQuarantinedBatches(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetQuarantinedBatchByID(ctx context.Context, id string) (*core.QuarantinedBatch, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	quarantined, err := or.database().GetQuarantinedBatchByID(ctx, or.namespace.Name, u)
+	if err != nil {
+		return nil, err
+	}
+	if quarantined == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return quarantined, nil
+}
+
+func (or *orchestrator) GetSLABreaches(ctx context.Context, filter ffapi.AndFilter) ([]*core.SLABreach, *ffapi.FilterResult, error) {
+	return or.database().GetSLABreaches(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetSLABreachByID(ctx context.Context, id string) (*core.SLABreach, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	breach, err := or.database().GetSLABreachByID(ctx, or.namespace.Name, u)
+	if err != nil {
+		return nil, err
+	}
+	if breach == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return breach, nil
+}
+
+func (or *orchestrator) ReleaseQuarantinedBatch(ctx context.Context, id string) (*core.QuarantinedBatch, error) {
+	quarantined, err := or.GetQuarantinedBatchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.ReleaseQuarantinedBatch(ctx, quarantined)
+}
+
+func (or *orchestrator) DiscardQuarantinedBatch(ctx context.Context, id string) (*core.QuarantinedBatch, error) {
+	quarantined, err := or.GetQuarantinedBatchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.DiscardQuarantinedBatch(ctx, quarantined)
+}
+
+func (or *orchestrator) AcceptMessage(ctx context.Context, id string, input *core.MessageAckInput) (*core.MessageAck, error) {
+	msg, err := or.getMessageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.AckMessage(ctx, msg, core.MessageAckOutcomeAccepted, input)
+}
+
+func (or *orchestrator) RejectMessage(ctx context.Context, id string, input *core.MessageAckInput) (*core.MessageAck, error) {
+	msg, err := or.getMessageByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.AckMessage(ctx, msg, core.MessageAckOutcomeRejected, input)
+}
+
+func (or *orchestrator) GetMessageAcks(ctx context.Context, filter ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error) {
+	return or.database().GetMessageAcks(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetQuarantinedBlobs(ctx context.Context, filter ffapi.AndFilter) ([]*core.QuarantinedBlob, *ffapi.FilterResult, error) {
+	return or.database().GetQuarantinedBlobs(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetQuarantinedBlobByID(ctx context.Context, id string) (*core.QuarantinedBlob, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	quarantined, err := or.database().GetQuarantinedBlobByID(ctx, or.namespace.Name, u)
+	if err != nil {
+		return nil, err
+	}
+	if quarantined == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return quarantined, nil
+}
+
+func (or *orchestrator) ReleaseQuarantinedBlob(ctx context.Context, id string) (*core.QuarantinedBlob, error) {
+	quarantined, err := or.GetQuarantinedBlobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.ReleaseQuarantinedBlob(ctx, quarantined)
+}
+
+func (or *orchestrator) DiscardQuarantinedBlob(ctx context.Context, id string) (*core.QuarantinedBlob, error) {
+	quarantined, err := or.GetQuarantinedBlobByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.DiscardQuarantinedBlob(ctx, quarantined)
+}
@@ -18,10 +18,16 @@ package orchestrator
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestRequestReplyMissingGroup(t *testing.T) {
@@ -44,3 +50,353 @@ func TestRequestReply(t *testing.T) {
 	_, err := or.RequestReply(context.Background(), input)
 	assert.NoError(t, err)
 }
+
+func newTestThreadMessage(parent *core.Message) *core.Message {
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			ID: fftypes.NewUUID(),
+		},
+	}
+	if parent != nil {
+		msg.Header.ParentMessage = parent.Header.ID
+	}
+	return msg
+}
+
+func TestGetMessageThread(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	root := newTestThreadMessage(nil)
+	reply1 := newTestThreadMessage(root)
+	reply2 := newTestThreadMessage(root)
+	reply3 := newTestThreadMessage(reply1)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", reply1.Header.ID).Return(reply1, nil)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", root.Header.ID).Return(root, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasPrefix(fi.String(), fmt.Sprintf("( parentmessage IN ['%s'] )", root.Header.ID))
+	})).Return([]*core.Message{reply1, reply2}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasPrefix(fi.String(), fmt.Sprintf("( parentmessage IN ['%s','%s'] )", reply1.Header.ID, reply2.Header.ID))
+	})).Return([]*core.Message{reply3}, nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return strings.HasPrefix(fi.String(), fmt.Sprintf("( parentmessage IN ['%s'] )", reply3.Header.ID))
+	})).Return([]*core.Message{}, nil, nil)
+
+	thread, err := or.GetMessageThread(context.Background(), reply1.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, root.Header.ID, thread.Root)
+	assert.Equal(t, []*core.Message{root, reply1, reply2, reply3}, thread.Messages)
+	assert.False(t, thread.Truncated)
+}
+
+func TestGetMessageThreadParentNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	msg := newTestThreadMessage(newTestThreadMessage(nil))
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ParentMessage).Return(nil, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return([]*core.Message{}, nil, nil)
+
+	thread, err := or.GetMessageThread(context.Background(), msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, msg.Header.ID, thread.Root)
+	assert.Equal(t, []*core.Message{msg}, thread.Messages)
+}
+
+func TestGetMessageThreadTruncated(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	root := newTestThreadMessage(nil)
+	replies := make([]*core.Message, messageThreadLimit)
+	for i := range replies {
+		replies[i] = newTestThreadMessage(root)
+	}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", root.Header.ID).Return(root, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return(replies, nil, nil).Once()
+
+	thread, err := or.GetMessageThread(context.Background(), root.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Len(t, thread.Messages, messageThreadLimit)
+	assert.True(t, thread.Truncated)
+}
+
+func TestGetMessageThreadBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetMessageThread(context.Background(), "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetMessageThreadParentFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	msg := newTestThreadMessage(newTestThreadMessage(nil))
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ParentMessage).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.GetMessageThread(context.Background(), msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetMessageThreadRepliesFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	root := newTestThreadMessage(nil)
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", root.Header.ID).Return(root, nil)
+	or.mdi.On("GetMessages", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetMessageThread(context.Background(), root.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetQuarantinedBatches(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetQuarantinedBatches", mock.Anything, "ns", mock.Anything).Return([]*core.QuarantinedBatch{}, nil, nil)
+	fb := database.QuarantineQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetQuarantinedBatches(or.ctx, fb.And(fb.Eq("state", core.QuarantineStatePending)))
+	assert.NoError(t, err)
+}
+
+func TestGetQuarantinedBatchByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	quarantined := &core.QuarantinedBatch{ID: fftypes.NewUUID()}
+	or.mdi.On("GetQuarantinedBatchByID", mock.Anything, "ns", quarantined.ID).Return(quarantined, nil)
+	res, err := or.GetQuarantinedBatchByID(or.ctx, quarantined.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, quarantined, res)
+}
+
+func TestGetQuarantinedBatchByIDBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetQuarantinedBatchByID(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetQuarantinedBatchByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetQuarantinedBatchByID", mock.Anything, "ns", id).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetQuarantinedBatchByID(or.ctx, id.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetQuarantinedBatchByIDNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetQuarantinedBatchByID", mock.Anything, "ns", id).Return(nil, nil)
+	_, err := or.GetQuarantinedBatchByID(or.ctx, id.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetSLABreaches(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSLABreaches", mock.Anything, "ns", mock.Anything).Return([]*core.SLABreach{}, nil, nil)
+	fb := database.SLABreachQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetSLABreaches(or.ctx, fb.And(fb.Eq("org", "did:firefly:org/org2")))
+	assert.NoError(t, err)
+}
+
+func TestGetSLABreachByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	breach := &core.SLABreach{ID: fftypes.NewUUID()}
+	or.mdi.On("GetSLABreachByID", mock.Anything, "ns", breach.ID).Return(breach, nil)
+	res, err := or.GetSLABreachByID(or.ctx, breach.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, breach, res)
+}
+
+func TestGetSLABreachByIDBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetSLABreachByID(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetSLABreachByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetSLABreachByID", mock.Anything, "ns", id).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetSLABreachByID(or.ctx, id.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetSLABreachByIDNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetSLABreachByID", mock.Anything, "ns", id).Return(nil, nil)
+	_, err := or.GetSLABreachByID(or.ctx, id.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestReleaseQuarantinedBatch(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	quarantined := &core.QuarantinedBatch{ID: fftypes.NewUUID()}
+	or.mdi.On("GetQuarantinedBatchByID", mock.Anything, "ns", quarantined.ID).Return(quarantined, nil)
+	or.mem.On("ReleaseQuarantinedBatch", mock.Anything, quarantined).Return(quarantined, nil)
+	_, err := or.ReleaseQuarantinedBatch(or.ctx, quarantined.ID.String())
+	assert.NoError(t, err)
+
+	_, err = or.ReleaseQuarantinedBatch(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDiscardQuarantinedBatch(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	quarantined := &core.QuarantinedBatch{ID: fftypes.NewUUID()}
+	or.mdi.On("GetQuarantinedBatchByID", mock.Anything, "ns", quarantined.ID).Return(quarantined, nil)
+	or.mem.On("DiscardQuarantinedBatch", mock.Anything, quarantined).Return(quarantined, nil)
+	_, err := or.DiscardQuarantinedBatch(or.ctx, quarantined.ID.String())
+	assert.NoError(t, err)
+
+	_, err = or.DiscardQuarantinedBatch(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestAcceptMessage(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	input := &core.MessageAckInput{Reason: "checked"}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mem.On("AckMessage", mock.Anything, msg, core.MessageAckOutcomeAccepted, input).Return(&core.MessageAck{}, nil)
+	_, err := or.AcceptMessage(or.ctx, msg.Header.ID.String(), input)
+	assert.NoError(t, err)
+
+	_, err = or.AcceptMessage(or.ctx, "bad", input)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestRejectMessage(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	msg := &core.Message{Header: core.MessageHeader{ID: fftypes.NewUUID()}}
+	input := &core.MessageAckInput{Reason: "over limit"}
+	or.mdi.On("GetMessageByID", mock.Anything, "ns", msg.Header.ID).Return(msg, nil)
+	or.mem.On("AckMessage", mock.Anything, msg, core.MessageAckOutcomeRejected, input).Return(&core.MessageAck{}, nil)
+	_, err := or.RejectMessage(or.ctx, msg.Header.ID.String(), input)
+	assert.NoError(t, err)
+
+	_, err = or.RejectMessage(or.ctx, "bad", input)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetMessageAcks(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetMessageAcks", mock.Anything, "ns", mock.Anything).Return([]*core.MessageAck{}, nil, nil)
+	fb := database.MessageAckQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetMessageAcks(or.ctx, fb.And(fb.Eq("outcome", core.MessageAckOutcomeRejected)))
+	assert.NoError(t, err)
+}
+
+func TestGetQuarantinedBlobs(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetQuarantinedBlobs", mock.Anything, "ns", mock.Anything).Return([]*core.QuarantinedBlob{}, nil, nil)
+	fb := database.QuarantineQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetQuarantinedBlobs(or.ctx, fb.And(fb.Eq("state", core.QuarantineStatePending)))
+	assert.NoError(t, err)
+}
+
+func TestGetQuarantinedBlobByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	quarantined := &core.QuarantinedBlob{ID: fftypes.NewUUID()}
+	or.mdi.On("GetQuarantinedBlobByID", mock.Anything, "ns", quarantined.ID).Return(quarantined, nil)
+	res, err := or.GetQuarantinedBlobByID(or.ctx, quarantined.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, quarantined, res)
+}
+
+func TestGetQuarantinedBlobByIDBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetQuarantinedBlobByID(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetQuarantinedBlobByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetQuarantinedBlobByID", mock.Anything, "ns", id).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetQuarantinedBlobByID(or.ctx, id.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetQuarantinedBlobByIDNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetQuarantinedBlobByID", mock.Anything, "ns", id).Return(nil, nil)
+	_, err := or.GetQuarantinedBlobByID(or.ctx, id.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestReleaseQuarantinedBlob(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	quarantined := &core.QuarantinedBlob{ID: fftypes.NewUUID()}
+	or.mdi.On("GetQuarantinedBlobByID", mock.Anything, "ns", quarantined.ID).Return(quarantined, nil)
+	or.mem.On("ReleaseQuarantinedBlob", mock.Anything, quarantined).Return(quarantined, nil)
+	_, err := or.ReleaseQuarantinedBlob(or.ctx, quarantined.ID.String())
+	assert.NoError(t, err)
+
+	_, err = or.ReleaseQuarantinedBlob(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDiscardQuarantinedBlob(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	quarantined := &core.QuarantinedBlob{ID: fftypes.NewUUID()}
+	or.mdi.On("GetQuarantinedBlobByID", mock.Anything, "ns", quarantined.ID).Return(quarantined, nil)
+	or.mem.On("DiscardQuarantinedBlob", mock.Anything, quarantined).Return(quarantined, nil)
+	_, err := or.DiscardQuarantinedBlob(or.ctx, quarantined.ID.String())
+	assert.NoError(t, err)
+
+	_, err = or.DiscardQuarantinedBlob(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
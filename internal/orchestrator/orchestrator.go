@@ -35,6 +35,7 @@ import (
 	"github.com/hyperledger/firefly/internal/definitions"
 	"github.com/hyperledger/firefly/internal/events"
 	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/jobs"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/internal/networkmap"
@@ -72,9 +73,11 @@ type Orchestrator interface {
 	NetworkMap() networkmap.Manager
 	Operations() operations.Manager
 	Identity() identity.Manager
+	Jobs() jobs.Manager
 
 	// Status
 	GetStatus(ctx context.Context) (*core.NamespaceStatus, error)
+	GetNetworkStatus(ctx context.Context, filter ffapi.AndFilter) (*core.NetworkStatus, error)
 
 	// Subscription management
 	GetSubscriptions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error)
@@ -83,6 +86,34 @@ type Orchestrator interface {
 	CreateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error)
 	CreateUpdateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error)
 	DeleteSubscription(ctx context.Context, id string) error
+	RewindSubscription(ctx context.Context, id string, rewind *core.SubscriptionRewind) (*core.SubscriptionRewind, error)
+	PullSubscription(ctx context.Context, id string, req *core.SubscriptionPull) ([]*core.PulledEvent, error)
+	AckSubscription(ctx context.Context, id string, ack *core.SubscriptionAck) error
+	CreateSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) (*core.SubscriptionTemplate, error)
+	GetSubscriptionTemplates(ctx context.Context, filter ffapi.AndFilter) ([]*core.SubscriptionTemplate, *ffapi.FilterResult, error)
+	GetSubscriptionTemplateByNameOrID(ctx context.Context, nameOrID string) (*core.SubscriptionTemplate, error)
+	DeleteSubscriptionTemplate(ctx context.Context, nameOrID string) error
+	CreateEventRule(ctx context.Context, rule *core.EventRule) (*core.EventRule, error)
+	GetEventRules(ctx context.Context, filter ffapi.AndFilter) ([]*core.EventRule, *ffapi.FilterResult, error)
+	GetEventRuleByNameOrID(ctx context.Context, nameOrID string) (*core.EventRule, error)
+	DeleteEventRule(ctx context.Context, nameOrID string) error
+	GetDeadLetters(ctx context.Context, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)
+	GetDeadLetterByID(ctx context.Context, id string) (*core.DeadLetter, error)
+	RequeueDeadLetter(ctx context.Context, id string) (*core.DeadLetter, error)
+	DiscardDeadLetter(ctx context.Context, id string) (*core.DeadLetter, error)
+	GetQuarantinedBatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.QuarantinedBatch, *ffapi.FilterResult, error)
+	GetQuarantinedBatchByID(ctx context.Context, id string) (*core.QuarantinedBatch, error)
+	ReleaseQuarantinedBatch(ctx context.Context, id string) (*core.QuarantinedBatch, error)
+	DiscardQuarantinedBatch(ctx context.Context, id string) (*core.QuarantinedBatch, error)
+	GetQuarantinedBlobs(ctx context.Context, filter ffapi.AndFilter) ([]*core.QuarantinedBlob, *ffapi.FilterResult, error)
+	GetQuarantinedBlobByID(ctx context.Context, id string) (*core.QuarantinedBlob, error)
+	ReleaseQuarantinedBlob(ctx context.Context, id string) (*core.QuarantinedBlob, error)
+	DiscardQuarantinedBlob(ctx context.Context, id string) (*core.QuarantinedBlob, error)
+	GetSLABreaches(ctx context.Context, filter ffapi.AndFilter) ([]*core.SLABreach, *ffapi.FilterResult, error)
+	GetSLABreachByID(ctx context.Context, id string) (*core.SLABreach, error)
+	AcceptMessage(ctx context.Context, id string, input *core.MessageAckInput) (*core.MessageAck, error)
+	RejectMessage(ctx context.Context, id string, input *core.MessageAckInput) (*core.MessageAck, error)
+	GetMessageAcks(ctx context.Context, filter ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error)
 
 	// Data Query
 	GetNamespace(ctx context.Context) *core.Namespace
@@ -93,10 +124,11 @@ type Orchestrator interface {
 	GetTransactions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Transaction, *ffapi.FilterResult, error)
 	GetMessageByID(ctx context.Context, id string) (*core.Message, error)
 	GetMessageByIDWithData(ctx context.Context, id string) (*core.MessageInOut, error)
-	GetMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
+	GetMessages(ctx context.Context, filter ffapi.AndFilter, fields ...string) ([]*core.Message, *ffapi.FilterResult, error)
 	GetMessagesWithData(ctx context.Context, filter ffapi.AndFilter) ([]*core.MessageInOut, *ffapi.FilterResult, error)
 	GetMessageTransaction(ctx context.Context, id string) (*core.Transaction, error)
 	GetMessageEvents(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error)
+	GetMessageThread(ctx context.Context, id string) (*core.MessageThread, error)
 	GetMessageData(ctx context.Context, id string) (core.DataArray, error)
 	GetMessagesForData(ctx context.Context, dataID string, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)
 	GetBatchByID(ctx context.Context, id string) (*core.BatchPersisted, error)
@@ -109,7 +141,7 @@ type Orchestrator interface {
 	GetDatatypes(ctx context.Context, filter ffapi.AndFilter) ([]*core.Datatype, *ffapi.FilterResult, error)
 	GetOperationByID(ctx context.Context, id string) (*core.Operation, error)
 	GetOperationByIDWithStatus(ctx context.Context, id string) (*core.OperationWithDetail, error)
-	GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error)
+	GetOperations(ctx context.Context, filter ffapi.AndFilter, fields ...string) ([]*core.Operation, *ffapi.FilterResult, error)
 	GetEventByID(ctx context.Context, id string) (*core.Event, error)
 	GetEventByIDWithReference(ctx context.Context, id string) (*core.EnrichedEvent, error)
 	GetEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error)
@@ -118,6 +150,7 @@ type Orchestrator interface {
 	GetBlockchainEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
 	GetPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.Pin, *ffapi.FilterResult, error)
 	GetNextPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.NextPin, *ffapi.FilterResult, error)
+	GetTopicSequences(ctx context.Context, filter ffapi.AndFilter) ([]*core.TopicSequence, *ffapi.FilterResult, error)
 	RewindPins(ctx context.Context, rewind *core.PinRewind) (*core.PinRewind, error)
 
 	// Charts
@@ -131,6 +164,17 @@ type Orchestrator interface {
 
 	// Authorizer
 	Authorize(ctx context.Context, authReq *fftypes.AuthReq) error
+	AuthorizeRole(ctx context.Context, authReq *fftypes.AuthReq, required core.Role) error
+
+	// Role bindings
+	CreateRoleBinding(ctx context.Context, binding *core.RoleBinding) (*core.RoleBinding, error)
+	GetRoleBindings(ctx context.Context, filter ffapi.AndFilter) ([]*core.RoleBinding, *ffapi.FilterResult, error)
+	GetRoleBindingByID(ctx context.Context, id string) (*core.RoleBinding, error)
+	DeleteRoleBinding(ctx context.Context, id string) error
+
+	// Audit log
+	RecordAuditLogEntry(ctx context.Context, entry *core.AuditLogEntry) error
+	GetAuditLog(ctx context.Context, filter ffapi.AndFilter) ([]*core.AuditLogEntry, *ffapi.FilterResult, error)
 }
 
 type BlockchainPlugin struct {
@@ -182,6 +226,7 @@ type Plugins struct {
 type Config struct {
 	DefaultKey          string
 	KeyNormalization    string
+	Confirmations       int
 	Multiparty          multiparty.Config
 	TokenBroadcastNames map[string]string
 }
@@ -213,6 +258,8 @@ type orchestrator struct {
 	cacheManager   cache.Manager
 	operations     operations.Manager
 	txHelper       txcommon.Helper
+	jobs           jobs.Manager
+	roles          roleBindings
 }
 
 func NewOrchestrator(ns *core.Namespace, config Config, plugins *Plugins, metrics metrics.Manager, cacheManager cache.Manager) Orchestrator {
@@ -290,6 +337,12 @@ func (or *orchestrator) Start() (err error) {
 	if err == nil {
 		err = or.operations.Start()
 	}
+	if err == nil {
+		err = or.assets.Start()
+	}
+	if err == nil {
+		err = or.jobs.Start()
+	}
 
 	or.started = true
 	return err
@@ -323,6 +376,14 @@ func (or *orchestrator) WaitStop() {
 		or.operations.WaitStop()
 		or.operations = nil
 	}
+	if or.assets != nil {
+		or.assets.WaitStop()
+		or.assets = nil
+	}
+	if or.jobs != nil {
+		or.jobs.WaitStop()
+		or.jobs = nil
+	}
 	or.startedLock.Lock()
 	defer or.startedLock.Unlock()
 	or.started = false
@@ -366,6 +427,10 @@ func (or *orchestrator) Assets() assets.Manager {
 	return or.assets
 }
 
+func (or *orchestrator) Jobs() jobs.Manager {
+	return or.jobs
+}
+
 func (or *orchestrator) Contracts() contracts.Manager {
 	return or.contracts
 }
@@ -459,7 +524,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 	}
 
 	if or.operations == nil {
-		if or.operations, err = operations.NewOperationsManager(ctx, or.namespace.Name, or.database(), or.txHelper, or.cacheManager); err != nil {
+		if or.operations, err = operations.NewOperationsManager(ctx, or.namespace.Name, or.config.Confirmations, or.database(), or.blockchain(), or.txHelper, or.cacheManager); err != nil {
 			return err
 		}
 	}
@@ -508,7 +573,7 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 
 	if or.blockchain() != nil {
 		if or.contracts == nil {
-			or.contracts, err = contracts.NewContractManager(ctx, or.namespace.Name, or.database(), or.blockchain(), or.data, or.broadcast, or.messaging, or.batch, or.identity, or.operations, or.txHelper, or.syncasync)
+			or.contracts, err = contracts.NewContractManager(ctx, or.namespace.Name, or.config.Confirmations, or.database(), or.blockchain(), or.data, or.broadcast, or.messaging, or.batch, or.identity, or.operations, or.txHelper, or.syncasync)
 			if err != nil {
 				return err
 			}
@@ -516,10 +581,18 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 	}
 
 	if or.assets == nil {
-		or.assets, err = assets.NewAssetManager(ctx, or.namespace.Name, or.config.KeyNormalization, or.database(), or.tokens(), or.identity, or.syncasync, or.broadcast, or.messaging, or.metrics, or.operations, or.contracts, or.txHelper, or.cacheManager)
+		or.assets, err = assets.NewAssetManager(ctx, or.namespace.Name, or.config.KeyNormalization, or.config.Confirmations, or.database(), or.tokens(), or.sharedstorage(), or.identity, or.syncasync, or.broadcast, or.messaging, or.metrics, or.operations, or.contracts, or.txHelper, or.cacheManager)
+		if err != nil {
+			return err
+		}
+	}
+
+	if or.jobs == nil {
+		or.jobs, err = jobs.NewJobManager(ctx, or.namespace.Name, or.database())
 		if err != nil {
 			return err
 		}
+		or.registerJobHandlers()
 	}
 
 	if or.defsender == nil {
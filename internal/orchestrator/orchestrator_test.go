@@ -41,6 +41,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/eventmocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
 	"github.com/hyperledger/firefly/mocks/identitymocks"
+	"github.com/hyperledger/firefly/mocks/jobmocks"
 	"github.com/hyperledger/firefly/mocks/metricsmocks"
 	"github.com/hyperledger/firefly/mocks/multipartymocks"
 	"github.com/hyperledger/firefly/mocks/networkmapmocks"
@@ -85,6 +86,7 @@ type testOrchestrator struct {
 	mdh *definitionsmocks.Handler
 	mmp *multipartymocks.Manager
 	mds *definitionsmocks.Sender
+	mjm *jobmocks.Manager
 }
 
 func (tor *testOrchestrator) cleanup(t *testing.T) {
@@ -111,6 +113,7 @@ func (tor *testOrchestrator) cleanup(t *testing.T) {
 	tor.mae.AssertExpectations(t)
 	tor.mdh.AssertExpectations(t)
 	tor.mmp.AssertExpectations(t)
+	tor.mjm.AssertExpectations(t)
 }
 
 func newTestOrchestrator() *testOrchestrator {
@@ -146,6 +149,7 @@ func newTestOrchestrator() *testOrchestrator {
 		mdh: &definitionsmocks.Handler{},
 		mmp: &multipartymocks.Manager{},
 		mds: &definitionsmocks.Sender{},
+		mjm: &jobmocks.Manager{},
 	}
 	tor.orchestrator.multiparty = tor.mmp
 	tor.orchestrator.data = tor.mdm
@@ -164,6 +168,7 @@ func newTestOrchestrator() *testOrchestrator {
 	tor.orchestrator.txHelper = tor.mth
 	tor.orchestrator.defhandler = tor.mdh
 	tor.orchestrator.defsender = tor.mds
+	tor.orchestrator.jobs = tor.mjm
 	tor.orchestrator.config.Multiparty.Enabled = true
 	tor.orchestrator.plugins = &Plugins{
 		Blockchain: BlockchainPlugin{
@@ -247,6 +252,7 @@ func TestInitOK(t *testing.T) {
 	assert.Equal(t, or.mnm, or.NetworkMap())
 	assert.Equal(t, or.mmp, or.MultiParty())
 	assert.Equal(t, or.identity, or.Identity())
+	assert.Equal(t, or.mjm, or.Jobs())
 }
 
 func TestCacheInitFail(t *testing.T) {
@@ -401,6 +407,26 @@ func TestInitAssetsComponentFail(t *testing.T) {
 	assert.Regexp(t, "FF10128", err)
 }
 
+func TestInitJobsComponentFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.plugins.Database.Plugin = nil
+	or.jobs = nil
+	or.mmp.On("ConfigureContract", mock.Anything, mock.Anything).Return(nil)
+	err := or.initManagers(context.Background())
+	assert.Regexp(t, "FF10128", err)
+}
+
+func TestInitJobsComponentOk(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.jobs = nil
+	or.mmp.On("ConfigureContract", mock.Anything, mock.Anything).Return(nil)
+	err := or.initManagers(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, or.Jobs())
+}
+
 func TestInitContractsComponentFail(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -441,12 +467,16 @@ func TestStartStopOk(t *testing.T) {
 	or.mbm.On("Start").Return(nil)
 	or.msd.On("Start").Return(nil)
 	or.mom.On("Start").Return(nil)
+	or.mam.On("Start").Return(nil)
+	or.mjm.On("Start").Return(nil)
 	or.mba.On("WaitStop").Return(nil)
 	or.mbm.On("WaitStop").Return(nil)
 	or.mdm.On("WaitStop").Return(nil)
 	or.msd.On("WaitStop").Return(nil)
 	or.mom.On("WaitStop").Return(nil)
 	or.mem.On("WaitStop").Return(nil)
+	or.mam.On("WaitStop").Return(nil)
+	or.mjm.On("WaitStop").Return(nil)
 	err := or.Start()
 	assert.NoError(t, err)
 	or.WaitStop()
@@ -0,0 +1,161 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// roleBindings caches the roles bound to each principal in the namespace, as they are checked
+// on every request. It is reset whenever a binding is created or deleted.
+type roleBindings struct {
+	mux         sync.Mutex
+	byPrincipal map[string][]core.Role
+}
+
+func (rb *roleBindings) reset() {
+	rb.mux.Lock()
+	defer rb.mux.Unlock()
+	rb.byPrincipal = nil
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal records a principal authenticated by the transport of the request, such as the identity
+// mapped from a verified client certificate
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// TransportPrincipal is the principal authenticated by the transport of the request, which unlike the
+// headers can be relied upon before the auth plugin has run. It is "" if the transport did not authenticate one
+func TransportPrincipal(ctx context.Context) string {
+	principal, _ := ctx.Value(principalContextKey{}).(string)
+	return principal
+}
+
+// Principal is the identity of the caller that roles are bound to - either authenticated by the transport,
+// from a header set by a trusted authenticating proxy, or the username of HTTP basic auth that has been
+// verified by the auth plugin
+func Principal(ctx context.Context, header http.Header) string {
+	if principal := TransportPrincipal(ctx); principal != "" {
+		return principal
+	}
+	if principalHeader := config.GetString(coreconfig.RBACPrincipalHeader); principalHeader != "" {
+		return header.Get(principalHeader)
+	}
+	username, _, _ := (&http.Request{Header: header}).BasicAuth()
+	return username
+}
+
+func (or *orchestrator) AuthorizeRole(ctx context.Context, authReq *fftypes.AuthReq, required core.Role) error {
+	p := Principal(ctx, authReq.Header)
+	if p == "" {
+		return i18n.NewError(ctx, coremsgs.MsgRBACNoPrincipal)
+	}
+
+	or.roles.mux.Lock()
+	defer or.roles.mux.Unlock()
+	if or.roles.byPrincipal == nil {
+		bindings, _, err := or.database().GetRoleBindings(ctx, or.namespace.Name, database.RoleBindingQueryFactory.NewFilter(ctx).And())
+		if err != nil {
+			return err
+		}
+		or.roles.byPrincipal = make(map[string][]core.Role)
+		for _, b := range bindings {
+			or.roles.byPrincipal[b.Principal] = append(or.roles.byPrincipal[b.Principal], b.Role)
+		}
+	}
+	for _, role := range or.roles.byPrincipal[p] {
+		if core.RoleGrants(role, required) {
+			return nil
+		}
+	}
+	return i18n.NewError(ctx, coremsgs.MsgRBACForbidden, p, or.namespace.Name, required)
+}
+
+func (or *orchestrator) CreateRoleBinding(ctx context.Context, binding *core.RoleBinding) (*core.RoleBinding, error) {
+	binding.ID = fftypes.NewUUID()
+	binding.Created = fftypes.Now()
+	binding.Namespace = or.namespace.Name
+	if binding.Principal == "" {
+		return nil, i18n.NewError(ctx, i18n.MsgMissingRequiredField, "principal")
+	}
+	var err error
+	if binding.Role, err = fftypes.FFEnumParseString(ctx, "role", binding.Role.String()); err != nil {
+		return nil, err
+	}
+
+	fb := database.RoleBindingQueryFactory.NewFilter(ctx)
+	existing, _, err := or.database().GetRoleBindings(ctx, or.namespace.Name, fb.And(
+		fb.Eq("principal", binding.Principal),
+		fb.Eq("role", binding.Role),
+	))
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgAlreadyExists, "role binding", or.namespace.Name, binding.Principal+"/"+binding.Role.String())
+	}
+	if err := or.database().InsertRoleBinding(ctx, binding); err != nil {
+		return nil, err
+	}
+	or.roles.reset()
+	return binding, nil
+}
+
+func (or *orchestrator) GetRoleBindings(ctx context.Context, filter ffapi.AndFilter) ([]*core.RoleBinding, *ffapi.FilterResult, error) {
+	return or.database().GetRoleBindings(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetRoleBindingByID(ctx context.Context, id string) (*core.RoleBinding, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	binding, err := or.database().GetRoleBindingByID(ctx, or.namespace.Name, u)
+	if err != nil {
+		return nil, err
+	}
+	if binding == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return binding, nil
+}
+
+func (or *orchestrator) DeleteRoleBinding(ctx context.Context, id string) error {
+	binding, err := or.GetRoleBindingByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := or.database().DeleteRoleBindingByID(ctx, or.namespace.Name, binding.ID); err != nil {
+		return err
+	}
+	or.roles.reset()
+	return nil
+}
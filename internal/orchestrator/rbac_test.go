@@ -0,0 +1,258 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func basicAuthReq(username string) *fftypes.AuthReq {
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.SetBasicAuth(username, "secret")
+	return &fftypes.AuthReq{Header: req.Header}
+}
+
+func TestAuthorizeRole(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return([]*core.RoleBinding{
+		{Principal: "team-a", Role: core.RoleSender},
+		{Principal: "team-a", Role: core.RoleTokenAdmin},
+		{Principal: "team-b", Role: core.RoleReader},
+	}, nil, nil).Once()
+
+	err := or.AuthorizeRole(or.ctx, basicAuthReq("team-a"), core.RoleSender)
+	assert.NoError(t, err)
+	err = or.AuthorizeRole(or.ctx, basicAuthReq("team-a"), core.RoleTokenAdmin)
+	assert.NoError(t, err)
+	err = or.AuthorizeRole(or.ctx, basicAuthReq("team-a"), core.RoleNamespaceAdmin)
+	assert.Regexp(t, "FF10591.*team-a.*ns-admin", err)
+	err = or.AuthorizeRole(or.ctx, basicAuthReq("team-b"), core.RoleReader)
+	assert.NoError(t, err)
+	err = or.AuthorizeRole(or.ctx, basicAuthReq("team-b"), core.RoleSender)
+	assert.Regexp(t, "FF10591", err)
+	err = or.AuthorizeRole(or.ctx, basicAuthReq("team-c"), core.RoleReader)
+	assert.Regexp(t, "FF10591", err)
+}
+
+func TestAuthorizeRolePrincipalHeader(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	config.Set(coreconfig.RBACPrincipalHeader, "X-Forwarded-User")
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return([]*core.RoleBinding{
+		{Principal: "team-a", Role: core.RoleNamespaceAdmin},
+	}, nil, nil).Once()
+
+	// The basic auth username is ignored when a principal header is configured
+	err := or.AuthorizeRole(or.ctx, basicAuthReq("team-a"), core.RoleReader)
+	assert.Regexp(t, "FF10590", err)
+
+	authReq := &fftypes.AuthReq{Header: http.Header{}}
+	authReq.Header.Set("X-Forwarded-User", "team-a")
+	err = or.AuthorizeRole(or.ctx, authReq, core.RoleNamespaceAdmin)
+	assert.NoError(t, err)
+}
+
+func TestAuthorizeRoleContextPrincipal(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return([]*core.RoleBinding{
+		{Principal: "did:firefly:org/app1", Role: core.RoleSender},
+	}, nil, nil).Once()
+
+	// A principal authenticated by the transport takes precedence over the headers
+	ctx := WithPrincipal(or.ctx, "did:firefly:org/app1")
+	err := or.AuthorizeRole(ctx, basicAuthReq("team-a"), core.RoleSender)
+	assert.NoError(t, err)
+}
+
+func TestAuthorizeRoleNoPrincipal(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	err := or.AuthorizeRole(or.ctx, &fftypes.AuthReq{Header: http.Header{}}, core.RoleReader)
+	assert.Regexp(t, "FF10590", err)
+}
+
+func TestAuthorizeRoleLoadFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	err := or.AuthorizeRole(or.ctx, basicAuthReq("team-a"), core.RoleReader)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateRoleBinding(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return([]*core.RoleBinding{}, nil, nil)
+	or.mdi.On("InsertRoleBinding", mock.Anything, mock.Anything).Return(nil)
+	or.roles.byPrincipal = map[string][]core.Role{}
+
+	binding, err := or.CreateRoleBinding(or.ctx, &core.RoleBinding{
+		Principal: "team-a",
+		Role:      "SENDER",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "ns", binding.Namespace)
+	assert.Equal(t, core.RoleSender, binding.Role)
+	assert.NotNil(t, binding.ID)
+	assert.Nil(t, or.roles.byPrincipal)
+}
+
+func TestCreateRoleBindingBadInput(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateRoleBinding(or.ctx, &core.RoleBinding{Role: core.RoleSender})
+	assert.Regexp(t, "FF00112.*principal", err)
+
+	_, err = or.CreateRoleBinding(or.ctx, &core.RoleBinding{Principal: "team-a", Role: "superuser"})
+	assert.Regexp(t, "FF00172", err)
+}
+
+func TestCreateRoleBindingExists(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return([]*core.RoleBinding{{}}, nil, nil)
+
+	_, err := or.CreateRoleBinding(or.ctx, &core.RoleBinding{Principal: "team-a", Role: core.RoleSender})
+	assert.Regexp(t, "FF10193", err)
+}
+
+func TestCreateRoleBindingQueryFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.CreateRoleBinding(or.ctx, &core.RoleBinding{Principal: "team-a", Role: core.RoleSender})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestCreateRoleBindingInsertFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return([]*core.RoleBinding{}, nil, nil)
+	or.mdi.On("InsertRoleBinding", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := or.CreateRoleBinding(or.ctx, &core.RoleBinding{Principal: "team-a", Role: core.RoleSender})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetRoleBindings(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindings", mock.Anything, "ns", mock.Anything).Return([]*core.RoleBinding{}, nil, nil)
+	fb := database.RoleBindingQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetRoleBindings(or.ctx, fb.And(fb.Eq("principal", "team-a")))
+	assert.NoError(t, err)
+}
+
+func TestGetRoleBindingByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	binding := &core.RoleBinding{ID: fftypes.NewUUID()}
+	or.mdi.On("GetRoleBindingByID", mock.Anything, "ns", binding.ID).Return(binding, nil)
+
+	res, err := or.GetRoleBindingByID(or.ctx, binding.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, binding, res)
+}
+
+func TestGetRoleBindingByIDBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetRoleBindingByID(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetRoleBindingByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindingByID", mock.Anything, "ns", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.GetRoleBindingByID(or.ctx, fftypes.NewUUID().String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetRoleBindingByIDNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetRoleBindingByID", mock.Anything, "ns", mock.Anything).Return(nil, nil)
+
+	_, err := or.GetRoleBindingByID(or.ctx, fftypes.NewUUID().String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDeleteRoleBinding(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	binding := &core.RoleBinding{ID: fftypes.NewUUID()}
+	or.mdi.On("GetRoleBindingByID", mock.Anything, "ns", binding.ID).Return(binding, nil)
+	or.mdi.On("DeleteRoleBindingByID", mock.Anything, "ns", binding.ID).Return(nil)
+	or.roles.byPrincipal = map[string][]core.Role{}
+
+	err := or.DeleteRoleBinding(or.ctx, binding.ID.String())
+	assert.NoError(t, err)
+	assert.Nil(t, or.roles.byPrincipal)
+}
+
+func TestDeleteRoleBindingBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	err := or.DeleteRoleBinding(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDeleteRoleBindingFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	binding := &core.RoleBinding{ID: fftypes.NewUUID()}
+	or.mdi.On("GetRoleBindingByID", mock.Anything, "ns", binding.ID).Return(binding, nil)
+	or.mdi.On("DeleteRoleBindingByID", mock.Anything, "ns", binding.ID).Return(fmt.Errorf("pop"))
+
+	err := or.DeleteRoleBinding(or.ctx, binding.ID.String())
+	assert.EqualError(t, err, "pop")
+}
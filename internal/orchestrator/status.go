@@ -19,6 +19,8 @@ package orchestrator
 import (
 	"context"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -28,10 +30,14 @@ func (or *orchestrator) getPlugins() core.NamespaceStatusPlugins {
 	// Plugins can have more than one name, so they must be iterated over
 	tokensArray := make([]*core.NamespaceStatusPlugin, 0)
 	for _, plugin := range or.plugins.Tokens {
-		tokensArray = append(tokensArray, &core.NamespaceStatusPlugin{
+		status := &core.NamespaceStatusPlugin{
 			Name:       plugin.Name,
 			PluginType: plugin.Plugin.Name(),
-		})
+		}
+		if or.assets != nil {
+			status.Breaker = or.assets.GetTokenConnectorBreaker(plugin.Name)
+		}
+		tokensArray = append(tokensArray, status)
 	}
 
 	blockchainsArray := make([]*core.NamespaceStatusPlugin, 0)
@@ -129,3 +135,76 @@ func (or *orchestrator) GetStatus(ctx context.Context) (status *core.NamespaceSt
 
 	return status, nil
 }
+
+func (or *orchestrator) GetNetworkStatus(ctx context.Context, filter ffapi.AndFilter) (status *core.NetworkStatus, err error) {
+	status = &core.NetworkStatus{
+		Blockchain: make([]*core.BlockchainNetworkStatus, 0),
+		Listeners:  make([]*core.ListenerNetworkStatus, 0),
+	}
+
+	var chainHead int64
+	if or.blockchain() != nil {
+		bcStatus, err := or.blockchain().GetNetworkStatus(ctx)
+		if err != nil {
+			// Report the connector failure in the status, rather than failing the whole request
+			log.L(ctx).Warnf("Failed to query blockchain network status: %s", err)
+			bcStatus = &core.BlockchainNetworkStatus{Error: err.Error()}
+		}
+		bcStatus.Name = or.plugins.Blockchain.Name
+		bcStatus.PluginType = or.blockchain().Name()
+		chainHead = bcStatus.ChainHead
+		status.Blockchain = append(status.Blockchain, bcStatus)
+	}
+
+	listeners, res, err := or.database().GetContractListeners(ctx, or.namespace.Name, filter)
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		status.TotalListeners = res.TotalCount
+	}
+	lastBlocks := make(map[fftypes.UUID]int64, len(listeners))
+	if len(listeners) > 0 {
+		ids := make([]*fftypes.UUID, len(listeners))
+		for i, l := range listeners {
+			ids[i] = l.ID
+		}
+		events, err := or.database().GetLatestBlockchainEvents(ctx, or.namespace.Name, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			lastBlocks[*event.Listener] = event.Info.GetInt64("blockNumber")
+		}
+	}
+	for _, l := range listeners {
+		listenerStatus := &core.ListenerNetworkStatus{
+			ID:        l.ID,
+			Name:      l.Name,
+			BackendID: l.BackendID,
+		}
+		// Without a processed event there is no checkpoint to measure the lag from
+		if lastBlock, ok := lastBlocks[*l.ID]; ok {
+			listenerStatus.LastProcessedBlock = &lastBlock
+			if chainHead > 0 {
+				lag := chainHead - lastBlock
+				if lag < 0 {
+					lag = 0
+				}
+				listenerStatus.BlockLag = &lag
+			}
+		}
+		status.Listeners = append(status.Listeners, listenerStatus)
+	}
+
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	_, opsRes, err := or.database().GetOperations(ctx, or.namespace.Name, fb.And(fb.Eq("status", core.OpStatusPending)).Limit(1).Count(true))
+	if err != nil {
+		return nil, err
+	}
+	if opsRes != nil && opsRes.TotalCount != nil {
+		status.PendingTransactions = *opsRes.TotalCount
+	}
+
+	return status, nil
+}
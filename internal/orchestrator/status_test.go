@@ -21,9 +21,11 @@ import (
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -111,6 +113,7 @@ func TestGetStatusRegistered(t *testing.T) {
 	or.config.Multiparty.Node.Name = "node1"
 
 	or.mem.On("GetPlugins").Return(mockEventPlugins)
+	or.mam.On("GetTokenConnectorBreaker", "token").Return(nil)
 
 	status, err := or.GetStatus(or.ctx)
 	assert.NoError(t, err)
@@ -157,6 +160,7 @@ func TestGetStatusVerifierLookupFail(t *testing.T) {
 	or.mdi.On("GetVerifiers", or.ctx, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
 
 	or.mem.On("GetPlugins").Return(mockEventPlugins)
+	or.mam.On("GetTokenConnectorBreaker", "token").Return(nil)
 
 	_, err := or.GetStatus(or.ctx)
 	assert.Regexp(t, "pop", err)
@@ -199,6 +203,7 @@ func TestGetStatusWrongNodeOwner(t *testing.T) {
 	or.config.Multiparty.Node.Name = "node1"
 
 	or.mem.On("GetPlugins").Return(mockEventPlugins)
+	or.mam.On("GetTokenConnectorBreaker", "token").Return(nil)
 
 	status, err := or.GetStatus(or.ctx)
 	assert.NoError(t, err)
@@ -229,6 +234,7 @@ func TestGetStatusUnregistered(t *testing.T) {
 	or.config.Multiparty.Node.Name = "node1"
 
 	or.mem.On("GetPlugins").Return(mockEventPlugins)
+	or.mam.On("GetTokenConnectorBreaker", "token").Return(nil)
 
 	status, err := or.GetStatus(or.ctx)
 	assert.NoError(t, err)
@@ -243,6 +249,28 @@ func TestGetStatusUnregistered(t *testing.T) {
 
 }
 
+func TestGetStatusTokenConnectorBreakerOpen(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.config.Multiparty.Enabled = false
+
+	breaker := &core.TokenConnectorBreaker{
+		State:               core.TokenConnectorBreakerStateOpen,
+		ConsecutiveFailures: 5,
+		LastError:           "pop",
+	}
+	or.mem.On("GetPlugins").Return(mockEventPlugins)
+	or.mam.On("GetTokenConnectorBreaker", "token").Return(breaker)
+
+	status, err := or.GetStatus(or.ctx)
+	assert.NoError(t, err)
+
+	assert.Len(t, status.Plugins.Tokens, 1)
+	assert.Equal(t, "token", status.Plugins.Tokens[0].Name)
+	assert.Equal(t, breaker, status.Plugins.Tokens[0].Breaker)
+}
+
 func TestGetStatusOrgOnlyRegistered(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -272,6 +300,7 @@ func TestGetStatusOrgOnlyRegistered(t *testing.T) {
 	or.config.Multiparty.Node.Name = "node1"
 
 	or.mem.On("GetPlugins").Return(mockEventPlugins)
+	or.mam.On("GetTokenConnectorBreaker", "token").Return(nil)
 
 	status, err := or.GetStatus(or.ctx)
 	assert.NoError(t, err)
@@ -323,8 +352,123 @@ func TestGetStatusNodeError(t *testing.T) {
 	or.config.Multiparty.Node.Name = "node1"
 
 	or.mem.On("GetPlugins").Return(mockEventPlugins)
+	or.mam.On("GetTokenConnectorBreaker", "token").Return(nil)
 
 	_, err := or.GetStatus(or.ctx)
 	assert.EqualError(t, err, "pop")
 
 }
+
+func TestGetNetworkStatus(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	l1 := &core.ContractListener{ID: fftypes.NewUUID(), Name: "listener1", BackendID: "sub1"}
+	l2 := &core.ContractListener{ID: fftypes.NewUUID(), Name: "listener2", BackendID: "sub2"}
+	l3 := &core.ContractListener{ID: fftypes.NewUUID(), Name: "listener3", BackendID: "sub3"}
+	total := int64(3)
+	listenerTotal := int64(10)
+
+	or.mbi.On("GetNetworkStatus", or.ctx).Return(&core.BlockchainNetworkStatus{
+		ChainHead:   100,
+		EventStream: &core.EventStreamNetworkStatus{ID: "es1", Connected: true},
+	}, nil)
+	fb := database.ContractListenerQueryFactory.NewFilter(or.ctx)
+	filter := fb.And()
+	filter.Skip(5).Limit(3).Count(true)
+	or.mdi.On("GetContractListeners", or.ctx, "ns", filter).Return([]*core.ContractListener{l1, l2, l3}, &ffapi.FilterResult{TotalCount: &listenerTotal}, nil)
+	or.mdi.On("GetLatestBlockchainEvents", or.ctx, "ns", []*fftypes.UUID{l1.ID, l2.ID, l3.ID}).Return([]*core.BlockchainEvent{
+		{Listener: l1.ID, Info: fftypes.JSONObject{"blockNumber": "95"}},
+		{Listener: l3.ID, Info: fftypes.JSONObject{"blockNumber": "101"}},
+	}, nil)
+	or.mdi.On("GetOperations", or.ctx, "ns", mock.Anything).Return([]*core.Operation{}, &ffapi.FilterResult{TotalCount: &total}, nil)
+
+	status, err := or.GetNetworkStatus(or.ctx, filter)
+	assert.NoError(t, err)
+
+	assert.Len(t, status.Blockchain, 1)
+	assert.Equal(t, "mock-bi", status.Blockchain[0].PluginType)
+	assert.Equal(t, int64(100), status.Blockchain[0].ChainHead)
+	assert.True(t, status.Blockchain[0].EventStream.Connected)
+	assert.Equal(t, int64(10), *status.TotalListeners)
+	assert.Len(t, status.Listeners, 3)
+	assert.Equal(t, "listener1", status.Listeners[0].Name)
+	assert.Equal(t, int64(95), *status.Listeners[0].LastProcessedBlock)
+	assert.Equal(t, int64(5), *status.Listeners[0].BlockLag)
+	// A listener without a processed event has an unknown lag
+	assert.Nil(t, status.Listeners[1].LastProcessedBlock)
+	assert.Nil(t, status.Listeners[1].BlockLag)
+	// A listener ahead of the reported chain head is not lagging
+	assert.Equal(t, int64(101), *status.Listeners[2].LastProcessedBlock)
+	assert.Equal(t, int64(0), *status.Listeners[2].BlockLag)
+	assert.Equal(t, int64(3), status.PendingTransactions)
+}
+
+func TestGetNetworkStatusChainHeadUnknown(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	l1 := &core.ContractListener{ID: fftypes.NewUUID(), Name: "listener1"}
+	or.mbi.On("GetNetworkStatus", or.ctx).Return(nil, fmt.Errorf("pop"))
+	or.mdi.On("GetContractListeners", or.ctx, "ns", mock.Anything).Return([]*core.ContractListener{l1}, nil, nil)
+	or.mdi.On("GetLatestBlockchainEvents", or.ctx, "ns", mock.Anything).Return([]*core.BlockchainEvent{
+		{Listener: l1.ID, Info: fftypes.JSONObject{"blockNumber": "95"}},
+	}, nil)
+	or.mdi.On("GetOperations", or.ctx, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+
+	status, err := or.GetNetworkStatus(or.ctx, database.ContractListenerQueryFactory.NewFilter(or.ctx).And())
+	assert.NoError(t, err)
+	assert.Nil(t, status.TotalListeners)
+	assert.Equal(t, int64(95), *status.Listeners[0].LastProcessedBlock)
+	assert.Nil(t, status.Listeners[0].BlockLag)
+}
+
+func TestGetNetworkStatusBlockchainError(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mbi.On("GetNetworkStatus", or.ctx).Return(nil, fmt.Errorf("pop"))
+	or.mdi.On("GetContractListeners", or.ctx, "ns", mock.Anything).Return([]*core.ContractListener{}, nil, nil)
+	or.mdi.On("GetOperations", or.ctx, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+
+	status, err := or.GetNetworkStatus(or.ctx, database.ContractListenerQueryFactory.NewFilter(or.ctx).And())
+	assert.NoError(t, err)
+	assert.Equal(t, "pop", status.Blockchain[0].Error)
+	assert.Equal(t, "mock-bi", status.Blockchain[0].PluginType)
+	assert.Zero(t, status.PendingTransactions)
+}
+
+func TestGetNetworkStatusListenersFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mbi.On("GetNetworkStatus", or.ctx).Return(&core.BlockchainNetworkStatus{}, nil)
+	or.mdi.On("GetContractListeners", or.ctx, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetNetworkStatus(or.ctx, database.ContractListenerQueryFactory.NewFilter(or.ctx).And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetNetworkStatusEventsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mbi.On("GetNetworkStatus", or.ctx).Return(&core.BlockchainNetworkStatus{}, nil)
+	or.mdi.On("GetContractListeners", or.ctx, "ns", mock.Anything).Return([]*core.ContractListener{{ID: fftypes.NewUUID()}}, nil, nil)
+	or.mdi.On("GetLatestBlockchainEvents", or.ctx, "ns", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.GetNetworkStatus(or.ctx, database.ContractListenerQueryFactory.NewFilter(or.ctx).And())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetNetworkStatusOperationsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.plugins.Blockchain.Plugin = nil
+	or.mdi.On("GetContractListeners", or.ctx, "ns", mock.Anything).Return([]*core.ContractListener{}, nil, nil)
+	or.mdi.On("GetOperations", or.ctx, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetNetworkStatus(or.ctx, database.ContractListenerQueryFactory.NewFilter(or.ctx).And())
+	assert.EqualError(t, err, "pop")
+}
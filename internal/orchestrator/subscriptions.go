@@ -74,6 +74,39 @@ func (or *orchestrator) DeleteSubscription(ctx context.Context, id string) error
 	return or.events.DeleteDurableSubscription(ctx, sub)
 }
 
+func (or *orchestrator) RewindSubscription(ctx context.Context, id string, rewind *core.SubscriptionRewind) (*core.SubscriptionRewind, error) {
+	sub, err := or.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return or.events.RewindDurableSubscription(ctx, sub, rewind)
+}
+
+func (or *orchestrator) PullSubscription(ctx context.Context, id string, req *core.SubscriptionPull) ([]*core.PulledEvent, error) {
+	sub, err := or.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return or.events.PullSubscriptionEvents(ctx, sub, req)
+}
+
+func (or *orchestrator) AckSubscription(ctx context.Context, id string, ack *core.SubscriptionAck) error {
+	sub, err := or.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if sub == nil {
+		return i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return or.events.AckSubscriptionEvents(ctx, sub, ack)
+}
+
 func (or *orchestrator) GetSubscriptions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error) {
 	return or.database().GetSubscriptions(ctx, or.namespace.Name, filter)
 }
@@ -112,3 +145,124 @@ func (or *orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id st
 
 	return subWithStatus, nil
 }
+
+func (or *orchestrator) CreateSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) (*core.SubscriptionTemplate, error) {
+	template.ID = fftypes.NewUUID()
+	template.Created = fftypes.Now()
+	template.Namespace = or.namespace.Name
+	if err := fftypes.ValidateFFNameFieldNoUUID(ctx, template.Name, "name"); err != nil {
+		return nil, err
+	}
+	if template.Transport == system.SystemEventsTransport {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSystemTransportInternal)
+	}
+	if template.Options.TLSConfigName != "" && or.namespace.TLSConfigs[template.Options.TLSConfigName] == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNotFoundTLSConfig, template.Options.TLSConfigName, template.Namespace)
+	}
+	return template, or.events.CreateSubscriptionTemplate(ctx, template)
+}
+
+func (or *orchestrator) GetSubscriptionTemplates(ctx context.Context, filter ffapi.AndFilter) ([]*core.SubscriptionTemplate, *ffapi.FilterResult, error) {
+	return or.database().GetSubscriptionTemplates(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetSubscriptionTemplateByNameOrID(ctx context.Context, nameOrID string) (template *core.SubscriptionTemplate, err error) {
+	id, err := fftypes.ParseUUID(ctx, nameOrID)
+	if err != nil {
+		if err := fftypes.ValidateFFNameField(ctx, nameOrID, "name"); err != nil {
+			return nil, err
+		}
+		if template, err = or.database().GetSubscriptionTemplateByName(ctx, or.namespace.Name, nameOrID); err != nil {
+			return nil, err
+		}
+	} else if template, err = or.database().GetSubscriptionTemplateByID(ctx, or.namespace.Name, id); err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return template, nil
+}
+
+func (or *orchestrator) DeleteSubscriptionTemplate(ctx context.Context, nameOrID string) error {
+	template, err := or.GetSubscriptionTemplateByNameOrID(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+	return or.events.DeleteSubscriptionTemplate(ctx, template)
+}
+
+func (or *orchestrator) CreateEventRule(ctx context.Context, rule *core.EventRule) (*core.EventRule, error) {
+	rule.ID = fftypes.NewUUID()
+	rule.Created = fftypes.Now()
+	rule.Namespace = or.namespace.Name
+	if err := fftypes.ValidateFFNameFieldNoUUID(ctx, rule.Name, "name"); err != nil {
+		return nil, err
+	}
+	return rule, or.events.CreateEventRule(ctx, rule)
+}
+
+func (or *orchestrator) GetEventRules(ctx context.Context, filter ffapi.AndFilter) ([]*core.EventRule, *ffapi.FilterResult, error) {
+	return or.database().GetEventRules(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetEventRuleByNameOrID(ctx context.Context, nameOrID string) (rule *core.EventRule, err error) {
+	id, err := fftypes.ParseUUID(ctx, nameOrID)
+	if err != nil {
+		if err := fftypes.ValidateFFNameField(ctx, nameOrID, "name"); err != nil {
+			return nil, err
+		}
+		if rule, err = or.database().GetEventRuleByName(ctx, or.namespace.Name, nameOrID); err != nil {
+			return nil, err
+		}
+	} else if rule, err = or.database().GetEventRuleByID(ctx, or.namespace.Name, id); err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return rule, nil
+}
+
+func (or *orchestrator) DeleteEventRule(ctx context.Context, nameOrID string) error {
+	rule, err := or.GetEventRuleByNameOrID(ctx, nameOrID)
+	if err != nil {
+		return err
+	}
+	return or.events.DeleteEventRule(ctx, rule)
+}
+
+func (or *orchestrator) GetDeadLetters(ctx context.Context, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	return or.database().GetDeadLetters(ctx, or.namespace.Name, filter)
+}
+
+func (or *orchestrator) GetDeadLetterByID(ctx context.Context, id string) (*core.DeadLetter, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deadLetter, err := or.database().GetDeadLetterByID(ctx, or.namespace.Name, u)
+	if err != nil {
+		return nil, err
+	}
+	if deadLetter == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	return deadLetter, nil
+}
+
+func (or *orchestrator) RequeueDeadLetter(ctx context.Context, id string) (*core.DeadLetter, error) {
+	deadLetter, err := or.GetDeadLetterByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.RequeueDeadLetter(ctx, deadLetter)
+}
+
+func (or *orchestrator) DiscardDeadLetter(ctx context.Context, id string) (*core.DeadLetter, error) {
+	deadLetter, err := or.GetDeadLetterByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.events.DiscardDeadLetter(ctx, deadLetter)
+}
@@ -176,6 +176,120 @@ func TestDeleteSubscription(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRewindSubscriptionLookupError(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	_, err := or.RewindSubscription(or.ctx, fftypes.NewUUID().String(), &core.SubscriptionRewind{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRewindSubscriptionNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", mock.Anything).Return(nil, nil)
+	_, err := or.RewindSubscription(or.ctx, fftypes.NewUUID().String(), &core.SubscriptionRewind{})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestRewindSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+	}
+	seq := int64(10)
+	rewind := &core.SubscriptionRewind{Sequence: &seq}
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", sub.ID).Return(sub, nil)
+	or.mem.On("RewindDurableSubscription", mock.Anything, sub, rewind).Return(rewind, nil)
+	res, err := or.RewindSubscription(or.ctx, sub.ID.String(), rewind)
+	assert.NoError(t, err)
+	assert.Equal(t, rewind, res)
+}
+
+func TestPullSubscriptionLookupError(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	_, err := or.PullSubscription(or.ctx, fftypes.NewUUID().String(), &core.SubscriptionPull{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPullSubscriptionNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", mock.Anything).Return(nil, nil)
+	_, err := or.PullSubscription(or.ctx, fftypes.NewUUID().String(), &core.SubscriptionPull{})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestPullSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+		Transport: "pull",
+	}
+	pull := &core.SubscriptionPull{BatchSize: 5}
+	pulled := []*core.PulledEvent{{}}
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", sub.ID).Return(sub, nil)
+	or.mem.On("PullSubscriptionEvents", mock.Anything, sub, pull).Return(pulled, nil)
+	res, err := or.PullSubscription(or.ctx, sub.ID.String(), pull)
+	assert.NoError(t, err)
+	assert.Equal(t, pulled, res)
+}
+
+func TestAckSubscriptionLookupError(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	err := or.AckSubscription(or.ctx, fftypes.NewUUID().String(), &core.SubscriptionAck{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestAckSubscriptionNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", mock.Anything).Return(nil, nil)
+	err := or.AckSubscription(or.ctx, fftypes.NewUUID().String(), &core.SubscriptionAck{})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestAckSubscription(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        fftypes.NewUUID(),
+			Name:      "sub1",
+			Namespace: "ns1",
+		},
+		Transport: "pull",
+	}
+	ack := &core.SubscriptionAck{Events: []*fftypes.UUID{fftypes.NewUUID()}}
+	or.mdi.On("GetSubscriptionByID", mock.Anything, "ns", sub.ID).Return(sub, nil)
+	or.mem.On("AckSubscriptionEvents", mock.Anything, sub, ack).Return(nil)
+	err := or.AckSubscription(or.ctx, sub.ID.String(), ack)
+	assert.NoError(t, err)
+}
+
 func TestGetSubscriptions(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -265,3 +379,276 @@ func TestGetSGetSubscriptionsByIDWithStatusUnknownSub(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, subWithStatus)
 }
+
+func TestCreateSubscriptionTemplate(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	template := &core.SubscriptionTemplate{
+		Name:    "tmpl1",
+		Trigger: core.SubscriptionTemplateTriggerTopic,
+	}
+	or.mem.On("CreateSubscriptionTemplate", mock.Anything, template).Return(nil)
+	res, err := or.CreateSubscriptionTemplate(or.ctx, template)
+	assert.NoError(t, err)
+	assert.Equal(t, template, res)
+	assert.Equal(t, "ns", res.Namespace)
+	assert.NotNil(t, res.ID)
+}
+
+func TestCreateSubscriptionTemplateBadName(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateSubscriptionTemplate(or.ctx, &core.SubscriptionTemplate{Name: "!bad"})
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestCreateSubscriptionTemplateSystemTransport(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateSubscriptionTemplate(or.ctx, &core.SubscriptionTemplate{
+		Name:      "tmpl1",
+		Transport: system.SystemEventsTransport,
+	})
+	assert.Regexp(t, "FF10266", err)
+}
+
+func TestCreateSubscriptionTemplateBadTLSConfig(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateSubscriptionTemplate(or.ctx, &core.SubscriptionTemplate{
+		Name: "tmpl1",
+		Options: core.SubscriptionOptions{
+			WebhookSubOptions: core.WebhookSubOptions{
+				TLSConfigName: "missing",
+			},
+		},
+	})
+	assert.Regexp(t, "FF10455", err)
+}
+
+func TestGetSubscriptionTemplates(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetSubscriptionTemplates", mock.Anything, "ns", mock.Anything).Return([]*core.SubscriptionTemplate{}, nil, nil)
+	fb := database.SubscriptionTemplateQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetSubscriptionTemplates(or.ctx, fb.And(fb.Eq("name", "tmpl1")))
+	assert.NoError(t, err)
+}
+
+func TestGetSubscriptionTemplateByNameOrID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	template := &core.SubscriptionTemplate{ID: fftypes.NewUUID(), Name: "tmpl1"}
+	or.mdi.On("GetSubscriptionTemplateByName", mock.Anything, "ns", "tmpl1").Return(template, nil)
+	or.mdi.On("GetSubscriptionTemplateByID", mock.Anything, "ns", template.ID).Return(template, nil)
+
+	res, err := or.GetSubscriptionTemplateByNameOrID(or.ctx, "tmpl1")
+	assert.NoError(t, err)
+	assert.Equal(t, template, res)
+
+	res, err = or.GetSubscriptionTemplateByNameOrID(or.ctx, template.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, template, res)
+}
+
+func TestGetSubscriptionTemplateByNameOrIDErrors(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetSubscriptionTemplateByName", mock.Anything, "ns", "tmpl1").Return(nil, fmt.Errorf("pop"))
+	or.mdi.On("GetSubscriptionTemplateByID", mock.Anything, "ns", id).Return(nil, fmt.Errorf("pop")).Once()
+	or.mdi.On("GetSubscriptionTemplateByID", mock.Anything, "ns", id).Return(nil, nil).Once()
+
+	_, err := or.GetSubscriptionTemplateByNameOrID(or.ctx, "!bad")
+	assert.Regexp(t, "FF00140", err)
+	_, err = or.GetSubscriptionTemplateByNameOrID(or.ctx, "tmpl1")
+	assert.EqualError(t, err, "pop")
+	_, err = or.GetSubscriptionTemplateByNameOrID(or.ctx, id.String())
+	assert.EqualError(t, err, "pop")
+	_, err = or.GetSubscriptionTemplateByNameOrID(or.ctx, id.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDeleteSubscriptionTemplate(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	template := &core.SubscriptionTemplate{ID: fftypes.NewUUID(), Name: "tmpl1"}
+	or.mdi.On("GetSubscriptionTemplateByName", mock.Anything, "ns", "tmpl1").Return(template, nil)
+	or.mdi.On("GetSubscriptionTemplateByName", mock.Anything, "ns", "tmpl2").Return(nil, nil)
+	or.mem.On("DeleteSubscriptionTemplate", mock.Anything, template).Return(nil)
+
+	err := or.DeleteSubscriptionTemplate(or.ctx, "tmpl1")
+	assert.NoError(t, err)
+	err = or.DeleteSubscriptionTemplate(or.ctx, "tmpl2")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestCreateEventRule(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	rule := &core.EventRule{
+		Name:   "rule1",
+		Action: core.EventRuleActionSuppress,
+	}
+	or.mem.On("CreateEventRule", mock.Anything, rule).Return(nil)
+	res, err := or.CreateEventRule(or.ctx, rule)
+	assert.NoError(t, err)
+	assert.Equal(t, rule, res)
+	assert.Equal(t, "ns", res.Namespace)
+	assert.NotNil(t, res.ID)
+}
+
+func TestCreateEventRuleBadName(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.CreateEventRule(or.ctx, &core.EventRule{Name: "!bad"})
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestGetEventRules(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetEventRules", mock.Anything, "ns", mock.Anything).Return([]*core.EventRule{}, nil, nil)
+	fb := database.EventRuleQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetEventRules(or.ctx, fb.And(fb.Eq("action", core.EventRuleActionRoute)))
+	assert.NoError(t, err)
+}
+
+func TestGetEventRuleByNameOrID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	rule := &core.EventRule{ID: fftypes.NewUUID(), Name: "rule1"}
+	or.mdi.On("GetEventRuleByName", mock.Anything, "ns", "rule1").Return(rule, nil)
+	or.mdi.On("GetEventRuleByID", mock.Anything, "ns", rule.ID).Return(rule, nil)
+
+	res, err := or.GetEventRuleByNameOrID(or.ctx, "rule1")
+	assert.NoError(t, err)
+	assert.Equal(t, rule, res)
+
+	res, err = or.GetEventRuleByNameOrID(or.ctx, rule.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, rule, res)
+}
+
+func TestGetEventRuleByNameOrIDErrors(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetEventRuleByName", mock.Anything, "ns", "rule1").Return(nil, fmt.Errorf("pop"))
+	or.mdi.On("GetEventRuleByID", mock.Anything, "ns", id).Return(nil, fmt.Errorf("pop")).Once()
+	or.mdi.On("GetEventRuleByID", mock.Anything, "ns", id).Return(nil, nil).Once()
+
+	_, err := or.GetEventRuleByNameOrID(or.ctx, "!bad")
+	assert.Regexp(t, "FF00140", err)
+	_, err = or.GetEventRuleByNameOrID(or.ctx, "rule1")
+	assert.EqualError(t, err, "pop")
+	_, err = or.GetEventRuleByNameOrID(or.ctx, id.String())
+	assert.EqualError(t, err, "pop")
+	_, err = or.GetEventRuleByNameOrID(or.ctx, id.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDeleteEventRule(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	rule := &core.EventRule{ID: fftypes.NewUUID(), Name: "rule1"}
+	or.mdi.On("GetEventRuleByName", mock.Anything, "ns", "rule1").Return(rule, nil)
+	or.mdi.On("GetEventRuleByName", mock.Anything, "ns", "rule2").Return(nil, nil)
+	or.mem.On("DeleteEventRule", mock.Anything, rule).Return(nil)
+
+	err := or.DeleteEventRule(or.ctx, "rule1")
+	assert.NoError(t, err)
+	err = or.DeleteEventRule(or.ctx, "rule2")
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestGetDeadLetters(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetDeadLetters", mock.Anything, "ns", mock.Anything).Return([]*core.DeadLetter{}, nil, nil)
+	fb := database.DeadLetterQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetDeadLetters(or.ctx, fb.And(fb.Eq("state", core.DeadLetterStatePending)))
+	assert.NoError(t, err)
+}
+
+func TestGetDeadLetterByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	deadLetter := &core.DeadLetter{ID: fftypes.NewUUID()}
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", deadLetter.ID).Return(deadLetter, nil)
+	res, err := or.GetDeadLetterByID(or.ctx, deadLetter.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, deadLetter, res)
+}
+
+func TestGetDeadLetterByIDBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetDeadLetterByID(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetDeadLetterByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", id).Return(nil, fmt.Errorf("pop"))
+	_, err := or.GetDeadLetterByID(or.ctx, id.String())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestGetDeadLetterByIDNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", id).Return(nil, nil)
+	_, err := or.GetDeadLetterByID(or.ctx, id.String())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestRequeueDeadLetter(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	deadLetter := &core.DeadLetter{ID: fftypes.NewUUID()}
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", deadLetter.ID).Return(deadLetter, nil)
+	or.mem.On("RequeueDeadLetter", mock.Anything, deadLetter).Return(deadLetter, nil)
+	_, err := or.RequeueDeadLetter(or.ctx, deadLetter.ID.String())
+	assert.NoError(t, err)
+
+	_, err = or.RequeueDeadLetter(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDiscardDeadLetter(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	deadLetter := &core.DeadLetter{ID: fftypes.NewUUID()}
+	or.mdi.On("GetDeadLetterByID", mock.Anything, "ns", deadLetter.ID).Return(deadLetter, nil)
+	or.mem.On("DiscardDeadLetter", mock.Anything, deadLetter).Return(deadLetter, nil)
+	_, err := or.DiscardDeadLetter(or.ctx, deadLetter.ID.String())
+	assert.NoError(t, err)
+
+	_, err = or.DiscardDeadLetter(or.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
@@ -0,0 +1,122 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// GetDisclosureProof builds a proof of the values of the requested fields of a data item of a confirmed private
+// message, that can be verified by a party that is not a member of the group against the pins of the batch
+func (pm *privateMessaging) GetDisclosureProof(ctx context.Context, id string, input *core.DisclosureInput) (*core.DisclosureProof, error) {
+	msg, err := pm.getPrivateMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.State != core.MessageStateConfirmed {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureNotConfirmed, msg.Header.ID, msg.State)
+	}
+	if msg.BatchID == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureNotEnabled, msg.Header.ID)
+	}
+	batch, err := pm.database.GetBatchByID(ctx, pm.namespace.Name, msg.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil || batch.DisclosureSalt == nil || !core.IsPinned(batch.TX.Type) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureNotEnabled, msg.Header.ID)
+	}
+
+	dataID := input.Data
+	if dataID == nil {
+		if len(msg.Data) != 1 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureDataRequired, msg.Header.ID, len(msg.Data))
+		}
+		dataID = msg.Data[0].ID
+	}
+	inMessage := false
+	for _, dr := range msg.Data {
+		if dr.ID.Equals(dataID) {
+			inMessage = true
+			break
+		}
+	}
+	if !inMessage {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureDataNotInMessage, dataID, msg.Header.ID)
+	}
+	data, err := pm.database.GetDataByID(ctx, pm.namespace.Name, dataID, true)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+
+	var manifest core.BatchManifest
+	if err := batch.Manifest.Unmarshal(ctx, &manifest); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgJSONObjectParseFailed, fmt.Sprintf("batch %s manifest", batch.ID))
+	}
+	fields, err := data.DiscloseFields(ctx, batch.DisclosureSalt, input.Fields)
+	if err != nil {
+		return nil, err
+	}
+	return &core.DisclosureProof{
+		Batch:   &manifest,
+		Message: msg.BatchMessage(),
+		Data:    dataID,
+		Fields:  fields,
+	}, nil
+}
+
+// VerifyDisclosureProof checks a disclosure proof, and that the hash of the batch manifest in the proof has been
+// pinned to the blockchain. This node does not need to be a member of the group the message was sent to.
+func (pm *privateMessaging) VerifyDisclosureProof(ctx context.Context, proof *core.DisclosureProof) (*core.DisclosureVerification, error) {
+	result := &core.DisclosureVerification{}
+	if err := proof.Verify(ctx); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+	result.Batch = proof.Batch.ID
+	result.BatchHash = fftypes.HashString(proof.Batch.String())
+	result.Message = proof.Message.Header.ID
+	result.Data = proof.Data
+
+	fb := database.PinQueryFactory.NewFilter(ctx)
+	pins, _, err := pm.database.GetPins(ctx, pm.namespace.Name, fb.Eq("batch", proof.Batch.ID))
+	if err != nil {
+		return nil, err
+	}
+	for _, pin := range pins {
+		if pin.BatchHash.Equals(result.BatchHash) {
+			result.Valid = true
+			result.Signer = pin.Signer
+			for _, field := range proof.Fields {
+				result.Fields = append(result.Fields, field.Field)
+			}
+			return result, nil
+		}
+	}
+	result.Reason = i18n.NewError(ctx, coremsgs.MsgDisclosureBatchNotPinned, result.Batch, result.BatchHash).Error()
+	return result, nil
+}
@@ -0,0 +1,330 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testDisclosure struct {
+	msg   *core.Message
+	data  *core.Data
+	batch *core.BatchPersisted
+}
+
+func newTestDisclosure(t *testing.T, pm *privateMessaging) *testDisclosure {
+	data := &core.Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(`{"amount":100,"buyer":"org2","price":12.5}`),
+	}
+	err := data.Seal(pm.ctx, nil)
+	assert.NoError(t, err)
+	msg := &core.Message{
+		Header: core.MessageHeader{
+			Type:  core.MessageTypePrivate,
+			Group: fftypes.NewRandB32(),
+		},
+		Data: core.DataRefs{{ID: data.ID, Hash: data.Hash}},
+	}
+	err = msg.Seal(pm.ctx)
+	assert.NoError(t, err)
+	batch := &core.Batch{
+		BatchHeader: core.BatchHeader{ID: fftypes.NewUUID()},
+		Payload: core.BatchPayload{
+			TX:             core.TransactionRef{Type: core.TransactionTypeBatchPin, ID: fftypes.NewUUID()},
+			Messages:       []*core.Message{msg.BatchMessage()},
+			Data:           core.DataArray{data},
+			DisclosureSalt: fftypes.NewRandB32(),
+		},
+	}
+	bp, _ := batch.Confirmed()
+	bp.Hash = fftypes.HashString(bp.Manifest.String())
+	msg.BatchID = batch.ID
+	msg.State = core.MessageStateConfirmed
+	return &testDisclosure{msg: msg, data: data, batch: bp}
+}
+
+func (td *testDisclosure) pin() *core.Pin {
+	return &core.Pin{
+		Batch:     td.batch.ID,
+		BatchHash: td.batch.Hash,
+		Signer:    "0x12345",
+	}
+}
+
+func TestDisclosureProofOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+	mdi.On("GetDataByID", pm.ctx, "ns1", td.data.ID, true).Return(td.data, nil)
+	mdi.On("GetPins", pm.ctx, "ns1", mock.Anything).Return([]*core.Pin{
+		{Batch: td.batch.ID, BatchHash: fftypes.NewRandB32()},
+		td.pin(),
+	}, nil, nil)
+
+	proof, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.NoError(t, err)
+	assert.Equal(t, td.data.ID, proof.Data)
+	assert.Len(t, proof.Fields, 1)
+	assert.Equal(t, "12.5", proof.Fields[0].Value.String())
+
+	result, err := pm.VerifyDisclosureProof(pm.ctx, proof)
+	assert.NoError(t, err)
+	assert.True(t, result.Valid)
+	assert.Equal(t, td.batch.Hash, result.BatchHash)
+	assert.Equal(t, "0x12345", result.Signer)
+	assert.Equal(t, td.msg.Header.ID, result.Message)
+	assert.Equal(t, []string{"price"}, result.Fields)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDisclosureProofDataSpecified(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+	mdi.On("GetDataByID", pm.ctx, "ns1", td.data.ID, true).Return(td.data, nil)
+
+	proof, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Data: td.data.ID, Fields: []string{"amount", "buyer"}})
+	assert.NoError(t, err)
+	assert.Len(t, proof.Fields, 2)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestDisclosureProofBadID(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.GetDisclosureProof(pm.ctx, "bad", &core.DisclosureInput{})
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestDisclosureProofNotConfirmed(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+	td.msg.State = core.MessageStateSent
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.Regexp(t, "FF10642", err)
+}
+
+func TestDisclosureProofNoBatch(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+	td.msg.BatchID = nil
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.Regexp(t, "FF10641", err)
+}
+
+func TestDisclosureProofGetBatchFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDisclosureProofNoSalt(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+	td.batch.DisclosureSalt = nil
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.Regexp(t, "FF10641", err)
+}
+
+func TestDisclosureProofDataRequired(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+	td.msg.Data = append(td.msg.Data, &core.DataRef{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.Regexp(t, "FF10643", err)
+}
+
+func TestDisclosureProofDataNotInMessage(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Data: fftypes.NewUUID(), Fields: []string{"price"}})
+	assert.Regexp(t, "FF10644", err)
+}
+
+func TestDisclosureProofGetDataFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+	mdi.On("GetDataByID", pm.ctx, "ns1", td.data.ID, true).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestDisclosureProofDataNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+	mdi.On("GetDataByID", pm.ctx, "ns1", td.data.ID, true).Return(nil, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestDisclosureProofBadManifest(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+	td.batch.Manifest = fftypes.JSONAnyPtr("!json")
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+	mdi.On("GetDataByID", pm.ctx, "ns1", td.data.ID, true).Return(td.data, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"price"}})
+	assert.Regexp(t, "FF00127", err)
+}
+
+func TestDisclosureProofFieldNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", td.msg.Header.ID).Return(td.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", td.batch.ID).Return(td.batch, nil)
+	mdi.On("GetDataByID", pm.ctx, "ns1", td.data.ID, true).Return(td.data, nil)
+
+	_, err := pm.GetDisclosureProof(pm.ctx, td.msg.Header.ID.String(), &core.DisclosureInput{Fields: []string{"seller"}})
+	assert.Regexp(t, "FF10640", err)
+}
+
+func TestVerifyDisclosureProofInvalid(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	result, err := pm.VerifyDisclosureProof(pm.ctx, &core.DisclosureProof{})
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Regexp(t, "FF10646", result.Reason)
+}
+
+func TestVerifyDisclosureProofNotPinned(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	fields, err := td.data.DiscloseFields(pm.ctx, td.batch.DisclosureSalt, []string{"buyer"})
+	assert.NoError(t, err)
+	var manifest core.BatchManifest
+	err = td.batch.Manifest.Unmarshal(pm.ctx, &manifest)
+	assert.NoError(t, err)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetPins", pm.ctx, "ns1", mock.Anything).Return([]*core.Pin{}, nil, nil)
+
+	result, err := pm.VerifyDisclosureProof(pm.ctx, &core.DisclosureProof{
+		Batch:   &manifest,
+		Message: td.msg.BatchMessage(),
+		Data:    td.data.ID,
+		Fields:  fields,
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Regexp(t, "FF10650", result.Reason)
+	assert.Equal(t, td.batch.Hash, result.BatchHash)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestVerifyDisclosureProofGetPinsFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	td := newTestDisclosure(t, pm)
+
+	fields, err := td.data.DiscloseFields(pm.ctx, td.batch.DisclosureSalt, []string{"buyer"})
+	assert.NoError(t, err)
+	var manifest core.BatchManifest
+	err = td.batch.Manifest.Unmarshal(pm.ctx, &manifest)
+	assert.NoError(t, err)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetPins", pm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err = pm.VerifyDisclosureProof(pm.ctx, &core.DisclosureProof{
+		Batch:   &manifest,
+		Message: td.msg.BatchMessage(),
+		Data:    td.data.ID,
+		Fields:  fields,
+	})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
@@ -0,0 +1,383 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/nacl/box"
+)
+
+// EncryptionKeyProfileField is the field in the profile of a node that holds its public encryption key
+const EncryptionKeyProfileField = "encryptionKey"
+
+var randReader = rand.Reader
+
+// Encrypted blobs start with a header holding the key of the blob sealed to the receiving node, followed by the content
+// in segments that are each encrypted with AES-GCM. The final segment is flagged in its nonce, so the content cannot be
+// truncated at a segment boundary without failing to decrypt.
+var encryptedBlobMagic = []byte("FFBLOBE1")
+
+const (
+	encryptedBlobSegmentSize = 64 * 1024
+	encryptedBlobHeaderSize  = 8 /* magic */ + 32 + box.AnonymousOverhead
+)
+
+type encryptionKey struct {
+	private [32]byte
+	public  [32]byte
+}
+
+// LocalEncryptionKey returns the base64 encoded public encryption key of this node,
+// or an empty string if no private key is configured
+func LocalEncryptionKey(ctx context.Context) (string, error) {
+	key, err := loadEncryptionKey(ctx)
+	if err != nil || key == nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key.public[:]), nil
+}
+
+func loadEncryptionKey(ctx context.Context) (*encryptionKey, error) {
+	configured := config.GetString(coreconfig.PrivateMessagingEncryptionPrivateKey)
+	if configured == "" {
+		return nil, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(configured)
+	if err != nil || len(b) != 32 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidEncryptionKey, coreconfig.PrivateMessagingEncryptionPrivateKey)
+	}
+	key := &encryptionKey{}
+	copy(key.private[:], b)
+	curve25519.ScalarBaseMult(&key.public, &key.private)
+	return key, nil
+}
+
+func nodeEncryptionKey(ctx context.Context, node *core.Identity) (*[32]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(node.Profile.GetString(EncryptionKeyProfileField))
+	if err != nil || len(b) != 32 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNodeNoEncryptionKey, node.DID, EncryptionKeyProfileField)
+	}
+	var key [32]byte
+	copy(key[:], b)
+	return &key, nil
+}
+
+// encryptTransport returns a copy of the transport, with the data of the batch encrypted with a random
+// key, and that key sealed to the encryption key of each node of the group other than the local node
+func (pm *privateMessaging) encryptTransport(ctx context.Context, localNode *core.Identity, transport *core.TransportWrapper) (*core.TransportWrapper, error) {
+	_, nodes, err := pm.getGroupNodes(ctx, transport.Batch.Group, false)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(randReader, dataKey); err != nil {
+		return nil, err
+	}
+	encrypted := &core.EncryptedPayload{
+		Keys: make([]*core.WrappedKey, 0, len(nodes)),
+	}
+	for _, node := range nodes {
+		if node.ID.Equals(localNode.ID) {
+			continue
+		}
+		publicKey, err := nodeEncryptionKey(ctx, node)
+		if err != nil {
+			return nil, err
+		}
+		wrapped, err := box.SealAnonymous(nil, dataKey, publicKey, randReader)
+		if err != nil {
+			return nil, err
+		}
+		encrypted.Keys = append(encrypted.Keys, &core.WrappedKey{Node: node.ID, Key: wrapped})
+	}
+
+	plaintext, err := json.Marshal(transport.Batch.Payload.Data)
+	if err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
+	}
+	gcm := newGCM(dataKey)
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(randReader, nonce); err != nil {
+		return nil, err
+	}
+	encrypted.Data = gcm.Seal(nonce, nonce, plaintext, nil)
+
+	batch := *transport.Batch
+	batch.Payload.Data = nil
+	return &core.TransportWrapper{
+		Group:     transport.Group,
+		Batch:     &batch,
+		Encrypted: encrypted,
+	}, nil
+}
+
+// DecryptTransport restores the data of a batch received with an encrypted payload, using the key sealed to
+// this node. Returns false if the payload cannot be decrypted, in which case the batch should be ignored.
+func (pm *privateMessaging) DecryptTransport(ctx context.Context, transport *core.TransportWrapper) (bool, error) {
+	if transport.Encrypted == nil || transport.Batch == nil {
+		return true, nil
+	}
+	l := log.L(ctx)
+	if pm.encryptionKey == nil {
+		l.Errorf("Unable to decrypt batch '%s': no encryption key is configured", transport.Batch.ID)
+		return false, nil
+	}
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var dataKey []byte
+	for _, wrapped := range transport.Encrypted.Keys {
+		if wrapped.Node.Equals(localNode.ID) {
+			dataKey, _ = box.OpenAnonymous(nil, wrapped.Key, &pm.encryptionKey.public, &pm.encryptionKey.private)
+			break
+		}
+	}
+	if len(dataKey) != 32 {
+		l.Errorf("Unable to decrypt batch '%s': no valid key for node '%s'", transport.Batch.ID, localNode.ID)
+		return false, nil
+	}
+
+	gcm := newGCM(dataKey)
+	ciphertext := transport.Encrypted.Data
+	if len(ciphertext) < gcm.NonceSize() {
+		l.Errorf("Unable to decrypt batch '%s': invalid payload", transport.Batch.ID)
+		return false, nil
+	}
+	plaintext, err := gcm.Open(nil, ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():], nil)
+	if err != nil {
+		l.Errorf("Unable to decrypt batch '%s': %s", transport.Batch.ID, err)
+		return false, nil
+	}
+	var data core.DataArray
+	if err := json.Unmarshal(plaintext, &data); err != nil {
+		l.Errorf("Unable to decrypt batch '%s': %s", transport.Batch.ID, err)
+		return false, nil
+	}
+	transport.Batch.Payload.Data = data
+	transport.Encrypted = nil
+	return true, nil
+}
+
+func newGCM(key []byte) cipher.AEAD {
+	// Neither can fail for a 32 byte key
+	block, _ := aes.NewCipher(key)
+	gcm, _ := cipher.NewGCM(block)
+	return gcm
+}
+
+// encryptedBlobNamespace is the namespace path that the copy of a blob encrypted for a node is uploaded under, alongside
+// (rather than over) the blob itself. The receiving node finds the namespace and data ID at the end of the path as usual.
+func encryptedBlobNamespace(node *core.Identity, ns string) string {
+	return fmt.Sprintf("encrypted/%s/%s", node.ID, ns)
+}
+
+// encryptBlob uploads a copy of a blob to data exchange, encrypted with a random key that is sealed to the encryption
+// key of the node it is being sent to, and returns the reference and hash of the copy
+func (pm *privateMessaging) encryptBlob(ctx context.Context, node *core.Identity, blob *core.Blob) (payloadRef string, hash *fftypes.Bytes32, err error) {
+	publicKey, err := nodeEncryptionKey(ctx, node)
+	if err != nil {
+		return "", nil, err
+	}
+	blobKey := make([]byte, 32)
+	if _, err := io.ReadFull(randReader, blobKey); err != nil {
+		return "", nil, err
+	}
+	sealedKey, err := box.SealAnonymous(nil, blobKey, publicKey, randReader)
+	if err != nil {
+		return "", nil, err
+	}
+
+	content, err := pm.exchange.DownloadBlob(ctx, blob.PayloadRef)
+	if err != nil {
+		return "", nil, err
+	}
+	defer content.Close()
+
+	header := append(append([]byte{}, encryptedBlobMagic...), sealedKey...)
+	reader := io.MultiReader(bytes.NewReader(header), newBlobCipherReader(content, blobKey, true))
+	payloadRef, hash, _, err = pm.exchange.UploadBlob(ctx, encryptedBlobNamespace(node, pm.namespace.NetworkName), *blob.DataID, reader)
+	if err != nil {
+		return "", nil, err
+	}
+	log.L(ctx).Debugf("Encrypted blob '%s' for node '%s' as '%s'", blob.Hash, node.ID, payloadRef)
+	return payloadRef, hash, nil
+}
+
+// DecryptBlob replaces a blob received with encrypted content by a decrypted copy in data exchange, updating the
+// reference, hash and size of the blob. Blobs that were not encrypted are left as they are, as are blobs that cannot be
+// decrypted - which never match the hash of their data, so the data of their message is not confirmed.
+func (pm *privateMessaging) DecryptBlob(ctx context.Context, blob *core.Blob) error {
+	if pm.encryptionKey == nil {
+		return nil
+	}
+	l := log.L(ctx)
+	content, err := pm.exchange.DownloadBlob(ctx, blob.PayloadRef)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	header := make([]byte, encryptedBlobHeaderSize)
+	if _, err := io.ReadFull(content, header); err != nil || !bytes.Equal(header[:len(encryptedBlobMagic)], encryptedBlobMagic) {
+		// Too short to be encrypted, or not encrypted
+		return nil
+	}
+	blobKey, ok := box.OpenAnonymous(nil, header[len(encryptedBlobMagic):], &pm.encryptionKey.public, &pm.encryptionKey.private)
+	if !ok || len(blobKey) != 32 {
+		l.Errorf("Unable to decrypt blob '%s' for data '%s': it was not encrypted for this node", blob.PayloadRef, blob.DataID)
+		return nil
+	}
+
+	reader := newBlobCipherReader(content, blobKey, false)
+	payloadRef, hash, size, err := pm.exchange.UploadBlob(ctx, pm.namespace.NetworkName, *blob.DataID, reader)
+	if reader.invalid {
+		l.Errorf("Unable to decrypt blob '%s' for data '%s': invalid content", blob.PayloadRef, blob.DataID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := pm.exchange.DeleteBlob(ctx, blob.PayloadRef); err != nil {
+		l.Warnf("Failed to delete encrypted blob '%s': %s", blob.PayloadRef, err)
+	}
+	l.Infof("Decrypted blob '%s' for data '%s' as '%s'", blob.PayloadRef, blob.DataID, payloadRef)
+	blob.PayloadRef = payloadRef
+	blob.Hash = hash
+	blob.Size = size
+	return nil
+}
+
+// onSendBlobUpdate keeps track of the copy of a blob that was encrypted to send it, through the updates to the operation.
+// The receiving data exchange reports the hash of the copy, which is mapped back to the hash of the blob for the hash
+// check on the operation, and the copy is deleted once the operation is complete.
+func (pm *privateMessaging) onSendBlobUpdate(ctx context.Context, op *core.Operation, update *core.OperationUpdate) {
+	encryptedRef := op.Output.GetString("encryptedPayloadRef")
+	if encryptedRef == "" {
+		return
+	}
+	encryptedHash := op.Output.GetString("encryptedHash")
+	if update.Output == nil {
+		update.Output = fftypes.JSONObject{}
+	}
+	update.Output["encryptedPayloadRef"] = encryptedRef
+	update.Output["encryptedHash"] = encryptedHash
+	if update.DXHash != "" && update.DXHash == encryptedHash {
+		update.DXHash = op.Input.GetString("hash")
+	}
+	if update.Status == core.OpStatusSucceeded || update.Status == core.OpStatusFailed {
+		if err := pm.exchange.DeleteBlob(ctx, encryptedRef); err != nil {
+			log.L(ctx).Warnf("Failed to delete encrypted blob '%s': %s", encryptedRef, err)
+		}
+	}
+}
+
+// blobCipherReader encrypts or decrypts a stream in segments, as it is read
+type blobCipherReader struct {
+	src     *bufio.Reader
+	gcm     cipher.AEAD
+	seal    bool
+	segment []byte
+	buf     []byte
+	out     []byte
+	counter uint64
+	done    bool
+	invalid bool
+}
+
+func newBlobCipherReader(src io.Reader, key []byte, seal bool) *blobCipherReader {
+	gcm := newGCM(key)
+	segmentSize := encryptedBlobSegmentSize
+	if !seal {
+		segmentSize += gcm.Overhead()
+	}
+	return &blobCipherReader{
+		src:     bufio.NewReader(src),
+		gcm:     gcm,
+		seal:    seal,
+		segment: make([]byte, segmentSize),
+		buf:     make([]byte, 0, encryptedBlobSegmentSize+gcm.Overhead()),
+	}
+}
+
+func (r *blobCipherReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		if err := r.nextSegment(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+func (r *blobCipherReader) nextSegment() error {
+	n, err := io.ReadFull(r.src, r.segment)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	last := n < len(r.segment)
+	if !last {
+		if _, err := r.src.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	nonce := make([]byte, r.gcm.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-9:], r.counter)
+	if last {
+		nonce[len(nonce)-1] = 1
+	}
+	r.counter++
+	r.done = last
+
+	if r.seal {
+		r.out = r.gcm.Seal(r.buf[:0], nonce, r.segment[:n], nil)
+		return nil
+	}
+	if r.out, err = r.gcm.Open(r.buf[:0], nonce, r.segment[:n], nil); err != nil {
+		r.invalid = true
+		return err
+	}
+	return nil
+}
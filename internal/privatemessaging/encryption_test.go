@@ -0,0 +1,771 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/batchmocks"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/datamocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/multipartymocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/nacl/box"
+)
+
+const testPrivateKey = "AQIDBAUGBwgJCgsMDQ4PEBESExQVFhcYGRobHB0eHyA="
+
+type testEncryption struct {
+	localNode  *core.Identity
+	remoteNode *core.Identity
+	transport  *core.TransportWrapper
+}
+
+func newTestEncryption(t *testing.T, pm *privateMessaging) *testEncryption {
+	config.Set(coreconfig.PrivateMessagingEncryptionPrivateKey, testPrivateKey)
+	remoteKey, err := LocalEncryptionKey(context.Background())
+	assert.NoError(t, err)
+
+	te := &testEncryption{
+		localNode: &core.Identity{
+			IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeNode},
+		},
+		remoteNode: &core.Identity{
+			IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeNode, DID: "did:firefly:node/node2"},
+			IdentityProfile: core.IdentityProfile{
+				Profile: fftypes.JSONObject{"id": "peer2", EncryptionKeyProfileField: remoteKey},
+			},
+		},
+	}
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members: core.Members{
+				{Identity: "did:firefly:org/org1", Node: te.localNode.ID},
+				{Identity: "did:firefly:org/org2", Node: te.remoteNode.ID},
+			},
+		},
+	}
+	group.Seal()
+	pm.groupCache.Set(group.Hash.String(), &groupHashEntry{
+		group: group,
+		nodes: []*core.Identity{te.localNode, te.remoteNode},
+	})
+	te.transport = &core.TransportWrapper{
+		Group: group,
+		Batch: &core.Batch{
+			BatchHeader: core.BatchHeader{ID: fftypes.NewUUID(), Group: group.Hash},
+			Payload: core.BatchPayload{
+				Data: core.DataArray{
+					{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"secret":"value"}`)},
+				},
+			},
+		},
+	}
+	return te
+}
+
+func TestEncryptDecryptTransport(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	te := newTestEncryption(t, pm)
+
+	encrypted, err := pm.encryptTransport(pm.ctx, te.localNode, te.transport)
+	assert.NoError(t, err)
+	assert.Nil(t, encrypted.Batch.Payload.Data)
+	assert.Len(t, encrypted.Encrypted.Keys, 1)
+	assert.Equal(t, te.remoteNode.ID, encrypted.Encrypted.Keys[0].Node)
+	assert.NotContains(t, string(encrypted.Encrypted.Data), "secret")
+	assert.Len(t, te.transport.Batch.Payload.Data, 1)
+
+	// Round trip through JSON, as over data exchange
+	b, err := json.Marshal(encrypted)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "secret")
+	var received *core.TransportWrapper
+	err = json.Unmarshal(b, &received)
+	assert.NoError(t, err)
+
+	receiver, cancel2 := newTestPrivateMessaging(t)
+	defer cancel2()
+	config.Set(coreconfig.PrivateMessagingEncryptionPrivateKey, testPrivateKey)
+	receiver.encryptionKey, err = loadEncryptionKey(context.Background())
+	assert.NoError(t, err)
+	mim := receiver.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", receiver.ctx).Return(te.remoteNode, nil)
+
+	valid, err := receiver.DecryptTransport(receiver.ctx, received)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Nil(t, received.Encrypted)
+	assert.Equal(t, `{"secret":"value"}`, received.Batch.Payload.Data[0].Value.String())
+	assert.Equal(t, te.transport.Batch.Payload.Data[0].ID, received.Batch.Payload.Data[0].ID)
+
+	mim.AssertExpectations(t)
+}
+
+func TestInitBadEncryptionKey(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.PrivateMessagingEncryptionPrivateKey, base64.StdEncoding.EncodeToString([]byte("short")))
+
+	mba := &batchmocks.Manager{}
+	mba.On("RegisterDispatcher", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return().Maybe()
+	mmi := &metricsmocks.Manager{}
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(nil, nil)
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	_, err := NewPrivateMessaging(context.Background(), ns, &databasemocks.Plugin{}, &dataexchangemocks.Plugin{}, &blockchainmocks.Plugin{},
+		&identitymanagermocks.Manager{}, mba, &datamocks.Manager{}, &syncasyncmocks.Bridge{}, &multipartymocks.Manager{}, mmi, &operationmocks.Manager{}, cmi)
+	assert.Regexp(t, "FF10629", err)
+}
+
+func TestLocalEncryptionKeyNotSet(t *testing.T) {
+	coreconfig.Reset()
+	key, err := LocalEncryptionKey(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, key)
+}
+
+func TestEncryptTransportGroupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	te := newTestEncryption(t, pm)
+	te.transport.Batch.Group = fftypes.NewRandB32()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", te.transport.Batch.Group).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.encryptTransport(pm.ctx, te.localNode, te.transport)
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEncryptTransportNoNodeKey(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	te := newTestEncryption(t, pm)
+	te.remoteNode.Profile = fftypes.JSONObject{"id": "peer2"}
+
+	_, err := pm.encryptTransport(pm.ctx, te.localNode, te.transport)
+	assert.Regexp(t, "FF10630.*did:firefly:node/node2", err)
+}
+
+func TestEncryptTransportBadData(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	te := newTestEncryption(t, pm)
+	te.transport.Batch.Payload.Data[0].Value = fftypes.JSONAnyPtr(`!json`)
+
+	_, err := pm.encryptTransport(pm.ctx, te.localNode, te.transport)
+	assert.Regexp(t, "FF10137", err)
+}
+
+type testFailingReader struct {
+	remaining int
+}
+
+func (r *testFailingReader) Read(p []byte) (int, error) {
+	if r.remaining < len(p) {
+		return 0, fmt.Errorf("pop")
+	}
+	r.remaining -= len(p)
+	return len(p), nil
+}
+
+func TestEncryptTransportRandFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	te := newTestEncryption(t, pm)
+	defer func() { randReader = rand.Reader }()
+
+	// Fail reading the data key, the ephemeral key of the sealed box, and the nonce
+	for _, remaining := range []int{0, 32, 64} {
+		randReader = &testFailingReader{remaining: remaining}
+		_, err := pm.encryptTransport(pm.ctx, te.localNode, te.transport)
+		assert.EqualError(t, err, "pop")
+	}
+}
+
+func TestRunOperationBatchSendEncrypted(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.encryption = true
+	te := newTestEncryption(t, pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(te.localNode, nil)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("SendMessage", context.Background(), mock.Anything, te.remoteNode.Profile, te.localNode.Profile, mock.MatchedBy(func(payload []byte) bool {
+		var tw core.TransportWrapper
+		err := json.Unmarshal(payload, &tw)
+		return err == nil && tw.Encrypted != nil && tw.Batch.Payload.Data == nil
+	})).Return(nil)
+
+	_, _, err := pm.RunOperation(context.Background(), opSendBatch(&core.Operation{}, te.remoteNode, te.transport))
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestRunOperationBatchSendEncryptFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.encryption = true
+	te := newTestEncryption(t, pm)
+	te.remoteNode.Profile = fftypes.JSONObject{"id": "peer2"}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(te.localNode, nil)
+
+	_, _, err := pm.RunOperation(context.Background(), opSendBatch(&core.Operation{}, te.remoteNode, te.transport))
+	assert.Regexp(t, "FF10630", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestDecryptTransportNotEncrypted(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	valid, err := pm.DecryptTransport(pm.ctx, &core.TransportWrapper{Batch: &core.Batch{}})
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestDecryptTransportNoKey(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	valid, err := pm.DecryptTransport(pm.ctx, &core.TransportWrapper{
+		Batch:     &core.Batch{},
+		Encrypted: &core.EncryptedPayload{},
+	})
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestDecryptTransportLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	newTestEncryption(t, pm)
+	pm.encryptionKey, _ = loadEncryptionKey(context.Background())
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.DecryptTransport(pm.ctx, &core.TransportWrapper{
+		Batch:     &core.Batch{},
+		Encrypted: &core.EncryptedPayload{},
+	})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestDecryptTransportInvalid(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	te := newTestEncryption(t, pm)
+	pm.encryptionKey, _ = loadEncryptionKey(context.Background())
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(te.remoteNode, nil)
+
+	encrypted, err := pm.encryptTransport(pm.ctx, te.localNode, te.transport)
+	assert.NoError(t, err)
+	goodKey := encrypted.Encrypted.Keys[0].Key
+	goodData := encrypted.Encrypted.Data
+
+	// No key for this node
+	encrypted.Encrypted.Keys[0].Node = fftypes.NewUUID()
+	valid, err := pm.DecryptTransport(pm.ctx, encrypted)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// Key cannot be opened
+	encrypted.Encrypted.Keys[0].Node = te.remoteNode.ID
+	encrypted.Encrypted.Keys[0].Key = []byte("bad")
+	valid, err = pm.DecryptTransport(pm.ctx, encrypted)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// Data too short
+	encrypted.Encrypted.Keys[0].Key = goodKey
+	encrypted.Encrypted.Data = []byte("bad")
+	valid, err = pm.DecryptTransport(pm.ctx, encrypted)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	// Data tampered with
+	tampered := append([]byte{}, goodData...)
+	tampered[len(tampered)-1] ^= 0xff
+	encrypted.Encrypted.Data = tampered
+	valid, err = pm.DecryptTransport(pm.ctx, encrypted)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	mim.AssertExpectations(t)
+}
+
+func TestDecryptTransportBadData(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	te := newTestEncryption(t, pm)
+	pm.encryptionKey, _ = loadEncryptionKey(context.Background())
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(te.remoteNode, nil)
+
+	dataKey := make([]byte, 32)
+	gcm := newGCM(dataKey)
+	nonce := make([]byte, gcm.NonceSize())
+	wrapped, err := box.SealAnonymous(nil, dataKey, &pm.encryptionKey.public, rand.Reader)
+	assert.NoError(t, err)
+	transport := &core.TransportWrapper{
+		Batch: &core.Batch{},
+		Encrypted: &core.EncryptedPayload{
+			Keys: []*core.WrappedKey{{Node: te.remoteNode.ID, Key: wrapped}},
+			Data: gcm.Seal(nonce, nonce, []byte("!json"), nil),
+		},
+	}
+	valid, err := pm.DecryptTransport(pm.ctx, transport)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	mim.AssertExpectations(t)
+}
+
+type testEncryptedBlob struct {
+	pm         *privateMessaging
+	te         *testEncryption
+	blob       *core.Blob
+	content    []byte
+	ciphertext []byte
+}
+
+func newTestEncryptedBlob(t *testing.T, size int) (*testEncryptedBlob, func()) {
+	pm, cancel := newTestPrivateMessaging(t)
+	pm.encryption = true
+	te := newTestEncryption(t, pm)
+	content := make([]byte, size)
+	_, err := rand.Read(content)
+	assert.NoError(t, err)
+	dataID := fftypes.NewUUID()
+	return &testEncryptedBlob{
+		pm: pm,
+		te: te,
+		blob: &core.Blob{
+			Hash:       fftypes.NewRandB32(),
+			PayloadRef: "ns1/" + dataID.String(),
+			DataID:     dataID,
+		},
+		content: content,
+	}, cancel
+}
+
+// encrypt runs the encryption of the blob for the remote node, capturing the encrypted copy uploaded to data exchange
+func (tb *testEncryptedBlob) encrypt(t *testing.T) {
+	mdx := tb.pm.exchange.(*dataexchangemocks.Plugin)
+	encryptedRef := fmt.Sprintf("encrypted/%s/ns1/%s", tb.te.remoteNode.ID, tb.blob.DataID)
+	mdx.On("DownloadBlob", context.Background(), tb.blob.PayloadRef).Return(io.NopCloser(bytes.NewReader(tb.content)), nil).Once()
+	mdx.On("UploadBlob", context.Background(), "encrypted/"+tb.te.remoteNode.ID.String()+"/ns1", *tb.blob.DataID, mock.Anything).
+		Run(func(args mock.Arguments) {
+			var err error
+			tb.ciphertext, err = io.ReadAll(args[3].(io.Reader))
+			assert.NoError(t, err)
+		}).
+		Return(encryptedRef, fftypes.NewRandB32(), int64(0), nil).Once()
+
+	payloadRef, hash, err := tb.pm.encryptBlob(context.Background(), tb.te.remoteNode, tb.blob)
+	assert.NoError(t, err)
+	assert.Equal(t, encryptedRef, payloadRef)
+	assert.NotNil(t, hash)
+}
+
+func newTestBlobReceiver(t *testing.T) (*privateMessaging, func()) {
+	receiver, cancel := newTestPrivateMessaging(t)
+	config.Set(coreconfig.PrivateMessagingEncryptionPrivateKey, testPrivateKey)
+	var err error
+	receiver.encryptionKey, err = loadEncryptionKey(context.Background())
+	assert.NoError(t, err)
+	return receiver, cancel
+}
+
+func TestEncryptDecryptBlob(t *testing.T) {
+	// Sizes either side of the segment size, including an exact multiple, and empty content
+	for _, size := range []int{0, 10, encryptedBlobSegmentSize, 2*encryptedBlobSegmentSize + 123} {
+		tb, cancel := newTestEncryptedBlob(t, size)
+		defer cancel()
+		tb.encrypt(t)
+		assert.Equal(t, encryptedBlobMagic, tb.ciphertext[:len(encryptedBlobMagic)])
+		if size > 0 {
+			assert.False(t, bytes.Contains(tb.ciphertext, tb.content))
+		}
+
+		receiver, cancel2 := newTestBlobReceiver(t)
+		defer cancel2()
+		received := &core.Blob{
+			Hash:       fftypes.NewRandB32(),
+			PayloadRef: "peer1/encrypted/node2/ns1/" + tb.blob.DataID.String(),
+			DataID:     tb.blob.DataID,
+			Size:       int64(len(tb.ciphertext)),
+		}
+		decryptedHash := fftypes.NewRandB32()
+		var decrypted []byte
+		mdx := receiver.exchange.(*dataexchangemocks.Plugin)
+		mdx.On("DownloadBlob", receiver.ctx, received.PayloadRef).Return(io.NopCloser(bytes.NewReader(tb.ciphertext)), nil)
+		mdx.On("UploadBlob", receiver.ctx, "ns1", *tb.blob.DataID, mock.Anything).
+			Run(func(args mock.Arguments) {
+				var err error
+				decrypted, err = io.ReadAll(args[3].(io.Reader))
+				assert.NoError(t, err)
+			}).
+			Return("ns1/"+tb.blob.DataID.String(), decryptedHash, int64(size), nil)
+		mdx.On("DeleteBlob", receiver.ctx, "peer1/encrypted/node2/ns1/"+tb.blob.DataID.String()).Return(nil)
+
+		err := receiver.DecryptBlob(receiver.ctx, received)
+		assert.NoError(t, err)
+		assert.Equal(t, tb.content, decrypted)
+		assert.Equal(t, "ns1/"+tb.blob.DataID.String(), received.PayloadRef)
+		assert.Equal(t, decryptedHash, received.Hash)
+		assert.Equal(t, int64(size), received.Size)
+
+		mdx.AssertExpectations(t)
+	}
+}
+
+func TestEncryptBlobNoNodeKey(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+	tb.te.remoteNode.Profile = fftypes.JSONObject{"id": "peer2"}
+
+	_, _, err := tb.pm.encryptBlob(context.Background(), tb.te.remoteNode, tb.blob)
+	assert.Regexp(t, "FF10630", err)
+}
+
+func TestEncryptBlobRandFail(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+	defer func() { randReader = rand.Reader }()
+
+	// Fail reading the blob key, and the ephemeral key of the sealed box
+	for _, remaining := range []int{0, 32} {
+		randReader = &testFailingReader{remaining: remaining}
+		_, _, err := tb.pm.encryptBlob(context.Background(), tb.te.remoteNode, tb.blob)
+		assert.EqualError(t, err, "pop")
+	}
+}
+
+func TestEncryptBlobDownloadFail(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+
+	mdx := tb.pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", context.Background(), tb.blob.PayloadRef).Return(nil, fmt.Errorf("pop"))
+
+	_, _, err := tb.pm.encryptBlob(context.Background(), tb.te.remoteNode, tb.blob)
+	assert.EqualError(t, err, "pop")
+
+	mdx.AssertExpectations(t)
+}
+
+func TestEncryptBlobUploadFail(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+
+	mdx := tb.pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", context.Background(), tb.blob.PayloadRef).Return(io.NopCloser(&testFailingReader{}), nil)
+	mdx.On("UploadBlob", context.Background(), mock.Anything, *tb.blob.DataID, mock.Anything).
+		Run(func(args mock.Arguments) {
+			_, err := io.ReadAll(args[3].(io.Reader))
+			assert.EqualError(t, err, "pop")
+		}).
+		Return("", nil, int64(-1), fmt.Errorf("pop"))
+
+	_, _, err := tb.pm.encryptBlob(context.Background(), tb.te.remoteNode, tb.blob)
+	assert.EqualError(t, err, "pop")
+
+	mdx.AssertExpectations(t)
+}
+
+func TestRunOperationBlobSendEncrypted(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+
+	mim := tb.pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(tb.te.localNode, nil)
+	encryptedRef := fmt.Sprintf("encrypted/%s/ns1/%s", tb.te.remoteNode.ID, tb.blob.DataID)
+	encryptedHash := fftypes.NewRandB32()
+	mdx := tb.pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", context.Background(), tb.blob.PayloadRef).Return(io.NopCloser(bytes.NewReader(tb.content)), nil)
+	mdx.On("UploadBlob", context.Background(), mock.Anything, *tb.blob.DataID, mock.Anything).Return(encryptedRef, encryptedHash, int64(100), nil)
+	mdx.On("TransferBlob", context.Background(), mock.Anything, tb.te.remoteNode.Profile, tb.te.localNode.Profile, encryptedRef).Return(nil)
+
+	outputs, _, err := tb.pm.RunOperation(context.Background(), opSendBlob(&core.Operation{}, tb.te.remoteNode, tb.blob))
+	assert.NoError(t, err)
+	assert.Equal(t, encryptedRef, outputs.GetString("encryptedPayloadRef"))
+	assert.Equal(t, encryptedHash.String(), outputs.GetString("encryptedHash"))
+
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestRunOperationBlobSendEncryptFail(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+	tb.te.remoteNode.Profile = fftypes.JSONObject{"id": "peer2"}
+
+	mim := tb.pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(tb.te.localNode, nil)
+
+	_, _, err := tb.pm.RunOperation(context.Background(), opSendBlob(&core.Operation{}, tb.te.remoteNode, tb.blob))
+	assert.Regexp(t, "FF10630", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRunOperationBlobSendEncryptedTransferFail(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+
+	mim := tb.pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(tb.te.localNode, nil)
+	encryptedRef := fmt.Sprintf("encrypted/%s/ns1/%s", tb.te.remoteNode.ID, tb.blob.DataID)
+	mdx := tb.pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", context.Background(), tb.blob.PayloadRef).Return(io.NopCloser(bytes.NewReader(tb.content)), nil)
+	mdx.On("UploadBlob", context.Background(), mock.Anything, *tb.blob.DataID, mock.Anything).Return(encryptedRef, fftypes.NewRandB32(), int64(100), nil)
+	mdx.On("TransferBlob", context.Background(), mock.Anything, tb.te.remoteNode.Profile, tb.te.localNode.Profile, encryptedRef).Return(fmt.Errorf("pop"))
+	mdx.On("DeleteBlob", context.Background(), encryptedRef).Return(fmt.Errorf("pop2"))
+
+	_, _, err := tb.pm.RunOperation(context.Background(), opSendBlob(&core.Operation{}, tb.te.remoteNode, tb.blob))
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
+
+func TestDecryptBlobNoKey(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	err := pm.DecryptBlob(pm.ctx, &core.Blob{PayloadRef: "peer1/ns1/blob1"})
+	assert.NoError(t, err)
+}
+
+func TestDecryptBlobDownloadFail(t *testing.T) {
+	receiver, cancel := newTestBlobReceiver(t)
+	defer cancel()
+
+	mdx := receiver.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", receiver.ctx, "peer1/ns1/blob1").Return(nil, fmt.Errorf("pop"))
+
+	err := receiver.DecryptBlob(receiver.ctx, &core.Blob{PayloadRef: "peer1/ns1/blob1"})
+	assert.EqualError(t, err, "pop")
+
+	mdx.AssertExpectations(t)
+}
+
+func TestDecryptBlobNotEncrypted(t *testing.T) {
+	receiver, cancel := newTestBlobReceiver(t)
+	defer cancel()
+
+	mdx := receiver.exchange.(*dataexchangemocks.Plugin)
+	for _, content := range []string{"short", strings.Repeat("not encrypted", 100)} {
+		mdx.On("DownloadBlob", receiver.ctx, "peer1/ns1/blob1").Return(io.NopCloser(strings.NewReader(content)), nil).Once()
+
+		blob := &core.Blob{PayloadRef: "peer1/ns1/blob1"}
+		err := receiver.DecryptBlob(receiver.ctx, blob)
+		assert.NoError(t, err)
+		assert.Equal(t, "peer1/ns1/blob1", blob.PayloadRef)
+	}
+
+	mdx.AssertExpectations(t)
+}
+
+func TestDecryptBlobNotForThisNode(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+	tb.encrypt(t)
+
+	receiver, cancel2 := newTestBlobReceiver(t)
+	defer cancel2()
+	config.Set(coreconfig.PrivateMessagingEncryptionPrivateKey, base64.StdEncoding.EncodeToString(make([]byte, 32)))
+	var err error
+	receiver.encryptionKey, err = loadEncryptionKey(context.Background())
+	assert.NoError(t, err)
+
+	mdx := receiver.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", receiver.ctx, "peer1/ns1/blob1").Return(io.NopCloser(bytes.NewReader(tb.ciphertext)), nil)
+
+	blob := &core.Blob{PayloadRef: "peer1/ns1/blob1", DataID: tb.blob.DataID}
+	err = receiver.DecryptBlob(receiver.ctx, blob)
+	assert.NoError(t, err)
+	assert.Equal(t, "peer1/ns1/blob1", blob.PayloadRef)
+
+	mdx.AssertExpectations(t)
+}
+
+func TestDecryptBlobInvalidContent(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 2*encryptedBlobSegmentSize)
+	defer cancel()
+	tb.encrypt(t)
+
+	receiver, cancel2 := newTestBlobReceiver(t)
+	defer cancel2()
+
+	for _, ciphertext := range [][]byte{
+		// tampered
+		append(append([]byte{}, tb.ciphertext[:len(tb.ciphertext)-1]...), tb.ciphertext[len(tb.ciphertext)-1]^0xff),
+		// truncated at a segment boundary
+		tb.ciphertext[:encryptedBlobHeaderSize+encryptedBlobSegmentSize+16],
+		// no segments
+		tb.ciphertext[:encryptedBlobHeaderSize],
+	} {
+		mdx := receiver.exchange.(*dataexchangemocks.Plugin)
+		mdx.On("DownloadBlob", receiver.ctx, "peer1/ns1/blob1").Return(io.NopCloser(bytes.NewReader(ciphertext)), nil).Once()
+		mdx.On("UploadBlob", receiver.ctx, "ns1", *tb.blob.DataID, mock.Anything).
+			Run(func(args mock.Arguments) {
+				_, err := io.ReadAll(args[3].(io.Reader))
+				assert.Error(t, err)
+			}).
+			Return("", nil, int64(-1), fmt.Errorf("pop")).Once()
+
+		blob := &core.Blob{PayloadRef: "peer1/ns1/blob1", DataID: tb.blob.DataID}
+		err := receiver.DecryptBlob(receiver.ctx, blob)
+		assert.NoError(t, err)
+		assert.Equal(t, "peer1/ns1/blob1", blob.PayloadRef)
+
+		mdx.AssertExpectations(t)
+	}
+}
+
+func TestDecryptBlobUploadFail(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+	tb.encrypt(t)
+
+	receiver, cancel2 := newTestBlobReceiver(t)
+	defer cancel2()
+
+	mdx := receiver.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", receiver.ctx, "peer1/ns1/blob1").Return(io.NopCloser(bytes.NewReader(tb.ciphertext)), nil)
+	mdx.On("UploadBlob", receiver.ctx, "ns1", *tb.blob.DataID, mock.Anything).Return("", nil, int64(-1), fmt.Errorf("pop"))
+
+	err := receiver.DecryptBlob(receiver.ctx, &core.Blob{PayloadRef: "peer1/ns1/blob1", DataID: tb.blob.DataID})
+	assert.EqualError(t, err, "pop")
+
+	mdx.AssertExpectations(t)
+}
+
+func TestDecryptBlobDeleteFail(t *testing.T) {
+	tb, cancel := newTestEncryptedBlob(t, 10)
+	defer cancel()
+	tb.encrypt(t)
+
+	receiver, cancel2 := newTestBlobReceiver(t)
+	defer cancel2()
+
+	mdx := receiver.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DownloadBlob", receiver.ctx, "peer1/ns1/blob1").Return(io.NopCloser(bytes.NewReader(tb.ciphertext)), nil)
+	mdx.On("UploadBlob", receiver.ctx, "ns1", *tb.blob.DataID, mock.Anything).Return("ns1/blob1", fftypes.NewRandB32(), int64(10), nil)
+	mdx.On("DeleteBlob", receiver.ctx, "peer1/ns1/blob1").Return(fmt.Errorf("pop"))
+
+	blob := &core.Blob{PayloadRef: "peer1/ns1/blob1", DataID: tb.blob.DataID}
+	err := receiver.DecryptBlob(receiver.ctx, blob)
+	assert.NoError(t, err)
+	assert.Equal(t, "ns1/blob1", blob.PayloadRef)
+
+	mdx.AssertExpectations(t)
+}
+
+func TestBlobCipherReaderPeekFail(t *testing.T) {
+	r := newBlobCipherReader(io.MultiReader(bytes.NewReader(make([]byte, encryptedBlobSegmentSize)), &testFailingReader{}), make([]byte, 32), true)
+	_, err := io.ReadAll(r)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestOnOperationUpdateSendBlobEncrypted(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	blobHash := fftypes.NewRandB32()
+	encryptedHash := fftypes.NewRandB32()
+	op := &core.Operation{
+		Type:   core.OpTypeDataExchangeSendBlob,
+		Input:  fftypes.JSONObject{"hash": blobHash.String()},
+		Output: fftypes.JSONObject{"encryptedPayloadRef": "encrypted/node2/ns1/blob1", "encryptedHash": encryptedHash.String()},
+	}
+
+	// A pending update keeps the encrypted copy, and its details on the operation
+	update := &core.OperationUpdate{Status: core.OpStatusPending, Output: fftypes.JSONObject{"dx": "info"}}
+	err := pm.OnOperationUpdate(pm.ctx, op, update)
+	assert.NoError(t, err)
+	assert.Equal(t, "info", update.Output.GetString("dx"))
+	assert.Equal(t, "encrypted/node2/ns1/blob1", update.Output.GetString("encryptedPayloadRef"))
+	assert.Equal(t, encryptedHash.String(), update.Output.GetString("encryptedHash"))
+
+	// The acknowledgement of the encrypted copy is checked against the hash of the blob, and the copy is deleted
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("DeleteBlob", pm.ctx, "encrypted/node2/ns1/blob1").Return(nil).Once()
+	update = &core.OperationUpdate{Status: core.OpStatusSucceeded, DXHash: encryptedHash.String()}
+	err = pm.OnOperationUpdate(pm.ctx, op, update)
+	assert.NoError(t, err)
+	assert.Equal(t, blobHash.String(), update.DXHash)
+	assert.Equal(t, "encrypted/node2/ns1/blob1", update.Output.GetString("encryptedPayloadRef"))
+
+	// A different hash is left to fail the check
+	mdx.On("DeleteBlob", pm.ctx, "encrypted/node2/ns1/blob1").Return(fmt.Errorf("pop")).Once()
+	update = &core.OperationUpdate{Status: core.OpStatusSucceeded, DXHash: "wrong"}
+	err = pm.OnOperationUpdate(pm.ctx, op, update)
+	assert.NoError(t, err)
+	assert.Equal(t, "wrong", update.DXHash)
+
+	mdx.AssertExpectations(t)
+}
+
+func TestOnOperationUpdateSendBlobNotEncrypted(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	update := &core.OperationUpdate{Status: core.OpStatusSucceeded, DXHash: "hash1"}
+	err := pm.OnOperationUpdate(pm.ctx, &core.Operation{Type: core.OpTypeDataExchangeSendBlob}, update)
+	assert.NoError(t, err)
+	assert.Equal(t, "hash1", update.DXHash)
+	assert.Nil(t, update.Output)
+}
@@ -0,0 +1,270 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// ChangeGroupMembers creates the next generation of a group, with members added and removed.
+//
+// The next generation is a new group with a new hash, which is sent to its members with a group init message.
+// The members of the current generation, including any removed members, are notified of the change with a
+// message in the current group, which is then closed to new messages.
+func (pm *privateMessaging) ChangeGroupMembers(ctx context.Context, hash string, input *core.GroupMembersInput) (*core.Group, error) {
+	group, err := pm.GetGroupByID(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupNotFound, hash)
+	}
+	if group.Superseded != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupSuperseded, group.Hash)
+	}
+
+	if err := pm.identity.ResolveInputSigningIdentity(ctx, &input.SignerRef); err != nil {
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgAuthorInvalid)
+	}
+
+	removes, err := pm.resolveRemovedMembers(ctx, input.Remove)
+	if err != nil {
+		return nil, err
+	}
+	members := make(core.Members, 0, len(group.Members)+len(input.Add))
+	change := &core.GroupMembershipChange{
+		Group:      group.Hash,
+		Generation: group.Generation + 1,
+	}
+	for _, m := range group.Members {
+		if memberMatches(removes, m) {
+			change.Removed = append(change.Removed, m)
+		} else {
+			members = append(members, m)
+		}
+	}
+	for _, rInput := range input.Add {
+		identity, _, err := pm.identity.CachedIdentityLookupMustExist(ctx, rInput.Identity)
+		if err != nil {
+			return nil, err
+		}
+		node, err := pm.resolveNode(ctx, identity, rInput.Node)
+		if err != nil {
+			return nil, err
+		}
+		m := &core.Member{Identity: identity.DID, Node: node.ID}
+		if !memberMatches(members, m) {
+			members = append(members, m)
+			change.Added = append(change.Added, m)
+		}
+	}
+	if len(change.Added) == 0 && len(change.Removed) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupMembersUnchanged, group.Hash)
+	}
+
+	next := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace:  group.Namespace,
+			Name:       group.Name,
+			Members:    members,
+			Previous:   group.Hash,
+			Generation: change.Generation,
+		},
+		Created: fftypes.Now(),
+	}
+	next.Seal()
+	if !hasMemberIdentity(group, input.Author) || !hasMemberIdentity(next, input.Author) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGroupChangeAuthorNotMember, input.Author, group.Hash)
+	}
+
+	initMsg, initData, err := pm.prepareGroupInit(ctx, &input.SignerRef, next)
+	if err != nil {
+		return nil, err
+	}
+	changeMsg, changeData, err := pm.prepareGroupChange(ctx, &input.SignerRef, group, change, initMsg.Header.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	superseded := fftypes.Now()
+	err = pm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := pm.writeGroupInit(ctx, initMsg, initData, next); err != nil {
+			return err
+		}
+		if err := pm.database.UpsertData(ctx, changeData, database.UpsertOptimizationNew); err != nil {
+			return err
+		}
+		if err := pm.database.UpsertMessage(ctx, changeMsg, database.UpsertOptimizationNew); err != nil {
+			return err
+		}
+		update := database.GroupQueryFactory.NewUpdate(ctx).
+			Set("successor", next.Hash).
+			Set("superseded", superseded)
+		return pm.database.UpdateGroup(ctx, pm.namespace.Name, group.Hash, update)
+	})
+	if err != nil {
+		return nil, err
+	}
+	group.Successor = next.Hash
+	group.Superseded = superseded
+	pm.updateCachedGroup(group)
+
+	log.L(ctx).Infof("Changed members of group %s: generation=%d hash=%s added=%d removed=%d", group.Hash, next.Generation, next.Hash, len(change.Added), len(change.Removed))
+	return next, nil
+}
+
+// resolveRemovedMembers resolves the members to remove. A removal without a node matches every node of the identity.
+func (pm *privateMessaging) resolveRemovedMembers(ctx context.Context, inputs []core.MemberInput) (core.Members, error) {
+	removes := make(core.Members, len(inputs))
+	for i, rInput := range inputs {
+		identity, _, err := pm.identity.CachedIdentityLookupMustExist(ctx, rInput.Identity)
+		if err != nil {
+			return nil, err
+		}
+		removes[i] = &core.Member{Identity: identity.DID}
+		if rInput.Node != "" {
+			node, err := pm.resolveNode(ctx, identity, rInput.Node)
+			if err != nil {
+				return nil, err
+			}
+			removes[i].Node = node.ID
+		}
+	}
+	return removes, nil
+}
+
+func memberMatches(members core.Members, member *core.Member) bool {
+	for _, m := range members {
+		if m.Identity == member.Identity && (m.Node == nil || m.Node.Equals(member.Node)) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMemberIdentity(group *core.Group, did string) bool {
+	for _, m := range group.Members {
+		if m.Identity == did {
+			return true
+		}
+	}
+	return false
+}
+
+func (gm *groupManager) prepareGroupChange(ctx context.Context, signer *core.SignerRef, group *core.Group, change *core.GroupMembershipChange, initMsgID *fftypes.UUID) (*core.Message, *core.Data, error) {
+	data := &core.Data{
+		Validator: core.ValidatorTypeSystemDefinition,
+		ID:        fftypes.NewUUID(),
+		Namespace: gm.namespace.Name,
+		Created:   fftypes.Now(),
+	}
+	b, err := json.Marshal(&change)
+	if err == nil {
+		data.Value = fftypes.JSONAnyPtrBytes(b)
+		err = data.Seal(ctx, nil)
+	}
+	if err != nil {
+		return nil, nil, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
+	}
+
+	// The change is sent in the current generation of the group, so it reaches every current member,
+	// including those being removed. It is correlated to the group init message of the next generation.
+	msg := &core.Message{
+		State:          core.MessageStateReady,
+		LocalNamespace: gm.namespace.Name,
+		Header: core.MessageHeader{
+			Group:     group.Hash,
+			Namespace: gm.namespace.NetworkName,
+			Type:      core.MessageTypeGroupInit,
+			SignerRef: *signer,
+			Tag:       core.SystemTagChangeGroup,
+			Topics:    fftypes.FFStringArray{group.Topic()},
+			TxType:    core.TransactionTypeBatchPin,
+			CID:       initMsgID,
+		},
+		Data: core.DataRefs{
+			{ID: data.ID, Hash: data.Hash},
+		},
+	}
+	if err = msg.Seal(ctx); err != nil {
+		return nil, nil, err
+	}
+	return msg, data, nil
+}
+
+// ResolveGroupChange is called when a group init message is ready for dispatch, to process membership changes.
+// It returns whether the message changed the membership of a group that this node is notified of - either a change
+// in a group it is already a member of, or the next generation of a group it has been added to.
+func (gm *groupManager) ResolveGroupChange(ctx context.Context, msg *core.Message, data core.DataArray) (core.MessageAction, bool, error) {
+	switch msg.Header.Tag {
+	case core.SystemTagChangeGroup:
+		var change core.GroupMembershipChange
+		if len(data) != 1 || data[0].Value == nil || json.Unmarshal(data[0].Value.Bytes(), &change) != nil ||
+			!change.Group.Equals(msg.Header.Group) {
+			log.L(ctx).Warnf("Group %s change in message %s invalid", msg.Header.Group, msg.Header.ID)
+			return core.ActionReject, false, nil
+		}
+		group, err := gm.database.GetGroupByHash(ctx, gm.namespace.Name, msg.Header.Group)
+		if err != nil {
+			return core.ActionRetry, false, err
+		}
+		if group == nil {
+			log.L(ctx).Warnf("Group %s not found for change in message %s", msg.Header.Group, msg.Header.ID)
+			return core.ActionReject, false, nil
+		}
+		if group.Superseded == nil {
+			group.Superseded = fftypes.Now()
+			update := database.GroupQueryFactory.NewUpdate(ctx).Set("superseded", group.Superseded)
+			if err := gm.database.UpdateGroup(ctx, gm.namespace.Name, group.Hash, update); err != nil {
+				return core.ActionRetry, false, err
+			}
+			gm.updateCachedGroup(group)
+		}
+		return core.ActionConfirm, true, nil
+	case core.SystemTagDefineGroup:
+		var group core.Group
+		if len(data) == 0 || data[0].Value == nil || json.Unmarshal(data[0].Value.Bytes(), &group) != nil || group.Previous == nil {
+			return core.ActionConfirm, false, nil
+		}
+		// Members of the previous generation are notified by the change message in that generation
+		previous, err := gm.database.GetGroupByHash(ctx, gm.namespace.Name, group.Previous)
+		if err != nil {
+			return core.ActionRetry, false, err
+		}
+		return core.ActionConfirm, previous == nil, nil
+	default:
+		return core.ActionConfirm, false, nil
+	}
+}
+
+// updateCachedGroup replaces the group in the cache, if it is cached, keeping the resolved nodes
+func (gm *groupManager) updateCachedGroup(group *core.Group) {
+	if cachedValue := gm.groupCache.Get(group.Hash.String()); cachedValue != nil {
+		gm.groupCache.Set(group.Hash.String(), &groupHashEntry{
+			group: group,
+			nodes: cachedValue.(*groupHashEntry).nodes,
+		})
+	}
+}
@@ -0,0 +1,592 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testGroupChange struct {
+	org1, org2, org3    *core.Identity
+	node1, node2, node3 *core.Identity
+	group               *core.Group
+	input               *core.GroupMembersInput
+}
+
+func newTestGroupChange(pm *privateMessaging) *testGroupChange {
+	tgc := &testGroupChange{
+		org1: newTestOrg("org1"),
+		org2: newTestOrg("org2"),
+		org3: newTestOrg("org3"),
+	}
+	tgc.node1 = newTestNode("node1", tgc.org1)
+	tgc.node2 = newTestNode("node2", tgc.org2)
+	tgc.node3 = newTestNode("node3", tgc.org3)
+	tgc.group = &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Name:      "group1",
+			Namespace: "ns1",
+			Members: core.Members{
+				{Identity: tgc.org1.DID, Node: tgc.node1.ID},
+				{Identity: tgc.org2.DID, Node: tgc.node2.ID},
+			},
+		},
+	}
+	tgc.group.Seal()
+	tgc.input = &core.GroupMembersInput{
+		SignerRef: core.SignerRef{Author: "org1"},
+		Add:       []core.MemberInput{{Identity: "org3", Node: "node3"}},
+		Remove:    []core.MemberInput{{Identity: "org2"}},
+	}
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, &tgc.input.SignerRef).Run(func(args mock.Arguments) {
+		args[1].(*core.SignerRef).Author = tgc.org1.DID
+		args[1].(*core.SignerRef).Key = "0x12345"
+	}).Return(nil).Maybe()
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org2").Return(tgc.org2, false, nil).Maybe()
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org3").Return(tgc.org3, false, nil).Maybe()
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "node3").Return(tgc.node3, false, nil).Maybe()
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, tgc.org1.DID).Return(tgc.org1, false, nil).Maybe()
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, tgc.org3.DID).Return(tgc.org3, false, nil).Maybe()
+	mim.On("CachedIdentityLookupByID", pm.ctx, tgc.node1.ID).Return(tgc.node1, nil).Maybe()
+	mim.On("CachedIdentityLookupByID", pm.ctx, tgc.node3.ID).Return(tgc.node3, nil).Maybe()
+	mim.On("ValidateNodeOwner", pm.ctx, tgc.node1, tgc.org1).Return(true, nil).Maybe()
+	mim.On("ValidateNodeOwner", pm.ctx, tgc.node3, tgc.org3).Return(true, nil).Maybe()
+	return tgc
+}
+
+func TestChangeGroupMembersOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	pm.groupCache.Set(tgc.group.Hash.String(), &groupHashEntry{group: tgc.group})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil).Twice()
+	var msgs []*core.Message
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Run(func(args mock.Arguments) {
+		msgs = append(msgs, args[1].(*core.Message))
+	}).Return(nil).Twice()
+	mdi.On("UpsertGroup", pm.ctx, mock.MatchedBy(func(g *core.Group) bool {
+		return g.Previous.Equals(tgc.group.Hash)
+	}), database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpdateGroup", pm.ctx, "ns1", tgc.group.Hash, mock.Anything).Return(nil)
+
+	next, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.NoError(t, err)
+	assert.Equal(t, tgc.group.Hash, next.Previous)
+	assert.Equal(t, int64(1), next.Generation)
+	assert.Equal(t, "group1", next.Name)
+	assert.Len(t, next.Members, 2)
+	assert.True(t, hasMemberIdentity(next, tgc.org1.DID))
+	assert.True(t, hasMemberIdentity(next, tgc.org3.DID))
+	assert.NoError(t, next.Validate(pm.ctx, true))
+
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, core.SystemTagDefineGroup, msgs[0].Header.Tag)
+	assert.Equal(t, next.Hash, msgs[0].Header.Group)
+	assert.Equal(t, core.SystemTagChangeGroup, msgs[1].Header.Tag)
+	assert.Equal(t, tgc.group.Hash, msgs[1].Header.Group)
+	assert.Equal(t, msgs[0].Header.ID, msgs[1].Header.CID)
+	assert.Equal(t, tgc.group.Topic(), msgs[1].Header.Topics[0])
+
+	assert.Equal(t, next.Hash, tgc.group.Successor)
+	assert.NotNil(t, tgc.group.Superseded)
+	cached := pm.groupCache.Get(tgc.group.Hash.String()).(*groupHashEntry)
+	assert.NotNil(t, cached.group.Superseded)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestChangeGroupMembersBadHash(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, "!bad", &core.GroupMembersInput{})
+	assert.Regexp(t, "FF00107", err)
+}
+
+func TestChangeGroupMembersGetGroupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, fftypes.NewRandB32().String(), &core.GroupMembersInput{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestChangeGroupMembersGroupNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, fftypes.NewRandB32().String(), &core.GroupMembersInput{})
+	assert.Regexp(t, "FF10226", err)
+}
+
+func TestChangeGroupMembersSuperseded(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.group.Superseded = fftypes.Now()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.Regexp(t, "FF10622", err)
+}
+
+func TestChangeGroupMembersSignerFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	input := &core.GroupMembersInput{}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, &input.SignerRef).Return(fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), input)
+	assert.Regexp(t, "FF10206.*pop", err)
+}
+
+func TestChangeGroupMembersRemoveLookupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Remove = []core.MemberInput{{Identity: "unknown"}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, false, fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestChangeGroupMembersRemoveNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Remove = []core.MemberInput{{Identity: "org2", Node: "unknown"}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, true, fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestChangeGroupMembersRemoveNode(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Add = nil
+	tgc.input.Remove = []core.MemberInput{{Identity: "org2", Node: "node2"}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpdateGroup", pm.ctx, "ns1", tgc.group.Hash, mock.Anything).Return(nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "node2").Return(tgc.node2, false, nil)
+
+	next, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.NoError(t, err)
+	assert.Len(t, next.Members, 1)
+	assert.Equal(t, tgc.org1.DID, next.Members[0].Identity)
+}
+
+func TestChangeGroupMembersAddLookupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Add = []core.MemberInput{{Identity: "unknown"}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, false, fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestChangeGroupMembersAddNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Add = []core.MemberInput{{Identity: "org3", Node: "unknown"}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "unknown").Return(nil, true, fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestChangeGroupMembersUnchanged(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Add = []core.MemberInput{{Identity: "org1", Node: "node1"}}
+	tgc.input.Remove = nil
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org1").Return(tgc.org1, false, nil)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "node1").Return(tgc.node1, false, nil)
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.Regexp(t, "FF10623", err)
+}
+
+func TestChangeGroupMembersAuthorRemoved(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Remove = []core.MemberInput{{Identity: "org1"}}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org1").Return(tgc.org1, false, nil)
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.Regexp(t, "FF10624", err)
+}
+
+func TestChangeGroupMembersInvalidMember(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+	tgc.input.Add = []core.MemberInput{{Identity: "org2", Node: "node3"}}
+	tgc.input.Remove = nil
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", pm.ctx, tgc.node2.ID).Return(tgc.node2, nil)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, tgc.org2.DID).Return(tgc.org2, false, nil)
+	mim.On("ValidateNodeOwner", pm.ctx, tgc.node2, tgc.org2).Return(true, nil)
+	mim.On("ValidateNodeOwner", pm.ctx, tgc.node3, tgc.org2).Return(false, nil)
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.Regexp(t, "FF10422", err)
+}
+
+func TestChangeGroupMembersWriteFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpdateGroup", pm.ctx, "ns1", tgc.group.Hash, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+	assert.Nil(t, tgc.group.Superseded)
+}
+
+func TestChangeGroupMembersWriteInitFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestChangeGroupMembersWriteChangeDataFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil).Once()
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestChangeGroupMembersWriteChangeMessageFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tgc := newTestGroupChange(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tgc.group.Hash).Return(tgc.group, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil).Once()
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+
+	_, err := pm.ChangeGroupMembers(pm.ctx, tgc.group.Hash.String(), tgc.input)
+	assert.EqualError(t, err, "pop")
+}
+
+func newTestGroupChangeMessage(group *fftypes.Bytes32, change *core.GroupMembershipChange) (*core.Message, core.DataArray) {
+	b, _ := json.Marshal(change)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:    fftypes.NewUUID(),
+			Type:  core.MessageTypeGroupInit,
+			Tag:   core.SystemTagChangeGroup,
+			Group: group,
+		},
+	}, core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}
+}
+
+func TestResolveGroupChangeOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{Hash: fftypes.NewRandB32()}
+	pm.groupCache.Set(group.Hash.String(), &groupHashEntry{group: group})
+	msg, data := newTestGroupChangeMessage(group.Hash, &core.GroupMembershipChange{Group: group.Hash, Generation: 1})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Hash).Return(group, nil)
+	mdi.On("UpdateGroup", pm.ctx, "ns1", group.Hash, mock.Anything).Return(nil)
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.True(t, changed)
+	assert.NotNil(t, group.Superseded)
+	cached := pm.groupCache.Get(group.Hash.String()).(*groupHashEntry)
+	assert.NotNil(t, cached.group.Superseded)
+}
+
+func TestResolveGroupChangeAlreadySuperseded(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{Hash: fftypes.NewRandB32(), Superseded: fftypes.Now()}
+	msg, data := newTestGroupChangeMessage(group.Hash, &core.GroupMembershipChange{Group: group.Hash, Generation: 1})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Hash).Return(group, nil)
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.True(t, changed)
+}
+
+func TestResolveGroupChangeMismatchedGroup(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msg, data := newTestGroupChangeMessage(fftypes.NewRandB32(), &core.GroupMembershipChange{Group: fftypes.NewRandB32(), Generation: 1})
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+	assert.False(t, changed)
+}
+
+func TestResolveGroupChangeBadData(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	msg, _ := newTestGroupChangeMessage(fftypes.NewRandB32(), &core.GroupMembershipChange{})
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr("!json")},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+	assert.False(t, changed)
+}
+
+func TestResolveGroupChangeGetGroupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	hash := fftypes.NewRandB32()
+	msg, data := newTestGroupChangeMessage(hash, &core.GroupMembershipChange{Group: hash, Generation: 1})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", hash).Return(nil, fmt.Errorf("pop"))
+
+	action, _, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.ActionRetry, action)
+}
+
+func TestResolveGroupChangeGroupNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	hash := fftypes.NewRandB32()
+	msg, data := newTestGroupChangeMessage(hash, &core.GroupMembershipChange{Group: hash, Generation: 1})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", hash).Return(nil, nil)
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+	assert.False(t, changed)
+}
+
+func TestResolveGroupChangeUpdateFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{Hash: fftypes.NewRandB32()}
+	msg, data := newTestGroupChangeMessage(group.Hash, &core.GroupMembershipChange{Group: group.Hash, Generation: 1})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Hash).Return(group, nil)
+	mdi.On("UpdateGroup", pm.ctx, "ns1", group.Hash, mock.Anything).Return(fmt.Errorf("pop"))
+
+	action, _, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.ActionRetry, action)
+}
+
+func newTestDefineGroupMessage(group *core.Group) (*core.Message, core.DataArray) {
+	b, _ := json.Marshal(group)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:    fftypes.NewUUID(),
+			Type:  core.MessageTypeGroupInit,
+			Tag:   core.SystemTagDefineGroup,
+			Group: group.Hash,
+		},
+	}, core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}
+}
+
+func TestResolveGroupChangeDefineNewMember(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{GroupIdentity: core.GroupIdentity{Previous: fftypes.NewRandB32(), Generation: 1}}
+	group.Seal()
+	msg, data := newTestDefineGroupMessage(group)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Previous).Return(nil, nil)
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.True(t, changed)
+}
+
+func TestResolveGroupChangeDefineExistingMember(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{GroupIdentity: core.GroupIdentity{Previous: fftypes.NewRandB32(), Generation: 1}}
+	group.Seal()
+	msg, data := newTestDefineGroupMessage(group)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Previous).Return(&core.Group{Hash: group.Previous}, nil)
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.False(t, changed)
+}
+
+func TestResolveGroupChangeDefineGetPreviousFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{GroupIdentity: core.GroupIdentity{Previous: fftypes.NewRandB32(), Generation: 1}}
+	group.Seal()
+	msg, data := newTestDefineGroupMessage(group)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", group.Previous).Return(nil, fmt.Errorf("pop"))
+
+	action, _, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.ActionRetry, action)
+}
+
+func TestResolveGroupChangeDefineFirstGeneration(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	group := &core.Group{}
+	group.Seal()
+	msg, data := newTestDefineGroupMessage(group)
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.False(t, changed)
+}
+
+func TestResolveGroupChangeOtherTag(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	action, changed, err := pm.ResolveGroupChange(pm.ctx, &core.Message{
+		Header: core.MessageHeader{Type: core.MessageTypeGroupInit},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.False(t, changed)
+}
@@ -36,6 +36,7 @@ type GroupManager interface {
 	GetGroupByID(ctx context.Context, id string) (*core.Group, error)
 	GetGroups(ctx context.Context, filter ffapi.AndFilter) ([]*core.Group, *ffapi.FilterResult, error)
 	ResolveInitGroup(ctx context.Context, msg *core.Message, creator *core.Member) (*core.Group, error)
+	ResolveGroupChange(ctx context.Context, msg *core.Message, data core.DataArray) (core.MessageAction, bool, error)
 	EnsureLocalGroup(ctx context.Context, group *core.Group, creator *core.Member) (ok bool, err error)
 }
 
@@ -78,6 +79,20 @@ func (gm *groupManager) EnsureLocalGroup(ctx context.Context, group *core.Group,
 }
 
 func (gm *groupManager) groupInit(ctx context.Context, signer *core.SignerRef, group *core.Group) (err error) {
+	msg, data, err := gm.prepareGroupInit(ctx, signer, group)
+	if err != nil {
+		return err
+	}
+	err = gm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		return gm.writeGroupInit(ctx, msg, data, group)
+	})
+	if err == nil {
+		log.L(ctx).Infof("Created new group %s", group.Hash)
+	}
+	return err
+}
+
+func (gm *groupManager) prepareGroupInit(ctx context.Context, signer *core.SignerRef, group *core.Group) (*core.Message, *core.Data, error) {
 
 	// Serialize it into a data object, as a piece of data we can write to a message
 	data := &core.Data{
@@ -95,7 +110,7 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *core.SignerRef, g
 		}
 	}
 	if err != nil {
-		return i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
+		return nil, nil, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
 	}
 	group.LocalNamespace = gm.namespace.Name
 
@@ -103,17 +118,17 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *core.SignerRef, g
 	for _, member := range group.Members {
 		node, err := gm.identity.CachedIdentityLookupByID(ctx, member.Node)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		org, _, err := gm.identity.CachedIdentityLookupMustExist(ctx, member.Identity)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 		valid, err := gm.identity.ValidateNodeOwner(ctx, node, org)
 		if err != nil {
-			return err
+			return nil, nil, err
 		} else if !valid {
-			return i18n.NewError(ctx, coremsgs.MsgInvalidGroupMember, node.DID, member.Identity)
+			return nil, nil, i18n.NewError(ctx, coremsgs.MsgInvalidGroupMember, node.DID, member.Identity)
 		}
 	}
 
@@ -134,28 +149,27 @@ func (gm *groupManager) groupInit(ctx context.Context, signer *core.SignerRef, g
 			{ID: data.ID, Hash: data.Hash},
 		},
 	}
-	if err = msg.Seal(ctx); err == nil {
-		err = gm.database.RunAsGroup(ctx, func(ctx context.Context) error {
-			// Write as data to the local store
-			if err = gm.database.UpsertData(ctx, data, database.UpsertOptimizationNew); err != nil {
-				return err
-			}
+	if err = msg.Seal(ctx); err != nil {
+		return nil, nil, err
+	}
+	return msg, data, nil
+}
 
-			// Store the message - this asynchronously triggers the next step in process
-			if err = gm.database.UpsertMessage(ctx, msg, database.UpsertOptimizationNew); err != nil {
-				return err
-			}
+func (gm *groupManager) writeGroupInit(ctx context.Context, msg *core.Message, data *core.Data, group *core.Group) error {
+	// Write as data to the local store
+	if err := gm.database.UpsertData(ctx, data, database.UpsertOptimizationNew); err != nil {
+		return err
+	}
 
-			// Write the unconfirmed group directly to our database, so it can be used straight away.
-			// We're able to do this by making the identifier of the group a hash of the identity fields
-			// (name, ledger and member list), as that is all the group contains. There's no data in there.
-			return gm.database.UpsertGroup(ctx, group, database.UpsertOptimizationNew /* we think we're first */)
-		})
-		if err == nil {
-			log.L(ctx).Infof("Created new group %s", group.Hash)
-		}
+	// Store the message - this asynchronously triggers the next step in process
+	if err := gm.database.UpsertMessage(ctx, msg, database.UpsertOptimizationNew); err != nil {
+		return err
 	}
-	return err
+
+	// Write the unconfirmed group directly to our database, so it can be used straight away.
+	// We're able to do this by making the identifier of the group a hash of the identity fields
+	// (name, ledger and member list), as that is all the group contains. There's no data in there.
+	return gm.database.UpsertGroup(ctx, group, database.UpsertOptimizationNew /* we think we're first */)
 }
 
 func (gm *groupManager) GetGroupByID(ctx context.Context, hash string) (*core.Group, error) {
@@ -244,6 +258,13 @@ func (gm *groupManager) ResolveInitGroup(ctx context.Context, msg *core.Message,
 		if err != nil {
 			return nil, err
 		}
+		if newGroup.Previous != nil {
+			// Link the previous generation to this one, if we were a member of it
+			update := database.GroupQueryFactory.NewUpdate(ctx).Set("successor", newGroup.Hash)
+			if err = gm.database.UpdateGroup(ctx, gm.namespace.Name, newGroup.Previous, update); err != nil {
+				return nil, err
+			}
+		}
 		return &newGroup, nil
 	}
 
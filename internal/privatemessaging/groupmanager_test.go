@@ -427,6 +427,81 @@ func TestResolveInitGroupNewOk(t *testing.T) {
 
 }
 
+func TestResolveInitGroupNextGeneration(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	member := &core.Member{Identity: "abce12345", Node: fftypes.NewUUID()}
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Name:       "group1",
+			Namespace:  "ns1",
+			Members:    core.Members{member},
+			Previous:   fftypes.NewRandB32(),
+			Generation: 1,
+		},
+	}
+	group.Seal()
+	assert.NoError(t, group.Validate(pm.ctx, true))
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", pm.ctx, mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpdateGroup", pm.ctx, "ns1", group.Previous, mock.Anything).Return(nil)
+
+	newGroup, err := pm.ResolveInitGroup(pm.ctx, &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Tag:       core.SystemTagDefineGroup,
+			Group:     group.Hash,
+		},
+	}, member)
+	assert.NoError(t, err)
+	assert.Equal(t, group.Hash, newGroup.Hash)
+	mdi.AssertExpectations(t)
+}
+
+func TestResolveInitGroupNextGenerationUpdateFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	member := &core.Member{Identity: "abce12345", Node: fftypes.NewUUID()}
+	group := &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Name:       "group1",
+			Namespace:  "ns1",
+			Members:    core.Members{member},
+			Previous:   fftypes.NewRandB32(),
+			Generation: 1,
+		},
+	}
+	group.Seal()
+	b, _ := json.Marshal(&group)
+
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", pm.ctx, mock.Anything).Return(core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}, true, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("UpsertGroup", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpdateGroup", pm.ctx, "ns1", group.Previous, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := pm.ResolveInitGroup(pm.ctx, &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Tag:       core.SystemTagDefineGroup,
+			Group:     group.Hash,
+		},
+	}, member)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestResolveInitGroupExistingOK(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
@@ -18,6 +18,7 @@ package privatemessaging
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -60,8 +61,14 @@ func (pm *privateMessaging) RequestReply(ctx context.Context, in *core.MessageIn
 	if in.Header.CID != nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgRequestCannotHaveCID)
 	}
+	if in.SendTime != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgSendTimeWithWait)
+	}
+	if in.Draft {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDraftWithWait)
+	}
 	message := pm.NewMessage(in)
-	return pm.syncasync.WaitForReply(ctx, in.Header.ID, message.Send)
+	return pm.syncasync.WaitForReply(ctx, in.Header.ID, in.Reply, message.Send)
 }
 
 // sendMethod is the specific operation requested of the messageSender.
@@ -135,6 +142,31 @@ func (s *messageSender) resolveAndSend(ctx context.Context, method sendMethod) e
 func (s *messageSender) resolve(ctx context.Context) error {
 	msg := s.msg.Message
 
+	// Check the priority class, which is case insensitive on input
+	if msg.Priority != "" {
+		priority, err := fftypes.FFEnumParseString(ctx, "messagepriority", msg.Priority.String())
+		if err != nil {
+			return err
+		}
+		msg.Priority = priority
+	}
+
+	// Set the expiry from the TTL, which starts from the send time of a scheduled message
+	if msg.TTL != nil {
+		if msg.Draft {
+			return i18n.NewError(ctx, coremsgs.MsgDraftWithTTL)
+		}
+		if *msg.TTL <= 0 {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidMessageTTL, msg.TTL)
+		}
+		start := time.Now()
+		if msg.SendTime != nil && time.Time(*msg.SendTime).After(start) {
+			start = time.Time(*msg.SendTime)
+		}
+		expires := fftypes.FFTime(start.Add(time.Duration(*msg.TTL)))
+		msg.Expires = &expires
+	}
+
 	// Resolve the sending identity
 	if err := s.mgr.identity.ResolveInputSigningIdentity(ctx, &msg.Header.SignerRef); err != nil {
 		return i18n.WrapError(ctx, err, coremsgs.MsgAuthorInvalid)
@@ -145,6 +177,13 @@ func (s *messageSender) resolve(ctx context.Context) error {
 		return err
 	}
 
+	// A reply in a thread must be to a message this node has, in the same group
+	if msg.Header.ParentMessage != nil {
+		if err := s.mgr.data.CheckParentMessage(ctx, &msg.Message); err != nil {
+			return err
+		}
+	}
+
 	// The data manager is responsible for the heavy lifting of storing/validating all our in-line data elements
 	err := s.mgr.data.ResolveInlineData(ctx, s.msg)
 	return err
@@ -154,6 +193,12 @@ func (s *messageSender) sendInternal(ctx context.Context, method sendMethod) err
 	msg := &s.msg.Message.Message
 
 	if method == methodSendAndWait {
+		if msg.SendTime != nil {
+			return i18n.NewError(ctx, coremsgs.MsgSendTimeWithWait)
+		}
+		if s.msg.Message.Draft {
+			return i18n.NewError(ctx, coremsgs.MsgDraftWithWait)
+		}
 		// Pass it to the sync-async handler to wait for the confirmation to come back in.
 		// NOTE: Our caller makes sure we are not in a RunAsGroup (which would be bad)
 		out, err := s.mgr.syncasync.WaitForMessage(ctx, msg.Header.ID, s.Send)
@@ -171,6 +216,23 @@ func (s *messageSender) sendInternal(ctx context.Context, method sendMethod) err
 		return nil
 	}
 
+	// Hold the message for the batch manager to dispatch at its send time
+	if msg.SendTime != nil && time.Time(*msg.SendTime).After(time.Now()) {
+		msg.State = core.MessageStateScheduled
+	}
+
+	// Hold a draft until it is committed, unless the caller has set its own state (such as for a token transfer)
+	if s.msg.Message.Draft && (msg.State == core.MessageStateReady || msg.State == core.MessageStateScheduled) {
+		msg.State = core.MessageStateDraft
+	}
+
+	// Reject a message that is ready to send, while the batch queue of any of its topics is over its limit
+	if msg.State == core.MessageStateReady {
+		if err := s.mgr.batch.CheckBackpressure(ctx, msg.Header.Topics); err != nil {
+			return err
+		}
+	}
+
 	// Store the message - this asynchronously triggers the next step in process
 	if err := s.mgr.data.WriteNewMessage(ctx, s.msg); err != nil {
 		return err
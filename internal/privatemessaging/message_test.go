@@ -20,11 +20,13 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/batch"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/syncasync"
+	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
@@ -171,6 +173,266 @@ func TestSendUnpinnedMessageE2EOk(t *testing.T) {
 
 }
 
+func TestSendScheduledMessage(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", pm.ctx, mock.MatchedBy(func(newMsg *data.NewMessage) bool {
+		return newMsg.Message.State == core.MessageStateScheduled
+	})).Return(nil).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+
+	sendTime := fftypes.FFTime(time.Now().Add(1 * time.Hour))
+	ttl := fftypes.FFDuration(1 * time.Minute)
+	msg, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+			SendTime: &sendTime,
+			Priority: core.MessagePriorityHigh,
+		},
+		TTL: &ttl,
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"some": "data"}`)},
+		},
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateScheduled, msg.State)
+	// The TTL starts from the send time
+	assert.Equal(t, time.Time(sendTime).Add(1*time.Minute).UnixNano(), msg.Expires.UnixNano())
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+
+}
+
+func TestSendScheduledMessageWaitConfirm(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+			SendTime: fftypes.Now(),
+		},
+	}, true)
+	assert.Regexp(t, "FF10617", err)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+
+}
+
+func TestSendDraftMessage(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+	mdm.On("WriteNewMessage", pm.ctx, mock.MatchedBy(func(newMsg *data.NewMessage) bool {
+		return newMsg.Message.State == core.MessageStateDraft
+	})).Return(nil).Once()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+
+	msg, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"some": "data"}`)},
+		},
+		Draft: true,
+	}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageStateDraft, msg.State)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+
+}
+
+func TestSendDraftMessageWaitConfirm(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+		Draft: true,
+	}, true)
+	assert.Regexp(t, "FF10661", err)
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+
+}
+
+func TestSendDraftMessageTTL(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	ttl := fftypes.FFDuration(1 * time.Minute)
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: fftypes.NewRandB32(),
+			},
+		},
+		TTL:   &ttl,
+		Draft: true,
+	}, false)
+	assert.Regexp(t, "FF10662", err)
+
+}
+
+func TestSendMessageBackpressure(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+
+	groupID := fftypes.NewRandB32()
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("ResolveInlineData", pm.ctx, mock.Anything).Return(nil)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+
+	mba := &batchmocks.Manager{}
+	mba.On("CheckBackpressure", pm.ctx, []string{"topic1"}).Return(fmt.Errorf("pop"))
+	pm.batch = mba
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group:  groupID,
+				Topics: fftypes.FFStringArray{"topic1"},
+			},
+		},
+		InlineData: core.InlineData{
+			{Value: fftypes.JSONAnyPtr(`{"some": "data"}`)},
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mdm.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mba.AssertExpectations(t)
+
+}
+
+func TestSendMessageBadPriority(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Priority: "urgent",
+		},
+	}, false)
+	assert.Regexp(t, "FF00172", err)
+
+}
+
+func TestSendMessageBadTTL(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	ttl := fftypes.FFDuration(-1 * time.Second)
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		TTL: &ttl,
+	}, false)
+	assert.Regexp(t, "FF10619", err)
+
+}
+
+func TestSendMessageParentMessageFail(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+	parentID := fftypes.NewUUID()
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID}, nil)
+	mdm := pm.data.(*datamocks.Manager)
+	mdm.On("CheckParentMessage", pm.ctx, mock.MatchedBy(func(msg *core.Message) bool {
+		return msg.Header.ParentMessage.Equals(parentID) && msg.Header.Group.Equals(groupID)
+	})).Return(fmt.Errorf("pop"))
+
+	_, err := pm.SendMessage(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group:         groupID,
+				ParentMessage: parentID,
+			},
+		},
+	}, false)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+	mdm.AssertExpectations(t)
+}
+
 func TestSendMessageBadGroup(t *testing.T) {
 
 	pm, cancel := newTestPrivateMessaging(t)
@@ -591,6 +853,38 @@ func TestRequestReplyInvalidCID(t *testing.T) {
 	assert.Regexp(t, "FF10262", err)
 }
 
+func TestRequestReplySendTime(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Tag:   "mytag",
+				Group: fftypes.NewRandB32(),
+			},
+			SendTime: fftypes.Now(),
+		},
+	})
+	assert.Regexp(t, "FF10617", err)
+}
+
+func TestRequestReplyDraft(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.RequestReply(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Tag:   "mytag",
+				Group: fftypes.NewRandB32(),
+			},
+		},
+		Draft: true,
+	})
+	assert.Regexp(t, "FF10661", err)
+}
+
 func TestRequestReplySuccess(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
@@ -599,9 +893,9 @@ func TestRequestReplySuccess(t *testing.T) {
 	mim.On("ResolveInputSigningIdentity", pm.ctx, mock.Anything).Return(nil)
 
 	msa := pm.syncasync.(*syncasyncmocks.Bridge)
-	msa.On("WaitForReply", pm.ctx, mock.Anything, mock.Anything).
+	msa.On("WaitForReply", pm.ctx, mock.Anything, mock.Anything, mock.Anything).
 		Run(func(args mock.Arguments) {
-			send := args[2].(syncasync.SendFunction)
+			send := args[3].(syncasync.SendFunction)
 			send(pm.ctx)
 		}).
 		Return(nil, nil)
@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -37,6 +38,11 @@ type batchSendData struct {
 	Transport *core.TransportWrapper `json:"transport"`
 }
 
+type receiptsSendData struct {
+	Node      *core.Identity         `json:"node"`
+	Transport *core.TransportWrapper `json:"transport"`
+}
+
 func addTransferBlobInputs(op *core.Operation, nodeID *fftypes.UUID, blobHash *fftypes.Bytes32, dataID *fftypes.UUID) {
 	op.Input = fftypes.JSONObject{
 		"node":    nodeID.String(),
@@ -80,6 +86,25 @@ func retrieveBatchSendInputs(ctx context.Context, op *core.Operation) (nodeID *f
 	return nodeID, groupHash, batchID, err
 }
 
+func addReceiptsSendInputs(op *core.Operation, nodeID *fftypes.UUID, receipts []*core.MessageReceipt) {
+	op.Input = fftypes.JSONObject{
+		"node":     nodeID.String(),
+		"receipts": receipts,
+	}
+}
+
+func retrieveReceiptsSendInputs(ctx context.Context, op *core.Operation) (nodeID *fftypes.UUID, receipts []*core.MessageReceipt, err error) {
+	nodeID, err = fftypes.ParseUUID(ctx, op.Input.GetString("node"))
+	if err != nil {
+		return nil, nil, err
+	}
+	b, _ := json.Marshal(op.Input["receipts"])
+	if err = json.Unmarshal(b, &receipts); err != nil {
+		return nil, nil, i18n.WrapError(ctx, err, coremsgs.MsgJSONDecodeFailed)
+	}
+	return nodeID, receipts, nil
+}
+
 func (pm *privateMessaging) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	switch op.Type {
 	case core.OpTypeDataExchangeSendBlob:
@@ -132,6 +157,19 @@ func (pm *privateMessaging) PrepareOperation(ctx context.Context, op *core.Opera
 		transport := &core.TransportWrapper{Group: group, Batch: batch}
 		return opSendBatch(op, node, transport), nil
 
+	case core.OpTypeDataExchangeSendReceipts:
+		nodeID, receipts, err := retrieveReceiptsSendInputs(ctx, op)
+		if err != nil {
+			return nil, err
+		}
+		node, err := pm.identity.CachedIdentityLookupByID(ctx, nodeID)
+		if err != nil {
+			return nil, err
+		} else if node == nil {
+			return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+		}
+		return opSendReceipts(op, node, receipts), nil
+
 	default:
 		return nil, i18n.NewError(ctx, coremsgs.MsgOperationNotSupported, op.Type)
 	}
@@ -144,7 +182,24 @@ func (pm *privateMessaging) RunOperation(ctx context.Context, op *core.PreparedO
 		if err != nil {
 			return nil, false, err
 		}
-		return nil, false, pm.exchange.TransferBlob(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, data.Blob.PayloadRef)
+		if !pm.encryption {
+			return nil, false, pm.exchange.TransferBlob(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, data.Blob.PayloadRef)
+		}
+
+		encryptedRef, encryptedHash, err := pm.encryptBlob(ctx, data.Node, data.Blob)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := pm.exchange.TransferBlob(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, encryptedRef); err != nil {
+			if deleteErr := pm.exchange.DeleteBlob(ctx, encryptedRef); deleteErr != nil {
+				log.L(ctx).Warnf("Failed to delete encrypted blob '%s': %s", encryptedRef, deleteErr)
+			}
+			return nil, false, err
+		}
+		return fftypes.JSONObject{
+			"encryptedPayloadRef": encryptedRef,
+			"encryptedHash":       encryptedHash.String(),
+		}, false, nil
 
 	case batchSendData:
 		localNode, err := pm.identity.GetLocalNode(ctx)
@@ -152,18 +207,36 @@ func (pm *privateMessaging) RunOperation(ctx context.Context, op *core.PreparedO
 			return nil, false, err
 		}
 
-		payload, err := json.Marshal(data.Transport)
+		transport := data.Transport
+		if pm.encryption {
+			if transport, err = pm.encryptTransport(ctx, localNode, transport); err != nil {
+				return nil, false, err
+			}
+		}
+		payload, err := json.Marshal(transport)
 		if err != nil {
 			return nil, false, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
 		}
 		return nil, false, pm.exchange.SendMessage(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, payload)
 
+	case receiptsSendData:
+		localNode, err := pm.identity.GetLocalNode(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+
+		payload, _ := json.Marshal(data.Transport)
+		return nil, false, pm.exchange.SendMessage(ctx, op.NamespacedIDString(), data.Node.Profile, localNode.Profile, payload)
+
 	default:
 		return nil, false, i18n.NewError(ctx, coremsgs.MsgOperationDataIncorrect, op.Data)
 	}
 }
 
 func (pm *privateMessaging) OnOperationUpdate(ctx context.Context, op *core.Operation, update *core.OperationUpdate) error {
+	if op != nil && op.Type == core.OpTypeDataExchangeSendBlob {
+		pm.onSendBlobUpdate(ctx, op, update)
+	}
 	return nil
 }
 
@@ -186,3 +259,13 @@ func opSendBatch(op *core.Operation, node *core.Identity, transport *core.Transp
 		Data:      batchSendData{Node: node, Transport: transport},
 	}
 }
+
+func opSendReceipts(op *core.Operation, node *core.Identity, receipts []*core.MessageReceipt) *core.PreparedOperation {
+	return &core.PreparedOperation{
+		ID:        op.ID,
+		Namespace: op.Namespace,
+		Plugin:    op.Plugin,
+		Type:      op.Type,
+		Data:      receiptsSendData{Node: node, Transport: &core.TransportWrapper{Receipts: receipts}},
+	}
+}
@@ -17,6 +17,7 @@ package privatemessaging
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -698,3 +699,149 @@ func TestRetrieveBSendBlobInputs(t *testing.T) {
 	n, h, d, err = retrieveSendBlobInputs(context.Background(), op)
 	assert.Regexp(t, "FF00138", err)
 }
+
+func TestPrepareAndRunReceiptsSend(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:      core.OpTypeDataExchangeSendReceipts,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	node := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID: fftypes.NewUUID(),
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id": "peer1",
+			},
+		},
+	}
+	localNode := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID: fftypes.NewUUID(),
+		},
+		IdentityProfile: core.IdentityProfile{
+			Profile: fftypes.JSONObject{
+				"id": "local1",
+			},
+		},
+	}
+	receipts := []*core.MessageReceipt{{
+		ID:      fftypes.NewUUID(),
+		Message: fftypes.NewUUID(),
+		Type:    core.MessageReceiptTypeDelivered,
+	}}
+	addReceiptsSendInputs(op, node.ID, receipts)
+
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(localNode, nil)
+	mim.On("CachedIdentityLookupByID", context.Background(), node.ID).Return(node, nil)
+	mdx.On("SendMessage", context.Background(), "ns1:"+op.ID.String(), node.Profile, localNode.Profile, mock.MatchedBy(func(payload []byte) bool {
+		var tw core.TransportWrapper
+		err := json.Unmarshal(payload, &tw)
+		return err == nil && tw.Batch == nil && len(tw.Receipts) == 1 && tw.Receipts[0].ID.Equals(receipts[0].ID)
+	})).Return(nil)
+
+	po, err := pm.PrepareOperation(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, node, po.Data.(receiptsSendData).Node)
+	assert.Equal(t, receipts[0].ID, po.Data.(receiptsSendData).Transport.Receipts[0].ID)
+
+	_, complete, err := pm.RunOperation(context.Background(), po)
+
+	assert.False(t, complete)
+	assert.NoError(t, err)
+
+	mdx.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestPrepareOperationReceiptsSendBadInput(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type:  core.OpTypeDataExchangeSendReceipts,
+		Input: fftypes.JSONObject{"node": "bad"},
+	}
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestPrepareOperationReceiptsSendBadReceipts(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{
+		Type: core.OpTypeDataExchangeSendReceipts,
+		Input: fftypes.JSONObject{
+			"node":     fftypes.NewUUID().String(),
+			"receipts": "bad",
+		},
+	}
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10103", err)
+}
+
+func TestPrepareOperationReceiptsSendNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type: core.OpTypeDataExchangeSendReceipts,
+	}
+	addReceiptsSendInputs(op, nodeID, []*core.MessageReceipt{})
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", context.Background(), nodeID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestPrepareOperationReceiptsSendNodeNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	nodeID := fftypes.NewUUID()
+	op := &core.Operation{
+		Type: core.OpTypeDataExchangeSendReceipts,
+	}
+	addReceiptsSendInputs(op, nodeID, []*core.MessageReceipt{})
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupByID", context.Background(), nodeID).Return(nil, nil)
+
+	_, err := pm.PrepareOperation(context.Background(), op)
+	assert.Regexp(t, "FF10109", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestRunOperationReceiptsSendNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	op := &core.Operation{}
+	node := &core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID: fftypes.NewUUID(),
+		},
+	}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(nil, fmt.Errorf("pop"))
+
+	_, complete, err := pm.RunOperation(context.Background(), opSendReceipts(op, node, []*core.MessageReceipt{}))
+
+	assert.False(t, complete)
+	assert.EqualError(t, err, "pop")
+}
@@ -51,6 +51,16 @@ type Manager interface {
 	NewMessage(msg *core.MessageInOut) syncasync.Sender
 	SendMessage(ctx context.Context, in *core.MessageInOut, waitConfirm bool) (out *core.Message, err error)
 	RequestReply(ctx context.Context, request *core.MessageInOut) (reply *core.MessageInOut, err error)
+	ChangeGroupMembers(ctx context.Context, hash string, input *core.GroupMembersInput) (*core.Group, error)
+	SendDeliveryReceipts(ctx context.Context, nodeID, txID *fftypes.UUID, msgs []*core.Message) error
+	MarkMessageRead(ctx context.Context, id string, input *core.MessageReadInput) (*core.MessageReceipt, error)
+	GetMessageDeliveryStatus(ctx context.Context, id string) (*core.MessageDeliveryStatus, error)
+	DecryptTransport(ctx context.Context, transport *core.TransportWrapper) (bool, error)
+	DecryptBlob(ctx context.Context, blob *core.Blob) error
+	RecallMessage(ctx context.Context, id string, input *core.MessageRecallInput) (*core.Message, error)
+	ResolveRecall(ctx context.Context, msg *core.Message, data core.DataArray) (action core.MessageAction, recalled *core.Message, purge bool, err error)
+	GetDisclosureProof(ctx context.Context, id string, input *core.DisclosureInput) (*core.DisclosureProof, error)
+	VerifyDisclosureProof(ctx context.Context, proof *core.DisclosureProof) (*core.DisclosureVerification, error)
 
 	// From operations.OperationHandler
 	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
@@ -67,6 +77,7 @@ type privateMessaging struct {
 	exchange              dataexchange.Plugin
 	blockchain            blockchain.Plugin
 	data                  data.Manager
+	batch                 batch.Manager
 	syncasync             syncasync.Bridge
 	multiparty            multiparty.Manager
 	retry                 retry.Retry
@@ -74,6 +85,9 @@ type privateMessaging struct {
 	metrics               metrics.Manager
 	operations            operations.Manager
 	orgFirstNodes         map[string]*core.Identity
+	deliveryReceipts      bool
+	encryption            bool
+	encryptionKey         *encryptionKey
 }
 
 type blobTransferTracker struct {
@@ -95,6 +109,7 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 		exchange:   dx,
 		blockchain: bi,
 		data:       dm,
+		batch:      ba,
 		syncasync:  sa,
 		multiparty: mult,
 		groupManager: groupManager{
@@ -112,6 +127,8 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 		metrics:               mm,
 		operations:            om,
 		orgFirstNodes:         make(map[string]*core.Identity),
+		deliveryReceipts:      config.GetBool(coreconfig.PrivateMessagingReceiptsDelivery),
+		encryption:            config.GetBool(coreconfig.PrivateMessagingEncryptionEnabled),
 	}
 
 	groupCache, err := cacheManager.GetCache(
@@ -128,12 +145,18 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 
 	pm.groupManager.groupCache = groupCache
 
+	if pm.encryptionKey, err = loadEncryptionKey(ctx); err != nil {
+		return nil, err
+	}
+
 	bo := batch.DispatcherOptions{
 		BatchType:      core.BatchTypePrivate,
 		BatchMaxSize:   config.GetUint(coreconfig.PrivateMessagingBatchSize),
 		BatchMaxBytes:  pm.maxBatchPayloadLength,
 		BatchTimeout:   config.GetDuration(coreconfig.PrivateMessagingBatchTimeout),
 		DisposeTimeout: config.GetDuration(coreconfig.PrivateMessagingBatchAgentTimeout),
+		// Only takes effect for the pinned dispatchers, as unpinned batches have no hash on the blockchain to prove against
+		DisclosureProofs: config.GetBool(coreconfig.PrivateMessagingDisclosureEnabled),
 	}
 
 	ba.RegisterDispatcher(pinnedPrivateDispatcherName,
@@ -163,6 +186,7 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 	om.RegisterHandler(ctx, pm, []core.OpType{
 		core.OpTypeDataExchangeSendBlob,
 		core.OpTypeDataExchangeSendBatch,
+		core.OpTypeDataExchangeSendReceipts,
 	})
 
 	return pm, nil
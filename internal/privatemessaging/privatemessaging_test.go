@@ -64,6 +64,7 @@ func newTestPrivateMessagingCommon(t *testing.T, metricsEnabled bool) (*privateM
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	mockRunAsGroupPassthrough(mdi)
 
+	mba.On("CheckBackpressure", mock.Anything, mock.Anything).Return(nil).Maybe()
 	mba.On("RegisterDispatcher",
 		pinnedPrivateDispatcherName,
 		core.TransactionTypeBatchPin,
@@ -0,0 +1,154 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// RecallMessage recalls a confirmed private message sent by this node, by sending a recall message to its group.
+//
+// The recall message is sent by the same author, on the same topics, so it is ordered after the message it recalls
+// on every member node. Each member marks the message recalled when the recall is confirmed.
+func (pm *privateMessaging) RecallMessage(ctx context.Context, id string, input *core.MessageRecallInput) (*core.Message, error) {
+	msg, err := pm.getPrivateMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.State == core.MessageStateRecalled {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageAlreadyRecalled, msg.Header.ID)
+	}
+	if msg.State != core.MessageStateConfirmed {
+		return nil, i18n.NewError(ctx, coremsgs.MsgRecallNotConfirmed, msg.Header.ID, msg.State)
+	}
+	if msg.BatchID == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgRecallNotOwnMessage, msg.Header.ID)
+	}
+	batch, err := pm.database.GetBatchByID(ctx, pm.namespace.Name, msg.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !batch.Node.Equals(localNode.ID) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgRecallNotOwnMessage, msg.Header.ID)
+	}
+
+	recall := &core.MessageRecall{
+		Message: msg.Header.ID,
+		Hash:    msg.Hash,
+		Purge:   input.Purge,
+	}
+	recallMsg, recallData, err := pm.prepareRecall(ctx, msg, recall)
+	if err != nil {
+		return nil, err
+	}
+	err = pm.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := pm.database.UpsertData(ctx, recallData, database.UpsertOptimizationNew); err != nil {
+			return err
+		}
+		return pm.database.UpsertMessage(ctx, recallMsg, database.UpsertOptimizationNew)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.L(ctx).Infof("Recalling message %s in group %s with message %s (purge=%t)", msg.Header.ID, msg.Header.Group, recallMsg.Header.ID, recall.Purge)
+	return recallMsg, nil
+}
+
+func (pm *privateMessaging) prepareRecall(ctx context.Context, msg *core.Message, recall *core.MessageRecall) (*core.Message, *core.Data, error) {
+	data := &core.Data{
+		Validator: core.ValidatorTypeSystemDefinition,
+		ID:        fftypes.NewUUID(),
+		Namespace: pm.namespace.Name,
+		Created:   fftypes.Now(),
+	}
+	b, err := json.Marshal(&recall)
+	if err == nil {
+		data.Value = fftypes.JSONAnyPtrBytes(b)
+		err = data.Seal(ctx, nil)
+	}
+	if err != nil {
+		return nil, nil, i18n.WrapError(ctx, err, coremsgs.MsgSerializationFailed)
+	}
+
+	// The recall is always pinned, with the same author and topics as the message it recalls,
+	// and is correlated to that message
+	recallMsg := &core.Message{
+		State:          core.MessageStateReady,
+		LocalNamespace: pm.namespace.Name,
+		Header: core.MessageHeader{
+			Group:     msg.Header.Group,
+			Namespace: pm.namespace.NetworkName,
+			Type:      core.MessageTypePrivate,
+			SignerRef: msg.Header.SignerRef,
+			Tag:       core.SystemTagRecallMessage,
+			Topics:    msg.Header.Topics,
+			TxType:    core.TransactionTypeBatchPin,
+			CID:       msg.Header.ID,
+		},
+		Data: core.DataRefs{
+			{ID: data.ID, Hash: data.Hash},
+		},
+	}
+	if err = recallMsg.Seal(ctx); err != nil {
+		return nil, nil, err
+	}
+	return recallMsg, data, nil
+}
+
+// ResolveRecall is called when a recall message is ready for dispatch. It returns the message being recalled,
+// and whether its data should be purged, if the recall is valid.
+//
+// A recall is only valid from the author of the message, in the same group, and with the hash of the message.
+func (pm *privateMessaging) ResolveRecall(ctx context.Context, msg *core.Message, data core.DataArray) (action core.MessageAction, recalled *core.Message, purge bool, err error) {
+	var recall core.MessageRecall
+	if len(data) != 1 || data[0].Value == nil || json.Unmarshal(data[0].Value.Bytes(), &recall) != nil || recall.Message == nil {
+		log.L(ctx).Warnf("Recall in message %s invalid", msg.Header.ID)
+		return core.ActionReject, nil, false, nil
+	}
+	recalled, err = pm.database.GetMessageByID(ctx, pm.namespace.Name, recall.Message)
+	if err != nil {
+		return core.ActionRetry, nil, false, err
+	}
+	switch {
+	case recalled == nil:
+		log.L(ctx).Warnf("Message %s recalled by message %s not found", recall.Message, msg.Header.ID)
+		return core.ActionReject, nil, false, nil
+	case !recalled.Header.Group.Equals(msg.Header.Group) || recalled.Header.Author != msg.Header.Author || !recalled.Hash.Equals(recall.Hash):
+		log.L(ctx).Warnf("Message %s recalled by message %s does not match the group, author or hash of the recall", recall.Message, msg.Header.ID)
+		return core.ActionReject, nil, false, nil
+	case recalled.State == core.MessageStateRecalled:
+		log.L(ctx).Warnf("Message %s recalled by message %s has already been recalled", recall.Message, msg.Header.ID)
+		return core.ActionReject, nil, false, nil
+	}
+	return core.ActionConfirm, recalled, recall.Purge, nil
+}
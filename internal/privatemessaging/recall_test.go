@@ -0,0 +1,323 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRecall(pm *privateMessaging) *testReceipts {
+	tr := newTestReceipts(pm)
+	tr.msg.Header.SignerRef = core.SignerRef{Author: "did:firefly:org/org2", Key: "0x12345"}
+	tr.msg.Header.Topics = fftypes.FFStringArray{"topic1", "topic2"}
+	tr.batch.Node = tr.localNode.ID
+	return tr
+}
+
+func TestRecallMessageOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mdi.On("UpsertData", pm.ctx, mock.MatchedBy(func(d *core.Data) bool {
+		var recall core.MessageRecall
+		err := json.Unmarshal(d.Value.Bytes(), &recall)
+		return err == nil && recall.Message.Equals(tr.msg.Header.ID) && recall.Hash.Equals(tr.msg.Hash) && recall.Purge
+	}), database.UpsertOptimizationNew).Return(nil)
+	mdi.On("UpsertMessage", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	recallMsg, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{Purge: true})
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageTypePrivate, recallMsg.Header.Type)
+	assert.Equal(t, core.SystemTagRecallMessage, recallMsg.Header.Tag)
+	assert.Equal(t, tr.msg.Header.Group, recallMsg.Header.Group)
+	assert.Equal(t, tr.msg.Header.SignerRef, recallMsg.Header.SignerRef)
+	assert.Equal(t, tr.msg.Header.Topics, recallMsg.Header.Topics)
+	assert.Equal(t, tr.msg.Header.ID, recallMsg.Header.CID)
+	assert.Equal(t, core.TransactionTypeBatchPin, recallMsg.Header.TxType)
+	assert.Equal(t, core.MessageStateReady, recallMsg.State)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestRecallMessageBadID(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.RecallMessage(pm.ctx, "bad", &core.MessageRecallInput{})
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestRecallMessageAlreadyRecalled(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	tr.msg.State = core.MessageStateRecalled
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.Regexp(t, "FF10632", err)
+}
+
+func TestRecallMessageNotConfirmed(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	tr.msg.State = core.MessageStateSent
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.Regexp(t, "FF10633", err)
+}
+
+func TestRecallMessageNoBatch(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	tr.msg.BatchID = nil
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.Regexp(t, "FF10631", err)
+}
+
+func TestRecallMessageGetBatchFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRecallMessageBatchNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(nil, nil)
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestRecallMessageLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRecallMessageOtherNode(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	tr.batch.Node = tr.remoteNode.ID
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.Regexp(t, "FF10631", err)
+}
+
+func TestRecallMessageSealFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	tr.msg.Header.Topics = fftypes.FFStringArray{"!bad"}
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.Regexp(t, "FF00140", err)
+}
+
+func TestRecallMessageWriteFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mdi.On("UpsertData", pm.ctx, mock.Anything, database.UpsertOptimizationNew).Return(fmt.Errorf("pop"))
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	_, err := pm.RecallMessage(pm.ctx, tr.msg.Header.ID.String(), &core.MessageRecallInput{})
+	assert.EqualError(t, err, "pop")
+}
+
+func newTestRecallMessage(recalled *core.Message, recall *core.MessageRecall) (*core.Message, core.DataArray) {
+	b, _ := json.Marshal(recall)
+	return &core.Message{
+		Header: core.MessageHeader{
+			ID:        fftypes.NewUUID(),
+			Type:      core.MessageTypePrivate,
+			Tag:       core.SystemTagRecallMessage,
+			Group:     recalled.Header.Group,
+			SignerRef: recalled.Header.SignerRef,
+			CID:       recalled.Header.ID,
+		},
+	}, core.DataArray{
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtrBytes(b)},
+	}
+}
+
+func TestResolveRecallOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	msg, data := newTestRecallMessage(tr.msg, &core.MessageRecall{Message: tr.msg.Header.ID, Hash: tr.msg.Hash, Purge: true})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	action, recalled, purge, err := pm.ResolveRecall(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionConfirm, action)
+	assert.Equal(t, tr.msg, recalled)
+	assert.True(t, purge)
+}
+
+func TestResolveRecallBadData(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	msg, _ := newTestRecallMessage(tr.msg, &core.MessageRecall{})
+
+	action, recalled, _, err := pm.ResolveRecall(pm.ctx, msg, core.DataArray{{Value: fftypes.JSONAnyPtr("!json")}})
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+	assert.Nil(t, recalled)
+}
+
+func TestResolveRecallGetMessageFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	msg, data := newTestRecallMessage(tr.msg, &core.MessageRecall{Message: tr.msg.Header.ID, Hash: tr.msg.Hash})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(nil, fmt.Errorf("pop"))
+
+	action, _, _, err := pm.ResolveRecall(pm.ctx, msg, data)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, core.ActionRetry, action)
+}
+
+func TestResolveRecallMessageNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	msg, data := newTestRecallMessage(tr.msg, &core.MessageRecall{Message: tr.msg.Header.ID, Hash: tr.msg.Hash})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(nil, nil)
+
+	action, _, _, err := pm.ResolveRecall(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+}
+
+func TestResolveRecallOtherAuthor(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	msg, data := newTestRecallMessage(tr.msg, &core.MessageRecall{Message: tr.msg.Header.ID, Hash: tr.msg.Hash})
+	msg.Header.Author = "did:firefly:org/org1"
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	action, _, _, err := pm.ResolveRecall(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+}
+
+func TestResolveRecallHashMismatch(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	msg, data := newTestRecallMessage(tr.msg, &core.MessageRecall{Message: tr.msg.Header.ID, Hash: fftypes.NewRandB32()})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	action, _, _, err := pm.ResolveRecall(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+}
+
+func TestResolveRecallAlreadyRecalled(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestRecall(pm)
+	tr.msg.State = core.MessageStateRecalled
+	msg, data := newTestRecallMessage(tr.msg, &core.MessageRecall{Message: tr.msg.Header.ID, Hash: tr.msg.Hash})
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	action, _, _, err := pm.ResolveRecall(pm.ctx, msg, data)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ActionReject, action)
+}
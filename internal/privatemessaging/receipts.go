@@ -0,0 +1,226 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// SendDeliveryReceipts sends a delivery receipt back to the node that sent a batch of private messages, for each
+// member of the group on this node, once the messages have been confirmed. Only sent if delivery receipts are enabled.
+func (pm *privateMessaging) SendDeliveryReceipts(ctx context.Context, nodeID, txID *fftypes.UUID, msgs []*core.Message) error {
+	if !pm.deliveryReceipts || len(msgs) == 0 {
+		return nil
+	}
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return err
+	}
+	if nodeID.Equals(localNode.ID) {
+		return nil
+	}
+
+	receipts := make([]*core.MessageReceipt, 0, len(msgs))
+	for _, msg := range msgs {
+		group, _, err := pm.getGroupNodes(ctx, msg.Header.Group, false)
+		if err != nil {
+			return err
+		}
+		for _, member := range group.Members {
+			if member.Node.Equals(localNode.ID) {
+				receipts = append(receipts, pm.newReceipt(msg, core.MessageReceiptTypeDelivered, member))
+			}
+		}
+	}
+	return pm.sendReceipts(ctx, nodeID, txID, receipts)
+}
+
+// MarkMessageRead sends a read receipt for a private message received by this node, back to the node that sent it
+func (pm *privateMessaging) MarkMessageRead(ctx context.Context, id string, input *core.MessageReadInput) (*core.MessageReceipt, error) {
+	msg, err := pm.getPrivateMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if msg.State != core.MessageStateConfirmed {
+		return nil, i18n.NewError(ctx, coremsgs.MsgMessageNotConfirmed, msg.Header.ID, msg.State)
+	}
+	if msg.BatchID == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgReceiptOwnMessage, msg.Header.ID)
+	}
+	batch, err := pm.database.GetBatchByID(ctx, pm.namespace.Name, msg.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Node.Equals(localNode.ID) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgReceiptOwnMessage, msg.Header.ID)
+	}
+
+	identity := ""
+	if input.Identity != "" {
+		resolved, _, err := pm.identity.CachedIdentityLookupMustExist(ctx, input.Identity)
+		if err != nil {
+			return nil, err
+		}
+		identity = resolved.DID
+	}
+	group, _, err := pm.getGroupNodes(ctx, msg.Header.Group, false)
+	if err != nil {
+		return nil, err
+	}
+	var member *core.Member
+	for _, m := range group.Members {
+		if m.Node.Equals(localNode.ID) && (identity == "" || m.Identity == identity) {
+			member = m
+			break
+		}
+	}
+	if member == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgReceiptNotLocalMember, input.Identity, group.Hash)
+	}
+
+	receipt := pm.newReceipt(msg, core.MessageReceiptTypeRead, member)
+	if err := pm.sendReceipts(ctx, batch.Node, msg.TransactionID, []*core.MessageReceipt{receipt}); err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// GetMessageDeliveryStatus returns the receipts received for a private message, for each member of its group on another node
+func (pm *privateMessaging) GetMessageDeliveryStatus(ctx context.Context, id string) (*core.MessageDeliveryStatus, error) {
+	msg, err := pm.getPrivateMessage(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	group, _, err := pm.getGroupNodes(ctx, msg.Header.Group, false)
+	if err != nil {
+		return nil, err
+	}
+	localNode, err := pm.identity.GetLocalNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fb := database.MessageReceiptQueryFactory.NewFilter(ctx)
+	receipts, _, err := pm.database.GetMessageReceipts(ctx, pm.namespace.Name, fb.And(fb.Eq("message", msg.Header.ID)))
+	if err != nil {
+		return nil, err
+	}
+
+	status := &core.MessageDeliveryStatus{
+		Message:    msg.Header.ID,
+		Group:      group.Hash,
+		Recipients: make([]*core.MessageRecipientStatus, 0, len(group.Members)),
+	}
+	for _, member := range group.Members {
+		if member.Node.Equals(localNode.ID) {
+			continue
+		}
+		recipient := &core.MessageRecipientStatus{
+			Identity: member.Identity,
+			Node:     member.Node,
+		}
+		for _, r := range receipts {
+			if r.Identity == member.Identity && r.Node.Equals(member.Node) {
+				switch r.Type {
+				case core.MessageReceiptTypeDelivered:
+					recipient.Delivered = r.Created
+				case core.MessageReceiptTypeRead:
+					recipient.Read = r.Created
+				}
+			}
+		}
+		if recipient.Delivered != nil {
+			status.Delivered++
+		}
+		if recipient.Read != nil {
+			status.Read++
+		}
+		status.Recipients = append(status.Recipients, recipient)
+	}
+	return status, nil
+}
+
+func (pm *privateMessaging) getPrivateMessage(ctx context.Context, id string) (*core.Message, error) {
+	msgID, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := pm.database.GetMessageByID(ctx, pm.namespace.Name, msgID)
+	if err != nil {
+		return nil, err
+	}
+	if msg == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NotFound)
+	}
+	if msg.Header.Group == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNotPrivateMessage, msg.Header.ID)
+	}
+	return msg, nil
+}
+
+func (pm *privateMessaging) newReceipt(msg *core.Message, receiptType core.MessageReceiptType, member *core.Member) *core.MessageReceipt {
+	return &core.MessageReceipt{
+		ID:          fftypes.NewUUID(),
+		Namespace:   pm.namespace.NetworkName,
+		Message:     msg.Header.ID,
+		MessageHash: msg.Hash,
+		Type:        receiptType,
+		Identity:    member.Identity,
+		Node:        member.Node,
+		Created:     fftypes.Now(),
+	}
+}
+
+func (pm *privateMessaging) sendReceipts(ctx context.Context, nodeID, txID *fftypes.UUID, receipts []*core.MessageReceipt) error {
+	if len(receipts) == 0 {
+		return nil
+	}
+	node, err := pm.identity.CachedIdentityLookupByID(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+	if node == nil {
+		return i18n.NewError(ctx, coremsgs.MsgNodeNotFound, nodeID)
+	}
+
+	op := core.NewOperation(
+		pm.exchange,
+		pm.namespace.Name,
+		txID,
+		core.OpTypeDataExchangeSendReceipts)
+	addReceiptsSendInputs(op, node.ID, receipts)
+	if err = pm.operations.AddOrReuseOperation(ctx, op); err != nil {
+		return err
+	}
+
+	log.L(ctx).Debugf("Sending %d receipts to node=%s", len(receipts), node.ID)
+	_, err = pm.operations.RunOperation(ctx, opSendReceipts(op, node, receipts))
+	return err
+}
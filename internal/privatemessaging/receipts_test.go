@@ -0,0 +1,596 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privatemessaging
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/mocks/dataexchangemocks"
+	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/operationmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type testReceipts struct {
+	localNode  *core.Identity
+	remoteNode *core.Identity
+	group      *core.Group
+	msg        *core.Message
+	batch      *core.BatchPersisted
+}
+
+func newTestReceipts(pm *privateMessaging) *testReceipts {
+	tr := &testReceipts{
+		localNode: &core.Identity{
+			IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeNode},
+		},
+		remoteNode: &core.Identity{
+			IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), Type: core.IdentityTypeNode},
+		},
+	}
+	tr.group = &core.Group{
+		GroupIdentity: core.GroupIdentity{
+			Namespace: "ns1",
+			Members: core.Members{
+				{Identity: "did:firefly:org/org1", Node: tr.remoteNode.ID},
+				{Identity: "did:firefly:org/org2", Node: tr.localNode.ID},
+				{Identity: "did:firefly:org/org3", Node: tr.localNode.ID},
+			},
+		},
+	}
+	tr.group.Seal()
+	tr.msg = &core.Message{
+		Header: core.MessageHeader{
+			ID:    fftypes.NewUUID(),
+			Type:  core.MessageTypePrivate,
+			Group: tr.group.Hash,
+		},
+		Hash:          fftypes.NewRandB32(),
+		BatchID:       fftypes.NewUUID(),
+		TransactionID: fftypes.NewUUID(),
+		State:         core.MessageStateConfirmed,
+	}
+	tr.batch = &core.BatchPersisted{
+		BatchHeader: core.BatchHeader{ID: tr.msg.BatchID, Node: tr.remoteNode.ID},
+	}
+	pm.groupCache.Set(tr.group.Hash.String(), &groupHashEntry{group: tr.group})
+	return tr
+}
+
+func TestSendDeliveryReceiptsOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+	tr := newTestReceipts(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, tr.remoteNode.ID).Return(tr.remoteNode, nil)
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.MatchedBy(func(op *core.Operation) bool {
+		return op.Type == core.OpTypeDataExchangeSendReceipts && op.Transaction.Equals(tr.msg.TransactionID)
+	})).Return(nil)
+	mom.On("RunOperation", pm.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		receipts := op.Data.(receiptsSendData).Transport.Receipts
+		return len(receipts) == 2 &&
+			receipts[0].Identity == "did:firefly:org/org2" &&
+			receipts[1].Identity == "did:firefly:org/org3" &&
+			receipts[0].Type == core.MessageReceiptTypeDelivered &&
+			receipts[0].MessageHash.Equals(tr.msg.Hash)
+	})).Return(nil, nil)
+
+	err := pm.SendDeliveryReceipts(pm.ctx, tr.remoteNode.ID, tr.msg.TransactionID, []*core.Message{tr.msg})
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestSendDeliveryReceiptsDisabled(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	err := pm.SendDeliveryReceipts(pm.ctx, fftypes.NewUUID(), fftypes.NewUUID(), []*core.Message{{}})
+	assert.NoError(t, err)
+}
+
+func TestSendDeliveryReceiptsLocalNode(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+	tr := newTestReceipts(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	err := pm.SendDeliveryReceipts(pm.ctx, tr.localNode.ID, tr.msg.TransactionID, []*core.Message{tr.msg})
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendDeliveryReceiptsLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.SendDeliveryReceipts(pm.ctx, fftypes.NewUUID(), fftypes.NewUUID(), []*core.Message{{}})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendDeliveryReceiptsGroupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+	tr := newTestReceipts(pm)
+	tr.msg.Header.Group = fftypes.NewRandB32()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tr.msg.Header.Group).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.SendDeliveryReceipts(pm.ctx, tr.remoteNode.ID, tr.msg.TransactionID, []*core.Message{tr.msg})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdi.AssertExpectations(t)
+}
+
+func TestSendDeliveryReceiptsNoLocalMembers(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+	tr := newTestReceipts(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID()},
+	}, nil)
+
+	err := pm.SendDeliveryReceipts(pm.ctx, tr.remoteNode.ID, tr.msg.TransactionID, []*core.Message{tr.msg})
+	assert.NoError(t, err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendDeliveryReceiptsNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+	tr := newTestReceipts(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, tr.remoteNode.ID).Return(nil, fmt.Errorf("pop"))
+
+	err := pm.SendDeliveryReceipts(pm.ctx, tr.remoteNode.ID, tr.msg.TransactionID, []*core.Message{tr.msg})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendDeliveryReceiptsNodeNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+	tr := newTestReceipts(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, tr.remoteNode.ID).Return(nil, nil)
+
+	err := pm.SendDeliveryReceipts(pm.ctx, tr.remoteNode.ID, tr.msg.TransactionID, []*core.Message{tr.msg})
+	assert.Regexp(t, "FF10224", err)
+
+	mim.AssertExpectations(t)
+}
+
+func TestSendDeliveryReceiptsAddOpFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	pm.deliveryReceipts = true
+	tr := newTestReceipts(pm)
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, tr.remoteNode.ID).Return(tr.remoteNode, nil)
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	err := pm.SendDeliveryReceipts(pm.ctx, tr.remoteNode.ID, tr.msg.TransactionID, []*core.Message{tr.msg})
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMarkMessageReadOk(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org3").Return(&core.Identity{
+		IdentityBase: core.IdentityBase{DID: "did:firefly:org/org3"},
+	}, false, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, tr.remoteNode.ID).Return(tr.remoteNode, nil)
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.Anything).Return(nil)
+	mom.On("RunOperation", pm.ctx, mock.Anything).Return(nil, nil)
+
+	receipt, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{Identity: "org3"})
+	assert.NoError(t, err)
+	assert.Equal(t, core.MessageReceiptTypeRead, receipt.Type)
+	assert.Equal(t, "did:firefly:org/org3", receipt.Identity)
+	assert.Equal(t, tr.localNode.ID, receipt.Node)
+	assert.Equal(t, "ns1", receipt.Namespace)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
+func TestMarkMessageReadDefaultIdentity(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupByID", pm.ctx, tr.remoteNode.ID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestMarkMessageReadBadID(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.MarkMessageRead(pm.ctx, "bad", &core.MessageReadInput{})
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestMarkMessageReadGetMessageFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.MarkMessageRead(pm.ctx, fftypes.NewUUID().String(), &core.MessageReadInput{})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMarkMessageReadMessageNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", mock.Anything).Return(nil, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, fftypes.NewUUID().String(), &core.MessageReadInput{})
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMarkMessageReadNotPrivate(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+	tr.msg.Header.Group = nil
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.Regexp(t, "FF10625", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMarkMessageReadNotConfirmed(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+	tr.msg.State = core.MessageStatePending
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.Regexp(t, "FF10626", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMarkMessageReadNoBatch(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+	tr.msg.BatchID = nil
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.Regexp(t, "FF10628", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMarkMessageReadGetBatchFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMarkMessageReadBatchNotFound(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(nil, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.Regexp(t, "FF10109", err)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestMarkMessageReadLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestMarkMessageReadOwnMessage(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.remoteNode, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.Regexp(t, "FF10628", err)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestMarkMessageReadIdentityFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org3").Return(nil, false, fmt.Errorf("pop"))
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{Identity: "org3"})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestMarkMessageReadNotLocalMember(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org1").Return(&core.Identity{
+		IdentityBase: core.IdentityBase{DID: "did:firefly:org/org1"},
+	}, false, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{Identity: "org1"})
+	assert.Regexp(t, "FF10627", err)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestMarkMessageReadGroupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+	tr.msg.Header.Group = fftypes.NewRandB32()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetBatchByID", pm.ctx, "ns1", tr.msg.BatchID).Return(tr.batch, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tr.msg.Header.Group).Return(nil, fmt.Errorf("pop"))
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	_, err := pm.MarkMessageRead(pm.ctx, tr.msg.Header.ID.String(), &core.MessageReadInput{})
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestGetMessageDeliveryStatus(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+	tr.group.Members = append(tr.group.Members, &core.Member{Identity: "did:firefly:org/org4", Node: tr.remoteNode.ID})
+
+	delivered := fftypes.Now()
+	read := fftypes.Now()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetMessageReceipts", pm.ctx, "ns1", mock.Anything).Return([]*core.MessageReceipt{
+		{Identity: "did:firefly:org/org1", Node: tr.remoteNode.ID, Type: core.MessageReceiptTypeDelivered, Created: delivered},
+		{Identity: "did:firefly:org/org1", Node: tr.remoteNode.ID, Type: core.MessageReceiptTypeRead, Created: read},
+		{Identity: "did:firefly:org/org4", Node: fftypes.NewUUID(), Type: core.MessageReceiptTypeDelivered, Created: delivered},
+	}, nil, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	status, err := pm.GetMessageDeliveryStatus(pm.ctx, tr.msg.Header.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, tr.msg.Header.ID, status.Message)
+	assert.Equal(t, tr.group.Hash, status.Group)
+	assert.Equal(t, 1, status.Delivered)
+	assert.Equal(t, 1, status.Read)
+	assert.Len(t, status.Recipients, 2)
+	assert.Equal(t, delivered, status.Recipients[0].Delivered)
+	assert.Equal(t, read, status.Recipients[0].Read)
+	assert.Nil(t, status.Recipients[1].Delivered)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestGetMessageDeliveryStatusBadID(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	_, err := pm.GetMessageDeliveryStatus(pm.ctx, "bad")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetMessageDeliveryStatusGroupFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+	tr.msg.Header.Group = fftypes.NewRandB32()
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", tr.msg.Header.Group).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetMessageDeliveryStatus(pm.ctx, tr.msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+}
+
+func TestGetMessageDeliveryStatusLocalNodeFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(nil, fmt.Errorf("pop"))
+
+	_, err := pm.GetMessageDeliveryStatus(pm.ctx, tr.msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestGetMessageDeliveryStatusReceiptsFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+	tr := newTestReceipts(pm)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", pm.ctx, "ns1", tr.msg.Header.ID).Return(tr.msg, nil)
+	mdi.On("GetMessageReceipts", pm.ctx, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(tr.localNode, nil)
+
+	_, err := pm.GetMessageDeliveryStatus(pm.ctx, tr.msg.Header.ID.String())
+	assert.EqualError(t, err, "pop")
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+}
+
+func TestRunOperationReceiptsSendFail(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	node := &core.Identity{
+		IdentityProfile: core.IdentityProfile{Profile: fftypes.JSONObject{"id": "peer1"}},
+	}
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", context.Background()).Return(node, nil)
+	mdx := pm.exchange.(*dataexchangemocks.Plugin)
+	mdx.On("SendMessage", context.Background(), mock.Anything, node.Profile, node.Profile, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, _, err := pm.RunOperation(context.Background(), opSendReceipts(&core.Operation{}, node, []*core.MessageReceipt{}))
+	assert.EqualError(t, err, "pop")
+
+	mim.AssertExpectations(t)
+	mdx.AssertExpectations(t)
+}
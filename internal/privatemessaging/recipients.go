@@ -38,6 +38,9 @@ func (pm *privateMessaging) resolveRecipientList(ctx context.Context, in *core.M
 		if group == nil {
 			return i18n.NewError(ctx, coremsgs.MsgGroupNotFound, in.Header.Group)
 		}
+		if group.Superseded != nil {
+			return i18n.NewError(ctx, coremsgs.MsgGroupSuperseded, in.Header.Group)
+		}
 		// We have a group already resolved
 		return nil
 	}
@@ -173,6 +176,9 @@ func (pm *privateMessaging) findOrGenerateGroup(ctx context.Context, in *core.Me
 		return nil, false, err
 	}
 	if group != nil {
+		if group.Superseded != nil {
+			return nil, false, i18n.NewError(ctx, coremsgs.MsgGroupSuperseded, group.Hash)
+		}
 		return group, false, nil
 	}
 	return newCandidate, true, nil
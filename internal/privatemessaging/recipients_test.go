@@ -409,6 +409,59 @@ func TestResolveReceipientListExisting(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestResolveReceipientListSuperseded(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	groupID := fftypes.NewRandB32()
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{Hash: groupID, Superseded: fftypes.Now()}, nil)
+
+	err := pm.resolveRecipientList(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				Group: groupID,
+			},
+		},
+	})
+	assert.Regexp(t, "FF10622", err)
+}
+
+func TestResolveMemberListExistingGroupSuperseded(t *testing.T) {
+
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	localOrg := newTestOrg("org1")
+	localNode := newTestNode("node1", localOrg)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentities", pm.ctx, "ns1", mock.Anything).Return([]*core.Identity{localNode}, nil, nil)
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", mock.Anything, mock.Anything).Return(&core.Group{Hash: fftypes.NewRandB32(), Superseded: fftypes.Now()}, nil, nil).Once()
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("CachedIdentityLookupMustExist", pm.ctx, "org1").Return(localOrg, false, nil)
+	mim.On("GetRootOrg", pm.ctx).Return(localOrg, nil)
+	mim.On("GetLocalNode", pm.ctx).Return(localNode, nil)
+
+	err := pm.resolveRecipientList(pm.ctx, &core.MessageInOut{
+		Message: core.Message{
+			Header: core.MessageHeader{
+				SignerRef: core.SignerRef{
+					Author: "org1",
+				},
+				Namespace: "ns1",
+			},
+		},
+		Group: &core.InputGroup{
+			Members: []core.MemberInput{
+				{Identity: "org1"},
+			},
+		},
+	})
+	assert.Regexp(t, "FF10622", err)
+
+}
+
 func TestResolveReceipientListEmptyList(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
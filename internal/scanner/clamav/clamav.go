@@ -0,0 +1,144 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clamav
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/scanner"
+)
+
+const (
+	defaultTimeout = time.Minute
+	chunkSize      = 64 * 1024
+)
+
+// ClamAV scans blobs with a ClamAV daemon, streaming the content of each blob to clamd with the INSTREAM command
+type ClamAV struct{}
+
+type clamd struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+func (c *ClamAV) Name() string {
+	return "clamav"
+}
+
+func (c *ClamAV) NewScanner(ctx context.Context, options fftypes.JSONObject) (scanner.Scanner, error) {
+	cd := &clamd{
+		network: options.GetString("network"),
+		address: options.GetString("address"),
+		timeout: defaultTimeout,
+	}
+	if cd.network == "" {
+		cd.network = "tcp"
+	}
+	if cd.network != "tcp" && cd.network != "unix" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobScannerOptionsInvalid, c.Name(), "'network' must be 'tcp' or 'unix'")
+	}
+	if cd.address == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgBlobScannerOptionsInvalid, c.Name(), "'address' is required")
+	}
+	if timeout := options.GetString("timeout"); timeout != "" {
+		d, err := fftypes.ParseDurationString(timeout, time.Millisecond)
+		if err != nil || d <= 0 {
+			return nil, i18n.NewError(ctx, coremsgs.MsgBlobScannerOptionsInvalid, c.Name(), fmt.Sprintf("invalid timeout '%s'", timeout))
+		}
+		cd.timeout = time.Duration(d)
+	}
+	return cd, nil
+}
+
+func (cd *clamd) Scan(ctx context.Context, content io.Reader) (string, error) {
+	var dialer net.Dialer
+	dialCtx, cancel := context.WithTimeout(ctx, cd.timeout)
+	defer cancel()
+	conn, err := dialer.DialContext(dialCtx, cd.network, cd.address)
+	if err != nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgBlobScanFailed, "clamav", err)
+	}
+	defer conn.Close()
+
+	if err := cd.stream(ctx, conn, content); err != nil {
+		return "", err
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(cd.timeout))
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	if reply == "" {
+		return "", i18n.NewError(ctx, coremsgs.MsgBlobScanFailed, "clamav", err)
+	}
+	return cd.parseReply(ctx, reply)
+}
+
+// stream sends the content to clamd in chunks, each prefixed with its length, followed by a zero length chunk
+func (cd *clamd) stream(ctx context.Context, conn net.Conn, content io.Reader) error {
+	write := func(b []byte) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(cd.timeout))
+		if _, err := conn.Write(b); err != nil {
+			return i18n.NewError(ctx, coremsgs.MsgBlobScanFailed, "clamav", err)
+		}
+		return nil
+	}
+
+	if err := write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+	buf := make([]byte, 4+chunkSize)
+	for {
+		n, err := io.ReadFull(content, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if err := write(buf[:4+n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return i18n.WrapError(ctx, err, coremsgs.MsgBlobStreamingFailed)
+		}
+	}
+	return write([]byte{0, 0, 0, 0})
+}
+
+// parseReply interprets the reply of clamd, which is "stream: OK" for clean content, or "stream: <signature> FOUND"
+func (cd *clamd) parseReply(ctx context.Context, reply string) (string, error) {
+	result := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case result == "OK":
+		return "", nil
+	case strings.HasSuffix(result, " FOUND"):
+		return strings.TrimSuffix(result, " FOUND"), nil
+	default:
+		return "", i18n.NewError(ctx, coremsgs.MsgBlobScanFailed, "clamav", reply)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clamav
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+// newFakeClamd accepts a single INSTREAM scan, and replies with the result of the reply function for the content received
+func newFakeClamd(t *testing.T, network, address string, reply func(content []byte) string) net.Listener {
+	l, err := net.Listen(network, address)
+	assert.NoError(t, err)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		cmd := make([]byte, len("zINSTREAM\x00"))
+		if _, err := io.ReadFull(conn, cmd); err != nil || string(cmd) != "zINSTREAM\x00" {
+			return
+		}
+		var content bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&content, conn, int64(size)); err != nil {
+				return
+			}
+		}
+		_, _ = conn.Write([]byte(reply(content.Bytes()) + "\x00"))
+	}()
+	return l
+}
+
+func newTestScanner(t *testing.T, options fftypes.JSONObject) *clamd {
+	s, err := (&ClamAV{}).NewScanner(context.Background(), options)
+	assert.NoError(t, err)
+	return s.(*clamd)
+}
+
+func eicarReply(content []byte) string {
+	if bytes.Contains(content, []byte("EICAR")) {
+		return "stream: Eicar-Test-Signature FOUND"
+	}
+	return "stream: OK"
+}
+
+func TestScanClean(t *testing.T) {
+	l := newFakeClamd(t, "tcp", "127.0.0.1:0", eicarReply)
+	defer l.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": l.Addr().String(), "timeout": "5s"})
+	finding, err := cd.Scan(context.Background(), strings.NewReader(strings.Repeat("clean ", 20000)))
+	assert.NoError(t, err)
+	assert.Empty(t, finding)
+}
+
+func TestScanFound(t *testing.T) {
+	l := newFakeClamd(t, "tcp", "127.0.0.1:0", eicarReply)
+	defer l.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": l.Addr().String()})
+	finding, err := cd.Scan(context.Background(), strings.NewReader("X5O!P%@AP-EICAR-STANDARD-ANTIVIRUS-TEST-FILE"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Eicar-Test-Signature", finding)
+}
+
+func TestScanUnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "clamd.sock")
+	l := newFakeClamd(t, "unix", sock, eicarReply)
+	defer l.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"network": "unix", "address": sock})
+	finding, err := cd.Scan(context.Background(), strings.NewReader("clean"))
+	assert.NoError(t, err)
+	assert.Empty(t, finding)
+}
+
+func TestScanErrorReply(t *testing.T) {
+	l := newFakeClamd(t, "tcp", "127.0.0.1:0", func(content []byte) string {
+		return "INSTREAM size limit exceeded. ERROR"
+	})
+	defer l.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": l.Addr().String()})
+	_, err := cd.Scan(context.Background(), strings.NewReader("some data"))
+	assert.Regexp(t, "FF10682.*size limit exceeded", err)
+}
+
+func TestScanNoReply(t *testing.T) {
+	l := newFakeClamd(t, "tcp", "127.0.0.1:0", func(content []byte) string {
+		return ""
+	})
+	defer l.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": l.Addr().String()})
+	_, err := cd.Scan(context.Background(), strings.NewReader("some data"))
+	assert.Regexp(t, "FF10682", err)
+}
+
+func TestScanConnectFail(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	address := l.Addr().String()
+	l.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": address})
+	_, err = cd.Scan(context.Background(), strings.NewReader("some data"))
+	assert.Regexp(t, "FF10682", err)
+}
+
+func TestScanContentReadFail(t *testing.T) {
+	l := newFakeClamd(t, "tcp", "127.0.0.1:0", eicarReply)
+	defer l.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": l.Addr().String()})
+	_, err := cd.Scan(context.Background(), iotest.ErrReader(fmt.Errorf("pop")))
+	assert.Regexp(t, "FF10217.*pop", err)
+}
+
+func TestScanWriteFail(t *testing.T) {
+	client, server := net.Pipe()
+	server.Close()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": "unused"})
+	err := cd.stream(context.Background(), client, strings.NewReader("some data"))
+	assert.Regexp(t, "FF10682", err)
+}
+
+func TestScanChunkWriteFail(t *testing.T) {
+	client, server := net.Pipe()
+	go func() {
+		// Accept the command, then close before the first chunk
+		_, _ = server.Read(make([]byte, len("zINSTREAM\x00")))
+		server.Close()
+	}()
+
+	cd := newTestScanner(t, fftypes.JSONObject{"address": "unused"})
+	err := cd.stream(context.Background(), client, strings.NewReader("some data"))
+	assert.Regexp(t, "FF10682", err)
+}
+
+func TestNewScannerBadOptions(t *testing.T) {
+	for _, options := range []fftypes.JSONObject{
+		{},
+		{"address": "localhost:3310", "network": "udp"},
+		{"address": "localhost:3310", "timeout": "wrong"},
+		{"address": "localhost:3310", "timeout": "0"},
+	} {
+		_, err := (&ClamAV{}).NewScanner(context.Background(), options)
+		assert.Regexp(t, "FF10681.*clamav", err, options)
+	}
+}
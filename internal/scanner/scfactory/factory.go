@@ -0,0 +1,60 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scfactory
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/scanner/clamav"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/scanner"
+)
+
+var plugins = []scanner.Plugin{
+	&clamav.ClamAV{},
+}
+
+var pluginsByName = make(map[string]scanner.Plugin)
+
+func init() {
+	for _, p := range plugins {
+		pluginsByName[p.Name()] = p
+	}
+}
+
+func GetPlugin(ctx context.Context, pluginType string) (scanner.Plugin, error) {
+	plugin, ok := pluginsByName[pluginType]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnknownBlobScannerPlugin, pluginType)
+	}
+	return plugin, nil
+}
+
+// NewScanner creates the blob scanner configured for a namespace, validating its options, or returns nil if
+// the namespace does not have one
+func NewScanner(ctx context.Context, conf *core.BlobScannerConfig) (scanner.Scanner, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	plugin, err := GetPlugin(ctx, conf.Plugin)
+	if err != nil {
+		return nil, err
+	}
+	return plugin.NewScanner(ctx, conf.Options)
+}
@@ -0,0 +1,46 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scfactory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScanner(t *testing.T) {
+	s, err := NewScanner(context.Background(), &core.BlobScannerConfig{
+		Plugin:  "clamav",
+		Options: fftypes.JSONObject{"address": "localhost:3310"},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, s)
+}
+
+func TestNewScannerNone(t *testing.T) {
+	s, err := NewScanner(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestNewScannerUnknown(t *testing.T) {
+	_, err := NewScanner(context.Background(), &core.BlobScannerConfig{Plugin: "wrong"})
+	assert.Regexp(t, "FF10680.*wrong", err)
+}
@@ -43,8 +43,8 @@ type Bridge interface {
 	// The following "WaitFor*" methods all wait for a particular type of event callback, and block until it is received.
 	// To use them, invoke the appropriate method, and pass a "send" callback that is expected to trigger the relevant event.
 
-	// WaitForReply waits for a reply to the message with the supplied ID
-	WaitForReply(ctx context.Context, id *fftypes.UUID, send SendFunction) (*core.MessageInOut, error)
+	// WaitForReply waits for the replies to the message with the supplied ID, as described by the reply options (which can be nil)
+	WaitForReply(ctx context.Context, id *fftypes.UUID, opts *core.ReplyOptions, send SendFunction) (*core.MessageInOut, error)
 	// WaitForMessage waits for a message with the supplied ID
 	WaitForMessage(ctx context.Context, id *fftypes.UUID, send SendFunction) (*core.Message, error)
 	// WaitForIdentity waits for an identity with the supplied ID
@@ -88,12 +88,15 @@ type inflightRequest struct {
 	startTime time.Time
 	response  chan inflightResponse
 	reqType   requestType
+	reply     *core.ReplyOptions
+	done      chan struct{}
 }
 
 type inflightResponse struct {
-	id   *fftypes.UUID
-	data interface{}
-	err  error
+	id      *fftypes.UUID
+	data    interface{}
+	err     error
+	invalid error // a reply that does not meet the options of the request, which is not counted
 }
 
 type inflightRequestMap map[string]map[fftypes.UUID]*inflightRequest
@@ -125,12 +128,14 @@ func (sa *syncAsyncBridge) Init(sysevents system.EventInterface) {
 	sa.sysevents = sysevents
 }
 
-func (sa *syncAsyncBridge) addInFlight(ns string, id *fftypes.UUID, reqType requestType) (*inflightRequest, error) {
+func (sa *syncAsyncBridge) addInFlight(ns string, id *fftypes.UUID, reqType requestType, reply *core.ReplyOptions) (*inflightRequest, error) {
 	inflight := &inflightRequest{
 		id:        id,
 		startTime: time.Now(),
 		response:  make(chan inflightResponse),
 		reqType:   reqType,
+		reply:     reply,
+		done:      make(chan struct{}),
 	}
 	sa.inflightMux.Lock()
 	defer func() {
@@ -316,6 +321,18 @@ func (sa *syncAsyncBridge) handleMessageRejectedEvent(event *core.EventDelivery)
 	return nil
 }
 
+func (sa *syncAsyncBridge) handleMessageExpiredEvent(event *core.EventDelivery) error {
+
+	// See if this is the expiry of an inflight message, or of a request that is waiting for a reply
+	if inflight := sa.getInFlight(event.Namespace, messageConfirm, event.Reference); inflight != nil {
+		go sa.resolveExpired(inflight, event.Reference)
+	}
+	if inflightReply := sa.getInFlight(event.Namespace, messageReply, event.Reference); inflightReply != nil {
+		go sa.resolveExpired(inflightReply, event.Reference)
+	}
+	return nil
+}
+
 func (sa *syncAsyncBridge) handleIdentityConfirmedEvent(event *core.EventDelivery) error {
 	// See if the CID marks this as a reply to an inflight identity
 	inflightReply := sa.getInFlight(event.Namespace, identityConfirm, event.Reference)
@@ -521,6 +538,9 @@ func (sa *syncAsyncBridge) eventCallback(event *core.EventDelivery) error {
 	case core.EventTypeMessageRejected:
 		return sa.handleMessageRejectedEvent(event)
 
+	case core.EventTypeMessageExpired:
+		return sa.handleMessageExpiredEvent(event)
+
 	case core.EventTypeIdentityConfirmed:
 		return sa.handleIdentityConfirmedEvent(event)
 
@@ -568,7 +588,21 @@ func (sa *syncAsyncBridge) resolveReply(inflight *inflightRequest, msg *core.Mes
 		return
 	}
 	response.SetInlineData(data)
-	inflight.response <- inflightResponse{id: msg.Header.ID, data: response}
+	result := inflightResponse{id: msg.Header.ID, data: response}
+	if !inflight.reply.MatchesDatatype(data) {
+		result.invalid = i18n.NewError(sa.ctx, coremsgs.MsgReplyDatatypeMismatch, msg.Header.ID, inflight.reply.Datatype)
+	} else if inflight.reply != nil && inflight.reply.Datatype != nil {
+		if _, err := sa.data.ValidateAll(sa.ctx, data); err != nil {
+			result.invalid = err
+		}
+	}
+
+	// There can be more replies than the request needs, so replies that arrive after it completes are dropped
+	select {
+	case inflight.response <- result:
+	case <-inflight.done:
+		log.L(sa.ctx).Debugf("Dropping reply '%s' to completed request '%s'", msg.Header.ID, inflight.id)
+	}
 }
 
 func (sa *syncAsyncBridge) resolveConfirmed(inflight *inflightRequest, msg *core.Message) {
@@ -582,6 +616,12 @@ func (sa *syncAsyncBridge) resolveRejected(inflight *inflightRequest, msgID *fft
 	inflight.response <- inflightResponse{err: err}
 }
 
+func (sa *syncAsyncBridge) resolveExpired(inflight *inflightRequest, msgID *fftypes.UUID) {
+	err := i18n.NewError(sa.ctx, coremsgs.MsgExpired, msgID)
+	log.L(sa.ctx).Errorf("Resolving message request '%s' with error: %s", inflight.id, err)
+	inflight.response <- inflightResponse{err: err}
+}
+
 func (sa *syncAsyncBridge) resolveIdentity(inflight *inflightRequest, identity *core.Identity) {
 	log.L(sa.ctx).Debugf("Resolving identity creation '%s' with ID '%s'", inflight.id, identity.ID)
 	inflight.response <- inflightResponse{id: identity.ID, data: identity}
@@ -619,7 +659,11 @@ func (sa *syncAsyncBridge) resolveFailedOperation(inflight *inflightRequest, typ
 }
 
 func (sa *syncAsyncBridge) sendAndWait(ctx context.Context, ns string, id *fftypes.UUID, reqType requestType, send SendFunction) (interface{}, error) {
-	inflight, err := sa.addInFlight(ns, id, reqType)
+	return sa.sendAndCollect(ctx, ns, id, reqType, nil, send)
+}
+
+func (sa *syncAsyncBridge) sendAndCollect(ctx context.Context, ns string, id *fftypes.UUID, reqType requestType, reply *core.ReplyOptions, send SendFunction) (interface{}, error) {
+	inflight, err := sa.addInFlight(ns, id, reqType, reply)
 	if err != nil {
 		return nil, err
 	}
@@ -627,6 +671,7 @@ func (sa *syncAsyncBridge) sendAndWait(ctx context.Context, ns string, id *fftyp
 	var replyID *fftypes.UUID
 	defer func() {
 		sa.removeInFlight(ns, inflight.id)
+		close(inflight.done)
 		if replyID != nil {
 			log.L(sa.ctx).Infof("Inflight request '%s' resolved with reply '%s' after %.2fms", inflight.id, replyID, inflight.msInflight())
 		} else {
@@ -639,6 +684,14 @@ func (sa *syncAsyncBridge) sendAndWait(ctx context.Context, ns string, id *fftyp
 		return nil, err
 	}
 
+	if reqType == messageReply {
+		response, err := sa.collectReplies(ctx, inflight)
+		if response != nil {
+			replyID = response.Header.ID
+		}
+		return response, err
+	}
+
 	select {
 	case <-ctx.Done():
 		return nil, i18n.NewError(ctx, coremsgs.MsgRequestTimeout, inflight.id, inflight.msInflight())
@@ -648,8 +701,94 @@ func (sa *syncAsyncBridge) sendAndWait(ctx context.Context, ns string, id *fftyp
 	}
 }
 
-func (sa *syncAsyncBridge) WaitForReply(ctx context.Context, id *fftypes.UUID, send SendFunction) (*core.MessageInOut, error) {
-	reply, err := sa.sendAndWait(ctx, sa.namespace, id, messageReply, send)
+// collectReplies waits for the number of valid replies the request needs, from different authors.
+// The first of them is returned, with all of the replies included when more than one is needed.
+func (sa *syncAsyncBridge) collectReplies(ctx context.Context, inflight *inflightRequest) (*core.MessageInOut, error) {
+	required := inflight.reply.Required()
+	var first *core.MessageInOut
+	replies := make([]*core.ReplyMessage, 0, required)
+	authors := make(map[string]bool)
+	invalid := 0
+	for {
+		select {
+		case <-ctx.Done():
+			if len(replies) == 0 && invalid == 0 {
+				return nil, i18n.NewError(ctx, coremsgs.MsgRequestTimeout, inflight.id, inflight.msInflight())
+			}
+			return nil, i18n.NewError(ctx, coremsgs.MsgRequestReplyIncomplete, inflight.id, inflight.msInflight(), len(replies), required, invalid)
+		case response := <-inflight.response:
+			if response.err != nil {
+				return nil, response.err
+			}
+			if response.invalid != nil {
+				log.L(sa.ctx).Warnf("Ignoring reply '%s' to request '%s': %s", response.id, inflight.id, response.invalid)
+				invalid++
+				continue
+			}
+			reply := response.data.(*core.MessageInOut)
+			if authors[reply.Header.Author] {
+				log.L(sa.ctx).Debugf("Ignoring reply '%s' to request '%s' from '%s', which has already replied", response.id, inflight.id, reply.Header.Author)
+				continue
+			}
+			authors[reply.Header.Author] = true
+			replies = append(replies, &core.ReplyMessage{Message: reply.Message, InlineData: reply.InlineData})
+			if first == nil {
+				first = reply
+			}
+			if len(replies) >= required {
+				if required > 1 {
+					first.Replies = replies
+				}
+				return first, nil
+			}
+		}
+	}
+}
+
+func (sa *syncAsyncBridge) checkReplyOptions(ctx context.Context, opts *core.ReplyOptions) error {
+	if opts == nil {
+		return nil
+	}
+	if opts.Mode == "" {
+		opts.Mode = core.ReplyModeFirst
+	} else {
+		mode, err := fftypes.FFEnumParseString(ctx, "replymode", opts.Mode.String())
+		if err != nil {
+			return err
+		}
+		opts.Mode = mode
+	}
+	if opts.Quorum < 0 || (opts.Mode == core.ReplyModeFirst && opts.Quorum > 1) || (opts.Mode == core.ReplyModeQuorum && opts.Quorum < 1) {
+		return i18n.NewError(ctx, coremsgs.MsgReplyQuorumInvalid, opts.Quorum, opts.Mode)
+	}
+	if opts.Timeout != nil && *opts.Timeout <= 0 {
+		return i18n.NewError(ctx, coremsgs.MsgInvalidReplyTimeout, opts.Timeout)
+	}
+	if opts.Datatype != nil {
+		if opts.Datatype.Name == "" || opts.Datatype.Version == "" {
+			return i18n.NewError(ctx, coremsgs.MsgReplyDatatypeRequired)
+		}
+		dt, err := sa.database.GetDatatypeByName(ctx, sa.namespace, opts.Datatype.Name, opts.Datatype.Version)
+		if err != nil {
+			return err
+		}
+		if dt == nil {
+			return i18n.NewError(ctx, coremsgs.MsgDatatypeNotFound, opts.Datatype)
+		}
+	}
+	return nil
+}
+
+func (sa *syncAsyncBridge) WaitForReply(ctx context.Context, id *fftypes.UUID, opts *core.ReplyOptions, send SendFunction) (*core.MessageInOut, error) {
+	if err := sa.checkReplyOptions(ctx, opts); err != nil {
+		return nil, err
+	}
+	if opts != nil && opts.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*opts.Timeout))
+		defer cancel()
+	}
+	reply, err := sa.sendAndCollect(ctx, sa.namespace, id, messageReply, opts, send)
 	if err != nil {
 		return nil, err
 	}
@@ -21,8 +21,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
@@ -76,7 +78,7 @@ func TestRequestReplyOk(t *testing.T) {
 		{ID: dataID, Value: fftypes.JSONAnyPtr(`"response data"`)},
 	}, true, nil)
 
-	reply, err := sa.WaitForReply(sa.ctx, requestID, func(ctx context.Context) error {
+	reply, err := sa.WaitForReply(sa.ctx, requestID, nil, func(ctx context.Context) error {
 		go func() {
 			sa.eventCallback(&core.EventDelivery{
 				EnrichedEvent: core.EnrichedEvent{
@@ -192,6 +194,62 @@ func TestAwaitConfirmationRejected(t *testing.T) {
 	assert.Regexp(t, "FF10269", err)
 }
 
+func TestAwaitConfirmationExpired(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	defer cancel()
+
+	requestID := fftypes.NewUUID()
+
+	mse := sa.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+
+	_, err := sa.WaitForMessage(sa.ctx, requestID, func(ctx context.Context) error {
+		go func() {
+			sa.eventCallback(&core.EventDelivery{
+				EnrichedEvent: core.EnrichedEvent{
+					Event: core.Event{
+						ID:        fftypes.NewUUID(),
+						Type:      core.EventTypeMessageExpired,
+						Reference: requestID,
+						Namespace: "ns1",
+					},
+				},
+			})
+		}()
+		return nil
+	})
+	assert.Regexp(t, "FF10620", err)
+}
+
+func TestRequestReplyExpired(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	defer cancel()
+
+	requestID := fftypes.NewUUID()
+
+	mse := sa.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+
+	_, err := sa.WaitForReply(sa.ctx, requestID, nil, func(ctx context.Context) error {
+		go func() {
+			sa.eventCallback(&core.EventDelivery{
+				EnrichedEvent: core.EnrichedEvent{
+					Event: core.Event{
+						ID:        fftypes.NewUUID(),
+						Type:      core.EventTypeMessageExpired,
+						Reference: requestID,
+						Namespace: "ns1",
+					},
+				},
+			})
+		}()
+		return nil
+	})
+	assert.Regexp(t, "FF10620", err)
+}
+
 func TestRequestReplyTimeout(t *testing.T) {
 
 	sa, cancel := newTestSyncAsyncBridge(t)
@@ -200,7 +258,21 @@ func TestRequestReplyTimeout(t *testing.T) {
 	mse := sa.sysevents.(*systemeventmocks.EventInterface)
 	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
 
-	_, err := sa.WaitForReply(sa.ctx, fftypes.NewUUID(), func(ctx context.Context) error {
+	_, err := sa.WaitForReply(sa.ctx, fftypes.NewUUID(), nil, func(ctx context.Context) error {
+		return nil
+	})
+	assert.Regexp(t, "FF10260", err)
+}
+
+func TestAwaitConfirmationTimeout(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	cancel()
+
+	mse := sa.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+
+	_, err := sa.WaitForMessage(sa.ctx, fftypes.NewUUID(), func(ctx context.Context) error {
 		return nil
 	})
 	assert.Regexp(t, "FF10260", err)
@@ -214,7 +286,7 @@ func TestRequestSetupSystemListenerFail(t *testing.T) {
 	mse := sa.sysevents.(*systemeventmocks.EventInterface)
 	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(fmt.Errorf("pop"))
 
-	_, err := sa.WaitForReply(sa.ctx, fftypes.NewUUID(), func(ctx context.Context) error {
+	_, err := sa.WaitForReply(sa.ctx, fftypes.NewUUID(), nil, func(ctx context.Context) error {
 		return nil
 	})
 	assert.Regexp(t, "pop", err)
@@ -241,11 +313,12 @@ func TestEventCallbackNotInflight(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	sa.addInFlight("ns1", fftypes.NewUUID(), messageConfirm)
+	sa.addInFlight("ns1", fftypes.NewUUID(), messageConfirm, nil)
 
 	for _, eventType := range []core.EventType{
 		core.EventTypeMessageConfirmed,
 		core.EventTypeMessageRejected,
+		core.EventTypeMessageExpired,
 		core.EventTypePoolConfirmed,
 		core.EventTypeTransferConfirmed,
 		core.EventTypeApprovalConfirmed,
@@ -1700,3 +1773,203 @@ func TestAwaitInvokeOpFailedLookupFail(t *testing.T) {
 	})
 	assert.EqualError(t, err, "pop")
 }
+
+type testReply struct {
+	msg  *core.Message
+	data core.DataArray
+}
+
+func newTestReply(requestID *fftypes.UUID, author string, datatype *core.DatatypeRef) *testReply {
+	r := &testReply{
+		msg: &core.Message{
+			Header: core.MessageHeader{
+				ID:        fftypes.NewUUID(),
+				CID:       requestID,
+				SignerRef: core.SignerRef{Author: author},
+			},
+		},
+		data: core.DataArray{
+			{ID: fftypes.NewUUID(), Validator: core.ValidatorTypeJSON, Datatype: datatype, Value: fftypes.JSONAnyPtr(`{"price":10}`)},
+		},
+	}
+	r.msg.Data = core.DataRefs{{ID: r.data[0].ID}}
+	return r
+}
+
+func mockReplies(sa *syncAsyncBridge, replies ...*testReply) {
+	byID := make(map[fftypes.UUID]*testReply)
+	for _, r := range replies {
+		byID[*r.msg.Header.ID] = r
+	}
+	mdi := sa.database.(*databasemocks.Plugin)
+	mdi.On("GetMessageByID", sa.ctx, "ns1", mock.Anything).Return(func(ctx context.Context, ns string, id *fftypes.UUID) *core.Message {
+		return byID[*id].msg
+	}, nil)
+	mdm := sa.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", sa.ctx, mock.Anything).Return(func(ctx context.Context, msg *core.Message, options ...data.CacheReadOption) core.DataArray {
+		return byID[*msg.Header.ID].data
+	}, true, nil)
+}
+
+func sendReplies(sa *syncAsyncBridge, replies ...*testReply) {
+	for _, r := range replies {
+		sa.eventCallback(&core.EventDelivery{
+			EnrichedEvent: core.EnrichedEvent{
+				Event: core.Event{
+					ID:         fftypes.NewUUID(),
+					Type:       core.EventTypeMessageConfirmed,
+					Reference:  r.msg.Header.ID,
+					Correlator: r.msg.Header.CID,
+					Namespace:  "ns1",
+				},
+			},
+		})
+	}
+}
+
+func TestRequestReplyQuorumOk(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	defer cancel()
+
+	requestID := fftypes.NewUUID()
+	dtRef := &core.DatatypeRef{Name: "quote", Version: "1.0"}
+	reply1 := newTestReply(requestID, "did:firefly:org/org1", dtRef)
+	reply1Again := newTestReply(requestID, "did:firefly:org/org1", dtRef)
+	replyUntyped := newTestReply(requestID, "did:firefly:org/org2", nil)
+	reply2 := newTestReply(requestID, "did:firefly:org/org3", dtRef)
+	reply3 := newTestReply(requestID, "did:firefly:org/org4", dtRef)
+
+	mse := sa.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+
+	mdi := sa.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", sa.ctx, "ns1", "quote", "1.0").Return(&core.Datatype{}, nil)
+	mockReplies(sa, reply1, reply1Again, replyUntyped, reply2, reply3)
+	mdm := sa.data.(*datamocks.Manager)
+	mdm.On("ValidateAll", sa.ctx, mock.Anything).Return(true, nil)
+
+	reply, err := sa.WaitForReply(sa.ctx, requestID, &core.ReplyOptions{
+		Datatype: dtRef,
+		Mode:     "Quorum",
+		Quorum:   2,
+	}, func(ctx context.Context) error {
+		go func() {
+			// Events are delivered in order, and each reply is resolved before the next
+			for _, r := range []*testReply{reply1, reply1Again, replyUntyped, reply2, reply3} {
+				sendReplies(sa, r)
+				time.Sleep(10 * time.Millisecond)
+			}
+		}()
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, *reply1.msg.Header.ID, *reply.Header.ID)
+	assert.Len(t, reply.Replies, 2)
+	assert.Equal(t, *reply1.msg.Header.ID, *reply.Replies[0].Header.ID)
+	assert.Equal(t, *reply2.msg.Header.ID, *reply.Replies[1].Header.ID)
+	assert.Equal(t, `{"price":10}`, reply.Replies[1].InlineData[0].Value.String())
+
+}
+
+func TestRequestReplyInvalidReplyTimeout(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	defer cancel()
+
+	requestID := fftypes.NewUUID()
+	dtRef := &core.DatatypeRef{Name: "quote", Version: "1.0"}
+	reply1 := newTestReply(requestID, "did:firefly:org/org1", dtRef)
+	reply2 := newTestReply(requestID, "did:firefly:org/org2", dtRef)
+
+	mse := sa.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+
+	mdi := sa.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", mock.Anything, "ns1", "quote", "1.0").Return(&core.Datatype{}, nil)
+	mockReplies(sa, reply1, reply2)
+	mdm := sa.data.(*datamocks.Manager)
+	mdm.On("ValidateAll", sa.ctx, reply1.data).Return(false, fmt.Errorf("pop"))
+	mdm.On("ValidateAll", sa.ctx, reply2.data).Return(true, nil)
+
+	timeout := fftypes.FFDuration(100 * time.Millisecond)
+	_, err := sa.WaitForReply(sa.ctx, requestID, &core.ReplyOptions{
+		Datatype: dtRef,
+		Mode:     core.ReplyModeQuorum,
+		Quorum:   2,
+		Timeout:  &timeout,
+	}, func(ctx context.Context) error {
+		go sendReplies(sa, reply1, reply2)
+		return nil
+	})
+	assert.Regexp(t, "FF10659.*1 of 2.*1 replies", err)
+
+}
+
+func TestRequestReplyTimeoutOverride(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	defer cancel()
+
+	mse := sa.sysevents.(*systemeventmocks.EventInterface)
+	mse.On("AddSystemEventListener", "ns1", mock.Anything).Return(nil)
+
+	timeout := fftypes.FFDuration(1 * time.Millisecond)
+	_, err := sa.WaitForReply(sa.ctx, fftypes.NewUUID(), &core.ReplyOptions{
+		Timeout: &timeout,
+	}, func(ctx context.Context) error {
+		return nil
+	})
+	assert.Regexp(t, "FF10260", err)
+}
+
+func TestRequestReplyDropLateReply(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	defer cancel()
+
+	requestID := fftypes.NewUUID()
+	reply := newTestReply(requestID, "did:firefly:org/org1", nil)
+
+	mdm := sa.data.(*datamocks.Manager)
+	mdm.On("GetMessageDataCached", sa.ctx, reply.msg).Return(reply.data, true, nil)
+
+	inflight := &inflightRequest{
+		id:       requestID,
+		response: make(chan inflightResponse),
+		reqType:  messageReply,
+		done:     make(chan struct{}),
+	}
+	close(inflight.done)
+	sa.resolveReply(inflight, reply.msg)
+}
+
+func TestRequestReplyOptionsInvalid(t *testing.T) {
+
+	sa, cancel := newTestSyncAsyncBridge(t)
+	defer cancel()
+
+	mdi := sa.database.(*databasemocks.Plugin)
+	mdi.On("GetDatatypeByName", sa.ctx, "ns1", "missing", "1.0").Return(nil, nil)
+	mdi.On("GetDatatypeByName", sa.ctx, "ns1", "fail", "1.0").Return(nil, fmt.Errorf("pop"))
+
+	zero := fftypes.FFDuration(0)
+	for _, tc := range []struct {
+		opts *core.ReplyOptions
+		err  string
+	}{
+		{&core.ReplyOptions{Mode: "wrong"}, "FF00172"},
+		{&core.ReplyOptions{Quorum: 2}, "FF10656"},
+		{&core.ReplyOptions{Mode: core.ReplyModeQuorum}, "FF10656"},
+		{&core.ReplyOptions{Mode: core.ReplyModeQuorum, Quorum: -1}, "FF10656"},
+		{&core.ReplyOptions{Timeout: &zero}, "FF10658"},
+		{&core.ReplyOptions{Datatype: &core.DatatypeRef{Name: "quote"}}, "FF10657"},
+		{&core.ReplyOptions{Datatype: &core.DatatypeRef{Name: "missing", Version: "1.0"}}, "FF10195"},
+		{&core.ReplyOptions{Datatype: &core.DatatypeRef{Name: "fail", Version: "1.0"}}, "pop"},
+	} {
+		_, err := sa.WaitForReply(sa.ctx, fftypes.NewUUID(), tc.opts, func(ctx context.Context) error {
+			panic("should not be called")
+		})
+		assert.Regexp(t, tc.err, err)
+	}
+}
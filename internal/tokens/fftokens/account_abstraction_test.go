@@ -0,0 +1,134 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftokens
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/accountabstraction"
+	"github.com/hyperledger/firefly/mocks/coremocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testSmartAccount = "0x2a7c9d5248681ce6c393117e641ad037f5c079f6"
+	testPaymaster    = "0xd8da6bf26964af9d7eed9e03e53415d37aa96045"
+)
+
+func TestNewAccountAbstraction(t *testing.T) {
+	h, _, _, _, done := newTestFFTokens(t)
+	defer done()
+	assert.Nil(t, h.accountAbstraction)
+
+	aaConf := ffTokensConfig.SubSection(FFTAccountAbstractionConfigKey)
+	aaConf.Set(accountabstraction.ConfigSmartAccounts, []string{"0x2A7C9D5248681CE6C393117E641AD037F5C079F6"})
+	aaConf.Set(accountabstraction.ConfigPaymaster, testPaymaster)
+	err := h.Init(h.ctx, h.cancelCtx, "testtokens", ffTokensConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, &accountabstraction.UserOperation{
+		EntryPoint: "0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789",
+		Paymaster:  testPaymaster,
+	}, h.accountAbstraction.UserOperationFor(testSmartAccount))
+}
+
+func TestNewAccountAbstractionBadSmartAccount(t *testing.T) {
+	h, _, _, _, done := newTestFFTokens(t)
+	defer done()
+
+	ffTokensConfig.SubSection(FFTAccountAbstractionConfigKey).Set(accountabstraction.ConfigSmartAccounts, []string{"bad"})
+	err := h.Init(h.ctx, h.cancelCtx, "testtokens", ffTokensConfig)
+	assert.Regexp(t, "FF10141", err)
+}
+
+func TestTransferTokensSmartAccount(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+	aaConf := ffTokensConfig.SubSection(FFTAccountAbstractionConfigKey)
+	aaConf.Set(accountabstraction.ConfigSmartAccounts, []string{testSmartAccount})
+	aaConf.Set(accountabstraction.ConfigPaymaster, testPaymaster)
+	var err error
+	h.accountAbstraction, err = accountabstraction.New(context.Background(), aaConf)
+	assert.NoError(t, err)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body fftypes.JSONObject
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			switch body["signer"] {
+			case testSmartAccount:
+				assert.Equal(t, map[string]interface{}{
+					"entryPoint": "0x5ff137d4b0fdcd49dca30c7cf57e578a026d2789",
+					"paymaster":  testPaymaster,
+				}, body["userOperation"])
+			default:
+				assert.NotContains(t, body, "userOperation")
+			}
+			return httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"})(req)
+		})
+
+	for _, key := range []string{testSmartAccount, "0x123"} {
+		transfer := &core.TokenTransfer{
+			From:   "user1",
+			To:     "user2",
+			Key:    key,
+			Amount: *fftypes.NewFFBigInt(10),
+			TX: core.TransactionRef{
+				ID:   fftypes.NewUUID(),
+				Type: core.TransactionTypeTokenTransfer,
+			},
+		}
+		err := h.TransferTokens(context.Background(), "ns1:"+fftypes.NewUUID().String(), "123", transfer, nil)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestReceiptUserOperation(t *testing.T) {
+	h, _, _, _, done := newTestFFTokens(t)
+	defer done()
+	mcb := &coremocks.OperationCallbacks{}
+	h.SetOperationHandler("ns1", mcb)
+	opID := fftypes.NewUUID()
+
+	mcb.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == "ns1:"+opID.String() &&
+			update.Status == core.OpStatusSucceeded &&
+			update.Output.GetString("userOpHash") == "0x9a3d5b4e3e1c1ff4c3aa8d542c4eb7b2ee7a0fce91de2d6c530bf78b3a9cd8e1" &&
+			update.Output.GetString("sponsorship") == "sponsored"
+	})).Return(nil)
+
+	h.handleReceipt(context.Background(), fftypes.JSONObject{
+		"headers": fftypes.JSONObject{
+			"requestId": "ns1:" + opID.String(),
+			"type":      "TransactionSuccess",
+		},
+		"transactionHash": "0x71a38acb7a5d4a970854f6d638ceb1fa10a4b59cbf4ed7674273a1a8dc8b36b8",
+		"userOpHash":      "0x9a3d5b4e3e1c1ff4c3aa8d542c4eb7b2ee7a0fce91de2d6c530bf78b3a9cd8e1",
+		"sponsorship":     "sponsored",
+	})
+
+	mcb.AssertExpectations(t)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/accountabstraction"
 )
 
 const (
@@ -32,12 +33,21 @@ const (
 	FFTBackgroundStartMaxDelay     = "backgroundStart.maxDelay"
 	FFTBackgroundStartFactor       = "backgroundStart.factor"
 
+	FFTAccountAbstractionConfigKey = "accountAbstraction"
+
+	HTSAutoAssociate = "autoAssociate"
+
 	defaultBackgroundInitialDelay = "5s"
 	defaultBackgroundRetryFactor  = 2.0
 	defaultBackgroundMaxDelay     = "1m"
 )
 
 func (ft *FFTokens) InitConfig(config config.Section) {
+	initConnectorConfig(config)
+	accountabstraction.InitConfig(config.SubSection(FFTAccountAbstractionConfigKey))
+}
+
+func initConnectorConfig(config config.Section) {
 	wsclient.InitConfig(config)
 
 	config.AddKnownKey(FFTEventRetryInitialDelay, 50*time.Millisecond)
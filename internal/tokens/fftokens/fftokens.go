@@ -33,6 +33,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
 	"github.com/hyperledger/firefly-signer/pkg/abi"
 	"github.com/hyperledger/firefly-signer/pkg/ffi2abi"
+	"github.com/hyperledger/firefly/internal/accountabstraction"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -50,6 +51,8 @@ type FFTokens struct {
 	retry           *retry.Retry
 	backgroundRetry *retry.Retry
 	backgroundStart bool
+
+	accountAbstraction *accountabstraction.AccountAbstraction
 }
 
 type callbacks struct {
@@ -59,7 +62,7 @@ type callbacks struct {
 	opHandlers map[string]core.OperationCallbacks
 }
 
-func (cb *callbacks) OperationUpdate(ctx context.Context, nsOpID string, status core.OpStatus, blockchainTXID, errorMessage string, opOutput fftypes.JSONObject) {
+func (cb *callbacks) OperationUpdate(ctx context.Context, nsOpID string, status core.OpStatus, blockchainTXID, errorMessage string, failureReason core.OpFailureReason, opOutput fftypes.JSONObject) {
 	namespace, _, _ := core.ParseNamespacedOpID(ctx, nsOpID)
 	if handler, ok := cb.opHandlers[namespace]; ok {
 		handler.OperationUpdate(&core.OperationUpdate{
@@ -68,6 +71,7 @@ func (cb *callbacks) OperationUpdate(ctx context.Context, nsOpID string, status
 			Status:         status,
 			BlockchainTXID: blockchainTXID,
 			ErrorMessage:   errorMessage,
+			FailureReason:  failureReason,
 			Output:         opOutput,
 		})
 	} else {
@@ -109,6 +113,23 @@ func (cb *callbacks) TokensTransferred(ctx context.Context, namespace string, tr
 	return nil
 }
 
+func (cb *callbacks) TokenEventRemoved(ctx context.Context, namespace string, event *blockchain.Event) error {
+	if namespace == "" {
+		// Older token subscriptions don't populate namespace, so deliver the event to every handler
+		for _, handler := range cb.handlers {
+			if err := handler.TokenEventRemoved(cb.plugin, event); err != nil {
+				return err
+			}
+		}
+	} else {
+		if handler, ok := cb.handlers[namespace]; ok {
+			return handler.TokenEventRemoved(cb.plugin, event)
+		}
+		log.L(ctx).Errorf("No handler found for removed token event on namespace '%s'", namespace)
+	}
+	return nil
+}
+
 func (cb *callbacks) TokensApproved(ctx context.Context, namespace string, approval *tokens.TokenApproval) error {
 	if namespace == "" {
 		// Older token subscriptions don't populate namespace, so deliver the event to every handler
@@ -152,13 +173,14 @@ type tokenData struct {
 }
 
 type createPool struct {
-	Type      core.TokenType     `json:"type"`
-	RequestID string             `json:"requestId"`
-	Signer    string             `json:"signer"`
-	Data      string             `json:"data,omitempty"`
-	Config    fftypes.JSONObject `json:"config"`
-	Name      string             `json:"name"`
-	Symbol    string             `json:"symbol"`
+	Type          core.TokenType                    `json:"type"`
+	RequestID     string                            `json:"requestId"`
+	Signer        string                            `json:"signer"`
+	Data          string                            `json:"data,omitempty"`
+	Config        fftypes.JSONObject                `json:"config"`
+	Name          string                            `json:"name"`
+	Symbol        string                            `json:"symbol"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
 }
 
 type activatePool struct {
@@ -184,57 +206,130 @@ type checkInterface struct {
 }
 
 type mintTokens struct {
-	PoolLocator string             `json:"poolLocator"`
-	TokenIndex  string             `json:"tokenIndex,omitempty"`
-	To          string             `json:"to"`
-	Amount      string             `json:"amount"`
-	RequestID   string             `json:"requestId,omitempty"`
-	Signer      string             `json:"signer"`
-	Data        string             `json:"data,omitempty"`
-	URI         string             `json:"uri,omitempty"`
-	Config      fftypes.JSONObject `json:"config"`
-	Interface   interface{}        `json:"interface,omitempty"`
+	PoolLocator   string                            `json:"poolLocator"`
+	TokenIndex    string                            `json:"tokenIndex,omitempty"`
+	To            string                            `json:"to"`
+	Amount        string                            `json:"amount"`
+	RequestID     string                            `json:"requestId,omitempty"`
+	Signer        string                            `json:"signer"`
+	Data          string                            `json:"data,omitempty"`
+	URI           string                            `json:"uri,omitempty"`
+	Config        fftypes.JSONObject                `json:"config"`
+	Interface     interface{}                       `json:"interface,omitempty"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
 }
 
 type burnTokens struct {
-	PoolLocator string             `json:"poolLocator"`
-	TokenIndex  string             `json:"tokenIndex,omitempty"`
-	From        string             `json:"from"`
-	Amount      string             `json:"amount"`
-	RequestID   string             `json:"requestId,omitempty"`
-	Signer      string             `json:"signer"`
-	Data        string             `json:"data,omitempty"`
-	Config      fftypes.JSONObject `json:"config"`
-	Interface   interface{}        `json:"interface,omitempty"`
+	PoolLocator   string                            `json:"poolLocator"`
+	TokenIndex    string                            `json:"tokenIndex,omitempty"`
+	From          string                            `json:"from"`
+	Amount        string                            `json:"amount"`
+	RequestID     string                            `json:"requestId,omitempty"`
+	Signer        string                            `json:"signer"`
+	Data          string                            `json:"data,omitempty"`
+	Config        fftypes.JSONObject                `json:"config"`
+	Interface     interface{}                       `json:"interface,omitempty"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
 }
 
 type transferTokens struct {
+	PoolLocator   string                            `json:"poolLocator"`
+	TokenIndex    string                            `json:"tokenIndex,omitempty"`
+	From          string                            `json:"from"`
+	To            string                            `json:"to"`
+	Amount        string                            `json:"amount"`
+	RequestID     string                            `json:"requestId,omitempty"`
+	Signer        string                            `json:"signer"`
+	Data          string                            `json:"data,omitempty"`
+	Config        fftypes.JSONObject                `json:"config"`
+	Interface     interface{}                       `json:"interface,omitempty"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
+}
+
+type transferTokensBatch struct {
+	PoolLocator   string                            `json:"poolLocator"`
+	From          string                            `json:"from"`
+	To            string                            `json:"to"`
+	Transfers     []*transferBatchEntry             `json:"transfers"`
+	RequestID     string                            `json:"requestId,omitempty"`
+	Signer        string                            `json:"signer"`
+	Data          string                            `json:"data,omitempty"`
+	Config        fftypes.JSONObject                `json:"config"`
+	Interface     interface{}                       `json:"interface,omitempty"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
+}
+
+type transferBatchEntry struct {
+	TokenIndex string `json:"tokenIndex,omitempty"`
+	Amount     string `json:"amount"`
+}
+
+type lockTokens struct {
+	PoolLocator   string                            `json:"poolLocator"`
+	LockID        string                            `json:"lockId"`
+	TokenIndex    string                            `json:"tokenIndex,omitempty"`
+	From          string                            `json:"from"`
+	To            string                            `json:"to"`
+	Amount        string                            `json:"amount"`
+	HashLock      string                            `json:"hashLock"`
+	Timeout       *fftypes.FFTime                   `json:"timeout"`
+	RequestID     string                            `json:"requestId,omitempty"`
+	Signer        string                            `json:"signer"`
+	Data          string                            `json:"data,omitempty"`
+	Config        fftypes.JSONObject                `json:"config"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
+}
+
+type unlockTokens struct {
+	PoolLocator   string                            `json:"poolLocator"`
+	LockID        string                            `json:"lockId"`
+	Preimage      string                            `json:"preimage,omitempty"`
+	RequestID     string                            `json:"requestId,omitempty"`
+	Signer        string                            `json:"signer"`
+	Data          string                            `json:"data,omitempty"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
+}
+
+type tokenApproval struct {
+	Signer        string                            `json:"signer"`
+	Operator      string                            `json:"operator"`
+	Approved      bool                              `json:"approved"`
+	PoolLocator   string                            `json:"poolLocator"`
+	RequestID     string                            `json:"requestId,omitempty"`
+	Data          string                            `json:"data,omitempty"`
+	Config        fftypes.JSONObject                `json:"config"`
+	Interface     interface{}                       `json:"interface,omitempty"`
+	UserOperation *accountabstraction.UserOperation `json:"userOperation,omitempty"`
+}
+
+type checkTransfer struct {
 	PoolLocator string             `json:"poolLocator"`
 	TokenIndex  string             `json:"tokenIndex,omitempty"`
 	From        string             `json:"from"`
 	To          string             `json:"to"`
 	Amount      string             `json:"amount"`
-	RequestID   string             `json:"requestId,omitempty"`
 	Signer      string             `json:"signer"`
-	Data        string             `json:"data,omitempty"`
 	Config      fftypes.JSONObject `json:"config"`
-	Interface   interface{}        `json:"interface,omitempty"`
 }
 
-type tokenApproval struct {
-	Signer      string             `json:"signer"`
-	Operator    string             `json:"operator"`
-	Approved    bool               `json:"approved"`
-	PoolLocator string             `json:"poolLocator"`
-	RequestID   string             `json:"requestId,omitempty"`
-	Data        string             `json:"data,omitempty"`
-	Config      fftypes.JSONObject `json:"config"`
-	Interface   interface{}        `json:"interface,omitempty"`
+type tokenBalance struct {
+	Balance fftypes.FFBigInt `json:"balance"`
 }
 
 type tokenError struct {
 	Error   string `json:"error,omitempty"`
 	Message string `json:"message,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Reason reported by connectors for permissioned tokens, when a request is rejected by the token's compliance rules
+const complianceRejectedReason = "compliance"
+
+func mapFailureReason(reason string) core.OpFailureReason {
+	if reason == complianceRejectedReason {
+		return core.OpFailureReasonComplianceRejected
+	}
+	return ""
 }
 
 func packPoolData(namespace string, id *fftypes.UUID) string {
@@ -259,6 +354,14 @@ func (ft *FFTokens) Name() string {
 }
 
 func (ft *FFTokens) Init(ctx context.Context, cancelCtx context.CancelFunc, name string, config config.Section) (err error) {
+	if ft.accountAbstraction, err = accountabstraction.New(ctx, config.SubSection(FFTAccountAbstractionConfigKey)); err != nil {
+		return err
+	}
+	return ft.initConnector(ctx, cancelCtx, name, config)
+}
+
+// initConnector initializes the connection to the token connector, which is shared with the HTS plugin
+func (ft *FFTokens) initConnector(ctx context.Context, cancelCtx context.CancelFunc, name string, config config.Section) (err error) {
 	ft.ctx = log.WithLogField(ctx, "proto", "fftokens")
 	ft.cancelCtx = cancelCtx
 	ft.configuredName = name
@@ -334,7 +437,7 @@ func (ft *FFTokens) SetOperationHandler(namespace string, handler core.Operation
 }
 
 func (ft *FFTokens) backgroundStartLoop() {
-	_ = ft.backgroundRetry.Do(ft.ctx, fmt.Sprintf("Background start %s", ft.Name()), func(attempt int) (retry bool, err error) {
+	_ = ft.backgroundRetry.Do(ft.ctx, fmt.Sprintf("Background start %s", ft.callbacks.plugin.Name()), func(attempt int) (retry bool, err error) {
 		err = ft.wsconn.Connect()
 		if err != nil {
 			return true, err
@@ -371,6 +474,7 @@ func (ft *FFTokens) handleReceipt(ctx context.Context, data fftypes.JSONObject)
 		return
 	}
 	var updateType core.OpStatus
+	var failureReason core.OpFailureReason
 	switch replyType {
 	case "TransactionSuccess":
 		updateType = core.OpStatusSucceeded
@@ -378,9 +482,10 @@ func (ft *FFTokens) handleReceipt(ctx context.Context, data fftypes.JSONObject)
 		updateType = core.OpStatusPending
 	default:
 		updateType = core.OpStatusFailed
+		failureReason = mapFailureReason(data.GetString("errorReason"))
 	}
 	l.Infof("Received operation update: status=%s request=%s message=%s", updateType, requestID, message)
-	ft.callbacks.OperationUpdate(ctx, requestID, updateType, txHash, message, data)
+	ft.callbacks.OperationUpdate(ctx, requestID, updateType, txHash, message, failureReason, data)
 }
 
 func (ft *FFTokens) buildBlockchainEvent(eventData fftypes.JSONObject) *blockchain.Event {
@@ -399,7 +504,7 @@ func (ft *FFTokens) buildBlockchainEvent(eventData fftypes.JSONObject) *blockcha
 		return &blockchain.Event{
 			ProtocolID:     blockchainID,
 			BlockchainTXID: txHash,
-			Source:         ft.Name() + ":" + ft.configuredName,
+			Source:         ft.callbacks.plugin.Name() + ":" + ft.configuredName,
 			Name:           eventData.GetString("name"),
 			Output:         eventData.GetObject("output"),
 			Location:       eventData.GetString("location"),
@@ -426,6 +531,7 @@ func (ft *FFTokens) handleTokenPoolCreate(ctx context.Context, eventData fftypes
 	interfaceFormat := eventData.GetString("interfaceFormat")
 	symbol := eventData.GetString("symbol")
 	decimals := eventData.GetInt64("decimals")
+	identityRegistry := eventData.GetString("identityRegistry")
 	info := eventData.GetObject("info")
 	blockchainEvent := eventData.GetObject("blockchain")
 	poolData := eventData.GetString("poolData")
@@ -458,13 +564,14 @@ func (ft *FFTokens) handleTokenPoolCreate(ctx context.Context, eventData fftypes
 			ID:   txData.TX,
 			Type: txType,
 		},
-		Connector:       ft.configuredName,
-		Standard:        standard,
-		InterfaceFormat: interfaceFormat,
-		Symbol:          symbol,
-		Decimals:        int(decimals),
-		Info:            info,
-		Event:           ft.buildBlockchainEvent(blockchainEvent),
+		Connector:        ft.configuredName,
+		Standard:         standard,
+		InterfaceFormat:  interfaceFormat,
+		Symbol:           symbol,
+		Decimals:         int(decimals),
+		IdentityRegistry: identityRegistry,
+		Info:             info,
+		Event:            ft.buildBlockchainEvent(blockchainEvent),
 	}
 
 	// If there's an error dispatching the event, we must return the error and shutdown
@@ -472,7 +579,24 @@ func (ft *FFTokens) handleTokenPoolCreate(ctx context.Context, eventData fftypes
 	return ft.callbacks.TokenPoolCreated(ctx, namespace, pool)
 }
 
+// handleTokenEventRemoved passes on the removal of the blockchain event of a token transfer or approval delivered
+// previously, which the connector flags on the blockchain event it forwards (such as after a chain reorganization)
+func (ft *FFTokens) handleTokenEventRemoved(ctx context.Context, eventData fftypes.JSONObject) error {
+	blockchainEvent := ft.buildBlockchainEvent(eventData.GetObject("blockchain"))
+	if blockchainEvent == nil {
+		log.L(ctx).Errorf("Removed token event is not valid - missing blockchain event: %+v", eventData)
+		return nil // move on
+	}
+	log.L(ctx).Warnf("Token event %s in transaction %s has been removed from the chain", blockchainEvent.ProtocolID, blockchainEvent.BlockchainTXID)
+	namespace, _ := unpackPoolData(ctx, eventData.GetString("poolData"))
+	return ft.callbacks.TokenEventRemoved(ctx, namespace, blockchainEvent)
+}
+
 func (ft *FFTokens) handleTokenTransfer(ctx context.Context, t core.TokenTransferType, eventData fftypes.JSONObject) (err error) {
+	if eventData.GetObject("blockchain").GetBool("removed") {
+		return ft.handleTokenEventRemoved(ctx, eventData)
+	}
+
 	protocolID := eventData.GetString("id")
 	poolLocator := eventData.GetString("poolLocator")
 	signerAddress := eventData.GetString("signer")
@@ -540,11 +664,31 @@ func (ft *FFTokens) handleTokenTransfer(ctx context.Context, t core.TokenTransfe
 		Event: blockchainEvent,
 	}
 
+	// Connectors for standards such as Solana SPL report the token accounts created by the transfer (such as
+	// the associated token account of a recipient receiving their first tokens in a pool)
+	for _, created := range eventData.GetObjectArray("createdAccounts") {
+		owner := created.GetString("owner")
+		account := created.GetString("account")
+		if owner == "" || account == "" {
+			log.L(ctx).Warnf("%s event contains an invalid created account - ignoring: %+v", t, created)
+			continue
+		}
+		transfer.CreatedAccounts = append(transfer.CreatedAccounts, &tokens.TokenAccountCreated{
+			Owner:   owner,
+			Account: account,
+			Payer:   created.GetString("payer"),
+		})
+	}
+
 	// If there's an error dispatching the event, we must return the error and shutdown
 	return ft.callbacks.TokensTransferred(ctx, namespace, transfer)
 }
 
 func (ft *FFTokens) handleTokenApproval(ctx context.Context, eventData fftypes.JSONObject) (err error) {
+	if eventData.GetObject("blockchain").GetBool("removed") {
+		return ft.handleTokenEventRemoved(ctx, eventData)
+	}
+
 	protocolID := eventData.GetString("id")
 	subject := eventData.GetString("subject")
 	signerAddress := eventData.GetString("signer")
@@ -689,14 +833,27 @@ func (ft *FFTokens) eventLoop() {
 // into a message of the form:
 //
 //	"Bad Request: Field 'x' is required"
+//
+// Rejections by the compliance rules of a permissioned token are returned as a core.OperationFailure,
+// so that the reason is recorded on the operation. Any other error response from the connector is
+// returned as a tokens.ErrorResponse with the HTTP status code.
 func wrapError(ctx context.Context, errRes *tokenError, res *resty.Response, err error) error {
 	if errRes != nil && errRes.Message != "" {
 		if errRes.Error != "" {
-			return i18n.WrapError(ctx, err, coremsgs.MsgTokensRESTErr, errRes.Error+": "+errRes.Message)
+			err = i18n.WrapError(ctx, err, coremsgs.MsgTokensRESTErr, errRes.Error+": "+errRes.Message)
+		} else {
+			err = i18n.WrapError(ctx, err, coremsgs.MsgTokensRESTErr, errRes.Message)
 		}
-		return i18n.WrapError(ctx, err, coremsgs.MsgTokensRESTErr, errRes.Message)
+		if reason := mapFailureReason(errRes.Reason); reason != "" {
+			return &core.OperationFailure{Reason: reason, Err: err}
+		}
+	} else {
+		err = ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgTokensRESTErr)
 	}
-	return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgTokensRESTErr)
+	if res != nil && res.StatusCode() >= 400 {
+		return &tokens.ErrorResponse{StatusCode: res.StatusCode(), Err: err}
+	}
+	return err
 }
 
 func (ft *FFTokens) CreateTokenPool(ctx context.Context, nsOpID string, pool *core.TokenPool) (complete bool, err error) {
@@ -708,13 +865,14 @@ func (ft *FFTokens) CreateTokenPool(ctx context.Context, nsOpID string, pool *co
 	var errRes tokenError
 	res, err := ft.client.R().SetContext(ctx).
 		SetBody(&createPool{
-			Type:      pool.Type,
-			RequestID: nsOpID,
-			Signer:    pool.Key,
-			Data:      string(data),
-			Config:    pool.Config,
-			Name:      pool.Name,
-			Symbol:    pool.Symbol,
+			Type:          pool.Type,
+			RequestID:     nsOpID,
+			Signer:        pool.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(pool.Key),
+			Data:          string(data),
+			Config:        pool.Config,
+			Name:          pool.Name,
+			Symbol:        pool.Symbol,
 		}).
 		SetError(&errRes).
 		Post("/api/v1/createpool")
@@ -847,16 +1005,17 @@ func (ft *FFTokens) MintTokens(ctx context.Context, nsOpID string, poolLocator s
 	var errRes tokenError
 	res, err := ft.client.R().SetContext(ctx).
 		SetBody(&mintTokens{
-			PoolLocator: poolLocator,
-			TokenIndex:  mint.TokenIndex,
-			To:          mint.To,
-			Amount:      mint.Amount.Int().String(),
-			RequestID:   nsOpID,
-			Signer:      mint.Key,
-			Data:        string(data),
-			URI:         mint.URI,
-			Config:      mint.Config,
-			Interface:   iface,
+			PoolLocator:   poolLocator,
+			TokenIndex:    mint.TokenIndex,
+			To:            mint.To,
+			Amount:        mint.Amount.Int().String(),
+			RequestID:     nsOpID,
+			Signer:        mint.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(mint.Key),
+			Data:          string(data),
+			URI:           mint.URI,
+			Config:        mint.Config,
+			Interface:     iface,
 		}).
 		SetError(&errRes).
 		Post("/api/v1/mint")
@@ -882,15 +1041,16 @@ func (ft *FFTokens) BurnTokens(ctx context.Context, nsOpID string, poolLocator s
 	var errRes tokenError
 	res, err := ft.client.R().SetContext(ctx).
 		SetBody(&burnTokens{
-			PoolLocator: poolLocator,
-			TokenIndex:  burn.TokenIndex,
-			From:        burn.From,
-			Amount:      burn.Amount.Int().String(),
-			RequestID:   nsOpID,
-			Signer:      burn.Key,
-			Data:        string(data),
-			Config:      burn.Config,
-			Interface:   iface,
+			PoolLocator:   poolLocator,
+			TokenIndex:    burn.TokenIndex,
+			From:          burn.From,
+			Amount:        burn.Amount.Int().String(),
+			RequestID:     nsOpID,
+			Signer:        burn.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(burn.Key),
+			Data:          string(data),
+			Config:        burn.Config,
+			Interface:     iface,
 		}).
 		SetError(&errRes).
 		Post("/api/v1/burn")
@@ -916,23 +1076,189 @@ func (ft *FFTokens) TransferTokens(ctx context.Context, nsOpID string, poolLocat
 	var errRes tokenError
 	res, err := ft.client.R().SetContext(ctx).
 		SetBody(&transferTokens{
+			PoolLocator:   poolLocator,
+			TokenIndex:    transfer.TokenIndex,
+			From:          transfer.From,
+			To:            transfer.To,
+			Amount:        transfer.Amount.Int().String(),
+			RequestID:     nsOpID,
+			Signer:        transfer.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(transfer.Key),
+			Data:          string(data),
+			Config:        transfer.Config,
+			Interface:     iface,
+		}).
+		SetError(&errRes).
+		Post("/api/v1/transfer")
+	if err != nil || !res.IsSuccess() {
+		return wrapError(ctx, &errRes, res, err)
+	}
+	return nil
+}
+
+func (ft *FFTokens) TransferTokensBatch(ctx context.Context, nsOpID string, poolLocator string, transfers []*core.TokenTransfer, methods *fftypes.JSONAny) error {
+	// All legs share the same transaction, accounts and signer
+	first := transfers[0]
+	data, _ := json.Marshal(tokenData{
+		TX:     first.TX.ID,
+		TXType: first.TX.Type,
+	})
+
+	var iface interface{}
+	if methods != nil {
+		iface = methods.JSONObject()["transferBatch"]
+	}
+
+	entries := make([]*transferBatchEntry, len(transfers))
+	for i, transfer := range transfers {
+		entries[i] = &transferBatchEntry{
+			TokenIndex: transfer.TokenIndex,
+			Amount:     transfer.Amount.Int().String(),
+		}
+	}
+
+	var errRes tokenError
+	res, err := ft.client.R().SetContext(ctx).
+		SetBody(&transferTokensBatch{
+			PoolLocator:   poolLocator,
+			From:          first.From,
+			To:            first.To,
+			Transfers:     entries,
+			RequestID:     nsOpID,
+			Signer:        first.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(first.Key),
+			Data:          string(data),
+			Config:        first.Config,
+			Interface:     iface,
+		}).
+		SetError(&errRes).
+		Post("/api/v1/transferbatch")
+	if err != nil || !res.IsSuccess() {
+		return wrapError(ctx, &errRes, res, err)
+	}
+	return nil
+}
+
+func (ft *FFTokens) LockTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	data, _ := json.Marshal(tokenData{
+		TX:     swap.TX.ID,
+		TXType: swap.TX.Type,
+	})
+	var errRes tokenError
+	res, err := ft.client.R().SetContext(ctx).
+		SetBody(&lockTokens{
+			PoolLocator:   poolLocator,
+			LockID:        leg.LockID,
+			TokenIndex:    leg.TokenIndex,
+			From:          leg.From,
+			To:            leg.To,
+			Amount:        leg.Amount.Int().String(),
+			HashLock:      swap.HashLock.String(),
+			Timeout:       swap.Timeout,
+			RequestID:     nsOpID,
+			Signer:        leg.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(leg.Key),
+			Data:          string(data),
+			Config:        leg.Config,
+		}).
+		SetError(&errRes).
+		Post("/api/v1/lock")
+	if err != nil || !res.IsSuccess() {
+		return wrapError(ctx, &errRes, res, err)
+	}
+	return nil
+}
+
+func (ft *FFTokens) ClaimTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return ft.unlockTokens(ctx, "/api/v1/claim", nsOpID, poolLocator, swap, leg, swap.Preimage.String())
+}
+
+func (ft *FFTokens) RefundTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return ft.unlockTokens(ctx, "/api/v1/refund", nsOpID, poolLocator, swap, leg, "")
+}
+
+func (ft *FFTokens) unlockTokens(ctx context.Context, path, nsOpID, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg, preimage string) error {
+	data, _ := json.Marshal(tokenData{
+		TX:     swap.TX.ID,
+		TXType: swap.TX.Type,
+	})
+	var errRes tokenError
+	res, err := ft.client.R().SetContext(ctx).
+		SetBody(&unlockTokens{
+			PoolLocator:   poolLocator,
+			LockID:        leg.LockID,
+			Preimage:      preimage,
+			RequestID:     nsOpID,
+			Signer:        leg.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(leg.Key),
+			Data:          string(data),
+		}).
+		SetError(&errRes).
+		Post(path)
+	if err != nil || !res.IsSuccess() {
+		return wrapError(ctx, &errRes, res, err)
+	}
+	return nil
+}
+
+func (ft *FFTokens) CheckTransfer(ctx context.Context, poolLocator string, transfer *core.TokenTransfer) (*core.TokenTransferEligibility, error) {
+	var errRes tokenError
+	var eligibility core.TokenTransferEligibility
+	res, err := ft.client.R().SetContext(ctx).
+		SetBody(&checkTransfer{
 			PoolLocator: poolLocator,
 			TokenIndex:  transfer.TokenIndex,
 			From:        transfer.From,
 			To:          transfer.To,
 			Amount:      transfer.Amount.Int().String(),
-			RequestID:   nsOpID,
 			Signer:      transfer.Key,
-			Data:        string(data),
 			Config:      transfer.Config,
-			Interface:   iface,
 		}).
 		SetError(&errRes).
-		Post("/api/v1/transfer")
+		SetResult(&eligibility).
+		Post("/api/v1/checktransfer")
 	if err != nil || !res.IsSuccess() {
-		return wrapError(ctx, &errRes, res, err)
+		return nil, wrapError(ctx, &errRes, res, err)
 	}
-	return nil
+	return &eligibility, nil
+}
+
+func (ft *FFTokens) GetBalance(ctx context.Context, poolLocator, tokenIndex, account string) (*fftypes.FFBigInt, error) {
+	var errRes tokenError
+	var balance tokenBalance
+	res, err := ft.client.R().SetContext(ctx).
+		SetQueryParam("poolLocator", poolLocator).
+		SetQueryParam("tokenIndex", tokenIndex).
+		SetQueryParam("account", account).
+		SetError(&errRes).
+		SetResult(&balance).
+		Get("/api/v1/balance")
+	if err != nil || !res.IsSuccess() {
+		return nil, wrapError(ctx, &errRes, res, err)
+	}
+	return &balance.Balance, nil
+}
+
+func (ft *FFTokens) GetTokenFees(ctx context.Context, poolLocator, tokenIndex string) (*core.TokenFees, error) {
+	var errRes tokenError
+	var fees core.TokenFees
+	res, err := ft.client.R().SetContext(ctx).
+		SetQueryParam("poolLocator", poolLocator).
+		SetQueryParam("tokenIndex", tokenIndex).
+		SetError(&errRes).
+		SetResult(&fees).
+		Get("/api/v1/fees")
+	if err == nil && res.StatusCode() == 404 {
+		// Connector does not report fees for this pool
+		return nil, nil
+	}
+	if err != nil || !res.IsSuccess() {
+		return nil, wrapError(ctx, &errRes, res, err)
+	}
+	if fees.Royalty == nil && fees.TransferFee == nil {
+		return nil, nil
+	}
+	return &fees, nil
 }
 
 func (ft *FFTokens) TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error {
@@ -951,14 +1277,15 @@ func (ft *FFTokens) TokensApproval(ctx context.Context, nsOpID string, poolLocat
 	var errRes tokenError
 	res, err := ft.client.R().SetContext(ctx).
 		SetBody(&tokenApproval{
-			PoolLocator: poolLocator,
-			Signer:      approval.Key,
-			Operator:    approval.Operator,
-			Approved:    approval.Approved,
-			RequestID:   nsOpID,
-			Data:        string(data),
-			Config:      approval.Config,
-			Interface:   iface,
+			PoolLocator:   poolLocator,
+			Signer:        approval.Key,
+			UserOperation: ft.accountAbstraction.UserOperationFor(approval.Key),
+			Operator:      approval.Operator,
+			Approved:      approval.Approved,
+			RequestID:     nsOpID,
+			Data:          string(data),
+			Config:        approval.Config,
+			Interface:     iface,
 		}).
 		SetError(&errRes).
 		Post("/api/v1/approval")
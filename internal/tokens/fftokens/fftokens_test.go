@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -37,6 +38,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/coremocks"
 	"github.com/hyperledger/firefly/mocks/tokenmocks"
 	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/tokens"
 	"github.com/jarcoal/httpmock"
@@ -299,11 +301,12 @@ func TestCreateTokenPoolSynchronous(t *testing.T) {
 
 			res := &http.Response{
 				Body: ioutil.NopCloser(bytes.NewReader([]byte(fftypes.JSONObject{
-					"type":        "fungible",
-					"poolLocator": "F1",
-					"signer":      "0x0",
-					"decimals":    18,
-					"data":        `{"tx":"` + pool.TX.ID.String() + `"}`,
+					"type":             "fungible",
+					"poolLocator":      "F1",
+					"signer":           "0x0",
+					"decimals":         18,
+					"data":             `{"tx":"` + pool.TX.ID.String() + `"}`,
+					"identityRegistry": "0xregistry",
 				}.String()))),
 				Header: http.Header{
 					"Content-Type": []string{"application/json"},
@@ -319,7 +322,7 @@ func TestCreateTokenPoolSynchronous(t *testing.T) {
 	ctx, cancelCtx := context.WithCancel(context.Background())
 	defer cancelCtx()
 	mcb.On("TokenPoolCreated", ctx /* important this gets through */, h, mock.MatchedBy(func(p *tokens.TokenPool) bool {
-		return p.PoolLocator == "F1" && p.Type == core.TokenTypeFungible && *p.TX.ID == *pool.TX.ID
+		return p.PoolLocator == "F1" && p.Type == core.TokenTypeFungible && *p.TX.ID == *pool.TX.ID && p.IdentityRegistry == "0xregistry"
 	})).Return(nil)
 
 	complete, err := h.CreateTokenPool(ctx, nsOpID, pool)
@@ -948,6 +951,436 @@ func TestTransferTokensError(t *testing.T) {
 	assert.Regexp(t, "FF10274", err)
 }
 
+func TestTransferTokensBatch(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	txID := fftypes.NewUUID()
+	newLeg := func(tokenIndex string, amount int64) *core.TokenTransfer {
+		return &core.TokenTransfer{
+			LocalID:    fftypes.NewUUID(),
+			TokenIndex: tokenIndex,
+			From:       "user1",
+			To:         "user2",
+			Key:        "0x123",
+			Amount:     *fftypes.NewFFBigInt(amount),
+			TX: core.TransactionRef{
+				ID:   txID,
+				Type: core.TransactionTypeTokenTransfer,
+			},
+			Config: fftypes.JSONObject{
+				"foo": "bar",
+			},
+		}
+	}
+	transfers := []*core.TokenTransfer{newLeg("1", 10), newLeg("2", 5)}
+	methods := fftypes.JSONAnyPtr(`{"transferBatch":"safeBatchTransferFrom"}`)
+	opID := fftypes.NewUUID()
+	nsOpID := "ns1:" + opID.String()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transferbatch", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"poolLocator": "123",
+				"from":        "user1",
+				"to":          "user2",
+				"transfers": []interface{}{
+					map[string]interface{}{"tokenIndex": "1", "amount": "10"},
+					map[string]interface{}{"tokenIndex": "2", "amount": "5"},
+				},
+				"signer": "0x123",
+				"config": map[string]interface{}{
+					"foo": "bar",
+				},
+				"interface": "safeBatchTransferFrom",
+				"requestId": "ns1:" + opID.String(),
+				"data": fftypes.JSONObject{
+					"tx":     txID.String(),
+					"txtype": core.TransactionTypeTokenTransfer.String(),
+				}.String(),
+			}, body)
+
+			res := &http.Response{
+				Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"id":"1"}`))),
+				Header: http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+				StatusCode: 202,
+			}
+			return res, nil
+		})
+
+	err := h.TransferTokensBatch(context.Background(), nsOpID, "123", transfers, methods)
+	assert.NoError(t, err)
+}
+
+func TestTransferTokensBatchError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	transfers := []*core.TokenTransfer{{}}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transferbatch", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+	err := h.TransferTokensBatch(context.Background(), nsOpID, "F1", transfers, nil)
+	assert.Regexp(t, "FF10274", err)
+}
+
+func newTestTokenSwap() (*core.TokenSwap, *core.TokenSwapLeg) {
+	swap := &core.TokenSwap{
+		ID:       fftypes.NewUUID(),
+		HashLock: fftypes.NewRandB32(),
+		Preimage: fftypes.NewRandB32(),
+		Timeout:  fftypes.Now(),
+		TX: core.TransactionRef{
+			ID:   fftypes.NewUUID(),
+			Type: core.TransactionTypeTokenSwap,
+		},
+	}
+	leg := &core.TokenSwapLeg{
+		Type:       core.TokenSwapLegTypeTransfer,
+		LockID:     swap.ID.String() + "-0",
+		TokenIndex: "1",
+		Key:        "0x123",
+		From:       "user1",
+		To:         "user2",
+		Amount:     *fftypes.NewFFBigInt(10),
+		Config: fftypes.JSONObject{
+			"foo": "bar",
+		},
+	}
+	swap.Legs = core.TokenSwapLegs{leg}
+	return swap, leg
+}
+
+func TestLockTokens(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	swap, leg := newTestTokenSwap()
+	opID := fftypes.NewUUID()
+	nsOpID := "ns1:" + opID.String()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/lock", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"poolLocator": "123",
+				"lockId":      leg.LockID,
+				"tokenIndex":  "1",
+				"from":        "user1",
+				"to":          "user2",
+				"amount":      "10",
+				"hashLock":    swap.HashLock.String(),
+				"timeout":     swap.Timeout.String(),
+				"signer":      "0x123",
+				"config": map[string]interface{}{
+					"foo": "bar",
+				},
+				"requestId": nsOpID,
+				"data": fftypes.JSONObject{
+					"tx":     swap.TX.ID.String(),
+					"txtype": core.TransactionTypeTokenSwap.String(),
+				}.String(),
+			}, body)
+
+			res := &http.Response{
+				Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"id":"1"}`))),
+				Header: http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+				StatusCode: 202,
+			}
+			return res, nil
+		})
+
+	err := h.LockTokens(context.Background(), nsOpID, "123", swap, leg)
+	assert.NoError(t, err)
+}
+
+func TestLockTokensError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	swap, leg := newTestTokenSwap()
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/lock", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+	err := h.LockTokens(context.Background(), nsOpID, "F1", swap, leg)
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestClaimTokens(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	swap, leg := newTestTokenSwap()
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/claim", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"poolLocator": "123",
+				"lockId":      leg.LockID,
+				"preimage":    swap.Preimage.String(),
+				"signer":      "0x123",
+				"requestId":   nsOpID,
+				"data": fftypes.JSONObject{
+					"tx":     swap.TX.ID.String(),
+					"txtype": core.TransactionTypeTokenSwap.String(),
+				}.String(),
+			}, body)
+			return httpmock.NewJsonResponse(202, fftypes.JSONObject{"id": "1"})
+		})
+
+	err := h.ClaimTokens(context.Background(), nsOpID, "123", swap, leg)
+	assert.NoError(t, err)
+}
+
+func TestRefundTokens(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	swap, leg := newTestTokenSwap()
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/refund", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			_, hasPreimage := body["preimage"]
+			assert.False(t, hasPreimage)
+			assert.Equal(t, leg.LockID, body.GetString("lockId"))
+			return httpmock.NewJsonResponse(202, fftypes.JSONObject{"id": "1"})
+		})
+
+	err := h.RefundTokens(context.Background(), nsOpID, "123", swap, leg)
+	assert.NoError(t, err)
+}
+
+func TestRefundTokensError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	swap, leg := newTestTokenSwap()
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/refund", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+	err := h.RefundTokens(context.Background(), nsOpID, "F1", swap, leg)
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestTransferTokensComplianceRejected(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	transfer := &core.TokenTransfer{}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer", httpURL),
+		httpmock.NewJsonResponderOrPanic(422, fftypes.JSONObject{
+			"error":   "Unprocessable Entity",
+			"message": "recipient not verified",
+			"reason":  "compliance",
+		}))
+
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+	err := h.TransferTokens(context.Background(), nsOpID, "F1", transfer, nil)
+	assert.Regexp(t, "FF10274.*recipient not verified", err)
+	var failure *core.OperationFailure
+	assert.ErrorAs(t, err, &failure)
+	assert.Equal(t, core.OpFailureReasonComplianceRejected, failure.Reason)
+}
+
+func TestTransferTokensErrorStatus(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer", httpURL),
+		httpmock.NewJsonResponderOrPanic(400, fftypes.JSONObject{
+			"error":   "Bad Request",
+			"message": "invalid amount",
+		}))
+
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+	err := h.TransferTokens(context.Background(), nsOpID, "F1", &core.TokenTransfer{}, nil)
+	assert.Regexp(t, "FF10274.*invalid amount", err)
+	var errRes *tokens.ErrorResponse
+	assert.ErrorAs(t, err, &errRes)
+	assert.Equal(t, 400, errRes.StatusCode)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer", httpURL),
+		httpmock.NewStringResponder(502, "bad gateway"))
+	err = h.TransferTokens(context.Background(), nsOpID, "F1", &core.TokenTransfer{}, nil)
+	assert.Regexp(t, "FF10274.*bad gateway", err)
+	assert.ErrorAs(t, err, &errRes)
+	assert.Equal(t, 502, errRes.StatusCode)
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer", httpURL),
+		httpmock.NewErrorResponder(fmt.Errorf("pop")))
+	err = h.TransferTokens(context.Background(), nsOpID, "F1", &core.TokenTransfer{}, nil)
+	assert.Regexp(t, "FF10274.*pop", err)
+	assert.False(t, errors.As(err, &errRes))
+}
+
+func TestCheckTransfer(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	transfer := &core.TokenTransfer{
+		TokenIndex: "1",
+		From:       "user1",
+		To:         "user2",
+		Key:        "0x123",
+		Amount:     *fftypes.NewFFBigInt(10),
+	}
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/checktransfer", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			body := make(fftypes.JSONObject)
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"poolLocator": "123",
+				"tokenIndex":  "1",
+				"from":        "user1",
+				"to":          "user2",
+				"amount":      "10",
+				"signer":      "0x123",
+				"config":      nil,
+			}, body)
+
+			res := &http.Response{
+				Body: ioutil.NopCloser(bytes.NewReader([]byte(`{"eligible":false,"reason":"recipient not verified","info":{"code":"2"}}`))),
+				Header: http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+				StatusCode: 200,
+			}
+			return res, nil
+		})
+
+	eligibility, err := h.CheckTransfer(context.Background(), "123", transfer)
+	assert.NoError(t, err)
+	assert.False(t, eligibility.Eligible)
+	assert.Equal(t, "recipient not verified", eligibility.Reason)
+	assert.Equal(t, "2", eligibility.Info.GetString("code"))
+}
+
+func TestCheckTransferError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/checktransfer", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	_, err := h.CheckTransfer(context.Background(), "F1", &core.TokenTransfer{})
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestGetBalance(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/balance", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "123", req.URL.Query().Get("poolLocator"))
+			assert.Equal(t, "1", req.URL.Query().Get("tokenIndex"))
+			assert.Equal(t, "0x01", req.URL.Query().Get("account"))
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"balance": "10"})(req)
+		})
+
+	balance, err := h.GetBalance(context.Background(), "123", "1", "0x01")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), balance.Int().Int64())
+}
+
+func TestGetBalanceError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/balance", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	_, err := h.GetBalance(context.Background(), "123", "1", "0x01")
+	assert.Regexp(t, "FF10274", err)
+}
+
+func TestGetTokenFees(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/fees", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "123", req.URL.Query().Get("poolLocator"))
+			assert.Equal(t, "1", req.URL.Query().Get("tokenIndex"))
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"royalty": fftypes.JSONObject{
+					"receiver":    "0x01",
+					"basisPoints": 500,
+				},
+				"transferFee": fftypes.JSONObject{
+					"basisPoints": 100,
+					"maximum":     "50",
+				},
+			})(req)
+		})
+
+	fees, err := h.GetTokenFees(context.Background(), "123", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x01", fees.Royalty.Receiver)
+	assert.Equal(t, int64(500), fees.Royalty.BasisPoints)
+	assert.Equal(t, int64(100), fees.TransferFee.BasisPoints)
+	assert.Equal(t, int64(50), fees.TransferFee.Maximum.Int64())
+}
+
+func TestGetTokenFeesNone(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/fees", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{}))
+
+	fees, err := h.GetTokenFees(context.Background(), "123", "")
+	assert.NoError(t, err)
+	assert.Nil(t, fees)
+}
+
+func TestGetTokenFeesNotSupported(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/fees", httpURL),
+		httpmock.NewJsonResponderOrPanic(404, fftypes.JSONObject{}))
+
+	fees, err := h.GetTokenFees(context.Background(), "123", "")
+	assert.NoError(t, err)
+	assert.Nil(t, fees)
+}
+
+func TestGetTokenFeesError(t *testing.T) {
+	h, _, _, httpURL, done := newTestFFTokens(t)
+	defer done()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/api/v1/fees", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{}))
+
+	_, err := h.GetTokenFees(context.Background(), "123", "")
+	assert.Regexp(t, "FF10274", err)
+}
+
 func TestIgnoredEvents(t *testing.T) {
 	h, toServer, fromServer, _, done := newTestFFTokens(t)
 	defer done()
@@ -1190,6 +1623,27 @@ func TestReceiptEvents(t *testing.T) {
 	}.String()
 	<-mockCalled
 
+	// receipt: compliance rejection
+	mcb.On("OperationUpdate", mock.MatchedBy(func(update *core.OperationUpdate) bool {
+		return update.NamespacedOpID == "ns1:"+opID.String() &&
+			update.Status == core.OpStatusFailed &&
+			update.ErrorMessage == "recipient not verified" &&
+			update.FailureReason == core.OpFailureReasonComplianceRejected
+	})).Return(nil).Once().Run(func(args mock.Arguments) { mockCalled <- true })
+	fromServer <- fftypes.JSONObject{
+		"id":    "6",
+		"event": "receipt",
+		"data": fftypes.JSONObject{
+			"headers": fftypes.JSONObject{
+				"requestId": "ns1:" + opID.String(),
+				"type":      "TransactionFailed",
+			},
+			"errorMessage": "recipient not verified",
+			"errorReason":  "compliance",
+		},
+	}.String()
+	<-mockCalled
+
 	mcb.AssertExpectations(t)
 }
 
@@ -1529,6 +1983,54 @@ func TestTransferEvents(t *testing.T) {
 	}.String()
 }
 
+func TestTransferEventCreatedAccounts(t *testing.T) {
+	h, toServer, fromServer, _, done := newTestFFTokens(t)
+	defer done()
+
+	err := h.Start()
+	assert.NoError(t, err)
+
+	mcb := &tokenmocks.Callbacks{}
+	h.SetHandler("ns1", mcb)
+	txID := fftypes.NewUUID()
+
+	// token-mint: first mint to an owner creates their associated token account
+	mcb.On("TokensTransferred", h, mock.MatchedBy(func(t *tokens.TokenTransfer) bool {
+		return t.Amount.Int().Int64() == 5 &&
+			t.To == "Owner1" &&
+			len(t.CreatedAccounts) == 1 &&
+			t.CreatedAccounts[0].Owner == "Owner1" &&
+			t.CreatedAccounts[0].Account == "Account1" &&
+			t.CreatedAccounts[0].Payer == "Payer1"
+	})).Return(nil).Once()
+	fromServer <- fftypes.JSONObject{
+		"id":    "21",
+		"event": "token-mint",
+		"data": fftypes.JSONObject{
+			"id":          "000000000010/000020/000030/000040",
+			"poolLocator": "Mint1",
+			"signer":      "Payer1",
+			"to":          "Owner1",
+			"amount":      "5",
+			"data":        fftypes.JSONObject{"tx": txID.String()}.String(),
+			"createdAccounts": fftypes.JSONObjectArray{
+				{"owner": "Owner1", "account": "Account1", "payer": "Payer1"},
+				{"owner": "Owner2"}, // invalid and ignored
+			},
+			"blockchain": fftypes.JSONObject{
+				"id": "000000000010/000020/000030",
+				"info": fftypes.JSONObject{
+					"transactionHash": "0xffffeeee",
+				},
+			},
+		},
+	}.String()
+	msg := <-toServer
+	assert.Equal(t, `{"data":{"id":"21"},"event":"ack"}`, string(msg))
+
+	mcb.AssertExpectations(t)
+}
+
 func TestApprovalEvents(t *testing.T) {
 	h, toServer, fromServer, _, done := newTestFFTokens(t)
 	defer done()
@@ -1638,6 +2140,141 @@ func TestApprovalEvents(t *testing.T) {
 	mcb.AssertExpectations(t)
 }
 
+func TestRemovedTokenEvents(t *testing.T) {
+	h, toServer, fromServer, _, done := newTestFFTokens(t)
+	defer done()
+
+	err := h.Start()
+	assert.NoError(t, err)
+
+	mcb1 := &tokenmocks.Callbacks{}
+	mcb2 := &tokenmocks.Callbacks{}
+	h.SetHandler("ns1", mcb1)
+	h.SetHandler("ns2", mcb2)
+
+	removedEvent := func(t *blockchain.Event) bool {
+		return t.ProtocolID == "000000000010/000020/000030" && t.BlockchainTXID == "0xffffeeee"
+	}
+	blockchainInfo := fftypes.JSONObject{
+		"id":      "000000000010/000020/000030",
+		"removed": true,
+		"info": fftypes.JSONObject{
+			"transactionHash": "0xffffeeee",
+		},
+	}
+
+	// token-transfer: removed
+	mcb1.On("TokenEventRemoved", h, mock.MatchedBy(removedEvent)).Return(nil).Once()
+	fromServer <- fftypes.JSONObject{
+		"id":    "1",
+		"event": "token-transfer",
+		"data": fftypes.JSONObject{
+			"id":          "000000000010/000020/000030/000040",
+			"poolData":    "ns1",
+			"poolLocator": "F1",
+			"signer":      "0x0",
+			"from":        "0x1",
+			"to":          "0x2",
+			"amount":      "2",
+			"blockchain":  blockchainInfo,
+		},
+	}.String()
+	msg := <-toServer
+	assert.Equal(t, `{"data":{"id":"1"},"event":"ack"}`, string(msg))
+
+	// token-approval: removed
+	mcb2.On("TokenEventRemoved", h, mock.MatchedBy(removedEvent)).Return(nil).Once()
+	fromServer <- fftypes.JSONObject{
+		"id":    "2",
+		"event": "token-approval",
+		"data": fftypes.JSONObject{
+			"id":          "000000000010/000020/000030/000040",
+			"poolData":    "ns2",
+			"subject":     "a:b",
+			"poolLocator": "F1",
+			"signer":      "0x0",
+			"operator":    "0x0",
+			"approved":    true,
+			"blockchain":  blockchainInfo,
+		},
+	}.String()
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"2"},"event":"ack"}`, string(msg))
+
+	// token-burn: removed, unknown namespace
+	fromServer <- fftypes.JSONObject{
+		"id":    "3",
+		"event": "token-burn",
+		"data": fftypes.JSONObject{
+			"id":          "000000000010/000020/000030/000040",
+			"poolData":    "ns3",
+			"poolLocator": "F1",
+			"signer":      "0x0",
+			"from":        "0x1",
+			"amount":      "2",
+			"blockchain":  blockchainInfo,
+		},
+	}.String()
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"3"},"event":"ack"}`, string(msg))
+
+	// token-transfer: removed, no blockchain event info
+	fromServer <- fftypes.JSONObject{
+		"id":    "4",
+		"event": "token-transfer",
+		"data": fftypes.JSONObject{
+			"id":          "000000000010/000020/000030/000040",
+			"poolData":    "ns1",
+			"poolLocator": "F1",
+			"blockchain": fftypes.JSONObject{
+				"removed": true,
+			},
+		},
+	}.String()
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"4"},"event":"ack"}`, string(msg))
+
+	// token-mint: removed, no namespace delivers to all handlers
+	mcb1.On("TokenEventRemoved", h, mock.MatchedBy(removedEvent)).Return(nil).Once()
+	mcb2.On("TokenEventRemoved", h, mock.MatchedBy(removedEvent)).Return(nil).Once()
+	fromServer <- fftypes.JSONObject{
+		"id":    "5",
+		"event": "token-mint",
+		"data": fftypes.JSONObject{
+			"id":          "000000000010/000020/000030/000040",
+			"poolData":    "",
+			"poolLocator": "F1",
+			"signer":      "0x0",
+			"to":          "0x2",
+			"amount":      "2",
+			"blockchain":  blockchainInfo,
+		},
+	}.String()
+	msg = <-toServer
+	assert.Equal(t, `{"data":{"id":"5"},"event":"ack"}`, string(msg))
+
+	// token-transfer: removed, callback fail
+	errProcessed := make(chan struct{})
+	mcb1.On("TokenEventRemoved", h, mock.MatchedBy(removedEvent)).Return(fmt.Errorf("pop")).Once().Run(func(args mock.Arguments) {
+		// We do not ack in the case of an error
+		close(errProcessed)
+	})
+	fromServer <- fftypes.JSONObject{
+		"id":    "6",
+		"event": "token-transfer",
+		"data": fftypes.JSONObject{
+			"id":          "000000000010/000020/000030/000040",
+			"poolData":    "ns1",
+			"poolLocator": "F1",
+			"blockchain":  blockchainInfo,
+		},
+	}.String()
+	<-errProcessed
+
+	mcb1.AssertExpectations(t)
+	mcb2.AssertExpectations(t)
+}
+
 func TestEventLoopReceiveClosed(t *testing.T) {
 	wsm := &wsmocks.WSClient{}
 	called := false
@@ -1691,9 +2328,25 @@ func TestCallbacksWrongNamespace(t *testing.T) {
 	h, _, _, _, done := newTestFFTokens(t)
 	defer done()
 	nsOpID := "ns1:" + fftypes.NewUUID().String()
-	h.callbacks.OperationUpdate(context.Background(), nsOpID, core.OpStatusSucceeded, "tx123", "", nil)
+	h.callbacks.OperationUpdate(context.Background(), nsOpID, core.OpStatusSucceeded, "tx123", "", "", nil)
 	h.callbacks.TokensTransferred(context.Background(), "ns1", nil)
 	h.callbacks.TokensApproved(context.Background(), "ns1", nil)
+	h.callbacks.TokenEventRemoved(context.Background(), "ns1", nil)
+}
+
+func TestCallbacksTokenEventRemovedAllNamespacesFail(t *testing.T) {
+	h, _, _, _, done := newTestFFTokens(t)
+	defer done()
+
+	mcb := &tokenmocks.Callbacks{}
+	h.SetHandler("ns1", mcb)
+	event := &blockchain.Event{ProtocolID: "000000000010/000020/000030"}
+	mcb.On("TokenEventRemoved", h, event).Return(fmt.Errorf("pop"))
+
+	err := h.callbacks.TokenEventRemoved(context.Background(), "", event)
+	assert.EqualError(t, err, "pop")
+
+	mcb.AssertExpectations(t)
 }
 
 func TestCheckInterfaceBadFormat(t *testing.T) {
@@ -0,0 +1,164 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftokens
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var hederaEntityIDPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// HTS is the tokens plugin for the Hedera Token Service. It uses the same REST and websocket protocol
+// as the fftokens plugin, against a token connector for HTS, but checks that the accounts are Hedera
+// account IDs before calling the connector, and can associate the recipient with the token before
+// tokens are minted or transferred to it.
+//
+// HTS tokens are native to the network, rather than contracts, so custom contract interfaces and the
+// escrow used by token swaps are not supported.
+type HTS struct {
+	FFTokens
+	autoAssociate bool
+}
+
+type associateTokens struct {
+	PoolLocator string `json:"poolLocator"`
+	Account     string `json:"account"`
+	Signer      string `json:"signer"`
+}
+
+func (h *HTS) Name() string {
+	return "hts"
+}
+
+func (h *HTS) InitConfig(config config.Section) {
+	initConnectorConfig(config)
+	config.AddKnownKey(HTSAutoAssociate, false)
+}
+
+func (h *HTS) Init(ctx context.Context, cancelCtx context.CancelFunc, name string, config config.Section) (err error) {
+	if err = h.initConnector(ctx, cancelCtx, name, config); err != nil {
+		return err
+	}
+	h.callbacks.plugin = h
+	h.autoAssociate = config.GetBool(HTSAutoAssociate)
+	return nil
+}
+
+func (h *HTS) checkAccounts(ctx context.Context, accounts ...string) error {
+	for _, account := range accounts {
+		if !hederaEntityIDPattern.MatchString(account) {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidHederaAccountID, account)
+		}
+	}
+	return nil
+}
+
+// associate associates the recipient account with the token, which HTS requires before the account can hold it.
+// The connector must hold the key of the account, and accounts that are already associated are ignored.
+func (h *HTS) associate(ctx context.Context, poolLocator, account string) error {
+	if !h.autoAssociate {
+		return nil
+	}
+	var errRes tokenError
+	res, err := h.client.R().SetContext(ctx).
+		SetBody(&associateTokens{
+			PoolLocator: poolLocator,
+			Account:     account,
+			Signer:      account,
+		}).
+		SetError(&errRes).
+		Post("/api/v1/associate")
+	if err != nil || !res.IsSuccess() {
+		return wrapError(ctx, &errRes, res, err)
+	}
+	return nil
+}
+
+func (h *HTS) CreateTokenPool(ctx context.Context, nsOpID string, pool *core.TokenPool) (complete bool, err error) {
+	if err := h.checkAccounts(ctx, pool.Key); err != nil {
+		return false, err
+	}
+	return h.FFTokens.CreateTokenPool(ctx, nsOpID, pool)
+}
+
+func (h *HTS) CheckInterface(ctx context.Context, pool *core.TokenPool, methods []*fftypes.FFIMethod) (*fftypes.JSONAny, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgHTSUnsupported, "custom token interfaces")
+}
+
+func (h *HTS) MintTokens(ctx context.Context, nsOpID string, poolLocator string, mint *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	if err := h.checkAccounts(ctx, mint.Key, mint.To); err != nil {
+		return err
+	}
+	if err := h.associate(ctx, poolLocator, mint.To); err != nil {
+		return err
+	}
+	return h.FFTokens.MintTokens(ctx, nsOpID, poolLocator, mint, methods)
+}
+
+func (h *HTS) BurnTokens(ctx context.Context, nsOpID string, poolLocator string, burn *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	if err := h.checkAccounts(ctx, burn.Key, burn.From); err != nil {
+		return err
+	}
+	return h.FFTokens.BurnTokens(ctx, nsOpID, poolLocator, burn, methods)
+}
+
+func (h *HTS) TransferTokens(ctx context.Context, nsOpID string, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) error {
+	if err := h.checkAccounts(ctx, transfer.Key, transfer.From, transfer.To); err != nil {
+		return err
+	}
+	if err := h.associate(ctx, poolLocator, transfer.To); err != nil {
+		return err
+	}
+	return h.FFTokens.TransferTokens(ctx, nsOpID, poolLocator, transfer, methods)
+}
+
+func (h *HTS) TransferTokensBatch(ctx context.Context, nsOpID string, poolLocator string, transfers []*core.TokenTransfer, methods *fftypes.JSONAny) error {
+	first := transfers[0]
+	if err := h.checkAccounts(ctx, first.Key, first.From, first.To); err != nil {
+		return err
+	}
+	if err := h.associate(ctx, poolLocator, first.To); err != nil {
+		return err
+	}
+	return h.FFTokens.TransferTokensBatch(ctx, nsOpID, poolLocator, transfers, methods)
+}
+
+func (h *HTS) TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error {
+	if err := h.checkAccounts(ctx, approval.Key, approval.Operator); err != nil {
+		return err
+	}
+	return h.FFTokens.TokensApproval(ctx, nsOpID, poolLocator, approval, methods)
+}
+
+func (h *HTS) LockTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return i18n.NewError(ctx, coremsgs.MsgHTSUnsupported, "token swaps")
+}
+
+func (h *HTS) ClaimTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return i18n.NewError(ctx, coremsgs.MsgHTSUnsupported, "token swaps")
+}
+
+func (h *HTS) RefundTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	return i18n.NewError(ctx, coremsgs.MsgHTSUnsupported, "token swaps")
+}
@@ -0,0 +1,219 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fftokens
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var htsConfig = config.RootSection("hts")
+
+func newTestHTS(t *testing.T, autoAssociate bool) (h *HTS, httpURL string, done func()) {
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+
+	_, _, wsURL, cancel := wsclient.NewTestWSServer(nil)
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL = u.String()
+
+	coreconfig.Reset()
+	h = &HTS{}
+	h.InitConfig(htsConfig)
+
+	htsConfig.AddKnownKey(ffresty.HTTPConfigURL, httpURL)
+	htsConfig.AddKnownKey(ffresty.HTTPCustomClient, mockedClient)
+	htsConfig.Set(HTSAutoAssociate, autoAssociate)
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	err := h.Init(ctx, cancelCtx, "testhts", htsConfig)
+	assert.NoError(t, err)
+	return h, httpURL, func() {
+		cancel()
+		httpmock.DeactivateAndReset()
+	}
+}
+
+func newTestHTSTransfer() *core.TokenTransfer {
+	return &core.TokenTransfer{
+		From:   "0.0.1001",
+		To:     "0.0.1002",
+		Key:    "0.0.1001",
+		Amount: *fftypes.NewFFBigInt(10),
+		TX: core.TransactionRef{
+			ID:   fftypes.NewUUID(),
+			Type: core.TransactionTypeTokenTransfer,
+		},
+	}
+}
+
+func TestHTSInit(t *testing.T) {
+	h, _, done := newTestHTS(t, false)
+	defer done()
+	assert.Equal(t, "hts", h.Name())
+	assert.Equal(t, h, h.callbacks.plugin)
+	assert.False(t, h.autoAssociate)
+	assert.Nil(t, h.accountAbstraction)
+}
+
+func TestHTSInitBadURL(t *testing.T) {
+	coreconfig.Reset()
+	h := &HTS{}
+	h.InitConfig(htsConfig)
+	htsConfig.AddKnownKey(ffresty.HTTPConfigURL, "")
+
+	ctx, cancelCtx := context.WithCancel(context.Background())
+	err := h.Init(ctx, cancelCtx, "testhts", htsConfig)
+	assert.Regexp(t, "FF10138", err)
+}
+
+func TestHTSTokenOperations(t *testing.T) {
+	h, httpURL, done := newTestHTS(t, false)
+	defer done()
+
+	for _, path := range []string{"createpool", "mint", "burn", "transfer", "transferbatch", "approval"} {
+		httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/%s", httpURL, path),
+			httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"}))
+	}
+
+	transfer := newTestHTSTransfer()
+	nsOpID := "ns1:" + fftypes.NewUUID().String()
+	_, err := h.CreateTokenPool(context.Background(), nsOpID, &core.TokenPool{
+		Type: core.TokenTypeFungible,
+		Key:  "0.0.1001",
+		TX:   core.TransactionRef{ID: fftypes.NewUUID(), Type: core.TransactionTypeTokenPool},
+	})
+	assert.NoError(t, err)
+	err = h.MintTokens(context.Background(), nsOpID, "0.0.5005", transfer, nil)
+	assert.NoError(t, err)
+	err = h.BurnTokens(context.Background(), nsOpID, "0.0.5005", transfer, nil)
+	assert.NoError(t, err)
+	err = h.TransferTokens(context.Background(), nsOpID, "0.0.5005", transfer, nil)
+	assert.NoError(t, err)
+	err = h.TransferTokensBatch(context.Background(), nsOpID, "0.0.5005", []*core.TokenTransfer{transfer}, nil)
+	assert.NoError(t, err)
+	err = h.TokensApproval(context.Background(), nsOpID, "0.0.5005", &core.TokenApproval{
+		Key:      "0.0.1001",
+		Operator: "0.0.1003",
+		TX:       core.TransactionRef{ID: fftypes.NewUUID(), Type: core.TransactionTypeTokenApproval},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, httpmock.GetTotalCallCount())
+}
+
+func TestHTSBadAccounts(t *testing.T) {
+	h, _, done := newTestHTS(t, true)
+	defer done()
+
+	transfer := newTestHTSTransfer()
+	transfer.To = "0x123"
+	_, err := h.CreateTokenPool(context.Background(), "ns1:op1", &core.TokenPool{Key: "0x123"})
+	assert.Regexp(t, "FF10467.*0x123", err)
+	err = h.MintTokens(context.Background(), "ns1:op1", "0.0.5005", transfer, nil)
+	assert.Regexp(t, "FF10467.*0x123", err)
+	err = h.TransferTokens(context.Background(), "ns1:op1", "0.0.5005", transfer, nil)
+	assert.Regexp(t, "FF10467.*0x123", err)
+	err = h.TransferTokensBatch(context.Background(), "ns1:op1", "0.0.5005", []*core.TokenTransfer{transfer}, nil)
+	assert.Regexp(t, "FF10467.*0x123", err)
+	transfer.From = "0x456"
+	err = h.BurnTokens(context.Background(), "ns1:op1", "0.0.5005", transfer, nil)
+	assert.Regexp(t, "FF10467.*0x456", err)
+	err = h.TokensApproval(context.Background(), "ns1:op1", "0.0.5005", &core.TokenApproval{Key: "0.0.1001", Operator: "0x789"}, nil)
+	assert.Regexp(t, "FF10467.*0x789", err)
+	assert.Equal(t, 0, httpmock.GetTotalCallCount())
+}
+
+func TestHTSTransferAutoAssociate(t *testing.T) {
+	h, httpURL, done := newTestHTS(t, true)
+	defer done()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/associate", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body fftypes.JSONObject
+			err := json.NewDecoder(req.Body).Decode(&body)
+			assert.NoError(t, err)
+			assert.Equal(t, fftypes.JSONObject{
+				"poolLocator": "0.0.5005",
+				"account":     "0.0.1002",
+				"signer":      "0.0.1002",
+			}, body)
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{})(req)
+		})
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transfer", httpURL),
+		httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/mint", httpURL),
+		httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/transferbatch", httpURL),
+		httpmock.NewJsonResponderOrPanic(202, fftypes.JSONObject{"id": "1"}))
+
+	transfer := newTestHTSTransfer()
+	err := h.TransferTokens(context.Background(), "ns1:op1", "0.0.5005", transfer, nil)
+	assert.NoError(t, err)
+	err = h.MintTokens(context.Background(), "ns1:op2", "0.0.5005", transfer, nil)
+	assert.NoError(t, err)
+	err = h.TransferTokensBatch(context.Background(), "ns1:op3", "0.0.5005", []*core.TokenTransfer{transfer}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, httpmock.GetTotalCallCount())
+}
+
+func TestHTSAutoAssociateFail(t *testing.T) {
+	h, httpURL, done := newTestHTS(t, true)
+	defer done()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/api/v1/associate", httpURL),
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{"message": "not the key of the account"}))
+
+	transfer := newTestHTSTransfer()
+	err := h.TransferTokens(context.Background(), "ns1:op1", "0.0.5005", transfer, nil)
+	assert.Regexp(t, "FF10274.*not the key of the account", err)
+	err = h.MintTokens(context.Background(), "ns1:op1", "0.0.5005", transfer, nil)
+	assert.Regexp(t, "FF10274.*not the key of the account", err)
+	err = h.TransferTokensBatch(context.Background(), "ns1:op1", "0.0.5005", []*core.TokenTransfer{transfer}, nil)
+	assert.Regexp(t, "FF10274.*not the key of the account", err)
+	assert.Equal(t, 3, httpmock.GetTotalCallCount())
+}
+
+func TestHTSUnsupported(t *testing.T) {
+	h, _, done := newTestHTS(t, false)
+	defer done()
+
+	_, err := h.CheckInterface(context.Background(), &core.TokenPool{}, nil)
+	assert.Regexp(t, "FF10692.*interfaces", err)
+	swap := &core.TokenSwap{}
+	leg := &core.TokenSwapLeg{}
+	err = h.LockTokens(context.Background(), "ns1:op1", "0.0.5005", swap, leg)
+	assert.Regexp(t, "FF10692.*swaps", err)
+	err = h.ClaimTokens(context.Background(), "ns1:op1", "0.0.5005", swap, leg)
+	assert.Regexp(t, "FF10692.*swaps", err)
+	err = h.RefundTokens(context.Background(), "ns1:op1", "0.0.5005", swap, leg)
+	assert.Regexp(t, "FF10692.*swaps", err)
+}
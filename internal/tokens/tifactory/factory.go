@@ -29,6 +29,7 @@ import (
 
 var pluginsByName = map[string]func() tokens.Plugin{
 	(*fftokens.FFTokens)(nil).Name(): func() tokens.Plugin { return &fftokens.FFTokens{} },
+	(*fftokens.HTS)(nil).Name():      func() tokens.Plugin { return &fftokens.HTS{} },
 }
 
 func InitConfig(config config.ArraySection) {
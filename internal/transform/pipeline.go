@@ -0,0 +1,119 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/transform/trfactory"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/transform"
+)
+
+// Pipeline is the sequence of transforms configured for a namespace
+type Pipeline struct {
+	steps []*step
+}
+
+type step struct {
+	plugin    string
+	topics    map[string]bool
+	transform transform.Transform
+}
+
+// NewPipeline creates the transforms for each step configured on a namespace, validating their options
+func NewPipeline(ctx context.Context, steps []*core.TransformStep) (*Pipeline, error) {
+	p := &Pipeline{}
+	for _, s := range steps {
+		plugin, err := trfactory.GetPlugin(ctx, s.Plugin)
+		if err != nil {
+			return nil, err
+		}
+		t, err := plugin.NewTransform(ctx, s.Options)
+		if err != nil {
+			return nil, err
+		}
+		st := &step{plugin: s.Plugin, transform: t}
+		if len(s.Topics) > 0 {
+			st.topics = make(map[string]bool, len(s.Topics))
+			for _, topic := range s.Topics {
+				st.topics[topic] = true
+			}
+		}
+		p.steps = append(p.steps, st)
+	}
+	return p, nil
+}
+
+// AppliesTo returns true if the data of messages of this type are transformed. Only the data of application
+// messages is transformed - not definitions, group initialization or chunks.
+func AppliesTo(msgType core.MessageType) bool {
+	return msgType == core.MessageTypeBroadcast || msgType == core.MessageTypePrivate
+}
+
+func (s *step) matches(topics fftypes.FFStringArray) bool {
+	if s.topics == nil {
+		return true
+	}
+	if len(topics) == 0 {
+		return s.topics[core.DefaultTopic]
+	}
+	for _, topic := range topics {
+		if s.topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+// Send runs each step that applies to the topics of a message sent by this node, in order, on a new value of the message
+func (p *Pipeline) Send(ctx context.Context, topics fftypes.FFStringArray, idx int, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	var err error
+	for _, s := range p.steps {
+		if value == nil || !s.matches(topics) {
+			continue
+		}
+		if value, err = s.transform.Send(ctx, value); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTransformFailed, s.plugin, idx, err)
+		}
+	}
+	return value, nil
+}
+
+// Receive runs each step that applies to the topics of a message received from another node on one of its values.
+// The steps run in reverse order, so that each step receives the value in the form it would have sent it.
+func (p *Pipeline) Receive(ctx context.Context, topics fftypes.FFStringArray, idx int, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	var err error
+	for i := len(p.steps) - 1; i >= 0; i-- {
+		s := p.steps[i]
+		if value == nil || !s.matches(topics) {
+			continue
+		}
+		if value, err = s.transform.Receive(ctx, value); err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTransformFailed, s.plugin, idx, err)
+		}
+	}
+	return value, nil
+}
+
+// Enabled returns true if any steps are configured
+func (p *Pipeline) Enabled() bool {
+	return len(p.steps) > 0
+}
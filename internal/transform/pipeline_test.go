@@ -0,0 +1,124 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPipeline(t *testing.T) *Pipeline {
+	p, err := NewPipeline(context.Background(), []*core.TransformStep{
+		{
+			Plugin:  "upcast",
+			Options: fftypes.JSONObject{"rename": map[string]interface{}{"name": "fullName"}},
+		},
+		{
+			Plugin: "tokenize",
+			Topics: []string{"customers", core.DefaultTopic},
+			Options: fftypes.JSONObject{
+				"key":    "secret",
+				"fields": []interface{}{"fullName"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, p.Enabled())
+	return p
+}
+
+func TestPipelineSend(t *testing.T) {
+	p := newTestPipeline(t)
+	ctx := context.Background()
+
+	value, err := p.Send(ctx, fftypes.FFStringArray{"orders", "customers"}, 0, fftypes.JSONAnyPtr(`{"name":"Jane"}`))
+	assert.NoError(t, err)
+	assert.Regexp(t, "^tok_", value.JSONObject().GetString("fullName"))
+
+	value, err = p.Send(ctx, nil, 0, fftypes.JSONAnyPtr(`{"name":"Jane"}`))
+	assert.NoError(t, err)
+	assert.Regexp(t, "^tok_", value.JSONObject().GetString("fullName"))
+
+	value, err = p.Send(ctx, fftypes.FFStringArray{"orders"}, 0, fftypes.JSONAnyPtr(`{"name":"Jane"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", value.JSONObject().GetString("fullName"))
+
+	value, err = p.Send(ctx, fftypes.FFStringArray{"orders"}, 0, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+}
+
+func TestPipelineReceive(t *testing.T) {
+	p := newTestPipeline(t)
+
+	value, err := p.Receive(context.Background(), fftypes.FFStringArray{"customers"}, 0, fftypes.JSONAnyPtr(`{"name":"Jane"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "Jane", value.JSONObject().GetString("fullName"))
+}
+
+func TestPipelineEmpty(t *testing.T) {
+	p, err := NewPipeline(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.False(t, p.Enabled())
+
+	in := fftypes.JSONAnyPtr(`{"name":"Jane"}`)
+	value, err := p.Send(context.Background(), nil, 0, in)
+	assert.NoError(t, err)
+	assert.Equal(t, in, value)
+}
+
+func TestNewPipelineUnknownPlugin(t *testing.T) {
+	_, err := NewPipeline(context.Background(), []*core.TransformStep{{Plugin: "wrong"}})
+	assert.Regexp(t, "FF10665.*wrong", err)
+}
+
+func TestNewPipelineBadOptions(t *testing.T) {
+	_, err := NewPipeline(context.Background(), []*core.TransformStep{{Plugin: "tokenize"}})
+	assert.Regexp(t, "FF10666", err)
+}
+
+type failingTransform struct{}
+
+func (f *failingTransform) Send(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	return nil, assert.AnError
+}
+
+func (f *failingTransform) Receive(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	return nil, assert.AnError
+}
+
+func TestPipelineTransformFail(t *testing.T) {
+	p := &Pipeline{steps: []*step{{plugin: "failing", transform: &failingTransform{}}}}
+	ctx := context.Background()
+
+	_, err := p.Send(ctx, nil, 1, fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10667.*failing.*1", err)
+
+	_, err = p.Receive(ctx, nil, 2, fftypes.JSONAnyPtr(`{}`))
+	assert.Regexp(t, "FF10667.*failing.*2", err)
+}
+
+func TestAppliesTo(t *testing.T) {
+	assert.True(t, AppliesTo(core.MessageTypeBroadcast))
+	assert.True(t, AppliesTo(core.MessageTypePrivate))
+	assert.False(t, AppliesTo(core.MessageTypeDefinition))
+	assert.False(t, AppliesTo(core.MessageTypeChunk))
+}
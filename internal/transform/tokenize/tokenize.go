@@ -0,0 +1,101 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenize
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/transform"
+)
+
+// TokenPrefix is the prefix of the tokens that replace the values of tokenized fields
+const TokenPrefix = "tok_"
+
+// Tokenize replaces the values of personal data fields in messages sent by this node with tokens, so the
+// values are never shared with other nodes. Each token is an HMAC-SHA256 of the value, so equal values have
+// equal tokens, and the holder of the key can check a token against a value it already knows.
+type Tokenize struct{}
+
+type tokenizer struct {
+	key    []byte
+	fields []string
+}
+
+func (t *Tokenize) Name() string {
+	return "tokenize"
+}
+
+func (t *Tokenize) NewTransform(ctx context.Context, options fftypes.JSONObject) (transform.Transform, error) {
+	key := options.GetString("key")
+	fields, _ := options.GetStringArrayOk("fields")
+	if key == "" || len(fields) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTransformOptionsInvalid, t.Name(), "'key' and 'fields' are required")
+	}
+	return &tokenizer{key: []byte(key), fields: fields}, nil
+}
+
+func (tk *tokenizer) token(value interface{}) (string, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, tk.key)
+	mac.Write(b)
+	return TokenPrefix + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func (tk *tokenizer) Send(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	var obj map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(value.Bytes()))
+	d.UseNumber()
+	if err := d.Decode(&obj); err != nil || obj == nil {
+		// Only the fields of JSON objects are tokenized
+		return value, nil
+	}
+	changed := false
+	for _, field := range tk.fields {
+		if v, ok := obj[field]; ok && v != nil {
+			token, err := tk.token(v)
+			if err != nil {
+				return nil, err
+			}
+			obj[field] = token
+			changed = true
+		}
+	}
+	if !changed {
+		return value, nil
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return fftypes.JSONAnyPtrBytes(b), nil
+}
+
+func (tk *tokenizer) Receive(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	// Tokens cannot be reversed, so received data is stored as it was sent
+	return value, nil
+}
@@ -0,0 +1,78 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeFields(t *testing.T) {
+	ctx := context.Background()
+	tr, err := (&Tokenize{}).NewTransform(ctx, fftypes.JSONObject{
+		"key":    "secret",
+		"fields": []interface{}{"ssn", "email", "missing"},
+	})
+	assert.NoError(t, err)
+
+	value, err := tr.Send(ctx, fftypes.JSONAnyPtr(`{"ssn":"123-45-6789","email":"a@example.com","amount":12345678901234567890}`))
+	assert.NoError(t, err)
+	obj := value.JSONObject()
+	assert.Regexp(t, "^tok_[0-9a-f]{64}$", obj.GetString("ssn"))
+	assert.Regexp(t, "^tok_[0-9a-f]{64}$", obj.GetString("email"))
+	assert.NotEqual(t, obj.GetString("ssn"), obj.GetString("email"))
+	assert.Contains(t, value.String(), `"amount":12345678901234567890`)
+
+	// Equal values have equal tokens
+	value2, err := tr.Send(ctx, fftypes.JSONAnyPtr(`{"ssn":"123-45-6789"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, obj.GetString("ssn"), value2.JSONObject().GetString("ssn"))
+
+	received, err := tr.Receive(ctx, value)
+	assert.NoError(t, err)
+	assert.Equal(t, value, received)
+}
+
+func TestTokenizeUnchanged(t *testing.T) {
+	ctx := context.Background()
+	tr, err := (&Tokenize{}).NewTransform(ctx, fftypes.JSONObject{
+		"key":    "secret",
+		"fields": []interface{}{"ssn"},
+	})
+	assert.NoError(t, err)
+
+	for _, v := range []string{`"a string"`, `[1,2]`, `{ "other": 1 }`, `{"ssn":null}`} {
+		in := fftypes.JSONAnyPtr(v)
+		out, err := tr.Send(ctx, in)
+		assert.NoError(t, err)
+		assert.Equal(t, in, out)
+	}
+}
+
+func TestTokenizeBadOptions(t *testing.T) {
+	_, err := (&Tokenize{}).NewTransform(context.Background(), fftypes.JSONObject{"key": "secret"})
+	assert.Regexp(t, "FF10666.*tokenize", err)
+}
+
+func TestTokenizeMarshalFail(t *testing.T) {
+	tk := &tokenizer{key: []byte("secret")}
+	_, err := tk.token(map[bool]bool{true: true})
+	assert.Error(t, err)
+}
@@ -0,0 +1,48 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trfactory
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/transform/tokenize"
+	"github.com/hyperledger/firefly/internal/transform/upcast"
+	"github.com/hyperledger/firefly/pkg/transform"
+)
+
+var plugins = []transform.Plugin{
+	&tokenize.Tokenize{},
+	&upcast.Upcast{},
+}
+
+var pluginsByName = make(map[string]transform.Plugin)
+
+func init() {
+	for _, p := range plugins {
+		pluginsByName[p.Name()] = p
+	}
+}
+
+func GetPlugin(ctx context.Context, pluginType string) (transform.Plugin, error) {
+	plugin, ok := pluginsByName[pluginType]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnknownTransformPlugin, pluginType)
+	}
+	return plugin, nil
+}
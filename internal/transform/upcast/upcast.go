@@ -0,0 +1,107 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upcast
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/transform"
+)
+
+// Upcast brings data written to an older schema up to date, by renaming fields and adding default values for
+// missing fields. It runs on data sent by this node, and on data received from nodes that still send the
+// older schema.
+type Upcast struct{}
+
+type rename struct {
+	from string
+	to   string
+}
+
+type upcaster struct {
+	renames  []*rename
+	defaults map[string]interface{}
+}
+
+func (u *Upcast) Name() string {
+	return "upcast"
+}
+
+func (u *Upcast) NewTransform(ctx context.Context, options fftypes.JSONObject) (transform.Transform, error) {
+	uc := &upcaster{}
+	for from, to := range options.GetObject("rename") {
+		toStr, ok := to.(string)
+		if !ok || toStr == "" || toStr == from {
+			return nil, i18n.NewError(ctx, coremsgs.MsgTransformOptionsInvalid, u.Name(), "each 'rename' must map a field to a new field name")
+		}
+		uc.renames = append(uc.renames, &rename{from: from, to: toStr})
+	}
+	// Renames are applied in a fixed order, so the result does not depend on the order of the options
+	sort.Slice(uc.renames, func(i, j int) bool { return uc.renames[i].from < uc.renames[j].from })
+	uc.defaults = options.GetObject("defaults")
+	if len(uc.renames) == 0 && len(uc.defaults) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTransformOptionsInvalid, u.Name(), "at least one of 'rename' or 'defaults' is required")
+	}
+	return uc, nil
+}
+
+func (uc *upcaster) upcast(value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	var obj map[string]interface{}
+	d := json.NewDecoder(bytes.NewReader(value.Bytes()))
+	d.UseNumber()
+	if err := d.Decode(&obj); err != nil || obj == nil {
+		// Only JSON objects are upcast
+		return value, nil
+	}
+	changed := false
+	for _, r := range uc.renames {
+		v, hasFrom := obj[r.from]
+		if _, hasTo := obj[r.to]; hasFrom && !hasTo {
+			obj[r.to] = v
+			delete(obj, r.from)
+			changed = true
+		}
+	}
+	for field, v := range uc.defaults {
+		if _, ok := obj[field]; !ok {
+			obj[field] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return value, nil
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	return fftypes.JSONAnyPtrBytes(b), nil
+}
+
+func (uc *upcaster) Send(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	return uc.upcast(value)
+}
+
+func (uc *upcaster) Receive(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error) {
+	return uc.upcast(value)
+}
@@ -0,0 +1,68 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package upcast
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpcast(t *testing.T) {
+	ctx := context.Background()
+	tr, err := (&Upcast{}).NewTransform(ctx, fftypes.JSONObject{
+		"rename":   map[string]interface{}{"qty": "quantity", "ccy": "currency"},
+		"defaults": map[string]interface{}{"currency": "USD", "version": float64(2)},
+	})
+	assert.NoError(t, err)
+
+	value, err := tr.Send(ctx, fftypes.JSONAnyPtr(`{"qty":10,"price":1.50}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"quantity":10,"price":1.50,"currency":"USD","version":2}`, value.String())
+
+	value, err = tr.Receive(ctx, fftypes.JSONAnyPtr(`{"ccy":"GBP","quantity":1,"qty":2,"version":3}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"currency":"GBP","quantity":1,"qty":2,"version":3}`, value.String())
+
+	in := fftypes.JSONAnyPtr(`{"quantity":1, "currency":"EUR", "version":2}`)
+	value, err = tr.Send(ctx, in)
+	assert.NoError(t, err)
+	assert.Equal(t, in, value)
+
+	in = fftypes.JSONAnyPtr(`"not an object"`)
+	value, err = tr.Receive(ctx, in)
+	assert.NoError(t, err)
+	assert.Equal(t, in, value)
+}
+
+func TestUpcastBadOptions(t *testing.T) {
+	ctx := context.Background()
+	_, err := (&Upcast{}).NewTransform(ctx, fftypes.JSONObject{})
+	assert.Regexp(t, "FF10666.*upcast", err)
+
+	_, err = (&Upcast{}).NewTransform(ctx, fftypes.JSONObject{
+		"rename": map[string]interface{}{"qty": 1},
+	})
+	assert.Regexp(t, "FF10666.*upcast", err)
+
+	_, err = (&Upcast{}).NewTransform(ctx, fftypes.JSONObject{
+		"rename": map[string]interface{}{"qty": "qty"},
+	})
+	assert.Regexp(t, "FF10666.*upcast", err)
+}
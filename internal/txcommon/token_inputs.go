@@ -22,6 +22,7 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 )
 
@@ -53,6 +54,17 @@ func RetrieveTokenPoolActivateInputs(ctx context.Context, op *core.Operation) (*
 	return id, err
 }
 
+func AddTokenPoolMigrateInputs(op *core.Operation, migrationID *fftypes.UUID) {
+	op.Input = fftypes.JSONObject{
+		"migration": migrationID.String(),
+	}
+}
+
+func RetrieveTokenPoolMigrateInputs(ctx context.Context, op *core.Operation) (*fftypes.UUID, error) {
+	id, err := fftypes.ParseUUID(ctx, op.Input.GetString("migration"))
+	return id, err
+}
+
 func AddTokenTransferInputs(op *core.Operation, transfer *core.TokenTransfer) (err error) {
 	var transferJSON []byte
 	if transferJSON, err = json.Marshal(transfer); err == nil {
@@ -70,6 +82,48 @@ func RetrieveTokenTransferInputs(ctx context.Context, op *core.Operation) (*core
 	return &transfer, nil
 }
 
+type tokenTransferBatchInputs struct {
+	Transfers []*core.TokenTransfer `json:"transfers"`
+}
+
+func AddTokenTransferBatchInputs(op *core.Operation, transfers []*core.TokenTransfer) (err error) {
+	var batchJSON []byte
+	if batchJSON, err = json.Marshal(&tokenTransferBatchInputs{Transfers: transfers}); err == nil {
+		err = json.Unmarshal(batchJSON, &op.Input)
+	}
+	return err
+}
+
+func RetrieveTokenTransferBatchInputs(ctx context.Context, op *core.Operation) ([]*core.TokenTransfer, error) {
+	var batch tokenTransferBatchInputs
+	s := op.Input.String()
+	if err := json.Unmarshal([]byte(s), &batch); err != nil {
+		return nil, i18n.WrapError(ctx, err, i18n.MsgJSONObjectParseFailed, s)
+	}
+	if len(batch.Transfers) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTokenTransferBatchEmpty)
+	}
+	return batch.Transfers, nil
+}
+
+func AddTokenBulkMintInputs(op *core.Operation, bulkMintID *fftypes.UUID) {
+	op.Input = fftypes.JSONObject{
+		"bulkMint": bulkMintID.String(),
+	}
+}
+
+func AddTokenSwapInputs(op *core.Operation, swapID *fftypes.UUID, leg int) {
+	op.Input = fftypes.JSONObject{
+		"swap": swapID.String(),
+		"leg":  leg,
+	}
+}
+
+func RetrieveTokenSwapInputs(ctx context.Context, op *core.Operation) (*fftypes.UUID, int, error) {
+	id, err := fftypes.ParseUUID(ctx, op.Input.GetString("swap"))
+	return id, int(op.Input.GetInt64("leg")), err
+}
+
 func AddTokenApprovalInputs(op *core.Operation, approval *core.TokenApproval) (err error) {
 	var j []byte
 	if j, err = json.Marshal(approval); err == nil {
@@ -102,6 +102,35 @@ func TestRetrieveTokenPoolActivateInputs(t *testing.T) {
 	assert.Equal(t, *id, *poolID)
 }
 
+func TestAddTokenPoolMigrateInputs(t *testing.T) {
+	op := &core.Operation{}
+	migrationID := fftypes.NewUUID()
+
+	AddTokenPoolMigrateInputs(op, migrationID)
+	assert.Equal(t, migrationID.String(), op.Input.GetString("migration"))
+}
+
+func TestRetrieveTokenPoolMigrateInputs(t *testing.T) {
+	id := fftypes.NewUUID()
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"migration": id.String(),
+		},
+	}
+
+	migrationID, err := RetrieveTokenPoolMigrateInputs(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, *id, *migrationID)
+}
+
+func TestAddTokenBulkMintInputs(t *testing.T) {
+	op := &core.Operation{}
+	bulkMintID := fftypes.NewUUID()
+
+	AddTokenBulkMintInputs(op, bulkMintID)
+	assert.Equal(t, bulkMintID.String(), op.Input.GetString("bulkMint"))
+}
+
 func TestAddTokenTransferInputs(t *testing.T) {
 	op := &core.Operation{}
 	transfer := &core.TokenTransfer{
@@ -152,6 +181,80 @@ func TestRetrieveTokenTransferInputsBadID(t *testing.T) {
 	assert.Regexp(t, "FF00127", err)
 }
 
+func TestAddTokenTransferBatchInputs(t *testing.T) {
+	op := &core.Operation{}
+	transfer := &core.TokenTransfer{
+		LocalID:    fftypes.NewUUID(),
+		Type:       core.TokenTransferTypeTransfer,
+		TokenIndex: "1",
+		Amount:     *fftypes.NewFFBigInt(1),
+		TX: core.TransactionRef{
+			Type: core.TransactionTypeTokenTransfer,
+			ID:   fftypes.NewUUID(),
+		},
+	}
+
+	AddTokenTransferBatchInputs(op, []*core.TokenTransfer{transfer})
+	assert.Equal(t, fftypes.JSONObject{
+		"transfers": []interface{}{
+			map[string]interface{}{
+				"amount":     "1",
+				"localId":    transfer.LocalID.String(),
+				"tokenIndex": "1",
+				"tx": map[string]interface{}{
+					"id":   transfer.TX.ID.String(),
+					"type": "token_transfer",
+				},
+				"type": "transfer",
+			},
+		},
+	}, op.Input)
+}
+
+func TestRetrieveTokenTransferBatchInputs(t *testing.T) {
+	id := fftypes.NewUUID()
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"transfers": []interface{}{
+				map[string]interface{}{
+					"amount":  "1",
+					"localId": id.String(),
+				},
+			},
+		},
+	}
+
+	transfers, err := RetrieveTokenTransferBatchInputs(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 1)
+	assert.Equal(t, *id, *transfers[0].LocalID)
+	assert.Equal(t, int64(1), transfers[0].Amount.Int().Int64())
+}
+
+func TestRetrieveTokenTransferBatchInputsBadID(t *testing.T) {
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"transfers": []interface{}{
+				map[string]interface{}{
+					"localId": "bad",
+				},
+			},
+		},
+	}
+
+	_, err := RetrieveTokenTransferBatchInputs(context.Background(), op)
+	assert.Regexp(t, "FF00127", err)
+}
+
+func TestRetrieveTokenTransferBatchInputsEmpty(t *testing.T) {
+	op := &core.Operation{
+		Input: fftypes.JSONObject{},
+	}
+
+	_, err := RetrieveTokenTransferBatchInputs(context.Background(), op)
+	assert.Regexp(t, "FF10483", err)
+}
+
 func TestAddTokenApprovalInputs(t *testing.T) {
 	op := &core.Operation{}
 	approval := &core.TokenApproval{
@@ -204,3 +307,38 @@ func TestRetrieveTokenApprovalInputsBadID(t *testing.T) {
 	_, err := RetrieveTokenApprovalInputs(context.Background(), op)
 	assert.Regexp(t, "FF00127", err)
 }
+
+func TestAddTokenSwapInputs(t *testing.T) {
+	op := &core.Operation{}
+	swapID := fftypes.NewUUID()
+
+	AddTokenSwapInputs(op, swapID, 1)
+	assert.Equal(t, swapID.String(), op.Input.GetString("swap"))
+	assert.Equal(t, int64(1), op.Input.GetInt64("leg"))
+}
+
+func TestRetrieveTokenSwapInputs(t *testing.T) {
+	id := fftypes.NewUUID()
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"swap": id.String(),
+			"leg":  float64(1),
+		},
+	}
+
+	swapID, leg, err := RetrieveTokenSwapInputs(context.Background(), op)
+	assert.NoError(t, err)
+	assert.Equal(t, *id, *swapID)
+	assert.Equal(t, 1, leg)
+}
+
+func TestRetrieveTokenSwapInputsBadID(t *testing.T) {
+	op := &core.Operation{
+		Input: fftypes.JSONObject{
+			"swap": "bad",
+		},
+	}
+
+	_, _, err := RetrieveTokenSwapInputs(context.Background(), op)
+	assert.Regexp(t, "FF00138", err)
+}
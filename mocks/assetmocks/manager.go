@@ -10,9 +10,13 @@ import (
 
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
 
 	syncasync "github.com/hyperledger/firefly/internal/syncasync"
+
+	tokens "github.com/hyperledger/firefly/pkg/tokens"
 )
 
 // Manager is an autogenerated mock type for the Manager type
@@ -60,6 +64,58 @@ func (_m *Manager) BurnTokens(ctx context.Context, transfer *core.TokenTransferI
 	return r0, r1
 }
 
+// CheckTransferEligibility provides a mock function with given fields: ctx, transfer
+func (_m *Manager) CheckTransferEligibility(ctx context.Context, transfer *core.TokenTransferInput) (*core.TokenTransferEligibility, error) {
+	ret := _m.Called(ctx, transfer)
+
+	var r0 *core.TokenTransferEligibility
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferInput) (*core.TokenTransferEligibility, error)); ok {
+		return rf(ctx, transfer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferInput) *core.TokenTransferEligibility); ok {
+		r0 = rf(ctx, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferEligibility)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransferInput) error); ok {
+		r1 = rf(ctx, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteTokenPoolMigration provides a mock function with given fields: ctx, resolved
+func (_m *Manager) CompleteTokenPoolMigration(ctx context.Context, resolved *tokens.TokenPool) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, resolved)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *tokens.TokenPool) (*core.TokenPool, error)); ok {
+		return rf(ctx, resolved)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *tokens.TokenPool) *core.TokenPool); ok {
+		r0 = rf(ctx, resolved)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *tokens.TokenPool) error); ok {
+		r1 = rf(ctx, resolved)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateTokenPool provides a mock function with given fields: ctx, pool, waitConfirm
 func (_m *Manager) CreateTokenPool(ctx context.Context, pool *core.TokenPoolInput, waitConfirm bool) (*core.TokenPool, error) {
 	ret := _m.Called(ctx, pool, waitConfirm)
@@ -86,6 +142,58 @@ func (_m *Manager) CreateTokenPool(ctx context.Context, pool *core.TokenPoolInpu
 	return r0, r1
 }
 
+// CreateTokenPoolSnapshot provides a mock function with given fields: ctx, poolNameOrID, input
+func (_m *Manager) CreateTokenPoolSnapshot(ctx context.Context, poolNameOrID string, input *core.TokenSnapshotInput) (*core.TokenSnapshot, error) {
+	ret := _m.Called(ctx, poolNameOrID, input)
+
+	var r0 *core.TokenSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenSnapshotInput) (*core.TokenSnapshot, error)); ok {
+		return rf(ctx, poolNameOrID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenSnapshotInput) *core.TokenSnapshot); ok {
+		r0 = rf(ctx, poolNameOrID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.TokenSnapshotInput) error); ok {
+		r1 = rf(ctx, poolNameOrID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTokenSwap provides a mock function with given fields: ctx, input
+func (_m *Manager) CreateTokenSwap(ctx context.Context, input *core.TokenSwapInput) (*core.TokenSwap, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *core.TokenSwap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenSwapInput) (*core.TokenSwap, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenSwapInput) *core.TokenSwap); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenSwap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenSwapInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteTokenPool provides a mock function with given fields: ctx, poolNameOrID
 func (_m *Manager) DeleteTokenPool(ctx context.Context, poolNameOrID string) error {
 	ret := _m.Called(ctx, poolNameOrID)
@@ -100,6 +208,133 @@ func (_m *Manager) DeleteTokenPool(ctx context.Context, poolNameOrID string) err
 	return r0
 }
 
+// DeleteTokenTransferPolicy provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) DeleteTokenTransferPolicy(ctx context.Context, poolNameOrID string) error {
+	ret := _m.Called(ctx, poolNameOrID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, poolNameOrID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportTokenLedger provides a mock function with given fields: ctx, export
+func (_m *Manager) ExportTokenLedger(ctx context.Context, export *core.TokenLedgerExport) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, export)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenLedgerExport) (io.ReadCloser, error)); ok {
+		return rf(ctx, export)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenLedgerExport) io.ReadCloser); ok {
+		r0 = rf(ctx, export)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenLedgerExport) error); ok {
+		r1 = rf(ctx, export)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportTokenSnapshot provides a mock function with given fields: ctx, id
+func (_m *Manager) ExportTokenSnapshot(ctx context.Context, id string) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (io.ReadCloser, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) io.ReadCloser); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExportTokenTransfers provides a mock function with given fields: ctx, export
+func (_m *Manager) ExportTokenTransfers(ctx context.Context, export *core.TokenTransferExport) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, export)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferExport) (io.ReadCloser, error)); ok {
+		return rf(ctx, export)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferExport) io.ReadCloser); ok {
+		r0 = rf(ctx, export)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransferExport) error); ok {
+		r1 = rf(ctx, export)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenAccountActivity provides a mock function with given fields: ctx, key, filter
+func (_m *Manager) GetTokenAccountActivity(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenActivity, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, key, filter)
+
+	var r0 []*core.TokenActivity
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.TokenActivity, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, key, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.TokenActivity); ok {
+		r0 = rf(ctx, key, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenActivity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, key, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, key, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenAccountPools provides a mock function with given fields: ctx, key, filter
 func (_m *Manager) GetTokenAccountPools(ctx context.Context, key string, filter ffapi.AndFilter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, key, filter)
@@ -205,6 +440,76 @@ func (_m *Manager) GetTokenApprovals(ctx context.Context, filter ffapi.AndFilter
 	return r0, r1, r2
 }
 
+// GetTokenAssociatedAccounts provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenAssociatedAccounts(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenAssociatedAccount, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenAssociatedAccount
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenAssociatedAccount, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenAssociatedAccount); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenAssociatedAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenBalanceMismatches provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenBalanceMismatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenBalanceMismatch
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenBalanceMismatch); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalanceMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetTokenBalances provides a mock function with given fields: ctx, filter
 func (_m *Manager) GetTokenBalances(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -240,40 +545,588 @@ func (_m *Manager) GetTokenBalances(ctx context.Context, filter ffapi.AndFilter)
 	return r0, r1, r2
 }
 
-// GetTokenConnectors provides a mock function with given fields: ctx
-func (_m *Manager) GetTokenConnectors(ctx context.Context) []*core.TokenConnector {
-	ret := _m.Called(ctx)
+// GetTokenBulkMintByID provides a mock function with given fields: ctx, id
+func (_m *Manager) GetTokenBulkMintByID(ctx context.Context, id string) (*core.TokenBulkMint, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 []*core.TokenConnector
-	if rf, ok := ret.Get(0).(func(context.Context) []*core.TokenConnector); ok {
-		r0 = rf(ctx)
+	var r0 *core.TokenBulkMint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenBulkMint, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenBulkMint); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenConnector)
+			r0 = ret.Get(0).(*core.TokenBulkMint)
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// GetTokenPoolByID provides a mock function with given fields: ctx, id
-func (_m *Manager) GetTokenPoolByID(ctx context.Context, id *fftypes.UUID) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, id)
+// GetTokenBulkMints provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenBulkMints(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenBulkMint, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
 
-	var r0 *core.TokenPool
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) (*core.TokenPool, error)); ok {
-		return rf(ctx, id)
+	var r0 []*core.TokenBulkMint
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenBulkMint, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) *core.TokenPool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenBulkMint); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBulkMint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenConnectorBreaker provides a mock function with given fields: name
+func (_m *Manager) GetTokenConnectorBreaker(name string) *core.TokenConnectorBreaker {
+	ret := _m.Called(name)
+
+	var r0 *core.TokenConnectorBreaker
+	if rf, ok := ret.Get(0).(func(string) *core.TokenConnectorBreaker); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenConnectorBreaker)
+		}
+	}
+
+	return r0
+}
+
+// GetTokenConnectors provides a mock function with given fields: ctx
+func (_m *Manager) GetTokenConnectors(ctx context.Context) []*core.TokenConnector {
+	ret := _m.Called(ctx)
+
+	var r0 []*core.TokenConnector
+	if rf, ok := ret.Get(0).(func(context.Context) []*core.TokenConnector); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenConnector)
+		}
+	}
+
+	return r0
+}
+
+// GetTokenIndexOwnership provides a mock function with given fields: ctx, poolNameOrID, filter
+func (_m *Manager) GetTokenIndexOwnership(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, poolNameOrID, filter)
+
+	var r0 []*core.TokenIndexOwnership
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, poolNameOrID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.TokenIndexOwnership); ok {
+		r0 = rf(ctx, poolNameOrID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenIndexOwnership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, poolNameOrID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, poolNameOrID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenLedger provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenLedger(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenLedgerEntry, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenLedgerEntry
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenLedgerEntry, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenLedgerEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenLedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenMedia provides a mock function with given fields: ctx, metadataURI
+func (_m *Manager) GetTokenMedia(ctx context.Context, metadataURI string) (*core.TokenMedia, io.ReadCloser, error) {
+	ret := _m.Called(ctx, metadataURI)
+
+	var r0 *core.TokenMedia
+	var r1 io.ReadCloser
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenMedia, io.ReadCloser, error)); ok {
+		return rf(ctx, metadataURI)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenMedia); ok {
+		r0 = rf(ctx, metadataURI)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenMedia)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) io.ReadCloser); ok {
+		r1 = rf(ctx, metadataURI)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, metadataURI)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenPoolByID provides a mock function with given fields: ctx, id
+func (_m *Manager) GetTokenPoolByID(ctx context.Context, id *fftypes.UUID) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) (*core.TokenPool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) *core.TokenPool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPoolByLocator provides a mock function with given fields: ctx, connector, poolLocator
+func (_m *Manager) GetTokenPoolByLocator(ctx context.Context, connector string, poolLocator string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, connector, poolLocator)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, connector, poolLocator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenPool); ok {
+		r0 = rf(ctx, connector, poolLocator)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, connector, poolLocator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPoolByNameOrID provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) GetTokenPoolByNameOrID(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, poolNameOrID)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, poolNameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenPool); ok {
+		r0 = rf(ctx, poolNameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, poolNameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPoolDetails provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) GetTokenPoolDetails(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, poolNameOrID)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, poolNameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenPool); ok {
+		r0 = rf(ctx, poolNameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, poolNameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPoolHolders provides a mock function with given fields: ctx, poolNameOrID, filter
+func (_m *Manager) GetTokenPoolHolders(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, poolNameOrID, filter)
+
+	var r0 []*core.TokenPoolHolder
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, poolNameOrID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.TokenPoolHolder); ok {
+		r0 = rf(ctx, poolNameOrID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenPoolHolder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, poolNameOrID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, poolNameOrID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenPoolMigrations provides a mock function with given fields: ctx, poolNameOrID, filter
+func (_m *Manager) GetTokenPoolMigrations(ctx context.Context, poolNameOrID string, filter ffapi.AndFilter) ([]*core.TokenPoolMigration, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, poolNameOrID, filter)
+
+	var r0 []*core.TokenPoolMigration
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.TokenPoolMigration, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, poolNameOrID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.TokenPoolMigration); ok {
+		r0 = rf(ctx, poolNameOrID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenPoolMigration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, poolNameOrID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, poolNameOrID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenPools provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenPools(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenPool, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenPool
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenPool, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenPool); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenSnapshotBalances provides a mock function with given fields: ctx, id, filter
+func (_m *Manager) GetTokenSnapshotBalances(ctx context.Context, id string, filter ffapi.AndFilter) ([]*core.TokenSnapshotBalance, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, id, filter)
+
+	var r0 []*core.TokenSnapshotBalance
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) ([]*core.TokenSnapshotBalance, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, id, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.AndFilter) []*core.TokenSnapshotBalance); ok {
+		r0 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenSnapshotBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, id, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, id, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenSnapshotByID provides a mock function with given fields: ctx, id
+func (_m *Manager) GetTokenSnapshotByID(ctx context.Context, id string) (*core.TokenSnapshot, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.TokenSnapshot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenSnapshot, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenSnapshot); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenSnapshots provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenSnapshots(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenSnapshot, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenSnapshot
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenSnapshot, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenSnapshot); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenSnapshot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenSwapByID provides a mock function with given fields: ctx, id
+func (_m *Manager) GetTokenSwapByID(ctx context.Context, id string) (*core.TokenSwap, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.TokenSwap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenSwap, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenSwap); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenSwap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenSwaps provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenSwaps(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenSwap, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TokenSwap
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenSwap, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenSwap); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenSwap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenTransferByID provides a mock function with given fields: ctx, id
+func (_m *Manager) GetTokenTransferByID(ctx context.Context, id string) (*core.TokenTransfer, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.TokenTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenTransfer, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenTransfer); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenPool)
+			r0 = ret.Get(0).(*core.TokenTransfer)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
 		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
@@ -282,25 +1135,25 @@ func (_m *Manager) GetTokenPoolByID(ctx context.Context, id *fftypes.UUID) (*cor
 	return r0, r1
 }
 
-// GetTokenPoolByLocator provides a mock function with given fields: ctx, connector, poolLocator
-func (_m *Manager) GetTokenPoolByLocator(ctx context.Context, connector string, poolLocator string) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, connector, poolLocator)
+// GetTokenTransferPolicy provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) GetTokenTransferPolicy(ctx context.Context, poolNameOrID string) (*core.TokenTransferPolicy, error) {
+	ret := _m.Called(ctx, poolNameOrID)
 
-	var r0 *core.TokenPool
+	var r0 *core.TokenTransferPolicy
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenPool, error)); ok {
-		return rf(ctx, connector, poolLocator)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenTransferPolicy, error)); ok {
+		return rf(ctx, poolNameOrID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenPool); ok {
-		r0 = rf(ctx, connector, poolLocator)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenTransferPolicy); ok {
+		r0 = rf(ctx, poolNameOrID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenPool)
+			r0 = ret.Get(0).(*core.TokenTransferPolicy)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, connector, poolLocator)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, poolNameOrID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -308,25 +1161,25 @@ func (_m *Manager) GetTokenPoolByLocator(ctx context.Context, connector string,
 	return r0, r1
 }
 
-// GetTokenPoolByNameOrID provides a mock function with given fields: ctx, poolNameOrID
-func (_m *Manager) GetTokenPoolByNameOrID(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, poolNameOrID)
+// GetTokenTransferRequestByID provides a mock function with given fields: ctx, id
+func (_m *Manager) GetTokenTransferRequestByID(ctx context.Context, id string) (*core.TokenTransferRequest, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 *core.TokenPool
+	var r0 *core.TokenTransferRequest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenPool, error)); ok {
-		return rf(ctx, poolNameOrID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenTransferRequest, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenPool); ok {
-		r0 = rf(ctx, poolNameOrID)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenTransferRequest); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenPool)
+			r0 = ret.Get(0).(*core.TokenTransferRequest)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, poolNameOrID)
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -334,21 +1187,21 @@ func (_m *Manager) GetTokenPoolByNameOrID(ctx context.Context, poolNameOrID stri
 	return r0, r1
 }
 
-// GetTokenPools provides a mock function with given fields: ctx, filter
-func (_m *Manager) GetTokenPools(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenPool, *ffapi.FilterResult, error) {
+// GetTokenTransferRequests provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetTokenTransferRequests(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransferRequest, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
 
-	var r0 []*core.TokenPool
+	var r0 []*core.TokenTransferRequest
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenPool, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TokenTransferRequest, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenPool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TokenTransferRequest); ok {
 		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenPool)
+			r0 = ret.Get(0).([]*core.TokenTransferRequest)
 		}
 	}
 
@@ -369,32 +1222,6 @@ func (_m *Manager) GetTokenPools(ctx context.Context, filter ffapi.AndFilter) ([
 	return r0, r1, r2
 }
 
-// GetTokenTransferByID provides a mock function with given fields: ctx, id
-func (_m *Manager) GetTokenTransferByID(ctx context.Context, id string) (*core.TokenTransfer, error) {
-	ret := _m.Called(ctx, id)
-
-	var r0 *core.TokenTransfer
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenTransfer, error)); ok {
-		return rf(ctx, id)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenTransfer); ok {
-		r0 = rf(ctx, id)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenTransfer)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
-	} else {
-		r1 = ret.Error(1)
-	}
-
-	return r0, r1
-}
-
 // GetTokenTransfers provides a mock function with given fields: ctx, filter
 func (_m *Manager) GetTokenTransfers(ctx context.Context, filter ffapi.AndFilter) ([]*core.TokenTransfer, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -430,6 +1257,46 @@ func (_m *Manager) GetTokenTransfers(ctx context.Context, filter ffapi.AndFilter
 	return r0, r1, r2
 }
 
+// MediaProxyEnabled provides a mock function with given fields:
+func (_m *Manager) MediaProxyEnabled() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MigrateTokenPool provides a mock function with given fields: ctx, poolNameOrID, input
+func (_m *Manager) MigrateTokenPool(ctx context.Context, poolNameOrID string, input *core.TokenPoolMigrationInput) (*core.TokenPoolMigration, error) {
+	ret := _m.Called(ctx, poolNameOrID, input)
+
+	var r0 *core.TokenPoolMigration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenPoolMigrationInput) (*core.TokenPoolMigration, error)); ok {
+		return rf(ctx, poolNameOrID, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenPoolMigrationInput) *core.TokenPoolMigration); ok {
+		r0 = rf(ctx, poolNameOrID, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPoolMigration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.TokenPoolMigrationInput) error); ok {
+		r1 = rf(ctx, poolNameOrID, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // MintTokens provides a mock function with given fields: ctx, transfer, waitConfirm
 func (_m *Manager) MintTokens(ctx context.Context, transfer *core.TokenTransferInput, waitConfirm bool) (*core.TokenTransfer, error) {
 	ret := _m.Called(ctx, transfer, waitConfirm)
@@ -456,6 +1323,32 @@ func (_m *Manager) MintTokens(ctx context.Context, transfer *core.TokenTransferI
 	return r0, r1
 }
 
+// MintTokensBulk provides a mock function with given fields: ctx, input
+func (_m *Manager) MintTokensBulk(ctx context.Context, input *core.TokenBulkMintInput) (*core.TokenBulkMint, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *core.TokenBulkMint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenBulkMintInput) (*core.TokenBulkMint, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenBulkMintInput) *core.TokenBulkMint); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenBulkMint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenBulkMintInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Name provides a mock function with given fields:
 func (_m *Manager) Name() string {
 	ret := _m.Called()
@@ -502,6 +1395,32 @@ func (_m *Manager) NewTransfer(transfer *core.TokenTransferInput) syncasync.Send
 	return r0
 }
 
+// PauseTokenPool provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) PauseTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, poolNameOrID)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, poolNameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenPool); ok {
+		r0 = rf(ctx, poolNameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, poolNameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PrepareOperation provides a mock function with given fields: ctx, op
 func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	ret := _m.Called(ctx, op)
@@ -528,6 +1447,32 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
+// ReconcileTokenPool provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) ReconcileTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenReconciliation, error) {
+	ret := _m.Called(ctx, poolNameOrID)
+
+	var r0 *core.TokenReconciliation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenReconciliation, error)); ok {
+		return rf(ctx, poolNameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenReconciliation); ok {
+		r0 = rf(ctx, poolNameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenReconciliation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, poolNameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResolvePoolMethods provides a mock function with given fields: ctx, pool
 func (_m *Manager) ResolvePoolMethods(ctx context.Context, pool *core.TokenPool) error {
 	ret := _m.Called(ctx, pool)
@@ -542,6 +1487,58 @@ func (_m *Manager) ResolvePoolMethods(ctx context.Context, pool *core.TokenPool)
 	return r0
 }
 
+// ResumeTokenPool provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) ResumeTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, poolNameOrID)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, poolNameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenPool); ok {
+		r0 = rf(ctx, poolNameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, poolNameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetireTokenPool provides a mock function with given fields: ctx, poolNameOrID
+func (_m *Manager) RetireTokenPool(ctx context.Context, poolNameOrID string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, poolNameOrID)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, poolNameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.TokenPool); ok {
+		r0 = rf(ctx, poolNameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, poolNameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RunOperation provides a mock function with given fields: ctx, op
 func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation) (fftypes.JSONObject, bool, error) {
 	ret := _m.Called(ctx, op)
@@ -575,6 +1572,72 @@ func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation)
 	return r0, r1, r2
 }
 
+// SetTokenTransferPolicy provides a mock function with given fields: ctx, poolNameOrID, policy
+func (_m *Manager) SetTokenTransferPolicy(ctx context.Context, poolNameOrID string, policy *core.TokenTransferPolicy) (*core.TokenTransferPolicy, error) {
+	ret := _m.Called(ctx, poolNameOrID, policy)
+
+	var r0 *core.TokenTransferPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenTransferPolicy) (*core.TokenTransferPolicy, error)); ok {
+		return rf(ctx, poolNameOrID, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenTransferPolicy) *core.TokenTransferPolicy); ok {
+		r0 = rf(ctx, poolNameOrID, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.TokenTransferPolicy) error); ok {
+		r1 = rf(ctx, poolNameOrID, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SignoffTokenTransferRequest provides a mock function with given fields: ctx, id, principal, signer
+func (_m *Manager) SignoffTokenTransferRequest(ctx context.Context, id string, principal string, signer *core.SignerRef) (*core.TokenTransferRequest, error) {
+	ret := _m.Called(ctx, id, principal, signer)
+
+	var r0 *core.TokenTransferRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.SignerRef) (*core.TokenTransferRequest, error)); ok {
+		return rf(ctx, id, principal, signer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.SignerRef) *core.TokenTransferRequest); ok {
+		r0 = rf(ctx, id, principal, signer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, *core.SignerRef) error); ok {
+		r1 = rf(ctx, id, principal, signer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Start provides a mock function with given fields:
+func (_m *Manager) Start() error {
+	ret := _m.Called()
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TokenApproval provides a mock function with given fields: ctx, approval, waitConfirm
 func (_m *Manager) TokenApproval(ctx context.Context, approval *core.TokenApprovalInput, waitConfirm bool) (*core.TokenApproval, error) {
 	ret := _m.Called(ctx, approval, waitConfirm)
@@ -627,6 +1690,37 @@ func (_m *Manager) TransferTokens(ctx context.Context, transfer *core.TokenTrans
 	return r0, r1
 }
 
+// TransferTokensBatch provides a mock function with given fields: ctx, batch, waitConfirm
+func (_m *Manager) TransferTokensBatch(ctx context.Context, batch *core.TokenTransferBatchInput, waitConfirm bool) (*core.TokenTransferBatch, error) {
+	ret := _m.Called(ctx, batch, waitConfirm)
+
+	var r0 *core.TokenTransferBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput, bool) (*core.TokenTransferBatch, error)); ok {
+		return rf(ctx, batch, waitConfirm)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferBatchInput, bool) *core.TokenTransferBatch); ok {
+		r0 = rf(ctx, batch, waitConfirm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransferBatchInput, bool) error); ok {
+		r1 = rf(ctx, batch, waitConfirm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WaitStop provides a mock function with given fields:
+func (_m *Manager) WaitStop() {
+	_m.Called()
+}
+
 type mockConstructorTestingTNewManager interface {
 	mock.TestingT
 	Cleanup(func())
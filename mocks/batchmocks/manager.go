@@ -7,6 +7,10 @@ import (
 
 	batch "github.com/hyperledger/firefly/internal/batch"
 
+	core "github.com/hyperledger/firefly/pkg/core"
+
+	ffapi "github.com/hyperledger/firefly-common/pkg/ffapi"
+
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 
 	mock "github.com/stretchr/testify/mock"
@@ -17,11 +21,281 @@ type Manager struct {
 	mock.Mock
 }
 
+// CancelScheduledMessage provides a mock function with given fields: ctx, id
+func (_m *Manager) CancelScheduledMessage(ctx context.Context, id string) (*core.Message, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Message, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Message); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CheckBackpressure provides a mock function with given fields: ctx, topics
+func (_m *Manager) CheckBackpressure(ctx context.Context, topics []string) error {
+	ret := _m.Called(ctx, topics)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, topics)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *Manager) Close() {
 	_m.Called()
 }
 
+// CommitDraftMessage provides a mock function with given fields: ctx, id
+func (_m *Manager) CommitDraftMessage(ctx context.Context, id string) (*core.Message, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Message, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Message); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteBatchPolicy provides a mock function with given fields: ctx, topic
+func (_m *Manager) DeleteBatchPolicy(ctx context.Context, topic string) error {
+	ret := _m.Called(ctx, topic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, topic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DiscardDraftMessage provides a mock function with given fields: ctx, id
+func (_m *Manager) DiscardDraftMessage(ctx context.Context, id string) (*core.Message, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Message, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Message); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FlushBatches provides a mock function with given fields: ctx, req
+func (_m *Manager) FlushBatches(ctx context.Context, req *batch.FlushRequest) (*batch.FlushResult, error) {
+	ret := _m.Called(ctx, req)
+
+	var r0 *batch.FlushResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *batch.FlushRequest) (*batch.FlushResult, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *batch.FlushRequest) *batch.FlushResult); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*batch.FlushResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *batch.FlushRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatchPolicies provides a mock function with given fields: ctx
+func (_m *Manager) GetBatchPolicies(ctx context.Context) ([]*core.BatchPolicy, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*core.BatchPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*core.BatchPolicy, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*core.BatchPolicy); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BatchPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatchPolicy provides a mock function with given fields: ctx, topic
+func (_m *Manager) GetBatchPolicy(ctx context.Context, topic string) (*core.BatchPolicy, error) {
+	ret := _m.Called(ctx, topic)
+
+	var r0 *core.BatchPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.BatchPolicy, error)); ok {
+		return rf(ctx, topic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.BatchPolicy); ok {
+		r0 = rf(ctx, topic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BatchPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, topic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDraftMessages provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetDraftMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Message); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetQueueStatus provides a mock function with given fields:
+func (_m *Manager) GetQueueStatus() *batch.QueueStatus {
+	ret := _m.Called()
+
+	var r0 *batch.QueueStatus
+	if rf, ok := ret.Get(0).(func() *batch.QueueStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*batch.QueueStatus)
+		}
+	}
+
+	return r0
+}
+
+// GetScheduledMessages provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetScheduledMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Message); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // LoadContexts provides a mock function with given fields: ctx, payload
 func (_m *Manager) LoadContexts(ctx context.Context, payload *batch.DispatchPayload) error {
 	ret := _m.Called(ctx, payload)
@@ -57,6 +331,32 @@ func (_m *Manager) RegisterDispatcher(name string, txType fftypes.FFEnum, msgTyp
 	_m.Called(name, txType, msgTypes, handler, batchOptions)
 }
 
+// SetBatchPolicy provides a mock function with given fields: ctx, topic, policy
+func (_m *Manager) SetBatchPolicy(ctx context.Context, topic string, policy *core.BatchPolicy) (*core.BatchPolicy, error) {
+	ret := _m.Called(ctx, topic, policy)
+
+	var r0 *core.BatchPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.BatchPolicy) (*core.BatchPolicy, error)); ok {
+		return rf(ctx, topic, policy)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.BatchPolicy) *core.BatchPolicy); ok {
+		r0 = rf(ctx, topic, policy)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BatchPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.BatchPolicy) error); ok {
+		r1 = rf(ctx, topic, policy)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Manager) Start() error {
 	ret := _m.Called()
@@ -252,6 +252,32 @@ func (_m *Plugin) GetFFIParamValidator(ctx context.Context) (fftypes.FFIParamVal
 	return r0, r1
 }
 
+// GetNetworkStatus provides a mock function with given fields: ctx
+func (_m *Plugin) GetNetworkStatus(ctx context.Context) (*core.BlockchainNetworkStatus, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *core.BlockchainNetworkStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.BlockchainNetworkStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.BlockchainNetworkStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlockchainNetworkStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNetworkVersion provides a mock function with given fields: ctx, location
 func (_m *Plugin) GetNetworkVersion(ctx context.Context, location *fftypes.JSONAny) (int, error) {
 	ret := _m.Called(ctx, location)
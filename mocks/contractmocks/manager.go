@@ -631,6 +631,32 @@ func (_m *Manager) Name() string {
 	return r0
 }
 
+// NewInvokeOperation provides a mock function with given fields: txid, opType, req
+func (_m *Manager) NewInvokeOperation(txid *fftypes.UUID, opType fftypes.FFEnum, req *core.ContractCallRequest) (*core.Operation, error) {
+	ret := _m.Called(txid, opType, req)
+
+	var r0 *core.Operation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*fftypes.UUID, fftypes.FFEnum, *core.ContractCallRequest) (*core.Operation, error)); ok {
+		return rf(txid, opType, req)
+	}
+	if rf, ok := ret.Get(0).(func(*fftypes.UUID, fftypes.FFEnum, *core.ContractCallRequest) *core.Operation); ok {
+		r0 = rf(txid, opType, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*fftypes.UUID, fftypes.FFEnum, *core.ContractCallRequest) error); ok {
+		r1 = rf(txid, opType, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PrepareOperation provides a mock function with given fields: ctx, op
 func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error) {
 	ret := _m.Called(ctx, op)
@@ -699,6 +725,20 @@ func (_m *Manager) ResolveFFIReference(ctx context.Context, ref *fftypes.FFIRefe
 	return r0
 }
 
+// ResolveInvokeContractRequest provides a mock function with given fields: ctx, req
+func (_m *Manager) ResolveInvokeContractRequest(ctx context.Context, req *core.ContractCallRequest) error {
+	ret := _m.Called(ctx, req)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.ContractCallRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RunOperation provides a mock function with given fields: ctx, op
 func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation) (fftypes.JSONObject, bool, error) {
 	ret := _m.Called(ctx, op)
@@ -39,6 +39,20 @@ func (_m *Plugin) Capabilities() *database.Capabilities {
 	return r0
 }
 
+// DeleteBatchPolicy provides a mock function with given fields: ctx, namespace, topic
+func (_m *Plugin) DeleteBatchPolicy(ctx context.Context, namespace string, topic string) error {
+	ret := _m.Called(ctx, namespace, topic)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, namespace, topic)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteBlob provides a mock function with given fields: ctx, sequence
 func (_m *Plugin) DeleteBlob(ctx context.Context, sequence int64) error {
 	ret := _m.Called(ctx, sequence)
@@ -53,6 +67,20 @@ func (_m *Plugin) DeleteBlob(ctx context.Context, sequence int64) error {
 	return r0
 }
 
+// DeleteBlobUpload provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteContractAPI provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteContractAPI(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -95,6 +123,20 @@ func (_m *Plugin) DeleteData(ctx context.Context, namespace string, id *fftypes.
 	return r0
 }
 
+// DeleteEventRuleByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteEventRuleByID(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteFFI provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteFFI(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -137,6 +179,20 @@ func (_m *Plugin) DeleteOffset(ctx context.Context, t fftypes.FFEnum, name strin
 	return r0
 }
 
+// DeleteRoleBindingByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteRoleBindingByID(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSubscriptionByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteSubscriptionByID(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -151,6 +207,20 @@ func (_m *Plugin) DeleteSubscriptionByID(ctx context.Context, namespace string,
 	return r0
 }
 
+// DeleteSubscriptionTemplateByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteSubscriptionTemplateByID(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTokenApprovals provides a mock function with given fields: ctx, namespace, poolID
 func (_m *Plugin) DeleteTokenApprovals(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, poolID)
@@ -165,6 +235,34 @@ func (_m *Plugin) DeleteTokenApprovals(ctx context.Context, namespace string, po
 	return r0
 }
 
+// DeleteTokenBalanceMismatch provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteTokenBalanceMismatch(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTokenBalanceMismatches provides a mock function with given fields: ctx, namespace, poolID
+func (_m *Plugin) DeleteTokenBalanceMismatches(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, poolID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTokenBalances provides a mock function with given fields: ctx, namespace, poolID
 func (_m *Plugin) DeleteTokenBalances(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, poolID)
@@ -193,6 +291,20 @@ func (_m *Plugin) DeleteTokenPool(ctx context.Context, namespace string, id *fft
 	return r0
 }
 
+// DeleteTokenTransferPolicy provides a mock function with given fields: ctx, namespace, poolID
+func (_m *Plugin) DeleteTokenTransferPolicy(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, poolID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, poolID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTokenTransfers provides a mock function with given fields: ctx, namespace, poolID
 func (_m *Plugin) DeleteTokenTransfers(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, poolID)
@@ -207,6 +319,41 @@ func (_m *Plugin) DeleteTokenTransfers(ctx context.Context, namespace string, po
 	return r0
 }
 
+// GetAuditLog provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetAuditLog(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.AuditLogEntry, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.AuditLogEntry
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.AuditLogEntry, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.AuditLogEntry); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.AuditLogEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) GetBatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BatchPersisted, error) {
 	ret := _m.Called(ctx, namespace, id)
@@ -285,6 +432,58 @@ func (_m *Plugin) GetBatchIDsForMessages(ctx context.Context, namespace string,
 	return r0, r1
 }
 
+// GetBatchPolicies provides a mock function with given fields: ctx, namespace
+func (_m *Plugin) GetBatchPolicies(ctx context.Context, namespace string) ([]*core.BatchPolicy, error) {
+	ret := _m.Called(ctx, namespace)
+
+	var r0 []*core.BatchPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*core.BatchPolicy, error)); ok {
+		return rf(ctx, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*core.BatchPolicy); ok {
+		r0 = rf(ctx, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BatchPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatchPolicy provides a mock function with given fields: ctx, namespace, topic
+func (_m *Plugin) GetBatchPolicy(ctx context.Context, namespace string, topic string) (*core.BatchPolicy, error) {
+	ret := _m.Called(ctx, namespace, topic)
+
+	var r0 *core.BatchPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.BatchPolicy, error)); ok {
+		return rf(ctx, namespace, topic)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.BatchPolicy); ok {
+		r0 = rf(ctx, namespace, topic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BatchPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, topic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBatches provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetBatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BatchPersisted, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -320,6 +519,67 @@ func (_m *Plugin) GetBatches(ctx context.Context, namespace string, filter ffapi
 	return r0, r1, r2
 }
 
+// GetBlobUploadByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetBlobUploadByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.BlobUpload, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.BlobUpload); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlobUploads provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetBlobUploads(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlobUpload, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.BlobUpload
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.BlobUpload, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.BlobUpload); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetBlobs provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetBlobs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Blob, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -903,20 +1163,20 @@ func (_m *Plugin) GetDatatypes(ctx context.Context, namespace string, filter ffa
 	return r0, r1, r2
 }
 
-// GetEventByID provides a mock function with given fields: ctx, namespace, id
-func (_m *Plugin) GetEventByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Event, error) {
+// GetDeadLetterByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DeadLetter, error) {
 	ret := _m.Called(ctx, namespace, id)
 
-	var r0 *core.Event
+	var r0 *core.DeadLetter
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Event, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.DeadLetter, error)); ok {
 		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Event); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.DeadLetter); ok {
 		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Event)
+			r0 = ret.Get(0).(*core.DeadLetter)
 		}
 	}
 
@@ -929,21 +1189,21 @@ func (_m *Plugin) GetEventByID(ctx context.Context, namespace string, id *fftype
 	return r0, r1
 }
 
-// GetEvents provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetEvents(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Event, *ffapi.FilterResult, error) {
+// GetDeadLetters provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.Event
+	var r0 []*core.DeadLetter
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.Event, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.Event); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.DeadLetter); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.Event)
+			r0 = ret.Get(0).([]*core.DeadLetter)
 		}
 	}
 
@@ -964,25 +1224,25 @@ func (_m *Plugin) GetEvents(ctx context.Context, namespace string, filter ffapi.
 	return r0, r1, r2
 }
 
-// GetFFI provides a mock function with given fields: ctx, namespace, name, version
-func (_m *Plugin) GetFFI(ctx context.Context, namespace string, name string, version string) (*fftypes.FFI, error) {
-	ret := _m.Called(ctx, namespace, name, version)
+// GetEventByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetEventByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Event, error) {
+	ret := _m.Called(ctx, namespace, id)
 
-	var r0 *fftypes.FFI
+	var r0 *core.Event
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*fftypes.FFI, error)); ok {
-		return rf(ctx, namespace, name, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Event, error)); ok {
+		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.FFI); ok {
-		r0 = rf(ctx, namespace, name, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Event); ok {
+		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*fftypes.FFI)
+			r0 = ret.Get(0).(*core.Event)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = rf(ctx, namespace, name, version)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -990,20 +1250,20 @@ func (_m *Plugin) GetFFI(ctx context.Context, namespace string, name string, ver
 	return r0, r1
 }
 
-// GetFFIByID provides a mock function with given fields: ctx, namespace, id
-func (_m *Plugin) GetFFIByID(ctx context.Context, namespace string, id *fftypes.UUID) (*fftypes.FFI, error) {
+// GetEventRuleByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetEventRuleByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.EventRule, error) {
 	ret := _m.Called(ctx, namespace, id)
 
-	var r0 *fftypes.FFI
+	var r0 *core.EventRule
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*fftypes.FFI, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.EventRule, error)); ok {
 		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *fftypes.FFI); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.EventRule); ok {
 		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*fftypes.FFI)
+			r0 = ret.Get(0).(*core.EventRule)
 		}
 	}
 
@@ -1016,24 +1276,172 @@ func (_m *Plugin) GetFFIByID(ctx context.Context, namespace string, id *fftypes.
 	return r0, r1
 }
 
-// GetFFIByNetworkName provides a mock function with given fields: ctx, namespace, networkName, version
-func (_m *Plugin) GetFFIByNetworkName(ctx context.Context, namespace string, networkName string, version string) (*fftypes.FFI, error) {
-	ret := _m.Called(ctx, namespace, networkName, version)
+// GetEventRuleByName provides a mock function with given fields: ctx, namespace, name
+func (_m *Plugin) GetEventRuleByName(ctx context.Context, namespace string, name string) (*core.EventRule, error) {
+	ret := _m.Called(ctx, namespace, name)
 
-	var r0 *fftypes.FFI
+	var r0 *core.EventRule
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*fftypes.FFI, error)); ok {
-		return rf(ctx, namespace, networkName, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.EventRule, error)); ok {
+		return rf(ctx, namespace, name)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.FFI); ok {
-		r0 = rf(ctx, namespace, networkName, version)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.EventRule); ok {
+		r0 = rf(ctx, namespace, name)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*fftypes.FFI)
+			r0 = ret.Get(0).(*core.EventRule)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventRules provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetEventRules(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.EventRule, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.EventRule
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.EventRule, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.EventRule); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.EventRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEvents provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetEvents(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Event, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.Event
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.Event, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.Event); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetFFI provides a mock function with given fields: ctx, namespace, name, version
+func (_m *Plugin) GetFFI(ctx context.Context, namespace string, name string, version string) (*fftypes.FFI, error) {
+	ret := _m.Called(ctx, namespace, name, version)
+
+	var r0 *fftypes.FFI
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*fftypes.FFI, error)); ok {
+		return rf(ctx, namespace, name, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.FFI); ok {
+		r0 = rf(ctx, namespace, name, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.FFI)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, namespace, name, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFFIByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetFFIByID(ctx context.Context, namespace string, id *fftypes.UUID) (*fftypes.FFI, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *fftypes.FFI
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*fftypes.FFI, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *fftypes.FFI); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.FFI)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFFIByNetworkName provides a mock function with given fields: ctx, namespace, networkName, version
+func (_m *Plugin) GetFFIByNetworkName(ctx context.Context, namespace string, networkName string, version string) (*fftypes.FFI, error) {
+	ret := _m.Called(ctx, namespace, networkName, version)
+
+	var r0 *fftypes.FFI
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*fftypes.FFI, error)); ok {
+		return rf(ctx, namespace, networkName, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.FFI); ok {
+		r0 = rf(ctx, namespace, networkName, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.FFI)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
 		r1 = rf(ctx, namespace, networkName, version)
 	} else {
 		r1 = ret.Error(1)
@@ -1408,20 +1816,20 @@ func (_m *Plugin) GetIdentityByName(ctx context.Context, iType fftypes.FFEnum, n
 	return r0, r1
 }
 
-// GetMessageByID provides a mock function with given fields: ctx, namespace, id
-func (_m *Plugin) GetMessageByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Message, error) {
+// GetJobByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetJobByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Job, error) {
 	ret := _m.Called(ctx, namespace, id)
 
-	var r0 *core.Message
+	var r0 *core.Job
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Message, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Job, error)); ok {
 		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Message); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Job); ok {
 		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Message)
+			r0 = ret.Get(0).(*core.Job)
 		}
 	}
 
@@ -1434,25 +1842,60 @@ func (_m *Plugin) GetMessageByID(ctx context.Context, namespace string, id *ffty
 	return r0, r1
 }
 
-// GetMessageIDs provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetMessageIDs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.IDAndSequence, error) {
+// GetJobs provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetJobs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Job, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.IDAndSequence
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.IDAndSequence, error)); ok {
+	var r0 []*core.Job
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.Job, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.IDAndSequence); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.Job); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.IDAndSequence)
+			r0 = ret.Get(0).([]*core.Job)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
 		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetLatestBlockchainEvents provides a mock function with given fields: ctx, namespace, listeners
+func (_m *Plugin) GetLatestBlockchainEvents(ctx context.Context, namespace string, listeners []*fftypes.UUID) ([]*core.BlockchainEvent, error) {
+	ret := _m.Called(ctx, namespace, listeners)
+
+	var r0 []*core.BlockchainEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) ([]*core.BlockchainEvent, error)); ok {
+		return rf(ctx, namespace, listeners)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []*fftypes.UUID) []*core.BlockchainEvent); ok {
+		r0 = rf(ctx, namespace, listeners)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BlockchainEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []*fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, listeners)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1460,21 +1903,21 @@ func (_m *Plugin) GetMessageIDs(ctx context.Context, namespace string, filter ff
 	return r0, r1
 }
 
-// GetMessages provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetMessages(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Message, *ffapi.FilterResult, error) {
+// GetMessageAcks provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetMessageAcks(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.MessageAck, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.Message
+	var r0 []*core.MessageAck
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.MessageAck, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.Message); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.MessageAck); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.Message)
+			r0 = ret.Get(0).([]*core.MessageAck)
 		}
 	}
 
@@ -1495,60 +1938,51 @@ func (_m *Plugin) GetMessages(ctx context.Context, namespace string, filter ffap
 	return r0, r1, r2
 }
 
-// GetMessagesForData provides a mock function with given fields: ctx, namespace, dataID, filter
-func (_m *Plugin) GetMessagesForData(ctx context.Context, namespace string, dataID *fftypes.UUID, filter ffapi.Filter) ([]*core.Message, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, namespace, dataID, filter)
+// GetMessageByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetMessageByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Message, error) {
+	ret := _m.Called(ctx, namespace, id)
 
-	var r0 []*core.Message
-	var r1 *ffapi.FilterResult
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
-		return rf(ctx, namespace, dataID, filter)
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Message, error)); ok {
+		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) []*core.Message); ok {
-		r0 = rf(ctx, namespace, dataID, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Message); ok {
+		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.Message)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) *ffapi.FilterResult); ok {
-		r1 = rf(ctx, namespace, dataID, filter)
-	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*ffapi.FilterResult)
+			r0 = ret.Get(0).(*core.Message)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) error); ok {
-		r2 = rf(ctx, namespace, dataID, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetNamespace provides a mock function with given fields: ctx, name
-func (_m *Plugin) GetNamespace(ctx context.Context, name string) (*core.Namespace, error) {
-	ret := _m.Called(ctx, name)
+// GetMessageIDs provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetMessageIDs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.IDAndSequence, error) {
+	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 *core.Namespace
+	var r0 []*core.IDAndSequence
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Namespace, error)); ok {
-		return rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.IDAndSequence, error)); ok {
+		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Namespace); ok {
-		r0 = rf(ctx, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.IDAndSequence); ok {
+		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Namespace)
+			r0 = ret.Get(0).([]*core.IDAndSequence)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) error); ok {
+		r1 = rf(ctx, namespace, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1556,21 +1990,21 @@ func (_m *Plugin) GetNamespace(ctx context.Context, name string) (*core.Namespac
 	return r0, r1
 }
 
-// GetNextPins provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetNextPins(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error) {
+// GetMessageReceipts provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetMessageReceipts(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.MessageReceipt, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.NextPin
+	var r0 []*core.MessageReceipt
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.MessageReceipt, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.NextPin); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.MessageReceipt); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.NextPin)
+			r0 = ret.Get(0).([]*core.MessageReceipt)
 		}
 	}
 
@@ -1591,9 +2025,147 @@ func (_m *Plugin) GetNextPins(ctx context.Context, namespace string, filter ffap
 	return r0, r1, r2
 }
 
-// GetNextPinsForContext provides a mock function with given fields: ctx, namespace, _a2
-func (_m *Plugin) GetNextPinsForContext(ctx context.Context, namespace string, _a2 *fftypes.Bytes32) ([]*core.NextPin, error) {
-	ret := _m.Called(ctx, namespace, _a2)
+// GetMessages provides a mock function with given fields: ctx, namespace, filter, fields
+func (_m *Plugin) GetMessages(ctx context.Context, namespace string, filter ffapi.Filter, fields ...string) ([]*core.Message, *ffapi.FilterResult, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, namespace, filter)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, ...string) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter, fields...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, ...string) []*core.Message); ok {
+		r0 = rf(ctx, namespace, filter, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter, ...string) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter, fields...)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter, ...string) error); ok {
+		r2 = rf(ctx, namespace, filter, fields...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMessagesForData provides a mock function with given fields: ctx, namespace, dataID, filter
+func (_m *Plugin) GetMessagesForData(ctx context.Context, namespace string, dataID *fftypes.UUID, filter ffapi.Filter) ([]*core.Message, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, dataID, filter)
+
+	var r0 []*core.Message
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, dataID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) []*core.Message); ok {
+		r0 = rf(ctx, namespace, dataID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, dataID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, dataID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetNamespace provides a mock function with given fields: ctx, name
+func (_m *Plugin) GetNamespace(ctx context.Context, name string) (*core.Namespace, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *core.Namespace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Namespace, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Namespace); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Namespace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNextPins provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetNextPins(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.NextPin
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.NextPin); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.NextPin)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetNextPinsForContext provides a mock function with given fields: ctx, namespace, _a2
+func (_m *Plugin) GetNextPinsForContext(ctx context.Context, namespace string, _a2 *fftypes.Bytes32) ([]*core.NextPin, error) {
+	ret := _m.Called(ctx, namespace, _a2)
 
 	var r0 []*core.NextPin
 	var r1 error
@@ -1765,34 +2337,41 @@ func (_m *Plugin) GetOperationByID(ctx context.Context, namespace string, id *ff
 	return r0, r1
 }
 
-// GetOperations provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetOperations(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Operation, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, namespace, filter)
+// GetOperations provides a mock function with given fields: ctx, namespace, filter, fields
+func (_m *Plugin) GetOperations(ctx context.Context, namespace string, filter ffapi.Filter, fields ...string) ([]*core.Operation, *ffapi.FilterResult, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, namespace, filter)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*core.Operation
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
-		return rf(ctx, namespace, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, ...string) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter, fields...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.Operation); ok {
-		r0 = rf(ctx, namespace, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, ...string) []*core.Operation); ok {
+		r0 = rf(ctx, namespace, filter, fields...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*core.Operation)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
-		r1 = rf(ctx, namespace, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter, ...string) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter, fields...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*ffapi.FilterResult)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
-		r2 = rf(ctx, namespace, filter)
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter, ...string) error); ok {
+		r2 = rf(ctx, namespace, filter, fields...)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -1835,20 +2414,20 @@ func (_m *Plugin) GetPins(ctx context.Context, namespace string, filter ffapi.Fi
 	return r0, r1, r2
 }
 
-// GetSubscriptionByID provides a mock function with given fields: ctx, namespace, id
-func (_m *Plugin) GetSubscriptionByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Subscription, error) {
+// GetQuarantinedBatchByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetQuarantinedBatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.QuarantinedBatch, error) {
 	ret := _m.Called(ctx, namespace, id)
 
-	var r0 *core.Subscription
+	var r0 *core.QuarantinedBatch
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Subscription, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.QuarantinedBatch, error)); ok {
 		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Subscription); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.QuarantinedBatch); ok {
 		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Subscription)
+			r0 = ret.Get(0).(*core.QuarantinedBatch)
 		}
 	}
 
@@ -1861,25 +2440,60 @@ func (_m *Plugin) GetSubscriptionByID(ctx context.Context, namespace string, id
 	return r0, r1
 }
 
-// GetSubscriptionByName provides a mock function with given fields: ctx, namespace, name
-func (_m *Plugin) GetSubscriptionByName(ctx context.Context, namespace string, name string) (*core.Subscription, error) {
-	ret := _m.Called(ctx, namespace, name)
+// GetQuarantinedBatches provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetQuarantinedBatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.QuarantinedBatch, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 *core.Subscription
+	var r0 []*core.QuarantinedBatch
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.QuarantinedBatch, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.QuarantinedBatch); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.QuarantinedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetQuarantinedBlobByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetQuarantinedBlobByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.QuarantinedBlob, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.QuarantinedBlob
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.Subscription, error)); ok {
-		return rf(ctx, namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.QuarantinedBlob, error)); ok {
+		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.Subscription); ok {
-		r0 = rf(ctx, namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.QuarantinedBlob); ok {
+		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Subscription)
+			r0 = ret.Get(0).(*core.QuarantinedBlob)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1887,21 +2501,21 @@ func (_m *Plugin) GetSubscriptionByName(ctx context.Context, namespace string, n
 	return r0, r1
 }
 
-// GetSubscriptions provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetSubscriptions(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Subscription, *ffapi.FilterResult, error) {
+// GetQuarantinedBlobs provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetQuarantinedBlobs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.QuarantinedBlob, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.Subscription
+	var r0 []*core.QuarantinedBlob
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.Subscription, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.QuarantinedBlob, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.Subscription); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.QuarantinedBlob); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.Subscription)
+			r0 = ret.Get(0).([]*core.QuarantinedBlob)
 		}
 	}
 
@@ -1922,56 +2536,47 @@ func (_m *Plugin) GetSubscriptions(ctx context.Context, namespace string, filter
 	return r0, r1, r2
 }
 
-// GetTokenAccountPools provides a mock function with given fields: ctx, namespace, key, filter
-func (_m *Plugin) GetTokenAccountPools(ctx context.Context, namespace string, key string, filter ffapi.Filter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, namespace, key, filter)
+// GetRoleBindingByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetRoleBindingByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.RoleBinding, error) {
+	ret := _m.Called(ctx, namespace, id)
 
-	var r0 []*core.TokenAccountPool
-	var r1 *ffapi.FilterResult
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.Filter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error)); ok {
-		return rf(ctx, namespace, key, filter)
+	var r0 *core.RoleBinding
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.RoleBinding, error)); ok {
+		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.Filter) []*core.TokenAccountPool); ok {
-		r0 = rf(ctx, namespace, key, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.RoleBinding); ok {
+		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenAccountPool)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, ffapi.Filter) *ffapi.FilterResult); ok {
-		r1 = rf(ctx, namespace, key, filter)
-	} else {
-		if ret.Get(1) != nil {
-			r1 = ret.Get(1).(*ffapi.FilterResult)
+			r0 = ret.Get(0).(*core.RoleBinding)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, string, ffapi.Filter) error); ok {
-		r2 = rf(ctx, namespace, key, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
 	} else {
-		r2 = ret.Error(2)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2
+	return r0, r1
 }
 
-// GetTokenAccounts provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetTokenAccounts(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenAccount, *ffapi.FilterResult, error) {
+// GetRoleBindings provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetRoleBindings(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.RoleBinding, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.TokenAccount
+	var r0 []*core.RoleBinding
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenAccount, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.RoleBinding, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenAccount); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.RoleBinding); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenAccount)
+			r0 = ret.Get(0).([]*core.RoleBinding)
 		}
 	}
 
@@ -1992,25 +2597,25 @@ func (_m *Plugin) GetTokenAccounts(ctx context.Context, namespace string, filter
 	return r0, r1, r2
 }
 
-// GetTokenApprovalByID provides a mock function with given fields: ctx, namespace, localID
-func (_m *Plugin) GetTokenApprovalByID(ctx context.Context, namespace string, localID *fftypes.UUID) (*core.TokenApproval, error) {
-	ret := _m.Called(ctx, namespace, localID)
+// GetSLABreachByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetSLABreachByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.SLABreach, error) {
+	ret := _m.Called(ctx, namespace, id)
 
-	var r0 *core.TokenApproval
+	var r0 *core.SLABreach
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenApproval, error)); ok {
-		return rf(ctx, namespace, localID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.SLABreach, error)); ok {
+		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenApproval); ok {
-		r0 = rf(ctx, namespace, localID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.SLABreach); ok {
+		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenApproval)
+			r0 = ret.Get(0).(*core.SLABreach)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
-		r1 = rf(ctx, namespace, localID)
+		r1 = rf(ctx, namespace, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2018,25 +2623,966 @@ func (_m *Plugin) GetTokenApprovalByID(ctx context.Context, namespace string, lo
 	return r0, r1
 }
 
-// GetTokenApprovalByProtocolID provides a mock function with given fields: ctx, namespace, poolID, protocolID
-func (_m *Plugin) GetTokenApprovalByProtocolID(ctx context.Context, namespace string, poolID *fftypes.UUID, protocolID string) (*core.TokenApproval, error) {
-	ret := _m.Called(ctx, namespace, poolID, protocolID)
+// GetSLABreaches provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetSLABreaches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.SLABreach, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 *core.TokenApproval
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) (*core.TokenApproval, error)); ok {
-		return rf(ctx, namespace, poolID, protocolID)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) *core.TokenApproval); ok {
+	var r0 []*core.SLABreach
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.SLABreach, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.SLABreach); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.SLABreach)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSubscriptionByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetSubscriptionByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Subscription, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.Subscription, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.Subscription); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionByName provides a mock function with given fields: ctx, namespace, name
+func (_m *Plugin) GetSubscriptionByName(ctx context.Context, namespace string, name string) (*core.Subscription, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *core.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.Subscription, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.Subscription); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionTemplateByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetSubscriptionTemplateByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.SubscriptionTemplate, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.SubscriptionTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.SubscriptionTemplate, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.SubscriptionTemplate); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionTemplateByName provides a mock function with given fields: ctx, namespace, name
+func (_m *Plugin) GetSubscriptionTemplateByName(ctx context.Context, namespace string, name string) (*core.SubscriptionTemplate, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *core.SubscriptionTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.SubscriptionTemplate, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.SubscriptionTemplate); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionTemplates provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetSubscriptionTemplates(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.SubscriptionTemplate, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.SubscriptionTemplate
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.SubscriptionTemplate, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.SubscriptionTemplate); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.SubscriptionTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSubscriptions provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetSubscriptions(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Subscription, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.Subscription
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.Subscription, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.Subscription); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenAccountActivity provides a mock function with given fields: ctx, namespace, key, filter
+func (_m *Plugin) GetTokenAccountActivity(ctx context.Context, namespace string, key string, filter ffapi.Filter) ([]*core.TokenActivity, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, key, filter)
+
+	var r0 []*core.TokenActivity
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.Filter) ([]*core.TokenActivity, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, key, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.Filter) []*core.TokenActivity); ok {
+		r0 = rf(ctx, namespace, key, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenActivity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, key, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, key, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenAccountPools provides a mock function with given fields: ctx, namespace, key, filter
+func (_m *Plugin) GetTokenAccountPools(ctx context.Context, namespace string, key string, filter ffapi.Filter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, key, filter)
+
+	var r0 []*core.TokenAccountPool
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.Filter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, key, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, ffapi.Filter) []*core.TokenAccountPool); ok {
+		r0 = rf(ctx, namespace, key, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenAccountPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, key, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, key, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenAccounts provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenAccounts(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenAccount, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenAccount
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenAccount, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenAccount); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenApprovalByID provides a mock function with given fields: ctx, namespace, localID
+func (_m *Plugin) GetTokenApprovalByID(ctx context.Context, namespace string, localID *fftypes.UUID) (*core.TokenApproval, error) {
+	ret := _m.Called(ctx, namespace, localID)
+
+	var r0 *core.TokenApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenApproval, error)); ok {
+		return rf(ctx, namespace, localID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenApproval); ok {
+		r0 = rf(ctx, namespace, localID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, localID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenApprovalByProtocolID provides a mock function with given fields: ctx, namespace, poolID, protocolID
+func (_m *Plugin) GetTokenApprovalByProtocolID(ctx context.Context, namespace string, poolID *fftypes.UUID, protocolID string) (*core.TokenApproval, error) {
+	ret := _m.Called(ctx, namespace, poolID, protocolID)
+
+	var r0 *core.TokenApproval
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) (*core.TokenApproval, error)); ok {
+		return rf(ctx, namespace, poolID, protocolID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) *core.TokenApproval); ok {
 		r0 = rf(ctx, namespace, poolID, protocolID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenApproval)
+			r0 = ret.Get(0).(*core.TokenApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string) error); ok {
+		r1 = rf(ctx, namespace, poolID, protocolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenApprovals provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenApprovals(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenApproval, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenApproval
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenApproval, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenApproval); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenApproval)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenAssociatedAccount provides a mock function with given fields: ctx, namespace, poolID, owner
+func (_m *Plugin) GetTokenAssociatedAccount(ctx context.Context, namespace string, poolID *fftypes.UUID, owner string) (*core.TokenAssociatedAccount, error) {
+	ret := _m.Called(ctx, namespace, poolID, owner)
+
+	var r0 *core.TokenAssociatedAccount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) (*core.TokenAssociatedAccount, error)); ok {
+		return rf(ctx, namespace, poolID, owner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) *core.TokenAssociatedAccount); ok {
+		r0 = rf(ctx, namespace, poolID, owner)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenAssociatedAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string) error); ok {
+		r1 = rf(ctx, namespace, poolID, owner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenAssociatedAccounts provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenAssociatedAccounts(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenAssociatedAccount, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenAssociatedAccount
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenAssociatedAccount, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenAssociatedAccount); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenAssociatedAccount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenBalance provides a mock function with given fields: ctx, namespace, poolID, tokenIndex, identity
+func (_m *Plugin) GetTokenBalance(ctx context.Context, namespace string, poolID *fftypes.UUID, tokenIndex string, identity string) (*core.TokenBalance, error) {
+	ret := _m.Called(ctx, namespace, poolID, tokenIndex, identity)
+
+	var r0 *core.TokenBalance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string, string) (*core.TokenBalance, error)); ok {
+		return rf(ctx, namespace, poolID, tokenIndex, identity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string, string) *core.TokenBalance); ok {
+		r0 = rf(ctx, namespace, poolID, tokenIndex, identity)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string, string) error); ok {
+		r1 = rf(ctx, namespace, poolID, tokenIndex, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenBalanceMismatch provides a mock function with given fields: ctx, namespace, poolID, tokenIndex, key
+func (_m *Plugin) GetTokenBalanceMismatch(ctx context.Context, namespace string, poolID *fftypes.UUID, tokenIndex string, key string) (*core.TokenBalanceMismatch, error) {
+	ret := _m.Called(ctx, namespace, poolID, tokenIndex, key)
+
+	var r0 *core.TokenBalanceMismatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string, string) (*core.TokenBalanceMismatch, error)); ok {
+		return rf(ctx, namespace, poolID, tokenIndex, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string, string) *core.TokenBalanceMismatch); ok {
+		r0 = rf(ctx, namespace, poolID, tokenIndex, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenBalanceMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string, string) error); ok {
+		r1 = rf(ctx, namespace, poolID, tokenIndex, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenBalanceMismatchByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenBalanceMismatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBalanceMismatch, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.TokenBalanceMismatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenBalanceMismatch, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenBalanceMismatch); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenBalanceMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenBalanceMismatches provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenBalanceMismatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenBalanceMismatch
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenBalanceMismatch); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalanceMismatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenBalances provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenBalances(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenBalance
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenBalance); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenBulkMintByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenBulkMintByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBulkMint, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.TokenBulkMint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenBulkMint, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenBulkMint); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenBulkMint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenBulkMints provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenBulkMints(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBulkMint, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenBulkMint
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenBulkMint, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenBulkMint); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenBulkMint)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenIndexOwnership provides a mock function with given fields: ctx, namespace, poolID, filter
+func (_m *Plugin) GetTokenIndexOwnership(ctx context.Context, namespace string, poolID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, poolID, filter)
+
+	var r0 []*core.TokenIndexOwnership
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, poolID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) []*core.TokenIndexOwnership); ok {
+		r0 = rf(ctx, namespace, poolID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenIndexOwnership)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, poolID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, poolID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenLedgerEntries provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenLedgerEntries(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenLedgerEntry, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenLedgerEntry
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenLedgerEntry, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenLedgerEntry); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenLedgerEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenMediaByURI provides a mock function with given fields: ctx, namespace, uri
+func (_m *Plugin) GetTokenMediaByURI(ctx context.Context, namespace string, uri string) (*core.TokenMedia, error) {
+	ret := _m.Called(ctx, namespace, uri)
+
+	var r0 *core.TokenMedia
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenMedia, error)); ok {
+		return rf(ctx, namespace, uri)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenMedia); ok {
+		r0 = rf(ctx, namespace, uri)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenMedia)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, uri)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenMetadata provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenMetadata(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenMetadata, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	var r0 []*core.TokenMetadata
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenMetadata, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenMetadata); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenMetadataByURI provides a mock function with given fields: ctx, namespace, uri
+func (_m *Plugin) GetTokenMetadataByURI(ctx context.Context, namespace string, uri string) (*core.TokenMetadata, error) {
+	ret := _m.Called(ctx, namespace, uri)
+
+	var r0 *core.TokenMetadata
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenMetadata, error)); ok {
+		return rf(ctx, namespace, uri)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenMetadata); ok {
+		r0 = rf(ctx, namespace, uri)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenMetadata)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, uri)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPool provides a mock function with given fields: ctx, namespace, name
+func (_m *Plugin) GetTokenPool(ctx context.Context, namespace string, name string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, namespace, name)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, namespace, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenPool); ok {
+		r0 = rf(ctx, namespace, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPoolByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenPoolByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenPool, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenPool); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPoolByNetworkName provides a mock function with given fields: ctx, namespace, networkName
+func (_m *Plugin) GetTokenPoolByNetworkName(ctx context.Context, namespace string, networkName string) (*core.TokenPool, error) {
+	ret := _m.Called(ctx, namespace, networkName)
+
+	var r0 *core.TokenPool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenPool, error)); ok {
+		return rf(ctx, namespace, networkName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenPool); ok {
+		r0 = rf(ctx, namespace, networkName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, namespace, networkName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenPoolHolders provides a mock function with given fields: ctx, namespace, poolID, filter
+func (_m *Plugin) GetTokenPoolHolders(ctx context.Context, namespace string, poolID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, poolID, filter)
+
+	var r0 []*core.TokenPoolHolder
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, poolID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) []*core.TokenPoolHolder); ok {
+		r0 = rf(ctx, namespace, poolID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenPoolHolder)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string) error); ok {
-		r1 = rf(ctx, namespace, poolID, protocolID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, poolID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, poolID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenPoolMigrationByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenPoolMigrationByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenPoolMigration, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.TokenPoolMigration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenPoolMigration, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenPoolMigration); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenPoolMigration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2044,21 +3590,21 @@ func (_m *Plugin) GetTokenApprovalByProtocolID(ctx context.Context, namespace st
 	return r0, r1
 }
 
-// GetTokenApprovals provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetTokenApprovals(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenApproval, *ffapi.FilterResult, error) {
+// GetTokenPoolMigrations provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenPoolMigrations(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenPoolMigration, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.TokenApproval
+	var r0 []*core.TokenPoolMigration
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenApproval, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenPoolMigration, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenApproval); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenPoolMigration); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenApproval)
+			r0 = ret.Get(0).([]*core.TokenPoolMigration)
 		}
 	}
 
@@ -2079,25 +3625,95 @@ func (_m *Plugin) GetTokenApprovals(ctx context.Context, namespace string, filte
 	return r0, r1, r2
 }
 
-// GetTokenBalance provides a mock function with given fields: ctx, namespace, poolID, tokenIndex, identity
-func (_m *Plugin) GetTokenBalance(ctx context.Context, namespace string, poolID *fftypes.UUID, tokenIndex string, identity string) (*core.TokenBalance, error) {
-	ret := _m.Called(ctx, namespace, poolID, tokenIndex, identity)
+// GetTokenPools provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenPools(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenPool, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 *core.TokenBalance
+	var r0 []*core.TokenPool
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenPool, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenPool); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenPool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenSnapshotBalances provides a mock function with given fields: ctx, namespace, snapshotID, filter
+func (_m *Plugin) GetTokenSnapshotBalances(ctx context.Context, namespace string, snapshotID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenSnapshotBalance, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, snapshotID, filter)
+
+	var r0 []*core.TokenSnapshotBalance
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) ([]*core.TokenSnapshotBalance, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, snapshotID, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) []*core.TokenSnapshotBalance); ok {
+		r0 = rf(ctx, namespace, snapshotID, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenSnapshotBalance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, snapshotID, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *fftypes.UUID, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, snapshotID, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenSnapshotByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenSnapshotByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenSnapshot, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.TokenSnapshot
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string, string) (*core.TokenBalance, error)); ok {
-		return rf(ctx, namespace, poolID, tokenIndex, identity)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenSnapshot, error)); ok {
+		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string, string) *core.TokenBalance); ok {
-		r0 = rf(ctx, namespace, poolID, tokenIndex, identity)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenSnapshot); ok {
+		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenBalance)
+			r0 = ret.Get(0).(*core.TokenSnapshot)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string, string) error); ok {
-		r1 = rf(ctx, namespace, poolID, tokenIndex, identity)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2105,21 +3721,21 @@ func (_m *Plugin) GetTokenBalance(ctx context.Context, namespace string, poolID
 	return r0, r1
 }
 
-// GetTokenBalances provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetTokenBalances(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error) {
+// GetTokenSnapshots provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenSnapshots(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenSnapshot, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.TokenBalance
+	var r0 []*core.TokenSnapshot
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenBalance, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenSnapshot, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenBalance); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenSnapshot); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenBalance)
+			r0 = ret.Get(0).([]*core.TokenSnapshot)
 		}
 	}
 
@@ -2140,25 +3756,25 @@ func (_m *Plugin) GetTokenBalances(ctx context.Context, namespace string, filter
 	return r0, r1, r2
 }
 
-// GetTokenPool provides a mock function with given fields: ctx, namespace, name
-func (_m *Plugin) GetTokenPool(ctx context.Context, namespace string, name string) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, namespace, name)
+// GetTokenSwapByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenSwapByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenSwap, error) {
+	ret := _m.Called(ctx, namespace, id)
 
-	var r0 *core.TokenPool
+	var r0 *core.TokenSwap
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenPool, error)); ok {
-		return rf(ctx, namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenSwap, error)); ok {
+		return rf(ctx, namespace, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenPool); ok {
-		r0 = rf(ctx, namespace, name)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenSwap); ok {
+		r0 = rf(ctx, namespace, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenPool)
+			r0 = ret.Get(0).(*core.TokenSwap)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, namespace, name)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2166,25 +3782,60 @@ func (_m *Plugin) GetTokenPool(ctx context.Context, namespace string, name strin
 	return r0, r1
 }
 
-// GetTokenPoolByID provides a mock function with given fields: ctx, namespace, id
-func (_m *Plugin) GetTokenPoolByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, namespace, id)
+// GetTokenSwaps provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenSwaps(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenSwap, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 *core.TokenPool
+	var r0 []*core.TokenSwap
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenSwap, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenSwap); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenSwap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTokenTransferByID provides a mock function with given fields: ctx, namespace, localID
+func (_m *Plugin) GetTokenTransferByID(ctx context.Context, namespace string, localID *fftypes.UUID) (*core.TokenTransfer, error) {
+	ret := _m.Called(ctx, namespace, localID)
+
+	var r0 *core.TokenTransfer
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenPool, error)); ok {
-		return rf(ctx, namespace, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenTransfer, error)); ok {
+		return rf(ctx, namespace, localID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenPool); ok {
-		r0 = rf(ctx, namespace, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenTransfer); ok {
+		r0 = rf(ctx, namespace, localID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenPool)
+			r0 = ret.Get(0).(*core.TokenTransfer)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
-		r1 = rf(ctx, namespace, id)
+		r1 = rf(ctx, namespace, localID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2192,25 +3843,25 @@ func (_m *Plugin) GetTokenPoolByID(ctx context.Context, namespace string, id *ff
 	return r0, r1
 }
 
-// GetTokenPoolByNetworkName provides a mock function with given fields: ctx, namespace, networkName
-func (_m *Plugin) GetTokenPoolByNetworkName(ctx context.Context, namespace string, networkName string) (*core.TokenPool, error) {
-	ret := _m.Called(ctx, namespace, networkName)
+// GetTokenTransferByProtocolID provides a mock function with given fields: ctx, namespace, poolID, protocolID
+func (_m *Plugin) GetTokenTransferByProtocolID(ctx context.Context, namespace string, poolID *fftypes.UUID, protocolID string) (*core.TokenTransfer, error) {
+	ret := _m.Called(ctx, namespace, poolID, protocolID)
 
-	var r0 *core.TokenPool
+	var r0 *core.TokenTransfer
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenPool, error)); ok {
-		return rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) (*core.TokenTransfer, error)); ok {
+		return rf(ctx, namespace, poolID, protocolID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenPool); ok {
-		r0 = rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) *core.TokenTransfer); ok {
+		r0 = rf(ctx, namespace, poolID, protocolID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenPool)
+			r0 = ret.Get(0).(*core.TokenTransfer)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, namespace, networkName)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string) error); ok {
+		r1 = rf(ctx, namespace, poolID, protocolID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2218,21 +3869,73 @@ func (_m *Plugin) GetTokenPoolByNetworkName(ctx context.Context, namespace strin
 	return r0, r1
 }
 
-// GetTokenPools provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetTokenPools(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenPool, *ffapi.FilterResult, error) {
+// GetTokenTransferPolicy provides a mock function with given fields: ctx, namespace, poolID
+func (_m *Plugin) GetTokenTransferPolicy(ctx context.Context, namespace string, poolID *fftypes.UUID) (*core.TokenTransferPolicy, error) {
+	ret := _m.Called(ctx, namespace, poolID)
+
+	var r0 *core.TokenTransferPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenTransferPolicy, error)); ok {
+		return rf(ctx, namespace, poolID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenTransferPolicy); ok {
+		r0 = rf(ctx, namespace, poolID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, poolID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenTransferRequestByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetTokenTransferRequestByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenTransferRequest, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	var r0 *core.TokenTransferRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenTransferRequest, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenTransferRequest); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenTransferRequests provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenTransferRequests(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenTransferRequest, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.TokenPool
+	var r0 []*core.TokenTransferRequest
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenPool, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenTransferRequest, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenPool); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenTransferRequest); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenPool)
+			r0 = ret.Get(0).([]*core.TokenTransferRequest)
 		}
 	}
 
@@ -2253,51 +3956,60 @@ func (_m *Plugin) GetTokenPools(ctx context.Context, namespace string, filter ff
 	return r0, r1, r2
 }
 
-// GetTokenTransferByID provides a mock function with given fields: ctx, namespace, localID
-func (_m *Plugin) GetTokenTransferByID(ctx context.Context, namespace string, localID *fftypes.UUID) (*core.TokenTransfer, error) {
-	ret := _m.Called(ctx, namespace, localID)
+// GetTokenTransfers provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTokenTransfers(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenTransfer, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 *core.TokenTransfer
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.TokenTransfer, error)); ok {
-		return rf(ctx, namespace, localID)
+	var r0 []*core.TokenTransfer
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenTransfer, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.TokenTransfer); ok {
-		r0 = rf(ctx, namespace, localID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenTransfer); ok {
+		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenTransfer)
+			r0 = ret.Get(0).([]*core.TokenTransfer)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
-		r1 = rf(ctx, namespace, localID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetTokenTransferByProtocolID provides a mock function with given fields: ctx, namespace, poolID, protocolID
-func (_m *Plugin) GetTokenTransferByProtocolID(ctx context.Context, namespace string, poolID *fftypes.UUID, protocolID string) (*core.TokenTransfer, error) {
-	ret := _m.Called(ctx, namespace, poolID, protocolID)
+// GetTopicSequence provides a mock function with given fields: ctx, namespace, _a2
+func (_m *Plugin) GetTopicSequence(ctx context.Context, namespace string, _a2 *fftypes.Bytes32) (*core.TopicSequence, error) {
+	ret := _m.Called(ctx, namespace, _a2)
 
-	var r0 *core.TokenTransfer
+	var r0 *core.TopicSequence
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) (*core.TokenTransfer, error)); ok {
-		return rf(ctx, namespace, poolID, protocolID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.Bytes32) (*core.TopicSequence, error)); ok {
+		return rf(ctx, namespace, _a2)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, string) *core.TokenTransfer); ok {
-		r0 = rf(ctx, namespace, poolID, protocolID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.Bytes32) *core.TopicSequence); ok {
+		r0 = rf(ctx, namespace, _a2)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenTransfer)
+			r0 = ret.Get(0).(*core.TopicSequence)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, string) error); ok {
-		r1 = rf(ctx, namespace, poolID, protocolID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.Bytes32) error); ok {
+		r1 = rf(ctx, namespace, _a2)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -2305,21 +4017,21 @@ func (_m *Plugin) GetTokenTransferByProtocolID(ctx context.Context, namespace st
 	return r0, r1
 }
 
-// GetTokenTransfers provides a mock function with given fields: ctx, namespace, filter
-func (_m *Plugin) GetTokenTransfers(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenTransfer, *ffapi.FilterResult, error) {
+// GetTopicSequences provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetTopicSequences(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TopicSequence, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
 
-	var r0 []*core.TokenTransfer
+	var r0 []*core.TopicSequence
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TokenTransfer, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.TopicSequence, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, namespace, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TokenTransfer); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.TopicSequence); ok {
 		r0 = rf(ctx, namespace, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.TokenTransfer)
+			r0 = ret.Get(0).([]*core.TopicSequence)
 		}
 	}
 
@@ -2507,6 +4219,20 @@ func (_m *Plugin) InitConfig(_a0 config.Section) {
 	_m.Called(_a0)
 }
 
+// InsertAuditLogEntry provides a mock function with given fields: ctx, entry
+func (_m *Plugin) InsertAuditLogEntry(ctx context.Context, entry *core.AuditLogEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.AuditLogEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertBlob provides a mock function with given fields: ctx, blob
 func (_m *Plugin) InsertBlob(ctx context.Context, blob *core.Blob) error {
 	ret := _m.Called(ctx, blob)
@@ -2521,6 +4247,20 @@ func (_m *Plugin) InsertBlob(ctx context.Context, blob *core.Blob) error {
 	return r0
 }
 
+// InsertBlobUpload provides a mock function with given fields: ctx, upload
+func (_m *Plugin) InsertBlobUpload(ctx context.Context, upload *core.BlobUpload) error {
+	ret := _m.Called(ctx, upload)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUpload) error); ok {
+		r0 = rf(ctx, upload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertBlobs provides a mock function with given fields: ctx, blobs
 func (_m *Plugin) InsertBlobs(ctx context.Context, blobs []*core.Blob) error {
 	ret := _m.Called(ctx, blobs)
@@ -2563,6 +4303,20 @@ func (_m *Plugin) InsertDataArray(ctx context.Context, data core.DataArray) erro
 	return r0
 }
 
+// InsertDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *Plugin) InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	ret := _m.Called(ctx, deadLetter)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) error); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertEvent provides a mock function with given fields: ctx, data
 func (_m *Plugin) InsertEvent(ctx context.Context, data *core.Event) error {
 	ret := _m.Called(ctx, data)
@@ -2577,6 +4331,62 @@ func (_m *Plugin) InsertEvent(ctx context.Context, data *core.Event) error {
 	return r0
 }
 
+// InsertEventRule provides a mock function with given fields: ctx, rule
+func (_m *Plugin) InsertEventRule(ctx context.Context, rule *core.EventRule) error {
+	ret := _m.Called(ctx, rule)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.EventRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertJob provides a mock function with given fields: ctx, job
+func (_m *Plugin) InsertJob(ctx context.Context, job *core.Job) error {
+	ret := _m.Called(ctx, job)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Job) error); ok {
+		r0 = rf(ctx, job)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertMessageAck provides a mock function with given fields: ctx, ack
+func (_m *Plugin) InsertMessageAck(ctx context.Context, ack *core.MessageAck) error {
+	ret := _m.Called(ctx, ack)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageAck) error); ok {
+		r0 = rf(ctx, ack)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertMessageReceipt provides a mock function with given fields: ctx, receipt
+func (_m *Plugin) InsertMessageReceipt(ctx context.Context, receipt *core.MessageReceipt) error {
+	ret := _m.Called(ctx, receipt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MessageReceipt) error); ok {
+		r0 = rf(ctx, receipt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertMessages provides a mock function with given fields: ctx, messages, hooks
 func (_m *Plugin) InsertMessages(ctx context.Context, messages []*core.Message, hooks ...database.PostCompletionHook) error {
 	_va := make([]interface{}, len(hooks))
@@ -2777,39 +4587,221 @@ func (_m *Plugin) InsertOrGetTokenPool(ctx context.Context, pool *core.TokenPool
 	return r0, r1
 }
 
-// InsertOrGetTokenTransfer provides a mock function with given fields: ctx, approval
-func (_m *Plugin) InsertOrGetTokenTransfer(ctx context.Context, approval *core.TokenTransfer) (*core.TokenTransfer, error) {
-	ret := _m.Called(ctx, approval)
+// InsertOrGetTokenTransfer provides a mock function with given fields: ctx, approval
+func (_m *Plugin) InsertOrGetTokenTransfer(ctx context.Context, approval *core.TokenTransfer) (*core.TokenTransfer, error) {
+	ret := _m.Called(ctx, approval)
+
+	var r0 *core.TokenTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransfer) (*core.TokenTransfer, error)); ok {
+		return rf(ctx, approval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransfer) *core.TokenTransfer); ok {
+		r0 = rf(ctx, approval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransfer) error); ok {
+		r1 = rf(ctx, approval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertPins provides a mock function with given fields: ctx, pins
+func (_m *Plugin) InsertPins(ctx context.Context, pins []*core.Pin) error {
+	ret := _m.Called(ctx, pins)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*core.Pin) error); ok {
+		r0 = rf(ctx, pins)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertQuarantinedBatch provides a mock function with given fields: ctx, quarantined
+func (_m *Plugin) InsertQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) error {
+	ret := _m.Called(ctx, quarantined)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBatch) error); ok {
+		r0 = rf(ctx, quarantined)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertQuarantinedBlob provides a mock function with given fields: ctx, quarantined
+func (_m *Plugin) InsertQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) error {
+	ret := _m.Called(ctx, quarantined)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBlob) error); ok {
+		r0 = rf(ctx, quarantined)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertRoleBinding provides a mock function with given fields: ctx, binding
+func (_m *Plugin) InsertRoleBinding(ctx context.Context, binding *core.RoleBinding) error {
+	ret := _m.Called(ctx, binding)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.RoleBinding) error); ok {
+		r0 = rf(ctx, binding)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertSLABreach provides a mock function with given fields: ctx, breach
+func (_m *Plugin) InsertSLABreach(ctx context.Context, breach *core.SLABreach) error {
+	ret := _m.Called(ctx, breach)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SLABreach) error); ok {
+		r0 = rf(ctx, breach)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertSubscriptionTemplate provides a mock function with given fields: ctx, template
+func (_m *Plugin) InsertSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) error {
+	ret := _m.Called(ctx, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SubscriptionTemplate) error); ok {
+		r0 = rf(ctx, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertTokenAssociatedAccount provides a mock function with given fields: ctx, account
+func (_m *Plugin) InsertTokenAssociatedAccount(ctx context.Context, account *core.TokenAssociatedAccount) error {
+	ret := _m.Called(ctx, account)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenAssociatedAccount) error); ok {
+		r0 = rf(ctx, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertTokenBulkMint provides a mock function with given fields: ctx, bulkMint
+func (_m *Plugin) InsertTokenBulkMint(ctx context.Context, bulkMint *core.TokenBulkMint) error {
+	ret := _m.Called(ctx, bulkMint)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenBulkMint) error); ok {
+		r0 = rf(ctx, bulkMint)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertTokenLedgerEntry provides a mock function with given fields: ctx, entry
+func (_m *Plugin) InsertTokenLedgerEntry(ctx context.Context, entry *core.TokenLedgerEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenLedgerEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertTokenMedia provides a mock function with given fields: ctx, media
+func (_m *Plugin) InsertTokenMedia(ctx context.Context, media *core.TokenMedia) error {
+	ret := _m.Called(ctx, media)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenMedia) error); ok {
+		r0 = rf(ctx, media)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertTokenPoolMigration provides a mock function with given fields: ctx, migration
+func (_m *Plugin) InsertTokenPoolMigration(ctx context.Context, migration *core.TokenPoolMigration) error {
+	ret := _m.Called(ctx, migration)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenPoolMigration) error); ok {
+		r0 = rf(ctx, migration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// InsertTokenSnapshot provides a mock function with given fields: ctx, snapshot, balances
+func (_m *Plugin) InsertTokenSnapshot(ctx context.Context, snapshot *core.TokenSnapshot, balances []*core.TokenSnapshotBalance) error {
+	ret := _m.Called(ctx, snapshot, balances)
 
-	var r0 *core.TokenTransfer
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransfer) (*core.TokenTransfer, error)); ok {
-		return rf(ctx, approval)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransfer) *core.TokenTransfer); ok {
-		r0 = rf(ctx, approval)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenSnapshot, []*core.TokenSnapshotBalance) error); ok {
+		r0 = rf(ctx, snapshot, balances)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.TokenTransfer)
-		}
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *core.TokenTransfer) error); ok {
-		r1 = rf(ctx, approval)
+	return r0
+}
+
+// InsertTokenSwap provides a mock function with given fields: ctx, swap
+func (_m *Plugin) InsertTokenSwap(ctx context.Context, swap *core.TokenSwap) error {
+	ret := _m.Called(ctx, swap)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenSwap) error); ok {
+		r0 = rf(ctx, swap)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// InsertPins provides a mock function with given fields: ctx, pins
-func (_m *Plugin) InsertPins(ctx context.Context, pins []*core.Pin) error {
-	ret := _m.Called(ctx, pins)
+// InsertTokenTransferRequest provides a mock function with given fields: ctx, request
+func (_m *Plugin) InsertTokenTransferRequest(ctx context.Context, request *core.TokenTransferRequest) error {
+	ret := _m.Called(ctx, request)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, []*core.Pin) error); ok {
-		r0 = rf(ctx, pins)
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferRequest) error); ok {
+		r0 = rf(ctx, request)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -2892,6 +4884,34 @@ func (_m *Plugin) UpdateBatch(ctx context.Context, namespace string, id *fftypes
 	return r0
 }
 
+// UpdateBlobUpload provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateBlockchainEvent provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateBlockchainEvent(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateContractListener provides a mock function with given fields: ctx, namespace, id, update
 func (_m *Plugin) UpdateContractListener(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, id, update)
@@ -2920,6 +4940,58 @@ func (_m *Plugin) UpdateData(ctx context.Context, namespace string, id *fftypes.
 	return r0
 }
 
+// UpdateDeadLetter provides a mock function with given fields: ctx, namespace, id, filter, update
+func (_m *Plugin) UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (bool, error) {
+	ret := _m.Called(ctx, namespace, id, filter, update)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) (bool, error)); ok {
+		return rf(ctx, namespace, id, filter, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) bool); ok {
+		r0 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) error); ok {
+		r1 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateGroup provides a mock function with given fields: ctx, namespace, hash, update
+func (_m *Plugin) UpdateGroup(ctx context.Context, namespace string, hash *fftypes.Bytes32, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, hash, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.Bytes32, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, hash, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateJob provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateJob(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateMessage provides a mock function with given fields: ctx, namespace, id, update
 func (_m *Plugin) UpdateMessage(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, id, update)
@@ -3028,6 +5100,54 @@ func (_m *Plugin) UpdatePins(ctx context.Context, namespace string, filter ffapi
 	return r0
 }
 
+// UpdateQuarantinedBatch provides a mock function with given fields: ctx, namespace, id, filter, update
+func (_m *Plugin) UpdateQuarantinedBatch(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (bool, error) {
+	ret := _m.Called(ctx, namespace, id, filter, update)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) (bool, error)); ok {
+		return rf(ctx, namespace, id, filter, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) bool); ok {
+		r0 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) error); ok {
+		r1 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateQuarantinedBlob provides a mock function with given fields: ctx, namespace, id, filter, update
+func (_m *Plugin) UpdateQuarantinedBlob(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (bool, error) {
+	ret := _m.Called(ctx, namespace, id, filter, update)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) (bool, error)); ok {
+		return rf(ctx, namespace, id, filter, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) bool); ok {
+		r0 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) error); ok {
+		r1 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateSubscription provides a mock function with given fields: ctx, namespace, name, update
 func (_m *Plugin) UpdateSubscription(ctx context.Context, namespace string, name string, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, name, update)
@@ -3070,6 +5190,86 @@ func (_m *Plugin) UpdateTokenBalances(ctx context.Context, transfer *core.TokenT
 	return r0
 }
 
+// UpdateTokenBulkMint provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateTokenBulkMint(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTokenPoolMigration provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateTokenPoolMigration(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTokenSwap provides a mock function with given fields: ctx, namespace, id, update
+func (_m *Plugin) UpdateTokenSwap(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, id, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, id, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTokenTransfer provides a mock function with given fields: ctx, namespace, localID, update
+func (_m *Plugin) UpdateTokenTransfer(ctx context.Context, namespace string, localID *fftypes.UUID, update ffapi.Update) error {
+	ret := _m.Called(ctx, namespace, localID, update)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Update) error); ok {
+		r0 = rf(ctx, namespace, localID, update)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTokenTransferRequest provides a mock function with given fields: ctx, namespace, id, filter, update
+func (_m *Plugin) UpdateTokenTransferRequest(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (bool, error) {
+	ret := _m.Called(ctx, namespace, id, filter, update)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) (bool, error)); ok {
+		return rf(ctx, namespace, id, filter, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) bool); ok {
+		r0 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID, ffapi.Filter, ffapi.Update) error); ok {
+		r1 = rf(ctx, namespace, id, filter, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateTransaction provides a mock function with given fields: ctx, namespace, id, update
 func (_m *Plugin) UpdateTransaction(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, id, update)
@@ -3084,6 +5284,20 @@ func (_m *Plugin) UpdateTransaction(ctx context.Context, namespace string, id *f
 	return r0
 }
 
+// UpsertBatchPolicy provides a mock function with given fields: ctx, policy
+func (_m *Plugin) UpsertBatchPolicy(ctx context.Context, policy *core.BatchPolicy) error {
+	ret := _m.Called(ctx, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BatchPolicy) error); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpsertContractAPI provides a mock function with given fields: ctx, api, optimization
 func (_m *Plugin) UpsertContractAPI(ctx context.Context, api *core.ContractAPI, optimization database.UpsertOptimization) error {
 	ret := _m.Called(ctx, api, optimization)
@@ -3301,6 +5515,34 @@ func (_m *Plugin) UpsertTokenApproval(ctx context.Context, approval *core.TokenA
 	return r0
 }
 
+// UpsertTokenBalanceMismatch provides a mock function with given fields: ctx, mismatch
+func (_m *Plugin) UpsertTokenBalanceMismatch(ctx context.Context, mismatch *core.TokenBalanceMismatch) error {
+	ret := _m.Called(ctx, mismatch)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenBalanceMismatch) error); ok {
+		r0 = rf(ctx, mismatch)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertTokenMetadata provides a mock function with given fields: ctx, metadata
+func (_m *Plugin) UpsertTokenMetadata(ctx context.Context, metadata *core.TokenMetadata) error {
+	ret := _m.Called(ctx, metadata)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenMetadata) error); ok {
+		r0 = rf(ctx, metadata)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpsertTokenPool provides a mock function with given fields: ctx, pool, optimization
 func (_m *Plugin) UpsertTokenPool(ctx context.Context, pool *core.TokenPool, optimization database.UpsertOptimization) error {
 	ret := _m.Called(ctx, pool, optimization)
@@ -3315,6 +5557,34 @@ func (_m *Plugin) UpsertTokenPool(ctx context.Context, pool *core.TokenPool, opt
 	return r0
 }
 
+// UpsertTokenTransferPolicy provides a mock function with given fields: ctx, policy
+func (_m *Plugin) UpsertTokenTransferPolicy(ctx context.Context, policy *core.TokenTransferPolicy) error {
+	ret := _m.Called(ctx, policy)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TokenTransferPolicy) error); ok {
+		r0 = rf(ctx, policy)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertTopicSequence provides a mock function with given fields: ctx, ts
+func (_m *Plugin) UpsertTopicSequence(ctx context.Context, ts *core.TopicSequence) error {
+	ret := _m.Called(ctx, ts)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TopicSequence) error); ok {
+		r0 = rf(ctx, ts)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpsertVerifier provides a mock function with given fields: ctx, data, optimization
 func (_m *Plugin) UpsertVerifier(ctx context.Context, data *core.Verifier, optimization database.UpsertOptimization) error {
 	ret := _m.Called(ctx, data, optimization)
@@ -93,6 +93,32 @@ func (_m *Plugin) DownloadBlob(ctx context.Context, payloadRef string) (io.ReadC
 	return r0, r1
 }
 
+// DownloadBlobRange provides a mock function with given fields: ctx, payloadRef, offset, length
+func (_m *Plugin) DownloadBlobRange(ctx context.Context, payloadRef string, offset int64, length int64) (io.ReadCloser, error) {
+	ret := _m.Called(ctx, payloadRef, offset, length)
+
+	var r0 io.ReadCloser
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) (io.ReadCloser, error)); ok {
+		return rf(ctx, payloadRef, offset, length)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) io.ReadCloser); ok {
+		r0 = rf(ctx, payloadRef, offset, length)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) error); ok {
+		r1 = rf(ctx, payloadRef, offset, length)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEndpointInfo provides a mock function with given fields: ctx, nodeName
 func (_m *Plugin) GetEndpointInfo(ctx context.Context, nodeName string) (fftypes.JSONObject, error) {
 	ret := _m.Called(ctx, nodeName)
@@ -22,6 +22,32 @@ type Manager struct {
 	mock.Mock
 }
 
+// AppendBlobUpload provides a mock function with given fields: ctx, id, offset, chunkHash, chunk
+func (_m *Manager) AppendBlobUpload(ctx context.Context, id string, offset int64, chunkHash *fftypes.Bytes32, chunk *ffapi.Multipart) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, id, offset, chunkHash, chunk)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *fftypes.Bytes32, *ffapi.Multipart) (*core.BlobUpload, error)); ok {
+		return rf(ctx, id, offset, chunkHash, chunk)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, *fftypes.Bytes32, *ffapi.Multipart) *core.BlobUpload); ok {
+		r0 = rf(ctx, id, offset, chunkHash, chunk)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, *fftypes.Bytes32, *ffapi.Multipart) error); ok {
+		r1 = rf(ctx, id, offset, chunkHash, chunk)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // BlobsEnabled provides a mock function with given fields:
 func (_m *Manager) BlobsEnabled() bool {
 	ret := _m.Called()
@@ -50,6 +76,88 @@ func (_m *Manager) CheckDatatype(ctx context.Context, datatype *core.Datatype) e
 	return r0
 }
 
+// CheckDatatypeCompatibility provides a mock function with given fields: ctx, previous, datatype
+func (_m *Manager) CheckDatatypeCompatibility(ctx context.Context, previous *core.Datatype, datatype *core.Datatype) error {
+	ret := _m.Called(ctx, previous, datatype)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Datatype, *core.Datatype) error); ok {
+		r0 = rf(ctx, previous, datatype)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CheckParentMessage provides a mock function with given fields: ctx, msg
+func (_m *Manager) CheckParentMessage(ctx context.Context, msg *core.Message) error {
+	ret := _m.Called(ctx, msg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message) error); ok {
+		r0 = rf(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteBlobUpload provides a mock function with given fields: ctx, id
+func (_m *Manager) CompleteBlobUpload(ctx context.Context, id string) (*core.Data, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.Data
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Data, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Data); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Data)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteBlobPayload provides a mock function with given fields: ctx, payloadRef
+func (_m *Manager) DeleteBlobPayload(ctx context.Context, payloadRef string) error {
+	ret := _m.Called(ctx, payloadRef)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, payloadRef)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteBlobUpload provides a mock function with given fields: ctx, id
+func (_m *Manager) DeleteBlobUpload(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteData provides a mock function with given fields: ctx, dataID
 func (_m *Manager) DeleteData(ctx context.Context, dataID string) error {
 	ret := _m.Called(ctx, dataID)
@@ -99,6 +207,119 @@ func (_m *Manager) DownloadBlob(ctx context.Context, dataID string) (*core.Blob,
 	return r0, r1, r2
 }
 
+// DownloadBlobRange provides a mock function with given fields: ctx, dataID, br
+func (_m *Manager) DownloadBlobRange(ctx context.Context, dataID string, br *core.BlobRange) (*data.BlobDownload, error) {
+	ret := _m.Called(ctx, dataID, br)
+
+	var r0 *data.BlobDownload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.BlobRange) (*data.BlobDownload, error)); ok {
+		return rf(ctx, dataID, br)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.BlobRange) *data.BlobDownload); ok {
+		r0 = rf(ctx, dataID, br)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.BlobDownload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.BlobRange) error); ok {
+		r1 = rf(ctx, dataID, br)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlobUpload provides a mock function with given fields: ctx, id
+func (_m *Manager) GetBlobUpload(ctx context.Context, id string) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.BlobUpload, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.BlobUpload); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlobUploads provides a mock function with given fields: ctx, filter
+func (_m *Manager) GetBlobUploads(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlobUpload, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.BlobUpload
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.BlobUpload, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.BlobUpload); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetLatestDatatype provides a mock function with given fields: ctx, name
+func (_m *Manager) GetLatestDatatype(ctx context.Context, name string) (*core.Datatype, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *core.Datatype
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Datatype, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Datatype); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Datatype)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMessageDataCached provides a mock function with given fields: ctx, msg, options
 func (_m *Manager) GetMessageDataCached(ctx context.Context, msg *core.Message, options ...data.CacheReadOption) (core.DataArray, bool, error) {
 	_va := make([]interface{}, len(options))
@@ -214,6 +435,32 @@ func (_m *Manager) HydrateBatch(ctx context.Context, persistedBatch *core.BatchP
 	return r0, r1
 }
 
+// InitBlobUpload provides a mock function with given fields: ctx, input
+func (_m *Manager) InitBlobUpload(ctx context.Context, input *core.BlobUploadInput) (*core.BlobUpload, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *core.BlobUpload
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUploadInput) (*core.BlobUpload, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlobUploadInput) *core.BlobUpload); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlobUpload)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.BlobUploadInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // PeekMessageCache provides a mock function with given fields: ctx, id, options
 func (_m *Manager) PeekMessageCache(ctx context.Context, id *fftypes.UUID, options ...data.CacheReadOption) (*core.Message, core.DataArray) {
 	_va := make([]interface{}, len(options))
@@ -263,11 +510,61 @@ func (_m *Manager) ResolveInlineData(ctx context.Context, msg *data.NewMessage)
 	return r0
 }
 
+// ScanBlob provides a mock function with given fields: ctx, payloadRef
+func (_m *Manager) ScanBlob(ctx context.Context, payloadRef string) (string, error) {
+	ret := _m.Called(ctx, payloadRef)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, payloadRef)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, payloadRef)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, payloadRef)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Manager) Start() {
 	_m.Called()
 }
 
+// TranscodeDataValue provides a mock function with given fields: ctx, _a1
+func (_m *Manager) TranscodeDataValue(ctx context.Context, _a1 *core.Data) (*fftypes.JSONAny, error) {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 *fftypes.JSONAny
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Data) (*fftypes.JSONAny, error)); ok {
+		return rf(ctx, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Data) *fftypes.JSONAny); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.JSONAny)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Data) error); ok {
+		r1 = rf(ctx, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateMessageCache provides a mock function with given fields: msg, _a1
 func (_m *Manager) UpdateMessageCache(msg *core.Message, _a1 core.DataArray) {
 	_m.Called(msg, _a1)
@@ -27,6 +27,46 @@ type EventManager struct {
 	mock.Mock
 }
 
+// AckMessage provides a mock function with given fields: ctx, msg, outcome, input
+func (_m *EventManager) AckMessage(ctx context.Context, msg *core.Message, outcome fftypes.FFEnum, input *core.MessageAckInput) (*core.MessageAck, error) {
+	ret := _m.Called(ctx, msg, outcome, input)
+
+	var r0 *core.MessageAck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, fftypes.FFEnum, *core.MessageAckInput) (*core.MessageAck, error)); ok {
+		return rf(ctx, msg, outcome, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, fftypes.FFEnum, *core.MessageAckInput) *core.MessageAck); ok {
+		r0 = rf(ctx, msg, outcome, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MessageAck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Message, fftypes.FFEnum, *core.MessageAckInput) error); ok {
+		r1 = rf(ctx, msg, outcome, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AckSubscriptionEvents provides a mock function with given fields: ctx, subDef, ack
+func (_m *EventManager) AckSubscriptionEvents(ctx context.Context, subDef *core.Subscription, ack *core.SubscriptionAck) error {
+	ret := _m.Called(ctx, subDef, ack)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Subscription, *core.SubscriptionAck) error); ok {
+		r0 = rf(ctx, subDef, ack)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // AddSystemEventListener provides a mock function with given fields: ns, el
 func (_m *EventManager) AddSystemEventListener(ns string, el system.EventListener) error {
 	ret := _m.Called(ns, el)
@@ -55,6 +95,34 @@ func (_m *EventManager) BlockchainEventBatch(batch []*blockchain.EventToDispatch
 	return r0
 }
 
+// CreateEventRule provides a mock function with given fields: ctx, rule
+func (_m *EventManager) CreateEventRule(ctx context.Context, rule *core.EventRule) error {
+	ret := _m.Called(ctx, rule)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.EventRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSubscriptionTemplate provides a mock function with given fields: ctx, template
+func (_m *EventManager) CreateSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) error {
+	ret := _m.Called(ctx, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SubscriptionTemplate) error); ok {
+		r0 = rf(ctx, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateUpdateDurableSubscription provides a mock function with given fields: ctx, subDef, mustNew
 func (_m *EventManager) CreateUpdateDurableSubscription(ctx context.Context, subDef *core.Subscription, mustNew bool) error {
 	ret := _m.Called(ctx, subDef, mustNew)
@@ -97,6 +165,34 @@ func (_m *EventManager) DeleteDurableSubscription(ctx context.Context, subDef *c
 	return r0
 }
 
+// DeleteEventRule provides a mock function with given fields: ctx, rule
+func (_m *EventManager) DeleteEventRule(ctx context.Context, rule *core.EventRule) error {
+	ret := _m.Called(ctx, rule)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.EventRule) error); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSubscriptionTemplate provides a mock function with given fields: ctx, template
+func (_m *EventManager) DeleteSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) error {
+	ret := _m.Called(ctx, template)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SubscriptionTemplate) error); ok {
+		r0 = rf(ctx, template)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeletedSubscriptions provides a mock function with given fields:
 func (_m *EventManager) DeletedSubscriptions() chan<- *fftypes.UUID {
 	ret := _m.Called()
@@ -113,6 +209,84 @@ func (_m *EventManager) DeletedSubscriptions() chan<- *fftypes.UUID {
 	return r0
 }
 
+// DiscardDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *EventManager) DiscardDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, deadLetter)
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) (*core.DeadLetter, error)); ok {
+		return rf(ctx, deadLetter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) *core.DeadLetter); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.DeadLetter) error); ok {
+		r1 = rf(ctx, deadLetter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardQuarantinedBatch provides a mock function with given fields: ctx, quarantined
+func (_m *EventManager) DiscardQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) (*core.QuarantinedBatch, error) {
+	ret := _m.Called(ctx, quarantined)
+
+	var r0 *core.QuarantinedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBatch) (*core.QuarantinedBatch, error)); ok {
+		return rf(ctx, quarantined)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBatch) *core.QuarantinedBatch); ok {
+		r0 = rf(ctx, quarantined)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.QuarantinedBatch) error); ok {
+		r1 = rf(ctx, quarantined)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardQuarantinedBlob provides a mock function with given fields: ctx, quarantined
+func (_m *EventManager) DiscardQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) (*core.QuarantinedBlob, error) {
+	ret := _m.Called(ctx, quarantined)
+
+	var r0 *core.QuarantinedBlob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBlob) (*core.QuarantinedBlob, error)); ok {
+		return rf(ctx, quarantined)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBlob) *core.QuarantinedBlob); ok {
+		r0 = rf(ctx, quarantined)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBlob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.QuarantinedBlob) error); ok {
+		r1 = rf(ctx, quarantined)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EnrichEvent provides a mock function with given fields: ctx, event
 func (_m *EventManager) EnrichEvent(ctx context.Context, event *core.Event) (*core.EnrichedEvent, error) {
 	ret := _m.Called(ctx, event)
@@ -203,11 +377,141 @@ func (_m *EventManager) NewSubscriptions() chan<- *fftypes.UUID {
 	return r0
 }
 
+// PullSubscriptionEvents provides a mock function with given fields: ctx, subDef, req
+func (_m *EventManager) PullSubscriptionEvents(ctx context.Context, subDef *core.Subscription, req *core.SubscriptionPull) ([]*core.PulledEvent, error) {
+	ret := _m.Called(ctx, subDef, req)
+
+	var r0 []*core.PulledEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Subscription, *core.SubscriptionPull) ([]*core.PulledEvent, error)); ok {
+		return rf(ctx, subDef, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Subscription, *core.SubscriptionPull) []*core.PulledEvent); ok {
+		r0 = rf(ctx, subDef, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.PulledEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Subscription, *core.SubscriptionPull) error); ok {
+		r1 = rf(ctx, subDef, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // QueueBatchRewind provides a mock function with given fields: batchID
 func (_m *EventManager) QueueBatchRewind(batchID *fftypes.UUID) {
 	_m.Called(batchID)
 }
 
+// ReleaseQuarantinedBatch provides a mock function with given fields: ctx, quarantined
+func (_m *EventManager) ReleaseQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) (*core.QuarantinedBatch, error) {
+	ret := _m.Called(ctx, quarantined)
+
+	var r0 *core.QuarantinedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBatch) (*core.QuarantinedBatch, error)); ok {
+		return rf(ctx, quarantined)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBatch) *core.QuarantinedBatch); ok {
+		r0 = rf(ctx, quarantined)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.QuarantinedBatch) error); ok {
+		r1 = rf(ctx, quarantined)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseQuarantinedBlob provides a mock function with given fields: ctx, quarantined
+func (_m *EventManager) ReleaseQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) (*core.QuarantinedBlob, error) {
+	ret := _m.Called(ctx, quarantined)
+
+	var r0 *core.QuarantinedBlob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBlob) (*core.QuarantinedBlob, error)); ok {
+		return rf(ctx, quarantined)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.QuarantinedBlob) *core.QuarantinedBlob); ok {
+		r0 = rf(ctx, quarantined)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBlob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.QuarantinedBlob) error); ok {
+		r1 = rf(ctx, quarantined)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequeueDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *EventManager) RequeueDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, deadLetter)
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) (*core.DeadLetter, error)); ok {
+		return rf(ctx, deadLetter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) *core.DeadLetter); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.DeadLetter) error); ok {
+		r1 = rf(ctx, deadLetter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RewindDurableSubscription provides a mock function with given fields: ctx, subDef, rewind
+func (_m *EventManager) RewindDurableSubscription(ctx context.Context, subDef *core.Subscription, rewind *core.SubscriptionRewind) (*core.SubscriptionRewind, error) {
+	ret := _m.Called(ctx, subDef, rewind)
+
+	var r0 *core.SubscriptionRewind
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Subscription, *core.SubscriptionRewind) (*core.SubscriptionRewind, error)); ok {
+		return rf(ctx, subDef, rewind)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Subscription, *core.SubscriptionRewind) *core.SubscriptionRewind); ok {
+		r0 = rf(ctx, subDef, rewind)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionRewind)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Subscription, *core.SubscriptionRewind) error); ok {
+		r1 = rf(ctx, subDef, rewind)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SharedStorageBatchDownloaded provides a mock function with given fields: ss, payloadRef, data
 func (_m *EventManager) SharedStorageBatchDownloaded(ss sharedstorage.Plugin, payloadRef string, data []byte) (*fftypes.UUID, error) {
 	ret := _m.Called(ss, payloadRef, data)
@@ -278,6 +582,20 @@ func (_m *EventManager) SubscriptionUpdates() chan<- *fftypes.UUID {
 	return r0
 }
 
+// TokenEventRemoved provides a mock function with given fields: ti, event
+func (_m *EventManager) TokenEventRemoved(ti tokens.Plugin, event *blockchain.Event) error {
+	ret := _m.Called(ti, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(tokens.Plugin, *blockchain.Event) error); ok {
+		r0 = rf(ti, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TokenPoolCreated provides a mock function with given fields: ctx, ti, pool
 func (_m *EventManager) TokenPoolCreated(ctx context.Context, ti tokens.Plugin, pool *tokens.TokenPool) error {
 	ret := _m.Called(ctx, ti, pool)
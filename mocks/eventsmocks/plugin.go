@@ -21,6 +21,20 @@ type Plugin struct {
 	mock.Mock
 }
 
+// BatchDeliveryRequest provides a mock function with given fields: connID, sub, _a2
+func (_m *Plugin) BatchDeliveryRequest(connID string, sub *core.Subscription, _a2 []*core.CombinedEventDataDelivery) error {
+	ret := _m.Called(connID, sub, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *core.Subscription, []*core.CombinedEventDataDelivery) error); ok {
+		r0 = rf(connID, sub, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Capabilities provides a mock function with given fields:
 func (_m *Plugin) Capabilities() *events.Capabilities {
 	ret := _m.Called()
@@ -79,6 +79,11 @@ func (_m *Manager) IsMetricsEnabled() bool {
 	return r0
 }
 
+// MessageConfirmLatency provides a mock function with given fields: namespace, org, latency
+func (_m *Manager) MessageConfirmLatency(namespace string, org string, latency time.Duration) {
+	_m.Called(namespace, org, latency)
+}
+
 // MessageConfirmed provides a mock function with given fields: msg, eventType
 func (_m *Manager) MessageConfirmed(msg *core.Message, eventType fftypes.FFEnum) {
 	_m.Called(msg, eventType)
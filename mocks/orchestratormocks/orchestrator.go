@@ -28,6 +28,8 @@ import (
 
 	identity "github.com/hyperledger/firefly/internal/identity"
 
+	jobs "github.com/hyperledger/firefly/internal/jobs"
+
 	mock "github.com/stretchr/testify/mock"
 
 	multiparty "github.com/hyperledger/firefly/internal/multiparty"
@@ -44,6 +46,46 @@ type Orchestrator struct {
 	mock.Mock
 }
 
+// AcceptMessage provides a mock function with given fields: ctx, id, input
+func (_m *Orchestrator) AcceptMessage(ctx context.Context, id string, input *core.MessageAckInput) (*core.MessageAck, error) {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 *core.MessageAck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageAckInput) (*core.MessageAck, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageAckInput) *core.MessageAck); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MessageAck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.MessageAckInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AckSubscription provides a mock function with given fields: ctx, id, ack
+func (_m *Orchestrator) AckSubscription(ctx context.Context, id string, ack *core.SubscriptionAck) error {
+	ret := _m.Called(ctx, id, ack)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.SubscriptionAck) error); ok {
+		r0 = rf(ctx, id, ack)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Assets provides a mock function with given fields:
 func (_m *Orchestrator) Assets() assets.Manager {
 	ret := _m.Called()
@@ -74,6 +116,20 @@ func (_m *Orchestrator) Authorize(ctx context.Context, authReq *fftypes.AuthReq)
 	return r0
 }
 
+// AuthorizeRole provides a mock function with given fields: ctx, authReq, required
+func (_m *Orchestrator) AuthorizeRole(ctx context.Context, authReq *fftypes.AuthReq, required fftypes.FFEnum) error {
+	ret := _m.Called(ctx, authReq, required)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.AuthReq, fftypes.FFEnum) error); ok {
+		r0 = rf(ctx, authReq, required)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BatchManager provides a mock function with given fields:
 func (_m *Orchestrator) BatchManager() batch.Manager {
 	ret := _m.Called()
@@ -122,6 +178,58 @@ func (_m *Orchestrator) Contracts() contracts.Manager {
 	return r0
 }
 
+// CreateEventRule provides a mock function with given fields: ctx, rule
+func (_m *Orchestrator) CreateEventRule(ctx context.Context, rule *core.EventRule) (*core.EventRule, error) {
+	ret := _m.Called(ctx, rule)
+
+	var r0 *core.EventRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.EventRule) (*core.EventRule, error)); ok {
+		return rf(ctx, rule)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.EventRule) *core.EventRule); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.EventRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.EventRule) error); ok {
+		r1 = rf(ctx, rule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateRoleBinding provides a mock function with given fields: ctx, binding
+func (_m *Orchestrator) CreateRoleBinding(ctx context.Context, binding *core.RoleBinding) (*core.RoleBinding, error) {
+	ret := _m.Called(ctx, binding)
+
+	var r0 *core.RoleBinding
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.RoleBinding) (*core.RoleBinding, error)); ok {
+		return rf(ctx, binding)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.RoleBinding) *core.RoleBinding); ok {
+		r0 = rf(ctx, binding)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.RoleBinding)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.RoleBinding) error); ok {
+		r1 = rf(ctx, binding)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateSubscription provides a mock function with given fields: ctx, subDef
 func (_m *Orchestrator) CreateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error) {
 	ret := _m.Called(ctx, subDef)
@@ -148,6 +256,32 @@ func (_m *Orchestrator) CreateSubscription(ctx context.Context, subDef *core.Sub
 	return r0, r1
 }
 
+// CreateSubscriptionTemplate provides a mock function with given fields: ctx, template
+func (_m *Orchestrator) CreateSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) (*core.SubscriptionTemplate, error) {
+	ret := _m.Called(ctx, template)
+
+	var r0 *core.SubscriptionTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SubscriptionTemplate) (*core.SubscriptionTemplate, error)); ok {
+		return rf(ctx, template)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.SubscriptionTemplate) *core.SubscriptionTemplate); ok {
+		r0 = rf(ctx, template)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.SubscriptionTemplate) error); ok {
+		r1 = rf(ctx, template)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateUpdateSubscription provides a mock function with given fields: ctx, subDef
 func (_m *Orchestrator) CreateUpdateSubscription(ctx context.Context, subDef *core.Subscription) (*core.Subscription, error) {
 	ret := _m.Called(ctx, subDef)
@@ -206,6 +340,34 @@ func (_m *Orchestrator) DefinitionSender() definitions.Sender {
 	return r0
 }
 
+// DeleteEventRule provides a mock function with given fields: ctx, nameOrID
+func (_m *Orchestrator) DeleteEventRule(ctx context.Context, nameOrID string) error {
+	ret := _m.Called(ctx, nameOrID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, nameOrID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteRoleBinding provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) DeleteRoleBinding(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteSubscription provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) DeleteSubscription(ctx context.Context, id string) error {
 	ret := _m.Called(ctx, id)
@@ -220,6 +382,98 @@ func (_m *Orchestrator) DeleteSubscription(ctx context.Context, id string) error
 	return r0
 }
 
+// DeleteSubscriptionTemplate provides a mock function with given fields: ctx, nameOrID
+func (_m *Orchestrator) DeleteSubscriptionTemplate(ctx context.Context, nameOrID string) error {
+	ret := _m.Called(ctx, nameOrID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, nameOrID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DiscardDeadLetter provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) DiscardDeadLetter(ctx context.Context, id string) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.DeadLetter, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.DeadLetter); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardQuarantinedBatch provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) DiscardQuarantinedBatch(ctx context.Context, id string) (*core.QuarantinedBatch, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.QuarantinedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.QuarantinedBatch, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.QuarantinedBatch); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DiscardQuarantinedBlob provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) DiscardQuarantinedBlob(ctx context.Context, id string) (*core.QuarantinedBlob, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.QuarantinedBlob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.QuarantinedBlob, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.QuarantinedBlob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBlob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Events provides a mock function with given fields:
 func (_m *Orchestrator) Events() events.EventManager {
 	ret := _m.Called()
@@ -236,6 +490,41 @@ func (_m *Orchestrator) Events() events.EventManager {
 	return r0
 }
 
+// GetAuditLog provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetAuditLog(ctx context.Context, filter ffapi.AndFilter) ([]*core.AuditLogEntry, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.AuditLogEntry
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.AuditLogEntry, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.AuditLogEntry); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.AuditLogEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetBatchByID(ctx context.Context, id string) (*core.BatchPersisted, error) {
 	ret := _m.Called(ctx, id)
@@ -558,6 +847,67 @@ func (_m *Orchestrator) GetDatatypes(ctx context.Context, filter ffapi.AndFilter
 	return r0, r1, r2
 }
 
+// GetDeadLetterByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetDeadLetterByID(ctx context.Context, id string) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.DeadLetter, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.DeadLetter); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetDeadLetters(ctx context.Context, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.DeadLetter
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.DeadLetter); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetEventByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetEventByID(ctx context.Context, id string) (*core.Event, error) {
 	ret := _m.Called(ctx, id)
@@ -610,14 +960,75 @@ func (_m *Orchestrator) GetEventByIDWithReference(ctx context.Context, id string
 	return r0, r1
 }
 
-// GetEvents provides a mock function with given fields: ctx, filter
-func (_m *Orchestrator) GetEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, filter)
+// GetEventRuleByNameOrID provides a mock function with given fields: ctx, nameOrID
+func (_m *Orchestrator) GetEventRuleByNameOrID(ctx context.Context, nameOrID string) (*core.EventRule, error) {
+	ret := _m.Called(ctx, nameOrID)
 
-	var r0 []*core.Event
-	var r1 *ffapi.FilterResult
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error)); ok {
+	var r0 *core.EventRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.EventRule, error)); ok {
+		return rf(ctx, nameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.EventRule); ok {
+		r0 = rf(ctx, nameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.EventRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, nameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventRules provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetEventRules(ctx context.Context, filter ffapi.AndFilter) ([]*core.EventRule, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.EventRule
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.EventRule, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.EventRule); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.EventRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetEvents provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.Event
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, filter)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Event); ok {
@@ -680,6 +1091,41 @@ func (_m *Orchestrator) GetEventsWithReferences(ctx context.Context, filter ffap
 	return r0, r1, r2
 }
 
+// GetMessageAcks provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetMessageAcks(ctx context.Context, filter ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.MessageAck
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.MessageAck, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.MessageAck); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.MessageAck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetMessageByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetMessageByID(ctx context.Context, id string) (*core.Message, error) {
 	ret := _m.Called(ctx, id)
@@ -793,6 +1239,32 @@ func (_m *Orchestrator) GetMessageEvents(ctx context.Context, id string, filter
 	return r0, r1, r2
 }
 
+// GetMessageThread provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetMessageThread(ctx context.Context, id string) (*core.MessageThread, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.MessageThread
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.MessageThread, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.MessageThread); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MessageThread)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetMessageTransaction provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetMessageTransaction(ctx context.Context, id string) (*core.Transaction, error) {
 	ret := _m.Called(ctx, id)
@@ -819,34 +1291,41 @@ func (_m *Orchestrator) GetMessageTransaction(ctx context.Context, id string) (*
 	return r0, r1
 }
 
-// GetMessages provides a mock function with given fields: ctx, filter
-func (_m *Orchestrator) GetMessages(ctx context.Context, filter ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, filter)
+// GetMessages provides a mock function with given fields: ctx, filter, fields
+func (_m *Orchestrator) GetMessages(ctx context.Context, filter ffapi.AndFilter, fields ...string) ([]*core.Message, *ffapi.FilterResult, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, filter)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*core.Message
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Message, *ffapi.FilterResult, error)); ok {
-		return rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, ...string) ([]*core.Message, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter, fields...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Message); ok {
-		r0 = rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, ...string) []*core.Message); ok {
+		r0 = rf(ctx, filter, fields...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*core.Message)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
-		r1 = rf(ctx, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter, ...string) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter, fields...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*ffapi.FilterResult)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
-		r2 = rf(ctx, filter)
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter, ...string) error); ok {
+		r2 = rf(ctx, filter, fields...)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -940,6 +1419,32 @@ func (_m *Orchestrator) GetNamespace(ctx context.Context) *core.Namespace {
 	return r0
 }
 
+// GetNetworkStatus provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetNetworkStatus(ctx context.Context, filter ffapi.AndFilter) (*core.NetworkStatus, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 *core.NetworkStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) (*core.NetworkStatus, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) *core.NetworkStatus); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NetworkStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNextPins provides a mock function with given fields: ctx, filter
 func (_m *Orchestrator) GetNextPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.NextPin, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -1027,34 +1532,41 @@ func (_m *Orchestrator) GetOperationByIDWithStatus(ctx context.Context, id strin
 	return r0, r1
 }
 
-// GetOperations provides a mock function with given fields: ctx, filter
-func (_m *Orchestrator) GetOperations(ctx context.Context, filter ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, filter)
+// GetOperations provides a mock function with given fields: ctx, filter, fields
+func (_m *Orchestrator) GetOperations(ctx context.Context, filter ffapi.AndFilter, fields ...string) ([]*core.Operation, *ffapi.FilterResult, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, filter)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []*core.Operation
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
-		return rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, ...string) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter, fields...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Operation); ok {
-		r0 = rf(ctx, filter)
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter, ...string) []*core.Operation); ok {
+		r0 = rf(ctx, filter, fields...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*core.Operation)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
-		r1 = rf(ctx, filter)
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter, ...string) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter, fields...)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*ffapi.FilterResult)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
-		r2 = rf(ctx, filter)
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter, ...string) error); ok {
+		r2 = rf(ctx, filter, fields...)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -1097,25 +1609,25 @@ func (_m *Orchestrator) GetPins(ctx context.Context, filter ffapi.AndFilter) ([]
 	return r0, r1, r2
 }
 
-// GetStatus provides a mock function with given fields: ctx
-func (_m *Orchestrator) GetStatus(ctx context.Context) (*core.NamespaceStatus, error) {
-	ret := _m.Called(ctx)
+// GetQuarantinedBatchByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetQuarantinedBatchByID(ctx context.Context, id string) (*core.QuarantinedBatch, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 *core.NamespaceStatus
+	var r0 *core.QuarantinedBatch
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (*core.NamespaceStatus, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.QuarantinedBatch, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) *core.NamespaceStatus); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.QuarantinedBatch); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.NamespaceStatus)
+			r0 = ret.Get(0).(*core.QuarantinedBatch)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -1123,46 +1635,55 @@ func (_m *Orchestrator) GetStatus(ctx context.Context) (*core.NamespaceStatus, e
 	return r0, r1
 }
 
-// GetSubscriptionByID provides a mock function with given fields: ctx, id
-func (_m *Orchestrator) GetSubscriptionByID(ctx context.Context, id string) (*core.Subscription, error) {
-	ret := _m.Called(ctx, id)
+// GetQuarantinedBatches provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetQuarantinedBatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.QuarantinedBatch, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
 
-	var r0 *core.Subscription
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Subscription, error)); ok {
-		return rf(ctx, id)
+	var r0 []*core.QuarantinedBatch
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.QuarantinedBatch, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Subscription); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.QuarantinedBatch); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Subscription)
+			r0 = ret.Get(0).([]*core.QuarantinedBatch)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
 	}
 
-	return r0, r1
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
 }
 
-// GetSubscriptionByIDWithStatus provides a mock function with given fields: ctx, id
-func (_m *Orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id string) (*core.SubscriptionWithStatus, error) {
+// GetQuarantinedBlobByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetQuarantinedBlobByID(ctx context.Context, id string) (*core.QuarantinedBlob, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 *core.SubscriptionWithStatus
+	var r0 *core.QuarantinedBlob
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.SubscriptionWithStatus, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.QuarantinedBlob, error)); ok {
 		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *core.SubscriptionWithStatus); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.QuarantinedBlob); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.SubscriptionWithStatus)
+			r0 = ret.Get(0).(*core.QuarantinedBlob)
 		}
 	}
 
@@ -1175,21 +1696,21 @@ func (_m *Orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id st
 	return r0, r1
 }
 
-// GetSubscriptions provides a mock function with given fields: ctx, filter
-func (_m *Orchestrator) GetSubscriptions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error) {
+// GetQuarantinedBlobs provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetQuarantinedBlobs(ctx context.Context, filter ffapi.AndFilter) ([]*core.QuarantinedBlob, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
 
-	var r0 []*core.Subscription
+	var r0 []*core.QuarantinedBlob
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.QuarantinedBlob, *ffapi.FilterResult, error)); ok {
 		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Subscription); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.QuarantinedBlob); ok {
 		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.Subscription)
+			r0 = ret.Get(0).([]*core.QuarantinedBlob)
 		}
 	}
 
@@ -1210,34 +1731,60 @@ func (_m *Orchestrator) GetSubscriptions(ctx context.Context, filter ffapi.AndFi
 	return r0, r1, r2
 }
 
-// GetTransactionBlockchainEvents provides a mock function with given fields: ctx, id
-func (_m *Orchestrator) GetTransactionBlockchainEvents(ctx context.Context, id string) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
+// GetRoleBindingByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetRoleBindingByID(ctx context.Context, id string) (*core.RoleBinding, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 []*core.BlockchainEvent
-	var r1 *ffapi.FilterResult
-	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)); ok {
+	var r0 *core.RoleBinding
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.RoleBinding, error)); ok {
 		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*core.BlockchainEvent); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.RoleBinding); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.BlockchainEvent)
+			r0 = ret.Get(0).(*core.RoleBinding)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) *ffapi.FilterResult); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
 		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRoleBindings provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetRoleBindings(ctx context.Context, filter ffapi.AndFilter) ([]*core.RoleBinding, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.RoleBinding
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.RoleBinding, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.RoleBinding); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.RoleBinding)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*ffapi.FilterResult)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
-		r2 = rf(ctx, id)
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -1245,20 +1792,20 @@ func (_m *Orchestrator) GetTransactionBlockchainEvents(ctx context.Context, id s
 	return r0, r1, r2
 }
 
-// GetTransactionByID provides a mock function with given fields: ctx, id
-func (_m *Orchestrator) GetTransactionByID(ctx context.Context, id string) (*core.Transaction, error) {
+// GetSLABreachByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetSLABreachByID(ctx context.Context, id string) (*core.SLABreach, error) {
 	ret := _m.Called(ctx, id)
 
-	var r0 *core.Transaction
+	var r0 *core.SLABreach
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Transaction, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.SLABreach, error)); ok {
 		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Transaction); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.SLABreach); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*core.Transaction)
+			r0 = ret.Get(0).(*core.SLABreach)
 		}
 	}
 
@@ -1271,35 +1818,340 @@ func (_m *Orchestrator) GetTransactionByID(ctx context.Context, id string) (*cor
 	return r0, r1
 }
 
-// GetTransactionOperations provides a mock function with given fields: ctx, id
-func (_m *Orchestrator) GetTransactionOperations(ctx context.Context, id string) ([]*core.Operation, *ffapi.FilterResult, error) {
-	ret := _m.Called(ctx, id)
+// GetSLABreaches provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetSLABreaches(ctx context.Context, filter ffapi.AndFilter) ([]*core.SLABreach, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
 
-	var r0 []*core.Operation
+	var r0 []*core.SLABreach
 	var r1 *ffapi.FilterResult
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
-		return rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.SLABreach, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []*core.Operation); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.SLABreach); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*core.Operation)
+			r0 = ret.Get(0).([]*core.SLABreach)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) *ffapi.FilterResult); ok {
-		r1 = rf(ctx, id)
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*ffapi.FilterResult)
 		}
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
-		r2 = rf(ctx, id)
-	} else {
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetStatus provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetStatus(ctx context.Context) (*core.NamespaceStatus, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *core.NamespaceStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.NamespaceStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.NamespaceStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NamespaceStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetSubscriptionByID(ctx context.Context, id string) (*core.Subscription, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Subscription, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Subscription); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionByIDWithStatus provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id string) (*core.SubscriptionWithStatus, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.SubscriptionWithStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.SubscriptionWithStatus, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.SubscriptionWithStatus); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionWithStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionTemplateByNameOrID provides a mock function with given fields: ctx, nameOrID
+func (_m *Orchestrator) GetSubscriptionTemplateByNameOrID(ctx context.Context, nameOrID string) (*core.SubscriptionTemplate, error) {
+	ret := _m.Called(ctx, nameOrID)
+
+	var r0 *core.SubscriptionTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.SubscriptionTemplate, error)); ok {
+		return rf(ctx, nameOrID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.SubscriptionTemplate); ok {
+		r0 = rf(ctx, nameOrID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, nameOrID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionTemplates provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetSubscriptionTemplates(ctx context.Context, filter ffapi.AndFilter) ([]*core.SubscriptionTemplate, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.SubscriptionTemplate
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.SubscriptionTemplate, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.SubscriptionTemplate); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.SubscriptionTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSubscriptions provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetSubscriptions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.Subscription
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.Subscription); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTopicSequences provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetTopicSequences(ctx context.Context, filter ffapi.AndFilter) ([]*core.TopicSequence, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*core.TopicSequence
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.TopicSequence, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.TopicSequence); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TopicSequence)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTransactionBlockchainEvents provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetTransactionBlockchainEvents(ctx context.Context, id string) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*core.BlockchainEvent
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*core.BlockchainEvent); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.BlockchainEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetTransactionByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetTransactionByID(ctx context.Context, id string) (*core.Transaction, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.Transaction, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.Transaction); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionOperations provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetTransactionOperations(ctx context.Context, id string) ([]*core.Operation, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []*core.Operation
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*core.Operation, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*core.Operation); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Operation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, id)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, id)
+	} else {
 		r2 = ret.Error(2)
 	}
 
@@ -1397,6 +2249,22 @@ func (_m *Orchestrator) Init() error {
 	return r0
 }
 
+// Jobs provides a mock function with given fields:
+func (_m *Orchestrator) Jobs() jobs.Manager {
+	ret := _m.Called()
+
+	var r0 jobs.Manager
+	if rf, ok := ret.Get(0).(func() jobs.Manager); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(jobs.Manager)
+		}
+	}
+
+	return r0
+}
+
 // MultiParty provides a mock function with given fields:
 func (_m *Orchestrator) MultiParty() multiparty.Manager {
 	ret := _m.Called()
@@ -1466,6 +2334,124 @@ func (_m *Orchestrator) PrivateMessaging() privatemessaging.Manager {
 	return r0
 }
 
+// PullSubscription provides a mock function with given fields: ctx, id, req
+func (_m *Orchestrator) PullSubscription(ctx context.Context, id string, req *core.SubscriptionPull) ([]*core.PulledEvent, error) {
+	ret := _m.Called(ctx, id, req)
+
+	var r0 []*core.PulledEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.SubscriptionPull) ([]*core.PulledEvent, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.SubscriptionPull) []*core.PulledEvent); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.PulledEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.SubscriptionPull) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordAuditLogEntry provides a mock function with given fields: ctx, entry
+func (_m *Orchestrator) RecordAuditLogEntry(ctx context.Context, entry *core.AuditLogEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.AuditLogEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RejectMessage provides a mock function with given fields: ctx, id, input
+func (_m *Orchestrator) RejectMessage(ctx context.Context, id string, input *core.MessageAckInput) (*core.MessageAck, error) {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 *core.MessageAck
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageAckInput) (*core.MessageAck, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageAckInput) *core.MessageAck); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MessageAck)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.MessageAckInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseQuarantinedBatch provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) ReleaseQuarantinedBatch(ctx context.Context, id string) (*core.QuarantinedBatch, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.QuarantinedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.QuarantinedBatch, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.QuarantinedBatch); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseQuarantinedBlob provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) ReleaseQuarantinedBlob(ctx context.Context, id string) (*core.QuarantinedBlob, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.QuarantinedBlob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.QuarantinedBlob, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.QuarantinedBlob); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.QuarantinedBlob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RequestReply provides a mock function with given fields: ctx, msg
 func (_m *Orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut) (*core.MessageInOut, error) {
 	ret := _m.Called(ctx, msg)
@@ -1492,6 +2478,32 @@ func (_m *Orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut
 	return r0, r1
 }
 
+// RequeueDeadLetter provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) RequeueDeadLetter(ctx context.Context, id string) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.DeadLetter, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.DeadLetter); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RewindPins provides a mock function with given fields: ctx, rewind
 func (_m *Orchestrator) RewindPins(ctx context.Context, rewind *core.PinRewind) (*core.PinRewind, error) {
 	ret := _m.Called(ctx, rewind)
@@ -1518,6 +2530,32 @@ func (_m *Orchestrator) RewindPins(ctx context.Context, rewind *core.PinRewind)
 	return r0, r1
 }
 
+// RewindSubscription provides a mock function with given fields: ctx, id, rewind
+func (_m *Orchestrator) RewindSubscription(ctx context.Context, id string, rewind *core.SubscriptionRewind) (*core.SubscriptionRewind, error) {
+	ret := _m.Called(ctx, id, rewind)
+
+	var r0 *core.SubscriptionRewind
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.SubscriptionRewind) (*core.SubscriptionRewind, error)); ok {
+		return rf(ctx, id, rewind)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.SubscriptionRewind) *core.SubscriptionRewind); ok {
+		r0 = rf(ctx, id, rewind)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.SubscriptionRewind)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.SubscriptionRewind) error); ok {
+		r1 = rf(ctx, id, rewind)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Orchestrator) Start() error {
 	ret := _m.Called()
@@ -20,6 +20,70 @@ type Manager struct {
 	mock.Mock
 }
 
+// ChangeGroupMembers provides a mock function with given fields: ctx, hash, input
+func (_m *Manager) ChangeGroupMembers(ctx context.Context, hash string, input *core.GroupMembersInput) (*core.Group, error) {
+	ret := _m.Called(ctx, hash, input)
+
+	var r0 *core.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.GroupMembersInput) (*core.Group, error)); ok {
+		return rf(ctx, hash, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.GroupMembersInput) *core.Group); ok {
+		r0 = rf(ctx, hash, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.GroupMembersInput) error); ok {
+		r1 = rf(ctx, hash, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DecryptBlob provides a mock function with given fields: ctx, blob
+func (_m *Manager) DecryptBlob(ctx context.Context, blob *core.Blob) error {
+	ret := _m.Called(ctx, blob)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Blob) error); ok {
+		r0 = rf(ctx, blob)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DecryptTransport provides a mock function with given fields: ctx, transport
+func (_m *Manager) DecryptTransport(ctx context.Context, transport *core.TransportWrapper) (bool, error) {
+	ret := _m.Called(ctx, transport)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TransportWrapper) (bool, error)); ok {
+		return rf(ctx, transport)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.TransportWrapper) bool); ok {
+		r0 = rf(ctx, transport)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.TransportWrapper) error); ok {
+		r1 = rf(ctx, transport)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // EnsureLocalGroup provides a mock function with given fields: ctx, group, creator
 func (_m *Manager) EnsureLocalGroup(ctx context.Context, group *core.Group, creator *core.Member) (bool, error) {
 	ret := _m.Called(ctx, group, creator)
@@ -44,6 +108,32 @@ func (_m *Manager) EnsureLocalGroup(ctx context.Context, group *core.Group, crea
 	return r0, r1
 }
 
+// GetDisclosureProof provides a mock function with given fields: ctx, id, input
+func (_m *Manager) GetDisclosureProof(ctx context.Context, id string, input *core.DisclosureInput) (*core.DisclosureProof, error) {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 *core.DisclosureProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.DisclosureInput) (*core.DisclosureProof, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.DisclosureInput) *core.DisclosureProof); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DisclosureProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.DisclosureInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetGroupByID provides a mock function with given fields: ctx, id
 func (_m *Manager) GetGroupByID(ctx context.Context, id string) (*core.Group, error) {
 	ret := _m.Called(ctx, id)
@@ -105,6 +195,58 @@ func (_m *Manager) GetGroups(ctx context.Context, filter ffapi.AndFilter) ([]*co
 	return r0, r1, r2
 }
 
+// GetMessageDeliveryStatus provides a mock function with given fields: ctx, id
+func (_m *Manager) GetMessageDeliveryStatus(ctx context.Context, id string) (*core.MessageDeliveryStatus, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *core.MessageDeliveryStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.MessageDeliveryStatus, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.MessageDeliveryStatus); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MessageDeliveryStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkMessageRead provides a mock function with given fields: ctx, id, input
+func (_m *Manager) MarkMessageRead(ctx context.Context, id string, input *core.MessageReadInput) (*core.MessageReceipt, error) {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 *core.MessageReceipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageReadInput) (*core.MessageReceipt, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageReadInput) *core.MessageReceipt); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MessageReceipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.MessageReadInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Name provides a mock function with given fields:
 func (_m *Manager) Name() string {
 	ret := _m.Called()
@@ -161,6 +303,32 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
+// RecallMessage provides a mock function with given fields: ctx, id, input
+func (_m *Manager) RecallMessage(ctx context.Context, id string, input *core.MessageRecallInput) (*core.Message, error) {
+	ret := _m.Called(ctx, id, input)
+
+	var r0 *core.Message
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageRecallInput) (*core.Message, error)); ok {
+		return rf(ctx, id, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MessageRecallInput) *core.Message); ok {
+		r0 = rf(ctx, id, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.MessageRecallInput) error); ok {
+		r1 = rf(ctx, id, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RequestReply provides a mock function with given fields: ctx, request
 func (_m *Manager) RequestReply(ctx context.Context, request *core.MessageInOut) (*core.MessageInOut, error) {
 	ret := _m.Called(ctx, request)
@@ -187,6 +355,37 @@ func (_m *Manager) RequestReply(ctx context.Context, request *core.MessageInOut)
 	return r0, r1
 }
 
+// ResolveGroupChange provides a mock function with given fields: ctx, msg, data
+func (_m *Manager) ResolveGroupChange(ctx context.Context, msg *core.Message, data core.DataArray) (core.MessageAction, bool, error) {
+	ret := _m.Called(ctx, msg, data)
+
+	var r0 core.MessageAction
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, core.DataArray) (core.MessageAction, bool, error)); ok {
+		return rf(ctx, msg, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, core.DataArray) core.MessageAction); ok {
+		r0 = rf(ctx, msg, data)
+	} else {
+		r0 = ret.Get(0).(core.MessageAction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Message, core.DataArray) bool); ok {
+		r1 = rf(ctx, msg, data)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *core.Message, core.DataArray) error); ok {
+		r2 = rf(ctx, msg, data)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // ResolveInitGroup provides a mock function with given fields: ctx, msg, creator
 func (_m *Manager) ResolveInitGroup(ctx context.Context, msg *core.Message, creator *core.Member) (*core.Group, error) {
 	ret := _m.Called(ctx, msg, creator)
@@ -213,6 +412,46 @@ func (_m *Manager) ResolveInitGroup(ctx context.Context, msg *core.Message, crea
 	return r0, r1
 }
 
+// ResolveRecall provides a mock function with given fields: ctx, msg, data
+func (_m *Manager) ResolveRecall(ctx context.Context, msg *core.Message, data core.DataArray) (core.MessageAction, *core.Message, bool, error) {
+	ret := _m.Called(ctx, msg, data)
+
+	var r0 core.MessageAction
+	var r1 *core.Message
+	var r2 bool
+	var r3 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, core.DataArray) (core.MessageAction, *core.Message, bool, error)); ok {
+		return rf(ctx, msg, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Message, core.DataArray) core.MessageAction); ok {
+		r0 = rf(ctx, msg, data)
+	} else {
+		r0 = ret.Get(0).(core.MessageAction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Message, core.DataArray) *core.Message); ok {
+		r1 = rf(ctx, msg, data)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*core.Message)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *core.Message, core.DataArray) bool); ok {
+		r2 = rf(ctx, msg, data)
+	} else {
+		r2 = ret.Get(2).(bool)
+	}
+
+	if rf, ok := ret.Get(3).(func(context.Context, *core.Message, core.DataArray) error); ok {
+		r3 = rf(ctx, msg, data)
+	} else {
+		r3 = ret.Error(3)
+	}
+
+	return r0, r1, r2, r3
+}
+
 // RunOperation provides a mock function with given fields: ctx, op
 func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation) (fftypes.JSONObject, bool, error) {
 	ret := _m.Called(ctx, op)
@@ -246,6 +485,20 @@ func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation)
 	return r0, r1, r2
 }
 
+// SendDeliveryReceipts provides a mock function with given fields: ctx, nodeID, txID, msgs
+func (_m *Manager) SendDeliveryReceipts(ctx context.Context, nodeID *fftypes.UUID, txID *fftypes.UUID, msgs []*core.Message) error {
+	ret := _m.Called(ctx, nodeID, txID, msgs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *fftypes.UUID, []*core.Message) error); ok {
+		r0 = rf(ctx, nodeID, txID, msgs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SendMessage provides a mock function with given fields: ctx, in, waitConfirm
 func (_m *Manager) SendMessage(ctx context.Context, in *core.MessageInOut, waitConfirm bool) (*core.Message, error) {
 	ret := _m.Called(ctx, in, waitConfirm)
@@ -272,6 +525,32 @@ func (_m *Manager) SendMessage(ctx context.Context, in *core.MessageInOut, waitC
 	return r0, r1
 }
 
+// VerifyDisclosureProof provides a mock function with given fields: ctx, proof
+func (_m *Manager) VerifyDisclosureProof(ctx context.Context, proof *core.DisclosureProof) (*core.DisclosureVerification, error) {
+	ret := _m.Called(ctx, proof)
+
+	var r0 *core.DisclosureVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DisclosureProof) (*core.DisclosureVerification, error)); ok {
+		return rf(ctx, proof)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DisclosureProof) *core.DisclosureVerification); ok {
+		r0 = rf(ctx, proof)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DisclosureVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.DisclosureProof) error); ok {
+		r1 = rf(ctx, proof)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewManager interface {
 	mock.TestingT
 	Cleanup(func())
@@ -129,25 +129,25 @@ func (_m *Bridge) WaitForMessage(ctx context.Context, id *fftypes.UUID, send syn
 	return r0, r1
 }
 
-// WaitForReply provides a mock function with given fields: ctx, id, send
-func (_m *Bridge) WaitForReply(ctx context.Context, id *fftypes.UUID, send syncasync.SendFunction) (*core.MessageInOut, error) {
-	ret := _m.Called(ctx, id, send)
+// WaitForReply provides a mock function with given fields: ctx, id, opts, send
+func (_m *Bridge) WaitForReply(ctx context.Context, id *fftypes.UUID, opts *core.ReplyOptions, send syncasync.SendFunction) (*core.MessageInOut, error) {
+	ret := _m.Called(ctx, id, opts, send)
 
 	var r0 *core.MessageInOut
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, syncasync.SendFunction) (*core.MessageInOut, error)); ok {
-		return rf(ctx, id, send)
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.ReplyOptions, syncasync.SendFunction) (*core.MessageInOut, error)); ok {
+		return rf(ctx, id, opts, send)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, syncasync.SendFunction) *core.MessageInOut); ok {
-		r0 = rf(ctx, id, send)
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID, *core.ReplyOptions, syncasync.SendFunction) *core.MessageInOut); ok {
+		r0 = rf(ctx, id, opts, send)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*core.MessageInOut)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID, syncasync.SendFunction) error); ok {
-		r1 = rf(ctx, id, send)
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID, *core.ReplyOptions, syncasync.SendFunction) error); ok {
+		r1 = rf(ctx, id, opts, send)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -5,8 +5,11 @@ package tokenmocks
 import (
 	context "context"
 
-	tokens "github.com/hyperledger/firefly/pkg/tokens"
+	blockchain "github.com/hyperledger/firefly/pkg/blockchain"
+
 	mock "github.com/stretchr/testify/mock"
+
+	tokens "github.com/hyperledger/firefly/pkg/tokens"
 )
 
 // Callbacks is an autogenerated mock type for the Callbacks type
@@ -14,6 +17,20 @@ type Callbacks struct {
 	mock.Mock
 }
 
+// TokenEventRemoved provides a mock function with given fields: plugin, event
+func (_m *Callbacks) TokenEventRemoved(plugin tokens.Plugin, event *blockchain.Event) error {
+	ret := _m.Called(plugin, event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(tokens.Plugin, *blockchain.Event) error); ok {
+		r0 = rf(plugin, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TokenPoolCreated provides a mock function with given fields: ctx, plugin, pool
 func (_m *Callbacks) TokenPoolCreated(ctx context.Context, plugin tokens.Plugin, pool *tokens.TokenPool) error {
 	ret := _m.Called(ctx, plugin, pool)
@@ -101,6 +101,46 @@ func (_m *Plugin) CheckInterface(ctx context.Context, pool *core.TokenPool, meth
 	return r0, r1
 }
 
+// CheckTransfer provides a mock function with given fields: ctx, poolLocator, transfer
+func (_m *Plugin) CheckTransfer(ctx context.Context, poolLocator string, transfer *core.TokenTransfer) (*core.TokenTransferEligibility, error) {
+	ret := _m.Called(ctx, poolLocator, transfer)
+
+	var r0 *core.TokenTransferEligibility
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenTransfer) (*core.TokenTransferEligibility, error)); ok {
+		return rf(ctx, poolLocator, transfer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.TokenTransfer) *core.TokenTransferEligibility); ok {
+		r0 = rf(ctx, poolLocator, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenTransferEligibility)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.TokenTransfer) error); ok {
+		r1 = rf(ctx, poolLocator, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimTokens provides a mock function with given fields: ctx, nsOpID, poolLocator, swap, leg
+func (_m *Plugin) ClaimTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	ret := _m.Called(ctx, nsOpID, poolLocator, swap, leg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.TokenSwap, *core.TokenSwapLeg) error); ok {
+		r0 = rf(ctx, nsOpID, poolLocator, swap, leg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CreateTokenPool provides a mock function with given fields: ctx, nsOpID, pool
 func (_m *Plugin) CreateTokenPool(ctx context.Context, nsOpID string, pool *core.TokenPool) (bool, error) {
 	ret := _m.Called(ctx, nsOpID, pool)
@@ -139,6 +179,58 @@ func (_m *Plugin) DeactivateTokenPool(ctx context.Context, pool *core.TokenPool)
 	return r0
 }
 
+// GetBalance provides a mock function with given fields: ctx, poolLocator, tokenIndex, account
+func (_m *Plugin) GetBalance(ctx context.Context, poolLocator string, tokenIndex string, account string) (*fftypes.FFBigInt, error) {
+	ret := _m.Called(ctx, poolLocator, tokenIndex, account)
+
+	var r0 *fftypes.FFBigInt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*fftypes.FFBigInt, error)); ok {
+		return rf(ctx, poolLocator, tokenIndex, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *fftypes.FFBigInt); ok {
+		r0 = rf(ctx, poolLocator, tokenIndex, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*fftypes.FFBigInt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, poolLocator, tokenIndex, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenFees provides a mock function with given fields: ctx, poolLocator, tokenIndex
+func (_m *Plugin) GetTokenFees(ctx context.Context, poolLocator string, tokenIndex string) (*core.TokenFees, error) {
+	ret := _m.Called(ctx, poolLocator, tokenIndex)
+
+	var r0 *core.TokenFees
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*core.TokenFees, error)); ok {
+		return rf(ctx, poolLocator, tokenIndex)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *core.TokenFees); ok {
+		r0 = rf(ctx, poolLocator, tokenIndex)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.TokenFees)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, poolLocator, tokenIndex)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Init provides a mock function with given fields: ctx, cancelCtx, name, _a3
 func (_m *Plugin) Init(ctx context.Context, cancelCtx context.CancelFunc, name string, _a3 config.Section) error {
 	ret := _m.Called(ctx, cancelCtx, name, _a3)
@@ -158,6 +250,20 @@ func (_m *Plugin) InitConfig(_a0 config.Section) {
 	_m.Called(_a0)
 }
 
+// LockTokens provides a mock function with given fields: ctx, nsOpID, poolLocator, swap, leg
+func (_m *Plugin) LockTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	ret := _m.Called(ctx, nsOpID, poolLocator, swap, leg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.TokenSwap, *core.TokenSwapLeg) error); ok {
+		r0 = rf(ctx, nsOpID, poolLocator, swap, leg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // MintTokens provides a mock function with given fields: ctx, nsOpID, poolLocator, mint, methods
 func (_m *Plugin) MintTokens(ctx context.Context, nsOpID string, poolLocator string, mint *core.TokenTransfer, methods *fftypes.JSONAny) error {
 	ret := _m.Called(ctx, nsOpID, poolLocator, mint, methods)
@@ -186,6 +292,20 @@ func (_m *Plugin) Name() string {
 	return r0
 }
 
+// RefundTokens provides a mock function with given fields: ctx, nsOpID, poolLocator, swap, leg
+func (_m *Plugin) RefundTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error {
+	ret := _m.Called(ctx, nsOpID, poolLocator, swap, leg)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *core.TokenSwap, *core.TokenSwapLeg) error); ok {
+		r0 = rf(ctx, nsOpID, poolLocator, swap, leg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetHandler provides a mock function with given fields: namespace, handler
 func (_m *Plugin) SetHandler(namespace string, handler tokens.Callbacks) {
 	_m.Called(namespace, handler)
@@ -238,6 +358,20 @@ func (_m *Plugin) TransferTokens(ctx context.Context, nsOpID string, poolLocator
 	return r0
 }
 
+// TransferTokensBatch provides a mock function with given fields: ctx, nsOpID, poolLocator, transfers, methods
+func (_m *Plugin) TransferTokensBatch(ctx context.Context, nsOpID string, poolLocator string, transfers []*core.TokenTransfer, methods *fftypes.JSONAny) error {
+	ret := _m.Called(ctx, nsOpID, poolLocator, transfers, methods)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []*core.TokenTransfer, *fftypes.JSONAny) error); ok {
+		r0 = rf(ctx, nsOpID, poolLocator, transfers, methods)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPlugin interface {
 	mock.TestingT
 	Cleanup(func())
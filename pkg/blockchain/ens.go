@@ -0,0 +1,26 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import "strings"
+
+// IsENSName returns true for a name under the .eth domain of the Ethereum Name Service (such as "alice.eth"),
+// which is resolved to an address by the blockchain plugin. Other values that contain dots, such as Hedera
+// account IDs, are keys or addresses in their own right.
+func IsENSName(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".eth") && !strings.ContainsAny(name, " /:")
+}
@@ -0,0 +1,34 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsENSName(t *testing.T) {
+	assert.True(t, IsENSName("alice.eth"))
+	assert.True(t, IsENSName("pay.Alice.ETH"))
+	assert.True(t, IsENSName("ålice.eth"))
+	assert.False(t, IsENSName("sub.domain.xyz"))
+	assert.False(t, IsENSName("0.0.1002"))
+	assert.False(t, IsENSName("0x2a7c9d5248681ce6c393117e641ad037f5c079f6"))
+	assert.False(t, IsENSName("m/44'/60'/0'/0/0"))
+	assert.False(t, IsENSName("https://alice.eth"))
+}
@@ -127,6 +127,9 @@ type Plugin interface {
 
 	// Get the latest status of the given transaction
 	GetTransactionStatus(ctx context.Context, operation *core.Operation) (interface{}, error)
+
+	// GetNetworkStatus gets the chain head and event stream health from the backend connector
+	GetNetworkStatus(ctx context.Context) (*core.BlockchainNetworkStatus, error)
 }
 
 type NormalizeType int
@@ -144,6 +147,7 @@ const (
 	EventTypeBatchPinComplete EventType = iota
 	EventTypeNetworkAction
 	EventTypeForListener
+	EventTypeRemoved
 )
 
 // BatchPinComplete notifies on the arrival of a sequenced batch of messages, which might have been
@@ -175,6 +179,7 @@ type EventToDispatch struct {
 	BatchPinComplete *BatchPinCompleteEvent
 	NetworkAction    *NetworkActionEvent
 	ForListener      *EventForListener
+	Removed          *Event // a previously delivered event that is no longer on the canonical chain
 }
 
 // Callbacks is the interface provided to the blockchain plugin, to allow it to pass events back to firefly.
@@ -203,6 +208,7 @@ type MultipartyContract struct {
 	Location   *fftypes.JSONAny
 	FirstEvent string
 	Options    *fftypes.JSONAny
+	ChainID    string
 }
 
 // BatchPin is the set of data pinned to the blockchain for a batch - whether it's private or broadcast.
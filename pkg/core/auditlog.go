@@ -0,0 +1,36 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// AuditLogEntry records a call to the API that changed a namespace - who made it, what it requested,
+// and the result
+type AuditLogEntry struct {
+	ID             *fftypes.UUID    `ffstruct:"AuditLogEntry" json:"id"`
+	Namespace      string           `ffstruct:"AuditLogEntry" json:"namespace"`
+	Principal      string           `ffstruct:"AuditLogEntry" json:"principal,omitempty"`
+	Method         string           `ffstruct:"AuditLogEntry" json:"method"`
+	Route          string           `ffstruct:"AuditLogEntry" json:"route"`
+	Path           string           `ffstruct:"AuditLogEntry" json:"path,omitempty"`
+	RequestHash    *fftypes.Bytes32 `ffstruct:"AuditLogEntry" json:"requestHash,omitempty"`
+	IdempotencyKey IdempotencyKey   `ffstruct:"AuditLogEntry" json:"idempotencyKey,omitempty"`
+	Transaction    *fftypes.UUID    `ffstruct:"AuditLogEntry" json:"tx,omitempty"`
+	Status         int              `ffstruct:"AuditLogEntry" json:"status"`
+	Error          string           `ffstruct:"AuditLogEntry" json:"error,omitempty"`
+	Created        *fftypes.FFTime  `ffstruct:"AuditLogEntry" json:"created"`
+}
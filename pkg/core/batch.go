@@ -51,7 +51,7 @@ type BatchHeader struct {
 
 type MessageManifestEntry struct {
 	MessageRef
-	Topics int `json:"topics"` // We only need the count, to be able to match up the pins
+	Topics int `ffstruct:"MessageManifestEntry" json:"topics"` // We only need the count, to be able to match up the pins
 }
 
 // BatchManifest is all we need to persist to be able to reconstitute
@@ -60,12 +60,13 @@ type MessageManifestEntry struct {
 // It can be generated from a received batch to
 // confirm you have received an identical batch to that sent.
 type BatchManifest struct {
-	Version uint           `json:"version"`
-	ID      *fftypes.UUID  `json:"id"`
-	TX      TransactionRef `json:"tx"`
+	Version uint           `ffstruct:"BatchManifest" json:"version"`
+	ID      *fftypes.UUID  `ffstruct:"BatchManifest" json:"id"`
+	TX      TransactionRef `ffstruct:"BatchManifest" json:"tx"`
 	SignerRef
-	Messages []*MessageManifestEntry `json:"messages"`
-	Data     DataRefs                `json:"data"`
+	Messages   []*MessageManifestEntry `ffstruct:"BatchManifest" json:"messages"`
+	Data       DataRefs                `ffstruct:"BatchManifest" json:"data"`
+	Disclosure []*DisclosureRoot       `ffstruct:"BatchManifest" json:"disclosure,omitempty"`
 }
 
 // Batch is the full payload object used in-flight.
@@ -78,10 +79,11 @@ type Batch struct {
 // BatchPersisted is the structure written to the database
 type BatchPersisted struct {
 	BatchHeader
-	Hash      *fftypes.Bytes32 `ffstruct:"Batch" json:"hash"`
-	Manifest  *fftypes.JSONAny `ffstruct:"Batch" json:"manifest"`
-	TX        TransactionRef   `ffstruct:"Batch" json:"tx"`
-	Confirmed *fftypes.FFTime  `ffstruct:"Batch" json:"confirmed"`
+	Hash           *fftypes.Bytes32 `ffstruct:"Batch" json:"hash"`
+	Manifest       *fftypes.JSONAny `ffstruct:"Batch" json:"manifest"`
+	TX             TransactionRef   `ffstruct:"Batch" json:"tx"`
+	Confirmed      *fftypes.FFTime  `ffstruct:"Batch" json:"confirmed"`
+	DisclosureSalt *fftypes.Bytes32 `json:"-"` // Only shared with the members of the group, as it allows the salt of every field to be derived
 }
 
 // BatchPayload contains the full JSON of the messages and data, but
@@ -93,9 +95,10 @@ type BatchPersisted struct {
 // calculating the hash).
 // - See Message.BatchMessage() and Data.BatchData()
 type BatchPayload struct {
-	TX       TransactionRef `ffstruct:"BatchPayload" json:"tx"`
-	Messages []*Message     `ffstruct:"BatchPayload" json:"messages"`
-	Data     DataArray      `ffstruct:"BatchPayload" json:"data"`
+	TX             TransactionRef   `ffstruct:"BatchPayload" json:"tx"`
+	Messages       []*Message       `ffstruct:"BatchPayload" json:"messages"`
+	Data           DataArray        `ffstruct:"BatchPayload" json:"data"`
+	DisclosureSalt *fftypes.Bytes32 `ffstruct:"BatchPayload" json:"disclosureSalt,omitempty"`
 }
 
 func (bm *BatchManifest) String() string {
@@ -134,6 +137,11 @@ func (ma *BatchPayload) Manifest(id *fftypes.UUID) *BatchManifest {
 				ID:   d.ID,
 				Hash: d.Hash,
 			})
+			if ma.DisclosureSalt != nil {
+				if root := d.DisclosureRoot(ma.DisclosureSalt); root != nil {
+					tm.Disclosure = append(tm.Disclosure, &DisclosureRoot{ID: d.ID, Root: root})
+				}
+			}
 		}
 	}
 	return tm
@@ -141,9 +149,10 @@ func (ma *BatchPayload) Manifest(id *fftypes.UUID) *BatchManifest {
 
 func (b *BatchPersisted) GenManifest(messages []*Message, data DataArray) *BatchManifest {
 	return (&BatchPayload{
-		TX:       b.TX,
-		Messages: messages,
-		Data:     data,
+		TX:             b.TX,
+		Messages:       messages,
+		Data:           data,
+		DisclosureSalt: b.DisclosureSalt,
 	}).Manifest(b.ID)
 }
 
@@ -152,9 +161,10 @@ func (b *BatchPersisted) GenInflight(messages []*Message, data DataArray) *Batch
 		BatchHeader: b.BatchHeader,
 		Hash:        b.Hash,
 		Payload: BatchPayload{
-			TX:       b.TX,
-			Messages: messages,
-			Data:     data,
+			TX:             b.TX,
+			Messages:       messages,
+			Data:           data,
+			DisclosureSalt: b.DisclosureSalt,
 		},
 	}
 }
@@ -164,10 +174,11 @@ func (b *Batch) Confirmed() (*BatchPersisted, *BatchManifest) {
 	manifest := b.Payload.Manifest(b.ID)
 	manifestString := manifest.String()
 	return &BatchPersisted{
-		BatchHeader: b.BatchHeader,
-		Hash:        b.Hash,
-		TX:          b.Payload.TX,
-		Manifest:    fftypes.JSONAnyPtr(manifestString),
-		Confirmed:   fftypes.Now(),
+		BatchHeader:    b.BatchHeader,
+		Hash:           b.Hash,
+		TX:             b.Payload.TX,
+		Manifest:       fftypes.JSONAnyPtr(manifestString),
+		Confirmed:      fftypes.Now(),
+		DisclosureSalt: b.Payload.DisclosureSalt,
 	}, manifest
 }
@@ -0,0 +1,31 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// BatchPolicy overrides the batch timeout and size of the dispatcher for the messages on a topic, so that
+// latency-sensitive topics are not held back by the batching of the rest of the namespace.
+// Policies are local to this node.
+type BatchPolicy struct {
+	Topic      string              `ffstruct:"BatchPolicy" json:"topic,omitempty" ffexcludeinput:"true"`
+	Namespace  string              `ffstruct:"BatchPolicy" json:"namespace,omitempty" ffexcludeinput:"true"`
+	MaxLatency *fftypes.FFDuration `ffstruct:"BatchPolicy" json:"maxLatency,omitempty"`
+	MaxSize    uint                `ffstruct:"BatchPolicy" json:"maxSize,omitempty"`
+	Created    *fftypes.FFTime     `ffstruct:"BatchPolicy" json:"created,omitempty" ffexcludeinput:"true"`
+	Updated    *fftypes.FFTime     `ffstruct:"BatchPolicy" json:"updated,omitempty" ffexcludeinput:"true"`
+}
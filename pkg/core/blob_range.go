@@ -0,0 +1,99 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+// BlobRange is a single range of bytes of a blob requested with an HTTP Range header.
+// First is -1 for a suffix range of the last Last bytes, and Last is -1 for a range to the end of the blob.
+type BlobRange struct {
+	First   int64
+	Last    int64
+	IfRange string
+}
+
+// ParseBlobRange parses an HTTP Range header such as "bytes=0-1023", "bytes=1024-" or "bytes=-1024",
+// along with any If-Range header. Multiple ranges, and headers that are not valid, return nil so the
+// whole blob is returned, as HTTP allows.
+func ParseBlobRange(rangeHeader, ifRangeHeader string) *BlobRange {
+	rangeHeader = strings.TrimSpace(rangeHeader)
+	if !strings.HasPrefix(rangeHeader, "bytes=") || strings.Contains(rangeHeader, ",") {
+		return nil
+	}
+	spec := strings.TrimPrefix(rangeHeader, "bytes=")
+	firstStr, lastStr, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil
+	}
+	br := &BlobRange{First: -1, Last: -1, IfRange: strings.TrimSpace(ifRangeHeader)}
+	var err error
+	if firstStr != "" {
+		if br.First, err = strconv.ParseInt(firstStr, 10, 64); err != nil || br.First < 0 {
+			return nil
+		}
+	}
+	if lastStr != "" {
+		if br.Last, err = strconv.ParseInt(lastStr, 10, 64); err != nil || br.Last < 0 {
+			return nil
+		}
+	}
+	switch {
+	case firstStr == "" && lastStr == "":
+		return nil
+	case firstStr != "" && lastStr != "" && br.Last < br.First:
+		return nil
+	}
+	return br
+}
+
+// Matches checks the If-Range of the range against the ETag of a blob, which is its quoted hash
+func (br *BlobRange) Matches(hash *fftypes.Bytes32) bool {
+	return br.IfRange == "" || br.IfRange == BlobETag(hash)
+}
+
+// Resolve returns the first and last bytes of the range in a blob of the given size,
+// or false if the range cannot be satisfied
+func (br *BlobRange) Resolve(size int64) (first, last int64, ok bool) {
+	switch {
+	case br.First < 0:
+		if br.Last == 0 || size == 0 {
+			return 0, 0, false
+		}
+		first = size - br.Last
+		if first < 0 {
+			first = 0
+		}
+		return first, size - 1, true
+	case br.First >= size:
+		return 0, 0, false
+	case br.Last < 0 || br.Last >= size:
+		return br.First, size - 1, true
+	default:
+		return br.First, br.Last, true
+	}
+}
+
+// BlobETag is the HTTP ETag of a blob. Blobs cannot change, so the hash identifies the content
+func BlobETag(hash *fftypes.Bytes32) string {
+	return fmt.Sprintf(`"%s"`, hash)
+}
@@ -0,0 +1,65 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBlobRange(t *testing.T) {
+	assert.Equal(t, &BlobRange{First: 0, Last: 1023}, ParseBlobRange("bytes=0-1023", ""))
+	assert.Equal(t, &BlobRange{First: 1024, Last: -1}, ParseBlobRange(" bytes=1024-", ""))
+	assert.Equal(t, &BlobRange{First: -1, Last: 1024, IfRange: `"abc"`}, ParseBlobRange("bytes=-1024", ` "abc" `))
+
+	assert.Nil(t, ParseBlobRange("", ""))
+	assert.Nil(t, ParseBlobRange("items=0-1", ""))
+	assert.Nil(t, ParseBlobRange("bytes=0-1,5-6", ""))
+	assert.Nil(t, ParseBlobRange("bytes=5", ""))
+	assert.Nil(t, ParseBlobRange("bytes=-", ""))
+	assert.Nil(t, ParseBlobRange("bytes=a-1", ""))
+	assert.Nil(t, ParseBlobRange("bytes=0-b", ""))
+	assert.Nil(t, ParseBlobRange("bytes=5-1", ""))
+}
+
+func TestBlobRangeMatches(t *testing.T) {
+	hash := fftypes.NewRandB32()
+	assert.True(t, (&BlobRange{}).Matches(hash))
+	assert.True(t, (&BlobRange{IfRange: BlobETag(hash)}).Matches(hash))
+	assert.False(t, (&BlobRange{IfRange: `"other"`}).Matches(hash))
+}
+
+func TestBlobRangeResolve(t *testing.T) {
+	check := func(br *BlobRange, size, first, last int64, ok bool) {
+		f, l, o := br.Resolve(size)
+		assert.Equal(t, ok, o)
+		if ok {
+			assert.Equal(t, first, f)
+			assert.Equal(t, last, l)
+		}
+	}
+	check(&BlobRange{First: 0, Last: 9}, 100, 0, 9, true)
+	check(&BlobRange{First: 90, Last: 200}, 100, 90, 99, true)
+	check(&BlobRange{First: 90, Last: -1}, 100, 90, 99, true)
+	check(&BlobRange{First: 100, Last: -1}, 100, 0, 0, false)
+	check(&BlobRange{First: -1, Last: 10}, 100, 90, 99, true)
+	check(&BlobRange{First: -1, Last: 200}, 100, 0, 99, true)
+	check(&BlobRange{First: -1, Last: 0}, 100, 0, 0, false)
+	check(&BlobRange{First: -1, Last: 10}, 0, 0, 0, false)
+}
@@ -0,0 +1,41 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// BlobUploadInput starts a chunked upload of a blob, with the details of the data to create when it is complete
+type BlobUploadInput struct {
+	Size      int64            `ffstruct:"BlobUpload" json:"size,omitempty"`
+	Hash      *fftypes.Bytes32 `ffstruct:"BlobUpload" json:"hash,omitempty"`
+	Filename  string           `ffstruct:"BlobUpload" json:"filename,omitempty"`
+	Mimetype  string           `ffstruct:"BlobUpload" json:"mimetype,omitempty"`
+	AutoMeta  bool             `ffstruct:"BlobUpload" json:"autometa,omitempty"`
+	Validator ValidatorType    `ffstruct:"BlobUpload" json:"validator,omitempty"`
+	Datatype  *DatatypeRef     `ffstruct:"BlobUpload" json:"datatype,omitempty"`
+	Value     *fftypes.JSONAny `ffstruct:"BlobUpload" json:"value,omitempty"`
+}
+
+// BlobUpload is a chunked upload of a blob in progress, which is resumed from its offset after an interruption
+type BlobUpload struct {
+	ID        *fftypes.UUID `ffstruct:"BlobUpload" json:"id"`
+	Namespace string        `ffstruct:"BlobUpload" json:"namespace"`
+	Offset    int64         `ffstruct:"BlobUpload" json:"offset"`
+	BlobUploadInput
+	Created *fftypes.FFTime `ffstruct:"BlobUpload" json:"created"`
+	Updated *fftypes.FFTime `ffstruct:"BlobUpload" json:"updated"`
+}
@@ -0,0 +1,68 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+const (
+	// CloudEventsSpecVersion is the version of the CloudEvents specification events are wrapped in
+	CloudEventsSpecVersion = "1.0"
+	// CloudEventsTypePrefix is prepended to the FireFly event type, to give the CloudEvents type
+	CloudEventsTypePrefix = "io.hyperledger.firefly."
+)
+
+// CloudEvent is a FireFly event, wrapped in a CloudEvents 1.0 envelope in structured mode.
+// The sequence, topic and subscription of the FireFly event are carried as extension attributes.
+type CloudEvent struct {
+	SpecVersion         string          `json:"specversion"`
+	ID                  string          `json:"id"`
+	Source              string          `json:"source"`
+	Type                string          `json:"type"`
+	Subject             string          `json:"subject,omitempty"`
+	Time                *fftypes.FFTime `json:"time,omitempty"`
+	DataContentType     string          `json:"datacontenttype,omitempty"`
+	FireFlySequence     int64           `json:"fireflysequence"`
+	FireFlyTopic        string          `json:"fireflytopic,omitempty"`
+	FireFlySubscription string          `json:"fireflysubscription,omitempty"`
+	Data                interface{}     `json:"data,omitempty"`
+}
+
+// NewCloudEvent wraps the supplied data, which is the payload the transport would otherwise deliver for the event
+func NewCloudEvent(event *EventDelivery, data interface{}) *CloudEvent {
+	ce := &CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              event.ID.String(),
+		Source:          fmt.Sprintf("/namespaces/%s", event.Namespace),
+		Type:            CloudEventsTypePrefix + string(event.Type),
+		Time:            event.Created,
+		DataContentType: "application/json",
+		FireFlySequence: event.Sequence,
+		FireFlyTopic:    event.Topic,
+		Data:            data,
+	}
+	if event.Reference != nil {
+		ce.Subject = event.Reference.String()
+	}
+	if event.Subscription.ID != nil {
+		ce.FireFlySubscription = event.Subscription.ID.String()
+	}
+	return ce
+}
@@ -0,0 +1,79 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCloudEvent(t *testing.T) {
+	eventID := fftypes.MustParseUUID("d0d2f6a8-5e4d-4b2b-9c3b-9f1c8e6b1a01")
+	refID := fftypes.MustParseUUID("b9ae6b3e-3c3d-4b0e-a6a3-9c38e0a0c3b2")
+	subID := fftypes.MustParseUUID("0f5b5c8e-1d0a-4f8a-9a0a-6f0c1b2a3d4e")
+	created := fftypes.UnixTime(1000000000)
+	event := &EventDelivery{
+		EnrichedEvent: EnrichedEvent{
+			Event: Event{
+				ID:        eventID,
+				Sequence:  12345,
+				Type:      EventTypeMessageConfirmed,
+				Namespace: "ns1",
+				Reference: refID,
+				Topic:     "topic1",
+				Created:   created,
+			},
+		},
+		Subscription: SubscriptionRef{ID: subID},
+	}
+
+	ce := NewCloudEvent(event, fftypes.JSONObject{"some": "data"})
+	b, err := json.Marshal(ce)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"specversion": "1.0",
+		"id": "d0d2f6a8-5e4d-4b2b-9c3b-9f1c8e6b1a01",
+		"source": "/namespaces/ns1",
+		"type": "io.hyperledger.firefly.message_confirmed",
+		"subject": "b9ae6b3e-3c3d-4b0e-a6a3-9c38e0a0c3b2",
+		"time": "`+created.String()+`",
+		"datacontenttype": "application/json",
+		"fireflysequence": 12345,
+		"fireflytopic": "topic1",
+		"fireflysubscription": "0f5b5c8e-1d0a-4f8a-9a0a-6f0c1b2a3d4e",
+		"data": {"some": "data"}
+	}`, string(b))
+}
+
+func TestNewCloudEventMinimal(t *testing.T) {
+	event := &EventDelivery{
+		EnrichedEvent: EnrichedEvent{
+			Event: Event{
+				ID:   fftypes.NewUUID(),
+				Type: EventTypeTransactionSubmitted,
+			},
+		},
+	}
+
+	ce := NewCloudEvent(event, nil)
+	assert.Empty(t, ce.Subject)
+	assert.Empty(t, ce.FireFlySubscription)
+	assert.Equal(t, "io.hyperledger.firefly.transaction_submitted", ce.Type)
+}
@@ -43,6 +43,12 @@ const (
 	// SystemTagDefineGroup is the tag for messages that send the definition of a group, to all parties in that group
 	SystemTagDefineGroup = "ff_define_group"
 
+	// SystemTagChangeGroup is the tag for messages that notify all parties in a group that its membership has changed
+	SystemTagChangeGroup = "ff_change_group"
+
+	// SystemTagRecallMessage is the tag for messages that recall a message previously sent to a group
+	SystemTagRecallMessage = "ff_recall_message"
+
 	// SystemTagDefinePool is the tag for messages that broadcast data definitions
 	SystemTagDefinePool = "ff_define_pool"
 
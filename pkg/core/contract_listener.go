@@ -44,7 +44,10 @@ type ContractListenerWithStatus struct {
 	Status interface{} `ffstruct:"ContractListenerWithStatus" json:"status,omitempty" ffexcludeinput:"true"`
 }
 type ContractListenerOptions struct {
-	FirstEvent string `ffstruct:"ContractListenerOptions" json:"firstEvent,omitempty"`
+	FirstEvent    string           `ffstruct:"ContractListenerOptions" json:"firstEvent,omitempty"`
+	Confirmations *int             `ffstruct:"ContractListenerOptions" json:"confirmations,omitempty"`
+	Topics        []string         `ffstruct:"ContractListenerOptions" json:"topics,omitempty"`
+	DecodingHint  *fftypes.JSONAny `ffstruct:"ContractListenerOptions" json:"decodingHint,omitempty"`
 }
 
 type ListenerStatusError struct {
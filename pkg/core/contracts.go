@@ -51,9 +51,15 @@ type ContractDeployRequest struct {
 	Definition     *fftypes.JSONAny       `ffstruct:"ContractDeployRequest" json:"definition"`
 	Contract       *fftypes.JSONAny       `ffstruct:"ContractDeployRequest" json:"contract"`
 	Options        map[string]interface{} `ffstruct:"ContractDeployRequest" json:"options"`
+	API            *ContractDeployAPI     `ffstruct:"ContractDeployRequest" json:"api,omitempty"`
 	IdempotencyKey IdempotencyKey         `ffstruct:"ContractDeployRequest" json:"idempotencyKey,omitempty" ffexcludeoutput:"true"`
 }
 
+type ContractDeployAPI struct {
+	Name      string                `ffstruct:"ContractDeployAPI" json:"name"`
+	Interface *fftypes.FFIReference `ffstruct:"ContractDeployAPI" json:"interface"`
+}
+
 type ContractURLs struct {
 	OpenAPI string `ffstruct:"ContractURLs" json:"openapi"`
 	UI      string `ffstruct:"ContractURLs" json:"ui"`
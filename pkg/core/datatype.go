@@ -28,27 +28,47 @@ type ValidatorType = fftypes.FFEnum
 var (
 	// ValidatorTypeJSON is the validator type for JSON Schema validation
 	ValidatorTypeJSON = fftypes.FFEnumValue("validatortype", "json")
+	// ValidatorTypeAvro is the validator type for Apache Avro schema validation
+	ValidatorTypeAvro = fftypes.FFEnumValue("validatortype", "avro")
+	// ValidatorTypeProtobuf is the validator type for Protocol Buffers message validation
+	ValidatorTypeProtobuf = fftypes.FFEnumValue("validatortype", "protobuf")
 	// ValidatorTypeNone explicitly disables validation, even when a datatype is set. Allowing categorization of datatype without validation.
 	ValidatorTypeNone = fftypes.FFEnumValue("validatortype", "none")
 	// ValidatorTypeSystemDefinition is the validator type for system definitions
 	ValidatorTypeSystemDefinition = fftypes.FFEnumValue("validatortype", "definition")
 )
 
+type DatatypeCompatibility = fftypes.FFEnum
+
+var (
+	// DatatypeCompatibilityNone allows a new version of a datatype to make any change to the previous version
+	DatatypeCompatibilityNone = fftypes.FFEnumValue("datatypecompatibility", "none")
+	// DatatypeCompatibilityBackward requires a new version of a datatype to accept all data that was valid for the previous version
+	DatatypeCompatibilityBackward = fftypes.FFEnumValue("datatypecompatibility", "backward")
+	// DatatypeCompatibilityForward requires all data that is valid for a new version of a datatype to be accepted by the previous version
+	DatatypeCompatibilityForward = fftypes.FFEnumValue("datatypecompatibility", "forward")
+	// DatatypeCompatibilityFull requires a new version of a datatype to be both backward and forward compatible with the previous version
+	DatatypeCompatibilityFull = fftypes.FFEnumValue("datatypecompatibility", "full")
+)
+
 // Datatype is the structure defining a data definition, such as a JSON schema
 type Datatype struct {
-	ID        *fftypes.UUID    `ffstruct:"Datatype" json:"id,omitempty" ffexcludeinput:"true"`
-	Message   *fftypes.UUID    `ffstruct:"Datatype" json:"message,omitempty" ffexcludeinput:"true"`
-	Validator ValidatorType    `ffstruct:"Datatype" json:"validator" ffenum:"validatortype"`
-	Namespace string           `ffstruct:"Datatype" json:"namespace,omitempty" ffexcludeinput:"true"`
-	Name      string           `ffstruct:"Datatype" json:"name,omitempty"`
-	Version   string           `ffstruct:"Datatype" json:"version,omitempty"`
-	Hash      *fftypes.Bytes32 `ffstruct:"Datatype" json:"hash,omitempty" ffexcludeinput:"true"`
-	Created   *fftypes.FFTime  `ffstruct:"Datatype" json:"created,omitempty" ffexcludeinput:"true"`
-	Value     *fftypes.JSONAny `ffstruct:"Datatype" json:"value,omitempty"`
+	ID            *fftypes.UUID         `ffstruct:"Datatype" json:"id,omitempty" ffexcludeinput:"true"`
+	Message       *fftypes.UUID         `ffstruct:"Datatype" json:"message,omitempty" ffexcludeinput:"true"`
+	Validator     ValidatorType         `ffstruct:"Datatype" json:"validator" ffenum:"validatortype"`
+	Namespace     string                `ffstruct:"Datatype" json:"namespace,omitempty" ffexcludeinput:"true"`
+	Name          string                `ffstruct:"Datatype" json:"name,omitempty"`
+	Version       string                `ffstruct:"Datatype" json:"version,omitempty"`
+	Hash          *fftypes.Bytes32      `ffstruct:"Datatype" json:"hash,omitempty" ffexcludeinput:"true"`
+	Created       *fftypes.FFTime       `ffstruct:"Datatype" json:"created,omitempty" ffexcludeinput:"true"`
+	Value         *fftypes.JSONAny      `ffstruct:"Datatype" json:"value,omitempty"`
+	Compatibility DatatypeCompatibility `ffstruct:"Datatype" json:"compatibility,omitempty" ffenum:"datatypecompatibility"`
 }
 
 func (dt *Datatype) Validate(ctx context.Context, existing bool) (err error) {
-	if dt.Validator != ValidatorTypeJSON {
+	switch dt.Validator {
+	case ValidatorTypeJSON, ValidatorTypeAvro, ValidatorTypeProtobuf:
+	default:
 		return i18n.NewError(ctx, i18n.MsgUnknownFieldValue, "validator", dt.Validator)
 	}
 	if err = fftypes.ValidateFFNameFieldNoUUID(ctx, dt.Name, "name"); err != nil {
@@ -60,6 +80,11 @@ func (dt *Datatype) Validate(ctx context.Context, existing bool) (err error) {
 	if dt.Value == nil || len(*dt.Value) == 0 {
 		return i18n.NewError(ctx, i18n.MsgMissingRequiredField, "value")
 	}
+	if dt.Compatibility != "" {
+		if dt.Compatibility, err = fftypes.FFEnumParseString(ctx, "datatypecompatibility", dt.Compatibility.String()); err != nil {
+			return err
+		}
+	}
 	if existing {
 		if dt.ID == nil {
 			return i18n.NewError(ctx, i18n.MsgNilID)
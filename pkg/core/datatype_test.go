@@ -63,6 +63,17 @@ func TestDatatypeValidation(t *testing.T) {
 	}
 	assert.NoError(t, dt.Validate(context.Background(), false))
 
+	dt.Compatibility = "wrong"
+	assert.Regexp(t, "FF00172.*wrong", dt.Validate(context.Background(), false))
+	dt.Compatibility = "Backward"
+	assert.NoError(t, dt.Validate(context.Background(), false))
+	assert.Equal(t, DatatypeCompatibilityBackward, dt.Compatibility)
+
+	dt.Validator = ValidatorTypeAvro
+	assert.NoError(t, dt.Validate(context.Background(), false))
+	dt.Validator = ValidatorTypeProtobuf
+	assert.NoError(t, dt.Validate(context.Background(), false))
+
 	assert.Regexp(t, "FF00114", dt.Validate(context.Background(), true))
 
 	dt.ID = fftypes.NewUUID()
@@ -0,0 +1,85 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// DeadLetterState is the current state of a dead letter in the dead letter queue of the namespace
+type DeadLetterState = fftypes.FFEnum
+
+var (
+	// DeadLetterStatePending is a dead letter that is waiting for an administrator to requeue or discard it
+	DeadLetterStatePending = fftypes.FFEnumValue("deadletterstate", "pending")
+	// DeadLetterStateRequeued is a dead letter whose event has been redelivered to the subscription
+	DeadLetterStateRequeued = fftypes.FFEnumValue("deadletterstate", "requeued")
+	// DeadLetterStateDiscarded is a dead letter that an administrator has chosen not to redeliver
+	DeadLetterStateDiscarded = fftypes.FFEnumValue("deadletterstate", "discarded")
+)
+
+// DeadLetterAttempt records the outcome of a single delivery attempt of a dead lettered event
+type DeadLetterAttempt struct {
+	Time   *fftypes.FFTime `ffstruct:"DeadLetterAttempt" json:"time"`
+	Status int             `ffstruct:"DeadLetterAttempt" json:"status,omitempty"`
+	Error  string          `ffstruct:"DeadLetterAttempt" json:"error,omitempty"`
+}
+
+// DeadLetterAttempts is the attempt history of a dead letter, in the order the attempts were made
+type DeadLetterAttempts []*DeadLetterAttempt
+
+// DeadLetter records an event that could not be delivered to a subscription, once the retry policy of the subscription was exhausted
+type DeadLetter struct {
+	ID            *fftypes.UUID      `ffstruct:"DeadLetter" json:"id"`
+	Namespace     string             `ffstruct:"DeadLetter" json:"namespace"`
+	Subscription  SubscriptionRef    `ffstruct:"DeadLetter" json:"subscription"`
+	Event         *fftypes.UUID      `ffstruct:"DeadLetter" json:"event"`
+	EventType     EventType          `ffstruct:"DeadLetter" json:"eventType" ffenum:"eventtype"`
+	Reference     *fftypes.UUID      `ffstruct:"DeadLetter" json:"reference,omitempty"`
+	Attempts      int                `ffstruct:"DeadLetter" json:"attempts"`
+	Status        int                `ffstruct:"DeadLetter" json:"status,omitempty"`
+	Error         string             `ffstruct:"DeadLetter" json:"error,omitempty"`
+	History       DeadLetterAttempts `ffstruct:"DeadLetter" json:"history,omitempty"`
+	OriginalEvent *fftypes.JSONAny   `ffstruct:"DeadLetter" json:"originalEvent,omitempty"`
+	State         DeadLetterState    `ffstruct:"DeadLetter" json:"state" ffenum:"deadletterstate"`
+	Created       *fftypes.FFTime    `ffstruct:"DeadLetter" json:"created"`
+	Updated       *fftypes.FFTime    `ffstruct:"DeadLetter" json:"updated,omitempty"`
+}
+
+// Scan implements sql.Scanner
+func (h *DeadLetterAttempts) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), h)
+	case []byte:
+		return json.Unmarshal(src, h)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, h)
+	}
+}
+
+// Value implements sql.Valuer
+func (h DeadLetterAttempts) Value() (driver.Value, error) {
+	return json.Marshal(h)
+}
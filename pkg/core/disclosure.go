@@ -0,0 +1,290 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// DisclosureRoot is the root of the Merkle tree of the salted hashes of the fields of a data item, which is
+// included in the manifest of a batch sent with selective disclosure, and hence in the hash pinned for the batch
+type DisclosureRoot struct {
+	ID   *fftypes.UUID    `ffstruct:"DisclosureRoot" json:"id"`
+	Root *fftypes.Bytes32 `ffstruct:"DisclosureRoot" json:"root"`
+}
+
+// DisclosureInput selects the fields of a data item of a message to disclose
+type DisclosureInput struct {
+	Data   *fftypes.UUID `ffstruct:"DisclosureInput" json:"data,omitempty"`
+	Fields []string      `ffstruct:"DisclosureInput" json:"fields"`
+}
+
+// DisclosureProof proves the value of some of the fields of the data of a pinned private message, without revealing
+// the other fields, or any of the other data in the batch
+type DisclosureProof struct {
+	Batch   *BatchManifest    `ffstruct:"DisclosureProof" json:"batch"`
+	Message *Message          `ffstruct:"DisclosureProof" json:"message"`
+	Data    *fftypes.UUID     `ffstruct:"DisclosureProof" json:"data"`
+	Fields  []*DisclosedField `ffstruct:"DisclosureProof" json:"fields"`
+}
+
+// DisclosedField is the value of a field, with its salt and the path from its hash to the disclosure root of the data
+type DisclosedField struct {
+	Field string                `ffstruct:"DisclosedField" json:"field"`
+	Value *fftypes.JSONAny      `ffstruct:"DisclosedField" json:"value"`
+	Salt  *fftypes.Bytes32      `ffstruct:"DisclosedField" json:"salt"`
+	Path  []*DisclosurePathStep `ffstruct:"DisclosedField" json:"path"`
+}
+
+// DisclosurePathStep is a sibling hash on the path from a field to the disclosure root
+type DisclosurePathStep struct {
+	Hash *fftypes.Bytes32 `ffstruct:"DisclosurePathStep" json:"hash"`
+	Left bool             `ffstruct:"DisclosurePathStep" json:"left,omitempty"`
+}
+
+// DisclosureVerification is the result of verifying a disclosure proof
+type DisclosureVerification struct {
+	Valid     bool             `ffstruct:"DisclosureVerification" json:"valid"`
+	Reason    string           `ffstruct:"DisclosureVerification" json:"reason,omitempty"`
+	Batch     *fftypes.UUID    `ffstruct:"DisclosureVerification" json:"batch,omitempty"`
+	BatchHash *fftypes.Bytes32 `ffstruct:"DisclosureVerification" json:"batchHash,omitempty"`
+	Signer    string           `ffstruct:"DisclosureVerification" json:"signer,omitempty"`
+	Message   *fftypes.UUID    `ffstruct:"DisclosureVerification" json:"message,omitempty"`
+	Data      *fftypes.UUID    `ffstruct:"DisclosureVerification" json:"data,omitempty"`
+	Fields    []string         `ffstruct:"DisclosureVerification" json:"fields,omitempty"`
+}
+
+// DisclosureFieldSalt derives the salt of a field of a data item from the disclosure salt of its batch.
+// Revealing the salt of one field does not reveal the salt of the batch, or of any other field.
+func DisclosureFieldSalt(batchSalt *fftypes.Bytes32, dataID *fftypes.UUID, field string) *fftypes.Bytes32 {
+	mac := hmac.New(sha256.New, batchSalt[:])
+	mac.Write([]byte(dataID.String()))
+	mac.Write([]byte{0})
+	mac.Write([]byte(field))
+	return fftypes.HashResult(mac)
+}
+
+// DisclosureLeaf is the salted hash of the value of a field. The value is compacted first, so that the hash
+// does not depend on the whitespace of the JSON.
+func DisclosureLeaf(salt *fftypes.Bytes32, field string, value *fftypes.JSONAny) *fftypes.Bytes32 {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, value.Bytes()); err != nil {
+		compacted.Reset()
+		compacted.Write(value.Bytes())
+	}
+	fieldName, _ := json.Marshal(field)
+	hash := sha256.New()
+	hash.Write([]byte{0})
+	hash.Write(salt[:])
+	hash.Write(fieldName)
+	hash.Write(compacted.Bytes())
+	return fftypes.HashResult(hash)
+}
+
+func disclosureNode(left, right *fftypes.Bytes32) *fftypes.Bytes32 {
+	hash := sha256.New()
+	hash.Write([]byte{1})
+	hash.Write(left[:])
+	hash.Write(right[:])
+	return fftypes.HashResult(hash)
+}
+
+// disclosureLevel hashes each pair of nodes in a level of the tree, promoting an odd node at the end unchanged
+func disclosureLevel(level []*fftypes.Bytes32) []*fftypes.Bytes32 {
+	next := make([]*fftypes.Bytes32, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 < len(level) {
+			next = append(next, disclosureNode(level[i], level[i+1]))
+		} else {
+			next = append(next, level[i])
+		}
+	}
+	return next
+}
+
+type disclosureTree struct {
+	fields []string
+	values map[string]*fftypes.JSONAny
+	leaves []*fftypes.Bytes32
+}
+
+// disclosureTree builds the leaves of the tree for a data item, from the top-level fields of its value in name order.
+// Only a JSON object value with at least one field has a tree.
+func (d *Data) disclosureTree(batchSalt *fftypes.Bytes32) *disclosureTree {
+	var object map[string]json.RawMessage
+	if d.Value == nil || json.Unmarshal(d.Value.Bytes(), &object) != nil || len(object) == 0 {
+		return nil
+	}
+	t := &disclosureTree{
+		fields: make([]string, 0, len(object)),
+		values: make(map[string]*fftypes.JSONAny, len(object)),
+	}
+	for field, value := range object {
+		t.fields = append(t.fields, field)
+		t.values[field] = fftypes.JSONAnyPtrBytes(value)
+	}
+	sort.Strings(t.fields)
+	for _, field := range t.fields {
+		t.leaves = append(t.leaves, DisclosureLeaf(DisclosureFieldSalt(batchSalt, d.ID, field), field, t.values[field]))
+	}
+	return t
+}
+
+func (t *disclosureTree) root() *fftypes.Bytes32 {
+	level := t.leaves
+	for len(level) > 1 {
+		level = disclosureLevel(level)
+	}
+	return level[0]
+}
+
+func (t *disclosureTree) path(index int) []*DisclosurePathStep {
+	path := []*DisclosurePathStep{}
+	level := t.leaves
+	for len(level) > 1 {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			path = append(path, &DisclosurePathStep{Hash: level[sibling], Left: sibling < index})
+		}
+		level = disclosureLevel(level)
+		index /= 2
+	}
+	return path
+}
+
+// DisclosureRoot returns the root of the salted hashes of the fields of the data, or nil if the data
+// does not have any fields that can be disclosed
+func (d *Data) DisclosureRoot(batchSalt *fftypes.Bytes32) *fftypes.Bytes32 {
+	t := d.disclosureTree(batchSalt)
+	if t == nil {
+		return nil
+	}
+	return t.root()
+}
+
+// DiscloseFields returns the values of the requested fields of the data, each with the proof that it is
+// part of the disclosure root of the data
+func (d *Data) DiscloseFields(ctx context.Context, batchSalt *fftypes.Bytes32, fields []string) ([]*DisclosedField, error) {
+	if len(fields) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureNoFieldsRequested)
+	}
+	t := d.disclosureTree(batchSalt)
+	if t == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureNoFields, d.ID)
+	}
+	disclosed := make([]*DisclosedField, len(fields))
+	for i, field := range fields {
+		index := sort.SearchStrings(t.fields, field)
+		if index == len(t.fields) || t.fields[index] != field {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDisclosureFieldNotFound, field, d.ID)
+		}
+		disclosed[i] = &DisclosedField{
+			Field: field,
+			Value: t.values[field],
+			Salt:  DisclosureFieldSalt(batchSalt, d.ID, field),
+			Path:  t.path(index),
+		}
+	}
+	return disclosed, nil
+}
+
+// Root returns the disclosure root calculated from the value, salt and path of the field
+func (df *DisclosedField) Root() *fftypes.Bytes32 {
+	hash := DisclosureLeaf(df.Salt, df.Field, df.Value)
+	for _, step := range df.Path {
+		if step.Left {
+			hash = disclosureNode(step.Hash, hash)
+		} else {
+			hash = disclosureNode(hash, step.Hash)
+		}
+	}
+	return hash
+}
+
+// Verify checks that each of the fields is part of the disclosure root of the data in the batch manifest, and
+// that the data is part of the message in the batch. It does not check that the batch has been pinned, which
+// requires the hash of the manifest to be compared with the pins of the batch.
+func (dp *DisclosureProof) Verify(ctx context.Context) error {
+	if dp.Batch == nil || dp.Message == nil || dp.Message.Header.ID == nil || dp.Data == nil || len(dp.Fields) == 0 {
+		return i18n.NewError(ctx, coremsgs.MsgDisclosureProofIncomplete)
+	}
+	for _, field := range dp.Fields {
+		if field == nil || field.Salt == nil {
+			return i18n.NewError(ctx, coremsgs.MsgDisclosureProofIncomplete)
+		}
+		for _, step := range field.Path {
+			if step == nil || step.Hash == nil {
+				return i18n.NewError(ctx, coremsgs.MsgDisclosureProofIncomplete)
+			}
+		}
+	}
+	if err := dp.Message.Verify(ctx); err != nil {
+		return err
+	}
+
+	msg := dp.Message
+	inBatch := false
+	for _, entry := range dp.Batch.Messages {
+		if entry != nil && entry.ID.Equals(msg.Header.ID) && entry.Hash.Equals(msg.Hash) {
+			inBatch = true
+			break
+		}
+	}
+	if !inBatch {
+		return i18n.NewError(ctx, coremsgs.MsgDisclosureMessageNotInBatch, msg.Header.ID, msg.Hash, dp.Batch.ID)
+	}
+
+	var dataRef *DataRef
+	for _, dr := range msg.Data {
+		if dr != nil && dr.ID.Equals(dp.Data) {
+			dataRef = dr
+			break
+		}
+	}
+	if dataRef == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDisclosureDataNotInMessage, dp.Data, msg.Header.ID)
+	}
+	var root *fftypes.Bytes32
+	for _, dr := range dp.Batch.Data {
+		if dr != nil && dr.ID.Equals(dp.Data) && dr.Hash.Equals(dataRef.Hash) {
+			for _, r := range dp.Batch.Disclosure {
+				if r != nil && r.ID.Equals(dp.Data) {
+					root = r.Root
+				}
+			}
+		}
+	}
+	if root == nil {
+		return i18n.NewError(ctx, coremsgs.MsgDisclosureDataNotInBatch, dp.Data, dp.Batch.ID)
+	}
+
+	for _, field := range dp.Fields {
+		if !field.Root().Equals(root) {
+			return i18n.NewError(ctx, coremsgs.MsgDisclosureFieldMismatch, field.Field, dp.Data)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+// Copyright © 2021 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestDisclosureBatch(t *testing.T, value string) (*Batch, *Data) {
+	data := &Data{
+		ID:    fftypes.NewUUID(),
+		Value: fftypes.JSONAnyPtr(value),
+	}
+	err := data.Seal(context.Background(), nil)
+	assert.NoError(t, err)
+	msg := &Message{
+		Header: MessageHeader{
+			Type: MessageTypePrivate,
+		},
+		Data: DataRefs{{ID: data.ID, Hash: data.Hash}},
+	}
+	err = msg.Seal(context.Background())
+	assert.NoError(t, err)
+	batch := &Batch{
+		BatchHeader: BatchHeader{
+			ID: fftypes.NewUUID(),
+		},
+		Payload: BatchPayload{
+			TX:             TransactionRef{Type: TransactionTypeBatchPin, ID: fftypes.NewUUID()},
+			Messages:       []*Message{msg},
+			Data:           DataArray{data},
+			DisclosureSalt: fftypes.NewRandB32(),
+		},
+	}
+	return batch, data
+}
+
+func newTestDisclosureProof(t *testing.T, fields ...string) (*DisclosureProof, *Data) {
+	batch, data := newTestDisclosureBatch(t, `{"a": 1, "b": "two", "c": {"d": true}, "e": [1, 2], "f": null}`)
+	disclosed, err := data.DiscloseFields(context.Background(), batch.Payload.DisclosureSalt, fields)
+	assert.NoError(t, err)
+	return &DisclosureProof{
+		Batch:   batch.Payload.Manifest(batch.ID),
+		Message: batch.Payload.Messages[0].BatchMessage(),
+		Data:    data.ID,
+		Fields:  disclosed,
+	}, data
+}
+
+func TestDisclosureManifest(t *testing.T) {
+	batch, data := newTestDisclosureBatch(t, `{"a": 1}`)
+	batch.Payload.Data = append(batch.Payload.Data, &Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"not an object"`)})
+
+	bp, manifest := batch.Confirmed()
+	assert.Equal(t, batch.Payload.DisclosureSalt, bp.DisclosureSalt)
+	assert.Len(t, manifest.Disclosure, 1)
+	assert.Equal(t, data.ID, manifest.Disclosure[0].ID)
+	assert.Equal(t, data.DisclosureRoot(batch.Payload.DisclosureSalt), manifest.Disclosure[0].Root)
+	assert.Equal(t, manifest.String(), bp.GenManifest(batch.Payload.Messages, batch.Payload.Data).String())
+	assert.Equal(t, batch, bp.GenInflight(batch.Payload.Messages, batch.Payload.Data))
+
+	batch.Payload.DisclosureSalt = nil
+	_, manifest = batch.Confirmed()
+	assert.Nil(t, manifest.Disclosure)
+	assert.NotContains(t, manifest.String(), "disclosure")
+}
+
+func TestDisclosureRootNoFields(t *testing.T) {
+	salt := fftypes.NewRandB32()
+	assert.Nil(t, (&Data{ID: fftypes.NewUUID()}).DisclosureRoot(salt))
+	assert.Nil(t, (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`[1,2]`)}).DisclosureRoot(salt))
+	assert.Nil(t, (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{}`)}).DisclosureRoot(salt))
+	assert.NotNil(t, (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":1}`)}).DisclosureRoot(salt))
+}
+
+func TestDisclosureRootSaltAndWhitespace(t *testing.T) {
+	salt := fftypes.NewRandB32()
+	id := fftypes.NewUUID()
+	root1 := (&Data{ID: id, Value: fftypes.JSONAnyPtr(`{"a":1,"b":{"c":2}}`)}).DisclosureRoot(salt)
+	root2 := (&Data{ID: id, Value: fftypes.JSONAnyPtr(`{ "b": { "c": 2 }, "a": 1 }`)}).DisclosureRoot(salt)
+	assert.Equal(t, root1, root2)
+	root3 := (&Data{ID: id, Value: fftypes.JSONAnyPtr(`{"a":1,"b":{"c":2}}`)}).DisclosureRoot(fftypes.NewRandB32())
+	assert.NotEqual(t, root1, root3)
+	root4 := (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":1,"b":{"c":2}}`)}).DisclosureRoot(salt)
+	assert.NotEqual(t, root1, root4)
+}
+
+func TestDiscloseFieldsErrors(t *testing.T) {
+	salt := fftypes.NewRandB32()
+	_, err := (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":1}`)}).DiscloseFields(context.Background(), salt, nil)
+	assert.Regexp(t, "FF10645", err)
+	_, err = (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"a"`)}).DiscloseFields(context.Background(), salt, []string{"a"})
+	assert.Regexp(t, "FF10639", err)
+	_, err = (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":1}`)}).DiscloseFields(context.Background(), salt, []string{"b"})
+	assert.Regexp(t, "FF10640", err)
+	_, err = (&Data{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`{"a":1}`)}).DiscloseFields(context.Background(), salt, []string{"0"})
+	assert.Regexp(t, "FF10640", err)
+}
+
+func TestDisclosureProofEachField(t *testing.T) {
+	for _, field := range []string{"a", "b", "c", "d", "e", "f"} {
+		if field == "d" {
+			continue // nested fields are disclosed with their parent
+		}
+		proof, _ := newTestDisclosureProof(t, field)
+		assert.NoError(t, proof.Verify(context.Background()), field)
+	}
+	proof, _ := newTestDisclosureProof(t, "e", "a")
+	assert.NoError(t, proof.Verify(context.Background()))
+	assert.Equal(t, `[1, 2]`, proof.Fields[0].Value.String())
+	assert.Equal(t, `1`, proof.Fields[1].Value.String())
+}
+
+func TestDisclosureProofSingleField(t *testing.T) {
+	batch, data := newTestDisclosureBatch(t, `{"only":"one"}`)
+	disclosed, err := data.DiscloseFields(context.Background(), batch.Payload.DisclosureSalt, []string{"only"})
+	assert.NoError(t, err)
+	assert.Empty(t, disclosed[0].Path)
+	proof := &DisclosureProof{
+		Batch:   batch.Payload.Manifest(batch.ID),
+		Message: batch.Payload.Messages[0],
+		Data:    data.ID,
+		Fields:  disclosed,
+	}
+	assert.NoError(t, proof.Verify(context.Background()))
+}
+
+func TestDisclosureProofValueChanged(t *testing.T) {
+	proof, _ := newTestDisclosureProof(t, "b")
+	proof.Fields[0].Value = fftypes.JSONAnyPtr(`"three"`)
+	assert.Regexp(t, "FF10649", proof.Verify(context.Background()))
+	proof.Fields[0].Value = nil
+	assert.Regexp(t, "FF10649", proof.Verify(context.Background()))
+}
+
+func TestDisclosureProofSaltChanged(t *testing.T) {
+	proof, _ := newTestDisclosureProof(t, "b")
+	proof.Fields[0].Salt = fftypes.NewRandB32()
+	assert.Regexp(t, "FF10649", proof.Verify(context.Background()))
+}
+
+func TestDisclosureProofIncomplete(t *testing.T) {
+	proof, _ := newTestDisclosureProof(t, "b")
+	proof.Fields = nil
+	assert.Regexp(t, "FF10646", proof.Verify(context.Background()))
+
+	proof, _ = newTestDisclosureProof(t, "b")
+	proof.Fields[0].Salt = nil
+	assert.Regexp(t, "FF10646", proof.Verify(context.Background()))
+
+	proof, _ = newTestDisclosureProof(t, "b")
+	proof.Fields[0].Path[0] = nil
+	assert.Regexp(t, "FF10646", proof.Verify(context.Background()))
+}
+
+func TestDisclosureProofBadMessage(t *testing.T) {
+	proof, _ := newTestDisclosureProof(t, "b")
+	proof.Message.Header.Tag = "changed"
+	assert.Regexp(t, "FF00132", proof.Verify(context.Background()))
+}
+
+func TestDisclosureProofMessageNotInBatch(t *testing.T) {
+	proof, _ := newTestDisclosureProof(t, "b")
+	proof.Batch.Messages[0].Hash = fftypes.NewRandB32()
+	assert.Regexp(t, "FF10647", proof.Verify(context.Background()))
+}
+
+func TestDisclosureProofDataNotInMessage(t *testing.T) {
+	proof, _ := newTestDisclosureProof(t, "b")
+	proof.Data = fftypes.NewUUID()
+	assert.Regexp(t, "FF10644", proof.Verify(context.Background()))
+}
+
+func TestDisclosureProofDataNotInBatch(t *testing.T) {
+	proof, _ := newTestDisclosureProof(t, "b")
+	proof.Batch.Disclosure = nil
+	assert.Regexp(t, "FF10648", proof.Verify(context.Background()))
+}
@@ -29,6 +29,16 @@ var (
 	EventTypeMessageConfirmed = fftypes.FFEnumValue("eventtype", "message_confirmed")
 	// EventTypeMessageRejected occurs if a message is received and confirmed from a sequencing perspective, but is rejected as invalid (mismatch to schema, or duplicate system broadcast)
 	EventTypeMessageRejected = fftypes.FFEnumValue("eventtype", "message_rejected")
+	// EventTypeMessageExpired occurs when a message sent with a TTL reaches its expiry time without being confirmed
+	EventTypeMessageExpired = fftypes.FFEnumValue("eventtype", "message_expired")
+	// EventTypeGroupMembershipChanged occurs when the membership of a private group has been changed, to the members of the group before and after the change
+	EventTypeGroupMembershipChanged = fftypes.FFEnumValue("eventtype", "group_membership_changed")
+	// EventTypeMessageRecalled occurs when a private message has been recalled by its author, with the recalled message as the reference
+	EventTypeMessageRecalled = fftypes.FFEnumValue("eventtype", "message_recalled")
+	// EventTypeTopicSequenceGap occurs when a message on a strict topic arrives ahead of its topic sequence, and is parked until the missing messages arrive
+	EventTypeTopicSequenceGap = fftypes.FFEnumValue("eventtype", "topic_sequence_gap")
+	// EventTypeMessagePendingApp occurs when a message received on a deferred topic is ready to be confirmed, and is held until the local application accepts or rejects it
+	EventTypeMessagePendingApp = fftypes.FFEnumValue("eventtype", "message_pending_app")
 	// EventTypeDatatypeConfirmed occurs when a new datatype is ready for use (on the namespace of the datatype)
 	EventTypeDatatypeConfirmed = fftypes.FFEnumValue("eventtype", "datatype_confirmed")
 	// EventTypeIdentityConfirmed occurs when a new identity has been confirmed, as as result of a signed claim broadcast, and any associated claim verification
@@ -39,20 +49,48 @@ var (
 	EventTypePoolConfirmed = fftypes.FFEnumValue("eventtype", "token_pool_confirmed")
 	// EventTypePoolOpFailed occurs when a token pool creation initiated by this node has failed (based on feedback from connector)
 	EventTypePoolOpFailed = fftypes.FFEnumValue("eventtype", "token_pool_op_failed")
+	// EventTypePoolPaused occurs when a token pool has been paused on this node
+	EventTypePoolPaused = fftypes.FFEnumValue("eventtype", "token_pool_paused")
+	// EventTypePoolResumed occurs when a paused token pool has been resumed on this node
+	EventTypePoolResumed = fftypes.FFEnumValue("eventtype", "token_pool_resumed")
+	// EventTypePoolRetired occurs when a token pool has been retired on this node
+	EventTypePoolRetired = fftypes.FFEnumValue("eventtype", "token_pool_retired")
+	// EventTypePoolMigrated occurs when a token pool has been migrated to a new token connector on this node
+	EventTypePoolMigrated = fftypes.FFEnumValue("eventtype", "token_pool_migrated")
+	// EventTypePoolMigrationFailed occurs when the migration of a token pool to a new token connector has failed
+	EventTypePoolMigrationFailed = fftypes.FFEnumValue("eventtype", "token_pool_migration_failed")
 	// EventTypeTransferConfirmed occurs when a token transfer has been confirmed
 	EventTypeTransferConfirmed = fftypes.FFEnumValue("eventtype", "token_transfer_confirmed")
 	// EventTypeTransferOpFailed occurs when a token transfer submitted by this node has failed (based on feedback from connector)
 	EventTypeTransferOpFailed = fftypes.FFEnumValue("eventtype", "token_transfer_op_failed")
+	// EventTypeTransferInvalidated occurs when a previously confirmed token transfer has been reversed, because its blockchain event was invalidated
+	EventTypeTransferInvalidated = fftypes.FFEnumValue("eventtype", "token_transfer_invalidated")
+	// EventTypeTransferReconfirmed occurs when a token transfer that was invalidated has been confirmed again, and re-applied to the token balances
+	EventTypeTransferReconfirmed = fftypes.FFEnumValue("eventtype", "token_transfer_reconfirmed")
 	// EventTypeApprovalConfirmed occurs when a token approval has been confirmed
 	EventTypeApprovalConfirmed = fftypes.FFEnumValue("eventtype", "token_approval_confirmed")
 	// EventTypeApprovalOpFailed occurs when a token approval submitted by this node has failed (based on feedback from connector)
 	EventTypeApprovalOpFailed = fftypes.FFEnumValue("eventtype", "token_approval_op_failed")
+	// EventTypeApprovalExpired occurs when a token approval submitted by this node has reached its expiry, and a revocation has been submitted
+	EventTypeApprovalExpired = fftypes.FFEnumValue("eventtype", "token_approval_expired")
+	// EventTypeSwapCompleted occurs when a token swap submitted by this node has completed, and all escrowed tokens have been released to their recipients
+	EventTypeSwapCompleted = fftypes.FFEnumValue("eventtype", "token_swap_completed")
+	// EventTypeSwapRefunded occurs when a leg of a token swap submitted by this node failed, and any escrowed tokens have been returned to their owners
+	EventTypeSwapRefunded = fftypes.FFEnumValue("eventtype", "token_swap_refunded")
+	// EventTypeSwapFailed occurs when a claim or refund for a token swap submitted by this node failed, and requires the operation to be retried
+	EventTypeSwapFailed = fftypes.FFEnumValue("eventtype", "token_swap_failed")
+	// EventTypeReconciliationMismatch occurs when the balance FireFly has computed for an account does not match the balance reported on-chain
+	EventTypeReconciliationMismatch = fftypes.FFEnumValue("eventtype", "reconciliation_mismatch")
 	// EventTypeContractInterfaceConfirmed occurs when a new contract interface has been confirmed
 	EventTypeContractInterfaceConfirmed = fftypes.FFEnumValue("eventtype", "contract_interface_confirmed")
 	// EventTypeContractAPIConfirmed occurs when a new contract API has been confirmed
 	EventTypeContractAPIConfirmed = fftypes.FFEnumValue("eventtype", "contract_api_confirmed")
 	// EventTypeBlockchainEventReceived occurs when a new event has been received from the blockchain
 	EventTypeBlockchainEventReceived = fftypes.FFEnumValue("eventtype", "blockchain_event_received")
+	// EventTypeBlockchainEventInvalidated occurs when a previously received blockchain event has been removed from the canonical chain, such as by a chain reorganization
+	EventTypeBlockchainEventInvalidated = fftypes.FFEnumValue("eventtype", "blockchain_event_invalidated")
+	// EventTypeBlockchainEventReconfirmed occurs when a blockchain event that was invalidated is back on the canonical chain
+	EventTypeBlockchainEventReconfirmed = fftypes.FFEnumValue("eventtype", "blockchain_event_reconfirmed")
 	// EventTypeBlockchainInvokeOpSucceeded occurs when a blockchain "invoke" request has succeeded
 	EventTypeBlockchainInvokeOpSucceeded = fftypes.FFEnumValue("eventtype", "blockchain_invoke_op_succeeded")
 	// EventTypeBlockchainInvokeOpFailed occurs when a blockchain "invoke" request has failed
@@ -61,6 +99,16 @@ var (
 	EventTypeBlockchainContractDeployOpSucceeded = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_succeeded")
 	// EventTypeBlockchainContractDeployOpFailed occurs when a contract deployment request has failed
 	EventTypeBlockchainContractDeployOpFailed = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_failed")
+	// EventTypeDeadLetterCreated occurs when an event could not be delivered to a subscription after exhausting its retry policy, and has been recorded as a dead letter
+	EventTypeDeadLetterCreated = fftypes.FFEnumValue("eventtype", "dead_letter_created")
+	// EventTypeSenderThrottled occurs when the private batches received from an org start to be delayed, because the org is over the inbound message rate
+	EventTypeSenderThrottled = fftypes.FFEnumValue("eventtype", "sender_throttled")
+	// EventTypeBatchQuarantined occurs when a private batch received from an org is held for an administrator to release, because the org is over the inbound limits
+	EventTypeBatchQuarantined = fftypes.FFEnumValue("eventtype", "batch_quarantined")
+	// EventTypeBlobQuarantined occurs when a blob received from another node is held for an administrator to release, because the blob scanner flagged its content
+	EventTypeBlobQuarantined = fftypes.FFEnumValue("eventtype", "blob_quarantined")
+	// EventTypeSLABreached occurs when a message sent by this node takes longer than its SLA to be confirmed by this node, or by the node of another member
+	EventTypeSLABreached = fftypes.FFEnumValue("eventtype", "sla_breached")
 )
 
 // Event is an activity in the system, delivered reliably to applications, that indicates something has happened in the network
@@ -79,24 +127,40 @@ type Event struct {
 // EnrichedEvent adds the referred object to an event
 type EnrichedEvent struct {
 	Event
-	BlockchainEvent   *BlockchainEvent `ffstruct:"EnrichedEvent" json:"blockchainEvent,omitempty"`
-	ContractAPI       *ContractAPI     `ffstruct:"EnrichedEvent" json:"contractAPI,omitempty"`
-	ContractInterface *fftypes.FFI     `ffstruct:"EnrichedEvent" json:"contractInterface,omitempty"`
-	Datatype          *Datatype        `ffstruct:"EnrichedEvent" json:"datatype,omitempty"`
-	Identity          *Identity        `ffstruct:"EnrichedEvent" json:"identity,omitempty"`
-	Message           *Message         `ffstruct:"EnrichedEvent" json:"message,omitempty"`
-	TokenApproval     *TokenApproval   `ffstruct:"EnrichedEvent" json:"tokenApproval,omitempty"`
-	TokenPool         *TokenPool       `ffstruct:"EnrichedEvent" json:"tokenPool,omitempty"`
-	TokenTransfer     *TokenTransfer   `ffstruct:"EnrichedEvent" json:"tokenTransfer,omitempty"`
-	Transaction       *Transaction     `ffstruct:"EnrichedEvent" json:"transaction,omitempty"`
-	Operation         *Operation       `ffstruct:"EnrichedEvent" json:"operation,omitempty"`
+	BlockchainEvent   *BlockchainEvent      `ffstruct:"EnrichedEvent" json:"blockchainEvent,omitempty"`
+	ContractAPI       *ContractAPI          `ffstruct:"EnrichedEvent" json:"contractAPI,omitempty"`
+	ContractInterface *fftypes.FFI          `ffstruct:"EnrichedEvent" json:"contractInterface,omitempty"`
+	Datatype          *Datatype             `ffstruct:"EnrichedEvent" json:"datatype,omitempty"`
+	DeadLetter        *DeadLetter           `ffstruct:"EnrichedEvent" json:"deadLetter,omitempty"`
+	Identity          *Identity             `ffstruct:"EnrichedEvent" json:"identity,omitempty"`
+	Message           *Message              `ffstruct:"EnrichedEvent" json:"message,omitempty"`
+	ParentMessage     *Message              `ffstruct:"EnrichedEvent" json:"parentMessage,omitempty"`
+	QuarantinedBatch  *QuarantinedBatch     `ffstruct:"EnrichedEvent" json:"quarantinedBatch,omitempty"`
+	QuarantinedBlob   *QuarantinedBlob      `ffstruct:"EnrichedEvent" json:"quarantinedBlob,omitempty"`
+	SLABreach         *SLABreach            `ffstruct:"EnrichedEvent" json:"slaBreach,omitempty"`
+	TokenApproval     *TokenApproval        `ffstruct:"EnrichedEvent" json:"tokenApproval,omitempty"`
+	TokenPool         *TokenPool            `ffstruct:"EnrichedEvent" json:"tokenPool,omitempty"`
+	TokenSwap         *TokenSwap            `ffstruct:"EnrichedEvent" json:"tokenSwap,omitempty"`
+	TokenMismatch     *TokenBalanceMismatch `ffstruct:"EnrichedEvent" json:"tokenMismatch,omitempty"`
+	TokenTransfer     *TokenTransfer        `ffstruct:"EnrichedEvent" json:"tokenTransfer,omitempty"`
+	Transaction       *Transaction          `ffstruct:"EnrichedEvent" json:"transaction,omitempty"`
+	Operation         *Operation            `ffstruct:"EnrichedEvent" json:"operation,omitempty"`
+	Receipt           *TransactionReceipt   `ffstruct:"EnrichedEvent" json:"receipt,omitempty"`
 }
 
 // EventDelivery adds the referred object to an event, as well as details of the subscription that caused the event to
 // be dispatched to an application.
 type EventDelivery struct {
 	EnrichedEvent
-	Subscription SubscriptionRef `json:"subscription"`
+	Subscription SubscriptionRef    `ffstruct:"EventDelivery" json:"subscription"`
+	Labels       fftypes.JSONObject `ffstruct:"EventDelivery" json:"labels,omitempty"`
+}
+
+// CombinedEventDataDelivery is an event in a batch delivery, with the data of its message
+// if the subscription includes data
+type CombinedEventDataDelivery struct {
+	Event *EventDelivery
+	Data  DataArray
 }
 
 // EventDeliveryResponse is the payload an application sends back, to confirm it has accepted (or rejected) the event and as such
@@ -107,6 +171,7 @@ type EventDeliveryResponse struct {
 	Info         string          `json:"info,omitempty"`
 	Subscription SubscriptionRef `json:"subscription"`
 	Reply        *MessageInOut   `json:"reply,omitempty"`
+	DeadLetter   *DeadLetter     `json:"-"` // set by transports with a retry policy, never by applications
 }
 
 func NewEvent(t EventType, ns string, ref *fftypes.UUID, tx *fftypes.UUID, topic string) *Event {
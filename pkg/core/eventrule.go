@@ -0,0 +1,103 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// EventRuleAction is what an event rule does with the events it matches, for the subscriptions it targets
+type EventRuleAction = fftypes.FFEnum
+
+var (
+	// EventRuleActionRoute delivers matching events to the targeted subscriptions, even if their own filters do not match them
+	EventRuleActionRoute = fftypes.FFEnumValue("eventruleaction", "route")
+	// EventRuleActionLabel adds labels to matching events that the targeted subscriptions receive
+	EventRuleActionLabel = fftypes.FFEnumValue("eventruleaction", "label")
+	// EventRuleActionSuppress stops matching events being delivered to the targeted subscriptions
+	EventRuleActionSuppress = fftypes.FFEnumValue("eventruleaction", "suppress")
+)
+
+// EventRuleMatch are the conditions an event must meet for a rule to apply to it. All the conditions that are set must match.
+type EventRuleMatch struct {
+	Events     string `ffstruct:"EventRuleMatch" json:"events,omitempty"`
+	Topic      string `ffstruct:"EventRuleMatch" json:"topic,omitempty"`
+	Expression string `ffstruct:"EventRuleMatch" json:"expression,omitempty"`
+}
+
+// EventRuleTargets are the subscriptions a rule applies to - by name, or by the transport they use.
+// A rule with no targets applies to every subscription in the namespace.
+type EventRuleTargets struct {
+	Subscriptions []string `ffstruct:"EventRuleTargets" json:"subscriptions,omitempty"`
+	Transports    []string `ffstruct:"EventRuleTargets" json:"transports,omitempty"`
+}
+
+// EventRule is a namespace-level rule that routes, labels or suppresses classes of events for a set of subscriptions,
+// in addition to the filters of the subscriptions themselves
+type EventRule struct {
+	ID        *fftypes.UUID      `ffstruct:"EventRule" json:"id" ffexcludeinput:"true"`
+	Namespace string             `ffstruct:"EventRule" json:"namespace" ffexcludeinput:"true"`
+	Name      string             `ffstruct:"EventRule" json:"name"`
+	Action    EventRuleAction    `ffstruct:"EventRule" json:"action" ffenum:"eventruleaction"`
+	Match     EventRuleMatch     `ffstruct:"EventRule" json:"match"`
+	Targets   EventRuleTargets   `ffstruct:"EventRule" json:"targets"`
+	Labels    fftypes.JSONObject `ffstruct:"EventRule" json:"labels,omitempty"`
+	Created   *fftypes.FFTime    `ffstruct:"EventRule" json:"created" ffexcludeinput:"true"`
+}
+
+// Scan implements sql.Scanner
+func (m *EventRuleMatch) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), m)
+	case []byte:
+		return json.Unmarshal(src, m)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, m)
+	}
+}
+
+// Value implements sql.Valuer
+func (m EventRuleMatch) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+// Scan implements sql.Scanner
+func (t *EventRuleTargets) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), t)
+	case []byte:
+		return json.Unmarshal(src, t)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, t)
+	}
+}
+
+// Value implements sql.Valuer
+func (t EventRuleTargets) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
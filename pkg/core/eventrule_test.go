@@ -0,0 +1,67 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventRuleMatchScanValue(t *testing.T) {
+	match := EventRuleMatch{Events: "message_confirmed", Topic: "^orders$"}
+	val, err := match.Value()
+	assert.NoError(t, err)
+
+	var restored EventRuleMatch
+	err = restored.Scan(val)
+	assert.NoError(t, err)
+	assert.Equal(t, match, restored)
+
+	restored = EventRuleMatch{}
+	err = restored.Scan(string(val.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, match, restored)
+
+	err = restored.Scan(nil)
+	assert.NoError(t, err)
+
+	err = restored.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
+
+func TestEventRuleTargetsScanValue(t *testing.T) {
+	targets := EventRuleTargets{Subscriptions: []string{"sub1"}, Transports: []string{"webhooks"}}
+	val, err := targets.Value()
+	assert.NoError(t, err)
+
+	var restored EventRuleTargets
+	err = restored.Scan(val)
+	assert.NoError(t, err)
+	assert.Equal(t, targets, restored)
+
+	restored = EventRuleTargets{}
+	err = restored.Scan(string(val.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, targets, restored)
+
+	err = restored.Scan(nil)
+	assert.NoError(t, err)
+
+	err = restored.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
@@ -25,12 +25,15 @@ import (
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 )
 
 type GroupIdentity struct {
-	Namespace string  `ffstruct:"Group" json:"namespace,omitempty"`
-	Name      string  `ffstruct:"Group" json:"name"`
-	Members   Members `ffstruct:"Group" json:"members"`
+	Namespace  string           `ffstruct:"Group" json:"namespace,omitempty"`
+	Name       string           `ffstruct:"Group" json:"name"`
+	Members    Members          `ffstruct:"Group" json:"members"`
+	Previous   *fftypes.Bytes32 `ffstruct:"Group" json:"previous,omitempty"`
+	Generation int64            `ffstruct:"Group" json:"generation,omitempty"`
 }
 
 type Group struct {
@@ -39,6 +42,24 @@ type Group struct {
 	Message        *fftypes.UUID    `ffstruct:"Group" json:"message,omitempty"`
 	Hash           *fftypes.Bytes32 `ffstruct:"Group" json:"hash,omitempty"`
 	Created        *fftypes.FFTime  `ffstruct:"Group" json:"created,omitempty"`
+	Successor      *fftypes.Bytes32 `ffstruct:"Group" json:"successor,omitempty"`
+	Superseded     *fftypes.FFTime  `ffstruct:"Group" json:"superseded,omitempty"`
+}
+
+// GroupMembersInput is a request to add and remove members of a group, which creates the next generation of the group
+type GroupMembersInput struct {
+	SignerRef
+	Add    []MemberInput `ffstruct:"GroupMembersInput" json:"add,omitempty"`
+	Remove []MemberInput `ffstruct:"GroupMembersInput" json:"remove,omitempty"`
+}
+
+// GroupMembershipChange is sent to the members of a group when its membership changes.
+// It does not contain the hash of the next generation, which is only sent to the members of that generation.
+type GroupMembershipChange struct {
+	Group      *fftypes.Bytes32 `json:"group"`
+	Generation int64            `json:"generation"`
+	Added      Members          `json:"added,omitempty"`
+	Removed    Members          `json:"removed,omitempty"`
 }
 
 type Members []*Member
@@ -104,6 +125,9 @@ func (group *Group) Validate(ctx context.Context, existing bool) (err error) {
 		}
 		dupCheck[key] = true
 	}
+	if (group.Previous == nil) != (group.Generation == 0) {
+		return i18n.NewError(ctx, coremsgs.MsgGroupInvalidGeneration, group.Generation)
+	}
 	if existing {
 		hash := group.GroupIdentity.Hash()
 		if !group.Hash.Equals(hash) {
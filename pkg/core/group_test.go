@@ -146,6 +146,32 @@ func TestGroupValidation(t *testing.T) {
 	assert.NotNil(t, group.Message)
 }
 
+func TestGroupValidationGeneration(t *testing.T) {
+
+	group := &Group{
+		GroupIdentity: GroupIdentity{
+			Name:       "ok",
+			Namespace:  "ok",
+			Members:    Members{{Identity: "0x12345", Node: fftypes.NewUUID()}},
+			Generation: 1,
+		},
+	}
+	assert.Regexp(t, "FF10621", group.Validate(context.Background(), false))
+
+	group.Generation = 0
+	group.Previous = fftypes.NewRandB32()
+	assert.Regexp(t, "FF10621", group.Validate(context.Background(), false))
+
+	first := &Group{GroupIdentity: group.GroupIdentity}
+	first.Previous = nil
+	first.Seal()
+	group.Previous = first.Hash
+	group.Generation = 1
+	group.Seal()
+	assert.NoError(t, group.Validate(context.Background(), true))
+	assert.NotEqual(t, first.Hash, group.Hash)
+}
+
 func TestGroupSealSorting(t *testing.T) {
 
 	m1 := &Member{Node: fftypes.NewUUID(), Identity: "0x11111"}
@@ -0,0 +1,100 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// JobType is the long-running administrative action performed by a job
+type JobType = fftypes.FFEnum
+
+var (
+	// JobTypeSubscriptionRewind rewinds a durable subscription to a sequence or timestamp
+	JobTypeSubscriptionRewind = fftypes.FFEnumValue("jobtype", "subscription_rewind")
+	// JobTypeTokenPoolSnapshot takes a snapshot of the balances of a token pool
+	JobTypeTokenPoolSnapshot = fftypes.FFEnumValue("jobtype", "token_pool_snapshot")
+	// JobTypeTokenSnapshotExport exports the balances of a token snapshot to a file
+	JobTypeTokenSnapshotExport = fftypes.FFEnumValue("jobtype", "token_snapshot_export")
+	// JobTypeTokenTransfersExport exports token transfer history to a file
+	JobTypeTokenTransfersExport = fftypes.FFEnumValue("jobtype", "token_transfers_export")
+	// JobTypeTokenLedgerExport exports token ledger entries to a file
+	JobTypeTokenLedgerExport = fftypes.FFEnumValue("jobtype", "token_ledger_export")
+)
+
+// JobStatus is the current status of a job
+type JobStatus = fftypes.FFEnum
+
+var (
+	// JobStatusPending is a job waiting for a worker
+	JobStatusPending = fftypes.FFEnumValue("jobstatus", "pending")
+	// JobStatusRunning is a job being performed by a worker
+	JobStatusRunning = fftypes.FFEnumValue("jobstatus", "running")
+	// JobStatusSucceeded is a job that completed, with its output
+	JobStatusSucceeded = fftypes.FFEnumValue("jobstatus", "succeeded")
+	// JobStatusFailed is a job that completed with an error
+	JobStatusFailed = fftypes.FFEnumValue("jobstatus", "failed")
+	// JobStatusCancelled is a job that was cancelled before it completed
+	JobStatusCancelled = fftypes.FFEnumValue("jobstatus", "cancelled")
+)
+
+// JobInput is the request to start a job
+type JobInput struct {
+	Type  JobType            `ffstruct:"JobInput" json:"type" ffenum:"jobtype"`
+	Input fftypes.JSONObject `ffstruct:"JobInput" json:"input,omitempty"`
+}
+
+// JobProgress is the progress of a running job, in units specific to the type of job
+type JobProgress struct {
+	Done  int64 `ffstruct:"JobProgress" json:"done"`
+	Total int64 `ffstruct:"JobProgress" json:"total,omitempty"`
+}
+
+// Job is a long-running administrative action, performed in the background by a worker so the progress
+// can be polled, and the action cancelled, rather than holding an HTTP request open until it completes
+type Job struct {
+	ID        *fftypes.UUID      `ffstruct:"Job" json:"id"`
+	Namespace string             `ffstruct:"Job" json:"namespace"`
+	Type      JobType            `ffstruct:"Job" json:"type" ffenum:"jobtype"`
+	Status    JobStatus          `ffstruct:"Job" json:"status" ffenum:"jobstatus"`
+	Input     fftypes.JSONObject `ffstruct:"Job" json:"input,omitempty"`
+	Output    fftypes.JSONObject `ffstruct:"Job" json:"output,omitempty"`
+	Progress  JobProgress        `ffstruct:"Job" json:"progress"`
+	Error     string             `ffstruct:"Job" json:"error,omitempty"`
+	Created   *fftypes.FFTime    `ffstruct:"Job" json:"created"`
+	Updated   *fftypes.FFTime    `ffstruct:"Job" json:"updated,omitempty"`
+}
+
+// IsComplete checks whether a job has reached a final status
+func (j *Job) IsComplete() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// JobSubscriptionRewindInput is the input of a subscription_rewind job
+type JobSubscriptionRewindInput struct {
+	Subscription string `ffstruct:"JobSubscriptionRewindInput" json:"subscription"`
+	SubscriptionRewind
+}
+
+// JobTokenPoolSnapshotInput is the input of a token_pool_snapshot job
+type JobTokenPoolSnapshotInput struct {
+	Pool string `ffstruct:"JobTokenPoolSnapshotInput" json:"pool"`
+	TokenSnapshotInput
+}
+
+// JobTokenSnapshotExportInput is the input of a token_snapshot_export job
+type JobTokenSnapshotExportInput struct {
+	Snapshot string `ffstruct:"JobTokenSnapshotExportInput" json:"snapshot"`
+}
@@ -42,6 +42,8 @@ var (
 	MessageTypePrivate = fftypes.FFEnumValue("messagetype", "private")
 	// MessageTypeGroupInit is a special private message that contains the definition of the group
 	MessageTypeGroupInit = fftypes.FFEnumValue("messagetype", "groupinit")
+	// MessageTypeChunk is a part of the data of a broadcast message that was too large to send in a single batch
+	MessageTypeChunk = fftypes.FFEnumValue("messagetype", "chunk")
 	// MessageTypeDeprecatedTransferBroadcast is deprecated - use MessageTypeBroadcast (and refer to TxParent.Type)
 	MessageTypeDeprecatedTransferBroadcast = fftypes.FFEnumValue("messagetype", "transfer_broadcast")
 	// MessageTypeDeprecatedTransferPrivate is deprecated - use MessageTypePrivate (and refer to TxParent.Type)
@@ -58,6 +60,12 @@ type MessageState = fftypes.FFEnum
 var (
 	// MessageStateStaged is a message created locally which is not ready to send
 	MessageStateStaged = fftypes.FFEnumValue("messagestate", "staged")
+	// MessageStateScheduled is a message created locally which is waiting for its send time before it is ready to send
+	MessageStateScheduled = fftypes.FFEnumValue("messagestate", "scheduled")
+	// MessageStateDraft is a message created locally with its data stored and validated, which is held until it is committed
+	MessageStateDraft = fftypes.FFEnumValue("messagestate", "draft")
+	// MessageStateCancelled is a scheduled or draft message that was cancelled before it was sent
+	MessageStateCancelled = fftypes.FFEnumValue("messagestate", "cancelled")
 	// MessageStateReady is a message created locally which is ready to send
 	MessageStateReady = fftypes.FFEnumValue("messagestate", "ready")
 	// MessageStateSent is a message created locally which has been sent in a batch
@@ -68,6 +76,24 @@ var (
 	MessageStateConfirmed = fftypes.FFEnumValue("messagestate", "confirmed")
 	// MessageStateRejected is a message that has completed confirmation, but has been rejected by FireFly
 	MessageStateRejected = fftypes.FFEnumValue("messagestate", "rejected")
+	// MessageStateExpired is a message that reached its expiry time before it was confirmed
+	MessageStateExpired = fftypes.FFEnumValue("messagestate", "expired")
+	// MessageStateRecalled is a private message that was recalled by its author after it was sent
+	MessageStateRecalled = fftypes.FFEnumValue("messagestate", "recalled")
+	// MessageStateParked is a message on a strict topic that arrived ahead of its topic sequence, and is held until the gap is filled
+	MessageStateParked = fftypes.FFEnumValue("messagestate", "parked")
+	// MessageStatePendingApp is a message received on a deferred topic, which is held until the local application accepts or rejects it
+	MessageStatePendingApp = fftypes.FFEnumValue("messagestate", "pending_app")
+)
+
+// MessagePriority is the priority class of a locally sent message, used in batch assembly
+type MessagePriority = fftypes.FFEnum
+
+var (
+	// MessagePriorityNormal is the default priority, where messages are batched in the order they were sent
+	MessagePriorityNormal = fftypes.FFEnumValue("messagepriority", "normal")
+	// MessagePriorityHigh is for messages that should be sent ahead of normal traffic, flushing the batch they are assembled into
+	MessagePriorityHigh = fftypes.FFEnumValue("messagepriority", "high")
 )
 
 // MessageHeader contains all fields that contribute to the hash
@@ -78,13 +104,15 @@ type MessageHeader struct {
 	Type   MessageType     `ffstruct:"MessageHeader" json:"type" ffenum:"messagetype"`
 	TxType TransactionType `ffstruct:"MessageHeader" json:"txtype,omitempty" ffenum:"txtype"`
 	SignerRef
-	Created   *fftypes.FFTime       `ffstruct:"MessageHeader" json:"created,omitempty" ffexcludeinput:"true"`
-	Namespace string                `ffstruct:"MessageHeader" json:"namespace,omitempty" ffexcludeinput:"true"`
-	Group     *fftypes.Bytes32      `ffstruct:"MessageHeader" json:"group,omitempty" ffexclude:"postNewMessageBroadcast"`
-	Topics    fftypes.FFStringArray `ffstruct:"MessageHeader" json:"topics,omitempty"`
-	Tag       string                `ffstruct:"MessageHeader" json:"tag,omitempty"`
-	DataHash  *fftypes.Bytes32      `ffstruct:"MessageHeader" json:"datahash,omitempty" ffexcludeinput:"true"`
-	TxParent  *TransactionRef       `ffstruct:"MessageHeader" json:"txparent,omitempty" ffexcludeinput:"true"`
+	Created       *fftypes.FFTime       `ffstruct:"MessageHeader" json:"created,omitempty" ffexcludeinput:"true"`
+	Namespace     string                `ffstruct:"MessageHeader" json:"namespace,omitempty" ffexcludeinput:"true"`
+	Group         *fftypes.Bytes32      `ffstruct:"MessageHeader" json:"group,omitempty" ffexclude:"postNewMessageBroadcast"`
+	Topics        fftypes.FFStringArray `ffstruct:"MessageHeader" json:"topics,omitempty"`
+	Tag           string                `ffstruct:"MessageHeader" json:"tag,omitempty"`
+	DataHash      *fftypes.Bytes32      `ffstruct:"MessageHeader" json:"datahash,omitempty" ffexcludeinput:"true"`
+	TxParent      *TransactionRef       `ffstruct:"MessageHeader" json:"txparent,omitempty" ffexcludeinput:"true"`
+	ParentMessage *fftypes.UUID         `ffstruct:"MessageHeader" json:"parentMessage,omitempty"`
+	TopicSequence int64                 `ffstruct:"MessageHeader" json:"topicSequence,omitempty"`
 }
 
 // Message is the envelope by which coordinated data exchange can happen between parties in the network
@@ -102,6 +130,10 @@ type Message struct {
 	Data           DataRefs              `ffstruct:"Message" json:"data" ffexcludeinput:"true"`
 	Pins           fftypes.FFStringArray `ffstruct:"Message" json:"pins,omitempty" ffexcludeinput:"true"`
 	IdempotencyKey IdempotencyKey        `ffstruct:"Message" json:"idempotencyKey,omitempty"`
+	SendTime       *fftypes.FFTime       `ffstruct:"Message" json:"sendTime,omitempty"`
+	Priority       MessagePriority       `ffstruct:"Message" json:"priority,omitempty" ffenum:"messagepriority"`
+	Expires        *fftypes.FFTime       `ffstruct:"Message" json:"expires,omitempty" ffexcludeinput:"true"`
+	Chunks         fftypes.FFStringArray `ffstruct:"Message" json:"chunks,omitempty" ffexcludeinput:"true"`
 	Sequence       int64                 `ffstruct:"Message" json:"-"` // Local database sequence used internally for batch assembly
 }
 
@@ -119,6 +151,8 @@ func (m *Message) BatchMessage() *Message {
 		TransactionID: m.TransactionID,
 		// The pins are immutable once assigned by the sender, which happens before the batch is sealed
 		Pins: m.Pins,
+		// The chunks are verified by reassembling the data, which must match the data hash of the header
+		Chunks: m.Chunks,
 	}
 }
 
@@ -126,8 +160,12 @@ func (m *Message) BatchMessage() *Message {
 // will be broken out and stored separately during the call.
 type MessageInOut struct {
 	Message
-	InlineData InlineData  `ffstruct:"MessageInOut" json:"data,omitempty"`
-	Group      *InputGroup `ffstruct:"MessageInOut" json:"group,omitempty" ffexclude:"postNewMessageBroadcast"`
+	InlineData InlineData          `ffstruct:"MessageInOut" json:"data,omitempty"`
+	Group      *InputGroup         `ffstruct:"MessageInOut" json:"group,omitempty" ffexclude:"postNewMessageBroadcast"`
+	TTL        *fftypes.FFDuration `ffstruct:"MessageInOut" json:"ttl,omitempty"`
+	Reply      *ReplyOptions       `ffstruct:"MessageInOut" json:"reply,omitempty" ffexclude:"postNewMessageBroadcast"`
+	Replies    []*ReplyMessage     `ffstruct:"MessageInOut" json:"replies,omitempty" ffexcludeinput:"true"`
+	Draft      bool                `ffstruct:"MessageInOut" json:"draft,omitempty" ffexclude:"postNewMessageRequestReply"`
 }
 
 // InputGroup declares a group in-line for automatic resolution, without having to define a group up-front
@@ -0,0 +1,66 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// DeferredConfirmPolicy is the set of topics on which the messages received from other nodes are held in the
+// pending_app state, until the local application accepts or rejects them, configured for a namespace
+type DeferredConfirmPolicy struct {
+	Topics map[string]bool
+}
+
+// AppliesTo returns true if any of the topics of a message are deferred. A policy without topics applies to all topics.
+func (p *DeferredConfirmPolicy) AppliesTo(topics fftypes.FFStringArray) bool {
+	if p == nil {
+		return false
+	}
+	if len(p.Topics) == 0 {
+		return true
+	}
+	for _, topic := range topics {
+		if p.Topics[topic] {
+			return true
+		}
+	}
+	return false
+}
+
+// MessageAckOutcome is the decision of the local application on a message in the pending_app state
+type MessageAckOutcome = fftypes.FFEnum
+
+var (
+	// MessageAckOutcomeAccepted is a message the application accepted, which is confirmed
+	MessageAckOutcomeAccepted = fftypes.FFEnumValue("messageackoutcome", "accepted")
+	// MessageAckOutcomeRejected is a message the application rejected, which is rejected
+	MessageAckOutcomeRejected = fftypes.FFEnumValue("messageackoutcome", "rejected")
+)
+
+// MessageAck records the decision of the local application on a message that was held for it in the pending_app state
+type MessageAck struct {
+	ID        *fftypes.UUID     `ffstruct:"MessageAck" json:"id"`
+	Namespace string            `ffstruct:"MessageAck" json:"namespace"`
+	Message   *fftypes.UUID     `ffstruct:"MessageAck" json:"message"`
+	Outcome   MessageAckOutcome `ffstruct:"MessageAck" json:"outcome" ffenum:"messageackoutcome"`
+	Reason    string            `ffstruct:"MessageAck" json:"reason,omitempty"`
+	Created   *fftypes.FFTime   `ffstruct:"MessageAck" json:"created"`
+}
+
+// MessageAckInput is the input to accept or reject a message in the pending_app state
+type MessageAckInput struct {
+	Reason string `ffstruct:"MessageAckInput" json:"reason,omitempty"`
+}
@@ -0,0 +1,36 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeferredConfirmPolicyAppliesTo(t *testing.T) {
+	var p *DeferredConfirmPolicy
+	assert.False(t, p.AppliesTo(fftypes.FFStringArray{"topic1"}))
+
+	p = &DeferredConfirmPolicy{}
+	assert.True(t, p.AppliesTo(fftypes.FFStringArray{"topic1"}))
+
+	p = &DeferredConfirmPolicy{Topics: map[string]bool{"orders": true}}
+	assert.True(t, p.AppliesTo(fftypes.FFStringArray{"other", "orders"}))
+	assert.False(t, p.AppliesTo(fftypes.FFStringArray{"other"}))
+}
@@ -0,0 +1,32 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// MessageRecall is the data of a recall message, sent by the author of a private message to the same group,
+// to recall the message from the other members of the group
+type MessageRecall struct {
+	Message *fftypes.UUID    `json:"message"`
+	Hash    *fftypes.Bytes32 `json:"hash"`
+	Purge   bool             `json:"purge,omitempty"`
+}
+
+// MessageRecallInput is the input to recall a private message sent by this node
+type MessageRecallInput struct {
+	Purge bool `ffstruct:"MessageRecallInput" json:"purge,omitempty"`
+}
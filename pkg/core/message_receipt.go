@@ -0,0 +1,64 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// MessageReceiptType is the type of a receipt for a private message
+type MessageReceiptType = fftypes.FFEnum
+
+var (
+	// MessageReceiptTypeDelivered is sent by a recipient node when it has confirmed the message
+	MessageReceiptTypeDelivered = fftypes.FFEnumValue("receipttype", "delivered")
+	// MessageReceiptTypeRead is sent when an application on a recipient node marks the message as read
+	MessageReceiptTypeRead = fftypes.FFEnumValue("receipttype", "read")
+)
+
+// MessageReceipt is sent from the node of a member of a group back to the node that sent a private message,
+// over data exchange. It is authenticated in the same way as a private batch, by the data exchange peer of the node.
+type MessageReceipt struct {
+	ID          *fftypes.UUID      `ffstruct:"MessageReceipt" json:"id"`
+	Namespace   string             `ffstruct:"MessageReceipt" json:"namespace"`
+	Message     *fftypes.UUID      `ffstruct:"MessageReceipt" json:"message"`
+	MessageHash *fftypes.Bytes32   `ffstruct:"MessageReceipt" json:"messageHash"`
+	Type        MessageReceiptType `ffstruct:"MessageReceipt" json:"type" ffenum:"receipttype"`
+	Identity    string             `ffstruct:"MessageReceipt" json:"identity"`
+	Node        *fftypes.UUID      `ffstruct:"MessageReceipt" json:"node"`
+	Created     *fftypes.FFTime    `ffstruct:"MessageReceipt" json:"created"`
+}
+
+// MessageReadInput is the input to mark a private message as read
+type MessageReadInput struct {
+	Identity string `ffstruct:"MessageReadInput" json:"identity,omitempty"`
+}
+
+// MessageDeliveryStatus is the delivery status of a private message sent by this node, to each of the other members of its group
+type MessageDeliveryStatus struct {
+	Message    *fftypes.UUID             `ffstruct:"MessageDeliveryStatus" json:"message"`
+	Group      *fftypes.Bytes32          `ffstruct:"MessageDeliveryStatus" json:"group"`
+	Delivered  int                       `ffstruct:"MessageDeliveryStatus" json:"delivered"`
+	Read       int                       `ffstruct:"MessageDeliveryStatus" json:"read"`
+	Recipients []*MessageRecipientStatus `ffstruct:"MessageDeliveryStatus" json:"recipients"`
+}
+
+// MessageRecipientStatus is the delivery status of a private message to one member of its group
+type MessageRecipientStatus struct {
+	Identity  string          `ffstruct:"MessageRecipientStatus" json:"identity"`
+	Node      *fftypes.UUID   `ffstruct:"MessageRecipientStatus" json:"node"`
+	Delivered *fftypes.FFTime `ffstruct:"MessageRecipientStatus" json:"delivered,omitempty"`
+	Read      *fftypes.FFTime `ffstruct:"MessageRecipientStatus" json:"read,omitempty"`
+}
@@ -255,9 +255,11 @@ func TestMessageImmutable(t *testing.T) {
 		Data: DataRefs{
 			{ID: fftypes.NewUUID(), Hash: fftypes.NewRandB32()},
 		},
-		Pins: fftypes.NewFFStringArray("pin1", "pin2"),
+		Pins:   fftypes.NewFFStringArray("pin1", "pin2"),
+		Chunks: fftypes.NewFFStringArray(fftypes.NewUUID().String()),
 	}
 	assert.True(t, msg.Hash.Equals(msg.BatchMessage().Hash))
+	assert.Equal(t, msg.Chunks, msg.BatchMessage().Chunks)
 }
 
 func TestMessageActions(t *testing.T) {
@@ -29,12 +29,17 @@ import (
 // Namespace is an isolated set of named resources, to allow multiple applications to co-exist in the same network, with the same named objects.
 // Can be used for use case segregation, or multi-tenancy.
 type Namespace struct {
-	Name        string                 `ffstruct:"Namespace" json:"name"`
-	NetworkName string                 `ffstruct:"Namespace" json:"networkName"`
-	Description string                 `ffstruct:"Namespace" json:"description"`
-	Created     *fftypes.FFTime        `ffstruct:"Namespace" json:"created" ffexcludeinput:"true"`
-	Contracts   *MultipartyContracts   `ffstruct:"Namespace" json:"-"`
-	TLSConfigs  map[string]*tls.Config `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	Name            string                 `ffstruct:"Namespace" json:"name"`
+	NetworkName     string                 `ffstruct:"Namespace" json:"networkName"`
+	Description     string                 `ffstruct:"Namespace" json:"description"`
+	Tenant          string                 `ffstruct:"Namespace" json:"tenant,omitempty" ffexcludeinput:"true"`
+	Created         *fftypes.FFTime        `ffstruct:"Namespace" json:"created" ffexcludeinput:"true"`
+	Contracts       *MultipartyContracts   `ffstruct:"Namespace" json:"-"`
+	TLSConfigs      map[string]*tls.Config `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	SLA             *SLAPolicy             `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	Transforms      []*TransformStep       `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	DeferredConfirm *DeferredConfirmPolicy `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	BlobScanner     *BlobScannerConfig     `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
 }
 
 type NamespaceWithInitStatus struct {
@@ -56,8 +56,9 @@ type NamespaceStatusPlugins struct {
 
 // NamespaceStatusPlugin is information about a plugin
 type NamespaceStatusPlugin struct {
-	Name       string `ffstruct:"NamespaceStatusPlugin" json:"name,omitempty"`
-	PluginType string `ffstruct:"NamespaceStatusPlugin" json:"pluginType"`
+	Name       string                 `ffstruct:"NamespaceStatusPlugin" json:"name,omitempty"`
+	PluginType string                 `ffstruct:"NamespaceStatusPlugin" json:"pluginType"`
+	Breaker    *TokenConnectorBreaker `ffstruct:"NamespaceStatusPlugin" json:"breaker,omitempty"`
 }
 
 // NamespaceStatusMultiparty is information about multiparty mode and any associated multiparty contracts
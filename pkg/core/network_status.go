@@ -0,0 +1,71 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// NetworkStatus is a summary of how far behind the blockchain this node is, for a given namespace
+type NetworkStatus struct {
+	Blockchain          []*BlockchainNetworkStatus `ffstruct:"NetworkStatus" json:"blockchain"`
+	Listeners           []*ListenerNetworkStatus   `ffstruct:"NetworkStatus" json:"listeners"`
+	TotalListeners      *int64                     `ffstruct:"NetworkStatus" json:"totalListeners,omitempty"`
+	PendingTransactions int64                      `ffstruct:"NetworkStatus" json:"pendingTransactions"`
+}
+
+// BlockchainNetworkStatus is the status of the connection from a blockchain plugin to its connector
+type BlockchainNetworkStatus struct {
+	Name        string                    `ffstruct:"BlockchainNetworkStatus" json:"name,omitempty"`
+	PluginType  string                    `ffstruct:"BlockchainNetworkStatus" json:"pluginType"`
+	ChainHead   int64                     `ffstruct:"BlockchainNetworkStatus" json:"chainHead,omitempty"`
+	EventStream *EventStreamNetworkStatus `ffstruct:"BlockchainNetworkStatus" json:"eventStream,omitempty"`
+	Catchup     *BlockchainCatchupStatus  `ffstruct:"BlockchainNetworkStatus" json:"catchup,omitempty"`
+	Error       string                    `ffstruct:"BlockchainNetworkStatus" json:"error,omitempty"`
+}
+
+// BlockchainCatchupStatus is the progress of replaying events missed while a blockchain plugin was disconnected
+type BlockchainCatchupStatus struct {
+	Active    bool                               `ffstruct:"BlockchainCatchupStatus" json:"active"`
+	Started   *fftypes.FFTime                    `ffstruct:"BlockchainCatchupStatus" json:"started,omitempty"`
+	Completed *fftypes.FFTime                    `ffstruct:"BlockchainCatchupStatus" json:"completed,omitempty"`
+	Events    int64                              `ffstruct:"BlockchainCatchupStatus" json:"events"`
+	LastBlock int64                              `ffstruct:"BlockchainCatchupStatus" json:"lastBlock,omitempty"`
+	Listeners []*BlockchainCatchupListenerStatus `ffstruct:"BlockchainCatchupStatus" json:"listeners,omitempty"`
+}
+
+// BlockchainCatchupListenerStatus is the replay progress of a single listener during catch-up, from the checkpoint held by the connector
+type BlockchainCatchupListenerStatus struct {
+	BackendID string `ffstruct:"BlockchainCatchupListenerStatus" json:"backendId"`
+	Name      string `ffstruct:"BlockchainCatchupListenerStatus" json:"name,omitempty"`
+	FromBlock int64  `ffstruct:"BlockchainCatchupListenerStatus" json:"fromBlock"`
+	LastBlock int64  `ffstruct:"BlockchainCatchupListenerStatus" json:"lastBlock,omitempty"`
+	Events    int64  `ffstruct:"BlockchainCatchupListenerStatus" json:"events"`
+}
+
+// EventStreamNetworkStatus is the status of the event stream used by a blockchain plugin
+type EventStreamNetworkStatus struct {
+	ID        string `ffstruct:"EventStreamNetworkStatus" json:"id"`
+	Connected bool   `ffstruct:"EventStreamNetworkStatus" json:"connected"`
+}
+
+// ListenerNetworkStatus is the event processing progress of a single contract listener
+type ListenerNetworkStatus struct {
+	ID                 *fftypes.UUID `ffstruct:"ListenerNetworkStatus" json:"id"`
+	Name               string        `ffstruct:"ListenerNetworkStatus" json:"name,omitempty"`
+	BackendID          string        `ffstruct:"ListenerNetworkStatus" json:"backendId,omitempty"`
+	LastProcessedBlock *int64        `ffstruct:"ListenerNetworkStatus" json:"lastProcessedBlock,omitempty"`
+	BlockLag           *int64        `ffstruct:"ListenerNetworkStatus" json:"blockLag,omitempty"`
+}
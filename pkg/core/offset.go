@@ -27,6 +27,8 @@ var (
 	OffsetTypeAggregator = fftypes.FFEnumValue("offsettype", "aggregator")
 	// OffsetTypeSubscription is an offeset stored by a dispatcher on the events table
 	OffsetTypeSubscription = fftypes.FFEnumValue("offsettype", "subscription")
+	// OffsetTypeBridge is an offset stored by a namespace bridge on the events table
+	OffsetTypeBridge = fftypes.FFEnumValue("offsettype", "bridge")
 )
 
 // Offset is a simple stored data structure that records a sequence position within another collection
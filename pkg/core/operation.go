@@ -53,25 +53,43 @@ var (
 	OpTypeDataExchangeSendBatch = fftypes.FFEnumValue("optype", "dataexchange_send_batch")
 	// OpTypeDataExchangeSendBlob is a private send of a blob
 	OpTypeDataExchangeSendBlob = fftypes.FFEnumValue("optype", "dataexchange_send_blob")
+	// OpTypeDataExchangeSendReceipts is a private send of message receipts, back to the node that sent the messages
+	OpTypeDataExchangeSendReceipts = fftypes.FFEnumValue("optype", "dataexchange_send_receipts")
 	// OpTypeTokenCreatePool is a token pool creation
 	OpTypeTokenCreatePool = fftypes.FFEnumValue("optype", "token_create_pool")
 	// OpTypeTokenActivatePool is a token pool activation
 	OpTypeTokenActivatePool = fftypes.FFEnumValue("optype", "token_activate_pool")
+	// OpTypeTokenMigratePool is a token pool activation on a new token connector, when migrating the pool to that connector
+	OpTypeTokenMigratePool = fftypes.FFEnumValue("optype", "token_migrate_pool")
 	// OpTypeTokenTransfer is a token transfer
 	OpTypeTokenTransfer = fftypes.FFEnumValue("optype", "token_transfer")
+	// OpTypeTokenTransferBatch is a set of token transfers submitted in a single blockchain transaction
+	OpTypeTokenTransferBatch = fftypes.FFEnumValue("optype", "token_transfer_batch")
+	// OpTypeTokenBulkMint tracks the progress of a bulk mint, whose individual mints are token_transfer operations in the same transaction
+	OpTypeTokenBulkMint = fftypes.FFEnumValue("optype", "token_bulk_mint")
 	// OpTypeTokenApproval is a token approval
 	OpTypeTokenApproval = fftypes.FFEnumValue("optype", "token_approval")
+	// OpTypeTokenSwapLock locks the tokens for one leg of a swap in escrow
+	OpTypeTokenSwapLock = fftypes.FFEnumValue("optype", "token_swap_lock")
+	// OpTypeTokenSwapInvoke is the contract invoke for one leg of a swap
+	OpTypeTokenSwapInvoke = fftypes.FFEnumValue("optype", "token_swap_invoke")
+	// OpTypeTokenSwapClaim releases the escrowed tokens for one leg of a swap to the recipient
+	OpTypeTokenSwapClaim = fftypes.FFEnumValue("optype", "token_swap_claim")
+	// OpTypeTokenSwapRefund returns the escrowed tokens for one leg of a swap to the owner
+	OpTypeTokenSwapRefund = fftypes.FFEnumValue("optype", "token_swap_refund")
 )
 
 func (op *Operation) IsBlockchainOperation() bool {
 	return op.Type == OpTypeBlockchainInvoke ||
 		op.Type == OpTypeBlockchainNetworkAction ||
 		op.Type == OpTypeBlockchainPinBatch ||
-		op.Type == OpTypeBlockchainContractDeploy
+		op.Type == OpTypeBlockchainContractDeploy ||
+		op.Type == OpTypeTokenSwapInvoke
 }
 
 func (op *Operation) IsTokenOperation() bool {
-	return op.Type == OpTypeTokenActivatePool || op.Type == OpTypeTokenApproval || op.Type == OpTypeTokenCreatePool || op.Type == OpTypeTokenTransfer
+	return op.Type == OpTypeTokenActivatePool || op.Type == OpTypeTokenApproval || op.Type == OpTypeTokenCreatePool || op.Type == OpTypeTokenTransfer || op.Type == OpTypeTokenTransferBatch ||
+		op.Type == OpTypeTokenSwapLock || op.Type == OpTypeTokenSwapClaim || op.Type == OpTypeTokenSwapRefund
 }
 
 // OpStatus is the current status of an operation
@@ -89,6 +107,28 @@ const (
 	OpStatusFailed OpStatus = "Failed"
 )
 
+// OpFailureReason classifies the cause of a failed operation, where the plugin was able to determine it
+type OpFailureReason = fftypes.FFEnum
+
+var (
+	// OpFailureReasonComplianceRejected indicates the operation was rejected by the compliance rules of a permissioned token
+	OpFailureReasonComplianceRejected = fftypes.FFEnumValue("opfailurereason", "compliance_rejected")
+)
+
+// OperationFailure can be returned by a plugin when submitting an operation, to record why it failed
+type OperationFailure struct {
+	Reason OpFailureReason
+	Err    error
+}
+
+func (of *OperationFailure) Error() string {
+	return of.Err.Error()
+}
+
+func (of *OperationFailure) Unwrap() error {
+	return of.Err
+}
+
 type Named interface {
 	Name() string
 }
@@ -111,18 +151,19 @@ func NewOperation(plugin Named, namespace string, tx *fftypes.UUID, opType OpTyp
 
 // Operation is a description of an action performed as part of a transaction submitted by this node
 type Operation struct {
-	ID          *fftypes.UUID      `ffstruct:"Operation" json:"id" ffexcludeinput:"true"`
-	Namespace   string             `ffstruct:"Operation" json:"namespace" ffexcludeinput:"true"`
-	Transaction *fftypes.UUID      `ffstruct:"Operation" json:"tx" ffexcludeinput:"true"`
-	Type        OpType             `ffstruct:"Operation" json:"type" ffenum:"optype" ffexcludeinput:"true"`
-	Status      OpStatus           `ffstruct:"Operation" json:"status"`
-	Plugin      string             `ffstruct:"Operation" json:"plugin" ffexcludeinput:"true"`
-	Input       fftypes.JSONObject `ffstruct:"Operation" json:"input,omitempty" ffexcludeinput:"true"`
-	Output      fftypes.JSONObject `ffstruct:"Operation" json:"output,omitempty"`
-	Error       string             `ffstruct:"Operation" json:"error,omitempty"`
-	Created     *fftypes.FFTime    `ffstruct:"Operation" json:"created,omitempty" ffexcludeinput:"true"`
-	Updated     *fftypes.FFTime    `ffstruct:"Operation" json:"updated,omitempty" ffexcludeinput:"true"`
-	Retry       *fftypes.UUID      `ffstruct:"Operation" json:"retry,omitempty" ffexcludeinput:"true"`
+	ID            *fftypes.UUID      `ffstruct:"Operation" json:"id" ffexcludeinput:"true"`
+	Namespace     string             `ffstruct:"Operation" json:"namespace" ffexcludeinput:"true"`
+	Transaction   *fftypes.UUID      `ffstruct:"Operation" json:"tx" ffexcludeinput:"true"`
+	Type          OpType             `ffstruct:"Operation" json:"type" ffenum:"optype" ffexcludeinput:"true"`
+	Status        OpStatus           `ffstruct:"Operation" json:"status"`
+	Plugin        string             `ffstruct:"Operation" json:"plugin" ffexcludeinput:"true"`
+	Input         fftypes.JSONObject `ffstruct:"Operation" json:"input,omitempty" ffexcludeinput:"true"`
+	Output        fftypes.JSONObject `ffstruct:"Operation" json:"output,omitempty"`
+	Error         string             `ffstruct:"Operation" json:"error,omitempty"`
+	Created       *fftypes.FFTime    `ffstruct:"Operation" json:"created,omitempty" ffexcludeinput:"true"`
+	Updated       *fftypes.FFTime    `ffstruct:"Operation" json:"updated,omitempty" ffexcludeinput:"true"`
+	Retry         *fftypes.UUID      `ffstruct:"Operation" json:"retry,omitempty" ffexcludeinput:"true"`
+	FailureReason OpFailureReason    `ffstruct:"Operation" json:"failureReason,omitempty" ffenum:"opfailurereason" ffexcludeinput:"true"`
 }
 
 // OperationUpdateDTO is the subset of fields on an operation that are mutable, via the SPI
@@ -177,6 +218,7 @@ type OperationUpdate struct {
 	Status         OpStatus
 	BlockchainTXID string
 	ErrorMessage   string
+	FailureReason  OpFailureReason
 	Output         fftypes.JSONObject
 	VerifyManifest bool
 	DXManifest     string
@@ -79,6 +79,10 @@ func TestOperationTypes(t *testing.T) {
 	assert.True(t, op.IsBlockchainOperation())
 	assert.False(t, op.IsTokenOperation())
 
+	op.Type = OpTypeTokenSwapInvoke
+	assert.True(t, op.IsBlockchainOperation())
+	assert.False(t, op.IsTokenOperation())
+
 	// Token operation types
 	op.Type = OpTypeTokenActivatePool
 	assert.True(t, op.IsTokenOperation())
@@ -95,6 +99,22 @@ func TestOperationTypes(t *testing.T) {
 	op.Type = OpTypeTokenTransfer
 	assert.True(t, op.IsTokenOperation())
 	assert.False(t, op.IsBlockchainOperation())
+
+	op.Type = OpTypeTokenTransferBatch
+	assert.True(t, op.IsTokenOperation())
+	assert.False(t, op.IsBlockchainOperation())
+
+	op.Type = OpTypeTokenSwapLock
+	assert.True(t, op.IsTokenOperation())
+	assert.False(t, op.IsBlockchainOperation())
+
+	op.Type = OpTypeTokenSwapClaim
+	assert.True(t, op.IsTokenOperation())
+	assert.False(t, op.IsBlockchainOperation())
+
+	op.Type = OpTypeTokenSwapRefund
+	assert.True(t, op.IsTokenOperation())
+	assert.False(t, op.IsBlockchainOperation())
 }
 
 func TestParseNamespacedOpID(t *testing.T) {
@@ -0,0 +1,92 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+// QuarantineReason is why a quarantined batch was held when it was received
+type QuarantineReason = fftypes.FFEnum
+
+var (
+	// QuarantineReasonRate is a batch received while its sender was over the configured message rate
+	QuarantineReasonRate = fftypes.FFEnumValue("quarantinereason", "rate")
+	// QuarantineReasonQuota is a batch that would have taken its sender over the configured daily size quota
+	QuarantineReasonQuota = fftypes.FFEnumValue("quarantinereason", "quota")
+	// QuarantineReasonDecryption is a batch with encrypted data that the node could not decrypt
+	QuarantineReasonDecryption = fftypes.FFEnumValue("quarantinereason", "decryption")
+)
+
+// QuarantineState is the current state of a quarantined batch in the release queue of the namespace
+type QuarantineState = fftypes.FFEnum
+
+var (
+	// QuarantineStatePending is a quarantined batch that is waiting for an administrator to release or discard it
+	QuarantineStatePending = fftypes.FFEnumValue("quarantinestate", "pending")
+	// QuarantineStateReleased is a quarantined batch that has been processed in the same way as when it was received
+	QuarantineStateReleased = fftypes.FFEnumValue("quarantinestate", "released")
+	// QuarantineStateDiscarded is a quarantined batch that an administrator has chosen not to process
+	QuarantineStateDiscarded = fftypes.FFEnumValue("quarantinestate", "discarded")
+)
+
+// QuarantinedBatch is a private batch received from another org that exceeded the inbound limits of the node, or that
+// could not be decrypted, and is held until an administrator releases or discards it
+type QuarantinedBatch struct {
+	ID        *fftypes.UUID     `ffstruct:"QuarantinedBatch" json:"id"`
+	Namespace string            `ffstruct:"QuarantinedBatch" json:"namespace"`
+	Org       string            `ffstruct:"QuarantinedBatch" json:"org"`
+	Node      *fftypes.UUID     `ffstruct:"QuarantinedBatch" json:"node,omitempty"`
+	Peer      string            `ffstruct:"QuarantinedBatch" json:"peer"`
+	Batch     *fftypes.UUID     `ffstruct:"QuarantinedBatch" json:"batch,omitempty"`
+	Reason    QuarantineReason  `ffstruct:"QuarantinedBatch" json:"reason" ffenum:"quarantinereason"`
+	Messages  int               `ffstruct:"QuarantinedBatch" json:"messages"`
+	Size      int64             `ffstruct:"QuarantinedBatch" json:"size"`
+	State     QuarantineState   `ffstruct:"QuarantinedBatch" json:"state" ffenum:"quarantinestate"`
+	Transport *TransportWrapper `json:"-"`
+	Created   *fftypes.FFTime   `ffstruct:"QuarantinedBatch" json:"created"`
+	Updated   *fftypes.FFTime   `ffstruct:"QuarantinedBatch" json:"updated,omitempty"`
+}
+
+// QuarantinedBlob is a blob received from another node that the blob scanner of the namespace flagged, and is held
+// until an administrator releases or discards it. The data of the blob cannot be confirmed while it is held.
+type QuarantinedBlob struct {
+	ID         *fftypes.UUID    `ffstruct:"QuarantinedBlob" json:"id"`
+	Namespace  string           `ffstruct:"QuarantinedBlob" json:"namespace"`
+	Data       *fftypes.UUID    `ffstruct:"QuarantinedBlob" json:"data,omitempty"`
+	Hash       *fftypes.Bytes32 `ffstruct:"QuarantinedBlob" json:"hash"`
+	Size       int64            `ffstruct:"QuarantinedBlob" json:"size"`
+	Peer       string           `ffstruct:"QuarantinedBlob" json:"peer,omitempty"`
+	PayloadRef string           `ffstruct:"QuarantinedBlob" json:"payloadRef"`
+	Finding    string           `ffstruct:"QuarantinedBlob" json:"finding"`
+	State      QuarantineState  `ffstruct:"QuarantinedBlob" json:"state" ffenum:"quarantinestate"`
+	Created    *fftypes.FFTime  `ffstruct:"QuarantinedBlob" json:"created"`
+	Updated    *fftypes.FFTime  `ffstruct:"QuarantinedBlob" json:"updated,omitempty"`
+}
+
+// Blob returns the blob to insert when a quarantined blob is released
+func (qb *QuarantinedBlob) Blob() *Blob {
+	return &Blob{
+		Namespace:  qb.Namespace,
+		Hash:       qb.Hash,
+		Size:       qb.Size,
+		Peer:       qb.Peer,
+		PayloadRef: qb.PayloadRef,
+		DataID:     qb.Data,
+		Created:    fftypes.Now(),
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+// ReplyMode is how the replies to a request are collected
+type ReplyMode = fftypes.FFEnum
+
+var (
+	// ReplyModeFirst returns the first valid reply to the request
+	ReplyModeFirst = fftypes.FFEnumValue("replymode", "first")
+	// ReplyModeQuorum waits for valid replies from a quorum of different authors
+	ReplyModeQuorum = fftypes.FFEnumValue("replymode", "quorum")
+)
+
+// ReplyOptions are the options of a request message on the expected replies
+type ReplyOptions struct {
+	Datatype *DatatypeRef        `ffstruct:"ReplyOptions" json:"datatype,omitempty"`
+	Mode     ReplyMode           `ffstruct:"ReplyOptions" json:"mode,omitempty" ffenum:"replymode"`
+	Quorum   int                 `ffstruct:"ReplyOptions" json:"quorum,omitempty"`
+	Timeout  *fftypes.FFDuration `ffstruct:"ReplyOptions" json:"timeout,omitempty"`
+}
+
+// ReplyMessage is one of the replies collected for a request, with its data in-line
+type ReplyMessage struct {
+	Message
+	InlineData InlineData `ffstruct:"ReplyMessage" json:"data,omitempty"`
+}
+
+// Required is the number of valid replies, from different authors, needed to complete the request
+func (ro *ReplyOptions) Required() int {
+	if ro == nil || ro.Mode != ReplyModeQuorum || ro.Quorum < 1 {
+		return 1
+	}
+	return ro.Quorum
+}
+
+// MatchesDatatype checks that the data of a reply is all of the expected datatype
+func (ro *ReplyOptions) MatchesDatatype(data DataArray) bool {
+	if ro == nil || ro.Datatype == nil {
+		return true
+	}
+	if len(data) == 0 {
+		return false
+	}
+	for _, d := range data {
+		if d.Datatype == nil || d.Validator == ValidatorTypeNone ||
+			d.Datatype.Name != ro.Datatype.Name || d.Datatype.Version != ro.Datatype.Version {
+			return false
+		}
+	}
+	return true
+}
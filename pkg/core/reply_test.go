@@ -0,0 +1,53 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplyOptionsRequired(t *testing.T) {
+	var ro *ReplyOptions
+	assert.Equal(t, 1, ro.Required())
+	assert.Equal(t, 1, (&ReplyOptions{Quorum: 3}).Required())
+	assert.Equal(t, 1, (&ReplyOptions{Mode: ReplyModeQuorum}).Required())
+	assert.Equal(t, 3, (&ReplyOptions{Mode: ReplyModeQuorum, Quorum: 3}).Required())
+}
+
+func TestReplyOptionsMatchesDatatype(t *testing.T) {
+	var ro *ReplyOptions
+	assert.True(t, ro.MatchesDatatype(nil))
+
+	ro = &ReplyOptions{Datatype: &DatatypeRef{Name: "quote", Version: "1.0"}}
+	assert.False(t, ro.MatchesDatatype(DataArray{}))
+	assert.True(t, ro.MatchesDatatype(DataArray{
+		{ID: fftypes.NewUUID(), Validator: ValidatorTypeJSON, Datatype: &DatatypeRef{Name: "quote", Version: "1.0"}},
+	}))
+	assert.False(t, ro.MatchesDatatype(DataArray{
+		{ID: fftypes.NewUUID(), Validator: ValidatorTypeJSON, Datatype: &DatatypeRef{Name: "quote", Version: "2.0"}},
+	}))
+	assert.False(t, ro.MatchesDatatype(DataArray{
+		{ID: fftypes.NewUUID(), Validator: ValidatorTypeNone, Datatype: &DatatypeRef{Name: "quote", Version: "1.0"}},
+	}))
+	assert.False(t, ro.MatchesDatatype(DataArray{
+		{ID: fftypes.NewUUID(), Validator: ValidatorTypeJSON, Datatype: &DatatypeRef{Name: "quote", Version: "1.0"}},
+		{ID: fftypes.NewUUID(), Value: fftypes.JSONAnyPtr(`"untyped"`)},
+	}))
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+// Role is a set of permissions on the API of a namespace, that can be bound to a principal
+type Role = fftypes.FFEnum
+
+var (
+	// RoleReader can query all the resources of the namespace
+	RoleReader = fftypes.FFEnumValue("role", "reader")
+	// RoleSender can query, and send messages, upload data and invoke contracts
+	RoleSender = fftypes.FFEnumValue("role", "sender")
+	// RoleTokenAdmin can query, and create and manage token pools, transfers and approvals
+	RoleTokenAdmin = fftypes.FFEnumValue("role", "token-admin")
+	// RoleNamespaceAdmin can use all the routes of the namespace, including definitions and subscriptions
+	RoleNamespaceAdmin = fftypes.FFEnumValue("role", "ns-admin")
+)
+
+// RoleGrants checks whether a role includes the permissions of another. Every role can read,
+// and the namespace admin role can do everything.
+func RoleGrants(role, required Role) bool {
+	return role == required || role == RoleNamespaceAdmin || required == RoleReader
+}
+
+// RoleBinding binds a role in a namespace to an authenticated principal
+type RoleBinding struct {
+	ID        *fftypes.UUID   `ffstruct:"RoleBinding" json:"id" ffexcludeinput:"true"`
+	Namespace string          `ffstruct:"RoleBinding" json:"namespace" ffexcludeinput:"true"`
+	Principal string          `ffstruct:"RoleBinding" json:"principal"`
+	Role      Role            `ffstruct:"RoleBinding" json:"role" ffenum:"role"`
+	Created   *fftypes.FFTime `ffstruct:"RoleBinding" json:"created" ffexcludeinput:"true"`
+}
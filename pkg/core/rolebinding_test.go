@@ -0,0 +1,38 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleGrants(t *testing.T) {
+	assert.True(t, RoleGrants(RoleReader, RoleReader))
+	assert.True(t, RoleGrants(RoleSender, RoleReader))
+	assert.True(t, RoleGrants(RoleTokenAdmin, RoleReader))
+	assert.True(t, RoleGrants(RoleSender, RoleSender))
+	assert.True(t, RoleGrants(RoleTokenAdmin, RoleTokenAdmin))
+	assert.True(t, RoleGrants(RoleNamespaceAdmin, RoleSender))
+	assert.True(t, RoleGrants(RoleNamespaceAdmin, RoleTokenAdmin))
+	assert.True(t, RoleGrants(RoleNamespaceAdmin, RoleNamespaceAdmin))
+	assert.False(t, RoleGrants(RoleReader, RoleSender))
+	assert.False(t, RoleGrants(RoleSender, RoleTokenAdmin))
+	assert.False(t, RoleGrants(RoleTokenAdmin, RoleSender))
+	assert.False(t, RoleGrants(RoleTokenAdmin, RoleNamespaceAdmin))
+}
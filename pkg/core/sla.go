@@ -0,0 +1,71 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
+
+// SLAPolicy is the time within which the messages sent by this node are expected to be confirmed, configured for a namespace
+type SLAPolicy struct {
+	ConfirmTime time.Duration
+	Topics      map[string]time.Duration
+}
+
+// ForTopics returns the strictest SLA that applies to a message with the given topics, and the topic it applies to.
+// A topic with its own SLA uses it in place of the default, and an SLA of zero means the topic is not tracked.
+func (p *SLAPolicy) ForTopics(topics fftypes.FFStringArray) (topic string, sla time.Duration) {
+	if p == nil {
+		return "", 0
+	}
+	for _, t := range topics {
+		topicSLA, ok := p.Topics[t]
+		if !ok {
+			topicSLA = p.ConfirmTime
+		}
+		if topicSLA > 0 && (sla == 0 || topicSLA < sla) {
+			topic, sla = t, topicSLA
+		}
+	}
+	return topic, sla
+}
+
+// SLABreachType is the stage of delivery at which a message exceeded its SLA
+type SLABreachType = fftypes.FFEnum
+
+var (
+	// SLABreachTypeConfirmed is a message sent by this node that was confirmed by this node after its SLA
+	SLABreachTypeConfirmed = fftypes.FFEnumValue("slabreachtype", "confirmed")
+	// SLABreachTypeDelivered is a private message sent by this node that was confirmed by the node of another member after its SLA
+	SLABreachTypeDelivered = fftypes.FFEnumValue("slabreachtype", "delivered")
+)
+
+// SLABreach records a message sent by this node that took longer than its SLA to be confirmed by this node, or by the node of another org
+type SLABreach struct {
+	ID        *fftypes.UUID      `ffstruct:"SLABreach" json:"id"`
+	Namespace string             `ffstruct:"SLABreach" json:"namespace"`
+	Type      SLABreachType      `ffstruct:"SLABreach" json:"type" ffenum:"slabreachtype"`
+	Message   *fftypes.UUID      `ffstruct:"SLABreach" json:"message"`
+	Topic     string             `ffstruct:"SLABreach" json:"topic"`
+	Org       string             `ffstruct:"SLABreach" json:"org,omitempty"`
+	Node      *fftypes.UUID      `ffstruct:"SLABreach" json:"node,omitempty"`
+	SLA       fftypes.FFDuration `ffstruct:"SLABreach" json:"sla"`
+	Latency   fftypes.FFDuration `ffstruct:"SLABreach" json:"latency"`
+	Created   *fftypes.FFTime    `ffstruct:"SLABreach" json:"created"`
+}
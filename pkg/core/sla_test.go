@@ -0,0 +1,57 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSLAPolicyForTopics(t *testing.T) {
+	var p *SLAPolicy
+	topic, sla := p.ForTopics(fftypes.FFStringArray{"topic1"})
+	assert.Equal(t, "", topic)
+	assert.Zero(t, sla)
+
+	p = &SLAPolicy{
+		ConfirmTime: 10 * time.Second,
+		Topics: map[string]time.Duration{
+			"fast":    1 * time.Second,
+			"untimed": 0,
+			"relaxed": 1 * time.Minute,
+		},
+	}
+	topic, sla = p.ForTopics(fftypes.FFStringArray{"other"})
+	assert.Equal(t, "other", topic)
+	assert.Equal(t, 10*time.Second, sla)
+
+	topic, sla = p.ForTopics(fftypes.FFStringArray{"relaxed", "fast", "other"})
+	assert.Equal(t, "fast", topic)
+	assert.Equal(t, 1*time.Second, sla)
+
+	topic, sla = p.ForTopics(fftypes.FFStringArray{"untimed"})
+	assert.Equal(t, "", topic)
+	assert.Zero(t, sla)
+
+	p.ConfirmTime = 0
+	topic, sla = p.ForTopics(fftypes.FFStringArray{"other", "relaxed"})
+	assert.Equal(t, "relaxed", topic)
+	assert.Equal(t, 1*time.Minute, sla)
+}
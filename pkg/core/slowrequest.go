@@ -0,0 +1,34 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// SlowRequest is one of the slowest recent requests to the API or SPI
+type SlowRequest struct {
+	Server    string              `ffstruct:"SlowRequest" json:"server"`
+	Namespace string              `ffstruct:"SlowRequest" json:"namespace,omitempty"`
+	Principal string              `ffstruct:"SlowRequest" json:"principal,omitempty"`
+	Method    string              `ffstruct:"SlowRequest" json:"method"`
+	Route     string              `ffstruct:"SlowRequest" json:"route"`
+	Path      string              `ffstruct:"SlowRequest" json:"path"`
+	Query     string              `ffstruct:"SlowRequest" json:"query,omitempty"`
+	Status    int                 `ffstruct:"SlowRequest" json:"status"`
+	TraceID   string              `ffstruct:"SlowRequest" json:"traceId"`
+	Started   *fftypes.FFTime     `ffstruct:"SlowRequest" json:"started"`
+	Duration  *fftypes.FFDuration `ffstruct:"SlowRequest" json:"duration"`
+}
@@ -32,7 +32,9 @@ type SubscriptionFilter struct {
 	Message          MessageFilter         `ffstruct:"SubscriptionFilter" json:"message,omitempty"`
 	Transaction      TransactionFilter     `ffstruct:"SubscriptionFilter" json:"transaction,omitempty"`
 	BlockchainEvent  BlockchainEventFilter `ffstruct:"SubscriptionFilter" json:"blockchainevent,omitempty"`
+	TokenTransfer    *TokenTransferFilter  `ffstruct:"SubscriptionFilter" json:"tokentransfer,omitempty"`
 	Topic            string                `ffstruct:"SubscriptionFilter" json:"topic,omitempty"`
+	Expression       string                `ffstruct:"SubscriptionFilter" json:"expression,omitempty"`
 	DeprecatedTopics string                `ffstruct:"SubscriptionFilter" json:"topics,omitempty"`
 	DeprecatedTag    string                `ffstruct:"SubscriptionFilter" json:"tag,omitempty"`
 	DeprecatedGroup  string                `ffstruct:"SubscriptionFilter" json:"group,omitempty"`
@@ -55,6 +57,7 @@ func NewSubscriptionFilterFromQuery(query url.Values) SubscriptionFilter {
 			Type: query.Get("filter.transaction.type"),
 		},
 		Topic:            query.Get("filter.topic"),
+		Expression:       query.Get("filter.expression"),
 		DeprecatedTag:    query.Get("filter.tag"),
 		DeprecatedTopics: query.Get("filter.topics"),
 		DeprecatedGroup:  query.Get("filter.group"),
@@ -77,6 +80,15 @@ type BlockchainEventFilter struct {
 	Listener string `ffstruct:"SubscriptionBlockchainEventFilter" json:"listener,omitempty"`
 }
 
+type TokenTransferFilter struct {
+	Pool       string            `ffstruct:"SubscriptionTokenTransferFilter" json:"pool,omitempty"`
+	TokenIndex string            `ffstruct:"SubscriptionTokenTransferFilter" json:"tokenIndex,omitempty"`
+	From       string            `ffstruct:"SubscriptionTokenTransferFilter" json:"from,omitempty"`
+	To         string            `ffstruct:"SubscriptionTokenTransferFilter" json:"to,omitempty"`
+	MinAmount  *fftypes.FFBigInt `ffstruct:"SubscriptionTokenTransferFilter" json:"minAmount,omitempty"`
+	MaxAmount  *fftypes.FFBigInt `ffstruct:"SubscriptionTokenTransferFilter" json:"maxAmount,omitempty"`
+}
+
 // SubOptsFirstEvent picks the first event that should be dispatched on the subscription, and can be a string containing an exact sequence as well as one of the enum values
 type SubOptsFirstEvent string
 
@@ -87,11 +99,79 @@ const (
 	SubOptsFirstEventNewest SubOptsFirstEvent = "newest"
 )
 
+// SubOptsFormat is the envelope events are delivered to the application in
+type SubOptsFormat string
+
+const (
+	// SubOptsFormatNative delivers the FireFly event JSON directly
+	SubOptsFormatNative SubOptsFormat = "native"
+	// SubOptsFormatCloudEvents wraps each event in a CloudEvents 1.0 envelope
+	SubOptsFormatCloudEvents SubOptsFormat = "cloudevents"
+)
+
+// SubOptsGroupDelivery is how events are shared between the connections of a consumer group
+type SubOptsGroupDelivery string
+
+const (
+	// SubOptsGroupDeliveryRoundRobin delivers each event to the next connection in the group in turn
+	SubOptsGroupDeliveryRoundRobin SubOptsGroupDelivery = "roundrobin"
+	// SubOptsGroupDeliveryPartition delivers all events with the same partition key to the same connection
+	SubOptsGroupDeliveryPartition SubOptsGroupDelivery = "partition"
+)
+
+// SubOptsPartitionKey is the field of an event that decides which connection of a consumer group it is delivered to
+type SubOptsPartitionKey string
+
+const (
+	// SubOptsPartitionKeyTopic partitions events on their topic
+	SubOptsPartitionKeyTopic SubOptsPartitionKey = "topic"
+	// SubOptsPartitionKeyGroup partitions events on the privacy group of their message
+	SubOptsPartitionKeyGroup SubOptsPartitionKey = "group"
+	// SubOptsPartitionKeyAuthor partitions events on the author of their message
+	SubOptsPartitionKeyAuthor SubOptsPartitionKey = "author"
+)
+
+// SubscriptionConsumerGroup shares the events of a durable subscription between all the connections that start it,
+// rather than delivering them to one connection at a time
+type SubscriptionConsumerGroup struct {
+	Delivery     SubOptsGroupDelivery `ffstruct:"SubscriptionConsumerGroup" json:"delivery,omitempty"`
+	PartitionKey SubOptsPartitionKey  `ffstruct:"SubscriptionConsumerGroup" json:"partitionKey,omitempty"`
+}
+
+// SubOptsDeliveryMode controls how many events are delivered to the transport of a subscription at once
+type SubOptsDeliveryMode string
+
+const (
+	// SubOptsDeliveryModeOrdered delivers one event at a time, waiting for it to be acknowledged before delivering the next
+	SubOptsDeliveryModeOrdered SubOptsDeliveryMode = "ordered"
+	// SubOptsDeliveryModeParallel invokes the transport concurrently for each in flight event, so one slow delivery does not hold up the others
+	SubOptsDeliveryModeParallel SubOptsDeliveryMode = "parallel"
+)
+
+// SubscriptionDeliveryOptions throttle the delivery of events on a subscription
+type SubscriptionDeliveryOptions struct {
+	Mode        SubOptsDeliveryMode `ffstruct:"SubscriptionDeliveryOptions" json:"mode,omitempty"`
+	MaxInflight *uint16             `ffstruct:"SubscriptionDeliveryOptions" json:"maxInflight,omitempty"`
+	RateLimit   float64             `ffstruct:"SubscriptionDeliveryOptions" json:"rateLimit,omitempty"`
+}
+
+// SubscriptionBatchOptions deliver events to the transport in batches, rather than one at a time
+type SubscriptionBatchOptions struct {
+	Size    uint16              `ffstruct:"SubscriptionBatchOptions" json:"size"`
+	Timeout *fftypes.FFDuration `ffstruct:"SubscriptionBatchOptions" json:"timeout,omitempty"`
+}
+
 // SubscriptionCoreOptions are the core options that apply across all transports
 type SubscriptionCoreOptions struct {
-	FirstEvent *SubOptsFirstEvent `ffstruct:"SubscriptionCoreOptions" json:"firstEvent,omitempty"`
-	ReadAhead  *uint16            `ffstruct:"SubscriptionCoreOptions" json:"readAhead,omitempty"`
-	WithData   *bool              `ffstruct:"SubscriptionCoreOptions" json:"withData,omitempty"`
+	FirstEvent    *SubOptsFirstEvent           `ffstruct:"SubscriptionCoreOptions" json:"firstEvent,omitempty"`
+	ReadAhead     *uint16                      `ffstruct:"SubscriptionCoreOptions" json:"readAhead,omitempty"`
+	WithData      *bool                        `ffstruct:"SubscriptionCoreOptions" json:"withData,omitempty"`
+	Format        SubOptsFormat                `ffstruct:"SubscriptionCoreOptions" json:"format,omitempty"`
+	ConsumerGroup *SubscriptionConsumerGroup   `ffstruct:"SubscriptionCoreOptions" json:"consumerGroup,omitempty"`
+	Delivery      *SubscriptionDeliveryOptions `ffstruct:"SubscriptionCoreOptions" json:"delivery,omitempty"`
+	DedupWindow   *fftypes.FFDuration          `ffstruct:"SubscriptionCoreOptions" json:"dedupWindow,omitempty"`
+	Batch         *SubscriptionBatchOptions    `ffstruct:"SubscriptionCoreOptions" json:"batch,omitempty"`
+	WithReceipt   *bool                        `ffstruct:"SubscriptionCoreOptions" json:"withReceipt,omitempty"`
 }
 
 // SubscriptionOptions customize the behavior of subscriptions
@@ -122,6 +202,36 @@ type Subscription struct {
 	Updated   *fftypes.FFTime     `ffstruct:"Subscription" json:"updated" ffexcludeinput:"true"`
 }
 
+// SubscriptionRewind is a request to move the offset of a durable subscription back to a sequence or timestamp,
+// so that the events after that point are redelivered
+type SubscriptionRewind struct {
+	Sequence       *int64          `ffstruct:"SubscriptionRewind" json:"sequence,omitempty"`
+	Timestamp      *fftypes.FFTime `ffstruct:"SubscriptionRewind" json:"timestamp,omitempty"`
+	DryRun         bool            `ffstruct:"SubscriptionRewind" json:"dryRun,omitempty"`
+	PreviousOffset int64           `ffstruct:"SubscriptionRewind" json:"previousOffset" ffexcludeinput:"true"`
+	Offset         int64           `ffstruct:"SubscriptionRewind" json:"offset" ffexcludeinput:"true"`
+	Events         int64           `ffstruct:"SubscriptionRewind" json:"events" ffexcludeinput:"true"`
+}
+
+// SubscriptionPull is a request for the next events of a subscription that uses the pull transport,
+// waiting for events to arrive if none are available yet
+type SubscriptionPull struct {
+	BatchSize int                 `ffstruct:"SubscriptionPull" json:"batchSize,omitempty"`
+	Wait      *fftypes.FFDuration `ffstruct:"SubscriptionPull" json:"wait,omitempty"`
+}
+
+// SubscriptionAck acknowledges events pulled from a subscription, or rejects them so they are redelivered
+type SubscriptionAck struct {
+	Events []*fftypes.UUID `ffstruct:"SubscriptionAck" json:"events"`
+	Reject bool            `ffstruct:"SubscriptionAck" json:"reject,omitempty"`
+}
+
+// PulledEvent is an event pulled from a subscription, with the data of its message if the subscription includes data
+type PulledEvent struct {
+	EventDelivery
+	Data DataArray `ffstruct:"PulledEvent" json:"data,omitempty"`
+}
+
 type SubscriptionWithStatus struct {
 	Subscription
 	Status SubscriptionStatus `ffstruct:"SubscriptionWithStatus" json:"status,omitempty" ffexcludeinput:"true"`
@@ -146,6 +256,12 @@ func (so *SubscriptionOptions) UnmarshalJSON(b []byte) error {
 	delete(so.additionalOptions, "firstEvent")
 	delete(so.additionalOptions, "readAhead")
 	delete(so.additionalOptions, "withData")
+	delete(so.additionalOptions, "format")
+	delete(so.additionalOptions, "consumerGroup")
+	delete(so.additionalOptions, "delivery")
+	delete(so.additionalOptions, "dedupWindow")
+	delete(so.additionalOptions, "batch")
+	delete(so.additionalOptions, "withReceipt")
 	return nil
 }
 
@@ -162,6 +278,24 @@ func (so SubscriptionOptions) MarshalJSON() ([]byte, error) {
 	if so.ReadAhead != nil {
 		so.additionalOptions["readAhead"] = float64(*so.ReadAhead)
 	}
+	if so.Format != "" {
+		so.additionalOptions["format"] = so.Format
+	}
+	if so.ConsumerGroup != nil {
+		so.additionalOptions["consumerGroup"] = so.ConsumerGroup
+	}
+	if so.Delivery != nil {
+		so.additionalOptions["delivery"] = so.Delivery
+	}
+	if so.DedupWindow != nil {
+		so.additionalOptions["dedupWindow"] = so.DedupWindow
+	}
+	if so.Batch != nil {
+		so.additionalOptions["batch"] = so.Batch
+	}
+	if so.WithReceipt != nil {
+		so.additionalOptions["withReceipt"] = so.WithReceipt
+	}
 	if so.TLSConfigName != "" {
 		so.additionalOptions["tlsConfigName"] = so.TLSConfigName
 	}
@@ -20,7 +20,9 @@ import (
 	"encoding/json"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,12 +30,26 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 	firstEvent := SubOptsFirstEventNewest
 	readAhead := uint16(50)
 	yes := true
+	dedupWindow := fftypes.FFDuration(10 * time.Second)
 	sub1 := &Subscription{
 		Options: SubscriptionOptions{
 			SubscriptionCoreOptions: SubscriptionCoreOptions{
 				FirstEvent: &firstEvent,
 				ReadAhead:  &readAhead,
 				WithData:   &yes,
+				Format:     SubOptsFormatCloudEvents,
+				ConsumerGroup: &SubscriptionConsumerGroup{
+					Delivery: SubOptsGroupDeliveryRoundRobin,
+				},
+				Delivery: &SubscriptionDeliveryOptions{
+					Mode:      SubOptsDeliveryModeOrdered,
+					RateLimit: 2.5,
+				},
+				DedupWindow: &dedupWindow,
+				Batch: &SubscriptionBatchOptions{
+					Size: 10,
+				},
+				WithReceipt: &yes,
 			},
 			WebhookSubOptions: WebhookSubOptions{
 				TLSConfigName: "myconfig",
@@ -49,7 +65,7 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 	// Verify it serializes as bytes to the database
 	b1, err := sub1.Options.Value()
 	assert.NoError(t, err)
-	assert.Equal(t, `{"firstEvent":"newest","my-nested-opts":{"myopt1":12345,"myopt2":"test"},"readAhead":50,"tlsConfigName":"myconfig","withData":true}`, string(b1.([]byte)))
+	assert.Equal(t, `{"batch":{"size":10},"consumerGroup":{"delivery":"roundrobin"},"dedupWindow":"10s","delivery":{"mode":"ordered","rateLimit":2.5},"firstEvent":"newest","format":"cloudevents","my-nested-opts":{"myopt1":12345,"myopt2":"test"},"readAhead":50,"tlsConfigName":"myconfig","withData":true,"withReceipt":true}`, string(b1.([]byte)))
 
 	f1, err := sub1.Filter.Value()
 	assert.NoError(t, err)
@@ -64,6 +80,13 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, SubOptsFirstEventNewest, *sub2.Options.FirstEvent)
 	assert.Equal(t, uint16(50), *sub2.Options.ReadAhead)
+	assert.Equal(t, SubOptsFormatCloudEvents, sub2.Options.Format)
+	assert.Equal(t, SubOptsGroupDeliveryRoundRobin, sub2.Options.ConsumerGroup.Delivery)
+	assert.Equal(t, SubOptsDeliveryModeOrdered, sub2.Options.Delivery.Mode)
+	assert.Equal(t, 2.5, sub2.Options.Delivery.RateLimit)
+	assert.Equal(t, 10*time.Second, time.Duration(*sub2.Options.DedupWindow))
+	assert.Equal(t, uint16(10), sub2.Options.Batch.Size)
+	assert.True(t, *sub2.Options.WithReceipt)
 	assert.Equal(t, "myconfig", sub2.Options.TLSConfigName)
 	assert.Equal(t, string(b1.([]byte)), string(b2.([]byte)))
 
@@ -71,6 +94,12 @@ func TestSubscriptionOptionsDatabaseSerialization(t *testing.T) {
 	assert.Nil(t, sub2.Options.TransportOptions()["withData"])
 	assert.Nil(t, sub2.Options.TransportOptions()["firstEvent"])
 	assert.Nil(t, sub2.Options.TransportOptions()["readAhead"])
+	assert.Nil(t, sub2.Options.TransportOptions()["format"])
+	assert.Nil(t, sub2.Options.TransportOptions()["consumerGroup"])
+	assert.Nil(t, sub2.Options.TransportOptions()["delivery"])
+	assert.Nil(t, sub2.Options.TransportOptions()["dedupWindow"])
+	assert.Nil(t, sub2.Options.TransportOptions()["batch"])
+	assert.Nil(t, sub2.Options.TransportOptions()["withReceipt"])
 
 	// Confirm we get back the transport options
 	assert.Equal(t, float64(12345), sub2.Options.TransportOptions().GetObject("my-nested-opts")["myopt1"])
@@ -0,0 +1,53 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// SubscriptionTemplateTrigger is the kind of resource whose creation instantiates a subscription template
+type SubscriptionTemplateTrigger = fftypes.FFEnum
+
+var (
+	// SubscriptionTemplateTriggerContractAPI instantiates the template when a contract API is confirmed
+	SubscriptionTemplateTriggerContractAPI = fftypes.FFEnumValue("subtemplatetrigger", "contract_api")
+	// SubscriptionTemplateTriggerTokenPool instantiates the template when a token pool is confirmed
+	SubscriptionTemplateTriggerTokenPool = fftypes.FFEnumValue("subtemplatetrigger", "token_pool")
+	// SubscriptionTemplateTriggerTopic instantiates the template when the first message is confirmed on a topic
+	SubscriptionTemplateTriggerTopic = fftypes.FFEnumValue("subtemplatetrigger", "topic")
+)
+
+// SubscriptionTemplate is a namespace-level definition of a durable subscription, that is instantiated automatically
+// each time a matching contract API, token pool or topic is created.
+// The filter and options can refer to the resource with Go template actions: {{.Name}}, {{.ID}} and {{.Trigger}}
+type SubscriptionTemplate struct {
+	ID        *fftypes.UUID               `ffstruct:"SubscriptionTemplate" json:"id" ffexcludeinput:"true"`
+	Namespace string                      `ffstruct:"SubscriptionTemplate" json:"namespace" ffexcludeinput:"true"`
+	Name      string                      `ffstruct:"SubscriptionTemplate" json:"name"`
+	Trigger   SubscriptionTemplateTrigger `ffstruct:"SubscriptionTemplate" json:"trigger" ffenum:"subtemplatetrigger"`
+	Match     string                      `ffstruct:"SubscriptionTemplate" json:"match,omitempty"`
+	Transport string                      `ffstruct:"SubscriptionTemplate" json:"transport"`
+	Filter    SubscriptionFilter          `ffstruct:"SubscriptionTemplate" json:"filter"`
+	Options   SubscriptionOptions         `ffstruct:"SubscriptionTemplate" json:"options"`
+	Created   *fftypes.FFTime             `ffstruct:"SubscriptionTemplate" json:"created" ffexcludeinput:"true"`
+}
+
+// SubscriptionTemplateInput is the resource a subscription template is instantiated for
+type SubscriptionTemplateInput struct {
+	Name    string
+	ID      *fftypes.UUID
+	Trigger SubscriptionTemplateTrigger
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+type TokenActivityType = fftypes.FFEnum
+
+var (
+	TokenActivityTypeMint     = fftypes.FFEnumValue("tokenactivitytype", "mint")
+	TokenActivityTypeBurn     = fftypes.FFEnumValue("tokenactivitytype", "burn")
+	TokenActivityTypeTransfer = fftypes.FFEnumValue("tokenactivitytype", "transfer")
+	TokenActivityTypeApproval = fftypes.FFEnumValue("tokenactivitytype", "approval")
+)
+
+// TokenActivity is a single entry in the combined feed of token transfers and approvals for an account.
+// It is a read-only view across the transfer and approval collections, and is not stored.
+type TokenActivity struct {
+	Type            TokenActivityType `ffstruct:"TokenActivity" json:"type" ffenum:"tokenactivitytype"`
+	LocalID         *fftypes.UUID     `ffstruct:"TokenActivity" json:"localId,omitempty"`
+	Pool            *fftypes.UUID     `ffstruct:"TokenActivity" json:"pool,omitempty"`
+	TokenIndex      string            `ffstruct:"TokenActivity" json:"tokenIndex,omitempty"`
+	Connector       string            `ffstruct:"TokenActivity" json:"connector,omitempty"`
+	Namespace       string            `ffstruct:"TokenActivity" json:"namespace,omitempty"`
+	Key             string            `ffstruct:"TokenActivity" json:"key,omitempty"`
+	From            string            `ffstruct:"TokenActivity" json:"from,omitempty"`
+	To              string            `ffstruct:"TokenActivity" json:"to,omitempty"`
+	Operator        string            `ffstruct:"TokenActivity" json:"operator,omitempty"`
+	Amount          *fftypes.FFBigInt `ffstruct:"TokenActivity" json:"amount,omitempty"`
+	Approved        *bool             `ffstruct:"TokenActivity" json:"approved,omitempty"`
+	ProtocolID      string            `ffstruct:"TokenActivity" json:"protocolId,omitempty"`
+	Message         *fftypes.UUID     `ffstruct:"TokenActivity" json:"message,omitempty"`
+	TX              TransactionRef    `ffstruct:"TokenActivity" json:"tx"`
+	BlockchainEvent *fftypes.UUID     `ffstruct:"TokenActivity" json:"blockchainEvent,omitempty"`
+	Created         *fftypes.FFTime   `ffstruct:"TokenActivity" json:"created,omitempty"`
+}
@@ -0,0 +1,34 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenAssociatedAccount is a token account that holds the balance of an owner within a pool, for token standards
+// where balances are not held directly against the owner's address - such as the associated token accounts that hold
+// the balances of a Solana SPL mint. Balances and transfers are always recorded against the owner. The token account
+// is recorded when the connector reports that it was created as a side effect of a transfer or mint.
+type TokenAssociatedAccount struct {
+	Pool            *fftypes.UUID   `ffstruct:"TokenAssociatedAccount" json:"pool"`
+	Namespace       string          `ffstruct:"TokenAssociatedAccount" json:"namespace"`
+	Owner           string          `ffstruct:"TokenAssociatedAccount" json:"owner"`
+	Account         string          `ffstruct:"TokenAssociatedAccount" json:"account"`
+	Payer           string          `ffstruct:"TokenAssociatedAccount" json:"payer,omitempty"`
+	TX              TransactionRef  `ffstruct:"TokenAssociatedAccount" json:"tx"`
+	BlockchainEvent *fftypes.UUID   `ffstruct:"TokenAssociatedAccount" json:"blockchainEvent,omitempty"`
+	Created         *fftypes.FFTime `ffstruct:"TokenAssociatedAccount" json:"created"`
+}
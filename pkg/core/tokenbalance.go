@@ -27,6 +27,7 @@ type TokenBalance struct {
 	Key        string           `ffstruct:"TokenBalance" json:"key,omitempty"`
 	Balance    fftypes.FFBigInt `ffstruct:"TokenBalance" json:"balance"`
 	Updated    *fftypes.FFTime  `ffstruct:"TokenBalance" json:"updated,omitempty"`
+	Metadata   *TokenMetadata   `ffstruct:"TokenBalance" json:"metadata,omitempty"` // for REST calls only (not stored)
 }
 
 func TokenBalanceIdentifier(pool *fftypes.UUID, tokenIndex, identity string) string {
@@ -45,3 +46,19 @@ type TokenAccount struct {
 type TokenAccountPool struct {
 	Pool *fftypes.UUID `ffstruct:"TokenBalance" json:"pool,omitempty"`
 }
+
+// TokenPoolHolder rolls up the balances of a key across every token index in a non-fungible pool
+type TokenPoolHolder struct {
+	Key     string          `ffstruct:"TokenPoolHolder" json:"key"`
+	Tokens  int64           `ffstruct:"TokenPoolHolder" json:"tokens"`
+	Updated *fftypes.FFTime `ffstruct:"TokenPoolHolder" json:"updated,omitempty"`
+}
+
+// TokenIndexOwnership rolls up the balances of every key that holds a token index in a non-fungible pool
+type TokenIndexOwnership struct {
+	TokenIndex string          `ffstruct:"TokenIndexOwnership" json:"tokenIndex"`
+	URI        string          `ffstruct:"TokenIndexOwnership" json:"uri,omitempty"`
+	Owners     int64           `ffstruct:"TokenIndexOwnership" json:"owners"`
+	Key        string          `ffstruct:"TokenIndexOwnership" json:"key,omitempty"`
+	Updated    *fftypes.FFTime `ffstruct:"TokenIndexOwnership" json:"updated,omitempty"`
+}
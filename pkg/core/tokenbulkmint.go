@@ -0,0 +1,102 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// TokenBulkMintState is the current state of a bulk mint
+type TokenBulkMintState = fftypes.FFEnum
+
+var (
+	// TokenBulkMintStatePending is a bulk mint where batches of mints are still being submitted to the connector
+	TokenBulkMintStatePending = fftypes.FFEnumValue("tokenbulkmintstate", "pending")
+	// TokenBulkMintStateSubmitted is a bulk mint where every mint has been submitted, and the connector is still processing some of them
+	TokenBulkMintStateSubmitted = fftypes.FFEnumValue("tokenbulkmintstate", "submitted")
+	// TokenBulkMintStateCompleted is a bulk mint where every mint succeeded
+	TokenBulkMintStateCompleted = fftypes.FFEnumValue("tokenbulkmintstate", "completed")
+	// TokenBulkMintStateFailed is a bulk mint where the connector has processed every mint, and at least one failed
+	TokenBulkMintStateFailed = fftypes.FFEnumValue("tokenbulkmintstate", "failed")
+)
+
+// TokenBulkMintItem is a single token to be minted as part of a bulk mint
+type TokenBulkMintItem struct {
+	TokenIndex string `ffstruct:"TokenBulkMintItem" json:"tokenIndex,omitempty"`
+	URI        string `ffstruct:"TokenBulkMintItem" json:"uri,omitempty"`
+}
+
+// TokenBulkMintItems is the list of tokens in a bulk mint, stored as JSON
+type TokenBulkMintItems []*TokenBulkMintItem
+
+type TokenBulkMintInput struct {
+	Pool           string               `ffstruct:"TokenBulkMintInput" json:"pool,omitempty"`
+	Key            string               `ffstruct:"TokenBulkMintInput" json:"key,omitempty"`
+	To             string               `ffstruct:"TokenBulkMintInput" json:"to,omitempty"`
+	Items          []*TokenBulkMintItem `ffstruct:"TokenBulkMintInput" json:"items"`
+	BatchSize      int                  `ffstruct:"TokenBulkMintInput" json:"batchSize,omitempty"`
+	Config         fftypes.JSONObject   `ffstruct:"TokenBulkMintInput" json:"config,omitempty"`
+	IdempotencyKey IdempotencyKey       `ffstruct:"TokenBulkMintInput" json:"idempotencyKey,omitempty"`
+}
+
+// TokenBulkMint is a mint of a large number of non-fungible tokens, such as an NFT drop. The tokens are submitted to
+// the connector in batches by a background loop, as individual mint operations in a single transaction. The progress
+// of the whole mint is tracked by a parent operation in the same transaction.
+type TokenBulkMint struct {
+	ID        *fftypes.UUID      `ffstruct:"TokenBulkMint" json:"id"`
+	Namespace string             `ffstruct:"TokenBulkMint" json:"namespace"`
+	Pool      *fftypes.UUID      `ffstruct:"TokenBulkMint" json:"pool"`
+	Connector string             `ffstruct:"TokenBulkMint" json:"connector"`
+	State     TokenBulkMintState `ffstruct:"TokenBulkMint" json:"state" ffenum:"tokenbulkmintstate"`
+	Key       string             `ffstruct:"TokenBulkMint" json:"key"`
+	To        string             `ffstruct:"TokenBulkMint" json:"to"`
+	Items     TokenBulkMintItems `ffstruct:"TokenBulkMint" json:"items,omitempty"`
+	BatchSize int                `ffstruct:"TokenBulkMint" json:"batchSize"`
+	Total     int                `ffstruct:"TokenBulkMint" json:"total"`
+	Submitted int                `ffstruct:"TokenBulkMint" json:"submitted"`
+	Succeeded int                `ffstruct:"TokenBulkMint" json:"succeeded"`
+	Failed    int                `ffstruct:"TokenBulkMint" json:"failed"`
+	Config    fftypes.JSONObject `ffstruct:"TokenBulkMint" json:"config,omitempty"`
+	TX        TransactionRef     `ffstruct:"TokenBulkMint" json:"tx"`
+	Operation *fftypes.UUID      `ffstruct:"TokenBulkMint" json:"operation"`
+	Created   *fftypes.FFTime    `ffstruct:"TokenBulkMint" json:"created"`
+	Updated   *fftypes.FFTime    `ffstruct:"TokenBulkMint" json:"updated"`
+}
+
+// Scan implements sql.Scanner
+func (ti *TokenBulkMintItems) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), ti)
+	case []byte:
+		return json.Unmarshal(src, ti)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, ti)
+	}
+}
+
+// Value implements sql.Valuer
+func (ti TokenBulkMintItems) Value() (driver.Value, error) {
+	return json.Marshal(ti)
+}
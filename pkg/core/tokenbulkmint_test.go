@@ -0,0 +1,57 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBulkMintItemsScanValue(t *testing.T) {
+	items := TokenBulkMintItems{
+		{TokenIndex: "1", URI: "ipfs://drop/1"},
+		{URI: "ipfs://drop/2"},
+	}
+	val, err := items.Value()
+	assert.NoError(t, err)
+
+	var restored TokenBulkMintItems
+	err = restored.Scan(val)
+	assert.NoError(t, err)
+	assert.Len(t, restored, 2)
+	assert.Equal(t, "1", restored[0].TokenIndex)
+	assert.Equal(t, "ipfs://drop/2", restored[1].URI)
+
+	restored = nil
+	err = restored.Scan(string(val.([]byte)))
+	assert.NoError(t, err)
+	assert.Len(t, restored, 2)
+}
+
+func TestTokenBulkMintItemsScanNil(t *testing.T) {
+	var items TokenBulkMintItems
+	err := items.Scan(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, items)
+}
+
+func TestTokenBulkMintItemsScanError(t *testing.T) {
+	var items TokenBulkMintItems
+	err := items.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
@@ -16,6 +16,30 @@
 
 package core
 
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenConnectorBreakerState is the state of the circuit breaker in front of a token connector
+type TokenConnectorBreakerState = fftypes.FFEnum
+
+var (
+	// TokenConnectorBreakerStateClosed is a connector that requests are passed through to as normal
+	TokenConnectorBreakerStateClosed = fftypes.FFEnumValue("tokenconnectorbreakerstate", "closed")
+	// TokenConnectorBreakerStateOpen is a connector that has failed repeatedly, so new requests are failed fast until the next probe
+	TokenConnectorBreakerStateOpen = fftypes.FFEnumValue("tokenconnectorbreakerstate", "open")
+	// TokenConnectorBreakerStateHalfOpen is a connector where a single request has been let through, to probe whether it has recovered
+	TokenConnectorBreakerStateHalfOpen = fftypes.FFEnumValue("tokenconnectorbreakerstate", "half_open")
+)
+
 type TokenConnector struct {
-	Name string `ffstruct:"TokenConnector" json:"name,omitempty"`
+	Name    string                 `ffstruct:"TokenConnector" json:"name,omitempty"`
+	Breaker *TokenConnectorBreaker `ffstruct:"TokenConnector" json:"breaker,omitempty"`
+}
+
+// TokenConnectorBreaker is the state of the circuit breaker in front of the REST calls to a token connector
+type TokenConnectorBreaker struct {
+	State               TokenConnectorBreakerState `ffstruct:"TokenConnectorBreaker" json:"state" ffenum:"tokenconnectorbreakerstate"`
+	ConsecutiveFailures int                        `ffstruct:"TokenConnectorBreaker" json:"consecutiveFailures"`
+	LastError           string                     `ffstruct:"TokenConnectorBreaker" json:"lastError,omitempty"`
+	LastFailure         *fftypes.FFTime            `ffstruct:"TokenConnectorBreaker" json:"lastFailure,omitempty"`
+	NextProbe           *fftypes.FFTime            `ffstruct:"TokenConnectorBreaker" json:"nextProbe,omitempty"`
 }
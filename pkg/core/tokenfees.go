@@ -0,0 +1,49 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenFees are the royalty and transfer fee reported by the connector for a token pool, or a single token within a pool
+type TokenFees struct {
+	Royalty     *TokenRoyalty     `ffstruct:"TokenFees" json:"royalty,omitempty"`
+	TransferFee *TokenTransferFee `ffstruct:"TokenFees" json:"transferFee,omitempty"`
+}
+
+// TokenRoyalty is the creator royalty on sales of a token, such as reported by an EIP-2981 contract
+type TokenRoyalty struct {
+	Receiver    string `ffstruct:"TokenRoyalty" json:"receiver"`
+	BasisPoints int64  `ffstruct:"TokenRoyalty" json:"basisPoints"`
+}
+
+// TokenTransferFee is a fee that the token contract deducts from every transfer
+type TokenTransferFee struct {
+	BasisPoints int64             `ffstruct:"TokenTransferFee" json:"basisPoints"`
+	Maximum     *fftypes.FFBigInt `ffstruct:"TokenTransferFee" json:"maximum,omitempty"`
+	Amount      *fftypes.FFBigInt `ffstruct:"TokenTransferFee" json:"amount,omitempty"`
+}
+
+// FeeOn calculates the transfer fee that would be deducted from a transfer of the given amount
+func (tf *TokenTransferFee) FeeOn(amount *fftypes.FFBigInt) *fftypes.FFBigInt {
+	fee := new(fftypes.FFBigInt)
+	fee.Int().Mul(amount.Int(), fftypes.NewFFBigInt(tf.BasisPoints).Int())
+	fee.Int().Div(fee.Int(), fftypes.NewFFBigInt(10000).Int())
+	if tf.Maximum != nil && fee.Int().Cmp(tf.Maximum.Int()) > 0 {
+		fee.Int().Set(tf.Maximum.Int())
+	}
+	return fee
+}
@@ -0,0 +1,34 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferFeeOn(t *testing.T) {
+	fee := &TokenTransferFee{BasisPoints: 250}
+	assert.Equal(t, int64(25), fee.FeeOn(fftypes.NewFFBigInt(1000)).Int64())
+	assert.Equal(t, int64(0), fee.FeeOn(fftypes.NewFFBigInt(10)).Int64())
+
+	fee.Maximum = fftypes.NewFFBigInt(20)
+	assert.Equal(t, int64(20), fee.FeeOn(fftypes.NewFFBigInt(1000)).Int64())
+	assert.Equal(t, int64(2), fee.FeeOn(fftypes.NewFFBigInt(100)).Int64())
+}
@@ -0,0 +1,50 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenLedgerEntry is a single debit or credit to an account in a token pool, recorded for each side of a confirmed token transfer.
+// Tokens leaving the account are a debit, and tokens arriving are a credit.
+type TokenLedgerEntry struct {
+	ID              *fftypes.UUID     `ffstruct:"TokenLedgerEntry" json:"id"`
+	Sequence        int64             `ffstruct:"TokenLedgerEntry" json:"sequence"`
+	Namespace       string            `ffstruct:"TokenLedgerEntry" json:"namespace"`
+	Pool            *fftypes.UUID     `ffstruct:"TokenLedgerEntry" json:"pool"`
+	Account         string            `ffstruct:"TokenLedgerEntry" json:"account"`
+	Counterparty    string            `ffstruct:"TokenLedgerEntry" json:"counterparty,omitempty"`
+	TokenIndex      string            `ffstruct:"TokenLedgerEntry" json:"tokenIndex,omitempty"`
+	TransferType    TokenTransferType `ffstruct:"TokenLedgerEntry" json:"transferType" ffenum:"tokentransfertype"`
+	Transfer        *fftypes.UUID     `ffstruct:"TokenLedgerEntry" json:"transfer"`
+	Debit           fftypes.FFBigInt  `ffstruct:"TokenLedgerEntry" json:"debit"`
+	Credit          fftypes.FFBigInt  `ffstruct:"TokenLedgerEntry" json:"credit"`
+	Balance         fftypes.FFBigInt  `ffstruct:"TokenLedgerEntry" json:"balance"`
+	Reversal        bool              `ffstruct:"TokenLedgerEntry" json:"reversal,omitempty"`
+	TX              TransactionRef    `ffstruct:"TokenLedgerEntry" json:"tx"`
+	BlockchainEvent *fftypes.UUID     `ffstruct:"TokenLedgerEntry" json:"blockchainEvent,omitempty"`
+	Created         *fftypes.FFTime   `ffstruct:"TokenLedgerEntry" json:"created,omitempty"`
+}
+
+// TokenLedgerExport selects the token ledger entries to include in an export, and the format to write them in
+type TokenLedgerExport struct {
+	Format    TokenExportFormat `ffstruct:"TokenLedgerExport" json:"format" ffenum:"tokenexportformat"`
+	Pool      string            `ffstruct:"TokenLedgerExport" json:"pool,omitempty"`
+	Account   string            `ffstruct:"TokenLedgerExport" json:"account,omitempty"`
+	Columns   []string          `ffstruct:"TokenLedgerExport" json:"columns,omitempty"`
+	StartTime *fftypes.FFTime   `ffstruct:"TokenLedgerExport" json:"startTime,omitempty"`
+	EndTime   *fftypes.FFTime   `ffstruct:"TokenLedgerExport" json:"endTime,omitempty"`
+}
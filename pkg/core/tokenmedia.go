@@ -0,0 +1,33 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenMedia is a cached copy of the image referenced by the metadata of a non-fungible token, which
+// is served by the FireFly API so that applications do not need to fetch it from third-party gateways.
+// The content is held either in the database, or in the shared storage of the namespace.
+type TokenMedia struct {
+	URI         string           `ffstruct:"TokenMedia" json:"uri"`
+	Namespace   string           `ffstruct:"TokenMedia" json:"namespace"`
+	ContentType string           `ffstruct:"TokenMedia" json:"contentType"`
+	Size        int64            `ffstruct:"TokenMedia" json:"size"`
+	Hash        *fftypes.Bytes32 `ffstruct:"TokenMedia" json:"hash"`
+	PayloadRef  string           `ffstruct:"TokenMedia" json:"payloadRef,omitempty"`
+	Content     []byte           `json:"-"`
+	Created     *fftypes.FFTime  `ffstruct:"TokenMedia" json:"created"`
+}
@@ -0,0 +1,32 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenMetadata is the cached result of dereferencing the URI of a non-fungible token,
+// following the ERC-721 / ERC-1155 metadata JSON schema
+type TokenMetadata struct {
+	URI         string           `ffstruct:"TokenMetadata" json:"uri"`
+	Namespace   string           `ffstruct:"TokenMetadata" json:"namespace"`
+	Name        string           `ffstruct:"TokenMetadata" json:"name,omitempty"`
+	Description string           `ffstruct:"TokenMetadata" json:"description,omitempty"`
+	Image       string           `ffstruct:"TokenMetadata" json:"image,omitempty"`
+	Content     *fftypes.JSONAny `ffstruct:"TokenMetadata" json:"content,omitempty"`
+	Error       string           `ffstruct:"TokenMetadata" json:"error,omitempty"`
+	Updated     *fftypes.FFTime  `ffstruct:"TokenMetadata" json:"updated,omitempty"`
+}
@@ -0,0 +1,33 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenTransferPolicy is a set of local rules that are checked before this node submits a transfer, mint or burn
+// in a token pool. Policies are not broadcast, so each node enforces its own.
+type TokenTransferPolicy struct {
+	Pool       *fftypes.UUID         `ffstruct:"TokenTransferPolicy" json:"pool,omitempty" ffexcludeinput:"true"`
+	Namespace  string                `ffstruct:"TokenTransferPolicy" json:"namespace,omitempty" ffexcludeinput:"true"`
+	MaxAmount  *fftypes.FFBigInt     `ffstruct:"TokenTransferPolicy" json:"maxAmount,omitempty"`
+	DailyLimit *fftypes.FFBigInt     `ffstruct:"TokenTransferPolicy" json:"dailyLimit,omitempty"`
+	Senders    fftypes.FFStringArray `ffstruct:"TokenTransferPolicy" json:"senders,omitempty"`
+	Recipients fftypes.FFStringArray `ffstruct:"TokenTransferPolicy" json:"recipients,omitempty"`
+	Denied     fftypes.FFStringArray `ffstruct:"TokenTransferPolicy" json:"denied,omitempty"`
+	Created    *fftypes.FFTime       `ffstruct:"TokenTransferPolicy" json:"created,omitempty" ffexcludeinput:"true"`
+	Updated    *fftypes.FFTime       `ffstruct:"TokenTransferPolicy" json:"updated,omitempty" ffexcludeinput:"true"`
+}
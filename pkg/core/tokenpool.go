@@ -29,7 +29,7 @@ var (
 	TokenTypeNonFungible = fftypes.FFEnumValue("tokentype", "nonfungible")
 )
 
-// TokenPoolState is the current confirmation state of a token pool
+// TokenPoolState is the current lifecycle state of a token pool
 type TokenPoolState = fftypes.FFEnum
 
 var (
@@ -37,6 +37,12 @@ var (
 	TokenPoolStatePending = fftypes.FFEnumValue("tokenpoolstate", "pending")
 	// TokenPoolStateConfirmed is a token pool that has been confirmed on chain
 	TokenPoolStateConfirmed = fftypes.FFEnumValue("tokenpoolstate", "confirmed")
+	// TokenPoolStatePaused is a confirmed token pool that has been paused on this node, so new transfers and approvals are rejected
+	TokenPoolStatePaused = fftypes.FFEnumValue("tokenpoolstate", "paused")
+	// TokenPoolStateRetired is a token pool that has been permanently retired on this node, and can no longer be used
+	TokenPoolStateRetired = fftypes.FFEnumValue("tokenpoolstate", "retired")
+	// TokenPoolStateMigrating is a token pool that is being migrated to a new token connector on this node, so new transfers and approvals are rejected
+	TokenPoolStateMigrating = fftypes.FFEnumValue("tokenpoolstate", "migrating")
 )
 
 type TokenInterfaceFormat = fftypes.FFEnum
@@ -52,28 +58,30 @@ type TokenPoolInput struct {
 }
 
 type TokenPool struct {
-	ID              *fftypes.UUID         `ffstruct:"TokenPool" json:"id,omitempty" ffexcludeinput:"true"`
-	Type            TokenType             `ffstruct:"TokenPool" json:"type" ffenum:"tokentype"`
-	Namespace       string                `ffstruct:"TokenPool" json:"namespace,omitempty" ffexcludeinput:"true"`
-	Name            string                `ffstruct:"TokenPool" json:"name,omitempty"`
-	NetworkName     string                `ffstruct:"TokenPool" json:"networkName,omitempty"`
-	Standard        string                `ffstruct:"TokenPool" json:"standard,omitempty" ffexcludeinput:"true"`
-	Locator         string                `ffstruct:"TokenPool" json:"locator,omitempty" ffexcludeinput:"true"`
-	Key             string                `ffstruct:"TokenPool" json:"key,omitempty"`
-	Symbol          string                `ffstruct:"TokenPool" json:"symbol,omitempty"`
-	Decimals        int                   `ffstruct:"TokenPool" json:"decimals,omitempty" ffexcludeinput:"true"`
-	Connector       string                `ffstruct:"TokenPool" json:"connector,omitempty"`
-	Message         *fftypes.UUID         `ffstruct:"TokenPool" json:"message,omitempty" ffexcludeinput:"true"`
-	State           TokenPoolState        `ffstruct:"TokenPool" json:"state,omitempty" ffenum:"tokenpoolstate" ffexcludeinput:"true"`
-	Created         *fftypes.FFTime       `ffstruct:"TokenPool" json:"created,omitempty" ffexcludeinput:"true"`
-	Config          fftypes.JSONObject    `ffstruct:"TokenPool" json:"config,omitempty" ffexcludeoutput:"true"` // for REST calls only (not stored)
-	Info            fftypes.JSONObject    `ffstruct:"TokenPool" json:"info,omitempty" ffexcludeinput:"true"`
-	TX              TransactionRef        `ffstruct:"TokenPool" json:"tx,omitempty" ffexcludeinput:"true"`
-	Interface       *fftypes.FFIReference `ffstruct:"TokenPool" json:"interface,omitempty"`
-	InterfaceFormat TokenInterfaceFormat  `ffstruct:"TokenPool" json:"interfaceFormat,omitempty" ffenum:"tokeninterfaceformat" ffexcludeinput:"true"`
-	Methods         *fftypes.JSONAny      `ffstruct:"TokenPool" json:"methods,omitempty" ffexcludeinput:"true"`
-	Published       bool                  `ffstruct:"TokenPool" json:"published" ffexcludeinput:"true"`
-	PluginData      string                `ffstruct:"TokenPool" json:"-" ffexcludeinput:"true"` // reserved for internal plugin use (not returned on API)
+	ID               *fftypes.UUID         `ffstruct:"TokenPool" json:"id,omitempty" ffexcludeinput:"true"`
+	Type             TokenType             `ffstruct:"TokenPool" json:"type" ffenum:"tokentype"`
+	Namespace        string                `ffstruct:"TokenPool" json:"namespace,omitempty" ffexcludeinput:"true"`
+	Name             string                `ffstruct:"TokenPool" json:"name,omitempty"`
+	NetworkName      string                `ffstruct:"TokenPool" json:"networkName,omitempty"`
+	Standard         string                `ffstruct:"TokenPool" json:"standard,omitempty" ffexcludeinput:"true"`
+	Locator          string                `ffstruct:"TokenPool" json:"locator,omitempty" ffexcludeinput:"true"`
+	Key              string                `ffstruct:"TokenPool" json:"key,omitempty"`
+	Symbol           string                `ffstruct:"TokenPool" json:"symbol,omitempty"`
+	Decimals         int                   `ffstruct:"TokenPool" json:"decimals,omitempty" ffexcludeinput:"true"`
+	Connector        string                `ffstruct:"TokenPool" json:"connector,omitempty"`
+	Message          *fftypes.UUID         `ffstruct:"TokenPool" json:"message,omitempty" ffexcludeinput:"true"`
+	State            TokenPoolState        `ffstruct:"TokenPool" json:"state,omitempty" ffenum:"tokenpoolstate" ffexcludeinput:"true"`
+	Created          *fftypes.FFTime       `ffstruct:"TokenPool" json:"created,omitempty" ffexcludeinput:"true"`
+	Config           fftypes.JSONObject    `ffstruct:"TokenPool" json:"config,omitempty" ffexcludeoutput:"true"` // for REST calls only (not stored)
+	Info             fftypes.JSONObject    `ffstruct:"TokenPool" json:"info,omitempty" ffexcludeinput:"true"`
+	TX               TransactionRef        `ffstruct:"TokenPool" json:"tx,omitempty" ffexcludeinput:"true"`
+	Interface        *fftypes.FFIReference `ffstruct:"TokenPool" json:"interface,omitempty"`
+	InterfaceFormat  TokenInterfaceFormat  `ffstruct:"TokenPool" json:"interfaceFormat,omitempty" ffenum:"tokeninterfaceformat" ffexcludeinput:"true"`
+	Methods          *fftypes.JSONAny      `ffstruct:"TokenPool" json:"methods,omitempty" ffexcludeinput:"true"`
+	Published        bool                  `ffstruct:"TokenPool" json:"published" ffexcludeinput:"true"`
+	IdentityRegistry string                `ffstruct:"TokenPool" json:"identityRegistry,omitempty" ffexcludeinput:"true"`
+	Fees             *TokenFees            `ffstruct:"TokenPool" json:"fees,omitempty" ffexcludeinput:"true"` // for REST calls only (not stored)
+	PluginData       string                `ffstruct:"TokenPool" json:"-" ffexcludeinput:"true"`              // reserved for internal plugin use (not returned on API)
 }
 
 type TokenPoolDefinition struct {
@@ -0,0 +1,56 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenPoolMigrationState is the state of the migration of a token pool to a new connector
+type TokenPoolMigrationState = fftypes.FFEnum
+
+var (
+	// TokenPoolMigrationStatePending is a migration that is waiting for the new connector to activate the pool
+	TokenPoolMigrationStatePending = fftypes.FFEnumValue("tokenpoolmigrationstate", "pending")
+	// TokenPoolMigrationStateSucceeded is a migration where the pool has been re-pointed to the new connector
+	TokenPoolMigrationStateSucceeded = fftypes.FFEnumValue("tokenpoolmigrationstate", "succeeded")
+	// TokenPoolMigrationStateFailed is a migration that failed, leaving the pool on its original connector
+	TokenPoolMigrationStateFailed = fftypes.FFEnumValue("tokenpoolmigrationstate", "failed")
+)
+
+type TokenPoolMigrationInput struct {
+	Connector string             `ffstruct:"TokenPoolMigrationInput" json:"connector"`
+	Config    fftypes.JSONObject `ffstruct:"TokenPoolMigrationInput" json:"config,omitempty"`
+}
+
+// TokenPoolMigration records the re-pointing of a confirmed token pool from one token connector to another,
+// such as after upgrading to a new connector implementation for the same token contract. The pool keeps its
+// ID, so all transfer, approval and balance history is retained.
+type TokenPoolMigration struct {
+	ID            *fftypes.UUID           `ffstruct:"TokenPoolMigration" json:"id"`
+	Namespace     string                  `ffstruct:"TokenPoolMigration" json:"namespace"`
+	Pool          *fftypes.UUID           `ffstruct:"TokenPoolMigration" json:"pool"`
+	State         TokenPoolMigrationState `ffstruct:"TokenPoolMigration" json:"state" ffenum:"tokenpoolmigrationstate"`
+	FromConnector string                  `ffstruct:"TokenPoolMigration" json:"fromConnector"`
+	FromLocator   string                  `ffstruct:"TokenPoolMigration" json:"fromLocator"`
+	ToConnector   string                  `ffstruct:"TokenPoolMigration" json:"toConnector"`
+	ToLocator     string                  `ffstruct:"TokenPoolMigration" json:"toLocator,omitempty"`
+	PoolState     TokenPoolState          `ffstruct:"TokenPoolMigration" json:"poolState" ffenum:"tokenpoolstate"`
+	Config        fftypes.JSONObject      `ffstruct:"TokenPoolMigration" json:"config,omitempty"`
+	TX            TransactionRef          `ffstruct:"TokenPoolMigration" json:"tx"`
+	Error         string                  `ffstruct:"TokenPoolMigration" json:"error,omitempty"`
+	Created       *fftypes.FFTime         `ffstruct:"TokenPoolMigration" json:"created"`
+	Updated       *fftypes.FFTime         `ffstruct:"TokenPoolMigration" json:"updated"`
+}
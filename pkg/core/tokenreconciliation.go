@@ -0,0 +1,41 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenBalanceMismatch is a persisted discrepancy between the balance FireFly has computed for an account
+// from the transfers it has indexed, and the balance reported on-chain by the token connector
+type TokenBalanceMismatch struct {
+	ID           *fftypes.UUID    `ffstruct:"TokenBalanceMismatch" json:"id"`
+	Namespace    string           `ffstruct:"TokenBalanceMismatch" json:"namespace"`
+	Pool         *fftypes.UUID    `ffstruct:"TokenBalanceMismatch" json:"pool"`
+	TokenIndex   string           `ffstruct:"TokenBalanceMismatch" json:"tokenIndex,omitempty"`
+	Key          string           `ffstruct:"TokenBalanceMismatch" json:"key"`
+	Balance      fftypes.FFBigInt `ffstruct:"TokenBalanceMismatch" json:"balance"`
+	ChainBalance fftypes.FFBigInt `ffstruct:"TokenBalanceMismatch" json:"chainBalance"`
+	Created      *fftypes.FFTime  `ffstruct:"TokenBalanceMismatch" json:"created"`
+	Updated      *fftypes.FFTime  `ffstruct:"TokenBalanceMismatch" json:"updated"`
+}
+
+// TokenReconciliation is the result of comparing every known account balance in a pool with the chain
+type TokenReconciliation struct {
+	Pool       *fftypes.UUID           `ffstruct:"TokenReconciliation" json:"pool"`
+	Accounts   int64                   `ffstruct:"TokenReconciliation" json:"accounts"`
+	Mismatches []*TokenBalanceMismatch `ffstruct:"TokenReconciliation" json:"mismatches"`
+	Created    *fftypes.FFTime         `ffstruct:"TokenReconciliation" json:"created"`
+}
@@ -0,0 +1,45 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TokenSnapshot is a persisted record of all account balances in a token pool, at a given block height or time
+type TokenSnapshot struct {
+	ID          *fftypes.UUID   `ffstruct:"TokenSnapshot" json:"id"`
+	Namespace   string          `ffstruct:"TokenSnapshot" json:"namespace"`
+	Pool        *fftypes.UUID   `ffstruct:"TokenSnapshot" json:"pool"`
+	BlockNumber *int64          `ffstruct:"TokenSnapshot" json:"blockNumber,omitempty"`
+	Timestamp   *fftypes.FFTime `ffstruct:"TokenSnapshot" json:"timestamp,omitempty"`
+	Transfers   int64           `ffstruct:"TokenSnapshot" json:"transfers"`
+	Accounts    int64           `ffstruct:"TokenSnapshot" json:"accounts"`
+	Created     *fftypes.FFTime `ffstruct:"TokenSnapshot" json:"created"`
+}
+
+type TokenSnapshotInput struct {
+	BlockNumber *int64          `ffstruct:"TokenSnapshotInput" json:"blockNumber,omitempty"`
+	Timestamp   *fftypes.FFTime `ffstruct:"TokenSnapshotInput" json:"timestamp,omitempty"`
+}
+
+// TokenSnapshotBalance is the balance of a single account within a snapshot
+type TokenSnapshotBalance struct {
+	Snapshot   *fftypes.UUID    `ffstruct:"TokenSnapshotBalance" json:"snapshot"`
+	Namespace  string           `ffstruct:"TokenSnapshotBalance" json:"namespace"`
+	TokenIndex string           `ffstruct:"TokenSnapshotBalance" json:"tokenIndex,omitempty"`
+	Key        string           `ffstruct:"TokenSnapshotBalance" json:"key"`
+	Balance    fftypes.FFBigInt `ffstruct:"TokenSnapshotBalance" json:"balance"`
+}
@@ -0,0 +1,118 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// TokenSwapState is the current state of a token swap
+type TokenSwapState = fftypes.FFEnum
+
+var (
+	// TokenSwapStatePending is a swap where the legs are still being locked in escrow (or invoked)
+	TokenSwapStatePending = fftypes.FFEnumValue("tokenswapstate", "pending")
+	// TokenSwapStateClaiming is a swap where every leg has been locked or invoked, and the escrowed tokens are being released to their recipients
+	TokenSwapStateClaiming = fftypes.FFEnumValue("tokenswapstate", "claiming")
+	// TokenSwapStateCompleted is a swap where all escrowed tokens have been released to their recipients
+	TokenSwapStateCompleted = fftypes.FFEnumValue("tokenswapstate", "completed")
+	// TokenSwapStateRefunding is a swap where a leg failed, and any escrowed tokens are being returned to their owners
+	TokenSwapStateRefunding = fftypes.FFEnumValue("tokenswapstate", "refunding")
+	// TokenSwapStateRefunded is a swap where a leg failed, and all escrowed tokens have been returned to their owners
+	TokenSwapStateRefunded = fftypes.FFEnumValue("tokenswapstate", "refunded")
+	// TokenSwapStateFailed is a swap where a claim or refund failed - retrying the failed operation will resume the swap
+	TokenSwapStateFailed = fftypes.FFEnumValue("tokenswapstate", "failed")
+)
+
+// TokenSwapLegType is the type of a single leg of a token swap
+type TokenSwapLegType = fftypes.FFEnum
+
+var (
+	// TokenSwapLegTypeTransfer is a leg that transfers tokens via the escrow contract of the token connector
+	TokenSwapLegTypeTransfer = fftypes.FFEnumValue("tokenswaplegtype", "transfer")
+	// TokenSwapLegTypeInvoke is a leg that invokes a custom smart contract method
+	TokenSwapLegTypeInvoke = fftypes.FFEnumValue("tokenswaplegtype", "invoke")
+)
+
+// TokenSwapLeg is one side of a token swap
+type TokenSwapLeg struct {
+	Type       TokenSwapLegType     `ffstruct:"TokenSwapLeg" json:"type" ffenum:"tokenswaplegtype"`
+	LockID     string               `ffstruct:"TokenSwapLeg" json:"lockId,omitempty" ffexcludeinput:"true"`
+	Pool       *fftypes.UUID        `ffstruct:"TokenSwapLeg" json:"pool,omitempty"`
+	Connector  string               `ffstruct:"TokenSwapLeg" json:"connector,omitempty" ffexcludeinput:"true"`
+	TokenIndex string               `ffstruct:"TokenSwapLeg" json:"tokenIndex,omitempty"`
+	Key        string               `ffstruct:"TokenSwapLeg" json:"key,omitempty"`
+	From       string               `ffstruct:"TokenSwapLeg" json:"from,omitempty"`
+	To         string               `ffstruct:"TokenSwapLeg" json:"to,omitempty"`
+	Amount     fftypes.FFBigInt     `ffstruct:"TokenSwapLeg" json:"amount"`
+	Config     fftypes.JSONObject   `ffstruct:"TokenSwapLeg" json:"config,omitempty"`
+	Invoke     *ContractCallRequest `ffstruct:"TokenSwapLeg" json:"invoke,omitempty"`
+}
+
+// TokenSwapLegs is the list of legs in a token swap, stored as JSON
+type TokenSwapLegs []*TokenSwapLeg
+
+// TokenSwap is an exchange of two legs (two token transfers, or a token transfer and a contract invoke) that either
+// completes in full, or is rolled back. Token transfer legs are locked in an escrow contract via the token connector,
+// under a hash lock whose preimage is known only to this node, until every leg has succeeded.
+type TokenSwap struct {
+	ID        *fftypes.UUID    `ffstruct:"TokenSwap" json:"id" ffexcludeinput:"true"`
+	Namespace string           `ffstruct:"TokenSwap" json:"namespace" ffexcludeinput:"true"`
+	State     TokenSwapState   `ffstruct:"TokenSwap" json:"state" ffenum:"tokenswapstate" ffexcludeinput:"true"`
+	Legs      TokenSwapLegs    `ffstruct:"TokenSwap" json:"legs"`
+	HashLock  *fftypes.Bytes32 `ffstruct:"TokenSwap" json:"hashLock" ffexcludeinput:"true"`
+	Preimage  *fftypes.Bytes32 `json:"-"` // known only to this node, and never returned on the API
+	Timeout   *fftypes.FFTime  `ffstruct:"TokenSwap" json:"timeout" ffexcludeinput:"true"`
+	TX        TransactionRef   `ffstruct:"TokenSwap" json:"tx" ffexcludeinput:"true"`
+	Created   *fftypes.FFTime  `ffstruct:"TokenSwap" json:"created" ffexcludeinput:"true"`
+	Updated   *fftypes.FFTime  `ffstruct:"TokenSwap" json:"updated" ffexcludeinput:"true"`
+}
+
+type TokenSwapLegInput struct {
+	TokenSwapLeg
+	Pool string `ffstruct:"TokenSwapLegInput" json:"pool,omitempty"`
+}
+
+type TokenSwapInput struct {
+	Legs           []*TokenSwapLegInput `ffstruct:"TokenSwapInput" json:"legs"`
+	Timeout        *fftypes.FFDuration  `ffstruct:"TokenSwapInput" json:"timeout,omitempty"`
+	IdempotencyKey IdempotencyKey       `ffstruct:"TokenSwapInput" json:"idempotencyKey,omitempty"`
+}
+
+// Scan implements sql.Scanner
+func (tl *TokenSwapLegs) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), tl)
+	case []byte:
+		return json.Unmarshal(src, tl)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, tl)
+	}
+}
+
+// Value implements sql.Valuer
+func (tl TokenSwapLegs) Value() (driver.Value, error) {
+	return json.Marshal(tl)
+}
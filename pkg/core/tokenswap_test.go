@@ -0,0 +1,58 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenSwapLegsScanValue(t *testing.T) {
+	legs := TokenSwapLegs{
+		{Type: TokenSwapLegTypeTransfer, LockID: "lock1", Amount: *fftypes.NewFFBigInt(5)},
+		{Type: TokenSwapLegTypeInvoke, Invoke: &ContractCallRequest{MethodPath: "pay"}},
+	}
+	val, err := legs.Value()
+	assert.NoError(t, err)
+
+	var restored TokenSwapLegs
+	err = restored.Scan(val)
+	assert.NoError(t, err)
+	assert.Len(t, restored, 2)
+	assert.Equal(t, "lock1", restored[0].LockID)
+	assert.Equal(t, "pay", restored[1].Invoke.MethodPath)
+
+	restored = nil
+	err = restored.Scan(string(val.([]byte)))
+	assert.NoError(t, err)
+	assert.Len(t, restored, 2)
+}
+
+func TestTokenSwapLegsScanNil(t *testing.T) {
+	var legs TokenSwapLegs
+	err := legs.Scan(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, legs)
+}
+
+func TestTokenSwapLegsScanError(t *testing.T) {
+	var legs TokenSwapLegs
+	err := legs.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
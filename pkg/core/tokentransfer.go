@@ -44,7 +44,12 @@ type TokenTransfer struct {
 	Created         *fftypes.FFTime    `ffstruct:"TokenTransfer" json:"created,omitempty" ffexcludeinput:"true"`
 	TX              TransactionRef     `ffstruct:"TokenTransfer" json:"tx" ffexcludeinput:"true"`
 	BlockchainEvent *fftypes.UUID      `ffstruct:"TokenTransfer" json:"blockchainEvent,omitempty" ffexcludeinput:"true"`
-	Config          fftypes.JSONObject `ffstruct:"TokenTransfer" json:"config,omitempty" ffexcludeoutput:"true"` // for REST calls only (not stored)
+	Invalidated     bool               `ffstruct:"TokenTransfer" json:"invalidated,omitempty" ffexcludeinput:"true"`
+	Metadata        *TokenMetadata     `ffstruct:"TokenTransfer" json:"metadata,omitempty" ffexcludeinput:"true"` // for REST calls only (not stored)
+	Fees            *TokenFees         `ffstruct:"TokenTransfer" json:"fees,omitempty" ffexcludeinput:"true"`     // for REST calls only (not stored)
+	Request         *fftypes.UUID      `ffstruct:"TokenTransfer" json:"request,omitempty" ffexcludeinput:"true"`  // for REST calls only (not stored)
+	Config          fftypes.JSONObject `ffstruct:"TokenTransfer" json:"config,omitempty" ffexcludeoutput:"true"`  // for REST calls only (not stored)
+	Sequence        int64              `ffstruct:"TokenTransfer" json:"-"`                                        // Local database sequence used internally for paging exports
 }
 
 type TokenTransferInput struct {
@@ -53,3 +58,50 @@ type TokenTransferInput struct {
 	Pool           string         `ffstruct:"TokenTransferInput" json:"pool,omitempty"`
 	IdempotencyKey IdempotencyKey `ffstruct:"TokenTransferInput" json:"idempotencyKey,omitempty" ffexcludeoutput:"true"`
 }
+
+// TokenTransferEligibility is the result of asking the connector if a transfer would be permitted, without submitting it
+type TokenTransferEligibility struct {
+	Eligible bool               `ffstruct:"TokenTransferEligibility" json:"eligible"`
+	Reason   string             `ffstruct:"TokenTransferEligibility" json:"reason,omitempty"`
+	Info     fftypes.JSONObject `ffstruct:"TokenTransferEligibility" json:"info,omitempty"`
+}
+
+// TokenTransferBatchLeg is a single token index and amount within a batch transfer
+type TokenTransferBatchLeg struct {
+	TokenIndex string           `ffstruct:"TokenTransferBatchLeg" json:"tokenIndex,omitempty"`
+	Amount     fftypes.FFBigInt `ffstruct:"TokenTransferBatchLeg" json:"amount"`
+}
+
+// TokenTransferBatchInput moves several token index/amount pairs between the same two accounts, in a single blockchain transaction
+type TokenTransferBatchInput struct {
+	Pool           string                   `ffstruct:"TokenTransferBatchInput" json:"pool,omitempty"`
+	Key            string                   `ffstruct:"TokenTransferBatchInput" json:"key,omitempty"`
+	From           string                   `ffstruct:"TokenTransferBatchInput" json:"from,omitempty"`
+	To             string                   `ffstruct:"TokenTransferBatchInput" json:"to,omitempty"`
+	Legs           []*TokenTransferBatchLeg `ffstruct:"TokenTransferBatchInput" json:"legs"`
+	Config         fftypes.JSONObject       `ffstruct:"TokenTransferBatchInput" json:"config,omitempty"`
+	IdempotencyKey IdempotencyKey           `ffstruct:"TokenTransferBatchInput" json:"idempotencyKey,omitempty"`
+}
+
+// TokenTransferBatch is a single FireFly transaction, with a transfer record for each leg of the batch
+type TokenTransferBatch struct {
+	TX        TransactionRef   `ffstruct:"TokenTransferBatch" json:"tx"`
+	Transfers []*TokenTransfer `ffstruct:"TokenTransferBatch" json:"transfers"`
+}
+
+type TokenExportFormat = fftypes.FFEnum
+
+var (
+	TokenExportFormatCSV     = fftypes.FFEnumValue("tokenexportformat", "csv")
+	TokenExportFormatParquet = fftypes.FFEnumValue("tokenexportformat", "parquet")
+)
+
+// TokenTransferExport selects the token transfers to include in an export, and the format to write them in
+type TokenTransferExport struct {
+	Format    TokenExportFormat `ffstruct:"TokenTransferExport" json:"format" ffenum:"tokenexportformat"`
+	Pool      string            `ffstruct:"TokenTransferExport" json:"pool,omitempty"`
+	Account   string            `ffstruct:"TokenTransferExport" json:"account,omitempty"`
+	Columns   []string          `ffstruct:"TokenTransferExport" json:"columns,omitempty"`
+	StartTime *fftypes.FFTime   `ffstruct:"TokenTransferExport" json:"startTime,omitempty"`
+	EndTime   *fftypes.FFTime   `ffstruct:"TokenTransferExport" json:"endTime,omitempty"`
+}
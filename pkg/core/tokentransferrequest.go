@@ -0,0 +1,107 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
+
+// TokenTransferRequestState is the current state of a token transfer request that requires sign-off
+type TokenTransferRequestState = fftypes.FFEnum
+
+var (
+	// TokenTransferRequestStatePending is a request that is waiting for sign-off from the configured approvers
+	TokenTransferRequestStatePending = fftypes.FFEnumValue("tokentransferrequeststate", "pending")
+	// TokenTransferRequestStateSubmitted is a request that has been signed off, and submitted to the token connector
+	TokenTransferRequestStateSubmitted = fftypes.FFEnumValue("tokentransferrequeststate", "submitted")
+	// TokenTransferRequestStateFailed is a request that has been signed off, but could not be submitted to the token connector
+	TokenTransferRequestStateFailed = fftypes.FFEnumValue("tokentransferrequeststate", "failed")
+)
+
+// TokenTransferSignoff records the sign-off of a single approver against a token transfer request
+type TokenTransferSignoff struct {
+	Principal string          `ffstruct:"TokenTransferSignoff" json:"principal"`
+	Author    string          `ffstruct:"TokenTransferSignoff" json:"author"`
+	Key       string          `ffstruct:"TokenTransferSignoff" json:"key"`
+	Created   *fftypes.FFTime `ffstruct:"TokenTransferSignoff" json:"created"`
+}
+
+// TokenTransferSignoffs is the list of sign-offs against a token transfer request, stored as JSON
+type TokenTransferSignoffs []*TokenTransferSignoff
+
+// TokenTransferRequest is a token transfer or mint above the configured sign-off threshold, which is held
+// by this node until the required number of approvers have signed it off, and only then submitted to the
+// token connector.
+type TokenTransferRequest struct {
+	ID        *fftypes.UUID             `ffstruct:"TokenTransferRequest" json:"id"`
+	Namespace string                    `ffstruct:"TokenTransferRequest" json:"namespace"`
+	State     TokenTransferRequestState `ffstruct:"TokenTransferRequest" json:"state" ffenum:"tokentransferrequeststate"`
+	Type      TokenTransferType         `ffstruct:"TokenTransferRequest" json:"type" ffenum:"tokentransfertype"`
+	Pool      *fftypes.UUID             `ffstruct:"TokenTransferRequest" json:"pool"`
+	Key       string                    `ffstruct:"TokenTransferRequest" json:"key"`
+	Amount    fftypes.FFBigInt          `ffstruct:"TokenTransferRequest" json:"amount"`
+	Transfer  *TokenTransferInput       `ffstruct:"TokenTransferRequest" json:"transfer"`
+	Required  int                       `ffstruct:"TokenTransferRequest" json:"required"`
+	Signoffs  TokenTransferSignoffs     `ffstruct:"TokenTransferRequest" json:"signoffs"`
+	TX        TransactionRef            `ffstruct:"TokenTransferRequest" json:"tx"`
+	Error     string                    `ffstruct:"TokenTransferRequest" json:"error,omitempty"`
+	Created   *fftypes.FFTime           `ffstruct:"TokenTransferRequest" json:"created"`
+	Updated   *fftypes.FFTime           `ffstruct:"TokenTransferRequest" json:"updated"`
+}
+
+// Scan implements sql.Scanner
+func (ts *TokenTransferSignoffs) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), ts)
+	case []byte:
+		return json.Unmarshal(src, ts)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, ts)
+	}
+}
+
+// Value implements sql.Valuer
+func (ts TokenTransferSignoffs) Value() (driver.Value, error) {
+	return json.Marshal(ts)
+}
+
+// Scan implements sql.Scanner
+func (ti *TokenTransferInput) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), ti)
+	case []byte:
+		return json.Unmarshal(src, ti)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, ti)
+	}
+}
+
+// Value implements sql.Valuer
+func (ti TokenTransferInput) Value() (driver.Value, error) {
+	return json.Marshal(ti)
+}
@@ -0,0 +1,83 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenTransferSignoffsScanValue(t *testing.T) {
+	signoffs := TokenTransferSignoffs{
+		{Author: "did:firefly:org/org1", Key: "0x12345", Created: fftypes.Now()},
+	}
+	val, err := signoffs.Value()
+	assert.NoError(t, err)
+
+	var restored TokenTransferSignoffs
+	err = restored.Scan(val)
+	assert.NoError(t, err)
+	assert.Len(t, restored, 1)
+	assert.Equal(t, "did:firefly:org/org1", restored[0].Author)
+
+	restored = nil
+	err = restored.Scan(string(val.([]byte)))
+	assert.NoError(t, err)
+	assert.Len(t, restored, 1)
+
+	restored = nil
+	err = restored.Scan(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, restored)
+
+	err = restored.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
+
+func TestTokenTransferInputScanValue(t *testing.T) {
+	input := TokenTransferInput{
+		TokenTransfer: TokenTransfer{
+			To:     "0x67890",
+			Amount: *fftypes.NewFFBigInt(1000),
+		},
+		Pool:           "pool1",
+		IdempotencyKey: "idem1",
+	}
+	val, err := input.Value()
+	assert.NoError(t, err)
+
+	var restored TokenTransferInput
+	err = restored.Scan(val)
+	assert.NoError(t, err)
+	assert.Equal(t, "pool1", restored.Pool)
+	assert.Equal(t, "0x67890", restored.To)
+	assert.Equal(t, int64(1000), restored.Amount.Int().Int64())
+	assert.Equal(t, IdempotencyKey("idem1"), restored.IdempotencyKey)
+
+	restored = TokenTransferInput{}
+	err = restored.Scan(string(val.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, "pool1", restored.Pool)
+
+	err = restored.Scan(nil)
+	assert.NoError(t, err)
+
+	err = restored.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
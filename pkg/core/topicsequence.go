@@ -0,0 +1,30 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// TopicSequence is the next topic sequence expected for a context on a strict topic. Messages on a strict topic
+// are only confirmed in the order of the topicSequence in their header, across all the senders on the context.
+type TopicSequence struct {
+	Namespace string           `ffstruct:"TopicSequence" json:"namespace"`
+	Context   *fftypes.Bytes32 `ffstruct:"TopicSequence" json:"context"`
+	Topic     string           `ffstruct:"TopicSequence" json:"topic"`
+	Group     *fftypes.Bytes32 `ffstruct:"TopicSequence" json:"group,omitempty"`
+	Next      int64            `ffstruct:"TopicSequence" json:"next"`
+	Updated   *fftypes.FFTime  `ffstruct:"TopicSequence" json:"updated,omitempty"`
+}
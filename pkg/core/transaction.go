@@ -43,6 +43,8 @@ var (
 	TransactionTypeContractInvokePin = fftypes.FFEnumValue("txtype", "contract_invoke_pin")
 	// TransactionTypeTokenTransfer represents a token approval
 	TransactionTypeTokenApproval = fftypes.FFEnumValue("txtype", "token_approval")
+	// TransactionTypeTokenSwap represents an atomic swap of two legs, via an escrow contract
+	TransactionTypeTokenSwap = fftypes.FFEnumValue("txtype", "token_swap")
 	// TransactionTypeDataPublish represents a publish to shared storage
 	TransactionTypeDataPublish = fftypes.FFEnumValue("txtype", "data_publish")
 )
@@ -97,6 +99,15 @@ type TransactionStatus struct {
 	Details []*TransactionStatusDetails `ffstruct:"TransactionStatus" json:"details"`
 }
 
+// TransactionReceipt is the receipt of the blockchain transaction behind an event, as reported by the blockchain connector
+type TransactionReceipt struct {
+	Status          OpStatus `ffstruct:"TransactionReceipt" json:"status"`
+	BlockNumber     string   `ffstruct:"TransactionReceipt" json:"blockNumber,omitempty"`
+	TransactionHash string   `ffstruct:"TransactionReceipt" json:"transactionHash,omitempty"`
+	GasUsed         string   `ffstruct:"TransactionReceipt" json:"gasUsed,omitempty"`
+	Error           string   `ffstruct:"TransactionReceipt" json:"error,omitempty"`
+}
+
 func (tx *Transaction) Size() int64 {
 	return transactionBaseSizeEstimate // currently a static size assessment for caching
 }
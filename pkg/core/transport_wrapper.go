@@ -16,7 +16,14 @@
 
 package core
 
-import "github.com/hyperledger/firefly-common/pkg/fftypes"
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+)
 
 type TransportPayloadType = fftypes.FFEnum
 
@@ -27,6 +34,40 @@ var (
 
 // TransportWrapper wraps paylaods over data exchange transfers, for easy deserialization at target
 type TransportWrapper struct {
-	Group *Group `json:"group,omitempty"`
-	Batch *Batch `json:"batch,omitempty"`
+	Group     *Group            `json:"group,omitempty"`
+	Batch     *Batch            `json:"batch,omitempty"`
+	Receipts  []*MessageReceipt `json:"receipts,omitempty"`
+	Encrypted *EncryptedPayload `json:"encrypted,omitempty"`
+}
+
+// EncryptedPayload holds the data of a batch encrypted with a random key, with a copy
+// of that key wrapped to the encryption key of each member node of the group
+type EncryptedPayload struct {
+	Keys []*WrappedKey `json:"keys"`
+	Data []byte        `json:"data"`
+}
+
+// WrappedKey is the key of an encrypted payload, sealed to the encryption key of a node
+type WrappedKey struct {
+	Node *fftypes.UUID `json:"node"`
+	Key  []byte        `json:"key"`
+}
+
+// Scan implements sql.Scanner
+func (tw *TransportWrapper) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case string:
+		return json.Unmarshal([]byte(src), tw)
+	case []byte:
+		return json.Unmarshal(src, tw)
+	default:
+		return i18n.NewError(context.Background(), i18n.MsgTypeRestoreFailed, src, tw)
+	}
+}
+
+// Value implements sql.Valuer
+func (tw TransportWrapper) Value() (driver.Value, error) {
+	return json.Marshal(tw)
 }
@@ -53,3 +53,31 @@ func TestBatchManifest(t *testing.T) {
 	assert.Equal(t, tw.Batch.Payload.Data[1].Hash.String(), tm.Data[1].Hash.String())
 
 }
+
+func TestTransportWrapperScanValue(t *testing.T) {
+	tw := TransportWrapper{
+		Batch: &Batch{
+			BatchHeader: BatchHeader{ID: fftypes.NewUUID()},
+		},
+	}
+	val, err := tw.Value()
+	assert.NoError(t, err)
+
+	var restored TransportWrapper
+	err = restored.Scan(val)
+	assert.NoError(t, err)
+	assert.Equal(t, tw.Batch.ID, restored.Batch.ID)
+
+	restored = TransportWrapper{}
+	err = restored.Scan(string(val.([]byte)))
+	assert.NoError(t, err)
+	assert.Equal(t, tw.Batch.ID, restored.Batch.ID)
+
+	restored = TransportWrapper{}
+	err = restored.Scan(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, restored.Batch)
+
+	err = restored.Scan(12345)
+	assert.Regexp(t, "FF00105", err)
+}
@@ -32,6 +32,8 @@ var (
 	VerifierTypeMSPIdentity = fftypes.FFEnumValue("verifiertype", "fabric_msp_id")
 	// VerifierTypeFFDXPeerID is the peer identifier that FireFly Data Exchange verifies (using plugin specific tech) when receiving data
 	VerifierTypeFFDXPeerID = fftypes.FFEnumValue("verifiertype", "dx_peer_id")
+	// VerifierTypeHederaAccountID is a Hedera account ID, in the format shard.realm.num
+	VerifierTypeHederaAccountID = fftypes.FFEnumValue("verifiertype", "hedera_account_id")
 )
 
 // VerifierRef is just the type + value (public key identifier etc.) from the verifier
@@ -16,21 +16,28 @@
 
 package core
 
-import "crypto/tls"
+import (
+	"crypto/tls"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+)
 
 type WebhookSubOptions struct {
-	Fastack       bool                `ffstruct:"WebhookSubOptions" json:"fastack,omitempty"`
-	URL           string              `ffstruct:"WebhookSubOptions" json:"url,omitempty"`
-	Method        string              `ffstruct:"WebhookSubOptions" json:"method,omitempty"`
-	JSON          bool                `ffstruct:"WebhookSubOptions" json:"json,omitempty"`
-	Reply         bool                `ffstruct:"WebhookSubOptions" json:"reply,omitempty"`
-	ReplyTag      string              `ffstruct:"WebhookSubOptions" json:"replytag,omitempty"`
-	ReplyTX       string              `ffstruct:"WebhookSubOptions" json:"replytx,omitempty"`
-	Headers       map[string]string   `ffstruct:"WebhookSubOptions" json:"headers,omitempty"`
-	Query         map[string]string   `ffstruct:"WebhookSubOptions" json:"query,omitempty"`
-	TLSConfigName string              `ffstruct:"WebhookSubOptions" json:"tlsConfigName,omitempty"`
-	TLSConfig     *tls.Config         `ffstruct:"WebhookSubOptions" json:"-" ffexcludeinput:"true"`
-	Input         WebhookInputOptions `ffstruct:"WebhookSubOptions" json:"input,omitempty"`
+	Fastack         bool                    `ffstruct:"WebhookSubOptions" json:"fastack,omitempty"`
+	URL             string                  `ffstruct:"WebhookSubOptions" json:"url,omitempty"`
+	Method          string                  `ffstruct:"WebhookSubOptions" json:"method,omitempty"`
+	JSON            bool                    `ffstruct:"WebhookSubOptions" json:"json,omitempty"`
+	Reply           bool                    `ffstruct:"WebhookSubOptions" json:"reply,omitempty"`
+	ReplyTag        string                  `ffstruct:"WebhookSubOptions" json:"replytag,omitempty"`
+	ReplyTX         string                  `ffstruct:"WebhookSubOptions" json:"replytx,omitempty"`
+	Headers         map[string]string       `ffstruct:"WebhookSubOptions" json:"headers,omitempty"`
+	Query           map[string]string       `ffstruct:"WebhookSubOptions" json:"query,omitempty"`
+	TLSConfigName   string                  `ffstruct:"WebhookSubOptions" json:"tlsConfigName,omitempty"`
+	TLSConfig       *tls.Config             `ffstruct:"WebhookSubOptions" json:"-" ffexcludeinput:"true"`
+	Input           WebhookInputOptions     `ffstruct:"WebhookSubOptions" json:"input,omitempty"`
+	Retry           *WebhookRetryOptions    `ffstruct:"WebhookSubOptions" json:"retry,omitempty"`
+	CloudEventsMode string                  `ffstruct:"WebhookSubOptions" json:"cloudEventsMode,omitempty"`
+	Template        *WebhookTemplateOptions `ffstruct:"WebhookSubOptions" json:"template,omitempty"`
 }
 
 type WebhookInputOptions struct {
@@ -40,3 +47,20 @@ type WebhookInputOptions struct {
 	Path    string `ffstruct:"WebhookInputOptions" json:"path,omitempty"`
 	ReplyTX string `ffstruct:"WebhookInputOptions" json:"replytx,omitempty"`
 }
+
+// WebhookTemplateOptions build the request for each event with Go templates, executed against the JSON of the event,
+// so a webhook can call an existing API directly
+type WebhookTemplateOptions struct {
+	Path    map[string]string `ffstruct:"WebhookTemplateOptions" json:"path,omitempty"`
+	Headers map[string]string `ffstruct:"WebhookTemplateOptions" json:"headers,omitempty"`
+	Body    string            `ffstruct:"WebhookTemplateOptions" json:"body,omitempty"`
+}
+
+// WebhookRetryOptions configure how a failed webhook invocation is retried, before the event is recorded as a dead letter
+type WebhookRetryOptions struct {
+	MaxAttempts  int                 `ffstruct:"WebhookRetryOptions" json:"maxAttempts,omitempty"`
+	InitialDelay *fftypes.FFDuration `ffstruct:"WebhookRetryOptions" json:"initialDelay,omitempty"`
+	MaxDelay     *fftypes.FFDuration `ffstruct:"WebhookRetryOptions" json:"maxDelay,omitempty"`
+	Factor       float64             `ffstruct:"WebhookRetryOptions" json:"factor,omitempty"`
+	StatusCodes  []int               `ffstruct:"WebhookRetryOptions" json:"statusCodes,omitempty"`
+}
@@ -26,6 +26,8 @@ var (
 	WSClientActionStart = fftypes.FFEnumValue("wstype", "start")
 	// WSClientActionAck acknowledges an event that was delivered, allowing further messages to be sent
 	WSClientActionAck = fftypes.FFEnumValue("wstype", "ack")
+	// WSClientActionToken refreshes the bearer token of the connection, before the previous token expires
+	WSClientActionToken = fftypes.FFEnumValue("wstype", "token")
 
 	// WSProtocolErrorEventType is a special event "type" field for server to send the client, if it performs a ProtocolError
 	WSProtocolErrorEventType = fftypes.FFEnumValue("wstype", "protocol_error")
@@ -56,6 +58,13 @@ type WSAck struct {
 	Subscription *SubscriptionRef `ffstruct:"WSAck" json:"subscription,omitempty"`
 }
 
+// WSToken replaces the bearer token used to authenticate the connection
+type WSToken struct {
+	WSActionBase
+
+	Token string `ffstruct:"WSToken" json:"token"`
+}
+
 // WSError is sent to the client by the server in the case of a protocol error
 type WSError struct {
 	Type  WSClientPayloadType `ffstruct:"WSAck" json:"type" ffenum:"wstype"`
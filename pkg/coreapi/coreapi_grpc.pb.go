@@ -0,0 +1,221 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: pkg/coreapi/coreapi.proto
+
+package coreapi
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// FireFlyClient is the client API for FireFly service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type FireFlyClient interface {
+	// SendMessage broadcasts a message, or sends it privately when a group or members are set
+	SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*Message, error)
+	// InvokeContract invokes a method on a smart contract, or on a contract API
+	InvokeContract(ctx context.Context, in *InvokeContractRequest, opts ...grpc.CallOption) (*InvokeContractResponse, error)
+	// TransferTokens transfers tokens within a token pool
+	TransferTokens(ctx context.Context, in *TransferTokensRequest, opts ...grpc.CallOption) (*TokenTransfer, error)
+	// Query returns a page of a collection, with the same filtering as the REST API
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+}
+
+type fireFlyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFireFlyClient(cc grpc.ClientConnInterface) FireFlyClient {
+	return &fireFlyClient{cc}
+}
+
+func (c *fireFlyClient) SendMessage(ctx context.Context, in *SendMessageRequest, opts ...grpc.CallOption) (*Message, error) {
+	out := new(Message)
+	err := c.cc.Invoke(ctx, "/firefly.coreapi.v1.FireFly/SendMessage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fireFlyClient) InvokeContract(ctx context.Context, in *InvokeContractRequest, opts ...grpc.CallOption) (*InvokeContractResponse, error) {
+	out := new(InvokeContractResponse)
+	err := c.cc.Invoke(ctx, "/firefly.coreapi.v1.FireFly/InvokeContract", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fireFlyClient) TransferTokens(ctx context.Context, in *TransferTokensRequest, opts ...grpc.CallOption) (*TokenTransfer, error) {
+	out := new(TokenTransfer)
+	err := c.cc.Invoke(ctx, "/firefly.coreapi.v1.FireFly/TransferTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *fireFlyClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/firefly.coreapi.v1.FireFly/Query", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FireFlyServer is the server API for FireFly service.
+// All implementations must embed UnimplementedFireFlyServer
+// for forward compatibility
+type FireFlyServer interface {
+	// SendMessage broadcasts a message, or sends it privately when a group or members are set
+	SendMessage(context.Context, *SendMessageRequest) (*Message, error)
+	// InvokeContract invokes a method on a smart contract, or on a contract API
+	InvokeContract(context.Context, *InvokeContractRequest) (*InvokeContractResponse, error)
+	// TransferTokens transfers tokens within a token pool
+	TransferTokens(context.Context, *TransferTokensRequest) (*TokenTransfer, error)
+	// Query returns a page of a collection, with the same filtering as the REST API
+	Query(context.Context, *QueryRequest) (*QueryResponse, error)
+	mustEmbedUnimplementedFireFlyServer()
+}
+
+// UnimplementedFireFlyServer must be embedded to have forward compatible implementations.
+type UnimplementedFireFlyServer struct {
+}
+
+func (UnimplementedFireFlyServer) SendMessage(context.Context, *SendMessageRequest) (*Message, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendMessage not implemented")
+}
+func (UnimplementedFireFlyServer) InvokeContract(context.Context, *InvokeContractRequest) (*InvokeContractResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InvokeContract not implemented")
+}
+func (UnimplementedFireFlyServer) TransferTokens(context.Context, *TransferTokensRequest) (*TokenTransfer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferTokens not implemented")
+}
+func (UnimplementedFireFlyServer) Query(context.Context, *QueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedFireFlyServer) mustEmbedUnimplementedFireFlyServer() {}
+
+// UnsafeFireFlyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FireFlyServer will
+// result in compilation errors.
+type UnsafeFireFlyServer interface {
+	mustEmbedUnimplementedFireFlyServer()
+}
+
+func RegisterFireFlyServer(s grpc.ServiceRegistrar, srv FireFlyServer) {
+	s.RegisterService(&FireFly_ServiceDesc, srv)
+}
+
+func _FireFly_SendMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FireFlyServer).SendMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/firefly.coreapi.v1.FireFly/SendMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FireFlyServer).SendMessage(ctx, req.(*SendMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FireFly_InvokeContract_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvokeContractRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FireFlyServer).InvokeContract(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/firefly.coreapi.v1.FireFly/InvokeContract",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FireFlyServer).InvokeContract(ctx, req.(*InvokeContractRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FireFly_TransferTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FireFlyServer).TransferTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/firefly.coreapi.v1.FireFly/TransferTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FireFlyServer).TransferTokens(ctx, req.(*TransferTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FireFly_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FireFlyServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/firefly.coreapi.v1.FireFly/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FireFlyServer).Query(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FireFly_ServiceDesc is the grpc.ServiceDesc for FireFly service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FireFly_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "firefly.coreapi.v1.FireFly",
+	HandlerType: (*FireFlyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SendMessage",
+			Handler:    _FireFly_SendMessage_Handler,
+		},
+		{
+			MethodName: "InvokeContract",
+			Handler:    _FireFly_InvokeContract_Handler,
+		},
+		{
+			MethodName: "TransferTokens",
+			Handler:    _FireFly_TransferTokens_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _FireFly_Query_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/coreapi/coreapi.proto",
+}
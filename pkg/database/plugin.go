@@ -49,6 +49,28 @@ const (
 	GlobalHandler = "ff:global"
 )
 
+type iteratorContextKey struct{}
+
+// RowIterator is called with each resource read by a collection query. Returning an error stops the query,
+// and the error is returned by the query.
+type RowIterator func(row interface{}) error
+
+// WithIterator requests that the collection queries made with the context pass each resource to the
+// iterator as it is read, rather than returning them in the list of results. Collections that do not
+// support iteration ignore it, so the caller must also handle any resources returned in the list.
+func WithIterator(ctx context.Context, it RowIterator) context.Context {
+	if it == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, iteratorContextKey{}, it)
+}
+
+// Iterator returns the iterator requested on the context with WithIterator, or nil
+func Iterator(ctx context.Context) RowIterator {
+	it, _ := ctx.Value(iteratorContextKey{}).(RowIterator)
+	return it
+}
+
 // Plugin is the interface implemented by each plugin
 type Plugin interface {
 	PersistenceInterface // Split out to aid pluggability the next level down (SQL provider etc.)
@@ -97,8 +119,10 @@ type iMessageCollection interface {
 	// GetMessageByID - Get a message by ID
 	GetMessageByID(ctx context.Context, namespace string, id *fftypes.UUID) (message *core.Message, err error)
 
-	// GetMessages - List messages, reverse sorted (newest first) by Confirmed then Created, with pagination, and simple must filters
-	GetMessages(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Message, res *ffapi.FilterResult, err error)
+	// GetMessages - List messages, reverse sorted (newest first) by Confirmed then Created, with pagination, and simple must filters.
+	//               If fields are listed, as JSON paths such as "header.author", only the columns for those fields are read,
+	//               and the other fields of the messages are not populated.
+	GetMessages(ctx context.Context, namespace string, filter ffapi.Filter, fields ...string) (message []*core.Message, res *ffapi.FilterResult, err error)
 
 	// GetMessageIDs - Retrieves messages, but only querying the messages ID (no other fields)
 	GetMessageIDs(ctx context.Context, namespace string, filter ffapi.Filter) (ids []*core.IDAndSequence, err error)
@@ -156,6 +180,20 @@ type iBatchCollection interface {
 	GetBatches(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.BatchPersisted, res *ffapi.FilterResult, err error)
 }
 
+type iBatchPolicyCollection interface {
+	// UpsertBatchPolicy - Upsert the batch policy for a topic
+	UpsertBatchPolicy(ctx context.Context, policy *core.BatchPolicy) error
+
+	// GetBatchPolicies - Get the batch policies of all topics in a namespace
+	GetBatchPolicies(ctx context.Context, namespace string) ([]*core.BatchPolicy, error)
+
+	// GetBatchPolicy - Get the batch policy for a topic
+	GetBatchPolicy(ctx context.Context, namespace, topic string) (*core.BatchPolicy, error)
+
+	// DeleteBatchPolicy - Delete the batch policy for a topic
+	DeleteBatchPolicy(ctx context.Context, namespace, topic string) error
+}
+
 type iTransactionCollection interface {
 	// InsertTransaction - Insert a new transaction
 	InsertTransaction(ctx context.Context, data *core.Transaction) (err error)
@@ -225,8 +263,8 @@ type iOperationCollection interface {
 	// GetOperationByID - Get an operation by ID
 	GetOperationByID(ctx context.Context, namespace string, id *fftypes.UUID) (operation *core.Operation, err error)
 
-	// GetOperations - Get operation
-	GetOperations(ctx context.Context, namespace string, filter ffapi.Filter) (operation []*core.Operation, res *ffapi.FilterResult, err error)
+	// GetOperations - Get operation. If fields are listed, as for GetMessages, only the columns for those fields are read.
+	GetOperations(ctx context.Context, namespace string, filter ffapi.Filter, fields ...string) (operation []*core.Operation, res *ffapi.FilterResult, err error)
 }
 
 type iSubscriptionCollection interface {
@@ -264,6 +302,162 @@ type iEventCollection interface {
 	GetEvents(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Event, res *ffapi.FilterResult, err error)
 }
 
+type iDeadLetterCollection interface {
+	// InsertDeadLetter - Insert a dead letter for an event that could not be delivered
+	InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error
+
+	// GetDeadLetterByID - Get a dead letter by ID
+	GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DeadLetter, error)
+
+	// GetDeadLetters - Get dead letters
+	GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error)
+
+	// UpdateDeadLetter - Update a dead letter, if it matches the filter
+	UpdateDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error)
+}
+
+type iQuarantineCollection interface {
+	// InsertQuarantinedBatch - Insert a batch that was quarantined when it was received
+	InsertQuarantinedBatch(ctx context.Context, quarantined *core.QuarantinedBatch) error
+
+	// GetQuarantinedBatchByID - Get a quarantined batch by ID
+	GetQuarantinedBatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.QuarantinedBatch, error)
+
+	// GetQuarantinedBatches - Get quarantined batches
+	GetQuarantinedBatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.QuarantinedBatch, *ffapi.FilterResult, error)
+
+	// UpdateQuarantinedBatch - Update a quarantined batch, if it matches the filter
+	UpdateQuarantinedBatch(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error)
+}
+
+type iSLABreachCollection interface {
+	// InsertSLABreach - Insert a record of a message that exceeded its SLA
+	InsertSLABreach(ctx context.Context, breach *core.SLABreach) error
+
+	// GetSLABreachByID - Get an SLA breach by ID
+	GetSLABreachByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.SLABreach, error)
+
+	// GetSLABreaches - Get SLA breaches
+	GetSLABreaches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.SLABreach, *ffapi.FilterResult, error)
+}
+
+type iMessageAckCollection interface {
+	// InsertMessageAck - Insert the decision of the local application on a message held for it
+	InsertMessageAck(ctx context.Context, ack *core.MessageAck) error
+
+	// GetMessageAcks - Get message acks
+	GetMessageAcks(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.MessageAck, *ffapi.FilterResult, error)
+}
+
+type iBlobUploadCollection interface {
+	// InsertBlobUpload - Insert a new chunked blob upload
+	InsertBlobUpload(ctx context.Context, upload *core.BlobUpload) error
+
+	// UpdateBlobUpload - Update a chunked blob upload
+	UpdateBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error
+
+	// GetBlobUploadByID - Get a chunked blob upload by ID
+	GetBlobUploadByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.BlobUpload, error)
+
+	// GetBlobUploads - Get chunked blob uploads
+	GetBlobUploads(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlobUpload, *ffapi.FilterResult, error)
+
+	// DeleteBlobUpload - Delete a chunked blob upload
+	DeleteBlobUpload(ctx context.Context, namespace string, id *fftypes.UUID) error
+}
+
+type iQuarantinedBlobCollection interface {
+	// InsertQuarantinedBlob - Insert a blob that was quarantined when it was received
+	InsertQuarantinedBlob(ctx context.Context, quarantined *core.QuarantinedBlob) error
+
+	// GetQuarantinedBlobByID - Get a quarantined blob by ID
+	GetQuarantinedBlobByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.QuarantinedBlob, error)
+
+	// GetQuarantinedBlobs - Get quarantined blobs
+	GetQuarantinedBlobs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.QuarantinedBlob, *ffapi.FilterResult, error)
+
+	// UpdateQuarantinedBlob - Update a quarantined blob, if it matches the filter
+	UpdateQuarantinedBlob(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error)
+}
+
+type iSubscriptionTemplateCollection interface {
+	// InsertSubscriptionTemplate - Insert a subscription template
+	InsertSubscriptionTemplate(ctx context.Context, template *core.SubscriptionTemplate) error
+
+	// GetSubscriptionTemplateByID - Get a subscription template by ID
+	GetSubscriptionTemplateByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.SubscriptionTemplate, error)
+
+	// GetSubscriptionTemplateByName - Get a subscription template by name
+	GetSubscriptionTemplateByName(ctx context.Context, namespace, name string) (*core.SubscriptionTemplate, error)
+
+	// GetSubscriptionTemplates - Get subscription templates
+	GetSubscriptionTemplates(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.SubscriptionTemplate, *ffapi.FilterResult, error)
+
+	// DeleteSubscriptionTemplateByID - Delete a subscription template
+	DeleteSubscriptionTemplateByID(ctx context.Context, namespace string, id *fftypes.UUID) error
+}
+
+type iEventRuleCollection interface {
+	// InsertEventRule - Insert an event rule
+	InsertEventRule(ctx context.Context, rule *core.EventRule) error
+
+	// GetEventRuleByID - Get an event rule by ID
+	GetEventRuleByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.EventRule, error)
+
+	// GetEventRuleByName - Get an event rule by name
+	GetEventRuleByName(ctx context.Context, namespace, name string) (*core.EventRule, error)
+
+	// GetEventRules - Get event rules
+	GetEventRules(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.EventRule, *ffapi.FilterResult, error)
+
+	// DeleteEventRuleByID - Delete an event rule
+	DeleteEventRuleByID(ctx context.Context, namespace string, id *fftypes.UUID) error
+}
+
+type iRoleBindingCollection interface {
+	// InsertRoleBinding - Insert a role binding
+	InsertRoleBinding(ctx context.Context, binding *core.RoleBinding) error
+
+	// GetRoleBindingByID - Get a role binding by ID
+	GetRoleBindingByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.RoleBinding, error)
+
+	// GetRoleBindings - Get role bindings
+	GetRoleBindings(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.RoleBinding, *ffapi.FilterResult, error)
+
+	// DeleteRoleBindingByID - Delete a role binding
+	DeleteRoleBindingByID(ctx context.Context, namespace string, id *fftypes.UUID) error
+}
+
+type iJobCollection interface {
+	// InsertJob - Insert a new job
+	InsertJob(ctx context.Context, job *core.Job) error
+
+	// UpdateJob - Update a job
+	UpdateJob(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error
+
+	// GetJobByID - Get a job by ID
+	GetJobByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Job, error)
+
+	// GetJobs - Get jobs
+	GetJobs(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.Job, *ffapi.FilterResult, error)
+}
+
+type iAuditLogCollection interface {
+	// InsertAuditLogEntry - Insert a new audit log entry
+	InsertAuditLogEntry(ctx context.Context, entry *core.AuditLogEntry) error
+
+	// GetAuditLog - Get audit log entries
+	GetAuditLog(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.AuditLogEntry, *ffapi.FilterResult, error)
+}
+
+type iMessageReceiptCollection interface {
+	// InsertMessageReceipt - Insert a receipt for a private message, received from another member of its group
+	InsertMessageReceipt(ctx context.Context, receipt *core.MessageReceipt) error
+
+	// GetMessageReceipts - Get message receipts
+	GetMessageReceipts(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.MessageReceipt, *ffapi.FilterResult, error)
+}
+
 type iIdentitiesCollection interface {
 	// UpsertIdentity - Upsert an identity
 	UpsertIdentity(ctx context.Context, data *core.Identity, optimization UpsertOptimization) (err error)
@@ -299,6 +493,9 @@ type iGroupCollection interface {
 	// UpsertGroup - Upsert a group, with a hint to whether to optmize for existing or new
 	UpsertGroup(ctx context.Context, data *core.Group, optimization UpsertOptimization) (err error)
 
+	// UpdateGroup - Update the local state of a group
+	UpdateGroup(ctx context.Context, namespace string, hash *fftypes.Bytes32, update ffapi.Update) (err error)
+
 	// GetGroupByHash - Get a group by ID
 	GetGroupByHash(ctx context.Context, namespace string, hash *fftypes.Bytes32) (node *core.Group, err error)
 
@@ -337,6 +534,17 @@ type iNextPinCollection interface {
 	UpdateNextPin(ctx context.Context, namespace string, sequence int64, update ffapi.Update) (err error)
 }
 
+type iTopicSequenceCollection interface {
+	// UpsertTopicSequence - Upsert the next topic sequence for a context
+	UpsertTopicSequence(ctx context.Context, ts *core.TopicSequence) (err error)
+
+	// GetTopicSequence - Get the next topic sequence for a context
+	GetTopicSequence(ctx context.Context, namespace string, context *fftypes.Bytes32) (*core.TopicSequence, error)
+
+	// GetTopicSequences - Get topic sequences with generic filters
+	GetTopicSequences(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TopicSequence, *ffapi.FilterResult, error)
+}
+
 type iBlobCollection interface {
 	// InsertBlob - insert a blob
 	InsertBlob(ctx context.Context, blob *core.Blob) (err error)
@@ -391,10 +599,158 @@ type iTokenBalanceCollection interface {
 	// GetTokenAccountPools - Get the list of pools referenced by a given account
 	GetTokenAccountPools(ctx context.Context, namespace, key string, filter ffapi.Filter) ([]*core.TokenAccountPool, *ffapi.FilterResult, error)
 
+	// GetTokenPoolHolders - Get the number of token indexes held by each key with a non-zero balance in a pool
+	GetTokenPoolHolders(ctx context.Context, namespace string, poolID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenPoolHolder, *ffapi.FilterResult, error)
+
+	// GetTokenIndexOwnership - Get the number of keys with a non-zero balance of each token index in a pool
+	GetTokenIndexOwnership(ctx context.Context, namespace string, poolID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenIndexOwnership, *ffapi.FilterResult, error)
+
+	// GetTokenAccountActivity - Get the combined transfers and approvals involving a given account, across all pools
+	GetTokenAccountActivity(ctx context.Context, namespace, key string, filter ffapi.Filter) ([]*core.TokenActivity, *ffapi.FilterResult, error)
+
 	// DeleteTokenBalances - Delete token balances from a particular pool
 	DeleteTokenBalances(ctx context.Context, namespace string, poolID *fftypes.UUID) error
 }
 
+type iTokenMetadataCollection interface {
+	// UpsertTokenMetadata - Upsert the cached metadata for a token URI
+	UpsertTokenMetadata(ctx context.Context, metadata *core.TokenMetadata) error
+
+	// GetTokenMetadataByURI - Get the cached metadata for a token URI
+	GetTokenMetadataByURI(ctx context.Context, namespace, uri string) (*core.TokenMetadata, error)
+
+	// GetTokenMetadata - Get cached token metadata
+	GetTokenMetadata(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenMetadata, *ffapi.FilterResult, error)
+}
+
+type iTokenSnapshotCollection interface {
+	// InsertTokenSnapshot - Insert a token balance snapshot, along with all of its balances
+	InsertTokenSnapshot(ctx context.Context, snapshot *core.TokenSnapshot, balances []*core.TokenSnapshotBalance) error
+
+	// GetTokenSnapshotByID - Get a token balance snapshot by ID
+	GetTokenSnapshotByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenSnapshot, error)
+
+	// GetTokenSnapshots - Get token balance snapshots
+	GetTokenSnapshots(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenSnapshot, *ffapi.FilterResult, error)
+
+	// GetTokenSnapshotBalances - Get the balances recorded in a token balance snapshot
+	GetTokenSnapshotBalances(ctx context.Context, namespace string, snapshotID *fftypes.UUID, filter ffapi.Filter) ([]*core.TokenSnapshotBalance, *ffapi.FilterResult, error)
+}
+
+type iTokenSwapCollection interface {
+	// InsertTokenSwap - Insert a new token swap
+	InsertTokenSwap(ctx context.Context, swap *core.TokenSwap) error
+
+	// UpdateTokenSwap - Update a token swap
+	UpdateTokenSwap(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error
+
+	// GetTokenSwapByID - Get a token swap by ID
+	GetTokenSwapByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenSwap, error)
+
+	// GetTokenSwaps - Get token swaps
+	GetTokenSwaps(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenSwap, *ffapi.FilterResult, error)
+}
+
+type iTokenTransferRequestCollection interface {
+	// InsertTokenTransferRequest - Insert a new token transfer request that requires sign-off
+	InsertTokenTransferRequest(ctx context.Context, request *core.TokenTransferRequest) error
+
+	// UpdateTokenTransferRequest - Update a token transfer request, optionally only if it matches the filter
+	UpdateTokenTransferRequest(ctx context.Context, namespace string, id *fftypes.UUID, filter ffapi.Filter, update ffapi.Update) (updated bool, err error)
+
+	// GetTokenTransferRequestByID - Get a token transfer request by ID
+	GetTokenTransferRequestByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenTransferRequest, error)
+
+	// GetTokenTransferRequests - Get token transfer requests
+	GetTokenTransferRequests(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenTransferRequest, *ffapi.FilterResult, error)
+}
+
+type iTokenPoolMigrationCollection interface {
+	// InsertTokenPoolMigration - Insert a new migration of a token pool to a different connector
+	InsertTokenPoolMigration(ctx context.Context, migration *core.TokenPoolMigration) error
+
+	// UpdateTokenPoolMigration - Update a token pool migration
+	UpdateTokenPoolMigration(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error
+
+	// GetTokenPoolMigrationByID - Get a token pool migration by ID
+	GetTokenPoolMigrationByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenPoolMigration, error)
+
+	// GetTokenPoolMigrations - Get token pool migrations
+	GetTokenPoolMigrations(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenPoolMigration, *ffapi.FilterResult, error)
+}
+
+type iTokenBulkMintCollection interface {
+	// InsertTokenBulkMint - Insert a new bulk mint of non-fungible tokens
+	InsertTokenBulkMint(ctx context.Context, bulkMint *core.TokenBulkMint) error
+
+	// UpdateTokenBulkMint - Update a bulk mint
+	UpdateTokenBulkMint(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error
+
+	// GetTokenBulkMintByID - Get a bulk mint by ID
+	GetTokenBulkMintByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBulkMint, error)
+
+	// GetTokenBulkMints - Get bulk mints
+	GetTokenBulkMints(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBulkMint, *ffapi.FilterResult, error)
+}
+
+type iTokenLedgerCollection interface {
+	// InsertTokenLedgerEntry - Insert a new token ledger entry
+	InsertTokenLedgerEntry(ctx context.Context, entry *core.TokenLedgerEntry) error
+
+	// GetTokenLedgerEntries - Get token ledger entries
+	GetTokenLedgerEntries(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenLedgerEntry, *ffapi.FilterResult, error)
+}
+
+type iTokenAssociatedAccountCollection interface {
+	// InsertTokenAssociatedAccount - Insert a token account that holds the balance of an owner in a pool
+	InsertTokenAssociatedAccount(ctx context.Context, account *core.TokenAssociatedAccount) error
+
+	// GetTokenAssociatedAccount - Get the token account that holds the balance of an owner in a pool
+	GetTokenAssociatedAccount(ctx context.Context, namespace string, poolID *fftypes.UUID, owner string) (*core.TokenAssociatedAccount, error)
+
+	// GetTokenAssociatedAccounts - Get token associated accounts
+	GetTokenAssociatedAccounts(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenAssociatedAccount, *ffapi.FilterResult, error)
+}
+
+type iTokenMediaCollection interface {
+	// InsertTokenMedia - Insert a cached copy of the image referenced by token metadata
+	InsertTokenMedia(ctx context.Context, media *core.TokenMedia) error
+
+	// GetTokenMediaByURI - Get the cached copy of the image at a URI
+	GetTokenMediaByURI(ctx context.Context, namespace, uri string) (*core.TokenMedia, error)
+}
+
+type iTokenBalanceMismatchCollection interface {
+	// UpsertTokenBalanceMismatch - Upsert the token balance mismatch for a pool, token index and account
+	UpsertTokenBalanceMismatch(ctx context.Context, mismatch *core.TokenBalanceMismatch) error
+
+	// GetTokenBalanceMismatch - Get the token balance mismatch for a pool, token index and account
+	GetTokenBalanceMismatch(ctx context.Context, namespace string, poolID *fftypes.UUID, tokenIndex, key string) (*core.TokenBalanceMismatch, error)
+
+	// GetTokenBalanceMismatchByID - Get a token balance mismatch by ID
+	GetTokenBalanceMismatchByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.TokenBalanceMismatch, error)
+
+	// GetTokenBalanceMismatches - Get token balance mismatches
+	GetTokenBalanceMismatches(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenBalanceMismatch, *ffapi.FilterResult, error)
+
+	// DeleteTokenBalanceMismatch - Delete a token balance mismatch, once the balances match
+	DeleteTokenBalanceMismatch(ctx context.Context, namespace string, id *fftypes.UUID) error
+
+	// DeleteTokenBalanceMismatches - Delete token balance mismatches from a particular pool
+	DeleteTokenBalanceMismatches(ctx context.Context, namespace string, poolID *fftypes.UUID) error
+}
+
+type iTokenPolicyCollection interface {
+	// UpsertTokenTransferPolicy - Upsert the transfer policy for a token pool
+	UpsertTokenTransferPolicy(ctx context.Context, policy *core.TokenTransferPolicy) error
+
+	// GetTokenTransferPolicy - Get the transfer policy for a token pool
+	GetTokenTransferPolicy(ctx context.Context, namespace string, poolID *fftypes.UUID) (*core.TokenTransferPolicy, error)
+
+	// DeleteTokenTransferPolicy - Delete the transfer policy for a token pool
+	DeleteTokenTransferPolicy(ctx context.Context, namespace string, poolID *fftypes.UUID) error
+}
+
 type iTokenTransferCollection interface {
 	// InsertOrGetTokenTransfer - insert a token transfer event from the blockchain
 	// If the ProtocolID has already been recorded, it does not insert but returns the existing row
@@ -409,6 +765,9 @@ type iTokenTransferCollection interface {
 	// GetTokenTransfers - Get token transfers
 	GetTokenTransfers(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenTransfer, *ffapi.FilterResult, error)
 
+	// UpdateTokenTransfer - Update a token transfer
+	UpdateTokenTransfer(ctx context.Context, namespace string, localID *fftypes.UUID, update ffapi.Update) (err error)
+
 	// DeleteTokenTransfers - Delete token transfers from a particular pool
 	DeleteTokenTransfers(ctx context.Context, namespace string, poolID *fftypes.UUID) error
 }
@@ -547,6 +906,12 @@ type iBlockchainEventCollection interface {
 
 	// GetBlockchainEvents - get blockchain events
 	GetBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
+
+	// GetLatestBlockchainEvents - get the valid event with the highest protocol ID for each of the listeners
+	GetLatestBlockchainEvents(ctx context.Context, namespace string, listeners []*fftypes.UUID) ([]*core.BlockchainEvent, error)
+
+	// UpdateBlockchainEvent - update a blockchain event
+	UpdateBlockchainEvent(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) (err error)
 }
 
 // PersistenceInterface are the operations that must be implemented by a database interface plugin.
@@ -593,6 +958,7 @@ type PersistenceInterface interface {
 	iMessageCollection
 	iDataCollection
 	iBatchCollection
+	iBatchPolicyCollection
 	iTransactionCollection
 	iDatatypeCollection
 	iOffsetCollection
@@ -600,14 +966,38 @@ type PersistenceInterface interface {
 	iOperationCollection
 	iSubscriptionCollection
 	iEventCollection
+	iDeadLetterCollection
+	iQuarantineCollection
+	iQuarantinedBlobCollection
+	iSLABreachCollection
+	iMessageAckCollection
+	iBlobUploadCollection
+	iSubscriptionTemplateCollection
+	iEventRuleCollection
+	iRoleBindingCollection
+	iJobCollection
+	iAuditLogCollection
+	iMessageReceiptCollection
 	iIdentitiesCollection
 	iVerifiersCollection
 	iGroupCollection
 	iNonceCollection
 	iNextPinCollection
+	iTopicSequenceCollection
 	iBlobCollection
 	iTokenPoolCollection
 	iTokenBalanceCollection
+	iTokenMetadataCollection
+	iTokenMediaCollection
+	iTokenSnapshotCollection
+	iTokenSwapCollection
+	iTokenTransferRequestCollection
+	iTokenPoolMigrationCollection
+	iTokenBulkMintCollection
+	iTokenLedgerCollection
+	iTokenAssociatedAccountCollection
+	iTokenBalanceMismatchCollection
+	iTokenPolicyCollection
 	iTokenTransferCollection
 	iTokenApprovalCollection
 	iFFICollection
@@ -683,11 +1073,14 @@ const (
 type OtherCollection CollectionName
 
 const (
-	CollectionBlobs         OtherCollection = "blobs"
-	CollectionNextpins      OtherCollection = "nextpins"
-	CollectionNonces        OtherCollection = "nonces"
-	CollectionOffsets       OtherCollection = "offsets"
-	CollectionTokenBalances OtherCollection = "tokenbalances"
+	CollectionBlobs                 OtherCollection = "blobs"
+	CollectionNextpins              OtherCollection = "nextpins"
+	CollectionNonces                OtherCollection = "nonces"
+	CollectionOffsets               OtherCollection = "offsets"
+	CollectionTokenBalances         OtherCollection = "tokenbalances"
+	CollectionTokenMetadata         OtherCollection = "tokenmetadata"
+	CollectionTokenSnapshots        OtherCollection = "tokensnapshots"
+	CollectionTokenSnapshotBalances OtherCollection = "tokensnapshotbalances"
 )
 
 // PostCompletionHook is a closure/function that will be called after a successful insertion.
@@ -737,18 +1130,23 @@ var MessageQueryFactory = &ffapi.QueryFields{
 	"group":          &ffapi.Bytes32Field{},
 	"created":        &ffapi.TimeField{},
 	"datahash":       &ffapi.Bytes32Field{},
+	"expires":        &ffapi.TimeField{},
 	"idempotencykey": &ffapi.StringField{},
 	"hash":           &ffapi.Bytes32Field{},
 	"pins":           &ffapi.FFStringArrayField{},
+	"priority":       &ffapi.StringField{},
 	"state":          &ffapi.StringField{},
 	"confirmed":      &ffapi.TimeField{},
 	"rejectreason":   &ffapi.StringField{},
+	"sendtime":       &ffapi.TimeField{},
 	"sequence":       &ffapi.Int64Field{},
 	"txtype":         &ffapi.StringField{},
 	"batch":          &ffapi.UUIDField{},
 	"txid":           &ffapi.UUIDField{},
 	"txparent.type":  &ffapi.StringField{},
 	"txparent.id":    &ffapi.UUIDField{},
+	"parentmessage":  &ffapi.UUIDField{},
+	"topicsequence":  &ffapi.Int64Field{},
 }
 
 // BatchQueryFactory filter fields for batches
@@ -795,12 +1193,13 @@ var DataQueryFactory = &ffapi.QueryFields{
 
 // DatatypeQueryFactory filter fields for data definitions
 var DatatypeQueryFactory = &ffapi.QueryFields{
-	"id":        &ffapi.UUIDField{},
-	"message":   &ffapi.UUIDField{},
-	"validator": &ffapi.StringField{},
-	"name":      &ffapi.StringField{},
-	"version":   &ffapi.StringField{},
-	"created":   &ffapi.TimeField{},
+	"id":            &ffapi.UUIDField{},
+	"message":       &ffapi.UUIDField{},
+	"validator":     &ffapi.StringField{},
+	"name":          &ffapi.StringField{},
+	"version":       &ffapi.StringField{},
+	"created":       &ffapi.TimeField{},
+	"compatibility": &ffapi.StringField{},
 }
 
 // OffsetQueryFactory filter fields for data offsets
@@ -812,17 +1211,18 @@ var OffsetQueryFactory = &ffapi.QueryFields{
 
 // OperationQueryFactory filter fields for data operations
 var OperationQueryFactory = &ffapi.QueryFields{
-	"id":      &ffapi.UUIDField{},
-	"tx":      &ffapi.UUIDField{},
-	"type":    &ffapi.StringField{},
-	"status":  &ffapi.StringField{},
-	"error":   &ffapi.StringField{},
-	"plugin":  &ffapi.StringField{},
-	"input":   &ffapi.JSONField{},
-	"output":  &ffapi.JSONField{},
-	"created": &ffapi.TimeField{},
-	"updated": &ffapi.TimeField{},
-	"retry":   &ffapi.UUIDField{},
+	"id":            &ffapi.UUIDField{},
+	"tx":            &ffapi.UUIDField{},
+	"type":          &ffapi.StringField{},
+	"status":        &ffapi.StringField{},
+	"error":         &ffapi.StringField{},
+	"plugin":        &ffapi.StringField{},
+	"input":         &ffapi.JSONField{},
+	"output":        &ffapi.JSONField{},
+	"created":       &ffapi.TimeField{},
+	"updated":       &ffapi.TimeField{},
+	"retry":         &ffapi.UUIDField{},
+	"failurereason": &ffapi.StringField{},
 }
 
 // SubscriptionQueryFactory filter fields for data subscriptions
@@ -848,6 +1248,101 @@ var EventQueryFactory = &ffapi.QueryFields{
 	"created":    &ffapi.TimeField{},
 }
 
+// DeadLetterQueryFactory filter fields for dead letters
+var DeadLetterQueryFactory = &ffapi.QueryFields{
+	"id":                &ffapi.UUIDField{},
+	"subscription.id":   &ffapi.UUIDField{},
+	"subscription.name": &ffapi.StringField{},
+	"event":             &ffapi.UUIDField{},
+	"eventtype":         &ffapi.StringField{},
+	"reference":         &ffapi.UUIDField{},
+	"attempts":          &ffapi.Int64Field{},
+	"status":            &ffapi.Int64Field{},
+	"error":             &ffapi.StringField{},
+	"originalevent":     &ffapi.JSONField{},
+	"state":             &ffapi.StringField{},
+	"created":           &ffapi.TimeField{},
+	"updated":           &ffapi.TimeField{},
+}
+
+// QuarantineQueryFactory filter fields for quarantined batches
+var QuarantineQueryFactory = &ffapi.QueryFields{
+	"id":       &ffapi.UUIDField{},
+	"org":      &ffapi.StringField{},
+	"node":     &ffapi.UUIDField{},
+	"peer":     &ffapi.StringField{},
+	"batch":    &ffapi.UUIDField{},
+	"reason":   &ffapi.StringField{},
+	"messages": &ffapi.Int64Field{},
+	"size":     &ffapi.Int64Field{},
+	"state":    &ffapi.StringField{},
+	"created":  &ffapi.TimeField{},
+	"updated":  &ffapi.TimeField{},
+}
+
+// SLABreachQueryFactory filter fields for SLA breaches
+var SLABreachQueryFactory = &ffapi.QueryFields{
+	"id":      &ffapi.UUIDField{},
+	"type":    &ffapi.StringField{},
+	"message": &ffapi.UUIDField{},
+	"topic":   &ffapi.StringField{},
+	"org":     &ffapi.StringField{},
+	"node":    &ffapi.UUIDField{},
+	"created": &ffapi.TimeField{},
+}
+
+// MessageAckQueryFactory filter fields for message acks
+var MessageAckQueryFactory = &ffapi.QueryFields{
+	"id":      &ffapi.UUIDField{},
+	"message": &ffapi.UUIDField{},
+	"outcome": &ffapi.StringField{},
+	"reason":  &ffapi.StringField{},
+	"created": &ffapi.TimeField{},
+}
+
+// BlobUploadQueryFactory filter fields for chunked blob uploads
+var BlobUploadQueryFactory = &ffapi.QueryFields{
+	"id":               &ffapi.UUIDField{},
+	"offset":           &ffapi.Int64Field{},
+	"size":             &ffapi.Int64Field{},
+	"hash":             &ffapi.Bytes32Field{},
+	"filename":         &ffapi.StringField{},
+	"mimetype":         &ffapi.StringField{},
+	"autometa":         &ffapi.BoolField{},
+	"validator":        &ffapi.StringField{},
+	"datatype.name":    &ffapi.StringField{},
+	"datatype.version": &ffapi.StringField{},
+	"value":            &ffapi.JSONField{},
+	"created":          &ffapi.TimeField{},
+	"updated":          &ffapi.TimeField{},
+}
+
+// QuarantinedBlobQueryFactory filter fields for quarantined blobs
+var QuarantinedBlobQueryFactory = &ffapi.QueryFields{
+	"id":         &ffapi.UUIDField{},
+	"data":       &ffapi.UUIDField{},
+	"hash":       &ffapi.Bytes32Field{},
+	"size":       &ffapi.Int64Field{},
+	"peer":       &ffapi.StringField{},
+	"payloadref": &ffapi.StringField{},
+	"finding":    &ffapi.StringField{},
+	"state":      &ffapi.StringField{},
+	"created":    &ffapi.TimeField{},
+	"updated":    &ffapi.TimeField{},
+}
+
+// SubscriptionTemplateQueryFactory filter fields for subscription templates
+var SubscriptionTemplateQueryFactory = &ffapi.QueryFields{
+	"id":        &ffapi.UUIDField{},
+	"name":      &ffapi.StringField{},
+	"trigger":   &ffapi.StringField{},
+	"match":     &ffapi.StringField{},
+	"transport": &ffapi.StringField{},
+	"filters":   &ffapi.JSONField{},
+	"options":   &ffapi.StringField{},
+	"created":   &ffapi.TimeField{},
+}
+
 // PinQueryFactory filter fields for parked contexts
 var PinQueryFactory = &ffapi.QueryFields{
 	"sequence":   &ffapi.Int64Field{},
@@ -891,6 +1386,10 @@ var GroupQueryFactory = &ffapi.QueryFields{
 	"description": &ffapi.StringField{},
 	"ledger":      &ffapi.UUIDField{},
 	"created":     &ffapi.TimeField{},
+	"previous":    &ffapi.Bytes32Field{},
+	"generation":  &ffapi.Int64Field{},
+	"successor":   &ffapi.Bytes32Field{},
+	"superseded":  &ffapi.TimeField{},
 }
 
 // NonceQueryFactory filter fields for nonces
@@ -907,6 +1406,15 @@ var NextPinQueryFactory = &ffapi.QueryFields{
 	"nonce":    &ffapi.Int64Field{},
 }
 
+// TopicSequenceQueryFactory filter fields for topic sequences
+var TopicSequenceQueryFactory = &ffapi.QueryFields{
+	"context": &ffapi.Bytes32Field{},
+	"topic":   &ffapi.StringField{},
+	"group":   &ffapi.Bytes32Field{},
+	"next":    &ffapi.Int64Field{},
+	"updated": &ffapi.TimeField{},
+}
+
 // BlobQueryFactory filter fields for config records
 var BlobQueryFactory = &ffapi.QueryFields{
 	"hash":       &ffapi.Bytes32Field{},
@@ -918,23 +1426,24 @@ var BlobQueryFactory = &ffapi.QueryFields{
 
 // TokenPoolQueryFactory filter fields for token pools
 var TokenPoolQueryFactory = &ffapi.QueryFields{
-	"id":              &ffapi.UUIDField{},
-	"type":            &ffapi.StringField{},
-	"name":            &ffapi.StringField{},
-	"networkname":     &ffapi.StringField{},
-	"standard":        &ffapi.StringField{},
-	"locator":         &ffapi.StringField{},
-	"symbol":          &ffapi.StringField{},
-	"decimals":        &ffapi.Int64Field{},
-	"message":         &ffapi.UUIDField{},
-	"state":           &ffapi.StringField{},
-	"created":         &ffapi.TimeField{},
-	"connector":       &ffapi.StringField{},
-	"tx.type":         &ffapi.StringField{},
-	"tx.id":           &ffapi.UUIDField{},
-	"interface":       &ffapi.UUIDField{},
-	"interfaceformat": &ffapi.StringField{},
-	"published":       &ffapi.BoolField{},
+	"id":               &ffapi.UUIDField{},
+	"type":             &ffapi.StringField{},
+	"name":             &ffapi.StringField{},
+	"networkname":      &ffapi.StringField{},
+	"standard":         &ffapi.StringField{},
+	"locator":          &ffapi.StringField{},
+	"symbol":           &ffapi.StringField{},
+	"decimals":         &ffapi.Int64Field{},
+	"message":          &ffapi.UUIDField{},
+	"state":            &ffapi.StringField{},
+	"created":          &ffapi.TimeField{},
+	"connector":        &ffapi.StringField{},
+	"tx.type":          &ffapi.StringField{},
+	"tx.id":            &ffapi.UUIDField{},
+	"interface":        &ffapi.UUIDField{},
+	"interfaceformat":  &ffapi.StringField{},
+	"published":        &ffapi.BoolField{},
+	"identityregistry": &ffapi.StringField{},
 }
 
 // TokenBalanceQueryFactory filter fields for token balances
@@ -948,6 +1457,142 @@ var TokenBalanceQueryFactory = &ffapi.QueryFields{
 	"updated":    &ffapi.TimeField{},
 }
 
+// TokenMetadataQueryFactory filter fields for token metadata
+var TokenMetadataQueryFactory = &ffapi.QueryFields{
+	"uri":     &ffapi.StringField{},
+	"name":    &ffapi.StringField{},
+	"error":   &ffapi.StringField{},
+	"updated": &ffapi.TimeField{},
+}
+
+// TokenSnapshotQueryFactory filter fields for token balance snapshots
+var TokenSnapshotQueryFactory = &ffapi.QueryFields{
+	"id":          &ffapi.UUIDField{},
+	"pool":        &ffapi.UUIDField{},
+	"blocknumber": &ffapi.Int64Field{},
+	"timestamp":   &ffapi.TimeField{},
+	"transfers":   &ffapi.Int64Field{},
+	"accounts":    &ffapi.Int64Field{},
+	"created":     &ffapi.TimeField{},
+}
+
+// TokenSwapQueryFactory filter fields for token swaps
+var TokenSwapQueryFactory = &ffapi.QueryFields{
+	"id":       &ffapi.UUIDField{},
+	"state":    &ffapi.StringField{},
+	"hashlock": &ffapi.Bytes32Field{},
+	"timeout":  &ffapi.TimeField{},
+	"tx.type":  &ffapi.StringField{},
+	"tx.id":    &ffapi.UUIDField{},
+	"created":  &ffapi.TimeField{},
+	"updated":  &ffapi.TimeField{},
+}
+
+// TokenTransferRequestQueryFactory filter fields for token transfer requests that require sign-off
+var TokenTransferRequestQueryFactory = &ffapi.QueryFields{
+	"id":       &ffapi.UUIDField{},
+	"state":    &ffapi.StringField{},
+	"type":     &ffapi.StringField{},
+	"pool":     &ffapi.UUIDField{},
+	"key":      &ffapi.StringField{},
+	"amount":   &ffapi.Int64Field{},
+	"required": &ffapi.Int64Field{},
+	"signoffs": &ffapi.JSONField{},
+	"tx.type":  &ffapi.StringField{},
+	"tx.id":    &ffapi.UUIDField{},
+	"error":    &ffapi.StringField{},
+	"created":  &ffapi.TimeField{},
+	"updated":  &ffapi.TimeField{},
+}
+
+// TokenPoolMigrationQueryFactory filter fields for token pool migrations
+var TokenPoolMigrationQueryFactory = &ffapi.QueryFields{
+	"id":            &ffapi.UUIDField{},
+	"pool":          &ffapi.UUIDField{},
+	"state":         &ffapi.StringField{},
+	"fromconnector": &ffapi.StringField{},
+	"fromlocator":   &ffapi.StringField{},
+	"toconnector":   &ffapi.StringField{},
+	"tolocator":     &ffapi.StringField{},
+	"poolstate":     &ffapi.StringField{},
+	"tx.type":       &ffapi.StringField{},
+	"tx.id":         &ffapi.UUIDField{},
+	"error":         &ffapi.StringField{},
+	"created":       &ffapi.TimeField{},
+	"updated":       &ffapi.TimeField{},
+}
+
+// TokenBulkMintQueryFactory filter fields for bulk mints
+var TokenBulkMintQueryFactory = &ffapi.QueryFields{
+	"id":        &ffapi.UUIDField{},
+	"pool":      &ffapi.UUIDField{},
+	"connector": &ffapi.StringField{},
+	"state":     &ffapi.StringField{},
+	"key":       &ffapi.StringField{},
+	"to":        &ffapi.StringField{},
+	"batchsize": &ffapi.Int64Field{},
+	"total":     &ffapi.Int64Field{},
+	"submitted": &ffapi.Int64Field{},
+	"succeeded": &ffapi.Int64Field{},
+	"failed":    &ffapi.Int64Field{},
+	"tx.type":   &ffapi.StringField{},
+	"tx.id":     &ffapi.UUIDField{},
+	"operation": &ffapi.UUIDField{},
+	"created":   &ffapi.TimeField{},
+	"updated":   &ffapi.TimeField{},
+}
+
+// TokenLedgerQueryFactory filter fields for token ledger entries
+var TokenLedgerQueryFactory = &ffapi.QueryFields{
+	"id":              &ffapi.UUIDField{},
+	"sequence":        &ffapi.Int64Field{},
+	"pool":            &ffapi.UUIDField{},
+	"account":         &ffapi.StringField{},
+	"counterparty":    &ffapi.StringField{},
+	"tokenindex":      &ffapi.StringField{},
+	"transfertype":    &ffapi.StringField{},
+	"transfer":        &ffapi.UUIDField{},
+	"debit":           &ffapi.Int64Field{},
+	"credit":          &ffapi.Int64Field{},
+	"balance":         &ffapi.Int64Field{},
+	"reversal":        &ffapi.BoolField{},
+	"tx.type":         &ffapi.StringField{},
+	"tx.id":           &ffapi.UUIDField{},
+	"blockchainevent": &ffapi.UUIDField{},
+	"created":         &ffapi.TimeField{},
+}
+
+// TokenAssociatedAccountQueryFactory filter fields for token associated accounts
+var TokenAssociatedAccountQueryFactory = &ffapi.QueryFields{
+	"pool":            &ffapi.UUIDField{},
+	"owner":           &ffapi.StringField{},
+	"account":         &ffapi.StringField{},
+	"payer":           &ffapi.StringField{},
+	"tx.type":         &ffapi.StringField{},
+	"tx.id":           &ffapi.UUIDField{},
+	"blockchainevent": &ffapi.UUIDField{},
+	"created":         &ffapi.TimeField{},
+}
+
+// TokenBalanceMismatchQueryFactory filter fields for token balance mismatches
+var TokenBalanceMismatchQueryFactory = &ffapi.QueryFields{
+	"id":           &ffapi.UUIDField{},
+	"pool":         &ffapi.UUIDField{},
+	"tokenindex":   &ffapi.StringField{},
+	"key":          &ffapi.StringField{},
+	"balance":      &ffapi.Int64Field{},
+	"chainbalance": &ffapi.Int64Field{},
+	"created":      &ffapi.TimeField{},
+	"updated":      &ffapi.TimeField{},
+}
+
+// TokenSnapshotBalanceQueryFactory filter fields for the balances in a token balance snapshot
+var TokenSnapshotBalanceQueryFactory = &ffapi.QueryFields{
+	"tokenindex": &ffapi.StringField{},
+	"key":        &ffapi.StringField{},
+	"balance":    &ffapi.Int64Field{},
+}
+
 // TokenAccountQueryFactory filter fields for token accounts
 var TokenAccountQueryFactory = &ffapi.QueryFields{
 	"key":     &ffapi.StringField{},
@@ -960,43 +1605,79 @@ var TokenAccountPoolQueryFactory = &ffapi.QueryFields{
 	"updated": &ffapi.TimeField{},
 }
 
-// TokenTransferQueryFactory filter fields for token transfers
-var TokenTransferQueryFactory = &ffapi.QueryFields{
-	"localid":         &ffapi.StringField{},
+// TokenPoolHolderQueryFactory filter fields for the holders of a token pool
+var TokenPoolHolderQueryFactory = &ffapi.QueryFields{
+	"key":     &ffapi.StringField{},
+	"updated": &ffapi.TimeField{},
+}
+
+// TokenIndexOwnershipQueryFactory filter fields for the ownership of the token indexes in a pool
+var TokenIndexOwnershipQueryFactory = &ffapi.QueryFields{
+	"tokenindex": &ffapi.StringField{},
+	"uri":        &ffapi.StringField{},
+	"updated":    &ffapi.TimeField{},
+}
+
+// TokenActivityQueryFactory filter fields for the token activity of an account
+var TokenActivityQueryFactory = &ffapi.QueryFields{
+	"type":            &ffapi.StringField{},
+	"localid":         &ffapi.UUIDField{},
 	"pool":            &ffapi.UUIDField{},
 	"tokenindex":      &ffapi.StringField{},
-	"uri":             &ffapi.StringField{},
 	"connector":       &ffapi.StringField{},
 	"key":             &ffapi.StringField{},
 	"from":            &ffapi.StringField{},
 	"to":              &ffapi.StringField{},
-	"amount":          &ffapi.Int64Field{},
+	"operator":        &ffapi.StringField{},
 	"protocolid":      &ffapi.StringField{},
 	"message":         &ffapi.UUIDField{},
-	"messagehash":     &ffapi.Bytes32Field{},
-	"created":         &ffapi.TimeField{},
 	"tx.type":         &ffapi.StringField{},
 	"tx.id":           &ffapi.UUIDField{},
 	"blockchainevent": &ffapi.UUIDField{},
-	"type":            &ffapi.StringField{},
+	"created":         &ffapi.TimeField{},
 }
 
-var TokenApprovalQueryFactory = &ffapi.QueryFields{
+// TokenTransferQueryFactory filter fields for token transfers
+var TokenTransferQueryFactory = &ffapi.QueryFields{
 	"localid":         &ffapi.StringField{},
 	"pool":            &ffapi.UUIDField{},
+	"tokenindex":      &ffapi.StringField{},
+	"uri":             &ffapi.StringField{},
 	"connector":       &ffapi.StringField{},
 	"key":             &ffapi.StringField{},
-	"operator":        &ffapi.StringField{},
-	"approved":        &ffapi.BoolField{},
+	"from":            &ffapi.StringField{},
+	"to":              &ffapi.StringField{},
+	"amount":          &ffapi.Int64Field{},
 	"protocolid":      &ffapi.StringField{},
-	"subject":         &ffapi.StringField{},
-	"active":          &ffapi.BoolField{},
+	"message":         &ffapi.UUIDField{},
+	"messagehash":     &ffapi.Bytes32Field{},
 	"created":         &ffapi.TimeField{},
 	"tx.type":         &ffapi.StringField{},
 	"tx.id":           &ffapi.UUIDField{},
 	"blockchainevent": &ffapi.UUIDField{},
-	"message":         &ffapi.UUIDField{},
-	"messagehash":     &ffapi.Bytes32Field{},
+	"type":            &ffapi.StringField{},
+	"invalidated":     &ffapi.BoolField{},
+	"sequence":        &ffapi.Int64Field{},
+}
+
+var TokenApprovalQueryFactory = &ffapi.QueryFields{
+	"localid":          &ffapi.StringField{},
+	"pool":             &ffapi.UUIDField{},
+	"connector":        &ffapi.StringField{},
+	"key":              &ffapi.StringField{},
+	"operator":         &ffapi.StringField{},
+	"approved":         &ffapi.BoolField{},
+	"protocolid":       &ffapi.StringField{},
+	"subject":          &ffapi.StringField{},
+	"active":           &ffapi.BoolField{},
+	"created":          &ffapi.TimeField{},
+	"tx.type":          &ffapi.StringField{},
+	"tx.id":            &ffapi.UUIDField{},
+	"blockchainevent":  &ffapi.UUIDField{},
+	"message":          &ffapi.UUIDField{},
+	"messagehash":      &ffapi.Bytes32Field{},
+	"expiry":           &ffapi.TimeField{},
+	"expiryrevocation": &ffapi.UUIDField{},
 }
 
 // FFIQueryFactory filter fields for contract definitions
@@ -1060,6 +1741,7 @@ var BlockchainEventQueryFactory = &ffapi.QueryFields{
 	"tx.id":           &ffapi.UUIDField{},
 	"tx.blockchainid": &ffapi.StringField{},
 	"timestamp":       &ffapi.TimeField{},
+	"invalidated":     &ffapi.BoolField{},
 }
 
 // ContractAPIQueryFactory filter fields for Contract APIs
@@ -1070,3 +1752,62 @@ var ContractAPIQueryFactory = &ffapi.QueryFields{
 	"interface":   &ffapi.UUIDField{},
 	"published":   &ffapi.BoolField{},
 }
+
+// EventRuleQueryFactory filter fields for event rules
+var EventRuleQueryFactory = &ffapi.QueryFields{
+	"id":      &ffapi.UUIDField{},
+	"name":    &ffapi.StringField{},
+	"action":  &ffapi.StringField{},
+	"match":   &ffapi.JSONField{},
+	"targets": &ffapi.JSONField{},
+	"labels":  &ffapi.JSONField{},
+	"created": &ffapi.TimeField{},
+}
+
+// RoleBindingQueryFactory filter fields for role bindings
+var RoleBindingQueryFactory = &ffapi.QueryFields{
+	"id":        &ffapi.UUIDField{},
+	"principal": &ffapi.StringField{},
+	"role":      &ffapi.StringField{},
+	"created":   &ffapi.TimeField{},
+}
+
+// JobQueryFactory filter fields for jobs
+var JobQueryFactory = &ffapi.QueryFields{
+	"id":             &ffapi.UUIDField{},
+	"type":           &ffapi.StringField{},
+	"status":         &ffapi.StringField{},
+	"input":          &ffapi.JSONField{},
+	"output":         &ffapi.JSONField{},
+	"progress.done":  &ffapi.Int64Field{},
+	"progress.total": &ffapi.Int64Field{},
+	"error":          &ffapi.StringField{},
+	"created":        &ffapi.TimeField{},
+	"updated":        &ffapi.TimeField{},
+}
+
+// MessageReceiptQueryFactory filter fields for message receipts
+var MessageReceiptQueryFactory = &ffapi.QueryFields{
+	"id":          &ffapi.UUIDField{},
+	"message":     &ffapi.UUIDField{},
+	"messagehash": &ffapi.Bytes32Field{},
+	"type":        &ffapi.StringField{},
+	"identity":    &ffapi.StringField{},
+	"node":        &ffapi.UUIDField{},
+	"created":     &ffapi.TimeField{},
+}
+
+// AuditLogQueryFactory filter fields for audit log entries
+var AuditLogQueryFactory = &ffapi.QueryFields{
+	"id":             &ffapi.UUIDField{},
+	"principal":      &ffapi.StringField{},
+	"method":         &ffapi.StringField{},
+	"route":          &ffapi.StringField{},
+	"path":           &ffapi.StringField{},
+	"requesthash":    &ffapi.Bytes32Field{},
+	"idempotencykey": &ffapi.StringField{},
+	"tx":             &ffapi.UUIDField{},
+	"status":         &ffapi.Int64Field{},
+	"error":          &ffapi.StringField{},
+	"created":        &ffapi.TimeField{},
+}
@@ -89,6 +89,9 @@ type Plugin interface {
 	// DownloadBlob streams a received blob out of storage
 	DownloadBlob(ctx context.Context, payloadRef string) (content io.ReadCloser, err error)
 
+	// DownloadBlobRange streams length bytes of a received blob out of storage, starting at offset
+	DownloadBlobRange(ctx context.Context, payloadRef string, offset, length int64) (content io.ReadCloser, err error)
+
 	// DeleteBlob streams a deletes a blob from the local DB and DX
 	DeleteBlob(ctx context.Context, payloadRef string) (err error)
 
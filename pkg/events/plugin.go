@@ -51,6 +51,10 @@ type Plugin interface {
 	// Data will only be supplied as non-nil if the subscription is set to include data
 	DeliveryRequest(connID string, sub *core.Subscription, event *core.EventDelivery, data core.DataArray) error
 
+	// BatchDeliveryRequest requests delivery of a batch of events in one request, which must later be
+	// responded to for each event. Only called if the plugin has the BatchDelivery capability
+	BatchDeliveryRequest(connID string, sub *core.Subscription, events []*core.CombinedEventDataDelivery) error
+
 	// NamespaceRestarted is called after a namespace restarts. For a connect-in style plugin, like
 	// WebSockets, this must re-register any active connections that started before the time passed in.
 	NamespaceRestarted(ns string, startTime time.Time)
@@ -83,4 +87,11 @@ type Callbacks interface {
 	DeliveryResponse(connID string, inflight *core.EventDeliveryResponse)
 }
 
-type Capabilities struct{}
+type Capabilities struct {
+	// CloudEvents indicates the transport can deliver events wrapped in a CloudEvents envelope
+	CloudEvents bool
+	// ConsumerGroups indicates the transport can share the events of a subscription across multiple connections
+	ConsumerGroups bool
+	// BatchDelivery indicates the transport can deliver multiple events in a single request
+	BatchDelivery bool
+}
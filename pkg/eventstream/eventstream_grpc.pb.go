@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             (unknown)
+// source: pkg/eventstream/eventstream.proto
+
+package eventstream
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// EventStreamClient is the client API for EventStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EventStreamClient interface {
+	// Listen starts subscriptions with Start messages, and acknowledges the events it
+	// receives with Ack messages, unless auto_ack is set on the stream. The stream is ended
+	// with an INVALID_ARGUMENT status if the consumer sends an invalid message
+	Listen(ctx context.Context, opts ...grpc.CallOption) (EventStream_ListenClient, error)
+}
+
+type eventStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEventStreamClient(cc grpc.ClientConnInterface) EventStreamClient {
+	return &eventStreamClient{cc}
+}
+
+func (c *eventStreamClient) Listen(ctx context.Context, opts ...grpc.CallOption) (EventStream_ListenClient, error) {
+	stream, err := c.cc.NewStream(ctx, &EventStream_ServiceDesc.Streams[0], "/firefly.eventstream.v1.EventStream/Listen", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventStreamListenClient{stream}
+	return x, nil
+}
+
+type EventStream_ListenClient interface {
+	Send(*ClientMessage) error
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type eventStreamListenClient struct {
+	grpc.ClientStream
+}
+
+func (x *eventStreamListenClient) Send(m *ClientMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *eventStreamListenClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EventStreamServer is the server API for EventStream service.
+// All implementations must embed UnimplementedEventStreamServer
+// for forward compatibility
+type EventStreamServer interface {
+	// Listen starts subscriptions with Start messages, and acknowledges the events it
+	// receives with Ack messages, unless auto_ack is set on the stream. The stream is ended
+	// with an INVALID_ARGUMENT status if the consumer sends an invalid message
+	Listen(EventStream_ListenServer) error
+	mustEmbedUnimplementedEventStreamServer()
+}
+
+// UnimplementedEventStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedEventStreamServer struct {
+}
+
+func (UnimplementedEventStreamServer) Listen(EventStream_ListenServer) error {
+	return status.Errorf(codes.Unimplemented, "method Listen not implemented")
+}
+func (UnimplementedEventStreamServer) mustEmbedUnimplementedEventStreamServer() {}
+
+// UnsafeEventStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EventStreamServer will
+// result in compilation errors.
+type UnsafeEventStreamServer interface {
+	mustEmbedUnimplementedEventStreamServer()
+}
+
+func RegisterEventStreamServer(s grpc.ServiceRegistrar, srv EventStreamServer) {
+	s.RegisterService(&EventStream_ServiceDesc, srv)
+}
+
+func _EventStream_Listen_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(EventStreamServer).Listen(&eventStreamListenServer{stream})
+}
+
+type EventStream_ListenServer interface {
+	Send(*Event) error
+	Recv() (*ClientMessage, error)
+	grpc.ServerStream
+}
+
+type eventStreamListenServer struct {
+	grpc.ServerStream
+}
+
+func (x *eventStreamListenServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *eventStreamListenServer) Recv() (*ClientMessage, error) {
+	m := new(ClientMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// EventStream_ServiceDesc is the grpc.ServiceDesc for EventStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EventStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "firefly.eventstream.v1.EventStream",
+	HandlerType: (*EventStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Listen",
+			Handler:       _EventStream_Listen_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pkg/eventstream/eventstream.proto",
+}
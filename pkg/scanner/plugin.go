@@ -0,0 +1,41 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scanner
+
+import (
+	"context"
+	"io"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Plugin is the interface implemented by each type of blob scanner, which checks the content of blobs - for
+// example with an anti-virus engine - before they are accepted on a namespace
+type Plugin interface {
+	core.Named
+
+	// NewScanner validates the options configured for the blob scanner of a namespace, and returns the scanner
+	NewScanner(ctx context.Context, options fftypes.JSONObject) (Scanner, error)
+}
+
+// Scanner checks the content of blobs uploaded to this node, and of blobs received from other nodes
+type Scanner interface {
+	// Scan reads the whole content of a blob, and returns a description of the threat that was found in it, or
+	// an empty string if the content is clean. An error is returned if the content could not be scanned.
+	Scan(ctx context.Context, content io.Reader) (finding string, err error)
+}
@@ -70,6 +70,30 @@ type Plugin interface {
 	// TransferTokens transfers tokens within a pool from one account to another
 	TransferTokens(ctx context.Context, nsOpID string, poolLocator string, transfer *core.TokenTransfer, methods *fftypes.JSONAny) error
 
+	// TransferTokensBatch transfers several token index/amount pairs within a pool, from one account to another, in a single blockchain transaction.
+	// All transfers share the same from and to accounts, signing key and FireFly transaction.
+	TransferTokensBatch(ctx context.Context, nsOpID string, poolLocator string, transfers []*core.TokenTransfer, methods *fftypes.JSONAny) error
+
+	// CheckTransfer asks the connector if a transfer would be permitted, without submitting it (such as for compliance-gated tokens)
+	CheckTransfer(ctx context.Context, poolLocator string, transfer *core.TokenTransfer) (*core.TokenTransferEligibility, error)
+
+	// GetBalance queries the connector for the current on-chain balance of an account, for reconciliation with the balance computed by FireFly
+	GetBalance(ctx context.Context, poolLocator, tokenIndex, account string) (*fftypes.FFBigInt, error)
+
+	// GetTokenFees queries the connector for the royalty (such as EIP-2981 royalty info) and transfer fee of a pool, or of a single token index
+	// within the pool. Returns nil if the connector does not report any fees for the pool.
+	GetTokenFees(ctx context.Context, poolLocator, tokenIndex string) (*core.TokenFees, error)
+
+	// LockTokens places the tokens for one leg of a swap into the escrow contract of the connector, under the hash lock of the swap.
+	// The tokens are held until they are released to the recipient by ClaimTokens, or returned to the owner by RefundTokens.
+	LockTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error
+
+	// ClaimTokens releases the escrowed tokens for one leg of a swap to the recipient, by revealing the preimage of the hash lock
+	ClaimTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error
+
+	// RefundTokens returns the escrowed tokens for one leg of a swap to the owner
+	RefundTokens(ctx context.Context, nsOpID string, poolLocator string, swap *core.TokenSwap, leg *core.TokenSwapLeg) error
+
 	// TokenApproval approves an operator to transfer tokens on the owner's behalf
 	TokensApproval(ctx context.Context, nsOpID string, poolLocator string, approval *core.TokenApproval, methods *fftypes.JSONAny) error
 }
@@ -99,6 +123,12 @@ type Callbacks interface {
 	//
 	// Error should will only be returned in shutdown scenarios
 	TokensApproved(plugin Plugin, approval *TokenApproval) error
+
+	// TokenEventRemoved notifies that the blockchain event of a token transfer or approval delivered previously
+	// is no longer on the canonical chain, such as after a chain reorganization
+	//
+	// Error should only be returned in shutdown scenarios
+	TokenEventRemoved(plugin Plugin, event *blockchain.Event) error
 }
 
 // Capabilities is the supported featureset of the tokens interface implemented by the plugin, with the specified config
@@ -134,6 +164,9 @@ type TokenPool struct {
 	// Symbol is the short token symbol, if the connector uses one (optional)
 	Symbol string
 
+	// IdentityRegistry is the location of the identity registry that gates transfers in this pool (optional)
+	IdentityRegistry string
+
 	// Info is any other connector-specific info on the pool that may be worth saving (optional)
 	Info fftypes.JSONObject
 
@@ -159,10 +192,26 @@ type TokenTransfer struct {
 	// PoolLocator is the ID assigned to the token pool by the connector
 	PoolLocator string
 
+	// CreatedAccounts are any token accounts created as a side effect of this transfer, for token standards
+	// where balances are held in accounts associated with the owner (such as Solana SPL associated token accounts)
+	CreatedAccounts []*TokenAccountCreated
+
 	// Event contains info on the underlying blockchain event for this transfer
 	Event *blockchain.Event
 }
 
+// TokenAccountCreated is a token account that was created as a side effect of a transfer
+type TokenAccountCreated struct {
+	// Owner is the address that owns the token account, and that transfers and balances are recorded against
+	Owner string
+
+	// Account is the address of the token account that holds the balance of the owner
+	Account string
+
+	// Payer is the address that paid for the creation of the token account (optional)
+	Payer string
+}
+
 type TokenApproval struct {
 	// Although not every field will be filled in, embed core.TokenApproval to avoid duplicating lots of fields
 	core.TokenApproval
@@ -173,3 +222,18 @@ type TokenApproval struct {
 	// Event contains info on the underlying blockchain event for this transfer
 	Event *blockchain.Event
 }
+
+// ErrorResponse is returned by a plugin when the token connector responded to a request with an error status,
+// as opposed to not being reachable at all
+type ErrorResponse struct {
+	StatusCode int
+	Err        error
+}
+
+func (er *ErrorResponse) Error() string {
+	return er.Err.Error()
+}
+
+func (er *ErrorResponse) Unwrap() error {
+	return er.Err
+}
@@ -0,0 +1,46 @@
+// Copyright © 2023 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Plugin is the interface implemented by each type of transform, which applies a data policy - such as
+// tokenizing personal data, or upgrading data to a newer schema - to the data of messages on a namespace
+type Plugin interface {
+	core.Named
+
+	// NewTransform validates the options configured for a step of the pipeline of a namespace, and returns
+	// the transform to run for that step
+	NewTransform(ctx context.Context, options fftypes.JSONObject) (Transform, error)
+}
+
+// Transform is one step of a pipeline, which is called on the value of each data item of the messages on
+// the topics of the step. Data with only a blob, and no value, is not passed to the transform.
+type Transform interface {
+	// Send is called on each new value of a message sent by this node, before the data is validated and hashed.
+	// The value returned is the data of the message, on this node and on every node it is sent to.
+	Send(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error)
+
+	// Receive is called on each value of a message received from another node, after the hashes of its batch
+	// have been verified, and before the data is stored and validated
+	Receive(ctx context.Context, value *fftypes.JSONAny) (*fftypes.JSONAny, error)
+}
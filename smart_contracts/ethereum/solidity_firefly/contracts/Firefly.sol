@@ -42,6 +42,10 @@ contract Firefly is IBatchPin {
         );
     }
 
+    function pinBatchPacked(bytes calldata data) public override {
+        emit BatchPinPacked(tx.origin, block.timestamp, data);
+    }
+
     function networkAction(string memory action, string memory payload) public {
         bytes32[] memory contexts;
         emit BatchPin(
@@ -56,6 +60,6 @@ contract Firefly is IBatchPin {
     }
 
     function networkVersion() public pure returns (uint8) {
-        return 2;
+        return 3;
     }
 }
@@ -13,6 +13,8 @@ interface IBatchPin {
         bytes32[] contexts
     );
 
+    event BatchPinPacked(address author, uint timestamp, bytes data);
+
     function pinBatchData(bytes calldata data) external;
 
     function pinBatch(
@@ -21,4 +23,6 @@ interface IBatchPin {
         string memory payloadRef,
         bytes32[] memory contexts
     ) external;
+
+    function pinBatchPacked(bytes calldata data) external;
 }
@@ -107,6 +107,32 @@ describe("Firefly.sol", () => {
       });
     });
 
+    describe("pinBatchPacked", () => {
+      it("emits the packed data unchanged", async () => {
+        const data = ethers.utils.solidityPack(
+          [
+            "uint8",
+            "bytes32",
+            "bytes32",
+            "uint16",
+            "string",
+            "uint16",
+            "bytes32",
+          ],
+          [1, randB32Hex(), randB32Hex(), 4, "ref1", 1, randB32Hex()]
+        );
+        const result = await fireflyContract.pinBatchPacked(data);
+        const receipt = await result.wait();
+        const logArgs = receipt.events?.[0]?.args;
+        assert.isDefined(logArgs);
+        if (logArgs) {
+          assert.equal(receipt.events?.[0]?.event, "BatchPinPacked");
+          assert.equal(logArgs.author, deployer.address);
+          assert.equal(logArgs.data, data);
+        }
+      });
+    });
+
     describe("networkAction", () => {
       it("terminate action", async () => {
         const result = await fireflyContract.networkAction(
@@ -132,5 +158,11 @@ describe("Firefly.sol", () => {
         }
       });
     });
+
+    describe("networkVersion", () => {
+      it("is version 3", async () => {
+        assert.equal(await fireflyContract.networkVersion(), 3);
+      });
+    });
   });
 });